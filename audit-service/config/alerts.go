@@ -0,0 +1,241 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Alert severities
+const (
+	AlertSeverityLow      = "LOW"
+	AlertSeverityMedium   = "MEDIUM"
+	AlertSeverityHigh     = "HIGH"
+	AlertSeverityCritical = "CRITICAL"
+)
+
+// Alert threshold grouping dimensions
+const (
+	AlertGroupByConsumer = "consumer"
+	AlertGroupByActorID  = "actorId"
+	AlertGroupByTargetID = "targetId"
+)
+
+// AlertsConfig defines the rules evaluated against every audit log as it is stored
+type AlertsConfig struct {
+	// BusinessHours is the working week used by rules matching outsideBusinessHours
+	BusinessHours BusinessHours `yaml:"businessHours"`
+
+	Rules []AlertRule `yaml:"rules"`
+}
+
+// BusinessHours is a daily time range on given weekdays in a time zone
+type BusinessHours struct {
+	Timezone string   `yaml:"timezone"` // IANA name, default UTC
+	Days     []string `yaml:"days"`     // MON to SUN, default MON to FRI
+	Start    string   `yaml:"start"`    // HH:MM, default 09:00
+	End      string   `yaml:"end"`      // HH:MM (exclusive), default 17:00
+
+	location     *time.Location
+	days         map[time.Weekday]bool
+	startMinutes int
+	endMinutes   int
+}
+
+// AlertRule fires an alert when logs match it: for every matching log or, with a threshold,
+// when enough matching logs for the same group occur within a time window
+type AlertRule struct {
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description"`
+	Severity    string          `yaml:"severity"` // LOW, MEDIUM (default), HIGH or CRITICAL
+	Match       AlertMatch      `yaml:"match"`
+	Threshold   *AlertThreshold `yaml:"threshold"`
+	Notify      AlertNotify     `yaml:"notify"`
+}
+
+// AlertMatch selects logs; every condition that is set must hold
+type AlertMatch struct {
+	EventTypes []string `yaml:"eventTypes"`
+	Status     string   `yaml:"status"`
+
+	// ConsentStatus matches the consent status recorded in a consent check's response, e.g. rejected
+	ConsentStatus string `yaml:"consentStatus"`
+
+	// Fields matches logs requesting any of these fields (requiredFields or requestedFields in the metadata);
+	// a trailing * matches a prefix, e.g. person.*
+	Fields []string `yaml:"fields"`
+
+	// OutsideBusinessHours matches logs whose timestamp falls outside the configured business hours
+	OutsideBusinessHours bool `yaml:"outsideBusinessHours"`
+}
+
+// AlertThreshold makes a rule fire only once Count matching logs for the same group occur within Window
+type AlertThreshold struct {
+	Count   int           `yaml:"count"`
+	Window  time.Duration `yaml:"window"`
+	GroupBy string        `yaml:"groupBy"` // consumer, actorId or targetId; empty counts all matching logs together
+}
+
+// AlertNotify lists where a rule's alerts are sent, in addition to being recorded
+type AlertNotify struct {
+	Webhooks []string `yaml:"webhooks"`
+	Emails   []string `yaml:"emails"`
+}
+
+// alertsFile is the top-level structure of the alerts YAML file
+type alertsFile struct {
+	Alerts AlertsConfig `yaml:"alerts"`
+}
+
+// LoadAlerts loads the alert rules from a YAML file
+// If the file is not found, no rules are evaluated
+func LoadAlerts(configPath string) (*AlertsConfig, error) {
+	if configPath == "" {
+		configPath = "config/alerts.yaml"
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			alerts := &AlertsConfig{}
+			return alerts, alerts.Validate()
+		}
+		return nil, fmt.Errorf("failed to read alerts config file %s: %w", configPath, err)
+	}
+
+	var file alertsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts config file %s: %w", configPath, err)
+	}
+	if err := file.Alerts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid alerts config file %s: %w", configPath, err)
+	}
+	return &file.Alerts, nil
+}
+
+// Validate checks the rules and applies defaults
+func (c *AlertsConfig) Validate() error {
+	if err := c.BusinessHours.init(); err != nil {
+		return fmt.Errorf("businessHours: %w", err)
+	}
+
+	names := make(map[string]bool, len(c.Rules))
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *AlertRule) validate() error {
+	r.Severity = strings.ToUpper(r.Severity)
+	switch r.Severity {
+	case "":
+		r.Severity = AlertSeverityMedium
+	case AlertSeverityLow, AlertSeverityMedium, AlertSeverityHigh, AlertSeverityCritical:
+	default:
+		return fmt.Errorf("invalid severity %q", r.Severity)
+	}
+
+	if t := r.Threshold; t != nil {
+		if t.Count < 1 {
+			return fmt.Errorf("threshold count must be at least 1, got %d", t.Count)
+		}
+		if t.Window <= 0 {
+			return fmt.Errorf("threshold window must be positive, got %s", t.Window)
+		}
+		switch t.GroupBy {
+		case "", AlertGroupByConsumer, AlertGroupByActorID, AlertGroupByTargetID:
+		default:
+			return fmt.Errorf("invalid threshold groupBy %q", t.GroupBy)
+		}
+	}
+
+	for _, webhook := range r.Notify.Webhooks {
+		parsed, err := url.Parse(webhook)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q", webhook)
+		}
+	}
+	for _, email := range r.Notify.Emails {
+		if !strings.Contains(email, "@") || strings.ContainsAny(email, " \r\n") {
+			return fmt.Errorf("invalid email address %q", email)
+		}
+	}
+	return nil
+}
+
+// weekdays maps the day names accepted in business hours
+var weekdays = map[string]time.Weekday{
+	"SUN": time.Sunday, "MON": time.Monday, "TUE": time.Tuesday, "WED": time.Wednesday,
+	"THU": time.Thursday, "FRI": time.Friday, "SAT": time.Saturday,
+}
+
+func (b *BusinessHours) init() error {
+	if b.Timezone == "" {
+		b.Timezone = "UTC"
+	}
+	location, err := time.LoadLocation(b.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", b.Timezone, err)
+	}
+	b.location = location
+
+	if len(b.Days) == 0 {
+		b.Days = []string{"MON", "TUE", "WED", "THU", "FRI"}
+	}
+	b.days = make(map[time.Weekday]bool, len(b.Days))
+	for _, day := range b.Days {
+		weekday, ok := weekdays[strings.ToUpper(day)]
+		if !ok {
+			return fmt.Errorf("invalid day %q", day)
+		}
+		b.days[weekday] = true
+	}
+
+	if b.Start == "" {
+		b.Start = "09:00"
+	}
+	if b.End == "" {
+		b.End = "17:00"
+	}
+	if b.startMinutes, err = parseClock(b.Start); err != nil {
+		return err
+	}
+	if b.endMinutes, err = parseClock(b.End); err != nil {
+		return err
+	}
+	if b.endMinutes <= b.startMinutes {
+		return fmt.Errorf("end %s must be after start %s", b.End, b.Start)
+	}
+	return nil
+}
+
+// Contains reports whether t falls within business hours
+func (b *BusinessHours) Contains(t time.Time) bool {
+	local := t.In(b.location)
+	minutes := local.Hour()*60 + local.Minute()
+	return b.days[local.Weekday()] && minutes >= b.startMinutes && minutes < b.endMinutes
+}
+
+// parseClock parses HH:MM into minutes after midnight
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadAlerts_MissingFile(t *testing.T) {
+	alerts, err := LoadAlerts("/nonexistent/path/alerts.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error for non-existent file, got: %v", err)
+	}
+	if len(alerts.Rules) != 0 {
+		t.Errorf("Expected no rules without a config file, got %d", len(alerts.Rules))
+	}
+}
+
+func TestLoadAlerts_ValidYAML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "alerts.yaml")
+	configContent := `alerts:
+  businessHours:
+    timezone: Asia/Colombo
+    start: "08:30"
+  rules:
+    - name: denied-requests
+      severity: high
+      match:
+        eventTypes: [POLICY_CHECK]
+        status: FAILURE
+      threshold:
+        count: 10
+        window: 5m
+        groupBy: consumer
+      notify:
+        webhooks: [https://alerts.example.com/audit]
+        emails: [security@example.com]
+    - name: sensitive-fields
+      match:
+        fields: [person.nic]
+        outsideBusinessHours: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	alerts, err := LoadAlerts(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(alerts.Rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(alerts.Rules))
+	}
+
+	denied := alerts.Rules[0]
+	if denied.Severity != AlertSeverityHigh {
+		t.Errorf("Expected severity to be normalized to HIGH, got %s", denied.Severity)
+	}
+	if denied.Threshold == nil || denied.Threshold.Count != 10 || denied.Threshold.Window != 5*time.Minute {
+		t.Errorf("Unexpected threshold %+v", denied.Threshold)
+	}
+	if alerts.Rules[1].Severity != AlertSeverityMedium || alerts.Rules[1].Threshold != nil {
+		t.Errorf("Expected default severity and no threshold, got %+v", alerts.Rules[1])
+	}
+
+	// Colombo is UTC+5:30; business hours default to Monday to Friday until 17:00
+	tests := map[string]struct {
+		at   time.Time
+		want bool
+	}{
+		"weekday morning":     {time.Date(2026, 1, 5, 3, 0, 0, 0, time.UTC), true},    // Mon 08:30
+		"weekday before open": {time.Date(2026, 1, 5, 2, 59, 0, 0, time.UTC), false},  // Mon 08:29
+		"weekday at close":    {time.Date(2026, 1, 5, 11, 30, 0, 0, time.UTC), false}, // Mon 17:00
+		"saturday":            {time.Date(2026, 1, 10, 6, 0, 0, 0, time.UTC), false},  // Sat 11:30
+	}
+	for name, tt := range tests {
+		if got := alerts.BusinessHours.Contains(tt.at); got != tt.want {
+			t.Errorf("%s: Contains() = %v, want %v", name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadAlerts_InvalidYAML(t *testing.T) {
+	tests := map[string]string{
+		"unparseable":      "alerts: [",
+		"missing name":     "alerts:\n  rules:\n    - severity: HIGH\n",
+		"duplicate name":   "alerts:\n  rules:\n    - name: a\n    - name: a\n",
+		"invalid severity": "alerts:\n  rules:\n    - name: a\n      severity: URGENT\n",
+		"zero count":       "alerts:\n  rules:\n    - name: a\n      threshold:\n        count: 0\n        window: 5m\n",
+		"missing window":   "alerts:\n  rules:\n    - name: a\n      threshold:\n        count: 3\n",
+		"invalid groupBy":  "alerts:\n  rules:\n    - name: a\n      threshold:\n        count: 3\n        window: 5m\n        groupBy: traceId\n",
+		"invalid webhook":  "alerts:\n  rules:\n    - name: a\n      notify:\n        webhooks: [ftp://example.com]\n",
+		"invalid email":    "alerts:\n  rules:\n    - name: a\n      notify:\n        emails: [security]\n",
+		"invalid timezone": "alerts:\n  businessHours:\n    timezone: Mars/Olympus\n",
+		"invalid day":      "alerts:\n  businessHours:\n    days: [FUN]\n",
+		"end before start": "alerts:\n  businessHours:\n    start: \"18:00\"\n    end: \"09:00\"\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "alerts.yaml")
+			if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+			if _, err := LoadAlerts(configPath); err == nil {
+				t.Error("Expected an error for invalid alerts config")
+			}
+		})
+	}
+}
+
+func TestLoadAlerts_ShippedConfig(t *testing.T) {
+	alerts, err := LoadAlerts("alerts.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load shipped alerts config: %v", err)
+	}
+	if len(alerts.Rules) == 0 {
+		t.Error("Expected the shipped config to define rules")
+	}
+}
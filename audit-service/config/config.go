@@ -40,6 +40,10 @@ var DefaultEnums = AuditEnums{
 		"MANAGEMENT_EVENT",
 		"USER_MANAGEMENT",
 		"DATA_FETCH",
+		"AUDIT_EXPORT",
+		"AUDIT_ARCHIVE",
+		"AUDIT_SUBJECT_REPORT",
+		"AUDIT_ERASURE",
 	},
 	EventActions: []string{
 		"CREATE",
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RetentionConfig defines how long audit logs are kept before they are archived and deleted.
+// Retention is configured per event type; a period of 0 days keeps logs forever.
+type RetentionConfig struct {
+	// DefaultDays applies to event types without their own policy, and to logs without an event type
+	DefaultDays int `yaml:"defaultDays"`
+
+	// EventTypes maps an event type to its retention period in days
+	EventTypes map[string]int `yaml:"eventTypes"`
+}
+
+// retentionFile is the top-level structure of the retention YAML file
+type retentionFile struct {
+	Retention RetentionConfig `yaml:"retention"`
+}
+
+// LoadRetention loads the retention configuration from a YAML file
+// If the file is not found, logs are kept forever
+func LoadRetention(configPath string) (*RetentionConfig, error) {
+	if configPath == "" {
+		configPath = "config/retention.yaml"
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &RetentionConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read retention config file %s: %w", configPath, err)
+	}
+
+	// Unlike enums, an unparseable retention file is an error: falling back to a default
+	// could delete logs that were meant to be kept
+	var file retentionFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse retention config file %s: %w", configPath, err)
+	}
+	if err := file.Retention.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retention config file %s: %w", configPath, err)
+	}
+	return &file.Retention, nil
+}
+
+// Validate checks that no retention period is negative
+func (c *RetentionConfig) Validate() error {
+	if c.DefaultDays < 0 {
+		return fmt.Errorf("defaultDays must not be negative, got %d", c.DefaultDays)
+	}
+	for eventType, days := range c.EventTypes {
+		if days < 0 {
+			return fmt.Errorf("retention for %s must not be negative, got %d", eventType, days)
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether any logs ever expire
+func (c *RetentionConfig) Enabled() bool {
+	if c.DefaultDays > 0 {
+		return true
+	}
+	for _, days := range c.EventTypes {
+		if days > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LongestDays returns the longest retention period in days, and whether every log expires;
+// a log older than that has expired whatever its event type
+func (c *RetentionConfig) LongestDays() (int, bool) {
+	if c.DefaultDays <= 0 {
+		return 0, false
+	}
+	longest := c.DefaultDays
+	for _, days := range c.EventTypes {
+		if days <= 0 {
+			return 0, false
+		}
+		if days > longest {
+			longest = days
+		}
+	}
+	return longest, true
+}
+
+// ConfiguredEventTypes returns the event types with their own policy, in sorted order
+func (c *RetentionConfig) ConfiguredEventTypes() []string {
+	eventTypes := make([]string, 0, len(c.EventTypes))
+	for eventType := range c.EventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRetention_MissingFile(t *testing.T) {
+	retention, err := LoadRetention("/nonexistent/path/retention.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error for non-existent file, got: %v", err)
+	}
+	if retention.Enabled() {
+		t.Error("Expected retention to be disabled without a config file")
+	}
+}
+
+func TestLoadRetention_ValidYAML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "retention.yaml")
+	configContent := `retention:
+  defaultDays: 365
+  eventTypes:
+    POLICY_CHECK: 90
+    AUDIT_EXPORT: 0
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	retention, err := LoadRetention(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if retention.DefaultDays != 365 {
+		t.Errorf("Expected defaultDays 365, got %d", retention.DefaultDays)
+	}
+	if retention.EventTypes["POLICY_CHECK"] != 90 {
+		t.Errorf("Expected POLICY_CHECK retention 90, got %d", retention.EventTypes["POLICY_CHECK"])
+	}
+	if !retention.Enabled() {
+		t.Error("Expected retention to be enabled")
+	}
+	if got := retention.ConfiguredEventTypes(); len(got) != 2 || got[0] != "AUDIT_EXPORT" || got[1] != "POLICY_CHECK" {
+		t.Errorf("Expected sorted configured event types, got %v", got)
+	}
+	if _, all := retention.LongestDays(); all {
+		t.Error("Expected AUDIT_EXPORT logs never to expire")
+	}
+}
+
+func TestRetentionConfig_LongestDays(t *testing.T) {
+	retention := &RetentionConfig{DefaultDays: 365, EventTypes: map[string]int{"POLICY_CHECK": 90, "DATA_EXCHANGE": 730}}
+	if days, all := retention.LongestDays(); !all || days != 730 {
+		t.Errorf("Expected every log to expire after 730 days, got %d, %v", days, all)
+	}
+	if _, all := (&RetentionConfig{EventTypes: map[string]int{"POLICY_CHECK": 90}}).LongestDays(); all {
+		t.Error("Expected logs under the default policy never to expire")
+	}
+}
+
+func TestLoadRetention_InvalidYAML(t *testing.T) {
+	tests := map[string]string{
+		"unparseable":      "retention: [",
+		"negative default": "retention:\n  defaultDays: -1\n",
+		"negative type":    "retention:\n  eventTypes:\n    POLICY_CHECK: -30\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "retention.yaml")
+			if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+			if _, err := LoadRetention(configPath); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
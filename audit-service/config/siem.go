@@ -0,0 +1,302 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SIEM event formats
+const (
+	SIEMFormatCEF  = "CEF"
+	SIEMFormatLEEF = "LEEF"
+)
+
+// SIEM syslog transports
+const (
+	SIEMTransportTLS = "tls"
+	SIEMTransportTCP = "tcp"
+	SIEMTransportUDP = "udp"
+)
+
+// Log attributes that SIEM fields can be mapped from, in addition to metadata keys
+// (requestMetadata.<key>, responseMetadata.<key> and additionalMetadata.<key>)
+var siemAttributes = map[string]bool{
+	"id": true, "eventId": true, "timestamp": true, "traceId": true, "correlationId": true, "source": true,
+	"status": true, "eventType": true, "eventAction": true, "actorType": true, "actorId": true,
+	"targetType": true, "targetId": true, "hash": true,
+}
+
+// siemMetadataPrefixes are the metadata attributes whose top-level keys fields can be mapped from
+var siemMetadataPrefixes = []string{"requestMetadata.", "responseMetadata.", "additionalMetadata."}
+
+// siemFieldKey matches CEF and LEEF extension keys
+var siemFieldKey = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// Default CEF fields: standard keys where CEF has one, custom strings (labeled with the attribute) otherwise
+var defaultCEFFields = map[string]string{
+	"externalId": "id",
+	"rt":         "timestamp",
+	"act":        "eventAction",
+	"outcome":    "status",
+	"suser":      "actorId",
+	"cs1":        "actorType",
+	"duid":       "targetId",
+	"cs2":        "targetType",
+	"cs3":        "traceId",
+	"cs4":        "correlationId",
+}
+
+// Default LEEF fields, using LEEF 2.0 predefined keys where one exists
+var defaultLEEFFields = map[string]string{
+	"devTime":    "timestamp",
+	"usrName":    "actorId",
+	"actorType":  "actorType",
+	"resource":   "targetId",
+	"targetType": "targetType",
+	"action":     "eventAction",
+	"outcome":    "status",
+	"externalId": "id",
+	"traceId":    "traceId",
+}
+
+// SIEMConfig configures the exporter that forwards every stored audit log to a SIEM over syslog
+type SIEMConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Address is the host:port of the SIEM's syslog receiver
+	Address string `yaml:"address"`
+
+	// Transport is tls (default, RFC 5425), tcp (RFC 6587 octet counting) or udp (RFC 5426)
+	Transport string        `yaml:"transport"`
+	TLS       SIEMTLSConfig `yaml:"tls"`
+
+	// Format is CEF (default) or LEEF
+	Format string `yaml:"format"`
+
+	// Facility is the syslog facility, 13 (log audit) by default
+	Facility int `yaml:"facility"`
+
+	// AppName and Hostname identify the sender in the syslog header; Hostname defaults to the machine's
+	AppName  string `yaml:"appName"`
+	Hostname string `yaml:"hostname"`
+
+	// Vendor, Product and Version identify the device in the CEF or LEEF header
+	Vendor  string `yaml:"vendor"`
+	Product string `yaml:"product"`
+	Version string `yaml:"version"`
+
+	// BufferSize bounds the logs waiting to be delivered, 10000 by default; logs are dropped while it is full
+	BufferSize int `yaml:"bufferSize"`
+
+	Mapping SIEMMapping `yaml:"mapping"`
+}
+
+// SIEMTLSConfig configures the TLS connection to the SIEM. The system roots verify the SIEM's
+// certificate unless a CA file is given; a client certificate is presented when one is given.
+type SIEMTLSConfig struct {
+	CAFile     string `yaml:"caFile"`
+	CertFile   string `yaml:"certFile"`
+	KeyFile    string `yaml:"keyFile"`
+	ServerName string `yaml:"serverName"`
+}
+
+// SIEMMapping maps audit logs to SIEM events
+type SIEMMapping struct {
+	// DefaultSeverity applies to successful events of types without their own severity, 3 by default
+	DefaultSeverity *int `yaml:"defaultSeverity"`
+
+	// FailureSeverity applies to failed events of types without their own severity, 7 by default
+	FailureSeverity *int `yaml:"failureSeverity"`
+
+	// EventTypes maps an event type to its SIEM event name and severity
+	EventTypes map[string]SIEMEventMapping `yaml:"eventTypes"`
+
+	// Fields maps extension keys to the log attributes they carry; the format's defaults apply when empty.
+	// CEF custom fields (cs1 to cs6) are labeled with the attribute they carry.
+	Fields map[string]string `yaml:"fields"`
+}
+
+// SIEMEventMapping is the SIEM event an event type is exported as
+type SIEMEventMapping struct {
+	// Name is the event name, the event type by default
+	Name string `yaml:"name"`
+
+	// Severity (0 to 10) overrides the default and failure severities
+	Severity *int `yaml:"severity"`
+}
+
+// siemFile is the top-level structure of the SIEM YAML file
+type siemFile struct {
+	SIEM SIEMConfig `yaml:"siem"`
+}
+
+// LoadSIEM loads the SIEM export configuration from a YAML file
+// If the file is not found, logs are not exported
+func LoadSIEM(configPath string) (*SIEMConfig, error) {
+	if configPath == "" {
+		configPath = "config/siem.yaml"
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SIEMConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read SIEM config file %s: %w", configPath, err)
+	}
+
+	var file siemFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse SIEM config file %s: %w", configPath, err)
+	}
+	if err := file.SIEM.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid SIEM config file %s: %w", configPath, err)
+	}
+	return &file.SIEM, nil
+}
+
+// Validate checks an enabled configuration and applies defaults
+func (c *SIEMConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return fmt.Errorf("invalid address %q: %w", c.Address, err)
+	}
+
+	c.Transport = strings.ToLower(c.Transport)
+	switch c.Transport {
+	case "":
+		c.Transport = SIEMTransportTLS
+	case SIEMTransportTLS, SIEMTransportTCP, SIEMTransportUDP:
+	default:
+		return fmt.Errorf("invalid transport %q", c.Transport)
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("tls certFile and keyFile must be set together")
+	}
+
+	c.Format = strings.ToUpper(c.Format)
+	switch c.Format {
+	case "":
+		c.Format = SIEMFormatCEF
+	case SIEMFormatCEF, SIEMFormatLEEF:
+	default:
+		return fmt.Errorf("invalid format %q", c.Format)
+	}
+
+	if c.Facility == 0 {
+		c.Facility = 13
+	}
+	if c.Facility < 0 || c.Facility > 23 {
+		return fmt.Errorf("facility must be between 0 and 23, got %d", c.Facility)
+	}
+	if c.AppName == "" {
+		c.AppName = "audit-service"
+	}
+	if c.Vendor == "" {
+		c.Vendor = "OpenDIF"
+	}
+	if c.Product == "" {
+		c.Product = "Audit Service"
+	}
+	if c.Version == "" {
+		c.Version = "1.0"
+	}
+	if c.BufferSize == 0 {
+		c.BufferSize = 10000
+	}
+	if c.BufferSize < 0 {
+		return fmt.Errorf("bufferSize must not be negative, got %d", c.BufferSize)
+	}
+
+	return c.Mapping.validate(c.Format)
+}
+
+func (m *SIEMMapping) validate(format string) error {
+	if m.DefaultSeverity == nil {
+		m.DefaultSeverity = intPtr(3)
+	}
+	if m.FailureSeverity == nil {
+		m.FailureSeverity = intPtr(7)
+	}
+	if err := validateSeverity("defaultSeverity", *m.DefaultSeverity); err != nil {
+		return err
+	}
+	if err := validateSeverity("failureSeverity", *m.FailureSeverity); err != nil {
+		return err
+	}
+	for eventType, event := range m.EventTypes {
+		if event.Severity != nil {
+			if err := validateSeverity("severity of "+eventType, *event.Severity); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(m.Fields) == 0 {
+		m.Fields = maps.Clone(defaultCEFFields)
+		if format == SIEMFormatLEEF {
+			m.Fields = maps.Clone(defaultLEEFFields)
+		}
+	}
+	for key, attribute := range m.Fields {
+		if !siemFieldKey.MatchString(key) {
+			return fmt.Errorf("invalid field key %q", key)
+		}
+		if !IsSIEMAttribute(attribute) {
+			return fmt.Errorf("field %s maps unknown attribute %q", key, attribute)
+		}
+	}
+	return nil
+}
+
+// IsSIEMAttribute reports whether a SIEM field can be mapped from the log attribute
+func IsSIEMAttribute(attribute string) bool {
+	if siemAttributes[attribute] {
+		return true
+	}
+	for _, prefix := range siemMetadataPrefixes {
+		if key, ok := strings.CutPrefix(attribute, prefix); ok && key != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity returns the severity of an event type's events with the given status
+func (m *SIEMMapping) Severity(eventType string, failed bool) int {
+	if event, ok := m.EventTypes[eventType]; ok && event.Severity != nil {
+		return *event.Severity
+	}
+	if failed {
+		return *m.FailureSeverity
+	}
+	return *m.DefaultSeverity
+}
+
+// EventName returns the SIEM event name of an event type
+func (m *SIEMMapping) EventName(eventType string) string {
+	if event, ok := m.EventTypes[eventType]; ok && event.Name != "" {
+		return event.Name
+	}
+	return eventType
+}
+
+func validateSeverity(name string, severity int) error {
+	if severity < 0 || severity > 10 {
+		return fmt.Errorf("%s must be between 0 and 10, got %d", name, severity)
+	}
+	return nil
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSIEM_MissingFile(t *testing.T) {
+	siem, err := LoadSIEM("/nonexistent/path/siem.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error for non-existent file, got: %v", err)
+	}
+	if siem.Enabled {
+		t.Error("Expected SIEM export to be disabled without a config file")
+	}
+}
+
+func TestLoadSIEM_Defaults(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "siem.yaml")
+	configContent := `siem:
+  enabled: true
+  address: siem.example.com:6514
+  mapping:
+    eventTypes:
+      POLICY_CHECK:
+        name: Policy decision
+        severity: 5
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	siem, err := LoadSIEM(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if siem.Transport != SIEMTransportTLS || siem.Format != SIEMFormatCEF {
+		t.Errorf("Expected tls transport and CEF format, got %s and %s", siem.Transport, siem.Format)
+	}
+	if siem.Facility != 13 || siem.BufferSize != 10000 || siem.AppName != "audit-service" {
+		t.Errorf("Unexpected defaults: facility %d, bufferSize %d, appName %s", siem.Facility, siem.BufferSize, siem.AppName)
+	}
+	if siem.Mapping.Fields["suser"] != "actorId" {
+		t.Errorf("Expected the default CEF fields, got %v", siem.Mapping.Fields)
+	}
+	if got := siem.Mapping.Severity("POLICY_CHECK", true); got != 5 {
+		t.Errorf("Expected the event type's severity 5, got %d", got)
+	}
+	if got := siem.Mapping.Severity("DATA_REQUEST", true); got != 7 {
+		t.Errorf("Expected the failure severity 7, got %d", got)
+	}
+	if got := siem.Mapping.Severity("DATA_REQUEST", false); got != 3 {
+		t.Errorf("Expected the default severity 3, got %d", got)
+	}
+	if got := siem.Mapping.EventName("POLICY_CHECK"); got != "Policy decision" {
+		t.Errorf("Expected the mapped event name, got %s", got)
+	}
+	if got := siem.Mapping.EventName("DATA_REQUEST"); got != "DATA_REQUEST" {
+		t.Errorf("Expected the event type as event name, got %s", got)
+	}
+}
+
+func TestLoadSIEM_Disabled(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "siem.yaml")
+	// Disabled configurations are not validated
+	if err := os.WriteFile(configPath, []byte("siem:\n  enabled: false\n  transport: carrier-pigeon\n"), 0o644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	siem, err := LoadSIEM(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if siem.Enabled {
+		t.Error("Expected SIEM export to be disabled")
+	}
+}
+
+func TestLoadSIEM_InvalidYAML(t *testing.T) {
+	enabled := "siem:\n  enabled: true\n  address: siem:514\n"
+	tests := map[string]string{
+		"unparseable":       "siem: [",
+		"missing address":   "siem:\n  enabled: true\n",
+		"invalid transport": enabled + "  transport: http\n",
+		"invalid format":    enabled + "  format: JSON\n",
+		"invalid facility":  enabled + "  facility: 24\n",
+		"cert without key":  enabled + "  tls:\n    certFile: client.pem\n",
+		"invalid severity":  enabled + "  mapping:\n    failureSeverity: 11\n",
+		"invalid key":       enabled + "  mapping:\n    fields:\n      user-name: actorId\n",
+		"unknown attribute": enabled + "  mapping:\n    fields:\n      suser: actorEmail\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "siem.yaml")
+			if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+			if _, err := LoadSIEM(configPath); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestIsSIEMAttribute(t *testing.T) {
+	for _, attribute := range []string{"actorId", "timestamp", "requestMetadata.applicationId"} {
+		if !IsSIEMAttribute(attribute) {
+			t.Errorf("Expected %s to be a SIEM attribute", attribute)
+		}
+	}
+	for _, attribute := range []string{"", "actorEmail", "requestMetadata.", "metadata.applicationId"} {
+		if IsSIEMAttribute(attribute) {
+			t.Errorf("Expected %s not to be a SIEM attribute", attribute)
+		}
+	}
+}
@@ -3,7 +3,12 @@ module github.com/gov-dx-sandbox/audit-service
 go 1.24.6
 
 require (
+	github.com/golang-jwt/jwt/v5 v5.2.1
 	github.com/google/uuid v1.6.0
+	github.com/gov-dx-sandbox/shared/health v0.0.0
+	github.com/gov-dx-sandbox/shared/requestid v0.0.0
+	github.com/gov-dx-sandbox/shared/s3 v0.0.0
+	github.com/jackc/pgx/v5 v5.6.0
 	github.com/stretchr/testify v1.8.1
 	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/driver/postgres v1.6.0
@@ -11,11 +16,16 @@ require (
 	gorm.io/gorm v1.31.1
 )
 
+replace github.com/gov-dx-sandbox/shared/health => ../shared/health
+
+replace github.com/gov-dx-sandbox/shared/requestid => ../shared/requestid
+
+replace github.com/gov-dx-sandbox/shared/s3 => ../shared/s3
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
-	github.com/jackc/pgx/v5 v5.6.0 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
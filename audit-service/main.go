@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"flag"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -17,7 +19,11 @@ import (
 	v1database "github.com/gov-dx-sandbox/audit-service/v1/database"
 	v1handlers "github.com/gov-dx-sandbox/audit-service/v1/handlers"
 	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/schemas"
 	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"github.com/gov-dx-sandbox/shared/s3"
 )
 
 // Build information - set during build
@@ -35,6 +41,9 @@ func main() {
 	)
 	flag.Parse()
 
+	// Records logged with a request's context include its request ID
+	slog.SetDefault(slog.New(requestid.NewLogHandler(slog.NewTextHandler(os.Stdout, nil))))
+
 	// Server configuration
 	serverPort := *port
 
@@ -78,21 +87,6 @@ func main() {
 	// Setup routes
 	mux := http.NewServeMux()
 
-	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-
-		// Simple health check - just return healthy if service is running
-		// Database connectivity is checked during startup, not in health check
-		w.WriteHeader(http.StatusOK)
-		response := map[string]string{
-			"service": "audit-service",
-			"status":  "healthy",
-		}
-
-		json.NewEncoder(w).Encode(response)
-	})
-
 	// Version endpoint
 	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -108,23 +102,346 @@ func main() {
 		json.NewEncoder(w).Encode(response)
 	})
 
+	// Read APIs require an Asgardeo access token when ASGARDEO_BASE_URL (or ASGARDEO_JWKS_URL) is set:
+	// admins see all logs, entity users only logs involving the IDs in their AUDIT_AUTH_ENTITY_ID_CLAIM claim.
+	// Audit events are still accepted from services without a token.
+	var readAuth *middleware.JWTAuthMiddleware
+	asgardeoBaseURL := os.Getenv("ASGARDEO_BASE_URL")
+	jwksURL, issuer := os.Getenv("ASGARDEO_JWKS_URL"), os.Getenv("ASGARDEO_TOKEN_URL")
+	if asgardeoBaseURL != "" {
+		jwksURL = config.GetEnvOrDefault("ASGARDEO_JWKS_URL", asgardeoBaseURL+"/oauth2/jwks")
+		issuer = config.GetEnvOrDefault("ASGARDEO_TOKEN_URL", asgardeoBaseURL+"/oauth2/token")
+	}
+	if jwksURL != "" {
+		var validClientIDs []string
+		for _, name := range []string{"ASGARDEO_MEMBER_PORTAL_CLIENT_ID", "ASGARDEO_ADMIN_PORTAL_CLIENT_ID"} {
+			if clientID := os.Getenv(name); clientID != "" {
+				validClientIDs = append(validClientIDs, clientID)
+			}
+		}
+		jwtConfig := middleware.JWTAuthConfig{
+			JWKSURL:        jwksURL,
+			ExpectedIssuer: issuer,
+			ValidClientIDs: validClientIDs,
+			OrgName:        os.Getenv("ASGARDEO_ORG_NAME"),
+			EntityIDClaim:  config.GetEnvOrDefault("AUDIT_AUTH_ENTITY_ID_CLAIM", middleware.DefaultEntityIDClaim),
+		}
+		if err := jwtConfig.Validate(); err != nil {
+			slog.Error("Invalid JWT configuration", "error", err)
+			os.Exit(1)
+		}
+		readAuth = middleware.NewJWTAuthMiddleware(jwtConfig)
+	} else {
+		slog.Warn("ASGARDEO_BASE_URL not set, audit log read APIs are unauthenticated")
+	}
+
+	// Time partitioning: with AUDIT_LOG_TIME_PARTITIONING on PostgreSQL, a new audit_logs table is partitioned
+	// by month, so that expired months are dropped whole; it must be created before the schema is migrated
+	if timePartitioning, _ := strconv.ParseBool(os.Getenv("AUDIT_LOG_TIME_PARTITIONING")); timePartitioning {
+		if err := v1database.PartitionAuditLogs(gormDB); err != nil {
+			slog.Warn("Audit logs are not partitioned by time", "error", err)
+		}
+	}
+
 	// Initialize v1 API with database-agnostic repository
 	v1Repository := v1database.NewGormRepository(gormDB)
 	v1AuditService := v1services.NewAuditService(v1Repository)
+
+	// Write batching: logs stored concurrently over HTTP are appended to the hash chain in batches of up to
+	// AUDIT_WRITE_BATCH_SIZE, each waiting at most AUDIT_WRITE_BATCH_DELAY for more; a size of 1 disables it
+	writeBatchSize, err := strconv.Atoi(config.GetEnvOrDefault("AUDIT_WRITE_BATCH_SIZE", strconv.Itoa(v1services.DefaultWriteBatchSize)))
+	if err != nil || writeBatchSize < 1 {
+		slog.Warn("Invalid AUDIT_WRITE_BATCH_SIZE, using default", "error", err, "default", v1services.DefaultWriteBatchSize)
+		writeBatchSize = v1services.DefaultWriteBatchSize
+	}
+	writeBatchDelay, err := time.ParseDuration(config.GetEnvOrDefault("AUDIT_WRITE_BATCH_DELAY", v1services.DefaultWriteBatchDelay.String()))
+	if err != nil {
+		slog.Warn("Invalid AUDIT_WRITE_BATCH_DELAY, using default", "error", err, "default", v1services.DefaultWriteBatchDelay)
+		writeBatchDelay = v1services.DefaultWriteBatchDelay
+	}
+	batchCtx, stopBatching := context.WithCancel(context.Background())
+	defer stopBatching()
+	var v1WriteBatcher *v1services.WriteBatcher
+	if writeBatchSize > 1 {
+		v1WriteBatcher = v1services.NewWriteBatcher(v1Repository, writeBatchSize, writeBatchDelay)
+		v1AuditService.SetWriteBatcher(v1WriteBatcher)
+		go v1WriteBatcher.Start(batchCtx)
+	}
 	v1AuditHandler := v1handlers.NewAuditHandler(v1AuditService)
 
+	// Events of types with a schema (v1/schemas/definitions) are validated against it before they are stored.
+	// The registry is public so that producing services can discover the expected payloads.
+	eventSchemas, err := schemas.DefaultRegistry()
+	if err != nil {
+		slog.Error("Failed to load event schemas", "error", err)
+		os.Exit(1)
+	}
+	v1AuditService.SetEventSchemas(eventSchemas)
+	v1EventTypeHandler := v1handlers.NewEventTypeHandler(eventSchemas)
+	mux.HandleFunc("/api/audit-logs/event-types", v1EventTypeHandler.HandleEventTypes)
+	mux.HandleFunc("/api/audit-logs/event-types/", v1EventTypeHandler.HandleEventTypes)
+	slog.Info("Loaded event schemas", "eventTypes", len(eventSchemas.EventTypes()))
+
+	// Alerting: every stored log is evaluated against the rules in AUDIT_ALERTS_CONFIG; alerts are recorded
+	// and sent to the rules' webhooks (signed with ALERT_WEBHOOK_SECRET) and emails (via ALERT_SMTP_*)
+	alertsPath := config.GetEnvOrDefault("AUDIT_ALERTS_CONFIG", "config/alerts.yaml")
+	alerts, err := config.LoadAlerts(alertsPath)
+	if err != nil {
+		slog.Error("Failed to load alert rules", "error", err, "path", alertsPath)
+		os.Exit(1)
+	}
+	smtpPort, err := strconv.Atoi(config.GetEnvOrDefault("ALERT_SMTP_PORT", "587"))
+	if err != nil {
+		slog.Warn("Invalid ALERT_SMTP_PORT, using default", "error", err, "default", 587)
+		smtpPort = 587
+	}
+	v1AlertService := v1services.NewAlertService(v1Repository, alerts, v1services.NewAlertNotifier(
+		os.Getenv("ALERT_WEBHOOK_SECRET"),
+		v1services.SMTPConfig{
+			Host:     os.Getenv("ALERT_SMTP_HOST"),
+			Port:     smtpPort,
+			Username: os.Getenv("ALERT_SMTP_USERNAME"),
+			Password: os.Getenv("ALERT_SMTP_PASSWORD"),
+			From:     config.GetEnvOrDefault("ALERT_EMAIL_FROM", "audit-service@localhost"),
+		},
+	))
+	v1AuditService.SetAlertService(v1AlertService)
+	v1AlertHandler := v1handlers.NewAlertHandler(v1AlertService)
+	slog.Info("Loaded alert rules", "rules", len(alerts.Rules))
+
+	mux.HandleFunc("/api/audit-logs/alerts", readAuth.AuthenticateAdmin(v1AlertHandler.HandleAlerts))
+	mux.HandleFunc("/api/audit-logs/alerts/", readAuth.AuthenticateAdmin(v1AlertHandler.HandleAlerts))
+
+	// SIEM export: when enabled in AUDIT_SIEM_CONFIG, every stored log is also forwarded to the SIEM as a
+	// CEF or LEEF event over syslog; logs are buffered in memory while the SIEM is unreachable
+	siemPath := config.GetEnvOrDefault("AUDIT_SIEM_CONFIG", "config/siem.yaml")
+	siemConfig, err := config.LoadSIEM(siemPath)
+	if err != nil {
+		slog.Error("Failed to load SIEM export configuration", "error", err, "path", siemPath)
+		os.Exit(1)
+	}
+	siemCtx, stopSIEMExport := context.WithCancel(context.Background())
+	defer stopSIEMExport()
+	var v1SIEMExporter *v1services.SIEMExporter
+	if siemConfig.Enabled {
+		v1SIEMExporter, err = v1services.NewSIEMExporter(siemConfig)
+		if err != nil {
+			slog.Error("Invalid SIEM export configuration", "error", err)
+			os.Exit(1)
+		}
+		v1AuditService.SetSIEMExporter(v1SIEMExporter)
+		go v1SIEMExporter.Start(siemCtx)
+	}
+	v1SIEMHandler := v1handlers.NewSIEMHandler(v1SIEMExporter)
+	mux.HandleFunc("/api/audit-logs/siem/status", readAuth.AuthenticateAdmin(v1SIEMHandler.GetStatus))
+
+	// Exports stream directly up to EXPORT_MAX_SYNC_ROWS logs; larger ones run as jobs writing to EXPORT_DIR
+	exportDir := config.GetEnvOrDefault("EXPORT_DIR", "./data/exports")
+	maxSyncExportRows, err := strconv.ParseInt(config.GetEnvOrDefault("EXPORT_MAX_SYNC_ROWS", strconv.Itoa(v1services.DefaultMaxSyncExportRows)), 10, 64)
+	if err != nil {
+		slog.Warn("Invalid EXPORT_MAX_SYNC_ROWS, using default", "error", err, "default", v1services.DefaultMaxSyncExportRows)
+		maxSyncExportRows = v1services.DefaultMaxSyncExportRows
+	}
+	v1ExportService := v1services.NewExportService(v1Repository, v1Repository, exportDir, maxSyncExportRows)
+	if err := v1ExportService.FailUnfinishedJobs(context.Background()); err != nil {
+		slog.Warn("Failed to clean up interrupted export jobs", "error", err)
+	}
+	v1ExportHandler := v1handlers.NewExportHandler(v1ExportService)
+
 	// API endpoint for generalized audit logs (V1)
+	getAuditLogs := readAuth.Authenticate(v1AuditHandler.GetAuditLogs)
 	mux.HandleFunc("/api/audit-logs", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
 			v1AuditHandler.CreateAuditLog(w, r)
 		case http.MethodGet:
-			v1AuditHandler.GetAuditLogs(w, r)
+			getAuditLogs(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
 
+	// Tamper evidence: logs are hash-chained on write; the chain head is periodically signed
+	// with AUDIT_CHECKPOINT_SIGNING_KEY (base64 Ed25519 seed) so the chain cannot be silently rewritten
+	var checkpointSigningKey ed25519.PrivateKey
+	if encodedKey := os.Getenv("AUDIT_CHECKPOINT_SIGNING_KEY"); encodedKey != "" {
+		checkpointSigningKey, err = v1services.ParseCheckpointSigningKey(encodedKey)
+		if err != nil {
+			slog.Error("Invalid AUDIT_CHECKPOINT_SIGNING_KEY", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		slog.Warn("AUDIT_CHECKPOINT_SIGNING_KEY not set, signed checkpoints are disabled")
+	}
+	checkpointInterval, err := time.ParseDuration(config.GetEnvOrDefault("AUDIT_CHECKPOINT_INTERVAL", v1services.DefaultCheckpointInterval.String()))
+	if err != nil {
+		slog.Warn("Invalid AUDIT_CHECKPOINT_INTERVAL, using default", "error", err, "default", v1services.DefaultCheckpointInterval)
+		checkpointInterval = v1services.DefaultCheckpointInterval
+	}
+	v1IntegrityService := v1services.NewIntegrityService(v1Repository, checkpointSigningKey)
+	v1IntegrityHandler := v1handlers.NewIntegrityHandler(v1IntegrityService)
+
+	checkpointCtx, stopCheckpointing := context.WithCancel(context.Background())
+	defer stopCheckpointing()
+	go v1IntegrityService.StartCheckpointing(checkpointCtx, checkpointInterval)
+
+	// Every log of one exchange request, by trace or correlation ID; entity users see only their own
+	mux.HandleFunc("/api/audit-logs/trace/", readAuth.Authenticate(v1AuditHandler.GetTrace))
+	// Management events of one resource (e.g. an application), oldest first, across the services recording them
+	mux.HandleFunc("/api/events/resource/", readAuth.Authenticate(v1AuditHandler.GetResourceTimeline))
+	mux.HandleFunc("/api/audit-logs/verify", readAuth.AuthenticateAdmin(v1IntegrityHandler.VerifyChain))
+
+	// Retention: logs older than their event type's period (AUDIT_RETENTION_CONFIG) are archived
+	// as gzipped NDJSON to the S3-compatible bucket ARCHIVE_S3_BUCKET and then deleted
+	retentionPath := config.GetEnvOrDefault("AUDIT_RETENTION_CONFIG", "config/retention.yaml")
+	retention, err := config.LoadRetention(retentionPath)
+	if err != nil {
+		slog.Error("Failed to load retention configuration", "error", err, "path", retentionPath)
+		os.Exit(1)
+	}
+	var archiveStore v1services.ArchiveStore
+	if bucket := os.Getenv("ARCHIVE_S3_BUCKET"); bucket != "" {
+		pathStyle, _ := strconv.ParseBool(config.GetEnvOrDefault("ARCHIVE_S3_PATH_STYLE", "true"))
+		s3Store, err := v1services.NewS3ArchiveStore(s3.Config{
+			Endpoint:        config.GetEnvOrDefault("ARCHIVE_S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:          config.GetEnvOrDefault("ARCHIVE_S3_REGION", "us-east-1"),
+			Bucket:          bucket,
+			AccessKeyID:     os.Getenv("ARCHIVE_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("ARCHIVE_S3_SECRET_ACCESS_KEY"),
+			PathStyle:       pathStyle,
+		})
+		if err != nil {
+			slog.Error("Invalid archive storage configuration", "error", err)
+			os.Exit(1)
+		}
+		archiveStore = s3Store
+	} else if retention.Enabled() {
+		slog.Warn("ARCHIVE_S3_BUCKET not set, expired audit logs will not be archived or deleted")
+	}
+	archiveInterval, err := time.ParseDuration(config.GetEnvOrDefault("ARCHIVE_INTERVAL", v1services.DefaultArchiveInterval.String()))
+	if err != nil {
+		slog.Warn("Invalid ARCHIVE_INTERVAL, using default", "error", err, "default", v1services.DefaultArchiveInterval)
+		archiveInterval = v1services.DefaultArchiveInterval
+	}
+	v1ArchiveService := v1services.NewArchiveService(v1Repository, v1Repository, archiveStore, retention,
+		config.GetEnvOrDefault("ARCHIVE_S3_PREFIX", v1services.DefaultArchivePrefix))
+	if err := v1ArchiveService.FailUnfinishedRuns(context.Background()); err != nil {
+		slog.Warn("Failed to clean up interrupted archive runs", "error", err)
+	}
+	v1ArchiveHandler := v1handlers.NewArchiveHandler(v1ArchiveService)
+
+	archiveCtx, stopArchiving := context.WithCancel(context.Background())
+	defer stopArchiving()
+	go v1ArchiveService.StartScheduler(archiveCtx, archiveInterval)
+
+	mux.HandleFunc("/api/audit-logs/archive-runs", readAuth.AuthenticateAdmin(v1ArchiveHandler.HandleArchiveRuns))
+	mux.HandleFunc("/api/audit-logs/archive-runs/", readAuth.AuthenticateAdmin(v1ArchiveHandler.HandleArchiveRuns))
+
+	// Time partition maintenance: when audit_logs is partitioned, partitions are created
+	// TIME_PARTITION_PREMAKE_MONTHS ahead, and months whose every log has expired are archived whole
+	// and dropped, every TIME_PARTITION_MAINTENANCE_INTERVAL
+	timePartitionInterval, err := time.ParseDuration(config.GetEnvOrDefault("TIME_PARTITION_MAINTENANCE_INTERVAL", v1services.DefaultTimePartitionInterval.String()))
+	if err != nil {
+		slog.Warn("Invalid TIME_PARTITION_MAINTENANCE_INTERVAL, using default", "error", err, "default", v1services.DefaultTimePartitionInterval)
+		timePartitionInterval = v1services.DefaultTimePartitionInterval
+	}
+	premakeMonths, err := strconv.Atoi(config.GetEnvOrDefault("TIME_PARTITION_PREMAKE_MONTHS", strconv.Itoa(v1services.DefaultTimePartitionPremakeMonths)))
+	if err != nil {
+		slog.Warn("Invalid TIME_PARTITION_PREMAKE_MONTHS, using default", "error", err, "default", v1services.DefaultTimePartitionPremakeMonths)
+		premakeMonths = v1services.DefaultTimePartitionPremakeMonths
+	}
+	v1TimePartitionService := v1services.NewTimePartitionService(v1Repository, v1ArchiveService, retention, premakeMonths)
+
+	timePartitionCtx, stopTimePartitionMaintenance := context.WithCancel(context.Background())
+	defer stopTimePartitionMaintenance()
+	go v1TimePartitionService.StartScheduler(timePartitionCtx, timePartitionInterval)
+
+	// Queue ingestion: when AUDIT_QUEUE_URL is set, audit events are also consumed from a NATS JetStream
+	// stream; events carry an eventId so that redelivered messages are stored only once
+	v1IngestionService := v1services.NewIngestionService(v1AuditService, v1Repository, v1services.QueueConfig{
+		URL:      os.Getenv("AUDIT_QUEUE_URL"),
+		Stream:   config.GetEnvOrDefault("AUDIT_QUEUE_STREAM", v1services.DefaultQueueStream),
+		Subject:  config.GetEnvOrDefault("AUDIT_QUEUE_SUBJECT", v1services.DefaultQueueSubject),
+		Consumer: config.GetEnvOrDefault("AUDIT_QUEUE_CONSUMER", v1services.DefaultQueueConsumer),
+	})
+	v1IngestionHandler := v1handlers.NewIngestionHandler(v1IngestionService)
+
+	consumerCtx, stopConsuming := context.WithCancel(context.Background())
+	defer stopConsuming()
+	go v1IngestionService.StartConsumer(consumerCtx)
+
+	// Health check endpoints: events cannot be stored without the database; a disconnected queue
+	// consumer only delays the events that are not also sent over HTTP, and a disconnected SIEM
+	// export only delays forwarding
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		slog.Error("Failed to get database connection", "error", err)
+		os.Exit(1)
+	}
+	healthChecker := health.NewChecker("audit-service")
+	healthChecker.Register("database", health.PingCheck(sqlDB))
+	if v1IngestionService.QueueEnabled() {
+		healthChecker.RegisterOptional("audit_queue", v1IngestionService.CheckQueue)
+	}
+	if v1SIEMExporter != nil {
+		healthChecker.RegisterOptional("siem_export", v1SIEMExporter.CheckHealth)
+	}
+	healthChecker.RegisterRoutes(mux)
+
+	mux.HandleFunc("/api/audit-logs/ingestion/reconciliation", readAuth.AuthenticateAdmin(v1IngestionHandler.Reconcile))
+
+	// Aggregate statistics for the admin portal dashboards
+	v1StatsHandler := v1handlers.NewStatsHandler(v1services.NewStatsService(v1Repository))
+	mux.HandleFunc("/api/audit-logs/stats", readAuth.AuthenticateAdmin(v1StatsHandler.GetStats))
+
+	// Data subject access reports: which consumers accessed a data owner's fields, as JSON or PDF
+	v1SubjectReportHandler := v1handlers.NewSubjectReportHandler(v1services.NewSubjectReportService(v1Repository))
+	mux.HandleFunc("/api/audit-logs/subject/", readAuth.AuthenticateAdmin(v1SubjectReportHandler.GetSubjectAccessReport))
+
+	// Tenant partitions: logs are partitioned by the provider and consumer entities they involve, and entity
+	// users read only their own partitions; maintenance runs at startup and every PARTITION_MAINTENANCE_INTERVAL
+	partitionMaintenanceInterval, err := time.ParseDuration(config.GetEnvOrDefault("PARTITION_MAINTENANCE_INTERVAL", v1services.DefaultPartitionMaintenanceInterval.String()))
+	if err != nil {
+		slog.Warn("Invalid PARTITION_MAINTENANCE_INTERVAL, using default", "error", err, "default", v1services.DefaultPartitionMaintenanceInterval)
+		partitionMaintenanceInterval = v1services.DefaultPartitionMaintenanceInterval
+	}
+	v1TenantService := v1services.NewTenantService(v1Repository)
+	v1TenantHandler := v1handlers.NewTenantHandler(v1TenantService)
+
+	partitionCtx, stopPartitionMaintenance := context.WithCancel(context.Background())
+	defer stopPartitionMaintenance()
+	go v1TenantService.StartScheduler(partitionCtx, partitionMaintenanceInterval)
+
+	mux.HandleFunc("/api/audit-logs/tenants", readAuth.AuthenticateAdmin(v1TenantHandler.HandleTenants))
+	mux.HandleFunc("/api/audit-logs/tenants/", readAuth.AuthenticateAdmin(v1TenantHandler.HandleTenants))
+
+	// Data subject erasure: pseudonymizes a data owner's logs, keeping a log of erasures as evidence
+	v1ErasureHandler := v1handlers.NewErasureHandler(v1services.NewErasureService(v1Repository, v1Repository))
+	mux.HandleFunc("/api/audit-logs/erasures", readAuth.AuthenticateAdmin(v1ErasureHandler.HandleErasures))
+	mux.HandleFunc("/api/audit-logs/erasures/", readAuth.AuthenticateAdmin(v1ErasureHandler.HandleErasures))
+
+	// Live stream of stored logs and fired alerts (Server-Sent Events) for the admin portal's activity feed;
+	// the last STREAM_BUFFER_SIZE events are kept so that clients can resume after reconnecting
+	streamBufferSize, err := strconv.Atoi(config.GetEnvOrDefault("STREAM_BUFFER_SIZE", strconv.Itoa(v1services.DefaultStreamBufferSize)))
+	if err != nil {
+		slog.Warn("Invalid STREAM_BUFFER_SIZE, using default", "error", err, "default", v1services.DefaultStreamBufferSize)
+		streamBufferSize = v1services.DefaultStreamBufferSize
+	}
+	streamHeartbeat, err := time.ParseDuration(config.GetEnvOrDefault("STREAM_HEARTBEAT_INTERVAL", v1services.DefaultStreamHeartbeatInterval.String()))
+	if err != nil {
+		slog.Warn("Invalid STREAM_HEARTBEAT_INTERVAL, using default", "error", err, "default", v1services.DefaultStreamHeartbeatInterval)
+		streamHeartbeat = v1services.DefaultStreamHeartbeatInterval
+	}
+	v1StreamService := v1services.NewStreamService(streamBufferSize)
+	v1AuditService.SetStream(v1StreamService)
+	v1AlertService.SetStream(v1StreamService)
+	v1StreamHandler := v1handlers.NewStreamHandler(v1StreamService, streamHeartbeat)
+	mux.HandleFunc("/api/audit-logs/stream", readAuth.Authenticate(v1StreamHandler.StreamEvents))
+
+	// Audit log exports (CSV/NDJSON) and asynchronous export jobs (V1)
+	mux.HandleFunc("/api/audit-logs/export", readAuth.AuthenticateAdmin(v1ExportHandler.ExportAuditLogs))
+	mux.HandleFunc("/api/audit-logs/exports/", readAuth.AuthenticateAdmin(v1ExportHandler.HandleExportJobs))
+
 	// Start server
 	slog.Info("Audit Service starting",
 		"environment", *env,
@@ -138,8 +455,8 @@ func main() {
 	// Setup CORS middleware
 	corsMiddleware := middleware.NewCORSMiddleware()
 
-	// Apply middleware chain: CORS -> main handler
-	handler := corsMiddleware(mux)
+	// Apply middleware chain: request ID -> CORS -> main handler
+	handler := requestid.Middleware(corsMiddleware(mux))
 
 	server := &http.Server{
 		Addr:         ":" + serverPort,
@@ -176,5 +493,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Stop consuming only after in-flight HTTP requests are done; unacknowledged messages are redelivered
+	stopConsuming()
+	v1IngestionService.Wait()
+
+	// Store the last batch of logs written over HTTP; logs written after that are stored one by one
+	stopBatching()
+	if v1WriteBatcher != nil {
+		v1WriteBatcher.Wait()
+	}
+	v1AlertService.Wait()
+
+	// Forward the logs stored during shutdown before stopping the SIEM export
+	stopSIEMExport()
+	if v1SIEMExporter != nil {
+		v1SIEMExporter.Wait()
+	}
+
 	slog.Info("Audit Service exited")
 }
@@ -37,9 +37,11 @@ func DefaultCORSConfig() CORSConfig {
 		AllowedHeaders: []string{
 			"Origin", "Content-Type", "Accept", "Authorization",
 			"X-Requested-With", "X-CSRF-Token", "X-Request-ID",
+			"X-Actor-Type", "X-Actor-Id",
 		},
 		ExposedHeaders: []string{
-			"Content-Length", "X-Request-ID",
+			"Content-Length", "X-Request-ID", "X-Total-Count",
+			"Content-Disposition", "Location",
 		},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24 hours
@@ -0,0 +1,364 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// Roles issued by the identity provider, as used by portal-backend
+const (
+	RoleAdmin  = "OpenDIF_Admin"  // Reads all audit logs
+	RoleMember = "OpenDIF_Member" // Reads audit logs involving their own entities
+	RoleSystem = "OpenDIF_System" // Internal services; reads all audit logs
+)
+
+// DefaultEntityIDClaim is the token claim listing the member and provider IDs of an entity user
+const DefaultEntityIDClaim = "member_id"
+
+// JWKS represents the JSON Web Key Set structure
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK represents a single JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Principal is the authenticated caller of a read API
+type Principal struct {
+	Subject string
+	Email   string
+	Roles   []string
+	// EntityIDs are the member and provider IDs the caller acts for, from the entity ID claim
+	EntityIDs []string
+}
+
+// CanReadAll reports whether the caller may read every audit log
+func (p *Principal) CanReadAll() bool {
+	return slices.Contains(p.Roles, RoleAdmin) || slices.Contains(p.Roles, RoleSystem)
+}
+
+// Scope returns the IDs an entity user's audit logs are restricted to: their entity IDs and
+// their own user ID, which portal-backend records as the actor of their actions
+func (p *Principal) Scope() []string {
+	scope := append([]string{p.Subject}, p.EntityIDs...)
+	slices.Sort(scope)
+	return slices.Compact(scope)
+}
+
+// ActorType returns the audit actor type of the caller
+func (p *Principal) ActorType() string {
+	switch {
+	case slices.Contains(p.Roles, RoleAdmin):
+		return "ADMIN"
+	case slices.Contains(p.Roles, RoleSystem):
+		return "SYSTEM"
+	default:
+		return "MEMBER"
+	}
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if the request was authenticated
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// JWTAuthConfig contains configuration for JWT authentication
+type JWTAuthConfig struct {
+	JWKSURL        string
+	ExpectedIssuer string
+	ValidClientIDs []string // Client IDs of the portals whose tokens are accepted
+	OrgName        string
+	EntityIDClaim  string // Defaults to DefaultEntityIDClaim
+	Timeout        time.Duration
+}
+
+// Validate checks if the JWT configuration is valid
+func (c JWTAuthConfig) Validate() error {
+	if c.JWKSURL == "" {
+		return fmt.Errorf("JWKSURL is required for JWT authentication")
+	}
+	if c.ExpectedIssuer == "" {
+		return fmt.Errorf("ExpectedIssuer is required for JWT authentication")
+	}
+	if len(c.ValidClientIDs) == 0 {
+		return fmt.Errorf("at least one ValidClientID is required for JWT authentication")
+	}
+	for i, clientID := range c.ValidClientIDs {
+		if strings.TrimSpace(clientID) == "" {
+			return fmt.Errorf("ValidClientID at index %d is empty", i)
+		}
+	}
+	return nil
+}
+
+// JWTAuthMiddleware validates bearer tokens against the identity provider's JWKS
+// Thread-safe: All methods can be called concurrently from multiple goroutines
+type JWTAuthMiddleware struct {
+	config     JWTAuthConfig
+	httpClient *http.Client
+
+	// keysMutex guards both keys and lastFetch
+	keysMutex sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	lastFetch time.Time
+}
+
+// NewJWTAuthMiddleware creates a new JWT authentication middleware.
+// A nil middleware performs no authentication, for deployments without an identity provider.
+func NewJWTAuthMiddleware(config JWTAuthConfig) *JWTAuthMiddleware {
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.EntityIDClaim == "" {
+		config.EntityIDClaim = DefaultEntityIDClaim
+	}
+	return &JWTAuthMiddleware{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		keys:       make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Authenticate requires a valid token from an admin, system or member user.
+// Member users are admitted; handlers restrict what they see using PrincipalFromContext.
+func (j *JWTAuthMiddleware) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return j.authenticate(next, false)
+}
+
+// AuthenticateAdmin requires a valid token from an admin or system user
+func (j *JWTAuthMiddleware) AuthenticateAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return j.authenticate(next, true)
+}
+
+func (j *JWTAuthMiddleware) authenticate(next http.HandlerFunc, adminOnly bool) http.HandlerFunc {
+	if j == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(tokenString) == "" {
+			utils.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing authorization header", nil)
+			return
+		}
+
+		principal, err := j.validateToken(strings.TrimSpace(tokenString))
+		if err != nil {
+			slog.Warn("Token validation failed", "error", err, "path", r.URL.Path, "method", r.Method)
+			utils.RespondWithError(w, http.StatusUnauthorized, "Invalid access token", nil)
+			return
+		}
+
+		if !principal.CanReadAll() && (adminOnly || !slices.Contains(principal.Roles, RoleMember)) {
+			slog.Warn("Audit read access denied", "user", principal.Subject, "roles", principal.Roles, "path", r.URL.Path)
+			utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions", nil)
+			return
+		}
+
+		next(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	}
+}
+
+// validateToken validates a JWT token and returns the caller it was issued to
+func (j *JWTAuthMiddleware) validateToken(tokenString string) (*Principal, error) {
+	if err := j.ensureKeysFresh(); err != nil {
+		return nil, fmt.Errorf("failed to ensure fresh keys: %w", err)
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, j.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(j.config.ExpectedIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	audience, err := claims.GetAudience()
+	if err != nil {
+		return nil, fmt.Errorf("invalid audience: %w", err)
+	}
+	if !slices.ContainsFunc(audience, func(aud string) bool { return slices.Contains(j.config.ValidClientIDs, aud) }) {
+		return nil, fmt.Errorf("invalid audience: expected one of %v, got %v", j.config.ValidClientIDs, audience)
+	}
+	if j.config.OrgName != "" {
+		if orgName, _ := claims["org_name"].(string); orgName != j.config.OrgName {
+			return nil, fmt.Errorf("invalid org_name: expected %s, got %s", j.config.OrgName, orgName)
+		}
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("subject claim is missing")
+	}
+	email, _ := claims["email"].(string)
+
+	return &Principal{
+		Subject:   subject,
+		Email:     email,
+		Roles:     claimStrings(claims, "roles"),
+		EntityIDs: claimStrings(claims, j.config.EntityIDClaim),
+	}, nil
+}
+
+// keyFunc returns the public key a token was signed with, refreshing the JWKS once for unknown key IDs
+func (j *JWTAuthMiddleware) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing 'kid' in token header")
+	}
+
+	j.keysMutex.RLock()
+	publicKey, exists := j.keys[kid]
+	j.keysMutex.RUnlock()
+	if exists {
+		return publicKey, nil
+	}
+
+	slog.Info("Key not found, refreshing JWKS", "kid", kid)
+	if err := j.fetchJWKS(); err != nil {
+		return nil, fmt.Errorf("failed to refresh JWKS: %w", err)
+	}
+
+	j.keysMutex.RLock()
+	publicKey, exists = j.keys[kid]
+	j.keysMutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no public key found for kid: %s", kid)
+	}
+	return publicKey, nil
+}
+
+// claimStrings reads a claim holding either a single string or an array of strings
+func claimStrings(claims jwt.MapClaims, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// fetchJWKS fetches the JWKS from the configured endpoint
+func (j *JWTAuthMiddleware) fetchJWKS() error {
+	ctx, cancel := context.WithTimeout(context.Background(), j.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.config.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	var jwks JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	newKeys := make(map[string]*rsa.PublicKey)
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		publicKey, err := buildRSAPublicKey(key.N, key.E)
+		if err != nil {
+			slog.Warn("Failed to build RSA public key", "kid", key.Kid, "error", err)
+			continue
+		}
+		newKeys[key.Kid] = publicKey
+	}
+
+	j.keysMutex.Lock()
+	j.keys = newKeys
+	j.lastFetch = time.Now()
+	j.keysMutex.Unlock()
+
+	slog.Info("Successfully fetched JWKS", "keys_count", len(newKeys))
+	return nil
+}
+
+// buildRSAPublicKey constructs an RSA public key from its base64url encoded modulus and exponent
+func buildRSAPublicKey(nStr, eStr string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(nStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(eStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode exponent: %w", err)
+	}
+
+	n := new(big.Int).SetBytes(nBytes)
+	e := new(big.Int).SetBytes(eBytes)
+	if n.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA modulus too small: %d bits, minimum 2048 required", n.BitLen())
+	}
+	if !e.IsInt64() || e.Int64() < 2 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+// ensureKeysFresh refreshes the JWKS if it has not been fetched in the last hour
+func (j *JWTAuthMiddleware) ensureKeysFresh() error {
+	j.keysMutex.RLock()
+	needsRefresh := len(j.keys) == 0 || time.Since(j.lastFetch) > time.Hour
+	j.keysMutex.RUnlock()
+
+	if needsRefresh {
+		return j.fetchJWKS()
+	}
+	return nil
+}
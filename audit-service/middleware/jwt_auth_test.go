@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://idp.example.com/oauth2/token"
+	testClientID = "admin-portal"
+	testKeyID    = "test-key"
+)
+
+// setupJWTAuth starts a JWKS endpoint serving the public half of a new signing key
+func setupJWTAuth(t *testing.T) (*JWTAuthMiddleware, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(JWKS{Keys: []JWK{{
+			Kty: "RSA",
+			Kid: testKeyID,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(server.Close)
+
+	config := JWTAuthConfig{JWKSURL: server.URL, ExpectedIssuer: testIssuer, ValidClientIDs: []string{testClientID}}
+	require.NoError(t, config.Validate())
+	return NewJWTAuthMiddleware(config), key
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	base := jwt.MapClaims{
+		"iss": testIssuer,
+		"aud": testClientID,
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		base[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, base)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	auth, key := setupJWTAuth(t)
+
+	var principal *Principal
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}
+	call := func(h http.HandlerFunc, token string) int {
+		principal = nil
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w.Code
+	}
+
+	t.Run("Admin", func(t *testing.T) {
+		token := signToken(t, key, jwt.MapClaims{"roles": "OpenDIF_Admin", "email": "admin@example.com"})
+
+		assert.Equal(t, http.StatusOK, call(auth.AuthenticateAdmin(handler), token))
+		require.NotNil(t, principal)
+		assert.True(t, principal.CanReadAll())
+		assert.Equal(t, "ADMIN", principal.ActorType())
+		assert.Equal(t, "admin@example.com", principal.Email)
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		token := signToken(t, key, jwt.MapClaims{
+			"roles":     []string{"OpenDIF_Member"},
+			"member_id": []string{"member-1", "provider-1"},
+		})
+
+		assert.Equal(t, http.StatusOK, call(auth.Authenticate(handler), token))
+		require.NotNil(t, principal)
+		assert.False(t, principal.CanReadAll())
+		assert.Equal(t, []string{"member-1", "provider-1", "user-1"}, principal.Scope())
+
+		assert.Equal(t, http.StatusForbidden, call(auth.AuthenticateAdmin(handler), token))
+	})
+
+	t.Run("NoRole", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, call(auth.Authenticate(handler), signToken(t, key, nil)))
+	})
+
+	t.Run("InvalidTokens", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		roles := jwt.MapClaims{"roles": "OpenDIF_Admin"}
+		tokens := map[string]string{
+			"missing":      "",
+			"malformed":    "not-a-token",
+			"wrong key":    signToken(t, otherKey, roles),
+			"expired":      signToken(t, key, jwt.MapClaims{"roles": "OpenDIF_Admin", "exp": time.Now().Add(-time.Minute).Unix()}),
+			"wrong issuer": signToken(t, key, jwt.MapClaims{"roles": "OpenDIF_Admin", "iss": "https://other.example.com"}),
+			"wrong client": signToken(t, key, jwt.MapClaims{"roles": "OpenDIF_Admin", "aud": "other-client"}),
+		}
+		for name, token := range tokens {
+			assert.Equal(t, http.StatusUnauthorized, call(auth.Authenticate(handler), token), name)
+			assert.Nil(t, principal, name)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		var disabled *JWTAuthMiddleware
+		assert.Equal(t, http.StatusOK, call(disabled.AuthenticateAdmin(handler), ""))
+		assert.Nil(t, principal)
+	})
+}
+
+func TestJWTAuthConfig_Validate(t *testing.T) {
+	valid := JWTAuthConfig{JWKSURL: "https://idp.example.com/oauth2/jwks", ExpectedIssuer: testIssuer, ValidClientIDs: []string{testClientID}}
+	assert.NoError(t, valid.Validate())
+
+	for name, config := range map[string]JWTAuthConfig{
+		"jwks":     {ExpectedIssuer: testIssuer, ValidClientIDs: []string{testClientID}},
+		"issuer":   {JWKSURL: valid.JWKSURL, ValidClientIDs: []string{testClientID}},
+		"clients":  {JWKSURL: valid.JWKSURL, ExpectedIssuer: testIssuer},
+		"empty id": {JWKSURL: valid.JWKSURL, ExpectedIssuer: testIssuer, ValidClientIDs: []string{" "}},
+	} {
+		assert.Error(t, config.Validate(), name)
+	}
+}
@@ -2,29 +2,277 @@ package database
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/audit-service/v1/models"
 )
 
 // AuditRepository defines the database-agnostic interface for audit log operations
 // This allows the service to work with any database implementation (PostgreSQL, MongoDB, etc.)
 type AuditRepository interface {
-	// CreateAuditLog creates a new audit log entry
+	// CreateAuditLog creates a new audit log entry.
+	// If a log with the same EventID already exists, nothing is stored and the existing log
+	// is returned together with ErrDuplicateEventID.
 	CreateAuditLog(ctx context.Context, log *models.AuditLog) (*models.AuditLog, error)
 
+	// CreateAuditLogs creates several audit log entries in one transaction, in order.
+	// The result at each index is that of the log at the same index: as with CreateAuditLog, a log whose
+	// EventID is already stored, or used by an earlier log of the batch, is not stored and its result
+	// holds the existing log and ErrDuplicateEventID. Any other error fails the whole batch.
+	CreateAuditLogs(ctx context.Context, logs []*models.AuditLog) ([]CreateResult, error)
+
 	// GetAuditLogsByTraceID retrieves all audit logs for a given trace ID
 	GetAuditLogsByTraceID(ctx context.Context, traceID string) ([]models.AuditLog, error)
 
 	// GetAuditLogs retrieves audit logs with optional filtering
 	GetAuditLogs(ctx context.Context, filters *AuditLogFilters) ([]models.AuditLog, int64, error)
+
+	// StreamAuditLogs passes every log matching the filters to fn in batches of at most batchSize,
+	// in the filters' sort order. Limit and Offset are ignored. Streaming stops at the first error from fn.
+	StreamAuditLogs(ctx context.Context, filters *AuditLogFilters, batchSize int, fn func([]models.AuditLog) error) error
+
+	// CountAuditLogsBySource counts the logs matching the filters per ingestion source and actor
+	CountAuditLogsBySource(ctx context.Context, filters *AuditLogFilters) ([]SourceCount, error)
+}
+
+// CreateResult is the outcome of storing one log of a batch
+type CreateResult struct {
+	Log *models.AuditLog
+	Err error
 }
 
+// SourceCount is the number of logs an actor submitted through one ingestion source
+type SourceCount struct {
+	Source  string
+	ActorID string
+	Count   int64
+}
+
+// ExportJobRepository defines the database-agnostic interface for asynchronous export jobs
+type ExportJobRepository interface {
+	// CreateExportJob creates a new export job
+	CreateExportJob(ctx context.Context, job *models.ExportJob) (*models.ExportJob, error)
+
+	// GetExportJob retrieves an export job by ID, returning ErrExportJobNotFound if it does not exist
+	GetExportJob(ctx context.Context, id uuid.UUID) (*models.ExportJob, error)
+
+	// UpdateExportJob saves the job's current state
+	UpdateExportJob(ctx context.Context, job *models.ExportJob) error
+
+	// FailUnfinishedExportJobs marks pending and running jobs as failed, returning how many were affected.
+	// Used at startup, since jobs run in-process and do not survive a restart.
+	FailUnfinishedExportJobs(ctx context.Context, reason string) (int64, error)
+}
+
+// IntegrityRepository defines the database-agnostic interface for the audit log hash chain
+type IntegrityRepository interface {
+	// GetChainHead returns the sequence and hash of the last chained log (zero values for an empty chain)
+	GetChainHead(ctx context.Context) (*models.AuditChainHead, error)
+
+	// GetAuditLogBySequence retrieves the chained log at the given sequence, returning ErrAuditLogNotFound if absent
+	GetAuditLogBySequence(ctx context.Context, sequence int64) (*models.AuditLog, error)
+
+	// StreamChain passes the chained logs with from <= sequence <= to to fn in ascending sequence order,
+	// in batches of at most batchSize. Streaming stops at the first error from fn.
+	StreamChain(ctx context.Context, from, to int64, batchSize int, fn func([]models.AuditLog) error) error
+
+	// CreateCheckpoint stores a signed checkpoint
+	CreateCheckpoint(ctx context.Context, checkpoint *models.AuditCheckpoint) error
+
+	// GetLatestCheckpoint returns the checkpoint with the highest sequence, or nil if there is none
+	GetLatestCheckpoint(ctx context.Context) (*models.AuditCheckpoint, error)
+
+	// ListCheckpoints returns the checkpoints with from <= sequence <= to in ascending sequence order
+	ListCheckpoints(ctx context.Context, from, to int64) ([]models.AuditCheckpoint, error)
+
+	// ListChainTombstones returns the tombstones of archived logs with from <= sequence <= to
+	// in ascending sequence order
+	ListChainTombstones(ctx context.Context, from, to int64) ([]models.AuditChainTombstone, error)
+}
+
+// ArchiveRepository defines the database-agnostic interface for retention archive runs
+type ArchiveRepository interface {
+	// CreateArchiveRun creates a new archive run
+	CreateArchiveRun(ctx context.Context, run *models.ArchiveRun) (*models.ArchiveRun, error)
+
+	// GetArchiveRun retrieves an archive run by ID, returning ErrArchiveRunNotFound if it does not exist
+	GetArchiveRun(ctx context.Context, id uuid.UUID) (*models.ArchiveRun, error)
+
+	// ListArchiveRuns returns the most recent archive runs, newest first
+	ListArchiveRuns(ctx context.Context, limit int) ([]models.ArchiveRun, error)
+
+	// UpdateArchiveRun saves the run's current state
+	UpdateArchiveRun(ctx context.Context, run *models.ArchiveRun) error
+
+	// FailUnfinishedArchiveRuns marks running runs as failed, returning how many were affected.
+	// Used at startup, since runs execute in-process and do not survive a restart.
+	FailUnfinishedArchiveRuns(ctx context.Context, reason string) (int64, error)
+
+	// DeleteArchivedAuditLogs deletes logs that were written to the given archive object, returning
+	// how many were deleted. A tombstone is kept for each chained log so the hash chain stays verifiable.
+	DeleteArchivedAuditLogs(ctx context.Context, logs []models.AuditLog, runID uuid.UUID, objectKey string) (int64, error)
+
+	// TombstoneArchivedAuditLogs keeps a tombstone for each chained log written to the given archive object
+	// without deleting the logs, for logs that are removed by dropping their time partition
+	TombstoneArchivedAuditLogs(ctx context.Context, logs []models.AuditLog, runID uuid.UUID, objectKey string) error
+}
+
+// ErasureRepository defines the database-agnostic interface for data subject erasures
+type ErasureRepository interface {
+	// CreateErasure creates a new erasure
+	CreateErasure(ctx context.Context, erasure *models.Erasure) (*models.Erasure, error)
+
+	// GetErasure retrieves an erasure by ID, returning ErrErasureNotFound if it does not exist
+	GetErasure(ctx context.Context, id uuid.UUID) (*models.Erasure, error)
+
+	// ListErasures returns the most recent erasures, newest first
+	ListErasures(ctx context.Context, limit int) ([]models.Erasure, error)
+
+	// UpdateErasure saves the erasure's current state
+	UpdateErasure(ctx context.Context, erasure *models.Erasure) error
+
+	// PseudonymizeAuditLogs saves the rewritten identifiers, metadata and erasure fields of the given logs
+	// in one transaction, so an erasure never leaves a data owner partly erased. Returns how many were updated.
+	PseudonymizeAuditLogs(ctx context.Context, logs []models.AuditLog) (int64, error)
+}
+
+// TenantRepository defines the database-agnostic interface for the per-tenant partitions of audit logs
+type TenantRepository interface {
+	// BackfillPartitions partitions logs without partitions in batches of at most batchSize, returning how many were partitioned
+	BackfillPartitions(ctx context.Context, batchSize int) (int64, error)
+
+	// PrunePartitions deletes the partitions of logs that no longer exist, returning how many were deleted
+	PrunePartitions(ctx context.Context) (int64, error)
+
+	// RefreshTenants recomputes the summary of every tenant's partition, returning the number of tenants
+	RefreshTenants(ctx context.Context) (int64, error)
+
+	// ListTenants returns a page of tenant summaries, most active first, and the total number of tenants
+	ListTenants(ctx context.Context, limit, offset int) ([]models.AuditTenant, int64, error)
+}
+
+// TimePartitionRepository defines the interface for the monthly time partitions of the audit_logs table.
+// Time partitioning is only supported on PostgreSQL; elsewhere the table is never partitioned.
+type TimePartitionRepository interface {
+	// TimePartitioned reports whether the audit_logs table is partitioned by time
+	TimePartitioned(ctx context.Context) (bool, error)
+
+	// ListTimePartitions returns the monthly partitions, oldest first
+	ListTimePartitions(ctx context.Context) ([]TimePartition, error)
+
+	// CreateTimePartition creates the partition of the month containing t unless it exists, returning whether it was created
+	CreateTimePartition(ctx context.Context, t time.Time) (bool, error)
+
+	// TimePartitionEmpty reports whether a partition holds no logs
+	TimePartitionEmpty(ctx context.Context, partition TimePartition) (bool, error)
+
+	// DropTimePartition detaches and drops a partition, deleting the tenant partitions of its logs with it
+	DropTimePartition(ctx context.Context, partition TimePartition) error
+}
+
+// AlertRepository defines the database-agnostic interface for fired alerts
+type AlertRepository interface {
+	// CreateAlert stores a fired alert
+	CreateAlert(ctx context.Context, alert *models.Alert) (*models.Alert, error)
+
+	// GetAlert retrieves an alert by ID, returning ErrAlertNotFound if it does not exist
+	GetAlert(ctx context.Context, id uuid.UUID) (*models.Alert, error)
+
+	// ListAlerts returns the alerts matching the filters, most recently fired first, and the total count
+	ListAlerts(ctx context.Context, filters *AlertFilters) ([]models.Alert, int64, error)
+
+	// UpdateAlert saves the alert's current state
+	UpdateAlert(ctx context.Context, alert *models.Alert) error
+}
+
+// AlertFilters represents query filters for listing alerts
+type AlertFilters struct {
+	RuleName *string
+	Severity *string
+	GroupKey *string
+
+	// StartTime and EndTime bound when the alert fired (inclusive start, exclusive end)
+	StartTime *time.Time
+	EndTime   *time.Time
+
+	Limit  int
+	Offset int
+}
+
+// ErrDuplicateEventID is returned when creating a log whose EventID has already been stored
+var ErrDuplicateEventID = errors.New("duplicate event ID")
+
+// ErrTimePartitioningUnsupported is returned when time partitions are used on a database other than PostgreSQL
+var ErrTimePartitioningUnsupported = errors.New("time partitioning requires PostgreSQL")
+
+// ErrAuditLogNotFound is returned when an audit log does not exist
+var ErrAuditLogNotFound = errors.New("audit log not found")
+
+// ErrExportJobNotFound is returned when an export job does not exist
+var ErrExportJobNotFound = errors.New("export job not found")
+
+// ErrArchiveRunNotFound is returned when an archive run does not exist
+var ErrArchiveRunNotFound = errors.New("archive run not found")
+
+// ErrErasureNotFound is returned when an erasure does not exist
+var ErrErasureNotFound = errors.New("erasure not found")
+
+// ErrAlertNotFound is returned when an alert does not exist
+var ErrAlertNotFound = errors.New("alert not found")
+
 // AuditLogFilters represents query filters for retrieving audit logs
 type AuditLogFilters struct {
-	TraceID     *string
-	EventType   *string
-	EventAction *string
-	Status      *string
-	Limit       int
-	Offset      int
+	TraceID       *string
+	CorrelationID *string
+	EventType     *string
+	EventAction   *string
+	Status        *string
+	ActorType     *string
+	ActorID       *string
+	TargetType    *string
+	TargetID      *string
+
+	// ExcludeEventTypes leaves out logs with any of these event types; logs without an event type still match
+	ExcludeEventTypes []string
+
+	// ConsumerAppID matches logs where the consumer application is the actor,
+	// or is recorded as applicationId in the request or response metadata
+	ConsumerAppID *string
+
+	// OwnerID matches logs recording ownerId or ownerEmail of the data owner in the request metadata
+	OwnerID *string
+
+	// MentionedID matches logs whose additional metadata holds the ID as a string value anywhere, e.g. as the
+	// resourceId of a management event or in its relatedResources; callers check where it appears
+	MentionedID *string
+
+	// EntityIDs, when non-nil, matches logs in the partition of one of these tenants: logs whose actor, target
+	// or consumer application (applicationId in the request or response metadata) is one of these IDs.
+	// An empty list matches nothing.
+	EntityIDs []string
+
+	// StartTime and EndTime bound the event timestamp (inclusive start, exclusive end)
+	StartTime *time.Time
+	EndTime   *time.Time
+
+	// Search is a case-insensitive substring matched against actor, target, event type and event action
+	Search *string
+
+	// SortAscending orders results oldest first; the default is newest first
+	SortAscending bool
+
+	// After resumes a listing after the given log (keyset pagination); Offset is ignored when set
+	After *AuditLogCursor
+
+	Limit  int
+	Offset int
+}
+
+// AuditLogCursor identifies a position in a timestamp-ordered listing of audit logs.
+// The ID breaks ties between logs that share a timestamp.
+type AuditLogCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
 }
@@ -2,36 +2,276 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// GormRepository implements AuditRepository using GORM (works with SQLite or PostgreSQL)
+// GormRepository implements AuditRepository, ExportJobRepository, IntegrityRepository and ArchiveRepository
+// using GORM (works with SQLite or PostgreSQL)
 type GormRepository struct {
 	db *gorm.DB
+
+	// chainMu serializes appends to the hash chain within this process;
+	// the locked chain head row does the same across processes on PostgreSQL
+	chainMu sync.Mutex
+
+	// timePartitioned is set when audit_logs is partitioned by month (see PartitionAuditLogs);
+	// timePartitions holds the names of the partitions known to exist and is guarded by chainMu
+	timePartitioned bool
+	timePartitions  map[string]bool
 }
 
 // NewGormRepository creates a new repository (works with SQLite or PostgreSQL)
 func NewGormRepository(db *gorm.DB) *GormRepository {
-	// Auto-migrate the audit_logs table
-	if err := db.AutoMigrate(&models.AuditLog{}); err != nil {
+	// Auto-migrate the audit tables
+	if err := db.AutoMigrate(&models.AuditLog{}, &models.ExportJob{}, &models.AuditChainHead{}, &models.AuditCheckpoint{},
+		&models.ArchiveRun{}, &models.AuditChainTombstone{}, &models.Alert{}, &models.Erasure{},
+		&models.AuditLogPartition{}, &models.AuditTenant{}); err != nil {
 		// Log migration error but don't fail service creation
 		// The actual database operation will fail later if schema is wrong
-		slog.Warn("Failed to auto-migrate audit_logs table", "error", err)
+		slog.Warn("Failed to auto-migrate audit tables", "error", err)
+	}
+	// Create the chain head row up front so that appends only ever need to lock it
+	if err := db.FirstOrCreate(&models.AuditChainHead{}, models.AuditChainHead{ID: models.AuditChainHeadID}).Error; err != nil {
+		slog.Warn("Failed to initialize audit chain head", "error", err)
+	}
+
+	repo := &GormRepository{db: db, timePartitions: make(map[string]bool)}
+	if db.Dialector.Name() == "postgres" {
+		partitioned, err := timePartitioned(db)
+		if err != nil {
+			slog.Warn("Failed to check audit log partitioning", "error", err)
+		}
+		repo.timePartitioned = partitioned
 	}
-	return &GormRepository{db: db}
+	return repo
 }
 
-// CreateAuditLog creates a new audit log entry
+// CreateAuditLog creates a new audit log entry, appending it to the hash chain
 func (r *GormRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) (*models.AuditLog, error) {
-	result := r.db.WithContext(ctx).Create(log)
-	if result.Error != nil {
-		return nil, fmt.Errorf("failed to create audit log: %w", result.Error)
+	results, err := r.CreateAuditLogs(ctx, []*models.AuditLog{log})
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Log, results[0].Err
+}
+
+const (
+	// auditLogInsertBatchSize bounds the rows per multi-row INSERT and the event IDs per duplicate check
+	auditLogInsertBatchSize = 500
+
+	// auditLogCopyMinBatch is the smallest batch written with COPY on PostgreSQL. Smaller batches are
+	// as fast with a multi-row INSERT, which does not need a dedicated connection.
+	auditLogCopyMinBatch = 16
+)
+
+// CreateAuditLogs creates several audit log entries, appending them to the hash chain in order.
+// The whole batch takes the chain head lock once and is written with one COPY (PostgreSQL) or
+// multi-row INSERTs, so a batch costs about as much as a single log.
+func (r *GormRepository) CreateAuditLogs(ctx context.Context, logs []*models.AuditLog) ([]CreateResult, error) {
+	if len(logs) == 0 {
+		return nil, nil
+	}
+	// The hash covers the ID and timestamp, so they must be final before it is computed
+	for _, log := range logs {
+		if log.ID == uuid.Nil {
+			log.ID = uuid.New()
+		}
+		if log.Timestamp.IsZero() {
+			log.Timestamp = time.Now()
+		}
+		log.Timestamp = models.ChainTimestamp(log.Timestamp)
+	}
+
+	r.chainMu.Lock()
+	defer r.chainMu.Unlock()
+
+	// Partitions are created outside the write transaction, as creating one locks the whole table
+	if err := r.ensureTimePartitions(ctx, logs); err != nil {
+		return nil, fmt.Errorf("failed to create audit logs: %w", err)
+	}
+
+	var results []CreateResult
+	var stored []*models.AuditLog
+	write := func(tx *gorm.DB, conn *sql.Conn) error {
+		var err error
+		results, stored, err = r.appendAuditLogs(tx, conn, logs)
+		return err
+	}
+
+	var err error
+	if r.db.Dialector.Name() == "postgres" && len(logs) >= auditLogCopyMinBatch {
+		// COPY runs on the driver connection, which must be the one holding the transaction
+		err = r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+			sqlConn, ok := conn.Statement.ConnPool.(*sql.Conn)
+			if !ok {
+				return fmt.Errorf("unexpected connection type %T", conn.Statement.ConnPool)
+			}
+			return conn.Transaction(func(tx *gorm.DB) error { return write(tx, sqlConn) })
+		})
+	} else {
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error { return write(tx, nil) })
+	}
+	if err != nil {
+		for _, log := range stored {
+			log.Sequence, log.PreviousHash, log.Hash = nil, "", ""
+		}
+		return nil, fmt.Errorf("failed to create audit logs: %w", err)
+	}
+	return results, nil
+}
+
+// appendAuditLogs chains and inserts the logs whose event IDs are new, returning every log's result
+// and the logs that were inserted. conn, if set, is the connection of tx and the logs are copied through it.
+func (r *GormRepository) appendAuditLogs(tx *gorm.DB, conn *sql.Conn, logs []*models.AuditLog) ([]CreateResult, []*models.AuditLog, error) {
+	var head models.AuditChainHead
+	if err := r.lockForUpdate(tx).First(&head, models.AuditChainHeadID).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to lock audit chain head: %w", err)
+	}
+
+	// Appends are serialized by the head lock, so checking for the event IDs first is race-free
+	existing, err := r.findByEventIDs(tx, logs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]CreateResult, len(logs))
+	stored := make([]*models.AuditLog, 0, len(logs))
+	var partitions []models.AuditLogPartition
+	sequence, previousHash := head.LastSequence, head.LastHash
+	for i, log := range logs {
+		if log.EventID != nil {
+			if duplicate, ok := existing[*log.EventID]; ok {
+				results[i] = CreateResult{Log: duplicate, Err: ErrDuplicateEventID}
+				continue
+			}
+			existing[*log.EventID] = log
+		}
+
+		// Hooks are skipped so that partitions are written in bulk below; BeforeCreate only sets defaults
+		if err := log.BeforeCreate(tx); err != nil {
+			return nil, stored, err
+		}
+		sequence++
+		chained := sequence
+		log.Sequence = &chained
+		log.PreviousHash = previousHash
+		stored = append(stored, log)
+		if log.Hash, err = log.ComputeChainHash(); err != nil {
+			return nil, stored, fmt.Errorf("failed to hash audit log: %w", err)
+		}
+		previousHash = log.Hash
+		partitions = append(partitions, log.Partitions()...)
+		results[i] = CreateResult{Log: log}
+	}
+	if len(stored) == 0 {
+		return results, stored, nil
 	}
-	return log, nil
+
+	if conn != nil {
+		err = r.copyAuditLogs(tx, conn, stored)
+	} else {
+		err = tx.Session(&gorm.Session{SkipHooks: true}).CreateInBatches(stored, auditLogInsertBatchSize).Error
+	}
+	if err != nil {
+		return nil, stored, err
+	}
+	if len(partitions) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(partitions, auditLogInsertBatchSize).Error; err != nil {
+			return nil, stored, fmt.Errorf("failed to partition audit logs: %w", err)
+		}
+	}
+	err = tx.Model(&head).Updates(map[string]interface{}{"last_sequence": sequence, "last_hash": previousHash}).Error
+	return results, stored, err
+}
+
+// findByEventIDs returns the stored logs with the event IDs of the given logs, by event ID
+func (r *GormRepository) findByEventIDs(tx *gorm.DB, logs []*models.AuditLog) (map[string]*models.AuditLog, error) {
+	var eventIDs []string
+	for _, log := range logs {
+		if log.EventID != nil {
+			eventIDs = append(eventIDs, *log.EventID)
+		}
+	}
+
+	existing := make(map[string]*models.AuditLog)
+	for start := 0; start < len(eventIDs); start += auditLogInsertBatchSize {
+		end := min(start+auditLogInsertBatchSize, len(eventIDs))
+		var duplicates []models.AuditLog
+		if err := tx.Where("event_id IN ?", eventIDs[start:end]).Find(&duplicates).Error; err != nil {
+			return nil, fmt.Errorf("failed to check event IDs: %w", err)
+		}
+		for i := range duplicates {
+			existing[*duplicates[i].EventID] = &duplicates[i]
+		}
+	}
+	return existing, nil
+}
+
+// copyAuditLogs inserts logs with COPY on the pgx connection conn
+func (r *GormRepository) copyAuditLogs(tx *gorm.DB, conn *sql.Conn, logs []*models.AuditLog) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(&models.AuditLog{}); err != nil {
+		return fmt.Errorf("failed to parse audit log schema: %w", err)
+	}
+	columns := stmt.Schema.DBNames
+	rows := make([][]any, len(logs))
+	for i, log := range logs {
+		value := reflect.ValueOf(log).Elem()
+		row := make([]any, len(columns))
+		for j, column := range columns {
+			field, _ := stmt.Schema.FieldsByDBName[column].ValueOf(tx.Statement.Context, value)
+			var err error
+			if row[j], err = copyValue(field); err != nil {
+				return fmt.Errorf("failed to encode %s: %w", column, err)
+			}
+		}
+		rows[i] = row
+	}
+
+	return conn.Raw(func(driverConn any) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("COPY requires the pgx driver, got %T", driverConn)
+		}
+		_, err := pgxConn.Conn().CopyFrom(tx.Statement.Context, pgx.Identifier{stmt.Schema.Table}, columns, pgx.CopyFromRows(rows))
+		return err
+	})
+}
+
+// copyValue converts a field value for COPY: nil pointers become NULL and types with a driver.Valuer
+// (UUIDs, JSON metadata) are stored the way GORM stores them
+func copyValue(value any) (any, error) {
+	if reflected := reflect.ValueOf(value); reflected.Kind() == reflect.Pointer && reflected.IsNil() {
+		return nil, nil
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return value, nil
+}
+
+// lockForUpdate adds SELECT ... FOR UPDATE on PostgreSQL.
+// SQLite has no row locks; its write transactions are already serialized.
+func (r *GormRepository) lockForUpdate(tx *gorm.DB) *gorm.DB {
+	if r.db.Dialector.Name() == "postgres" {
+		return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	return tx
 }
 
 // GetAuditLogsByTraceID retrieves all audit logs for a given trace ID
@@ -50,26 +290,13 @@ func (r *GormRepository) GetAuditLogsByTraceID(ctx context.Context, traceID stri
 	return logs, nil
 }
 
-// GetAuditLogs retrieves audit logs with optional filtering
+// GetAuditLogs retrieves audit logs with optional filtering.
+// The returned total counts every log matching the filters, regardless of the cursor or offset.
 func (r *GormRepository) GetAuditLogs(ctx context.Context, filters *AuditLogFilters) ([]models.AuditLog, int64, error) {
 	var logs []models.AuditLog
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
-
-	// Apply filters
-	if filters.TraceID != nil && *filters.TraceID != "" {
-		query = query.Where("trace_id = ?", *filters.TraceID)
-	}
-	if filters.EventType != nil && *filters.EventType != "" {
-		query = query.Where("event_type = ?", *filters.EventType)
-	}
-	if filters.EventAction != nil && *filters.EventAction != "" {
-		query = query.Where("event_action = ?", *filters.EventAction)
-	}
-	if filters.Status != nil && *filters.Status != "" {
-		query = query.Where("status = ?", *filters.Status)
-	}
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&models.AuditLog{}), filters)
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -77,7 +304,7 @@ func (r *GormRepository) GetAuditLogs(ctx context.Context, filters *AuditLogFilt
 	}
 
 	// Apply pagination and ordering
-	// Note: Results are ordered by timestamp DESC (newest first) for general queries.
+	// Note: Results are ordered by timestamp DESC (newest first) unless SortAscending is set.
 	// For trace-specific queries, use GetAuditLogsByTraceID which orders by ASC (chronological).
 	limit := filters.Limit
 	if limit <= 0 {
@@ -87,7 +314,12 @@ func (r *GormRepository) GetAuditLogs(ctx context.Context, filters *AuditLogFilt
 		limit = 1000 // max
 	}
 
-	if err := query.Order("timestamp DESC").Limit(limit).Offset(filters.Offset).Find(&logs).Error; err != nil {
+	query = applyOrdering(query, filters.SortAscending, filters.After).Limit(limit)
+	if filters.After == nil {
+		query = query.Offset(filters.Offset)
+	}
+
+	if err := query.Find(&logs).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to retrieve audit logs: %w", err)
 	}
 
@@ -97,3 +329,691 @@ func (r *GormRepository) GetAuditLogs(ctx context.Context, filters *AuditLogFilt
 
 	return logs, total, nil
 }
+
+// StreamAuditLogs passes every log matching the filters to fn in batches.
+// Batches are fetched with keyset pagination, so each query stays cheap however deep the export goes,
+// and the next batch is only read once fn has returned (e.g. after the client consumed the previous one).
+func (r *GormRepository) StreamAuditLogs(ctx context.Context, filters *AuditLogFilters, batchSize int, fn func([]models.AuditLog) error) error {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	after := filters.After
+	for {
+		var batch []models.AuditLog
+		query := r.applyFilters(r.db.WithContext(ctx).Model(&models.AuditLog{}), filters)
+		if err := applyOrdering(query, filters.SortAscending, after).Limit(batchSize).Find(&batch).Error; err != nil {
+			return fmt.Errorf("failed to stream audit logs: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		after = &AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+}
+
+// CountAuditLogsBySource counts the logs matching the filters per ingestion source and actor
+func (r *GormRepository) CountAuditLogsBySource(ctx context.Context, filters *AuditLogFilters) ([]SourceCount, error) {
+	var counts []SourceCount
+	err := r.applyFilters(r.db.WithContext(ctx).Model(&models.AuditLog{}), filters).
+		Select("COALESCE(source, '') AS source, actor_id, COUNT(*) AS count").
+		Group("source").Group("actor_id").
+		Order("actor_id").Order("source").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count audit logs by source: %w", err)
+	}
+	return counts, nil
+}
+
+// applyOrdering orders the query by (timestamp, id) and, if after is set, skips logs up to and including it.
+// The ID is a tie-breaker so that cursor pagination is stable for logs sharing a timestamp.
+func applyOrdering(query *gorm.DB, ascending bool, after *AuditLogCursor) *gorm.DB {
+	direction, comparator := "DESC", "<"
+	if ascending {
+		direction, comparator = "ASC", ">"
+	}
+	query = query.Order("timestamp " + direction).Order("id " + direction)
+	if after != nil {
+		query = query.Where(
+			fmt.Sprintf("timestamp %[1]s ? OR (timestamp = ? AND id %[1]s ?)", comparator),
+			after.Timestamp, after.Timestamp, after.ID,
+		)
+	}
+	return query
+}
+
+// applyFilters adds the WHERE conditions for the given filters to the query
+func (r *GormRepository) applyFilters(query *gorm.DB, filters *AuditLogFilters) *gorm.DB {
+	if filters.TraceID != nil && *filters.TraceID != "" {
+		query = query.Where("trace_id = ?", *filters.TraceID)
+	}
+	if filters.CorrelationID != nil && *filters.CorrelationID != "" {
+		query = query.Where("correlation_id = ?", *filters.CorrelationID)
+	}
+	if filters.EventType != nil && *filters.EventType != "" {
+		query = query.Where("event_type = ?", *filters.EventType)
+	}
+	if len(filters.ExcludeEventTypes) > 0 {
+		query = query.Where("event_type IS NULL OR event_type NOT IN ?", filters.ExcludeEventTypes)
+	}
+	if filters.EventAction != nil && *filters.EventAction != "" {
+		query = query.Where("event_action = ?", *filters.EventAction)
+	}
+	if filters.Status != nil && *filters.Status != "" {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.ActorType != nil && *filters.ActorType != "" {
+		query = query.Where("actor_type = ?", *filters.ActorType)
+	}
+	if filters.ActorID != nil && *filters.ActorID != "" {
+		query = query.Where("actor_id = ?", *filters.ActorID)
+	}
+	if filters.TargetType != nil && *filters.TargetType != "" {
+		query = query.Where("target_type = ?", *filters.TargetType)
+	}
+	if filters.TargetID != nil && *filters.TargetID != "" {
+		query = query.Where("target_id = ?", *filters.TargetID)
+	}
+	if filters.ConsumerAppID != nil && *filters.ConsumerAppID != "" {
+		appID := *filters.ConsumerAppID
+		query = query.Where(
+			fmt.Sprintf("(actor_type = ? AND actor_id = ?) OR %s = ? OR %s = ?",
+				r.jsonField("request_metadata", "applicationId"),
+				r.jsonField("response_metadata", "applicationId")),
+			models.ActorTypeApplication, appID, appID, appID,
+		)
+	}
+	if filters.EntityIDs != nil {
+		// Entities are tenants: their logs are looked up through their partitions
+		partitions := r.db.Model(&models.AuditLogPartition{}).Select("log_id").Where("tenant_id IN ?", filters.EntityIDs)
+		if filters.StartTime != nil {
+			partitions = partitions.Where("timestamp >= ?", *filters.StartTime)
+		}
+		if filters.EndTime != nil {
+			partitions = partitions.Where("timestamp < ?", *filters.EndTime)
+		}
+		query = query.Where("id IN (?)", partitions)
+	}
+	if filters.OwnerID != nil && *filters.OwnerID != "" {
+		query = query.Where(
+			fmt.Sprintf("%s = ? OR %s = ?",
+				r.jsonField("request_metadata", "ownerId"),
+				r.jsonField("request_metadata", "ownerEmail")),
+			*filters.OwnerID, *filters.OwnerID,
+		)
+	}
+	if filters.MentionedID != nil && *filters.MentionedID != "" {
+		// Matching the quoted ID keeps it from matching inside longer IDs
+		pattern := "%" + likeEscaper.Replace(`"`+*filters.MentionedID+`"`) + "%"
+		query = query.Where(`CAST(additional_metadata AS TEXT) LIKE ? ESCAPE '\'`, pattern)
+	}
+	if filters.StartTime != nil {
+		query = query.Where("timestamp >= ?", *filters.StartTime)
+	}
+	if filters.EndTime != nil {
+		query = query.Where("timestamp < ?", *filters.EndTime)
+	}
+	if filters.Search != nil && *filters.Search != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(*filters.Search)) + "%"
+		query = query.Where(
+			`LOWER(actor_id) LIKE ? ESCAPE '\' OR LOWER(target_id) LIKE ? ESCAPE '\' OR `+
+				`LOWER(event_type) LIKE ? ESCAPE '\' OR LOWER(event_action) LIKE ? ESCAPE '\'`,
+			pattern, pattern, pattern, pattern,
+		)
+	}
+	return query
+}
+
+// likeEscaper escapes LIKE wildcards so search terms match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// jsonField returns an expression extracting a top-level string field from a JSON column.
+// PostgreSQL stores metadata as jsonb; SQLite stores it as text and needs json_extract.
+func (r *GormRepository) jsonField(column, field string) string {
+	if r.db.Dialector.Name() == "postgres" {
+		return fmt.Sprintf("%s->>'%s'", column, field)
+	}
+	return fmt.Sprintf("json_extract(CAST(%s AS TEXT), '$.%s')", column, field)
+}
+
+// CreateExportJob creates a new export job
+func (r *GormRepository) CreateExportJob(ctx context.Context, job *models.ExportJob) (*models.ExportJob, error) {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+	return job, nil
+}
+
+// GetExportJob retrieves an export job by ID
+func (r *GormRepository) GetExportJob(ctx context.Context, id uuid.UUID) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportJobNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve export job: %w", err)
+	}
+	return &job, nil
+}
+
+// UpdateExportJob saves the job's current state
+func (r *GormRepository) UpdateExportJob(ctx context.Context, job *models.ExportJob) error {
+	job.UpdatedAt = time.Now().UTC()
+	if err := r.db.WithContext(ctx).Save(job).Error; err != nil {
+		return fmt.Errorf("failed to update export job: %w", err)
+	}
+	return nil
+}
+
+// FailUnfinishedExportJobs marks pending and running jobs as failed
+func (r *GormRepository) FailUnfinishedExportJobs(ctx context.Context, reason string) (int64, error) {
+	now := time.Now().UTC()
+	result := r.db.WithContext(ctx).Model(&models.ExportJob{}).
+		Where("status IN ?", []string{models.ExportJobStatusPending, models.ExportJobStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":       models.ExportJobStatusFailed,
+			"error":        reason,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to fail unfinished export jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetChainHead returns the sequence and hash of the last chained log
+func (r *GormRepository) GetChainHead(ctx context.Context) (*models.AuditChainHead, error) {
+	var head models.AuditChainHead
+	if err := r.db.WithContext(ctx).First(&head, models.AuditChainHeadID).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve audit chain head: %w", err)
+	}
+	return &head, nil
+}
+
+// GetAuditLogBySequence retrieves the chained log at the given sequence
+func (r *GormRepository) GetAuditLogBySequence(ctx context.Context, sequence int64) (*models.AuditLog, error) {
+	var log models.AuditLog
+	if err := r.db.WithContext(ctx).Where("sequence = ?", sequence).First(&log).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuditLogNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve audit log by sequence: %w", err)
+	}
+	return &log, nil
+}
+
+// StreamChain passes the chained logs in the sequence range to fn in ascending order
+func (r *GormRepository) StreamChain(ctx context.Context, from, to int64, batchSize int, fn func([]models.AuditLog) error) error {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	next := from
+	for next <= to {
+		var batch []models.AuditLog
+		err := r.db.WithContext(ctx).
+			Where("sequence >= ? AND sequence <= ?", next, to).
+			Order("sequence ASC").
+			Limit(batchSize).
+			Find(&batch).Error
+		if err != nil {
+			return fmt.Errorf("failed to stream audit chain: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		next = *batch[len(batch)-1].Sequence + 1
+	}
+	return nil
+}
+
+// CreateCheckpoint stores a signed checkpoint
+func (r *GormRepository) CreateCheckpoint(ctx context.Context, checkpoint *models.AuditCheckpoint) error {
+	if err := r.db.WithContext(ctx).Create(checkpoint).Error; err != nil {
+		return fmt.Errorf("failed to create audit checkpoint: %w", err)
+	}
+	return nil
+}
+
+// GetLatestCheckpoint returns the checkpoint with the highest sequence, or nil if there is none
+func (r *GormRepository) GetLatestCheckpoint(ctx context.Context) (*models.AuditCheckpoint, error) {
+	var checkpoints []models.AuditCheckpoint
+	if err := r.db.WithContext(ctx).Order("sequence DESC").Limit(1).Find(&checkpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve latest audit checkpoint: %w", err)
+	}
+	if len(checkpoints) == 0 {
+		return nil, nil
+	}
+	return &checkpoints[0], nil
+}
+
+// ListCheckpoints returns the checkpoints in the sequence range in ascending order
+func (r *GormRepository) ListCheckpoints(ctx context.Context, from, to int64) ([]models.AuditCheckpoint, error) {
+	var checkpoints []models.AuditCheckpoint
+	err := r.db.WithContext(ctx).
+		Where("sequence >= ? AND sequence <= ?", from, to).
+		Order("sequence ASC").
+		Find(&checkpoints).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit checkpoints: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// ListChainTombstones returns the tombstones of archived logs in the sequence range in ascending order
+func (r *GormRepository) ListChainTombstones(ctx context.Context, from, to int64) ([]models.AuditChainTombstone, error) {
+	var tombstones []models.AuditChainTombstone
+	err := r.db.WithContext(ctx).
+		Where("sequence >= ? AND sequence <= ?", from, to).
+		Order("sequence ASC").
+		Find(&tombstones).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit chain tombstones: %w", err)
+	}
+	return tombstones, nil
+}
+
+// CreateArchiveRun creates a new archive run
+func (r *GormRepository) CreateArchiveRun(ctx context.Context, run *models.ArchiveRun) (*models.ArchiveRun, error) {
+	if err := r.db.WithContext(ctx).Create(run).Error; err != nil {
+		return nil, fmt.Errorf("failed to create archive run: %w", err)
+	}
+	return run, nil
+}
+
+// GetArchiveRun retrieves an archive run by ID
+func (r *GormRepository) GetArchiveRun(ctx context.Context, id uuid.UUID) (*models.ArchiveRun, error) {
+	var run models.ArchiveRun
+	if err := r.db.WithContext(ctx).First(&run, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrArchiveRunNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve archive run: %w", err)
+	}
+	return &run, nil
+}
+
+// ListArchiveRuns returns the most recent archive runs, newest first
+func (r *GormRepository) ListArchiveRuns(ctx context.Context, limit int) ([]models.ArchiveRun, error) {
+	var runs []models.ArchiveRun
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list archive runs: %w", err)
+	}
+	if runs == nil {
+		runs = []models.ArchiveRun{}
+	}
+	return runs, nil
+}
+
+// UpdateArchiveRun saves the run's current state
+func (r *GormRepository) UpdateArchiveRun(ctx context.Context, run *models.ArchiveRun) error {
+	run.UpdatedAt = time.Now().UTC()
+	if err := r.db.WithContext(ctx).Save(run).Error; err != nil {
+		return fmt.Errorf("failed to update archive run: %w", err)
+	}
+	return nil
+}
+
+// FailUnfinishedArchiveRuns marks running runs as failed
+func (r *GormRepository) FailUnfinishedArchiveRuns(ctx context.Context, reason string) (int64, error) {
+	now := time.Now().UTC()
+	result := r.db.WithContext(ctx).Model(&models.ArchiveRun{}).
+		Where("status = ?", models.ArchiveRunStatusRunning).
+		Updates(map[string]interface{}{
+			"status":       models.ArchiveRunStatusFailed,
+			"error":        reason,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to fail unfinished archive runs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// archiveDeleteBatchSize bounds the number of IDs per DELETE statement
+const archiveDeleteBatchSize = 500
+
+// DeleteArchivedAuditLogs deletes archived logs, keeping a tombstone for each chained log.
+// Tombstones and deletions are written in one transaction, so the chain never has an unexplained gap.
+func (r *GormRepository) DeleteArchivedAuditLogs(ctx context.Context, logs []models.AuditLog, runID uuid.UUID, objectKey string) (int64, error) {
+	var deleted int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createTombstones(tx, logs, runID, objectKey); err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, 0, len(logs))
+		for _, log := range logs {
+			ids = append(ids, log.ID)
+		}
+		for start := 0; start < len(ids); start += archiveDeleteBatchSize {
+			end := min(start+archiveDeleteBatchSize, len(ids))
+			if err := tx.Where("log_id IN ?", ids[start:end]).Delete(&models.AuditLogPartition{}).Error; err != nil {
+				return fmt.Errorf("failed to delete archived audit log partitions: %w", err)
+			}
+			result := tx.Where("id IN ?", ids[start:end]).Delete(&models.AuditLog{})
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete archived audit logs: %w", result.Error)
+			}
+			deleted += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// TombstoneArchivedAuditLogs keeps a tombstone for each chained log of an archive object, leaving the logs
+// to be removed with their time partition. Tombstones of logs archived before are kept as they are.
+func (r *GormRepository) TombstoneArchivedAuditLogs(ctx context.Context, logs []models.AuditLog, runID uuid.UUID, objectKey string) error {
+	return createTombstones(r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}), logs, runID, objectKey)
+}
+
+// createTombstones creates the tombstones of the chained logs among the archived logs
+func createTombstones(tx *gorm.DB, logs []models.AuditLog, runID uuid.UUID, objectKey string) error {
+	archivedAt := time.Now().UTC()
+	tombstones := make([]models.AuditChainTombstone, 0, len(logs))
+	for _, log := range logs {
+		if log.Sequence == nil {
+			continue
+		}
+		tombstones = append(tombstones, models.AuditChainTombstone{
+			Sequence:     *log.Sequence,
+			LogID:        log.ID,
+			PreviousHash: log.PreviousHash,
+			Hash:         log.Hash,
+			ArchiveRunID: runID,
+			ObjectKey:    objectKey,
+			ArchivedAt:   archivedAt,
+		})
+	}
+	if len(tombstones) == 0 {
+		return nil
+	}
+	if err := tx.CreateInBatches(tombstones, archiveDeleteBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to create audit chain tombstones: %w", err)
+	}
+	return nil
+}
+
+// CreateErasure creates a new erasure
+func (r *GormRepository) CreateErasure(ctx context.Context, erasure *models.Erasure) (*models.Erasure, error) {
+	if err := r.db.WithContext(ctx).Create(erasure).Error; err != nil {
+		return nil, fmt.Errorf("failed to create erasure: %w", err)
+	}
+	return erasure, nil
+}
+
+// GetErasure retrieves an erasure by ID
+func (r *GormRepository) GetErasure(ctx context.Context, id uuid.UUID) (*models.Erasure, error) {
+	var erasure models.Erasure
+	if err := r.db.WithContext(ctx).First(&erasure, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrErasureNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve erasure: %w", err)
+	}
+	return &erasure, nil
+}
+
+// ListErasures returns the most recent erasures, newest first
+func (r *GormRepository) ListErasures(ctx context.Context, limit int) ([]models.Erasure, error) {
+	var erasures []models.Erasure
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&erasures).Error; err != nil {
+		return nil, fmt.Errorf("failed to list erasures: %w", err)
+	}
+	if erasures == nil {
+		erasures = []models.Erasure{}
+	}
+	return erasures, nil
+}
+
+// UpdateErasure saves the erasure's current state
+func (r *GormRepository) UpdateErasure(ctx context.Context, erasure *models.Erasure) error {
+	erasure.UpdatedAt = time.Now().UTC()
+	if err := r.db.WithContext(ctx).Save(erasure).Error; err != nil {
+		return fmt.Errorf("failed to update erasure: %w", err)
+	}
+	return nil
+}
+
+// PseudonymizeAuditLogs saves the pseudonymized fields of the given logs in one transaction.
+// Only the fields an erasure rewrites, and the log's partitions, are updated; hashes and chain positions
+// are left as they are.
+func (r *GormRepository) PseudonymizeAuditLogs(ctx context.Context, logs []models.AuditLog) (int64, error) {
+	var updated int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, log := range logs {
+			result := tx.Model(&models.AuditLog{}).Where("id = ?", log.ID).Updates(map[string]interface{}{
+				"actor_id":            log.ActorID,
+				"target_id":           log.TargetID,
+				"request_metadata":    log.RequestMetadata,
+				"response_metadata":   log.ResponseMetadata,
+				"additional_metadata": log.AdditionalMetadata,
+				"erasure_id":          log.ErasureID,
+				"pseudonymized_hash":  log.PseudonymizedHash,
+			})
+			if result.Error != nil {
+				return fmt.Errorf("failed to pseudonymize audit log %s: %w", log.ID, result.Error)
+			}
+			updated += result.RowsAffected
+
+			// The actor and target may have been rewritten, which moves the log to other partitions
+			if err := tx.Where("log_id = ?", log.ID).Delete(&models.AuditLogPartition{}).Error; err != nil {
+				return fmt.Errorf("failed to repartition audit log %s: %w", log.ID, err)
+			}
+			if result.RowsAffected > 0 {
+				if partitions := log.Partitions(); len(partitions) > 0 {
+					if err := tx.Create(&partitions).Error; err != nil {
+						return fmt.Errorf("failed to repartition audit log %s: %w", log.ID, err)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// CreateAlert stores a fired alert
+func (r *GormRepository) CreateAlert(ctx context.Context, alert *models.Alert) (*models.Alert, error) {
+	if err := r.db.WithContext(ctx).Create(alert).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+	return alert, nil
+}
+
+// GetAlert retrieves an alert by ID
+func (r *GormRepository) GetAlert(ctx context.Context, id uuid.UUID) (*models.Alert, error) {
+	var alert models.Alert
+	if err := r.db.WithContext(ctx).First(&alert, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlertNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve alert: %w", err)
+	}
+	return &alert, nil
+}
+
+// ListAlerts returns the alerts matching the filters, most recently fired first
+func (r *GormRepository) ListAlerts(ctx context.Context, filters *AlertFilters) ([]models.Alert, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Alert{})
+	if filters.RuleName != nil && *filters.RuleName != "" {
+		query = query.Where("rule_name = ?", *filters.RuleName)
+	}
+	if filters.Severity != nil && *filters.Severity != "" {
+		query = query.Where("severity = ?", *filters.Severity)
+	}
+	if filters.GroupKey != nil && *filters.GroupKey != "" {
+		query = query.Where("group_key = ?", *filters.GroupKey)
+	}
+	if filters.StartTime != nil {
+		query = query.Where("fired_at >= ?", *filters.StartTime)
+	}
+	if filters.EndTime != nil {
+		query = query.Where("fired_at < ?", *filters.EndTime)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count alerts: %w", err)
+	}
+
+	var alerts []models.Alert
+	if err := query.Order("fired_at DESC").Order("id DESC").Limit(filters.Limit).Offset(filters.Offset).Find(&alerts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	if alerts == nil {
+		alerts = []models.Alert{}
+	}
+	return alerts, total, nil
+}
+
+// UpdateAlert saves the alert's current state
+func (r *GormRepository) UpdateAlert(ctx context.Context, alert *models.Alert) error {
+	alert.UpdatedAt = time.Now().UTC()
+	if err := r.db.WithContext(ctx).Save(alert).Error; err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+	return nil
+}
+
+// BackfillPartitions partitions logs that have no partitions, such as logs stored before partitioning
+// was introduced, in batches of at most batchSize
+func (r *GormRepository) BackfillPartitions(ctx context.Context, batchSize int) (int64, error) {
+	var backfilled int64
+	for {
+		var logs []models.AuditLog
+		err := r.db.WithContext(ctx).
+			Where("NOT EXISTS (SELECT 1 FROM audit_log_partitions p WHERE p.log_id = audit_logs.id)").
+			Order("timestamp ASC").Order("id ASC").Limit(batchSize).Find(&logs).Error
+		if err != nil {
+			return backfilled, fmt.Errorf("failed to find unpartitioned audit logs: %w", err)
+		}
+		var partitions []models.AuditLogPartition
+		for i := range logs {
+			partitions = append(partitions, logs[i].Partitions()...)
+		}
+		// Every log has an actor, so logs without partitions would only come back on the next batch
+		if len(partitions) == 0 {
+			return backfilled, nil
+		}
+		err = r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(partitions, batchSize).Error
+		if err != nil {
+			return backfilled, fmt.Errorf("failed to backfill audit log partitions: %w", err)
+		}
+		backfilled += int64(len(logs))
+		if len(logs) < batchSize {
+			return backfilled, nil
+		}
+	}
+}
+
+// PrunePartitions deletes the partitions of logs that no longer exist
+func (r *GormRepository) PrunePartitions(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("NOT EXISTS (SELECT 1 FROM audit_logs l WHERE l.id = audit_log_partitions.log_id)").
+		Delete(&models.AuditLogPartition{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune audit log partitions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// RefreshTenants recomputes the summary of every tenant's partition, replacing the previous summaries
+func (r *GormRepository) RefreshTenants(ctx context.Context) (int64, error) {
+	var counts []struct {
+		TenantID     string
+		LogCount     int64
+		FailureCount int64
+	}
+	err := r.db.WithContext(ctx).Table("audit_log_partitions p").
+		Select("p.tenant_id AS tenant_id, COUNT(*) AS log_count, "+
+			"SUM(CASE WHEN l.status = ? THEN 1 ELSE 0 END) AS failure_count", models.StatusFailure).
+		Joins("JOIN audit_logs l ON l.id = p.log_id").
+		Group("p.tenant_id").
+		Scan(&counts).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to summarize tenants: %w", err)
+	}
+
+	refreshedAt := time.Now().UTC()
+	tenants := make([]models.AuditTenant, 0, len(counts))
+	for _, count := range counts {
+		tenant := models.AuditTenant{
+			TenantID:     count.TenantID,
+			LogCount:     count.LogCount,
+			FailureCount: count.FailureCount,
+			RefreshedAt:  refreshedAt,
+		}
+		// The first and last events come from the tenant/time index, one lookup each
+		for _, bound := range []struct {
+			order string
+			dest  **time.Time
+		}{{"timestamp ASC", &tenant.FirstEventAt}, {"timestamp DESC", &tenant.LastEventAt}} {
+			var partition models.AuditLogPartition
+			err := r.db.WithContext(ctx).Where("tenant_id = ?", count.TenantID).Order(bound.order).Limit(1).Find(&partition).Error
+			if err != nil {
+				return 0, fmt.Errorf("failed to summarize tenant %s: %w", count.TenantID, err)
+			}
+			if !partition.Timestamp.IsZero() {
+				timestamp := partition.Timestamp.UTC()
+				*bound.dest = &timestamp
+			}
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.AuditTenant{}).Error; err != nil {
+			return err
+		}
+		if len(tenants) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(tenants, archiveDeleteBatchSize).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to save tenant summaries: %w", err)
+	}
+	return int64(len(tenants)), nil
+}
+
+// ListTenants returns the tenant summaries, most active first
+func (r *GormRepository) ListTenants(ctx context.Context, limit, offset int) ([]models.AuditTenant, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.AuditTenant{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count tenants: %w", err)
+	}
+	var tenants []models.AuditTenant
+	err := r.db.WithContext(ctx).Order("log_count DESC").Order("tenant_id ASC").
+		Limit(limit).Offset(offset).Find(&tenants).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	if tenants == nil {
+		tenants = []models.AuditTenant{}
+	}
+	return tenants, total, nil
+}
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// timePartitionPrefix names the monthly partitions of audit_logs: audit_logs_p202401 holds January 2024
+const timePartitionPrefix = "audit_logs_p"
+
+// timePartitionName matches the names of the partitions created by the service
+var timePartitionName = regexp.MustCompile(`^audit_logs_p(\d{6})$`)
+
+// TimePartition is the partition of the audit_logs table holding the logs of one month
+type TimePartition struct {
+	Name string
+
+	// From and To bound the timestamps of the partition's logs (inclusive start, exclusive end)
+	From time.Time
+	To   time.Time
+}
+
+// TimePartitionOf returns the monthly partition holding logs with timestamp t
+func TimePartitionOf(t time.Time) TimePartition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return TimePartition{
+		Name: timePartitionPrefix + from.Format("200601"),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+// parseTimePartition returns the partition with the given name, or false if the name is not one of a monthly partition
+func parseTimePartition(name string) (TimePartition, bool) {
+	match := timePartitionName.FindStringSubmatch(name)
+	if match == nil {
+		return TimePartition{}, false
+	}
+	month, err := time.Parse("200601", match[1])
+	if err != nil {
+		return TimePartition{}, false
+	}
+	return TimePartitionOf(month), true
+}
+
+// PartitionAuditLogs creates the audit_logs table partitioned by month on PostgreSQL, and must run
+// before NewGormRepository migrates the schema. Compared to a plain table, the primary key and the
+// unique event ID and sequence indexes include the timestamp, as PostgreSQL requires of partitioned
+// tables (event IDs stay unique as appends check them under the chain head lock), and timestamps get a
+// BRIN index besides the B-tree: the B-tree serves newest-first listings, while long time-range scans
+// (statistics, exports, archival) read the BRIN index, which is a tiny fraction of its size.
+// An existing unpartitioned table is left as it is; see docs/DATABASE_CONFIGURATION.md for converting it.
+func PartitionAuditLogs(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return ErrTimePartitioningUnsupported
+	}
+	if db.Migrator().HasTable(&models.AuditLog{}) {
+		partitioned, err := timePartitioned(db)
+		if err != nil {
+			return err
+		}
+		if !partitioned {
+			return fmt.Errorf("audit_logs already exists and is not partitioned")
+		}
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&models.AuditLog{}); err != nil {
+		return fmt.Errorf("failed to parse audit log schema: %w", err)
+	}
+	// Columns get the types GORM would give them, so that migrations see no difference
+	sql := "CREATE TABLE ? ("
+	values := []interface{}{clause.Table{Name: stmt.Schema.Table}}
+	for _, dbName := range stmt.Schema.DBNames {
+		sql += "? ?, "
+		values = append(values, clause.Column{Name: dbName}, db.Migrator().FullDataTypeOf(stmt.Schema.FieldsByDBName[dbName]))
+	}
+	sql += `PRIMARY KEY ("id", "timestamp")) PARTITION BY RANGE ("timestamp")`
+
+	// Indexes are named as in the model, so migrations do not create the unpartitionable ones again
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range []struct {
+			sql    string
+			values []interface{}
+		}{
+			{sql, values},
+			{`CREATE UNIQUE INDEX "idx_audit_logs_event_id" ON "audit_logs" ("event_id", "timestamp")`, nil},
+			{`CREATE UNIQUE INDEX "idx_audit_logs_sequence" ON "audit_logs" ("sequence", "timestamp")`, nil},
+			{`CREATE INDEX "idx_audit_logs_timestamp_brin" ON "audit_logs" USING brin ("timestamp")`, nil},
+		} {
+			if err := tx.Exec(statement.sql, statement.values...).Error; err != nil {
+				return fmt.Errorf("failed to create partitioned audit_logs table: %w", err)
+			}
+		}
+		return createTimePartition(tx, TimePartitionOf(time.Now()))
+	})
+}
+
+// timePartitioned reports whether audit_logs is a partitioned table
+func timePartitioned(db *gorm.DB) (bool, error) {
+	var count int64
+	err := db.Raw(`SELECT COUNT(*) FROM pg_partitioned_table pt JOIN pg_class c ON c.oid = pt.partrelid
+		WHERE c.relname = ? AND c.relnamespace = to_regnamespace(current_schema())`, "audit_logs").Scan(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check audit_logs partitioning: %w", err)
+	}
+	return count > 0, nil
+}
+
+// createTimePartition creates a partition unless it exists
+func createTimePartition(db *gorm.DB, partition TimePartition) error {
+	// Partition bounds cannot be bind parameters; they are formatted from times, never from input
+	err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF "audit_logs" FOR VALUES FROM ('%s') TO ('%s')`,
+		db.Statement.Quote(partition.Name), partition.From.Format(time.RFC3339), partition.To.Format(time.RFC3339))).Error
+	if err != nil {
+		return fmt.Errorf("failed to create audit log partition %s: %w", partition.Name, err)
+	}
+	return nil
+}
+
+// ensureTimePartitions creates the partitions the logs will be stored in, if audit_logs is partitioned.
+// Partitions known to exist are remembered, so this usually costs no query. Callers hold chainMu.
+func (r *GormRepository) ensureTimePartitions(ctx context.Context, logs []*models.AuditLog) error {
+	if !r.timePartitioned {
+		return nil
+	}
+	for _, log := range logs {
+		partition := TimePartitionOf(log.Timestamp)
+		if r.timePartitions[partition.Name] {
+			continue
+		}
+		if err := createTimePartition(r.db.WithContext(ctx), partition); err != nil {
+			return err
+		}
+		r.timePartitions[partition.Name] = true
+	}
+	return nil
+}
+
+// TimePartitioned reports whether audit_logs is partitioned by time
+func (r *GormRepository) TimePartitioned(ctx context.Context) (bool, error) {
+	if r.db.Dialector.Name() != "postgres" {
+		return false, nil
+	}
+	return timePartitioned(r.db.WithContext(ctx))
+}
+
+// ListTimePartitions returns the monthly partitions of audit_logs, oldest first.
+// Partitions not named like the service's monthly partitions are left out.
+func (r *GormRepository) ListTimePartitions(ctx context.Context) ([]TimePartition, error) {
+	if r.db.Dialector.Name() != "postgres" {
+		return nil, ErrTimePartitioningUnsupported
+	}
+	var names []string
+	err := r.db.WithContext(ctx).Raw(`SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = ? AND p.relnamespace = to_regnamespace(current_schema()) ORDER BY c.relname`, "audit_logs").
+		Scan(&names).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log partitions: %w", err)
+	}
+	partitions := make([]TimePartition, 0, len(names))
+	for _, name := range names {
+		if partition, ok := parseTimePartition(name); ok {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions, nil
+}
+
+// CreateTimePartition creates the partition of the month containing t unless it exists
+func (r *GormRepository) CreateTimePartition(ctx context.Context, t time.Time) (bool, error) {
+	if r.db.Dialector.Name() != "postgres" {
+		return false, ErrTimePartitioningUnsupported
+	}
+	partition := TimePartitionOf(t)
+	var exists bool
+	if err := r.db.WithContext(ctx).Raw("SELECT to_regclass(?) IS NOT NULL", partition.Name).Scan(&exists).Error; err != nil {
+		return false, fmt.Errorf("failed to check audit log partition %s: %w", partition.Name, err)
+	}
+	if exists {
+		return false, nil
+	}
+	if err := createTimePartition(r.db.WithContext(ctx), partition); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TimePartitionEmpty reports whether a partition holds no logs
+func (r *GormRepository) TimePartitionEmpty(ctx context.Context, partition TimePartition) (bool, error) {
+	var found []int
+	err := r.db.WithContext(ctx).Table(partition.Name).Select("1").Limit(1).Scan(&found).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to read audit log partition %s: %w", partition.Name, err)
+	}
+	return len(found) == 0, nil
+}
+
+// DropTimePartition detaches and drops a partition. Its tenant partitions are deleted in the same
+// transaction; they hold the timestamps of their logs, so they are found by time rather than by log.
+func (r *GormRepository) DropTimePartition(ctx context.Context, partition TimePartition) error {
+	if r.db.Dialector.Name() != "postgres" {
+		return ErrTimePartitioningUnsupported
+	}
+	if _, ok := parseTimePartition(partition.Name); !ok {
+		return fmt.Errorf("not an audit log partition: %s", partition.Name)
+	}
+	quoted := r.db.Statement.Quote(partition.Name)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE "audit_logs" DETACH PARTITION ` + quoted).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DROP TABLE " + quoted).Error; err != nil {
+			return err
+		}
+		return tx.Where("timestamp >= ? AND timestamp < ?", partition.From, partition.To).
+			Delete(&models.AuditLogPartition{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to drop audit log partition %s: %w", partition.Name, err)
+	}
+
+	r.chainMu.Lock()
+	delete(r.timePartitions, partition.Name)
+	r.chainMu.Unlock()
+	return nil
+}
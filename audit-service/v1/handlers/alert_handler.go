@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// alertsPath is the route for fired alerts
+const alertsPath = "/api/audit-logs/alerts"
+
+// AlertHandler handles HTTP requests for fired alerts
+type AlertHandler struct {
+	service *services.AlertService
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(service *services.AlertService) *AlertHandler {
+	return &AlertHandler{service: service}
+}
+
+// HandleAlerts handles GET /api/audit-logs/alerts (list fired alerts) and GET /api/audit-logs/alerts/{id}
+func (h *AlertHandler) HandleAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, alertsPath), "/")
+	switch {
+	case id == "":
+		h.listAlerts(w, r)
+	case !strings.Contains(id, "/"):
+		h.getAlert(w, r, id)
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+	}
+}
+
+// listAlerts serves the list of alerts
+// Query parameters: ruleName, severity, groupKey, startTime and endTime (RFC3339, on when the alert fired), limit, offset
+func (h *AlertHandler) listAlerts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &v1models.ListAlertsRequest{
+		RuleName:  query.Get("ruleName"),
+		Severity:  query.Get("severity"),
+		GroupKey:  query.Get("groupKey"),
+		StartTime: query.Get("startTime"),
+		EndTime:   query.Get("endTime"),
+	}
+	for name, target := range map[string]*int{"limit": &req.Limit, "offset": &req.Offset} {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				utils.RespondWithError(w, http.StatusBadRequest, "Invalid alert query", fmt.Errorf("invalid %s: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	response, err := h.service.ListAlerts(r.Context(), req)
+	if err != nil {
+		respondWithAlertError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+func (h *AlertHandler) getAlert(w http.ResponseWriter, r *http.Request, id string) {
+	alert, err := h.service.GetAlert(r.Context(), id)
+	if err != nil {
+		respondWithAlertError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, alert)
+}
+
+// respondWithAlertError maps alert service errors to HTTP status codes
+func respondWithAlertError(w http.ResponseWriter, err error) {
+	switch {
+	case services.IsValidationError(err):
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid alert query", err)
+	case errors.Is(err, services.ErrAlertNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Alert not found", err)
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve alerts", err)
+	}
+}
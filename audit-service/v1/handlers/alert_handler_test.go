@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	v1database "github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAlertHandler_HandleAlerts(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	repo := v1database.NewGormRepository(db)
+	handler := NewAlertHandler(v1services.NewAlertService(repo, &config.AlertsConfig{}, nil))
+
+	now := time.Now().UTC()
+	var alertIDs []string
+	for _, rule := range []string{"consumer-denied-requests", "consent-rejections"} {
+		alert, err := repo.CreateAlert(context.Background(), &v1models.Alert{
+			RuleName:           rule,
+			Severity:           config.AlertSeverityHigh,
+			GroupKey:           "app-1",
+			LogCount:           1,
+			LogIDs:             v1models.JSONBRawMessage(`[]`),
+			FirstLogAt:         now,
+			LastLogAt:          now,
+			FiredAt:            now,
+			NotificationStatus: v1models.AlertNotificationNone,
+		})
+		require.NoError(t, err)
+		alertIDs = append(alertIDs, alert.ID.String())
+	}
+
+	t.Run("List", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/alerts?ruleName=consent-rejections&severity=high", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleAlerts(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var result v1models.ListAlertsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, int64(1), result.Total)
+		require.Len(t, result.Alerts, 1)
+		assert.Equal(t, alertIDs[1], result.Alerts[0].ID.String())
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/alerts/"+alertIDs[0], nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleAlerts(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var result v1models.AlertResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, "consumer-denied-requests", result.RuleName)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/alerts/00000000-0000-0000-0000-000000000000", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleAlerts(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("InvalidParameters", func(t *testing.T) {
+		for _, path := range []string{"?limit=ten", "?startTime=yesterday", "/not-a-uuid"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/alerts"+path, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleAlerts(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/audit-logs/alerts", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleAlerts(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
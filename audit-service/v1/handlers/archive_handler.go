@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// archiveRunsPath is the route for retention archive runs
+const archiveRunsPath = "/api/audit-logs/archive-runs"
+
+// ArchiveHandler handles HTTP requests for retention archive runs
+type ArchiveHandler struct {
+	service *services.ArchiveService
+}
+
+// NewArchiveHandler creates a new archive handler
+func NewArchiveHandler(service *services.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{service: service}
+}
+
+// HandleArchiveRuns handles POST /api/audit-logs/archive-runs (start a run),
+// GET /api/audit-logs/archive-runs (list recent runs) and GET /api/audit-logs/archive-runs/{id}
+func (h *ArchiveHandler) HandleArchiveRuns(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, archiveRunsPath), "/")
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		h.startArchiveRun(w, r)
+	case id == "" && r.Method == http.MethodGet:
+		h.listArchiveRuns(w, r)
+	case id != "" && !strings.Contains(id, "/") && r.Method == http.MethodGet:
+		h.getArchiveRun(w, r, id)
+	case id != "" && strings.Contains(id, "/"):
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ArchiveHandler) startArchiveRun(w http.ResponseWriter, r *http.Request) {
+	actorType, actorID := requestActor(r)
+	run, err := h.service.StartArchiveRun(r.Context(), actorType, actorID)
+	if err != nil {
+		respondWithArchiveError(w, err)
+		return
+	}
+	w.Header().Set("Location", archiveRunsPath+"/"+run.ID.String())
+	utils.RespondWithJSON(w, http.StatusAccepted, run)
+}
+
+func (h *ArchiveHandler) listArchiveRuns(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid limit parameter", err)
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.service.ListArchiveRuns(r.Context(), limit)
+	if err != nil {
+		respondWithArchiveError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, runs)
+}
+
+func (h *ArchiveHandler) getArchiveRun(w http.ResponseWriter, r *http.Request, id string) {
+	run, err := h.service.GetArchiveRun(r.Context(), id)
+	if err != nil {
+		respondWithArchiveError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, run)
+}
+
+// respondWithArchiveError maps archive service errors to HTTP status codes
+func respondWithArchiveError(w http.ResponseWriter, err error) {
+	switch {
+	case services.IsValidationError(err):
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid archive request", err)
+	case errors.Is(err, services.ErrArchivalNotConfigured):
+		utils.RespondWithError(w, http.StatusServiceUnavailable, "Archival is not configured", err)
+	case errors.Is(err, services.ErrArchiveRunInProgress):
+		utils.RespondWithError(w, http.StatusConflict, "An archive run is already in progress", err)
+	case errors.Is(err, services.ErrArchiveRunNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Archive run not found", err)
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to process archive request", err)
+	}
+}
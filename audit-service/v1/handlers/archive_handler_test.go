@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	v1database "github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// discardArchiveStore accepts and drops every object
+type discardArchiveStore struct{}
+
+func (discardArchiveStore) PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType, sha256Hex string) error {
+	_, err := io.Copy(io.Discard, body)
+	return err
+}
+
+func TestArchiveHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	repo := v1database.NewGormRepository(db)
+	retention := &config.RetentionConfig{DefaultDays: 30}
+	service := v1services.NewArchiveService(repo, repo, discardArchiveStore{}, retention, "")
+	handler := NewArchiveHandler(service)
+
+	_, err = repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+		Timestamp:  time.Now().UTC().AddDate(0, 0, -60),
+		Status:     v1models.StatusSuccess,
+		ActorType:  "SERVICE",
+		ActorID:    "orchestration-engine",
+		TargetType: "SERVICE",
+	})
+	require.NoError(t, err)
+
+	var runID string
+	t.Run("StartRun", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/audit-logs/archive-runs", nil)
+		req.Header.Set("X-Actor-Type", "ADMIN")
+		req.Header.Set("X-Actor-Id", "admin@example.com")
+		w := httptest.NewRecorder()
+
+		handler.HandleArchiveRuns(w, req)
+		service.Wait()
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		var run v1models.ArchiveRunResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+		runID = run.ID.String()
+		assert.Equal(t, "/api/audit-logs/archive-runs/"+runID, w.Header().Get("Location"))
+	})
+
+	t.Run("GetRun", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/archive-runs/"+runID, nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleArchiveRuns(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var run v1models.ArchiveRunResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+		assert.Equal(t, v1models.ArchiveRunStatusCompleted, run.Status)
+		assert.Equal(t, int64(1), run.ArchivedCount)
+		assert.Len(t, run.Objects, 1)
+	})
+
+	t.Run("ListRuns", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/archive-runs?limit=10", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleArchiveRuns(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var runs v1models.ListArchiveRunsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &runs))
+		assert.Len(t, runs.Runs, 1)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			method, path string
+			want         int
+		}{
+			{http.MethodGet, "/api/audit-logs/archive-runs/not-a-uuid", http.StatusBadRequest},
+			{http.MethodGet, "/api/audit-logs/archive-runs/00000000-0000-0000-0000-000000000000", http.StatusNotFound},
+			{http.MethodGet, "/api/audit-logs/archive-runs?limit=0", http.StatusBadRequest},
+			{http.MethodDelete, "/api/audit-logs/archive-runs", http.StatusMethodNotAllowed},
+		}
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			handler.HandleArchiveRuns(w, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.want, w.Code, "%s %s", tt.method, tt.path)
+		}
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		disabled := NewArchiveHandler(v1services.NewArchiveService(repo, repo, nil, retention, ""))
+		w := httptest.NewRecorder()
+
+		disabled.HandleArchiveRuns(w, httptest.NewRequest(http.MethodPost, "/api/audit-logs/archive-runs", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
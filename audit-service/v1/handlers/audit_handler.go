@@ -2,13 +2,19 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/middleware"
 	"github.com/gov-dx-sandbox/audit-service/v1/models"
 	"github.com/gov-dx-sandbox/audit-service/v1/services"
 	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+	"github.com/gov-dx-sandbox/shared/requestid"
 )
 
 // AuditHandler handles HTTP requests for audit logs
@@ -33,10 +39,27 @@ func (h *AuditHandler) CreateAuditLog(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
 		return
 	}
+	req.Source = models.IngestionSourceHTTP
 
 	// Validation is handled by the service layer (auditLog.Validate())
 	auditLog, err := h.service.CreateAuditLog(r.Context(), &req)
+	if errors.Is(err, services.ErrDuplicateEvent) {
+		// The event was already stored (e.g. a retry); respond with the original log
+		utils.RespondWithJSON(w, http.StatusOK, auditLog)
+		return
+	}
 	if err != nil {
+		// Schema mismatches list every offending field so producers can fix their payloads
+		var schemaErr *services.EventSchemaError
+		if errors.As(err, &schemaErr) {
+			utils.RespondWithJSON(w, http.StatusBadRequest, models.ErrorResponse{
+				Error:     "Invalid request payload",
+				Code:      "SCHEMA_VALIDATION_FAILED",
+				Details:   schemaErr.Fields,
+				RequestID: requestid.FromResponse(w),
+			})
+			return
+		}
 		// Return 400 Bad Request for validation errors, 500 for other errors
 		if services.IsValidationError(err) {
 			utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload", err)
@@ -49,52 +72,117 @@ func (h *AuditHandler) CreateAuditLog(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusCreated, auditLog)
 }
 
-// GetAuditLogs handles GET /api/audit-logs
-func (h *AuditHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+// tracePath is the path prefix of trace lookups; the trace or correlation ID follows it
+const tracePath = "/api/audit-logs/trace/"
+
+// GetTrace handles GET /api/audit-logs/trace/{traceId}
+// Returns every audit log of one exchange request, oldest first. Authenticated entity users only see
+// the logs in their own tenants' partitions.
+func (h *AuditHandler) GetTrace(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Parse query parameters
-	traceID := r.URL.Query().Get("traceId")
-	eventType := r.URL.Query().Get("eventType")
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 100 // default
-	offset := 0  // default
+	id, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), tracePath))
+	if err != nil || id == "" || strings.Contains(id, "/") {
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+	entityIDs, ok := tenantScope(r)
+	if !ok {
+		utils.RespondWithError(w, http.StatusForbidden, "Access to tenant denied", nil)
+		return
+	}
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
-			limit = l
+	response, err := h.service.GetTrace(r.Context(), id, entityIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTraceNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, "Trace not found", nil)
+		case services.IsValidationError(err):
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid trace ID", err)
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve trace", err)
 		}
+		return
 	}
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// resourceTimelinePath is the path prefix of resource timelines; the resource type and ID follow it
+const resourceTimelinePath = "/api/events/resource/"
+
+// GetResourceTimeline handles GET /api/events/resource/{type}/{id}
+// Returns the management events of one resource, e.g. an application, oldest first. Authenticated entity
+// users only see the events in their own tenants' partitions.
+func (h *AuditHandler) GetResourceTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), resourceTimelinePath), "/")
+	if len(parts) != 2 {
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+	resourceType, typeErr := url.PathUnescape(parts[0])
+	resourceID, idErr := url.PathUnescape(parts[1])
+	if typeErr != nil || idErr != nil || resourceType == "" || resourceID == "" {
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+	entityIDs, ok := tenantScope(r)
+	if !ok {
+		utils.RespondWithError(w, http.StatusForbidden, "Access to tenant denied", nil)
+		return
+	}
+
+	response, err := h.service.GetResourceTimeline(r.Context(), resourceType, resourceID, entityIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrResourceNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, "Resource not found", nil)
+		case services.IsValidationError(err):
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid resource", err)
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve resource timeline", err)
 		}
+		return
 	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// GetAuditLogs handles GET /api/audit-logs
+// The total number of matching logs is also returned in the X-Total-Count header.
+// Authenticated entity users only see logs in their own tenants' partitions; tenantId selects one partition.
+func (h *AuditHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := getAuditLogsRequestFromQuery(r.URL.Query())
+	entityIDs, ok := tenantScope(r)
+	if !ok {
+		utils.RespondWithError(w, http.StatusForbidden, "Access to tenant denied", nil)
+		return
+	}
+	req.EntityIDs = entityIDs
 
 	// Validate traceId format if provided
-	var traceIDPtr *string
-	if traceID != "" {
+	if req.TraceID != "" {
 		// Validate UUID format - return 400 for invalid format instead of 500
-		if _, err := uuid.Parse(traceID); err != nil {
+		if _, err := uuid.Parse(req.TraceID); err != nil {
 			utils.RespondWithError(w, http.StatusBadRequest, "Invalid traceId format: expected UUID", err)
 			return
 		}
-		traceIDPtr = &traceID
 	}
 
-	var eventTypePtr *string
-	if eventType != "" {
-		eventTypePtr = &eventType
-	}
-
-	logs, total, err := h.service.GetAuditLogs(r.Context(), traceIDPtr, eventTypePtr, limit, offset)
+	response, err := h.service.GetAuditLogs(r.Context(), &req)
 	if err != nil {
-		// Check if it's a validation error (e.g., invalid traceId format from service layer)
+		// Validation errors cover malformed times, cursors, status and sort order
 		if services.IsValidationError(err) {
 			utils.RespondWithError(w, http.StatusBadRequest, "Invalid query parameters", err)
 			return
@@ -103,16 +191,73 @@ func (h *AuditHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := models.GetAuditLogsResponse{
-		Logs:   make([]models.AuditLogResponse, len(logs)),
-		Total:  total,
-		Limit:  limit,
-		Offset: offset,
+	w.Header().Set("X-Total-Count", strconv.FormatInt(response.Total, 10))
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// tenantScope returns the tenants whose partitions the caller reads, or nil for every partition.
+// The tenantId query parameter, which may be repeated, selects partitions. Entity users are confined to
+// the tenants of their token, their entity IDs and user ID; ok is false when they select another
+// tenant's partition.
+func tenantScope(r *http.Request) (tenantIDs []string, ok bool) {
+	for _, tenantID := range r.URL.Query()["tenantId"] {
+		if tenantID = strings.TrimSpace(tenantID); tenantID != "" && !slices.Contains(tenantIDs, tenantID) {
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+	if principal, authenticated := middleware.PrincipalFromContext(r.Context()); authenticated && !principal.CanReadAll() {
+		scope := principal.Scope()
+		if len(tenantIDs) == 0 {
+			return scope, true
+		}
+		for _, tenantID := range tenantIDs {
+			if !slices.Contains(scope, tenantID) {
+				return nil, false
+			}
+		}
 	}
+	return tenantIDs, true
+}
 
-	for i, log := range logs {
-		response.Logs[i] = models.ToAuditLogResponse(log)
+// getAuditLogsRequestFromQuery reads the audit log filter, sort and pagination query parameters
+func getAuditLogsRequestFromQuery(query url.Values) models.GetAuditLogsRequest {
+	req := models.GetAuditLogsRequest{
+		TraceID:       query.Get("traceId"),
+		CorrelationID: query.Get("correlationId"),
+		EventType:     query.Get("eventType"),
+		EventAction:   query.Get("eventAction"),
+		Status:        query.Get("status"),
+		ActorType:     query.Get("actorType"),
+		ActorID:       query.Get("actorId"),
+		ConsumerAppID: query.Get("consumerAppId"),
+		TargetType:    query.Get("targetType"),
+		TargetID:      query.Get("targetId"),
+		StartTime:     query.Get("startTime"),
+		EndTime:       query.Get("endTime"),
+		Search:        query.Get("q"),
+		SortOrder:     query.Get("sortOrder"),
+		Cursor:        query.Get("cursor"),
+		Limit:         100, // default
 	}
 
-	utils.RespondWithJSON(w, http.StatusOK, response)
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
+			req.Limit = l
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			req.Offset = o
+		}
+	}
+	return req
+}
+
+// requestActor identifies the caller of an audited read: the authenticated user when the request
+// carries a verified token, otherwise the X-Actor-Type and X-Actor-Id headers set by the calling service
+func requestActor(r *http.Request) (actorType, actorID string) {
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		return principal.ActorType(), principal.Subject
+	}
+	return r.Header.Get("X-Actor-Type"), r.Header.Get("X-Actor-Id")
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,7 +11,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/audit-service/config"
+	"github.com/gov-dx-sandbox/audit-service/middleware"
 	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/schemas"
 	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
 	v1testutil "github.com/gov-dx-sandbox/audit-service/v1/testutil"
 	"github.com/stretchr/testify/assert"
@@ -146,6 +149,85 @@ func TestAuditHandler_CreateAuditLog(t *testing.T) {
 	}
 }
 
+func TestAuditHandler_CreateAuditLog_DuplicateEventID(t *testing.T) {
+	handler := NewAuditHandler(v1services.NewAuditService(v1testutil.NewMockRepository()))
+
+	post := func(status string) (int, v1models.AuditLog) {
+		body, err := json.Marshal(map[string]interface{}{
+			"eventId":    "orchestration-engine:request-1",
+			"timestamp":  time.Now().UTC().Format(time.RFC3339),
+			"status":     status,
+			"actorType":  "SERVICE",
+			"actorId":    "orchestration-engine",
+			"targetType": "SERVICE",
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/audit-logs", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		handler.CreateAuditLog(w, req)
+
+		var response v1models.AuditLog
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return w.Code, response
+	}
+
+	code, created := post(v1models.StatusSuccess)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, v1models.IngestionSourceHTTP, created.Source)
+
+	// A retried request returns the original log instead of storing a second one
+	code, duplicate := post(v1models.StatusFailure)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, created.ID, duplicate.ID)
+	assert.Equal(t, v1models.StatusSuccess, duplicate.Status)
+}
+
+func TestAuditHandler_CreateAuditLog_SchemaValidation(t *testing.T) {
+	enums := &config.AuditEnums{
+		EventTypes:   []string{"POLICY_CHECK", "MANAGEMENT_EVENT"},
+		EventActions: []string{"CREATE", "READ", "UPDATE", "DELETE"},
+		ActorTypes:   []string{"SERVICE", "ADMIN", "MEMBER", "SYSTEM"},
+		TargetTypes:  []string{"SERVICE", "RESOURCE"},
+	}
+	enums.InitializeMaps()
+	v1models.SetEnumConfig(enums)
+
+	registry, err := schemas.DefaultRegistry()
+	require.NoError(t, err)
+	service := v1services.NewAuditService(v1testutil.NewMockRepository())
+	service.SetEventSchemas(registry)
+	handler := NewAuditHandler(service)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"traceId":         uuid.New().String(),
+		"timestamp":       time.Now().UTC().Format(time.RFC3339),
+		"eventType":       "POLICY_CHECK",
+		"status":          v1models.StatusSuccess,
+		"actorType":       "SERVICE",
+		"actorId":         "orchestration-engine",
+		"targetType":      "SERVICE",
+		"targetId":        "policy-decision-point",
+		"requestMetadata": map[string]interface{}{"requiredFields": []string{"person.name"}},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/audit-logs", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handler.CreateAuditLog(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Error   string               `json:"error"`
+		Code    string               `json:"code"`
+		Details []schemas.FieldError `json:"details"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "SCHEMA_VALIDATION_FAILED", response.Code)
+	assert.Equal(t, []schemas.FieldError{
+		{Field: "requestMetadata.applicationId", Message: "is required"},
+		{Field: "requestMetadata.requiredFields[0]", Message: "must be of type object"},
+	}, response.Details)
+}
+
 func TestAuditHandler_GetAuditLogs(t *testing.T) {
 	mockRepo := v1testutil.NewMockRepository()
 	service := v1services.NewAuditService(mockRepo)
@@ -192,4 +274,227 @@ func TestAuditHandler_GetAuditLogs(t *testing.T) {
 		// Should return 200 OK (traceId is optional)
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("TotalCountHeader", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := mockRepo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+				Timestamp:  time.Now().UTC().Add(time.Duration(i) * time.Second),
+				Status:     v1models.StatusSuccess,
+				ActorType:  "SERVICE",
+				ActorID:    "orchestration-engine",
+				TargetType: "SERVICE",
+			})
+			require.NoError(t, err)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs?actorId=orchestration-engine&limit=2", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetAuditLogs(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+
+		var response v1models.GetAuditLogsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Logs, 2)
+		require.NotNil(t, response.NextCursor)
+
+		// The cursor continues where the first page stopped
+		req = httptest.NewRequest(http.MethodGet, "/api/audit-logs?actorId=orchestration-engine&limit=2&cursor="+*response.NextCursor, nil)
+		w = httptest.NewRecorder()
+
+		handler.GetAuditLogs(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var next v1models.GetAuditLogsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &next))
+		assert.Len(t, next.Logs, 1)
+		assert.Nil(t, next.NextCursor)
+		assert.NotEqual(t, response.Logs[1].ID, next.Logs[0].ID)
+	})
+
+	t.Run("InvalidTimeRange", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs?startTime=not-a-time", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetAuditLogs(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAuditHandler_GetAuditLogs_EntityScope(t *testing.T) {
+	mockRepo := v1testutil.NewMockRepository()
+	handler := NewAuditHandler(v1services.NewAuditService(mockRepo))
+
+	for _, actorID := range []string{"member-user", "passport-app", "tax-app"} {
+		_, err := mockRepo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:  time.Now().UTC(),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "MEMBER",
+			ActorID:    actorID,
+			TargetType: "SERVICE",
+		})
+		require.NoError(t, err)
+	}
+
+	actorIDs := func(principal *middleware.Principal) []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs?sortOrder=asc", nil)
+		req = req.WithContext(middleware.WithPrincipal(req.Context(), principal))
+		w := httptest.NewRecorder()
+
+		handler.GetAuditLogs(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response v1models.GetAuditLogsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		ids := []string{}
+		for _, log := range response.Logs {
+			ids = append(ids, log.ActorID)
+		}
+		return ids
+	}
+
+	member := &middleware.Principal{Subject: "member-user", Roles: []string{middleware.RoleMember}, EntityIDs: []string{"passport-app"}}
+	assert.Equal(t, []string{"member-user", "passport-app"}, actorIDs(member))
+
+	admin := &middleware.Principal{Subject: "admin-user", Roles: []string{middleware.RoleAdmin}}
+	assert.Equal(t, []string{"member-user", "passport-app", "tax-app"}, actorIDs(admin))
+}
+
+func TestAuditHandler_GetAuditLogs_TenantID(t *testing.T) {
+	mockRepo := v1testutil.NewMockRepository()
+	handler := NewAuditHandler(v1services.NewAuditService(mockRepo))
+
+	for _, actorID := range []string{"member-user", "passport-app", "tax-app"} {
+		_, err := mockRepo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:  time.Now().UTC(),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "MEMBER",
+			ActorID:    actorID,
+			TargetType: "SERVICE",
+		})
+		require.NoError(t, err)
+	}
+
+	get := func(tenantID string, principal *middleware.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs?tenantId="+tenantID, nil)
+		req = req.WithContext(middleware.WithPrincipal(req.Context(), principal))
+		w := httptest.NewRecorder()
+		handler.GetAuditLogs(w, req)
+		return w
+	}
+	total := func(w *httptest.ResponseRecorder) int64 {
+		require.Equal(t, http.StatusOK, w.Code)
+		var response v1models.GetAuditLogsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Total
+	}
+
+	member := &middleware.Principal{Subject: "member-user", Roles: []string{middleware.RoleMember}, EntityIDs: []string{"passport-app"}}
+	assert.Equal(t, int64(1), total(get("passport-app", member)))
+	assert.Equal(t, http.StatusForbidden, get("tax-app", member).Code)
+
+	assert.Equal(t, int64(2), total(get("passport-app&tenantId=member-user", member)))
+	assert.Equal(t, http.StatusForbidden, get("passport-app&tenantId=tax-app", member).Code)
+
+	// Admins can look into any tenant, or several, as portal-backend does to scope a member's logs
+	admin := &middleware.Principal{Subject: "admin-user", Roles: []string{middleware.RoleAdmin}}
+	assert.Equal(t, int64(1), total(get("tax-app", admin)))
+	assert.Equal(t, int64(2), total(get("tax-app&tenantId=passport-app", admin)))
+}
+
+func TestAuditHandler_GetTrace(t *testing.T) {
+	mockRepo := v1testutil.NewMockRepository()
+	handler := NewAuditHandler(v1services.NewAuditService(mockRepo))
+
+	traceID := uuid.New()
+	start := time.Now().UTC()
+	for i, target := range []string{"policy-decision-point", "consent-engine"} {
+		_, err := mockRepo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:  start.Add(time.Duration(i) * time.Second),
+			TraceID:    &traceID,
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+			TargetID:   &target,
+		})
+		require.NoError(t, err)
+	}
+
+	get := func(path string, principal *middleware.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if principal != nil {
+			req = req.WithContext(middleware.WithPrincipal(req.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		handler.GetTrace(w, req)
+		return w
+	}
+
+	w := get("/api/audit-logs/trace/"+traceID.String(), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var trace v1models.TraceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trace))
+	assert.Equal(t, traceID.String(), trace.TraceID)
+	assert.Equal(t, 2, trace.EventCount)
+	assert.Equal(t, int64(1000), trace.DurationMs)
+	assert.Equal(t, []string{"orchestration-engine", "policy-decision-point", "consent-engine"}, trace.Participants)
+
+	member := &middleware.Principal{Subject: "member-user", Roles: []string{middleware.RoleMember}, EntityIDs: []string{"consent-engine"}}
+	w = get("/api/audit-logs/trace/"+traceID.String(), member)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trace))
+	assert.Equal(t, 1, trace.EventCount)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/audit-logs/trace/"+uuid.New().String(), nil).Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/audit-logs/trace/", nil).Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/audit-logs/trace/a/b", nil).Code)
+}
+
+func TestAuditHandler_GetResourceTimeline(t *testing.T) {
+	mockRepo := v1testutil.NewMockRepository()
+	handler := NewAuditHandler(v1services.NewAuditService(mockRepo))
+
+	eventType := "MANAGEMENT_EVENT"
+	start := time.Now().UTC()
+	for i, metadata := range []string{
+		`{"resource":"APPLICATIONS","resourceId":"app 1"}`,
+		`{"resource":"BULK-OPERATIONS","operation":"suspend","items":[{"type":"application","id":"app 1","success":true}]}`,
+	} {
+		_, err := mockRepo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:          start.Add(time.Duration(i) * time.Second),
+			EventType:          &eventType,
+			Status:             v1models.StatusSuccess,
+			ActorType:          "ADMIN",
+			ActorID:            "admin-1",
+			TargetType:         "RESOURCE",
+			AdditionalMetadata: v1models.JSONBRawMessage(metadata),
+		})
+		require.NoError(t, err)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.GetResourceTimeline(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/events/resource/applications/app%201")
+	require.Equal(t, http.StatusOK, w.Code)
+	var timeline v1models.ResourceTimelineResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &timeline))
+	assert.Equal(t, "APPLICATION", timeline.ResourceType)
+	assert.Equal(t, "app 1", timeline.ResourceID)
+	require.Len(t, timeline.Events, 2)
+	assert.Equal(t, "self", timeline.Events[0].Relation)
+	assert.Equal(t, "bulk", timeline.Events[1].Relation)
+	assert.Equal(t, "BULK-OPERATIONS", timeline.Events[1].Resource)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/events/resource/schemas/app%201").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/events/resource/applications").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/events/resource/applications/app-1/grants").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/events/resource/applications/app%5C1").Code)
 }
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// erasuresPath is the route for data subject erasures
+const erasuresPath = "/api/audit-logs/erasures"
+
+// ErasureHandler handles HTTP requests for data subject erasures
+type ErasureHandler struct {
+	service *services.ErasureService
+}
+
+// NewErasureHandler creates a new erasure handler
+func NewErasureHandler(service *services.ErasureService) *ErasureHandler {
+	return &ErasureHandler{service: service}
+}
+
+// HandleErasures handles POST /api/audit-logs/erasures (erase a data owner),
+// GET /api/audit-logs/erasures (list recent erasures) and GET /api/audit-logs/erasures/{id}
+func (h *ErasureHandler) HandleErasures(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, erasuresPath), "/")
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		h.createErasure(w, r)
+	case id == "" && r.Method == http.MethodGet:
+		h.listErasures(w, r)
+	case id != "" && !strings.Contains(id, "/") && r.Method == http.MethodGet:
+		h.getErasure(w, r, id)
+	case id != "" && strings.Contains(id, "/"):
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ErasureHandler) createErasure(w http.ResponseWriter, r *http.Request) {
+	var req v1models.CreateErasureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.ActorType, req.ActorID = requestActor(r)
+
+	erasure, err := h.service.EraseSubject(r.Context(), &req)
+	if err != nil {
+		respondWithErasureError(w, err)
+		return
+	}
+	w.Header().Set("Location", erasuresPath+"/"+erasure.ID.String())
+	utils.RespondWithJSON(w, http.StatusCreated, erasure)
+}
+
+func (h *ErasureHandler) listErasures(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid limit parameter", err)
+			return
+		}
+		limit = parsed
+	}
+
+	erasures, err := h.service.ListErasures(r.Context(), limit)
+	if err != nil {
+		respondWithErasureError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, erasures)
+}
+
+func (h *ErasureHandler) getErasure(w http.ResponseWriter, r *http.Request, id string) {
+	erasure, err := h.service.GetErasure(r.Context(), id)
+	if err != nil {
+		respondWithErasureError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, erasure)
+}
+
+// respondWithErasureError maps erasure service errors to HTTP status codes
+func respondWithErasureError(w http.ResponseWriter, err error) {
+	switch {
+	case services.IsValidationError(err):
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid erasure request", err)
+	case errors.Is(err, services.ErrErasureNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Erasure not found", err)
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to process erasure request", err)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1database "github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestErasureHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	repo := v1database.NewGormRepository(db)
+	handler := NewErasureHandler(v1services.NewErasureService(repo, repo))
+
+	_, err = repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+		Timestamp:       time.Now().UTC(),
+		Status:          v1models.StatusSuccess,
+		ActorType:       "SERVICE",
+		ActorID:         "orchestration-engine",
+		TargetType:      "SERVICE",
+		RequestMetadata: v1models.JSONBRawMessage(`{"ownerId":"citizen@example.com"}`),
+	})
+	require.NoError(t, err)
+
+	var erasureID string
+	t.Run("CreateErasure", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/audit-logs/erasures",
+			strings.NewReader(`{"ownerId":"citizen@example.com","reference":"DSR-42"}`))
+		req.Header.Set("X-Actor-Type", "ADMIN")
+		req.Header.Set("X-Actor-Id", "dpo@example.com")
+		w := httptest.NewRecorder()
+
+		handler.HandleErasures(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var erasure v1models.ErasureResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &erasure))
+		erasureID = erasure.ID.String()
+		assert.Equal(t, "/api/audit-logs/erasures/"+erasureID, w.Header().Get("Location"))
+		assert.Equal(t, v1models.ErasureStatusCompleted, erasure.Status)
+		assert.Equal(t, int64(1), erasure.PseudonymizedCount)
+		assert.Equal(t, "dpo@example.com", erasure.ActorID)
+		assert.NotContains(t, w.Body.String(), "citizen@example.com")
+	})
+
+	t.Run("GetErasure", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.HandleErasures(w, httptest.NewRequest(http.MethodGet, "/api/audit-logs/erasures/"+erasureID, nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var erasure v1models.ErasureResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &erasure))
+		assert.Equal(t, "DSR-42", *erasure.Reference)
+	})
+
+	t.Run("ListErasures", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.HandleErasures(w, httptest.NewRequest(http.MethodGet, "/api/audit-logs/erasures?limit=10", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var erasures v1models.ListErasuresResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &erasures))
+		assert.Len(t, erasures.Erasures, 1)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			method, path, body string
+			want               int
+		}{
+			{http.MethodPost, "/api/audit-logs/erasures", `{"ownerId":""}`, http.StatusBadRequest},
+			{http.MethodPost, "/api/audit-logs/erasures", `not json`, http.StatusBadRequest},
+			{http.MethodGet, "/api/audit-logs/erasures/not-a-uuid", "", http.StatusBadRequest},
+			{http.MethodGet, "/api/audit-logs/erasures/00000000-0000-0000-0000-000000000000", "", http.StatusNotFound},
+			{http.MethodGet, "/api/audit-logs/erasures?limit=0", "", http.StatusBadRequest},
+			{http.MethodDelete, "/api/audit-logs/erasures", "", http.StatusMethodNotAllowed},
+		}
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			handler.HandleErasures(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.want, w.Code, "%s %s", tt.method, tt.path)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/schemas"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// eventTypesPath is the route for the event type registry
+const eventTypesPath = "/api/audit-logs/event-types"
+
+// EventTypeHandler serves the registry of event schemas, so producing services can discover the payloads they must send
+type EventTypeHandler struct {
+	registry *schemas.Registry
+}
+
+// NewEventTypeHandler creates a new event type handler
+func NewEventTypeHandler(registry *schemas.Registry) *EventTypeHandler {
+	return &EventTypeHandler{registry: registry}
+}
+
+// HandleEventTypes handles GET /api/audit-logs/event-types and GET /api/audit-logs/event-types/{eventType}
+func (h *EventTypeHandler) HandleEventTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	eventType := strings.Trim(strings.TrimPrefix(r.URL.Path, eventTypesPath), "/")
+	switch {
+	case eventType == "":
+		h.listEventTypes(w)
+	case !strings.Contains(eventType, "/"):
+		h.getEventType(w, eventType)
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+	}
+}
+
+// listEventTypes serves a summary of every event type that has a schema
+func (h *EventTypeHandler) listEventTypes(w http.ResponseWriter) {
+	response := v1models.ListEventTypesResponse{EventTypes: []v1models.EventTypeSummary{}}
+	for _, eventType := range h.registry.EventTypes() {
+		versions := h.registry.Versions(eventType)
+		summary := v1models.EventTypeSummary{
+			EventType:     eventType,
+			Description:   versions[len(versions)-1].Description,
+			Versions:      make([]int, len(versions)),
+			LatestVersion: len(versions),
+		}
+		for i, schema := range versions {
+			summary.Versions[i] = schema.Version
+		}
+		response.EventTypes = append(response.EventTypes, summary)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// getEventType serves every schema version of an event type
+func (h *EventTypeHandler) getEventType(w http.ResponseWriter, eventType string) {
+	versions := h.registry.Versions(eventType)
+	if len(versions) == 0 {
+		utils.RespondWithError(w, http.StatusNotFound, "Event type has no schema", nil)
+		return
+	}
+
+	response := v1models.EventTypeResponse{
+		EventType:     eventType,
+		LatestVersion: len(versions),
+		Versions:      make([]v1models.EventSchemaVersion, len(versions)),
+	}
+	for i, schema := range versions {
+		response.Versions[i] = v1models.EventSchemaVersion{
+			Version:     schema.Version,
+			Description: schema.Description,
+			Schema:      schema.Document,
+		}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/schemas"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTypeHandler_HandleEventTypes(t *testing.T) {
+	registry, err := schemas.DefaultRegistry()
+	require.NoError(t, err)
+	handler := NewEventTypeHandler(registry)
+
+	call := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.HandleEventTypes(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("List", func(t *testing.T) {
+		w := call(http.MethodGet, "/api/audit-logs/event-types")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response v1models.ListEventTypesResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		require.Len(t, response.EventTypes, len(registry.EventTypes()))
+		for _, summary := range response.EventTypes {
+			assert.Equal(t, []int{1}, summary.Versions, summary.EventType)
+			assert.Equal(t, 1, summary.LatestVersion, summary.EventType)
+			assert.NotEmpty(t, summary.Description, summary.EventType)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		w := call(http.MethodGet, "/api/audit-logs/event-types/CONSENT_CHECK")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response v1models.EventTypeResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, "CONSENT_CHECK", response.EventType)
+		assert.Equal(t, 1, response.LatestVersion)
+		require.Len(t, response.Versions, 1)
+
+		var schema map[string]interface{}
+		require.NoError(t, json.Unmarshal(response.Versions[0].Schema, &schema))
+		assert.Equal(t, "object", schema["type"])
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/api/audit-logs/event-types/USER_MANAGEMENT").Code)
+		assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/api/audit-logs/event-types/CONSENT_CHECK/1").Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, call(http.MethodPost, "/api/audit-logs/event-types").Code)
+	})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+const (
+	// exportBatchWriteTimeout is the write deadline for each streamed batch;
+	// it replaces the server's WriteTimeout, which would otherwise cut off long exports
+	exportBatchWriteTimeout = 2 * time.Minute
+	// exportDownloadWriteTimeout is the write deadline for downloading a finished export job
+	exportDownloadWriteTimeout = 30 * time.Minute
+
+	// exportJobsPath is the route prefix for asynchronous export jobs
+	exportJobsPath = "/api/audit-logs/exports/"
+)
+
+// ExportHandler handles HTTP requests for audit log exports
+type ExportHandler struct {
+	service *services.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(service *services.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportAuditLogs handles GET /api/audit-logs/export
+// Accepts the same filters as GET /api/audit-logs plus format=csv|ndjson.
+// With async=true the export runs as a background job and 202 Accepted is returned.
+func (h *ExportHandler) ExportAuditLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := models.ExportAuditLogsRequest{
+		Query:  getAuditLogsRequestFromQuery(query),
+		Format: query.Get("format"),
+	}
+	req.ActorType, req.ActorID = requestActor(r)
+	// Pagination does not apply to exports, apart from resuming after a cursor
+	req.Query.Limit = 0
+	req.Query.Offset = 0
+
+	if async, _ := strconv.ParseBool(query.Get("async")); async {
+		job, err := h.service.CreateExportJob(r.Context(), &req)
+		if err != nil {
+			respondWithExportError(w, err)
+			return
+		}
+		w.Header().Set("Location", exportJobsPath+job.ID.String())
+		utils.RespondWithJSON(w, http.StatusAccepted, job)
+		return
+	}
+
+	export, err := h.service.PrepareExport(r.Context(), &req)
+	if err != nil {
+		respondWithExportError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	extendDeadline := func() error {
+		if err := rc.SetWriteDeadline(time.Now().Add(exportBatchWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+	if err := extendDeadline(); err != nil {
+		slog.Warn("Failed to extend export write deadline", "error", err)
+	}
+
+	filename := fmt.Sprintf("audit-logs-%s.%s", time.Now().UTC().Format("20060102T150405Z"), export.Format)
+	w.Header().Set("Content-Type", export.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("X-Total-Count", strconv.FormatInt(export.Total, 10))
+	w.WriteHeader(http.StatusOK)
+
+	written, err := h.service.WriteExport(r.Context(), export, w, func() error {
+		if err := rc.Flush(); err != nil {
+			return err
+		}
+		return extendDeadline()
+	})
+	if err != nil {
+		// The status has already been sent; the client sees a truncated body
+		slog.Error("Audit log export aborted", "format", export.Format, "written", written, "error", err)
+	}
+}
+
+// HandleExportJobs handles GET /api/audit-logs/exports/{id} and GET /api/audit-logs/exports/{id}/download
+func (h *ExportHandler) HandleExportJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, exportJobsPath), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		h.getExportJob(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "download":
+		h.downloadExportJob(w, r, parts[0])
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+	}
+}
+
+func (h *ExportHandler) getExportJob(w http.ResponseWriter, r *http.Request, id string) {
+	job, err := h.service.GetExportJob(r.Context(), id)
+	if err != nil {
+		respondWithExportError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, job)
+}
+
+func (h *ExportHandler) downloadExportJob(w http.ResponseWriter, r *http.Request, id string) {
+	actorType, actorID := requestActor(r)
+	file, job, err := h.service.OpenExportJobFile(r.Context(), id, actorType, actorID)
+	if err != nil {
+		respondWithExportError(w, err)
+		return
+	}
+	defer file.Close()
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportDownloadWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Warn("Failed to extend export download write deadline", "error", err)
+	}
+
+	format := services.ExportFormat(job.Format)
+	filename := "audit-logs-" + job.ID.String() + filepath.Ext(job.FilePath)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	http.ServeContent(w, r, filename, job.UpdatedAt, file)
+}
+
+// respondWithExportError maps export service errors to HTTP status codes
+func respondWithExportError(w http.ResponseWriter, err error) {
+	switch {
+	case services.IsValidationError(err):
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid export request", err)
+	case errors.Is(err, services.ErrExportTooLarge):
+		utils.RespondWithError(w, http.StatusBadRequest, "Export too large, retry with async=true", err)
+	case errors.Is(err, services.ErrExportJobNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Export job not found", err)
+	case errors.Is(err, services.ErrExportJobNotReady):
+		utils.RespondWithError(w, http.StatusConflict, "Export job has not completed", err)
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to export audit logs", err)
+	}
+}
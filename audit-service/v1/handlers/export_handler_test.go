@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	v1testutil "github.com/gov-dx-sandbox/audit-service/v1/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportHandler(t *testing.T) {
+	mockRepo := v1testutil.NewMockRepository()
+	service := v1services.NewExportService(mockRepo, mockRepo, t.TempDir(), 0)
+	handler := NewExportHandler(service)
+
+	for i := 0; i < 2; i++ {
+		_, err := mockRepo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:  time.Now().UTC().Add(-time.Duration(i+1) * time.Minute),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("StreamsCSV", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/export?format=csv&actorId=orchestration-engine", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportAuditLogs(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+		assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, rows, 3) // header + 2 logs
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/export?format=xml", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportAuditLogs(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("AsyncJob", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/export?format=ndjson&async=true&actorId=orchestration-engine", nil)
+		req.Header.Set("X-Actor-Type", "ADMIN")
+		req.Header.Set("X-Actor-Id", "admin@example.com")
+		w := httptest.NewRecorder()
+
+		handler.ExportAuditLogs(w, req)
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		var job v1models.ExportJobResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		assert.Equal(t, "/api/audit-logs/exports/"+job.ID.String(), w.Header().Get("Location"))
+
+		service.Wait()
+
+		req = httptest.NewRequest(http.MethodGet, "/api/audit-logs/exports/"+job.ID.String(), nil)
+		w = httptest.NewRecorder()
+		handler.HandleExportJobs(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		assert.Equal(t, v1models.ExportJobStatusCompleted, job.Status)
+		assert.Equal(t, int64(2), job.RowCount)
+		require.NotNil(t, job.DownloadURL)
+
+		req = httptest.NewRequest(http.MethodGet, *job.DownloadURL, nil)
+		w = httptest.NewRecorder()
+		handler.HandleExportJobs(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Equal(t, int(job.FileSize), w.Body.Len())
+	})
+
+	t.Run("UnknownJob", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/exports/00000000-0000-0000-0000-000000000001", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleExportJobs(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
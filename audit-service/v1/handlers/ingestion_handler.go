@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// IngestionHandler handles HTTP requests about audit log ingestion
+type IngestionHandler struct {
+	service *services.IngestionService
+}
+
+// NewIngestionHandler creates a new ingestion handler
+func NewIngestionHandler(service *services.IngestionService) *IngestionHandler {
+	return &IngestionHandler{service: service}
+}
+
+// Reconcile handles GET /api/audit-logs/ingestion/reconciliation
+// Query parameters: startTime and endTime (RFC3339); the default window is the last 24 hours
+func (h *IngestionHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	response, err := h.service.Reconcile(r.Context(), query.Get("startTime"), query.Get("endTime"))
+	if err != nil {
+		if services.IsValidationError(err) {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid reconciliation parameters", err)
+			return
+		}
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to reconcile ingestion counts", err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
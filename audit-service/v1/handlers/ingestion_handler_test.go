@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1database "github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestIngestionHandler_Reconcile(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	repo := v1database.NewGormRepository(db)
+	handler := NewIngestionHandler(v1services.NewIngestionService(v1services.NewAuditService(repo), repo, v1services.QueueConfig{}))
+
+	for _, source := range []string{v1models.IngestionSourceHTTP, v1models.IngestionSourceQueue, v1models.IngestionSourceQueue} {
+		_, err := repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Source:     source,
+			Timestamp:  time.Now().UTC().Add(-time.Minute),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("Counts", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/ingestion/reconciliation", nil)
+		w := httptest.NewRecorder()
+
+		handler.Reconcile(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var result v1models.IngestionReconciliationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, v1models.IngestionCounts{HTTP: 1, Queue: 2}, result.Totals)
+		require.Len(t, result.ByActor, 1)
+		assert.Equal(t, "orchestration-engine", result.ByActor[0].ActorID)
+		assert.Nil(t, result.Consumer)
+	})
+
+	t.Run("InvalidTime", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/ingestion/reconciliation?startTime=yesterday", nil)
+		w := httptest.NewRecorder()
+
+		handler.Reconcile(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/audit-logs/ingestion/reconciliation", nil)
+		w := httptest.NewRecorder()
+
+		handler.Reconcile(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// verifyWriteTimeout replaces the server's WriteTimeout while verifying, since long ranges take a while
+const verifyWriteTimeout = 10 * time.Minute
+
+// IntegrityHandler handles HTTP requests for audit log integrity verification
+type IntegrityHandler struct {
+	service *services.IntegrityService
+}
+
+// NewIntegrityHandler creates a new integrity handler
+func NewIntegrityHandler(service *services.IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{service: service}
+}
+
+// VerifyChain handles GET /api/audit-logs/verify
+// Optional from and to query parameters bound the verified range of chain sequence numbers.
+// A broken chain is reported in the response body with 200 OK; errors are reserved for bad requests.
+func (h *IntegrityHandler) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, err := parseSequenceParam(r, "from")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	to, err := parseSequenceParam(r, "to")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(verifyWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Warn("Failed to extend verification write deadline", "error", err)
+	}
+
+	result, err := h.service.VerifyChain(r.Context(), from, to)
+	if err != nil {
+		if services.IsValidationError(err) {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid query parameters", err)
+			return
+		}
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to verify audit chain", err)
+		return
+	}
+
+	if !result.Valid {
+		slog.Warn("Audit chain verification failed", "sequence", result.Failure.Sequence, "reason", result.Failure.Reason)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, result)
+}
+
+// parseSequenceParam reads an optional positive chain sequence number from the query
+func parseSequenceParam(r *http.Request, name string) (int64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	sequence, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sequence < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return sequence, nil
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// SIEMHandler handles HTTP requests about the SIEM export
+type SIEMHandler struct {
+	exporter *services.SIEMExporter
+}
+
+// NewSIEMHandler creates a new SIEM handler; a nil exporter reports export as disabled
+func NewSIEMHandler(exporter *services.SIEMExporter) *SIEMHandler {
+	return &SIEMHandler{exporter: exporter}
+}
+
+// GetStatus handles GET /api/audit-logs/siem/status
+// Returns the exporter's connection state and delivery counters since the service started
+func (h *SIEMHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, h.exporter.Status())
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// StatsHandler handles HTTP requests for aggregate audit log statistics
+type StatsHandler struct {
+	service *services.StatsService
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(service *services.StatsService) *StatsHandler {
+	return &StatsHandler{service: service}
+}
+
+// GetStats handles GET /api/audit-logs/stats
+// Query parameters: startTime and endTime (RFC3339; the default window is the last 30 days),
+// bucket (hour, day or week), groupBy, limit, and the optional eventType and consumerAppId scope
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &v1models.GetStatsRequest{
+		StartTime:     query.Get("startTime"),
+		EndTime:       query.Get("endTime"),
+		Bucket:        query.Get("bucket"),
+		GroupBy:       query.Get("groupBy"),
+		EventType:     query.Get("eventType"),
+		ConsumerAppID: query.Get("consumerAppId"),
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid stats parameters", err)
+			return
+		}
+		req.Limit = limit
+	}
+
+	response, err := h.service.GetStats(r.Context(), req)
+	if err != nil {
+		if services.IsValidationError(err) {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid stats parameters", err)
+			return
+		}
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to compute audit log statistics", err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
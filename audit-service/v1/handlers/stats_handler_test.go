@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	v1testutil "github.com/gov-dx-sandbox/audit-service/v1/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHandler_GetStats(t *testing.T) {
+	repo := v1testutil.NewMockRepository()
+	handler := NewStatsHandler(v1services.NewStatsService(repo))
+
+	for _, status := range []string{v1models.StatusSuccess, v1models.StatusFailure} {
+		_, err := repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:  time.Now().UTC().Add(-time.Minute),
+			Status:     status,
+			ActorType:  v1models.ActorTypeApplication,
+			ActorID:    "app-1",
+			TargetType: "SERVICE",
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("Stats", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/stats?bucket=hour&groupBy=status&startTime="+
+			time.Now().UTC().Add(-2*time.Hour).Format(time.RFC3339), nil)
+		w := httptest.NewRecorder()
+
+		handler.GetStats(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var result v1models.StatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, int64(2), result.Totals.Events)
+		assert.Equal(t, 0.5, result.Totals.FailureRate)
+		assert.Equal(t, "status", result.GroupBy)
+		assert.Len(t, result.Groups, 2)
+		require.Len(t, result.TopConsumers, 1)
+		assert.Equal(t, "app-1", result.TopConsumers[0].Key)
+	})
+
+	t.Run("InvalidParameters", func(t *testing.T) {
+		for _, query := range []string{"bucket=month", "limit=ten", "startTime=yesterday"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/stats?"+query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetStats(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/audit-logs/stats", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetStats(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// streamRetry is the reconnection delay, in milliseconds, suggested to stream clients
+const streamRetry = 3000
+
+// StreamHandler handles the live stream of stored logs and fired alerts
+type StreamHandler struct {
+	service   *services.StreamService
+	heartbeat time.Duration
+}
+
+// NewStreamHandler creates a new stream handler sending a heartbeat on idle streams every heartbeat;
+// non-positive intervals use services.DefaultStreamHeartbeatInterval
+func NewStreamHandler(service *services.StreamService, heartbeat time.Duration) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = services.DefaultStreamHeartbeatInterval
+	}
+	return &StreamHandler{service: service, heartbeat: heartbeat}
+}
+
+// StreamEvents handles GET /api/audit-logs/stream as Server-Sent Events.
+// Query parameters: type (log or alert), eventType, status and severity, each repeatable or
+// comma-separated, and actorId, targetId and tenantId. Entity users receive only their own logs and no alerts.
+// Clients resume with the Last-Event-ID header (or lastEventId parameter); a reset event tells them that
+// events were missed and should be reloaded with GET /api/audit-logs.
+func (h *StreamHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := services.StreamFilter{
+		Types:      queryList(query["type"]),
+		EventTypes: queryList(query["eventType"]),
+		Statuses:   queryList(query["status"]),
+		Severities: queryList(query["severity"]),
+		ActorID:    query.Get("actorId"),
+		TargetID:   query.Get("targetId"),
+	}
+	for _, eventType := range filter.Types {
+		if eventType != services.StreamEventLog && eventType != services.StreamEventAlert {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid stream parameters",
+				fmt.Errorf("type must be %s or %s", services.StreamEventLog, services.StreamEventAlert))
+			return
+		}
+	}
+	entityIDs, ok := tenantScope(r)
+	if !ok {
+		utils.RespondWithError(w, http.StatusForbidden, "Access to tenant denied", nil)
+		return
+	}
+	filter.EntityIDs = entityIDs
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("lastEventId")
+	}
+
+	sub, replay, err := h.service.Subscribe(filter, lastEventID)
+	defer h.service.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	stream := &eventStreamWriter{w: w, rc: http.NewResponseController(w), timeout: 2 * h.heartbeat}
+	stream.writef("retry: %d\n\n", streamRetry)
+	if errors.Is(err, services.ErrStreamResumeGap) {
+		stream.writef("event: reset\ndata: {\"lastEventId\":%q}\n\n", lastEventID)
+	}
+	for _, event := range replay {
+		stream.writeEvent(event)
+	}
+	stream.flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for stream.err == nil {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-sub.Events():
+			if !open {
+				if sub.Dropped() {
+					slog.Warn("Dropped audit stream subscriber that fell behind")
+				}
+				return
+			}
+			stream.writeEvent(event)
+			stream.flush()
+		case <-ticker.C:
+			stream.writef(": heartbeat %s\n\n", time.Now().UTC().Format(time.RFC3339))
+			stream.flush()
+		}
+	}
+}
+
+// queryList splits repeated and comma-separated query values
+func queryList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" && !slices.Contains(list, item) {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// eventStreamWriter writes Server-Sent Events, remembering the first write error
+type eventStreamWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+	err     error
+}
+
+func (s *eventStreamWriter) writef(format string, args ...any) {
+	if s.err != nil {
+		return
+	}
+	// The server's write timeout would otherwise end long-lived streams; errors mean the writer has no deadline
+	_ = s.rc.SetWriteDeadline(time.Now().Add(s.timeout))
+	_, s.err = fmt.Fprintf(s.w, format, args...)
+}
+
+func (s *eventStreamWriter) writeEvent(event *services.StreamEvent) {
+	data, err := json.Marshal(event.Payload())
+	if err != nil {
+		slog.Error("Failed to encode audit stream event", "id", event.ID, "error", err)
+		return
+	}
+	s.writef("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
+
+func (s *eventStreamWriter) flush() {
+	if s.err == nil {
+		s.err = s.rc.Flush()
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/middleware"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvent is an event read from a Server-Sent Events stream
+type sseEvent struct {
+	id, event, data string
+}
+
+// openStream connects to the stream as the principal and returns a function reading its next event
+func openStream(t *testing.T, stream *v1services.StreamService, principal *middleware.Principal, query string, header http.Header) (*http.Response, func() sseEvent) {
+	t.Helper()
+	handler := NewStreamHandler(stream, 50*time.Millisecond)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal != nil {
+			r = r.WithContext(middleware.WithPrincipal(r.Context(), principal))
+		}
+		handler.StreamEvents(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/audit-logs/stream?"+query, nil)
+	require.NoError(t, err)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	reader := bufio.NewReader(resp.Body)
+	return resp, func() sseEvent {
+		t.Helper()
+		var event sseEvent
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "" && (event.event != "" || event.data != ""):
+				return event
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			case strings.HasPrefix(line, ": heartbeat"):
+				event.event = "heartbeat"
+			}
+		}
+	}
+}
+
+// waitForSubscribers waits until the stream has n subscribers
+func waitForSubscribers(t *testing.T, stream *v1services.StreamService, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return stream.Subscribers() == n }, time.Second, 5*time.Millisecond)
+}
+
+func newStreamLog(actorID string) *v1models.AuditLog {
+	return &v1models.AuditLog{
+		ID:         uuid.New(),
+		Timestamp:  time.Now().UTC(),
+		Status:     v1models.StatusSuccess,
+		ActorType:  v1models.ActorTypeApplication,
+		ActorID:    actorID,
+		TargetType: "SERVICE",
+	}
+}
+
+func TestStreamHandler_StreamEvents(t *testing.T) {
+	stream := v1services.NewStreamService(10)
+	resp, next := openStream(t, stream, nil, "type=log", nil)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitForSubscribers(t, stream, 1)
+
+	stream.PublishAlert(&v1models.Alert{ID: uuid.New(), RuleName: "rule", Severity: "critical"})
+	log := newStreamLog("app-1")
+	stream.PublishLog(log)
+
+	event := next()
+	assert.Equal(t, "log", event.event)
+	assert.NotEmpty(t, event.id)
+	assert.Contains(t, event.data, log.ID.String())
+
+	assert.Equal(t, "heartbeat", next().event)
+}
+
+func TestStreamHandler_Resume(t *testing.T) {
+	stream := v1services.NewStreamService(10)
+	_, next := openStream(t, stream, nil, "", nil)
+	waitForSubscribers(t, stream, 1)
+	stream.PublishLog(newStreamLog("app-1"))
+	first := next()
+
+	second := newStreamLog("app-2")
+	stream.PublishLog(second)
+
+	t.Run("replays missed events", func(t *testing.T) {
+		_, resumed := openStream(t, stream, nil, "", http.Header{"Last-Event-ID": {first.id}})
+		event := resumed()
+		assert.Equal(t, "log", event.event)
+		assert.Contains(t, event.data, second.ID.String())
+	})
+
+	t.Run("resets unknown event IDs", func(t *testing.T) {
+		_, resumed := openStream(t, stream, nil, "lastEventId=before-restart-1", nil)
+		assert.Equal(t, "reset", resumed().event)
+	})
+}
+
+func TestStreamHandler_EntityUsers(t *testing.T) {
+	stream := v1services.NewStreamService(10)
+	member := &middleware.Principal{Subject: "member-user", Roles: []string{middleware.RoleMember}, EntityIDs: []string{"passport-app"}}
+	_, next := openStream(t, stream, member, "", nil)
+	waitForSubscribers(t, stream, 1)
+
+	stream.PublishLog(newStreamLog("tax-app"))
+	stream.PublishAlert(&v1models.Alert{ID: uuid.New(), RuleName: "rule", Severity: "critical"})
+	own := newStreamLog("passport-app")
+	stream.PublishLog(own)
+
+	event := next()
+	assert.Equal(t, "log", event.event)
+	assert.Contains(t, event.data, own.ID.String())
+}
+
+func TestStreamHandler_InvalidRequests(t *testing.T) {
+	handler := NewStreamHandler(v1services.NewStreamService(10), time.Second)
+	member := &middleware.Principal{Subject: "member-user", Roles: []string{middleware.RoleMember}, EntityIDs: []string{"passport-app"}}
+
+	tests := []struct {
+		name      string
+		method    string
+		query     string
+		principal *middleware.Principal
+		want      int
+	}{
+		{"method not allowed", http.MethodPost, "", nil, http.StatusMethodNotAllowed},
+		{"unknown type", http.MethodGet, "type=trace", nil, http.StatusBadRequest},
+		{"other tenant", http.MethodGet, "tenantId=tax-app", member, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/audit-logs/stream?"+tt.query, nil)
+			if tt.principal != nil {
+				req = req.WithContext(middleware.WithPrincipal(req.Context(), tt.principal))
+			}
+			w := httptest.NewRecorder()
+
+			handler.StreamEvents(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
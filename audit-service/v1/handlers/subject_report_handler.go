@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// subjectReportPath is the path prefix of data subject access reports; the owner ID follows it
+const subjectReportPath = "/api/audit-logs/subject/"
+
+// SubjectReportHandler handles HTTP requests for data subject access reports
+type SubjectReportHandler struct {
+	service *services.SubjectReportService
+}
+
+// NewSubjectReportHandler creates a new subject report handler
+func NewSubjectReportHandler(service *services.SubjectReportService) *SubjectReportHandler {
+	return &SubjectReportHandler{service: service}
+}
+
+// GetSubjectAccessReport handles GET /api/audit-logs/subject/{ownerId}
+// Query parameters: limit, offset and format (json or pdf, default json).
+// The requester is identified as for exports (see requestActor).
+func (h *SubjectReportHandler) GetSubjectAccessReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ownerID := strings.TrimPrefix(r.URL.Path, subjectReportPath)
+	if ownerID == "" || strings.Contains(ownerID, "/") {
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	format := strings.ToLower(query.Get("format"))
+	if format != "" && format != "json" && format != "pdf" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid report parameters",
+			fmt.Errorf("invalid format: %q (must be json or pdf)", format))
+		return
+	}
+	req := &v1models.GetSubjectAccessReportRequest{OwnerID: ownerID}
+	req.ActorType, req.ActorID = requestActor(r)
+	for name, target := range map[string]*int{"limit": &req.Limit, "offset": &req.Offset} {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				utils.RespondWithError(w, http.StatusBadRequest, "Invalid report parameters", fmt.Errorf("invalid %s: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	report, err := h.service.GetSubjectAccessReport(r.Context(), req)
+	if err != nil {
+		if services.IsValidationError(err) {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid report parameters", err)
+			return
+		}
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to build access report", err)
+		return
+	}
+
+	if format != "pdf" {
+		utils.RespondWithJSON(w, http.StatusOK, report)
+		return
+	}
+	var pdf bytes.Buffer
+	if err := services.WriteSubjectAccessReportPDF(&pdf, report); err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to render access report", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="access-report.pdf"`)
+	w.Header().Set("X-Total-Count", strconv.FormatInt(report.Total, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(pdf.Bytes()); err != nil {
+		slog.Error("Failed to write access report", "error", err)
+	}
+}
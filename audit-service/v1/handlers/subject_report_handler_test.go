@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	v1testutil "github.com/gov-dx-sandbox/audit-service/v1/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubjectReportHandler_GetSubjectAccessReport(t *testing.T) {
+	repo := v1testutil.NewMockRepository()
+	handler := NewSubjectReportHandler(v1services.NewSubjectReportService(repo))
+
+	traceID := uuid.New()
+	consentCheck := "CONSENT_CHECK"
+	_, err := repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+		TraceID:          &traceID,
+		Timestamp:        time.Now().UTC().Add(-time.Minute),
+		EventType:        &consentCheck,
+		Status:           v1models.StatusSuccess,
+		ActorType:        "SERVICE",
+		ActorID:          "orchestration-engine",
+		TargetType:       "SERVICE",
+		RequestMetadata:  v1models.JSONBRawMessage(`{"applicationId":"app-1","ownerId":"owner-1"}`),
+		ResponseMetadata: v1models.JSONBRawMessage(`{"consentId":"consent-1","status":"approved"}`),
+	})
+	require.NoError(t, err)
+
+	t.Run("JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/subject/owner-1", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetSubjectAccessReport(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var report v1models.SubjectAccessReportResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, "owner-1", report.OwnerID)
+		assert.Equal(t, int64(1), report.Total)
+		require.Len(t, report.Accesses, 1)
+		assert.Equal(t, "app-1", report.Accesses[0].ConsumerAppID)
+		assert.Equal(t, traceID.String(), report.Accesses[0].TraceID)
+	})
+
+	t.Run("PDF", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/subject/owner-1?format=pdf&limit=10", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetSubjectAccessReport(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+		assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")))
+	})
+
+	t.Run("InvalidParameters", func(t *testing.T) {
+		for _, query := range []string{"format=xml", "limit=all", "offset=-1"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/audit-logs/subject/owner-1?"+query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetSubjectAccessReport(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		for _, path := range []string{"/api/audit-logs/subject/", "/api/audit-logs/subject/owner-1/extra"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetSubjectAccessReport(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/audit-logs/subject/owner-1", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetSubjectAccessReport(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/gov-dx-sandbox/audit-service/v1/utils"
+)
+
+// tenantsPath is the route for the cross-tenant view and partition maintenance
+const tenantsPath = "/api/audit-logs/tenants"
+
+// TenantHandler handles HTTP requests for tenant partitions
+type TenantHandler struct {
+	service *services.TenantService
+}
+
+// NewTenantHandler creates a new tenant handler
+func NewTenantHandler(service *services.TenantService) *TenantHandler {
+	return &TenantHandler{service: service}
+}
+
+// HandleTenants handles GET /api/audit-logs/tenants (summaries of every tenant's partition) and
+// POST /api/audit-logs/tenants/maintenance (run partition maintenance now)
+func (h *TenantHandler) HandleTenants(w http.ResponseWriter, r *http.Request) {
+	switch path := strings.Trim(strings.TrimPrefix(r.URL.Path, tenantsPath), "/"); {
+	case path == "" && r.Method == http.MethodGet:
+		h.listTenants(w, r)
+	case path == "maintenance" && r.Method == http.MethodPost:
+		h.runMaintenance(w, r)
+	case path != "" && path != "maintenance":
+		utils.RespondWithError(w, http.StatusNotFound, "Not found", nil)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TenantHandler) listTenants(w http.ResponseWriter, r *http.Request) {
+	var req v1models.ListTenantsRequest
+	for name, dest := range map[string]*int{"limit": &req.Limit, "offset": &req.Offset} {
+		if value := r.URL.Query().Get(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				utils.RespondWithError(w, http.StatusBadRequest, "Invalid "+name+" parameter", err)
+				return
+			}
+			*dest = parsed
+		}
+	}
+
+	tenants, err := h.service.ListTenants(r.Context(), &req)
+	if err != nil {
+		respondWithTenantError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, tenants)
+}
+
+func (h *TenantHandler) runMaintenance(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.RunMaintenance(r.Context())
+	if err != nil {
+		respondWithTenantError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, result)
+}
+
+// respondWithTenantError maps tenant service errors to HTTP status codes
+func respondWithTenantError(w http.ResponseWriter, err error) {
+	switch {
+	case services.IsValidationError(err):
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid tenant request", err)
+	case errors.Is(err, services.ErrPartitionMaintenanceInProgress):
+		utils.RespondWithError(w, http.StatusConflict, "Partition maintenance is already in progress", err)
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to process tenant request", err)
+	}
+}
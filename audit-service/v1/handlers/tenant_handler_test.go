@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1database "github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1services "github.com/gov-dx-sandbox/audit-service/v1/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTenantHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	repo := v1database.NewGormRepository(db)
+	handler := NewTenantHandler(v1services.NewTenantService(repo))
+
+	target := "drp"
+	for _, status := range []string{v1models.StatusSuccess, v1models.StatusFailure} {
+		_, err = repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:  time.Now().UTC(),
+			Status:     status,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+			TargetID:   &target,
+		})
+		require.NoError(t, err)
+	}
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.HandleTenants(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("RunMaintenance", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/audit-logs/tenants/maintenance")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result v1models.PartitionMaintenanceResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, int64(2), result.TenantCount)
+	})
+
+	t.Run("ListTenants", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/audit-logs/tenants?limit=1")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response v1models.ListTenantsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, int64(2), response.Total)
+		require.Len(t, response.Tenants, 1)
+		assert.Equal(t, int64(2), response.Tenants[0].LogCount)
+		assert.Equal(t, int64(1), response.Tenants[0].FailureCount)
+	})
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/audit-logs/tenants?limit=abc").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/audit-logs/tenants?offset=-1").Code)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/audit-logs/tenants/unknown").Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodDelete, "/api/audit-logs/tenants").Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "/api/audit-logs/tenants/maintenance").Code)
+	})
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Alert notification status constants
+const (
+	AlertNotificationPending = "PENDING"
+	AlertNotificationSent    = "SENT"
+	AlertNotificationFailed  = "FAILED"
+	AlertNotificationNone    = "NONE" // The rule has no webhooks or emails
+)
+
+// Alert is an instance of an alert rule firing
+type Alert struct {
+	ID          uuid.UUID `gorm:"primaryKey" json:"id"`
+	RuleName    string    `gorm:"type:varchar(100);not null;index:idx_audit_alerts_rule_name" json:"ruleName"`
+	Severity    string    `gorm:"type:varchar(20);not null;index:idx_audit_alerts_severity" json:"severity"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+
+	// GroupKey is the value of the rule's threshold groupBy dimension (e.g. the consumer application)
+	GroupKey string `gorm:"type:varchar(255);index:idx_audit_alerts_group_key" json:"groupKey,omitempty"`
+
+	// The matching logs that fired the alert (a JSON array of IDs) and the period they span
+	LogCount   int             `gorm:"not null" json:"logCount"`
+	LogIDs     JSONBRawMessage `gorm:"type:jsonb" json:"logIds"`
+	FirstLogAt time.Time       `gorm:"not null" json:"firstLogAt"`
+	LastLogAt  time.Time       `gorm:"not null" json:"lastLogAt"`
+
+	FiredAt time.Time `gorm:"not null;index:idx_audit_alerts_fired_at" json:"firedAt"`
+
+	NotificationStatus string  `gorm:"type:varchar(20);not null" json:"notificationStatus"`
+	NotificationError  *string `gorm:"type:text" json:"notificationError,omitempty"`
+
+	CreatedAt time.Time `gorm:"not null" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// TableName sets the table name for Alert model
+func (Alert) TableName() string {
+	return "audit_alerts"
+}
+
+// BeforeCreate hook to set default values
+func (a *Alert) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return nil
+}
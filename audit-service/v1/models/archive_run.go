@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Archive run status constants
+const (
+	ArchiveRunStatusRunning   = "RUNNING"
+	ArchiveRunStatusCompleted = "COMPLETED"
+	ArchiveRunStatusFailed    = "FAILED"
+)
+
+// Archive run trigger constants
+const (
+	ArchiveRunTriggerScheduled = "SCHEDULED"
+	ArchiveRunTriggerManual    = "MANUAL"
+	ArchiveRunTriggerPartition = "PARTITION"
+)
+
+// ArchiveRun tracks one pass of the retention job, which archives expired audit logs
+// to object storage and then deletes them from the database
+type ArchiveRun struct {
+	ID      uuid.UUID `gorm:"primaryKey" json:"id"`
+	Status  string    `gorm:"type:varchar(20);not null;index:idx_audit_archive_runs_status" json:"status"`
+	Trigger string    `gorm:"type:varchar(20);not null" json:"trigger"`
+
+	// Requester of a manual run; SYSTEM for scheduled runs
+	ActorType string `gorm:"type:varchar(50);not null" json:"actorType"`
+	ActorID   string `gorm:"type:varchar(255);not null" json:"actorId"`
+
+	// Progress, updated after every archived object
+	ArchivedCount int64           `gorm:"not null;default:0" json:"archivedCount"`
+	Objects       JSONBRawMessage `gorm:"type:jsonb" json:"objects,omitempty"`
+	Error         *string         `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt   *time.Time      `json:"completedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"not null" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// TableName sets the table name for ArchiveRun model
+func (ArchiveRun) TableName() string {
+	return "audit_archive_runs"
+}
+
+// BeforeCreate hook to set default values
+func (r *ArchiveRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	r.CreatedAt = now
+	r.UpdatedAt = now
+	return nil
+}
+
+// ArchiveObject describes one gzipped NDJSON object written by an archive run
+type ArchiveObject struct {
+	Key       string `json:"key"`
+	EventType string `json:"eventType,omitempty"`
+	LogCount  int    `json:"logCount"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+}
+
+// AuditChainTombstone keeps the hash chain link of an audit log that was archived and deleted,
+// so the rest of the chain can still be verified without the log's content
+type AuditChainTombstone struct {
+	Sequence     int64     `gorm:"primaryKey;autoIncrement:false" json:"sequence"`
+	LogID        uuid.UUID `gorm:"type:uuid;not null" json:"logId"`
+	PreviousHash string    `gorm:"type:varchar(64);not null" json:"previousHash"`
+	Hash         string    `gorm:"type:varchar(64);not null" json:"hash"`
+	ArchiveRunID uuid.UUID `gorm:"type:uuid;not null" json:"archiveRunId"`
+	ObjectKey    string    `gorm:"type:varchar(1024);not null" json:"objectKey"`
+	ArchivedAt   time.Time `gorm:"not null" json:"archivedAt"`
+}
+
+// TableName sets the table name for AuditChainTombstone model
+func (AuditChainTombstone) TableName() string {
+	return "audit_chain_tombstones"
+}
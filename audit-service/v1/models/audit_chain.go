@@ -0,0 +1,147 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AuditChainHead holds the position and hash of the last log in the hash chain.
+// It is a single row, locked while a log is appended so that concurrent writers cannot fork the chain.
+type AuditChainHead struct {
+	ID           int    `gorm:"primaryKey;autoIncrement:false"`
+	LastSequence int64  `gorm:"not null;default:0"`
+	LastHash     string `gorm:"type:varchar(64);not null;default:''"`
+}
+
+// TableName sets the table name for AuditChainHead model
+func (AuditChainHead) TableName() string {
+	return "audit_chain_head"
+}
+
+// AuditChainHeadID is the primary key of the single chain head row
+const AuditChainHeadID = 1
+
+// AuditCheckpoint is a signed statement of the chain hash at a given sequence.
+// Because it is signed with a key the database does not hold, rewriting the chain up to a
+// checkpoint (or truncating it) is detectable even by someone able to recompute every hash.
+type AuditCheckpoint struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Sequence  int64     `gorm:"not null;uniqueIndex:idx_audit_checkpoints_sequence" json:"sequence"`
+	Hash      string    `gorm:"type:varchar(64);not null" json:"hash"`
+	KeyID     string    `gorm:"type:varchar(64);not null" json:"keyId"`
+	Signature string    `gorm:"type:text;not null" json:"signature"` // base64 Ed25519 signature of SigningPayload
+	CreatedAt time.Time `gorm:"not null" json:"createdAt"`
+}
+
+// TableName sets the table name for AuditCheckpoint model
+func (AuditCheckpoint) TableName() string {
+	return "audit_checkpoints"
+}
+
+// SigningPayload returns the bytes covered by the checkpoint signature
+func (c *AuditCheckpoint) SigningPayload() []byte {
+	return []byte(strconv.FormatInt(c.Sequence, 10) + ":" + c.Hash + ":" + c.CreatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// chainContent is the canonical form of an audit log that is hashed.
+// Field order is fixed by the struct; timestamps are UTC with microsecond precision
+// (what PostgreSQL stores) and metadata is re-encoded with sorted keys, so the hash
+// does not depend on how the database normalizes JSONB.
+type chainContent struct {
+	Sequence           int64           `json:"sequence"`
+	PreviousHash       string          `json:"previousHash"`
+	ID                 string          `json:"id"`
+	Timestamp          string          `json:"timestamp"`
+	TraceID            *string         `json:"traceId"`
+	Status             string          `json:"status"`
+	EventType          *string         `json:"eventType"`
+	EventAction        *string         `json:"eventAction"`
+	ActorType          string          `json:"actorType"`
+	ActorID            string          `json:"actorId"`
+	TargetType         string          `json:"targetType"`
+	TargetID           *string         `json:"targetId"`
+	RequestMetadata    json.RawMessage `json:"requestMetadata"`
+	ResponseMetadata   json.RawMessage `json:"responseMetadata"`
+	AdditionalMetadata json.RawMessage `json:"additionalMetadata"`
+
+	// Fields added after chaining was introduced are omitted when empty,
+	// so the hashes of logs stored before they existed stay valid
+	EventID       *string `json:"eventId,omitempty"`
+	Source        string  `json:"source,omitempty"`
+	EventVersion  *int    `json:"eventVersion,omitempty"`
+	SpanID        *string `json:"spanId,omitempty"`
+	CorrelationID *string `json:"correlationId,omitempty"`
+}
+
+// ComputeChainHash returns the hex SHA-256 hash of the log's content, sequence and PreviousHash
+func (l *AuditLog) ComputeChainHash() (string, error) {
+	if l.Sequence == nil {
+		return "", fmt.Errorf("audit log %s has no chain sequence", l.ID)
+	}
+
+	content := chainContent{
+		Sequence:      *l.Sequence,
+		PreviousHash:  l.PreviousHash,
+		ID:            l.ID.String(),
+		Timestamp:     ChainTimestamp(l.Timestamp).Format(time.RFC3339Nano),
+		Status:        l.Status,
+		EventType:     l.EventType,
+		EventAction:   l.EventAction,
+		ActorType:     l.ActorType,
+		ActorID:       l.ActorID,
+		TargetType:    l.TargetType,
+		TargetID:      l.TargetID,
+		EventID:       l.EventID,
+		Source:        l.Source,
+		EventVersion:  l.EventVersion,
+		SpanID:        l.SpanID,
+		CorrelationID: l.CorrelationID,
+	}
+	if l.TraceID != nil {
+		traceID := l.TraceID.String()
+		content.TraceID = &traceID
+	}
+
+	var err error
+	if content.RequestMetadata, err = canonicalJSON(l.RequestMetadata); err != nil {
+		return "", fmt.Errorf("invalid requestMetadata: %w", err)
+	}
+	if content.ResponseMetadata, err = canonicalJSON(l.ResponseMetadata); err != nil {
+		return "", fmt.Errorf("invalid responseMetadata: %w", err)
+	}
+	if content.AdditionalMetadata, err = canonicalJSON(l.AdditionalMetadata); err != nil {
+		return "", fmt.Errorf("invalid additionalMetadata: %w", err)
+	}
+
+	encoded, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ChainTimestamp normalizes a timestamp to the precision that survives a database round trip
+func ChainTimestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// canonicalJSON re-encodes a JSON document with sorted object keys and no insignificant whitespace.
+// Numbers keep their original text.
+func canonicalJSON(raw JSONBRawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
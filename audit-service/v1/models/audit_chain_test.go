@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditLog_ComputeChainHash(t *testing.T) {
+	sequence := int64(7)
+	base := AuditLog{
+		ID:              uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"),
+		Timestamp:       time.Date(2024, 1, 20, 10, 0, 0, 123456789, time.UTC),
+		Status:          StatusSuccess,
+		ActorType:       "SERVICE",
+		ActorID:         "orchestration-engine",
+		TargetType:      "SERVICE",
+		RequestMetadata: JSONBRawMessage(`{"b":1,"a":[1,2.50]}`),
+		Sequence:        &sequence,
+		PreviousHash:    "abc",
+	}
+
+	hash, err := base.ComputeChainHash()
+	if err != nil {
+		t.Fatalf("ComputeChainHash() error = %v", err)
+	}
+
+	// Representations a database round trip may produce must hash the same
+	roundTripped := base
+	roundTripped.Timestamp = time.Date(2024, 1, 20, 15, 30, 0, 123456000, time.FixedZone("IST", 5*3600+1800))
+	roundTripped.RequestMetadata = JSONBRawMessage(`{"a": [1, 2.50], "b": 1}`)
+	if got, _ := roundTripped.ComputeChainHash(); got != hash {
+		t.Errorf("hash changed after round trip: got %s, want %s", got, hash)
+	}
+
+	// Any change to content or chain position must change the hash
+	changes := map[string]func(l *AuditLog){
+		"actor":         func(l *AuditLog) { l.ActorID = "someone-else" },
+		"metadata":      func(l *AuditLog) { l.RequestMetadata = JSONBRawMessage(`{"b":2,"a":[1,2.50]}`) },
+		"previous hash": func(l *AuditLog) { l.PreviousHash = "abd" },
+		"sequence": func(l *AuditLog) {
+			other := int64(8)
+			l.Sequence = &other
+		},
+	}
+	for name, change := range changes {
+		modified := base
+		change(&modified)
+		if got, _ := modified.ComputeChainHash(); got == hash {
+			t.Errorf("changing %s did not change the hash", name)
+		}
+	}
+
+	unchained := base
+	unchained.Sequence = nil
+	if _, err := unchained.ComputeChainHash(); err == nil {
+		t.Error("expected an error for a log without a sequence")
+	}
+}
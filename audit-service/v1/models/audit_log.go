@@ -85,6 +85,23 @@ const (
 	// Example: {"succeeded": 95, "failed": 5, "total": 100}
 )
 
+// Ingestion source constants, recorded on logs submitted by producers.
+// Events the audit service records about itself (exports, archival) have no source.
+const (
+	IngestionSourceHTTP  = "HTTP"
+	IngestionSourceQueue = "QUEUE"
+)
+
+// maxEventIDLength matches the event_id column size
+const maxEventIDLength = 255
+
+// maxCorrelationIDLength matches the correlation_id column size
+const maxCorrelationIDLength = 255
+
+// ActorTypeApplication is the actor type (configured in enums.yaml) used when a consumer
+// application is the actor, e.g. for DATA_REQUEST events; filtering by consumer app relies on it
+const ActorTypeApplication = "APPLICATION"
+
 // Enum configuration (loaded from YAML config file)
 // Uses config.AuditEnums to leverage O(1) validation lookups
 var (
@@ -111,18 +128,32 @@ type AuditLog struct {
 	// Primary Key
 	ID uuid.UUID `gorm:"primaryKey" json:"id"`
 
+	// EventID is an optional producer-assigned idempotency key; at most one log is stored per EventID,
+	// so producers can safely retry or redeliver an event
+	EventID *string `gorm:"type:varchar(255);uniqueIndex:idx_audit_logs_event_id" json:"eventId,omitempty"`
+
+	// Source records how the log was ingested (HTTP or QUEUE)
+	Source string `gorm:"type:varchar(10);index:idx_audit_logs_source" json:"source,omitempty"`
+
 	// Temporal
 	Timestamp time.Time `gorm:"not null;index:idx_audit_logs_timestamp" json:"timestamp"`
 
 	// Trace & Correlation
 	// Global trace ID for distributed requests. Provided by the client. Nullable for standalone events.
 	TraceID *uuid.UUID `gorm:"index:idx_audit_logs_trace_id" json:"traceId,omitempty"`
+	// SpanID is the OpenTelemetry span the event was recorded in (16 hex characters), from the producer's traceparent
+	SpanID *string `gorm:"type:varchar(16)" json:"spanId,omitempty"`
+	// CorrelationID is a producer-defined request ID, for producers that do not propagate trace IDs
+	CorrelationID *string `gorm:"type:varchar(255);index:idx_audit_logs_correlation_id" json:"correlationId,omitempty"`
 
 	// Event Classification
 	Status      string  `gorm:"type:varchar(20);not null;index:idx_audit_logs_status" json:"status"`
 	EventType   *string `gorm:"type:varchar(50)" json:"eventType,omitempty"`   // e.g., POLICY_CHECK, MANAGEMENT_EVENT (user-defined custom names)
 	EventAction *string `gorm:"type:varchar(50)" json:"eventAction,omitempty"` // e.g., CREATE, READ, UPDATE, DELETE
 
+	// EventVersion is the version of the event type's schema the log was validated against; nil for event types without schemas
+	EventVersion *int `json:"eventVersion,omitempty"`
+
 	// Actor Information (unified approach)
 	ActorType string `gorm:"type:varchar(50);not null" json:"actorType"`
 	ActorID   string `gorm:"type:varchar(255);not null" json:"actorId"` // email, uuid, or service-name
@@ -136,6 +167,20 @@ type AuditLog struct {
 	ResponseMetadata   JSONBRawMessage `gorm:"type:jsonb" json:"responseMetadata,omitempty"`   // Response or Error details
 	AdditionalMetadata JSONBRawMessage `gorm:"type:jsonb" json:"additionalMetadata,omitempty"` // Additional context-specific data
 
+	// Hash chain (tamper evidence). Assigned by the repository when the log is stored:
+	// Sequence is the log's position in the chain, PreviousHash the Hash of the log before it,
+	// and Hash covers this log's content and PreviousHash. Logs stored before chaining was
+	// introduced have no sequence and are not part of the chain.
+	Sequence     *int64 `gorm:"uniqueIndex:idx_audit_logs_sequence" json:"sequence,omitempty"`
+	PreviousHash string `gorm:"type:varchar(64)" json:"previousHash,omitempty"`
+	Hash         string `gorm:"type:varchar(64)" json:"hash,omitempty"`
+
+	// Data subject erasure. Erasing a data owner rewrites the identifiers in their logs but keeps
+	// PreviousHash and Hash, so the chain links and signed checkpoints stay valid; ErasureID records
+	// the erasure and PseudonymizedHash covers the rewritten content in place of Hash.
+	ErasureID         *uuid.UUID `gorm:"type:uuid;index:idx_audit_logs_erasure_id" json:"erasureId,omitempty"`
+	PseudonymizedHash string     `gorm:"type:varchar(64)" json:"pseudonymizedHash,omitempty"`
+
 	// BaseModel provides CreatedAt
 	BaseModel
 }
@@ -172,6 +217,13 @@ func (l *AuditLog) Validate() error {
 		return fmt.Errorf("invalid status: %s (must be %s or %s)", l.Status, StatusSuccess, StatusFailure)
 	}
 
+	if l.EventID != nil && (*l.EventID == "" || len(*l.EventID) > maxEventIDLength) {
+		return fmt.Errorf("eventId must be between 1 and %d characters", maxEventIDLength)
+	}
+	if l.CorrelationID != nil && (*l.CorrelationID == "" || len(*l.CorrelationID) > maxCorrelationIDLength) {
+		return fmt.Errorf("correlationId must be between 1 and %d characters", maxCorrelationIDLength)
+	}
+
 	// Validate actor_id is not empty (required for all actor types)
 	if l.ActorID == "" {
 		return fmt.Errorf("actorId is required")
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuditLogPartition assigns an audit log to the partition of one tenant. A tenant is a provider or
+// consumer entity; a log belongs to the partition of each entity it involves, so an exchange between a
+// consumer and a provider is visible to both. Partitions are written with the log, and indexed by
+// tenant and time so that a tenant's logs are found without scanning the others'.
+type AuditLogPartition struct {
+	TenantID  string    `gorm:"type:varchar(255);primaryKey;index:idx_audit_log_partitions_tenant_time,priority:1"`
+	LogID     uuid.UUID `gorm:"type:uuid;primaryKey;index:idx_audit_log_partitions_log_id"`
+	Timestamp time.Time `gorm:"not null;index:idx_audit_log_partitions_tenant_time,priority:2"`
+}
+
+// TableName sets the table name for AuditLogPartition model
+func (AuditLogPartition) TableName() string {
+	return "audit_log_partitions"
+}
+
+// AuditTenant summarizes the partition of one tenant. Summaries are recomputed by partition
+// maintenance, so they lag behind the logs by up to the maintenance interval.
+type AuditTenant struct {
+	TenantID     string `gorm:"type:varchar(255);primaryKey"`
+	LogCount     int64  `gorm:"not null;default:0"`
+	FailureCount int64  `gorm:"not null;default:0"`
+	FirstEventAt *time.Time
+	LastEventAt  *time.Time
+	RefreshedAt  time.Time `gorm:"not null"`
+}
+
+// TableName sets the table name for AuditTenant model
+func (AuditTenant) TableName() string {
+	return "audit_tenants"
+}
+
+// TenantIDs returns the tenants whose partitions the log belongs to: its actor and target, and the
+// consumer application recorded as applicationId in the request or response metadata
+func (l *AuditLog) TenantIDs() []string {
+	ids := []string{l.ActorID, metadataApplicationID(l.RequestMetadata), metadataApplicationID(l.ResponseMetadata)}
+	if l.TargetID != nil {
+		ids = append(ids, *l.TargetID)
+	}
+	ids = slices.DeleteFunc(ids, func(id string) bool { return id == "" })
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// Partitions returns the partition rows of the log
+func (l *AuditLog) Partitions() []AuditLogPartition {
+	tenantIDs := l.TenantIDs()
+	partitions := make([]AuditLogPartition, len(tenantIDs))
+	for i, tenantID := range tenantIDs {
+		partitions[i] = AuditLogPartition{TenantID: tenantID, LogID: l.ID, Timestamp: l.Timestamp}
+	}
+	return partitions
+}
+
+// AfterCreate hook assigns the log to its tenants' partitions in the transaction that created it
+func (l *AuditLog) AfterCreate(tx *gorm.DB) error {
+	partitions := l.Partitions()
+	if len(partitions) == 0 {
+		return nil
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).Clauses(clause.OnConflict{DoNothing: true}).Create(&partitions).Error; err != nil {
+		return fmt.Errorf("failed to partition audit log: %w", err)
+	}
+	return nil
+}
+
+// metadataApplicationID returns the applicationId recorded in log metadata, if any
+func metadataApplicationID(metadata JSONBRawMessage) string {
+	var fields struct {
+		ApplicationID string `json:"applicationId"`
+	}
+	if len(metadata) == 0 || json.Unmarshal(metadata, &fields) != nil {
+		return ""
+	}
+	return fields.ApplicationID
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog_TenantIDs(t *testing.T) {
+	target := "drp"
+	log := &AuditLog{
+		ID:               uuid.New(),
+		ActorType:        "SERVICE",
+		ActorID:          "orchestration-engine",
+		TargetID:         &target,
+		RequestMetadata:  JSONBRawMessage(`{"applicationId":"passport-app"}`),
+		ResponseMetadata: JSONBRawMessage(`{"applicationId":"passport-app","status":"ok"}`),
+	}
+	assert.Equal(t, []string{"drp", "orchestration-engine", "passport-app"}, log.TenantIDs())
+
+	partitions := log.Partitions()
+	assert.Len(t, partitions, 3)
+	for _, partition := range partitions {
+		assert.Equal(t, log.ID, partition.LogID)
+	}
+
+	// Malformed or non-string applicationIds are ignored
+	log.TargetID = nil
+	log.RequestMetadata = JSONBRawMessage(`{"applicationId":42}`)
+	log.ResponseMetadata = JSONBRawMessage(`not json`)
+	assert.Equal(t, []string{"orchestration-engine"}, log.TenantIDs())
+}
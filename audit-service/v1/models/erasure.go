@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Erasure status constants
+const (
+	ErasureStatusRunning   = "RUNNING"
+	ErasureStatusCompleted = "COMPLETED"
+	ErasureStatusFailed    = "FAILED"
+)
+
+// Erasure records a data subject erasure request, which pseudonymized the audit logs of a data owner.
+// Erasures are kept as compliance evidence; they never hold the owner's identifiers, only the token
+// the owner ID was replaced with.
+type Erasure struct {
+	ID     uuid.UUID `gorm:"primaryKey" json:"id"`
+	Status string    `gorm:"type:varchar(20);not null;index:idx_audit_erasures_status" json:"status"`
+
+	// Reference is an optional caller-supplied reference, such as a ticket number for the request
+	Reference *string `gorm:"type:varchar(255)" json:"reference,omitempty"`
+
+	// SubjectToken is the token the owner ID was replaced with
+	SubjectToken string `gorm:"type:varchar(80);not null" json:"subjectToken"`
+
+	// Requester of the erasure
+	ActorType string `gorm:"type:varchar(50);not null" json:"actorType"`
+	ActorID   string `gorm:"type:varchar(255);not null" json:"actorId"`
+
+	// Result. Digest is the SHA-256 of the pseudonymized logs' IDs and hashes, so the set of logs an
+	// erasure changed can be re-checked later.
+	PseudonymizedCount int64      `gorm:"not null;default:0" json:"pseudonymizedCount"`
+	Digest             string     `gorm:"type:varchar(64)" json:"digest,omitempty"`
+	Error              *string    `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt        *time.Time `json:"completedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"not null" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// TableName sets the table name for Erasure model
+func (Erasure) TableName() string {
+	return "audit_erasures"
+}
+
+// BeforeCreate hook to set default values
+func (e *Erasure) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	return nil
+}
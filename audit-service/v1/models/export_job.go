@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export job status constants
+const (
+	ExportJobStatusPending   = "PENDING"
+	ExportJobStatusRunning   = "RUNNING"
+	ExportJobStatusCompleted = "COMPLETED"
+	ExportJobStatusFailed    = "FAILED"
+)
+
+// ExportJob tracks an asynchronous export of audit logs to a file
+// Unlike audit logs, jobs are updated as they progress, so they carry their own timestamps
+type ExportJob struct {
+	ID     uuid.UUID `gorm:"primaryKey" json:"id"`
+	Status string    `gorm:"type:varchar(20);not null;index:idx_audit_export_jobs_status" json:"status"`
+	Format string    `gorm:"type:varchar(10);not null" json:"format"`
+
+	// Query holds the GetAuditLogsRequest the export was started with
+	Query JSONBRawMessage `gorm:"type:jsonb" json:"query,omitempty"`
+
+	// Requester of the export
+	ActorType string `gorm:"type:varchar(50);not null" json:"actorType"`
+	ActorID   string `gorm:"type:varchar(255);not null" json:"actorId"`
+
+	// Result
+	FilePath    string     `gorm:"type:varchar(1024)" json:"-"`
+	RowCount    int64      `gorm:"not null;default:0" json:"rowCount"`
+	FileSize    int64      `gorm:"not null;default:0" json:"fileSize"`
+	Error       *string    `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"not null" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// TableName sets the table name for ExportJob model
+func (ExportJob) TableName() string {
+	return "audit_export_jobs"
+}
+
+// BeforeCreate hook to set default values
+func (j *ExportJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	j.CreatedAt = now
+	j.UpdatedAt = now
+	return nil
+}
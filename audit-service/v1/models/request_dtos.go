@@ -3,8 +3,21 @@ package models
 // CreateAuditLogRequest represents the request payload for creating a generalized audit log
 // This matches the final SQL schema with unified actor/target approach
 type CreateAuditLogRequest struct {
+	// Idempotency key assigned by the producer; resubmitting an event with the same ID is a no-op
+	EventID *string `json:"eventId,omitempty"`
+
+	// Source is how the request was received (HTTP or QUEUE); set by the service, not the producer
+	Source string `json:"-"`
+
 	// Trace & Correlation
-	TraceID *string `json:"traceId,omitempty"` // UUID string, nullable for standalone events
+	TraceID *string `json:"traceId,omitempty"` // UUID or 32-hex OpenTelemetry trace ID, nullable for standalone events
+
+	// Traceparent is the W3C trace context of the operation (version-traceid-spanid-flags); its trace ID
+	// is used when traceId is not given, and its span ID links the log to the span
+	Traceparent *string `json:"traceparent,omitempty"`
+
+	// CorrelationID is a producer-defined request or correlation ID, for callers that do not use trace IDs
+	CorrelationID *string `json:"correlationId,omitempty"`
 
 	// Temporal
 	Timestamp string `json:"timestamp" validate:"required"` // ISO 8601 format, required
@@ -14,6 +27,9 @@ type CreateAuditLogRequest struct {
 	EventAction *string `json:"eventAction,omitempty"`      // CREATE, READ, UPDATE, DELETE
 	Status      string  `json:"status" validate:"required"` // SUCCESS, FAILURE
 
+	// Version of the event type's schema the payload follows; defaults to 1 for event types with schemas
+	EventVersion *int `json:"eventVersion,omitempty"`
+
 	// Actor Information (unified approach)
 	ActorType string `json:"actorType" validate:"required"` // SERVICE, ADMIN, MEMBER, SYSTEM
 	ActorID   string `json:"actorId" validate:"required"`   // email, uuid, or service-name (required)
@@ -29,3 +45,95 @@ type CreateAuditLogRequest struct {
 	ResponseMetadata   JSONBRawMessage `json:"responseMetadata,omitempty"`   // Response or Error details
 	AdditionalMetadata JSONBRawMessage `json:"additionalMetadata,omitempty"` // Additional context-specific data
 }
+
+// GetAuditLogsRequest represents the query parameters for listing audit logs
+// Values are passed through as received; the service layer parses and validates them.
+// The JSON form is stored with asynchronous export jobs.
+type GetAuditLogsRequest struct {
+	TraceID       string `json:"traceId,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+	EventType     string `json:"eventType,omitempty"`
+	EventAction   string `json:"eventAction,omitempty"`
+	Status        string `json:"status,omitempty"`
+	ActorType     string `json:"actorType,omitempty"`
+	ActorID       string `json:"actorId,omitempty"`
+	ConsumerAppID string `json:"consumerAppId,omitempty"`
+	TargetType    string `json:"targetType,omitempty"`
+	TargetID      string `json:"targetId,omitempty"`
+
+	// EntityIDs restricts the logs to the partitions of these tenants; set from the caller's token and the
+	// tenantId parameter, never directly from the query
+	EntityIDs []string `json:"entityIds,omitempty"`
+
+	StartTime string `json:"startTime,omitempty"` // RFC3339, inclusive
+	EndTime   string `json:"endTime,omitempty"`   // RFC3339, exclusive
+	Search    string `json:"q,omitempty"`         // Case-insensitive substring of actor, target, event type or event action
+	SortOrder string `json:"sortOrder,omitempty"` // asc or desc (default desc, newest first)
+
+	Cursor string `json:"cursor,omitempty"` // Opaque cursor from a previous response's nextCursor
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+// GetStatsRequest represents the query parameters for aggregate audit log statistics
+// Values are passed through as received; the service layer parses and validates them.
+type GetStatsRequest struct {
+	StartTime string // RFC3339, inclusive
+	EndTime   string // RFC3339, exclusive
+	Bucket    string // hour, day or week
+	GroupBy   string // eventType, status, actorType, actorId, targetId or consumer
+	Limit     int    // Entries in the top-N lists
+
+	// Optional scope
+	EventType     string
+	ConsumerAppID string
+}
+
+// GetSubjectAccessReportRequest represents a request for a data owner's access report
+type GetSubjectAccessReportRequest struct {
+	OwnerID string // ownerId or ownerEmail recorded in consent checks
+	Limit   int
+	Offset  int
+
+	// Requester, recorded in the audit event for the report
+	ActorType string
+	ActorID   string
+}
+
+// CreateErasureRequest represents a request to erase a data owner from the audit logs
+type CreateErasureRequest struct {
+	OwnerID   string  `json:"ownerId"`             // ownerId or ownerEmail recorded in the data owner's logs
+	Reference *string `json:"reference,omitempty"` // e.g. the ticket number of the erasure request
+
+	// Requester, recorded on the erasure and in its audit event
+	ActorType string `json:"-"`
+	ActorID   string `json:"-"`
+}
+
+// ListTenantsRequest represents the query parameters for listing tenant summaries
+type ListTenantsRequest struct {
+	Limit  int
+	Offset int
+}
+
+// ListAlertsRequest represents the query parameters for listing fired alerts
+// Values are passed through as received; the service layer parses and validates them.
+type ListAlertsRequest struct {
+	RuleName  string
+	Severity  string
+	GroupKey  string
+	StartTime string // RFC3339, inclusive
+	EndTime   string // RFC3339, exclusive
+	Limit     int
+	Offset    int
+}
+
+// ExportAuditLogsRequest represents a request to export audit logs
+type ExportAuditLogsRequest struct {
+	Query  GetAuditLogsRequest
+	Format string // csv or ndjson
+
+	// Requester, recorded in the audit event for the export
+	ActorType string
+	ActorID   string
+}
@@ -10,12 +10,18 @@ import (
 // AuditLogResponse represents the response payload for an audit log entry
 type AuditLogResponse struct {
 	ID        uuid.UUID  `json:"id"`
+	EventID   *string    `json:"eventId,omitempty"`
+	Source    string     `json:"source,omitempty"`
 	Timestamp time.Time  `json:"timestamp"`
 	TraceID   *uuid.UUID `json:"traceId,omitempty"`
 
-	EventType   *string `json:"eventType,omitempty"`
-	EventAction *string `json:"eventAction,omitempty"`
-	Status      string  `json:"status"`
+	SpanID        *string `json:"spanId,omitempty"`
+	CorrelationID *string `json:"correlationId,omitempty"`
+
+	EventType    *string `json:"eventType,omitempty"`
+	EventAction  *string `json:"eventAction,omitempty"`
+	EventVersion *int    `json:"eventVersion,omitempty"`
+	Status       string  `json:"status"`
 
 	ActorType string `json:"actorType"`
 	ActorID   string `json:"actorId"`
@@ -27,6 +33,15 @@ type AuditLogResponse struct {
 	ResponseMetadata   json.RawMessage `json:"responseMetadata,omitempty"`
 	AdditionalMetadata json.RawMessage `json:"additionalMetadata,omitempty"`
 
+	// Hash chain position and hashes; omitted for logs stored before chaining was introduced
+	Sequence     *int64 `json:"sequence,omitempty"`
+	PreviousHash string `json:"previousHash,omitempty"`
+	Hash         string `json:"hash,omitempty"`
+
+	// Set on logs pseudonymized by a data subject erasure
+	ErasureID         *uuid.UUID `json:"erasureId,omitempty"`
+	PseudonymizedHash string     `json:"pseudonymizedHash,omitempty"`
+
 	CreatedAt time.Time `json:"createdAt"`
 }
 
@@ -36,6 +51,56 @@ type GetAuditLogsResponse struct {
 	Total  int64              `json:"total"`
 	Limit  int                `json:"limit"`
 	Offset int                `json:"offset"`
+
+	// NextCursor fetches the following page; it is omitted once a page comes back short
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// TraceResponse represents every audit log of one exchange request, oldest first
+type TraceResponse struct {
+	// Exactly one of TraceID and CorrelationID is set, depending on what the trace was looked up by
+	TraceID       string `json:"traceId,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	DurationMs   int64     `json:"durationMs"`
+	EventCount   int       `json:"eventCount"`
+	FailureCount int       `json:"failureCount"`
+
+	// Participants are the applications and services that acted or were called, in order of first appearance
+	Participants []string           `json:"participants"`
+	Events       []AuditLogResponse `json:"events"`
+
+	// Truncated is set when the trace has more events than were returned
+	Truncated bool `json:"truncated"`
+}
+
+// ResourceTimelineResponse represents the management events of one resource, oldest first
+type ResourceTimelineResponse struct {
+	// ResourceType is the normalized resource type, e.g. APPLICATION for "applications"
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceId"`
+
+	EventCount int                     `json:"eventCount"`
+	Events     []ResourceTimelineEvent `json:"events"`
+
+	// Truncated is set when the resource has more events than were returned
+	Truncated bool `json:"truncated"`
+}
+
+// ResourceTimelineEvent is a management event in a resource's timeline
+type ResourceTimelineEvent struct {
+	AuditLogResponse
+
+	// Relation is how the event concerns the resource: recorded on it (self), on a bulk operation that
+	// included it (bulk) or on another resource naming it as related (related)
+	Relation string `json:"relation"`
+	// Resource and ResourceID are the resource the event was recorded on
+	Resource   string `json:"resource"`
+	ResourceID string `json:"resourceId,omitempty"`
+	// Operation is the operation performed on that resource, e.g. "submit" or "credentials/cred_1/rotate"
+	Operation string `json:"operation,omitempty"`
 }
 
 // ToAuditLogResponse converts an AuditLog model to an AuditLogResponse
@@ -43,10 +108,15 @@ type GetAuditLogsResponse struct {
 func ToAuditLogResponse(log AuditLog) AuditLogResponse {
 	return AuditLogResponse{
 		ID:                 log.ID,
+		EventID:            log.EventID,
+		Source:             log.Source,
 		Timestamp:          log.Timestamp,
 		TraceID:            log.TraceID,
+		SpanID:             log.SpanID,
+		CorrelationID:      log.CorrelationID,
 		EventType:          log.EventType,
 		EventAction:        log.EventAction,
+		EventVersion:       log.EventVersion,
 		Status:             log.Status,
 		ActorType:          log.ActorType,
 		ActorID:            log.ActorID,
@@ -55,13 +125,425 @@ func ToAuditLogResponse(log AuditLog) AuditLogResponse {
 		RequestMetadata:    json.RawMessage(log.RequestMetadata),
 		ResponseMetadata:   json.RawMessage(log.ResponseMetadata),
 		AdditionalMetadata: json.RawMessage(log.AdditionalMetadata),
+		Sequence:           log.Sequence,
+		PreviousHash:       log.PreviousHash,
+		Hash:               log.Hash,
+		ErasureID:          log.ErasureID,
+		PseudonymizedHash:  log.PseudonymizedHash,
 		CreatedAt:          log.CreatedAt,
 	}
 }
 
+// ExportJobResponse represents the response payload for an asynchronous export job
+type ExportJobResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"`
+	Format      string     `json:"format"`
+	RowCount    int64      `json:"rowCount"`
+	FileSize    int64      `json:"fileSize"`
+	Error       *string    `json:"error,omitempty"`
+	DownloadURL *string    `json:"downloadUrl,omitempty"` // Set once the job has completed
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// ToExportJobResponse converts an ExportJob model to an ExportJobResponse
+func ToExportJobResponse(job ExportJob) ExportJobResponse {
+	response := ExportJobResponse{
+		ID:          job.ID,
+		Status:      job.Status,
+		Format:      job.Format,
+		RowCount:    job.RowCount,
+		FileSize:    job.FileSize,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+	if job.Status == ExportJobStatusCompleted {
+		downloadURL := "/api/audit-logs/exports/" + job.ID.String() + "/download"
+		response.DownloadURL = &downloadURL
+	}
+	return response
+}
+
+// ArchiveRunResponse represents the response payload for an archive run
+type ArchiveRunResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	Status        string          `json:"status"`
+	Trigger       string          `json:"trigger"`
+	ActorType     string          `json:"actorType"`
+	ActorID       string          `json:"actorId"`
+	ArchivedCount int64           `json:"archivedCount"`
+	Objects       []ArchiveObject `json:"objects"`
+	Error         *string         `json:"error,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+	CompletedAt   *time.Time      `json:"completedAt,omitempty"`
+}
+
+// ListArchiveRunsResponse represents the response for listing recent archive runs
+type ListArchiveRunsResponse struct {
+	Runs []ArchiveRunResponse `json:"runs"`
+}
+
+// ToArchiveRunResponse converts an ArchiveRun model to an ArchiveRunResponse
+func ToArchiveRunResponse(run ArchiveRun) ArchiveRunResponse {
+	objects := []ArchiveObject{}
+	if len(run.Objects) > 0 {
+		// Objects are only ever written by the archive service, so a decoding failure leaves the list empty
+		_ = json.Unmarshal(run.Objects, &objects)
+	}
+	return ArchiveRunResponse{
+		ID:            run.ID,
+		Status:        run.Status,
+		Trigger:       run.Trigger,
+		ActorType:     run.ActorType,
+		ActorID:       run.ActorID,
+		ArchivedCount: run.ArchivedCount,
+		Objects:       objects,
+		Error:         run.Error,
+		CreatedAt:     run.CreatedAt,
+		UpdatedAt:     run.UpdatedAt,
+		CompletedAt:   run.CompletedAt,
+	}
+}
+
+// AlertResponse represents the response payload for a fired alert
+type AlertResponse struct {
+	ID                 uuid.UUID   `json:"id"`
+	RuleName           string      `json:"ruleName"`
+	Severity           string      `json:"severity"`
+	Description        string      `json:"description,omitempty"`
+	GroupKey           string      `json:"groupKey,omitempty"`
+	LogCount           int         `json:"logCount"`
+	LogIDs             []uuid.UUID `json:"logIds"`
+	FirstLogAt         time.Time   `json:"firstLogAt"`
+	LastLogAt          time.Time   `json:"lastLogAt"`
+	FiredAt            time.Time   `json:"firedAt"`
+	NotificationStatus string      `json:"notificationStatus"`
+	NotificationError  *string     `json:"notificationError,omitempty"`
+}
+
+// ToAlertResponse converts an Alert model to an AlertResponse
+func ToAlertResponse(alert Alert) AlertResponse {
+	logIDs := []uuid.UUID{}
+	if len(alert.LogIDs) > 0 {
+		// Log IDs are only ever written by the alert service, so a decoding failure leaves the list empty
+		_ = json.Unmarshal(alert.LogIDs, &logIDs)
+	}
+	return AlertResponse{
+		ID:                 alert.ID,
+		RuleName:           alert.RuleName,
+		Severity:           alert.Severity,
+		Description:        alert.Description,
+		GroupKey:           alert.GroupKey,
+		LogCount:           alert.LogCount,
+		LogIDs:             logIDs,
+		FirstLogAt:         alert.FirstLogAt,
+		LastLogAt:          alert.LastLogAt,
+		FiredAt:            alert.FiredAt,
+		NotificationStatus: alert.NotificationStatus,
+		NotificationError:  alert.NotificationError,
+	}
+}
+
+// ListAlertsResponse represents the response for listing fired alerts
+type ListAlertsResponse struct {
+	Alerts []AlertResponse `json:"alerts"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+// ChainVerificationResponse represents the result of verifying the audit log hash chain over a sequence range
+type ChainVerificationResponse struct {
+	Valid        bool  `json:"valid"`
+	FromSequence int64 `json:"fromSequence"`
+	ToSequence   int64 `json:"toSequence"`
+	CheckedCount int64 `json:"checkedCount"`
+
+	// ArchivedCount counts logs in the range that were archived and deleted;
+	// only their chain links, kept as tombstones, could be checked
+	ArchivedCount int64 `json:"archivedCount"`
+
+	// PseudonymizedCount counts logs in the range rewritten by a data subject erasure;
+	// their content was checked against the hash recorded at erasure
+	PseudonymizedCount int64 `json:"pseudonymizedCount"`
+
+	// CheckpointsVerified counts checkpoints in the range whose signature and hash matched;
+	// CheckpointsSkipped counts those signed with a key other than the configured one
+	CheckpointsVerified int `json:"checkpointsVerified"`
+	CheckpointsSkipped  int `json:"checkpointsSkipped"`
+
+	// Failure describes the first integrity violation found, if any
+	Failure *ChainVerificationFailure `json:"failure,omitempty"`
+}
+
+// ChainVerificationFailure describes where and why chain verification failed
+type ChainVerificationFailure struct {
+	Sequence int64      `json:"sequence"`
+	LogID    *uuid.UUID `json:"logId,omitempty"`
+	Reason   string     `json:"reason"`
+}
+
 // ErrorResponse represents a structured error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    string `json:"code,omitempty"`
-	Details any    `json:"details,omitempty"`
+	Error     string `json:"error"`
+	Code      string `json:"code,omitempty"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// IngestionReconciliationResponse compares the logs received over HTTP with those received from the queue
+type IngestionReconciliationResponse struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+
+	Totals  IngestionCounts        `json:"totals"`
+	ByActor []ActorIngestionCounts `json:"byActor"`
+
+	// Consumer holds the queue consumer's counters since the service started; omitted if no queue is configured
+	Consumer *QueueConsumerStats `json:"consumer,omitempty"`
+}
+
+// IngestionCounts counts stored logs per ingestion source.
+// Other counts logs without a source: events recorded by the audit service itself and logs stored before sources were tracked.
+type IngestionCounts struct {
+	HTTP  int64 `json:"http"`
+	Queue int64 `json:"queue"`
+	Other int64 `json:"other"`
+}
+
+// ActorIngestionCounts counts the logs of one actor per ingestion source
+type ActorIngestionCounts struct {
+	ActorID string `json:"actorId"`
+	IngestionCounts
+}
+
+// QueueConsumerStats describes the queue consumer's activity since the service started
+type QueueConsumerStats struct {
+	Stream        string     `json:"stream"`
+	Consumer      string     `json:"consumer"`
+	Connected     bool       `json:"connected"`
+	Received      int64      `json:"received"`
+	Ingested      int64      `json:"ingested"`
+	Duplicates    int64      `json:"duplicates"`
+	Rejected      int64      `json:"rejected"`
+	Failed        int64      `json:"failed"`
+	LastMessageAt *time.Time `json:"lastMessageAt,omitempty"`
+}
+
+// SIEMExportStatus describes the SIEM exporter's delivery since the service started
+type SIEMExportStatus struct {
+	Enabled   bool   `json:"enabled"`
+	Address   string `json:"address,omitempty"`
+	Transport string `json:"transport,omitempty"`
+	Format    string `json:"format,omitempty"`
+	Connected bool   `json:"connected"`
+
+	// Queued logs wait to be delivered; at most BufferSize are kept, and logs arriving while the buffer is full are dropped
+	Queued     int `json:"queued"`
+	BufferSize int `json:"bufferSize,omitempty"`
+
+	Exported     int64      `json:"exported"`
+	Dropped      int64      `json:"dropped"`
+	Failed       int64      `json:"failed"`
+	LastExportAt *time.Time `json:"lastExportAt,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	LastErrorAt  *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+// StatsResponse holds aggregate audit log statistics for a time window
+type StatsResponse struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Bucket    string    `json:"bucket"`
+	GroupBy   string    `json:"groupBy"`
+
+	Totals StatsCounts `json:"totals"`
+
+	// Timeline has one entry per bucket in the window, including empty ones
+	Timeline []StatsBucket `json:"timeline"`
+
+	// Groups breaks the totals down by the groupBy dimension, most events first
+	Groups []StatsGroup `json:"groups"`
+
+	// TopConsumers are the consumer applications with the most events, most events first
+	TopConsumers []StatsGroup `json:"topConsumers"`
+
+	// TopFields are the fields most often requested in authorized policy checks, most requests first
+	TopFields []FieldAccessCount `json:"topFields"`
+}
+
+// StatsCounts counts events, failed events and consent checks denied by the data owner
+type StatsCounts struct {
+	Events        int64   `json:"events"`
+	Failures      int64   `json:"failures"`
+	FailureRate   float64 `json:"failureRate"`
+	ConsentDenied int64   `json:"consentDenied"`
+}
+
+// StatsBucket holds the counts of one time bucket
+type StatsBucket struct {
+	Start time.Time `json:"start"`
+	StatsCounts
+
+	// Groups counts the bucket's events per groupBy value
+	Groups map[string]int64 `json:"groups"`
+}
+
+// StatsGroup holds the counts of one value of a grouping dimension
+type StatsGroup struct {
+	Key string `json:"key"`
+	StatsCounts
+}
+
+// FieldAccessCount is the number of times a field was requested
+type FieldAccessCount struct {
+	Field string `json:"field"`
+	Count int64  `json:"count"`
+}
+
+// SubjectAccessReportResponse lists the data requests concerning one data owner, newest first
+type SubjectAccessReportResponse struct {
+	OwnerID     string                `json:"ownerId"`
+	GeneratedAt time.Time             `json:"generatedAt"`
+	Accesses    []SubjectAccessRecord `json:"accesses"`
+	Total       int64                 `json:"total"`
+	Limit       int                   `json:"limit"`
+	Offset      int                   `json:"offset"`
+}
+
+// SubjectAccessRecord describes one consumer request for a data owner's data, joined from the
+// consent check and the provider fetches of its trace
+type SubjectAccessRecord struct {
+	TraceID       string    `json:"traceId,omitempty"`
+	RequestedAt   time.Time `json:"requestedAt"`
+	ConsumerAppID string    `json:"consumerAppId"`
+	ConsentID     string    `json:"consentId,omitempty"`
+	ConsentStatus string    `json:"consentStatus,omitempty"`
+
+	// DataShared is set when at least one provider returned data to the consumer
+	DataShared bool `json:"dataShared"`
+	// Fields are the fields fetched successfully from providers for the consumer
+	Fields    []string                `json:"fields"`
+	Providers []SubjectProviderAccess `json:"providers"`
+}
+
+// SubjectProviderAccess is a fetch of a data owner's fields from one provider
+type SubjectProviderAccess struct {
+	ServiceKey string    `json:"serviceKey"`
+	AccessedAt time.Time `json:"accessedAt"`
+	Status     string    `json:"status"`
+	Fields     []string  `json:"fields"`
+}
+
+// EventTypeSummary describes an event type that has a schema, for producers discovering the expected payloads
+type EventTypeSummary struct {
+	EventType     string `json:"eventType"`
+	Description   string `json:"description,omitempty"` // Of the latest version
+	Versions      []int  `json:"versions"`
+	LatestVersion int    `json:"latestVersion"`
+}
+
+// ListEventTypesResponse represents the response payload for the event type registry
+type ListEventTypesResponse struct {
+	EventTypes []EventTypeSummary `json:"eventTypes"`
+}
+
+// EventSchemaVersion is one version of an event type's schema
+type EventSchemaVersion struct {
+	Version     int             `json:"version"`
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema"` // JSON Schema document
+}
+
+// EventTypeResponse represents the response payload for a single event type's schemas
+type EventTypeResponse struct {
+	EventType     string               `json:"eventType"`
+	LatestVersion int                  `json:"latestVersion"`
+	Versions      []EventSchemaVersion `json:"versions"`
+}
+
+// ErasureResponse represents the response payload for a data subject erasure
+type ErasureResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	Status             string     `json:"status"`
+	Reference          *string    `json:"reference,omitempty"`
+	SubjectToken       string     `json:"subjectToken"`
+	ActorType          string     `json:"actorType"`
+	ActorID            string     `json:"actorId"`
+	PseudonymizedCount int64      `json:"pseudonymizedCount"`
+	Digest             string     `json:"digest,omitempty"`
+	Error              *string    `json:"error,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+	CompletedAt        *time.Time `json:"completedAt,omitempty"`
+}
+
+// ListErasuresResponse represents the response for listing recent data subject erasures
+type ListErasuresResponse struct {
+	Erasures []ErasureResponse `json:"erasures"`
+}
+
+// ToErasureResponse converts an Erasure model to an ErasureResponse
+func ToErasureResponse(erasure Erasure) ErasureResponse {
+	return ErasureResponse{
+		ID:                 erasure.ID,
+		Status:             erasure.Status,
+		Reference:          erasure.Reference,
+		SubjectToken:       erasure.SubjectToken,
+		ActorType:          erasure.ActorType,
+		ActorID:            erasure.ActorID,
+		PseudonymizedCount: erasure.PseudonymizedCount,
+		Digest:             erasure.Digest,
+		Error:              erasure.Error,
+		CreatedAt:          erasure.CreatedAt,
+		UpdatedAt:          erasure.UpdatedAt,
+		CompletedAt:        erasure.CompletedAt,
+	}
+}
+
+// TenantResponse represents the summary of one tenant's partition in the cross-tenant view
+type TenantResponse struct {
+	TenantID     string     `json:"tenantId"`
+	LogCount     int64      `json:"logCount"`
+	FailureCount int64      `json:"failureCount"`
+	FirstEventAt *time.Time `json:"firstEventAt,omitempty"`
+	LastEventAt  *time.Time `json:"lastEventAt,omitempty"`
+	RefreshedAt  time.Time  `json:"refreshedAt"`
+}
+
+// ListTenantsResponse represents a page of tenant summaries
+type ListTenantsResponse struct {
+	Tenants []TenantResponse `json:"tenants"`
+	Total   int64            `json:"total"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+}
+
+// ToTenantResponse converts an AuditTenant model to a TenantResponse
+func ToTenantResponse(tenant AuditTenant) TenantResponse {
+	return TenantResponse{
+		TenantID:     tenant.TenantID,
+		LogCount:     tenant.LogCount,
+		FailureCount: tenant.FailureCount,
+		FirstEventAt: tenant.FirstEventAt,
+		LastEventAt:  tenant.LastEventAt,
+		RefreshedAt:  tenant.RefreshedAt,
+	}
+}
+
+// PartitionMaintenanceResponse represents the outcome of a partition maintenance pass
+type PartitionMaintenanceResponse struct {
+	// BackfilledCount counts logs that had no partitions, such as logs stored before partitioning
+	BackfilledCount int64 `json:"backfilledCount"`
+	// PrunedCount counts partitions removed because their log no longer exists
+	PrunedCount int64 `json:"prunedCount"`
+	// TenantCount is the number of tenants whose summaries were refreshed
+	TenantCount int64     `json:"tenantCount"`
+	StartedAt   time.Time `json:"startedAt"`
+	CompletedAt time.Time `json:"completedAt"`
 }
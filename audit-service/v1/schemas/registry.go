@@ -0,0 +1,122 @@
+// Package schemas holds the versioned JSON Schemas that audit events are validated against on ingestion
+package schemas
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// definitions holds one schema file per event type version, named <EVENT_TYPE>.v<N>.json
+//
+//go:embed definitions/*.json
+var definitions embed.FS
+
+// definitionName matches schema file names and captures the event type and version
+var definitionName = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)\.v([1-9][0-9]*)\.json$`)
+
+// EventSchema is one version of the payload expected for an event type
+type EventSchema struct {
+	EventType   string
+	Version     int
+	Description string
+	// Document is the JSON Schema as published
+	Document json.RawMessage
+
+	schema *Schema
+}
+
+// Validate checks an event, in the JSON form it was submitted in, against the schema
+func (s *EventSchema) Validate(event any) []FieldError {
+	return s.schema.Validate(event)
+}
+
+// Registry holds the schemas of every event type, by version
+type Registry struct {
+	eventTypes map[string][]*EventSchema // Ordered by version
+}
+
+// DefaultRegistry returns the registry of the schemas built into the service
+func DefaultRegistry() (*Registry, error) {
+	return LoadRegistry(definitions, "definitions")
+}
+
+// LoadRegistry reads the schema files in dir. Versions of an event type must be numbered from 1 without gaps.
+func LoadRegistry(fsys fs.FS, dir string) (*Registry, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event schemas: %w", err)
+	}
+
+	registry := &Registry{eventTypes: make(map[string][]*EventSchema)}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		match := definitionName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid event schema file name %q, expected <EVENT_TYPE>.v<N>.json", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[2])
+
+		document, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event schema %s: %w", entry.Name(), err)
+		}
+		schema, err := Compile(document)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event schema %s: %w", entry.Name(), err)
+		}
+		var annotations struct {
+			Description string `json:"description"`
+		}
+		_ = json.Unmarshal(document, &annotations) // Already parsed by Compile
+
+		registry.eventTypes[match[1]] = append(registry.eventTypes[match[1]], &EventSchema{
+			EventType:   match[1],
+			Version:     version,
+			Description: annotations.Description,
+			Document:    json.RawMessage(document),
+			schema:      schema,
+		})
+	}
+
+	for eventType, versions := range registry.eventTypes {
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+		for i, schema := range versions {
+			if schema.Version != i+1 {
+				return nil, fmt.Errorf("event type %s is missing schema version %d", eventType, i+1)
+			}
+		}
+	}
+	return registry, nil
+}
+
+// EventTypes returns the event types that have schemas, sorted by name
+func (r *Registry) EventTypes() []string {
+	eventTypes := make([]string, 0, len(r.eventTypes))
+	for eventType := range r.eventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+// Versions returns the schemas of an event type, oldest first, or nil if it has none
+func (r *Registry) Versions(eventType string) []*EventSchema {
+	return r.eventTypes[eventType]
+}
+
+// Lookup returns the given version of an event type's schema
+func (r *Registry) Lookup(eventType string, version int) (*EventSchema, bool) {
+	versions := r.eventTypes[eventType]
+	if version < 1 || version > len(versions) {
+		return nil, false
+	}
+	return versions[version-1], true
+}
@@ -0,0 +1,66 @@
+package schemas
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	registry, err := DefaultRegistry()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"CONSENT_CHECK", "DATA_REQUEST", "MANAGEMENT_EVENT", "POLICY_CHECK", "PROVIDER_FETCH"}, registry.EventTypes())
+
+	schema, ok := registry.Lookup("POLICY_CHECK", 1)
+	require.True(t, ok)
+	assert.NotEmpty(t, schema.Description)
+
+	// The shape the orchestration engine records
+	assert.Empty(t, schema.Validate(decode(t, `{
+		"traceId": "5f0c3a5e-9d8a-4f5e-8d2a-3c1e0b7a6f41",
+		"targetType": "SERVICE",
+		"targetId": "policy-decision-point",
+		"requestMetadata": {"applicationId": "app-1", "requiredFields": [{"fieldName": "person.name", "schemaId": "s-1"}]},
+		"responseMetadata": {"authorized": false, "unauthorizedFields": [{"fieldName": "person.name", "schemaId": "s-1"}], "expiredFields": null}
+	}`)))
+	assert.Equal(t, []FieldError{
+		{Field: "requestMetadata.requiredFields[0]", Message: "must be of type object"},
+		{Field: "traceId", Message: "is required"},
+	}, schema.Validate(decode(t, `{
+		"targetId": "policy-decision-point",
+		"requestMetadata": {"applicationId": "app-1", "requiredFields": ["person.name"]}
+	}`)))
+
+	_, ok = registry.Lookup("POLICY_CHECK", 2)
+	assert.False(t, ok)
+	assert.Nil(t, registry.Versions("USER_MANAGEMENT"))
+}
+
+func TestLoadRegistry(t *testing.T) {
+	schema := &fstest.MapFile{Data: []byte(`{"description": "v", "type": "object"}`)}
+
+	registry, err := LoadRegistry(fstest.MapFS{
+		"defs/TEST_EVENT.v2.json": schema,
+		"defs/TEST_EVENT.v1.json": schema,
+		"defs/README.md":          &fstest.MapFile{Data: []byte("ignored")},
+	}, "defs")
+	require.NoError(t, err)
+	versions := registry.Versions("TEST_EVENT")
+	require.Len(t, versions, 2)
+	assert.Equal(t, 1, versions[0].Version)
+	assert.Equal(t, 2, versions[1].Version)
+	assert.Equal(t, "v", versions[1].Description)
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"missing version": {"defs/TEST_EVENT.v2.json": schema},
+		"bad name":        {"defs/test_event.json": schema},
+		"bad schema":      {"defs/TEST_EVENT.v1.json": &fstest.MapFile{Data: []byte(`{"type": "date"}`)}},
+		"missing dir":     {},
+	} {
+		_, err := LoadRegistry(fsys, "defs")
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,336 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldError describes one field of a document that does not match its schema
+type FieldError struct {
+	Field   string `json:"field"` // Dotted path, e.g. requestMetadata.requiredFields[0]; empty for the document itself
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Schema is a compiled JSON Schema. Only the keywords needed to describe audit events are supported:
+// type, properties, required, additionalProperties (boolean), items, enum, const, minLength, maxLength,
+// pattern, format (date-time, uuid, uri), minimum, maximum and minItems. Annotations such as title and
+// description are ignored; any other keyword is rejected when the schema is compiled.
+type Schema struct {
+	types                []string
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *bool
+	items                *Schema
+	enum                 []any
+	constValue           any
+	hasConst             bool
+	minLength, maxLength *int
+	minItems             *int
+	minimum, maximum     *float64
+	pattern              *regexp.Regexp
+	format               string
+}
+
+// annotationKeywords carry documentation only and do not affect validation
+var annotationKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "examples": true, "default": true,
+}
+
+var jsonTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "integer": true, "number": true, "boolean": true, "null": true,
+}
+
+var formats = map[string]func(string) bool{
+	"date-time": func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil },
+	"uuid":      func(s string) bool { _, err := uuid.Parse(s); return err == nil },
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+}
+
+// Compile parses a JSON Schema document
+func Compile(data []byte) (*Schema, error) {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return compile(document, "#")
+}
+
+func compile(node any, path string) (*Schema, error) {
+	keywords, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object", path)
+	}
+
+	s := &Schema{}
+	for keyword, value := range keywords {
+		var err error
+		switch keyword {
+		case "type":
+			s.types, err = compileTypes(value)
+		case "properties":
+			properties, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s/properties: must be an object", path)
+			}
+			s.properties = make(map[string]*Schema, len(properties))
+			for name, property := range properties {
+				if s.properties[name], err = compile(property, path+"/properties/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.required, err = compileStrings(value)
+		case "additionalProperties":
+			allowed, ok := value.(bool)
+			if !ok {
+				err = fmt.Errorf("must be a boolean")
+			}
+			s.additionalProperties = &allowed
+		case "items":
+			if s.items, err = compile(value, path+"/items"); err != nil {
+				return nil, err
+			}
+		case "enum":
+			values, ok := value.([]any)
+			if !ok || len(values) == 0 {
+				err = fmt.Errorf("must be a non-empty array")
+			}
+			s.enum = values
+		case "const":
+			s.constValue, s.hasConst = value, true
+		case "minLength":
+			s.minLength, err = compileCount(value)
+		case "maxLength":
+			s.maxLength, err = compileCount(value)
+		case "minItems":
+			s.minItems, err = compileCount(value)
+		case "minimum":
+			s.minimum, err = compileNumber(value)
+		case "maximum":
+			s.maximum, err = compileNumber(value)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				err = fmt.Errorf("must be a string")
+				break
+			}
+			s.pattern, err = regexp.Compile(pattern)
+		case "format":
+			format, ok := value.(string)
+			if !ok || formats[format] == nil {
+				err = fmt.Errorf("unsupported format %v", value)
+			}
+			s.format = format
+		default:
+			if !annotationKeywords[keyword] {
+				err = fmt.Errorf("unsupported keyword")
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", path, keyword, err)
+		}
+	}
+	return s, nil
+}
+
+func compileTypes(value any) ([]string, error) {
+	var types []string
+	if name, ok := value.(string); ok {
+		types = []string{name}
+	} else {
+		var err error
+		if types, err = compileStrings(value); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range types {
+		if !jsonTypes[name] {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	return types, nil
+}
+
+func compileStrings(value any) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		if values[i], ok = item.(string); !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+	}
+	return values, nil
+}
+
+func compileCount(value any) (*int, error) {
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, fmt.Errorf("must be a non-negative integer")
+	}
+	count := int(number)
+	return &count, nil
+}
+
+func compileNumber(value any) (*float64, error) {
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("must be a number")
+	}
+	return &number, nil
+}
+
+// Validate checks a decoded JSON document (as produced by json.Unmarshal into an any)
+// and returns every mismatch, ordered by field
+func (s *Schema) Validate(document any) []FieldError {
+	var errs []FieldError
+	s.validate(document, "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+func (s *Schema) validate(value any, path string, errs *[]FieldError) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.types) > 0 && !matchesAnyType(value, s.types) {
+		fail("must be of type %s", strings.Join(s.types, " or "))
+		return
+	}
+	if s.hasConst && !reflect.DeepEqual(value, s.constValue) {
+		fail("must be %s", formatValue(s.constValue))
+		return
+	}
+	if s.enum != nil && !containsValue(s.enum, value) {
+		values := make([]string, len(s.enum))
+		for i, allowed := range s.enum {
+			values[i] = formatValue(allowed)
+		}
+		fail("must be one of %s", strings.Join(values, ", "))
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := len([]rune(v))
+		if s.minLength != nil && length < *s.minLength {
+			fail("must be at least %d characters", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail("must be at most %d characters", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match pattern %s", s.pattern)
+		}
+		if s.format != "" && !formats[s.format](v) {
+			fail("must be a valid %s", s.format)
+		}
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			fail("must be at least %v", *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			fail("must be at most %v", *s.maximum)
+		}
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("must have at least %d items", *s.minItems)
+		}
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is required"})
+			}
+		}
+		for name, property := range v {
+			if schema, ok := s.properties[name]; ok {
+				schema.validate(property, joinPath(path, name), errs)
+			} else if s.additionalProperties != nil && !*s.additionalProperties {
+				*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is not allowed"})
+			}
+		}
+	}
+}
+
+func matchesAnyType(value any, types []string) bool {
+	for _, name := range types {
+		switch v := value.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case float64:
+			if name == "number" || (name == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case []any:
+			if name == "array" {
+				return true
+			}
+		case map[string]any:
+			if name == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValue(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
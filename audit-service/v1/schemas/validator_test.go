@@ -0,0 +1,84 @@
+package schemas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, document string) any {
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(document), &value))
+	return value
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"title": "test",
+		"type": "object",
+		"required": ["id", "kind"],
+		"additionalProperties": false,
+		"properties": {
+			"id": { "type": "string", "format": "uuid" },
+			"kind": { "enum": ["a", "b"] },
+			"name": { "type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[a-z]+$" },
+			"count": { "type": "integer", "minimum": 0, "maximum": 10 },
+			"fixed": { "const": "yes" },
+			"tags": {
+				"type": ["array", "null"],
+				"minItems": 1,
+				"items": { "type": "object", "required": ["fieldName"] }
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.Empty(t, schema.Validate(decode(t, `{
+			"id": "5f0c3a5e-9d8a-4f5e-8d2a-3c1e0b7a6f41", "kind": "a", "name": "abc",
+			"count": 3, "fixed": "yes", "tags": [{"fieldName": "x"}]
+		}`)))
+		assert.Empty(t, schema.Validate(decode(t, `{"id": "5f0c3a5e-9d8a-4f5e-8d2a-3c1e0b7a6f41", "kind": "b", "tags": null}`)))
+	})
+
+	t.Run("FieldErrors", func(t *testing.T) {
+		errs := schema.Validate(decode(t, `{
+			"id": "not-a-uuid", "name": "A", "count": 1.5, "fixed": "no",
+			"tags": [{"fieldName": "x"}, "y", {}], "extra": true
+		}`))
+		assert.Equal(t, []FieldError{
+			{Field: "count", Message: "must be of type integer"},
+			{Field: "extra", Message: "is not allowed"},
+			{Field: "fixed", Message: `must be "yes"`},
+			{Field: "id", Message: "must be a valid uuid"},
+			{Field: "kind", Message: "is required"},
+			{Field: "name", Message: "must be at least 2 characters"},
+			{Field: "name", Message: "must match pattern ^[a-z]+$"},
+			{Field: "tags[1]", Message: "must be of type object"},
+			{Field: "tags[2].fieldName", Message: "is required"},
+		}, errs)
+	})
+
+	t.Run("Document", func(t *testing.T) {
+		assert.Equal(t, []FieldError{{Message: "must be of type object"}}, schema.Validate(decode(t, `[]`)))
+		assert.Equal(t, []FieldError{{Field: "kind", Message: `must be one of "a", "b"`}},
+			schema.Validate(decode(t, `{"id": "5f0c3a5e-9d8a-4f5e-8d2a-3c1e0b7a6f41", "kind": "c"}`)))
+	})
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for name, document := range map[string]string{
+		"json":           `{`,
+		"not an object":  `[]`,
+		"unknown type":   `{"type": "date"}`,
+		"unknown format": `{"type": "string", "format": "email"}`,
+		"unsupported":    `{"oneOf": []}`,
+		"bad pattern":    `{"pattern": "("}`,
+		"nested":         `{"properties": {"a": {"minLength": -1}}}`,
+	} {
+		_, err := Compile([]byte(document))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// alertWebhookTimeout bounds each webhook call
+	alertWebhookTimeout = 10 * time.Second
+
+	// AlertSignatureHeader carries the HMAC-SHA256 of the webhook body, keyed with the webhook secret
+	AlertSignatureHeader = "X-Audit-Alert-Signature"
+)
+
+// SMTPConfig configures the mail server alert emails are sent through
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // PLAIN authentication is used when set
+	Password string
+	From     string
+}
+
+// AlertNotifier sends fired alerts to webhooks and by email
+type AlertNotifier struct {
+	client        *http.Client
+	webhookSecret []byte
+	smtp          SMTPConfig
+}
+
+// NewAlertNotifier creates a notifier; webhook bodies are signed when webhookSecret is set,
+// and emails are only sent when smtpConfig names a host
+func NewAlertNotifier(webhookSecret string, smtpConfig SMTPConfig) *AlertNotifier {
+	if smtpConfig.Port == 0 {
+		smtpConfig.Port = 587
+	}
+	return &AlertNotifier{
+		client:        &http.Client{Timeout: alertWebhookTimeout},
+		webhookSecret: []byte(webhookSecret),
+		smtp:          smtpConfig,
+	}
+}
+
+// Notify sends the alert to every webhook and email address of the rule.
+// All targets are attempted; the returned error joins the failures.
+func (n *AlertNotifier) Notify(ctx context.Context, alert *v1models.Alert, targets config.AlertNotify) error {
+	body, err := json.Marshal(v1models.ToAlertResponse(*alert))
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	var errs []error
+	for _, webhook := range targets.Webhooks {
+		if err := n.postWebhook(ctx, webhook, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", webhook, err))
+		}
+	}
+	if len(targets.Emails) > 0 {
+		if err := n.sendEmail(alert, targets.Emails); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *AlertNotifier) postWebhook(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.webhookSecret) > 0 {
+		mac := hmac.New(sha256.New, n.webhookSecret)
+		mac.Write(body)
+		req.Header.Set(AlertSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (n *AlertNotifier) sendEmail(alert *v1models.Alert, recipients []string) error {
+	if n.smtp.Host == "" {
+		return errors.New("email notifications are not configured")
+	}
+
+	subject := fmt.Sprintf("[%s] Audit alert: %s", alert.Severity, alert.RuleName)
+	if alert.GroupKey != "" {
+		subject += " (" + alert.GroupKey + ")"
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Alert rule %q fired at %s.\r\n\r\n", alert.RuleName, alert.FiredAt.UTC().Format(time.RFC3339))
+	if alert.Description != "" {
+		fmt.Fprintf(&body, "%s\r\n\r\n", alert.Description)
+	}
+	fmt.Fprintf(&body, "Severity: %s\r\n", alert.Severity)
+	if alert.GroupKey != "" {
+		fmt.Fprintf(&body, "Group:    %s\r\n", alert.GroupKey)
+	}
+	fmt.Fprintf(&body, "Logs:     %d between %s and %s\r\n", alert.LogCount,
+		alert.FirstLogAt.UTC().Format(time.RFC3339), alert.LastLogAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&body, "Alert ID: %s\r\n", alert.ID)
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.smtp.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", headerSafe(subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	message.WriteString(body.String())
+
+	var auth smtp.Auth
+	if n.smtp.Username != "" {
+		auth = smtp.PlainAuth("", n.smtp.Username, n.smtp.Password, n.smtp.Host)
+	}
+	addr := net.JoinHostPort(n.smtp.Host, strconv.Itoa(n.smtp.Port))
+	return smtp.SendMail(addr, auth, n.smtp.From, recipients, message.Bytes())
+}
+
+// headerSafe strips line breaks so configured values cannot inject mail headers
+func headerSafe(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
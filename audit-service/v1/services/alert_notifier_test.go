@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/config"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAlert() *v1models.Alert {
+	now := time.Date(2026, 1, 5, 22, 0, 0, 0, time.UTC)
+	return &v1models.Alert{
+		ID:                 uuid.New(),
+		RuleName:           "denied-requests",
+		Severity:           config.AlertSeverityHigh,
+		GroupKey:           "app-1",
+		LogCount:           3,
+		LogIDs:             v1models.JSONBRawMessage(`[]`),
+		FirstLogAt:         now.Add(-time.Minute),
+		LastLogAt:          now,
+		FiredAt:            now,
+		NotificationStatus: v1models.AlertNotificationPending,
+	}
+}
+
+func TestAlertNotifier_Webhook(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(AlertSignatureHeader)
+	}))
+	defer server.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	notifier := NewAlertNotifier("secret", SMTPConfig{})
+	alert := testAlert()
+	require.NoError(t, notifier.Notify(context.Background(), alert, config.AlertNotify{Webhooks: []string{server.URL}}))
+
+	var received v1models.AlertResponse
+	require.NoError(t, json.Unmarshal(body, &received))
+	assert.Equal(t, alert.ID, received.ID)
+	assert.Equal(t, "app-1", received.GroupKey)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+
+	// Every target is attempted; failures are reported together
+	err := notifier.Notify(context.Background(), alert, config.AlertNotify{
+		Webhooks: []string{failing.URL, server.URL},
+		Emails:   []string{"security@example.com"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status 502")
+	assert.Contains(t, err.Error(), "email notifications are not configured")
+}
+
+// fakeSMTPServer accepts one mail transaction at a time and records the messages it receives
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeSMTPServer{listener: listener}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var message strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				message.WriteString(dataLine)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, message.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(command, "QUIT"):
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) Messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func TestAlertNotifier_Email(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := net.LookupPort("tcp", port)
+	require.NoError(t, err)
+
+	notifier := NewAlertNotifier("", SMTPConfig{Host: host, Port: portNumber, From: "audit@example.com"})
+	require.NoError(t, notifier.Notify(context.Background(), testAlert(), config.AlertNotify{
+		Emails: []string{"security@example.com", "oncall@example.com"},
+	}))
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "To: security@example.com, oncall@example.com\r\n")
+	assert.Contains(t, messages[0], "Subject: [HIGH] Audit alert: denied-requests (app-1)\r\n")
+	assert.Contains(t, messages[0], "Logs:     3 between 2026-01-05T21:59:00Z and 2026-01-05T22:00:00Z")
+}
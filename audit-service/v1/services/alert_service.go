@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/config"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// alertDeliveryTimeout bounds storing an alert and sending its notifications
+	alertDeliveryTimeout = time.Minute
+
+	// alertWindowSweepInterval is how often threshold windows without recent logs are dropped
+	alertWindowSweepInterval = time.Minute
+
+	// defaultAlertLimit and maxAlertLimit bound the page size when listing alerts
+	defaultAlertLimit = 100
+	maxAlertLimit     = 1000
+)
+
+// ErrAlertNotFound is returned when an alert does not exist
+var ErrAlertNotFound = errors.New("alert not found")
+
+// alertNotifier sends fired alerts to the rule's targets
+type alertNotifier interface {
+	Notify(ctx context.Context, alert *v1models.Alert, targets config.AlertNotify) error
+}
+
+// alertWindowKey identifies the threshold window of one rule and group
+type alertWindowKey struct {
+	rule  string
+	group string
+}
+
+// alertHit is a log that matched a threshold rule
+type alertHit struct {
+	logID uuid.UUID
+	at    time.Time
+}
+
+// AlertService evaluates alert rules against audit logs as they are stored,
+// records the alerts that fire and sends them to the rules' webhooks and emails
+type AlertService struct {
+	repo     database.AlertRepository
+	config   *config.AlertsConfig
+	notifier alertNotifier
+
+	// windows holds, per threshold rule and group, the most recent matching logs (at most the rule's count).
+	// Windows are kept in memory: they start empty after a restart and are not shared between instances.
+	mu        sync.Mutex
+	windows   map[alertWindowKey][]alertHit
+	lastSweep time.Time
+
+	// running tracks alerts being stored and sent
+	running sync.WaitGroup
+
+	// stream, if set, receives every recorded alert for live subscribers
+	stream *StreamService
+}
+
+// NewAlertService creates a new alert service evaluating the configured rules
+func NewAlertService(repo database.AlertRepository, alerts *config.AlertsConfig, notifier *AlertNotifier) *AlertService {
+	return newAlertService(repo, alerts, notifier)
+}
+
+func newAlertService(repo database.AlertRepository, alerts *config.AlertsConfig, notifier alertNotifier) *AlertService {
+	if alerts == nil {
+		alerts = &config.AlertsConfig{}
+	}
+	return &AlertService{
+		repo:     repo,
+		config:   alerts,
+		notifier: notifier,
+		windows:  make(map[alertWindowKey][]alertHit),
+	}
+}
+
+// SetStream publishes the alerts the service records to the live event stream
+func (s *AlertService) SetStream(stream *StreamService) {
+	s.stream = stream
+}
+
+// Evaluate checks a newly stored log against every rule. Alerts that fire are stored and sent in the
+// background, so ingestion is not slowed down by notifications; Wait blocks until they are done.
+func (s *AlertService) Evaluate(log *v1models.AuditLog) {
+	if len(s.config.Rules) == 0 {
+		return
+	}
+	request, response := parseExchangeMetadata(log)
+	for i := range s.config.Rules {
+		rule := &s.config.Rules[i]
+		if !s.matches(rule, log, request, response) {
+			continue
+		}
+		hit := alertHit{logID: log.ID, at: log.Timestamp}
+		if rule.Threshold == nil {
+			s.fire(rule, "", []alertHit{hit})
+			continue
+		}
+		group := alertGroupKey(rule.Threshold.GroupBy, log, request, response)
+		if hits := s.record(rule, group, hit); hits != nil {
+			s.fire(rule, group, hits)
+		}
+	}
+}
+
+// Wait blocks until alerts being stored and sent are done
+func (s *AlertService) Wait() {
+	s.running.Wait()
+}
+
+// matches reports whether a log meets every condition of the rule
+func (s *AlertService) matches(rule *config.AlertRule, log *v1models.AuditLog, request, response exchangeMetadata) bool {
+	match := &rule.Match
+	if len(match.EventTypes) > 0 && !slices.Contains(match.EventTypes, derefString(log.EventType)) {
+		return false
+	}
+	if match.Status != "" && !strings.EqualFold(match.Status, log.Status) {
+		return false
+	}
+	if match.ConsentStatus != "" && !strings.EqualFold(match.ConsentStatus, response.Status) {
+		return false
+	}
+	if len(match.Fields) > 0 && !matchesAnyField(match.Fields, request.RequiredFields, response.RequestedFields) {
+		return false
+	}
+	if match.OutsideBusinessHours && s.config.BusinessHours.Contains(log.Timestamp) {
+		return false
+	}
+	return true
+}
+
+// record adds a hit to the rule's window for the group. Once the window holds the rule's count of hits
+// within its duration, the window is emptied and the hits are returned so the alert fires.
+func (s *AlertService) record(rule *config.AlertRule, group string, hit alertHit) []alertHit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepLocked()
+
+	key := alertWindowKey{rule: rule.Name, group: group}
+	hits := append(s.windows[key], hit)
+	// Logs may arrive out of order, e.g. when producers replay spooled events
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].at.Before(hits[j].at) })
+
+	newest := hits[len(hits)-1].at
+	start := 0
+	for start < len(hits) && newest.Sub(hits[start].at) > rule.Threshold.Window {
+		start++
+	}
+	hits = hits[max(start, len(hits)-rule.Threshold.Count):]
+
+	if len(hits) >= rule.Threshold.Count {
+		delete(s.windows, key)
+		return hits
+	}
+	s.windows[key] = hits
+	return nil
+}
+
+// sweepLocked drops windows whose newest hit has left the window, so groups that went quiet do not pile up
+func (s *AlertService) sweepLocked() {
+	now := time.Now()
+	if now.Sub(s.lastSweep) < alertWindowSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	windows := make(map[string]time.Duration, len(s.config.Rules))
+	for _, rule := range s.config.Rules {
+		if rule.Threshold != nil {
+			windows[rule.Name] = rule.Threshold.Window
+		}
+	}
+	for key, hits := range s.windows {
+		if now.Sub(hits[len(hits)-1].at) > windows[key.rule] {
+			delete(s.windows, key)
+		}
+	}
+}
+
+// fire stores and sends an alert for the hits in the background
+func (s *AlertService) fire(rule *config.AlertRule, group string, hits []alertHit) {
+	logIDs := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		logIDs[i] = hit.logID
+	}
+	encodedIDs, _ := json.Marshal(logIDs)
+
+	alert := &v1models.Alert{
+		RuleName:           rule.Name,
+		Severity:           rule.Severity,
+		Description:        rule.Description,
+		GroupKey:           group,
+		LogCount:           len(hits),
+		LogIDs:             v1models.JSONBRawMessage(encodedIDs),
+		FirstLogAt:         hits[0].at,
+		LastLogAt:          hits[len(hits)-1].at,
+		FiredAt:            time.Now().UTC(),
+		NotificationStatus: v1models.AlertNotificationNone,
+	}
+	targets := rule.Notify
+	if len(targets.Webhooks) > 0 || len(targets.Emails) > 0 {
+		alert.NotificationStatus = v1models.AlertNotificationPending
+	}
+	slog.Warn("Audit alert fired", "rule", rule.Name, "severity", rule.Severity, "group", group, "logs", len(hits))
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.deliver(alert, targets)
+	}()
+}
+
+// deliver stores the alert, then sends it and records the outcome
+func (s *AlertService) deliver(alert *v1models.Alert, targets config.AlertNotify) {
+	ctx, cancel := context.WithTimeout(context.Background(), alertDeliveryTimeout)
+	defer cancel()
+
+	if _, err := s.repo.CreateAlert(ctx, alert); err != nil {
+		slog.Error("Failed to record audit alert", "rule", alert.RuleName, "error", err)
+		return
+	}
+	if s.stream != nil {
+		// Subscribers get the alert as recorded, before its notifications are sent
+		recorded := *alert
+		s.stream.PublishAlert(&recorded)
+	}
+	if alert.NotificationStatus == v1models.AlertNotificationNone {
+		return
+	}
+
+	alert.NotificationStatus = v1models.AlertNotificationSent
+	if err := s.notifier.Notify(ctx, alert, targets); err != nil {
+		slog.Error("Failed to send audit alert", "rule", alert.RuleName, "alertId", alert.ID, "error", err)
+		message := err.Error()
+		alert.NotificationStatus = v1models.AlertNotificationFailed
+		alert.NotificationError = &message
+	}
+	if err := s.repo.UpdateAlert(ctx, alert); err != nil {
+		slog.Error("Failed to update audit alert", "alertId", alert.ID, "error", err)
+	}
+}
+
+// ListAlerts returns a page of fired alerts matching the request's filters, most recent first
+func (s *AlertService) ListAlerts(ctx context.Context, req *v1models.ListAlertsRequest) (*v1models.ListAlertsResponse, error) {
+	filters := &database.AlertFilters{Limit: req.Limit, Offset: req.Offset}
+	if filters.Limit == 0 {
+		filters.Limit = defaultAlertLimit
+	}
+	if filters.Limit < 0 || filters.Limit > maxAlertLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxAlertLimit)
+	}
+	if filters.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
+	if req.RuleName != "" {
+		filters.RuleName = &req.RuleName
+	}
+	if req.Severity != "" {
+		severity := strings.ToUpper(req.Severity)
+		filters.Severity = &severity
+	}
+	if req.GroupKey != "" {
+		filters.GroupKey = &req.GroupKey
+	}
+	if req.StartTime != "" {
+		startTime, err := time.Parse(time.RFC3339, req.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid startTime format, expected RFC3339: %w", ErrInvalidInput, err)
+		}
+		filters.StartTime = &startTime
+	}
+	if req.EndTime != "" {
+		endTime, err := time.Parse(time.RFC3339, req.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid endTime format, expected RFC3339: %w", ErrInvalidInput, err)
+		}
+		filters.EndTime = &endTime
+	}
+
+	alerts, total, err := s.repo.ListAlerts(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	response := &v1models.ListAlertsResponse{
+		Alerts: make([]v1models.AlertResponse, 0, len(alerts)),
+		Total:  total,
+		Limit:  filters.Limit,
+		Offset: filters.Offset,
+	}
+	for _, alert := range alerts {
+		response.Alerts = append(response.Alerts, v1models.ToAlertResponse(alert))
+	}
+	return response, nil
+}
+
+// GetAlert retrieves a fired alert by ID
+func (s *AlertService) GetAlert(ctx context.Context, id string) (*v1models.AlertResponse, error) {
+	alertID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid alert ID: %w", ErrInvalidInput, err)
+	}
+	alert, err := s.repo.GetAlert(ctx, alertID)
+	if err != nil {
+		if errors.Is(err, database.ErrAlertNotFound) {
+			return nil, ErrAlertNotFound
+		}
+		return nil, err
+	}
+	response := v1models.ToAlertResponse(*alert)
+	return &response, nil
+}
+
+// alertGroupKey returns the value of a threshold's groupBy dimension for a log
+func alertGroupKey(groupBy string, log *v1models.AuditLog, request, response exchangeMetadata) string {
+	switch groupBy {
+	case config.AlertGroupByConsumer:
+		return exchangeConsumer(log, request, response)
+	case config.AlertGroupByActorID:
+		return log.ActorID
+	case config.AlertGroupByTargetID:
+		return derefString(log.TargetID)
+	default:
+		return ""
+	}
+}
+
+// matchesAnyField reports whether any requested field matches a pattern; a trailing * matches a prefix
+func matchesAnyField(patterns []string, fieldLists ...[]string) bool {
+	for _, fields := range fieldLists {
+		for _, field := range fields {
+			for _, pattern := range patterns {
+				if prefix, ok := strings.CutSuffix(pattern, "*"); (ok && strings.HasPrefix(field, prefix)) || field == pattern {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
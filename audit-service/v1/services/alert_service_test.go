@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier records the alerts it is asked to send, failing while err is set
+type recordingNotifier struct {
+	mu     sync.Mutex
+	sent   []string
+	err    error
+	target config.AlertNotify
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert *v1models.Alert, targets config.AlertNotify) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, alert.RuleName)
+	n.target = targets
+	return n.err
+}
+
+func setupAlertTest(t *testing.T, rules ...config.AlertRule) (*AlertService, *exchangeLogger, *recordingNotifier) {
+	alerts := &config.AlertsConfig{Rules: rules}
+	require.NoError(t, alerts.Validate())
+
+	repo := database.NewGormRepository(setupSQLiteTestDB(t))
+	notifier := &recordingNotifier{}
+	alertService := newAlertService(repo, alerts, notifier)
+	return alertService, &exchangeLogger{repo: repo, alerts: alertService}, notifier
+}
+
+// exchangeLogger stores exchange events and evaluates them against the alert rules.
+// It bypasses event type validation, whose enum configuration is shared across tests.
+type exchangeLogger struct {
+	repo   *database.GormRepository
+	alerts *AlertService
+}
+
+func createExchangeLog(t *testing.T, logger *exchangeLogger, at time.Time, eventType, status, request, response string) {
+	log := &v1models.AuditLog{
+		Timestamp:  at,
+		EventType:  stringPtr(eventType),
+		Status:     status,
+		ActorType:  "SERVICE",
+		ActorID:    "orchestration-engine",
+		TargetType: "SERVICE",
+	}
+	if request != "" {
+		log.RequestMetadata = v1models.JSONBRawMessage(request)
+	}
+	if response != "" {
+		log.ResponseMetadata = v1models.JSONBRawMessage(response)
+	}
+	created, err := logger.repo.CreateAuditLog(context.Background(), log)
+	require.NoError(t, err)
+	logger.alerts.Evaluate(created)
+}
+
+func listAlerts(t *testing.T, service *AlertService, req *v1models.ListAlertsRequest) []v1models.AlertResponse {
+	service.Wait()
+	response, err := service.ListAlerts(context.Background(), req)
+	require.NoError(t, err)
+	return response.Alerts
+}
+
+func TestAlertService_ThresholdRule(t *testing.T) {
+	alerts, audit, notifier := setupAlertTest(t, config.AlertRule{
+		Name:      "denied-requests",
+		Severity:  "high",
+		Match:     config.AlertMatch{EventTypes: []string{"POLICY_CHECK"}, Status: v1models.StatusFailure},
+		Threshold: &config.AlertThreshold{Count: 3, Window: 5 * time.Minute, GroupBy: config.AlertGroupByConsumer},
+		Notify:    config.AlertNotify{Webhooks: []string{"https://alerts.example.com"}},
+	})
+	base := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	denied := func(at time.Time, appID string) {
+		createExchangeLog(t, audit, at, "POLICY_CHECK", v1models.StatusFailure, `{"applicationId":"`+appID+`"}`, `{"authorized":false}`)
+	}
+
+	// Spread over more than the window: no alert
+	denied(base, "app-1")
+	denied(base.Add(4*time.Minute), "app-1")
+	denied(base.Add(10*time.Minute), "app-1")
+	// Other consumers, other event types and successes do not count towards app-1
+	denied(base.Add(11*time.Minute), "app-2")
+	createExchangeLog(t, audit, base.Add(11*time.Minute), "POLICY_CHECK", v1models.StatusSuccess, `{"applicationId":"app-1"}`, "")
+	createExchangeLog(t, audit, base.Add(11*time.Minute), "CONSENT_CHECK", v1models.StatusFailure, `{"applicationId":"app-1"}`, "")
+	assert.Empty(t, listAlerts(t, alerts, &v1models.ListAlertsRequest{}))
+
+	// The third denial within five minutes fires, even when logged out of order
+	denied(base.Add(13*time.Minute), "app-1")
+	denied(base.Add(12*time.Minute), "app-1")
+	fired := listAlerts(t, alerts, &v1models.ListAlertsRequest{})
+	require.Len(t, fired, 1)
+	alert := fired[0]
+	assert.Equal(t, "denied-requests", alert.RuleName)
+	assert.Equal(t, config.AlertSeverityHigh, alert.Severity)
+	assert.Equal(t, "app-1", alert.GroupKey)
+	assert.Equal(t, 3, alert.LogCount)
+	assert.Len(t, alert.LogIDs, 3)
+	assert.Equal(t, base.Add(10*time.Minute), alert.FirstLogAt.UTC())
+	assert.Equal(t, base.Add(13*time.Minute), alert.LastLogAt.UTC())
+	assert.Equal(t, v1models.AlertNotificationSent, alert.NotificationStatus)
+	assert.Equal(t, []string{"denied-requests"}, notifier.sent)
+	assert.Equal(t, []string{"https://alerts.example.com"}, notifier.target.Webhooks)
+
+	// The window starts over after firing
+	denied(base.Add(14*time.Minute), "app-1")
+	assert.Len(t, listAlerts(t, alerts, &v1models.ListAlertsRequest{}), 1)
+}
+
+func TestAlertService_MatchRule(t *testing.T) {
+	alerts, audit, notifier := setupAlertTest(t,
+		config.AlertRule{
+			Name:     "sensitive-fields-after-hours",
+			Severity: config.AlertSeverityCritical,
+			Match: config.AlertMatch{
+				EventTypes:           []string{"PROVIDER_FETCH"},
+				Fields:               []string{"person.nic", "person.medical.*"},
+				OutsideBusinessHours: true,
+			},
+			Notify: config.AlertNotify{Emails: []string{"security@example.com"}},
+		},
+		config.AlertRule{
+			Name:  "consent-rejected",
+			Match: config.AlertMatch{ConsentStatus: "rejected"},
+		},
+	)
+	notifier.err = errors.New("mail server unavailable")
+
+	monday := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC) // business hours default to 09:00-17:00 UTC
+	fetch := func(at time.Time, fields string) {
+		createExchangeLog(t, audit, at, "PROVIDER_FETCH", v1models.StatusSuccess, "", `{"applicationId":"app-1","requestedFields":`+fields+`}`)
+	}
+	fetch(monday.Add(10*time.Hour), `["person.nic"]`)                         // during business hours
+	fetch(monday.Add(22*time.Hour), `["person.name"]`)                        // not sensitive
+	fetch(monday.Add(22*time.Hour), `["person.name","person.medical.blood"]`) // fires
+	createExchangeLog(t, audit, monday, "CONSENT_CHECK", v1models.StatusSuccess, "", `{"status":"approved"}`)
+	createExchangeLog(t, audit, monday, "CONSENT_CHECK", v1models.StatusSuccess, "", `{"status":"rejected"}`)
+
+	sensitive := listAlerts(t, alerts, &v1models.ListAlertsRequest{RuleName: "sensitive-fields-after-hours"})
+	require.Len(t, sensitive, 1)
+	assert.Equal(t, 1, sensitive[0].LogCount)
+	assert.Equal(t, v1models.AlertNotificationFailed, sensitive[0].NotificationStatus)
+	require.NotNil(t, sensitive[0].NotificationError)
+	assert.Contains(t, *sensitive[0].NotificationError, "mail server unavailable")
+
+	rejected := listAlerts(t, alerts, &v1models.ListAlertsRequest{Severity: "medium"})
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "consent-rejected", rejected[0].RuleName)
+	assert.Equal(t, v1models.AlertNotificationNone, rejected[0].NotificationStatus)
+	assert.Equal(t, []string{"sensitive-fields-after-hours"}, notifier.sent, "rules without targets are only recorded")
+}
+
+func TestAlertService_ListAndGetAlerts(t *testing.T) {
+	alerts, logger, _ := setupAlertTest(t, config.AlertRule{Name: "failures", Match: config.AlertMatch{Status: v1models.StatusFailure}})
+	audit := NewAuditService(logger.repo)
+	audit.SetAlertService(alerts)
+	for _, status := range []string{v1models.StatusFailure, v1models.StatusSuccess, v1models.StatusFailure, v1models.StatusFailure} {
+		_, err := audit.CreateAuditLog(context.Background(), &v1models.CreateAuditLogRequest{
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			EventType:  stringPtr("MANAGEMENT_EVENT"),
+			Status:     status,
+			ActorType:  "SERVICE",
+			ActorID:    "portal-backend",
+			TargetType: "RESOURCE",
+		})
+		require.NoError(t, err)
+	}
+
+	page := listAlerts(t, alerts, &v1models.ListAlertsRequest{Limit: 2, Offset: 2})
+	assert.Len(t, page, 1)
+	response, err := alerts.ListAlerts(context.Background(), &v1models.ListAlertsRequest{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), response.Total)
+	assert.Len(t, response.Alerts, 2)
+
+	alert, err := alerts.GetAlert(context.Background(), response.Alerts[0].ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, response.Alerts[0].ID, alert.ID)
+
+	_, err = alerts.GetAlert(context.Background(), "00000000-0000-0000-0000-000000000000")
+	assert.ErrorIs(t, err, ErrAlertNotFound)
+	_, err = alerts.GetAlert(context.Background(), "not-a-uuid")
+	assert.True(t, IsValidationError(err))
+
+	for name, req := range map[string]*v1models.ListAlertsRequest{
+		"limit":     {Limit: maxAlertLimit + 1},
+		"offset":    {Offset: -1},
+		"startTime": {StartTime: "yesterday"},
+	} {
+		_, err := alerts.ListAlerts(context.Background(), req)
+		assert.True(t, IsValidationError(err), name)
+	}
+}
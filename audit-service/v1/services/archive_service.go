@@ -0,0 +1,527 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/config"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// DefaultArchiveInterval is how often expired logs are archived when no interval is configured
+	DefaultArchiveInterval = 24 * time.Hour
+
+	// DefaultArchivePrefix is the object key prefix for archives when none is configured
+	DefaultArchivePrefix = "audit-logs"
+
+	// archiveBatchSize is how many expired logs are read from the database per query
+	archiveBatchSize = 1000
+
+	// archiveObjectMaxLogs caps the logs per archive object; logs are only deleted once their object is stored,
+	// so this also bounds how many logs are held for deletion at a time
+	archiveObjectMaxLogs = 50000
+
+	// maxArchiveRunsListed caps how many runs ListArchiveRuns returns
+	maxArchiveRunsListed = 100
+
+	// Archive runs are themselves recorded as audit events
+	archiveEventType     = "AUDIT_ARCHIVE"
+	archiveTargetID      = "audit_logs"
+	archiveSchedulerType = "SYSTEM"
+	archiveSchedulerID   = "retention-scheduler"
+	archivePartitionerID = "time-partition-scheduler"
+
+	// defaultArchiveCategory names the object key folder of logs archived under the default policy
+	defaultArchiveCategory = "_default"
+)
+
+var (
+	// ErrArchivalNotConfigured is returned when no object store or no expiring retention policy is configured
+	ErrArchivalNotConfigured = errors.New("audit log archival is not configured")
+	// ErrArchiveRunInProgress is returned when starting a run while another is still running
+	ErrArchiveRunInProgress = errors.New("an archive run is already in progress")
+	// ErrArchiveRunNotFound is returned when an archive run does not exist
+	ErrArchiveRunNotFound = errors.New("archive run not found")
+)
+
+// ArchiveService enforces retention policies: logs older than their event type's retention period
+// are written to object storage as gzipped NDJSON and then deleted from the database
+type ArchiveService struct {
+	repo      database.AuditRepository
+	archives  database.ArchiveRepository
+	store     ArchiveStore
+	retention *config.RetentionConfig
+	prefix    string
+
+	// mu guards active, which ensures only one run deletes logs at a time
+	mu     sync.Mutex
+	active bool
+
+	// running tracks in-flight archive runs
+	running sync.WaitGroup
+}
+
+// NewArchiveService creates a new archive service. store may be nil, which disables archival.
+func NewArchiveService(repo database.AuditRepository, archives database.ArchiveRepository, store ArchiveStore, retention *config.RetentionConfig, prefix string) *ArchiveService {
+	if retention == nil {
+		retention = &config.RetentionConfig{}
+	}
+	if prefix == "" {
+		prefix = DefaultArchivePrefix
+	}
+	return &ArchiveService{
+		repo:      repo,
+		archives:  archives,
+		store:     store,
+		retention: retention,
+		prefix:    prefix,
+	}
+}
+
+// Enabled reports whether runs can archive anything
+func (s *ArchiveService) Enabled() bool {
+	return s.store != nil && s.retention.Enabled()
+}
+
+// StartArchiveRun starts a manual archive run in the background
+func (s *ArchiveService) StartArchiveRun(ctx context.Context, actorType, actorID string) (*v1models.ArchiveRunResponse, error) {
+	actorType, actorID = exportActor(actorType, actorID)
+	if enums := v1models.GetEnumConfig(); enums != nil && !enums.IsValidActorType(actorType) {
+		return nil, fmt.Errorf("%w: invalid actorType: %s", ErrInvalidInput, actorType)
+	}
+
+	run, err := s.beginRun(ctx, v1models.ArchiveRunTriggerManual, actorType, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the response before the run starts updating its state
+	response := v1models.ToArchiveRunResponse(*run)
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.executeRun(run, s.archiveExpired)
+	}()
+
+	return &response, nil
+}
+
+// RunScheduledArchive archives expired logs and waits for the run to finish.
+// Returns ErrArchiveRunInProgress if a manual run is still going.
+func (s *ArchiveService) RunScheduledArchive(ctx context.Context) (*v1models.ArchiveRun, error) {
+	run, err := s.beginRun(ctx, v1models.ArchiveRunTriggerScheduled, archiveSchedulerType, archiveSchedulerID)
+	if err != nil {
+		return nil, err
+	}
+	s.executeRun(run, s.archiveExpired)
+	return run, nil
+}
+
+// ArchivePartition archives every log of a time partition and waits for the run to finish. Its logs are
+// tombstoned rather than deleted, as the caller drops the whole partition once the run has completed.
+// Returns ErrArchiveRunInProgress if another run is still going.
+func (s *ArchiveService) ArchivePartition(ctx context.Context, partition database.TimePartition) (*v1models.ArchiveRun, error) {
+	run, err := s.beginRun(ctx, v1models.ArchiveRunTriggerPartition, archiveSchedulerType, archivePartitionerID)
+	if err != nil {
+		return nil, err
+	}
+	s.executeRun(run, func(ctx context.Context, state *archiveRunState, _ time.Time) error {
+		policy := retentionPolicy{
+			category: partition.Name,
+			filters:  &database.AuditLogFilters{StartTime: &partition.From, EndTime: &partition.To, SortAscending: true},
+			keepLogs: true,
+		}
+		if err := s.archivePolicy(ctx, state, policy); err != nil {
+			return fmt.Errorf("failed to archive partition %s: %w", partition.Name, err)
+		}
+		return nil
+	})
+	return run, nil
+}
+
+// StartScheduler archives expired logs every interval until the context is cancelled
+func (s *ArchiveService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if !s.Enabled() || interval <= 0 {
+		slog.Info("Audit log archival scheduler disabled")
+		return
+	}
+
+	slog.Info("Audit log archival scheduler started", "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Audit log archival scheduler stopped")
+			return
+		case <-ticker.C:
+			if _, err := s.RunScheduledArchive(ctx); err != nil {
+				slog.Error("Failed to start scheduled archive run", "error", err)
+			}
+		}
+	}
+}
+
+// beginRun claims the single run slot and records the run
+func (s *ArchiveService) beginRun(ctx context.Context, trigger, actorType, actorID string) (*v1models.ArchiveRun, error) {
+	if !s.Enabled() {
+		return nil, ErrArchivalNotConfigured
+	}
+
+	s.mu.Lock()
+	if s.active {
+		s.mu.Unlock()
+		return nil, ErrArchiveRunInProgress
+	}
+	s.active = true
+	s.mu.Unlock()
+
+	run, err := s.archives.CreateArchiveRun(ctx, &v1models.ArchiveRun{
+		ID:        uuid.New(),
+		Status:    v1models.ArchiveRunStatusRunning,
+		Trigger:   trigger,
+		ActorType: actorType,
+		ActorID:   actorID,
+	})
+	if err != nil {
+		s.release()
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *ArchiveService) release() {
+	s.mu.Lock()
+	s.active = false
+	s.mu.Unlock()
+}
+
+// executeRun archives logs and records the outcome on the run and as an audit event.
+// The run outlives the request that started it, so it runs with its own context.
+func (s *ArchiveService) executeRun(run *v1models.ArchiveRun, archive func(context.Context, *archiveRunState, time.Time) error) {
+	defer s.release()
+	ctx := context.Background()
+
+	slog.Info("Archive run started", "runId", run.ID, "trigger", run.Trigger)
+	state := &archiveRunState{run: run}
+	err := archive(ctx, state, time.Now().UTC())
+
+	completedAt := time.Now().UTC()
+	run.CompletedAt = &completedAt
+	if err != nil {
+		message := err.Error()
+		run.Status = v1models.ArchiveRunStatusFailed
+		run.Error = &message
+		slog.Error("Archive run failed", "runId", run.ID, "archived", run.ArchivedCount, "error", err)
+	} else {
+		run.Status = v1models.ArchiveRunStatusCompleted
+		slog.Info("Archive run completed", "runId", run.ID, "archived", run.ArchivedCount, "objects", len(state.objects))
+	}
+	if err := s.archives.UpdateArchiveRun(ctx, run); err != nil {
+		slog.Error("Failed to record archive run result", "runId", run.ID, "error", err)
+	}
+	s.recordArchiveEvent(ctx, run, len(state.objects))
+}
+
+// archiveRunState accumulates the objects written by a run
+type archiveRunState struct {
+	run     *v1models.ArchiveRun
+	objects []v1models.ArchiveObject
+}
+
+// retentionPolicy selects the logs of one event category that have expired
+type retentionPolicy struct {
+	category string
+	filters  *database.AuditLogFilters
+
+	// eventType is recorded on the policy's archive objects; empty for logs of any event type
+	eventType string
+
+	// keepLogs tombstones archived logs instead of deleting them, for logs dropped with their partition
+	keepLogs bool
+}
+
+// policies returns the expiring retention policies as of now.
+// The default policy covers every event type without its own policy, including logs without an event type.
+func (s *ArchiveService) policies(now time.Time) []retentionPolicy {
+	var policies []retentionPolicy
+	configured := s.retention.ConfiguredEventTypes()
+	for _, eventType := range configured {
+		days := s.retention.EventTypes[eventType]
+		if days <= 0 {
+			continue
+		}
+		cutoff := now.AddDate(0, 0, -days)
+		policies = append(policies, retentionPolicy{
+			category:  eventType,
+			filters:   &database.AuditLogFilters{EventType: &eventType, EndTime: &cutoff, SortAscending: true},
+			eventType: eventType,
+		})
+	}
+	if s.retention.DefaultDays > 0 {
+		cutoff := now.AddDate(0, 0, -s.retention.DefaultDays)
+		policies = append(policies, retentionPolicy{
+			category: defaultArchiveCategory,
+			filters:  &database.AuditLogFilters{ExcludeEventTypes: configured, EndTime: &cutoff, SortAscending: true},
+		})
+	}
+	return policies
+}
+
+// archiveExpired archives the expired logs of every retention policy
+func (s *ArchiveService) archiveExpired(ctx context.Context, state *archiveRunState, now time.Time) error {
+	for _, policy := range s.policies(now) {
+		if err := s.archivePolicy(ctx, state, policy); err != nil {
+			return fmt.Errorf("failed to archive %s logs: %w", policy.category, err)
+		}
+	}
+	return nil
+}
+
+// archivePolicy streams a policy's expired logs, oldest first, into archive objects.
+// Each object is uploaded before its logs are deleted, so a failure never loses a log;
+// at worst, logs of an object whose deletion failed are archived again by the next run.
+func (s *ArchiveService) archivePolicy(ctx context.Context, state *archiveRunState, policy retentionPolicy) error {
+	var object *archiveObjectWriter
+	defer func() {
+		if object != nil {
+			object.discard()
+		}
+	}()
+
+	err := s.repo.StreamAuditLogs(ctx, policy.filters, archiveBatchSize, func(batch []v1models.AuditLog) error {
+		for _, log := range batch {
+			if object == nil {
+				var err error
+				if object, err = newArchiveObjectWriter(); err != nil {
+					return err
+				}
+			}
+			if err := object.add(log); err != nil {
+				return err
+			}
+			if len(object.logs) >= archiveObjectMaxLogs {
+				err := s.storeObject(ctx, state, policy, object)
+				object.discard()
+				object = nil
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if object != nil {
+		return s.storeObject(ctx, state, policy, object)
+	}
+	return nil
+}
+
+// storeObject uploads an archive object, deletes its logs and records progress on the run
+func (s *ArchiveService) storeObject(ctx context.Context, state *archiveRunState, policy retentionPolicy, object *archiveObjectWriter) error {
+	size, checksum, err := object.finish()
+	if err != nil {
+		return err
+	}
+
+	run := state.run
+	key := path.Join(s.prefix, policy.category, run.CreatedAt.UTC().Format("2006/01/02"),
+		fmt.Sprintf("%s-%04d.ndjson.gz", run.ID, len(state.objects)+1))
+	if err := s.store.PutObject(ctx, key, object.file, size, "application/gzip", checksum); err != nil {
+		return err
+	}
+
+	var deleted int64
+	if policy.keepLogs {
+		if err := s.archives.TombstoneArchivedAuditLogs(ctx, object.logs, run.ID, key); err != nil {
+			return fmt.Errorf("archived %s but failed to tombstone its logs: %w", key, err)
+		}
+		deleted = int64(len(object.logs))
+	} else if deleted, err = s.archives.DeleteArchivedAuditLogs(ctx, object.logs, run.ID, key); err != nil {
+		return fmt.Errorf("archived %s but failed to delete its logs: %w", key, err)
+	}
+
+	archived := v1models.ArchiveObject{Key: key, LogCount: len(object.logs), Size: size, SHA256: checksum, EventType: policy.eventType}
+	state.objects = append(state.objects, archived)
+	run.ArchivedCount += deleted
+
+	objects, err := json.Marshal(state.objects)
+	if err != nil {
+		return fmt.Errorf("failed to marshal archive objects: %w", err)
+	}
+	run.Objects = v1models.JSONBRawMessage(objects)
+	if err := s.archives.UpdateArchiveRun(ctx, run); err != nil {
+		slog.Warn("Failed to record archive run progress", "runId", run.ID, "error", err)
+	}
+	slog.Info("Archived audit logs", "runId", run.ID, "key", key, "logs", len(object.logs), "bytes", size)
+	return nil
+}
+
+// archiveObjectWriter writes logs as gzipped NDJSON to a temporary file
+type archiveObjectWriter struct {
+	file    *os.File
+	gzip    *gzip.Writer
+	encoder exportEncoder
+	logs    []v1models.AuditLog
+}
+
+func newArchiveObjectWriter() (*archiveObjectWriter, error) {
+	file, err := os.CreateTemp("", "audit-archive-*.ndjson.gz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	compressed := gzip.NewWriter(file)
+	encoder, err := newExportEncoder(ExportFormatNDJSON, compressed)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return &archiveObjectWriter{file: file, gzip: compressed, encoder: encoder}, nil
+}
+
+func (w *archiveObjectWriter) add(log v1models.AuditLog) error {
+	if err := w.encoder.Encode(log); err != nil {
+		return fmt.Errorf("failed to encode audit log: %w", err)
+	}
+	w.logs = append(w.logs, log)
+	return nil
+}
+
+// finish completes the file, rewinds it for upload and returns its size and hex SHA-256
+func (w *archiveObjectWriter) finish() (int64, string, error) {
+	if err := w.encoder.Flush(); err != nil {
+		return 0, "", fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := w.gzip.Close(); err != nil {
+		return 0, "", fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return 0, "", fmt.Errorf("failed to read archive file: %w", err)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(hash, w.file)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read archive file: %w", err)
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return 0, "", fmt.Errorf("failed to rewind archive file: %w", err)
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// discard removes the temporary file
+func (w *archiveObjectWriter) discard() {
+	w.file.Close()
+	os.Remove(w.file.Name())
+}
+
+// GetArchiveRun retrieves an archive run's status
+func (s *ArchiveService) GetArchiveRun(ctx context.Context, id string) (*v1models.ArchiveRunResponse, error) {
+	runID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid archive run ID: %w", ErrInvalidInput, err)
+	}
+	run, err := s.archives.GetArchiveRun(ctx, runID)
+	if err != nil {
+		if errors.Is(err, database.ErrArchiveRunNotFound) {
+			return nil, ErrArchiveRunNotFound
+		}
+		return nil, err
+	}
+	response := v1models.ToArchiveRunResponse(*run)
+	return &response, nil
+}
+
+// ListArchiveRuns returns the most recent archive runs, newest first
+func (s *ArchiveService) ListArchiveRuns(ctx context.Context, limit int) (*v1models.ListArchiveRunsResponse, error) {
+	if limit <= 0 || limit > maxArchiveRunsListed {
+		limit = maxArchiveRunsListed
+	}
+	runs, err := s.archives.ListArchiveRuns(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	response := &v1models.ListArchiveRunsResponse{Runs: make([]v1models.ArchiveRunResponse, 0, len(runs))}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, v1models.ToArchiveRunResponse(run))
+	}
+	return response, nil
+}
+
+// FailUnfinishedRuns marks runs left running by a previous process as failed.
+// Logs are only deleted after their object is stored, so an interrupted run loses nothing;
+// the next run picks up whatever it did not delete.
+func (s *ArchiveService) FailUnfinishedRuns(ctx context.Context) error {
+	count, err := s.archives.FailUnfinishedArchiveRuns(ctx, "interrupted by service restart")
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		slog.Warn("Marked interrupted archive runs as failed", "count", count)
+	}
+	return nil
+}
+
+// Wait blocks until all manual archive runs started by this service have finished
+func (s *ArchiveService) Wait() {
+	s.running.Wait()
+}
+
+// recordArchiveEvent writes an AUDIT_ARCHIVE audit log for a finished run.
+// The logs are already deleted by then, so a failure to record the event is only logged.
+func (s *ArchiveService) recordArchiveEvent(ctx context.Context, run *v1models.ArchiveRun, objectCount int) {
+	metadata := map[string]interface{}{
+		"runId":         run.ID,
+		"trigger":       run.Trigger,
+		"archivedCount": run.ArchivedCount,
+		"objectCount":   objectCount,
+	}
+	status := v1models.StatusSuccess
+	if run.Error != nil {
+		status = v1models.StatusFailure
+		metadata["error"] = *run.Error
+	}
+	requestMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		slog.Error("Failed to marshal archive event metadata", "runId", run.ID, "error", err)
+		return
+	}
+
+	eventType := archiveEventType
+	eventAction := "DELETE"
+	targetID := archiveTargetID
+	event := &v1models.AuditLog{
+		Timestamp:       time.Now().UTC(),
+		Status:          status,
+		EventType:       &eventType,
+		EventAction:     &eventAction,
+		ActorType:       run.ActorType,
+		ActorID:         run.ActorID,
+		TargetType:      "RESOURCE",
+		TargetID:        &targetID,
+		RequestMetadata: v1models.JSONBRawMessage(requestMetadata),
+	}
+	if _, err := s.repo.CreateAuditLog(ctx, event); err != nil {
+		slog.Error("Failed to record archive audit event", "runId", run.ID, "error", err)
+	}
+}
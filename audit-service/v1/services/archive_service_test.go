@@ -0,0 +1,217 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryArchiveStore keeps uploaded objects in memory
+type memoryArchiveStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	err     error
+}
+
+func newMemoryArchiveStore() *memoryArchiveStore {
+	return &memoryArchiveStore{objects: make(map[string][]byte)}
+}
+
+func (s *memoryArchiveStore) PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType, sha256Hex string) error {
+	if s.err != nil {
+		return s.err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != sha256Hex {
+		return errors.New("checksum mismatch")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	return nil
+}
+
+// readArchiveObject decompresses an archive object into its logs
+func readArchiveObject(t *testing.T, data []byte) []v1models.AuditLogResponse {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var logs []v1models.AuditLogResponse
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var log v1models.AuditLogResponse
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &log))
+		logs = append(logs, log)
+	}
+	require.NoError(t, scanner.Err())
+	return logs
+}
+
+// setupArchiveTest seeds logs of various ages and event types
+func setupArchiveTest(t *testing.T, store ArchiveStore, retention *config.RetentionConfig) (*ArchiveService, *database.GormRepository, *gorm.DB) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+
+	now := time.Now().UTC()
+	seed := []struct {
+		eventType *string
+		age       time.Duration
+	}{
+		{stringPtr("POLICY_CHECK"), 40 * 24 * time.Hour},
+		{stringPtr("POLICY_CHECK"), 20 * 24 * time.Hour},
+		{stringPtr("AUDIT_EXPORT"), 400 * 24 * time.Hour},
+		{nil, 400 * 24 * time.Hour},
+		{stringPtr("DATA_FETCH"), 400 * 24 * time.Hour},
+		{stringPtr("DATA_FETCH"), 100 * 24 * time.Hour},
+		{stringPtr("POLICY_CHECK"), time.Hour},
+	}
+	for _, log := range seed {
+		_, err := repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:  now.Add(-log.age),
+			Status:     v1models.StatusSuccess,
+			EventType:  log.eventType,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+		})
+		require.NoError(t, err)
+	}
+
+	return NewArchiveService(repo, repo, store, retention, ""), repo, db
+}
+
+func TestArchiveService_RunScheduledArchive(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryArchiveStore()
+	retention := &config.RetentionConfig{
+		DefaultDays: 365,
+		EventTypes:  map[string]int{"POLICY_CHECK": 30, "AUDIT_EXPORT": 0},
+	}
+	service, repo, db := setupArchiveTest(t, store, retention)
+
+	run, err := service.RunScheduledArchive(ctx)
+	require.NoError(t, err)
+	require.Nil(t, run.Error, "%v", derefString(run.Error))
+	assert.Equal(t, v1models.ArchiveRunStatusCompleted, run.Status)
+	assert.Equal(t, v1models.ArchiveRunTriggerScheduled, run.Trigger)
+	// One expired POLICY_CHECK, plus the old DATA_FETCH and untyped logs under the default policy
+	assert.Equal(t, int64(3), run.ArchivedCount)
+
+	response := v1models.ToArchiveRunResponse(*run)
+	require.Len(t, response.Objects, 2)
+	assert.Equal(t, "POLICY_CHECK", response.Objects[0].EventType)
+	assert.Equal(t, 1, response.Objects[0].LogCount)
+	assert.Empty(t, response.Objects[1].EventType)
+	assert.Equal(t, 2, response.Objects[1].LogCount)
+	assert.Contains(t, response.Objects[1].Key, "audit-logs/_default/")
+
+	// The archive holds the full logs, including their chain fields
+	archived := readArchiveObject(t, store.objects[response.Objects[1].Key])
+	require.Len(t, archived, 2)
+	assert.NotNil(t, archived[0].Sequence)
+	assert.NotEmpty(t, archived[0].Hash)
+
+	// Retained logs stay; the archive run itself is recorded as an audit event
+	var remaining []v1models.AuditLog
+	require.NoError(t, db.Order("sequence ASC").Find(&remaining).Error)
+	require.Len(t, remaining, 5)
+	last := remaining[len(remaining)-1]
+	require.NotNil(t, last.EventType)
+	assert.Equal(t, archiveEventType, *last.EventType)
+	assert.Equal(t, "retention-scheduler", last.ActorID)
+
+	// The chain still verifies through the tombstones of archived logs
+	result, err := NewIntegrityService(repo, nil).VerifyChain(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "%+v", result.Failure)
+	assert.Equal(t, int64(3), result.ArchivedCount)
+	assert.Equal(t, int64(5), result.CheckedCount)
+
+	// Nothing is left to archive on the next run
+	run, err = service.RunScheduledArchive(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.ArchivedCount)
+
+	// A deletion disguised as archival without a tombstone still breaks the chain
+	var tombstone v1models.AuditChainTombstone
+	require.NoError(t, db.Order("sequence ASC").First(&tombstone).Error)
+	require.NoError(t, db.Delete(&tombstone).Error)
+	result, err = NewIntegrityService(repo, nil).VerifyChain(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, tombstone.Sequence, result.Failure.Sequence)
+}
+
+func TestArchiveService_UploadFailureKeepsLogs(t *testing.T) {
+	store := newMemoryArchiveStore()
+	store.err = errors.New("bucket unavailable")
+	service, _, db := setupArchiveTest(t, store, &config.RetentionConfig{DefaultDays: 1})
+
+	run, err := service.RunScheduledArchive(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, v1models.ArchiveRunStatusFailed, run.Status)
+	require.NotNil(t, run.Error)
+	assert.Contains(t, *run.Error, "bucket unavailable")
+
+	var count int64
+	require.NoError(t, db.Model(&v1models.AuditLog{}).Where("event_type IS NULL OR event_type <> ?", archiveEventType).Count(&count).Error)
+	assert.Equal(t, int64(7), count)
+}
+
+func TestArchiveService_NotConfigured(t *testing.T) {
+	ctx := context.Background()
+
+	noStore, _, _ := setupArchiveTest(t, nil, &config.RetentionConfig{DefaultDays: 30})
+	_, err := noStore.RunScheduledArchive(ctx)
+	assert.ErrorIs(t, err, ErrArchivalNotConfigured)
+
+	keepForever, _, _ := setupArchiveTest(t, newMemoryArchiveStore(), &config.RetentionConfig{EventTypes: map[string]int{"POLICY_CHECK": 0}})
+	_, err = keepForever.StartArchiveRun(ctx, "ADMIN", "admin@example.com")
+	assert.ErrorIs(t, err, ErrArchivalNotConfigured)
+}
+
+func TestArchiveService_ManualRun(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := setupArchiveTest(t, newMemoryArchiveStore(), &config.RetentionConfig{EventTypes: map[string]int{"DATA_FETCH": 30}})
+
+	started, err := service.StartArchiveRun(ctx, "ADMIN", "admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, v1models.ArchiveRunStatusRunning, started.Status)
+	assert.Equal(t, v1models.ArchiveRunTriggerManual, started.Trigger)
+	service.Wait()
+
+	run, err := service.GetArchiveRun(ctx, started.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, v1models.ArchiveRunStatusCompleted, run.Status)
+	assert.Equal(t, int64(2), run.ArchivedCount)
+	assert.Equal(t, "admin@example.com", run.ActorID)
+
+	runs, err := service.ListArchiveRuns(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, runs.Runs, 1)
+
+	_, err = service.GetArchiveRun(ctx, "not-a-uuid")
+	assert.True(t, IsValidationError(err))
+}
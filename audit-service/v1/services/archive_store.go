@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gov-dx-sandbox/shared/s3"
+)
+
+// ArchiveStore stores archived audit logs as objects
+type ArchiveStore interface {
+	// PutObject uploads size bytes from body under key; sha256Hex is the hex SHA-256 of the content
+	PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType, sha256Hex string) error
+}
+
+// archiveUploadTimeout bounds the upload of an archive object, which can be large
+const archiveUploadTimeout = 10 * time.Minute
+
+// S3ArchiveStore uploads objects to an S3-compatible store
+type S3ArchiveStore struct {
+	client *s3.Client
+}
+
+// NewS3ArchiveStore creates a store for the configured bucket
+func NewS3ArchiveStore(config s3.Config) (*S3ArchiveStore, error) {
+	if config.Timeout == 0 {
+		config.Timeout = archiveUploadTimeout
+	}
+	client, err := s3.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return &S3ArchiveStore{client: client}, nil
+}
+
+// PutObject uploads an object with a single signed PUT request
+func (s *S3ArchiveStore) PutObject(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType, sha256Hex string) error {
+	if err := s.client.PutObject(ctx, key, contentType, body, size, sha256Hex); err != nil {
+		return fmt.Errorf("failed to upload archive object %s: %w", key, err)
+	}
+	return nil
+}
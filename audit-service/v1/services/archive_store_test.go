@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gov-dx-sandbox/shared/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3ArchiveStore_PutObject(t *testing.T) {
+	content := "compressed archive"
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+
+	var gotPath, gotBody, gotAuth, gotChecksum string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		gotAuth, gotChecksum = r.Header.Get("Authorization"), r.Header.Get("X-Amz-Content-Sha256")
+		if strings.Contains(r.URL.Path, "denied") {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, "<Error><Code>AccessDenied</Code></Error>")
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewS3ArchiveStore(s3.Config{
+		Endpoint:        server.URL,
+		Bucket:          "audit-archive",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	require.NoError(t, err)
+
+	err = store.PutObject(context.Background(), "audit-logs/POLICY_CHECK/run-0001.ndjson.gz",
+		strings.NewReader(content), int64(len(content)), "application/gzip", checksum)
+	require.NoError(t, err)
+	assert.Equal(t, "/audit-archive/audit-logs/POLICY_CHECK/run-0001.ndjson.gz", gotPath)
+	assert.Equal(t, content, gotBody)
+	assert.Equal(t, checksum, gotChecksum)
+	assert.Contains(t, gotAuth, "Credential=key/")
+	assert.Contains(t, gotAuth, "/us-east-1/s3/aws4_request")
+
+	err = store.PutObject(context.Background(), "denied.ndjson.gz",
+		strings.NewReader(content), int64(len(content)), "application/gzip", checksum)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
@@ -2,17 +2,37 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/audit-service/v1/database"
 	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/schemas"
 )
 
 // AuditService handles generalized audit log operations
 type AuditService struct {
 	repo database.AuditRepository
+
+	// alerts, if set, evaluates alert rules against every newly stored log
+	alerts *AlertService
+
+	// eventSchemas, if set, holds the schemas events are validated against before they are stored
+	eventSchemas *schemas.Registry
+
+	// stream, if set, receives every newly stored log for live subscribers
+	stream *StreamService
+
+	// siem, if set, forwards every newly stored log to the SIEM
+	siem *SIEMExporter
+
+	// batcher, if set, stores logs created one at a time in micro-batches
+	batcher *WriteBatcher
 }
 
 // NewAuditService creates a new audit service instance using the database repository
@@ -20,10 +40,90 @@ func NewAuditService(repo database.AuditRepository) *AuditService {
 	return &AuditService{repo: repo}
 }
 
-// CreateAuditLog creates a new audit log entry from a request
+// SetAlertService makes the service evaluate alert rules against the logs it stores,
+// whether they arrive over HTTP or from the queue
+func (s *AuditService) SetAlertService(alerts *AlertService) {
+	s.alerts = alerts
+}
+
+// SetStream publishes the logs the service stores to the live event stream
+func (s *AuditService) SetStream(stream *StreamService) {
+	s.stream = stream
+}
+
+// SetSIEMExporter forwards the logs the service stores to the SIEM
+func (s *AuditService) SetSIEMExporter(exporter *SIEMExporter) {
+	s.siem = exporter
+}
+
+// SetWriteBatcher makes the service store the logs of concurrent requests in micro-batches
+func (s *AuditService) SetWriteBatcher(batcher *WriteBatcher) {
+	s.batcher = batcher
+}
+
+// SetEventSchemas makes the service reject events whose payload does not match their event type's schema.
+// Event types without schemas are stored as before.
+func (s *AuditService) SetEventSchemas(registry *schemas.Registry) {
+	s.eventSchemas = registry
+}
+
+// CreateAuditLog creates a new audit log entry from a request.
+// Creation is idempotent per eventId: resubmitting an event returns the stored log together with ErrDuplicateEvent.
 func (s *AuditService) CreateAuditLog(ctx context.Context, req *v1models.CreateAuditLogRequest) (*v1models.AuditLog, error) {
+	auditLog, err := s.newAuditLog(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create in database using repository
+	var createdLog *v1models.AuditLog
+	if s.batcher != nil {
+		createdLog, err = s.batcher.Write(ctx, auditLog)
+	} else {
+		createdLog, err = s.repo.CreateAuditLog(ctx, auditLog)
+	}
+	return s.created(req, createdLog, err)
+}
+
+// AuditLogResult is the outcome of creating one audit log of a batch
+type AuditLogResult struct {
+	Log *v1models.AuditLog
+	Err error
+}
+
+// CreateAuditLogs creates audit log entries from several requests, storing the valid ones as one batch.
+// The result at each index is that of the request at the same index, with the log and error CreateAuditLog
+// would have returned for it.
+func (s *AuditService) CreateAuditLogs(ctx context.Context, reqs []*v1models.CreateAuditLogRequest) []AuditLogResult {
+	results := make([]AuditLogResult, len(reqs))
+	var logs []*v1models.AuditLog
+	var indexes []int
+	for i, req := range reqs {
+		auditLog, err := s.newAuditLog(req)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		logs = append(logs, auditLog)
+		indexes = append(indexes, i)
+	}
+	if len(logs) == 0 {
+		return results
+	}
+
+	for j, result := range createAuditLogs(ctx, s.repo, logs) {
+		i := indexes[j]
+		results[i].Log, results[i].Err = s.created(reqs[i], result.Log, result.Err)
+	}
+	return results
+}
+
+// newAuditLog converts and validates a create request
+func (s *AuditService) newAuditLog(req *v1models.CreateAuditLogRequest) (*v1models.AuditLog, error) {
 	// Convert request to model
 	auditLog := &v1models.AuditLog{
+		EventID:            req.EventID,
+		Source:             req.Source,
 		EventType:          req.EventType,
 		EventAction:        req.EventAction,
 		Status:             req.Status,
@@ -31,6 +131,7 @@ func (s *AuditService) CreateAuditLog(ctx context.Context, req *v1models.CreateA
 		ActorID:            req.ActorID,
 		TargetType:         req.TargetType,
 		TargetID:           req.TargetID,
+		CorrelationID:      req.CorrelationID,
 		RequestMetadata:    req.RequestMetadata,
 		ResponseMetadata:   req.ResponseMetadata,
 		AdditionalMetadata: req.AdditionalMetadata,
@@ -53,6 +154,26 @@ func (s *AuditService) CreateAuditLog(ctx context.Context, req *v1models.CreateA
 		auditLog.TraceID = &traceUUID
 	}
 
+	// A W3C traceparent supplies the trace ID if none was given, and the span the event belongs to
+	if req.Traceparent != nil && *req.Traceparent != "" {
+		traceID, spanID, err := parseTraceparent(*req.Traceparent)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid traceparent: %w", ErrValidation, err)
+		}
+		if auditLog.TraceID != nil && *auditLog.TraceID != traceID {
+			return nil, fmt.Errorf("%w: traceId %s does not match traceparent trace ID %s", ErrValidation, auditLog.TraceID, traceID)
+		}
+		if auditLog.TraceID == nil {
+			// Event schemas describe the trace ID however the producer supplied it
+			traceIDString := traceID.String()
+			withTraceID := *req
+			withTraceID.TraceID = &traceIDString
+			req = &withTraceID
+		}
+		auditLog.TraceID = &traceID
+		auditLog.SpanID = &spanID
+	}
+
 	// Validate before creating
 	if err := auditLog.Validate(); err != nil {
 		// All validation errors from the model are treated as domain validation errors
@@ -60,25 +181,297 @@ func (s *AuditService) CreateAuditLog(ctx context.Context, req *v1models.CreateA
 		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
 	}
 
-	// Create in database using repository
-	createdLog, err := s.repo.CreateAuditLog(ctx, auditLog)
+	version, err := s.validateEventSchema(req)
+	if err != nil {
+		return nil, err
+	}
+	auditLog.EventVersion = version
+	return auditLog, nil
+}
+
+// created maps the repository's result of storing a log and publishes a newly stored log
+func (s *AuditService) created(req *v1models.CreateAuditLogRequest, createdLog *v1models.AuditLog, err error) (*v1models.AuditLog, error) {
+	if errors.Is(err, database.ErrDuplicateEventID) {
+		return createdLog, fmt.Errorf("%w: eventId %s", ErrDuplicateEvent, *req.EventID)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if s.stream != nil {
+		s.stream.PublishLog(createdLog)
+	}
+	if s.siem != nil {
+		s.siem.Export(createdLog)
+	}
+	if s.alerts != nil {
+		s.alerts.Evaluate(createdLog)
+	}
 	return createdLog, nil
 }
 
-// GetAuditLogs retrieves audit logs with optional filtering
-func (s *AuditService) GetAuditLogs(ctx context.Context, traceID *string, eventType *string, limit, offset int) ([]v1models.AuditLog, int64, error) {
+// validateEventSchema checks the request against the schema of its event type and returns the schema version used.
+// Events without an eventVersion are validated against version 1, so producers that predate versioning keep working.
+func (s *AuditService) validateEventSchema(req *v1models.CreateAuditLogRequest) (*int, error) {
+	if s.eventSchemas == nil {
+		return req.EventVersion, nil
+	}
+
+	eventType := ""
+	if req.EventType != nil {
+		eventType = *req.EventType
+	}
+	versions := s.eventSchemas.Versions(eventType)
+	if len(versions) == 0 {
+		if req.EventVersion != nil {
+			return nil, fmt.Errorf("%w: eventVersion given for event type %q, which has no schema", ErrValidation, eventType)
+		}
+		return nil, nil
+	}
+
+	version := 1
+	if req.EventVersion != nil {
+		version = *req.EventVersion
+	}
+	schema, ok := s.eventSchemas.Lookup(eventType, version)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown eventVersion %d for event type %s, latest is %d", ErrValidation, version, eventType, len(versions))
+	}
+
+	// Validate the event in the JSON form the producer sent
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event for schema validation: %w", err)
+	}
+	var document any
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode event for schema validation: %w", err)
+	}
+	if fields := schema.Validate(document); len(fields) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrValidation, &EventSchemaError{EventType: eventType, Version: version, Fields: fields})
+	}
+	return &version, nil
+}
+
+const (
+	// defaultAuditLogLimit is the page size used when no limit is requested
+	defaultAuditLogLimit = 100
+	// maxAuditLogLimit caps the page size
+	maxAuditLogLimit = 1000
+)
+
+// GetAuditLogs retrieves a page of audit logs matching the request's filters.
+// Pages are addressed either by offset or by the opaque cursor returned as nextCursor;
+// cursors stay stable while new logs are written, so they are preferred for browsing.
+func (s *AuditService) GetAuditLogs(ctx context.Context, req *v1models.GetAuditLogsRequest) (*v1models.GetAuditLogsResponse, error) {
+	filters, err := buildAuditLogFilters(req)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, total, err := s.repo.GetAuditLogs(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &v1models.GetAuditLogsResponse{
+		Logs:   make([]v1models.AuditLogResponse, len(logs)),
+		Total:  total,
+		Limit:  filters.Limit,
+		Offset: filters.Offset,
+	}
+	for i, log := range logs {
+		response.Logs[i] = v1models.ToAuditLogResponse(log)
+	}
+
+	// A full page may be followed by more logs; a short page is the last one
+	if len(logs) == filters.Limit {
+		cursor := encodeAuditLogCursor(logs[len(logs)-1])
+		response.NextCursor = &cursor
+	}
+
+	return response, nil
+}
+
+// buildAuditLogFilters validates the request and converts it to repository filters
+func buildAuditLogFilters(req *v1models.GetAuditLogsRequest) (*database.AuditLogFilters, error) {
 	filters := &database.AuditLogFilters{
-		TraceID:   traceID,
-		EventType: eventType,
-		Limit:     limit,
-		Offset:    offset,
+		TraceID:       optionalString(req.TraceID),
+		CorrelationID: optionalString(req.CorrelationID),
+		EventType:     optionalString(req.EventType),
+		EventAction:   optionalString(req.EventAction),
+		Status:        optionalString(req.Status),
+		ActorType:     optionalString(req.ActorType),
+		ActorID:       optionalString(req.ActorID),
+		ConsumerAppID: optionalString(req.ConsumerAppID),
+		TargetType:    optionalString(req.TargetType),
+		TargetID:      optionalString(req.TargetID),
+		EntityIDs:     req.EntityIDs,
+		Search:        optionalString(strings.TrimSpace(req.Search)),
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	}
+
+	if filters.Limit <= 0 {
+		filters.Limit = defaultAuditLogLimit
+	}
+	if filters.Limit > maxAuditLogLimit {
+		filters.Limit = maxAuditLogLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	if req.TraceID != "" {
+		traceID, err := uuid.Parse(req.TraceID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid traceId format: %w", ErrValidation, err)
+		}
+		// Match the stored form, whichever form (e.g. a 32-hex OpenTelemetry trace ID) was given
+		canonical := traceID.String()
+		filters.TraceID = &canonical
+	}
+	if req.Status != "" && req.Status != v1models.StatusSuccess && req.Status != v1models.StatusFailure {
+		return nil, fmt.Errorf("%w: invalid status: %s (must be %s or %s)", ErrInvalidInput, req.Status, v1models.StatusSuccess, v1models.StatusFailure)
+	}
+
+	if req.StartTime != "" {
+		startTime, err := time.Parse(time.RFC3339, req.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid startTime format, expected RFC3339: %w", ErrInvalidInput, err)
+		}
+		startTime = startTime.UTC()
+		filters.StartTime = &startTime
+	}
+	if req.EndTime != "" {
+		endTime, err := time.Parse(time.RFC3339, req.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid endTime format, expected RFC3339: %w", ErrInvalidInput, err)
+		}
+		endTime = endTime.UTC()
+		filters.EndTime = &endTime
+	}
+	if filters.StartTime != nil && filters.EndTime != nil && !filters.EndTime.After(*filters.StartTime) {
+		return nil, fmt.Errorf("%w: endTime must be after startTime", ErrInvalidInput)
+	}
+
+	switch strings.ToLower(req.SortOrder) {
+	case "", "desc":
+	case "asc":
+		filters.SortAscending = true
+	default:
+		return nil, fmt.Errorf("%w: invalid sortOrder: %s (must be asc or desc)", ErrInvalidInput, req.SortOrder)
+	}
+
+	if req.Cursor != "" {
+		if req.Offset > 0 {
+			return nil, fmt.Errorf("%w: cursor and offset cannot be combined", ErrInvalidInput)
+		}
+		cursor, err := decodeAuditLogCursor(req.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor: %w", ErrInvalidInput, err)
+		}
+		filters.After = cursor
 	}
 
-	return s.repo.GetAuditLogs(ctx, filters)
+	return filters, nil
+}
+
+// encodeAuditLogCursor returns the opaque cursor pointing just past the given log
+func encodeAuditLogCursor(log v1models.AuditLog) string {
+	raw := log.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + log.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeAuditLogCursor parses a cursor produced by encodeAuditLogCursor
+func decodeAuditLogCursor(cursor string) (*database.AuditLogCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	timestampPart, idPart, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, timestampPart)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return nil, err
+	}
+	return &database.AuditLogCursor{Timestamp: timestamp.UTC(), ID: id}, nil
+}
+
+// optionalString returns nil for an empty string so unset filters are skipped
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// maxTraceEvents caps the events returned for one trace
+const maxTraceEvents = 1000
+
+// GetTrace returns the audit logs of one exchange request in the order they were recorded, across every
+// service that recorded them. The ID is a trace ID (UUID or 32-hex OpenTelemetry trace ID); any other value
+// is looked up as a correlation ID. A non-nil entityIDs restricts the logs as for GetAuditLogs.
+func (s *AuditService) GetTrace(ctx context.Context, id string, entityIDs []string) (*v1models.TraceResponse, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("%w: trace ID is required", ErrInvalidInput)
+	}
+
+	response := &v1models.TraceResponse{}
+	filters := &database.AuditLogFilters{EntityIDs: entityIDs, SortAscending: true, Limit: maxTraceEvents + 1}
+	if traceID, err := uuid.Parse(id); err == nil {
+		response.TraceID = traceID.String()
+		filters.TraceID = &response.TraceID
+	} else {
+		response.CorrelationID = id
+		filters.CorrelationID = &response.CorrelationID
+	}
+
+	logs, _, err := s.repo.GetAuditLogs(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve trace: %w", err)
+	}
+	if len(logs) == 0 {
+		return nil, ErrTraceNotFound
+	}
+	if len(logs) > maxTraceEvents {
+		logs = logs[:maxTraceEvents]
+		response.Truncated = true
+	}
+
+	response.StartTime = logs[0].Timestamp
+	response.EndTime = logs[len(logs)-1].Timestamp
+	response.DurationMs = response.EndTime.Sub(response.StartTime).Milliseconds()
+	response.EventCount = len(logs)
+	response.Participants = []string{}
+	response.Events = make([]v1models.AuditLogResponse, len(logs))
+	seen := make(map[string]bool)
+	addParticipant := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			response.Participants = append(response.Participants, id)
+		}
+	}
+	for i, log := range logs {
+		if log.Status == v1models.StatusFailure {
+			response.FailureCount++
+		}
+		if log.ActorType == "SERVICE" || log.ActorType == v1models.ActorTypeApplication {
+			addParticipant(log.ActorID)
+		}
+		if log.TargetType == "SERVICE" && log.TargetID != nil {
+			addParticipant(*log.TargetID)
+		}
+		response.Events[i] = v1models.ToAuditLogResponse(log)
+	}
+	return response, nil
 }
 
 // GetAuditLogsByTraceID retrieves audit logs by trace ID (convenience method)
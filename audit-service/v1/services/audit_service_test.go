@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/audit-service/config"
 	"github.com/gov-dx-sandbox/audit-service/v1/database"
 	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/audit-service/v1/schemas"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
@@ -206,6 +210,419 @@ func TestAuditService_CreateAuditLog_Validation(t *testing.T) {
 	}
 }
 
+func TestAuditService_CreateAuditLog_DuplicateEventID(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+
+	newRequest := func(eventID string) *v1models.CreateAuditLogRequest {
+		return &v1models.CreateAuditLogRequest{
+			EventID:    stringPtr(eventID),
+			Source:     v1models.IngestionSourceHTTP,
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+			TargetID:   stringPtr("consent-engine"),
+		}
+	}
+
+	first, err := service.CreateAuditLog(ctx, newRequest("event-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "event-1", derefString(first.EventID))
+	assert.Equal(t, v1models.IngestionSourceHTTP, first.Source)
+
+	// A redelivered event is not stored again; the original log is returned
+	retry := newRequest("event-1")
+	retry.Status = v1models.StatusFailure
+	duplicate, err := service.CreateAuditLog(ctx, retry)
+	assert.ErrorIs(t, err, ErrDuplicateEvent)
+	assert.False(t, IsValidationError(err))
+	require.NotNil(t, duplicate)
+	assert.Equal(t, first.ID, duplicate.ID)
+	assert.Equal(t, v1models.StatusSuccess, duplicate.Status)
+
+	_, err = service.CreateAuditLog(ctx, newRequest("event-2"))
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&v1models.AuditLog{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	_, err = service.CreateAuditLog(ctx, newRequest(""))
+	assert.True(t, IsValidationError(err), "an empty eventId is invalid")
+}
+
+func TestAuditService_CreateAuditLog_EventSchemas(t *testing.T) {
+	enums := &config.AuditEnums{
+		EventTypes:   []string{"POLICY_CHECK", "MANAGEMENT_EVENT"},
+		EventActions: []string{"CREATE", "READ", "UPDATE", "DELETE"},
+		ActorTypes:   []string{"SERVICE", "ADMIN", "MEMBER", "SYSTEM"},
+		TargetTypes:  []string{"SERVICE", "RESOURCE"},
+	}
+	enums.InitializeMaps()
+	v1models.SetEnumConfig(enums)
+
+	service, _ := setupTestService(t)
+	registry, err := schemas.DefaultRegistry()
+	require.NoError(t, err)
+	service.SetEventSchemas(registry)
+	ctx := context.Background()
+
+	managementEvent := func(additionalMetadata string) *v1models.CreateAuditLogRequest {
+		return &v1models.CreateAuditLogRequest{
+			Timestamp:          time.Now().UTC().Format(time.RFC3339),
+			EventType:          stringPtr("MANAGEMENT_EVENT"),
+			EventAction:        stringPtr("CREATE"),
+			Status:             v1models.StatusSuccess,
+			ActorType:          "ADMIN",
+			ActorID:            "admin-1",
+			TargetType:         "RESOURCE",
+			AdditionalMetadata: v1models.JSONBRawMessage(additionalMetadata),
+		}
+	}
+
+	t.Run("ValidDefaultsToVersion1", func(t *testing.T) {
+		log, err := service.CreateAuditLog(ctx, managementEvent(`{"resource":"members","resourceId":"m-1"}`))
+		require.NoError(t, err)
+		require.NotNil(t, log.EventVersion)
+		assert.Equal(t, 1, *log.EventVersion)
+	})
+
+	t.Run("FieldErrors", func(t *testing.T) {
+		req := managementEvent(`{"resourceId":"m-1"}`)
+		req.ActorType = "SERVICE"
+		_, err := service.CreateAuditLog(ctx, req)
+		assert.True(t, IsValidationError(err))
+
+		var schemaErr *EventSchemaError
+		require.True(t, errors.As(err, &schemaErr))
+		assert.Equal(t, "MANAGEMENT_EVENT", schemaErr.EventType)
+		assert.Equal(t, 1, schemaErr.Version)
+		assert.Equal(t, []schemas.FieldError{
+			{Field: "actorType", Message: `must be one of "ADMIN", "MEMBER", "SYSTEM"`},
+			{Field: "additionalMetadata.resource", Message: "is required"},
+		}, schemaErr.Fields)
+	})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		req := managementEvent(`{"resource":"members"}`)
+		req.EventVersion = intPtr(2)
+		_, err := service.CreateAuditLog(ctx, req)
+		assert.True(t, IsValidationError(err))
+	})
+
+	t.Run("EventTypeWithoutSchema", func(t *testing.T) {
+		req := &v1models.CreateAuditLogRequest{
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+		}
+		log, err := service.CreateAuditLog(ctx, req)
+		require.NoError(t, err)
+		assert.Nil(t, log.EventVersion)
+
+		req.EventVersion = intPtr(1)
+		_, err = service.CreateAuditLog(ctx, req)
+		assert.True(t, IsValidationError(err), "a version needs a schema")
+	})
+}
+
+func TestAuditService_CreateAuditLog_TraceContext(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	newRequest := func() *v1models.CreateAuditLogRequest {
+		return &v1models.CreateAuditLogRequest{
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+		}
+	}
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("Traceparent", func(t *testing.T) {
+		req := newRequest()
+		req.Traceparent = stringPtr(traceparent)
+		req.CorrelationID = stringPtr("req-42")
+		log, err := service.CreateAuditLog(ctx, req)
+		require.NoError(t, err)
+		require.NotNil(t, log.TraceID)
+		assert.Equal(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", log.TraceID.String())
+		assert.Equal(t, "00f067aa0ba902b7", derefString(log.SpanID))
+		assert.Equal(t, "req-42", derefString(log.CorrelationID))
+	})
+
+	t.Run("OpenTelemetryTraceID", func(t *testing.T) {
+		req := newRequest()
+		req.TraceID = stringPtr("4bf92f3577b34da6a3ce929d0e0e4736")
+		req.Traceparent = stringPtr(traceparent)
+		log, err := service.CreateAuditLog(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", log.TraceID.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, modify := range map[string]func(*v1models.CreateAuditLogRequest){
+			"malformed traceparent": func(r *v1models.CreateAuditLogRequest) { r.Traceparent = stringPtr("00-abc-def-01") },
+			"zero trace ID": func(r *v1models.CreateAuditLogRequest) {
+				r.Traceparent = stringPtr("00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+			},
+			"mismatched traceId": func(r *v1models.CreateAuditLogRequest) {
+				r.TraceID = stringPtr("550e8400-e29b-41d4-a716-446655440000")
+				r.Traceparent = stringPtr(traceparent)
+			},
+			"empty correlationId": func(r *v1models.CreateAuditLogRequest) { r.CorrelationID = stringPtr("") },
+		} {
+			req := newRequest()
+			modify(req)
+			_, err := service.CreateAuditLog(ctx, req)
+			assert.True(t, IsValidationError(err), name)
+		}
+	})
+}
+
+func TestAuditService_GetTrace(t *testing.T) {
+	service, db := setupTestService(t)
+	repo := database.NewGormRepository(db)
+	ctx := context.Background()
+
+	traceID := uuid.New()
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	record := func(offset time.Duration, eventType, status, actorType, actorID, targetID string, trace *uuid.UUID, correlationID *string) {
+		_, err := repo.CreateAuditLog(ctx, &v1models.AuditLog{
+			Timestamp:     start.Add(offset),
+			TraceID:       trace,
+			CorrelationID: correlationID,
+			EventType:     stringPtr(eventType),
+			Status:        status,
+			ActorType:     actorType,
+			ActorID:       actorID,
+			TargetType:    "SERVICE",
+			TargetID:      stringPtr(targetID),
+		})
+		require.NoError(t, err)
+	}
+	// Recorded out of order; the trace is returned oldest first
+	record(300*time.Millisecond, "PROVIDER_FETCH", v1models.StatusFailure, "SERVICE", "orchestration-engine", "drp", &traceID, nil)
+	record(0, "DATA_REQUEST", v1models.StatusSuccess, v1models.ActorTypeApplication, "app-1", "orchestration-engine", &traceID, nil)
+	record(100*time.Millisecond, "POLICY_CHECK", v1models.StatusSuccess, "SERVICE", "orchestration-engine", "policy-decision-point", &traceID, nil)
+	record(200*time.Millisecond, "CONSENT_CHECK", v1models.StatusSuccess, "SERVICE", "orchestration-engine", "consent-engine", &traceID, nil)
+	other := uuid.New()
+	record(0, "DATA_REQUEST", v1models.StatusSuccess, v1models.ActorTypeApplication, "app-2", "orchestration-engine", &other, stringPtr("req-7"))
+
+	t.Run("ByTraceID", func(t *testing.T) {
+		// OpenTelemetry form of the same trace ID
+		trace, err := service.GetTrace(ctx, strings.ReplaceAll(traceID.String(), "-", ""), nil)
+		require.NoError(t, err)
+		assert.Equal(t, traceID.String(), trace.TraceID)
+		assert.Equal(t, 4, trace.EventCount)
+		assert.Equal(t, 1, trace.FailureCount)
+		assert.Equal(t, int64(300), trace.DurationMs)
+		assert.Equal(t, []string{"app-1", "orchestration-engine", "policy-decision-point", "consent-engine", "drp"}, trace.Participants)
+		require.Len(t, trace.Events, 4)
+		assert.Equal(t, "DATA_REQUEST", derefString(trace.Events[0].EventType))
+		assert.Equal(t, "PROVIDER_FETCH", derefString(trace.Events[3].EventType))
+		assert.False(t, trace.Truncated)
+	})
+
+	t.Run("ByCorrelationID", func(t *testing.T) {
+		trace, err := service.GetTrace(ctx, "req-7", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "req-7", trace.CorrelationID)
+		assert.Empty(t, trace.TraceID)
+		assert.Equal(t, 1, trace.EventCount)
+	})
+
+	t.Run("EntityScope", func(t *testing.T) {
+		trace, err := service.GetTrace(ctx, traceID.String(), []string{"consent-engine"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, trace.EventCount)
+
+		_, err = service.GetTrace(ctx, traceID.String(), []string{"app-2"})
+		assert.ErrorIs(t, err, ErrTraceNotFound)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := service.GetTrace(ctx, uuid.New().String(), nil)
+		assert.ErrorIs(t, err, ErrTraceNotFound)
+		_, err = service.GetTrace(ctx, " ", nil)
+		assert.True(t, IsValidationError(err))
+	})
+}
+
+func TestAuditService_GetResourceTimeline(t *testing.T) {
+	service, db := setupTestService(t)
+	repo := database.NewGormRepository(db)
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	record := func(offset time.Duration, action, actorID, metadata string) {
+		_, err := repo.CreateAuditLog(ctx, &v1models.AuditLog{
+			Timestamp:          start.Add(offset),
+			EventType:          stringPtr(managementEventType),
+			EventAction:        stringPtr(action),
+			Status:             v1models.StatusSuccess,
+			ActorType:          "ADMIN",
+			ActorID:            actorID,
+			TargetType:         "RESOURCE",
+			AdditionalMetadata: v1models.JSONBRawMessage(metadata),
+		})
+		require.NoError(t, err)
+	}
+	// Recorded out of order; the timeline is returned oldest first
+	record(3*time.Hour, "UPDATE", "admin-1", `{"resource":"APPLICATIONS","resourceId":"app-1","operation":"credentials/cred-1/rotate"}`)
+	record(0, "CREATE", "member-1", `{"resource":"APPLICATIONS","resourceId":"app-1"}`)
+	record(time.Hour, "UPDATE", "member-1", `{"resource":"APPLICATION-SUBMISSIONS","resourceId":"sub-1","operation":"submit","relatedResources":[{"type":"APPLICATIONS","id":"app-1"}]}`)
+	record(2*time.Hour, "UPDATE", "admin-1", `{"resource":"BULK-OPERATIONS","operation":"suspend","items":[{"type":"application","id":"app-1","success":true},{"type":"application","id":"app-2","success":true}]}`)
+	// Other resources, and other kinds of resources with the same ID, are left out
+	record(0, "CREATE", "member-1", `{"resource":"APPLICATIONS","resourceId":"app-10"}`)
+	record(0, "CREATE", "member-1", `{"resource":"MEMBERS","resourceId":"app-1"}`)
+
+	t.Run("Stitched", func(t *testing.T) {
+		timeline, err := service.GetResourceTimeline(ctx, "applications", "app-1", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "APPLICATION", timeline.ResourceType)
+		assert.Equal(t, 4, timeline.EventCount)
+		require.Len(t, timeline.Events, 4)
+		relations := make([]string, len(timeline.Events))
+		for i, event := range timeline.Events {
+			relations[i] = event.Relation
+		}
+		assert.Equal(t, []string{TimelineRelationSelf, TimelineRelationRelated, TimelineRelationBulk, TimelineRelationSelf}, relations)
+		assert.Equal(t, "APPLICATION-SUBMISSIONS", timeline.Events[1].Resource)
+		assert.Equal(t, "sub-1", timeline.Events[1].ResourceID)
+		assert.Equal(t, "credentials/cred-1/rotate", timeline.Events[3].Operation)
+		assert.False(t, timeline.Truncated)
+	})
+
+	t.Run("EntityScope", func(t *testing.T) {
+		timeline, err := service.GetResourceTimeline(ctx, "APPLICATION", "app-1", []string{"admin-1"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, timeline.EventCount)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := service.GetResourceTimeline(ctx, "schemas", "app-1", nil)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+		_, err = service.GetResourceTimeline(ctx, "applications", " ", nil)
+		assert.True(t, IsValidationError(err))
+		_, err = service.GetResourceTimeline(ctx, "applications", `app"1`, nil)
+		assert.True(t, IsValidationError(err))
+	})
+}
+
+func TestAuditService_GetAuditLogs(t *testing.T) {
+	service, db := setupTestService(t)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)
+
+	// Logs are inserted directly so that actor types outside the configured enums can be used
+	seed := []v1models.AuditLog{
+		{Timestamp: base, Status: v1models.StatusSuccess, ActorType: v1models.ActorTypeApplication, ActorID: "passport-app",
+			TargetType: "SERVICE", TargetID: stringPtr("orchestration-engine"), EventType: stringPtr("DATA_REQUEST")},
+		{Timestamp: base.Add(time.Minute), Status: v1models.StatusFailure, ActorType: "SERVICE", ActorID: "orchestration-engine",
+			TargetType: "SERVICE", TargetID: stringPtr("drp"), EventType: stringPtr("PROVIDER_FETCH"),
+			ResponseMetadata: v1models.JSONBRawMessage(`{"applicationId":"passport-app","schemaId":"schema-1"}`)},
+		{Timestamp: base.Add(2 * time.Minute), Status: v1models.StatusSuccess, ActorType: "ADMIN", ActorID: "admin@example.com",
+			TargetType: "RESOURCE", TargetID: stringPtr("schema-1"), EventType: stringPtr("MANAGEMENT_EVENT"), EventAction: stringPtr("UPDATE")},
+		{Timestamp: base.Add(3 * time.Minute), Status: v1models.StatusSuccess, ActorType: v1models.ActorTypeApplication, ActorID: "tax-app",
+			TargetType: "SERVICE", TargetID: stringPtr("orchestration-engine"), EventType: stringPtr("DATA_REQUEST")},
+	}
+	for i := range seed {
+		require.NoError(t, db.Create(&seed[i]).Error)
+	}
+
+	t.Run("Filters", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			req      v1models.GetAuditLogsRequest
+			expected []string // actor IDs, newest first
+		}{
+			{"No filters", v1models.GetAuditLogsRequest{}, []string{"tax-app", "admin@example.com", "orchestration-engine", "passport-app"}},
+			{"Status", v1models.GetAuditLogsRequest{Status: v1models.StatusFailure}, []string{"orchestration-engine"}},
+			{"Actor", v1models.GetAuditLogsRequest{ActorType: "ADMIN", ActorID: "admin@example.com"}, []string{"admin@example.com"}},
+			{"Target type", v1models.GetAuditLogsRequest{TargetType: "RESOURCE"}, []string{"admin@example.com"}},
+			{"Consumer app as actor or in metadata", v1models.GetAuditLogsRequest{ConsumerAppID: "passport-app"}, []string{"orchestration-engine", "passport-app"}},
+			{"Entity as actor, target or consumer", v1models.GetAuditLogsRequest{EntityIDs: []string{"passport-app", "schema-1"}},
+				[]string{"admin@example.com", "orchestration-engine", "passport-app"}},
+			{"Empty entity scope", v1models.GetAuditLogsRequest{EntityIDs: []string{}}, []string{}},
+			{"Time range", v1models.GetAuditLogsRequest{
+				StartTime: base.Add(time.Minute).Format(time.RFC3339),
+				EndTime:   base.Add(3 * time.Minute).Format(time.RFC3339),
+			}, []string{"admin@example.com", "orchestration-engine"}},
+			{"Search is case-insensitive", v1models.GetAuditLogsRequest{Search: "Schema-1"}, []string{"admin@example.com"}},
+			{"Search treats wildcards literally", v1models.GetAuditLogsRequest{Search: "%"}, []string{}},
+			{"Ascending sort", v1models.GetAuditLogsRequest{SortOrder: "asc", EventType: "DATA_REQUEST"}, []string{"passport-app", "tax-app"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				response, err := service.GetAuditLogs(ctx, &tt.req)
+				require.NoError(t, err)
+
+				actorIDs := make([]string, 0, len(response.Logs))
+				for _, log := range response.Logs {
+					actorIDs = append(actorIDs, log.ActorID)
+				}
+				assert.Equal(t, tt.expected, actorIDs)
+				assert.Equal(t, int64(len(tt.expected)), response.Total)
+			})
+		}
+	})
+
+	t.Run("CursorPagination", func(t *testing.T) {
+		// Two logs sharing a timestamp must not be skipped or repeated across pages
+		require.NoError(t, db.Create(&v1models.AuditLog{Timestamp: base.Add(3 * time.Minute), Status: v1models.StatusSuccess,
+			ActorType: "SYSTEM", ActorID: "scheduler", TargetType: "SERVICE"}).Error)
+
+		seen := []string{}
+		req := v1models.GetAuditLogsRequest{Limit: 2}
+		for page := 0; page < 5; page++ {
+			response, err := service.GetAuditLogs(ctx, &req)
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), response.Total)
+			for _, log := range response.Logs {
+				seen = append(seen, log.ID.String())
+			}
+			if response.NextCursor == nil {
+				break
+			}
+			req.Cursor = *response.NextCursor
+		}
+
+		assert.Len(t, seen, 5)
+		unique := map[string]struct{}{}
+		for _, id := range seen {
+			unique[id] = struct{}{}
+		}
+		assert.Len(t, unique, 5, "pages should not repeat logs")
+	})
+
+	t.Run("InvalidParameters", func(t *testing.T) {
+		invalid := []v1models.GetAuditLogsRequest{
+			{Status: "PARTIAL"},
+			{StartTime: "yesterday"},
+			{StartTime: base.Format(time.RFC3339), EndTime: base.Add(-time.Hour).Format(time.RFC3339)},
+			{SortOrder: "sideways"},
+			{Cursor: "not-a-cursor"},
+			{Cursor: encodeAuditLogCursor(seed[0]), Offset: 10},
+		}
+		for _, req := range invalid {
+			_, err := service.GetAuditLogs(ctx, &req)
+			assert.True(t, IsValidationError(err), "expected validation error for %+v, got %v", req, err)
+		}
+	})
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// minErasureIdentifierLength is the shortest identifier replaced in log content; shorter values
+	// would match unrelated text
+	minErasureIdentifierLength = 4
+
+	// maxErasureReferenceLength matches the reference column size
+	maxErasureReferenceLength = 255
+
+	// maxErasuresListed caps how many erasures ListErasures returns
+	maxErasuresListed = 100
+
+	// erasureBatchSize is how many logs are read from the database per query
+	erasureBatchSize = 500
+
+	// erasureTokenPrefix marks the values that replaced a data owner's identifiers
+	erasureTokenPrefix = "anon_"
+
+	// Erasures are themselves recorded as audit events
+	erasureEventType = "AUDIT_ERASURE"
+	erasureTargetID  = "audit_logs"
+)
+
+// ErrErasureNotFound is returned when an erasure does not exist
+var ErrErasureNotFound = errors.New("erasure not found")
+
+// ErasureService erases data subjects from the audit logs. Their identifiers are replaced with tokens
+// derived from a random key that is discarded once the erasure is done, so the tokens cannot be traced
+// back to the owner. Everything else is kept, so statistics over the logs stay the same.
+type ErasureService struct {
+	repo     database.AuditRepository
+	erasures database.ErasureRepository
+}
+
+// NewErasureService creates a new erasure service
+func NewErasureService(repo database.AuditRepository, erasures database.ErasureRepository) *ErasureService {
+	return &ErasureService{repo: repo, erasures: erasures}
+}
+
+// EraseSubject pseudonymizes every log of a data owner: the logs recording the owner in their request
+// metadata or as actor or target, and the other logs of their traces. Every identifier of the owner
+// found in those logs is replaced, in the actor, target and metadata.
+//
+// Chained logs keep their Hash, which the next log and any checkpoint refer to; the hash of their
+// rewritten content is stored as PseudonymizedHash, which chain verification checks instead.
+// The erasure is recorded, without the owner's identifiers, both as an erasure and as an audit event.
+func (s *ErasureService) EraseSubject(ctx context.Context, req *v1models.CreateErasureRequest) (*v1models.ErasureResponse, error) {
+	ownerID := strings.TrimSpace(req.OwnerID)
+	if ownerID == "" {
+		return nil, fmt.Errorf("%w: ownerId is required", ErrInvalidInput)
+	}
+	if len(ownerID) < minErasureIdentifierLength || len(ownerID) > maxOwnerIDLength {
+		return nil, fmt.Errorf("%w: ownerId must be between %d and %d characters", ErrInvalidInput, minErasureIdentifierLength, maxOwnerIDLength)
+	}
+	var reference *string
+	if req.Reference != nil {
+		if trimmed := strings.TrimSpace(*req.Reference); trimmed != "" {
+			if len(trimmed) > maxErasureReferenceLength {
+				return nil, fmt.Errorf("%w: reference exceeds %d characters", ErrInvalidInput, maxErasureReferenceLength)
+			}
+			reference = &trimmed
+		}
+	}
+	actorType, actorID := exportActor(req.ActorType, req.ActorID)
+	if enums := v1models.GetEnumConfig(); enums != nil && !enums.IsValidActorType(actorType) {
+		return nil, fmt.Errorf("%w: invalid actorType: %s", ErrInvalidInput, actorType)
+	}
+
+	tokens, err := newErasureTokens()
+	if err != nil {
+		return nil, err
+	}
+	erasure, err := s.erasures.CreateErasure(ctx, &v1models.Erasure{
+		ID:           uuid.New(),
+		Status:       v1models.ErasureStatusRunning,
+		Reference:    reference,
+		SubjectToken: tokens.token(ownerID),
+		ActorType:    actorType,
+		ActorID:      actorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.erase(ctx, erasure, tokens, ownerID)
+
+	completedAt := time.Now().UTC()
+	erasure.CompletedAt = &completedAt
+	if err != nil {
+		message := err.Error()
+		erasure.Status = v1models.ErasureStatusFailed
+		erasure.Error = &message
+		slog.Error("Erasure failed", "erasureId", erasure.ID, "error", err)
+	} else {
+		erasure.Status = v1models.ErasureStatusCompleted
+		slog.Info("Erasure completed", "erasureId", erasure.ID, "pseudonymized", erasure.PseudonymizedCount)
+	}
+	if updateErr := s.erasures.UpdateErasure(ctx, erasure); updateErr != nil {
+		slog.Error("Failed to record erasure result", "erasureId", erasure.ID, "error", updateErr)
+	}
+	s.recordErasureEvent(ctx, erasure)
+	if err != nil {
+		return nil, fmt.Errorf("erasure %s failed: %w", erasure.ID, err)
+	}
+
+	response := v1models.ToErasureResponse(*erasure)
+	return &response, nil
+}
+
+// erase finds and pseudonymizes the owner's logs, recording the count and digest on the erasure
+func (s *ErasureService) erase(ctx context.Context, erasure *v1models.Erasure, tokens *erasureTokens, ownerID string) error {
+	logs, identifiers, err := s.findSubjectLogs(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	// Replace longer identifiers first, so an identifier containing another is replaced whole
+	sort.Slice(identifiers, func(i, j int) bool {
+		return len(identifiers[i]) > len(identifiers[j])
+	})
+	replacements := make([]string, 0, 2*len(identifiers))
+	for _, identifier := range identifiers {
+		replacements = append(replacements, identifier, tokens.token(identifier))
+	}
+	p := &pseudonymizer{identifiers: identifiers, tokens: tokens, replacer: strings.NewReplacer(replacements...)}
+
+	pseudonymized := make([]v1models.AuditLog, 0, len(logs))
+	for i := range logs {
+		log := &logs[i]
+		changed, err := p.pseudonymize(log)
+		if err != nil {
+			return fmt.Errorf("failed to pseudonymize audit log %s: %w", log.ID, err)
+		}
+		if !changed {
+			continue
+		}
+		log.ErasureID = &erasure.ID
+		if log.Sequence != nil {
+			if log.PseudonymizedHash, err = log.ComputeChainHash(); err != nil {
+				return fmt.Errorf("failed to hash pseudonymized audit log %s: %w", log.ID, err)
+			}
+		}
+		pseudonymized = append(pseudonymized, *log)
+	}
+
+	count, err := s.erasures.PseudonymizeAuditLogs(ctx, pseudonymized)
+	if err != nil {
+		return err
+	}
+	erasure.PseudonymizedCount = count
+	erasure.Digest = erasureDigest(pseudonymized)
+	return nil
+}
+
+// findSubjectLogs returns the logs to pseudonymize and the identifiers of the owner to replace in them.
+// The ownerId and ownerEmail values recorded next to a known identifier are the same owner's,
+// so they are searched for as well.
+func (s *ErasureService) findSubjectLogs(ctx context.Context, ownerID string) ([]v1models.AuditLog, []string, error) {
+	logsByID := make(map[uuid.UUID]v1models.AuditLog)
+	traces := make(map[string]bool)
+	collect := func(batch []v1models.AuditLog) error {
+		for _, log := range batch {
+			logsByID[log.ID] = log
+			if log.TraceID != nil {
+				traces[log.TraceID.String()] = true
+			}
+		}
+		return nil
+	}
+
+	identifiers := []string{ownerID}
+	known := map[string]bool{ownerID: true}
+	for i := 0; i < len(identifiers); i++ {
+		identifier := identifiers[i]
+		for _, filters := range []*database.AuditLogFilters{
+			{OwnerID: &identifier, SortAscending: true},
+			{ActorID: &identifier, SortAscending: true},
+			{TargetID: &identifier, SortAscending: true},
+		} {
+			if err := s.repo.StreamAuditLogs(ctx, filters, erasureBatchSize, collect); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, log := range logsByID {
+			for _, value := range ownerIdentifiers(&log) {
+				if !known[value] && len(value) >= minErasureIdentifierLength {
+					known[value] = true
+					identifiers = append(identifiers, value)
+				}
+			}
+		}
+	}
+
+	// The other logs of the owner's traces, such as provider fetches, may carry the owner's identifiers too
+	traceIDs := make([]string, 0, len(traces))
+	for traceID := range traces {
+		traceIDs = append(traceIDs, traceID)
+	}
+	sort.Strings(traceIDs)
+	for _, traceID := range traceIDs {
+		trace, err := s.repo.GetAuditLogsByTraceID(ctx, traceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := collect(trace); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	logs := make([]v1models.AuditLog, 0, len(logsByID))
+	for _, log := range logsByID {
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].ID.String() < logs[j].ID.String()
+	})
+	return logs, identifiers, nil
+}
+
+// ownerIdentifiers returns the ownerId and ownerEmail values recorded in a log's request metadata
+func ownerIdentifiers(log *v1models.AuditLog) []string {
+	if len(log.RequestMetadata) == 0 {
+		return nil
+	}
+	var metadata struct {
+		OwnerID    interface{} `json:"ownerId"`
+		OwnerEmail interface{} `json:"ownerEmail"`
+	}
+	if err := json.Unmarshal(log.RequestMetadata, &metadata); err != nil {
+		return nil
+	}
+	var identifiers []string
+	for _, value := range []interface{}{metadata.OwnerID, metadata.OwnerEmail} {
+		if s, ok := value.(string); ok && strings.TrimSpace(s) != "" {
+			identifiers = append(identifiers, strings.TrimSpace(s))
+		}
+	}
+	return identifiers
+}
+
+// GetErasure returns an erasure by ID
+func (s *ErasureService) GetErasure(ctx context.Context, id string) (*v1models.ErasureResponse, error) {
+	erasureID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid erasure ID: %w", ErrInvalidInput, err)
+	}
+	erasure, err := s.erasures.GetErasure(ctx, erasureID)
+	if err != nil {
+		if errors.Is(err, database.ErrErasureNotFound) {
+			return nil, ErrErasureNotFound
+		}
+		return nil, err
+	}
+	response := v1models.ToErasureResponse(*erasure)
+	return &response, nil
+}
+
+// ListErasures returns the most recent erasures, newest first
+func (s *ErasureService) ListErasures(ctx context.Context, limit int) (*v1models.ListErasuresResponse, error) {
+	if limit <= 0 || limit > maxErasuresListed {
+		limit = maxErasuresListed
+	}
+	erasures, err := s.erasures.ListErasures(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	response := &v1models.ListErasuresResponse{Erasures: make([]v1models.ErasureResponse, 0, len(erasures))}
+	for _, erasure := range erasures {
+		response.Erasures = append(response.Erasures, v1models.ToErasureResponse(erasure))
+	}
+	return response, nil
+}
+
+// recordErasureEvent records the outcome of an erasure as an audit event.
+// The event identifies the owner only by the erasure's subject token.
+func (s *ErasureService) recordErasureEvent(ctx context.Context, erasure *v1models.Erasure) {
+	metadata := map[string]interface{}{
+		"erasureId":          erasure.ID,
+		"subjectToken":       erasure.SubjectToken,
+		"pseudonymizedCount": erasure.PseudonymizedCount,
+		"digest":             erasure.Digest,
+	}
+	if erasure.Reference != nil {
+		metadata["reference"] = *erasure.Reference
+	}
+	status := v1models.StatusSuccess
+	if erasure.Error != nil {
+		status = v1models.StatusFailure
+		metadata["error"] = *erasure.Error
+	}
+	requestMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		slog.Error("Failed to marshal erasure event metadata", "erasureId", erasure.ID, "error", err)
+		return
+	}
+
+	eventType := erasureEventType
+	eventAction := "UPDATE"
+	targetID := erasureTargetID
+	event := &v1models.AuditLog{
+		Timestamp:       time.Now().UTC(),
+		Status:          status,
+		EventType:       &eventType,
+		EventAction:     &eventAction,
+		ActorType:       erasure.ActorType,
+		ActorID:         erasure.ActorID,
+		TargetType:      "RESOURCE",
+		TargetID:        &targetID,
+		RequestMetadata: v1models.JSONBRawMessage(requestMetadata),
+	}
+	if _, err := s.repo.CreateAuditLog(ctx, event); err != nil {
+		slog.Error("Failed to record erasure audit event", "erasureId", erasure.ID, "error", err)
+	}
+}
+
+// erasureDigest hashes the IDs, chain positions and hashes of the pseudonymized logs in ID order
+func erasureDigest(logs []v1models.AuditLog) string {
+	lines := make([]string, 0, len(logs))
+	for _, log := range logs {
+		sequence := ""
+		if log.Sequence != nil {
+			sequence = strconv.FormatInt(*log.Sequence, 10)
+		}
+		lines = append(lines, log.ID.String()+":"+sequence+":"+log.Hash+":"+log.PseudonymizedHash)
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// erasureTokens derives the tokens of one erasure. The key is random and never stored, so the same
+// identifier gets the same token throughout the erasure, but tokens cannot be linked back to it.
+type erasureTokens struct {
+	key []byte
+}
+
+func newErasureTokens() (*erasureTokens, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate erasure key: %w", err)
+	}
+	return &erasureTokens{key: key}, nil
+}
+
+func (t *erasureTokens) token(identifier string) string {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(identifier))
+	return erasureTokenPrefix + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// pseudonymizer replaces a data owner's identifiers in audit logs
+type pseudonymizer struct {
+	identifiers []string
+	tokens      *erasureTokens
+	replacer    *strings.Replacer
+}
+
+// pseudonymize replaces the identifiers in a log's actor, target and metadata, reporting whether anything changed
+func (p *pseudonymizer) pseudonymize(log *v1models.AuditLog) (bool, error) {
+	changed := false
+	if actorID := p.replacer.Replace(log.ActorID); actorID != log.ActorID {
+		log.ActorID = actorID
+		changed = true
+	}
+	if log.TargetID != nil {
+		if targetID := p.replacer.Replace(*log.TargetID); targetID != *log.TargetID {
+			log.TargetID = &targetID
+			changed = true
+		}
+	}
+	for _, metadata := range []*v1models.JSONBRawMessage{&log.RequestMetadata, &log.ResponseMetadata, &log.AdditionalMetadata} {
+		rewritten, ok, err := p.metadata(*metadata)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			*metadata = rewritten
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// metadata replaces the identifiers in the string values of a JSON document. Numbers keep their original
+// text unless they are themselves an identifier; documents without identifiers are left untouched.
+func (p *pseudonymizer) metadata(raw v1models.JSONBRawMessage) (v1models.JSONBRawMessage, bool, error) {
+	if len(raw) == 0 {
+		return raw, false, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false, fmt.Errorf("invalid metadata: %w", err)
+	}
+	value, changed := p.value(value)
+	if !changed {
+		return raw, false, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return v1models.JSONBRawMessage(encoded), true, nil
+}
+
+func (p *pseudonymizer) value(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		replaced := p.replacer.Replace(v)
+		return replaced, replaced != v
+	case json.Number:
+		for _, identifier := range p.identifiers {
+			if string(v) == identifier {
+				return p.tokens.token(identifier), true
+			}
+		}
+	case map[string]interface{}:
+		changed := false
+		for key, item := range v {
+			if replaced, ok := p.value(item); ok {
+				v[key] = replaced
+				changed = true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, item := range v {
+			if replaced, ok := p.value(item); ok {
+				v[i] = replaced
+				changed = true
+			}
+		}
+		return v, changed
+	}
+	return value, false
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErasureService_EraseSubject(t *testing.T) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+	service := NewErasureService(repo, repo)
+	integrity := NewIntegrityService(repo, nil)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	create := func(traceID *uuid.UUID, timestamp time.Time, eventType, actorID, request, response string) {
+		log := &v1models.AuditLog{
+			TraceID:    traceID,
+			Timestamp:  timestamp,
+			EventType:  stringPtr(eventType),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    actorID,
+			TargetType: "SERVICE",
+		}
+		if request != "" {
+			log.RequestMetadata = v1models.JSONBRawMessage(request)
+		}
+		if response != "" {
+			log.ResponseMetadata = v1models.JSONBRawMessage(response)
+		}
+		_, err := repo.CreateAuditLog(ctx, log)
+		require.NoError(t, err)
+	}
+
+	// The owner's exchange, recorded by NIC, with their email in the consent check
+	trace := uuid.New()
+	create(&trace, base, "CONSENT_CHECK", "orchestration-engine",
+		`{"applicationId":"app-1","ownerId":"199012345678","ownerEmail":"citizen@example.com"}`,
+		`{"consentId":"consent-1","status":"approved"}`)
+	create(&trace, base.Add(time.Second), "PROVIDER_FETCH", "orchestration-engine",
+		`{"variables":{"nic":"199012345678"},"count":3}`, `{"serviceKey":"drp"}`)
+	// A later action the owner took in the portal, recorded by email only
+	create(nil, base.Add(time.Hour), "CONSENT_UPDATE", "citizen@example.com", `{"consentId":"consent-1"}`, "")
+	// Another owner's exchange
+	other := uuid.New()
+	create(&other, base, "CONSENT_CHECK", "orchestration-engine", `{"ownerId":"198811111111"}`, "")
+
+	erasure, err := service.EraseSubject(ctx, &v1models.CreateErasureRequest{
+		OwnerID:   " 199012345678 ",
+		Reference: stringPtr("DSR-42"),
+		ActorType: "ADMIN",
+		ActorID:   "dpo@example.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, v1models.ErasureStatusCompleted, erasure.Status)
+	assert.Equal(t, int64(3), erasure.PseudonymizedCount)
+	assert.True(t, strings.HasPrefix(erasure.SubjectToken, erasureTokenPrefix))
+	assert.Len(t, erasure.Digest, 64)
+	assert.NotNil(t, erasure.CompletedAt)
+
+	t.Run("identifiers are replaced with tokens", func(t *testing.T) {
+		logs, _, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{SortAscending: true})
+		require.NoError(t, err)
+		for _, log := range logs {
+			content := string(log.RequestMetadata) + string(log.ResponseMetadata) + log.ActorID
+			assert.NotContains(t, content, "199012345678")
+			assert.NotContains(t, content, "citizen@example.com")
+		}
+
+		owned, _, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{OwnerID: &erasure.SubjectToken})
+		require.NoError(t, err)
+		require.Len(t, owned, 1)
+		assert.Equal(t, erasure.ID, *owned[0].ErasureID)
+		assert.Contains(t, string(owned[0].RequestMetadata), `"applicationId":"app-1"`)
+
+		// The email found next to the owner ID was erased as well, with a token of its own
+		_, total, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{ActorID: stringPtr("citizen@example.com")})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		updates, _, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{EventType: stringPtr("CONSENT_UPDATE")})
+		require.NoError(t, err)
+		require.Len(t, updates, 1)
+		assert.True(t, strings.HasPrefix(updates[0].ActorID, erasureTokenPrefix))
+		assert.NotEqual(t, erasure.SubjectToken, updates[0].ActorID)
+
+		// The log moved out of the partition of the owner's email with its actor
+		_, total, err = repo.GetAuditLogs(ctx, &database.AuditLogFilters{EntityIDs: []string{"citizen@example.com"}})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		_, total, err = repo.GetAuditLogs(ctx, &database.AuditLogFilters{EntityIDs: []string{updates[0].ActorID}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+
+		fetches, _, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{EventType: stringPtr("PROVIDER_FETCH")})
+		require.NoError(t, err)
+		require.Len(t, fetches, 1)
+		assert.Contains(t, string(fetches[0].RequestMetadata), `"nic":"`+erasure.SubjectToken+`"`)
+		assert.Contains(t, string(fetches[0].RequestMetadata), `"count":3`)
+	})
+
+	t.Run("other owners are untouched", func(t *testing.T) {
+		logs, _, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{OwnerID: stringPtr("198811111111")})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Nil(t, logs[0].ErasureID)
+	})
+
+	t.Run("chain still verifies", func(t *testing.T) {
+		result, err := integrity.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "%+v", result.Failure)
+		assert.Equal(t, int64(3), result.PseudonymizedCount)
+	})
+
+	t.Run("modifying a pseudonymized log is detected", func(t *testing.T) {
+		require.NoError(t, db.Model(&v1models.AuditLog{}).Where("erasure_id = ?", erasure.ID).
+			Update("actor_id", "someone-else").Error)
+		result, err := integrity.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("erasure is recorded without the owner ID", func(t *testing.T) {
+		events, _, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{EventType: stringPtr(erasureEventType)})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "dpo@example.com", events[0].ActorID)
+		assert.Contains(t, string(events[0].RequestMetadata), erasure.ID.String())
+		assert.NotContains(t, string(events[0].RequestMetadata), "199012345678")
+
+		stored, err := service.GetErasure(ctx, erasure.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "DSR-42", *stored.Reference)
+		assert.Equal(t, erasure.Digest, stored.Digest)
+
+		list, err := service.ListErasures(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, list.Erasures, 1)
+	})
+}
+
+func TestErasureService_Validation(t *testing.T) {
+	repo := database.NewGormRepository(setupSQLiteTestDB(t))
+	service := NewErasureService(repo, repo)
+	ctx := context.Background()
+
+	for name, req := range map[string]*v1models.CreateErasureRequest{
+		"missing owner":  {OwnerID: "  "},
+		"short owner":    {OwnerID: "abc"},
+		"long owner":     {OwnerID: strings.Repeat("a", maxOwnerIDLength+1)},
+		"long reference": {OwnerID: "199012345678", Reference: stringPtr(strings.Repeat("r", maxErasureReferenceLength+1))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.EraseSubject(ctx, req)
+			assert.True(t, IsValidationError(err), "%v", err)
+		})
+	}
+
+	_, err := service.GetErasure(ctx, "not-a-uuid")
+	assert.True(t, IsValidationError(err))
+	_, err = service.GetErasure(ctx, uuid.New().String())
+	assert.ErrorIs(t, err, ErrErasureNotFound)
+}
@@ -1,6 +1,12 @@
 package services
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/schemas"
+)
 
 // ErrValidation represents a validation error in the domain layer
 // This is a domain-specific error that abstracts away database implementation details
@@ -9,6 +15,30 @@ var ErrValidation = errors.New("validation error")
 // ErrInvalidInput represents an input validation error
 var ErrInvalidInput = errors.New("invalid input")
 
+// ErrTraceNotFound is returned when no audit logs were recorded for a trace
+var ErrTraceNotFound = errors.New("trace not found")
+
+// ErrResourceNotFound is returned when no management events were recorded for a resource
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrDuplicateEvent is returned when an event with the same eventId has already been stored
+var ErrDuplicateEvent = errors.New("duplicate event")
+
+// EventSchemaError is returned, wrapped in ErrValidation, when an event does not match its event type's schema
+type EventSchemaError struct {
+	EventType string
+	Version   int
+	Fields    []schemas.FieldError
+}
+
+func (e *EventSchemaError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field.String()
+	}
+	return fmt.Sprintf("%s event does not match schema version %d: %s", e.EventType, e.Version, strings.Join(fields, "; "))
+}
+
 // IsValidationError checks if an error is a validation error or invalid input
 func IsValidationError(err error) bool {
 	return errors.Is(err, ErrValidation) || errors.Is(err, ErrInvalidInput)
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"encoding/json"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+// exchangeMetadata holds the metadata keys the orchestration engine records on data exchange events
+// (DATA_REQUEST, POLICY_CHECK, CONSENT_CHECK and PROVIDER_FETCH) that statistics, reports and alerts use
+type exchangeMetadata struct {
+	ApplicationID string `json:"applicationId"`
+
+	// Consent checks: the consent record and its status (e.g. approved, rejected) in the response
+	ConsentID string `json:"consentId"`
+	Status    string `json:"status"`
+
+	// Policy checks list the query's requiredFields in the request;
+	// provider fetches list the requestedFields sent to the provider (serviceKey) in the response
+	RequiredFields  fieldNames `json:"requiredFields"`
+	ServiceKey      string     `json:"serviceKey"`
+	RequestedFields fieldNames `json:"requestedFields"`
+}
+
+// fieldNames decodes a list of field names given either as strings or, as the orchestration engine
+// records policy check fields, as objects with a fieldName (see the POLICY_CHECK event schema)
+type fieldNames []string
+
+func (f *fieldNames) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	names := make(fieldNames, 0, len(items))
+	for _, item := range items {
+		var name string
+		if err := json.Unmarshal(item, &name); err != nil {
+			var field struct {
+				FieldName string `json:"fieldName"`
+			}
+			if err := json.Unmarshal(item, &field); err != nil {
+				return err
+			}
+			name = field.FieldName
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	*f = names
+	return nil
+}
+
+// parseExchangeMetadata decodes a log's request and response metadata.
+// Metadata is free-form; keys that are missing or have another shape are left empty.
+func parseExchangeMetadata(log *v1models.AuditLog) (request, response exchangeMetadata) {
+	_ = json.Unmarshal(log.RequestMetadata, &request)
+	_ = json.Unmarshal(log.ResponseMetadata, &response)
+	return request, response
+}
+
+// exchangeConsumer returns the consumer application a log concerns: the actor if it is an application,
+// otherwise the applicationId recorded in the request or response metadata
+func exchangeConsumer(log *v1models.AuditLog, request, response exchangeMetadata) string {
+	switch {
+	case log.ActorType == v1models.ActorTypeApplication:
+		return log.ActorID
+	case request.ApplicationID != "":
+		return request.ApplicationID
+	default:
+		return response.ApplicationID
+	}
+}
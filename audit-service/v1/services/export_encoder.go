@@ -0,0 +1,140 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+// ExportFormat is a file format audit logs can be exported to
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// ParseExportFormat validates an export format name (case-insensitive)
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(format)) {
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	case ExportFormatNDJSON:
+		return ExportFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: invalid format: %q (must be csv or ndjson)", ErrInvalidInput, format)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// exportCSVHeader lists the CSV columns, in the order written by csvEncoder
+var exportCSVHeader = []string{
+	"id", "timestamp", "traceId", "eventType", "eventAction", "status",
+	"actorType", "actorId", "targetType", "targetId",
+	"requestMetadata", "responseMetadata", "additionalMetadata", "createdAt",
+	"sequence", "previousHash", "hash", "eventId", "source", "spanId", "correlationId",
+}
+
+// exportEncoder writes audit logs in an export format
+// Output is buffered until Flush, so callers control how often the underlying writer is hit
+type exportEncoder interface {
+	Encode(log v1models.AuditLog) error
+	Flush() error
+}
+
+// newExportEncoder returns an encoder for the format writing to w
+// For CSV, the header row is written immediately
+func newExportEncoder(format ExportFormat, w io.Writer) (exportEncoder, error) {
+	buffered := bufio.NewWriter(w)
+	if format == ExportFormatCSV {
+		encoder := &csvEncoder{buffered: buffered, writer: csv.NewWriter(buffered)}
+		if err := encoder.writer.Write(exportCSVHeader); err != nil {
+			return nil, err
+		}
+		return encoder, nil
+	}
+	return &ndjsonEncoder{buffered: buffered, encoder: json.NewEncoder(buffered)}, nil
+}
+
+// csvEncoder writes one row per log; metadata columns hold the raw JSON
+type csvEncoder struct {
+	buffered *bufio.Writer
+	writer   *csv.Writer
+}
+
+func (e *csvEncoder) Encode(log v1models.AuditLog) error {
+	traceID := ""
+	if log.TraceID != nil {
+		traceID = log.TraceID.String()
+	}
+	sequence := ""
+	if log.Sequence != nil {
+		sequence = strconv.FormatInt(*log.Sequence, 10)
+	}
+	return e.writer.Write([]string{
+		log.ID.String(),
+		log.Timestamp.UTC().Format(time.RFC3339Nano),
+		traceID,
+		derefString(log.EventType),
+		derefString(log.EventAction),
+		log.Status,
+		log.ActorType,
+		log.ActorID,
+		log.TargetType,
+		derefString(log.TargetID),
+		string(log.RequestMetadata),
+		string(log.ResponseMetadata),
+		string(log.AdditionalMetadata),
+		log.CreatedAt.UTC().Format(time.RFC3339Nano),
+		sequence,
+		log.PreviousHash,
+		log.Hash,
+		derefString(log.EventID),
+		log.Source,
+		derefString(log.SpanID),
+		derefString(log.CorrelationID),
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return err
+	}
+	return e.buffered.Flush()
+}
+
+// ndjsonEncoder writes one AuditLogResponse JSON object per line
+type ndjsonEncoder struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(log v1models.AuditLog) error {
+	return e.encoder.Encode(v1models.ToAuditLogResponse(log))
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return e.buffered.Flush()
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// DefaultMaxSyncExportRows caps how many logs a synchronous export may stream;
+	// larger exports must run as asynchronous jobs
+	DefaultMaxSyncExportRows = 100000
+
+	// exportBatchSize is how many logs are read from the database and written per flush
+	exportBatchSize = 500
+
+	// Export requests are themselves recorded as audit events
+	exportEventType  = "AUDIT_EXPORT"
+	exportTargetID   = "audit_logs"
+	defaultActorType = "SERVICE"
+	defaultActorID   = "unknown"
+)
+
+var (
+	// ErrExportTooLarge is returned when a synchronous export matches more logs than allowed
+	ErrExportTooLarge = errors.New("too many audit logs for a synchronous export, use an asynchronous export job")
+	// ErrExportJobNotFound is returned when an export job does not exist
+	ErrExportJobNotFound = errors.New("export job not found")
+	// ErrExportJobNotReady is returned when downloading an export job that has not completed
+	ErrExportJobNotReady = errors.New("export job has not completed")
+)
+
+// ExportService streams audit logs to CSV or NDJSON, either directly to the caller
+// or through asynchronous jobs that write the export to a file for later download
+type ExportService struct {
+	repo        database.AuditRepository
+	jobs        database.ExportJobRepository
+	exportDir   string
+	maxSyncRows int64
+
+	// running tracks in-flight export jobs
+	running sync.WaitGroup
+}
+
+// NewExportService creates a new export service writing job output to exportDir
+func NewExportService(repo database.AuditRepository, jobs database.ExportJobRepository, exportDir string, maxSyncRows int64) *ExportService {
+	if maxSyncRows <= 0 {
+		maxSyncRows = DefaultMaxSyncExportRows
+	}
+	return &ExportService{
+		repo:        repo,
+		jobs:        jobs,
+		exportDir:   exportDir,
+		maxSyncRows: maxSyncRows,
+	}
+}
+
+// Export is a validated synchronous export, ready to be written with WriteExport
+type Export struct {
+	Format ExportFormat
+	// Total is the number of logs matching the export's filters
+	Total int64
+
+	filters *database.AuditLogFilters
+}
+
+// PrepareExport validates a synchronous export and records it as an audit event.
+// Validation happens before anything is written, so the caller can still respond with an error status.
+func (s *ExportService) PrepareExport(ctx context.Context, req *v1models.ExportAuditLogsRequest) (*Export, error) {
+	format, err := ParseExportFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := buildExportFilters(&req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	countFilters := *filters
+	countFilters.Limit = 1
+	_, total, err := s.repo.GetAuditLogs(ctx, &countFilters)
+	if err != nil {
+		return nil, err
+	}
+	if total > s.maxSyncRows {
+		return nil, fmt.Errorf("%w: %d logs match, the limit is %d", ErrExportTooLarge, total, s.maxSyncRows)
+	}
+
+	err = s.recordExportEvent(ctx, req.ActorType, req.ActorID, exportTargetID, map[string]interface{}{
+		"mode":   "sync",
+		"format": format,
+		"query":  req.Query,
+		"total":  total,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Export{Format: format, Total: total, filters: filters}, nil
+}
+
+// WriteExport streams the export to w and returns the number of logs written.
+// Logs are read in batches and each batch is flushed before the next is read, so a slow reader
+// holds back the database reads instead of the export being buffered in memory.
+// flush, if set, is called after every batch (e.g. to flush an HTTP response).
+func (s *ExportService) WriteExport(ctx context.Context, export *Export, w io.Writer, flush func() error) (int64, error) {
+	encoder, err := newExportEncoder(export.Format, w)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write export header: %w", err)
+	}
+
+	var written int64
+	err = s.repo.StreamAuditLogs(ctx, export.filters, exportBatchSize, func(batch []v1models.AuditLog) error {
+		for _, log := range batch {
+			if err := encoder.Encode(log); err != nil {
+				return fmt.Errorf("failed to encode audit log: %w", err)
+			}
+		}
+		written += int64(len(batch))
+		if err := encoder.Flush(); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		if flush != nil {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+
+	// Flush the CSV header of an empty export
+	if err := encoder.Flush(); err != nil {
+		return written, fmt.Errorf("failed to write export: %w", err)
+	}
+	return written, nil
+}
+
+// CreateExportJob validates an export, records it as an audit event and starts writing it in the background
+func (s *ExportService) CreateExportJob(ctx context.Context, req *v1models.ExportAuditLogsRequest) (*v1models.ExportJobResponse, error) {
+	format, err := ParseExportFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := buildExportFilters(&req.Query)
+	if err != nil {
+		return nil, err
+	}
+	query, err := json.Marshal(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export query: %w", err)
+	}
+
+	actorType, actorID := exportActor(req.ActorType, req.ActorID)
+	job := &v1models.ExportJob{
+		ID:        uuid.New(),
+		Status:    v1models.ExportJobStatusPending,
+		Format:    string(format),
+		Query:     v1models.JSONBRawMessage(query),
+		ActorType: actorType,
+		ActorID:   actorID,
+	}
+
+	// Record the request before creating the job, so no export runs unaudited
+	err = s.recordExportEvent(ctx, actorType, actorID, job.ID.String(), map[string]interface{}{
+		"mode":   "async",
+		"format": format,
+		"query":  req.Query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	job, err = s.jobs.CreateExportJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the response before the job starts updating its state
+	response := v1models.ToExportJobResponse(*job)
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.runExportJob(job, &Export{Format: format, filters: filters})
+	}()
+
+	return &response, nil
+}
+
+// runExportJob writes the job's export to a file and records the outcome on the job.
+// The job outlives the request that created it, so it runs with its own context.
+func (s *ExportService) runExportJob(job *v1models.ExportJob, export *Export) {
+	ctx := context.Background()
+
+	job.Status = v1models.ExportJobStatusRunning
+	if err := s.jobs.UpdateExportJob(ctx, job); err != nil {
+		slog.Error("Failed to mark export job as running", "jobId", job.ID, "error", err)
+	}
+
+	path, rows, size, err := s.writeExportFile(ctx, job.ID, export)
+	completedAt := time.Now().UTC()
+	job.CompletedAt = &completedAt
+	if err != nil {
+		message := err.Error()
+		job.Status = v1models.ExportJobStatusFailed
+		job.Error = &message
+		slog.Error("Export job failed", "jobId", job.ID, "error", err)
+	} else {
+		job.Status = v1models.ExportJobStatusCompleted
+		job.FilePath = path
+		job.RowCount = rows
+		job.FileSize = size
+		slog.Info("Export job completed", "jobId", job.ID, "rows", rows, "bytes", size)
+	}
+
+	if err := s.jobs.UpdateExportJob(ctx, job); err != nil {
+		slog.Error("Failed to record export job result", "jobId", job.ID, "error", err)
+	}
+}
+
+// writeExportFile writes the export to a temporary file and moves it into place once complete,
+// so a partially written file is never served
+func (s *ExportService) writeExportFile(ctx context.Context, jobID uuid.UUID, export *Export) (string, int64, int64, error) {
+	if err := os.MkdirAll(s.exportDir, 0o750); err != nil {
+		return "", 0, 0, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.exportDir, jobID.String()+"-*.tmp")
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	rows, err := s.WriteExport(ctx, export, tmp, nil)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close export file: %w", closeErr)
+	}
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	path := filepath.Join(s.exportDir, jobID.String()+"."+string(export.Format))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, 0, fmt.Errorf("failed to move export file into place: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to stat export file: %w", err)
+	}
+	return path, rows, info.Size(), nil
+}
+
+// GetExportJob retrieves an export job's status
+func (s *ExportService) GetExportJob(ctx context.Context, id string) (*v1models.ExportJobResponse, error) {
+	job, err := s.getExportJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := v1models.ToExportJobResponse(*job)
+	return &response, nil
+}
+
+// OpenExportJobFile opens a completed job's file for download and records the download as an audit event.
+// The caller must close the file.
+func (s *ExportService) OpenExportJobFile(ctx context.Context, id, actorType, actorID string) (*os.File, *v1models.ExportJob, error) {
+	job, err := s.getExportJob(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != v1models.ExportJobStatusCompleted {
+		return nil, nil, fmt.Errorf("%w: status is %s", ErrExportJobNotReady, job.Status)
+	}
+
+	err = s.recordExportEvent(ctx, actorType, actorID, job.ID.String(), map[string]interface{}{
+		"mode":     "download",
+		"format":   job.Format,
+		"rowCount": job.RowCount,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open export file: %w", err)
+	}
+	return file, job, nil
+}
+
+// FailUnfinishedJobs marks jobs left pending or running by a previous process as failed.
+// Jobs run in-process, so they cannot be resumed after a restart.
+func (s *ExportService) FailUnfinishedJobs(ctx context.Context) error {
+	count, err := s.jobs.FailUnfinishedExportJobs(ctx, "interrupted by service restart")
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		slog.Warn("Marked interrupted export jobs as failed", "count", count)
+	}
+	return nil
+}
+
+// Wait blocks until all export jobs started by this service have finished
+func (s *ExportService) Wait() {
+	s.running.Wait()
+}
+
+func (s *ExportService) getExportJob(ctx context.Context, id string) (*v1models.ExportJob, error) {
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid export job ID: %w", ErrInvalidInput, err)
+	}
+	job, err := s.jobs.GetExportJob(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, database.ErrExportJobNotFound) {
+			return nil, ErrExportJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// recordExportEvent writes an AUDIT_EXPORT audit log for an export request or download.
+// Exports fail closed: if the event cannot be recorded, the export is refused.
+func (s *ExportService) recordExportEvent(ctx context.Context, actorType, actorID, targetID string, metadata map[string]interface{}) error {
+	return recordReadEvent(ctx, s.repo, exportEventType, actorType, actorID, targetID, metadata)
+}
+
+// recordReadEvent writes an audit log of eventType for a read of audit data by the given requester,
+// applying defaults for a requester that did not identify itself
+func recordReadEvent(ctx context.Context, repo database.AuditRepository, eventType, actorType, actorID, targetID string, metadata map[string]interface{}) error {
+	actorType, actorID = exportActor(actorType, actorID)
+	if enums := v1models.GetEnumConfig(); enums != nil && !enums.IsValidActorType(actorType) {
+		return fmt.Errorf("%w: invalid actorType: %s", ErrInvalidInput, actorType)
+	}
+
+	requestMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event metadata: %w", eventType, err)
+	}
+
+	eventAction := "READ"
+	event := &v1models.AuditLog{
+		Timestamp:       time.Now().UTC(),
+		Status:          v1models.StatusSuccess,
+		EventType:       &eventType,
+		EventAction:     &eventAction,
+		ActorType:       actorType,
+		ActorID:         actorID,
+		TargetType:      "RESOURCE",
+		TargetID:        &targetID,
+		RequestMetadata: v1models.JSONBRawMessage(requestMetadata),
+	}
+	if _, err := repo.CreateAuditLog(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s audit event: %w", eventType, err)
+	}
+	return nil
+}
+
+// buildExportFilters validates the export query and caps it at the current time,
+// so an export is a snapshot that excludes logs written while it runs, including its own audit event
+func buildExportFilters(query *v1models.GetAuditLogsRequest) (*database.AuditLogFilters, error) {
+	filters, err := buildAuditLogFilters(query)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := time.Now().UTC()
+	if filters.EndTime == nil || filters.EndTime.After(snapshot) {
+		filters.EndTime = &snapshot
+	}
+	return filters, nil
+}
+
+// exportActor applies defaults for a requester that did not identify itself
+func exportActor(actorType, actorID string) (string, string) {
+	if actorType == "" {
+		actorType = defaultActorType
+	}
+	if actorID == "" {
+		actorID = defaultActorID
+	}
+	return actorType, actorID
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupExportTestService creates an export service with SQLite test database and seeded logs
+func setupExportTestService(t *testing.T, maxSyncRows int64) (*ExportService, *database.GormRepository, *gorm.DB) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+	service := NewExportService(repo, repo, t.TempDir(), maxSyncRows)
+
+	base := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)
+	for i, actorID := range []string{"orchestration-engine", "admin@example.com", "consent-engine"} {
+		require.NoError(t, db.Create(&v1models.AuditLog{
+			Timestamp:       base.Add(time.Duration(i) * time.Minute),
+			Status:          v1models.StatusSuccess,
+			ActorType:       "SERVICE",
+			ActorID:         actorID,
+			TargetType:      "SERVICE",
+			RequestMetadata: v1models.JSONBRawMessage(`{"schemaId":"schema-1"}`),
+		}).Error)
+	}
+	return service, repo, db
+}
+
+// exportEvents returns the AUDIT_EXPORT events recorded so far
+func exportEvents(t *testing.T, db *gorm.DB) []v1models.AuditLog {
+	var events []v1models.AuditLog
+	require.NoError(t, db.Where("event_type = ?", exportEventType).Order("timestamp ASC").Find(&events).Error)
+	return events
+}
+
+func TestExportService_SyncExport(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CSV", func(t *testing.T) {
+		service, _, db := setupExportTestService(t, 0)
+
+		export, err := service.PrepareExport(ctx, &v1models.ExportAuditLogsRequest{
+			Query:     v1models.GetAuditLogsRequest{SortOrder: "asc"},
+			Format:    "csv",
+			ActorType: "ADMIN",
+			ActorID:   "admin@example.com",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), export.Total)
+
+		var buf bytes.Buffer
+		flushes := 0
+		written, err := service.WriteExport(ctx, export, &buf, func() error { flushes++; return nil })
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), written)
+		assert.Equal(t, 1, flushes)
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		assert.Equal(t, exportCSVHeader, rows[0])
+		assert.Equal(t, "orchestration-engine", rows[1][7])
+		assert.Equal(t, `{"schemaId":"schema-1"}`, rows[1][10])
+
+		events := exportEvents(t, db)
+		require.Len(t, events, 1)
+		assert.Equal(t, "ADMIN", events[0].ActorType)
+		assert.Equal(t, "admin@example.com", events[0].ActorID)
+		assert.Contains(t, string(events[0].RequestMetadata), `"mode":"sync"`)
+	})
+
+	t.Run("NDJSONWithFilters", func(t *testing.T) {
+		service, _, _ := setupExportTestService(t, 0)
+
+		export, err := service.PrepareExport(ctx, &v1models.ExportAuditLogsRequest{
+			Query:  v1models.GetAuditLogsRequest{ActorID: "consent-engine"},
+			Format: "NDJSON",
+		})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		_, err = service.WriteExport(ctx, export, &buf, nil)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 1)
+		var log v1models.AuditLogResponse
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &log))
+		assert.Equal(t, "consent-engine", log.ActorID)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		service, _, db := setupExportTestService(t, 2)
+
+		_, err := service.PrepareExport(ctx, &v1models.ExportAuditLogsRequest{Format: "csv"})
+		assert.ErrorIs(t, err, ErrExportTooLarge)
+		assert.Empty(t, exportEvents(t, db), "refused exports should not be recorded")
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		service, _, _ := setupExportTestService(t, 0)
+
+		_, err := service.PrepareExport(ctx, &v1models.ExportAuditLogsRequest{Format: "xml"})
+		assert.True(t, IsValidationError(err))
+	})
+}
+
+func TestExportService_ExportJob(t *testing.T) {
+	ctx := context.Background()
+	service, _, db := setupExportTestService(t, 0)
+
+	job, err := service.CreateExportJob(ctx, &v1models.ExportAuditLogsRequest{Format: "ndjson"})
+	require.NoError(t, err)
+	assert.Equal(t, v1models.ExportJobStatusPending, job.Status)
+
+	service.Wait()
+
+	job, err = service.GetExportJob(ctx, job.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, v1models.ExportJobStatusCompleted, job.Status, "job error: %v", job.Error)
+	assert.Equal(t, int64(3), job.RowCount)
+	require.NotNil(t, job.DownloadURL)
+
+	file, stored, err := service.OpenExportJobFile(ctx, job.ID.String(), "ADMIN", "admin@example.com")
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 3)
+	assert.Equal(t, job.FileSize, int64(len(content)))
+	assert.Equal(t, "ndjson", stored.Format)
+
+	// Both the job request and the download are recorded
+	events := exportEvents(t, db)
+	require.Len(t, events, 2)
+	assert.Contains(t, string(events[0].RequestMetadata), `"mode":"async"`)
+	assert.Contains(t, string(events[1].RequestMetadata), `"mode":"download"`)
+	assert.Equal(t, job.ID.String(), *events[1].TargetID)
+
+	_, err = service.GetExportJob(ctx, "00000000-0000-0000-0000-000000000001")
+	assert.ErrorIs(t, err, ErrExportJobNotFound)
+}
+
+func TestExportService_FailUnfinishedJobs(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := setupExportTestService(t, 0)
+
+	job, err := repo.CreateExportJob(ctx, &v1models.ExportJob{
+		Status: v1models.ExportJobStatusRunning, Format: "csv", ActorType: "SERVICE", ActorID: "unknown",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, service.FailUnfinishedJobs(ctx))
+
+	stored, err := service.GetExportJob(ctx, job.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, v1models.ExportJobStatusFailed, stored.Status)
+
+	_, _, err = service.OpenExportJobFile(ctx, job.ID.String(), "", "")
+	assert.ErrorIs(t, err, ErrExportJobNotReady)
+}
+
+func TestGormRepository_StreamAuditLogs(t *testing.T) {
+	_, repo, _ := setupExportTestService(t, 0)
+
+	var batches [][]string
+	err := repo.StreamAuditLogs(context.Background(), &database.AuditLogFilters{SortAscending: true}, 2, func(batch []v1models.AuditLog) error {
+		actorIDs := []string{}
+		for _, log := range batch {
+			actorIDs = append(actorIDs, log.ActorID)
+		}
+		batches = append(batches, actorIDs)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"orchestration-engine", "admin@example.com"}, {"consent-engine"}}, batches)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// queueEvent is a valid audit event payload as published by producers
+const queueEvent = `{"eventId":"%s","timestamp":"2026-01-01T00:00:00Z","status":"SUCCESS","actorType":"SERVICE","actorId":"orchestration-engine","targetType":"SERVICE","targetId":"consent-engine"}`
+
+func setupIngestionTest(t *testing.T, queue QueueConfig) (*IngestionService, *gorm.DB) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+	return NewIngestionService(NewAuditService(repo), repo, queue), db
+}
+
+func queueEventPayload(eventID string) []byte {
+	return []byte(fmt.Sprintf(queueEvent, eventID))
+}
+
+func TestIngestionService_HandleMessage(t *testing.T) {
+	service, db := setupIngestionTest(t, QueueConfig{URL: "nats://localhost:4222"})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		msg  *natsMsg
+		want queueOutcome
+	}{
+		{"new event", &natsMsg{Data: queueEventPayload("event-1")}, queueAck},
+		{"redelivered event", &natsMsg{Data: queueEventPayload("event-1")}, queueAck},
+		{"event ID from message header", &natsMsg{
+			Data:   []byte(`{"timestamp":"2026-01-01T00:00:00Z","status":"SUCCESS","actorType":"SERVICE","actorId":"orchestration-engine","targetType":"SERVICE"}`),
+			Header: map[string]string{natsMsgIDHeader: "event-2"},
+		}, queueAck},
+		{"missing event ID", &natsMsg{Data: []byte(`{"timestamp":"2026-01-01T00:00:00Z","status":"SUCCESS","actorType":"SERVICE","actorId":"a","targetType":"SERVICE"}`)}, queueTerm},
+		{"malformed payload", &natsMsg{Data: []byte(`{not json`)}, queueTerm},
+		{"invalid event", &natsMsg{Data: []byte(`{"eventId":"event-3","status":"SUCCESS"}`)}, queueTerm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, []queueOutcome{tt.want}, service.handleMessages(ctx, []*natsMsg{tt.msg}))
+		})
+	}
+
+	var logs []v1models.AuditLog
+	require.NoError(t, db.Order("sequence").Find(&logs).Error)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "event-1", derefString(logs[0].EventID))
+	assert.Equal(t, "event-2", derefString(logs[1].EventID))
+	assert.Equal(t, v1models.IngestionSourceQueue, logs[0].Source)
+
+	stats := service.consumerStats()
+	require.NotNil(t, stats)
+	assert.Equal(t, int64(6), stats.Received)
+	assert.Equal(t, int64(2), stats.Ingested)
+	assert.Equal(t, int64(1), stats.Duplicates)
+	assert.Equal(t, int64(3), stats.Rejected)
+	assert.Equal(t, int64(0), stats.Failed)
+	assert.NotNil(t, stats.LastMessageAt)
+
+	// Storage failures are retried through redelivery
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	assert.Equal(t, []queueOutcome{queueNak}, service.handleMessages(ctx, []*natsMsg{{Data: queueEventPayload("event-4")}}))
+	assert.Equal(t, int64(1), service.consumerStats().Failed)
+}
+
+func TestIngestionService_HandleMessages_Batch(t *testing.T) {
+	service, db := setupIngestionTest(t, QueueConfig{URL: "nats://localhost:4222"})
+
+	outcomes := service.handleMessages(context.Background(), []*natsMsg{
+		{Data: queueEventPayload("event-1")},
+		{Data: []byte(`{not json`)},
+		{Data: queueEventPayload("event-2")},
+		{Data: queueEventPayload("event-1")},
+		{Data: []byte(`{"eventId":"event-3","status":"SUCCESS"}`)},
+	})
+	assert.Equal(t, []queueOutcome{queueAck, queueTerm, queueAck, queueAck, queueTerm}, outcomes)
+
+	var logs []v1models.AuditLog
+	require.NoError(t, db.Order("sequence").Find(&logs).Error)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "event-1", derefString(logs[0].EventID))
+	assert.Equal(t, "event-2", derefString(logs[1].EventID))
+
+	stats := service.consumerStats()
+	assert.Equal(t, int64(2), stats.Ingested)
+	assert.Equal(t, int64(1), stats.Duplicates)
+	assert.Equal(t, int64(2), stats.Rejected)
+}
+
+func TestIngestionService_StartConsumer(t *testing.T) {
+	server := newFakeJetStream(t)
+	server.Publish("audit.events.orchestration", nil, queueEventPayload("event-1"))
+	server.Publish("audit.events.orchestration", nil, queueEventPayload("event-1"))
+	server.Publish("audit.events.orchestration", nil, []byte(`{"status":"SUCCESS"}`))
+
+	service, db := setupIngestionTest(t, QueueConfig{URL: server.URL("")})
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		service.StartConsumer(ctx)
+		close(stopped)
+	}()
+
+	require.Eventually(t, func() bool { return len(server.Acks()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"+ACK", "+ACK", "+TERM"}, server.Acks())
+	assert.True(t, service.consumerStats().Connected)
+
+	// Messages published while the consumer waits are delivered to the pending pull
+	server.Publish("audit.events.orchestration", nil, queueEventPayload("event-2"))
+	require.Eventually(t, func() bool { return len(server.Acks()) == 4 }, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("consumer did not stop")
+	}
+	assert.False(t, service.consumerStats().Connected)
+
+	var count int64
+	require.NoError(t, db.Model(&v1models.AuditLog{}).Where("source = ?", v1models.IngestionSourceQueue).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestIngestionService_Reconcile(t *testing.T) {
+	service, _ := setupIngestionTest(t, QueueConfig{})
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	create := func(actorID, source string, age time.Duration) {
+		_, err := service.audit.CreateAuditLog(ctx, &v1models.CreateAuditLogRequest{
+			Source:     source,
+			Timestamp:  now.Add(-age).Format(time.RFC3339),
+			Status:     v1models.StatusSuccess,
+			ActorType:  "SERVICE",
+			ActorID:    actorID,
+			TargetType: "SERVICE",
+		})
+		require.NoError(t, err)
+	}
+	create("orchestration-engine", v1models.IngestionSourceHTTP, time.Hour)
+	create("orchestration-engine", v1models.IngestionSourceQueue, time.Hour)
+	create("orchestration-engine", v1models.IngestionSourceQueue, 2*time.Hour)
+	create("consent-engine", v1models.IngestionSourceHTTP, time.Hour)
+	create("audit-service", "", time.Hour)
+	create("consent-engine", v1models.IngestionSourceHTTP, 48*time.Hour) // outside the default window
+
+	t.Run("default window", func(t *testing.T) {
+		result, err := service.Reconcile(ctx, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, v1models.IngestionCounts{HTTP: 2, Queue: 2, Other: 1}, result.Totals)
+		assert.Equal(t, []v1models.ActorIngestionCounts{
+			{ActorID: "audit-service", IngestionCounts: v1models.IngestionCounts{Other: 1}},
+			{ActorID: "consent-engine", IngestionCounts: v1models.IngestionCounts{HTTP: 1}},
+			{ActorID: "orchestration-engine", IngestionCounts: v1models.IngestionCounts{HTTP: 1, Queue: 2}},
+		}, result.ByActor)
+		assert.Nil(t, result.Consumer, "no queue is configured")
+	})
+
+	t.Run("explicit window", func(t *testing.T) {
+		result, err := service.Reconcile(ctx, now.Add(-72*time.Hour).Format(time.RFC3339), now.Add(-90*time.Minute).Format(time.RFC3339))
+		require.NoError(t, err)
+		assert.Equal(t, v1models.IngestionCounts{HTTP: 1, Queue: 1}, result.Totals)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := service.Reconcile(ctx, "yesterday", "")
+		assert.True(t, IsValidationError(err))
+		_, err = service.Reconcile(ctx, now.Format(time.RFC3339), now.Add(-time.Hour).Format(time.RFC3339))
+		assert.True(t, IsValidationError(err))
+	})
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// DefaultCheckpointInterval is how often the chain head is signed when no interval is configured
+	DefaultCheckpointInterval = time.Hour
+
+	// verifyBatchSize is how many chained logs are read per query during verification
+	verifyBatchSize = 1000
+)
+
+var (
+	// ErrCheckpointSigningDisabled is returned when creating a checkpoint without a signing key
+	ErrCheckpointSigningDisabled = errors.New("checkpoint signing key not configured")
+
+	// errChainBroken stops chain streaming at the first integrity violation
+	errChainBroken = errors.New("audit chain broken")
+)
+
+// IntegrityService maintains and verifies the tamper-evident hash chain over audit logs.
+// Every stored log carries the hash of the log before it; signed checkpoints of the chain head
+// make it impossible to rewrite or truncate the chain without the signing key.
+type IntegrityService struct {
+	repo database.IntegrityRepository
+
+	// signingKey signs checkpoints; nil disables checkpointing and signature verification
+	signingKey ed25519.PrivateKey
+	keyID      string
+}
+
+// NewIntegrityService creates a new integrity service. signingKey may be nil.
+func NewIntegrityService(repo database.IntegrityRepository, signingKey ed25519.PrivateKey) *IntegrityService {
+	service := &IntegrityService{repo: repo, signingKey: signingKey}
+	if signingKey != nil {
+		service.keyID = checkpointKeyID(signingKey.Public().(ed25519.PublicKey))
+	}
+	return service
+}
+
+// ParseCheckpointSigningKey decodes a base64-encoded Ed25519 seed (32 bytes) or private key (64 bytes)
+func ParseCheckpointSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("expected a %d-byte seed or %d-byte private key, got %d bytes", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// checkpointKeyID identifies a signing key by a short fingerprint of its public key
+func checkpointKeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// CreateCheckpoint signs the current chain head.
+// Returns nil if the chain is empty or has not grown since the last checkpoint.
+func (s *IntegrityService) CreateCheckpoint(ctx context.Context) (*v1models.AuditCheckpoint, error) {
+	if s.signingKey == nil {
+		return nil, ErrCheckpointSigningDisabled
+	}
+
+	head, err := s.repo.GetChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head.LastSequence == 0 {
+		return nil, nil
+	}
+	latest, err := s.repo.GetLatestCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Sequence >= head.LastSequence {
+		return nil, nil
+	}
+
+	checkpoint := &v1models.AuditCheckpoint{
+		Sequence:  head.LastSequence,
+		Hash:      head.LastHash,
+		KeyID:     s.keyID,
+		CreatedAt: v1models.ChainTimestamp(time.Now()),
+	}
+	checkpoint.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.signingKey, checkpoint.SigningPayload()))
+	if err := s.repo.CreateCheckpoint(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// StartCheckpointing signs the chain head every interval until the context is cancelled
+func (s *IntegrityService) StartCheckpointing(ctx context.Context, interval time.Duration) {
+	if s.signingKey == nil || interval <= 0 {
+		slog.Info("Audit chain checkpointing disabled")
+		return
+	}
+
+	slog.Info("Audit chain checkpointing started", "interval", interval, "keyId", s.keyID)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Audit chain checkpointing stopped")
+			return
+		case <-ticker.C:
+			checkpoint, err := s.CreateCheckpoint(ctx)
+			if err != nil {
+				slog.Error("Failed to create audit chain checkpoint", "error", err)
+				continue
+			}
+			if checkpoint != nil {
+				slog.Info("Created audit chain checkpoint", "sequence", checkpoint.Sequence)
+			}
+		}
+	}
+}
+
+// VerifyChain checks the hash chain over the sequence range [from, to].
+// A zero from starts at the beginning of the chain; a zero to (or one past the head) ends at the head.
+// Every log's hash is recomputed and its link to the preceding log checked, including the link into
+// the range; checkpoints in the range must match the chain and carry a valid signature.
+// Logs removed by retention archival are followed through their tombstones, whose links are checked.
+// Verification stops at the first violation, which is reported in the result rather than as an error.
+func (s *IntegrityService) VerifyChain(ctx context.Context, from, to int64) (*v1models.ChainVerificationResponse, error) {
+	head, err := s.repo.GetChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if from <= 0 {
+		from = 1
+	}
+	if to <= 0 || to > head.LastSequence {
+		to = head.LastSequence
+	}
+	if head.LastSequence > 0 && from > to {
+		return nil, fmt.Errorf("%w: from (%d) must not exceed to (%d) or the chain head", ErrInvalidInput, from, to)
+	}
+
+	result := &v1models.ChainVerificationResponse{Valid: true, FromSequence: from, ToSequence: to}
+	expected := from
+	fail := func(sequence int64, logID *uuid.UUID, reason string) {
+		result.Valid = false
+		result.Failure = &v1models.ChainVerificationFailure{Sequence: sequence, LogID: logID, Reason: reason}
+	}
+
+	// A signed checkpoint beyond the head means logs were removed from the end of the chain
+	latest, err := s.repo.GetLatestCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Sequence > head.LastSequence {
+		fail(latest.Sequence, nil, fmt.Sprintf("chain head is at %d but a checkpoint was signed at %d; logs were removed", head.LastSequence, latest.Sequence))
+		return result, nil
+	}
+	if head.LastSequence == 0 {
+		return result, nil
+	}
+
+	previousHash := ""
+	if from > 1 {
+		hash, found, err := s.linkHash(ctx, from-1)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			fail(from-1, nil, "log missing from chain")
+			return result, nil
+		}
+		previousHash = hash
+	}
+
+	checkpoints, err := s.repo.ListCheckpoints(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	checkpointsBySequence := make(map[int64]v1models.AuditCheckpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		checkpointsBySequence[checkpoint.Sequence] = checkpoint
+	}
+
+	// checkCheckpointAt checks the checkpoint signed at the expected sequence, if any
+	checkCheckpointAt := func(hash string, logID *uuid.UUID) bool {
+		checkpoint, ok := checkpointsBySequence[expected]
+		if !ok {
+			return true
+		}
+		verified, reason := s.checkCheckpoint(&checkpoint, hash)
+		if reason != "" {
+			fail(expected, logID, reason)
+			return false
+		}
+		if verified {
+			result.CheckpointsVerified++
+		} else {
+			result.CheckpointsSkipped++
+		}
+		return true
+	}
+
+	// skipArchived follows the tombstones of archived logs from the expected sequence up to upTo.
+	// Archived logs have no content left to hash, but their links must still connect the chain.
+	skipArchived := func(upTo int64) (bool, error) {
+		for expected <= upTo {
+			tombstones, err := s.repo.ListChainTombstones(ctx, expected, min(upTo, expected+verifyBatchSize-1))
+			if err != nil {
+				return false, err
+			}
+			if len(tombstones) == 0 {
+				break
+			}
+			for _, tombstone := range tombstones {
+				if tombstone.Sequence != expected {
+					fail(expected, nil, "log missing from chain")
+					return false, nil
+				}
+				if tombstone.PreviousHash != previousHash {
+					fail(expected, &tombstone.LogID, "previous hash of archived log does not match the preceding log")
+					return false, nil
+				}
+				if !checkCheckpointAt(tombstone.Hash, &tombstone.LogID) {
+					return false, nil
+				}
+				previousHash = tombstone.Hash
+				expected++
+				result.ArchivedCount++
+			}
+		}
+		if expected <= upTo {
+			fail(expected, nil, "log missing from chain")
+			return false, nil
+		}
+		return true, nil
+	}
+
+	err = s.repo.StreamChain(ctx, from, to, verifyBatchSize, func(batch []v1models.AuditLog) error {
+		for i := range batch {
+			log := &batch[i]
+			if *log.Sequence != expected {
+				ok, err := skipArchived(*log.Sequence - 1)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errChainBroken
+				}
+			}
+			if log.PreviousHash != previousHash {
+				fail(expected, &log.ID, "previous hash does not match the preceding log")
+				return errChainBroken
+			}
+			hash, err := log.ComputeChainHash()
+			if err != nil {
+				fail(expected, &log.ID, "log cannot be hashed: "+err.Error())
+				return errChainBroken
+			}
+			// Logs pseudonymized by an erasure keep their original Hash as the chain link;
+			// their content is checked against the hash recorded at erasure
+			contentHash := log.Hash
+			if log.ErasureID != nil {
+				contentHash = log.PseudonymizedHash
+				result.PseudonymizedCount++
+			}
+			if hash != contentHash {
+				fail(expected, &log.ID, "content hash mismatch; the log was modified")
+				return errChainBroken
+			}
+			if !checkCheckpointAt(log.Hash, &log.ID) {
+				return errChainBroken
+			}
+
+			previousHash = log.Hash
+			expected++
+			result.CheckedCount++
+		}
+		return nil
+	})
+	if errors.Is(err, errChainBroken) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := skipArchived(to)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return result, nil
+	}
+	if to == head.LastSequence && previousHash != head.LastHash {
+		fail(to, nil, "chain head hash does not match the last log")
+	}
+	return result, nil
+}
+
+// linkHash returns the hash of the chained log at the given sequence, or of its tombstone if it was archived
+func (s *IntegrityService) linkHash(ctx context.Context, sequence int64) (string, bool, error) {
+	log, err := s.repo.GetAuditLogBySequence(ctx, sequence)
+	if err == nil {
+		return log.Hash, true, nil
+	}
+	if !errors.Is(err, database.ErrAuditLogNotFound) {
+		return "", false, err
+	}
+	tombstones, err := s.repo.ListChainTombstones(ctx, sequence, sequence)
+	if err != nil {
+		return "", false, err
+	}
+	if len(tombstones) == 0 {
+		return "", false, nil
+	}
+	return tombstones[0].Hash, true, nil
+}
+
+// checkCheckpoint reports whether the checkpoint's signature was verified, or why the checkpoint is invalid.
+// Checkpoints signed with another key (e.g. before a key rotation) are neither verified nor invalid.
+func (s *IntegrityService) checkCheckpoint(checkpoint *v1models.AuditCheckpoint, logHash string) (bool, string) {
+	if checkpoint.Hash != logHash {
+		return false, fmt.Sprintf("log does not match the hash signed in checkpoint %d", checkpoint.ID)
+	}
+	if s.signingKey == nil || checkpoint.KeyID != s.keyID {
+		return false, ""
+	}
+	signature, err := base64.StdEncoding.DecodeString(checkpoint.Signature)
+	if err != nil || !ed25519.Verify(s.signingKey.Public().(ed25519.PublicKey), checkpoint.SigningPayload(), signature) {
+		return false, fmt.Sprintf("checkpoint %d has an invalid signature", checkpoint.ID)
+	}
+	return true, ""
+}
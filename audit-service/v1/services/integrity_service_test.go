@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// testSigningKey returns a deterministic checkpoint signing key
+func testSigningKey(seedByte byte) ed25519.PrivateKey {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = seedByte
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+// setupChainTest stores count chained logs and returns an integrity service over them
+func setupChainTest(t *testing.T, count int, signingKey ed25519.PrivateKey) (*IntegrityService, *database.GormRepository, *gorm.DB) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+	for i := 0; i < count; i++ {
+		_, err := repo.CreateAuditLog(context.Background(), &v1models.AuditLog{
+			Timestamp:       time.Date(2024, 1, 20, 10, i, 0, 0, time.UTC),
+			Status:          v1models.StatusSuccess,
+			ActorType:       "SERVICE",
+			ActorID:         "orchestration-engine",
+			TargetType:      "SERVICE",
+			RequestMetadata: v1models.JSONBRawMessage(`{"index":` + string(rune('0'+i)) + `}`),
+		})
+		require.NoError(t, err)
+	}
+	return NewIntegrityService(repo, signingKey), repo, db
+}
+
+func TestGormRepository_CreateAuditLog_Chains(t *testing.T) {
+	_, repo, _ := setupChainTest(t, 3, nil)
+	ctx := context.Background()
+
+	first, err := repo.GetAuditLogBySequence(ctx, 1)
+	require.NoError(t, err)
+	second, err := repo.GetAuditLogBySequence(ctx, 2)
+	require.NoError(t, err)
+
+	assert.Empty(t, first.PreviousHash)
+	assert.Len(t, first.Hash, 64)
+	assert.Equal(t, first.Hash, second.PreviousHash)
+
+	head, err := repo.GetChainHead(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), head.LastSequence)
+}
+
+func TestIntegrityService_VerifyChain(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("IntactChain", func(t *testing.T) {
+		service, _, _ := setupChainTest(t, 5, nil)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(5), result.CheckedCount)
+
+		result, err = service.VerifyChain(ctx, 2, 3)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(2), result.CheckedCount)
+	})
+
+	t.Run("EmptyChain", func(t *testing.T) {
+		service, _, _ := setupChainTest(t, 0, nil)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Zero(t, result.CheckedCount)
+	})
+
+	t.Run("ModifiedLog", func(t *testing.T) {
+		service, _, db := setupChainTest(t, 5, nil)
+		require.NoError(t, db.Model(&v1models.AuditLog{}).Where("sequence = ?", 3).Update("actor_id", "attacker").Error)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.Failure)
+		assert.Equal(t, int64(3), result.Failure.Sequence)
+		assert.Contains(t, result.Failure.Reason, "content hash mismatch")
+	})
+
+	t.Run("DeletedLog", func(t *testing.T) {
+		service, _, db := setupChainTest(t, 5, nil)
+		require.NoError(t, db.Where("sequence = ?", 2).Delete(&v1models.AuditLog{}).Error)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(2), result.Failure.Sequence)
+		assert.Contains(t, result.Failure.Reason, "missing")
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		service, _, _ := setupChainTest(t, 3, nil)
+
+		_, err := service.VerifyChain(ctx, 3, 2)
+		assert.True(t, IsValidationError(err))
+	})
+}
+
+func TestIntegrityService_Checkpoints(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SignsHeadOnlyWhenChainGrows", func(t *testing.T) {
+		service, repo, _ := setupChainTest(t, 3, testSigningKey(1))
+
+		checkpoint, err := service.CreateCheckpoint(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, checkpoint)
+		assert.Equal(t, int64(3), checkpoint.Sequence)
+
+		checkpoint, err = service.CreateCheckpoint(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, checkpoint, "no checkpoint expected without new logs")
+
+		_, err = repo.CreateAuditLog(ctx, &v1models.AuditLog{Status: v1models.StatusSuccess, ActorType: "SYSTEM", ActorID: "scheduler", TargetType: "SERVICE"})
+		require.NoError(t, err)
+		checkpoint, err = service.CreateCheckpoint(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, checkpoint)
+		assert.Equal(t, int64(4), checkpoint.Sequence)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, 2, result.CheckpointsVerified)
+	})
+
+	t.Run("DisabledWithoutKey", func(t *testing.T) {
+		service, _, _ := setupChainTest(t, 1, nil)
+
+		_, err := service.CreateCheckpoint(ctx)
+		assert.ErrorIs(t, err, ErrCheckpointSigningDisabled)
+	})
+
+	t.Run("DetectsRecomputedChain", func(t *testing.T) {
+		service, repo, db := setupChainTest(t, 3, testSigningKey(1))
+		_, err := service.CreateCheckpoint(ctx)
+		require.NoError(t, err)
+
+		// An attacker with database access modifies a log and recomputes every hash after it
+		previousHash := ""
+		for sequence := int64(1); sequence <= 3; sequence++ {
+			log, err := repo.GetAuditLogBySequence(ctx, sequence)
+			require.NoError(t, err)
+			if sequence == 2 {
+				log.ActorID = "attacker"
+			}
+			log.PreviousHash = previousHash
+			log.Hash, err = log.ComputeChainHash()
+			require.NoError(t, err)
+			require.NoError(t, db.Save(log).Error)
+			previousHash = log.Hash
+		}
+		require.NoError(t, db.Model(&v1models.AuditChainHead{}).Where("id = ?", v1models.AuditChainHeadID).Update("last_hash", previousHash).Error)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(3), result.Failure.Sequence)
+		assert.Contains(t, result.Failure.Reason, "checkpoint")
+	})
+
+	t.Run("DetectsTruncation", func(t *testing.T) {
+		service, _, db := setupChainTest(t, 3, testSigningKey(1))
+		_, err := service.CreateCheckpoint(ctx)
+		require.NoError(t, err)
+
+		// Remove the last log and rewind the head to hide it
+		var second v1models.AuditLog
+		require.NoError(t, db.Where("sequence = ?", 2).First(&second).Error)
+		require.NoError(t, db.Where("sequence = ?", 3).Delete(&v1models.AuditLog{}).Error)
+		require.NoError(t, db.Model(&v1models.AuditChainHead{}).Where("id = ?", v1models.AuditChainHeadID).
+			Updates(map[string]interface{}{"last_sequence": 2, "last_hash": second.Hash}).Error)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Failure.Reason, "removed")
+	})
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		service, _, db := setupChainTest(t, 2, testSigningKey(1))
+		_, err := service.CreateCheckpoint(ctx)
+		require.NoError(t, err)
+
+		forged := base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize))
+		require.NoError(t, db.Model(&v1models.AuditCheckpoint{}).Where("sequence = ?", 2).Update("signature", forged).Error)
+
+		result, err := service.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Failure.Reason, "invalid signature")
+	})
+
+	t.Run("OtherKeySkipped", func(t *testing.T) {
+		service, repo, _ := setupChainTest(t, 2, testSigningKey(1))
+		_, err := service.CreateCheckpoint(ctx)
+		require.NoError(t, err)
+
+		rotated := NewIntegrityService(repo, testSigningKey(2))
+		result, err := rotated.VerifyChain(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, 0, result.CheckpointsVerified)
+		assert.Equal(t, 1, result.CheckpointsSkipped)
+	})
+}
+
+func TestParseCheckpointSigningKey(t *testing.T) {
+	key := testSigningKey(1)
+
+	fromSeed, err := ParseCheckpointSigningKey(base64.StdEncoding.EncodeToString(key.Seed()))
+	require.NoError(t, err)
+	assert.Equal(t, key, fromSeed)
+
+	fromKey, err := ParseCheckpointSigningKey(base64.StdEncoding.EncodeToString(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, fromKey)
+
+	_, err = ParseCheckpointSigningKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+	_, err = ParseCheckpointSigningKey("not base64!")
+	assert.Error(t, err)
+}
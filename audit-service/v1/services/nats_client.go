@@ -0,0 +1,405 @@
+package services
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// natsConnectTimeout bounds dialing and the connection handshake
+const natsConnectTimeout = 10 * time.Second
+
+// natsMsg is a message delivered to the client's inbox
+type natsMsg struct {
+	Subject string
+	Reply   string
+	Header  map[string]string
+	Data    []byte
+
+	// Status and Description are set on JetStream control messages (e.g. 404 No Messages);
+	// Status is 0 for regular messages
+	Status      int
+	Description string
+}
+
+// natsConn is a minimal NATS client speaking just enough of the protocol to pull messages
+// from a JetStream durable consumer and acknowledge them. It is not safe for concurrent reads.
+type natsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+
+	// inbox is the prefix of reply subjects this connection is subscribed to
+	inbox     string
+	requestID int
+}
+
+// natsServerInfo is the subset of the server's INFO message the client uses
+type natsServerInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	Headers     bool `json:"headers"`
+	JetStream   bool `json:"jetstream"`
+}
+
+// dialNATS connects to the server at rawURL (nats://[user:pass@]host:port or tls://...);
+// a username without password in the URL is sent as an auth token
+func dialNATS(rawURL string) (*natsConn, error) {
+	serverURL, err := url.Parse(rawURL)
+	if err != nil || serverURL.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q", rawURL)
+	}
+	host := serverURL.Host
+	if serverURL.Port() == "" {
+		host = net.JoinHostPort(serverURL.Hostname(), "4222")
+	}
+
+	conn, err := net.DialTimeout("tcp", host, natsConnectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", host, err)
+	}
+	nc := &natsConn{conn: conn, reader: bufio.NewReader(conn)}
+	if err := nc.handshake(serverURL); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return nc, nil
+}
+
+// handshake reads the server INFO, upgrades to TLS if needed, authenticates and subscribes to the inbox
+func (nc *natsConn) handshake(serverURL *url.URL) error {
+	nc.conn.SetDeadline(time.Now().Add(natsConnectTimeout))
+	defer nc.conn.SetDeadline(time.Time{})
+
+	line, err := nc.readLine()
+	if err != nil {
+		return fmt.Errorf("failed to read NATS server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting: %q", line)
+	}
+	var info natsServerInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return fmt.Errorf("invalid NATS server info: %w", err)
+	}
+	if !info.Headers {
+		return errors.New("NATS server does not support headers, which JetStream pull consumers require")
+	}
+
+	if info.TLSRequired || serverURL.Scheme == "tls" {
+		tlsConn := tls.Client(nc.conn, &tls.Config{ServerName: serverURL.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("NATS TLS handshake failed: %w", err)
+		}
+		nc.conn = tlsConn
+		nc.reader = bufio.NewReader(tlsConn)
+	}
+
+	connect := map[string]any{
+		"verbose":       false,
+		"pedantic":      false,
+		"headers":       true,
+		"no_responders": true,
+		"lang":          "go",
+		"version":       "audit-service",
+		"name":          "audit-service",
+		"protocol":      1,
+	}
+	if serverURL.User != nil {
+		if password, ok := serverURL.User.Password(); ok {
+			connect["user"] = serverURL.User.Username()
+			connect["pass"] = password
+		} else {
+			connect["auth_token"] = serverURL.User.Username()
+		}
+	}
+	connectJSON, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+
+	nc.inbox = "_INBOX." + natsNUID()
+	if err := nc.write(fmt.Sprintf("CONNECT %s\r\nSUB %s.* 1\r\nPING\r\n", connectJSON, nc.inbox)); err != nil {
+		return fmt.Errorf("failed to send NATS CONNECT: %w", err)
+	}
+
+	// The server answers the PING once CONNECT is processed, or reports an authorization error
+	for {
+		line, err := nc.readLine()
+		if err != nil {
+			return fmt.Errorf("failed to complete NATS handshake: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS server rejected connection: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// Close closes the connection; a blocked read returns an error
+func (nc *natsConn) Close() error {
+	return nc.conn.Close()
+}
+
+// Publish publishes data to subject with an optional reply subject
+func (nc *natsConn) Publish(subject, reply string, data []byte) error {
+	var command strings.Builder
+	command.WriteString("PUB " + subject + " ")
+	if reply != "" {
+		command.WriteString(reply + " ")
+	}
+	command.WriteString(strconv.Itoa(len(data)) + "\r\n")
+	command.Write(data)
+	command.WriteString("\r\n")
+	return nc.write(command.String())
+}
+
+// newReplySubject returns a fresh subject within the connection's inbox
+func (nc *natsConn) newReplySubject() string {
+	nc.requestID++
+	return nc.inbox + "." + strconv.Itoa(nc.requestID)
+}
+
+// Request publishes data to subject and waits up to timeout for the reply
+func (nc *natsConn) Request(subject string, data []byte, timeout time.Duration) (*natsMsg, error) {
+	reply := nc.newReplySubject()
+	if err := nc.Publish(subject, reply, data); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		msg, err := nc.NextMsg(deadline)
+		if err != nil {
+			return nil, err
+		}
+		if msg.Subject != reply {
+			continue // a late delivery for an earlier request
+		}
+		if msg.Status == 503 {
+			return nil, fmt.Errorf("no responders for NATS subject %s (is JetStream enabled?)", subject)
+		}
+		return msg, nil
+	}
+}
+
+// NextMsg reads until the next message arrives on the inbox, answering server PINGs in between
+func (nc *natsConn) NextMsg(deadline time.Time) (*natsMsg, error) {
+	nc.conn.SetReadDeadline(deadline)
+	defer nc.conn.SetReadDeadline(time.Time{})
+
+	for {
+		line, err := nc.readLine()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case line == "PING":
+			if err := nc.write("PONG\r\n"); err != nil {
+				return nil, err
+			}
+		case line == "PONG", line == "+OK", strings.HasPrefix(line, "INFO "):
+		case strings.HasPrefix(line, "-ERR"):
+			return nil, fmt.Errorf("NATS server error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case strings.HasPrefix(line, "MSG "):
+			return nc.readMsg(strings.Fields(line)[1:], false)
+		case strings.HasPrefix(line, "HMSG "):
+			return nc.readMsg(strings.Fields(line)[1:], true)
+		default:
+			return nil, fmt.Errorf("unexpected NATS protocol line: %q", line)
+		}
+	}
+}
+
+// readMsg reads the payload of a MSG (subject sid [reply] size)
+// or HMSG (subject sid [reply] headerSize totalSize) line
+func (nc *natsConn) readMsg(args []string, withHeaders bool) (*natsMsg, error) {
+	sizeArgs := 1
+	if withHeaders {
+		sizeArgs = 2
+	}
+	if len(args) != 2+sizeArgs && len(args) != 3+sizeArgs {
+		return nil, fmt.Errorf("malformed NATS message arguments: %q", args)
+	}
+	msg := &natsMsg{Subject: args[0]}
+	if len(args) == 3+sizeArgs {
+		msg.Reply = args[2]
+	}
+
+	sizes := args[len(args)-sizeArgs:]
+	total, err := strconv.Atoi(sizes[len(sizes)-1])
+	if err != nil || total < 0 {
+		return nil, fmt.Errorf("malformed NATS message size: %q", args)
+	}
+	headerSize := 0
+	if withHeaders {
+		headerSize, err = strconv.Atoi(sizes[0])
+		if err != nil || headerSize < 0 || headerSize > total {
+			return nil, fmt.Errorf("malformed NATS header size: %q", args)
+		}
+	}
+
+	payload := make([]byte, total+2) // payload followed by CRLF
+	if _, err := io.ReadFull(nc.reader, payload); err != nil {
+		return nil, err
+	}
+	msg.Data = payload[headerSize:total]
+	if withHeaders {
+		msg.Status, msg.Description, msg.Header = parseNATSHeaders(string(payload[:headerSize]))
+	}
+	return msg, nil
+}
+
+// parseNATSHeaders parses a "NATS/1.0 [status [description]]" header block
+func parseNATSHeaders(block string) (int, string, map[string]string) {
+	lines := strings.Split(block, "\r\n")
+	status, description := 0, ""
+	if statusLine := strings.TrimSpace(strings.TrimPrefix(lines[0], "NATS/1.0")); statusLine != "" {
+		code, rest, _ := strings.Cut(statusLine, " ")
+		status, _ = strconv.Atoi(code)
+		description = strings.TrimSpace(rest)
+	}
+	headers := make(map[string]string)
+	for _, line := range lines[1:] {
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return status, description, headers
+}
+
+func (nc *natsConn) readLine() (string, error) {
+	line, err := nc.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (nc *natsConn) write(data string) error {
+	nc.writeMu.Lock()
+	defer nc.writeMu.Unlock()
+	_, err := io.WriteString(nc.conn, data)
+	return err
+}
+
+// natsNUID returns a random token for inbox subjects
+func natsNUID() string {
+	token := make([]byte, 11)
+	rand.Read(token)
+	return strings.ToUpper(hex.EncodeToString(token))
+}
+
+// jetStreamConsumer pulls messages from a durable JetStream consumer
+type jetStreamConsumer struct {
+	nc       *natsConn
+	stream   string
+	consumer string
+}
+
+// jsAPIResponse is the common envelope of JetStream API responses
+type jsAPIResponse struct {
+	Error *struct {
+		Code        int    `json:"code"`
+		ErrCode     int    `json:"err_code"`
+		Description string `json:"description"`
+	} `json:"error,omitempty"`
+}
+
+// ensureJetStreamConsumer creates the durable pull consumer, or returns the existing one
+// if it was already created with the same configuration
+func ensureJetStreamConsumer(nc *natsConn, stream, consumer, filterSubject string) (*jetStreamConsumer, error) {
+	request, err := json.Marshal(map[string]any{
+		"stream_name": stream,
+		"config": map[string]any{
+			"durable_name":   consumer,
+			"ack_policy":     "explicit",
+			"deliver_policy": "all",
+			"filter_subject": filterSubject,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	reply, err := nc.Request(fmt.Sprintf("$JS.API.CONSUMER.DURABLE.CREATE.%s.%s", stream, consumer), request, natsConnectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JetStream consumer: %w", err)
+	}
+	var response jsAPIResponse
+	if err := json.Unmarshal(reply.Data, &response); err != nil {
+		return nil, fmt.Errorf("invalid JetStream API response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("failed to create JetStream consumer %s on stream %s: %s (code %d)",
+			consumer, stream, response.Error.Description, response.Error.Code)
+	}
+	return &jetStreamConsumer{nc: nc, stream: stream, consumer: consumer}, nil
+}
+
+// Fetch requests up to batch messages and returns those delivered before the server
+// ends the pull or maxWait elapses. Returned messages must be acknowledged.
+func (c *jetStreamConsumer) Fetch(batch int, maxWait time.Duration) ([]*natsMsg, error) {
+	request, err := json.Marshal(map[string]any{"batch": batch, "expires": maxWait.Nanoseconds()})
+	if err != nil {
+		return nil, err
+	}
+	reply := c.nc.newReplySubject()
+	if err := c.nc.Publish(fmt.Sprintf("$JS.API.CONSUMER.MSG.NEXT.%s.%s", c.stream, c.consumer), reply, request); err != nil {
+		return nil, err
+	}
+
+	// Allow the server some slack to send its 408 Request Timeout once the pull expires
+	deadline := time.Now().Add(maxWait + time.Second)
+	var messages []*natsMsg
+	for len(messages) < batch {
+		msg, err := c.nc.NextMsg(deadline)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return messages, nil
+		}
+		if err != nil {
+			return messages, err
+		}
+		switch {
+		case msg.Status == 0 && strings.HasPrefix(msg.Reply, "$JS.ACK."):
+			// Messages of an earlier, expired pull may still arrive; they are processed all the same
+			messages = append(messages, msg)
+		case msg.Status == 100:
+			// idle heartbeat
+		case msg.Subject == reply && msg.Status != 0:
+			// 404 No Messages, 408 Request Timeout or 409 (e.g. consumer deleted) end the pull
+			if msg.Status == 409 {
+				return messages, fmt.Errorf("JetStream pull ended: %s", msg.Description)
+			}
+			return messages, nil
+		}
+	}
+	return messages, nil
+}
+
+// Ack acknowledges successful processing of msg
+func (c *jetStreamConsumer) Ack(msg *natsMsg) error {
+	return c.nc.Publish(msg.Reply, "", []byte("+ACK"))
+}
+
+// Nak asks the server to redeliver msg
+func (c *jetStreamConsumer) Nak(msg *natsMsg) error {
+	return c.nc.Publish(msg.Reply, "", []byte("-NAK"))
+}
+
+// Term tells the server never to redeliver msg
+func (c *jetStreamConsumer) Term(msg *natsMsg) error {
+	return c.nc.Publish(msg.Reply, "", []byte("+TERM"))
+}
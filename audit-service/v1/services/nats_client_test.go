@@ -0,0 +1,305 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJetStream is an in-process NATS server implementing the subset of JetStream
+// used by the pull consumer: durable consumer creation, pull requests and acknowledgements
+type fakeJetStream struct {
+	listener net.Listener
+	stream   string
+
+	// user and pass, if set, are the credentials clients must present
+	user string
+	pass string
+
+	mu        sync.Mutex
+	pending   []fakeStreamMsg
+	inFlight  map[string]fakeStreamMsg // by ack subject
+	acks      []string                 // ack payloads in the order received
+	consumers []map[string]any         // consumer create requests
+	waiting   *fakePullRequest
+	sequence  int
+}
+
+type fakeStreamMsg struct {
+	subject string
+	header  map[string]string
+	data    []byte
+}
+
+// fakePullRequest is a pull request held until a message is published
+type fakePullRequest struct {
+	conn  *fakeNATSConn
+	reply string
+	batch int
+}
+
+type fakeNATSConn struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+	sid     string
+}
+
+func (c *fakeNATSConn) write(data string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	io.WriteString(c.conn, data)
+}
+
+func newFakeJetStream(t *testing.T) *fakeJetStream {
+	return newAuthenticatedFakeJetStream(t, "", "")
+}
+
+// newAuthenticatedFakeJetStream starts a server that only accepts clients presenting user and pass
+func newAuthenticatedFakeJetStream(t *testing.T, user, pass string) *fakeJetStream {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &fakeJetStream{listener: listener, stream: DefaultQueueStream, user: user, pass: pass, inFlight: make(map[string]fakeStreamMsg)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(&fakeNATSConn{conn: conn})
+		}
+	}()
+	return f
+}
+
+// URL returns the server URL, with the given credentials if non-empty
+func (f *fakeJetStream) URL(userinfo string) string {
+	if userinfo != "" {
+		return "nats://" + userinfo + "@" + f.listener.Addr().String()
+	}
+	return "nats://" + f.listener.Addr().String()
+}
+
+// Publish adds a message to the stream
+func (f *fakeJetStream) Publish(subject string, header map[string]string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending = append(f.pending, fakeStreamMsg{subject: subject, header: header, data: data})
+	if f.waiting != nil {
+		pull := f.waiting
+		f.waiting = nil
+		f.deliverLocked(pull)
+	}
+}
+
+// Acks returns the acknowledgement payloads received so far
+func (f *fakeJetStream) Acks() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.acks...)
+}
+
+// Consumers returns the consumer create requests received so far
+func (f *fakeJetStream) Consumers() []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]any(nil), f.consumers...)
+}
+
+func (f *fakeJetStream) serve(c *fakeNATSConn) {
+	defer c.conn.Close()
+	c.write(`INFO {"server_id":"fake","version":"2.10.0","headers":true,"jetstream":true,"max_payload":1048576}` + "\r\n")
+
+	reader := bufio.NewReader(c.conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			var connect map[string]any
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &connect); err != nil {
+				c.write("-ERR 'Invalid Connect'\r\n")
+				return
+			}
+			if f.user != "" && (connect["user"] != f.user || connect["pass"] != f.pass) {
+				c.write("-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case strings.HasPrefix(line, "SUB "):
+			fields := strings.Fields(line)
+			c.sid = fields[len(fields)-1]
+		case line == "PING":
+			c.write("PONG\r\n")
+		case strings.HasPrefix(line, "PUB "):
+			fields := strings.Fields(line)[1:]
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			reply := ""
+			if len(fields) == 3 {
+				reply = fields[1]
+			}
+			f.handlePublish(c, fields[0], reply, payload[:size])
+		}
+	}
+}
+
+func (f *fakeJetStream) handlePublish(c *fakeNATSConn, subject, reply string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(subject, "$JS.API.CONSUMER.DURABLE.CREATE."):
+		var request map[string]any
+		json.Unmarshal(data, &request)
+		f.consumers = append(f.consumers, request)
+		response := `{"type":"io.nats.jetstream.api.v1.consumer_create_response","name":"audit-service"}`
+		if !strings.HasPrefix(subject, "$JS.API.CONSUMER.DURABLE.CREATE."+f.stream+".") {
+			response = `{"type":"io.nats.jetstream.api.v1.consumer_create_response","error":{"code":404,"err_code":10059,"description":"stream not found"}}`
+		}
+		c.write(fmt.Sprintf("MSG %s %s %d\r\n%s\r\n", reply, c.sid, len(response), response))
+	case strings.HasPrefix(subject, "$JS.API.CONSUMER.MSG.NEXT."):
+		var request struct {
+			Batch int `json:"batch"`
+		}
+		json.Unmarshal(data, &request)
+		pull := &fakePullRequest{conn: c, reply: reply, batch: request.Batch}
+		if len(f.pending) == 0 {
+			f.waiting = pull
+			return
+		}
+		f.deliverLocked(pull)
+	case strings.HasPrefix(subject, "$JS.ACK."):
+		f.acks = append(f.acks, string(data))
+		msg, ok := f.inFlight[subject]
+		delete(f.inFlight, subject)
+		if ok && string(data) == "-NAK" {
+			f.pending = append(f.pending, msg)
+		}
+	}
+}
+
+// deliverLocked sends up to the pull's batch of pending messages, then ends the pull
+func (f *fakeJetStream) deliverLocked(pull *fakePullRequest) {
+	for delivered := 0; delivered < pull.batch && len(f.pending) > 0; delivered++ {
+		msg := f.pending[0]
+		f.pending = f.pending[1:]
+		f.sequence++
+		ackSubject := fmt.Sprintf("$JS.ACK.%s.%s.1.%d.%d.%d.0", f.stream, DefaultQueueConsumer, f.sequence, f.sequence, time.Now().UnixNano())
+		f.inFlight[ackSubject] = msg
+
+		if len(msg.header) == 0 {
+			pull.conn.write(fmt.Sprintf("MSG %s %s %s %d\r\n%s\r\n", msg.subject, pull.conn.sid, ackSubject, len(msg.data), msg.data))
+			continue
+		}
+		header := "NATS/1.0\r\n"
+		for name, value := range msg.header {
+			header += name + ": " + value + "\r\n"
+		}
+		header += "\r\n"
+		pull.conn.write(fmt.Sprintf("HMSG %s %s %s %d %d\r\n%s%s\r\n",
+			msg.subject, pull.conn.sid, ackSubject, len(header), len(header)+len(msg.data), header, msg.data))
+	}
+	status := "NATS/1.0 408 Request Timeout\r\n\r\n"
+	pull.conn.write(fmt.Sprintf("HMSG %s %s %d %d\r\n%s\r\n", pull.reply, pull.conn.sid, len(status), len(status), status))
+}
+
+func TestNATSClient_FetchAndAcknowledge(t *testing.T) {
+	server := newFakeJetStream(t)
+	server.Publish("audit.events.orchestration", nil, []byte(`{"eventId":"e-1"}`))
+	server.Publish("audit.events.orchestration", map[string]string{natsMsgIDHeader: "e-2"}, []byte(`{}`))
+	server.Publish("audit.events.orchestration", nil, []byte(`{"eventId":"e-3"}`))
+
+	nc, err := dialNATS(server.URL(""))
+	require.NoError(t, err)
+	defer nc.Close()
+
+	consumer, err := ensureJetStreamConsumer(nc, DefaultQueueStream, DefaultQueueConsumer, DefaultQueueSubject)
+	require.NoError(t, err)
+	consumers := server.Consumers()
+	require.Len(t, consumers, 1)
+	config := consumers[0]["config"].(map[string]any)
+	assert.Equal(t, "explicit", config["ack_policy"])
+	assert.Equal(t, DefaultQueueSubject, config["filter_subject"])
+
+	messages, err := consumer.Fetch(10, time.Second)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.Equal(t, "audit.events.orchestration", messages[0].Subject)
+	assert.Equal(t, `{"eventId":"e-1"}`, string(messages[0].Data))
+	assert.Equal(t, "e-2", messages[1].Header[natsMsgIDHeader])
+	assert.Equal(t, `{}`, string(messages[1].Data))
+
+	require.NoError(t, consumer.Ack(messages[0]))
+	require.NoError(t, consumer.Term(messages[1]))
+	require.NoError(t, consumer.Nak(messages[2]))
+	assert.Eventually(t, func() bool { return len(server.Acks()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"+ACK", "+TERM", "-NAK"}, server.Acks())
+
+	// The NAKed message is redelivered
+	messages, err = consumer.Fetch(10, time.Second)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, `{"eventId":"e-3"}`, string(messages[0].Data))
+}
+
+func TestNATSClient_FetchWithoutMessages(t *testing.T) {
+	server := newFakeJetStream(t)
+	nc, err := dialNATS(server.URL(""))
+	require.NoError(t, err)
+	defer nc.Close()
+	consumer, err := ensureJetStreamConsumer(nc, DefaultQueueStream, DefaultQueueConsumer, DefaultQueueSubject)
+	require.NoError(t, err)
+
+	messages, err := consumer.Fetch(10, 50*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestNATSClient_Authentication(t *testing.T) {
+	server := newAuthenticatedFakeJetStream(t, "audit", "secret")
+
+	_, err := dialNATS(server.URL("audit:wrong"))
+	assert.ErrorContains(t, err, "Authorization Violation")
+
+	nc, err := dialNATS(server.URL("audit:secret"))
+	require.NoError(t, err)
+	nc.Close()
+}
+
+func TestNATSClient_StreamNotFound(t *testing.T) {
+	server := newFakeJetStream(t)
+	nc, err := dialNATS(server.URL(""))
+	require.NoError(t, err)
+	defer nc.Close()
+
+	_, err = ensureJetStreamConsumer(nc, "MISSING", DefaultQueueConsumer, DefaultQueueSubject)
+	assert.ErrorContains(t, err, "stream not found")
+}
+
+func TestParseNATSHeaders(t *testing.T) {
+	status, description, headers := parseNATSHeaders("NATS/1.0 404 No Messages\r\n\r\n")
+	assert.Equal(t, 404, status)
+	assert.Equal(t, "No Messages", description)
+	assert.Empty(t, headers)
+
+	status, _, headers = parseNATSHeaders("NATS/1.0\r\nNats-Msg-Id: abc\r\nX-Trace: 1\r\n\r\n")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, map[string]string{"Nats-Msg-Id": "abc", "X-Trace": "1"}, headers)
+}
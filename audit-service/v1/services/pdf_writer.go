@@ -0,0 +1,131 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page layout of text PDFs: A4 in points, with a fixed-width font so lines can be wrapped by character count
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 50
+	pdfFontSize     = 9
+	pdfLeading      = 12
+	pdfCharsPerLine = 90 // Courier glyphs are 0.6em wide: (595 - 2*50) / (9 * 0.6)
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// pdfLine is a line of text in a PDF; Bold lines use Courier-Bold
+type pdfLine struct {
+	Text string
+	Bold bool
+}
+
+// writeTextPDF writes lines as a plain-text PDF document, wrapping long lines and breaking pages as needed.
+// Every page gets a footer with the title and page number. Characters outside printable ASCII are replaced.
+func writeTextPDF(w io.Writer, title string, lines []pdfLine) error {
+	var wrapped []pdfLine
+	for _, line := range lines {
+		for _, text := range wrapPDFText(pdfText(line.Text), pdfCharsPerLine) {
+			wrapped = append(wrapped, pdfLine{Text: text, Bold: line.Bold})
+		}
+	}
+	var pages [][]pdfLine
+	for start := 0; start < len(wrapped) || start == 0; start += pdfLinesPerPage {
+		pages = append(pages, wrapped[start:min(start+pdfLinesPerPage, len(wrapped))])
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3 and 4 fonts, then a page and its content stream per page
+	var objects [][]byte
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))),
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>"),
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>"),
+	)
+	for i, page := range pages {
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin
+		for _, line := range page {
+			font := "F1"
+			if line.Bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, pdfFontSize, pdfMargin, y, escapePDFText(line.Text))
+			y -= pdfLeading
+		}
+		footer := pdfText(fmt.Sprintf("%s - page %d of %d", title, i+1, len(pages)))
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", pdfFontSize-1, pdfMargin, pdfMargin/2, escapePDFText(footer))
+
+		objects = append(objects,
+			[]byte(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 6+2*i)),
+			[]byte(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes())),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// pdfText replaces characters the standard fonts cannot show without embedding with '?' (tabs become spaces)
+func pdfText(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case r < 0x20 || r > 0x7e:
+			return '?'
+		default:
+			return r
+		}
+	}, text)
+}
+
+// escapePDFText escapes the characters that delimit PDF string literals
+func escapePDFText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(text)
+}
+
+// wrapPDFText splits text into lines of at most width characters, breaking at spaces where possible.
+// Continuation lines keep the original line's indentation.
+func wrapPDFText(text string, width int) []string {
+	if len(text) <= width {
+		return []string{text}
+	}
+	indent := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	if len(indent) >= width/2 {
+		indent = ""
+	}
+	var lines []string
+	for len(text) > width {
+		cut := strings.LastIndex(text[:width+1], " ")
+		if cut <= len(indent) {
+			cut = width
+		}
+		lines = append(lines, strings.TrimRight(text[:cut], " "))
+		text = indent + strings.TrimLeft(text[cut:], " ")
+	}
+	return append(lines, text)
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTextPDF(t *testing.T) {
+	lines := []pdfLine{{Text: "Report (draft) \\ Überblick", Bold: true}}
+	for i := 0; i < pdfLinesPerPage+5; i++ {
+		lines = append(lines, pdfLine{Text: fmt.Sprintf("line %d", i)})
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, writeTextPDF(&out, "Test", lines))
+	pdf := out.String()
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "/Count 2")
+	assert.Contains(t, pdf, `(Report \(draft\) \\ ?berblick)`)
+	assert.Contains(t, pdf, "(Test - page 2 of 2)")
+
+	// Every xref entry points at the start of its object
+	xref := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf, -1)
+	require.Len(t, xref, 8)
+	for i, entry := range xref {
+		offset, err := strconv.Atoi(entry[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(pdf[offset:], fmt.Sprintf("%d 0 obj", i+1)), "object %d", i+1)
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(pdf)
+	require.NotNil(t, startxref)
+	offset, _ := strconv.Atoi(startxref[1])
+	assert.True(t, strings.HasPrefix(pdf[offset:], "xref"))
+}
+
+func TestWrapPDFText(t *testing.T) {
+	assert.Equal(t, []string{"short"}, wrapPDFText("short", 10))
+	assert.Equal(t, []string{"  one two", "  three"}, wrapPDFText("  one two three", 10))
+	assert.Equal(t, []string{"abcdefghij", "klm"}, wrapPDFText("abcdefghijklm", 10))
+}
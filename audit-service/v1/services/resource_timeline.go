@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// managementEventType is recorded by the portal and other admin APIs for changes to managed resources;
+	// its additional metadata names the resource and, for bulk operations, the items acted on
+	managementEventType = "MANAGEMENT_EVENT"
+
+	// maxTimelineEvents caps the events returned for one resource
+	maxTimelineEvents = 1000
+
+	// maxResourceIDLength bounds the resource ID accepted in timeline requests
+	maxResourceIDLength = 255
+)
+
+// Relations of a management event to the resource whose timeline it is in
+const (
+	// TimelineRelationSelf events were recorded on the resource itself
+	TimelineRelationSelf = "self"
+	// TimelineRelationBulk events were recorded on a bulk operation that included the resource
+	TimelineRelationBulk = "bulk"
+	// TimelineRelationRelated events were recorded on another resource and name the resource as related
+	TimelineRelationRelated = "related"
+)
+
+// errTimelineFull stops streaming once a timeline has more events than are returned
+var errTimelineFull = errors.New("timeline full")
+
+// managementEventMetadata is the part of a management event's additional metadata naming the resources it concerns
+type managementEventMetadata struct {
+	Resource   string  `json:"resource"`
+	ResourceID *string `json:"resourceId"`
+	Operation  string  `json:"operation"`
+	// Items are the resources a bulk operation acted on
+	Items []resourceRef `json:"items"`
+	// RelatedResources are other resources the event concerns, e.g. the application a submission renews
+	RelatedResources []resourceRef `json:"relatedResources"`
+}
+
+// resourceRef names a resource in management event metadata
+type resourceRef struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// GetResourceTimeline returns the management events of one resource, oldest first: the events recorded on it,
+// on bulk operations that included it and on other resources naming it as related, whichever service recorded
+// them. Resource types are compared case-insensitively and in singular or plural, so "applications" matches
+// events recorded on APPLICATIONS and bulk items of type application. entityIDs limits the events as for GetTrace.
+func (s *AuditService) GetResourceTimeline(ctx context.Context, resourceType, resourceID string, entityIDs []string) (*v1models.ResourceTimelineResponse, error) {
+	resourceType = normalizeResourceType(resourceType)
+	if resourceType == "" {
+		return nil, fmt.Errorf("%w: resource type is required", ErrInvalidInput)
+	}
+	resourceID = strings.TrimSpace(resourceID)
+	if resourceID == "" {
+		return nil, fmt.Errorf("%w: resource ID is required", ErrInvalidInput)
+	}
+	if len(resourceID) > maxResourceIDLength {
+		return nil, fmt.Errorf("%w: resource ID exceeds %d characters", ErrInvalidInput, maxResourceIDLength)
+	}
+	if strings.ContainsAny(resourceID, `"\`) {
+		return nil, fmt.Errorf("%w: resource ID must not contain quotes or backslashes", ErrInvalidInput)
+	}
+
+	response := &v1models.ResourceTimelineResponse{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Events:       []v1models.ResourceTimelineEvent{},
+	}
+	eventType := managementEventType
+	filters := &database.AuditLogFilters{
+		EventType:     &eventType,
+		MentionedID:   &resourceID,
+		EntityIDs:     entityIDs,
+		SortAscending: true,
+	}
+	err := s.repo.StreamAuditLogs(ctx, filters, 500, func(logs []v1models.AuditLog) error {
+		for _, log := range logs {
+			var metadata managementEventMetadata
+			if len(log.AdditionalMetadata) > 0 {
+				if err := json.Unmarshal(log.AdditionalMetadata, &metadata); err != nil {
+					continue
+				}
+			}
+			relation := timelineRelation(&metadata, resourceType, resourceID)
+			if relation == "" {
+				continue
+			}
+			if len(response.Events) == maxTimelineEvents {
+				response.Truncated = true
+				return errTimelineFull
+			}
+			response.Events = append(response.Events, v1models.ResourceTimelineEvent{
+				AuditLogResponse: v1models.ToAuditLogResponse(log),
+				Relation:         relation,
+				Resource:         metadata.Resource,
+				ResourceID:       derefString(metadata.ResourceID),
+				Operation:        metadata.Operation,
+			})
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errTimelineFull) {
+		return nil, fmt.Errorf("failed to retrieve resource timeline: %w", err)
+	}
+	if len(response.Events) == 0 {
+		return nil, ErrResourceNotFound
+	}
+	response.EventCount = len(response.Events)
+	return response, nil
+}
+
+// timelineRelation returns how a management event concerns the resource, or "" if it does not
+func timelineRelation(metadata *managementEventMetadata, resourceType, resourceID string) string {
+	if normalizeResourceType(metadata.Resource) == resourceType && derefString(metadata.ResourceID) == resourceID {
+		return TimelineRelationSelf
+	}
+	for _, item := range metadata.Items {
+		if item.ID == resourceID && normalizeResourceType(item.Type) == resourceType {
+			return TimelineRelationBulk
+		}
+	}
+	for _, related := range metadata.RelatedResources {
+		if related.ID == resourceID && normalizeResourceType(related.Type) == resourceType {
+			return TimelineRelationRelated
+		}
+	}
+	return ""
+}
+
+// normalizeResourceType maps the spellings producers use for a resource type to one form:
+// "applications", "APPLICATIONS" and "application" all become APPLICATION
+func normalizeResourceType(resourceType string) string {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(resourceType), "_", "-"))
+	return strings.TrimSuffix(normalized, "S")
+}
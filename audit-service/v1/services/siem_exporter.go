@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// siemDialTimeout and siemWriteTimeout bound connecting to the SIEM and delivering one log
+	siemDialTimeout  = 10 * time.Second
+	siemWriteTimeout = 10 * time.Second
+
+	// siemReconnectMinDelay and siemReconnectMaxDelay bound the backoff between connection attempts
+	siemReconnectMinDelay = time.Second
+	siemReconnectMaxDelay = time.Minute
+
+	// siemDrainTimeout bounds delivering the buffered logs when the exporter stops
+	siemDrainTimeout = 5 * time.Second
+
+	// siemDropLogInterval logs every so many dropped logs, so that a full buffer does not flood the service log
+	siemDropLogInterval = 1000
+)
+
+// siemCounters counts the exporter's activity since the service started
+type siemCounters struct {
+	exported atomic.Int64
+	dropped  atomic.Int64
+	failed   atomic.Int64
+
+	connected    atomic.Bool
+	lastExportAt atomic.Int64 // unix nanoseconds, 0 if no log was delivered
+
+	mu          sync.Mutex
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// SIEMExporter forwards stored audit logs to a SIEM as CEF or LEEF events over syslog, so that security
+// operations can monitor the exchange centrally. Logs are buffered in memory and delivered in order by a
+// single connection that is re-established with exponential backoff; delivery is at-most-once, and the
+// buffer does not survive restarts.
+type SIEMExporter struct {
+	config    *config.SIEMConfig
+	tlsConfig *tls.Config
+	hostname  string
+
+	queue chan *v1models.AuditLog
+	stats siemCounters
+
+	// running tracks the delivery goroutine
+	running sync.WaitGroup
+}
+
+// NewSIEMExporter creates an exporter for a validated, enabled configuration
+func NewSIEMExporter(cfg *config.SIEMConfig) (*SIEMExporter, error) {
+	exporter := &SIEMExporter{
+		config:   cfg,
+		hostname: cfg.Hostname,
+		queue:    make(chan *v1models.AuditLog, cfg.BufferSize),
+	}
+	if exporter.hostname == "" {
+		exporter.hostname, _ = os.Hostname()
+	}
+
+	if cfg.Transport == config.SIEMTransportTLS {
+		tlsConfig, err := siemTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		exporter.tlsConfig = tlsConfig
+	}
+	return exporter, nil
+}
+
+// siemTLSConfig loads the CA and client certificate of the TLS connection
+func siemTLSConfig(cfg *config.SIEMConfig) (*tls.Config, error) {
+	host, _, _ := net.SplitHostPort(cfg.Address)
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: cfg.TLS.ServerName}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+
+	if cfg.TLS.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SIEM CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in SIEM CA file %s", cfg.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	if cfg.TLS.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SIEM client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// Export queues a stored log for delivery. It never blocks: the log is dropped if the buffer is full.
+func (e *SIEMExporter) Export(log *v1models.AuditLog) {
+	select {
+	case e.queue <- log:
+	default:
+		if dropped := e.stats.dropped.Add(1); dropped%siemDropLogInterval == 1 {
+			slog.Warn("SIEM export buffer is full, dropping audit logs", "bufferSize", e.config.BufferSize, "dropped", dropped)
+		}
+	}
+}
+
+// Start delivers the queued logs until the context is cancelled, then delivers what is still buffered
+// over the open connection, if any
+func (e *SIEMExporter) Start(ctx context.Context) {
+	e.running.Add(1)
+	defer e.running.Done()
+
+	slog.Info("SIEM exporter started", "address", e.config.Address, "transport", e.config.Transport, "format", e.config.Format)
+	var conn net.Conn
+	var pending *v1models.AuditLog
+	delay := siemReconnectMinDelay
+	defer func() {
+		e.drain(conn, pending)
+		slog.Info("SIEM exporter stopped")
+	}()
+
+	for {
+		if conn == nil {
+			var err error
+			if conn, err = e.dial(ctx); err != nil {
+				e.recordError(fmt.Errorf("connect: %w", err))
+				slog.Error("Failed to connect to SIEM, reconnecting", "address", e.config.Address, "error", err, "retryIn", delay)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delay):
+				}
+				delay = min(delay*2, siemReconnectMaxDelay)
+				continue
+			}
+			delay = siemReconnectMinDelay
+		}
+
+		if pending == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case pending = <-e.queue:
+			}
+		}
+
+		if err := e.deliver(conn, pending); err != nil {
+			e.recordError(fmt.Errorf("deliver: %w", err))
+			slog.Error("Failed to deliver audit log to SIEM, reconnecting", "auditLogId", pending.ID, "error", err)
+			e.disconnect(conn)
+			conn = nil
+			continue
+		}
+		pending = nil
+	}
+}
+
+// Wait blocks until the exporter has stopped
+func (e *SIEMExporter) Wait() {
+	e.running.Wait()
+}
+
+// drain delivers the pending and buffered logs over conn, giving up at the first failure or after siemDrainTimeout
+func (e *SIEMExporter) drain(conn net.Conn, pending *v1models.AuditLog) {
+	if conn == nil {
+		return
+	}
+	defer e.disconnect(conn)
+
+	deadline := time.Now().Add(siemDrainTimeout)
+	for time.Now().Before(deadline) {
+		if pending == nil {
+			select {
+			case pending = <-e.queue:
+			default:
+				return
+			}
+		}
+		if err := e.deliver(conn, pending); err != nil {
+			e.recordError(fmt.Errorf("deliver: %w", err))
+			return
+		}
+		pending = nil
+	}
+}
+
+// dial connects to the SIEM over the configured transport
+func (e *SIEMExporter) dial(ctx context.Context) (net.Conn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, siemDialTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	switch e.config.Transport {
+	case config.SIEMTransportTLS:
+		dialer := &tls.Dialer{Config: e.tlsConfig}
+		conn, err = dialer.DialContext(dialCtx, "tcp", e.config.Address)
+	case config.SIEMTransportUDP:
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(dialCtx, "udp", e.config.Address)
+	default:
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(dialCtx, "tcp", e.config.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.stats.connected.Store(true)
+	return conn, nil
+}
+
+func (e *SIEMExporter) disconnect(conn net.Conn) {
+	e.stats.connected.Store(false)
+	_ = conn.Close()
+}
+
+// deliver writes one log as a syslog message: a datagram over UDP, octet-counted over TCP and TLS
+func (e *SIEMExporter) deliver(conn net.Conn, log *v1models.AuditLog) error {
+	message := FormatSyslogMessage(e.config, e.hostname, log)
+	if e.config.Transport != config.SIEMTransportUDP {
+		message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+	}
+
+	if err := conn.SetWriteDeadline(time.Now().Add(siemWriteTimeout)); err != nil {
+		return err
+	}
+	if _, err := conn.Write(message); err != nil {
+		return err
+	}
+	e.stats.exported.Add(1)
+	e.stats.lastExportAt.Store(time.Now().UnixNano())
+	return nil
+}
+
+func (e *SIEMExporter) recordError(err error) {
+	e.stats.failed.Add(1)
+	e.stats.mu.Lock()
+	defer e.stats.mu.Unlock()
+	e.stats.lastError = err.Error()
+	e.stats.lastErrorAt = time.Now().UTC()
+}
+
+// CheckHealth reports the exporter as unhealthy while it is disconnected from the SIEM or its buffer is nearly full
+func (e *SIEMExporter) CheckHealth(ctx context.Context) error {
+	if !e.stats.connected.Load() {
+		return fmt.Errorf("not connected to SIEM at %s", e.config.Address)
+	}
+	if queued := len(e.queue); queued*10 >= cap(e.queue)*9 {
+		return errors.New("SIEM export buffer is nearly full")
+	}
+	return nil
+}
+
+// Status returns the exporter's delivery counters; a nil exporter reports export as disabled
+func (e *SIEMExporter) Status() *v1models.SIEMExportStatus {
+	if e == nil {
+		return &v1models.SIEMExportStatus{}
+	}
+	status := &v1models.SIEMExportStatus{
+		Enabled:    true,
+		Address:    e.config.Address,
+		Transport:  e.config.Transport,
+		Format:     e.config.Format,
+		Connected:  e.stats.connected.Load(),
+		Queued:     len(e.queue),
+		BufferSize: cap(e.queue),
+		Exported:   e.stats.exported.Load(),
+		Dropped:    e.stats.dropped.Load(),
+		Failed:     e.stats.failed.Load(),
+	}
+	if last := e.stats.lastExportAt.Load(); last != 0 {
+		lastExportAt := time.Unix(0, last).UTC()
+		status.LastExportAt = &lastExportAt
+	}
+
+	e.stats.mu.Lock()
+	defer e.stats.mu.Unlock()
+	if e.stats.lastError != "" {
+		lastErrorAt := e.stats.lastErrorAt
+		status.LastError = e.stats.lastError
+		status.LastErrorAt = &lastErrorAt
+	}
+	return status
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/config"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSIEMConfig(t *testing.T, format string, address string) *config.SIEMConfig {
+	cfg := &config.SIEMConfig{
+		Enabled:   true,
+		Address:   address,
+		Transport: config.SIEMTransportTCP,
+		Format:    format,
+		Hostname:  "audit-1",
+		Mapping: config.SIEMMapping{
+			EventTypes: map[string]config.SIEMEventMapping{
+				"POLICY_CHECK": {Name: "Policy decision"},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	return cfg
+}
+
+func newSIEMTestLog() *v1models.AuditLog {
+	traceID := uuid.MustParse("3f2504e0-4f89-11d3-9a0c-0305e82c3301")
+	return &v1models.AuditLog{
+		ID:              uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+		Timestamp:       time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC),
+		TraceID:         &traceID,
+		Status:          v1models.StatusFailure,
+		EventType:       stringPtr("POLICY_CHECK"),
+		EventAction:     stringPtr("READ"),
+		ActorType:       "SERVICE",
+		ActorID:         "orchestration-engine",
+		TargetType:      "SERVICE",
+		TargetID:        stringPtr("policy-decision-point"),
+		RequestMetadata: v1models.JSONBRawMessage(`{"applicationId":"app=1","requiredFields":["person.nic"]}`),
+	}
+}
+
+func TestFormatSIEMEvent_CEF(t *testing.T) {
+	cfg := newSIEMConfig(t, config.SIEMFormatCEF, "siem:514")
+	cfg.Mapping.Fields = map[string]string{
+		"suser":  "actorId",
+		"rt":     "timestamp",
+		"cs1":    "requestMetadata.applicationId",
+		"cs2":    "requestMetadata.requiredFields",
+		"cs3":    "correlationId", // unset, so omitted
+		"cn1":    "requestMetadata.missing",
+		"act":    "eventAction",
+		"duid":   "targetId",
+		"reason": "status",
+	}
+
+	event := FormatSIEMEvent(cfg, newSIEMTestLog())
+	assert.Equal(t, `CEF:0|OpenDIF|Audit Service|1.0|POLICY_CHECK|Policy decision|7|`+
+		`act=READ cs1=app\=1 cs1Label=requestMetadata.applicationId `+
+		`cs2=["person.nic"] cs2Label=requestMetadata.requiredFields `+
+		`duid=policy-decision-point reason=FAILURE rt=1772361000000 suser=orchestration-engine`, event)
+}
+
+func TestFormatSIEMEvent_LEEF(t *testing.T) {
+	cfg := newSIEMConfig(t, config.SIEMFormatLEEF, "siem:514")
+	log := newSIEMTestLog()
+	log.Status = v1models.StatusSuccess
+	log.ActorID = "user^1"
+
+	event := FormatSIEMEvent(cfg, log)
+	assert.Equal(t, `LEEF:2.0|OpenDIF|Audit Service|1.0|POLICY_CHECK|^|cat=Policy decision^sev=3^`+
+		`action=READ^actorType=SERVICE^devTime=1772361000000^externalId=6ba7b810-9dad-11d1-80b4-00c04fd430c8^`+
+		`outcome=SUCCESS^resource=policy-decision-point^targetType=SERVICE^`+
+		`traceId=3f2504e0-4f89-11d3-9a0c-0305e82c3301^usrName=user\^1`, event)
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	cfg := newSIEMConfig(t, config.SIEMFormatCEF, "siem:514")
+	log := newSIEMTestLog()
+
+	message := string(FormatSyslogMessage(cfg, "audit 1", log))
+	// Facility 13 with a failure severity of 7 (syslog error): 13*8 + 3
+	assert.True(t, strings.HasPrefix(message,
+		"<107>1 2026-03-01T10:30:00.000000Z audit1 audit-service - POLICY_CHECK - CEF:0|"), message)
+
+	log.EventType = nil
+	log.Status = v1models.StatusSuccess
+	message = string(FormatSyslogMessage(cfg, "", log))
+	assert.True(t, strings.HasPrefix(message,
+		"<110>1 2026-03-01T10:30:00.000000Z - audit-service - - - CEF:0|OpenDIF|Audit Service|1.0|UNKNOWN|UNKNOWN|3|"), message)
+}
+
+func TestSIEMExporter_DeliversOverTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			// Octet-counted frames: "<length> <message>"
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			size, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+			message := make([]byte, size)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				return
+			}
+			received <- string(message)
+		}
+	}()
+
+	exporter, err := NewSIEMExporter(newSIEMConfig(t, config.SIEMFormatCEF, listener.Addr().String()))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	go exporter.Start(ctx)
+
+	log := newSIEMTestLog()
+	exporter.Export(log)
+	exporter.Export(log)
+	for range 2 {
+		select {
+		case message := <-received:
+			assert.Contains(t, message, "audit-1 audit-service - POLICY_CHECK - CEF:0|")
+			assert.Contains(t, message, "externalId="+log.ID.String())
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the SIEM to receive the log")
+		}
+	}
+
+	cancel()
+	exporter.Wait()
+
+	status := exporter.Status()
+	assert.True(t, status.Enabled)
+	assert.Equal(t, int64(2), status.Exported)
+	assert.Equal(t, int64(0), status.Dropped)
+	assert.NotNil(t, status.LastExportAt)
+	assert.False(t, status.Connected)
+}
+
+func TestSIEMExporter_DropsWhenBufferIsFull(t *testing.T) {
+	cfg := newSIEMConfig(t, config.SIEMFormatCEF, "127.0.0.1:1")
+	cfg.BufferSize = 2
+	exporter, err := NewSIEMExporter(cfg)
+	require.NoError(t, err)
+
+	// Not started, so nothing leaves the buffer
+	for range 5 {
+		exporter.Export(newSIEMTestLog())
+	}
+
+	status := exporter.Status()
+	assert.Equal(t, 2, status.Queued)
+	assert.Equal(t, 2, status.BufferSize)
+	assert.Equal(t, int64(3), status.Dropped)
+	assert.Error(t, exporter.CheckHealth(context.Background()))
+}
+
+func TestSIEMExporter_NilStatus(t *testing.T) {
+	var exporter *SIEMExporter
+	assert.False(t, exporter.Status().Enabled)
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+// siemTimestampLayout is the RFC 5424 timestamp, which allows at most microsecond precision
+const siemTimestampLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// leefDelimiter separates LEEF attributes; it is declared in the LEEF 2.0 header
+const leefDelimiter = "^"
+
+// cefCustomString matches the CEF custom string keys, which are labeled with the attribute they carry
+var cefCustomString = regexp.MustCompile(`^cs[1-6]$`)
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+	leefValueEscaper    = strings.NewReplacer(leefDelimiter, `\`+leefDelimiter, "\r", " ", "\n", " ", "\t", " ")
+)
+
+// siemField is an extension key and the value of the log attribute mapped to it
+type siemField struct {
+	key   string
+	value string
+}
+
+// FormatSIEMEvent renders a log as a CEF or LEEF event, without the syslog header
+func FormatSIEMEvent(cfg *config.SIEMConfig, log *v1models.AuditLog) string {
+	eventType := derefString(log.EventType)
+	if eventType == "" {
+		eventType = "UNKNOWN"
+	}
+	severity := cfg.Mapping.Severity(eventType, log.Status == v1models.StatusFailure)
+	fields := siemFields(cfg.Mapping.Fields, log)
+
+	var event strings.Builder
+	if cfg.Format == config.SIEMFormatLEEF {
+		fmt.Fprintf(&event, "LEEF:2.0|%s|%s|%s|%s|%s|", cefHeaderEscaper.Replace(cfg.Vendor),
+			cefHeaderEscaper.Replace(cfg.Product), cefHeaderEscaper.Replace(cfg.Version),
+			cefHeaderEscaper.Replace(eventType), leefDelimiter)
+		// LEEF severities start at 1
+		fields = append([]siemField{
+			{key: "cat", value: cfg.Mapping.EventName(eventType)},
+			{key: "sev", value: strconv.Itoa(max(severity, 1))},
+		}, fields...)
+		for i, field := range fields {
+			if i > 0 {
+				event.WriteString(leefDelimiter)
+			}
+			event.WriteString(field.key + "=" + leefValueEscaper.Replace(field.value))
+		}
+		return event.String()
+	}
+
+	fmt.Fprintf(&event, "CEF:0|%s|%s|%s|%s|%s|%d|", cefHeaderEscaper.Replace(cfg.Vendor),
+		cefHeaderEscaper.Replace(cfg.Product), cefHeaderEscaper.Replace(cfg.Version),
+		cefHeaderEscaper.Replace(eventType), cefHeaderEscaper.Replace(cfg.Mapping.EventName(eventType)), severity)
+	for i, field := range fields {
+		if i > 0 {
+			event.WriteByte(' ')
+		}
+		event.WriteString(field.key + "=" + cefExtensionEscaper.Replace(field.value))
+		if cefCustomString.MatchString(field.key) {
+			event.WriteString(" " + field.key + "Label=" + cefExtensionEscaper.Replace(cfg.Mapping.Fields[field.key]))
+		}
+	}
+	return event.String()
+}
+
+// FormatSyslogMessage wraps a SIEM event in an RFC 5424 syslog message
+func FormatSyslogMessage(cfg *config.SIEMConfig, hostname string, log *v1models.AuditLog) []byte {
+	eventType := derefString(log.EventType)
+	severity := cfg.Mapping.Severity(eventType, log.Status == v1models.StatusFailure)
+	priority := cfg.Facility*8 + syslogSeverity(severity)
+
+	return fmt.Appendf(nil, "<%d>1 %s %s %s - %s - %s", priority, log.Timestamp.UTC().Format(siemTimestampLayout),
+		syslogHeaderValue(hostname, 255), syslogHeaderValue(cfg.AppName, 48), syslogHeaderValue(eventType, 32),
+		FormatSIEMEvent(cfg, log))
+}
+
+// syslogSeverity maps a SIEM severity (0 to 10) to a syslog severity
+func syslogSeverity(severity int) int {
+	switch {
+	case severity >= 9:
+		return 2 // critical
+	case severity >= 7:
+		return 3 // error
+	case severity >= 4:
+		return 4 // warning
+	default:
+		return 6 // informational
+	}
+}
+
+// syslogHeaderValue restricts a header value to printable ASCII of at most maxLength characters,
+// using the nil value - when it is empty
+func syslogHeaderValue(value string, maxLength int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	}
+	if len(value) > maxLength {
+		value = value[:maxLength]
+	}
+	return value
+}
+
+// siemFields returns the mapped attributes of the log that have a value, ordered by key
+func siemFields(mapping map[string]string, log *v1models.AuditLog) []siemField {
+	fields := make([]siemField, 0, len(mapping))
+	for key, attribute := range mapping {
+		if value := siemAttribute(log, attribute); value != "" {
+			fields = append(fields, siemField{key: key, value: value})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].key < fields[j].key })
+	return fields
+}
+
+// siemAttribute returns the value of a log attribute; timestamps are milliseconds since the epoch
+func siemAttribute(log *v1models.AuditLog, attribute string) string {
+	switch attribute {
+	case "id":
+		return log.ID.String()
+	case "eventId":
+		return derefString(log.EventID)
+	case "timestamp":
+		return strconv.FormatInt(log.Timestamp.UnixMilli(), 10)
+	case "traceId":
+		if log.TraceID == nil {
+			return ""
+		}
+		return log.TraceID.String()
+	case "correlationId":
+		return derefString(log.CorrelationID)
+	case "source":
+		return log.Source
+	case "status":
+		return log.Status
+	case "eventType":
+		return derefString(log.EventType)
+	case "eventAction":
+		return derefString(log.EventAction)
+	case "actorType":
+		return log.ActorType
+	case "actorId":
+		return log.ActorID
+	case "targetType":
+		return log.TargetType
+	case "targetId":
+		return derefString(log.TargetID)
+	case "hash":
+		return log.Hash
+	}
+
+	if key, ok := strings.CutPrefix(attribute, "requestMetadata."); ok {
+		return metadataValue(log.RequestMetadata, key)
+	}
+	if key, ok := strings.CutPrefix(attribute, "responseMetadata."); ok {
+		return metadataValue(log.ResponseMetadata, key)
+	}
+	if key, ok := strings.CutPrefix(attribute, "additionalMetadata."); ok {
+		return metadataValue(log.AdditionalMetadata, key)
+	}
+	return ""
+}
+
+// metadataValue returns a top-level value of a metadata object: strings as they are, other values as JSON
+func metadataValue(metadata v1models.JSONBRawMessage, key string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(metadata, &object); err != nil {
+		return ""
+	}
+	raw, ok := object[key]
+	if !ok || string(raw) == "null" {
+		return ""
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return ""
+	}
+	return compact.String()
+}
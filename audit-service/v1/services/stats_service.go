@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// defaultStatsWindow is the period aggregated when no start time is given
+	defaultStatsWindow = 30 * 24 * time.Hour
+
+	// defaultStatsLimit and maxStatsLimit bound the length of the top-N lists
+	defaultStatsLimit = 10
+	maxStatsLimit     = 100
+
+	// maxStatsBuckets caps the timeline, e.g. hourly buckets are available for windows of up to about six weeks
+	maxStatsBuckets = 1000
+
+	// statsBatchSize is how many logs are read from the repository at a time
+	statsBatchSize = 1000
+
+	// consentCheckEventType is logged by the orchestration engine for each consent engine call;
+	// its response metadata carries the consent's status
+	consentCheckEventType = "CONSENT_CHECK"
+	// consentDeniedStatus is the consent status recorded when the data owner rejected the request
+	consentDeniedStatus = "rejected"
+
+	// policyCheckEventType is logged by the orchestration engine for each policy decision;
+	// its request metadata lists the requiredFields of the query
+	policyCheckEventType = "POLICY_CHECK"
+)
+
+// Time buckets for the statistics timeline
+const (
+	StatsBucketHour = "hour"
+	StatsBucketDay  = "day"
+	StatsBucketWeek = "week"
+)
+
+// Grouping dimensions for statistics
+const (
+	StatsGroupByEventType = "eventType"
+	StatsGroupByStatus    = "status"
+	StatsGroupByActorType = "actorType"
+	StatsGroupByActorID   = "actorId"
+	StatsGroupByTargetID  = "targetId"
+	StatsGroupByConsumer  = "consumer"
+)
+
+// statsGroupKeys extracts the value of each grouping dimension from a log
+var statsGroupKeys = map[string]func(*statsLog) string{
+	StatsGroupByEventType: func(l *statsLog) string { return derefString(l.EventType) },
+	StatsGroupByStatus:    func(l *statsLog) string { return l.Status },
+	StatsGroupByActorType: func(l *statsLog) string { return l.ActorType },
+	StatsGroupByActorID:   func(l *statsLog) string { return l.ActorID },
+	StatsGroupByTargetID:  func(l *statsLog) string { return derefString(l.TargetID) },
+	StatsGroupByConsumer:  func(l *statsLog) string { return l.consumer },
+}
+
+// StatsService aggregates audit logs into counts and trends for dashboards
+type StatsService struct {
+	repo database.AuditRepository
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(repo database.AuditRepository) *StatsService {
+	return &StatsService{repo: repo}
+}
+
+// GetStats aggregates the logs between startTime and endTime (RFC3339; by default the last 30 days):
+// totals, a timeline per bucket, a breakdown by the groupBy dimension, the top consumer applications
+// and the most requested fields.
+// Logs are read in batches and aggregated in memory, so the cost grows with the number of logs in the window.
+func (s *StatsService) GetStats(ctx context.Context, req *v1models.GetStatsRequest) (*v1models.StatsResponse, error) {
+	start, end, err := parseTimeWindow(req.StartTime, req.EndTime, defaultStatsWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := req.Bucket
+	if bucket == "" {
+		bucket = StatsBucketDay
+	}
+	switch bucket {
+	case StatsBucketHour, StatsBucketDay, StatsBucketWeek:
+	default:
+		return nil, fmt.Errorf("%w: invalid bucket %q, expected hour, day or week", ErrInvalidInput, bucket)
+	}
+	buckets := statsBuckets(start, end, bucket)
+	if len(buckets) > maxStatsBuckets {
+		return nil, fmt.Errorf("%w: window spans more than %d %s buckets, use a shorter window or a larger bucket", ErrInvalidInput, maxStatsBuckets, bucket)
+	}
+
+	groupBy := req.GroupBy
+	if groupBy == "" {
+		groupBy = StatsGroupByEventType
+	}
+	groupKey, ok := statsGroupKeys[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid groupBy %q", ErrInvalidInput, groupBy)
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultStatsLimit
+	}
+	if limit < 0 || limit > maxStatsLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxStatsLimit)
+	}
+
+	filters := &database.AuditLogFilters{StartTime: &start, EndTime: &end, SortAscending: true}
+	if req.EventType != "" {
+		filters.EventType = &req.EventType
+	}
+	if req.ConsumerAppID != "" {
+		filters.ConsumerAppID = &req.ConsumerAppID
+	}
+
+	response := &v1models.StatsResponse{
+		StartTime: start,
+		EndTime:   end,
+		Bucket:    bucket,
+		GroupBy:   groupBy,
+		Timeline:  make([]v1models.StatsBucket, len(buckets)),
+	}
+	bucketIndex := make(map[time.Time]int, len(buckets))
+	for i, bucketStart := range buckets {
+		response.Timeline[i] = v1models.StatsBucket{Start: bucketStart, Groups: map[string]int64{}}
+		bucketIndex[bucketStart] = i
+	}
+	groups := make(map[string]*v1models.StatsCounts)
+	consumers := make(map[string]*v1models.StatsCounts)
+	fields := make(map[string]int64)
+
+	err = s.repo.StreamAuditLogs(ctx, filters, statsBatchSize, func(batch []v1models.AuditLog) error {
+		for i := range batch {
+			log := newStatsLog(&batch[i])
+
+			addStatsCounts(&response.Totals, log)
+			timelineBucket := &response.Timeline[bucketIndex[truncateToBucket(log.Timestamp, bucket)]]
+			addStatsCounts(&timelineBucket.StatsCounts, log)
+			key := groupKey(log)
+			timelineBucket.Groups[key]++
+			addStatsCounts(statsCountsFor(groups, key), log)
+			if log.consumer != "" {
+				addStatsCounts(statsCountsFor(consumers, log.consumer), log)
+			}
+			for _, field := range log.accessedFields {
+				fields[field]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	setFailureRate(&response.Totals)
+	for i := range response.Timeline {
+		setFailureRate(&response.Timeline[i].StatsCounts)
+	}
+	response.Groups = rankStatsGroups(groups, 0)
+	response.TopConsumers = rankStatsGroups(consumers, limit)
+	response.TopFields = rankFields(fields, limit)
+	return response, nil
+}
+
+// statsLog is an audit log with the values derived from its metadata that statistics need
+type statsLog struct {
+	*v1models.AuditLog
+
+	// consumer is the consumer application the log concerns, if any
+	consumer string
+	// consentDenied is set for consent checks the data owner rejected
+	consentDenied bool
+	// accessedFields are the fields requested in an authorized policy check
+	accessedFields []string
+}
+
+func newStatsLog(log *v1models.AuditLog) *statsLog {
+	request, response := parseExchangeMetadata(log)
+	l := &statsLog{AuditLog: log, consumer: exchangeConsumer(log, request, response)}
+
+	eventType := derefString(log.EventType)
+	l.consentDenied = eventType == consentCheckEventType && response.Status == consentDeniedStatus
+	if eventType == policyCheckEventType && log.Status == v1models.StatusSuccess {
+		l.accessedFields = request.RequiredFields
+	}
+	return l
+}
+
+func addStatsCounts(counts *v1models.StatsCounts, log *statsLog) {
+	counts.Events++
+	if log.Status == v1models.StatusFailure {
+		counts.Failures++
+	}
+	if log.consentDenied {
+		counts.ConsentDenied++
+	}
+}
+
+func setFailureRate(counts *v1models.StatsCounts) {
+	if counts.Events > 0 {
+		counts.FailureRate = float64(counts.Failures) / float64(counts.Events)
+	}
+}
+
+func statsCountsFor(counts map[string]*v1models.StatsCounts, key string) *v1models.StatsCounts {
+	c, ok := counts[key]
+	if !ok {
+		c = &v1models.StatsCounts{}
+		counts[key] = c
+	}
+	return c
+}
+
+// rankStatsGroups sorts groups by event count (then key) and keeps the first limit, or all if limit is 0
+func rankStatsGroups(counts map[string]*v1models.StatsCounts, limit int) []v1models.StatsGroup {
+	groups := make([]v1models.StatsGroup, 0, len(counts))
+	for key, c := range counts {
+		setFailureRate(c)
+		groups = append(groups, v1models.StatsGroup{Key: key, StatsCounts: *c})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Events != groups[j].Events {
+			return groups[i].Events > groups[j].Events
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+	return groups
+}
+
+// rankFields sorts fields by request count (then name) and keeps the first limit
+func rankFields(counts map[string]int64, limit int) []v1models.FieldAccessCount {
+	fields := make([]v1models.FieldAccessCount, 0, len(counts))
+	for field, count := range counts {
+		fields = append(fields, v1models.FieldAccessCount{Field: field, Count: count})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].Count != fields[j].Count {
+			return fields[i].Count > fields[j].Count
+		}
+		return fields[i].Field < fields[j].Field
+	})
+	if len(fields) > limit {
+		fields = fields[:limit]
+	}
+	return fields
+}
+
+// truncateToBucket returns the start of the UTC bucket containing t; weeks start on Monday
+func truncateToBucket(t time.Time, bucket string) time.Time {
+	t = t.UTC()
+	switch bucket {
+	case StatsBucketHour:
+		return t.Truncate(time.Hour)
+	case StatsBucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// statsBuckets lists the starts of the buckets overlapping [start, end).
+// Listing stops just past maxStatsBuckets, which is enough for the caller to reject the window.
+func statsBuckets(start, end time.Time, bucket string) []time.Time {
+	var buckets []time.Time
+	for b := truncateToBucket(start, bucket); b.Before(end) && len(buckets) <= maxStatsBuckets; {
+		buckets = append(buckets, b)
+		switch bucket {
+		case StatsBucketHour:
+			b = b.Add(time.Hour)
+		case StatsBucketWeek:
+			b = b.AddDate(0, 0, 7)
+		default:
+			b = b.AddDate(0, 0, 1)
+		}
+	}
+	return buckets
+}
+
+// parseTimeWindow parses an RFC3339 window; endTime defaults to now and startTime to defaultWindow before endTime
+func parseTimeWindow(startTime, endTime string, defaultWindow time.Duration) (time.Time, time.Time, error) {
+	end := time.Now().UTC()
+	if endTime != "" {
+		parsed, err := time.Parse(time.RFC3339, endTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid endTime format, expected RFC3339: %w", ErrInvalidInput, err)
+		}
+		end = parsed.UTC()
+	}
+	start := end.Add(-defaultWindow)
+	if startTime != "" {
+		parsed, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid startTime format, expected RFC3339: %w", ErrInvalidInput, err)
+		}
+		start = parsed.UTC()
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: endTime must be after startTime", ErrInvalidInput)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsService_GetStats(t *testing.T) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+	service := NewStatsService(repo)
+	ctx := context.Background()
+
+	// Monday 2026-01-05 and Tuesday 2026-01-06
+	monday := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	tuesday := monday.Add(24 * time.Hour)
+
+	create := func(timestamp time.Time, eventType, status, actorType, actorID, request, response string) {
+		log := &v1models.AuditLog{
+			Timestamp:  timestamp,
+			EventType:  stringPtr(eventType),
+			Status:     status,
+			ActorType:  actorType,
+			ActorID:    actorID,
+			TargetType: "SERVICE",
+		}
+		if request != "" {
+			log.RequestMetadata = v1models.JSONBRawMessage(request)
+		}
+		if response != "" {
+			log.ResponseMetadata = v1models.JSONBRawMessage(response)
+		}
+		_, err := repo.CreateAuditLog(ctx, log)
+		require.NoError(t, err)
+	}
+	create(monday, "DATA_REQUEST", v1models.StatusSuccess, v1models.ActorTypeApplication, "app-1", `{"applicationId":"app-1"}`, "")
+	create(monday, "POLICY_CHECK", v1models.StatusSuccess, "SERVICE", "orchestration-engine",
+		`{"applicationId":"app-1","requiredFields":[{"fieldName":"person.name","schemaId":"schema-1"},{"fieldName":"person.address","schemaId":"schema-1"}]}`, `{"authorized":true}`)
+	create(monday, "CONSENT_CHECK", v1models.StatusSuccess, "SERVICE", "orchestration-engine",
+		`{"applicationId":"app-1"}`, `{"consentId":"c-1","status":"rejected"}`)
+	create(tuesday, "DATA_REQUEST", v1models.StatusSuccess, v1models.ActorTypeApplication, "app-2", `{"applicationId":"app-2"}`, "")
+	create(tuesday, "POLICY_CHECK", v1models.StatusSuccess, "SERVICE", "orchestration-engine",
+		`{"applicationId":"app-2","requiredFields":["person.name"]}`, `{"authorized":true}`)
+	create(tuesday, "POLICY_CHECK", v1models.StatusFailure, "SERVICE", "orchestration-engine",
+		`{"applicationId":"app-2","requiredFields":["person.salary"]}`, `{"authorized":false}`)
+	create(tuesday, "MANAGEMENT_EVENT", v1models.StatusSuccess, "ADMIN", "admin-1", "", "")
+
+	window := func(req *v1models.GetStatsRequest) *v1models.GetStatsRequest {
+		req.StartTime = monday.Truncate(24 * time.Hour).Format(time.RFC3339)
+		req.EndTime = tuesday.Truncate(24 * time.Hour).Add(24 * time.Hour).Format(time.RFC3339)
+		return req
+	}
+
+	t.Run("daily by event type", func(t *testing.T) {
+		result, err := service.GetStats(ctx, window(&v1models.GetStatsRequest{}))
+		require.NoError(t, err)
+		assert.Equal(t, StatsBucketDay, result.Bucket)
+		assert.Equal(t, StatsGroupByEventType, result.GroupBy)
+		assert.Equal(t, v1models.StatsCounts{Events: 7, Failures: 1, FailureRate: 1.0 / 7, ConsentDenied: 1}, result.Totals)
+
+		require.Len(t, result.Timeline, 2)
+		assert.Equal(t, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), result.Timeline[0].Start)
+		assert.Equal(t, int64(3), result.Timeline[0].Events)
+		assert.Equal(t, int64(1), result.Timeline[0].ConsentDenied)
+		assert.Equal(t, map[string]int64{"DATA_REQUEST": 1, "POLICY_CHECK": 1, "CONSENT_CHECK": 1}, result.Timeline[0].Groups)
+		assert.Equal(t, int64(4), result.Timeline[1].Events)
+		assert.Equal(t, 0.25, result.Timeline[1].FailureRate)
+
+		require.Len(t, result.Groups, 4)
+		assert.Equal(t, "POLICY_CHECK", result.Groups[0].Key)
+		assert.Equal(t, v1models.StatsCounts{Events: 3, Failures: 1, FailureRate: 1.0 / 3}, result.Groups[0].StatsCounts)
+
+		require.Len(t, result.TopConsumers, 2)
+		assert.Equal(t, "app-1", result.TopConsumers[0].Key)
+		assert.Equal(t, int64(3), result.TopConsumers[0].Events)
+		assert.Equal(t, int64(1), result.TopConsumers[0].ConsentDenied)
+		assert.Equal(t, "app-2", result.TopConsumers[1].Key)
+		assert.Equal(t, int64(1), result.TopConsumers[1].Failures)
+
+		// Fields of the unauthorized policy check were not accessed
+		assert.Equal(t, []v1models.FieldAccessCount{{Field: "person.name", Count: 2}, {Field: "person.address", Count: 1}}, result.TopFields)
+	})
+
+	t.Run("weekly by consumer with limit", func(t *testing.T) {
+		result, err := service.GetStats(ctx, window(&v1models.GetStatsRequest{Bucket: StatsBucketWeek, GroupBy: StatsGroupByConsumer, Limit: 1}))
+		require.NoError(t, err)
+		require.Len(t, result.Timeline, 1)
+		assert.Equal(t, monday.Truncate(24*time.Hour), result.Timeline[0].Start)
+		assert.Equal(t, map[string]int64{"app-1": 3, "app-2": 3, "": 1}, result.Timeline[0].Groups)
+		assert.Len(t, result.TopConsumers, 1)
+		assert.Len(t, result.TopFields, 1)
+	})
+
+	t.Run("scoped to a consumer", func(t *testing.T) {
+		result, err := service.GetStats(ctx, window(&v1models.GetStatsRequest{Bucket: StatsBucketHour, ConsumerAppID: "app-2"}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.Totals.Events)
+		assert.Len(t, result.Timeline, 48)
+		require.Len(t, result.TopConsumers, 1)
+		assert.Equal(t, "app-2", result.TopConsumers[0].Key)
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		result, err := service.GetStats(ctx, &v1models.GetStatsRequest{})
+		require.NoError(t, err)
+		assert.Zero(t, result.Totals.Events)
+		assert.Len(t, result.Timeline, 31, "the default 30-day window overlaps 31 daily buckets")
+		assert.NotNil(t, result.Groups)
+		assert.NotNil(t, result.TopConsumers)
+		assert.NotNil(t, result.TopFields)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for name, req := range map[string]*v1models.GetStatsRequest{
+			"bucket":      {Bucket: "month"},
+			"groupBy":     {GroupBy: "traceId"},
+			"limit":       {Limit: maxStatsLimit + 1},
+			"startTime":   {StartTime: "yesterday"},
+			"too many":    {Bucket: StatsBucketHour, StartTime: "2025-01-01T00:00:00Z", EndTime: "2026-01-01T00:00:00Z"},
+			"empty range": {StartTime: "2026-01-02T00:00:00Z", EndTime: "2026-01-01T00:00:00Z"},
+		} {
+			_, err := service.GetStats(ctx, req)
+			assert.True(t, IsValidationError(err), name)
+		}
+	})
+}
+
+func TestTruncateToBucket(t *testing.T) {
+	sunday := time.Date(2026, 1, 11, 23, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 1, 11, 23, 0, 0, 0, time.UTC), truncateToBucket(sunday, StatsBucketHour))
+	assert.Equal(t, time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC), truncateToBucket(sunday, StatsBucketDay))
+	assert.Equal(t, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), truncateToBucket(sunday, StatsBucketWeek))
+}
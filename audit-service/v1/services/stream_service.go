@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+// Stream event types
+const (
+	StreamEventLog   = "log"
+	StreamEventAlert = "alert"
+)
+
+const (
+	// DefaultStreamBufferSize is how many recent events are kept for subscribers resuming a stream
+	DefaultStreamBufferSize = 1000
+
+	// DefaultStreamHeartbeatInterval is how often an idle stream sends a heartbeat
+	DefaultStreamHeartbeatInterval = 15 * time.Second
+
+	// streamSubscriberBuffer is how many events may be queued for a subscriber that has not caught up;
+	// subscribers falling further behind are dropped and resume from their last event
+	streamSubscriberBuffer = 256
+)
+
+// ErrStreamResumeGap is returned when a stream cannot be resumed from the given event ID because the events
+// after it are no longer buffered, or were published before the service restarted
+var ErrStreamResumeGap = errors.New("stream cannot be resumed from the given event ID")
+
+// StreamEvent is an event published to live subscribers: a stored log, or a fired alert
+type StreamEvent struct {
+	// ID orders the events of the stream; subscribers resume after the last ID they received
+	ID   string
+	Type string
+
+	Log   *v1models.AuditLog
+	Alert *v1models.Alert
+
+	// tenantIDs are the partitions of a log, computed once for every subscriber
+	tenantIDs []string
+	sequence  uint64
+}
+
+// Payload returns the JSON payload of the event
+func (e *StreamEvent) Payload() any {
+	if e.Alert != nil {
+		return v1models.ToAlertResponse(*e.Alert)
+	}
+	return v1models.ToAuditLogResponse(*e.Log)
+}
+
+// StreamFilter selects the events a subscriber receives. Empty lists match every value; log filters do not
+// apply to alerts, nor alert filters to logs.
+type StreamFilter struct {
+	// Types selects logs, alerts or both
+	Types []string
+
+	// EventTypes, Statuses, ActorID and TargetID match logs
+	EventTypes []string
+	Statuses   []string
+	ActorID    string
+	TargetID   string
+
+	// Severities matches alerts
+	Severities []string
+
+	// EntityIDs, when non-nil, confines logs to the partitions of these tenants; alerts are not sent
+	EntityIDs []string
+}
+
+// Matches reports whether the event passes the filter
+func (f *StreamFilter) Matches(event *StreamEvent) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+	if event.Alert != nil {
+		if f.EntityIDs != nil {
+			return false
+		}
+		return len(f.Severities) == 0 || slices.Contains(f.Severities, event.Alert.Severity)
+	}
+
+	log := event.Log
+	if len(f.EventTypes) > 0 && !slices.Contains(f.EventTypes, derefString(log.EventType)) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, log.Status) {
+		return false
+	}
+	if f.ActorID != "" && log.ActorID != f.ActorID {
+		return false
+	}
+	if f.TargetID != "" && derefString(log.TargetID) != f.TargetID {
+		return false
+	}
+	if f.EntityIDs != nil && !slices.ContainsFunc(event.tenantIDs, func(id string) bool { return slices.Contains(f.EntityIDs, id) }) {
+		return false
+	}
+	return true
+}
+
+// StreamSubscription receives the events matching its filter until it is closed
+type StreamSubscription struct {
+	filter StreamFilter
+	events chan *StreamEvent
+
+	// dropped is set when the subscription was closed because the subscriber fell behind
+	dropped bool
+}
+
+// Events returns the channel of events; it is closed when the subscription ends
+func (s *StreamSubscription) Events() <-chan *StreamEvent {
+	return s.events
+}
+
+// Dropped reports whether the subscription ended because the subscriber fell behind
+func (s *StreamSubscription) Dropped() bool {
+	return s.dropped
+}
+
+// StreamService fans stored logs and fired alerts out to live subscribers, such as the admin portal's
+// activity feed. Recent events are buffered in memory so that subscribers can resume after a reconnect;
+// the buffer does not survive restarts and is not shared between instances.
+type StreamService struct {
+	mu          sync.Mutex
+	epoch       string
+	nextSeq     uint64
+	buffer      []*StreamEvent
+	bufferSize  int
+	subscribers map[*StreamSubscription]struct{}
+}
+
+// NewStreamService creates a stream keeping the last bufferSize events for resuming subscribers;
+// non-positive sizes use DefaultStreamBufferSize
+func NewStreamService(bufferSize int) *StreamService {
+	if bufferSize <= 0 {
+		bufferSize = DefaultStreamBufferSize
+	}
+	return &StreamService{
+		// Event IDs carry the start time of the process, so IDs from before a restart are recognized
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		nextSeq:     1,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*StreamSubscription]struct{}),
+	}
+}
+
+// PublishLog sends a newly stored log to the subscribers
+func (s *StreamService) PublishLog(log *v1models.AuditLog) {
+	s.publish(&StreamEvent{Type: StreamEventLog, Log: log, tenantIDs: log.TenantIDs()})
+}
+
+// PublishAlert sends a newly fired alert to the subscribers
+func (s *StreamService) PublishAlert(alert *v1models.Alert) {
+	s.publish(&StreamEvent{Type: StreamEventAlert, Alert: alert})
+}
+
+func (s *StreamService) publish(event *StreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.sequence = s.nextSeq
+	event.ID = fmt.Sprintf("%s-%d", s.epoch, event.sequence)
+	s.nextSeq++
+
+	s.buffer = append(s.buffer, event)
+	if len(s.buffer) > s.bufferSize {
+		s.buffer = slices.Delete(s.buffer, 0, len(s.buffer)-s.bufferSize)
+	}
+
+	for sub := range s.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped = true
+			s.closeLocked(sub)
+		}
+	}
+}
+
+// Subscribe starts a subscription. With a lastEventID, the buffered events after it that match the filter
+// are returned to be sent before the subscription's events; ErrStreamResumeGap is returned, together with
+// the subscription, when events after lastEventID may have been missed.
+func (s *StreamService) Subscribe(filter StreamFilter, lastEventID string) (*StreamSubscription, []*StreamEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &StreamSubscription{filter: filter, events: make(chan *StreamEvent, streamSubscriberBuffer)}
+	s.subscribers[sub] = struct{}{}
+	if lastEventID == "" {
+		return sub, nil, nil
+	}
+
+	after, err := s.parseEventIDLocked(lastEventID)
+	if err != nil {
+		return sub, nil, err
+	}
+	var replay []*StreamEvent
+	for _, event := range s.buffer {
+		if event.sequence > after && filter.Matches(event) {
+			replay = append(replay, event)
+		}
+	}
+	return sub, replay, nil
+}
+
+// parseEventIDLocked returns the sequence of an event ID from which the buffered events can be replayed
+func (s *StreamService) parseEventIDLocked(eventID string) (uint64, error) {
+	epoch, seq, found := strings.Cut(eventID, "-")
+	if !found || epoch != s.epoch {
+		return 0, ErrStreamResumeGap
+	}
+	after, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || after >= s.nextSeq {
+		return 0, ErrStreamResumeGap
+	}
+	if len(s.buffer) > 0 && after+1 < s.buffer[0].sequence {
+		return 0, ErrStreamResumeGap
+	}
+	return after, nil
+}
+
+// Unsubscribe ends a subscription
+func (s *StreamService) Unsubscribe(sub *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked(sub)
+}
+
+func (s *StreamService) closeLocked(sub *StreamSubscription) {
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Subscribers returns the number of active subscriptions
+func (s *StreamService) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	v1testutil "github.com/gov-dx-sandbox/audit-service/v1/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamLog(actorID, status string) *v1models.AuditLog {
+	return &v1models.AuditLog{
+		ID:         uuid.New(),
+		Timestamp:  time.Now().UTC(),
+		EventType:  stringPtr("DATA_REQUEST"),
+		Status:     status,
+		ActorType:  v1models.ActorTypeApplication,
+		ActorID:    actorID,
+		TargetType: "SERVICE",
+	}
+}
+
+func receive(t *testing.T, sub *StreamSubscription) *StreamEvent {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		require.NotNil(t, event)
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no stream event received")
+		return nil
+	}
+}
+
+func TestStreamService_Filters(t *testing.T) {
+	stream := NewStreamService(10)
+	failures, _, err := stream.Subscribe(StreamFilter{Statuses: []string{v1models.StatusFailure}}, "")
+	require.NoError(t, err)
+	tenant, _, err := stream.Subscribe(StreamFilter{EntityIDs: []string{"app-2"}}, "")
+	require.NoError(t, err)
+	alerts, _, err := stream.Subscribe(StreamFilter{Types: []string{StreamEventAlert}, Severities: []string{"critical"}}, "")
+	require.NoError(t, err)
+
+	stream.PublishLog(streamLog("app-1", v1models.StatusSuccess))
+	stream.PublishLog(streamLog("app-1", v1models.StatusFailure))
+	stream.PublishLog(streamLog("app-2", v1models.StatusSuccess))
+	stream.PublishAlert(&v1models.Alert{ID: uuid.New(), RuleName: "warning", Severity: "warning"})
+	stream.PublishAlert(&v1models.Alert{ID: uuid.New(), RuleName: "critical", Severity: "critical"})
+
+	event := receive(t, failures)
+	assert.Equal(t, StreamEventLog, event.Type)
+	assert.Equal(t, "app-1", event.Log.ActorID)
+	assert.Equal(t, v1models.StatusFailure, event.Log.Status)
+	assert.Equal(t, StreamEventAlert, receive(t, failures).Type, "log filters do not apply to alerts")
+	assert.Equal(t, StreamEventAlert, receive(t, failures).Type)
+	assert.Empty(t, failures.Events())
+
+	assert.Equal(t, "app-2", receive(t, tenant).Log.ActorID)
+	assert.Empty(t, tenant.Events(), "tenant-scoped subscribers do not receive alerts")
+
+	assert.Equal(t, "critical", receive(t, alerts).Alert.RuleName)
+	assert.Empty(t, alerts.Events())
+}
+
+func TestStreamService_Resume(t *testing.T) {
+	stream := NewStreamService(3)
+	var ids []string
+	live, _, err := stream.Subscribe(StreamFilter{}, "")
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		stream.PublishLog(streamLog("app-1", v1models.StatusSuccess))
+		ids = append(ids, receive(t, live).ID)
+	}
+	stream.Unsubscribe(live)
+
+	t.Run("replays buffered events", func(t *testing.T) {
+		sub, replay, err := stream.Subscribe(StreamFilter{}, ids[2])
+		require.NoError(t, err)
+		defer stream.Unsubscribe(sub)
+		require.Len(t, replay, 2)
+		assert.Equal(t, ids[3], replay[0].ID)
+		assert.Equal(t, ids[4], replay[1].ID)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		sub, replay, err := stream.Subscribe(StreamFilter{}, ids[4])
+		require.NoError(t, err)
+		defer stream.Unsubscribe(sub)
+		assert.Empty(t, replay)
+	})
+
+	t.Run("gaps", func(t *testing.T) {
+		for _, lastEventID := range []string{ids[0], "otherepoch-4", "garbage", ids[4] + "0"} {
+			sub, replay, err := stream.Subscribe(StreamFilter{}, lastEventID)
+			assert.ErrorIs(t, err, ErrStreamResumeGap, lastEventID)
+			assert.Empty(t, replay)
+			require.NotNil(t, sub, "the subscription continues after a gap")
+			stream.Unsubscribe(sub)
+		}
+	})
+}
+
+func TestStreamService_DropsSlowSubscribers(t *testing.T) {
+	stream := NewStreamService(10)
+	sub, _, err := stream.Subscribe(StreamFilter{}, "")
+	require.NoError(t, err)
+
+	for i := 0; i <= streamSubscriberBuffer; i++ {
+		stream.PublishLog(streamLog("app-1", v1models.StatusSuccess))
+	}
+
+	for range sub.Events() {
+	}
+	assert.True(t, sub.Dropped())
+	assert.Equal(t, 0, stream.Subscribers())
+	stream.Unsubscribe(sub)
+}
+
+func TestAuditService_PublishesStoredLogs(t *testing.T) {
+	service := NewAuditService(v1testutil.NewMockRepository())
+	stream := NewStreamService(10)
+	service.SetStream(stream)
+	sub, _, err := stream.Subscribe(StreamFilter{}, "")
+	require.NoError(t, err)
+
+	eventID := "event-1"
+	req := &v1models.CreateAuditLogRequest{
+		EventID:    &eventID,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Status:     v1models.StatusSuccess,
+		ActorType:  "SERVICE",
+		ActorID:    "orchestration-engine",
+		TargetType: "SERVICE",
+	}
+	created, err := service.CreateAuditLog(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, receive(t, sub).Log.ID)
+
+	_, err = service.CreateAuditLog(context.Background(), req)
+	assert.ErrorIs(t, err, ErrDuplicateEvent)
+	assert.Empty(t, sub.Events(), "duplicate events are not published again")
+}
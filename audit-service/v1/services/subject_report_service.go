@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// defaultSubjectReportLimit and maxSubjectReportLimit bound the number of accesses per report page
+	defaultSubjectReportLimit = 50
+	maxSubjectReportLimit     = 500
+
+	// maxOwnerIDLength bounds the owner identifier accepted in report requests
+	maxOwnerIDLength = 255
+
+	// Report requests are themselves recorded as audit events
+	subjectReportEventType = "AUDIT_SUBJECT_REPORT"
+	subjectReportTargetID  = "subject_access_report"
+
+	// providerFetchEventType is logged by the orchestration engine for each provider call;
+	// its response metadata lists the requestedFields fetched from the provider
+	providerFetchEventType = "PROVIDER_FETCH"
+)
+
+// SubjectReportService builds data subject access reports: which consumers requested a data owner's
+// fields, under which consent, and which fields providers returned to them
+type SubjectReportService struct {
+	repo database.AuditRepository
+}
+
+// NewSubjectReportService creates a new subject report service
+func NewSubjectReportService(repo database.AuditRepository) *SubjectReportService {
+	return &SubjectReportService{repo: repo}
+}
+
+// GetSubjectAccessReport returns a page of the accesses to the data owner's data, newest first.
+// Each access starts from a consent check recording the owner and is joined with the provider fetches
+// of the same trace. The report request is recorded as an audit event; if that fails, no report is returned.
+func (s *SubjectReportService) GetSubjectAccessReport(ctx context.Context, req *v1models.GetSubjectAccessReportRequest) (*v1models.SubjectAccessReportResponse, error) {
+	ownerID := strings.TrimSpace(req.OwnerID)
+	if ownerID == "" {
+		return nil, fmt.Errorf("%w: ownerId is required", ErrInvalidInput)
+	}
+	if len(ownerID) > maxOwnerIDLength {
+		return nil, fmt.Errorf("%w: ownerId exceeds %d characters", ErrInvalidInput, maxOwnerIDLength)
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSubjectReportLimit
+	}
+	if limit < 0 || limit > maxSubjectReportLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxSubjectReportLimit)
+	}
+	if req.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
+
+	eventType := consentCheckEventType
+	consentChecks, total, err := s.repo.GetAuditLogs(ctx, &database.AuditLogFilters{
+		EventType: &eventType,
+		OwnerID:   &ownerID,
+		Limit:     limit,
+		Offset:    req.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &v1models.SubjectAccessReportResponse{
+		OwnerID:     ownerID,
+		GeneratedAt: time.Now().UTC(),
+		Accesses:    make([]v1models.SubjectAccessRecord, 0, len(consentChecks)),
+		Total:       total,
+		Limit:       limit,
+		Offset:      req.Offset,
+	}
+	for _, consentCheck := range consentChecks {
+		var trace []v1models.AuditLog
+		if consentCheck.TraceID != nil {
+			trace, err = s.repo.GetAuditLogsByTraceID(ctx, consentCheck.TraceID.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+		response.Accesses = append(response.Accesses, subjectAccessRecord(consentCheck, trace))
+	}
+
+	err = recordReadEvent(ctx, s.repo, subjectReportEventType, req.ActorType, req.ActorID, subjectReportTargetID, map[string]interface{}{
+		"ownerId":  ownerID,
+		"limit":    limit,
+		"offset":   req.Offset,
+		"accesses": len(response.Accesses),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// subjectAccessRecord joins a consent check with the provider fetches among the logs of its trace
+func subjectAccessRecord(consentCheck v1models.AuditLog, trace []v1models.AuditLog) v1models.SubjectAccessRecord {
+	request, response := parseExchangeMetadata(&consentCheck)
+
+	record := v1models.SubjectAccessRecord{
+		RequestedAt:   consentCheck.Timestamp,
+		ConsumerAppID: request.ApplicationID,
+		ConsentID:     response.ConsentID,
+		ConsentStatus: response.Status,
+		Fields:        []string{},
+		Providers:     []v1models.SubjectProviderAccess{},
+	}
+	if consentCheck.TraceID != nil {
+		record.TraceID = consentCheck.TraceID.String()
+	}
+
+	fields := make(map[string]bool)
+	for i := range trace {
+		log := &trace[i]
+		if derefString(log.EventType) != providerFetchEventType {
+			continue
+		}
+		_, fetch := parseExchangeMetadata(log)
+		if record.ConsumerAppID != "" && fetch.ApplicationID != "" && fetch.ApplicationID != record.ConsumerAppID {
+			continue
+		}
+		provider := v1models.SubjectProviderAccess{
+			ServiceKey: fetch.ServiceKey,
+			AccessedAt: log.Timestamp,
+			Status:     log.Status,
+			Fields:     fetch.RequestedFields,
+		}
+		if provider.Fields == nil {
+			provider.Fields = []string{}
+		}
+		if provider.ServiceKey == "" {
+			provider.ServiceKey = derefString(log.TargetID)
+		}
+		record.Providers = append(record.Providers, provider)
+
+		if log.Status == v1models.StatusSuccess {
+			record.DataShared = true
+			for _, field := range fetch.RequestedFields {
+				fields[field] = true
+			}
+		}
+	}
+	for field := range fields {
+		record.Fields = append(record.Fields, field)
+	}
+	sort.Strings(record.Fields)
+	sort.SliceStable(record.Providers, func(i, j int) bool {
+		return record.Providers[i].AccessedAt.Before(record.Providers[j].AccessedAt)
+	})
+	return record
+}
+
+// WriteSubjectAccessReportPDF renders a report page as a PDF document for the data owner
+func WriteSubjectAccessReportPDF(w io.Writer, report *v1models.SubjectAccessReportResponse) error {
+	const timeFormat = "2006-01-02 15:04:05 UTC"
+	lines := []pdfLine{
+		{Text: "Data Access Report", Bold: true},
+		{},
+		{Text: "Data owner:   " + report.OwnerID},
+		{Text: "Generated:    " + report.GeneratedAt.UTC().Format(timeFormat)},
+	}
+	if len(report.Accesses) == 0 {
+		lines = append(lines, pdfLine{Text: fmt.Sprintf("Requests:     none of %d", report.Total)})
+	} else {
+		lines = append(lines, pdfLine{Text: fmt.Sprintf("Requests:     %d to %d of %d",
+			report.Offset+1, report.Offset+len(report.Accesses), report.Total)})
+	}
+	lines = append(lines, pdfLine{})
+
+	for _, access := range report.Accesses {
+		consumer := access.ConsumerAppID
+		if consumer == "" {
+			consumer = "unknown consumer"
+		}
+		lines = append(lines, pdfLine{Text: access.RequestedAt.UTC().Format(timeFormat) + "  " + consumer, Bold: true})
+		consent := access.ConsentStatus
+		if consent == "" {
+			consent = "not recorded"
+		}
+		if access.ConsentID != "" {
+			consent += " (consent " + access.ConsentID + ")"
+		}
+		lines = append(lines, pdfLine{Text: "  Consent:       " + consent})
+		if access.DataShared {
+			lines = append(lines, pdfLine{Text: "  Fields shared: " + strings.Join(access.Fields, ", ")})
+		} else {
+			lines = append(lines, pdfLine{Text: "  Fields shared: none"})
+		}
+		for _, provider := range access.Providers {
+			lines = append(lines, pdfLine{Text: fmt.Sprintf("  Provider %s at %s: %s", provider.ServiceKey,
+				provider.AccessedAt.UTC().Format(timeFormat), provider.Status)})
+			if len(provider.Fields) > 0 {
+				lines = append(lines, pdfLine{Text: "    Fields: " + strings.Join(provider.Fields, ", ")})
+			}
+		}
+		if access.TraceID != "" {
+			lines = append(lines, pdfLine{Text: "  Reference:     " + access.TraceID})
+		}
+		lines = append(lines, pdfLine{})
+	}
+	return writeTextPDF(w, "Data Access Report for "+report.OwnerID, lines)
+}
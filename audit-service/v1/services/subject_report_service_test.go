@@ -0,0 +1,128 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubjectReportService_GetSubjectAccessReport(t *testing.T) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+	service := NewSubjectReportService(repo)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	create := func(traceID *uuid.UUID, timestamp time.Time, eventType, status, request, response string) {
+		log := &v1models.AuditLog{
+			TraceID:    traceID,
+			Timestamp:  timestamp,
+			EventType:  stringPtr(eventType),
+			Status:     status,
+			ActorType:  "SERVICE",
+			ActorID:    "orchestration-engine",
+			TargetType: "SERVICE",
+		}
+		if request != "" {
+			log.RequestMetadata = v1models.JSONBRawMessage(request)
+		}
+		if response != "" {
+			log.ResponseMetadata = v1models.JSONBRawMessage(response)
+		}
+		_, err := repo.CreateAuditLog(ctx, log)
+		require.NoError(t, err)
+	}
+
+	// An approved request served by two providers, one of which failed
+	approved := uuid.New()
+	create(&approved, base, "CONSENT_CHECK", v1models.StatusSuccess,
+		`{"applicationId":"app-1","ownerId":"citizen@example.com","ownerEmail":"citizen@example.com"}`,
+		`{"consentId":"consent-1","status":"approved"}`)
+	create(&approved, base.Add(time.Second), "PROVIDER_FETCH", v1models.StatusSuccess, "",
+		`{"applicationId":"app-1","serviceKey":"drp","requestedFields":["person.name","person.address"]}`)
+	create(&approved, base.Add(2*time.Second), "PROVIDER_FETCH", v1models.StatusFailure, "",
+		`{"applicationId":"app-1","serviceKey":"rgd","requestedFields":["person.birthDate"],"error":"timeout"}`)
+
+	// A later request the owner rejected, so nothing was fetched
+	rejected := uuid.New()
+	create(&rejected, base.Add(time.Hour), "CONSENT_CHECK", v1models.StatusSuccess,
+		`{"applicationId":"app-2","ownerEmail":"citizen@example.com"}`, `{"consentId":"consent-2","status":"rejected"}`)
+
+	// Another owner's request
+	other := uuid.New()
+	create(&other, base, "CONSENT_CHECK", v1models.StatusSuccess,
+		`{"applicationId":"app-1","ownerId":"someone@example.com"}`, `{"consentId":"consent-3","status":"approved"}`)
+	create(&other, base.Add(time.Second), "PROVIDER_FETCH", v1models.StatusSuccess, "",
+		`{"applicationId":"app-1","serviceKey":"drp","requestedFields":["person.name"]}`)
+
+	t.Run("report", func(t *testing.T) {
+		report, err := service.GetSubjectAccessReport(ctx, &v1models.GetSubjectAccessReportRequest{OwnerID: "citizen@example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), report.Total)
+		assert.Equal(t, defaultSubjectReportLimit, report.Limit)
+		require.Len(t, report.Accesses, 2)
+
+		latest := report.Accesses[0]
+		assert.Equal(t, "app-2", latest.ConsumerAppID)
+		assert.Equal(t, "rejected", latest.ConsentStatus)
+		assert.False(t, latest.DataShared)
+		assert.Empty(t, latest.Fields)
+		assert.Empty(t, latest.Providers)
+
+		first := report.Accesses[1]
+		assert.Equal(t, approved.String(), first.TraceID)
+		assert.Equal(t, base, first.RequestedAt.UTC())
+		assert.Equal(t, "app-1", first.ConsumerAppID)
+		assert.Equal(t, "consent-1", first.ConsentID)
+		assert.True(t, first.DataShared)
+		assert.Equal(t, []string{"person.address", "person.name"}, first.Fields, "fields of the failed fetch were not shared")
+		require.Len(t, first.Providers, 2)
+		assert.Equal(t, "drp", first.Providers[0].ServiceKey)
+		assert.Equal(t, "rgd", first.Providers[1].ServiceKey)
+		assert.Equal(t, v1models.StatusFailure, first.Providers[1].Status)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		report, err := service.GetSubjectAccessReport(ctx, &v1models.GetSubjectAccessReportRequest{OwnerID: "citizen@example.com", Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), report.Total)
+		require.Len(t, report.Accesses, 1)
+		assert.Equal(t, "app-1", report.Accesses[0].ConsumerAppID)
+	})
+
+	t.Run("report requests are audited", func(t *testing.T) {
+		var events []v1models.AuditLog
+		require.NoError(t, db.Where("event_type = ?", subjectReportEventType).Find(&events).Error)
+		require.Len(t, events, 2)
+		assert.Equal(t, "READ", derefString(events[0].EventAction))
+		assert.Contains(t, string(events[0].RequestMetadata), `"ownerId":"citizen@example.com"`)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for name, req := range map[string]*v1models.GetSubjectAccessReportRequest{
+			"missing owner":   {},
+			"limit too large": {OwnerID: "citizen@example.com", Limit: maxSubjectReportLimit + 1},
+			"negative offset": {OwnerID: "citizen@example.com", Offset: -1},
+		} {
+			_, err := service.GetSubjectAccessReport(ctx, req)
+			assert.True(t, IsValidationError(err), name)
+		}
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		report, err := service.GetSubjectAccessReport(ctx, &v1models.GetSubjectAccessReportRequest{OwnerID: "citizen@example.com"})
+		require.NoError(t, err)
+		var pdf bytes.Buffer
+		require.NoError(t, WriteSubjectAccessReportPDF(&pdf, report))
+		assert.True(t, bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-1.4")))
+		assert.Contains(t, pdf.String(), "(  Fields shared: person.address, person.name)")
+		assert.Contains(t, pdf.String(), "(  Consent:       rejected \\(consent consent-2\\))")
+	})
+}
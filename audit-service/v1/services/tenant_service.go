@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// DefaultPartitionMaintenanceInterval is how often partitions are maintained when no interval is configured
+	DefaultPartitionMaintenanceInterval = time.Hour
+
+	// partitionBackfillBatchSize is how many unpartitioned logs are read from the database per query
+	partitionBackfillBatchSize = 1000
+
+	// defaultTenantLimit and maxTenantLimit bound the number of tenants per page
+	defaultTenantLimit = 100
+	maxTenantLimit     = 1000
+)
+
+// ErrPartitionMaintenanceInProgress is returned when maintenance is started while it is already running
+var ErrPartitionMaintenanceInProgress = errors.New("partition maintenance is already in progress")
+
+// TenantService maintains the per-tenant partitions of the audit logs and serves the cross-tenant view.
+// Logs are partitioned as they are stored; maintenance partitions logs stored before partitioning was
+// introduced, removes the partitions of deleted logs and recomputes the tenant summaries.
+type TenantService struct {
+	tenants database.TenantRepository
+
+	// mu guards running, which ensures only one maintenance pass runs at a time
+	mu      sync.Mutex
+	running bool
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(tenants database.TenantRepository) *TenantService {
+	return &TenantService{tenants: tenants}
+}
+
+// RunMaintenance backfills and prunes partitions, then refreshes the tenant summaries.
+// Returns ErrPartitionMaintenanceInProgress if another pass is still running.
+func (s *TenantService) RunMaintenance(ctx context.Context) (*v1models.PartitionMaintenanceResponse, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, ErrPartitionMaintenanceInProgress
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	response := &v1models.PartitionMaintenanceResponse{StartedAt: time.Now().UTC()}
+	var err error
+	if response.BackfilledCount, err = s.tenants.BackfillPartitions(ctx, partitionBackfillBatchSize); err != nil {
+		return nil, err
+	}
+	if response.PrunedCount, err = s.tenants.PrunePartitions(ctx); err != nil {
+		return nil, err
+	}
+	if response.TenantCount, err = s.tenants.RefreshTenants(ctx); err != nil {
+		return nil, err
+	}
+	response.CompletedAt = time.Now().UTC()
+	return response, nil
+}
+
+// StartScheduler maintains partitions immediately, so logs stored before partitioning become visible to
+// their tenants, and then every interval until the context is cancelled
+func (s *TenantService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("Audit partition maintenance scheduler disabled")
+		return
+	}
+
+	slog.Info("Audit partition maintenance scheduler started", "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.RunMaintenance(ctx)
+		switch {
+		case err == nil:
+			slog.Info("Audit partition maintenance completed", "backfilled", result.BackfilledCount,
+				"pruned", result.PrunedCount, "tenants", result.TenantCount)
+		case ctx.Err() == nil:
+			slog.Error("Audit partition maintenance failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Audit partition maintenance scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListTenants returns a page of tenant summaries, most active first
+func (s *TenantService) ListTenants(ctx context.Context, req *v1models.ListTenantsRequest) (*v1models.ListTenantsResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultTenantLimit
+	}
+	if limit < 0 || limit > maxTenantLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxTenantLimit)
+	}
+	if req.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
+
+	tenants, total, err := s.tenants.ListTenants(ctx, limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	response := &v1models.ListTenantsResponse{
+		Tenants: make([]v1models.TenantResponse, 0, len(tenants)),
+		Total:   total,
+		Limit:   limit,
+		Offset:  req.Offset,
+	}
+	for _, tenant := range tenants {
+		response.Tenants = append(response.Tenants, v1models.ToTenantResponse(tenant))
+	}
+	return response, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTenantService_Partitions(t *testing.T) {
+	db := setupSQLiteTestDB(t)
+	repo := database.NewGormRepository(db)
+	service := NewTenantService(repo)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	create := func(timestamp time.Time, status, target, applicationID string) *v1models.AuditLog {
+		log, err := repo.CreateAuditLog(ctx, &v1models.AuditLog{
+			Timestamp:       timestamp,
+			Status:          status,
+			ActorType:       "SERVICE",
+			ActorID:         "orchestration-engine",
+			TargetType:      "SERVICE",
+			TargetID:        &target,
+			RequestMetadata: v1models.JSONBRawMessage(`{"applicationId":"` + applicationID + `"}`),
+		})
+		require.NoError(t, err)
+		return log
+	}
+	create(base, v1models.StatusSuccess, "drp", "passport-app")
+	create(base.Add(time.Minute), v1models.StatusFailure, "rgd", "passport-app")
+	create(base.Add(2*time.Minute), v1models.StatusSuccess, "drp", "tax-app")
+
+	logIDs := func(tenantIDs ...string) int64 {
+		_, total, err := repo.GetAuditLogs(ctx, &database.AuditLogFilters{EntityIDs: tenantIDs})
+		require.NoError(t, err)
+		return total
+	}
+
+	t.Run("logs are partitioned as they are stored", func(t *testing.T) {
+		assert.Equal(t, int64(2), logIDs("passport-app"))
+		assert.Equal(t, int64(2), logIDs("drp"))
+		assert.Equal(t, int64(3), logIDs("tax-app", "passport-app"))
+		assert.Equal(t, int64(0), logIDs([]string{}...), "an empty scope matches nothing")
+	})
+
+	t.Run("maintenance backfills, prunes and summarizes", func(t *testing.T) {
+		// A log stored before partitioning existed
+		legacyTarget := "dmt"
+		legacy := &v1models.AuditLog{Timestamp: base.Add(-time.Hour), Status: v1models.StatusSuccess,
+			ActorType: "SERVICE", ActorID: "orchestration-engine", TargetType: "SERVICE", TargetID: &legacyTarget}
+		require.NoError(t, db.Session(&gorm.Session{SkipHooks: true}).Create(legacy).Error)
+		assert.Equal(t, int64(0), logIDs("dmt"))
+
+		// A log deleted without its partitions
+		deleted := create(base.Add(3*time.Minute), v1models.StatusSuccess, "drp", "gone-app")
+		require.NoError(t, db.Session(&gorm.Session{SkipHooks: true}).Delete(&v1models.AuditLog{}, "id = ?", deleted.ID).Error)
+
+		result, err := service.RunMaintenance(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.BackfilledCount)
+		assert.Equal(t, int64(3), result.PrunedCount)
+		assert.Equal(t, int64(6), result.TenantCount)
+		assert.Equal(t, int64(1), logIDs("dmt"))
+
+		tenants, err := service.ListTenants(ctx, &v1models.ListTenantsRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), tenants.Total)
+		require.NotEmpty(t, tenants.Tenants)
+
+		// Most active first: orchestration-engine acted in every log
+		busiest := tenants.Tenants[0]
+		assert.Equal(t, "orchestration-engine", busiest.TenantID)
+		assert.Equal(t, int64(4), busiest.LogCount)
+		assert.Equal(t, int64(1), busiest.FailureCount)
+		assert.Equal(t, base.Add(-time.Hour), busiest.FirstEventAt.UTC())
+		assert.Equal(t, base.Add(2*time.Minute), busiest.LastEventAt.UTC())
+
+		// Maintenance is idempotent
+		result, err = service.RunMaintenance(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, result.BackfilledCount)
+		assert.Zero(t, result.PrunedCount)
+	})
+
+	t.Run("list validation", func(t *testing.T) {
+		_, err := service.ListTenants(ctx, &v1models.ListTenantsRequest{Limit: maxTenantLimit + 1})
+		assert.True(t, IsValidationError(err))
+		_, err = service.ListTenants(ctx, &v1models.ListTenantsRequest{Offset: -1})
+		assert.True(t, IsValidationError(err))
+	})
+}
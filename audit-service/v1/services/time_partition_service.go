@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// DefaultTimePartitionInterval is how often time partitions are maintained when no interval is configured
+	DefaultTimePartitionInterval = 6 * time.Hour
+
+	// DefaultTimePartitionPremakeMonths is how many months ahead partitions are created when not configured
+	DefaultTimePartitionPremakeMonths = 2
+)
+
+// ErrTimePartitionMaintenanceInProgress is returned when maintenance is started while it is already running
+var ErrTimePartitionMaintenanceInProgress = errors.New("time partition maintenance is already in progress")
+
+// TimePartitionMaintenanceResult is the outcome of a time partition maintenance pass
+type TimePartitionMaintenanceResult struct {
+	// Created names the partitions created ahead of the logs they will hold
+	Created []string
+	// Dropped names the expired partitions dropped, including the archived ones
+	Dropped []string
+	// Archived names the expired partitions whose logs were archived before they were dropped
+	Archived []string
+}
+
+// TimePartitionService maintains the monthly partitions of the audit_logs table: it creates partitions
+// ahead of time, so no append waits for one, and retires partitions whose logs have all expired.
+// Dropping a partition removes its logs at once, where deleting them row by row would leave the table bloated.
+type TimePartitionService struct {
+	partitions database.TimePartitionRepository
+	archiver   *ArchiveService
+	retention  *config.RetentionConfig
+	premake    int
+
+	// mu guards running, which ensures only one maintenance pass runs at a time
+	mu      sync.Mutex
+	running bool
+}
+
+// NewTimePartitionService creates a new time partition service. archiver may be nil, in which case
+// expired partitions are only dropped once archive runs have emptied them.
+func NewTimePartitionService(partitions database.TimePartitionRepository, archiver *ArchiveService, retention *config.RetentionConfig, premakeMonths int) *TimePartitionService {
+	if retention == nil {
+		retention = &config.RetentionConfig{}
+	}
+	if premakeMonths < 0 {
+		premakeMonths = DefaultTimePartitionPremakeMonths
+	}
+	return &TimePartitionService{
+		partitions: partitions,
+		archiver:   archiver,
+		retention:  retention,
+		premake:    premakeMonths,
+	}
+}
+
+// RunMaintenance creates the partitions of the current and coming months, then retires the partitions
+// whose every log has expired under the longest retention period. A partition still holding logs is
+// archived whole and then dropped, which requires archival to be configured; otherwise it is kept.
+// Returns ErrTimePartitionMaintenanceInProgress if another pass is still running.
+func (s *TimePartitionService) RunMaintenance(ctx context.Context) (*TimePartitionMaintenanceResult, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, ErrTimePartitionMaintenanceInProgress
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	result := &TimePartitionMaintenanceResult{}
+	now := time.Now().UTC()
+	month := database.TimePartitionOf(now).From
+	for i := 0; i <= s.premake; i++ {
+		t := month.AddDate(0, i, 0)
+		created, err := s.partitions.CreateTimePartition(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			result.Created = append(result.Created, database.TimePartitionOf(t).Name)
+		}
+	}
+
+	days, expires := s.retention.LongestDays()
+	if !expires {
+		return result, nil
+	}
+	cutoff := now.AddDate(0, 0, -days)
+	partitions, err := s.partitions.ListTimePartitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range partitions {
+		if partition.To.After(cutoff) {
+			continue
+		}
+		if err := s.retire(ctx, partition, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// retire drops an expired partition, archiving its logs first unless it is empty
+func (s *TimePartitionService) retire(ctx context.Context, partition database.TimePartition, result *TimePartitionMaintenanceResult) error {
+	empty, err := s.partitions.TimePartitionEmpty(ctx, partition)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		if s.archiver == nil || !s.archiver.Enabled() {
+			slog.Warn("Expired audit log partition kept, as archival is not configured", "partition", partition.Name)
+			return nil
+		}
+		run, err := s.archiver.ArchivePartition(ctx, partition)
+		if err != nil {
+			return err
+		}
+		if run.Status != v1models.ArchiveRunStatusCompleted {
+			return fmt.Errorf("archive run %s of partition %s did not complete", run.ID, partition.Name)
+		}
+		result.Archived = append(result.Archived, partition.Name)
+	}
+
+	if err := s.partitions.DropTimePartition(ctx, partition); err != nil {
+		return err
+	}
+	result.Dropped = append(result.Dropped, partition.Name)
+	return nil
+}
+
+// StartScheduler maintains time partitions immediately, so the current month's partition exists before
+// logs arrive, and then every interval until the context is cancelled. It does nothing unless the
+// audit_logs table is partitioned.
+func (s *TimePartitionService) StartScheduler(ctx context.Context, interval time.Duration) {
+	partitioned, err := s.partitions.TimePartitioned(ctx)
+	if err != nil {
+		slog.Error("Failed to check audit log time partitioning", "error", err)
+		return
+	}
+	if !partitioned || interval <= 0 {
+		slog.Info("Audit log time partition scheduler disabled")
+		return
+	}
+
+	slog.Info("Audit log time partition scheduler started", "interval", interval, "premakeMonths", s.premake)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.RunMaintenance(ctx)
+		switch {
+		case err == nil:
+			slog.Info("Audit log time partition maintenance completed", "created", result.Created,
+				"dropped", result.Dropped, "archived", result.Archived)
+		case ctx.Err() == nil:
+			slog.Error("Audit log time partition maintenance failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Audit log time partition scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
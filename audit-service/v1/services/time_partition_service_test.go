@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/config"
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryTimePartitions fakes the partitions of audit_logs. SQLite cannot partition tables, so partitions
+// are names over the logs of the test database, and dropping one deletes its logs.
+type memoryTimePartitions struct {
+	db         *gorm.DB
+	partitions map[string]database.TimePartition
+}
+
+func newMemoryTimePartitions(db *gorm.DB, months ...time.Time) *memoryTimePartitions {
+	m := &memoryTimePartitions{db: db, partitions: make(map[string]database.TimePartition)}
+	for _, month := range months {
+		partition := database.TimePartitionOf(month)
+		m.partitions[partition.Name] = partition
+	}
+	return m
+}
+
+func (m *memoryTimePartitions) TimePartitioned(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+func (m *memoryTimePartitions) ListTimePartitions(ctx context.Context) ([]database.TimePartition, error) {
+	var partitions []database.TimePartition
+	for _, partition := range m.partitions {
+		partitions = append(partitions, partition)
+	}
+	// Oldest first, as the repository lists them
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].From.Before(partitions[j].From) })
+	return partitions, nil
+}
+
+func (m *memoryTimePartitions) CreateTimePartition(ctx context.Context, t time.Time) (bool, error) {
+	partition := database.TimePartitionOf(t)
+	if _, ok := m.partitions[partition.Name]; ok {
+		return false, nil
+	}
+	m.partitions[partition.Name] = partition
+	return true, nil
+}
+
+func (m *memoryTimePartitions) TimePartitionEmpty(ctx context.Context, partition database.TimePartition) (bool, error) {
+	var count int64
+	err := m.db.Model(&v1models.AuditLog{}).Where("timestamp >= ? AND timestamp < ?", partition.From, partition.To).Count(&count).Error
+	return count == 0, err
+}
+
+func (m *memoryTimePartitions) DropTimePartition(ctx context.Context, partition database.TimePartition) error {
+	delete(m.partitions, partition.Name)
+	return m.db.Where("timestamp >= ? AND timestamp < ?", partition.From, partition.To).Delete(&v1models.AuditLog{}).Error
+}
+
+func TestTimePartitionService_RunMaintenance(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	store := newMemoryArchiveStore()
+	retention := &config.RetentionConfig{DefaultDays: 365, EventTypes: map[string]int{"POLICY_CHECK": 30}}
+	archiver, repo, db := setupArchiveTest(t, store, retention)
+
+	// The seeded logs 400 days old are in an expired partition, the older one is empty, and the logs
+	// 100 days old have not expired under the default policy
+	old := database.TimePartitionOf(now.AddDate(0, 0, -400))
+	empty := database.TimePartitionOf(now.AddDate(0, 0, -600))
+	recent := database.TimePartitionOf(now.AddDate(0, 0, -100))
+	partitions := newMemoryTimePartitions(db, old.From, empty.From, recent.From, now)
+
+	service := NewTimePartitionService(partitions, archiver, retention, 2)
+	result, err := service.RunMaintenance(ctx)
+	require.NoError(t, err)
+
+	month := database.TimePartitionOf(now).From
+	assert.Equal(t, []string{
+		database.TimePartitionOf(month.AddDate(0, 1, 0)).Name,
+		database.TimePartitionOf(month.AddDate(0, 2, 0)).Name,
+	}, result.Created)
+	assert.Equal(t, []string{empty.Name, old.Name}, result.Dropped)
+	assert.Equal(t, []string{old.Name}, result.Archived)
+	assert.Contains(t, partitions.partitions, recent.Name)
+
+	// The partition's logs were archived whole, and tombstoned rather than deleted by the run
+	runs, err := archiver.ListArchiveRuns(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, runs.Runs, 1)
+	run := runs.Runs[0]
+	assert.Equal(t, v1models.ArchiveRunTriggerPartition, run.Trigger)
+	assert.Equal(t, v1models.ArchiveRunStatusCompleted, run.Status)
+	assert.Equal(t, int64(3), run.ArchivedCount)
+	require.Len(t, run.Objects, 1)
+	assert.Contains(t, run.Objects[0].Key, "audit-logs/"+old.Name+"/")
+	assert.Empty(t, run.Objects[0].EventType)
+	assert.Len(t, readArchiveObject(t, store.objects[run.Objects[0].Key]), 3)
+
+	// With the partition dropped, the chain still verifies through the tombstones
+	verified, err := NewIntegrityService(repo, nil).VerifyChain(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.True(t, verified.Valid, "%+v", verified.Failure)
+	assert.Equal(t, int64(3), verified.ArchivedCount)
+
+	// Nothing is left to do on the next pass
+	result, err = service.RunMaintenance(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Empty(t, result.Dropped)
+}
+
+func TestTimePartitionService_KeepsPartitionsWithoutArchival(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	retention := &config.RetentionConfig{DefaultDays: 365}
+	_, _, db := setupArchiveTest(t, nil, retention)
+
+	old := database.TimePartitionOf(now.AddDate(0, 0, -400))
+	empty := database.TimePartitionOf(now.AddDate(0, 0, -600))
+	partitions := newMemoryTimePartitions(db, old.From, empty.From, now)
+
+	// Without archival only empty partitions are dropped
+	result, err := NewTimePartitionService(partitions, nil, retention, 0).RunMaintenance(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Equal(t, []string{empty.Name}, result.Dropped)
+	assert.Contains(t, partitions.partitions, old.Name)
+
+	// Logs kept forever keep every partition
+	result, err = NewTimePartitionService(partitions, nil, &config.RetentionConfig{}, 0).RunMaintenance(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Dropped)
+	assert.Contains(t, partitions.partitions, old.Name)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// traceparentPattern matches a W3C Trace Context traceparent value: version-traceid-parentid-flags.
+// Versions after 00 may append further fields, which are ignored.
+var traceparentPattern = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})(-.*)?$`)
+
+// parseTraceparent returns the trace ID and span (parent) ID of a traceparent value.
+// OpenTelemetry trace IDs are 16 bytes, so they are stored in the same UUID column as other trace IDs.
+func parseTraceparent(value string) (uuid.UUID, string, error) {
+	match := traceparentPattern.FindStringSubmatch(strings.TrimSpace(value))
+	switch {
+	case match == nil:
+		return uuid.Nil, "", fmt.Errorf("expected version-traceid-spanid-flags in lowercase hex")
+	case match[1] == "ff" || (match[1] == "00" && match[5] != ""):
+		return uuid.Nil, "", fmt.Errorf("unsupported version %s", match[1])
+	case match[2] == strings.Repeat("0", 32):
+		return uuid.Nil, "", fmt.Errorf("trace ID must not be all zeros")
+	case match[3] == strings.Repeat("0", 16):
+		return uuid.Nil, "", fmt.Errorf("span ID must not be all zeros")
+	}
+	traceID, err := uuid.Parse(match[2])
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	return traceID, match[3], nil
+}
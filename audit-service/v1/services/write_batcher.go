@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+)
+
+const (
+	// DefaultWriteBatchSize is the most logs stored per batch when no size is configured
+	DefaultWriteBatchSize = 500
+
+	// DefaultWriteBatchDelay is how long a batch waits for more logs when no delay is configured
+	DefaultWriteBatchDelay = 5 * time.Millisecond
+)
+
+// WriteBatcher stores the logs of concurrent requests in micro-batches. Appends to the hash chain are
+// serialized, so storing logs one by one caps ingestion at one transaction per log; a batch takes the
+// chain head lock once and is inserted with a single COPY or multi-row INSERT.
+// A batch is stored once it is full or its first log has waited for the batch delay.
+type WriteBatcher struct {
+	repo     database.AuditRepository
+	maxSize  int
+	maxDelay time.Duration
+
+	requests chan *batchedWrite
+
+	// stopped is closed once the batcher has stored its last batch and no longer accepts logs
+	stopped chan struct{}
+}
+
+// batchedWrite is a log waiting to be stored and the channel its result is delivered on
+type batchedWrite struct {
+	log    *v1models.AuditLog
+	result chan database.CreateResult
+}
+
+// NewWriteBatcher creates a write batcher; Start must be called before logs are written through it
+func NewWriteBatcher(repo database.AuditRepository, maxSize int, maxDelay time.Duration) *WriteBatcher {
+	if maxSize <= 0 {
+		maxSize = DefaultWriteBatchSize
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultWriteBatchDelay
+	}
+	return &WriteBatcher{
+		repo:     repo,
+		maxSize:  maxSize,
+		maxDelay: maxDelay,
+		requests: make(chan *batchedWrite),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Start stores batches until the context is cancelled. Logs written after that are stored one by one.
+func (b *WriteBatcher) Start(ctx context.Context) {
+	defer close(b.stopped)
+
+	slog.Info("Audit log write batching started", "maxSize", b.maxSize, "maxDelay", b.maxDelay)
+	for {
+		var batch []*batchedWrite
+		select {
+		case <-ctx.Done():
+			slog.Info("Audit log write batching stopped")
+			return
+		case write := <-b.requests:
+			batch = append(batch, write)
+		}
+
+		// Logs arriving while a batch is stored wait for the next one, so batches grow with the load
+		timer := time.NewTimer(b.maxDelay)
+	collect:
+		for len(batch) < b.maxSize {
+			select {
+			case write := <-b.requests:
+				batch = append(batch, write)
+			case <-timer.C:
+				break collect
+			case <-ctx.Done():
+				break collect
+			}
+		}
+		timer.Stop()
+		b.store(batch)
+	}
+}
+
+// Wait blocks until the batcher has stored its last batch
+func (b *WriteBatcher) Wait() {
+	<-b.stopped
+}
+
+// Write stores a log with the next batch and returns its result, as CreateAuditLog of the repository would
+func (b *WriteBatcher) Write(ctx context.Context, log *v1models.AuditLog) (*v1models.AuditLog, error) {
+	write := &batchedWrite{log: log, result: make(chan database.CreateResult, 1)}
+	select {
+	case b.requests <- write:
+	case <-b.stopped:
+		return b.repo.CreateAuditLog(ctx, log)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	// Once accepted the log is stored whatever happens to the request, so its result is always awaited
+	result := <-write.result
+	return result.Log, result.Err
+}
+
+// store stores a batch and delivers each log's result. A batch serves many requests, so it is not
+// bound to any one request's context.
+func (b *WriteBatcher) store(batch []*batchedWrite) {
+	logs := make([]*v1models.AuditLog, len(batch))
+	for i, write := range batch {
+		logs[i] = write.log
+	}
+	for i, result := range createAuditLogs(context.Background(), b.repo, logs) {
+		batch[i].result <- result
+	}
+}
+
+// createAuditLogs stores logs as one batch. One log the database rejects fails the whole batch, so the
+// logs of a failed batch are then stored one by one and only the offending log fails.
+func createAuditLogs(ctx context.Context, repo database.AuditRepository, logs []*v1models.AuditLog) []database.CreateResult {
+	results, err := repo.CreateAuditLogs(ctx, logs)
+	if err == nil {
+		return results
+	}
+	results = make([]database.CreateResult, len(logs))
+	if len(logs) == 1 {
+		results[0].Err = err
+		return results
+	}
+	slog.Warn("Failed to store audit log batch, storing its logs one by one", "logs", len(logs), "error", err)
+	for i, log := range logs {
+		results[i].Log, results[i].Err = repo.CreateAuditLog(ctx, log)
+	}
+	return results
+}
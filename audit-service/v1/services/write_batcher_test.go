@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/audit-service/v1/database"
+	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchedLog(eventID string) *v1models.AuditLog {
+	return &v1models.AuditLog{
+		Timestamp:  time.Now().UTC(),
+		Status:     v1models.StatusSuccess,
+		EventID:    &eventID,
+		ActorType:  "SERVICE",
+		ActorID:    "orchestration-engine",
+		TargetType: "SERVICE",
+	}
+}
+
+func TestWriteBatcher_ConcurrentWrites(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := database.NewGormRepository(setupSQLiteTestDB(t))
+	batcher := NewWriteBatcher(repo, 8, 50*time.Millisecond)
+	go batcher.Start(ctx)
+
+	// Twenty concurrent writes, two of them redeliveries of the same event
+	const writes = 20
+	logs := make([]*v1models.AuditLog, writes)
+	errs := make([]error, writes)
+	var wg sync.WaitGroup
+	for i := 0; i < writes; i++ {
+		eventID := fmt.Sprintf("event-%d", i)
+		if i == writes-1 {
+			eventID = "event-0"
+		}
+		wg.Add(1)
+		go func(i int, eventID string) {
+			defer wg.Done()
+			logs[i], errs[i] = batcher.Write(ctx, newBatchedLog(eventID))
+		}(i, eventID)
+	}
+	wg.Wait()
+
+	// Exactly one of the redeliveries is stored; the other gets the stored log
+	duplicates := 0
+	sequences := make(map[int64]bool)
+	for i := 0; i < writes; i++ {
+		if errs[i] != nil {
+			assert.ErrorIs(t, errs[i], database.ErrDuplicateEventID)
+			duplicates++
+			continue
+		}
+		require.NotNil(t, logs[i].Sequence)
+		sequences[*logs[i].Sequence] = true
+	}
+	assert.Equal(t, 1, duplicates)
+	assert.Len(t, sequences, writes-1)
+	assert.Equal(t, logs[0].ID, logs[writes-1].ID)
+
+	result, err := NewIntegrityService(repo, nil).VerifyChain(context.Background(), 0, 0)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "%+v", result.Failure)
+	assert.Equal(t, int64(writes-1), result.CheckedCount)
+
+	// Once stopped, logs are stored one by one
+	cancel()
+	batcher.Wait()
+	stored, err := batcher.Write(context.Background(), newBatchedLog("event-late"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(writes), *stored.Sequence)
+}
+
+// rejectingRepository fails every batch holding the rejected event ID, and that log on its own
+type rejectingRepository struct {
+	database.AuditRepository
+	rejected string
+}
+
+func (r *rejectingRepository) CreateAuditLogs(ctx context.Context, logs []*v1models.AuditLog) ([]database.CreateResult, error) {
+	for _, log := range logs {
+		if *log.EventID == r.rejected {
+			return nil, errors.New("value too long for type character varying(255)")
+		}
+	}
+	return r.AuditRepository.CreateAuditLogs(ctx, logs)
+}
+
+func (r *rejectingRepository) CreateAuditLog(ctx context.Context, log *v1models.AuditLog) (*v1models.AuditLog, error) {
+	results, err := r.CreateAuditLogs(ctx, []*v1models.AuditLog{log})
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Log, results[0].Err
+}
+
+func TestWriteBatcher_FailedBatchIsRetriedOneByOne(t *testing.T) {
+	repo := &rejectingRepository{AuditRepository: database.NewGormRepository(setupSQLiteTestDB(t)), rejected: "event-rejected"}
+
+	// The rejected log fails its batch; the other logs are still stored
+	results := createAuditLogs(context.Background(), repo, []*v1models.AuditLog{
+		newBatchedLog("event-1"), newBatchedLog("event-rejected"), newBatchedLog("event-2"),
+	})
+	require.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, int64(2), *results[2].Log.Sequence)
+}
@@ -2,28 +2,46 @@ package testutil
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/audit-service/v1/database"
 	v1models "github.com/gov-dx-sandbox/audit-service/v1/models"
 )
 
-// MockRepository is a simple mock implementation of database.AuditRepository for testing
+// MockRepository is a simple mock implementation of database.AuditRepository and
+// database.ExportJobRepository for testing
 type MockRepository struct {
-	logs []*v1models.AuditLog
+	mu         sync.Mutex
+	logs       []*v1models.AuditLog
+	exportJobs map[uuid.UUID]v1models.ExportJob
 }
 
 // NewMockRepository creates a new MockRepository instance
 func NewMockRepository() *MockRepository {
 	return &MockRepository{
-		logs: make([]*v1models.AuditLog, 0),
+		logs:       make([]*v1models.AuditLog, 0),
+		exportJobs: make(map[uuid.UUID]v1models.ExportJob),
 	}
 }
 
 // CreateAuditLog simulates creating an audit log
 // It automatically generates an ID if not provided (simulating BeforeCreate hook behavior)
 func (m *MockRepository) CreateAuditLog(ctx context.Context, log *v1models.AuditLog) (*v1models.AuditLog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if log.EventID != nil {
+		for _, existing := range m.logs {
+			if existing.EventID != nil && *existing.EventID == *log.EventID {
+				return existing, database.ErrDuplicateEventID
+			}
+		}
+	}
 	if log.ID == uuid.Nil {
 		log.ID = uuid.New()
 	}
@@ -31,9 +49,21 @@ func (m *MockRepository) CreateAuditLog(ctx context.Context, log *v1models.Audit
 	return log, nil
 }
 
+// CreateAuditLogs simulates creating several audit logs, with the per-log results of CreateAuditLog
+func (m *MockRepository) CreateAuditLogs(ctx context.Context, logs []*v1models.AuditLog) ([]database.CreateResult, error) {
+	results := make([]database.CreateResult, len(logs))
+	for i, log := range logs {
+		results[i].Log, results[i].Err = m.CreateAuditLog(ctx, log)
+	}
+	return results, nil
+}
+
 // GetAuditLogsByTraceID retrieves all audit logs for a given trace ID
 // Results are ordered by timestamp ASC (chronological order)
 func (m *MockRepository) GetAuditLogsByTraceID(ctx context.Context, traceID string) ([]v1models.AuditLog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// Parse traceID string to UUID for comparison
 	traceUUID, err := uuid.Parse(traceID)
 	if err != nil {
@@ -56,8 +86,11 @@ func (m *MockRepository) GetAuditLogsByTraceID(ctx context.Context, traceID stri
 }
 
 // GetAuditLogs retrieves audit logs with optional filtering
-// Results are ordered by timestamp DESC (newest first) and paginated
+// Results are ordered by timestamp DESC (newest first) unless SortAscending is set, and paginated
 func (m *MockRepository) GetAuditLogs(ctx context.Context, filters *database.AuditLogFilters) ([]v1models.AuditLog, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if filters == nil {
 		filters = &database.AuditLogFilters{}
 	}
@@ -78,6 +111,13 @@ func (m *MockRepository) GetAuditLogs(ctx context.Context, filters *database.Aud
 			}
 		}
 
+		// Filter by CorrelationID
+		if matches && filters.CorrelationID != nil && *filters.CorrelationID != "" {
+			if log.CorrelationID == nil || *log.CorrelationID != *filters.CorrelationID {
+				matches = false
+			}
+		}
+
 		// Filter by EventType
 		if matches && filters.EventType != nil && *filters.EventType != "" {
 			if log.EventType == nil || *log.EventType != *filters.EventType {
@@ -85,6 +125,11 @@ func (m *MockRepository) GetAuditLogs(ctx context.Context, filters *database.Aud
 			}
 		}
 
+		// Exclude event types; logs without an event type are kept
+		if matches && log.EventType != nil && slices.Contains(filters.ExcludeEventTypes, *log.EventType) {
+			matches = false
+		}
+
 		// Filter by EventAction
 		if matches && filters.EventAction != nil && *filters.EventAction != "" {
 			if log.EventAction == nil || *log.EventAction != *filters.EventAction {
@@ -99,6 +144,63 @@ func (m *MockRepository) GetAuditLogs(ctx context.Context, filters *database.Aud
 			}
 		}
 
+		// Filter by actor and target
+		if matches && filters.ActorType != nil && *filters.ActorType != "" && log.ActorType != *filters.ActorType {
+			matches = false
+		}
+		if matches && filters.ActorID != nil && *filters.ActorID != "" && log.ActorID != *filters.ActorID {
+			matches = false
+		}
+		if matches && filters.TargetType != nil && *filters.TargetType != "" && log.TargetType != *filters.TargetType {
+			matches = false
+		}
+		if matches && filters.TargetID != nil && *filters.TargetID != "" {
+			if log.TargetID == nil || *log.TargetID != *filters.TargetID {
+				matches = false
+			}
+		}
+
+		// Filter by consumer application (actor or applicationId in metadata)
+		if matches && filters.ConsumerAppID != nil && *filters.ConsumerAppID != "" {
+			appID := *filters.ConsumerAppID
+			isActor := log.ActorType == v1models.ActorTypeApplication && log.ActorID == appID
+			if !isActor && metadataApplicationID(log.RequestMetadata) != appID && metadataApplicationID(log.ResponseMetadata) != appID {
+				matches = false
+			}
+		}
+
+		// Filter by entity (actor, target or applicationId in metadata)
+		if matches && filters.EntityIDs != nil && !involvesEntity(log, filters.EntityIDs) {
+			matches = false
+		}
+
+		// Filter by data owner (ownerId or ownerEmail in request metadata)
+		if matches && filters.OwnerID != nil && *filters.OwnerID != "" {
+			ownerID, ownerEmail := metadataOwner(log.RequestMetadata)
+			if ownerID != *filters.OwnerID && ownerEmail != *filters.OwnerID {
+				matches = false
+			}
+		}
+
+		// Filter by an ID mentioned in the additional metadata
+		if matches && filters.MentionedID != nil && *filters.MentionedID != "" &&
+			!strings.Contains(string(log.AdditionalMetadata), `"`+*filters.MentionedID+`"`) {
+			matches = false
+		}
+
+		// Filter by time range
+		if matches && filters.StartTime != nil && log.Timestamp.Before(*filters.StartTime) {
+			matches = false
+		}
+		if matches && filters.EndTime != nil && !log.Timestamp.Before(*filters.EndTime) {
+			matches = false
+		}
+
+		// Filter by free-text search
+		if matches && filters.Search != nil && *filters.Search != "" {
+			matches = matchesSearch(log, strings.ToLower(*filters.Search))
+		}
+
 		if matches {
 			filteredLogs = append(filteredLogs, *log)
 		}
@@ -107,11 +209,23 @@ func (m *MockRepository) GetAuditLogs(ctx context.Context, filters *database.Aud
 	// Get total count before pagination
 	total := int64(len(filteredLogs))
 
-	// Sort by timestamp DESC (newest first)
+	// Sort by timestamp DESC (newest first), or ASC when requested, with ID as tie-breaker
 	sort.Slice(filteredLogs, func(i, j int) bool {
-		return filteredLogs[i].Timestamp.After(filteredLogs[j].Timestamp)
+		return logBefore(filteredLogs[i], filteredLogs[j]) == filters.SortAscending
 	})
 
+	// Resume after the cursor, skipping logs at or before it in sort order
+	if filters.After != nil {
+		cursorLog := v1models.AuditLog{ID: filters.After.ID, Timestamp: filters.After.Timestamp}
+		remaining := []v1models.AuditLog{}
+		for _, log := range filteredLogs {
+			if logBefore(cursorLog, log) == filters.SortAscending && log.ID != cursorLog.ID {
+				remaining = append(remaining, log)
+			}
+		}
+		filteredLogs = remaining
+	}
+
 	// Apply pagination
 	limit := filters.Limit
 	if limit <= 0 {
@@ -122,7 +236,7 @@ func (m *MockRepository) GetAuditLogs(ctx context.Context, filters *database.Aud
 	}
 
 	offset := filters.Offset
-	if offset < 0 {
+	if offset < 0 || filters.After != nil {
 		offset = 0
 	}
 
@@ -144,12 +258,176 @@ func (m *MockRepository) GetAuditLogs(ctx context.Context, filters *database.Aud
 	return paginatedLogs, total, nil
 }
 
+// StreamAuditLogs passes all logs matching the filters to fn in batches
+func (m *MockRepository) StreamAuditLogs(ctx context.Context, filters *database.AuditLogFilters, batchSize int, fn func([]v1models.AuditLog) error) error {
+	page := *filters
+	page.Limit = batchSize
+	page.Offset = 0
+	for {
+		batch, _, err := m.GetAuditLogs(ctx, &page)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		page.After = &database.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+}
+
+// CountAuditLogsBySource counts the logs matching the filters per ingestion source and actor
+func (m *MockRepository) CountAuditLogsBySource(ctx context.Context, filters *database.AuditLogFilters) ([]database.SourceCount, error) {
+	counts := make(map[[2]string]int64)
+	err := m.StreamAuditLogs(ctx, filters, 1000, func(batch []v1models.AuditLog) error {
+		for _, log := range batch {
+			counts[[2]string{log.ActorID, log.Source}]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]database.SourceCount, 0, len(counts))
+	for key, count := range counts {
+		result = append(result, database.SourceCount{ActorID: key[0], Source: key[1], Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ActorID != result[j].ActorID {
+			return result[i].ActorID < result[j].ActorID
+		}
+		return result[i].Source < result[j].Source
+	})
+	return result, nil
+}
+
+// CreateExportJob stores an export job
+func (m *MockRepository) CreateExportJob(ctx context.Context, job *v1models.ExportJob) (*v1models.ExportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	job.CreatedAt = time.Now().UTC()
+	job.UpdatedAt = job.CreatedAt
+	m.exportJobs[job.ID] = *job
+	return job, nil
+}
+
+// GetExportJob retrieves a stored export job
+func (m *MockRepository) GetExportJob(ctx context.Context, id uuid.UUID) (*v1models.ExportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.exportJobs[id]
+	if !ok {
+		return nil, database.ErrExportJobNotFound
+	}
+	return &job, nil
+}
+
+// UpdateExportJob replaces a stored export job
+func (m *MockRepository) UpdateExportJob(ctx context.Context, job *v1models.ExportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job.UpdatedAt = time.Now().UTC()
+	m.exportJobs[job.ID] = *job
+	return nil
+}
+
+// FailUnfinishedExportJobs marks pending and running export jobs as failed
+func (m *MockRepository) FailUnfinishedExportJobs(ctx context.Context, reason string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var count int64
+	for id, job := range m.exportJobs {
+		if job.Status == v1models.ExportJobStatusPending || job.Status == v1models.ExportJobStatusRunning {
+			job.Status = v1models.ExportJobStatusFailed
+			job.Error = &reason
+			m.exportJobs[id] = job
+			count++
+		}
+	}
+	return count, nil
+}
+
+// logBefore reports whether a sorts before b in ascending (timestamp, ID) order
+func logBefore(a, b v1models.AuditLog) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+// metadataApplicationID returns the applicationId field of a metadata object, if any
+func metadataApplicationID(metadata v1models.JSONBRawMessage) string {
+	var fields struct {
+		ApplicationID string `json:"applicationId"`
+	}
+	if len(metadata) == 0 || json.Unmarshal(metadata, &fields) != nil {
+		return ""
+	}
+	return fields.ApplicationID
+}
+
+// involvesEntity reports whether the log is in the partition of any of the IDs
+func involvesEntity(log *v1models.AuditLog, ids []string) bool {
+	for _, tenantID := range log.TenantIDs() {
+		if slices.Contains(ids, tenantID) {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataOwner extracts the data owner recorded in log metadata
+func metadataOwner(metadata v1models.JSONBRawMessage) (ownerID, ownerEmail string) {
+	var fields struct {
+		OwnerID    string `json:"ownerId"`
+		OwnerEmail string `json:"ownerEmail"`
+	}
+	if len(metadata) == 0 || json.Unmarshal(metadata, &fields) != nil {
+		return "", ""
+	}
+	return fields.OwnerID, fields.OwnerEmail
+}
+
+// matchesSearch reports whether the actor, target, event type or event action contains the lowercase term
+func matchesSearch(log *v1models.AuditLog, term string) bool {
+	candidates := []string{log.ActorID}
+	for _, value := range []*string{log.TargetID, log.EventType, log.EventAction} {
+		if value != nil {
+			candidates = append(candidates, *value)
+		}
+	}
+	for _, candidate := range candidates {
+		if strings.Contains(strings.ToLower(candidate), term) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetLogs returns all logs stored in the mock (useful for test assertions)
 func (m *MockRepository) GetLogs() []*v1models.AuditLog {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.logs
 }
 
 // ClearLogs clears all stored logs (useful for test cleanup)
 func (m *MockRepository) ClearLogs() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.logs = make([]*v1models.AuditLog, 0)
 }
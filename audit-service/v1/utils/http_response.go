@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/gov-dx-sandbox/audit-service/v1/models"
+	"github.com/gov-dx-sandbox/shared/requestid"
 )
 
 // RespondWithJSON sends a JSON response with the given status code
@@ -24,7 +25,8 @@ func RespondWithJSON(w http.ResponseWriter, statusCode int, payload interface{})
 // RespondWithError sends a JSON error response with the given status code
 func RespondWithError(w http.ResponseWriter, statusCode int, message string, err error) {
 	errorResp := models.ErrorResponse{
-		Error: message,
+		Error:     message,
+		RequestID: requestid.FromResponse(w),
 	}
 	if err != nil {
 		errorResp.Details = err.Error()
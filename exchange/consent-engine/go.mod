@@ -6,6 +6,9 @@ require (
 	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/google/uuid v1.6.0
 	github.com/gov-dx-sandbox/exchange/shared/utils v0.0.0
+	github.com/gov-dx-sandbox/shared/health v0.0.0
+	github.com/gov-dx-sandbox/shared/requestid v0.0.0
+	github.com/gov-dx-sandbox/shared/response v0.0.0 // indirect
 )
 
 require (
@@ -61,3 +64,9 @@ require (
 replace github.com/gov-dx-sandbox/exchange/shared/monitoring => ../shared/monitoring
 
 replace github.com/gov-dx-sandbox/exchange/shared/utils => ../shared/utils
+
+replace github.com/gov-dx-sandbox/shared/health => ../../shared/health
+
+replace github.com/gov-dx-sandbox/shared/requestid => ../../shared/requestid
+
+replace github.com/gov-dx-sandbox/shared/response => ../../shared/response
@@ -3,6 +3,7 @@ package config
 
 import (
 	"flag"
+	"strings"
 	"time"
 
 	"github.com/gov-dx-sandbox/exchange/shared/utils"
@@ -17,6 +18,11 @@ type Config struct {
 	Security         SecurityConfig
 	IDPConfig        IDPConfig
 	DBConfigs        DBConfigs
+	ConsentLinks     ConsentLinkConfig
+	PortalSessions   PortalSessionConfig
+	Notifications    NotificationConfig
+	ConsentReuse     ConsentReuseConfig
+	ConsentWebhooks  ConsentWebhookConfig
 }
 
 // ServiceConfig holds service-specific configuration
@@ -48,6 +54,43 @@ type IDPConfig struct {
 	OrgName  string
 }
 
+// ConsentLinkConfig holds the signing configuration of consent link tokens
+type ConsentLinkConfig struct {
+	Secret string
+	TTL    time.Duration
+}
+
+// PortalSessionConfig holds the signing configuration of consent portal session tokens
+type PortalSessionConfig struct {
+	Secret string
+	TTL    time.Duration
+}
+
+// NotificationConfig holds the SMTP configuration of owner notification emails
+type NotificationConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+}
+
+// ConsentReuseConfig holds how long an active consent is reused for equivalent consent requests
+type ConsentReuseConfig struct {
+	// Window is how long after its creation an active consent is reused; zero reuses it while it is active
+	Window time.Duration
+}
+
+// ConsentWebhookConfig holds the endpoints consent status changes are pushed to
+type ConsentWebhookConfig struct {
+	// URLs receive every consent status change; webhooks are disabled without them
+	URLs []string
+	// Secret signs the webhook requests
+	Secret string
+	// Interval is how often pending changes are pushed
+	Interval time.Duration
+}
+
 // DBConfigs holds database configuration
 type DBConfigs struct {
 	Host     string
@@ -94,6 +137,46 @@ func LoadConfig(serviceName string) *Config {
 	consentPortalUrl := utils.GetEnvOrDefault("CONSENT_PORTAL_URL", "http://localhost:5173")
 	allowedOrigins := utils.GetEnvOrDefault("CORS_ALLOWED_ORIGINS", "")
 
+	// Reading consent link configs; an invalid TTL falls back to the default
+	consentLinkSecret := utils.GetEnvOrDefault("CONSENT_LINK_SECRET", "")
+	consentLinkTTL, err := time.ParseDuration(utils.GetEnvOrDefault("CONSENT_LINK_TTL", "5m"))
+	if err != nil {
+		consentLinkTTL = 5 * time.Minute
+	}
+
+	// Reading portal session configs; an invalid TTL falls back to the default
+	portalSessionSecret := utils.GetEnvOrDefault("PORTAL_SESSION_SECRET", "")
+	portalSessionTTL, err := time.ParseDuration(utils.GetEnvOrDefault("PORTAL_SESSION_TTL", "15m"))
+	if err != nil {
+		portalSessionTTL = 15 * time.Minute
+	}
+
+	// Reading owner notification configs; notifications are disabled without an SMTP host
+	smtpHost := utils.GetEnvOrDefault("SMTP_HOST", "")
+	smtpPort := utils.GetEnvOrDefault("SMTP_PORT", "587")
+	smtpUsername := utils.GetEnvOrDefault("SMTP_USERNAME", "")
+	smtpPassword := utils.GetEnvOrDefault("SMTP_PASSWORD", "")
+	smtpFrom := utils.GetEnvOrDefault("SMTP_FROM", "")
+
+	// Reading the consent reuse window; an invalid or negative window falls back to the default
+	consentReuseWindow, err := time.ParseDuration(utils.GetEnvOrDefault("CONSENT_REUSE_WINDOW", "0s"))
+	if err != nil || consentReuseWindow < 0 {
+		consentReuseWindow = 0
+	}
+
+	// Reading consent change webhook configs; an invalid or non-positive interval falls back to the default
+	var consentWebhookURLs []string
+	for _, webhookURL := range strings.Split(utils.GetEnvOrDefault("CONSENT_WEBHOOK_URLS", ""), ",") {
+		if webhookURL = strings.TrimSpace(webhookURL); webhookURL != "" {
+			consentWebhookURLs = append(consentWebhookURLs, webhookURL)
+		}
+	}
+	consentWebhookSecret := utils.GetEnvOrDefault("CONSENT_WEBHOOK_SECRET", "")
+	consentWebhookInterval, err := time.ParseDuration(utils.GetEnvOrDefault("CONSENT_WEBHOOK_INTERVAL", "5s"))
+	if err != nil || consentWebhookInterval <= 0 {
+		consentWebhookInterval = 5 * time.Second
+	}
+
 	// add the consent portal url to the allowed origins list
 	allowedOrigins += "," + consentPortalUrl
 
@@ -132,6 +215,29 @@ func LoadConfig(serviceName string) *Config {
 			Database: dbName,
 			SSLMode:  dbSslMode,
 		},
+		ConsentLinks: ConsentLinkConfig{
+			Secret: consentLinkSecret,
+			TTL:    consentLinkTTL,
+		},
+		PortalSessions: PortalSessionConfig{
+			Secret: portalSessionSecret,
+			TTL:    portalSessionTTL,
+		},
+		Notifications: NotificationConfig{
+			SMTPHost:     smtpHost,
+			SMTPPort:     smtpPort,
+			SMTPUsername: smtpUsername,
+			SMTPPassword: smtpPassword,
+			SMTPFrom:     smtpFrom,
+		},
+		ConsentReuse: ConsentReuseConfig{
+			Window: consentReuseWindow,
+		},
+		ConsentWebhooks: ConsentWebhookConfig{
+			URLs:     consentWebhookURLs,
+			Secret:   consentWebhookSecret,
+			Interval: consentWebhookInterval,
+		},
 	}
 
 	return config
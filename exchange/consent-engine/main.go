@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,6 +10,8 @@ import (
 	"github.com/gov-dx-sandbox/exchange/consent-engine/internal/config"
 	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
 	"github.com/gov-dx-sandbox/exchange/shared/utils"
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
 
 	// V1 API imports
 	v1auth "github.com/gov-dx-sandbox/exchange/consent-engine/v1/auth"
@@ -73,9 +76,71 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Consent links (QR codes / deep links) are only enabled with a signing secret
+	if cfg.ConsentLinks.Secret != "" {
+		linkSigner, err := v1services.NewConsentLinkSigner(cfg.ConsentLinks.Secret, cfg.ConsentLinks.TTL)
+		if err != nil {
+			slog.Error("Failed to initialize consent link signer", "error", err)
+			os.Exit(1)
+		}
+		v1ConsentService.EnableConsentLinks(linkSigner)
+		slog.Info("Consent links enabled", "ttl", cfg.ConsentLinks.TTL)
+	} else {
+		slog.Warn("CONSENT_LINK_SECRET not set, consent links are disabled")
+	}
+
+	// Portal session hand-off is only enabled with a signing secret
+	var portalSessionsEnabled bool
+	if cfg.PortalSessions.Secret != "" {
+		sessionSigner, err := v1services.NewPortalSessionSigner(cfg.PortalSessions.Secret, cfg.PortalSessions.TTL)
+		if err != nil {
+			slog.Error("Failed to initialize portal session signer", "error", err)
+			os.Exit(1)
+		}
+		v1ConsentService.EnablePortalSessions(sessionSigner)
+		portalSessionsEnabled = true
+		slog.Info("Portal sessions enabled", "ttl", cfg.PortalSessions.TTL)
+	} else {
+		slog.Warn("PORTAL_SESSION_SECRET not set, portal sessions are disabled")
+	}
+
+	// Owners are only notified of consents cancelled by applications with an SMTP server
+	if cfg.Notifications.SMTPHost != "" {
+		sender := v1services.NewSMTPEmailSender(cfg.Notifications.SMTPHost, cfg.Notifications.SMTPPort,
+			cfg.Notifications.SMTPUsername, cfg.Notifications.SMTPPassword, cfg.Notifications.SMTPFrom)
+		v1ConsentService.EnableNotifications(v1services.NewEmailConsentNotifier(sender))
+		slog.Info("Owner notifications enabled", "smtp_host", cfg.Notifications.SMTPHost)
+	} else {
+		slog.Warn("SMTP_HOST not set, owner notifications are disabled")
+	}
+
+	// Active consents are reused for equivalent requests; a window limits for how long after their creation
+	if cfg.ConsentReuse.Window > 0 {
+		v1ConsentService.SetReuseWindow(cfg.ConsentReuse.Window)
+		slog.Info("Consent reuse window set", "window", cfg.ConsentReuse.Window)
+	}
+
+	// Consent status changes are pushed to webhooks when any are configured; they can always be polled
+	if len(cfg.ConsentWebhooks.URLs) > 0 {
+		dispatcher, err := v1services.NewConsentWebhookDispatcher(v1DB, cfg.ConsentWebhooks.URLs, cfg.ConsentWebhooks.Secret)
+		if err != nil {
+			slog.Error("Failed to initialize consent webhooks", "error", err)
+			os.Exit(1)
+		}
+		if err := dispatcher.Register(context.Background()); err != nil {
+			slog.Error("Failed to register consent webhooks", "error", err)
+			os.Exit(1)
+		}
+		webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+		defer stopWebhooks()
+		go dispatcher.Run(webhookCtx, cfg.ConsentWebhooks.Interval)
+		slog.Info("Consent change webhooks enabled", "webhooks", len(cfg.ConsentWebhooks.URLs), "interval", cfg.ConsentWebhooks.Interval)
+	}
+
 	// Initialize V1 handlers
 	v1InternalHandler := v1handlers.NewInternalHandler(v1ConsentService)
 	v1PortalHandler := v1handlers.NewPortalHandler(v1ConsentService)
+	v1AnalyticsHandler := v1handlers.NewAnalyticsHandler(v1services.NewConsentAnalyticsService(v1DB))
 
 	slog.Info("JWT verifier configuration",
 		"org_name", cfg.IDPConfig.OrgName,
@@ -95,15 +160,24 @@ func main() {
 	}
 
 	// Initialize V1 router and register all V1 routes
-	v1Router := v1router.NewV1Router(cfg.Service.AllowedOrigins, v1InternalHandler, v1PortalHandler, v1JWTVerifier)
+	v1Router := v1router.NewV1Router(cfg.Service.AllowedOrigins, v1InternalHandler, v1PortalHandler, v1AnalyticsHandler, v1JWTVerifier)
+	if portalSessionsEnabled {
+		v1Router.AcceptPortalSessions(v1ConsentService)
+	}
 	mux := http.NewServeMux()
 
 	slog.Info("Registering V1 API routes")
 	v1Router.RegisterRoutes(mux)
 	slog.Info("V1 API routes registered successfully")
 
-	// Register legacy /health endpoint for compatibility with health checks
-	mux.Handle("/health", utils.PanicRecoveryMiddleware(utils.HealthHandler("consent-engine")))
+	// Register /health, /health/live and /health/ready; consents cannot be served without the
+	// database, and portal users cannot sign in while the IDP's JWKS is unreachable
+	healthChecker := utils.NewHealthChecker("consent-engine")
+	healthChecker.Register("database", health.PingCheck(v1SqlDB))
+	if cfg.IDPConfig.JwksUrl != "" {
+		healthChecker.RegisterOptional("jwks", health.HTTPCheck(nil, cfg.IDPConfig.JwksUrl))
+	}
+	healthChecker.RegisterRoutes(mux)
 
 	// Register /metrics endpoint for Prometheus scraping
 	mux.Handle("/metrics", monitoring.Handler())
@@ -116,9 +190,9 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Wrap the mux with metrics (outermost) and then CORS from v1 router
+	// Wrap the mux with metrics (outermost), request IDs and then CORS from v1 router
 	// Metrics must be outermost to capture all requests, including CORS-blocked ones
-	handler := monitoring.HTTPMetricsMiddleware(v1Router.ApplyCORS(mux))
+	handler := monitoring.HTTPMetricsMiddleware(requestid.Middleware(v1Router.ApplyCORS(mux)))
 	httpServer := utils.CreateServer(serverConfig, handler)
 
 	// Start server with graceful shutdown
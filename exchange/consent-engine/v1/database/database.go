@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/gov-dx-sandbox/exchange/consent-engine/internal/config"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/migrations"
 	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -75,6 +76,8 @@ func ConnectGormDB(config *Config) (*gorm.DB, error) {
 	for i := 0; i < maxRetries; i++ {
 		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
 			Logger: gormLogger,
+			// Report unique violations as gorm.ErrDuplicatedKey
+			TranslateError: true,
 		})
 		if err == nil {
 			break
@@ -131,6 +134,14 @@ func ConnectGormDB(config *Config) (*gorm.DB, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to run auto-migration: %w", err)
 		}
+		// Views are not auto-migrated
+		if err := db.Exec(migrations.ConsentAnalyticsViews).Error; err != nil {
+			return nil, fmt.Errorf("failed to create consent analytics views: %w", err)
+		}
+		// The consent change feed is filled by a trigger, which is not auto-migrated either
+		if err := db.Exec(migrations.ConsentStatusChanges).Error; err != nil {
+			return nil, fmt.Errorf("failed to create consent status change feed: %w", err)
+		}
 		slog.Info("GORM auto-migration completed successfully")
 	} else {
 		slog.Info("Database connected (migration skipped)")
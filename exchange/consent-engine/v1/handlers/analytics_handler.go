@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/services"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/utils"
+)
+
+// defaultAnalyticsRange is the time range of consent analytics requested without a from parameter
+const defaultAnalyticsRange = 30 * 24 * time.Hour
+
+// AnalyticsHandler handles the internal consent analytics API used for governance reporting in the
+// admin portal
+type AnalyticsHandler struct {
+	analyticsService *services.ConsentAnalyticsService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService *services.ConsentAnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetApprovalRates handles GET /internal/api/v1/analytics/approval-rates
+// Query parameters: from, to (RFC3339, default the last 30 days) and appId
+// Returns: models.ApprovalRatesResponse
+func (h *AnalyticsHandler) GetApprovalRates(w http.ResponseWriter, r *http.Request) {
+	serveAnalytics(w, r, h.analyticsService.ApprovalRates)
+}
+
+// GetTimeToConsent handles GET /internal/api/v1/analytics/time-to-consent
+// Query parameters: from, to (RFC3339, default the last 30 days) and appId
+// Returns: models.TimeToConsentResponse
+func (h *AnalyticsHandler) GetTimeToConsent(w http.ResponseWriter, r *http.Request) {
+	serveAnalytics(w, r, h.analyticsService.TimeToConsent)
+}
+
+// GetGrantRenewals handles GET /internal/api/v1/analytics/grant-renewals
+// Query parameters: from, to (RFC3339, default the last 30 days) and appId
+// Returns: models.GrantRenewalsResponse
+func (h *AnalyticsHandler) GetGrantRenewals(w http.ResponseWriter, r *http.Request) {
+	serveAnalytics(w, r, h.analyticsService.GrantRenewals)
+}
+
+// GetDenialReasons handles GET /internal/api/v1/analytics/denial-reasons
+// Query parameters: from, to (RFC3339, default the last 30 days) and appId
+// Returns: models.DenialReasonsResponse
+func (h *AnalyticsHandler) GetDenialReasons(w http.ResponseWriter, r *http.Request) {
+	serveAnalytics(w, r, h.analyticsService.DenialReasons)
+}
+
+// serveAnalytics parses the analytics filter of the request and responds with the statistic computed for it
+func serveAnalytics[T any](w http.ResponseWriter, r *http.Request, compute func(context.Context, services.AnalyticsFilter) (T, error)) {
+	if r.Method != http.MethodGet {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, models.ErrorCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	filter, err := parseAnalyticsFilter(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	result, err := compute(r.Context(), filter)
+	if err != nil {
+		if r.Context().Err() != nil {
+			slog.Warn("Request context cancelled during service call", "error", r.Context().Err())
+			utils.RespondWithError(w, http.StatusRequestTimeout, models.ErrorCodeInternalError, "Request timeout or cancelled")
+			return
+		}
+		slog.Error("Failed to "+string(models.OpGetConsentAnalytics), "error", err)
+		utils.RespondWithError(w, http.StatusInternalServerError, models.ErrorCodeInternalError, "An unexpected error occurred")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, result)
+}
+
+// parseAnalyticsFilter parses the from, to and appId query parameters
+func parseAnalyticsFilter(r *http.Request) (services.AnalyticsFilter, error) {
+	query := r.URL.Query()
+	filter := services.AnalyticsFilter{AppID: query.Get("appId")}
+
+	filter.To = time.Now().UTC()
+	if value := query.Get("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to: must be an RFC3339 timestamp")
+		}
+		filter.To = to.UTC()
+	}
+	filter.From = filter.To.Add(-defaultAnalyticsRange)
+	if value := query.Get("from"); value != "" {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from: must be an RFC3339 timestamp")
+		}
+		filter.From = from.UTC()
+	}
+
+	if !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+	return filter, nil
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsHandler_MethodNotAllowed(t *testing.T) {
+	handler := &AnalyticsHandler{analyticsService: nil}
+
+	req := httptest.NewRequest("POST", "/internal/api/v1/analytics/approval-rates", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetApprovalRates(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestAnalyticsHandler_InvalidTimeRange(t *testing.T) {
+	handler := &AnalyticsHandler{analyticsService: nil}
+
+	for _, query := range []string{
+		"from=yesterday",
+		"to=2026-13-01",
+		"from=2026-10-01T00:00:00Z&to=2026-09-01T00:00:00Z",
+	} {
+		req := httptest.NewRequest("GET", "/internal/api/v1/analytics/denial-reasons?"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.GetDenialReasons(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestParseAnalyticsFilter(t *testing.T) {
+	t.Run("Defaults to the last 30 days", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/internal/api/v1/analytics/time-to-consent?appId=app-1", nil)
+
+		filter, err := parseAnalyticsFilter(req)
+		require.NoError(t, err)
+		assert.Equal(t, "app-1", filter.AppID)
+		assert.WithinDuration(t, time.Now(), filter.To, time.Minute)
+		assert.Equal(t, defaultAnalyticsRange, filter.To.Sub(filter.From))
+	})
+
+	t.Run("Uses the given range", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/internal/api/v1/analytics/time-to-consent?from=2026-09-01T00:00:00%2B05:30&to=2026-10-01T00:00:00Z", nil)
+
+		filter, err := parseAnalyticsFilter(req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 8, 31, 18, 30, 0, 0, time.UTC), filter.From)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), filter.To)
+	})
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
 	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/services"
 	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/utils"
@@ -91,6 +93,45 @@ func (h *InternalHandler) GetConsent(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, consent)
 }
 
+// GetConsentChanges handles GET /internal/api/v1/consents/changes
+// Query parameters: since (cursor of the last change seen, empty to start at the first), appId (optional)
+// and limit (optional, up to services.MaxConsentChangesLimit)
+// Returns: models.ConsentChangesResponse
+func (h *InternalHandler) GetConsentChanges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, models.ErrorCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	since, err := services.ParseConsentCursor(r.URL.Query().Get("since"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "since must be a cursor returned by this endpoint")
+		return
+	}
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "limit must be a positive integer")
+			return
+		}
+	}
+
+	changes, err := h.consentService.ListConsentChanges(r.Context(), since, r.URL.Query().Get("appId"), limit)
+	if err != nil {
+		if r.Context().Err() != nil {
+			slog.Warn("Request context cancelled during service call", "error", r.Context().Err())
+			utils.RespondWithError(w, http.StatusRequestTimeout, models.ErrorCodeInternalError, "Request timeout or cancelled")
+			return
+		}
+		slog.Error("Failed to "+string(models.OpGetConsentChanges), "error", err)
+		utils.RespondWithError(w, http.StatusInternalServerError, models.ErrorCodeInternalError, "An unexpected error occurred")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, changes)
+}
+
 // CreateConsent handles POST /internal/api/v1/consents
 // Body: models.CreateConsentRequest
 // Returns: []models.ConsentResponseInternalView
@@ -129,3 +170,76 @@ func (h *InternalHandler) CreateConsent(w http.ResponseWriter, r *http.Request)
 
 	utils.RespondWithJSON(w, http.StatusCreated, consents)
 }
+
+// CreateConsentLink handles POST /internal/api/v1/consents/:consentId/links
+// Body: models.CreateConsentLinkRequest
+// Returns: models.ConsentLinkResponse, whose deepLink can be rendered as a QR code
+func (h *InternalHandler) CreateConsentLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, models.ErrorCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	consentID := r.PathValue("consentId")
+	if _, err := uuid.Parse(consentID); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "invalid consentId format")
+		return
+	}
+
+	defer r.Body.Close()
+	var req models.CreateConsentLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+
+	link, err := h.consentService.CreateConsentLink(r.Context(), consentID, req)
+	if err != nil {
+		respondWithConsentLinkError(w, r, err, models.OpMintConsentLink)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, link)
+}
+
+// CancelConsent handles DELETE /internal/api/v1/consents/:consentId
+// Query parameters: appId of the application that requested the consent
+// Returns: models.ConsentResponseInternalView of the cancelled consent
+func (h *InternalHandler) CancelConsent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, models.ErrorCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	consentID := r.PathValue("consentId")
+	if _, err := uuid.Parse(consentID); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "invalid consentId format")
+		return
+	}
+	appID := r.URL.Query().Get("appId")
+	if appID == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "appId is required")
+		return
+	}
+
+	consent, err := h.consentService.CancelConsent(r.Context(), consentID, appID)
+	if err != nil {
+		switch {
+		case r.Context().Err() != nil:
+			slog.Warn("Request context cancelled during service call", "error", r.Context().Err())
+			utils.RespondWithError(w, http.StatusRequestTimeout, models.ErrorCodeInternalError, "Request timeout or cancelled")
+		case errors.Is(err, models.ErrConsentNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, models.ErrorCodeConsentNotFound, "Consent not found")
+		case errors.Is(err, models.ErrConsentAppMismatch):
+			utils.RespondWithError(w, http.StatusForbidden, models.ErrorCodeForbidden, "Access denied: consent was requested by a different application")
+		case errors.Is(err, models.ErrConsentNotPending):
+			utils.RespondWithError(w, http.StatusConflict, models.ErrorCodeConsentNotPending, "Consent is no longer pending")
+		default:
+			slog.Error("Failed to "+string(models.OpCancelConsent), "error", err)
+			utils.RespondWithError(w, http.StatusInternalServerError, models.ErrorCodeInternalError, "An unexpected error occurred")
+		}
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, consent)
+}
@@ -316,3 +316,168 @@ func TestInternalHandler_CreateConsent_InternalError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestInternalHandler_CreateConsentLink_Success(t *testing.T) {
+	service, mock := setupTestService(t)
+	signer, err := services.NewConsentLinkSigner("0123456789abcdef0123456789abcdef", time.Minute)
+	require.NoError(t, err)
+	service.EnableConsentLinks(signer)
+	handler := NewInternalHandler(service)
+
+	id := uuid.New()
+	rows := sqlmock.NewRows([]string{"consent_id", "owner_id", "owner_email", "app_id", "status", "type", "created_at", "updated_at", "grant_duration", "fields"}).
+		AddRow(id, "199012345678", "user@example.com", "app-1", "pending", "realtime", time.Now(), time.Now(), "P30D", "[]")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE consent_id = $1`)).
+		WithArgs(id, 1).
+		WillReturnRows(rows)
+
+	req := httptest.NewRequest("POST", "/internal/api/v1/consents/"+id.String()+"/links", bytes.NewBufferString(`{"ownerId":"199012345678"}`))
+	req.SetPathValue("consentId", id.String())
+	w := httptest.NewRecorder()
+
+	handler.CreateConsentLink(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.ConsentLinkResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, id.String(), response.ConsentID)
+	assert.Contains(t, response.DeepLink, "consentToken=")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInternalHandler_CreateConsentLink_Disabled(t *testing.T) {
+	service, _ := setupTestService(t)
+	handler := NewInternalHandler(service)
+
+	id := uuid.New().String()
+	req := httptest.NewRequest("POST", "/internal/api/v1/consents/"+id+"/links", bytes.NewBufferString(`{"ownerId":"199012345678"}`))
+	req.SetPathValue("consentId", id)
+	w := httptest.NewRecorder()
+
+	handler.CreateConsentLink(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestInternalHandler_CreateConsentLink_InvalidUUID(t *testing.T) {
+	handler := &InternalHandler{consentService: nil}
+
+	req := httptest.NewRequest("POST", "/internal/api/v1/consents/invalid-uuid/links", bytes.NewBufferString(`{}`))
+	req.SetPathValue("consentId", "invalid-uuid")
+	w := httptest.NewRecorder()
+
+	handler.CreateConsentLink(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestInternalHandler_CancelConsent(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		appID      string
+		wantStatus int
+	}{
+		{name: "pending", status: "pending", appID: "app-1", wantStatus: http.StatusOK},
+		{name: "other application", status: "pending", appID: "app-2", wantStatus: http.StatusForbidden},
+		{name: "approved", status: "approved", appID: "app-1", wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mock := setupTestService(t)
+			handler := NewInternalHandler(service)
+
+			id := uuid.New()
+			rows := sqlmock.NewRows([]string{"consent_id", "owner_id", "owner_email", "app_id", "status", "type", "created_at", "updated_at", "grant_duration", "fields"}).
+				AddRow(id, "199012345678", "user@example.com", "app-1", tt.status, "realtime", time.Now(), time.Now(), "P30D", "[]")
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE consent_id = $1`)).
+				WithArgs(id, 1).
+				WillReturnRows(rows)
+			if tt.wantStatus == http.StatusOK {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_records"`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			req := httptest.NewRequest("DELETE", "/internal/api/v1/consents/"+id.String()+"?appId="+tt.appID, nil)
+			req.SetPathValue("consentId", id.String())
+			w := httptest.NewRecorder()
+
+			handler.CancelConsent(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response models.ConsentResponseInternalView
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "cancelled", response.Status)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInternalHandler_CancelConsent_MissingAppId(t *testing.T) {
+	handler := &InternalHandler{consentService: nil}
+
+	id := uuid.New().String()
+	req := httptest.NewRequest("DELETE", "/internal/api/v1/consents/"+id, nil)
+	req.SetPathValue("consentId", id)
+	w := httptest.NewRecorder()
+
+	handler.CancelConsent(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestInternalHandler_GetConsentChanges_Success(t *testing.T) {
+	service, mock := setupTestService(t)
+	handler := NewInternalHandler(service)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_status_changes" WHERE id > $1 AND app_id = $2 ORDER BY id LIMIT $3`)).
+		WithArgs(int64(3), "app-1", 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "consent_id", "app_id", "owner_id", "previous_status", "status", "changed_at"}).
+			AddRow(4, uuid.New(), "app-1", "user-1", "approved", "revoked", time.Now()))
+
+	req := httptest.NewRequest("GET", "/internal/api/v1/consents/changes?since=3&appId=app-1&limit=10", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetConsentChanges(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.ConsentChangesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Changes, 1)
+	assert.Equal(t, "revoked", response.Changes[0].Status)
+	assert.Equal(t, "4", response.NextCursor)
+	assert.False(t, response.HasMore)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInternalHandler_GetConsentChanges_InvalidQuery(t *testing.T) {
+	handler := &InternalHandler{consentService: nil}
+
+	for _, query := range []string{"since=abc", "since=-2", "limit=0", "limit=ten"} {
+		req := httptest.NewRequest("GET", "/internal/api/v1/consents/changes?"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.GetConsentChanges(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestInternalHandler_GetConsentChanges_InternalError(t *testing.T) {
+	service, mock := setupTestService(t)
+	handler := NewInternalHandler(service)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_status_changes"`)).
+		WillReturnError(errors.New("connection refused"))
+
+	req := httptest.NewRequest("GET", "/internal/api/v1/consents/changes", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetConsentChanges(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -98,7 +98,7 @@ func (h *PortalHandler) GetConsent(w http.ResponseWriter, r *http.Request) {
 // UpdateConsent handles PUT /api/v1/consents/:consentId
 // Authorization: Bearer Token
 // Verifies that consent.owner_email matches the email from the decoded token
-// Body: { "action": "approve" | "reject" }
+// Body: { "action": "approve" | "reject", "reason": optional models.RejectionReason for rejections }
 func (h *PortalHandler) UpdateConsent(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		utils.RespondWithError(w, http.StatusMethodNotAllowed, models.ErrorCodeMethodNotAllowed, "Method not allowed")
@@ -127,7 +127,8 @@ func (h *PortalHandler) UpdateConsent(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var actionReq struct {
-		Action string `json:"action"`
+		Action string                  `json:"action"`
+		Reason *models.RejectionReason `json:"reason,omitempty"`
 	}
 	defer r.Body.Close()
 	if err := json.NewDecoder(r.Body).Decode(&actionReq); err != nil {
@@ -140,6 +141,11 @@ func (h *PortalHandler) UpdateConsent(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, fmt.Sprintf("Invalid action: %s. Must be 'approve' or 'reject'", actionReq.Action))
 		return
 	}
+	if actionReq.Reason != nil && (actionReq.Action != string(models.ActionReject) || !actionReq.Reason.IsValid()) {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest,
+			"Invalid reason. Only rejections take a reason: 'unknown_application', 'unnecessary_data', 'privacy_concern' or 'other'")
+		return
+	}
 
 	// First, get the consent to verify ownership (context with timeout is propagated)
 	consent, err := h.consentService.GetConsentPortalView(r.Context(), consentID)
@@ -170,6 +176,7 @@ func (h *PortalHandler) UpdateConsent(w http.ResponseWriter, r *http.Request) {
 		ConsentID: consentID,
 		Action:    models.ConsentPortalAction(actionReq.Action),
 		UpdatedBy: userEmail,
+		Reason:    actionReq.Reason,
 	}
 
 	if err := h.consentService.UpdateConsentStatusByPortalAction(r.Context(), updateReq); err != nil {
@@ -183,6 +190,10 @@ func (h *PortalHandler) UpdateConsent(w http.ResponseWriter, r *http.Request) {
 			utils.RespondWithError(w, http.StatusNotFound, models.ErrorCodeConsentNotFound, "Consent not found")
 			return
 		}
+		if errors.Is(err, models.ErrConsentNotPending) {
+			utils.RespondWithError(w, http.StatusConflict, models.ErrorCodeConsentNotPending, "Consent is no longer pending")
+			return
+		}
 		if errors.Is(err, models.ErrPortalRequestFailed) {
 			utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid consent update request")
 			return
@@ -203,3 +214,97 @@ func (h *PortalHandler) UpdateConsent(w http.ResponseWriter, r *http.Request) {
 	}
 	utils.RespondWithJSON(w, http.StatusOK, response)
 }
+
+// RedeemConsentLink handles POST /api/v1/consent-links/redeem
+// Authorization: Bearer Token
+// Body: models.RedeemConsentLinkRequest
+// Verifies the token was minted for the given NIC and that the consent belongs to the authenticated user
+func (h *PortalHandler) RedeemConsentLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, models.ErrorCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userEmail, ok := middleware.GetUserEmailFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "User email not found in token")
+		return
+	}
+
+	defer r.Body.Close()
+	var req models.RedeemConsentLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if req.Token == "" || req.OwnerID == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "token and ownerId are required")
+		return
+	}
+
+	redemption, err := h.consentService.RedeemConsentLink(r.Context(), req, userEmail)
+	if err != nil {
+		respondWithConsentLinkError(w, r, err, models.OpRedeemConsentLink)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, redemption)
+}
+
+// CreatePortalSession handles POST /api/v1/consents/{consentId}/portal-session
+// Authorization: Bearer Token
+// Exchanges the citizen's IDP token for a short-lived portal session token scoped to one pending consent
+func (h *PortalHandler) CreatePortalSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, models.ErrorCodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userEmail, ok := middleware.GetUserEmailFromContext(r.Context())
+	if !ok {
+		utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "User email not found in token")
+		return
+	}
+
+	consentID := r.PathValue("consentId")
+	if consentID == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Consent ID is required")
+		return
+	}
+
+	session, err := h.consentService.CreatePortalSession(r.Context(), consentID, userEmail)
+	if errors.Is(err, models.ErrPortalSessionsDisabled) {
+		utils.RespondWithError(w, http.StatusServiceUnavailable, models.ErrorCodePortalSessionsDisabled, "Portal sessions are not enabled")
+		return
+	}
+	if err != nil {
+		respondWithConsentLinkError(w, r, err, models.OpCreatePortalSession)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, session)
+}
+
+// respondWithConsentLinkError maps errors from minting or redeeming consent links to responses
+func respondWithConsentLinkError(w http.ResponseWriter, r *http.Request, err error, op models.ConsentEngineOperation) {
+	switch {
+	case r.Context().Err() != nil:
+		slog.Warn("Request context cancelled during service call", "error", r.Context().Err())
+		utils.RespondWithError(w, http.StatusRequestTimeout, models.ErrorCodeInternalError, "Request timeout or cancelled")
+	case errors.Is(err, models.ErrConsentLinksDisabled):
+		utils.RespondWithError(w, http.StatusServiceUnavailable, models.ErrorCodeConsentLinksDisabled, "Consent links are not enabled")
+	case errors.Is(err, models.ErrConsentLinkExpired):
+		utils.RespondWithError(w, http.StatusGone, models.ErrorCodeConsentLinkExpired, "Consent link has expired")
+	case errors.Is(err, models.ErrConsentLinkInvalid):
+		utils.RespondWithError(w, http.StatusBadRequest, models.ErrorCodeInvalidConsentLink, err.Error())
+	case errors.Is(err, models.ErrConsentOwnerMismatch):
+		utils.RespondWithError(w, http.StatusForbidden, models.ErrorCodeForbidden, "Access denied: consent belongs to a different user")
+	case errors.Is(err, models.ErrConsentNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, models.ErrorCodeConsentNotFound, "Consent not found")
+	case errors.Is(err, models.ErrConsentNotPending):
+		utils.RespondWithError(w, http.StatusConflict, models.ErrorCodeConsentNotPending, "Consent is no longer pending")
+	default:
+		slog.Error("Failed to "+string(op), "error", err)
+		utils.RespondWithError(w, http.StatusInternalServerError, models.ErrorCodeInternalError, "An unexpected error occurred")
+	}
+}
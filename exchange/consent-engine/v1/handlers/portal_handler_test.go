@@ -173,3 +173,47 @@ func TestPortalHandler_HealthCheck_MethodNotAllowed(t *testing.T) {
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 }
+
+func TestPortalHandler_RedeemConsentLink_MethodNotAllowed(t *testing.T) {
+	handler := &PortalHandler{consentService: nil}
+
+	req := httptest.NewRequest("GET", "/api/v1/consent-links/redeem", nil)
+	w := httptest.NewRecorder()
+
+	handler.RedeemConsentLink(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestPortalHandler_RedeemConsentLink_Unauthenticated(t *testing.T) {
+	handler := &PortalHandler{consentService: nil}
+
+	req := httptest.NewRequest("POST", "/api/v1/consent-links/redeem", bytes.NewBufferString(`{"token":"t","ownerId":"199012345678"}`))
+	w := httptest.NewRecorder()
+
+	handler.RedeemConsentLink(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPortalHandler_CreatePortalSession_MethodNotAllowed(t *testing.T) {
+	handler := &PortalHandler{consentService: nil}
+
+	req := httptest.NewRequest("GET", "/api/v1/consents/"+uuid.New().String()+"/portal-session", nil)
+	w := httptest.NewRecorder()
+
+	handler.CreatePortalSession(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestPortalHandler_CreatePortalSession_Unauthenticated(t *testing.T) {
+	handler := &PortalHandler{consentService: nil}
+
+	req := httptest.NewRequest("POST", "/api/v1/consents/"+uuid.New().String()+"/portal-session", nil)
+	w := httptest.NewRecorder()
+
+	handler.CreatePortalSession(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -20,9 +21,17 @@ const (
 	userEmailKey contextKey = "userEmail"
 )
 
+// PortalSessionVerifier verifies portal session tokens, which are scoped to a single consent
+type PortalSessionVerifier interface {
+	// VerifyPortalSession returns the email of the citizen the token was handed to
+	VerifyPortalSession(token, consentID string) (string, error)
+}
+
 // JWTAuthMiddleware provides HTTP middleware for JWT authentication
 type JWTAuthMiddleware struct {
 	verifier *auth.JWTVerifier
+	// sessions verifies portal session tokens; nil while they are not accepted
+	sessions PortalSessionVerifier
 }
 
 // NewJWTAuthMiddleware creates a new JWT authentication middleware
@@ -32,27 +41,16 @@ func NewJWTAuthMiddleware(verifier *auth.JWTVerifier) *JWTAuthMiddleware {
 	}
 }
 
+// AcceptPortalSessions lets AuthenticateConsent accept portal session tokens verified by sessions
+func (m *JWTAuthMiddleware) AcceptPortalSessions(sessions PortalSessionVerifier) {
+	m.sessions = sessions
+}
+
 // Authenticate is the middleware function that validates JWT tokens
 func (m *JWTAuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Authorization header is required")
-			return
-		}
-
-		// Check Bearer prefix
-		const bearerPrefix = "Bearer "
-		if !strings.HasPrefix(authHeader, bearerPrefix) {
-			utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Invalid authorization format. Expected 'Bearer <token>'")
-			return
-		}
-
-		// Extract token
-		tokenString := strings.TrimPrefix(authHeader, bearerPrefix)
-		if tokenString == "" {
-			utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Token is required")
+		tokenString, ok := bearerToken(w, r)
+		if !ok {
 			return
 		}
 
@@ -75,6 +73,63 @@ func (m *JWTAuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// AuthenticateConsent authenticates requests for the consent in the consentId path parameter, with
+// either the citizen's IDP token or a portal session token scoped to that consent
+func (m *JWTAuthMiddleware) AuthenticateConsent(next http.Handler) http.Handler {
+	authenticated := m.Authenticate(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString, ok := bearerToken(w, r)
+		if !ok {
+			return
+		}
+		if !strings.HasPrefix(tokenString, models.PortalSessionTokenPrefix) {
+			authenticated.ServeHTTP(w, r)
+			return
+		}
+		if m.sessions == nil {
+			utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Invalid or expired token")
+			return
+		}
+
+		email, err := m.sessions.VerifyPortalSession(tokenString, r.PathValue("consentId"))
+		if errors.Is(err, models.ErrPortalSessionScope) {
+			utils.RespondWithError(w, http.StatusForbidden, models.ErrorCodeForbidden, "Access denied: session is for a different consent")
+			return
+		}
+		if err != nil {
+			slog.Warn("Portal session verification failed", "error", err)
+			utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Invalid or expired token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userEmailKey, email)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// bearerToken extracts the bearer token of the request, responding with 401 when there is none
+func bearerToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Authorization header is required")
+		return "", false
+	}
+
+	// Check Bearer prefix
+	const bearerPrefix = "Bearer "
+	if !strings.HasPrefix(authHeader, bearerPrefix) {
+		utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Invalid authorization format. Expected 'Bearer <token>'")
+		return "", false
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, bearerPrefix)
+	if tokenString == "" {
+		utils.RespondWithError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Token is required")
+		return "", false
+	}
+	return tokenString, true
+}
+
 // GetUserEmailFromContext extracts the user email from the request context
 func GetUserEmailFromContext(ctx context.Context) (string, bool) {
 	email, ok := ctx.Value(userEmailKey).(string)
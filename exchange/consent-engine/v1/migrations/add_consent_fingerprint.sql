@@ -0,0 +1,17 @@
+-- Migration: Add request fingerprints to consent records
+-- Date: 2026-10-16
+-- Description: Adds the fingerprint column, a SHA-256 of the owner, consumer application, fields and
+--              purposes a consent was requested for, and a partial unique index on it that only applies
+--              when status is 'pending' or 'approved'.
+--              This ensures concurrent equivalent consent requests cannot create duplicate active consents.
+--              Consents created before this migration keep a NULL fingerprint, which the index ignores.
+
+ALTER TABLE consent_records ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_consent_active_fingerprint
+    ON consent_records(fingerprint)
+    WHERE status IN ('pending', 'approved');
+
+-- To rollback this migration:
+-- DROP INDEX IF EXISTS idx_consent_active_fingerprint;
+-- ALTER TABLE consent_records DROP COLUMN IF EXISTS fingerprint;
@@ -0,0 +1,65 @@
+-- Migration: Record consent decisions and create the consent analytics views
+-- Date: 2026-10-16
+-- Description: Adds decided_at and rejection_reason to consent_records, set when the owner approves or
+--              rejects a consent in the consent portal, and creates the views the consent analytics
+--              endpoints aggregate over a time range. Consents decided before this migration have no
+--              decided_at and are left out of time-to-consent.
+
+ALTER TABLE consent_records ADD COLUMN IF NOT EXISTS decided_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE consent_records ADD COLUMN IF NOT EXISTS rejection_reason VARCHAR(50);
+
+-- consent_outcomes derives the outcome of every consent. Approved consents keep their grant_expires_at
+-- when they later expire or are revoked, so they still count as approved.
+--   approved:  the owner approved the consent
+--   rejected:  the owner rejected the consent
+--   timed_out: the consent expired while pending
+--   withdrawn: the consent was cancelled by the consumer, or revoked by a newer request, while pending
+--   pending:   the consent still awaits the owner
+CREATE OR REPLACE VIEW consent_outcomes AS
+SELECT
+    consent_id,
+    app_id,
+    app_name,
+    owner_id,
+    type,
+    created_at,
+    decided_at,
+    grant_expires_at,
+    rejection_reason,
+    CASE
+        WHEN grant_expires_at IS NOT NULL THEN 'approved'
+        WHEN status = 'rejected' THEN 'rejected'
+        WHEN status = 'expired' OR (status = 'pending' AND pending_expires_at <= CURRENT_TIMESTAMP) THEN 'timed_out'
+        WHEN status = 'pending' THEN 'pending'
+        ELSE 'withdrawn'
+    END AS outcome,
+    EXTRACT(EPOCH FROM (decided_at - created_at)) AS seconds_to_decision
+FROM consent_records;
+
+-- consent_grant_expiries lists approved grants that reached their expiry, and whether the consumer
+-- renewed them by obtaining a newer approved consent from the same owner. Grants revoked before they
+-- expired are not listed.
+CREATE OR REPLACE VIEW consent_grant_expiries AS
+SELECT
+    g.consent_id,
+    g.app_id,
+    g.app_name,
+    g.owner_id,
+    g.grant_expires_at,
+    EXISTS (
+        SELECT 1 FROM consent_records n
+        WHERE n.owner_id = g.owner_id
+          AND n.app_id = g.app_id
+          AND n.created_at > g.created_at
+          AND n.grant_expires_at IS NOT NULL
+    ) AS renewed
+FROM consent_records g
+WHERE g.grant_expires_at IS NOT NULL
+  AND g.grant_expires_at <= CURRENT_TIMESTAMP
+  AND g.status IN ('approved', 'expired');
+
+-- To rollback this migration:
+-- DROP VIEW IF EXISTS consent_grant_expiries;
+-- DROP VIEW IF EXISTS consent_outcomes;
+-- ALTER TABLE consent_records DROP COLUMN IF EXISTS rejection_reason;
+-- ALTER TABLE consent_records DROP COLUMN IF EXISTS decided_at;
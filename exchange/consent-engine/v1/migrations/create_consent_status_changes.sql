@@ -0,0 +1,67 @@
+-- Migration: Record consent status changes for the consent change feed
+-- Date: 2026-10-16
+-- Description: Creates consent_status_changes, which a trigger on consent_records fills with every
+--              created consent and every change of a consent's status, in the transaction that made
+--              it. The id of a change is the cursor of GET /internal/api/v1/consents/changes.
+--              Writers take a transaction-scoped advisory lock before they take an id, so changes
+--              commit in id order and a reader never skips a change committed after its cursor.
+--              consent_change_webhooks keeps the cursor up to which each webhook was delivered.
+--              It is idempotent, so it is run after every auto-migration.
+
+CREATE TABLE IF NOT EXISTS consent_status_changes (
+    id BIGSERIAL PRIMARY KEY,
+    consent_id UUID NOT NULL,
+    app_id VARCHAR(255) NOT NULL,
+    owner_id VARCHAR(255) NOT NULL,
+    previous_status VARCHAR(50),
+    status VARCHAR(50) NOT NULL,
+    grant_expires_at TIMESTAMP WITH TIME ZONE,
+    changed_by VARCHAR(255),
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_consent_status_changes_app_id ON consent_status_changes(app_id, id);
+CREATE INDEX IF NOT EXISTS idx_consent_status_changes_consent_id ON consent_status_changes(consent_id);
+
+CREATE TABLE IF NOT EXISTS consent_change_webhooks (
+    url TEXT PRIMARY KEY,
+    cursor BIGINT NOT NULL DEFAULT 0,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE FUNCTION record_consent_status_change() RETURNS TRIGGER AS $$
+BEGIN
+    -- Serializes the writers of changes until they commit, so ids become visible in order
+    PERFORM pg_advisory_xact_lock(hashtext('consent_status_changes'));
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO consent_status_changes (consent_id, app_id, owner_id, previous_status, status, grant_expires_at, changed_by)
+        VALUES (NEW.consent_id, NEW.app_id, NEW.owner_id, NULL, NEW.status, NEW.grant_expires_at, NEW.updated_by);
+    ELSE
+        -- Lazy expiry keeps updated_by, so it only names who made the change when it was set with it
+        INSERT INTO consent_status_changes (consent_id, app_id, owner_id, previous_status, status, grant_expires_at, changed_by)
+        VALUES (NEW.consent_id, NEW.app_id, NEW.owner_id, OLD.status, NEW.status, NEW.grant_expires_at,
+                CASE WHEN NEW.updated_by IS DISTINCT FROM OLD.updated_by THEN NEW.updated_by END);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS consent_records_status_insert ON consent_records;
+CREATE TRIGGER consent_records_status_insert
+    AFTER INSERT ON consent_records
+    FOR EACH ROW EXECUTE FUNCTION record_consent_status_change();
+
+DROP TRIGGER IF EXISTS consent_records_status_update ON consent_records;
+CREATE TRIGGER consent_records_status_update
+    AFTER UPDATE OF status ON consent_records
+    FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION record_consent_status_change();
+
+-- To rollback this migration:
+-- DROP TRIGGER IF EXISTS consent_records_status_update ON consent_records;
+-- DROP TRIGGER IF EXISTS consent_records_status_insert ON consent_records;
+-- DROP FUNCTION IF EXISTS record_consent_status_change();
+-- DROP TABLE IF EXISTS consent_change_webhooks;
+-- DROP TABLE IF EXISTS consent_status_changes;
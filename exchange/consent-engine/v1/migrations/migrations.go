@@ -0,0 +1,16 @@
+// Package migrations holds the SQL migrations of the consent engine database
+package migrations
+
+import _ "embed"
+
+// ConsentAnalyticsViews adds the consent decision columns and creates the consent analytics views.
+// It is idempotent, so it is run after every auto-migration.
+//
+//go:embed create_consent_analytics_views.sql
+var ConsentAnalyticsViews string
+
+// ConsentStatusChanges creates the consent change feed table, the trigger that fills it and the
+// webhook cursor table. It is idempotent, so it is run after every auto-migration.
+//
+//go:embed create_consent_status_changes.sql
+var ConsentStatusChanges string
@@ -9,8 +9,9 @@ import (
 // ConsentRecord represents a consent record in the system
 // Business Rules:
 // - Only one record can exist with status 'pending' or 'approved' for a given (OwnerID, OwnerEmail, AppID) tuple
-// - Multiple records can exist with status 'revoked', 'expired', or 'rejected' for the same tuple
+// - Multiple records can exist with status 'revoked', 'expired', 'rejected' or 'cancelled' for the same tuple
 // - The most recently created record should have status 'pending' or 'approved' (if active)
+// - Only one record can exist with status 'pending' or 'approved' for a given Fingerprint
 type ConsentRecord struct {
 	// ConsentID is the unique identifier for the consent record
 	ConsentID uuid.UUID `gorm:"column:consent_id;type:uuid;primaryKey;default:gen_random_uuid()" json:"consent_id"`
@@ -25,7 +26,7 @@ type ConsentRecord struct {
 	AppID string `gorm:"column:app_id;type:varchar(255);not null;index:idx_consent_records_app_id;index:idx_consent_records_owner_app,composite:owner_app;index:idx_consent_active_unique,composite:active_unique,where:status IN ('pending', 'approved')" json:"app_id"`
 	// AppName is the name of the consumer application
 	AppName *string `gorm:"column:app_name;type:varchar(255);" json:"app_name,omitempty"`
-	// Status is the status of the consent record: pending, approved, rejected, expired, revoked
+	// Status is the status of the consent record: pending, approved, rejected, expired, revoked, cancelled
 	// Part of conditional unique constraint for active consents (pending/approved)
 	Status string `gorm:"column:status;type:varchar(50);not null;index:idx_consent_records_status;index:idx_consent_active_unique,composite:active_unique,where:status IN ('pending', 'approved')" json:"status"`
 	// Type is the type of consent mechanism "realtime" or "offline"
@@ -53,6 +54,15 @@ type ConsentRecord struct {
 	ConsentPortalURL string `gorm:"column:consent_portal_url;type:text;not null" json:"consent_portal_url"`
 	// UpdatedBy identifies who last updated the consent (audit field)
 	UpdatedBy *string `gorm:"column:updated_by;type:varchar(255)" json:"updated_by,omitempty"`
+	// DecidedAt is the timestamp when the owner approved or rejected the consent in the consent portal
+	// Unlike UpdatedAt, it does not change when the consent later expires or is revoked
+	DecidedAt *time.Time `gorm:"column:decided_at;type:timestamp with time zone" json:"decided_at,omitempty"`
+	// RejectionReason is the reason the owner gave for rejecting the consent, if any
+	RejectionReason *string `gorm:"column:rejection_reason;type:varchar(50)" json:"rejection_reason,omitempty"`
+	// Fingerprint identifies what the consent was requested for: the owner, the consumer application, the fields
+	// and the purposes. Nil for consents created before fingerprints were introduced
+	// Unique among active consents (pending/approved), so equivalent requests cannot create duplicates
+	Fingerprint *string `gorm:"column:fingerprint;type:varchar(64);uniqueIndex:idx_consent_active_fingerprint,where:status IN ('pending', 'approved')" json:"fingerprint,omitempty"`
 }
 
 // TableName specifies the table name for GORM
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsentStatusChange is a consent's creation or a change of its status. Changes are recorded by a trigger
+// on consent_records (see migrations/create_consent_status_changes.sql), so every write path is covered.
+// ID orders the changes and is the cursor of the consent change feed.
+type ConsentStatusChange struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	ConsentID uuid.UUID `gorm:"column:consent_id;type:uuid;not null" json:"consentId"`
+	AppID     string    `gorm:"column:app_id;type:varchar(255);not null" json:"appId"`
+	OwnerID   string    `gorm:"column:owner_id;type:varchar(255);not null" json:"ownerId"`
+	// PreviousStatus is nil when the consent was created
+	PreviousStatus *string `gorm:"column:previous_status;type:varchar(50)" json:"previousStatus,omitempty"`
+	Status         string  `gorm:"column:status;type:varchar(50);not null" json:"status"`
+	// GrantExpiresAt is set on approved consents, so that caches can expire them without another change
+	GrantExpiresAt *time.Time `gorm:"column:grant_expires_at;type:timestamp with time zone" json:"grantExpiresAt,omitempty"`
+	// ChangedBy identifies who made the change, when it was recorded with it
+	ChangedBy *string   `gorm:"column:changed_by;type:varchar(255)" json:"changedBy,omitempty"`
+	ChangedAt time.Time `gorm:"column:changed_at;type:timestamp with time zone;not null;default:CURRENT_TIMESTAMP" json:"changedAt"`
+}
+
+// TableName specifies the table name for GORM
+func (*ConsentStatusChange) TableName() string {
+	return "consent_status_changes"
+}
+
+// ConsentChangeWebhook is an endpoint consent status changes are pushed to, with the cursor up to
+// which they were delivered
+type ConsentChangeWebhook struct {
+	URL         string     `gorm:"column:url;type:text;primaryKey"`
+	Cursor      int64      `gorm:"column:cursor;not null;default:0"`
+	DeliveredAt *time.Time `gorm:"column:delivered_at;type:timestamp with time zone"`
+	LastError   *string    `gorm:"column:last_error;type:text"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;type:timestamp with time zone;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for GORM
+func (*ConsentChangeWebhook) TableName() string {
+	return "consent_change_webhooks"
+}
@@ -1,6 +1,9 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // ConsentStatus represents the status of a consent record
 type ConsentStatus string
@@ -12,6 +15,8 @@ const (
 	StatusRejected ConsentStatus = "rejected"
 	StatusExpired  ConsentStatus = "expired"
 	StatusRevoked  ConsentStatus = "revoked"
+	// StatusCancelled is a pending consent withdrawn by the consumer application that requested it
+	StatusCancelled ConsentStatus = "cancelled"
 )
 
 // ConsentType represents the type of consent mechanism
@@ -32,6 +37,28 @@ const (
 	ActionReject  ConsentPortalAction = "reject"
 )
 
+// RejectionReason is the reason an owner gives for rejecting a consent in the consent portal
+type RejectionReason string
+
+// RejectionReason constants
+const (
+	ReasonUnknownApplication RejectionReason = "unknown_application"
+	ReasonUnnecessaryData    RejectionReason = "unnecessary_data"
+	ReasonPrivacyConcern     RejectionReason = "privacy_concern"
+	ReasonOther              RejectionReason = "other"
+	// ReasonUnspecified counts rejections without a reason in consent analytics; owners cannot give it
+	ReasonUnspecified RejectionReason = "unspecified"
+)
+
+// IsValid checks if the reason can be given by an owner
+func (r RejectionReason) IsValid() bool {
+	switch r {
+	case ReasonUnknownApplication, ReasonUnnecessaryData, ReasonPrivacyConcern, ReasonOther:
+		return true
+	}
+	return false
+}
+
 // GrantDuration represents the duration for which consent is granted
 type GrantDuration string
 
@@ -46,6 +73,17 @@ const (
 	DurationDefault     GrantDuration = DurationOneHour // default duration
 )
 
+// DefaultConsentLinkTTL is how long a consent link token can be redeemed when no TTL is configured.
+// Links are shown as QR codes in person, so they only need to outlive the scan.
+const DefaultConsentLinkTTL = 5 * time.Minute
+
+// DefaultPortalSessionTTL is how long a consent portal session token is valid when no TTL is configured.
+// Sessions only need to last while the citizen reviews and acts on one consent.
+const DefaultPortalSessionTTL = 15 * time.Minute
+
+// PortalSessionTokenPrefix marks consent portal session tokens, telling them apart from IDP tokens
+const PortalSessionTokenPrefix = "pst_"
+
 // DefaultPendingTimeoutDuration represents the default duration for pending status expiry
 // based on consent type. Pending consents will expire after this duration if not approved or rejected
 // Format: ISO 8601 duration (e.g., "P1D" for 1 day, "PT24H" for 24 hours)
@@ -71,6 +109,24 @@ var (
 	ErrConsentGetFailed    = errors.New("failed to get consent records")
 	ErrConsentExpiryFailed = errors.New("failed to check consent expiry")
 	ErrPortalRequestFailed = errors.New("failed to process consent portal request")
+
+	ErrConsentLinksDisabled = errors.New("consent links are not enabled")
+	ErrConsentLinkInvalid   = errors.New("invalid consent link token")
+	ErrConsentLinkExpired   = errors.New("consent link token has expired")
+	ErrConsentOwnerMismatch = errors.New("consent belongs to a different owner")
+	ErrConsentNotPending    = errors.New("consent is not pending")
+	ErrConsentAppMismatch   = errors.New("consent was requested by a different application")
+	ErrConsentCancelFailed  = errors.New("failed to cancel consent record")
+
+	ErrPortalSessionsDisabled = errors.New("portal sessions are not enabled")
+	ErrPortalSessionInvalid   = errors.New("invalid portal session token")
+	ErrPortalSessionExpired   = errors.New("portal session token has expired")
+	ErrPortalSessionScope     = errors.New("portal session token is scoped to a different consent")
+
+	ErrAnalyticsFailed = errors.New("failed to compute consent analytics")
+
+	ErrInvalidConsentCursor = errors.New("invalid consent change cursor")
+	ErrConsentChangesFailed = errors.New("failed to list consent changes")
 )
 
 // ConsentErrorCode represents an error code
@@ -84,6 +140,13 @@ const (
 	ErrorCodeUnauthorized     ConsentErrorCode = "UNAUTHORIZED"
 	ErrorCodeForbidden        ConsentErrorCode = "FORBIDDEN"
 	ErrorCodeMethodNotAllowed ConsentErrorCode = "METHOD_NOT_ALLOWED"
+
+	ErrorCodeConsentLinksDisabled ConsentErrorCode = "CONSENT_LINKS_DISABLED"
+	ErrorCodeInvalidConsentLink   ConsentErrorCode = "INVALID_CONSENT_LINK"
+	ErrorCodeConsentLinkExpired   ConsentErrorCode = "CONSENT_LINK_EXPIRED"
+	ErrorCodeConsentNotPending    ConsentErrorCode = "CONSENT_NOT_PENDING"
+
+	ErrorCodePortalSessionsDisabled ConsentErrorCode = "PORTAL_SESSIONS_DISABLED"
 )
 
 // ConsentEngineOperation represents the operation
@@ -99,6 +162,12 @@ const (
 	OpGetConsentsByConsumer ConsentEngineOperation = "get consents by consumer"
 	OpCheckConsentExpiry    ConsentEngineOperation = "check consent expiry"
 	OpProcessPortalRequest  ConsentEngineOperation = "process consent portal"
+	OpMintConsentLink       ConsentEngineOperation = "mint consent link"
+	OpRedeemConsentLink     ConsentEngineOperation = "redeem consent link"
+	OpCancelConsent         ConsentEngineOperation = "cancel consent"
+	OpCreatePortalSession   ConsentEngineOperation = "create portal session"
+	OpGetConsentAnalytics   ConsentEngineOperation = "get consent analytics"
+	OpGetConsentChanges     ConsentEngineOperation = "get consent changes"
 )
 
 // UpdateByMessage represents who updated the consent with specific message
@@ -107,4 +176,7 @@ type UpdateByMessage string
 // UpdateByMessage constants
 const (
 	RevokedByNewConsentWithDifferentFields UpdateByMessage = "System: revoked due to new consent with different fields"
+	RevokedByConsentReuseWindowElapsed     UpdateByMessage = "System: revoked due to new consent after the reuse window elapsed"
+	// CancelledByConsumer is formatted with the ID of the application that withdrew the request
+	CancelledByConsumer UpdateByMessage = "Consumer: cancelled by application %s"
 )
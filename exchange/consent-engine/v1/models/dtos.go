@@ -39,6 +39,8 @@ type CreateConsentRequest struct {
 	ConsentRequirement ConsentRequirement `json:"consentRequirement"`
 	GrantDuration      *string            `json:"grantDuration,omitempty"`
 	ConsentType        *ConsentType       `json:"consentType,omitempty"`
+	// Purposes are the purposes the consumer requests the data for, if known
+	Purposes []string `json:"purposes,omitempty"`
 }
 
 // ConsentPortalActionRequest defines the structure for consent portal interactions
@@ -46,6 +48,43 @@ type ConsentPortalActionRequest struct {
 	ConsentID string              `json:"consentId"`
 	Action    ConsentPortalAction `json:"action"` // "approve" or "reject"
 	UpdatedBy string              `json:"updatedBy"`
+	// Reason is the optional reason for a rejection
+	Reason *RejectionReason `json:"reason,omitempty"`
+}
+
+// CreateConsentLinkRequest defines the structure for minting a consent link token. OwnerID is the
+// citizen's NIC; the token can only be redeemed by presenting the same NIC.
+type CreateConsentLinkRequest struct {
+	OwnerID string `json:"ownerId"`
+}
+
+// ConsentLinkResponse is a minted consent link. DeepLink opens the consent portal with the token and
+// is what a QR code should encode.
+type ConsentLinkResponse struct {
+	ConsentID string    `json:"consentId"`
+	Token     string    `json:"token"`
+	DeepLink  string    `json:"deepLink"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// RedeemConsentLinkRequest defines the structure for redeeming a consent link token
+type RedeemConsentLinkRequest struct {
+	Token   string `json:"token"`
+	OwnerID string `json:"ownerId"`
+}
+
+// ConsentLinkRedemption is the consent a redeemed link token refers to
+type ConsentLinkRedemption struct {
+	ConsentID string                    `json:"consentId"`
+	Consent   ConsentResponsePortalView `json:"consent"`
+}
+
+// PortalSessionResponse is a consent portal session. Token authorizes reading and acting on the one
+// consent until ExpiresAt, in place of the citizen's IDP token.
+type PortalSessionResponse struct {
+	ConsentID string    `json:"consentId"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 // ConsentResponseInternalView represents a simplified consent response structure for Internal API Responses
@@ -107,3 +146,74 @@ func (cr *ConsentRecord) ToConsentResponsePortalView() ConsentResponsePortalView
 		Fields:     cr.Fields, // Now includes DisplayName, Description, and Owner for rich UI rendering
 	}
 }
+
+// AnalyticsTimeRange is the time range consent analytics were computed for
+type AnalyticsTimeRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// ConsumerApprovalRate counts the outcomes of the consents one consumer application requested.
+// ApprovalRate is the share of approved consents among those that were approved, rejected or timed out;
+// it is omitted while none were.
+type ConsumerApprovalRate struct {
+	AppID        string   `json:"appId"`
+	AppName      *string  `json:"appName,omitempty"`
+	Requested    int64    `json:"requested"`
+	Approved     int64    `json:"approved"`
+	Rejected     int64    `json:"rejected"`
+	TimedOut     int64    `json:"timedOut"`
+	Withdrawn    int64    `json:"withdrawn"`
+	Pending      int64    `json:"pending"`
+	ApprovalRate *float64 `json:"approvalRate,omitempty"`
+}
+
+// ApprovalRatesResponse lists the approval rate of each consumer for the consents requested in the range
+type ApprovalRatesResponse struct {
+	AnalyticsTimeRange
+	Consumers []ConsumerApprovalRate `json:"consumers"`
+}
+
+// TimeToConsentResponse is how long owners took to approve or reject the consents requested in the
+// range. The medians are omitted when there were no such decisions.
+type TimeToConsentResponse struct {
+	AnalyticsTimeRange
+	Decisions              int64    `json:"decisions"`
+	MedianSeconds          *float64 `json:"medianSeconds,omitempty"`
+	ApprovalMedianSeconds  *float64 `json:"approvalMedianSeconds,omitempty"`
+	RejectionMedianSeconds *float64 `json:"rejectionMedianSeconds,omitempty"`
+}
+
+// GrantRenewalsResponse compares approved grants that expired in the range with those the consumer
+// renewed by obtaining a newer approved consent from the same owner. The rates are omitted when no
+// grants expired.
+type GrantRenewalsResponse struct {
+	AnalyticsTimeRange
+	Expired     int64    `json:"expired"`
+	Renewed     int64    `json:"renewed"`
+	NotRenewed  int64    `json:"notRenewed"`
+	RenewalRate *float64 `json:"renewalRate,omitempty"`
+	ExpiryRate  *float64 `json:"expiryRate,omitempty"`
+}
+
+// DenialReasonCount is how many rejections gave one reason, and their share of all rejections
+type DenialReasonCount struct {
+	Reason RejectionReason `json:"reason"`
+	Count  int64           `json:"count"`
+	Share  float64         `json:"share"`
+}
+
+// DenialReasonsResponse is the distribution of the reasons for rejecting the consents requested in the range
+type DenialReasonsResponse struct {
+	AnalyticsTimeRange
+	Rejections int64               `json:"rejections"`
+	Reasons    []DenialReasonCount `json:"reasons"`
+}
+
+// ConsentChangesResponse lists the consent status changes after a cursor, oldest first. NextCursor is
+// passed as since to get the changes after them; HasMore is set when more changes are already available.
+type ConsentChangesResponse struct {
+	Changes    []ConsentStatusChange `json:"changes"`
+	NextCursor string                `json:"nextCursor"`
+	HasMore    bool                  `json:"hasMore"`
+}
@@ -12,10 +12,11 @@ import (
 
 // V1Router handles all V1 API route registration
 type V1Router struct {
-	internalHandler *handlers.InternalHandler
-	portalHandler   *handlers.PortalHandler
-	authMiddleware  *middleware.JWTAuthMiddleware
-	corsMiddleware  func(http.Handler) http.Handler
+	internalHandler  *handlers.InternalHandler
+	portalHandler    *handlers.PortalHandler
+	analyticsHandler *handlers.AnalyticsHandler
+	authMiddleware   *middleware.JWTAuthMiddleware
+	corsMiddleware   func(http.Handler) http.Handler
 }
 
 // NewV1Router creates a new V1 router with all dependencies
@@ -23,13 +24,15 @@ func NewV1Router(
 	allowedOrigins string,
 	internalHandler *handlers.InternalHandler,
 	portalHandler *handlers.PortalHandler,
+	analyticsHandler *handlers.AnalyticsHandler,
 	jwtVerifier *auth.JWTVerifier,
 ) *V1Router {
 	return &V1Router{
-		internalHandler: internalHandler,
-		portalHandler:   portalHandler,
-		authMiddleware:  middleware.NewJWTAuthMiddleware(jwtVerifier),
-		corsMiddleware:  middleware.NewCORSMiddleware(allowedOrigins),
+		internalHandler:  internalHandler,
+		portalHandler:    portalHandler,
+		analyticsHandler: analyticsHandler,
+		authMiddleware:   middleware.NewJWTAuthMiddleware(jwtVerifier),
+		corsMiddleware:   middleware.NewCORSMiddleware(allowedOrigins),
 	}
 }
 
@@ -50,6 +53,22 @@ func (r *V1Router) registerInternalRoutes(mux *http.ServeMux) {
 		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.internalHandler.GetConsent)))
 	mux.Handle("POST /internal/api/v1/consents",
 		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.internalHandler.CreateConsent)))
+	mux.Handle("GET /internal/api/v1/consents/changes",
+		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.internalHandler.GetConsentChanges)))
+	mux.Handle("DELETE /internal/api/v1/consents/{consentId}",
+		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.internalHandler.CancelConsent)))
+	mux.Handle("POST /internal/api/v1/consents/{consentId}/links",
+		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.internalHandler.CreateConsentLink)))
+
+	// Consent analytics endpoints, for governance reporting in the admin portal
+	mux.Handle("GET /internal/api/v1/analytics/approval-rates",
+		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.analyticsHandler.GetApprovalRates)))
+	mux.Handle("GET /internal/api/v1/analytics/time-to-consent",
+		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.analyticsHandler.GetTimeToConsent)))
+	mux.Handle("GET /internal/api/v1/analytics/grant-renewals",
+		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.analyticsHandler.GetGrantRenewals)))
+	mux.Handle("GET /internal/api/v1/analytics/denial-reasons",
+		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.analyticsHandler.GetDenialReasons)))
 }
 
 // registerPortalRoutes registers portal API routes (authentication required for protected endpoints)
@@ -59,12 +78,24 @@ func (r *V1Router) registerPortalRoutes(mux *http.ServeMux) {
 		sharedUtils.PanicRecoveryMiddleware(http.HandlerFunc(r.portalHandler.HealthCheck)))
 
 	// Consent endpoints (authentication required)
+	// (a portal session token scoped to the consent is accepted in place of the IDP token)
 	mux.Handle("GET /api/v1/consents/{consentId}",
 		sharedUtils.PanicRecoveryMiddleware(
-			r.authMiddleware.Authenticate(http.HandlerFunc(r.portalHandler.GetConsent))))
+			r.authMiddleware.AuthenticateConsent(http.HandlerFunc(r.portalHandler.GetConsent))))
 	mux.Handle("PUT /api/v1/consents/{consentId}",
 		sharedUtils.PanicRecoveryMiddleware(
-			r.authMiddleware.Authenticate(http.HandlerFunc(r.portalHandler.UpdateConsent))))
+			r.authMiddleware.AuthenticateConsent(http.HandlerFunc(r.portalHandler.UpdateConsent))))
+	mux.Handle("POST /api/v1/consents/{consentId}/portal-session",
+		sharedUtils.PanicRecoveryMiddleware(
+			r.authMiddleware.Authenticate(http.HandlerFunc(r.portalHandler.CreatePortalSession))))
+	mux.Handle("POST /api/v1/consent-links/redeem",
+		sharedUtils.PanicRecoveryMiddleware(
+			r.authMiddleware.Authenticate(http.HandlerFunc(r.portalHandler.RedeemConsentLink))))
+}
+
+// AcceptPortalSessions accepts portal session tokens verified by sessions on the consent endpoints
+func (r *V1Router) AcceptPortalSessions(sessions middleware.PortalSessionVerifier) {
+	r.authMiddleware.AcceptPortalSessions(sessions)
 }
 
 // ApplyCORS wraps a handler with CORS middleware
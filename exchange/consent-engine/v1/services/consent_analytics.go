@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"gorm.io/gorm"
+)
+
+// AnalyticsFilter selects the consents that consent analytics are computed over. Consents are selected
+// by when they were requested, grant expiries by when the grant expired. AppID, when set, limits them to
+// one consumer application.
+type AnalyticsFilter struct {
+	models.AnalyticsTimeRange
+	AppID string
+}
+
+// ConsentAnalyticsService computes aggregate consent statistics for governance reporting from the
+// consent_outcomes and consent_grant_expiries views
+type ConsentAnalyticsService struct {
+	db *gorm.DB
+}
+
+// NewConsentAnalyticsService creates a new consent analytics service
+func NewConsentAnalyticsService(db *gorm.DB) *ConsentAnalyticsService {
+	return &ConsentAnalyticsService{db: db}
+}
+
+// ApprovalRates counts the outcomes of the consents each consumer requested in the range
+func (s *ConsentAnalyticsService) ApprovalRates(ctx context.Context, filter AnalyticsFilter) (*models.ApprovalRatesResponse, error) {
+	consumers := []models.ConsumerApprovalRate{}
+	err := s.outcomes(ctx, filter).
+		Select(`app_id, MAX(app_name) AS app_name, COUNT(*) AS requested,
+			COUNT(*) FILTER (WHERE outcome = 'approved') AS approved,
+			COUNT(*) FILTER (WHERE outcome = 'rejected') AS rejected,
+			COUNT(*) FILTER (WHERE outcome = 'timed_out') AS timed_out,
+			COUNT(*) FILTER (WHERE outcome = 'withdrawn') AS withdrawn,
+			COUNT(*) FILTER (WHERE outcome = 'pending') AS pending`).
+		Group("app_id").
+		Order("app_id").
+		Scan(&consumers).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrAnalyticsFailed, err)
+	}
+
+	for i := range consumers {
+		consumer := &consumers[i]
+		consumer.ApprovalRate = rate(consumer.Approved, consumer.Approved+consumer.Rejected+consumer.TimedOut)
+	}
+	return &models.ApprovalRatesResponse{AnalyticsTimeRange: filter.AnalyticsTimeRange, Consumers: consumers}, nil
+}
+
+// TimeToConsent computes the median time owners took to decide on the consents requested in the range
+func (s *ConsentAnalyticsService) TimeToConsent(ctx context.Context, filter AnalyticsFilter) (*models.TimeToConsentResponse, error) {
+	response := &models.TimeToConsentResponse{AnalyticsTimeRange: filter.AnalyticsTimeRange}
+	err := s.outcomes(ctx, filter).
+		Select(`COUNT(*) AS decisions,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds_to_decision) AS median_seconds,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds_to_decision) FILTER (WHERE outcome = 'approved') AS approval_median_seconds,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY seconds_to_decision) FILTER (WHERE outcome = 'rejected') AS rejection_median_seconds`).
+		Where("decided_at IS NOT NULL").
+		Scan(response).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrAnalyticsFailed, err)
+	}
+	return response, nil
+}
+
+// GrantRenewals compares the approved grants that expired in the range with those that were renewed
+func (s *ConsentAnalyticsService) GrantRenewals(ctx context.Context, filter AnalyticsFilter) (*models.GrantRenewalsResponse, error) {
+	query := s.db.WithContext(ctx).Table("consent_grant_expiries").
+		Where("grant_expires_at >= ? AND grant_expires_at < ?", filter.From, filter.To)
+	if filter.AppID != "" {
+		query = query.Where("app_id = ?", filter.AppID)
+	}
+
+	response := &models.GrantRenewalsResponse{AnalyticsTimeRange: filter.AnalyticsTimeRange}
+	err := query.
+		Select("COUNT(*) AS expired, COUNT(*) FILTER (WHERE renewed) AS renewed").
+		Scan(response).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrAnalyticsFailed, err)
+	}
+
+	response.NotRenewed = response.Expired - response.Renewed
+	response.RenewalRate = rate(response.Renewed, response.Expired)
+	response.ExpiryRate = rate(response.NotRenewed, response.Expired)
+	return response, nil
+}
+
+// DenialReasons computes the distribution of the reasons given for rejecting the consents requested in
+// the range; rejections without a reason are counted as unspecified
+func (s *ConsentAnalyticsService) DenialReasons(ctx context.Context, filter AnalyticsFilter) (*models.DenialReasonsResponse, error) {
+	reasons := []models.DenialReasonCount{}
+	err := s.outcomes(ctx, filter).
+		Select("COALESCE(rejection_reason, ?) AS reason, COUNT(*) AS count", string(models.ReasonUnspecified)).
+		Where("outcome = 'rejected'").
+		Group("reason").
+		Order("count DESC, reason").
+		Scan(&reasons).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrAnalyticsFailed, err)
+	}
+
+	response := &models.DenialReasonsResponse{AnalyticsTimeRange: filter.AnalyticsTimeRange, Reasons: reasons}
+	for _, reason := range reasons {
+		response.Rejections += reason.Count
+	}
+	for i := range reasons {
+		reasons[i].Share = float64(reasons[i].Count) / float64(response.Rejections)
+	}
+	return response, nil
+}
+
+// outcomes selects the consent outcomes requested in the range
+func (s *ConsentAnalyticsService) outcomes(ctx context.Context, filter AnalyticsFilter) *gorm.DB {
+	query := s.db.WithContext(ctx).Table("consent_outcomes").
+		Where("created_at >= ? AND created_at < ?", filter.From, filter.To)
+	if filter.AppID != "" {
+		query = query.Where("app_id = ?", filter.AppID)
+	}
+	return query
+}
+
+// rate returns count as a share of total, or nil when total is zero
+func rate(count, total int64) *float64 {
+	if total == 0 {
+		return nil
+	}
+	value := float64(count) / float64(total)
+	return &value
+}
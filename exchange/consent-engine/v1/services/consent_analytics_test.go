@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyticsFilter(appID string) AnalyticsFilter {
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	return AnalyticsFilter{
+		AnalyticsTimeRange: models.AnalyticsTimeRange{From: to.AddDate(0, -1, 0), To: to},
+		AppID:              appID,
+	}
+}
+
+func TestConsentAnalytics_ApprovalRates(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service := NewConsentAnalyticsService(db)
+	filter := analyticsFilter("")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "consent_outcomes" WHERE created_at >= $1 AND created_at < $2 GROUP BY "app_id" ORDER BY app_id`)).
+		WithArgs(filter.From, filter.To).
+		WillReturnRows(sqlmock.NewRows([]string{"app_id", "app_name", "requested", "approved", "rejected", "timed_out", "withdrawn", "pending"}).
+			AddRow("app-1", "App One", 10, 6, 2, 0, 1, 1).
+			AddRow("app-2", nil, 2, 0, 0, 0, 0, 2))
+
+	response, err := service.ApprovalRates(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, filter.AnalyticsTimeRange, response.AnalyticsTimeRange)
+	require.Len(t, response.Consumers, 2)
+
+	assert.Equal(t, "app-1", response.Consumers[0].AppID)
+	assert.Equal(t, "App One", *response.Consumers[0].AppName)
+	assert.Equal(t, int64(10), response.Consumers[0].Requested)
+	require.NotNil(t, response.Consumers[0].ApprovalRate)
+	assert.InDelta(t, 0.75, *response.Consumers[0].ApprovalRate, 1e-9, "withdrawn and pending consents are not decided")
+
+	assert.Nil(t, response.Consumers[1].ApprovalRate, "no rate without decided consents")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConsentAnalytics_TimeToConsent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service := NewConsentAnalyticsService(db)
+	filter := analyticsFilter("app-1")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "consent_outcomes" WHERE (created_at >= $1 AND created_at < $2) AND app_id = $3 AND decided_at IS NOT NULL`)).
+		WithArgs(filter.From, filter.To, "app-1").
+		WillReturnRows(sqlmock.NewRows([]string{"decisions", "median_seconds", "approval_median_seconds", "rejection_median_seconds"}).
+			AddRow(5, 120.0, 90.5, nil))
+
+	response, err := service.TimeToConsent(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), response.Decisions)
+	assert.Equal(t, 120.0, *response.MedianSeconds)
+	assert.Equal(t, 90.5, *response.ApprovalMedianSeconds)
+	assert.Nil(t, response.RejectionMedianSeconds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConsentAnalytics_GrantRenewals(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service := NewConsentAnalyticsService(db)
+	filter := analyticsFilter("")
+
+	t.Run("Rates of expired grants", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM "consent_grant_expiries" WHERE grant_expires_at >= $1 AND grant_expires_at < $2`)).
+			WithArgs(filter.From, filter.To).
+			WillReturnRows(sqlmock.NewRows([]string{"expired", "renewed"}).AddRow(8, 2))
+
+		response, err := service.GrantRenewals(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), response.Expired)
+		assert.Equal(t, int64(2), response.Renewed)
+		assert.Equal(t, int64(6), response.NotRenewed)
+		assert.InDelta(t, 0.25, *response.RenewalRate, 1e-9)
+		assert.InDelta(t, 0.75, *response.ExpiryRate, 1e-9)
+	})
+
+	t.Run("No rates without expired grants", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM "consent_grant_expiries"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"expired", "renewed"}).AddRow(0, 0))
+
+		response, err := service.GrantRenewals(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Nil(t, response.RenewalRate)
+		assert.Nil(t, response.ExpiryRate)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConsentAnalytics_DenialReasons(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service := NewConsentAnalyticsService(db)
+	filter := analyticsFilter("")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(rejection_reason, $1) AS reason, COUNT(*) AS count FROM "consent_outcomes" WHERE (created_at >= $2 AND created_at < $3) AND outcome = 'rejected' GROUP BY "reason"`)).
+		WithArgs("unspecified", filter.From, filter.To).
+		WillReturnRows(sqlmock.NewRows([]string{"reason", "count"}).
+			AddRow("privacy_concern", 3).
+			AddRow("unspecified", 1))
+
+	response, err := service.DenialReasons(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), response.Rejections)
+	require.Len(t, response.Reasons, 2)
+	assert.Equal(t, models.ReasonPrivacyConcern, response.Reasons[0].Reason)
+	assert.InDelta(t, 0.75, response.Reasons[0].Share, 1e-9)
+	assert.Equal(t, models.ReasonUnspecified, response.Reasons[1].Reason)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConsentAnalytics_QueryFailure(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service := NewConsentAnalyticsService(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "consent_outcomes"`)).WillReturnError(assert.AnError)
+
+	_, err := service.ApprovalRates(context.Background(), analyticsFilter(""))
+	assert.ErrorIs(t, err, models.ErrAnalyticsFailed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
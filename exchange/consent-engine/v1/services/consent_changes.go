@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+)
+
+const (
+	// DefaultConsentChangesLimit is how many changes are returned when no limit is given
+	DefaultConsentChangesLimit = 100
+	// MaxConsentChangesLimit caps the changes returned at once
+	MaxConsentChangesLimit = 1000
+)
+
+// ParseConsentCursor parses the since cursor of the consent change feed; an empty cursor starts at the first change
+func ParseConsentCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("%w: %q", models.ErrInvalidConsentCursor, cursor)
+	}
+	return id, nil
+}
+
+// ListConsentChanges returns up to limit consent status changes after the since cursor, oldest first,
+// optionally only those of one application. Consumers keep the returned cursor to resume the feed.
+func (s *ConsentService) ListConsentChanges(ctx context.Context, since int64, appID string, limit int) (*models.ConsentChangesResponse, error) {
+	if limit <= 0 {
+		limit = DefaultConsentChangesLimit
+	}
+	if limit > MaxConsentChangesLimit {
+		limit = MaxConsentChangesLimit
+	}
+
+	changes, err := s.consentChangesAfter(ctx, since, appID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrConsentChangesFailed, err)
+	}
+	response := &models.ConsentChangesResponse{
+		Changes:    changes,
+		NextCursor: strconv.FormatInt(since, 10),
+	}
+	if len(changes) > limit {
+		response.Changes = changes[:limit]
+		response.HasMore = true
+	}
+	if n := len(response.Changes); n > 0 {
+		response.NextCursor = strconv.FormatInt(response.Changes[n-1].ID, 10)
+	}
+	return response, nil
+}
+
+func (s *ConsentService) consentChangesAfter(ctx context.Context, since int64, appID string, limit int) ([]models.ConsentStatusChange, error) {
+	changes := []models.ConsentStatusChange{}
+	query := s.db.WithContext(ctx).Where("id > ?", since)
+	if appID != "" {
+		query = query.Where("app_id = ?", appID)
+	}
+	if err := query.Order("id").Limit(limit).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
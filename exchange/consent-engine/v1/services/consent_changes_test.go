@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var consentChangeColumns = []string{"id", "consent_id", "app_id", "owner_id", "previous_status", "status", "grant_expires_at", "changed_by", "changed_at"}
+
+func TestParseConsentCursor(t *testing.T) {
+	cursor, err := ParseConsentCursor("")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cursor)
+
+	cursor, err = ParseConsentCursor("42")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), cursor)
+
+	for _, invalid := range []string{"abc", "-1", "1.5"} {
+		_, err := ParseConsentCursor(invalid)
+		assert.ErrorIs(t, err, models.ErrInvalidConsentCursor, invalid)
+	}
+}
+
+func TestListConsentChanges(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	consentID := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_status_changes" WHERE id > $1 AND app_id = $2 ORDER BY id LIMIT $3`)).
+		WithArgs(int64(10), "app-1", 3).
+		WillReturnRows(sqlmock.NewRows(consentChangeColumns).
+			AddRow(11, consentID, "app-1", "owner-1", nil, "pending", nil, nil, now).
+			AddRow(12, consentID, "app-1", "owner-1", "pending", "approved", now.Add(time.Hour), "owner@example.com", now).
+			AddRow(14, consentID, "app-1", "owner-1", "approved", "revoked", now.Add(time.Hour), "owner@example.com", now))
+
+	response, err := service.ListConsentChanges(context.Background(), 10, "app-1", 2)
+	require.NoError(t, err)
+	require.Len(t, response.Changes, 2)
+	assert.Nil(t, response.Changes[0].PreviousStatus)
+	assert.Equal(t, "approved", response.Changes[1].Status)
+	assert.Equal(t, "pending", *response.Changes[1].PreviousStatus)
+	assert.Equal(t, "12", response.NextCursor)
+	assert.True(t, response.HasMore)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListConsentChanges_NoChanges(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_status_changes" WHERE id > $1 ORDER BY id LIMIT $2`)).
+		WithArgs(int64(7), DefaultConsentChangesLimit+1).
+		WillReturnRows(sqlmock.NewRows(consentChangeColumns))
+
+	response, err := service.ListConsentChanges(context.Background(), 7, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, response.Changes)
+	assert.Equal(t, "7", response.NextCursor, "the cursor stays put until there are changes")
+	assert.False(t, response.HasMore)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewConsentWebhookDispatcher_Validation(t *testing.T) {
+	db, _ := setupMockDB(t)
+
+	_, err := NewConsentWebhookDispatcher(db, []string{"https://oe.example.com/consent-changes"}, "")
+	assert.Error(t, err, "a secret is required")
+	_, err = NewConsentWebhookDispatcher(db, []string{"ftp://oe.example.com"}, "secret")
+	assert.Error(t, err)
+	_, err = NewConsentWebhookDispatcher(db, []string{"https://oe.example.com/consent-changes"}, "secret")
+	assert.NoError(t, err)
+}
+
+func TestConsentWebhookDispatcher_DeliverPending(t *testing.T) {
+	var received models.ConsentChangesResponse
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(ConsentWebhookTimestampHeader)
+		assert.Equal(t, "sha256="+SignConsentWebhookPayload("secret", timestamp, body), r.Header.Get(ConsentWebhookSignatureHeader))
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	db, mock := setupMockDB(t)
+	dispatcher, err := NewConsentWebhookDispatcher(db, []string{server.URL}, "secret")
+	require.NoError(t, err)
+	consentID := uuid.New()
+
+	expectBatch := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_change_webhooks" WHERE url = $1 LIMIT $2 FOR UPDATE SKIP LOCKED`)).
+			WithArgs(server.URL, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"url", "cursor", "delivered_at", "last_error", "updated_at"}).
+				AddRow(server.URL, 4, nil, nil, time.Now()))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_status_changes" WHERE id > $1 ORDER BY id LIMIT $2`)).
+			WithArgs(int64(4), consentWebhookBatchSize).
+			WillReturnRows(sqlmock.NewRows(consentChangeColumns).
+				AddRow(5, consentID, "app-1", "owner-1", "pending", "cancelled", nil, "Consumer: cancelled by application app-1", time.Now()))
+	}
+
+	// A failed delivery records the error and keeps the cursor
+	status = http.StatusServiceUnavailable
+	expectBatch()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_change_webhooks" SET "last_error"=$1,"updated_at"=$2 WHERE "url" = $3`)).
+		WithArgs("webhook endpoint responded with status 503", sqlmock.AnyArg(), server.URL).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	dispatcher.DeliverPending(context.Background())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// The batch is sent again and the cursor advances once it is accepted
+	status = http.StatusOK
+	expectBatch()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_change_webhooks" SET "cursor"=$1,"delivered_at"=$2,"last_error"=$3,"updated_at"=$4 WHERE "url" = $5`)).
+		WithArgs(int64(5), sqlmock.AnyArg(), nil, sqlmock.AnyArg(), server.URL).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	dispatcher.DeliverPending(context.Background())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, received.Changes, 1)
+	assert.Equal(t, "cancelled", received.Changes[0].Status)
+	assert.Equal(t, "5", received.NextCursor)
+	assert.False(t, received.HasMore)
+}
+
+func TestConsentWebhookDispatcher_SkipsLockedWebhook(t *testing.T) {
+	db, mock := setupMockDB(t)
+	dispatcher, err := NewConsentWebhookDispatcher(db, []string{"http://oe.example.com/consent-changes"}, "secret")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE SKIP LOCKED`)).
+		WillReturnRows(sqlmock.NewRows([]string{"url", "cursor"}))
+	mock.ExpectCommit()
+
+	dispatcher.DeliverPending(context.Background())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
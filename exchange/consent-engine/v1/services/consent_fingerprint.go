@@ -0,0 +1,59 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+)
+
+// fingerprintedConsent is what a consent fingerprint is computed over
+type fingerprintedConsent struct {
+	OwnerID    string   `json:"ownerId"`
+	OwnerEmail string   `json:"ownerEmail"`
+	AppID      string   `json:"appId"`
+	Fields     []string `json:"fields"`
+	Purposes   []string `json:"purposes"`
+}
+
+// consentFingerprint returns the SHA-256 (hex) of what a consent is requested for: the owner, the consumer
+// application, the fields and the purposes. The order and duplicates of fields and purposes, and the display
+// names and descriptions of fields, do not change it.
+func consentFingerprint(req models.CreateConsentRequest) string {
+	fields := make([]string, 0, len(req.ConsentRequirement.Fields))
+	for _, field := range req.ConsentRequirement.Fields {
+		fields = append(fields, field.SchemaID+"\x00"+field.FieldName+"\x00"+string(field.Owner))
+	}
+	slices.Sort(fields)
+
+	purposes := make([]string, 0, len(req.Purposes))
+	for _, purpose := range req.Purposes {
+		if purpose = strings.TrimSpace(purpose); purpose != "" {
+			purposes = append(purposes, purpose)
+		}
+	}
+	slices.Sort(purposes)
+
+	// Marshalling strings and string slices cannot fail
+	payload, _ := json.Marshal(fingerprintedConsent{
+		OwnerID:    req.ConsentRequirement.OwnerID,
+		OwnerEmail: req.ConsentRequirement.OwnerEmail,
+		AppID:      req.AppID,
+		Fields:     slices.Compact(fields),
+		Purposes:   slices.Compact(purposes),
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// isEquivalentConsent checks if the existing consent was requested for the same as the request. Consents created
+// before fingerprints were introduced are compared by their fields, and only to requests without purposes.
+func isEquivalentConsent(existing *models.ConsentRecord, fingerprint string, req models.CreateConsentRequest) bool {
+	if existing.Fingerprint != nil {
+		return *existing.Fingerprint == fingerprint
+	}
+	return len(req.Purposes) == 0 && areConsentFieldsEqual(&existing.Fields, &req.ConsentRequirement.Fields)
+}
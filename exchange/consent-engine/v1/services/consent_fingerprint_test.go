@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsentFingerprint(t *testing.T) {
+	description := "Primary email address"
+	req := models.CreateConsentRequest{
+		AppID: "app-1",
+		ConsentRequirement: models.ConsentRequirement{
+			OwnerID:    "user-1",
+			OwnerEmail: "user-1@example.com",
+			Fields: []models.ConsentField{
+				{FieldName: "email", SchemaID: "schema-1", Owner: "citizen"},
+				{FieldName: "address", SchemaID: "schema-2", Owner: "citizen"},
+			},
+		},
+		Purposes: []string{"tax-assessment", "benefit-eligibility"},
+	}
+	fingerprint := consentFingerprint(req)
+	assert.Len(t, fingerprint, 64)
+
+	t.Run("Ignores order, duplicates and display text", func(t *testing.T) {
+		same := req
+		same.ConsentRequirement.Fields = []models.ConsentField{
+			{FieldName: "address", SchemaID: "schema-2", Owner: "citizen"},
+			{FieldName: "email", SchemaID: "schema-1", Owner: "citizen", Description: &description},
+			{FieldName: "email", SchemaID: "schema-1", Owner: "citizen"},
+		}
+		same.Purposes = []string{" benefit-eligibility", "tax-assessment", "tax-assessment", ""}
+		assert.Equal(t, fingerprint, consentFingerprint(same))
+	})
+
+	t.Run("Changes with what is requested", func(t *testing.T) {
+		otherApp := req
+		otherApp.AppID = "app-2"
+		otherOwner := req
+		otherOwner.ConsentRequirement.OwnerID = "user-2"
+		otherFields := req
+		otherFields.ConsentRequirement.Fields = req.ConsentRequirement.Fields[:1]
+		otherPurposes := req
+		otherPurposes.Purposes = nil
+
+		for _, other := range []models.CreateConsentRequest{otherApp, otherOwner, otherFields, otherPurposes} {
+			assert.NotEqual(t, fingerprint, consentFingerprint(other))
+		}
+	})
+}
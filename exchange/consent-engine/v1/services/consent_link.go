@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"gorm.io/gorm"
+)
+
+// minConsentLinkSecretLength is the shortest accepted signing secret, the size of a SHA-256 key
+const minConsentLinkSecretLength = 32
+
+// ConsentLinkClaims is the payload of a consent link token. The owner's NIC is only carried as a
+// keyed hash, so it cannot be read from a QR code or recovered without the signing secret.
+type ConsentLinkClaims struct {
+	ConsentID string `json:"cid"`
+	OwnerHash string `json:"own"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// ConsentLinkSigner mints and verifies consent link tokens: short-lived HMAC-SHA256 signed tokens
+// referring to a pending consent, meant to be shown as QR codes or deep links in assisted channels
+type ConsentLinkSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewConsentLinkSigner creates a signer; non-positive TTLs use models.DefaultConsentLinkTTL
+func NewConsentLinkSigner(secret string, ttl time.Duration) (*ConsentLinkSigner, error) {
+	if len(secret) < minConsentLinkSecretLength {
+		return nil, fmt.Errorf("consent link secret must be at least %d bytes", minConsentLinkSecretLength)
+	}
+	if ttl <= 0 {
+		ttl = models.DefaultConsentLinkTTL
+	}
+	return &ConsentLinkSigner{secret: []byte(secret), ttl: ttl, now: time.Now}, nil
+}
+
+// Sign returns a token for the consent, bound to ownerID and expiring after the signer's TTL or at
+// notAfter, whichever comes first
+func (s *ConsentLinkSigner) Sign(consentID, ownerID string, notAfter *time.Time) (string, time.Time, error) {
+	issuedAt := s.now().UTC()
+	expiresAt := issuedAt.Add(s.ttl)
+	if notAfter != nil && notAfter.Before(expiresAt) {
+		expiresAt = notAfter.UTC()
+	}
+
+	payload, err := json.Marshal(ConsentLinkClaims{
+		ConsentID: consentID,
+		OwnerHash: s.ownerHash(ownerID),
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.signature(encoded), time.Unix(expiresAt.Unix(), 0).UTC(), nil
+}
+
+// Verify checks the token's signature and expiry and returns its claims
+func (s *ConsentLinkSigner) Verify(token string) (*ConsentLinkClaims, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, models.ErrConsentLinkInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, models.ErrConsentLinkInvalid
+	}
+	var claims ConsentLinkClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ConsentID == "" {
+		return nil, models.ErrConsentLinkInvalid
+	}
+
+	if !s.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, models.ErrConsentLinkExpired
+	}
+	return &claims, nil
+}
+
+// MatchesOwner reports whether the claims were signed for ownerID
+func (s *ConsentLinkSigner) MatchesOwner(claims *ConsentLinkClaims, ownerID string) bool {
+	return hmac.Equal([]byte(claims.OwnerHash), []byte(s.ownerHash(ownerID)))
+}
+
+func (s *ConsentLinkSigner) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("sig:" + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ownerHash is domain separated from signature, so a hash can never be used as a signature
+func (s *ConsentLinkSigner) ownerHash(ownerID string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("owner:" + strings.TrimSpace(ownerID)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// EnableConsentLinks lets the service mint and redeem consent link tokens with the signer
+func (s *ConsentService) EnableConsentLinks(signer *ConsentLinkSigner) {
+	s.linkSigner = signer
+}
+
+// CreateConsentLink mints a link token for a pending consent. ownerID must be the consent owner's NIC,
+// as the token can only be redeemed by presenting it again.
+func (s *ConsentService) CreateConsentLink(ctx context.Context, consentID string, req models.CreateConsentLinkRequest) (*models.ConsentLinkResponse, error) {
+	if s.linkSigner == nil {
+		return nil, models.ErrConsentLinksDisabled
+	}
+	if strings.TrimSpace(req.OwnerID) == "" {
+		return nil, fmt.Errorf("%w: ownerId is required", models.ErrConsentLinkInvalid)
+	}
+
+	consentRecord, err := s.getPendingConsent(ctx, consentID)
+	if err != nil {
+		return nil, err
+	}
+	if consentRecord.OwnerID != strings.TrimSpace(req.OwnerID) {
+		return nil, models.ErrConsentOwnerMismatch
+	}
+
+	token, expiresAt, err := s.linkSigner.Sign(consentRecord.ConsentID.String(), consentRecord.OwnerID, consentRecord.PendingExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrConsentGetFailed, err)
+	}
+
+	return &models.ConsentLinkResponse{
+		ConsentID: consentRecord.ConsentID.String(),
+		Token:     token,
+		DeepLink:  fmt.Sprintf("%s?consentToken=%s", s.consentPortalBaseURL, url.QueryEscape(token)),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// RedeemConsentLink verifies a link token presented with the citizen's NIC and returns the pending
+// consent it refers to, provided it belongs to the signed-in user
+func (s *ConsentService) RedeemConsentLink(ctx context.Context, req models.RedeemConsentLinkRequest, userEmail string) (*models.ConsentLinkRedemption, error) {
+	if s.linkSigner == nil {
+		return nil, models.ErrConsentLinksDisabled
+	}
+
+	claims, err := s.linkSigner.Verify(req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if !s.linkSigner.MatchesOwner(claims, req.OwnerID) {
+		return nil, models.ErrConsentOwnerMismatch
+	}
+
+	consentRecord, err := s.getPendingConsent(ctx, claims.ConsentID)
+	if err != nil {
+		return nil, err
+	}
+	if consentRecord.OwnerID != strings.TrimSpace(req.OwnerID) || consentRecord.OwnerEmail != userEmail {
+		return nil, models.ErrConsentOwnerMismatch
+	}
+
+	return &models.ConsentLinkRedemption{
+		ConsentID: consentRecord.ConsentID.String(),
+		Consent:   consentRecord.ToConsentResponsePortalView(),
+	}, nil
+}
+
+// getPendingConsent loads a consent that can still be approved or rejected
+func (s *ConsentService) getPendingConsent(ctx context.Context, consentID string) (*models.ConsentRecord, error) {
+	parsedConsentID, err := uuid.Parse(consentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid consent ID", models.ErrConsentGetFailed)
+	}
+
+	var consentRecord models.ConsentRecord
+	if err := s.db.WithContext(ctx).Where("consent_id = ?", parsedConsentID).First(&consentRecord).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", models.ErrConsentNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", models.ErrConsentGetFailed, err)
+	}
+
+	if consentRecord.Status != string(models.StatusPending) ||
+		(consentRecord.PendingExpiresAt != nil && !time.Now().Before(*consentRecord.PendingExpiresAt)) {
+		return nil, models.ErrConsentNotPending
+	}
+	return &consentRecord, nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLinkSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestLinkSigner(t *testing.T) *ConsentLinkSigner {
+	signer, err := NewConsentLinkSigner(testLinkSecret, time.Minute)
+	require.NoError(t, err)
+	return signer
+}
+
+// expectConsentLookup expects the consent to be loaded by ID and returns it with the given status
+func expectConsentLookup(mock sqlmock.Sqlmock, id uuid.UUID, status string, pendingExpiresAt *time.Time) {
+	rows := sqlmock.NewRows([]string{"consent_id", "owner_id", "owner_email", "app_id", "status", "type", "created_at", "updated_at", "pending_expires_at", "grant_duration", "fields"}).
+		AddRow(id, "199012345678", "user@example.com", "app-1", status, "realtime", time.Now(), time.Now(), pendingExpiresAt, "P30D", "[]")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE consent_id = $1`)+".*"+regexp.QuoteMeta(`LIMIT $2`)).
+		WithArgs(id, 1).
+		WillReturnRows(rows)
+}
+
+func TestNewConsentLinkSigner(t *testing.T) {
+	_, err := NewConsentLinkSigner("short", time.Minute)
+	assert.Error(t, err)
+
+	signer, err := NewConsentLinkSigner(testLinkSecret, 0)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultConsentLinkTTL, signer.ttl)
+}
+
+func TestConsentLinkSigner_SignAndVerify(t *testing.T) {
+	signer := newTestLinkSigner(t)
+	consentID := uuid.New().String()
+
+	token, expiresAt, err := signer.Sign(consentID, "199012345678", nil)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, 2*time.Second)
+	assert.NotContains(t, token, "199012345678")
+
+	claims, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, consentID, claims.ConsentID)
+	assert.True(t, signer.MatchesOwner(claims, "199012345678"))
+	assert.True(t, signer.MatchesOwner(claims, " 199012345678 "))
+	assert.False(t, signer.MatchesOwner(claims, "199012345679"))
+}
+
+func TestConsentLinkSigner_ExpiresWithConsent(t *testing.T) {
+	signer := newTestLinkSigner(t)
+	pendingExpiresAt := time.Now().Add(10 * time.Second)
+
+	_, expiresAt, err := signer.Sign(uuid.New().String(), "199012345678", &pendingExpiresAt)
+	require.NoError(t, err)
+	assert.WithinDuration(t, pendingExpiresAt, expiresAt, time.Second)
+}
+
+func TestConsentLinkSigner_Verify_Rejects(t *testing.T) {
+	signer := newTestLinkSigner(t)
+	token, _, err := signer.Sign(uuid.New().String(), "199012345678", nil)
+	require.NoError(t, err)
+	payload, signature, _ := strings.Cut(token, ".")
+
+	other, err := NewConsentLinkSigner(strings.Repeat("x", 32), time.Minute)
+	require.NoError(t, err)
+	otherToken, _, err := other.Sign(uuid.New().String(), "199012345678", nil)
+	require.NoError(t, err)
+	otherPayload, _, _ := strings.Cut(otherToken, ".")
+
+	for name, token := range map[string]string{
+		"empty":             "",
+		"no signature":      payload,
+		"swapped payload":   otherPayload + "." + signature,
+		"foreign signature": otherToken,
+		"garbage":           "not.a-token",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := signer.Verify(token)
+			assert.ErrorIs(t, err, models.ErrConsentLinkInvalid)
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		defer func() { signer.now = time.Now }()
+		_, err := signer.Verify(token)
+		assert.ErrorIs(t, err, models.ErrConsentLinkExpired)
+	})
+}
+
+func TestCreateConsentLink_Success(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	service.EnableConsentLinks(newTestLinkSigner(t))
+
+	id := uuid.New()
+	expectConsentLookup(mock, id, string(models.StatusPending), nil)
+
+	link, err := service.CreateConsentLink(context.Background(), id.String(), models.CreateConsentLinkRequest{OwnerID: "199012345678"})
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), link.ConsentID)
+	assert.Equal(t, "http://portal?consentToken="+link.Token, link.DeepLink)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateConsentLink_Rejects(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name             string
+		status           string
+		pendingExpiresAt *time.Time
+		ownerID          string
+		wantErr          error
+	}{
+		{"owner mismatch", string(models.StatusPending), nil, "199012345679", models.ErrConsentOwnerMismatch},
+		{"approved", string(models.StatusApproved), nil, "199012345678", models.ErrConsentNotPending},
+		{"pending timed out", string(models.StatusPending), &expired, "199012345678", models.ErrConsentNotPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			service, _ := NewConsentService(db, "http://portal")
+			service.EnableConsentLinks(newTestLinkSigner(t))
+
+			id := uuid.New()
+			expectConsentLookup(mock, id, tt.status, tt.pendingExpiresAt)
+
+			_, err := service.CreateConsentLink(context.Background(), id.String(), models.CreateConsentLinkRequest{OwnerID: tt.ownerID})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCreateConsentLink_Disabled(t *testing.T) {
+	db, _ := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+
+	_, err := service.CreateConsentLink(context.Background(), uuid.New().String(), models.CreateConsentLinkRequest{OwnerID: "199012345678"})
+	assert.ErrorIs(t, err, models.ErrConsentLinksDisabled)
+}
+
+func TestRedeemConsentLink(t *testing.T) {
+	signer := newTestLinkSigner(t)
+	id := uuid.New()
+	token, _, err := signer.Sign(id.String(), "199012345678", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		ownerID     string
+		email       string
+		status      string
+		expectQuery bool
+		wantErr     error
+	}{
+		{"success", "199012345678", "user@example.com", string(models.StatusPending), true, nil},
+		{"wrong NIC", "199012345679", "user@example.com", string(models.StatusPending), false, models.ErrConsentOwnerMismatch},
+		{"other user", "199012345678", "other@example.com", string(models.StatusPending), true, models.ErrConsentOwnerMismatch},
+		{"already approved", "199012345678", "user@example.com", string(models.StatusApproved), true, models.ErrConsentNotPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			service, _ := NewConsentService(db, "http://portal")
+			service.EnableConsentLinks(signer)
+			if tt.expectQuery {
+				expectConsentLookup(mock, id, tt.status, nil)
+			}
+
+			redemption, err := service.RedeemConsentLink(context.Background(), models.RedeemConsentLinkRequest{Token: token, OwnerID: tt.ownerID}, tt.email)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, id.String(), redemption.ConsentID)
+				assert.Equal(t, "app-1", redemption.Consent.AppID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"time"
 
@@ -12,10 +13,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// notificationTimeout bounds the delivery of a notification to an owner
+const notificationTimeout = 30 * time.Second
+
 // ConsentService provides business logic for consent operations
 type ConsentService struct {
 	db                   *gorm.DB
 	consentPortalBaseURL string
+	// linkSigner signs consent link tokens; nil while consent links are not enabled
+	linkSigner *ConsentLinkSigner
+	// sessionSigner signs portal session tokens; nil while portal sessions are not enabled
+	sessionSigner *PortalSessionSigner
+	// notifier tells owners about changes to their consents; nil while notifications are not enabled
+	notifier ConsentNotifier
+	// reuseWindow is how long after its creation an active consent is reused; zero reuses it while it is active
+	reuseWindow time.Duration
 }
 
 // NewConsentService creates a new consent service
@@ -30,26 +42,47 @@ func NewConsentService(db *gorm.DB, consentPortalBaseURL string) (*ConsentServic
 	}, nil
 }
 
-// CreateConsentRecord creates a new consent record in the database
+// SetReuseWindow limits reusing an active consent for an equivalent request to the window after its creation.
+// Once it elapses, the consent is revoked and replaced by a new one; a zero window reuses it while it is active
+func (s *ConsentService) SetReuseWindow(window time.Duration) {
+	s.reuseWindow = window
+}
+
+// EnableNotifications lets the service notify owners about changes to their consents with the notifier
+func (s *ConsentService) EnableNotifications(notifier ConsentNotifier) {
+	s.notifier = notifier
+}
+
+// CreateConsentRecord creates a new consent record in the database. An active consent of the owner for the
+// application with the same fingerprint is returned instead, unless it was created before the reuse window.
 func (s *ConsentService) CreateConsentRecord(ctx context.Context, req models.CreateConsentRequest) (*models.ConsentResponseInternalView, error) {
 	// Validate input first
 	if err := validateCreateConsentRequest(req); err != nil {
 		return nil, fmt.Errorf("%w: %w", models.ErrConsentCreateFailed, err)
 	}
+	fingerprint := consentFingerprint(req)
 
 	// First Check if a pending or approved consent already exists for the same (ownerID/ownerEmail, appID)
-	existingConsent, err := s.GetConsentInternalView(ctx, nil, &req.ConsentRequirement.OwnerID, &req.ConsentRequirement.OwnerEmail, &req.AppID)
+	existingConsent, err := s.findLatestConsentRecord(ctx, nil, &req.ConsentRequirement.OwnerID, &req.ConsentRequirement.OwnerEmail, &req.AppID)
 	if err == nil {
 		// An existing consent was found
 		if existingConsent.Status == string(models.StatusPending) || existingConsent.Status == string(models.StatusApproved) {
-			// Check if the fields match
-			if areConsentFieldsEqual(existingConsent.Fields, &req.ConsentRequirement.Fields) {
-				// Return the existing consent instead of creating a new one
-				return existingConsent, nil
+			revokedBy := models.RevokedByNewConsentWithDifferentFields
+			if isEquivalentConsent(existingConsent, fingerprint, req) {
+				if s.reuseWindow <= 0 || time.Since(existingConsent.CreatedAt) <= s.reuseWindow {
+					// Return the existing consent instead of creating a new one
+					internalView := existingConsent.ToConsentResponseInternalView()
+					return &internalView, nil
+				}
+				revokedBy = models.RevokedByConsentReuseWindowElapsed
 			}
-			// Revoke the existing consent if fields do not match and create a new one
+			// Revoke the existing consent and create a new one
 			// This operation must be transactional
-			return s.revokeAndCreateConsent(ctx, existingConsent.ConsentID, req)
+			internalView, err := s.revokeAndCreateConsent(ctx, existingConsent.ConsentID.String(), req, fingerprint, revokedBy)
+			if err != nil {
+				return s.activeConsentOnDuplicate(ctx, fingerprint, err)
+			}
+			return internalView, nil
 		}
 	} else {
 		// If the error is not "not found", return the error
@@ -59,14 +92,14 @@ func (s *ConsentService) CreateConsentRecord(ctx context.Context, req models.Cre
 	}
 
 	// Create new consent record
-	consentRecord, err := s.buildConsentRecord(req)
+	consentRecord, err := s.buildConsentRecord(req, fingerprint)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", models.ErrConsentCreateFailed, err)
 	}
 
 	// Insert consent record
 	if err := s.db.WithContext(ctx).Create(&consentRecord).Error; err != nil {
-		return nil, fmt.Errorf("%w: %w", models.ErrConsentCreateFailed, err)
+		return s.activeConsentOnDuplicate(ctx, fingerprint, fmt.Errorf("%w: %w", models.ErrConsentCreateFailed, err))
 	}
 
 	// Convert to internal view response
@@ -74,8 +107,25 @@ func (s *ConsentService) CreateConsentRecord(ctx context.Context, req models.Cre
 	return &internalView, nil
 }
 
+// activeConsentOnDuplicate returns the active consent with the fingerprint when createErr is a unique violation,
+// which means an equivalent request created it concurrently. Otherwise it returns createErr.
+func (s *ConsentService) activeConsentOnDuplicate(ctx context.Context, fingerprint string, createErr error) (*models.ConsentResponseInternalView, error) {
+	if !errors.Is(createErr, gorm.ErrDuplicatedKey) {
+		return nil, createErr
+	}
+	var consentRecord models.ConsentRecord
+	err := s.db.WithContext(ctx).
+		Where("fingerprint = ? AND status IN ?", fingerprint, []string{string(models.StatusPending), string(models.StatusApproved)}).
+		First(&consentRecord).Error
+	if err != nil {
+		return nil, createErr
+	}
+	internalView := consentRecord.ToConsentResponseInternalView()
+	return &internalView, nil
+}
+
 // revokeAndCreateConsent revokes an existing consent and creates a new one in a single transaction
-func (s *ConsentService) revokeAndCreateConsent(ctx context.Context, existingConsentID string, req models.CreateConsentRequest) (*models.ConsentResponseInternalView, error) {
+func (s *ConsentService) revokeAndCreateConsent(ctx context.Context, existingConsentID string, req models.CreateConsentRequest, fingerprint string, revokedBy models.UpdateByMessage) (*models.ConsentResponseInternalView, error) {
 	var newConsentRecord models.ConsentRecord
 
 	// Execute revoke and create in a transaction
@@ -101,7 +151,6 @@ func (s *ConsentService) revokeAndCreateConsent(ctx context.Context, existingCon
 		existingConsentRecord.Status = string(models.StatusRevoked)
 		currentTime := time.Now().UTC()
 		existingConsentRecord.UpdatedAt = currentTime
-		revokedBy := models.RevokedByNewConsentWithDifferentFields
 		existingConsentRecord.UpdatedBy = (*string)(&revokedBy)
 
 		if err := tx.Save(&existingConsentRecord).Error; err != nil {
@@ -109,7 +158,7 @@ func (s *ConsentService) revokeAndCreateConsent(ctx context.Context, existingCon
 		}
 
 		// Step 2: Create the new consent record
-		newConsentRecordPtr, err := s.buildConsentRecord(req)
+		newConsentRecordPtr, err := s.buildConsentRecord(req, fingerprint)
 		if err != nil {
 			return fmt.Errorf("failed to build new consent record: %w", err)
 		}
@@ -131,7 +180,7 @@ func (s *ConsentService) revokeAndCreateConsent(ctx context.Context, existingCon
 }
 
 // buildConsentRecord builds a ConsentRecord from the request
-func (s *ConsentService) buildConsentRecord(req models.CreateConsentRequest) (*models.ConsentRecord, error) {
+func (s *ConsentService) buildConsentRecord(req models.CreateConsentRequest, fingerprint string) (*models.ConsentRecord, error) {
 	// No need of Validate input
 	// Validation is already performed by callers (CreateConsentRecord)
 
@@ -159,6 +208,7 @@ func (s *ConsentService) buildConsentRecord(req models.CreateConsentRequest) (*m
 		Fields:           req.ConsentRequirement.Fields,
 		ConsentPortalURL: fmt.Sprintf("%s?consentId=%s", s.consentPortalBaseURL, consentID.String()),
 		PendingExpiresAt: &pendingExpiresAt,
+		Fingerprint:      &fingerprint,
 	}, nil
 }
 
@@ -172,6 +222,17 @@ func getGrantDurationOrDefault(grantDuration *models.GrantDuration) models.Grant
 
 // GetConsentInternalView retrieves a consent record by ID or by ((ownerID OR ownerEmail) AND appID) and returns its internal view
 func (s *ConsentService) GetConsentInternalView(ctx context.Context, consentID *string, ownerID *string, ownerEmail *string, appID *string) (*models.ConsentResponseInternalView, error) {
+	consentRecord, err := s.findLatestConsentRecord(ctx, consentID, ownerID, ownerEmail, appID)
+	if err != nil {
+		return nil, err
+	}
+	internalView := consentRecord.ToConsentResponseInternalView()
+	return &internalView, nil
+}
+
+// findLatestConsentRecord retrieves a consent record by ID or the latest one by ((ownerID OR ownerEmail) AND appID),
+// marking it expired if it is past its expiry
+func (s *ConsentService) findLatestConsentRecord(ctx context.Context, consentID *string, ownerID *string, ownerEmail *string, appID *string) (*models.ConsentRecord, error) {
 	var consentRecord models.ConsentRecord
 	query := s.db.WithContext(ctx).Model(&models.ConsentRecord{})
 
@@ -218,8 +279,7 @@ func (s *ConsentService) GetConsentInternalView(ctx context.Context, consentID *
 		}
 	}
 
-	internalView := consentRecord.ToConsentResponseInternalView()
-	return &internalView, nil
+	return &consentRecord, nil
 }
 
 // GetConsentPortalView retrieves a consent record by ID and returns its portal view
@@ -247,6 +307,9 @@ func (s *ConsentService) UpdateConsentStatusByPortalAction(ctx context.Context,
 	if !isValidConsentPortalAction(req.Action) {
 		return fmt.Errorf("%w: invalid action: %s", models.ErrPortalRequestFailed, req.Action)
 	}
+	if req.Reason != nil && (req.Action != models.ActionReject || !req.Reason.IsValid()) {
+		return fmt.Errorf("%w: invalid reason: %s", models.ErrPortalRequestFailed, *req.Reason)
+	}
 
 	var consentRecord models.ConsentRecord
 	parsedConsentID, err := uuid.Parse(req.ConsentID)
@@ -261,9 +324,17 @@ func (s *ConsentService) UpdateConsentStatusByPortalAction(ctx context.Context,
 		return fmt.Errorf("%w: %w", models.ErrConsentUpdateFailed, err)
 	}
 
+	// Consents that were cancelled, revoked or expired can no longer be acted upon
+	previousStatus := consentRecord.Status
+	switch models.ConsentStatus(previousStatus) {
+	case models.StatusCancelled, models.StatusRevoked, models.StatusExpired:
+		return fmt.Errorf("%w: consent is %s", models.ErrConsentNotPending, previousStatus)
+	}
+
 	currentTime := time.Now().UTC()
 	consentRecord.UpdatedAt = currentTime
 	consentRecord.UpdatedBy = &req.UpdatedBy
+	consentRecord.DecidedAt = &currentTime
 
 	switch req.Action {
 	case models.ActionApprove:
@@ -275,17 +346,96 @@ func (s *ConsentService) UpdateConsentStatusByPortalAction(ctx context.Context,
 		consentRecord.Status = string(models.StatusRejected)
 		// Do not set GrantExpiresAt on rejection - only approval gets a grant expiry
 		consentRecord.PendingExpiresAt = nil
+		consentRecord.RejectionReason = (*string)(req.Reason)
 	default:
 		return fmt.Errorf("%w: invalid action: %s", models.ErrPortalRequestFailed, req.Action)
 	}
 
-	if err := s.db.WithContext(ctx).Save(&consentRecord).Error; err != nil {
-		return fmt.Errorf("%w: %w", models.ErrConsentUpdateFailed, err)
+	// The update only applies if the status is unchanged, so that an approval racing a cancellation
+	// cannot overwrite it
+	result := s.db.WithContext(ctx).Model(&consentRecord).
+		Where("status = ?", previousStatus).
+		Select("status", "updated_at", "updated_by", "grant_expires_at", "pending_expires_at", "decided_at", "rejection_reason").
+		Updates(&consentRecord)
+	if result.Error != nil {
+		return fmt.Errorf("%w: %w", models.ErrConsentUpdateFailed, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: consent was changed concurrently", models.ErrConsentNotPending)
 	}
 
 	return nil
 }
 
+// CancelConsent withdraws a pending consent on behalf of the application that requested it. The owner
+// is notified, and the consent can no longer be approved.
+func (s *ConsentService) CancelConsent(ctx context.Context, consentID, appID string) (*models.ConsentResponseInternalView, error) {
+	parsedConsentID, err := uuid.Parse(consentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid consent ID", models.ErrConsentCancelFailed)
+	}
+	if appID == "" {
+		return nil, fmt.Errorf("%w: appId is required", models.ErrConsentCancelFailed)
+	}
+
+	var consentRecord models.ConsentRecord
+	if err := s.db.WithContext(ctx).Where("consent_id = ?", parsedConsentID).First(&consentRecord).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", models.ErrConsentNotFound, err)
+		}
+		return nil, fmt.Errorf("%w: %w", models.ErrConsentCancelFailed, err)
+	}
+	if consentRecord.AppID != appID {
+		return nil, models.ErrConsentAppMismatch
+	}
+	if consentRecord.Status != string(models.StatusPending) ||
+		(consentRecord.PendingExpiresAt != nil && time.Now().UTC().After(*consentRecord.PendingExpiresAt)) {
+		return nil, fmt.Errorf("%w: consent is %s", models.ErrConsentNotPending, consentRecord.Status)
+	}
+
+	cancelledBy := fmt.Sprintf(string(models.CancelledByConsumer), appID)
+	currentTime := time.Now().UTC()
+	// Only a consent that is still pending is cancelled, so a concurrent approval or rejection wins
+	result := s.db.WithContext(ctx).Model(&models.ConsentRecord{}).
+		Where("consent_id = ? AND status = ?", parsedConsentID, models.StatusPending).
+		Updates(map[string]interface{}{
+			"status":             string(models.StatusCancelled),
+			"updated_at":         currentTime,
+			"updated_by":         cancelledBy,
+			"pending_expires_at": nil,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrConsentCancelFailed, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: consent was changed concurrently", models.ErrConsentNotPending)
+	}
+
+	consentRecord.Status = string(models.StatusCancelled)
+	consentRecord.UpdatedAt = currentTime
+	consentRecord.UpdatedBy = &cancelledBy
+	consentRecord.PendingExpiresAt = nil
+	s.notifyConsentCancelled(consentRecord)
+
+	internalView := consentRecord.ToConsentResponseInternalView()
+	return &internalView, nil
+}
+
+// notifyConsentCancelled notifies the owner in the background, so the consumer is not held up by
+// the notification; failures are logged
+func (s *ConsentService) notifyConsentCancelled(consentRecord models.ConsentRecord) {
+	if s.notifier == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+		if err := s.notifier.NotifyConsentCancelled(ctx, &consentRecord); err != nil {
+			slog.Error("Failed to notify owner of cancelled consent", "consentId", consentRecord.ConsentID, "error", err)
+		}
+	}()
+}
+
 // RevokeConsent revokes an existing approved or pending consent
 func (s *ConsentService) RevokeConsent(ctx context.Context, consentID string, revokedBy string) error {
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"database/sql/driver"
 	"regexp"
 	"testing"
 	"time"
@@ -253,6 +254,177 @@ func TestCreateConsentRecord_RevokeAndCreate(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// revokeConsentArgs are the arguments of saving the consent revoked by updatedBy
+func revokeConsentArgs(consentID uuid.UUID, updatedBy models.UpdateByMessage) []driver.Value {
+	args := make([]driver.Value, 19)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[4] = string(models.StatusRevoked)
+	args[14] = string(updatedBy)
+	args[18] = consentID
+	return args
+}
+
+func TestCreateConsentRecord_ReuseByFingerprint(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	ctx := context.Background()
+
+	stored := models.CreateConsentRequest{
+		AppID: "app-1",
+		ConsentRequirement: models.ConsentRequirement{
+			OwnerID:    "user-1",
+			OwnerEmail: "user-1@example.com",
+			Fields: []models.ConsentField{
+				{FieldName: "email", SchemaID: "schema-1", Owner: "citizen"},
+				{FieldName: "address", SchemaID: "schema-1", Owner: "citizen"},
+			},
+		},
+		Purposes: []string{"tax-assessment"},
+	}
+	existID := uuid.New()
+	rows := sqlmock.NewRows([]string{"consent_id", "status", "fields", "fingerprint", "created_at"}).
+		AddRow(existID, "approved", `[{"fieldName":"email","schemaId":"schema-1","owner":"citizen"},{"fieldName":"address","schemaId":"schema-1","owner":"citizen"}]`,
+			consentFingerprint(stored), time.Now().Add(-time.Hour))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE owner_id = $1 AND app_id = $2 ORDER BY created_at DESC`)).
+		WithArgs("user-1", "app-1", 1).
+		WillReturnRows(rows)
+
+	// The same consent requested with the fields in another order and a display name
+	displayName := "Email"
+	req := stored
+	req.ConsentRequirement.Fields = []models.ConsentField{
+		{FieldName: "address", SchemaID: "schema-1", Owner: "citizen"},
+		{FieldName: "email", SchemaID: "schema-1", Owner: "citizen", DisplayName: &displayName},
+	}
+
+	resp, err := service.CreateConsentRecord(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, existID.String(), resp.ConsentID)
+	assert.Equal(t, string(models.StatusApproved), resp.Status)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateConsentRecord_DifferentPurposes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	ctx := context.Background()
+
+	stored := models.CreateConsentRequest{
+		AppID: "app-1",
+		ConsentRequirement: models.ConsentRequirement{
+			OwnerID:    "user-1",
+			OwnerEmail: "user-1@example.com",
+			Fields:     []models.ConsentField{{FieldName: "email", SchemaID: "schema-1", Owner: "citizen"}},
+		},
+		Purposes: []string{"tax-assessment"},
+	}
+	existID := uuid.New()
+	rows := sqlmock.NewRows([]string{"consent_id", "status", "fields", "fingerprint", "created_at"}).
+		AddRow(existID, "pending", `[{"fieldName":"email","schemaId":"schema-1","owner":"citizen"}]`, consentFingerprint(stored), time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE owner_id = $1 AND app_id = $2 ORDER BY created_at DESC`)).
+		WithArgs("user-1", "app-1", 1).
+		WillReturnRows(rows)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE consent_id = $1`)).
+		WithArgs(existID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"consent_id", "status"}).AddRow(existID, "pending"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_records"`)).
+		WithArgs(revokeConsentArgs(existID, models.RevokedByNewConsentWithDifferentFields)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "consent_records"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"consent_id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	// The same fields requested for another purpose
+	req := stored
+	req.Purposes = []string{"benefit-eligibility"}
+
+	resp, err := service.CreateConsentRecord(ctx, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, existID.String(), resp.ConsentID)
+	assert.Equal(t, string(models.StatusPending), resp.Status)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateConsentRecord_ReuseWindowElapsed(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	service.SetReuseWindow(time.Hour)
+	ctx := context.Background()
+
+	req := models.CreateConsentRequest{
+		AppID: "app-1",
+		ConsentRequirement: models.ConsentRequirement{
+			OwnerID:    "user-1",
+			OwnerEmail: "user-1@example.com",
+			Fields:     []models.ConsentField{{FieldName: "email", SchemaID: "schema-1", Owner: "citizen"}},
+		},
+	}
+	existID := uuid.New()
+	rows := sqlmock.NewRows([]string{"consent_id", "status", "fields", "fingerprint", "created_at"}).
+		AddRow(existID, "approved", `[{"fieldName":"email","schemaId":"schema-1","owner":"citizen"}]`, consentFingerprint(req), time.Now().Add(-2*time.Hour))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE owner_id = $1 AND app_id = $2 ORDER BY created_at DESC`)).
+		WithArgs("user-1", "app-1", 1).
+		WillReturnRows(rows)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE consent_id = $1`)).
+		WithArgs(existID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"consent_id", "status"}).AddRow(existID, "approved"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_records"`)).
+		WithArgs(revokeConsentArgs(existID, models.RevokedByConsentReuseWindowElapsed)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "consent_records"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"consent_id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	resp, err := service.CreateConsentRecord(ctx, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, existID.String(), resp.ConsentID)
+	assert.Equal(t, string(models.StatusPending), resp.Status)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateConsentRecord_ConcurrentDuplicate(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	ctx := context.Background()
+
+	req := models.CreateConsentRequest{
+		AppID: "app-1",
+		ConsentRequirement: models.ConsentRequirement{
+			OwnerID:    "user-1",
+			OwnerEmail: "user-1@example.com",
+			Fields:     []models.ConsentField{{FieldName: "email", SchemaID: "schema-1", Owner: "citizen"}},
+		},
+	}
+	fingerprint := consentFingerprint(req)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE owner_id = $1 AND app_id = $2 ORDER BY created_at DESC`)).
+		WithArgs("user-1", "app-1", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	// An equivalent request created the consent between the lookup and the insert
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "consent_records"`)).
+		WillReturnError(gorm.ErrDuplicatedKey)
+	concurrentID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE fingerprint = $1 AND status IN ($2,$3)`)).
+		WithArgs(fingerprint, "pending", "approved", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"consent_id", "status", "fingerprint"}).AddRow(concurrentID, "pending", fingerprint))
+
+	resp, err := service.CreateConsentRecord(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, concurrentID.String(), resp.ConsentID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetConsentInternalView_ByOwnerApp(t *testing.T) {
 	db, mock := setupMockDB(t)
 	service, _ := NewConsentService(db, "http://portal")
@@ -674,3 +846,81 @@ func TestParseGrantDuration_AllCases(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	}
 }
+
+func TestUpdateConsentStatusByPortalAction_Cancelled(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+
+	id := uuid.New()
+	expectConsentLookup(mock, id, string(models.StatusCancelled), nil)
+
+	req := models.ConsentPortalActionRequest{
+		ConsentID: id.String(),
+		Action:    models.ActionApprove,
+		UpdatedBy: "user@example.com",
+	}
+
+	err := service.UpdateConsentStatusByPortalAction(context.Background(), req)
+	assert.ErrorIs(t, err, models.ErrConsentNotPending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateConsentStatusByPortalAction_CancelledConcurrently(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+
+	id := uuid.New()
+	expectConsentLookup(mock, id, string(models.StatusPending), nil)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_records"`) + ".*" + regexp.QuoteMeta(`WHERE status = $8 AND "consent_id" = $9`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	req := models.ConsentPortalActionRequest{
+		ConsentID: id.String(),
+		Action:    models.ActionApprove,
+		UpdatedBy: "user@example.com",
+	}
+
+	err := service.UpdateConsentStatusByPortalAction(context.Background(), req)
+	assert.ErrorIs(t, err, models.ErrConsentNotPending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateConsentStatusByPortalAction_RejectionReason(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	reason := models.ReasonPrivacyConcern
+
+	id := uuid.New()
+	expectConsentLookup(mock, id, string(models.StatusPending), nil)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_records" SET "status"=$1,"updated_at"=$2,"pending_expires_at"=$3,"grant_expires_at"=$4,"updated_by"=$5,"decided_at"=$6,"rejection_reason"=$7`)).
+		WithArgs(string(models.StatusRejected), sqlmock.AnyArg(), nil, nil, "user@example.com", sqlmock.AnyArg(), "privacy_concern", "pending", id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := service.UpdateConsentStatusByPortalAction(context.Background(), models.ConsentPortalActionRequest{
+		ConsentID: id.String(),
+		Action:    models.ActionReject,
+		UpdatedBy: "user@example.com",
+		Reason:    &reason,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Run("Only rejections take a reason", func(t *testing.T) {
+		err := service.UpdateConsentStatusByPortalAction(context.Background(), models.ConsentPortalActionRequest{
+			ConsentID: id.String(),
+			Action:    models.ActionApprove,
+			UpdatedBy: "user@example.com",
+			Reason:    &reason,
+		})
+		assert.ErrorIs(t, err, models.ErrPortalRequestFailed)
+
+		unknown := models.RejectionReason("too_busy")
+		err = service.UpdateConsentStatusByPortalAction(context.Background(), models.ConsentPortalActionRequest{
+			ConsentID: id.String(),
+			Action:    models.ActionReject,
+			UpdatedBy: "user@example.com",
+			Reason:    &unknown,
+		})
+		assert.ErrorIs(t, err, models.ErrPortalRequestFailed)
+	})
+}
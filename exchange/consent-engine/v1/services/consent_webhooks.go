@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Headers sent with every consent change webhook request. The signature is the hex HMAC-SHA256, keyed
+// with the webhook secret, of the timestamp header, a ".", and the request body, as for the portal's webhooks.
+const (
+	ConsentWebhookSignatureHeader = "X-Webhook-Signature"
+	ConsentWebhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
+// DefaultConsentWebhookInterval is how often pending changes are pushed when no interval is configured
+const DefaultConsentWebhookInterval = 5 * time.Second
+
+const (
+	// consentWebhookBatchSize caps the changes sent in one webhook request
+	consentWebhookBatchSize = 100
+	// consentWebhookTimeout bounds each webhook request
+	consentWebhookTimeout = 10 * time.Second
+)
+
+// ConsentWebhookDispatcher pushes consent status changes to webhooks. Each webhook receives the
+// changes in order, in batches shaped like a page of the change feed; its cursor only advances once
+// an endpoint accepts a batch, so changes are delivered at least once and receivers should ignore
+// changes whose id they have seen.
+type ConsentWebhookDispatcher struct {
+	db     *gorm.DB
+	urls   []string
+	secret string
+	client *http.Client
+}
+
+// NewConsentWebhookDispatcher creates a dispatcher pushing changes to urls, signing them with secret
+func NewConsentWebhookDispatcher(db *gorm.DB, urls []string, secret string) (*ConsentWebhookDispatcher, error) {
+	if secret == "" {
+		return nil, errors.New("a webhook secret is required")
+	}
+	for _, rawURL := range urls {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q: must be an http or https URL", rawURL)
+		}
+	}
+	return &ConsentWebhookDispatcher{
+		db:     db,
+		urls:   urls,
+		secret: secret,
+		client: &http.Client{Timeout: consentWebhookTimeout},
+	}, nil
+}
+
+// Register adds the webhooks that are not registered yet. New webhooks start at the latest change, so
+// they receive the changes made from now on; receivers catch up on older ones with the change feed.
+func (d *ConsentWebhookDispatcher) Register(ctx context.Context) error {
+	for _, webhookURL := range d.urls {
+		err := d.db.WithContext(ctx).Exec(`INSERT INTO consent_change_webhooks (url, cursor, updated_at)
+			SELECT ?, COALESCE(MAX(id), 0), CURRENT_TIMESTAMP FROM consent_status_changes
+			ON CONFLICT (url) DO NOTHING`, webhookURL).Error
+		if err != nil {
+			return fmt.Errorf("failed to register webhook %s: %w", webhookURL, err)
+		}
+	}
+	return nil
+}
+
+// Run pushes pending changes every interval until ctx is done
+func (d *ConsentWebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultConsentWebhookInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.DeliverPending(ctx)
+		}
+	}
+}
+
+// DeliverPending pushes the changes each webhook has not received yet, until it is caught up or a
+// delivery fails; failed deliveries are retried on the next run
+func (d *ConsentWebhookDispatcher) DeliverPending(ctx context.Context) {
+	for _, webhookURL := range d.urls {
+		for {
+			more, err := d.deliverBatch(ctx, webhookURL)
+			if err != nil {
+				slog.Warn("Failed to deliver consent changes", "url", webhookURL, "error", err)
+				break
+			}
+			if !more {
+				break
+			}
+		}
+	}
+}
+
+// deliverBatch sends the next batch of changes to a webhook and advances its cursor. The webhook is
+// locked while the batch is sent, so replicas of the consent engine skip it instead of sending the
+// batch twice. It reports whether more changes may be waiting.
+func (d *ConsentWebhookDispatcher) deliverBatch(ctx context.Context, webhookURL string) (bool, error) {
+	var more bool
+	var sendErr error
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var webhook models.ConsentChangeWebhook
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("url = ?", webhookURL).Take(&webhook).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Another replica is delivering to the webhook
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock webhook: %w", err)
+		}
+
+		changes := []models.ConsentStatusChange{}
+		if err := tx.Where("id > ?", webhook.Cursor).Order("id").Limit(consentWebhookBatchSize).Find(&changes).Error; err != nil {
+			return fmt.Errorf("failed to list consent changes: %w", err)
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		more = len(changes) == consentWebhookBatchSize
+		cursor := changes[len(changes)-1].ID
+		if sendErr = d.send(ctx, webhookURL, changes, cursor, more); sendErr != nil {
+			return tx.Model(&webhook).Updates(map[string]interface{}{
+				"last_error": sendErr.Error(),
+				"updated_at": now,
+			}).Error
+		}
+		return tx.Model(&webhook).Updates(map[string]interface{}{
+			"cursor":       cursor,
+			"delivered_at": now,
+			"last_error":   nil,
+			"updated_at":   now,
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	if sendErr != nil {
+		return false, sendErr
+	}
+	return more, nil
+}
+
+// send POSTs a signed batch of changes to the webhook
+func (d *ConsentWebhookDispatcher) send(ctx context.Context, webhookURL string, changes []models.ConsentStatusChange, cursor int64, more bool) error {
+	body, err := json.Marshal(models.ConsentChangesResponse{
+		Changes:    changes,
+		NextCursor: strconv.FormatInt(cursor, 10),
+		HasMore:    more,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode consent changes: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ConsentWebhookTimestampHeader, timestamp)
+	req.Header.Set(ConsentWebhookSignatureHeader, "sha256="+SignConsentWebhookPayload(d.secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignConsentWebhookPayload returns the hex HMAC-SHA256 signature of a webhook request, which receivers
+// recompute from the timestamp header and raw body to verify it
+func SignConsentWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+)
+
+// ConsentNotifier tells data owners about changes to their consents that they did not make themselves
+type ConsentNotifier interface {
+	NotifyConsentCancelled(ctx context.Context, consent *models.ConsentRecord) error
+}
+
+// EmailSender delivers notification emails
+type EmailSender interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPEmailSender sends plain-text emails through an SMTP server
+type SMTPEmailSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPEmailSender creates an SMTP email sender. PLAIN authentication is used when username is set.
+func NewSMTPEmailSender(host, port, username, password, from string) *SMTPEmailSender {
+	sender := &SMTPEmailSender{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+// Send sends a plain-text email to a single recipient
+func (s *SMTPEmailSender) Send(to, subject, body string) error {
+	// Application names end up in the subject, so strip line breaks to keep them from injecting headers
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s\r\n",
+		s.from, to, subject, body)
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// EmailConsentNotifier notifies data owners by email, at the address the consent was requested for
+type EmailConsentNotifier struct {
+	sender EmailSender
+}
+
+// NewEmailConsentNotifier creates a notifier sending emails with sender
+func NewEmailConsentNotifier(sender EmailSender) *EmailConsentNotifier {
+	return &EmailConsentNotifier{sender: sender}
+}
+
+// NotifyConsentCancelled tells the owner that a pending consent request was withdrawn and needs no action
+func (n *EmailConsentNotifier) NotifyConsentCancelled(_ context.Context, consent *models.ConsentRecord) error {
+	app := consent.AppID
+	if consent.AppName != nil && *consent.AppName != "" {
+		app = *consent.AppName
+	}
+	subject := fmt.Sprintf("%s withdrew its request to access your data", app)
+	body := fmt.Sprintf("%s no longer needs access to your data and has withdrawn its consent request.\n\n"+
+		"You do not need to take any action. If you open the request, it can no longer be approved.\n\n"+
+		"Consent request: %s", app, consent.ConsentID)
+	return n.sender.Send(consent.OwnerEmail, subject, body)
+}
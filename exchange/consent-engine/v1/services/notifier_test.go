@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentEmail struct {
+	to, subject, body string
+}
+
+// fakeEmailSender records the emails it is asked to send
+type fakeEmailSender struct {
+	sent chan sentEmail
+	err  error
+}
+
+func newFakeEmailSender() *fakeEmailSender {
+	return &fakeEmailSender{sent: make(chan sentEmail, 1)}
+}
+
+func (s *fakeEmailSender) Send(to, subject, body string) error {
+	s.sent <- sentEmail{to: to, subject: subject, body: body}
+	return s.err
+}
+
+func TestEmailConsentNotifier_NotifyConsentCancelled(t *testing.T) {
+	sender := newFakeEmailSender()
+	notifier := NewEmailConsentNotifier(sender)
+	appName := "Passport Service"
+	consent := &models.ConsentRecord{
+		ConsentID:  uuid.New(),
+		OwnerEmail: "user@example.com",
+		AppID:      "app-1",
+		AppName:    &appName,
+	}
+
+	require.NoError(t, notifier.NotifyConsentCancelled(context.Background(), consent))
+
+	email := <-sender.sent
+	assert.Equal(t, "user@example.com", email.to)
+	assert.Contains(t, email.subject, "Passport Service")
+	assert.Contains(t, email.body, consent.ConsentID.String())
+}
+
+func TestEmailConsentNotifier_SendFails(t *testing.T) {
+	sender := newFakeEmailSender()
+	sender.err = errors.New("connection refused")
+	notifier := NewEmailConsentNotifier(sender)
+
+	err := notifier.NotifyConsentCancelled(context.Background(), &models.ConsentRecord{ConsentID: uuid.New(), AppID: "app-1"})
+	assert.Error(t, err)
+	email := <-sender.sent
+	assert.Contains(t, email.subject, "app-1")
+}
+
+func TestCancelConsent_Success(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	sender := newFakeEmailSender()
+	service.EnableNotifications(NewEmailConsentNotifier(sender))
+
+	id := uuid.New()
+	pendingExpiresAt := time.Now().Add(time.Hour)
+	expectConsentLookup(mock, id, string(models.StatusPending), &pendingExpiresAt)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_records" SET`)+".*"+regexp.QuoteMeta(`WHERE consent_id = $5 AND status = $6`)).
+		WithArgs(nil, string(models.StatusCancelled), sqlmock.AnyArg(), "Consumer: cancelled by application app-1", id, models.StatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	consent, err := service.CancelConsent(context.Background(), id.String(), "app-1")
+	require.NoError(t, err)
+	assert.Equal(t, string(models.StatusCancelled), consent.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	select {
+	case email := <-sender.sent:
+		assert.Equal(t, "user@example.com", email.to)
+	case <-time.After(time.Second):
+		t.Fatal("owner was not notified")
+	}
+}
+
+func TestCancelConsent_Rejects(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name             string
+		appID            string
+		status           string
+		pendingExpiresAt *time.Time
+		concurrent       bool
+		wantErr          error
+	}{
+		{name: "other application", appID: "app-2", status: string(models.StatusPending), wantErr: models.ErrConsentAppMismatch},
+		{name: "already approved", appID: "app-1", status: string(models.StatusApproved), wantErr: models.ErrConsentNotPending},
+		{name: "already cancelled", appID: "app-1", status: string(models.StatusCancelled), wantErr: models.ErrConsentNotPending},
+		{name: "pending expired", appID: "app-1", status: string(models.StatusPending), pendingExpiresAt: &expired, wantErr: models.ErrConsentNotPending},
+		{name: "approved concurrently", appID: "app-1", status: string(models.StatusPending), concurrent: true, wantErr: models.ErrConsentNotPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			service, _ := NewConsentService(db, "http://portal")
+
+			id := uuid.New()
+			expectConsentLookup(mock, id, tt.status, tt.pendingExpiresAt)
+			if tt.concurrent {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE "consent_records"`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			_, err := service.CancelConsent(context.Background(), id.String(), tt.appID)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCancelConsent_NotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+
+	id := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "consent_records" WHERE consent_id = $1`)+".*"+regexp.QuoteMeta(`LIMIT $2`)).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"consent_id"}))
+
+	_, err := service.CancelConsent(context.Background(), id.String(), "app-1")
+	assert.ErrorIs(t, err, models.ErrConsentNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+)
+
+// PortalSessionClaims is the payload of a portal session token: the one consent it is scoped to and
+// the signed-in citizen it was handed to
+type PortalSessionClaims struct {
+	ConsentID string `json:"cid"`
+	Email     string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// PortalSessionSigner mints and verifies portal session tokens: short-lived HMAC-SHA256 signed tokens
+// that let the consent portal read and act on exactly one consent without the citizen's IDP token
+type PortalSessionSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewPortalSessionSigner creates a signer; non-positive TTLs use models.DefaultPortalSessionTTL
+func NewPortalSessionSigner(secret string, ttl time.Duration) (*PortalSessionSigner, error) {
+	if len(secret) < minConsentLinkSecretLength {
+		return nil, fmt.Errorf("portal session secret must be at least %d bytes", minConsentLinkSecretLength)
+	}
+	if ttl <= 0 {
+		ttl = models.DefaultPortalSessionTTL
+	}
+	return &PortalSessionSigner{secret: []byte(secret), ttl: ttl, now: time.Now}, nil
+}
+
+// Sign returns a token for the consent and citizen, expiring after the signer's TTL or at notAfter,
+// whichever comes first
+func (s *PortalSessionSigner) Sign(consentID, email string, notAfter *time.Time) (string, time.Time, error) {
+	issuedAt := s.now().UTC()
+	expiresAt := issuedAt.Add(s.ttl)
+	if notAfter != nil && notAfter.Before(expiresAt) {
+		expiresAt = notAfter.UTC()
+	}
+
+	payload, err := json.Marshal(PortalSessionClaims{
+		ConsentID: consentID,
+		Email:     email,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return models.PortalSessionTokenPrefix + encoded + "." + s.signature(encoded), time.Unix(expiresAt.Unix(), 0).UTC(), nil
+}
+
+// Verify checks the token's signature and expiry and returns its claims
+func (s *PortalSessionSigner) Verify(token string) (*PortalSessionClaims, error) {
+	token, found := strings.CutPrefix(token, models.PortalSessionTokenPrefix)
+	if !found {
+		return nil, models.ErrPortalSessionInvalid
+	}
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, models.ErrPortalSessionInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, models.ErrPortalSessionInvalid
+	}
+	var claims PortalSessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ConsentID == "" || claims.Email == "" {
+		return nil, models.ErrPortalSessionInvalid
+	}
+
+	if !s.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, models.ErrPortalSessionExpired
+	}
+	return &claims, nil
+}
+
+func (s *PortalSessionSigner) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("portal-session:" + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// EnablePortalSessions lets the service hand off portal sessions signed with the signer
+func (s *ConsentService) EnablePortalSessions(signer *PortalSessionSigner) {
+	s.sessionSigner = signer
+}
+
+// CreatePortalSession exchanges the signed-in citizen's identity for a session token scoped to one of
+// their pending consents. The session ends when the consent stops being pending, if that is sooner.
+func (s *ConsentService) CreatePortalSession(ctx context.Context, consentID, userEmail string) (*models.PortalSessionResponse, error) {
+	if s.sessionSigner == nil {
+		return nil, models.ErrPortalSessionsDisabled
+	}
+
+	consentRecord, err := s.getPendingConsent(ctx, consentID)
+	if err != nil {
+		return nil, err
+	}
+	if consentRecord.OwnerEmail != userEmail {
+		return nil, models.ErrConsentOwnerMismatch
+	}
+
+	token, expiresAt, err := s.sessionSigner.Sign(consentRecord.ConsentID.String(), userEmail, consentRecord.PendingExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrConsentGetFailed, err)
+	}
+
+	return &models.PortalSessionResponse{
+		ConsentID: consentRecord.ConsentID.String(),
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyPortalSession checks a portal session token presented for a consent and returns the email of
+// the citizen it was handed to
+func (s *ConsentService) VerifyPortalSession(token, consentID string) (string, error) {
+	if s.sessionSigner == nil {
+		return "", models.ErrPortalSessionsDisabled
+	}
+
+	claims, err := s.sessionSigner.Verify(token)
+	if err != nil {
+		return "", err
+	}
+	if claims.ConsentID != consentID {
+		return "", models.ErrPortalSessionScope
+	}
+	return claims.Email, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionSigner(t *testing.T) *PortalSessionSigner {
+	signer, err := NewPortalSessionSigner(testLinkSecret, time.Minute)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestNewPortalSessionSigner(t *testing.T) {
+	_, err := NewPortalSessionSigner("short", time.Minute)
+	assert.Error(t, err)
+
+	signer, err := NewPortalSessionSigner(testLinkSecret, 0)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultPortalSessionTTL, signer.ttl)
+}
+
+func TestPortalSessionSigner_Verify_Rejects(t *testing.T) {
+	signer := newTestSessionSigner(t)
+	token, _, err := signer.Sign(uuid.New().String(), "user@example.com", nil)
+	require.NoError(t, err)
+
+	// Consent link tokens share the secret but must not be accepted as sessions
+	linkToken, _, err := newTestLinkSigner(t).Sign(uuid.New().String(), "199012345678", nil)
+	require.NoError(t, err)
+
+	expired := newTestSessionSigner(t)
+	expired.now = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+	expiredToken, _, err := expired.Sign(uuid.New().String(), "user@example.com", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"missing prefix", strings.TrimPrefix(token, models.PortalSessionTokenPrefix), models.ErrPortalSessionInvalid},
+		{"tampered signature", token + "x", models.ErrPortalSessionInvalid},
+		{"consent link token", models.PortalSessionTokenPrefix + linkToken, models.ErrPortalSessionInvalid},
+		{"expired", expiredToken, models.ErrPortalSessionExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCreatePortalSession(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name             string
+		email            string
+		status           string
+		pendingExpiresAt *time.Time
+		wantErr          error
+	}{
+		{"success", "user@example.com", string(models.StatusPending), nil, nil},
+		{"other user", "other@example.com", string(models.StatusPending), nil, models.ErrConsentOwnerMismatch},
+		{"already approved", "user@example.com", string(models.StatusApproved), nil, models.ErrConsentNotPending},
+		{"pending timed out", "user@example.com", string(models.StatusPending), &expired, models.ErrConsentNotPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			service, _ := NewConsentService(db, "http://portal")
+			service.EnablePortalSessions(newTestSessionSigner(t))
+
+			id := uuid.New()
+			expectConsentLookup(mock, id, tt.status, tt.pendingExpiresAt)
+
+			session, err := service.CreatePortalSession(context.Background(), id.String(), tt.email)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, id.String(), session.ConsentID)
+			assert.True(t, strings.HasPrefix(session.Token, models.PortalSessionTokenPrefix))
+			assert.WithinDuration(t, time.Now().Add(time.Minute), session.ExpiresAt, 2*time.Second)
+
+			email, err := service.VerifyPortalSession(session.Token, id.String())
+			require.NoError(t, err)
+			assert.Equal(t, "user@example.com", email)
+
+			_, err = service.VerifyPortalSession(session.Token, uuid.New().String())
+			assert.ErrorIs(t, err, models.ErrPortalSessionScope)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreatePortalSession_ExpiresWithConsent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+	service.EnablePortalSessions(newTestSessionSigner(t))
+
+	id := uuid.New()
+	pendingExpiresAt := time.Now().Add(10 * time.Second)
+	expectConsentLookup(mock, id, string(models.StatusPending), &pendingExpiresAt)
+
+	session, err := service.CreatePortalSession(context.Background(), id.String(), "user@example.com")
+	require.NoError(t, err)
+	assert.WithinDuration(t, pendingExpiresAt, session.ExpiresAt, time.Second)
+}
+
+func TestPortalSession_Disabled(t *testing.T) {
+	db, _ := setupMockDB(t)
+	service, _ := NewConsentService(db, "http://portal")
+
+	_, err := service.CreatePortalSession(context.Background(), uuid.New().String(), "user@example.com")
+	assert.ErrorIs(t, err, models.ErrPortalSessionsDisabled)
+	_, err = service.VerifyPortalSession(models.PortalSessionTokenPrefix+"x.y", uuid.New().String())
+	assert.ErrorIs(t, err, models.ErrPortalSessionsDisabled)
+}
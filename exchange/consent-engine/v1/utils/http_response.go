@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/gov-dx-sandbox/exchange/consent-engine/v1/models"
+	"github.com/gov-dx-sandbox/shared/requestid"
 )
 
 // ErrorResponse represents a standardized error response
@@ -14,6 +15,8 @@ type ErrorResponse struct {
 		Code    string `json:"code"`
 		Message string `json:"message"`
 	} `json:"error"`
+	// RequestID identifies the request in the logs of every service it reached
+	RequestID string `json:"requestId,omitempty"`
 }
 
 // RespondWithJSON sends a JSON response with the given status code
@@ -35,6 +38,7 @@ func RespondWithError(w http.ResponseWriter, statusCode int, errorCode models.Co
 	response := ErrorResponse{}
 	response.Error.Code = string(errorCode)
 	response.Error.Message = message
+	response.RequestID = requestid.FromResponse(w)
 
 	RespondWithJSON(w, statusCode, response)
 }
@@ -0,0 +1,172 @@
+// Package activation schedules schema versions to be activated at a later time, so that schema
+// cutovers can happen during maintenance windows without anyone online. Before a version is
+// activated its contract tests are run; if any fails, the active schema is left as it is.
+package activation
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusScheduled is an activation waiting for its time
+	StatusScheduled = "scheduled"
+	// StatusRunning is an activation whose contract tests are running
+	StatusRunning = "running"
+	// StatusActivated is an activation whose version was activated
+	StatusActivated = "activated"
+	// StatusFailed is an activation that did not activate its version, because a contract test
+	// failed or the version could not be activated
+	StatusFailed = "failed"
+	// StatusCancelled is an activation cancelled before its time
+	StatusCancelled = "cancelled"
+)
+
+var (
+	// ErrNotFound is returned when an activation does not exist, or is no longer scheduled when
+	// cancelling it
+	ErrNotFound = errors.New("schema activation not found")
+	// ErrVersionNotFound is returned when scheduling the activation of an unknown schema version
+	ErrVersionNotFound = errors.New("schema version not found")
+	// ErrInvalidActivation is returned when an activation is not scheduled in the future
+	ErrInvalidActivation = errors.New("invalid schema activation")
+)
+
+// Activation is the scheduled activation of a schema version
+type Activation struct {
+	ID          int64     `json:"id"`
+	Version     string    `json:"version"`
+	ActivateAt  time.Time `json:"activateAt"`
+	Status      string    `json:"status"`
+	ScheduledBy string    `json:"scheduledBy,omitempty"`
+	// Results are the results of the contract tests run before the activation
+	Results []TestResult `json:"results,omitempty"`
+	// FailureReason explains why a failed activation did not activate its version
+	FailureReason string     `json:"failureReason,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+}
+
+// TestResult is the result of a contract test
+type TestResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// Store persists scheduled activations
+type Store interface {
+	Create(ctx context.Context, activation *Activation) (*Activation, error)
+	// List returns the activations with the given status, or all activations for an empty status,
+	// ordered by activation time
+	List(ctx context.Context, status string) ([]*Activation, error)
+	// Cancel cancels a scheduled activation, returning ErrNotFound if it is not scheduled
+	Cancel(ctx context.Context, id int64) error
+	// ClaimDue marks the scheduled activations due at the given time as running and returns them,
+	// ordered by activation time. An activation is claimed once, even by several replicas.
+	ClaimDue(ctx context.Context, now time.Time) ([]*Activation, error)
+	// Complete records the outcome of a claimed activation
+	Complete(ctx context.Context, activation *Activation) error
+}
+
+// MemoryStore keeps activations in memory
+type MemoryStore struct {
+	mu          sync.Mutex
+	nextID      int64
+	activations map[int64]*Activation
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{activations: make(map[int64]*Activation)}
+}
+
+// Create stores a new activation
+func (s *MemoryStore) Create(_ context.Context, activation *Activation) (*Activation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	stored := *activation
+	stored.ID = s.nextID
+	stored.CreatedAt = time.Now().UTC()
+	s.activations[stored.ID] = &stored
+	return copyActivation(&stored), nil
+}
+
+// List returns the activations with the given status, ordered by activation time
+func (s *MemoryStore) List(_ context.Context, status string) ([]*Activation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	activations := make([]*Activation, 0, len(s.activations))
+	for _, activation := range s.activations {
+		if status == "" || activation.Status == status {
+			activations = append(activations, copyActivation(activation))
+		}
+	}
+	sortActivations(activations)
+	return activations, nil
+}
+
+// Cancel cancels a scheduled activation
+func (s *MemoryStore) Cancel(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	activation, ok := s.activations[id]
+	if !ok || activation.Status != StatusScheduled {
+		return ErrNotFound
+	}
+	now := time.Now().UTC()
+	activation.Status = StatusCancelled
+	activation.CompletedAt = &now
+	return nil
+}
+
+// ClaimDue marks the scheduled activations due at the given time as running and returns them
+func (s *MemoryStore) ClaimDue(_ context.Context, now time.Time) ([]*Activation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*Activation
+	for _, activation := range s.activations {
+		if activation.Status == StatusScheduled && !activation.ActivateAt.After(now) {
+			activation.Status = StatusRunning
+			due = append(due, copyActivation(activation))
+		}
+	}
+	sortActivations(due)
+	return due, nil
+}
+
+// Complete records the outcome of a claimed activation
+func (s *MemoryStore) Complete(_ context.Context, activation *Activation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.activations[activation.ID]; !ok {
+		return ErrNotFound
+	}
+	s.activations[activation.ID] = copyActivation(activation)
+	return nil
+}
+
+func copyActivation(activation *Activation) *Activation {
+	copied := *activation
+	copied.Results = append([]TestResult(nil), activation.Results...)
+	if activation.CompletedAt != nil {
+		completedAt := *activation.CompletedAt
+		copied.CompletedAt = &completedAt
+	}
+	return &copied
+}
+
+// sortActivations orders activations by activation time, then by ID
+func sortActivations(activations []*Activation) {
+	sort.Slice(activations, func(i, j int) bool {
+		a, b := activations[i], activations[j]
+		if !a.ActivateAt.Equal(b.ActivateAt) {
+			return a.ActivateAt.Before(b.ActivateAt)
+		}
+		return a.ID < b.ID
+	})
+}
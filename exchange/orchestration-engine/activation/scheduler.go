@@ -0,0 +1,196 @@
+package activation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+const (
+	// DefaultCheckInterval is how often the scheduler looks for due activations
+	DefaultCheckInterval = 15 * time.Second
+	// runTimeout bounds the contract tests and activation of the due activations
+	runTimeout = time.Minute
+)
+
+// Schemas is the schema management the scheduler activates versions through
+type Schemas interface {
+	// VersionSDL returns the SDL of a schema version, or an error wrapping ErrVersionNotFound
+	VersionSDL(version string) (string, error)
+	// ActivateSchema atomically makes a version the active schema
+	ActivateSchema(version string) error
+}
+
+// ContractTest checks that a schema version can be activated
+type ContractTest struct {
+	Name string
+	// Run returns an error explaining why the version must not be activated
+	Run func(ctx context.Context, version, sdl string) error
+}
+
+// Scheduler activates schema versions at their scheduled time, once their contract tests pass
+type Scheduler struct {
+	store         Store
+	schemas       Schemas
+	tests         []ContractTest
+	checkInterval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewScheduler creates a scheduler for the activations of store; a non-positive interval uses
+// DefaultCheckInterval. Due activations are run by Start, or by RunDue.
+func NewScheduler(store Store, schemas Schemas, tests []ContractTest, checkInterval time.Duration) *Scheduler {
+	if checkInterval <= 0 {
+		checkInterval = DefaultCheckInterval
+	}
+	return &Scheduler{
+		store:         store,
+		schemas:       schemas,
+		tests:         tests,
+		checkInterval: checkInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Schedule schedules the activation of a version at a future time
+func (s *Scheduler) Schedule(ctx context.Context, version string, at time.Time, scheduledBy string) (*Activation, error) {
+	if version == "" {
+		return nil, fmt.Errorf("%w: version is required", ErrInvalidActivation)
+	}
+	if !at.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the activation time must be in the future", ErrInvalidActivation)
+	}
+	if _, err := s.schemas.VersionSDL(version); err != nil {
+		return nil, err
+	}
+
+	activation, err := s.store.Create(ctx, &Activation{
+		Version:     version,
+		ActivateAt:  at.UTC(),
+		Status:      StatusScheduled,
+		ScheduledBy: scheduledBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule schema activation: %w", err)
+	}
+	logger.Log.Info("Schema activation scheduled", "id", activation.ID, "version", version, "activateAt", activation.ActivateAt)
+	return activation, nil
+}
+
+// List returns the activations with the given status, or all activations for an empty status
+func (s *Scheduler) List(ctx context.Context, status string) ([]*Activation, error) {
+	switch status {
+	case "", StatusScheduled, StatusRunning, StatusActivated, StatusFailed, StatusCancelled:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidActivation, status)
+	}
+	return s.store.List(ctx, status)
+}
+
+// Cancel cancels a scheduled activation
+func (s *Scheduler) Cancel(ctx context.Context, id int64) error {
+	if err := s.store.Cancel(ctx, id); err != nil {
+		return err
+	}
+	logger.Log.Info("Schema activation cancelled", "id", id)
+	return nil
+}
+
+// Start checks for due activations in the background until Stop is called
+func (s *Scheduler) Start() {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+				s.RunDue(ctx, time.Now())
+				cancel()
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks started by Start, waiting for a running activation to finish
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+// RunDue runs the activations due at the given time, in the order they were scheduled for, and
+// returns them with their outcome
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) []*Activation {
+	due, err := s.store.ClaimDue(ctx, now)
+	if err != nil {
+		logger.Log.Error("Failed to get due schema activations", "error", err)
+		return nil
+	}
+	for _, activation := range due {
+		s.run(ctx, activation)
+		if err := s.store.Complete(ctx, activation); err != nil {
+			logger.Log.Error("Failed to record schema activation outcome", "id", activation.ID, "status", activation.Status, "error", err)
+		}
+	}
+	return due
+}
+
+// run runs the contract tests of an activation and activates its version if they all pass
+func (s *Scheduler) run(ctx context.Context, activation *Activation) {
+	defer func() {
+		completedAt := time.Now().UTC()
+		activation.CompletedAt = &completedAt
+	}()
+
+	fail := func(reason string) {
+		activation.Status = StatusFailed
+		activation.FailureReason = reason
+		logger.Log.Warn("Scheduled schema activation failed", "id", activation.ID, "version", activation.Version, "reason", reason)
+	}
+
+	sdl, err := s.schemas.VersionSDL(activation.Version)
+	if err != nil {
+		if errors.Is(err, ErrVersionNotFound) {
+			fail("schema version no longer exists")
+		} else {
+			fail(fmt.Sprintf("failed to load schema version: %v", err))
+		}
+		return
+	}
+
+	activation.Results = make([]TestResult, 0, len(s.tests))
+	failed := 0
+	for _, test := range s.tests {
+		result := TestResult{Name: test.Name, Passed: true}
+		if err := test.Run(ctx, activation.Version, sdl); err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+			failed++
+		}
+		activation.Results = append(activation.Results, result)
+	}
+	if failed > 0 {
+		fail(fmt.Sprintf("%d of %d contract tests failed", failed, len(s.tests)))
+		return
+	}
+
+	if err := s.schemas.ActivateSchema(activation.Version); err != nil {
+		fail(fmt.Sprintf("failed to activate schema version: %v", err))
+		return
+	}
+	activation.Status = StatusActivated
+	logger.Log.Info("Scheduled schema activation completed", "id", activation.ID, "version", activation.Version)
+}
@@ -0,0 +1,132 @@
+package activation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Initialize logger for tests
+	logger.Init()
+}
+
+// fakeSchemas keeps schema versions in memory
+type fakeSchemas struct {
+	sdl    map[string]string
+	active string
+}
+
+func (f *fakeSchemas) VersionSDL(version string) (string, error) {
+	sdl, ok := f.sdl[version]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrVersionNotFound, version)
+	}
+	return sdl, nil
+}
+
+func (f *fakeSchemas) ActivateSchema(version string) error {
+	f.active = version
+	return nil
+}
+
+func rejectSDL(rejected string) ContractTest {
+	return ContractTest{Name: "no-" + rejected, Run: func(_ context.Context, _, sdl string) error {
+		if sdl == rejected {
+			return errors.New("rejected SDL")
+		}
+		return nil
+	}}
+}
+
+func TestScheduler_ActivatesDueVersions(t *testing.T) {
+	ctx := context.Background()
+	schemas := &fakeSchemas{sdl: map[string]string{"1.0.0": "type A", "2.0.0": "type B"}, active: "1.0.0"}
+	scheduler := NewScheduler(NewMemoryStore(), schemas, []ContractTest{rejectSDL("broken")}, time.Hour)
+	at := time.Now().Add(time.Hour)
+
+	scheduled, err := scheduler.Schedule(ctx, "2.0.0", at, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, StatusScheduled, scheduled.Status)
+
+	assert.Empty(t, scheduler.RunDue(ctx, time.Now()), "activations are not run before their time")
+	assert.Equal(t, "1.0.0", schemas.active)
+
+	ran := scheduler.RunDue(ctx, at)
+	require.Len(t, ran, 1)
+	assert.Equal(t, StatusActivated, ran[0].Status)
+	assert.NotNil(t, ran[0].CompletedAt)
+	assert.Equal(t, []TestResult{{Name: "no-broken", Passed: true}}, ran[0].Results)
+	assert.Equal(t, "2.0.0", schemas.active)
+
+	assert.Empty(t, scheduler.RunDue(ctx, at), "activations are run once")
+	activations, err := scheduler.List(ctx, StatusActivated)
+	require.NoError(t, err)
+	require.Len(t, activations, 1)
+	assert.Equal(t, scheduled.ID, activations[0].ID)
+}
+
+func TestScheduler_FailedContractTestKeepsActiveSchema(t *testing.T) {
+	ctx := context.Background()
+	schemas := &fakeSchemas{sdl: map[string]string{"1.0.0": "type A", "2.0.0": "broken"}, active: "1.0.0"}
+	scheduler := NewScheduler(NewMemoryStore(), schemas, []ContractTest{rejectSDL("broken"), rejectSDL("other")}, time.Hour)
+	at := time.Now().Add(time.Minute)
+
+	_, err := scheduler.Schedule(ctx, "2.0.0", at, "")
+	require.NoError(t, err)
+	ran := scheduler.RunDue(ctx, at)
+
+	require.Len(t, ran, 1)
+	assert.Equal(t, StatusFailed, ran[0].Status)
+	assert.Equal(t, "1 of 2 contract tests failed", ran[0].FailureReason)
+	assert.Equal(t, []TestResult{
+		{Name: "no-broken", Passed: false, Message: "rejected SDL"},
+		{Name: "no-other", Passed: true},
+	}, ran[0].Results)
+	assert.Equal(t, "1.0.0", schemas.active)
+}
+
+func TestScheduler_ScheduleAndCancel(t *testing.T) {
+	ctx := context.Background()
+	schemas := &fakeSchemas{sdl: map[string]string{"2.0.0": "type B"}}
+	scheduler := NewScheduler(NewMemoryStore(), schemas, nil, 0)
+
+	_, err := scheduler.Schedule(ctx, "2.0.0", time.Now().Add(-time.Minute), "")
+	assert.True(t, errors.Is(err, ErrInvalidActivation), "activations must be in the future")
+	_, err = scheduler.Schedule(ctx, "3.0.0", time.Now().Add(time.Hour), "")
+	assert.True(t, errors.Is(err, ErrVersionNotFound))
+	_, err = scheduler.List(ctx, "pending")
+	assert.True(t, errors.Is(err, ErrInvalidActivation))
+
+	scheduled, err := scheduler.Schedule(ctx, "2.0.0", time.Now().Add(time.Hour), "")
+	require.NoError(t, err)
+	require.NoError(t, scheduler.Cancel(ctx, scheduled.ID))
+	assert.True(t, errors.Is(scheduler.Cancel(ctx, scheduled.ID), ErrNotFound), "only scheduled activations can be cancelled")
+
+	assert.Empty(t, scheduler.RunDue(ctx, time.Now().Add(2*time.Hour)))
+	assert.Empty(t, schemas.active)
+}
+
+func TestScheduler_StartAndStop(t *testing.T) {
+	schemas := &fakeSchemas{sdl: map[string]string{"2.0.0": "type B"}}
+	store := NewMemoryStore()
+	scheduler := NewScheduler(store, schemas, nil, 10*time.Millisecond)
+	_, err := store.Create(context.Background(), &Activation{Version: "2.0.0", ActivateAt: time.Now(), Status: StatusScheduled})
+	require.NoError(t, err)
+
+	scheduler.Start()
+	require.Eventually(t, func() bool {
+		activations, err := store.List(context.Background(), StatusActivated)
+		return err == nil && len(activations) == 1
+	}, time.Second, 10*time.Millisecond)
+	scheduler.Stop()
+	scheduler.Stop()
+
+	assert.Equal(t, "2.0.0", schemas.active)
+}
@@ -0,0 +1,85 @@
+// Package appcontext resolves the consumer application behind a token: its owning member, organization
+// and sector, and whether the token belongs to its sandbox or production credentials. Applications are
+// registered in the portal; their context is attached to the policy and audit context of their requests.
+package appcontext
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Statuses of an application
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+)
+
+// ErrNotRegistered is returned for applications the portal does not know
+var ErrNotRegistered = errors.New("application is not registered")
+
+// Application is the context of a consumer application, as returned by the portal
+type Application struct {
+	ApplicationID    string  `json:"applicationId"`
+	ApplicationName  string  `json:"applicationName"`
+	MemberID         string  `json:"memberId"`
+	MemberName       string  `json:"memberName"`
+	OrganizationID   *string `json:"organizationId,omitempty"`
+	OrganizationName *string `json:"organizationName,omitempty"`
+	Sector           *string `json:"sector,omitempty"`
+	// Environment is "sandbox" for tokens of the application's sandbox credentials, otherwise "production"
+	Environment  string  `json:"environment"`
+	Status       string  `json:"status"`
+	StatusReason *string `json:"statusReason,omitempty"`
+	// GrantExpiresAt is when the application's access to its selected fields expires
+	GrantExpiresAt *time.Time `json:"grantExpiresAt,omitempty"`
+}
+
+// Suspended reports whether the application's requests are rejected until it is reactivated
+func (a *Application) Suspended() bool {
+	return a.Status == StatusSuspended
+}
+
+// GrantExpired reports whether the application's access to its selected fields expired before now
+func (a *Application) GrantExpired(now time.Time) bool {
+	return a.GrantExpiresAt != nil && !now.Before(*a.GrantExpiresAt)
+}
+
+// Attributes returns the application's attributes for policy conditions and audit metadata, without the
+// ones it does not have
+func (a *Application) Attributes() map[string]interface{} {
+	attributes := map[string]interface{}{
+		"applicationName": a.ApplicationName,
+		"memberId":        a.MemberID,
+		"memberName":      a.MemberName,
+		"environment":     a.Environment,
+	}
+	if a.OrganizationID != nil {
+		attributes["organizationId"] = *a.OrganizationID
+	}
+	if a.OrganizationName != nil {
+		attributes["organizationName"] = *a.OrganizationName
+	}
+	if a.Sector != nil {
+		attributes["sector"] = *a.Sector
+	}
+	return attributes
+}
+
+// Source looks up the context of an application for the client ID of a token
+type Source interface {
+	GetApplication(ctx context.Context, applicationID, clientID string) (*Application, error)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the application
+func NewContext(ctx context.Context, app *Application) context.Context {
+	return context.WithValue(ctx, contextKey{}, app)
+}
+
+// FromContext returns the application carried by ctx, or nil if it has none
+func FromContext(ctx context.Context) *Application {
+	app, _ := ctx.Value(contextKey{}).(*Application)
+	return app
+}
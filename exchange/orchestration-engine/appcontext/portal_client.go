@@ -0,0 +1,109 @@
+package appcontext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
+	"github.com/gov-dx-sandbox/shared/requestid"
+)
+
+// DefaultCacheTTL is how long application contexts read from the portal are reused by default
+const DefaultCacheTTL = time.Minute
+
+// PortalClient reads application contexts from the portal backend and caches them, so that suspensions
+// take effect within the cache TTL. Expired entries are still served while the portal cannot be reached.
+type PortalClient struct {
+	baseURL    string
+	httpClient *http.Client
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedApplication
+}
+
+type cachedApplication struct {
+	// app is nil for applications the portal does not know
+	app       *Application
+	expiresAt time.Time
+}
+
+// NewPortalClient creates a client for the portal backend at baseURL
+func NewPortalClient(baseURL string, ttl time.Duration) *PortalClient {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &PortalClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		ttl:        ttl,
+		cache:      make(map[string]cachedApplication),
+	}
+}
+
+// GetApplication returns the context of an application for the client ID of a token, or
+// ErrNotRegistered if the portal does not know the application
+func (c *PortalClient) GetApplication(ctx context.Context, applicationID, clientID string) (*Application, error) {
+	key := applicationID + "\x00" + clientID
+	c.mu.Lock()
+	cached, ok := c.cache[key]
+	c.mu.Unlock()
+	if !ok || !time.Now().Before(cached.expiresAt) {
+		app, err := c.fetchApplication(ctx, applicationID, clientID)
+		if err != nil && !ok {
+			return nil, err
+		}
+		if err == nil {
+			cached = cachedApplication{app: app, expiresAt: time.Now().Add(c.ttl)}
+			c.mu.Lock()
+			c.cache[key] = cached
+			c.mu.Unlock()
+		}
+	}
+
+	if cached.app == nil {
+		return nil, ErrNotRegistered
+	}
+	return cached.app, nil
+}
+
+// fetchApplication returns the context of the application, or nil if the portal does not know it
+func (c *PortalClient) fetchApplication(ctx context.Context, applicationID, clientID string) (*Application, error) {
+	endpoint := fmt.Sprintf("%s/internal/api/v1/applications/%s/context", c.baseURL, url.PathEscape(applicationID))
+	if clientID != "" {
+		endpoint += "?idpClientId=" + url.QueryEscape(clientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if traceID := monitoring.GetTraceIDFromContext(ctx); traceID != "" {
+		req.Header.Set("X-Trace-ID", traceID)
+	}
+	requestid.SetHeader(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to get application context, status code: %d", resp.StatusCode)
+	}
+
+	var app Application
+	if err := json.NewDecoder(resp.Body).Decode(&app); err != nil {
+		return nil, fmt.Errorf("failed to decode application context: %w", err)
+	}
+	return &app, nil
+}
@@ -0,0 +1,79 @@
+package appcontext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortalClient(t *testing.T) {
+	requests := 0
+	down := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/internal/api/v1/applications/app-1/context":
+			environment := "production"
+			if r.URL.Query().Get("idpClientId") == "sandbox-client" {
+				environment = "sandbox"
+			}
+			_, _ = w.Write([]byte(`{"applicationId":"app-1","applicationName":"Passport App","memberId":"mem-1",` +
+				`"memberName":"Immigration Dept","sector":"government","environment":"` + environment + `",` +
+				`"status":"active","grantExpiresAt":"2030-01-01T00:00:00Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewPortalClient(server.URL, time.Hour)
+	ctx := context.Background()
+
+	app, err := client.GetApplication(ctx, "app-1", "prod-client")
+	require.NoError(t, err)
+	assert.Equal(t, "Passport App", app.ApplicationName)
+	assert.Equal(t, "production", app.Environment)
+	assert.Equal(t, "government", app.Attributes()["sector"])
+	assert.False(t, app.GrantExpired(time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, app.GrantExpired(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	app, err = client.GetApplication(ctx, "app-1", "sandbox-client")
+	require.NoError(t, err)
+	assert.Equal(t, "sandbox", app.Environment)
+
+	_, err = client.GetApplication(ctx, "app-2", "")
+	assert.ErrorIs(t, err, ErrNotRegistered)
+	_, err = client.GetApplication(ctx, "app-2", "")
+	assert.ErrorIs(t, err, ErrNotRegistered)
+	assert.Equal(t, 3, requests, "contexts, including unknown applications, are cached")
+
+	// Expired entries are served while the portal is down
+	for key, cached := range client.cache {
+		cached.expiresAt = time.Now()
+		client.cache[key] = cached
+	}
+	down = true
+	app, err = client.GetApplication(ctx, "app-1", "prod-client")
+	require.NoError(t, err)
+	assert.Equal(t, "app-1", app.ApplicationID)
+	_, err = client.GetApplication(ctx, "app-3", "")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotRegistered)
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+
+	app := &Application{ApplicationID: "app-1"}
+	assert.Same(t, app, FromContext(NewContext(ctx, app)))
+}
@@ -0,0 +1,120 @@
+// Package canary routes part of the query traffic to a candidate schema version while the rest is
+// answered with the active version, so that a new version can be tried on real traffic before it is
+// activated. The error rate of each version is tracked, and the canary is rolled back automatically
+// when the candidate's error rate breaches its threshold.
+package canary
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusRunning is a canary whose candidate version receives traffic
+	StatusRunning = "running"
+	// StatusStopped is a canary stopped through the admin API
+	StatusStopped = "stopped"
+	// StatusRolledBack is a canary stopped because the candidate's error rate breached its threshold
+	StatusRolledBack = "rolled_back"
+)
+
+var (
+	// ErrNotFound is returned when no canary is running
+	ErrNotFound = errors.New("schema canary not found")
+	// ErrAlreadyRunning is returned when starting a canary while another one is running
+	ErrAlreadyRunning = errors.New("a schema canary is already running")
+	// ErrInvalidCanary is returned when a canary is missing required fields or its candidate
+	// version cannot be served
+	ErrInvalidCanary = errors.New("invalid schema canary")
+)
+
+// Canary routes a share of the traffic, and all the traffic of some consumers, to a candidate schema
+// version
+type Canary struct {
+	ID      int64  `json:"id"`
+	Version string `json:"version"`
+	// Percentage of the queries (0 to 100) answered with the candidate version
+	Percentage float64 `json:"percentage"`
+	// ConsumerIDs are the applications whose queries are always answered with the candidate version
+	ConsumerIDs []string `json:"consumerIds,omitempty"`
+	// ErrorThreshold is the error rate (0 to 1) of the candidate's last MinRequests queries above
+	// which the canary is rolled back
+	ErrorThreshold float64 `json:"errorThreshold"`
+	MinRequests    int     `json:"minRequests"`
+	Status         string  `json:"status"`
+	StartedBy      string  `json:"startedBy,omitempty"`
+	// EndReason explains why a rolled back canary was stopped
+	EndReason string     `json:"endReason,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+// Store persists canaries
+type Store interface {
+	// Latest returns the most recently started canary, or nil if none was started
+	Latest(ctx context.Context) (*Canary, error)
+	// Create stores a new running canary, returning ErrAlreadyRunning while another one is running
+	Create(ctx context.Context, canary *Canary) (*Canary, error)
+	// End ends a running canary with the given status, returning ErrNotFound if it is not running
+	End(ctx context.Context, id int64, status, reason string) error
+}
+
+// MemoryStore keeps canaries in memory
+type MemoryStore struct {
+	mu       sync.Mutex
+	canaries []*Canary
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Latest returns the most recently started canary
+func (s *MemoryStore) Latest(_ context.Context) (*Canary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.canaries) == 0 {
+		return nil, nil
+	}
+	return copyCanary(s.canaries[len(s.canaries)-1]), nil
+}
+
+// Create stores a new running canary
+func (s *MemoryStore) Create(_ context.Context, canary *Canary) (*Canary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.canaries); n > 0 && s.canaries[n-1].Status == StatusRunning {
+		return nil, ErrAlreadyRunning
+	}
+	stored := copyCanary(canary)
+	stored.ID = int64(len(s.canaries) + 1)
+	stored.Status = StatusRunning
+	stored.CreatedAt = time.Now().UTC()
+	s.canaries = append(s.canaries, stored)
+	return copyCanary(stored), nil
+}
+
+// End ends a running canary
+func (s *MemoryStore) End(_ context.Context, id int64, status, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, canary := range s.canaries {
+		if canary.ID == id && canary.Status == StatusRunning {
+			endedAt := time.Now().UTC()
+			canary.Status = status
+			canary.EndReason = reason
+			canary.EndedAt = &endedAt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func copyCanary(canary *Canary) *Canary {
+	copied := *canary
+	copied.ConsumerIDs = append([]string(nil), canary.ConsumerIDs...)
+	return &copied
+}
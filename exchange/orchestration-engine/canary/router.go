@@ -0,0 +1,389 @@
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	// DefaultRefreshInterval is how long the running canary is used before it is reloaded from the
+	// store, so that canaries started, stopped or rolled back through other replicas take effect
+	DefaultRefreshInterval = 15 * time.Second
+	// DefaultErrorThreshold is the error rate above which a canary is rolled back, when it sets none
+	DefaultErrorThreshold = 0.05
+	// DefaultMinRequests is how many of the candidate's last queries its error rate is measured
+	// over, when the canary sets no number
+	DefaultMinRequests = 100
+	// storeTimeout bounds reloading the running canary and recording a rollback
+	storeTimeout = 10 * time.Second
+)
+
+// Schemas loads schema versions
+type Schemas interface {
+	// VersionSDL returns the SDL of a schema version
+	VersionSDL(version string) (string, error)
+}
+
+// Status is the latest canary and the error rates it measured on this replica
+type Status struct {
+	// Canary is the latest canary, nil if none was started
+	Canary    *Canary        `json:"canary"`
+	Active    VersionMetrics `json:"active"`
+	Candidate VersionMetrics `json:"candidate"`
+}
+
+// VersionMetrics counts the queries answered with a schema version during a canary
+type VersionMetrics struct {
+	Version   string  `json:"version,omitempty"`
+	Requests  int64   `json:"requests"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+}
+
+func (m *VersionMetrics) record(failed bool) {
+	m.Requests++
+	if failed {
+		m.Errors++
+	}
+	m.ErrorRate = float64(m.Errors) / float64(m.Requests)
+}
+
+// metrics are the outcomes of the queries answered during a canary
+type metrics struct {
+	canaryID  int64
+	active    VersionMetrics
+	candidate VersionMetrics
+	// recent holds the outcomes of the candidate's last queries, true for errors, that its error rate
+	// is checked against the threshold over
+	recent       []bool
+	next         int
+	recentErrors int
+}
+
+func newMetrics(canary *Canary) *metrics {
+	return &metrics{
+		canaryID:  canary.ID,
+		candidate: VersionMetrics{Version: canary.Version},
+		recent:    make([]bool, 0, canary.MinRequests),
+	}
+}
+
+// recordCandidate records the outcome of a query answered with the candidate and returns its error
+// rate over the last MinRequests queries, or false until it answered that many
+func (m *metrics) recordCandidate(failed bool) (float64, bool) {
+	m.candidate.record(failed)
+	if len(m.recent) < cap(m.recent) {
+		m.recent = append(m.recent, failed)
+	} else {
+		if m.recent[m.next] {
+			m.recentErrors--
+		}
+		m.recent[m.next] = failed
+		m.next = (m.next + 1) % len(m.recent)
+	}
+	if failed {
+		m.recentErrors++
+	}
+	if len(m.recent) < cap(m.recent) {
+		return 0, false
+	}
+	return float64(m.recentErrors) / float64(len(m.recent)), true
+}
+
+// Router picks the schema version each query is answered with. The running canary is cached in memory,
+// so routing a query does not query the store. Error rates are measured by each replica over the
+// queries it answered, and a replica that sees the candidate breach its threshold rolls the canary back
+// for all of them.
+type Router struct {
+	store           Store
+	schemas         Schemas
+	refreshInterval time.Duration
+	// random returns a number in [0, 1) deciding whether a query is routed to the candidate
+	random func() float64
+
+	mu         sync.Mutex
+	canary     *Canary
+	loadedAt   time.Time
+	refreshing bool
+	metrics    *metrics
+	// schemaVersion is the version schema was parsed from
+	schemaVersion string
+	schema        *ast.Document
+}
+
+// NewRouter creates a router for the canaries of store; non-positive intervals use
+// DefaultRefreshInterval. The running canary is loaded on the first call to Route, or by Refresh.
+func NewRouter(store Store, schemas Schemas, refreshInterval time.Duration) *Router {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &Router{store: store, schemas: schemas, refreshInterval: refreshInterval, random: rand.Float64}
+}
+
+// Refresh reloads the running canary from the store
+func (r *Router) Refresh(ctx context.Context) error {
+	latest, err := r.store.Latest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load schema canary: %w", err)
+	}
+	if latest != nil && latest.Status != StatusRunning {
+		latest = nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setCanary(latest)
+	r.loadedAt = time.Now()
+	return nil
+}
+
+// setCanary switches to the running canary, starting its metrics if it is a new one; the caller holds mu
+func (r *Router) setCanary(canary *Canary) {
+	r.canary = canary
+	if canary != nil && (r.metrics == nil || r.metrics.canaryID != canary.ID) {
+		r.metrics = newMetrics(canary)
+	}
+}
+
+// Route returns the candidate version if the consumer's query is to be answered with it, or "" for
+// the active version. A nil router routes every query to the active version. A stale canary is
+// reloaded in the background, so a query is never held up by the store.
+func (r *Router) Route(consumerID string) string {
+	if r == nil {
+		return ""
+	}
+	r.mu.Lock()
+	canary := r.canary
+	stale := time.Since(r.loadedAt) >= r.refreshInterval
+	r.mu.Unlock()
+	if stale {
+		r.refreshInBackground()
+	}
+
+	if canary == nil {
+		return ""
+	}
+	if slices.Contains(canary.ConsumerIDs, consumerID) || r.random()*100 < canary.Percentage {
+		return canary.Version
+	}
+	return ""
+}
+
+// refreshInBackground starts a reload of the running canary unless one is already running
+func (r *Router) refreshInBackground() {
+	r.mu.Lock()
+	if r.refreshing {
+		r.mu.Unlock()
+		return
+	}
+	r.refreshing = true
+	r.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+		if err := r.Refresh(ctx); err != nil {
+			logger.Log.Warn("Failed to refresh schema canary, using the previous canary", "error", err)
+			// Wait for the next interval before retrying
+			r.mu.Lock()
+			r.loadedAt = time.Now()
+			r.mu.Unlock()
+		}
+		r.mu.Lock()
+		r.refreshing = false
+		r.mu.Unlock()
+	}()
+}
+
+// Schema returns the parsed SDL of the candidate version
+func (r *Router) Schema(version string) (*ast.Document, error) {
+	r.mu.Lock()
+	if r.schemaVersion == version && r.schema != nil {
+		schema := r.schema
+		r.mu.Unlock()
+		return schema, nil
+	}
+	r.mu.Unlock()
+
+	schema, err := r.loadSchema(version)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.schemaVersion = version
+	r.schema = schema
+	r.mu.Unlock()
+	return schema, nil
+}
+
+func (r *Router) loadSchema(version string) (*ast.Document, error) {
+	sdl, err := r.schemas.VersionSDL(version)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(sdl),
+		Name: "CandidateSchema",
+	})})
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse the SDL of version %s: %v", ErrInvalidCanary, version, err)
+	}
+	return schema, nil
+}
+
+// Record records the outcome of a query answered with the given version ("" for the active version),
+// rolling the canary back once the candidate's error rate over its last MinRequests queries exceeds
+// the canary's threshold
+func (r *Router) Record(version string, failed bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	canary := r.canary
+	if canary == nil {
+		r.mu.Unlock()
+		return
+	}
+	if version == "" {
+		r.metrics.active.record(failed)
+		r.mu.Unlock()
+		return
+	}
+	if version != canary.Version {
+		// The query was routed by a canary that has since ended
+		r.mu.Unlock()
+		return
+	}
+	errorRate, measured := r.metrics.recordCandidate(failed)
+	breached := measured && errorRate > canary.ErrorThreshold
+	if breached {
+		// Stop routing queries to the candidate before the rollback is stored
+		r.canary = nil
+	}
+	r.mu.Unlock()
+
+	if breached {
+		r.rollback(canary, errorRate)
+	}
+}
+
+// rollback ends a canary whose candidate breached its error threshold
+func (r *Router) rollback(canary *Canary, errorRate float64) {
+	reason := fmt.Sprintf("error rate %.1f%% over the last %d queries exceeded the threshold of %.1f%%",
+		errorRate*100, canary.MinRequests, canary.ErrorThreshold*100)
+	logger.Log.Warn("Schema canary rolled back", "id", canary.ID, "version", canary.Version, "reason", reason)
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := r.store.End(ctx, canary.ID, StatusRolledBack, reason); err != nil && !errors.Is(err, ErrNotFound) {
+		logger.Log.Error("Failed to record the schema canary rollback", "id", canary.ID, "error", err)
+	}
+}
+
+// Start starts routing traffic to a candidate version. It takes effect immediately on this replica,
+// and on the others once they refresh the running canary.
+func (r *Router) Start(ctx context.Context, canary *Canary) (*Canary, error) {
+	canary.Version = strings.TrimSpace(canary.Version)
+	if canary.Version == "" {
+		return nil, fmt.Errorf("%w: version is required", ErrInvalidCanary)
+	}
+	if canary.Percentage < 0 || canary.Percentage > 100 {
+		return nil, fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidCanary)
+	}
+	consumerIDs := make([]string, 0, len(canary.ConsumerIDs))
+	for _, consumerID := range canary.ConsumerIDs {
+		if consumerID = strings.TrimSpace(consumerID); consumerID != "" {
+			consumerIDs = append(consumerIDs, consumerID)
+		}
+	}
+	canary.ConsumerIDs = consumerIDs
+	if canary.Percentage == 0 && len(canary.ConsumerIDs) == 0 {
+		return nil, fmt.Errorf("%w: a percentage or consumer IDs are required", ErrInvalidCanary)
+	}
+	if canary.ErrorThreshold == 0 {
+		canary.ErrorThreshold = DefaultErrorThreshold
+	}
+	if canary.ErrorThreshold < 0 || canary.ErrorThreshold > 1 {
+		return nil, fmt.Errorf("%w: errorThreshold must be between 0 and 1", ErrInvalidCanary)
+	}
+	if canary.MinRequests == 0 {
+		canary.MinRequests = DefaultMinRequests
+	}
+	if canary.MinRequests < 0 {
+		return nil, fmt.Errorf("%w: minRequests must be positive", ErrInvalidCanary)
+	}
+
+	// A candidate that cannot be served would fail every query routed to it
+	schema, err := r.loadSchema(canary.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := r.store.Create(ctx, canary)
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Schema canary started", "id", stored.ID, "version", stored.Version,
+		"percentage", stored.Percentage, "consumerIds", stored.ConsumerIDs, "errorThreshold", stored.ErrorThreshold)
+
+	r.mu.Lock()
+	r.setCanary(stored)
+	r.schemaVersion = stored.Version
+	r.schema = schema
+	r.mu.Unlock()
+	return stored, nil
+}
+
+// Stop stops the running canary, routing all traffic back to the active version
+func (r *Router) Stop(ctx context.Context) (*Canary, error) {
+	latest, err := r.store.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil || latest.Status != StatusRunning {
+		return nil, ErrNotFound
+	}
+	if err := r.store.End(ctx, latest.ID, StatusStopped, ""); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Schema canary stopped", "id", latest.ID, "version", latest.Version)
+
+	r.mu.Lock()
+	if r.canary != nil && r.canary.ID == latest.ID {
+		r.canary = nil
+	}
+	r.mu.Unlock()
+	return r.store.Latest(ctx)
+}
+
+// Status returns the latest canary with the metrics this replica measured for it
+func (r *Router) Status(ctx context.Context) (*Status, error) {
+	latest, err := r.store.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{Canary: latest}
+	if latest == nil {
+		return status, nil
+	}
+	status.Candidate.Version = latest.Version
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.metrics != nil && r.metrics.canaryID == latest.ID {
+		status.Active = r.metrics.active
+		status.Candidate = r.metrics.candidate
+	}
+	return status, nil
+}
@@ -0,0 +1,152 @@
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Initialize logger for tests
+	logger.Init()
+}
+
+var errVersionNotFound = errors.New("schema version not found")
+
+// testSchemas serves a valid and an unparsable schema version
+type testSchemas struct{}
+
+func (testSchemas) VersionSDL(version string) (string, error) {
+	switch version {
+	case "2.0.0":
+		return "type Query { name: String }", nil
+	case "broken":
+		return "type Query {", nil
+	}
+	return "", fmt.Errorf("%w: %s", errVersionNotFound, version)
+}
+
+// newTestRouter creates a router whose random numbers are fixed to value
+func newTestRouter(store Store, value float64) *Router {
+	router := NewRouter(store, testSchemas{}, time.Hour)
+	router.random = func() float64 { return value }
+	return router
+}
+
+func TestRouter_Route(t *testing.T) {
+	ctx := context.Background()
+	router := newTestRouter(NewMemoryStore(), 0.5)
+	require.NoError(t, router.Refresh(ctx))
+	assert.Equal(t, "", router.Route("app-1"), "without a canary queries use the active version")
+
+	_, err := router.Start(ctx, &Canary{Version: "2.0.0", Percentage: 40, ConsumerIDs: []string{" app-beta "}})
+	require.NoError(t, err)
+	assert.Equal(t, "", router.Route("app-1"), "0.5 is outside the 40% routed to the candidate")
+	assert.Equal(t, "2.0.0", router.Route("app-beta"), "listed consumers always use the candidate")
+
+	router.random = func() float64 { return 0.39 }
+	assert.Equal(t, "2.0.0", router.Route("app-1"))
+
+	schema, err := router.Schema("2.0.0")
+	require.NoError(t, err)
+	assert.NotEmpty(t, schema.Definitions)
+
+	var nilRouter *Router
+	assert.Equal(t, "", nilRouter.Route("app-beta"))
+	nilRouter.Record("", true)
+}
+
+func TestRouter_StartValidation(t *testing.T) {
+	ctx := context.Background()
+	router := newTestRouter(NewMemoryStore(), 0)
+
+	invalid := map[string]*Canary{
+		"no version":          {Percentage: 10},
+		"percentage too high": {Version: "2.0.0", Percentage: 101},
+		"nothing routed":      {Version: "2.0.0", ConsumerIDs: []string{" "}},
+		"threshold too high":  {Version: "2.0.0", Percentage: 10, ErrorThreshold: 1.5},
+		"negative requests":   {Version: "2.0.0", Percentage: 10, MinRequests: -1},
+		"unparsable version":  {Version: "broken", Percentage: 10},
+	}
+	for name, canary := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := router.Start(ctx, canary)
+			assert.ErrorIs(t, err, ErrInvalidCanary)
+		})
+	}
+
+	_, err := router.Start(ctx, &Canary{Version: "3.0.0", Percentage: 10})
+	assert.ErrorIs(t, err, errVersionNotFound)
+
+	started, err := router.Start(ctx, &Canary{Version: "2.0.0", Percentage: 10})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultErrorThreshold, started.ErrorThreshold)
+	assert.Equal(t, DefaultMinRequests, started.MinRequests)
+	assert.Equal(t, StatusRunning, started.Status)
+
+	_, err = router.Start(ctx, &Canary{Version: "2.0.0", Percentage: 10})
+	assert.ErrorIs(t, err, ErrAlreadyRunning)
+}
+
+func TestRouter_RollsBackWhenErrorRateBreachesThreshold(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	router := newTestRouter(store, 0)
+	replica := newTestRouter(store, 0)
+	require.NoError(t, router.Refresh(ctx))
+
+	_, err := router.Start(ctx, &Canary{Version: "2.0.0", Percentage: 100, ErrorThreshold: 0.25, MinRequests: 4})
+	require.NoError(t, err)
+	require.NoError(t, replica.Refresh(ctx))
+	assert.Equal(t, "2.0.0", replica.Route("app-1"))
+
+	router.Record("", true)
+	router.Record("", false)
+	// One error in the last four queries is within the threshold
+	for _, failed := range []bool{false, true, false, false} {
+		router.Record("2.0.0", failed)
+	}
+	assert.Equal(t, "2.0.0", router.Route("app-1"))
+	router.Record("2.0.0", true)
+	assert.Equal(t, "", router.Route("app-1"), "two errors in the last four queries breach the threshold")
+
+	status, err := router.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRolledBack, status.Canary.Status)
+	assert.Contains(t, status.Canary.EndReason, "50.0% over the last 4 queries")
+	assert.Equal(t, VersionMetrics{Requests: 2, Errors: 1, ErrorRate: 0.5}, status.Active)
+	assert.Equal(t, VersionMetrics{Version: "2.0.0", Requests: 5, Errors: 2, ErrorRate: 0.4}, status.Candidate)
+
+	require.NoError(t, replica.Refresh(ctx))
+	assert.Equal(t, "", replica.Route("app-1"), "other replicas stop routing once they refresh")
+}
+
+func TestRouter_Stop(t *testing.T) {
+	ctx := context.Background()
+	router := newTestRouter(NewMemoryStore(), 0)
+	require.NoError(t, router.Refresh(ctx))
+
+	_, err := router.Stop(ctx)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	started, err := router.Start(ctx, &Canary{Version: "2.0.0", ConsumerIDs: []string{"app-beta"}})
+	require.NoError(t, err)
+	stopped, err := router.Stop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, started.ID, stopped.ID)
+	assert.Equal(t, StatusStopped, stopped.Status)
+	assert.NotNil(t, stopped.EndedAt)
+	assert.Equal(t, "", router.Route("app-beta"))
+
+	// Outcomes of queries routed before the canary ended are ignored
+	router.Record("2.0.0", true)
+	status, err := router.Status(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, status.Candidate.Requests)
+}
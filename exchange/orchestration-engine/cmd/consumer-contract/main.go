@@ -0,0 +1,103 @@
+// Command consumer-contract writes the contract of a consumer application from a schema file: the
+// fields the PDP allows the application to read, as contract.ts, contract.go and examples.graphql.
+//
+//	consumer-contract -schema schema.graphql -pdp-url http://localhost:8082 -application passport-app -out ./client
+//
+// Without -pdp-url every field of the schema is in the contract. Applications can fetch the same
+// artifacts for the active schema from the orchestration engine's /public/contract endpoint.
+package main
+
+import (
+	"context"
+	"flag"
+	"go/token"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/contract"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "schema.graphql", "Unified schema SDL file")
+	pdpURL := flag.String("pdp-url", os.Getenv("PDP_URL"), "Base URL of the PDP (default: every field is in the contract)")
+	applicationID := flag.String("application", "", "Application to generate the contract of")
+	outDir := flag.String("out", ".", "Directory the artifacts are written to")
+	goPackage := flag.String("package", contract.DefaultGoPackage, "Package of the generated Go file")
+	flag.Parse()
+
+	if *applicationID == "" {
+		slog.Error("-application is required")
+		os.Exit(2)
+	}
+	if !token.IsIdentifier(*goPackage) {
+		slog.Error("-package must be a Go identifier", "package", *goPackage)
+		os.Exit(2)
+	}
+
+	sdl, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		slog.Error("Failed to read schema", "error", err)
+		os.Exit(1)
+	}
+	schema, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: sdl, Name: *schemaPath})})
+	if err != nil {
+		slog.Error("Failed to parse schema", "error", err)
+		os.Exit(1)
+	}
+	fields := contract.Fields(schema)
+
+	var decision *policy.PdpResponse
+	if *pdpURL == "" {
+		slog.Warn("No PDP URL given, every field is in the contract")
+	} else {
+		requiredFields := make([]policy.RequiredField, 0, len(fields))
+		for _, field := range fields {
+			requiredFields = append(requiredFields, policy.RequiredField{SchemaID: field.SchemaID, FieldName: field.FieldName})
+		}
+		decision, err = policy.NewPdpClient(*pdpURL).MakePdpRequest(context.Background(), &policy.PdpRequest{
+			AppId:          *applicationID,
+			RequiredFields: requiredFields,
+			Context: map[string]interface{}{
+				"consumer.applicationId": *applicationID,
+				"request.time":           time.Now().Format(time.RFC3339),
+			},
+		})
+		if err != nil || decision == nil {
+			slog.Error("PDP request failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	generated, err := contract.Build(schema, *applicationID, contract.Classify(fields, decision), contract.Options{GoPackage: *goPackage})
+	if err != nil {
+		slog.Error("Failed to generate contract", "error", err)
+		os.Exit(1)
+	}
+
+	queries := make([]string, 0, len(generated.Queries))
+	for _, query := range generated.Queries {
+		queries = append(queries, query.Query)
+	}
+	artifacts := map[string]string{
+		"contract.ts":      generated.TypeScript,
+		"contract.go":      generated.Go,
+		"examples.graphql": strings.Join(queries, "\n"),
+	}
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		slog.Error("Failed to create output directory", "error", err)
+		os.Exit(1)
+	}
+	for name, content := range artifacts {
+		if err := os.WriteFile(filepath.Join(*outDir, name), []byte(content), 0o644); err != nil {
+			slog.Error("Failed to write artifact", "file", name, "error", err)
+			os.Exit(1)
+		}
+	}
+	slog.Info("Wrote consumer contract", "application", *applicationID, "fields", len(generated.Fields), "out", *outDir)
+}
@@ -0,0 +1,97 @@
+// Package codes normalizes provider-specific codes, such as the vehicle class codes of each
+// department, to the codes of the unified schema. Mappings are kept in a store managed through the
+// admin API and applied to fields of the unified schema marked with @codeList.
+package codes
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a code mapping does not exist
+	ErrNotFound = errors.New("code mapping not found")
+	// ErrInvalidMapping is returned when a code mapping is missing required fields
+	ErrInvalidMapping = errors.New("invalid code mapping")
+)
+
+// Mapping maps a provider's code of a code list to the unified code
+type Mapping struct {
+	ProviderKey  string    `json:"providerKey"`
+	CodeList     string    `json:"codeList"`
+	ProviderCode string    `json:"providerCode"`
+	UnifiedCode  string    `json:"unifiedCode"`
+	Description  string    `json:"description,omitempty"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Store persists code mappings
+type Store interface {
+	List(ctx context.Context) ([]*Mapping, error)
+	Upsert(ctx context.Context, mapping *Mapping) (*Mapping, error)
+	Delete(ctx context.Context, providerKey, codeList, providerCode string) error
+}
+
+// key identifies the mapping of a provider code
+type key struct {
+	providerKey, codeList, providerCode string
+}
+
+// MemoryStore keeps code mappings in a map
+type MemoryStore struct {
+	mu       sync.Mutex
+	mappings map[key]*Mapping
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{mappings: make(map[key]*Mapping)}
+}
+
+// List returns all mappings ordered by provider, code list and provider code
+func (s *MemoryStore) List(_ context.Context) ([]*Mapping, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mappings := make([]*Mapping, 0, len(s.mappings))
+	for _, mapping := range s.mappings {
+		copied := *mapping
+		mappings = append(mappings, &copied)
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		a, b := mappings[i], mappings[j]
+		if a.ProviderKey != b.ProviderKey {
+			return a.ProviderKey < b.ProviderKey
+		}
+		if a.CodeList != b.CodeList {
+			return a.CodeList < b.CodeList
+		}
+		return a.ProviderCode < b.ProviderCode
+	})
+	return mappings, nil
+}
+
+// Upsert creates or replaces the mapping of a provider code
+func (s *MemoryStore) Upsert(_ context.Context, mapping *Mapping) (*Mapping, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *mapping
+	stored.UpdatedAt = time.Now().UTC()
+	s.mappings[key{mapping.ProviderKey, mapping.CodeList, mapping.ProviderCode}] = &stored
+	copied := stored
+	return &copied, nil
+}
+
+// Delete removes the mapping of a provider code
+func (s *MemoryStore) Delete(_ context.Context, providerKey, codeList, providerCode string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := key{providerKey, codeList, providerCode}
+	if _, ok := s.mappings[k]; !ok {
+		return ErrNotFound
+	}
+	delete(s.mappings, k)
+	return nil
+}
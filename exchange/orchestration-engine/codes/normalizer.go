@@ -0,0 +1,171 @@
+package codes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+const (
+	// DefaultRefreshInterval is how long the mappings are used before they are reloaded from the
+	// store, so that changes made through other replicas take effect
+	DefaultRefreshInterval = time.Minute
+	// refreshTimeout bounds a reload of the mappings
+	refreshTimeout = 10 * time.Second
+)
+
+// Normalizer translates provider codes with the mappings of a store. The mappings are cached in
+// memory, so normalizing a response does not query the store.
+type Normalizer struct {
+	store           Store
+	refreshInterval time.Duration
+
+	mu         sync.RWMutex
+	table      map[key]string
+	loadedAt   time.Time
+	refreshing bool
+}
+
+// NewNormalizer creates a normalizer for the mappings of store; non-positive intervals use
+// DefaultRefreshInterval. The mappings are loaded on the first call to Normalize, or by Refresh.
+func NewNormalizer(store Store, refreshInterval time.Duration) *Normalizer {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &Normalizer{store: store, refreshInterval: refreshInterval, table: make(map[key]string)}
+}
+
+// Refresh reloads the mappings from the store
+func (n *Normalizer) Refresh(ctx context.Context) error {
+	mappings, err := n.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load code mappings: %w", err)
+	}
+	table := make(map[key]string, len(mappings))
+	for _, mapping := range mappings {
+		table[key{mapping.ProviderKey, mapping.CodeList, mapping.ProviderCode}] = mapping.UnifiedCode
+	}
+
+	n.mu.Lock()
+	n.table = table
+	n.loadedAt = time.Now()
+	n.mu.Unlock()
+	return nil
+}
+
+// Normalize returns the unified code of a provider code. Strings without a mapping are returned
+// unchanged, and arrays are normalized element by element. Stale mappings are reloaded in the
+// background, so a response is never held up by the store.
+func (n *Normalizer) Normalize(providerKey, codeList string, value interface{}) interface{} {
+	n.mu.RLock()
+	table := n.table
+	stale := time.Since(n.loadedAt) >= n.refreshInterval
+	n.mu.RUnlock()
+	if stale {
+		n.refreshInBackground()
+	}
+	return normalize(table, providerKey, codeList, value)
+}
+
+func normalize(table map[key]string, providerKey, codeList string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if unified, ok := table[key{providerKey, codeList, v}]; ok {
+			return unified
+		}
+		return v
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalize(table, providerKey, codeList, item)
+		}
+		return normalized
+	}
+	return value
+}
+
+// refreshInBackground starts a reload of the mappings unless one is already running
+func (n *Normalizer) refreshInBackground() {
+	n.mu.Lock()
+	if n.refreshing {
+		n.mu.Unlock()
+		return
+	}
+	n.refreshing = true
+	n.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		if err := n.Refresh(ctx); err != nil {
+			logger.Log.Warn("Failed to refresh code mappings, using the previous mappings", "error", err)
+			// Wait for the next interval before retrying
+			n.mu.Lock()
+			n.loadedAt = time.Now()
+			n.mu.Unlock()
+		}
+		n.mu.Lock()
+		n.refreshing = false
+		n.mu.Unlock()
+	}()
+}
+
+// List returns the mappings of the store, optionally only those of a provider and code list
+func (n *Normalizer) List(ctx context.Context, providerKey, codeList string) ([]*Mapping, error) {
+	mappings, err := n.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*Mapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		if (providerKey == "" || mapping.ProviderKey == providerKey) && (codeList == "" || mapping.CodeList == codeList) {
+			filtered = append(filtered, mapping)
+		}
+	}
+	return filtered, nil
+}
+
+// Put creates or replaces the mapping of a provider code. It takes effect immediately on this
+// replica, and on the others once they refresh their mappings.
+func (n *Normalizer) Put(ctx context.Context, mapping *Mapping) (*Mapping, error) {
+	mapping.ProviderKey = strings.TrimSpace(mapping.ProviderKey)
+	mapping.CodeList = strings.TrimSpace(mapping.CodeList)
+	if mapping.ProviderKey == "" || mapping.CodeList == "" || mapping.ProviderCode == "" || mapping.UnifiedCode == "" {
+		return nil, fmt.Errorf("%w: providerKey, codeList, providerCode and unifiedCode are required", ErrInvalidMapping)
+	}
+	stored, err := n.store.Upsert(ctx, mapping)
+	if err != nil {
+		return nil, err
+	}
+	n.set(key{stored.ProviderKey, stored.CodeList, stored.ProviderCode}, &stored.UnifiedCode)
+	return stored, nil
+}
+
+// Delete removes the mapping of a provider code
+func (n *Normalizer) Delete(ctx context.Context, providerKey, codeList, providerCode string) error {
+	if err := n.store.Delete(ctx, providerKey, codeList, providerCode); err != nil {
+		return err
+	}
+	n.set(key{providerKey, codeList, providerCode}, nil)
+	return nil
+}
+
+// set updates a mapping of the cached table, which is copied as Normalize may still be reading it
+func (n *Normalizer) set(k key, unifiedCode *string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	table := make(map[key]string, len(n.table)+1)
+	for existing, code := range n.table {
+		table[existing] = code
+	}
+	if unifiedCode != nil {
+		table[k] = *unifiedCode
+	} else {
+		delete(table, k)
+	}
+	n.table = table
+}
@@ -0,0 +1,103 @@
+package codes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNormalizer(t *testing.T, mappings ...*Mapping) *Normalizer {
+	store := NewMemoryStore()
+	for _, mapping := range mappings {
+		_, err := store.Upsert(context.Background(), mapping)
+		require.NoError(t, err)
+	}
+	normalizer := NewNormalizer(store, time.Hour)
+	require.NoError(t, normalizer.Refresh(context.Background()))
+	return normalizer
+}
+
+func TestNormalizer_Normalize(t *testing.T) {
+	normalizer := newTestNormalizer(t,
+		&Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1", UnifiedCode: "LIGHT_MOTOR_VEHICLE"},
+		&Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "G", UnifiedCode: "HEAVY_GOODS_VEHICLE"},
+	)
+
+	assert.Equal(t, "LIGHT_MOTOR_VEHICLE", normalizer.Normalize("dmt", "vehicleClass", "B1"))
+	assert.Equal(t, "A", normalizer.Normalize("dmt", "vehicleClass", "A"), "unmapped codes are kept")
+	assert.Equal(t, "B1", normalizer.Normalize("rgd", "vehicleClass", "B1"), "mappings are per provider")
+	assert.Equal(t, 42, normalizer.Normalize("dmt", "vehicleClass", 42))
+	assert.Nil(t, normalizer.Normalize("dmt", "vehicleClass", nil))
+	assert.Equal(t,
+		[]interface{}{"LIGHT_MOTOR_VEHICLE", "HEAVY_GOODS_VEHICLE", "A"},
+		normalizer.Normalize("dmt", "vehicleClass", []interface{}{"B1", "G", "A"}))
+}
+
+func TestNormalizer_PutAndDeleteUpdateMappings(t *testing.T) {
+	ctx := context.Background()
+	normalizer := newTestNormalizer(t)
+
+	stored, err := normalizer.Put(ctx, &Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1", UnifiedCode: "LIGHT_MOTOR_VEHICLE"})
+	require.NoError(t, err)
+	assert.False(t, stored.UpdatedAt.IsZero())
+	assert.Equal(t, "LIGHT_MOTOR_VEHICLE", normalizer.Normalize("dmt", "vehicleClass", "B1"))
+
+	_, err = normalizer.Put(ctx, &Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1", UnifiedCode: "MOTOR_CAR"})
+	require.NoError(t, err)
+	assert.Equal(t, "MOTOR_CAR", normalizer.Normalize("dmt", "vehicleClass", "B1"))
+
+	require.NoError(t, normalizer.Delete(ctx, "dmt", "vehicleClass", "B1"))
+	assert.Equal(t, "B1", normalizer.Normalize("dmt", "vehicleClass", "B1"))
+
+	err = normalizer.Delete(ctx, "dmt", "vehicleClass", "B1")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestNormalizer_PutRequiresFields(t *testing.T) {
+	normalizer := newTestNormalizer(t)
+
+	_, err := normalizer.Put(context.Background(), &Mapping{ProviderKey: "dmt", CodeList: " ", ProviderCode: "B1", UnifiedCode: "MOTOR_CAR"})
+	assert.True(t, errors.Is(err, ErrInvalidMapping))
+
+	_, err = normalizer.Put(context.Background(), &Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1"})
+	assert.True(t, errors.Is(err, ErrInvalidMapping))
+}
+
+func TestNormalizer_List(t *testing.T) {
+	normalizer := newTestNormalizer(t,
+		&Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "G", UnifiedCode: "HEAVY_GOODS_VEHICLE"},
+		&Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1", UnifiedCode: "LIGHT_MOTOR_VEHICLE"},
+		&Mapping{ProviderKey: "dmt", CodeList: "fuelType", ProviderCode: "P", UnifiedCode: "PETROL"},
+		&Mapping{ProviderKey: "rgd", CodeList: "gender", ProviderCode: "M", UnifiedCode: "MALE"},
+	)
+
+	all, err := normalizer.List(context.Background(), "", "")
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	vehicleClasses, err := normalizer.List(context.Background(), "dmt", "vehicleClass")
+	require.NoError(t, err)
+	require.Len(t, vehicleClasses, 2)
+	assert.Equal(t, "B1", vehicleClasses[0].ProviderCode)
+	assert.Equal(t, "G", vehicleClasses[1].ProviderCode)
+
+	rgd, err := normalizer.List(context.Background(), "rgd", "")
+	require.NoError(t, err)
+	assert.Len(t, rgd, 1)
+}
+
+func TestNormalizer_RefreshesStaleMappings(t *testing.T) {
+	store := NewMemoryStore()
+	normalizer := NewNormalizer(store, time.Millisecond)
+	_, err := store.Upsert(context.Background(), &Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1", UnifiedCode: "LIGHT_MOTOR_VEHICLE"})
+	require.NoError(t, err)
+
+	// The first call finds no mappings loaded and starts a refresh in the background
+	assert.Eventually(t, func() bool {
+		return normalizer.Normalize("dmt", "vehicleClass", "B1") == "LIGHT_MOTOR_VEHICLE"
+	}, time.Second, 5*time.Millisecond)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/auth"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/transform"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/provider"
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/parser"
@@ -28,6 +29,13 @@ type Config struct {
 	PdpConfig     PdpConfig             `json:"pdpConfig,omitempty"`
 	CeConfig      CeConfig              `json:"ceConfig,omitempty"`
 	AuditConfig   AuditConfig           `json:"auditConfig,omitempty"`
+	QuotaConfig   QuotaConfig           `json:"quotaConfig,omitempty"`
+	AppContext    AppContextConfig      `json:"appContext,omitempty"`
+	Synthetic     SyntheticConfig       `json:"synthetic,omitempty"`
+	Maintenance   MaintenanceConfig     `json:"maintenance,omitempty"`
+	QueryLog      QueryLogConfig        `json:"queryLog,omitempty"`
+	Signing       SigningConfig         `json:"signing,omitempty"`
+	DataExport    DataExportConfig      `json:"dataExport,omitempty"`
 	Schema        *string               `json:"schema,omitempty"`
 	Sdl           *string               `json:"sdl,omitempty"`
 	ArgMapping    []*graphql.ArgMapping `json:"argMapping,omitempty"`
@@ -41,6 +49,11 @@ type ProviderConfig struct {
 	ProviderURL string           `json:"providerUrl"`
 	Auth        *auth.AuthConfig `json:"auth,omitempty"`
 	SchemaID    string           `json:"schemaId"`
+	// Mapping adapts the provider's responses to the unified schema before they are accumulated
+	Mapping *transform.Config `json:"mapping,omitempty"`
+	// FeatureFlag is the key of the feature flag gating the provider: it is only called for the
+	// consumer applications the flag is on for, so that a new provider is rolled out gradually
+	FeatureFlag string `json:"featureFlag,omitempty"`
 }
 
 // ServerConfig holds the server-specific configuration.
@@ -61,8 +74,30 @@ type ServicesConfig struct {
 // PdpConfig holds PDP service configuration
 type PdpConfig struct {
 	ClientURL string `json:"clientUrl"`
+	// Mode is "remote" (default) to call the PDP service at ClientURL, "grpc" to call its gRPC
+	// decision API at GrpcAddress, or "embedded" to evaluate policies in process against the PDP's
+	// policy database
+	Mode string `json:"mode,omitempty"`
+	// GrpcAddress is the host:port of the PDP's gRPC decision API, required in grpc mode
+	GrpcAddress string `json:"grpcAddress,omitempty"`
+	// DatabaseURL is the PDP's policy database, required in embedded mode
+	DatabaseURL string `json:"databaseUrl,omitempty"`
+	// CachePollInterval is how often embedded policies are synced from the database, e.g. "30s" (default)
+	CachePollInterval string `json:"cachePollInterval,omitempty"`
+	// KillSwitchPollInterval is how often embedded kill switches are synced, e.g. "2s" (default)
+	KillSwitchPollInterval string `json:"killSwitchPollInterval,omitempty"`
+	// Auth holds the OAuth2 client credentials the engine gets its PDP access tokens with, in remote
+	// and grpc modes; the identity provider must issue them with the OpenDIF_System role
+	Auth *auth.AuthConfig `json:"auth,omitempty"`
 }
 
+// PDP modes
+const (
+	PdpModeRemote   = "remote"
+	PdpModeEmbedded = "embedded"
+	PdpModeGrpc     = "grpc"
+)
+
 // CeConfig holds Consent Engine configuration
 type CeConfig struct {
 	ClientURL string `json:"clientUrl"`
@@ -76,6 +111,88 @@ type AuditConfig struct {
 	// Note: targetType is not configured here as it varies per API call
 }
 
+// QuotaConfig holds the configuration of per-application record quotas
+type QuotaConfig struct {
+	// PortalURL is the portal backend the quotas are read from; quotas are not enforced without it
+	PortalURL string `json:"portalUrl,omitempty"`
+	// CacheTTL is how long quotas read from the portal are reused, e.g. "1m" (default)
+	CacheTTL string `json:"cacheTtl,omitempty"`
+}
+
+// AppContextConfig holds the configuration of the application context, which the tokens of consumer
+// applications are resolved to
+type AppContextConfig struct {
+	// PortalURL is the portal backend application contexts are read from; without it tokens are not
+	// checked against their application's status and requests carry no application context
+	PortalURL string `json:"portalUrl,omitempty"`
+	// CacheTTL is how long application contexts read from the portal are reused, e.g. "1m" (default)
+	CacheTTL string `json:"cacheTtl,omitempty"`
+}
+
+// SyntheticConfig holds the configuration of synthetic data mode, for demo environments and load tests
+type SyntheticConfig struct {
+	// Enabled answers every provider query with generated data instead of calling the provider
+	Enabled bool `json:"enabled,omitempty"`
+	// Seed changes the generated data; the same seed always generates the same data for a NIC
+	Seed string `json:"seed,omitempty"`
+	// Latency delays each generated response to simulate providers, e.g. "150ms"
+	Latency string `json:"latency,omitempty"`
+}
+
+// MaintenanceConfig holds the configuration of provider maintenance windows
+type MaintenanceConfig struct {
+	// CacheTTL is how long successful provider responses are kept to be served during maintenance
+	// windows that allow it, e.g. "1h"; responses are not cached without it
+	CacheTTL string `json:"cacheTtl,omitempty"`
+	// CacheEntries bounds the number of cached responses, 10000 by default
+	CacheEntries int `json:"cacheEntries,omitempty"`
+}
+
+// QueryLogConfig holds the configuration of the query log, which records every executed query
+type QueryLogConfig struct {
+	// Disabled stops queries from being logged
+	Disabled bool `json:"disabled,omitempty"`
+	// Retention is how long executed queries are kept, e.g. "168h"; 30 days by default
+	Retention string `json:"retention,omitempty"`
+	// BufferSize bounds the executions waiting to be written, 1000 by default; executions are
+	// dropped while it is full
+	BufferSize int `json:"bufferSize,omitempty"`
+}
+
+// SigningConfig holds the configuration of response signing, which lets consumers verify that
+// responses came from the exchange unmodified
+type SigningConfig struct {
+	// KeyFile is the PEM encoded ECDSA P-256, RSA or Ed25519 private key responses are signed with;
+	// responses are not signed without it
+	KeyFile string `json:"keyFile,omitempty"`
+	// KeyID identifies the key to consumers, sent as kid in the signature header
+	KeyID string `json:"keyId,omitempty"`
+}
+
+// DataExportConfig holds the configuration of data exports, which run registered queries in bulk for
+// approved use cases and release the results as encrypted archives in object storage
+type DataExportConfig struct {
+	// Bucket is the S3-compatible bucket archives are stored in; data exports are disabled without it
+	Bucket string `json:"bucket,omitempty"`
+	// Endpoint is the object store, e.g. "http://minio:9000"; AWS S3 in Region without it
+	Endpoint string `json:"endpoint,omitempty"`
+	// Region is the region of the bucket, "us-east-1" by default
+	Region          string `json:"region,omitempty"`
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	// PathStyle addresses the bucket in the URL path rather than in the host name, as most
+	// self-hosted stores require
+	PathStyle bool `json:"pathStyle,omitempty"`
+	// EncryptionKeyFile holds the hex encoded 32 byte key the keys of the archives are derived from
+	EncryptionKeyFile string `json:"encryptionKeyFile,omitempty"`
+	// BatchSize is how many rows of a job are run against the providers at once, 25 by default
+	BatchSize int `json:"batchSize,omitempty"`
+	// MaxRows bounds the sets of parameters of a job, 10000 by default
+	MaxRows int `json:"maxRows,omitempty"`
+	// LinkTTL is how long download links are valid, e.g. "1h" (default)
+	LinkTTL string `json:"linkTtl,omitempty"`
+}
+
 // JWTConfig holds JWT validation configuration
 type JWTConfig struct {
 	ExpectedIssuer string   `json:"expectedIssuer,omitempty"`
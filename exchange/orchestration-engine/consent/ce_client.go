@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
 	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
+	"github.com/gov-dx-sandbox/shared/requestid"
 )
 
 // CEServiceClient represents a client to interact with the Consent Engine service
@@ -48,6 +51,7 @@ func (c *CEServiceClient) CreateConsent(ctx context.Context, request *CreateCons
 	if traceID != "" {
 		req.Header.Set("X-Trace-ID", traceID)
 	}
+	requestid.SetHeader(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -74,3 +78,105 @@ func (c *CEServiceClient) CreateConsent(ctx context.Context, request *CreateCons
 
 	return &consentResponse, nil
 }
+
+// GetConsent looks up the consent record of a data owner for an application.
+// Returns nil without an error if the owner has no consent record for the application.
+func (c *CEServiceClient) GetConsent(ctx context.Context, appID, ownerID string) (*ConsentResponseInternalView, error) {
+	query := url.Values{}
+	query.Set("appId", appID)
+	query.Set("ownerId", ownerID)
+	requestURL := c.baseURL + consentEndpointPath + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		logger.Log.Error("Failed to create HTTP request for GetConsent", "error", err)
+		return nil, err
+	}
+
+	// Propagate traceID from context to header for audit correlation
+	traceID := monitoring.GetTraceIDFromContext(ctx)
+	if traceID != "" {
+		req.Header.Set("X-Trace-ID", traceID)
+	}
+	requestid.SetHeader(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Log.Error("Failed to send HTTP request for GetConsent", "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errorBody bytes.Buffer
+		if _, err := errorBody.ReadFrom(resp.Body); err != nil {
+			logger.Log.Error("Failed to read error response body", "error", err)
+		}
+		errorMsg := errorBody.String()
+		logger.Log.Error("Failed to get consent", "status", resp.StatusCode, "response", errorMsg)
+		return nil, fmt.Errorf("failed to get consent, status code: %d, response: %s", resp.StatusCode, errorMsg)
+	}
+
+	var consentResponse ConsentResponseInternalView
+	if err := json.NewDecoder(resp.Body).Decode(&consentResponse); err != nil {
+		logger.Log.Error("Failed to decode GetConsent response", "error", err)
+		return nil, err
+	}
+
+	return &consentResponse, nil
+}
+
+// GetConsentChanges reads the consent status changes after the since cursor, oldest first, optionally
+// only those of one application. An empty since starts at the first change; a limit of 0 uses the
+// Consent Engine's default. Pass the returned NextCursor as since to read the following changes.
+func (c *CEServiceClient) GetConsentChanges(ctx context.Context, since, appID string, limit int) (*ConsentChangesResponse, error) {
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if appID != "" {
+		query.Set("appId", appID)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	requestURL := c.baseURL + consentChangesEndpointPath
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		logger.Log.Error("Failed to create HTTP request for GetConsentChanges", "error", err)
+		return nil, err
+	}
+	requestid.SetHeader(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Log.Error("Failed to send HTTP request for GetConsentChanges", "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorBody bytes.Buffer
+		if _, err := errorBody.ReadFrom(resp.Body); err != nil {
+			logger.Log.Error("Failed to read error response body", "error", err)
+		}
+		errorMsg := errorBody.String()
+		logger.Log.Error("Failed to get consent changes", "status", resp.StatusCode, "response", errorMsg)
+		return nil, fmt.Errorf("failed to get consent changes, status code: %d, response: %s", resp.StatusCode, errorMsg)
+	}
+
+	var changes ConsentChangesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&changes); err != nil {
+		logger.Log.Error("Failed to decode GetConsentChanges response", "error", err)
+		return nil, err
+	}
+
+	return &changes, nil
+}
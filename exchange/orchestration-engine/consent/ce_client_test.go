@@ -421,6 +421,129 @@ func TestCreateConsent_WithMinimalFields(t *testing.T) {
 	}
 }
 
+func TestGetConsent_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/consents" {
+			t.Errorf("Expected path /consents, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("appId") != "test-app-id" || r.URL.Query().Get("ownerId") != "citizen 123" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+
+		fields := []ConsentField{{FieldName: "name", SchemaID: "schema-1", Owner: OwnerCitizen}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ConsentResponseInternalView{
+			ConsentID: "consent-123",
+			Status:    StatusApproved,
+			Fields:    &fields,
+		})
+	}))
+	defer server.Close()
+
+	client := NewCEServiceClient(server.URL)
+	response, err := client.GetConsent(context.Background(), "test-app-id", "citizen 123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response == nil {
+		t.Fatal("Expected non-nil response")
+	}
+	if response.Status != StatusApproved {
+		t.Errorf("Expected Status %s, got %s", StatusApproved, response.Status)
+	}
+	if response.Fields == nil || len(*response.Fields) != 1 {
+		t.Errorf("Expected 1 field, got %v", response.Fields)
+	}
+}
+
+func TestGetConsent_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": "CONSENT_NOT_FOUND", "message": "consent not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewCEServiceClient(server.URL)
+	response, err := client.GetConsent(context.Background(), "test-app-id", "citizen-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response != nil {
+		t.Errorf("Expected nil response, got %v", response)
+	}
+}
+
+func TestGetConsent_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message": "An unexpected error occurred"}`))
+	}))
+	defer server.Close()
+
+	client := NewCEServiceClient(server.URL)
+	response, err := client.GetConsent(context.Background(), "test-app-id", "citizen-123")
+	if err == nil {
+		t.Error("Expected error when server returns 500 status code")
+	}
+	if response != nil {
+		t.Errorf("Expected nil response on error, got %v", response)
+	}
+}
+
+func TestGetConsentChanges_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/consents/changes" {
+			t.Errorf("Expected path /consents/changes, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("since") != "41" || r.URL.Query().Get("appId") != "test-app-id" || r.URL.Query().Get("limit") != "50" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"changes":[{"id":42,"consentId":"consent-123","appId":"test-app-id","ownerId":"citizen-123","previousStatus":"approved","status":"revoked","changedAt":"2026-10-16T10:00:00Z"}],"nextCursor":"42","hasMore":false}`))
+	}))
+	defer server.Close()
+
+	client := NewCEServiceClient(server.URL)
+	response, err := client.GetConsentChanges(context.Background(), "41", "test-app-id", 50)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(response.Changes))
+	}
+	change := response.Changes[0]
+	if change.Status != StatusRevoked || change.PreviousStatus == nil || *change.PreviousStatus != StatusApproved {
+		t.Errorf("Unexpected change %+v", change)
+	}
+	if response.NextCursor != "42" {
+		t.Errorf("Expected NextCursor 42, got %s", response.NextCursor)
+	}
+}
+
+func TestGetConsentChanges_BadRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("Expected no query, got %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message": "since must be a cursor returned by this endpoint"}`))
+	}))
+	defer server.Close()
+
+	client := NewCEServiceClient(server.URL)
+	response, err := client.GetConsentChanges(context.Background(), "", "", 0)
+	if err == nil {
+		t.Error("Expected error when server returns 400 status code")
+	}
+	if response != nil {
+		t.Errorf("Expected nil response on error, got %v", response)
+	}
+}
+
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
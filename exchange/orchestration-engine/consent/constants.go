@@ -30,5 +30,6 @@ const (
 
 // Endpoint paths
 const (
-	consentEndpointPath = "/consents"
+	consentEndpointPath        = "/consents"
+	consentChangesEndpointPath = "/consents/changes"
 )
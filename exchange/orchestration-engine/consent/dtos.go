@@ -1,5 +1,7 @@
 package consent
 
+import "time"
+
 // ConsentField represents a field that requires consent
 // Matches PolicyDecisionResponseFieldRecord DTO structure from PolicyDecisionPoint
 type ConsentField struct {
@@ -26,6 +28,8 @@ type CreateConsentRequest struct {
 	ConsentRequirement ConsentRequirement `json:"consentRequirement"`
 	GrantDuration      *string            `json:"grantDuration,omitempty"`
 	ConsentType        *ConsentType       `json:"consentType,omitempty"`
+	// Purposes are the purposes the data is requested for; the CE reuses an active consent only for the same ones
+	Purposes []string `json:"purposes,omitempty"`
 }
 
 // ConsentResponseInternalView represents a simplified consent response structure for Internal API Responses
@@ -35,3 +39,23 @@ type ConsentResponseInternalView struct {
 	ConsentPortalURL *string         `json:"consentPortalUrl,omitempty"` // Only present when status is pending
 	Fields           *[]ConsentField `json:"fields,omitempty"`           // Included for internal view
 }
+
+// ConsentStatusChange is a consent's creation or a change of its status, as recorded by the Consent Engine
+type ConsentStatusChange struct {
+	ID             int64          `json:"id"`
+	ConsentID      string         `json:"consentId"`
+	AppID          string         `json:"appId"`
+	OwnerID        string         `json:"ownerId"`
+	PreviousStatus *ConsentStatus `json:"previousStatus,omitempty"` // Absent when the consent was created
+	Status         ConsentStatus  `json:"status"`
+	GrantExpiresAt *time.Time     `json:"grantExpiresAt,omitempty"`
+	ChangedBy      *string        `json:"changedBy,omitempty"`
+	ChangedAt      time.Time      `json:"changedAt"`
+}
+
+// ConsentChangesResponse is a page of the consent change feed; it is also the body of consent change webhooks
+type ConsentChangesResponse struct {
+	Changes    []ConsentStatusChange `json:"changes"`
+	NextCursor string                `json:"nextCursor"`
+	HasMore    bool                  `json:"hasMore"`
+}
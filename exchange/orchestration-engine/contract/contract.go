@@ -0,0 +1,522 @@
+// Package contract generates the contract of a consumer application: the part of the unified schema
+// it is allow-listed for, as TypeScript types, Go structs and an example query per root field.
+// Consumer teams build against the contract rather than the full schema, so they see exactly the
+// fields the PDP releases to their application and which of those need the data owner's consent.
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DefaultGoPackage is the package of the generated Go file when none is given
+const DefaultGoPackage = "contract"
+
+// Access is how an application may read a field
+type Access string
+
+const (
+	// AccessAvailable is a field released to the application as is
+	AccessAvailable Access = "available"
+	// AccessConsentRequired is a field released once the data owner consents
+	AccessConsentRequired Access = "consent_required"
+)
+
+// ErrNoQueryType is returned when the schema has no Query type
+var ErrNoQueryType = errors.New("schema has no Query type")
+
+// Field is a provider field as the PDP identifies it
+type Field struct {
+	SchemaID  string
+	FieldName string
+}
+
+// ContractField is a provider field in the contract, at the path consumers query it by
+type ContractField struct {
+	// Path is the dotted path of the field from the Query type, e.g. personInfo.birthInfo.district
+	Path            string `json:"path"`
+	Type            string `json:"type"`
+	ProviderKey     string `json:"providerKey"`
+	SchemaID        string `json:"schemaId"`
+	FieldName       string `json:"fieldName"`
+	ConsentRequired bool   `json:"consentRequired"`
+}
+
+// ExampleQuery selects every field of the contract under a root field
+type ExampleQuery struct {
+	// Name is the operation name of the query
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// Contract is the part of the unified schema an application may query
+type Contract struct {
+	ApplicationID string          `json:"applicationId"`
+	GeneratedAt   time.Time       `json:"generatedAt"`
+	Fields        []ContractField `json:"fields"`
+	Queries       []ExampleQuery  `json:"queries"`
+	TypeScript    string          `json:"typescript"`
+	Go            string          `json:"go"`
+}
+
+// Options configure the generated artifacts
+type Options struct {
+	// GoPackage is the package of the generated Go file; it defaults to DefaultGoPackage
+	GoPackage string
+}
+
+// Fields lists the distinct provider fields of the schema's object types, in schema order
+func Fields(schema *ast.Document) []Field {
+	var fields []Field
+	seen := make(map[Field]bool)
+	for _, def := range schema.Definitions {
+		objDef, ok := def.(*ast.ObjectDefinition)
+		if !ok {
+			continue
+		}
+		for _, fieldDef := range objDef.Fields {
+			field, _, ok := sourceInfo(fieldDef)
+			if !ok || seen[field] {
+				continue
+			}
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Classify returns the access a policy decision on fields grants the application; the fields it
+// denies are left out. A nil decision, made when no PDP is configured, grants every field.
+func Classify(fields []Field, decision *policy.PdpResponse) map[Field]Access {
+	denied := make(map[Field]bool)
+	needsConsent := make(map[Field]bool)
+	if decision != nil {
+		for _, list := range [][]policy.ConsentRequiredField{
+			decision.UnauthorizedFields, decision.ConditionFailedFields, decision.ExpiredFields, decision.BlockedFields,
+		} {
+			for _, field := range list {
+				denied[Field{SchemaID: field.SchemaID, FieldName: field.FieldName}] = true
+			}
+		}
+		if decision.AppRequiresOwnerConsent {
+			for _, field := range decision.ConsentRequiredFields {
+				needsConsent[Field{SchemaID: field.SchemaID, FieldName: field.FieldName}] = true
+			}
+		}
+	}
+
+	access := make(map[Field]Access, len(fields))
+	for _, field := range fields {
+		switch {
+		case denied[field]:
+		case needsConsent[field]:
+			access[field] = AccessConsentRequired
+		default:
+			access[field] = AccessAvailable
+		}
+	}
+	return access
+}
+
+// Build generates the contract of an application from the schema and the access it has to the
+// schema's provider fields. Object types keep the fields the application may read and the fields
+// leading to them; types left without fields are dropped.
+func Build(schema *ast.Document, applicationID string, access map[Field]Access, opts Options) (*Contract, error) {
+	if opts.GoPackage == "" {
+		opts.GoPackage = DefaultGoPackage
+	}
+	s := newSurface(schema, access)
+	if s.query == nil {
+		return nil, ErrNoQueryType
+	}
+
+	contract := &Contract{
+		ApplicationID: applicationID,
+		GeneratedAt:   time.Now().UTC(),
+		Fields:        s.contractFields(),
+		Queries:       s.exampleQueries(),
+	}
+	contract.TypeScript = s.typeScript(applicationID)
+	goSource, err := s.goSource(applicationID, opts.GoPackage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Go contract: %w", err)
+	}
+	contract.Go = goSource
+	return contract, nil
+}
+
+// field is a field of an object type kept in the contract
+type field struct {
+	def         *ast.FieldDefinition
+	source      *Field
+	providerKey string
+	consent     bool
+}
+
+func (f *field) name() string {
+	return f.def.Name.Value
+}
+
+// surface is the part of the schema an application may query
+type surface struct {
+	schema *ast.Document
+	query  *ast.ObjectDefinition
+	// objects are the kept fields of each object type left with fields
+	objects map[string][]*field
+	enums   map[string]*ast.EnumDefinition
+	inputs  map[string]*ast.InputObjectDefinition
+	// order lists the object types reachable from the Query type, in schema order
+	order []string
+}
+
+func newSurface(schema *ast.Document, access map[Field]Access) *surface {
+	s := &surface{
+		schema:  schema,
+		objects: make(map[string][]*field),
+		enums:   make(map[string]*ast.EnumDefinition),
+		inputs:  make(map[string]*ast.InputObjectDefinition),
+	}
+	definitions := make(map[string]*ast.ObjectDefinition)
+	for _, def := range schema.Definitions {
+		switch def := def.(type) {
+		case *ast.ObjectDefinition:
+			definitions[def.Name.Value] = def
+			if def.Name.Value == "Query" {
+				s.query = def
+			}
+		case *ast.EnumDefinition:
+			s.enums[def.Name.Value] = def
+		case *ast.InputObjectDefinition:
+			s.inputs[def.Name.Value] = def
+		}
+	}
+	if s.query == nil {
+		return s
+	}
+
+	// A field is kept if the application may read it and it is a provider leaf or leads to an object
+	// type with kept fields; types referring to each other are resolved by iterating to a fixed point
+	keep := func(fieldDef *ast.FieldDefinition) bool {
+		source, _, sourced := sourceInfo(fieldDef)
+		if sourced {
+			if _, ok := access[source]; !ok {
+				return false
+			}
+		}
+		typeName := namedType(fieldDef.Type)
+		if _, ok := definitions[typeName]; ok {
+			_, nonEmpty := s.objects[typeName]
+			return nonEmpty
+		}
+		return sourced
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, def := range definitions {
+			if _, ok := s.objects[name]; ok {
+				continue
+			}
+			for _, fieldDef := range def.Fields {
+				if keep(fieldDef) {
+					s.objects[name] = nil
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for name := range s.objects {
+		var fields []*field
+		for _, fieldDef := range definitions[name].Fields {
+			if !keep(fieldDef) {
+				continue
+			}
+			kept := &field{def: fieldDef}
+			if source, providerKey, ok := sourceInfo(fieldDef); ok {
+				kept.source = &source
+				kept.providerKey = providerKey
+				kept.consent = access[source] == AccessConsentRequired
+			}
+			fields = append(fields, kept)
+		}
+		s.objects[name] = fields
+	}
+
+	reachable := map[string]bool{"Query": true}
+	queue := []string{"Query"}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, f := range s.objects[name] {
+			typeName := namedType(f.def.Type)
+			if _, ok := s.objects[typeName]; ok && !reachable[typeName] {
+				reachable[typeName] = true
+				queue = append(queue, typeName)
+			}
+		}
+	}
+	for _, def := range schema.Definitions {
+		if objDef, ok := def.(*ast.ObjectDefinition); ok && reachable[objDef.Name.Value] {
+			if _, ok := s.objects[objDef.Name.Value]; ok {
+				s.order = append(s.order, objDef.Name.Value)
+			}
+		}
+	}
+	return s
+}
+
+// rootFields returns the kept fields of the Query type
+func (s *surface) rootFields() []*field {
+	return s.objects["Query"]
+}
+
+// contractFields lists the provider fields at every path they can be queried by. A type already on
+// the path is not entered again.
+func (s *surface) contractFields() []ContractField {
+	fields := []ContractField{}
+	var walk func(typeName, path string, onPath map[string]bool)
+	walk = func(typeName, path string, onPath map[string]bool) {
+		onPath[typeName] = true
+		defer delete(onPath, typeName)
+		for _, f := range s.objects[typeName] {
+			fieldPath := f.name()
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if f.source != nil {
+				fields = append(fields, ContractField{
+					Path:            fieldPath,
+					Type:            typeString(f.def.Type),
+					ProviderKey:     f.providerKey,
+					SchemaID:        f.source.SchemaID,
+					FieldName:       f.source.FieldName,
+					ConsentRequired: f.consent,
+				})
+			}
+			if child := namedType(f.def.Type); s.isObject(child) && !onPath[child] {
+				walk(child, fieldPath, onPath)
+			}
+		}
+	}
+	walk("Query", "", make(map[string]bool))
+	return fields
+}
+
+// variable is a variable of an example query, bound to an argument of a selected field
+type variable struct {
+	name string
+	def  *ast.InputValueDefinition
+}
+
+// exampleQuery is the example query of a root field
+type exampleQuery struct {
+	root      *field
+	name      string
+	text      string
+	variables []variable
+}
+
+// examples builds the example query of every root field
+func (s *surface) examples() []exampleQuery {
+	var examples []exampleQuery
+	for _, root := range s.rootFields() {
+		example := exampleQuery{root: root, name: exported(root.name())}
+		used := make(map[string]bool)
+		var body strings.Builder
+		var selection func(f *field, indent string, onPath map[string]bool)
+		selection = func(f *field, indent string, onPath map[string]bool) {
+			body.WriteString(indent + f.name())
+			if len(f.def.Arguments) > 0 {
+				arguments := make([]string, 0, len(f.def.Arguments))
+				for _, arg := range f.def.Arguments {
+					name := arg.Name.Value
+					if used[name] {
+						name = f.name() + exported(name)
+					}
+					used[name] = true
+					example.variables = append(example.variables, variable{name: name, def: arg})
+					arguments = append(arguments, arg.Name.Value+": $"+name)
+				}
+				body.WriteString("(" + strings.Join(arguments, ", ") + ")")
+			}
+			child := namedType(f.def.Type)
+			if !s.isObject(child) {
+				body.WriteString("\n")
+				return
+			}
+			body.WriteString(" {\n")
+			onPath[child] = true
+			for _, childField := range s.objects[child] {
+				if grandchild := namedType(childField.def.Type); s.isObject(grandchild) && onPath[grandchild] {
+					continue
+				}
+				selection(childField, indent+"  ", onPath)
+			}
+			delete(onPath, child)
+			body.WriteString(indent + "}\n")
+		}
+		selection(root, "  ", map[string]bool{"Query": true})
+
+		var text strings.Builder
+		text.WriteString("query " + example.name)
+		if len(example.variables) > 0 {
+			definitions := make([]string, 0, len(example.variables))
+			for _, v := range example.variables {
+				definitions = append(definitions, "$"+v.name+": "+typeString(v.def.Type))
+			}
+			text.WriteString("(" + strings.Join(definitions, ", ") + ")")
+		}
+		text.WriteString(" {\n" + body.String() + "}\n")
+		example.text = text.String()
+		examples = append(examples, example)
+	}
+	return examples
+}
+
+func (s *surface) exampleQueries() []ExampleQuery {
+	queries := []ExampleQuery{}
+	for _, example := range s.examples() {
+		queries = append(queries, ExampleQuery{Name: example.name, Query: example.text})
+	}
+	return queries
+}
+
+// variableInputs lists the input types the variables of the example queries refer to, directly or
+// through other input types, in schema order
+func (s *surface) variableInputs(examples []exampleQuery) []*ast.InputObjectDefinition {
+	referenced := make(map[string]bool)
+	var visit func(t ast.Type)
+	visit = func(t ast.Type) {
+		name := namedType(t)
+		input, ok := s.inputs[name]
+		if !ok || referenced[name] {
+			return
+		}
+		referenced[name] = true
+		for _, inputField := range input.Fields {
+			visit(inputField.Type)
+		}
+	}
+	for _, example := range examples {
+		for _, v := range example.variables {
+			visit(v.def.Type)
+		}
+	}
+
+	var inputs []*ast.InputObjectDefinition
+	for _, def := range s.schema.Definitions {
+		if input, ok := def.(*ast.InputObjectDefinition); ok && referenced[input.Name.Value] {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+// usedEnums lists the enums of the kept fields and of the variables, in schema order
+func (s *surface) usedEnums(examples []exampleQuery, inputs []*ast.InputObjectDefinition) []*ast.EnumDefinition {
+	used := make(map[string]bool)
+	for _, name := range s.order {
+		for _, f := range s.objects[name] {
+			used[namedType(f.def.Type)] = true
+		}
+	}
+	for _, example := range examples {
+		for _, v := range example.variables {
+			used[namedType(v.def.Type)] = true
+		}
+	}
+	for _, input := range inputs {
+		for _, inputField := range input.Fields {
+			used[namedType(inputField.Type)] = true
+		}
+	}
+
+	var enums []*ast.EnumDefinition
+	for _, def := range s.schema.Definitions {
+		if enum, ok := def.(*ast.EnumDefinition); ok && used[enum.Name.Value] {
+			enums = append(enums, enum)
+		}
+	}
+	return enums
+}
+
+func (s *surface) isObject(typeName string) bool {
+	_, ok := s.objects[typeName]
+	return ok
+}
+
+// sourceInfo returns the provider field and provider key of a field's @sourceInfo directive
+func sourceInfo(fieldDef *ast.FieldDefinition) (Field, string, bool) {
+	for _, dir := range fieldDef.Directives {
+		if dir.Name.Value != "sourceInfo" {
+			continue
+		}
+		var field Field
+		var providerKey string
+		for _, arg := range dir.Arguments {
+			if val, ok := arg.Value.(*ast.StringValue); ok {
+				switch arg.Name.Value {
+				case "providerKey":
+					providerKey = val.Value
+				case "schemaId":
+					field.SchemaID = val.Value
+				case "providerField":
+					field.FieldName = val.Value
+				}
+			}
+		}
+		return field, providerKey, field.FieldName != ""
+	}
+	return Field{}, "", false
+}
+
+// namedType returns the name of the type a list or non-null type wraps
+func namedType(t ast.Type) string {
+	switch t := t.(type) {
+	case *ast.NonNull:
+		return namedType(t.Type)
+	case *ast.List:
+		return namedType(t.Type)
+	case *ast.Named:
+		return t.Name.Value
+	}
+	return ""
+}
+
+// typeString prints a type as GraphQL SDL does
+func typeString(t ast.Type) string {
+	switch t := t.(type) {
+	case *ast.NonNull:
+		return typeString(t.Type) + "!"
+	case *ast.List:
+		return "[" + typeString(t.Type) + "]"
+	case *ast.Named:
+		return t.Name.Value
+	}
+	return ""
+}
+
+// exported turns a GraphQL name into an exported identifier
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	if name[0] == '_' {
+		return "X" + name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// description returns the description of a definition, if any
+func description(value *ast.StringValue) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(value.Value)
+}
@@ -0,0 +1,168 @@
+package contract
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
+	"github.com/graphql-go/graphql/language/ast"
+	gqlparser "github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchemaSDL = `
+type Query {
+	personInfo(nic: String!): PersonInfo
+	vehicle(regNo: String!): VehicleInfo
+}
+
+"A person in the population registry"
+type PersonInfo {
+	fullName: String @sourceInfo(providerKey: "drp", schemaId: "drp-schema-v1", providerField: "person.fullName")
+	address: String @sourceInfo(providerKey: "drp", schemaId: "drp-schema-v1", providerField: "person.permanentAddress")
+	birthInfo: BirthInfo
+	ownedVehicles: [VehicleInfo!] @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicles")
+}
+
+type BirthInfo {
+	district: String @sourceInfo(providerKey: "rgd", schemaId: "rgd-schema-v1", providerField: "getPersonInfo.district")
+}
+
+type VehicleInfo {
+	regNo: String! @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.registrationNumber")
+	year: Int @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.yearOfManufacture")
+	status: VehicleStatus @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.status")
+	owner: PersonInfo
+}
+
+enum VehicleStatus {
+	ACTIVE
+	WRITTEN_OFF
+}
+`
+
+func testSchema(t *testing.T) *ast.Document {
+	doc, err := gqlparser.Parse(gqlparser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(testSchemaSDL)})})
+	require.NoError(t, err)
+	return doc
+}
+
+func pdpField(schemaID, fieldName string) policy.ConsentRequiredField {
+	return policy.ConsentRequiredField{SchemaID: schemaID, FieldName: fieldName}
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields(testSchema(t))
+	require.Len(t, fields, 7)
+	assert.Equal(t, Field{SchemaID: "drp-schema-v1", FieldName: "person.fullName"}, fields[0])
+	assert.Equal(t, Field{SchemaID: "dmt-schema-v1", FieldName: "vehicle.status"}, fields[6])
+}
+
+func TestClassify(t *testing.T) {
+	schema := testSchema(t)
+	access := Classify(Fields(schema), &policy.PdpResponse{
+		AppRequiresOwnerConsent: true,
+		ConsentRequiredFields:   []policy.ConsentRequiredField{pdpField("drp-schema-v1", "person.permanentAddress")},
+		UnauthorizedFields:      []policy.ConsentRequiredField{pdpField("rgd-schema-v1", "getPersonInfo.district")},
+		BlockedFields:           []policy.ConsentRequiredField{pdpField("dmt-schema-v1", "vehicle.status")},
+	})
+
+	assert.Equal(t, AccessAvailable, access[Field{SchemaID: "drp-schema-v1", FieldName: "person.fullName"}])
+	assert.Equal(t, AccessConsentRequired, access[Field{SchemaID: "drp-schema-v1", FieldName: "person.permanentAddress"}])
+	assert.NotContains(t, access, Field{SchemaID: "rgd-schema-v1", FieldName: "getPersonInfo.district"})
+	assert.NotContains(t, access, Field{SchemaID: "dmt-schema-v1", FieldName: "vehicle.status"})
+
+	// Without a PDP every field is available
+	for _, got := range Classify(Fields(schema), nil) {
+		assert.Equal(t, AccessAvailable, got)
+	}
+}
+
+func TestBuild_PrunesToTheAccessibleSurface(t *testing.T) {
+	schema := testSchema(t)
+	access := Classify(Fields(schema), &policy.PdpResponse{
+		AppRequiresOwnerConsent: true,
+		ConsentRequiredFields:   []policy.ConsentRequiredField{pdpField("drp-schema-v1", "person.permanentAddress")},
+		UnauthorizedFields: []policy.ConsentRequiredField{
+			pdpField("rgd-schema-v1", "getPersonInfo.district"),
+			pdpField("dmt-schema-v1", "vehicle.status"),
+		},
+	})
+
+	contract, err := Build(schema, "tax-app", access, Options{GoPackage: "taxclient"})
+	require.NoError(t, err)
+	assert.Equal(t, "tax-app", contract.ApplicationID)
+
+	var paths []string
+	for _, f := range contract.Fields {
+		paths = append(paths, f.Path)
+	}
+	// BirthInfo has no field left and the owner of a vehicle is not entered again below personInfo
+	assert.Equal(t, []string{
+		"personInfo.fullName",
+		"personInfo.address",
+		"personInfo.ownedVehicles",
+		"personInfo.ownedVehicles.regNo",
+		"personInfo.ownedVehicles.year",
+		"vehicle.regNo",
+		"vehicle.year",
+		"vehicle.owner.fullName",
+		"vehicle.owner.address",
+		"vehicle.owner.ownedVehicles",
+	}, paths)
+	assert.True(t, contract.Fields[1].ConsentRequired)
+	assert.Equal(t, "[VehicleInfo!]", contract.Fields[2].Type)
+	assert.Equal(t, "dmt", contract.Fields[2].ProviderKey)
+
+	require.Len(t, contract.Queries, 2)
+	assert.Equal(t, "PersonInfo", contract.Queries[0].Name)
+	assert.Equal(t, `query PersonInfo($nic: String!) {
+  personInfo(nic: $nic) {
+    fullName
+    address
+    ownedVehicles {
+      regNo
+      year
+    }
+  }
+}
+`, contract.Queries[0].Query)
+
+	assert.NotContains(t, contract.TypeScript, "BirthInfo")
+	assert.NotContains(t, contract.TypeScript, "VehicleStatus")
+	assert.Contains(t, contract.TypeScript, "/** A person in the population registry */\nexport interface PersonInfo {")
+	assert.Contains(t, contract.TypeScript, "  /** Released once the data owner consents. */\n  address?: string | null;")
+	assert.Contains(t, contract.TypeScript, "  ownedVehicles?: Array<VehicleInfo> | null;")
+	assert.Contains(t, contract.TypeScript, "  regNo: string;")
+	assert.Contains(t, contract.TypeScript, "export interface PersonInfoQueryVariables {\n  nic: string;\n}")
+
+	assert.Contains(t, contract.Go, "package taxclient")
+	assert.Contains(t, contract.Go, "OwnedVehicles []VehicleInfo `json:\"ownedVehicles,omitempty\"`")
+	assert.Regexp(t, "RegNo +string +`json:\"regNo\"`", contract.Go)
+	assert.NotContains(t, contract.Go, "BirthInfo")
+	assert.Contains(t, contract.Go, "// PersonInfo is the PersonInfo type of the unified schema\n//\n// A person in the population registry\n")
+	_, err = parser.ParseFile(token.NewFileSet(), "contract.go", contract.Go, 0)
+	assert.NoError(t, err)
+}
+
+func TestBuild_RendersEnums(t *testing.T) {
+	schema := testSchema(t)
+	contract, err := Build(schema, "fleet-app", Classify(Fields(schema), nil), Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, contract.TypeScript, `export type VehicleStatus = "ACTIVE" | "WRITTEN_OFF";`)
+	assert.Contains(t, contract.Go, "package contract")
+	assert.Contains(t, contract.Go, `VehicleStatusWrittenOff VehicleStatus = "WRITTEN_OFF"`)
+	assert.Contains(t, contract.Go, "Status *VehicleStatus")
+}
+
+func TestBuild_WithoutQueryType(t *testing.T) {
+	doc, err := gqlparser.Parse(gqlparser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte("type Person { name: String }")})})
+	require.NoError(t, err)
+
+	_, err = Build(doc, "app", nil, Options{})
+	assert.ErrorIs(t, err, ErrNoQueryType)
+}
@@ -0,0 +1,160 @@
+package contract
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// goScalars maps the built-in GraphQL scalars to Go types; custom scalars are left undecoded
+var goScalars = map[string]string{
+	"String":  "string",
+	"ID":      "string",
+	"Int":     "int",
+	"Float":   "float64",
+	"Boolean": "bool",
+}
+
+// goSource renders the contract as a formatted Go file of structs and query constants
+func (s *surface) goSource(applicationID, goPackage string) (string, error) {
+	examples := s.examples()
+	inputs := s.variableInputs(examples)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by the orchestration engine for application %s. DO NOT EDIT.\n\n", applicationID)
+	fmt.Fprintf(&b, "package %s\n", goPackage)
+
+	for _, enum := range s.usedEnums(examples, inputs) {
+		name := exported(enum.Name.Value)
+		b.WriteString("\n")
+		goComment(&b, name, description(enum.Description))
+		fmt.Fprintf(&b, "type %s string\n\nconst (\n", name)
+		for _, value := range enum.Values {
+			fmt.Fprintf(&b, "%s%s %s = %q\n", name, enumConstant(value.Name.Value), name, value.Name.Value)
+		}
+		b.WriteString(")\n")
+	}
+
+	for _, name := range s.order {
+		if name == "Query" {
+			continue
+		}
+		b.WriteString("\n")
+		goComment(&b, exported(name), s.objectDescription(name))
+		fmt.Fprintf(&b, "type %s struct {\n", exported(name))
+		for _, f := range s.objects[name] {
+			s.goField(&b, f)
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, input := range inputs {
+		b.WriteString("\n")
+		goComment(&b, exported(input.Name.Value), description(input.Description))
+		fmt.Fprintf(&b, "type %s struct {\n", exported(input.Name.Value))
+		for _, inputField := range input.Fields {
+			goComment(&b, "", description(inputField.Description))
+			goProperty(&b, inputField.Name.Value, inputField.Type, s.goType(inputField.Type))
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, example := range examples {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "// %sQuery is the %s query of the contract\n", example.name, example.name)
+		fmt.Fprintf(&b, "const %sQuery = `%s`\n", example.name, example.text)
+
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "// %sQueryVariables are the variables of %sQuery\n", example.name, example.name)
+		fmt.Fprintf(&b, "type %sQueryVariables struct {\n", example.name)
+		for _, v := range example.variables {
+			goProperty(&b, v.name, v.def.Type, s.goType(v.def.Type))
+		}
+		b.WriteString("}\n")
+
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "// %sQueryData is the data returned by %sQuery\n", example.name, example.name)
+		fmt.Fprintf(&b, "type %sQueryData struct {\n", example.name)
+		s.goField(&b, example.root)
+		b.WriteString("}\n")
+	}
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+func (s *surface) goField(b *strings.Builder, f *field) {
+	goComment(b, "", fieldComment(f))
+	goProperty(b, f.name(), f.def.Type, s.goType(f.def.Type))
+}
+
+// goType returns the Go type of a GraphQL type; nullable scalars and objects are pointers
+func (s *surface) goType(t ast.Type) string {
+	nullable := true
+	if nonNull, ok := t.(*ast.NonNull); ok {
+		t = nonNull.Type
+		nullable = false
+	}
+	switch t := t.(type) {
+	case *ast.List:
+		return "[]" + s.goType(t.Type)
+	case *ast.Named:
+		name := t.Name.Value
+		_, isEnum := s.enums[name]
+		_, isInput := s.inputs[name]
+		var out string
+		switch scalar, isScalar := goScalars[name]; {
+		case isScalar:
+			out = scalar
+		case s.isObject(name), isEnum, isInput:
+			out = exported(name)
+		default:
+			return "interface{}"
+		}
+		if nullable {
+			out = "*" + out
+		}
+		return out
+	}
+	return "interface{}"
+}
+
+// goProperty writes a struct field tagged with its GraphQL name
+func goProperty(b *strings.Builder, name string, t ast.Type, goType string) {
+	tag := name
+	if _, nonNull := t.(*ast.NonNull); !nonNull {
+		tag += ",omitempty"
+	}
+	fmt.Fprintf(b, "%s %s `json:%q`\n", exported(name), goType, tag)
+}
+
+// goComment writes a doc comment, starting it with the declared name when there is one
+func goComment(b *strings.Builder, name, text string) {
+	if name != "" && !strings.HasPrefix(text, name+" ") {
+		heading := fmt.Sprintf("%s is the %s type of the unified schema", name, name)
+		if text != "" {
+			heading += "\n\n" + text
+		}
+		text = heading
+	}
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
+
+// enumConstant turns an enum value such as FIRST_CLASS into the suffix FirstClass
+func enumConstant(value string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.ToLower(value), "_") {
+		b.WriteString(exported(part))
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package contract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// tsScalars maps the built-in GraphQL scalars to TypeScript types; custom scalars are unknown
+var tsScalars = map[string]string{
+	"String":  "string",
+	"ID":      "string",
+	"Int":     "number",
+	"Float":   "number",
+	"Boolean": "boolean",
+}
+
+// typeScript renders the contract as TypeScript types and query constants
+func (s *surface) typeScript(applicationID string) string {
+	examples := s.examples()
+	inputs := s.variableInputs(examples)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by the orchestration engine for application %s. DO NOT EDIT.\n", applicationID)
+
+	for _, enum := range s.usedEnums(examples, inputs) {
+		values := make([]string, 0, len(enum.Values))
+		for _, value := range enum.Values {
+			values = append(values, fmt.Sprintf("%q", value.Name.Value))
+		}
+		b.WriteString("\n")
+		tsComment(&b, "", description(enum.Description))
+		fmt.Fprintf(&b, "export type %s = %s;\n", enum.Name.Value, strings.Join(values, " | "))
+	}
+
+	for _, name := range s.order {
+		if name == "Query" {
+			continue
+		}
+		b.WriteString("\n")
+		tsComment(&b, "", s.objectDescription(name))
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, f := range s.objects[name] {
+			s.tsField(&b, f)
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, input := range inputs {
+		b.WriteString("\n")
+		tsComment(&b, "", description(input.Description))
+		fmt.Fprintf(&b, "export interface %s {\n", input.Name.Value)
+		for _, inputField := range input.Fields {
+			tsComment(&b, "  ", description(inputField.Description))
+			tsProperty(&b, inputField.Name.Value, s.tsType(inputField.Type))
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, example := range examples {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "export const %sQuery = `\n%s`;\n", example.name, example.text)
+
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "export interface %sQueryVariables {\n", example.name)
+		for _, v := range example.variables {
+			tsProperty(&b, v.name, s.tsType(v.def.Type))
+		}
+		b.WriteString("}\n")
+
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "export interface %sQueryData {\n", example.name)
+		s.tsField(&b, example.root)
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func (s *surface) tsField(b *strings.Builder, f *field) {
+	tsComment(b, "  ", fieldComment(f))
+	tsProperty(b, f.name(), s.tsType(f.def.Type))
+}
+
+// tsType returns the TypeScript type of a GraphQL type; nullable types are unions with null
+func (s *surface) tsType(t ast.Type) string {
+	nullable := true
+	if nonNull, ok := t.(*ast.NonNull); ok {
+		t = nonNull.Type
+		nullable = false
+	}
+	var out string
+	switch t := t.(type) {
+	case *ast.List:
+		out = "Array<" + s.tsType(t.Type) + ">"
+	case *ast.Named:
+		name := t.Name.Value
+		_, isEnum := s.enums[name]
+		_, isInput := s.inputs[name]
+		switch scalar, isScalar := tsScalars[name]; {
+		case isScalar:
+			out = scalar
+		case s.isObject(name), isEnum, isInput:
+			out = name
+		default:
+			out = "unknown"
+		}
+	}
+	if nullable {
+		out += " | null"
+	}
+	return out
+}
+
+// tsProperty writes an interface property; nullable properties are also optional
+func tsProperty(b *strings.Builder, name, tsType string) {
+	if strings.HasSuffix(tsType, " | null") {
+		fmt.Fprintf(b, "  %s?: %s;\n", name, tsType)
+		return
+	}
+	fmt.Fprintf(b, "  %s: %s;\n", name, tsType)
+}
+
+func tsComment(b *strings.Builder, indent, text string) {
+	if text == "" {
+		return
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, text)
+		return
+	}
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+}
+
+// fieldComment returns the description of a field, noting when it needs the data owner's consent
+func fieldComment(f *field) string {
+	text := description(f.def.Description)
+	if f.consent {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += "Released once the data owner consents."
+	}
+	return text
+}
+
+func (s *surface) objectDescription(name string) string {
+	for _, def := range s.schema.Definitions {
+		if objDef, ok := def.(*ast.ObjectDefinition); ok && objDef.Name.Value == name {
+			return description(objDef.Description)
+		}
+	}
+	return ""
+}
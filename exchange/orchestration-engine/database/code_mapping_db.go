@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/codes"
+)
+
+// CodeMappingDB stores the mappings of provider codes to unified codes
+type CodeMappingDB struct {
+	db *sql.DB
+}
+
+// CodeMappingDB returns the code mappings stored alongside the schemas
+func (s *SchemaDB) CodeMappingDB() *CodeMappingDB {
+	return &CodeMappingDB{db: s.db}
+}
+
+// List returns all mappings ordered by provider, code list and provider code
+func (c *CodeMappingDB) List(ctx context.Context) ([]*codes.Mapping, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT provider_key, code_list, provider_code, unified_code, COALESCE(description, ''), updated_at
+		FROM code_mappings ORDER BY provider_key, code_list, provider_code`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []*codes.Mapping
+	for rows.Next() {
+		mapping := &codes.Mapping{}
+		if err := rows.Scan(&mapping.ProviderKey, &mapping.CodeList, &mapping.ProviderCode,
+			&mapping.UnifiedCode, &mapping.Description, &mapping.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan code mapping: %w", err)
+		}
+		mappings = append(mappings, mapping)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get code mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// Upsert creates or replaces the mapping of a provider code
+func (c *CodeMappingDB) Upsert(ctx context.Context, mapping *codes.Mapping) (*codes.Mapping, error) {
+	stored := *mapping
+	err := c.db.QueryRowContext(ctx, `
+		INSERT INTO code_mappings (provider_key, code_list, provider_code, unified_code, description)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (provider_key, code_list, provider_code)
+		DO UPDATE SET unified_code = EXCLUDED.unified_code, description = EXCLUDED.description, updated_at = NOW()
+		RETURNING updated_at`,
+		mapping.ProviderKey, mapping.CodeList, mapping.ProviderCode, mapping.UnifiedCode, mapping.Description).Scan(&stored.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save code mapping: %w", err)
+	}
+	return &stored, nil
+}
+
+// Delete removes the mapping of a provider code
+func (c *CodeMappingDB) Delete(ctx context.Context, providerKey, codeList, providerCode string) error {
+	result, err := c.db.ExecContext(ctx,
+		`DELETE FROM code_mappings WHERE provider_key = $1 AND code_list = $2 AND provider_code = $3`,
+		providerKey, codeList, providerCode)
+	if err != nil {
+		return fmt.Errorf("failed to delete code mapping: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if deleted == 0 {
+		return codes.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/dataexport"
+	"github.com/lib/pq"
+)
+
+// staleDataExportClaim is how long a running job may go without recording progress before another
+// replica claims it again, so that a job claimed by a replica that stopped is not left running
+const staleDataExportClaim = 10 * time.Minute
+
+// DataExportDB stores the queries registered for data exports and the jobs running them
+type DataExportDB struct {
+	db *sql.DB
+}
+
+// DataExportDB returns the data exports stored alongside the schemas
+func (s *SchemaDB) DataExportDB() *DataExportDB {
+	return &DataExportDB{db: s.db}
+}
+
+const dataExportQueryColumns = `id, name, COALESCE(description, ''), query, parameters, application_ids,
+	COALESCE(created_by, ''), created_at`
+
+const dataExportJobColumns = `id, query_id, application_id, COALESCE(client_id, ''), COALESCE(purpose, ''), parameters,
+	row_count, status, processed_rows, failed_rows, COALESCE(object_key, ''), archive_size, COALESCE(archive_sha256, ''),
+	COALESCE(failure_reason, ''), COALESCE(reviewed_by, ''), COALESCE(review_note, ''), reviewed_at, created_at, completed_at`
+
+// CreateQuery stores a new query; the unique index on names rejects a name in use
+func (d *DataExportDB) CreateQuery(ctx context.Context, query *dataexport.Query) (*dataexport.Query, error) {
+	stored := *query
+	err := d.db.QueryRowContext(ctx, `
+		INSERT INTO data_export_queries (id, name, description, query, parameters, application_ids, created_by)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''))
+		RETURNING created_at`,
+		query.ID, query.Name, query.Description, query.Query, pq.Array(query.Parameters), pq.Array(query.ApplicationIDs),
+		query.CreatedBy).Scan(&stored.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, dataexport.ErrQueryExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save data export query: %w", err)
+	}
+	return &stored, nil
+}
+
+// GetQuery returns a registered query
+func (d *DataExportDB) GetQuery(ctx context.Context, id string) (*dataexport.Query, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT `+dataExportQueryColumns+` FROM data_export_queries WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export query: %w", err)
+	}
+	queries, err := scanDataExportQueries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		return nil, dataexport.ErrQueryNotFound
+	}
+	return queries[0], nil
+}
+
+// ListQueries returns the registered queries ordered by name
+func (d *DataExportDB) ListQueries(ctx context.Context) ([]*dataexport.Query, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT `+dataExportQueryColumns+` FROM data_export_queries ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export queries: %w", err)
+	}
+	return scanDataExportQueries(rows)
+}
+
+// CreateJob stores a new job
+func (d *DataExportDB) CreateJob(ctx context.Context, job *dataexport.Job) (*dataexport.Job, error) {
+	parameters, err := json.Marshal(job.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data export parameters: %w", err)
+	}
+	stored := *job
+	err = d.db.QueryRowContext(ctx, `
+		INSERT INTO data_export_jobs (id, query_id, application_id, client_id, purpose, parameters, row_count, status)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+		RETURNING created_at`,
+		job.ID, job.QueryID, job.ApplicationID, job.ClientID, job.Purpose, parameters, job.Rows, job.Status).Scan(&stored.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save data export job: %w", err)
+	}
+	return &stored, nil
+}
+
+// GetJob returns a job
+func (d *DataExportDB) GetJob(ctx context.Context, id string) (*dataexport.Job, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT `+dataExportJobColumns+` FROM data_export_jobs WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export job: %w", err)
+	}
+	jobs, err := scanDataExportJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, dataexport.ErrJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// ListJobs returns the jobs of an application with the given status, newest first
+func (d *DataExportDB) ListJobs(ctx context.Context, applicationID, status string) ([]*dataexport.Job, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT `+dataExportJobColumns+`
+		FROM data_export_jobs WHERE ($1 = '' OR application_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC`, applicationID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export jobs: %w", err)
+	}
+	return scanDataExportJobs(rows)
+}
+
+// ClaimNext marks the oldest queued job as running and returns it. A job left running by a replica
+// that stopped is claimed again.
+func (d *DataExportDB) ClaimNext(ctx context.Context, now time.Time) (*dataexport.Job, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		UPDATE data_export_jobs SET status = $2, claimed_at = $1
+		WHERE id IN (
+			SELECT id FROM data_export_jobs
+			WHERE status = $3 OR (status = $2 AND claimed_at < $4)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+dataExportJobColumns,
+		now, dataexport.StatusRunning, dataexport.StatusQueued, now.Add(-staleDataExportClaim))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim data export job: %w", err)
+	}
+	jobs, err := scanDataExportJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return jobs[0], nil
+}
+
+// UpdateJob records the progress or the outcome of a claimed job, renewing its claim
+func (d *DataExportDB) UpdateJob(ctx context.Context, job *dataexport.Job) error {
+	result, err := d.db.ExecContext(ctx, `
+		UPDATE data_export_jobs SET status = $2, processed_rows = $3, failed_rows = $4, object_key = NULLIF($5, ''),
+			archive_size = $6, archive_sha256 = NULLIF($7, ''), failure_reason = NULLIF($8, ''), completed_at = $9,
+			claimed_at = NOW()
+		WHERE id = $1`,
+		job.ID, job.Status, job.ProcessedRows, job.FailedRows, job.ObjectKey, job.ArchiveSize, job.ArchiveSHA256,
+		job.FailureReason, job.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update data export job: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if updated == 0 {
+		return dataexport.ErrJobNotFound
+	}
+	return nil
+}
+
+// ReviewJob approves or rejects a job awaiting approval
+func (d *DataExportDB) ReviewJob(ctx context.Context, id, status, reviewedBy, note string, at time.Time) (*dataexport.Job, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		UPDATE data_export_jobs SET status = $2, reviewed_by = NULLIF($3, ''), review_note = NULLIF($4, ''), reviewed_at = $5
+		WHERE id = $1 AND status = $6
+		RETURNING `+dataExportJobColumns,
+		id, status, reviewedBy, note, at, dataexport.StatusAwaitingApproval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review data export job: %w", err)
+	}
+	jobs, err := scanDataExportJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		if _, err := d.GetJob(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, dataexport.ErrNotAwaitingApproval
+	}
+	return jobs[0], nil
+}
+
+func scanDataExportQueries(rows *sql.Rows) ([]*dataexport.Query, error) {
+	defer rows.Close()
+	queries := []*dataexport.Query{}
+	for rows.Next() {
+		query := &dataexport.Query{}
+		if err := rows.Scan(&query.ID, &query.Name, &query.Description, &query.Query, pq.Array(&query.Parameters),
+			pq.Array(&query.ApplicationIDs), &query.CreatedBy, &query.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan data export query: %w", err)
+		}
+		queries = append(queries, query)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get data export queries: %w", err)
+	}
+	return queries, nil
+}
+
+func scanDataExportJobs(rows *sql.Rows) ([]*dataexport.Job, error) {
+	defer rows.Close()
+	jobs := []*dataexport.Job{}
+	for rows.Next() {
+		job := &dataexport.Job{}
+		var parameters []byte
+		var reviewedAt, completedAt sql.NullTime
+		if err := rows.Scan(&job.ID, &job.QueryID, &job.ApplicationID, &job.ClientID, &job.Purpose, &parameters,
+			&job.Rows, &job.Status, &job.ProcessedRows, &job.FailedRows, &job.ObjectKey, &job.ArchiveSize,
+			&job.ArchiveSHA256, &job.FailureReason, &job.ReviewedBy, &job.ReviewNote, &reviewedAt, &job.CreatedAt,
+			&completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan data export job: %w", err)
+		}
+		if err := json.Unmarshal(parameters, &job.Parameters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data export parameters: %w", err)
+		}
+		if reviewedAt.Valid {
+			job.ReviewedAt = &reviewedAt.Time
+		}
+		if completedAt.Valid {
+			job.CompletedAt = &completedAt.Time
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get data export jobs: %w", err)
+	}
+	return jobs, nil
+}
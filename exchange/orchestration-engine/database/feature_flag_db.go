@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/featureflags"
+	"github.com/lib/pq"
+)
+
+// FeatureFlagDB stores the feature flags gating federation behaviors
+type FeatureFlagDB struct {
+	db *sql.DB
+}
+
+// FeatureFlagDB returns the feature flags stored alongside the schemas
+func (s *SchemaDB) FeatureFlagDB() *FeatureFlagDB {
+	return &FeatureFlagDB{db: s.db}
+}
+
+// List returns all flags ordered by key
+func (f *FeatureFlagDB) List(ctx context.Context) ([]*featureflags.Flag, error) {
+	rows, err := f.db.QueryContext(ctx, `
+		SELECT key, COALESCE(description, ''), enabled, percentage, application_ids, updated_at
+		FROM feature_flags ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []*featureflags.Flag
+	for rows.Next() {
+		flag := &featureflags.Flag{}
+		if err := rows.Scan(&flag.Key, &flag.Description, &flag.Enabled, &flag.Percentage,
+			pq.Array(&flag.ApplicationIDs), &flag.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// Upsert creates or replaces a flag
+func (f *FeatureFlagDB) Upsert(ctx context.Context, flag *featureflags.Flag) (*featureflags.Flag, error) {
+	stored := *flag
+	applicationIDs := flag.ApplicationIDs
+	if applicationIDs == nil {
+		applicationIDs = []string{}
+	}
+	err := f.db.QueryRowContext(ctx, `
+		INSERT INTO feature_flags (key, description, enabled, percentage, application_ids)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key)
+		DO UPDATE SET description = EXCLUDED.description, enabled = EXCLUDED.enabled, percentage = EXCLUDED.percentage,
+			application_ids = EXCLUDED.application_ids, updated_at = NOW()
+		RETURNING updated_at`,
+		flag.Key, flag.Description, flag.Enabled, flag.Percentage, pq.Array(applicationIDs)).Scan(&stored.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return &stored, nil
+}
+
+// Delete removes a flag
+func (f *FeatureFlagDB) Delete(ctx context.Context, key string) error {
+	result, err := f.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if deleted == 0 {
+		return featureflags.ErrNotFound
+	}
+	return nil
+}
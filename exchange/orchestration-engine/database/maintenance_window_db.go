@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
+)
+
+// MaintenanceWindowDB stores the maintenance windows of providers
+type MaintenanceWindowDB struct {
+	db *sql.DB
+}
+
+// MaintenanceWindowDB returns the maintenance windows stored alongside the schemas
+func (s *SchemaDB) MaintenanceWindowDB() *MaintenanceWindowDB {
+	return &MaintenanceWindowDB{db: s.db}
+}
+
+// List returns the windows ending after the given time, or all windows for the zero time, ordered by start
+func (m *MaintenanceWindowDB) List(ctx context.Context, endingAfter time.Time) ([]*maintenance.Window, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT id, provider_key, starts_at, ends_at, COALESCE(reason, ''), serve_cached, created_at
+		FROM provider_maintenance_windows WHERE ends_at > $1 ORDER BY starts_at, id`, endingAfter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*maintenance.Window
+	for rows.Next() {
+		window := &maintenance.Window{}
+		if err := rows.Scan(&window.ID, &window.ProviderKey, &window.StartsAt, &window.EndsAt,
+			&window.Reason, &window.ServeCached, &window.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+// Create stores a new window
+func (m *MaintenanceWindowDB) Create(ctx context.Context, window *maintenance.Window) (*maintenance.Window, error) {
+	stored := *window
+	err := m.db.QueryRowContext(ctx, `
+		INSERT INTO provider_maintenance_windows (provider_key, starts_at, ends_at, reason, serve_cached)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		window.ProviderKey, window.StartsAt, window.EndsAt, window.Reason, window.ServeCached).Scan(&stored.ID, &stored.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save maintenance window: %w", err)
+	}
+	return &stored, nil
+}
+
+// Delete removes a window
+func (m *MaintenanceWindowDB) Delete(ctx context.Context, id int64) error {
+	result, err := m.db.ExecContext(ctx, `DELETE FROM provider_maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if deleted == 0 {
+		return maintenance.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/querylog"
+	"github.com/lib/pq"
+)
+
+// QueryLogDB stores the queries executed by the engine
+type QueryLogDB struct {
+	db *sql.DB
+}
+
+// QueryLogDB returns the query log stored alongside the schemas
+func (s *SchemaDB) QueryLogDB() *QueryLogDB {
+	return &QueryLogDB{db: s.db}
+}
+
+// queryLogOrderBy maps the orderings of the top fingerprints to their aggregate
+var queryLogOrderBy = map[string]string{
+	querylog.OrderByExecutions: "COUNT(*)",
+	querylog.OrderByAvgLatency: "AVG(latency_ms)",
+	querylog.OrderByP95Latency: "percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms)",
+	querylog.OrderByRows:       "SUM(row_count)",
+}
+
+// Insert stores executions in a single statement
+func (q *QueryLogDB) Insert(ctx context.Context, entries []*querylog.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	const columns = 9
+	placeholders := make([]string, 0, len(entries))
+	args := make([]interface{}, 0, len(entries)*columns)
+	for i, entry := range entries {
+		row := make([]string, columns)
+		for j := range row {
+			row[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		placeholders = append(placeholders, "("+strings.Join(row, ", ")+")")
+		args = append(args, entry.Fingerprint, entry.NormalizedQuery, entry.OperationName, entry.ConsumerID,
+			entry.LatencyMs, pq.Array(entry.Providers), entry.RowCount, entry.ErrorCount, entry.ExecutedAt)
+	}
+	_, err := q.db.ExecContext(ctx, `
+		INSERT INTO queries (fingerprint, normalized_query, operation_name, consumer_id, latency_ms, providers,
+			row_count, error_count, executed_at)
+		VALUES `+strings.Join(placeholders, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to save query log: %w", err)
+	}
+	return nil
+}
+
+// Top aggregates the executions by fingerprint and returns the top fingerprints
+func (q *QueryLogDB) Top(ctx context.Context, opts querylog.TopOptions) ([]*querylog.Summary, error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT fingerprint,
+			(array_agg(normalized_query ORDER BY executed_at DESC))[1],
+			(array_agg(COALESCE(operation_name, '') ORDER BY executed_at DESC))[1],
+			COUNT(*), COUNT(DISTINCT consumer_id), AVG(latency_ms),
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), MAX(latency_ms),
+			SUM(row_count), COUNT(*) FILTER (WHERE error_count > 0), MAX(executed_at)
+		FROM queries
+		WHERE executed_at >= $1 AND ($2 = '' OR consumer_id = $2)
+		GROUP BY fingerprint
+		ORDER BY `+queryLogOrderBy[opts.OrderBy]+` DESC, fingerprint
+		LIMIT $3`, opts.Since, opts.ConsumerID, opts.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top queries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []*querylog.Summary{}
+	byFingerprint := make(map[string]*querylog.Summary)
+	for rows.Next() {
+		summary := &querylog.Summary{Providers: []string{}}
+		if err := rows.Scan(&summary.Fingerprint, &summary.NormalizedQuery, &summary.OperationName,
+			&summary.Executions, &summary.Consumers, &summary.AvgLatencyMs, &summary.P95LatencyMs,
+			&summary.MaxLatencyMs, &summary.TotalRows, &summary.FailedExecutions, &summary.LastExecutedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan top query: %w", err)
+		}
+		summaries = append(summaries, summary)
+		byFingerprint[summary.Fingerprint] = summary
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get top queries: %w", err)
+	}
+	if len(summaries) == 0 {
+		return summaries, nil
+	}
+
+	fingerprints := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		fingerprints = append(fingerprints, summary.Fingerprint)
+	}
+	providerRows, err := q.db.QueryContext(ctx, `
+		SELECT DISTINCT fingerprint, provider
+		FROM queries, unnest(providers) AS provider
+		WHERE fingerprint = ANY($1) AND executed_at >= $2 AND ($3 = '' OR consumer_id = $3)
+		ORDER BY fingerprint, provider`, pq.Array(fingerprints), opts.Since, opts.ConsumerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get providers of top queries: %w", err)
+	}
+	defer providerRows.Close()
+	for providerRows.Next() {
+		var fingerprint, provider string
+		if err := providerRows.Scan(&fingerprint, &provider); err != nil {
+			return nil, fmt.Errorf("failed to scan provider of top query: %w", err)
+		}
+		summary := byFingerprint[fingerprint]
+		summary.Providers = append(summary.Providers, provider)
+	}
+	if err := providerRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get providers of top queries: %w", err)
+	}
+	return summaries, nil
+}
+
+// List returns the matching executions, most recent first
+func (q *QueryLogDB) List(ctx context.Context, opts querylog.ListOptions) ([]*querylog.Entry, error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT id, fingerprint, normalized_query, COALESCE(operation_name, ''), consumer_id, latency_ms, providers,
+			row_count, error_count, executed_at
+		FROM queries
+		WHERE executed_at >= $1 AND latency_ms >= $2 AND ($3 = '' OR fingerprint = $3) AND ($4 = '' OR consumer_id = $4)
+		ORDER BY executed_at DESC, id DESC
+		LIMIT $5`, opts.Since, opts.MinLatencyMs, opts.Fingerprint, opts.ConsumerID, opts.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*querylog.Entry{}
+	for rows.Next() {
+		entry := &querylog.Entry{}
+		if err := rows.Scan(&entry.ID, &entry.Fingerprint, &entry.NormalizedQuery, &entry.OperationName,
+			&entry.ConsumerID, &entry.LatencyMs, pq.Array(&entry.Providers), &entry.RowCount, &entry.ErrorCount,
+			&entry.ExecutedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan query log entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get query log: %w", err)
+	}
+	return entries, nil
+}
+
+// DeleteBefore removes the executions older than the given time
+func (q *QueryLogDB) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, `DELETE FROM queries WHERE executed_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete query log entries: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/activation"
+)
+
+// staleActivationClaim is how long a claimed activation may run before another replica claims it
+// again, so that an activation claimed by a replica that stopped is not left running
+const staleActivationClaim = 5 * time.Minute
+
+// SchemaActivationDB stores the scheduled activations of schema versions
+type SchemaActivationDB struct {
+	db *sql.DB
+}
+
+// SchemaActivationDB returns the scheduled activations stored alongside the schemas
+func (s *SchemaDB) SchemaActivationDB() *SchemaActivationDB {
+	return &SchemaActivationDB{db: s.db}
+}
+
+const schemaActivationColumns = `id, version, activate_at, status, COALESCE(scheduled_by, ''), results,
+	COALESCE(failure_reason, ''), created_at, completed_at`
+
+// Create stores a new activation
+func (a *SchemaActivationDB) Create(ctx context.Context, act *activation.Activation) (*activation.Activation, error) {
+	stored := *act
+	err := a.db.QueryRowContext(ctx, `
+		INSERT INTO schema_activations (version, activate_at, status, scheduled_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		act.Version, act.ActivateAt, act.Status, act.ScheduledBy).Scan(&stored.ID, &stored.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save schema activation: %w", err)
+	}
+	return &stored, nil
+}
+
+// List returns the activations with the given status, or all activations for an empty status,
+// ordered by activation time
+func (a *SchemaActivationDB) List(ctx context.Context, status string) ([]*activation.Activation, error) {
+	rows, err := a.db.QueryContext(ctx, `
+		SELECT `+schemaActivationColumns+`
+		FROM schema_activations WHERE ($1 = '' OR status = $1) ORDER BY activate_at, id`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema activations: %w", err)
+	}
+	return scanSchemaActivations(rows)
+}
+
+// Cancel cancels a scheduled activation
+func (a *SchemaActivationDB) Cancel(ctx context.Context, id int64) error {
+	result, err := a.db.ExecContext(ctx, `
+		UPDATE schema_activations SET status = $2, completed_at = NOW()
+		WHERE id = $1 AND status = $3`, id, activation.StatusCancelled, activation.StatusScheduled)
+	if err != nil {
+		return fmt.Errorf("failed to cancel schema activation: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if updated == 0 {
+		return activation.ErrNotFound
+	}
+	return nil
+}
+
+// ClaimDue marks the scheduled activations due at the given time as running and returns them,
+// ordered by activation time. Activations left running by a replica that stopped are claimed again.
+func (a *SchemaActivationDB) ClaimDue(ctx context.Context, now time.Time) ([]*activation.Activation, error) {
+	rows, err := a.db.QueryContext(ctx, `
+		UPDATE schema_activations SET status = $2, claimed_at = $1
+		WHERE id IN (
+			SELECT id FROM schema_activations
+			WHERE (status = $3 AND activate_at <= $1) OR (status = $2 AND claimed_at < $4)
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+schemaActivationColumns,
+		now, activation.StatusRunning, activation.StatusScheduled, now.Add(-staleActivationClaim))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due schema activations: %w", err)
+	}
+	due, err := scanSchemaActivations(rows)
+	if err != nil {
+		return nil, err
+	}
+	sortDueActivations(due)
+	return due, nil
+}
+
+// Complete records the outcome of a claimed activation
+func (a *SchemaActivationDB) Complete(ctx context.Context, act *activation.Activation) error {
+	results, err := json.Marshal(act.Results)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contract test results: %w", err)
+	}
+	result, err := a.db.ExecContext(ctx, `
+		UPDATE schema_activations SET status = $2, results = $3, failure_reason = NULLIF($4, ''), completed_at = $5
+		WHERE id = $1`, act.ID, act.Status, results, act.FailureReason, act.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete schema activation: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if updated == 0 {
+		return activation.ErrNotFound
+	}
+	return nil
+}
+
+func scanSchemaActivations(rows *sql.Rows) ([]*activation.Activation, error) {
+	defer rows.Close()
+	activations := []*activation.Activation{}
+	for rows.Next() {
+		act := &activation.Activation{}
+		var results []byte
+		var completedAt sql.NullTime
+		if err := rows.Scan(&act.ID, &act.Version, &act.ActivateAt, &act.Status, &act.ScheduledBy, &results,
+			&act.FailureReason, &act.CreatedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema activation: %w", err)
+		}
+		if len(results) > 0 {
+			if err := json.Unmarshal(results, &act.Results); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal contract test results: %w", err)
+			}
+		}
+		if completedAt.Valid {
+			act.CompletedAt = &completedAt.Time
+		}
+		activations = append(activations, act)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get schema activations: %w", err)
+	}
+	return activations, nil
+}
+
+// sortDueActivations orders claimed activations by activation time, as UPDATE ... RETURNING does
+// not keep an order
+func sortDueActivations(activations []*activation.Activation) {
+	sort.Slice(activations, func(i, j int) bool {
+		a, b := activations[i], activations[j]
+		if !a.ActivateAt.Equal(b.ActivateAt) {
+			return a.ActivateAt.Before(b.ActivateAt)
+		}
+		return a.ID < b.ID
+	})
+}
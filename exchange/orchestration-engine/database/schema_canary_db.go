@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/canary"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// SchemaCanaryDB stores the canaries of candidate schema versions
+type SchemaCanaryDB struct {
+	db *sql.DB
+}
+
+// SchemaCanaryDB returns the schema canaries stored alongside the schemas
+func (s *SchemaDB) SchemaCanaryDB() *SchemaCanaryDB {
+	return &SchemaCanaryDB{db: s.db}
+}
+
+// Latest returns the most recently started canary, or nil if none was started
+func (c *SchemaCanaryDB) Latest(ctx context.Context) (*canary.Canary, error) {
+	stored := &canary.Canary{}
+	err := c.db.QueryRowContext(ctx, `
+		SELECT id, version, percentage, consumer_ids, error_threshold, min_requests, status,
+			COALESCE(started_by, ''), COALESCE(end_reason, ''), created_at, ended_at
+		FROM schema_canaries ORDER BY id DESC LIMIT 1`).Scan(&stored.ID, &stored.Version, &stored.Percentage,
+		pq.Array(&stored.ConsumerIDs), &stored.ErrorThreshold, &stored.MinRequests, &stored.Status,
+		&stored.StartedBy, &stored.EndReason, &stored.CreatedAt, &stored.EndedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema canary: %w", err)
+	}
+	return stored, nil
+}
+
+// Create stores a new running canary; the unique index on running canaries rejects a second one
+func (c *SchemaCanaryDB) Create(ctx context.Context, newCanary *canary.Canary) (*canary.Canary, error) {
+	stored := *newCanary
+	stored.Status = canary.StatusRunning
+	err := c.db.QueryRowContext(ctx, `
+		INSERT INTO schema_canaries (version, percentage, consumer_ids, error_threshold, min_requests, status, started_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		newCanary.Version, newCanary.Percentage, pq.Array(newCanary.ConsumerIDs), newCanary.ErrorThreshold, newCanary.MinRequests,
+		canary.StatusRunning, newCanary.StartedBy).Scan(&stored.ID, &stored.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, canary.ErrAlreadyRunning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save schema canary: %w", err)
+	}
+	return &stored, nil
+}
+
+// End ends a running canary with the given status
+func (c *SchemaCanaryDB) End(ctx context.Context, id int64, status, reason string) error {
+	result, err := c.db.ExecContext(ctx, `
+		UPDATE schema_canaries SET status = $2, end_reason = NULLIF($3, ''), ended_at = NOW()
+		WHERE id = $1 AND status = $4`, id, status, reason, canary.StatusRunning)
+	if err != nil {
+		return fmt.Errorf("failed to end schema canary: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if updated == 0 {
+		return canary.ErrNotFound
+	}
+	return nil
+}
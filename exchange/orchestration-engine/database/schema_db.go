@@ -2,12 +2,16 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	_ "github.com/lib/pq"
 )
 
+// ErrSchemaNotFound is returned when a schema version does not exist
+var ErrSchemaNotFound = errors.New("schema version not found")
+
 // SchemaDB handles database operations for schemas
 type SchemaDB struct {
 	db *sql.DB
@@ -34,6 +38,11 @@ func NewSchemaDB(connectionString string) (*SchemaDB, error) {
 	return schemaDB, nil
 }
 
+// DB returns the underlying connection pool
+func (s *SchemaDB) DB() *sql.DB {
+	return s.db
+}
+
 // Close closes the database connection
 func (s *SchemaDB) Close() error {
 	return s.db.Close()
@@ -76,6 +85,173 @@ func (s *SchemaDB) createTables() error {
 		return fmt.Errorf("failed to create schema_versions table: %w", err)
 	}
 
+	// Create application_usage_counters table for per-application record quotas
+	createUsageCountersTable := `
+	CREATE TABLE IF NOT EXISTS application_usage_counters (
+		application_id VARCHAR(255) NOT NULL,
+		period VARCHAR(32) NOT NULL,
+		records BIGINT NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		PRIMARY KEY (application_id, period)
+	);`
+
+	if _, err := s.db.Exec(createUsageCountersTable); err != nil {
+		return fmt.Errorf("failed to create application_usage_counters table: %w", err)
+	}
+
+	// Create code_mappings table mapping provider codes to unified schema codes
+	createCodeMappingsTable := `
+	CREATE TABLE IF NOT EXISTS code_mappings (
+		provider_key VARCHAR(255) NOT NULL,
+		code_list VARCHAR(100) NOT NULL,
+		provider_code VARCHAR(255) NOT NULL,
+		unified_code VARCHAR(255) NOT NULL,
+		description TEXT,
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		PRIMARY KEY (provider_key, code_list, provider_code)
+	);`
+
+	if _, err := s.db.Exec(createCodeMappingsTable); err != nil {
+		return fmt.Errorf("failed to create code_mappings table: %w", err)
+	}
+
+	// Create provider_maintenance_windows table for the periods providers are not called
+	createMaintenanceWindowsTable := `
+	CREATE TABLE IF NOT EXISTS provider_maintenance_windows (
+		id BIGSERIAL PRIMARY KEY,
+		provider_key VARCHAR(255) NOT NULL,
+		starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		reason TEXT,
+		serve_cached BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_provider_maintenance_windows_ends_at ON provider_maintenance_windows (ends_at);`
+
+	if _, err := s.db.Exec(createMaintenanceWindowsTable); err != nil {
+		return fmt.Errorf("failed to create provider_maintenance_windows table: %w", err)
+	}
+
+	// Create queries table logging the queries executed by the engine
+	createQueriesTable := `
+	CREATE TABLE IF NOT EXISTS queries (
+		id BIGSERIAL PRIMARY KEY,
+		fingerprint VARCHAR(64) NOT NULL,
+		normalized_query TEXT NOT NULL,
+		operation_name VARCHAR(255),
+		consumer_id VARCHAR(255) NOT NULL,
+		latency_ms BIGINT NOT NULL,
+		providers TEXT[] NOT NULL DEFAULT '{}',
+		row_count BIGINT NOT NULL DEFAULT 0,
+		error_count INTEGER NOT NULL DEFAULT 0,
+		executed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_queries_executed_at ON queries (executed_at);
+	CREATE INDEX IF NOT EXISTS idx_queries_fingerprint_executed_at ON queries (fingerprint, executed_at);`
+
+	if _, err := s.db.Exec(createQueriesTable); err != nil {
+		return fmt.Errorf("failed to create queries table: %w", err)
+	}
+
+	// Create schema_activations table for activations of schema versions scheduled for later
+	createSchemaActivationsTable := `
+	CREATE TABLE IF NOT EXISTS schema_activations (
+		id BIGSERIAL PRIMARY KEY,
+		version VARCHAR(50) NOT NULL,
+		activate_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+		scheduled_by VARCHAR(255),
+		results JSONB,
+		failure_reason TEXT,
+		claimed_at TIMESTAMP WITH TIME ZONE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		completed_at TIMESTAMP WITH TIME ZONE
+	);
+	CREATE INDEX IF NOT EXISTS idx_schema_activations_status_activate_at ON schema_activations (status, activate_at);`
+
+	if _, err := s.db.Exec(createSchemaActivationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_activations table: %w", err)
+	}
+
+	// Create data_export_queries and data_export_jobs tables for the queries registered for bulk data
+	// exports and the jobs running them
+	createDataExportTables := `
+	CREATE TABLE IF NOT EXISTS data_export_queries (
+		id VARCHAR(36) PRIMARY KEY,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		description TEXT,
+		query TEXT NOT NULL,
+		parameters TEXT[] NOT NULL DEFAULT '{}',
+		application_ids TEXT[] NOT NULL DEFAULT '{}',
+		created_by VARCHAR(255),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);
+	CREATE TABLE IF NOT EXISTS data_export_jobs (
+		id VARCHAR(36) PRIMARY KEY,
+		query_id VARCHAR(36) NOT NULL REFERENCES data_export_queries (id),
+		application_id VARCHAR(255) NOT NULL,
+		client_id VARCHAR(255),
+		purpose TEXT NOT NULL,
+		parameters JSONB NOT NULL,
+		row_count INTEGER NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'queued',
+		processed_rows INTEGER NOT NULL DEFAULT 0,
+		failed_rows INTEGER NOT NULL DEFAULT 0,
+		object_key TEXT,
+		archive_size BIGINT NOT NULL DEFAULT 0,
+		archive_sha256 VARCHAR(64),
+		failure_reason TEXT,
+		reviewed_by VARCHAR(255),
+		review_note TEXT,
+		reviewed_at TIMESTAMP WITH TIME ZONE,
+		claimed_at TIMESTAMP WITH TIME ZONE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		completed_at TIMESTAMP WITH TIME ZONE
+	);
+	CREATE INDEX IF NOT EXISTS idx_data_export_jobs_status_created_at ON data_export_jobs (status, created_at);
+	CREATE INDEX IF NOT EXISTS idx_data_export_jobs_application_id ON data_export_jobs (application_id);`
+
+	if _, err := s.db.Exec(createDataExportTables); err != nil {
+		return fmt.Errorf("failed to create data export tables: %w", err)
+	}
+
+	// Create schema_canaries table for the candidate schema versions part of the traffic is routed to;
+	// at most one canary runs at a time
+	createSchemaCanariesTable := `
+	CREATE TABLE IF NOT EXISTS schema_canaries (
+		id BIGSERIAL PRIMARY KEY,
+		version VARCHAR(50) NOT NULL,
+		percentage DOUBLE PRECISION NOT NULL DEFAULT 0,
+		consumer_ids TEXT[] NOT NULL DEFAULT '{}',
+		error_threshold DOUBLE PRECISION NOT NULL,
+		min_requests INTEGER NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'running',
+		started_by VARCHAR(255),
+		end_reason TEXT,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		ended_at TIMESTAMP WITH TIME ZONE
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_schema_canaries_running ON schema_canaries (status) WHERE status = 'running';`
+
+	if _, err := s.db.Exec(createSchemaCanariesTable); err != nil {
+		return fmt.Errorf("failed to create schema_canaries table: %w", err)
+	}
+
+	// Create feature_flags table for the flags gating federation behaviors per consumer application
+	createFeatureFlagsTable := `
+	CREATE TABLE IF NOT EXISTS feature_flags (
+		key VARCHAR(100) PRIMARY KEY,
+		description TEXT,
+		enabled BOOLEAN NOT NULL DEFAULT FALSE,
+		percentage DOUBLE PRECISION NOT NULL DEFAULT 0,
+		application_ids TEXT[] NOT NULL DEFAULT '{}',
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+	);`
+
+	if _, err := s.db.Exec(createFeatureFlagsTable); err != nil {
+		return fmt.Errorf("failed to create feature_flags table: %w", err)
+	}
+
 	return nil
 }
 
@@ -121,7 +297,7 @@ func (s *SchemaDB) GetSchemaByVersion(version string) (*Schema, error) {
 		&schema.Checksum, &schema.IsActive)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("schema version %s not found", version)
+			return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, version)
 		}
 		return nil, fmt.Errorf("failed to get schema: %w", err)
 	}
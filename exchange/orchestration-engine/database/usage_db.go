@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// UsageDB counts the records returned to applications per quota period
+type UsageDB struct {
+	db *sql.DB
+}
+
+// UsageDB returns the usage counters stored alongside the schemas
+func (s *SchemaDB) UsageDB() *UsageDB {
+	return &UsageDB{db: s.db}
+}
+
+// Get returns the records counted for the application in the period
+func (u *UsageDB) Get(ctx context.Context, applicationID, period string) (int64, error) {
+	var records int64
+	err := u.db.QueryRowContext(ctx,
+		`SELECT records FROM application_usage_counters WHERE application_id = $1 AND period = $2`,
+		applicationID, period).Scan(&records)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get usage counter: %w", err)
+	}
+	return records, nil
+}
+
+// Add adds records to the application's count for the period and returns the new count
+func (u *UsageDB) Add(ctx context.Context, applicationID, period string, records int64) (int64, error) {
+	var total int64
+	err := u.db.QueryRowContext(ctx, `
+		INSERT INTO application_usage_counters (application_id, period, records)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (application_id, period)
+		DO UPDATE SET records = application_usage_counters.records + EXCLUDED.records, updated_at = NOW()
+		RETURNING records`,
+		applicationID, period, records).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update usage counter: %w", err)
+	}
+	return total, nil
+}
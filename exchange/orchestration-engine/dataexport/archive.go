@@ -0,0 +1,139 @@
+package dataexport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Result is the outcome of running the query of a job with one set of parameters; it is written
+// to the archive as a line of results.ndjson
+type Result struct {
+	Row        int                    `json:"row"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Errors     []interface{}          `json:"errors,omitempty"`
+}
+
+// manifest describes the archive of a job, written to the archive as manifest.json
+type manifest struct {
+	JobID         string    `json:"jobId"`
+	QueryID       string    `json:"queryId"`
+	QueryName     string    `json:"queryName"`
+	Query         string    `json:"query"`
+	ApplicationID string    `json:"applicationId"`
+	Purpose       string    `json:"purpose,omitempty"`
+	Rows          int       `json:"rows"`
+	FailedRows    int       `json:"failedRows"`
+	CreatedAt     time.Time `json:"createdAt"`
+	CompletedAt   time.Time `json:"completedAt"`
+}
+
+// buildArchive assembles a gzipped tar archive of the manifest and results of a job
+func buildArchive(job *Job, query *Query, results []Result, completedAt time.Time) ([]byte, error) {
+	manifestJSON, err := json.MarshalIndent(manifest{
+		JobID:         job.ID,
+		QueryID:       query.ID,
+		QueryName:     query.Name,
+		Query:         query.Query,
+		ApplicationID: job.ApplicationID,
+		Purpose:       job.Purpose,
+		Rows:          len(results),
+		FailedRows:    job.FailedRows,
+		CreatedAt:     job.CreatedAt,
+		CompletedAt:   completedAt,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	var resultsNDJSON bytes.Buffer
+	encoder := json.NewEncoder(&resultsNDJSON)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return nil, fmt.Errorf("failed to encode result of row %d: %w", result.Row, err)
+		}
+	}
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{"manifest.json", manifestJSON},
+		{"results.ndjson", resultsNDJSON.Bytes()},
+	} {
+		header := &tar.Header{Name: file.name, Mode: 0o644, Size: int64(len(file.content)), ModTime: completedAt}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+		if _, err := tw.Write(file.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress archive: %w", err)
+	}
+	return archive.Bytes(), nil
+}
+
+// jobKey derives the key the archive of a job is encrypted with from the master key, so that each
+// archive has its own key and handing one out does not expose the others
+func jobKey(masterKey []byte, jobID string) []byte {
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write([]byte("data-export:" + jobID))
+	return mac.Sum(nil)
+}
+
+// encryptArchive encrypts an archive with AES-256-GCM. The result is the nonce followed by the
+// ciphertext, with the job ID as additional data so an archive cannot pass for another job's.
+func encryptArchive(key []byte, jobID string, archive []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, archive, []byte(jobID)), nil
+}
+
+// DecryptArchive decrypts an archive downloaded for a job with the key issued with its download link
+func DecryptArchive(key []byte, jobID string, encrypted []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return nil, errors.New("encrypted archive is too short")
+	}
+	nonce, ciphertext := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	archive, err := gcm.Open(nil, nonce, ciphertext, []byte(jobID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt archive: %w", err)
+	}
+	return archive, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,277 @@
+// Package dataexport runs data packages: bulk exports for approved use cases that need data on many
+// data owners at once, such as a statistics department. Admins register the queries a use case may
+// run and the applications allowed to run them. An application submits a job running a registered
+// query once per set of parameters; the job runs against the providers in batches, the results are
+// assembled into an encrypted archive in object storage, and once an admin approves the archive the
+// application gets a signed link to download it, with the key to decrypt it.
+package dataexport
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusQueued is a job waiting to run
+	StatusQueued = "queued"
+	// StatusRunning is a job whose query is running
+	StatusRunning = "running"
+	// StatusAwaitingApproval is a job whose archive is ready and waits for an admin to release it
+	StatusAwaitingApproval = "awaiting_approval"
+	// StatusApproved is a job whose archive an admin released; its download link can be issued
+	StatusApproved = "approved"
+	// StatusRejected is a job whose archive an admin refused to release; the archive is deleted
+	StatusRejected = "rejected"
+	// StatusFailed is a job that could not run or whose archive could not be stored
+	StatusFailed = "failed"
+)
+
+var (
+	// ErrQueryNotFound is returned when a registered query does not exist
+	ErrQueryNotFound = errors.New("registered query not found")
+	// ErrQueryExists is returned when registering a query under a name already in use
+	ErrQueryExists = errors.New("registered query already exists")
+	// ErrJobNotFound is returned when a job does not exist, or belongs to another application
+	ErrJobNotFound = errors.New("data export job not found")
+	// ErrInvalidRequest is returned when a query or job is missing required fields or has invalid ones
+	ErrInvalidRequest = errors.New("invalid data export request")
+	// ErrNotAllowed is returned when an application submits a job for a query it may not run
+	ErrNotAllowed = errors.New("application may not run this query")
+	// ErrNotAwaitingApproval is returned when reviewing a job whose archive is not awaiting approval
+	ErrNotAwaitingApproval = errors.New("data export job is not awaiting approval")
+	// ErrNotApproved is returned when requesting the download link of a job that is not approved
+	ErrNotApproved = errors.New("data export job is not approved")
+)
+
+// Query is a GraphQL query registered for data exports. Jobs run it once for each set of values
+// of its parameters, which are the query's variables.
+type Query struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Query       string `json:"query"`
+	// Parameters are the variables every set of parameters of a job must set
+	Parameters []string `json:"parameters"`
+	// ApplicationIDs are the applications allowed to submit jobs running the query
+	ApplicationIDs []string  `json:"applicationIds"`
+	CreatedBy      string    `json:"createdBy,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Allows reports whether an application may submit jobs running the query
+func (q *Query) Allows(applicationID string) bool {
+	return slices.Contains(q.ApplicationIDs, applicationID)
+}
+
+// Job is a run of a registered query for an application
+type Job struct {
+	ID            string `json:"id"`
+	QueryID       string `json:"queryId"`
+	ApplicationID string `json:"applicationId"`
+	// ClientID is the client the application submitted the job with; the query runs as that client
+	ClientID string `json:"clientId,omitempty"`
+	// Purpose is why the application needs the data, shown to the approving admin
+	Purpose string `json:"purpose,omitempty"`
+	// Parameters are the sets of parameter values the query runs with, one row of the export each
+	Parameters []map[string]interface{} `json:"-"`
+	Rows       int                      `json:"rows"`
+	Status     string                   `json:"status"`
+	// ProcessedRows and FailedRows count the rows run so far, and those that returned errors
+	ProcessedRows int `json:"processedRows"`
+	FailedRows    int `json:"failedRows"`
+	// ObjectKey, ArchiveSize and ArchiveSHA256 describe the encrypted archive in object storage
+	ObjectKey     string `json:"-"`
+	ArchiveSize   int64  `json:"archiveSize,omitempty"`
+	ArchiveSHA256 string `json:"archiveSha256,omitempty"`
+	// FailureReason explains why a failed job has no archive
+	FailureReason string     `json:"failureReason,omitempty"`
+	ReviewedBy    string     `json:"reviewedBy,omitempty"`
+	ReviewNote    string     `json:"reviewNote,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+}
+
+// Store persists registered queries and jobs
+type Store interface {
+	// CreateQuery stores a new query, returning ErrQueryExists if its name is in use
+	CreateQuery(ctx context.Context, query *Query) (*Query, error)
+	GetQuery(ctx context.Context, id string) (*Query, error)
+	// ListQueries returns the registered queries ordered by name
+	ListQueries(ctx context.Context) ([]*Query, error)
+
+	CreateJob(ctx context.Context, job *Job) (*Job, error)
+	GetJob(ctx context.Context, id string) (*Job, error)
+	// ListJobs returns the jobs of an application, or of every application for an empty ID, with the
+	// given status, or any status for an empty one, newest first
+	ListJobs(ctx context.Context, applicationID, status string) ([]*Job, error)
+	// ClaimNext marks the oldest queued job as running and returns it, or returns nil when no job is
+	// queued. A job is claimed once, even by several replicas.
+	ClaimNext(ctx context.Context, now time.Time) (*Job, error)
+	// UpdateJob records the progress or the outcome of a claimed job
+	UpdateJob(ctx context.Context, job *Job) error
+	// ReviewJob approves or rejects a job awaiting approval, returning ErrNotAwaitingApproval if it is
+	// not awaiting approval
+	ReviewJob(ctx context.Context, id, status, reviewedBy, note string, at time.Time) (*Job, error)
+}
+
+// MemoryStore keeps queries and jobs in memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	queries map[string]*Query
+	jobs    map[string]*Job
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{queries: make(map[string]*Query), jobs: make(map[string]*Job)}
+}
+
+// CreateQuery stores a new query
+func (s *MemoryStore) CreateQuery(_ context.Context, query *Query) (*Query, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.queries {
+		if existing.Name == query.Name {
+			return nil, ErrQueryExists
+		}
+	}
+	stored := copyQuery(query)
+	stored.CreatedAt = time.Now().UTC()
+	s.queries[stored.ID] = stored
+	return copyQuery(stored), nil
+}
+
+// GetQuery returns a registered query
+func (s *MemoryStore) GetQuery(_ context.Context, id string) (*Query, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query, ok := s.queries[id]
+	if !ok {
+		return nil, ErrQueryNotFound
+	}
+	return copyQuery(query), nil
+}
+
+// ListQueries returns the registered queries ordered by name
+func (s *MemoryStore) ListQueries(_ context.Context) ([]*Query, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queries := make([]*Query, 0, len(s.queries))
+	for _, query := range s.queries {
+		queries = append(queries, copyQuery(query))
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	return queries, nil
+}
+
+// CreateJob stores a new job
+func (s *MemoryStore) CreateJob(_ context.Context, job *Job) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := copyJob(job)
+	stored.CreatedAt = time.Now().UTC()
+	s.jobs[stored.ID] = stored
+	return copyJob(stored), nil
+}
+
+// GetJob returns a job
+func (s *MemoryStore) GetJob(_ context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return copyJob(job), nil
+}
+
+// ListJobs returns the jobs of an application with the given status, newest first
+func (s *MemoryStore) ListJobs(_ context.Context, applicationID, status string) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		if (applicationID == "" || job.ApplicationID == applicationID) && (status == "" || job.Status == status) {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID > jobs[j].ID
+	})
+	return jobs, nil
+}
+
+// ClaimNext marks the oldest queued job as running and returns it
+func (s *MemoryStore) ClaimNext(_ context.Context, _ time.Time) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next *Job
+	for _, job := range s.jobs {
+		if job.Status == StatusQueued && (next == nil || job.CreatedAt.Before(next.CreatedAt)) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.Status = StatusRunning
+	return copyJob(next), nil
+}
+
+// UpdateJob records the progress or the outcome of a claimed job
+func (s *MemoryStore) UpdateJob(_ context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; !ok {
+		return ErrJobNotFound
+	}
+	s.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// ReviewJob approves or rejects a job awaiting approval
+func (s *MemoryStore) ReviewJob(_ context.Context, id, status, reviewedBy, note string, at time.Time) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if job.Status != StatusAwaitingApproval {
+		return nil, ErrNotAwaitingApproval
+	}
+	job.Status = status
+	job.ReviewedBy = reviewedBy
+	job.ReviewNote = note
+	job.ReviewedAt = &at
+	return copyJob(job), nil
+}
+
+func copyQuery(query *Query) *Query {
+	copied := *query
+	copied.Parameters = append([]string(nil), query.Parameters...)
+	copied.ApplicationIDs = append([]string(nil), query.ApplicationIDs...)
+	return &copied
+}
+
+func copyJob(job *Job) *Job {
+	copied := *job
+	copied.Parameters = append([]map[string]interface{}(nil), job.Parameters...)
+	if job.ReviewedAt != nil {
+		reviewedAt := *job.ReviewedAt
+		copied.ReviewedAt = &reviewedAt
+	}
+	if job.CompletedAt != nil {
+		completedAt := *job.CompletedAt
+		copied.CompletedAt = &completedAt
+	}
+	return &copied
+}
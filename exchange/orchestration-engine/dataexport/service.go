@@ -0,0 +1,426 @@
+package dataexport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	// DefaultBatchSize is how many rows of a job are run against the providers at once
+	DefaultBatchSize = 25
+	// DefaultMaxRows bounds the rows of a job
+	DefaultMaxRows = 10000
+	// DefaultLinkTTL is how long download links are valid
+	DefaultLinkTTL = time.Hour
+	// DefaultCheckInterval is how often the worker looks for queued jobs
+	DefaultCheckInterval = 5 * time.Second
+	// EncryptionAlgorithm is how archives are encrypted: AES-256-GCM, the 12 byte nonce followed by
+	// the ciphertext, with the job ID as additional data
+	EncryptionAlgorithm = "AES-256-GCM"
+	// archiveContentType is the content type archives are stored with
+	archiveContentType = "application/octet-stream"
+)
+
+// Runner runs a GraphQL query against the providers as a consumer application
+type Runner func(ctx context.Context, request graphql.Request, consumer *auth.ConsumerAssertion) graphql.Response
+
+// Options tune how jobs are run and archives are released; zero values use the defaults
+type Options struct {
+	BatchSize     int
+	MaxRows       int
+	LinkTTL       time.Duration
+	CheckInterval time.Duration
+}
+
+// DownloadLink is a signed link to the encrypted archive of an approved job, with the key to decrypt it
+type DownloadLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// EncryptionKey is the base64 encoded key of the archive
+	EncryptionKey string `json:"encryptionKey"`
+	Algorithm     string `json:"algorithm"`
+	// ArchiveSHA256 is the hex SHA-256 of the encrypted archive, to check the download against
+	ArchiveSHA256 string `json:"archiveSha256"`
+}
+
+// Service registers export queries, runs the jobs submitted for them and releases their archives
+// once an admin approves them
+type Service struct {
+	store         Store
+	storage       Storage
+	run           Runner
+	masterKey     []byte
+	batchSize     int
+	maxRows       int
+	linkTTL       time.Duration
+	checkInterval time.Duration
+	now           func() time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewService creates the data export service. Archives are stored in storage, encrypted with keys
+// derived from the 32 byte masterKey, and the queries of jobs are run with run. Queued jobs are run
+// by Start, or by RunNext.
+func NewService(store Store, storage Storage, masterKey []byte, run Runner, opts Options) (*Service, error) {
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("data export encryption key must be 32 bytes, got %d", len(masterKey))
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = DefaultMaxRows
+	}
+	if opts.LinkTTL <= 0 {
+		opts.LinkTTL = DefaultLinkTTL
+	}
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = DefaultCheckInterval
+	}
+	return &Service{
+		store:         store,
+		storage:       storage,
+		run:           run,
+		masterKey:     masterKey,
+		batchSize:     opts.BatchSize,
+		maxRows:       opts.MaxRows,
+		linkTTL:       opts.LinkTTL,
+		checkInterval: opts.CheckInterval,
+		now:           time.Now,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// RegisterQuery registers a query the given applications may export data with. Its parameters are
+// the variables the query declares.
+func (s *Service) RegisterQuery(ctx context.Context, query *Query, createdBy string) (*Query, error) {
+	query.Name = strings.TrimSpace(query.Name)
+	if query.Name == "" || strings.TrimSpace(query.Query) == "" {
+		return nil, fmt.Errorf("%w: name and query are required", ErrInvalidRequest)
+	}
+	if len(query.ApplicationIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one application must be allowed to run the query", ErrInvalidRequest)
+	}
+	parameters, err := queryParameters(query.Query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	query.ID = uuid.New().String()
+	query.Parameters = parameters
+	query.CreatedBy = createdBy
+	created, err := s.store.CreateQuery(ctx, query)
+	if err != nil {
+		if errors.Is(err, ErrQueryExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to register export query: %w", err)
+	}
+	logger.Log.Info("Data export query registered", "id", created.ID, "name", created.Name, "applications", created.ApplicationIDs)
+	return created, nil
+}
+
+// ListQueries returns the registered queries
+func (s *Service) ListQueries(ctx context.Context) ([]*Query, error) {
+	return s.store.ListQueries(ctx)
+}
+
+// Submit queues a job running a registered query once for each set of parameters, as the consumer
+func (s *Service) Submit(ctx context.Context, consumer *auth.ConsumerAssertion, queryID, purpose string, parameters []map[string]interface{}) (*Job, error) {
+	query, err := s.store.GetQuery(ctx, queryID)
+	if err != nil {
+		return nil, err
+	}
+	if !query.Allows(consumer.ApplicationID) {
+		return nil, ErrNotAllowed
+	}
+	if strings.TrimSpace(purpose) == "" {
+		return nil, fmt.Errorf("%w: purpose is required", ErrInvalidRequest)
+	}
+	if len(parameters) == 0 {
+		return nil, fmt.Errorf("%w: at least one set of parameters is required", ErrInvalidRequest)
+	}
+	if len(parameters) > s.maxRows {
+		return nil, fmt.Errorf("%w: a job may have at most %d sets of parameters", ErrInvalidRequest, s.maxRows)
+	}
+	for i, row := range parameters {
+		if err := checkParameters(query.Parameters, row); err != nil {
+			return nil, fmt.Errorf("%w: parameters %d: %v", ErrInvalidRequest, i+1, err)
+		}
+	}
+
+	job, err := s.store.CreateJob(ctx, &Job{
+		ID:            uuid.New().String(),
+		QueryID:       query.ID,
+		ApplicationID: consumer.ApplicationID,
+		ClientID:      consumer.ClientID,
+		Purpose:       strings.TrimSpace(purpose),
+		Parameters:    parameters,
+		Rows:          len(parameters),
+		Status:        StatusQueued,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit data export job: %w", err)
+	}
+	logger.Log.Info("Data export job submitted", "id", job.ID, "query", query.Name, "applicationId", job.ApplicationID, "rows", job.Rows)
+	return job, nil
+}
+
+// Get returns a job of an application, or of any application for an empty ID
+func (s *Service) Get(ctx context.Context, applicationID, id string) (*Job, error) {
+	job, err := s.store.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if applicationID != "" && job.ApplicationID != applicationID {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// List returns the jobs of an application, or of every application for an empty ID, with the given
+// status, or any status for an empty one
+func (s *Service) List(ctx context.Context, applicationID, status string) ([]*Job, error) {
+	switch status {
+	case "", StatusQueued, StatusRunning, StatusAwaitingApproval, StatusApproved, StatusRejected, StatusFailed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidRequest, status)
+	}
+	return s.store.ListJobs(ctx, applicationID, status)
+}
+
+// Approve releases the archive of a job to its application
+func (s *Service) Approve(ctx context.Context, id, reviewedBy, note string) (*Job, error) {
+	job, err := s.store.ReviewJob(ctx, id, StatusApproved, reviewedBy, note, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Data export job approved", "id", job.ID, "applicationId", job.ApplicationID, "reviewedBy", reviewedBy)
+	return job, nil
+}
+
+// Reject refuses to release the archive of a job and deletes it
+func (s *Service) Reject(ctx context.Context, id, reviewedBy, note string) (*Job, error) {
+	job, err := s.store.ReviewJob(ctx, id, StatusRejected, reviewedBy, note, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.Delete(ctx, job.ObjectKey); err != nil {
+		logger.Log.Error("Failed to delete archive of rejected data export job", "id", job.ID, "key", job.ObjectKey, "error", err)
+	}
+	logger.Log.Info("Data export job rejected", "id", job.ID, "applicationId", job.ApplicationID, "reviewedBy", reviewedBy)
+	return job, nil
+}
+
+// DownloadLink issues a signed link to the archive of an approved job of an application
+func (s *Service) DownloadLink(ctx context.Context, applicationID, id string) (*DownloadLink, error) {
+	job, err := s.Get(ctx, applicationID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != StatusApproved {
+		return nil, ErrNotApproved
+	}
+	expiresAt := s.now().UTC().Add(s.linkTTL)
+	url, err := s.storage.PresignGet(job.ObjectKey, "data-export-"+job.ID+".tar.gz.enc", s.linkTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign download link: %w", err)
+	}
+	logger.Log.Info("Data export download link issued", "id", job.ID, "applicationId", job.ApplicationID, "expiresAt", expiresAt)
+	return &DownloadLink{
+		URL:           url,
+		ExpiresAt:     expiresAt,
+		EncryptionKey: base64.StdEncoding.EncodeToString(jobKey(s.masterKey, job.ID)),
+		Algorithm:     EncryptionAlgorithm,
+		ArchiveSHA256: job.ArchiveSHA256,
+	}, nil
+}
+
+// Start runs queued jobs in the background until Stop is called
+func (s *Service) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				for s.RunNext(ctx) {
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the background runs started by Start. A running job is interrupted and left running,
+// to be run again once its claim goes stale.
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+// RunNext runs the oldest queued job and reports whether there was one
+func (s *Service) RunNext(ctx context.Context) bool {
+	job, err := s.store.ClaimNext(ctx, s.now())
+	if err != nil {
+		logger.Log.Error("Failed to claim data export job", "error", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+	s.runJob(ctx, job)
+	return ctx.Err() == nil
+}
+
+// runJob runs the query of a job with each set of its parameters, in batches, and stores the
+// encrypted archive of the results for approval
+func (s *Service) runJob(ctx context.Context, job *Job) {
+	logger.Log.Info("Data export job started", "id", job.ID, "rows", job.Rows)
+	query, err := s.store.GetQuery(ctx, job.QueryID)
+	if err != nil {
+		s.fail(ctx, job, fmt.Sprintf("failed to load export query: %v", err))
+		return
+	}
+
+	consumer := &auth.ConsumerAssertion{ApplicationID: job.ApplicationID, ClientID: job.ClientID}
+	results := make([]Result, len(job.Parameters))
+	job.ProcessedRows, job.FailedRows = 0, 0
+	for start := 0; start < len(job.Parameters); start += s.batchSize {
+		end := min(start+s.batchSize, len(job.Parameters))
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				response := s.run(ctx, graphql.Request{Query: query.Query, Variables: job.Parameters[i]}, consumer)
+				results[i] = Result{Row: i + 1, Parameters: job.Parameters[i], Data: response.Data, Errors: response.Errors}
+			}(i)
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			logger.Log.Warn("Data export job interrupted", "id", job.ID, "processedRows", job.ProcessedRows)
+			return
+		}
+
+		for _, result := range results[start:end] {
+			if len(result.Errors) > 0 {
+				job.FailedRows++
+			}
+		}
+		job.ProcessedRows = end
+		if err := s.store.UpdateJob(ctx, job); err != nil {
+			logger.Log.Error("Failed to record data export job progress", "id", job.ID, "error", err)
+		}
+	}
+
+	completedAt := s.now().UTC()
+	archive, err := buildArchive(job, query, results, completedAt)
+	if err != nil {
+		s.fail(ctx, job, err.Error())
+		return
+	}
+	encrypted, err := encryptArchive(jobKey(s.masterKey, job.ID), job.ID, archive)
+	if err != nil {
+		s.fail(ctx, job, err.Error())
+		return
+	}
+	key := "data-exports/" + job.ID + ".tar.gz.enc"
+	if err := s.storage.Put(ctx, key, archiveContentType, encrypted); err != nil {
+		s.fail(ctx, job, fmt.Sprintf("failed to store archive: %v", err))
+		return
+	}
+
+	sum := sha256.Sum256(encrypted)
+	job.ObjectKey = key
+	job.ArchiveSize = int64(len(encrypted))
+	job.ArchiveSHA256 = hex.EncodeToString(sum[:])
+	job.Status = StatusAwaitingApproval
+	job.CompletedAt = &completedAt
+	if err := s.store.UpdateJob(ctx, job); err != nil {
+		logger.Log.Error("Failed to record data export job outcome", "id", job.ID, "error", err)
+		return
+	}
+	logger.Log.Info("Data export job awaiting approval", "id", job.ID, "rows", job.Rows, "failedRows", job.FailedRows)
+}
+
+// fail records that a job could not produce an archive
+func (s *Service) fail(ctx context.Context, job *Job, reason string) {
+	completedAt := s.now().UTC()
+	job.Status = StatusFailed
+	job.FailureReason = reason
+	job.CompletedAt = &completedAt
+	logger.Log.Warn("Data export job failed", "id", job.ID, "reason", reason)
+	if err := s.store.UpdateJob(ctx, job); err != nil {
+		logger.Log.Error("Failed to record data export job outcome", "id", job.ID, "status", job.Status, "error", err)
+	}
+}
+
+// queryParameters returns the variables declared by a query, which must be a single query operation
+func queryParameters(query string) ([]string, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query), Name: "Query"})})
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		if op, ok := definition.(*ast.OperationDefinition); ok {
+			if operation != nil {
+				return nil, errors.New("the query must have a single operation")
+			}
+			operation = op
+		}
+	}
+	if operation == nil || operation.Operation != ast.OperationTypeQuery {
+		return nil, errors.New("the query must have a single query operation")
+	}
+	parameters := make([]string, 0, len(operation.VariableDefinitions))
+	for _, variable := range operation.VariableDefinitions {
+		parameters = append(parameters, variable.Variable.Name.Value)
+	}
+	return parameters, nil
+}
+
+// checkParameters checks that a set of parameters sets each parameter of a query, and nothing else
+func checkParameters(parameters []string, row map[string]interface{}) error {
+	for _, name := range parameters {
+		if value, ok := row[name]; !ok || value == nil {
+			return fmt.Errorf("%s is required", name)
+		}
+	}
+	for name := range row {
+		if !slices.Contains(parameters, name) {
+			return fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package dataexport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Initialize logger for tests
+	logger.Init()
+}
+
+const testQuery = `query Person($nic: String!) { personInfo(nic: $nic) { fullName } }`
+
+// memoryStorage keeps archives in memory
+type memoryStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	failPut bool
+}
+
+func (m *memoryStorage) Put(_ context.Context, key, _ string, content []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failPut {
+		return errors.New("bucket unavailable")
+	}
+	m.objects[key] = content
+	return nil
+}
+
+func (m *memoryStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memoryStorage) PresignGet(key, _ string, _ time.Duration) (string, error) {
+	return "https://storage.test/" + key + "?X-Amz-Signature=signed", nil
+}
+
+func newTestService(t *testing.T, run Runner) (*Service, *memoryStorage) {
+	t.Helper()
+	if run == nil {
+		run = func(_ context.Context, request graphql.Request, _ *auth.ConsumerAssertion) graphql.Response {
+			nic := request.Variables["nic"].(string)
+			if nic == "000000000V" {
+				return graphql.Response{Errors: []interface{}{map[string]interface{}{"message": "not found"}}}
+			}
+			return graphql.Response{Data: map[string]interface{}{"personInfo": map[string]interface{}{"fullName": "Person " + nic}}}
+		}
+	}
+	storage := &memoryStorage{objects: map[string][]byte{}}
+	service, err := NewService(NewMemoryStore(), storage, bytes.Repeat([]byte{7}, 32), run, Options{BatchSize: 2, MaxRows: 5})
+	require.NoError(t, err)
+	return service, storage
+}
+
+func registerTestQuery(t *testing.T, service *Service) *Query {
+	t.Helper()
+	query, err := service.RegisterQuery(context.Background(), &Query{
+		Name: "census-persons", Query: testQuery, ApplicationIDs: []string{"stats-app"},
+	}, "admin@example.com")
+	require.NoError(t, err)
+	return query
+}
+
+// readArchive decrypts and unpacks an archive into its files
+func readArchive(t *testing.T, key []byte, jobID string, encrypted []byte) map[string]string {
+	t.Helper()
+	archive, err := DecryptArchive(key, jobID, encrypted)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	return files
+}
+
+func TestService_RegisterQuery(t *testing.T) {
+	service, _ := newTestService(t, nil)
+	ctx := context.Background()
+
+	query := registerTestQuery(t, service)
+	assert.Equal(t, []string{"nic"}, query.Parameters)
+	assert.Equal(t, "admin@example.com", query.CreatedBy)
+
+	_, err := service.RegisterQuery(ctx, &Query{Name: "census-persons", Query: testQuery, ApplicationIDs: []string{"a"}}, "")
+	assert.ErrorIs(t, err, ErrQueryExists)
+
+	for name, query := range map[string]*Query{
+		"no applications": {Name: "q", Query: testQuery},
+		"invalid query":   {Name: "q", Query: "query {", ApplicationIDs: []string{"a"}},
+		"mutation":        {Name: "q", Query: `mutation { reset }`, ApplicationIDs: []string{"a"}},
+		"no name":         {Query: testQuery, ApplicationIDs: []string{"a"}},
+	} {
+		_, err := service.RegisterQuery(ctx, query, "")
+		assert.ErrorIs(t, err, ErrInvalidRequest, name)
+	}
+}
+
+func TestService_Submit_ValidatesJob(t *testing.T) {
+	service, _ := newTestService(t, nil)
+	ctx := context.Background()
+	query := registerTestQuery(t, service)
+	consumer := &auth.ConsumerAssertion{ApplicationID: "stats-app"}
+	rows := func(n int) []map[string]interface{} {
+		parameters := make([]map[string]interface{}, n)
+		for i := range parameters {
+			parameters[i] = map[string]interface{}{"nic": "199012345678"}
+		}
+		return parameters
+	}
+
+	_, err := service.Submit(ctx, &auth.ConsumerAssertion{ApplicationID: "other-app"}, query.ID, "census", rows(1))
+	assert.ErrorIs(t, err, ErrNotAllowed)
+	_, err = service.Submit(ctx, consumer, "missing", "census", rows(1))
+	assert.ErrorIs(t, err, ErrQueryNotFound)
+	_, err = service.Submit(ctx, consumer, query.ID, "", rows(1))
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = service.Submit(ctx, consumer, query.ID, "census", nil)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = service.Submit(ctx, consumer, query.ID, "census", rows(6))
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = service.Submit(ctx, consumer, query.ID, "census", []map[string]interface{}{{}})
+	assert.ErrorContains(t, err, "nic is required")
+	_, err = service.Submit(ctx, consumer, query.ID, "census", []map[string]interface{}{{"nic": "1", "limit": 5}})
+	assert.ErrorContains(t, err, "unknown parameter limit")
+
+	job, err := service.Submit(ctx, consumer, query.ID, "census", rows(5))
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Equal(t, 5, job.Rows)
+}
+
+func TestService_RunApproveAndDownload(t *testing.T) {
+	var mu sync.Mutex
+	var consumers []string
+	service, storage := newTestService(t, func(_ context.Context, request graphql.Request, consumer *auth.ConsumerAssertion) graphql.Response {
+		mu.Lock()
+		consumers = append(consumers, consumer.ApplicationID+"/"+consumer.ClientID)
+		mu.Unlock()
+		nic := request.Variables["nic"].(string)
+		if nic == "000000000V" {
+			return graphql.Response{Errors: []interface{}{map[string]interface{}{"message": "not found"}}}
+		}
+		return graphql.Response{Data: map[string]interface{}{"personInfo": map[string]interface{}{"fullName": "Person " + nic}}}
+	})
+	ctx := context.Background()
+	query := registerTestQuery(t, service)
+
+	job, err := service.Submit(ctx, &auth.ConsumerAssertion{ApplicationID: "stats-app", ClientID: "stats-client"}, query.ID,
+		"annual census", []map[string]interface{}{{"nic": "199012345678"}, {"nic": "000000000V"}, {"nic": "198512345678"}})
+	require.NoError(t, err)
+
+	_, err = service.DownloadLink(ctx, "stats-app", job.ID)
+	assert.ErrorIs(t, err, ErrNotApproved)
+
+	assert.True(t, service.RunNext(ctx))
+	assert.False(t, service.RunNext(ctx))
+	assert.Equal(t, []string{"stats-app/stats-client", "stats-app/stats-client", "stats-app/stats-client"}, consumers)
+
+	job, err = service.Get(ctx, "stats-app", job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusAwaitingApproval, job.Status)
+	assert.Equal(t, 3, job.ProcessedRows)
+	assert.Equal(t, 1, job.FailedRows)
+	assert.Equal(t, "data-exports/"+job.ID+".tar.gz.enc", job.ObjectKey)
+	encrypted := storage.objects[job.ObjectKey]
+	sum := sha256.Sum256(encrypted)
+	assert.Equal(t, hex.EncodeToString(sum[:]), job.ArchiveSHA256)
+	assert.Equal(t, int64(len(encrypted)), job.ArchiveSize)
+
+	// Jobs are scoped to their application
+	_, err = service.Get(ctx, "other-app", job.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	_, err = service.DownloadLink(ctx, "stats-app", job.ID)
+	assert.ErrorIs(t, err, ErrNotApproved)
+
+	approved, err := service.Approve(ctx, job.ID, "reviewer@example.com", "aggregate use only")
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, approved.Status)
+	assert.Equal(t, "reviewer@example.com", approved.ReviewedBy)
+	_, err = service.Reject(ctx, job.ID, "reviewer@example.com", "")
+	assert.ErrorIs(t, err, ErrNotAwaitingApproval)
+
+	_, err = service.DownloadLink(ctx, "other-app", job.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+	link, err := service.DownloadLink(ctx, "stats-app", job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.test/"+job.ObjectKey+"?X-Amz-Signature=signed", link.URL)
+	assert.Equal(t, EncryptionAlgorithm, link.Algorithm)
+	assert.Equal(t, job.ArchiveSHA256, link.ArchiveSHA256)
+	assert.WithinDuration(t, time.Now().Add(DefaultLinkTTL), link.ExpiresAt, time.Minute)
+
+	key, err := base64.StdEncoding.DecodeString(link.EncryptionKey)
+	require.NoError(t, err)
+	files := readArchive(t, key, job.ID, encrypted)
+
+	var manifest map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(files["manifest.json"]), &manifest))
+	assert.Equal(t, "census-persons", manifest["queryName"])
+	assert.Equal(t, "annual census", manifest["purpose"])
+	assert.EqualValues(t, 3, manifest["rows"])
+	assert.EqualValues(t, 1, manifest["failedRows"])
+
+	lines := strings.Split(strings.TrimSpace(files["results.ndjson"]), "\n")
+	require.Len(t, lines, 3)
+	var results []Result
+	for _, line := range lines {
+		var result Result
+		require.NoError(t, json.Unmarshal([]byte(line), &result))
+		results = append(results, result)
+	}
+	assert.Equal(t, 1, results[0].Row)
+	assert.Equal(t, "199012345678", results[0].Parameters["nic"])
+	assert.Equal(t, map[string]interface{}{"fullName": "Person 199012345678"}, results[0].Data["personInfo"])
+	assert.Len(t, results[1].Errors, 1)
+	assert.Equal(t, 3, results[2].Row)
+
+	// The key of one archive does not decrypt another job's
+	_, err = DecryptArchive(key, "another-job", encrypted)
+	assert.Error(t, err)
+}
+
+func TestService_Reject_DeletesArchive(t *testing.T) {
+	service, storage := newTestService(t, nil)
+	ctx := context.Background()
+	query := registerTestQuery(t, service)
+	job, err := service.Submit(ctx, &auth.ConsumerAssertion{ApplicationID: "stats-app"}, query.ID, "census",
+		[]map[string]interface{}{{"nic": "199012345678"}})
+	require.NoError(t, err)
+	require.True(t, service.RunNext(ctx))
+
+	job, err = service.Get(ctx, "", job.ID)
+	require.NoError(t, err)
+	require.Contains(t, storage.objects, job.ObjectKey)
+
+	rejected, err := service.Reject(ctx, job.ID, "reviewer@example.com", "identifiable data")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRejected, rejected.Status)
+	assert.NotContains(t, storage.objects, job.ObjectKey)
+	_, err = service.DownloadLink(ctx, "stats-app", job.ID)
+	assert.ErrorIs(t, err, ErrNotApproved)
+}
+
+func TestService_RunNext_FailsJobWhenArchiveCannotBeStored(t *testing.T) {
+	service, storage := newTestService(t, nil)
+	storage.failPut = true
+	ctx := context.Background()
+	query := registerTestQuery(t, service)
+	job, err := service.Submit(ctx, &auth.ConsumerAssertion{ApplicationID: "stats-app"}, query.ID, "census",
+		[]map[string]interface{}{{"nic": "199012345678"}})
+	require.NoError(t, err)
+	require.True(t, service.RunNext(ctx))
+
+	job, err = service.Get(ctx, "stats-app", job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Contains(t, job.FailureReason, "bucket unavailable")
+	assert.NotNil(t, job.CompletedAt)
+
+	jobs, err := service.List(ctx, "stats-app", StatusFailed)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	_, err = service.List(ctx, "", "unknown")
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestNewService_RequiresA256BitKey(t *testing.T) {
+	_, err := NewService(NewMemoryStore(), &memoryStorage{}, []byte("short"), nil, Options{})
+	assert.Error(t, err)
+}
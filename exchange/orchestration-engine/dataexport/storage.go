@@ -0,0 +1,60 @@
+package dataexport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gov-dx-sandbox/shared/s3"
+)
+
+// Storage stores the archives of data exports
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, content []byte) error
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a URL that downloads the object as fileName, without credentials, until ttl passes
+	PresignGet(key, fileName string, ttl time.Duration) (string, error)
+}
+
+// S3Storage stores archives in a bucket of an S3-compatible object store, such as AWS S3 or MinIO
+type S3Storage struct {
+	client *s3.Client
+}
+
+// NewS3Storage creates an S3 storage for the bucket served at endpoint
+func NewS3Storage(endpoint, bucket, region, accessKey, secretKey string, pathStyle bool) (*S3Storage, error) {
+	client, err := s3.NewClient(s3.Config{
+		Endpoint:        endpoint,
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		PathStyle:       pathStyle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &S3Storage{client: client}, nil
+}
+
+// Put uploads an object, replacing any object with the same key
+func (s *S3Storage) Put(ctx context.Context, key, contentType string, content []byte) error {
+	if err := s.client.Put(ctx, key, contentType, content); err != nil {
+		return fmt.Errorf("failed to upload archive: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an object. Deleting a missing object succeeds, as it does in S3.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if err := s.client.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete archive: %w", err)
+	}
+	return nil
+}
+
+// PresignGet returns a presigned GET URL. When fileName is set, the response asks browsers to save
+// the object under that name instead of displaying it.
+func (s *S3Storage) PresignGet(key, fileName string, ttl time.Duration) (string, error) {
+	return s.client.PresignGet(key, fileName, ttl)
+}
@@ -0,0 +1,47 @@
+package dataexport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Storage(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") ||
+			r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	storage, err := NewS3Storage(server.URL, "exports", "us-east-1", "access", "secret", true)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, "data-exports/job_1.tar.gz.enc", "application/octet-stream", []byte("archive")))
+	assert.Equal(t, "archive", objects["/exports/data-exports/job_1.tar.gz.enc"])
+
+	require.NoError(t, storage.Delete(ctx, "data-exports/job_1.tar.gz.enc"))
+	assert.Empty(t, objects)
+
+	storage, err = NewS3Storage(server.URL, "exports", "us-east-1", "wrong", "secret", true)
+	require.NoError(t, err)
+	assert.ErrorContains(t, storage.Put(ctx, "data-exports/job_1.tar.gz.enc", "application/octet-stream", nil), "status 403")
+}
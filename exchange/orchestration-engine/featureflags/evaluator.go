@@ -0,0 +1,162 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+const (
+	// DefaultRefreshInterval is how long the flags are used before they are reloaded from the store,
+	// so that changes made through other replicas take effect
+	DefaultRefreshInterval = 30 * time.Second
+	// refreshTimeout bounds a reload of the flags
+	refreshTimeout = 10 * time.Second
+)
+
+// Evaluator answers whether a flag is on for a consumer application with the flags of a store. The
+// flags are cached in memory, so evaluating a flag does not query the store.
+type Evaluator struct {
+	store           Store
+	refreshInterval time.Duration
+
+	mu         sync.RWMutex
+	flags      map[string]*Flag
+	loadedAt   time.Time
+	refreshing bool
+}
+
+// NewEvaluator creates an evaluator for the flags of store; non-positive intervals use
+// DefaultRefreshInterval. The flags are loaded on the first call to Enabled, or by Refresh.
+func NewEvaluator(store Store, refreshInterval time.Duration) *Evaluator {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &Evaluator{store: store, refreshInterval: refreshInterval, flags: make(map[string]*Flag)}
+}
+
+// Refresh reloads the flags from the store
+func (e *Evaluator) Refresh(ctx context.Context) error {
+	list, err := e.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	flags := make(map[string]*Flag, len(list))
+	for _, flag := range list {
+		flags[flag.Key] = flag
+	}
+
+	e.mu.Lock()
+	e.flags = flags
+	e.loadedAt = time.Now()
+	e.mu.Unlock()
+	return nil
+}
+
+// Enabled reports whether a flag is on for a consumer application. Unknown flags are off, and so is
+// every flag of a nil evaluator. Stale flags are reloaded in the background, so a query is never held
+// up by the store.
+func (e *Evaluator) Enabled(key, applicationID string) bool {
+	if e == nil {
+		return false
+	}
+	e.mu.RLock()
+	flag := e.flags[key]
+	stale := time.Since(e.loadedAt) >= e.refreshInterval
+	e.mu.RUnlock()
+	if stale {
+		e.refreshInBackground()
+	}
+	return flag != nil && flag.EnabledFor(applicationID)
+}
+
+// refreshInBackground starts a reload of the flags unless one is already running
+func (e *Evaluator) refreshInBackground() {
+	e.mu.Lock()
+	if e.refreshing {
+		e.mu.Unlock()
+		return
+	}
+	e.refreshing = true
+	e.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		if err := e.Refresh(ctx); err != nil {
+			logger.Log.Warn("Failed to refresh feature flags, using the previous flags", "error", err)
+			// Wait for the next interval before retrying
+			e.mu.Lock()
+			e.loadedAt = time.Now()
+			e.mu.Unlock()
+		}
+		e.mu.Lock()
+		e.refreshing = false
+		e.mu.Unlock()
+	}()
+}
+
+// List returns the flags of the store
+func (e *Evaluator) List(ctx context.Context) ([]*Flag, error) {
+	return e.store.List(ctx)
+}
+
+// Put creates or replaces a flag. It takes effect immediately on this replica, and on the others once
+// they refresh their flags.
+func (e *Evaluator) Put(ctx context.Context, flag *Flag) (*Flag, error) {
+	flag.Key = strings.TrimSpace(flag.Key)
+	if !keyPattern.MatchString(flag.Key) {
+		return nil, fmt.Errorf("%w: key must be 1 to 100 lower-case letters, digits, '.', '_' or '-'", ErrInvalidFlag)
+	}
+	if flag.Percentage < 0 || flag.Percentage > 100 {
+		return nil, fmt.Errorf("%w: percentage must be between 0 and 100", ErrInvalidFlag)
+	}
+	applicationIDs := make([]string, 0, len(flag.ApplicationIDs))
+	for _, applicationID := range flag.ApplicationIDs {
+		if applicationID = strings.TrimSpace(applicationID); applicationID != "" {
+			applicationIDs = append(applicationIDs, applicationID)
+		}
+	}
+	flag.ApplicationIDs = applicationIDs
+
+	stored, err := e.store.Upsert(ctx, flag)
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Feature flag saved", "key", stored.Key, "enabled", stored.Enabled,
+		"percentage", stored.Percentage, "applicationIds", stored.ApplicationIDs)
+
+	e.set(stored.Key, stored)
+	return stored, nil
+}
+
+// Delete removes a flag, turning it off for every application
+func (e *Evaluator) Delete(ctx context.Context, key string) error {
+	if err := e.store.Delete(ctx, key); err != nil {
+		return err
+	}
+	logger.Log.Info("Feature flag removed", "key", key)
+
+	e.set(key, nil)
+	return nil
+}
+
+// set updates a flag of the cache, which is copied as Enabled may still be reading it
+func (e *Evaluator) set(key string, flag *Flag) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	flags := make(map[string]*Flag, len(e.flags)+1)
+	for existing, cached := range e.flags {
+		flags[existing] = cached
+	}
+	if flag != nil {
+		flags[key] = flag
+	} else {
+		delete(flags, key)
+	}
+	e.flags = flags
+}
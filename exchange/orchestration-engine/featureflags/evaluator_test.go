@@ -0,0 +1,94 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Initialize logger for tests
+	logger.Init()
+}
+
+func TestEvaluator_Enabled(t *testing.T) {
+	ctx := context.Background()
+	evaluator := NewEvaluator(NewMemoryStore(), time.Hour)
+	require.NoError(t, evaluator.Refresh(ctx))
+
+	_, err := evaluator.Put(ctx, &Flag{Key: "provider.dmt-v2", Enabled: true, ApplicationIDs: []string{" app-1 ", ""}})
+	require.NoError(t, err)
+	assert.True(t, evaluator.Enabled("provider.dmt-v2", "app-1"))
+	assert.False(t, evaluator.Enabled("provider.dmt-v2", "app-2"))
+	assert.False(t, evaluator.Enabled("unknown", "app-1"), "unknown flags are off")
+
+	_, err = evaluator.Put(ctx, &Flag{Key: "provider.dmt-v2", Enabled: false, Percentage: 100, ApplicationIDs: []string{"app-1"}})
+	require.NoError(t, err)
+	assert.False(t, evaluator.Enabled("provider.dmt-v2", "app-1"), "disabled flags are off for everyone")
+
+	_, err = evaluator.Put(ctx, &Flag{Key: "provider.dmt-v2", Enabled: true, Percentage: 100})
+	require.NoError(t, err)
+	assert.True(t, evaluator.Enabled("provider.dmt-v2", "app-2"))
+
+	require.NoError(t, evaluator.Delete(ctx, "provider.dmt-v2"))
+	assert.False(t, evaluator.Enabled("provider.dmt-v2", "app-2"))
+	assert.True(t, errors.Is(evaluator.Delete(ctx, "provider.dmt-v2"), ErrNotFound))
+
+	var nilEvaluator *Evaluator
+	assert.False(t, nilEvaluator.Enabled("provider.dmt-v2", "app-1"))
+}
+
+func TestFlag_PercentageRolloutIsStablePerApplication(t *testing.T) {
+	flag := &Flag{Key: "array-processing-v2", Enabled: true, Percentage: 25}
+
+	enabled := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		applicationID := fmt.Sprintf("app-%d", i)
+		if flag.EnabledFor(applicationID) {
+			enabled[applicationID] = true
+		}
+	}
+	assert.InDelta(t, 250, len(enabled), 60, "about a quarter of the applications are in the rollout")
+
+	flag.Percentage = 50
+	for applicationID := range enabled {
+		assert.True(t, flag.EnabledFor(applicationID), "applications stay in the rollout as it grows")
+	}
+}
+
+func TestEvaluator_OtherReplicasSeeFlagsOnRefresh(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	evaluator := NewEvaluator(store, time.Hour)
+	replica := NewEvaluator(store, time.Hour)
+	require.NoError(t, replica.Refresh(ctx))
+
+	_, err := evaluator.Put(ctx, &Flag{Key: "defer", Enabled: true, Percentage: 100})
+	require.NoError(t, err)
+	assert.False(t, replica.Enabled("defer", "app-1"), "stale until the next refresh")
+
+	require.NoError(t, replica.Refresh(ctx))
+	assert.True(t, replica.Enabled("defer", "app-1"))
+}
+
+func TestEvaluator_Put_RejectsInvalidFlags(t *testing.T) {
+	evaluator := NewEvaluator(NewMemoryStore(), time.Hour)
+
+	for name, flag := range map[string]*Flag{
+		"missing key":         {Enabled: true},
+		"upper-case key":      {Key: "Defer"},
+		"negative percentage": {Key: "defer", Percentage: -1},
+		"percentage over 100": {Key: "defer", Percentage: 101},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := evaluator.Put(context.Background(), flag)
+			assert.True(t, errors.Is(err, ErrInvalidFlag))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package featureflags gates federation behaviors, such as calling a newly added provider, per
+// consumer application. Flags are kept in a store managed through the admin API, so that a behavior is
+// rolled out to some applications, or to a share of them, and rolled back without redeploying the
+// engine's configuration.
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a feature flag does not exist
+	ErrNotFound = errors.New("feature flag not found")
+	// ErrInvalidFlag is returned when a feature flag has an invalid key or rollout
+	ErrInvalidFlag = errors.New("invalid feature flag")
+)
+
+// keyPattern is the format of flag keys, e.g. "provider.dmt-v2"
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,99}$`)
+
+// Keys of the flags gating federation behaviors of the engine. Providers are gated by the flags named
+// in their configuration.
+const (
+	// FlagArrayProcessingV2 reads the fields of array elements at their provider paths relative to the
+	// element, so nested provider fields are found, and returns null for a null provider array
+	FlagArrayProcessingV2 = "federation.array-processing-v2"
+	// FlagDefer accepts queries that defer fragments with @defer. Deferred fragments are resolved
+	// eagerly and delivered in the initial response.
+	FlagDefer = "federation.defer"
+)
+
+// Flag turns a behavior on for some consumer applications
+type Flag struct {
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+	// Enabled switches the flag on; a disabled flag is off for every application, whatever its rollout
+	Enabled bool `json:"enabled"`
+	// Percentage of the consumer applications (0 to 100) the flag is on for. Applications are picked by
+	// a hash of their ID and the key, so an application keeps the behavior as the percentage grows.
+	Percentage float64 `json:"percentage"`
+	// ApplicationIDs are the consumer applications the flag is always on for
+	ApplicationIDs []string  `json:"applicationIds,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// EnabledFor reports whether the flag is on for a consumer application
+func (f *Flag) EnabledFor(applicationID string) bool {
+	if !f.Enabled {
+		return false
+	}
+	if f.Percentage >= 100 || slices.Contains(f.ApplicationIDs, applicationID) {
+		return true
+	}
+	return bucket(f.Key, applicationID) < f.Percentage
+}
+
+// bucket places an application in [0, 100) for the rollout of a flag. The key is hashed along with
+// the application ID, so that each flag is rolled out to a different share of the applications.
+func bucket(key, applicationID string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(applicationID))
+	return float64(h.Sum32()%10000) / 100
+}
+
+// Store persists feature flags
+type Store interface {
+	List(ctx context.Context) ([]*Flag, error)
+	Upsert(ctx context.Context, flag *Flag) (*Flag, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore keeps feature flags in memory
+type MemoryStore struct {
+	mu    sync.Mutex
+	flags map[string]*Flag
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{flags: make(map[string]*Flag)}
+}
+
+// List returns all flags ordered by key
+func (s *MemoryStore) List(_ context.Context) ([]*Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flags := make([]*Flag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flags = append(flags, copyFlag(flag))
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags, nil
+}
+
+// Upsert creates or replaces a flag
+func (s *MemoryStore) Upsert(_ context.Context, flag *Flag) (*Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := copyFlag(flag)
+	stored.UpdatedAt = time.Now().UTC()
+	s.flags[stored.Key] = stored
+	return copyFlag(stored), nil
+}
+
+// Delete removes a flag
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.flags[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.flags, key)
+	return nil
+}
+
+func copyFlag(flag *Flag) *Flag {
+	copied := *flag
+	copied.ApplicationIDs = append([]string(nil), flag.ApplicationIDs...)
+	return &copied
+}
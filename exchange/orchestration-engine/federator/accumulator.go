@@ -7,6 +7,7 @@ import (
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/federator"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/transform"
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/visitor"
 )
@@ -510,15 +511,60 @@ func processArrayFieldWithSchema(responseData map[string]interface{}, path []str
 	}
 }
 
+// applyProviderMappings rewrites each provider response with the provider's configured mappings, so
+// that formats and structures differing from the unified schema are adapted before accumulation.
+// Fields whose mapping fails are kept as the provider returned them.
+func applyProviderMappings(federatedResponse *FederationResponse, transformers map[string]*transform.Transformer) {
+	if federatedResponse == nil {
+		return
+	}
+	for _, response := range federatedResponse.Responses {
+		transformer := transformers[response.ServiceKey]
+		if transformer == nil || response.Response.Data == nil {
+			continue
+		}
+		if err := transformer.Apply(response.Response.Data); err != nil {
+			logger.Log.Warn("Failed to apply provider mappings", "providerKey", response.ServiceKey, "error", err)
+		}
+	}
+}
+
+// CodeNormalizer translates the provider codes of fields marked with @codeList to unified codes
+type CodeNormalizer interface {
+	Normalize(providerKey, codeList string, value interface{}) interface{}
+}
+
 // AccumulateResponseWithSchemaInfo uses schema information for array-aware processing
 func AccumulateResponseWithSchemaInfo(queryAST *ast.Document, federatedResponse *FederationResponse, schemaInfoMap map[string]*SourceSchemaInfo) graphql.Response {
+	return AccumulateResponseWithCodes(queryAST, federatedResponse, schemaInfoMap, nil)
+}
+
+// AccumulateResponseWithCodes is AccumulateResponseWithSchemaInfo normalizing the codes of fields
+// marked with @codeList; codes may be nil to keep the provider codes
+func AccumulateResponseWithCodes(queryAST *ast.Document, federatedResponse *FederationResponse, schemaInfoMap map[string]*SourceSchemaInfo, codes CodeNormalizer) graphql.Response {
+	return accumulateResponse(federatedResponse, schemaInfoMap, codes, accumulateArrayResponse)
+}
+
+// AccumulateResponseV2 is AccumulateResponseWithCodes with array processing v2: the fields of array
+// elements are read at their provider paths relative to the element, and a null provider array is
+// returned as null instead of being dropped
+func AccumulateResponseV2(queryAST *ast.Document, federatedResponse *FederationResponse, schemaInfoMap map[string]*SourceSchemaInfo, codes CodeNormalizer) graphql.Response {
+	return accumulateResponse(federatedResponse, schemaInfoMap, codes, accumulateArrayResponseV2)
+}
+
+// arrayAccumulator pushes the array field at fieldPath from the provider response into destination
+type arrayAccumulator func(destination map[string]interface{}, fieldPath string, fieldSchemaInfo *SourceSchemaInfo, federatedResponse *FederationResponse, codes CodeNormalizer) error
+
+// accumulateResponse builds the response to the query from the provider responses, pushing array
+// fields with accumulateArray
+func accumulateResponse(federatedResponse *FederationResponse, schemaInfoMap map[string]*SourceSchemaInfo, codes CodeNormalizer, accumulateArray arrayAccumulator) graphql.Response {
 	responseData := make(map[string]interface{})
 
 	// Process each field in the schema info map
 	for fieldPath, schemaInfo := range schemaInfoMap {
 		if schemaInfo.IsArray {
 			// Handle array fields with object-by-object processing
-			err := accumulateArrayResponse(responseData, fieldPath, schemaInfo, federatedResponse)
+			err := accumulateArray(responseData, fieldPath, schemaInfo, federatedResponse, codes)
 			if err != nil {
 				logger.Log.Error("Error processing array field", "path", fieldPath, "error", err)
 			}
@@ -528,6 +574,7 @@ func AccumulateResponseWithSchemaInfo(queryAST *ast.Document, federatedResponse
 			if response != nil {
 				value, err := GetValueAtPath(response.Response.Data, schemaInfo.ProviderField)
 				if err == nil {
+					value = normalizeCode(codes, schemaInfo, value)
 					_, err = PushValue(responseData, fieldPath, value)
 				} else {
 					logger.Log.Error("Error getting value", "path", schemaInfo.ProviderField, "error", err)
@@ -547,6 +594,7 @@ func accumulateArrayResponse(
 	fieldPath string, // e.g., "personInfo.ownedVehicles"
 	fieldSchemaInfo *SourceSchemaInfo, // The schema info for the 'ownedVehicles' field
 	federatedResponse *FederationResponse,
+	codes CodeNormalizer,
 ) error {
 	// 1. Get the provider response
 	response := federatedResponse.GetProviderResponse(fieldSchemaInfo.ProviderKey)
@@ -586,7 +634,7 @@ func accumulateArrayResponse(
 				// Use the final part of the consumer field name as the key (e.g., "regNo")
 				keyParts := strings.Split(consumerFieldName, ".")
 				key := keyParts[len(keyParts)-1]
-				destinationObject[key] = value
+				destinationObject[key] = normalizeCode(codes, subFieldInfo, value)
 			} else {
 				// Field not found in source item, skip it silently
 			}
@@ -599,6 +647,68 @@ func accumulateArrayResponse(
 	return err
 }
 
+// accumulateArrayResponseV2 is accumulateArrayResponse with array processing v2. Each field of an
+// element is read at its provider path relative to the element, so fields nested below the element
+// (e.g. "owner.name") are found. A null provider array, or a null element, is returned as null.
+func accumulateArrayResponseV2(
+	destination map[string]interface{},
+	fieldPath string,
+	fieldSchemaInfo *SourceSchemaInfo,
+	federatedResponse *FederationResponse,
+	codes CodeNormalizer,
+) error {
+	response := federatedResponse.GetProviderResponse(fieldSchemaInfo.ProviderKey)
+	if response == nil {
+		return fmt.Errorf("no response found for provider %s", fieldSchemaInfo.ProviderKey)
+	}
+
+	sourceArrayInterface, err := GetValueAtPath(response.Response.Data, fieldSchemaInfo.ProviderArrayFieldPath)
+	if err != nil {
+		return fmt.Errorf("source array path not found: %s", fieldSchemaInfo.ProviderArrayFieldPath)
+	}
+	if sourceArrayInterface == nil {
+		_, err = PushValue(destination, fieldPath, nil)
+		return err
+	}
+	sourceArray, ok := sourceArrayInterface.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected an array at path %s but got %T", fieldSchemaInfo.ProviderArrayFieldPath, sourceArrayInterface)
+	}
+
+	destinationArray := make([]interface{}, 0, len(sourceArray))
+	for _, sourceItemInterface := range sourceArray {
+		sourceItem, ok := sourceItemInterface.(map[string]interface{})
+		if !ok {
+			destinationArray = append(destinationArray, nil)
+			continue
+		}
+
+		destinationObject := make(map[string]interface{})
+		for consumerFieldName, subFieldInfo := range fieldSchemaInfo.SubFieldSchemaInfos {
+			elementField := subFieldInfo.ProviderElementField
+			if elementField == "" {
+				elementField = subFieldInfo.ProviderField
+			}
+			// Fields missing from an element are left out, as in accumulateArrayResponse
+			if value, err := GetValueAtPath(sourceItem, elementField); err == nil {
+				destinationObject[consumerFieldName] = normalizeCode(codes, subFieldInfo, value)
+			}
+		}
+		destinationArray = append(destinationArray, destinationObject)
+	}
+
+	_, err = PushValue(destination, fieldPath, destinationArray)
+	return err
+}
+
+// normalizeCode translates the provider codes of a field marked with @codeList
+func normalizeCode(codes CodeNormalizer, schemaInfo *SourceSchemaInfo, value interface{}) interface{} {
+	if codes == nil || schemaInfo.CodeList == "" {
+		return value
+	}
+	return codes.Normalize(schemaInfo.ProviderKey, schemaInfo.CodeList, value)
+}
+
 // PushValue pushes a value into a JSON-like structure (map[string]interface{} / []interface{})
 // using a dot-notation path. If a segment already points to an array, the value is appended to all items.
 func PushValue(obj interface{}, path string, value interface{}) (interface{}, error) {
@@ -410,3 +410,175 @@ func TestAccumulateResponseWithSchema_EmptyArray(t *testing.T) {
 
 	assert.Len(t, ownedVehiclesArray, 0)
 }
+
+// fakeCodeNormalizer maps the codes of a single provider and code list
+type fakeCodeNormalizer struct {
+	providerKey, codeList string
+	codes                 map[string]string
+}
+
+func (n *fakeCodeNormalizer) Normalize(providerKey, codeList string, value interface{}) interface{} {
+	if code, ok := value.(string); ok && providerKey == n.providerKey && codeList == n.codeList {
+		if unified, ok := n.codes[code]; ok {
+			return unified
+		}
+	}
+	return value
+}
+
+// TestAccumulateResponseWithCodes tests that fields marked with @codeList are normalized
+func TestAccumulateResponseWithCodes(t *testing.T) {
+	schema := ParseSchemaDoc(t, `
+directive @sourceInfo(
+	providerKey: String!
+	schemaId: String
+	providerField: String!
+) on FIELD_DEFINITION
+
+directive @codeList(name: String!) on FIELD_DEFINITION
+
+type Query {
+	personInfo(nic: String!): PersonInfo
+}
+
+type PersonInfo {
+	name: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.owner.name")
+	licenseClass: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.owner.licenseClass") @codeList(name: "vehicleClass")
+	class: [VehicleClass] @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.classes")
+}
+
+type VehicleClass {
+	className: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.classes.className")
+	classCode: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.classes.classCode") @codeList(name: "vehicleClass")
+}
+`)
+	query := ParseTestQuery(t, `
+		query {
+			personInfo(nic: "123456789V") {
+				name
+				licenseClass
+				class {
+					className
+					classCode
+				}
+			}
+		}
+	`)
+
+	schemaInfoMap, err := BuildSchemaInfoMap(schema, query)
+	assert.NoError(t, err)
+	assert.Equal(t, "vehicleClass", schemaInfoMap["personInfo.licenseClass"].CodeList)
+	assert.Empty(t, schemaInfoMap["personInfo.name"].CodeList)
+
+	federatedResponse := &FederationResponse{
+		Responses: []*ProviderResponse{
+			{
+				ServiceKey: "dmt",
+				Response: graphql.Response{
+					Data: map[string]interface{}{
+						"vehicle": map[string]interface{}{
+							"owner": map[string]interface{}{"name": "B1", "licenseClass": "B1"},
+							"classes": []interface{}{
+								map[string]interface{}{"className": "Light motor vehicle", "classCode": "B1"},
+								map[string]interface{}{"className": "Motorcycle", "classCode": "A"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	codes := &fakeCodeNormalizer{providerKey: "dmt", codeList: "vehicleClass", codes: map[string]string{"B1": "LIGHT_MOTOR_VEHICLE"}}
+
+	response := AccumulateResponseWithCodes(query, federatedResponse, schemaInfoMap, codes)
+
+	personInfo := response.Data["personInfo"].(map[string]interface{})
+	assert.Equal(t, "B1", personInfo["name"], "fields without @codeList are kept")
+	assert.Equal(t, "LIGHT_MOTOR_VEHICLE", personInfo["licenseClass"])
+
+	classes := personInfo["class"].([]map[string]interface{})
+	assert.Len(t, classes, 2)
+	assert.Equal(t, "LIGHT_MOTOR_VEHICLE", classes[0]["classCode"])
+	assert.Equal(t, "A", classes[1]["classCode"], "unmapped codes are kept")
+	assert.Equal(t, "Light motor vehicle", classes[0]["className"])
+}
+
+// TestAccumulateResponseV2 tests that array processing v2 reads nested fields of array elements and
+// keeps null arrays
+func TestAccumulateResponseV2(t *testing.T) {
+	schema := ParseSchemaDoc(t, `
+directive @sourceInfo(
+	providerKey: String!
+	schemaId: String
+	providerField: String!
+) on FIELD_DEFINITION
+
+type Query {
+	personInfo(nic: String!): PersonInfo
+}
+
+type PersonInfo {
+	ownedVehicles: [VehicleInfo] @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.vehicles")
+	licenses: [License] @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.licenses")
+}
+
+type VehicleInfo {
+	regNo: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.vehicles.registrationNumber")
+	ownerName: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.vehicles.owner.name")
+}
+
+type License {
+	number: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.licenses.number")
+}
+`)
+	query := ParseTestQuery(t, `
+		query {
+			personInfo(nic: "123456789V") {
+				ownedVehicles {
+					regNo
+					ownerName
+				}
+				licenses {
+					number
+				}
+			}
+		}
+	`)
+
+	schemaInfoMap, err := BuildSchemaInfoMap(schema, query)
+	assert.NoError(t, err)
+	assert.Equal(t, "owner.name", schemaInfoMap["personInfo.ownedVehicles"].SubFieldSchemaInfos["ownerName"].ProviderElementField)
+
+	federatedResponse := &FederationResponse{
+		Responses: []*ProviderResponse{
+			{
+				ServiceKey: "dmt",
+				Response: graphql.Response{
+					Data: map[string]interface{}{
+						"vehicle": map[string]interface{}{
+							"vehicles": []interface{}{
+								map[string]interface{}{"registrationNumber": "ABC123", "owner": map[string]interface{}{"name": "John Doe"}},
+								nil,
+							},
+							"licenses": nil,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	v1 := AccumulateResponseWithCodes(query, federatedResponse, schemaInfoMap, nil)
+	v1Vehicles := v1.Data["personInfo"].(map[string]interface{})["ownedVehicles"].([]map[string]interface{})
+	assert.NotContains(t, v1Vehicles[0], "ownerName", "v1 only reads the last segment of the provider path")
+	assert.NotContains(t, v1.Data["personInfo"], "licenses", "v1 drops null arrays")
+
+	response := AccumulateResponseV2(query, federatedResponse, schemaInfoMap, nil)
+	personInfo := response.Data["personInfo"].(map[string]interface{})
+	vehicles := personInfo["ownedVehicles"].([]interface{})
+	assert.Len(t, vehicles, 2)
+	assert.Equal(t, map[string]interface{}{"regNo": "ABC123", "ownerName": "John Doe"}, vehicles[0])
+	assert.Nil(t, vehicles[1], "null elements are kept")
+	assert.Contains(t, personInfo, "licenses")
+	assert.Nil(t, personInfo["licenses"], "null arrays are returned as null")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/transform"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -438,3 +439,33 @@ func TestAccumulateResponse_MixedObjectAndArray(t *testing.T) {
 	assert.Equal(t, "Honda", vehicle2["make"])
 	assert.Equal(t, "Civic", vehicle2["model"])
 }
+
+func TestApplyProviderMappings(t *testing.T) {
+	transformer, err := transform.Compile(&transform.Config{
+		Fields: []*transform.FieldMapping{
+			{Field: "getPersonInfo.dateOfBirth", Expr: `getPersonInfo.dateOfBirth | date("02/01/2006", "2006-01-02")`},
+		},
+	})
+	assert.NoError(t, err)
+
+	federatedResponse := &FederationResponse{
+		Responses: []*ProviderResponse{
+			{ServiceKey: "rgd", Response: graphql.Response{Data: map[string]interface{}{
+				"getPersonInfo": map[string]interface{}{"dateOfBirth": "24/03/1990"},
+			}}},
+			{ServiceKey: "drp", Response: graphql.Response{Data: map[string]interface{}{
+				"getPersonInfo": map[string]interface{}{"dateOfBirth": "24/03/1990"},
+			}}},
+		},
+	}
+	applyProviderMappings(federatedResponse, map[string]*transform.Transformer{"rgd": transformer})
+
+	value, err := GetValueAtPath(federatedResponse.GetProviderResponse("rgd").Response.Data, "getPersonInfo.dateOfBirth")
+	assert.NoError(t, err)
+	assert.Equal(t, "1990-03-24", value)
+
+	// Providers without mappings are left as they are
+	value, err = GetValueAtPath(federatedResponse.GetProviderResponse("drp").Response.Data, "getPersonInfo.dateOfBirth")
+	assert.NoError(t, err)
+	assert.Equal(t, "24/03/1990", value)
+}
@@ -0,0 +1,51 @@
+package federator
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/appcontext"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	oeerrors "github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+// ApplicationRejection is why the token of a consumer application is rejected
+type ApplicationRejection struct {
+	Code    string
+	Message string
+}
+
+// ResolveApplication resolves the consumer application behind the token and returns a copy of ctx carrying
+// its context, for the policy and audit context of the request. Tokens of suspended, expired and unregistered
+// applications are rejected. The request is allowed without the application's context if it cannot be
+// resolved, or if applications are not resolved.
+func (f *Federator) ResolveApplication(ctx context.Context, consumerInfo *auth.ConsumerAssertion) (context.Context, *ApplicationRejection) {
+	if f.Applications == nil || consumerInfo == nil {
+		return ctx, nil
+	}
+	app, err := f.Applications.GetApplication(ctx, consumerInfo.ApplicationID, consumerInfo.ClientID)
+	if errors.Is(err, appcontext.ErrNotRegistered) {
+		logger.Log.Info("Rejected token of unregistered application", "applicationId", consumerInfo.ApplicationID)
+		return ctx, &ApplicationRejection{Code: oeerrors.CodeApplicationNotRegistered, Message: "Application is not registered"}
+	}
+	if err != nil {
+		logger.Log.Warn("Failed to resolve application, allowing request", "applicationId", consumerInfo.ApplicationID, "error", err)
+		return ctx, nil
+	}
+
+	if app.Suspended() {
+		logger.Log.Info("Rejected token of suspended application", "applicationId", app.ApplicationID)
+		message := "Application is suspended"
+		if app.StatusReason != nil && *app.StatusReason != "" {
+			message += ": " + *app.StatusReason
+		}
+		return ctx, &ApplicationRejection{Code: oeerrors.CodeApplicationSuspended, Message: message}
+	}
+	if app.GrantExpired(time.Now()) {
+		logger.Log.Info("Rejected token of expired application", "applicationId", app.ApplicationID, "grantExpiresAt", app.GrantExpiresAt)
+		return ctx, &ApplicationRejection{Code: oeerrors.CodeApplicationExpired, Message: "Application's access has expired"}
+	}
+	return appcontext.NewContext(ctx, app), nil
+}
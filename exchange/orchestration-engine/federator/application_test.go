@@ -0,0 +1,86 @@
+package federator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/appcontext"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	oeerrors "github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testApplications resolves the applications it holds, and fails while err is set
+type testApplications struct {
+	apps map[string]*appcontext.Application
+	err  error
+}
+
+func (s *testApplications) GetApplication(_ context.Context, applicationID, _ string) (*appcontext.Application, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	app, ok := s.apps[applicationID]
+	if !ok {
+		return nil, appcontext.ErrNotRegistered
+	}
+	return app, nil
+}
+
+func TestResolveApplication(t *testing.T) {
+	sector := "banking"
+	reason := "Unpaid invoice"
+	past := time.Now().Add(-time.Hour)
+	applications := &testApplications{apps: map[string]*appcontext.Application{
+		"active-app":    {ApplicationID: "active-app", ApplicationName: "Loans", MemberID: "mem-1", Sector: &sector, Environment: "production", Status: appcontext.StatusActive},
+		"suspended-app": {ApplicationID: "suspended-app", Status: appcontext.StatusSuspended, StatusReason: &reason},
+		"expired-app":   {ApplicationID: "expired-app", Status: appcontext.StatusActive, GrantExpiresAt: &past},
+	}}
+	f := &Federator{Applications: applications}
+	ctx := context.Background()
+
+	resolved, rejection := f.ResolveApplication(ctx, &auth.ConsumerAssertion{ApplicationID: "active-app", ClientID: "client-1"})
+	require.Nil(t, rejection)
+	app := appcontext.FromContext(resolved)
+	require.NotNil(t, app)
+
+	pdpRequest := newPdpRequest(resolved, &auth.ConsumerAssertion{ApplicationID: "active-app", ClientID: "client-1"}, &[]ProviderLevelFieldRecord{})
+	assert.Equal(t, "banking", pdpRequest.Context["consumer.sector"])
+	assert.Equal(t, "Loans", pdpRequest.Context["consumer.applicationName"])
+	assert.Equal(t, "production", pdpRequest.Context["consumer.environment"])
+	assert.Equal(t, "client-1", pdpRequest.Context["consumer.clientId"])
+
+	metadata := map[string]interface{}{}
+	addApplicationMetadata(resolved, metadata)
+	assert.Equal(t, "mem-1", metadata["application"].(map[string]interface{})["memberId"])
+
+	_, rejection = f.ResolveApplication(ctx, &auth.ConsumerAssertion{ApplicationID: "suspended-app"})
+	require.NotNil(t, rejection)
+	assert.Equal(t, oeerrors.CodeApplicationSuspended, rejection.Code)
+	assert.Contains(t, rejection.Message, reason)
+
+	_, rejection = f.ResolveApplication(ctx, &auth.ConsumerAssertion{ApplicationID: "expired-app"})
+	require.NotNil(t, rejection)
+	assert.Equal(t, oeerrors.CodeApplicationExpired, rejection.Code)
+
+	_, rejection = f.ResolveApplication(ctx, &auth.ConsumerAssertion{ApplicationID: "unknown-app"})
+	require.NotNil(t, rejection)
+	assert.Equal(t, oeerrors.CodeApplicationNotRegistered, rejection.Code)
+
+	t.Run("Allows requests while the portal cannot be reached", func(t *testing.T) {
+		applications.err = errors.New("connection refused")
+		defer func() { applications.err = nil }()
+		resolved, rejection := f.ResolveApplication(ctx, &auth.ConsumerAssertion{ApplicationID: "suspended-app"})
+		assert.Nil(t, rejection)
+		assert.Nil(t, appcontext.FromContext(resolved))
+	})
+
+	t.Run("Does not resolve applications without a source", func(t *testing.T) {
+		resolved, rejection := (&Federator{}).ResolveApplication(ctx, &auth.ConsumerAssertion{ApplicationID: "unknown-app"})
+		assert.Nil(t, rejection)
+		assert.Nil(t, appcontext.FromContext(resolved))
+	})
+}
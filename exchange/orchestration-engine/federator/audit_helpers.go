@@ -1,6 +1,9 @@
 package federator
 
 import (
+	"context"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/appcontext"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/middleware"
 )
 
@@ -20,3 +23,10 @@ func convertToAuditFieldRecords(records *[]ProviderLevelFieldRecord) *[]middlewa
 	}
 	return &result
 }
+
+// addApplicationMetadata adds the consumer application resolved for the request to audit metadata
+func addApplicationMetadata(ctx context.Context, metadata map[string]interface{}) {
+	if app := appcontext.FromContext(ctx); app != nil {
+		metadata["application"] = app.Attributes()
+	}
+}
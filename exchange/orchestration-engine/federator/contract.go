@@ -0,0 +1,49 @@
+package federator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/contract"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+var (
+	// ErrContractSchemaUnavailable is returned when there is no active schema to generate a contract from
+	ErrContractSchemaUnavailable = errors.New("no active schema found")
+	// ErrContractPolicyCheck is returned when the PDP cannot tell which fields the application may read
+	ErrContractPolicyCheck = errors.New("authorization check failed")
+)
+
+// Contract generates the contract of the consumer's application: the part of the active schema the
+// PDP allows the application to read, as typed client artifacts. Every provider field of the schema
+// is checked in a single PDP request, so fields behind conditions are left out when their conditions
+// do not hold at generation time. Without a PDP every field is in the contract.
+func (f *Federator) Contract(ctx context.Context, consumerInfo *auth.ConsumerAssertion, opts contract.Options) (*contract.Contract, error) {
+	schema, err := f.resolveSchema()
+	if err != nil {
+		logger.Log.Error("Failed to load schema from file", "Error", err)
+		return nil, ErrContractSchemaUnavailable
+	}
+	fields := contract.Fields(schema)
+
+	var access map[contract.Field]contract.Access
+	if pdpClient := f.pdp(); pdpClient == nil {
+		logger.Log.Warn("PDP client not available, skipping policy check")
+		access = contract.Classify(fields, nil)
+	} else {
+		records := make([]ProviderLevelFieldRecord, 0, len(fields))
+		for _, field := range fields {
+			records = append(records, ProviderLevelFieldRecord{SchemaId: field.SchemaID, FieldPath: field.FieldName})
+		}
+		pdpResponse, err := pdpClient.MakePdpRequest(ctx, newPdpRequest(ctx, consumerInfo, &records))
+		if err != nil || pdpResponse == nil {
+			logger.Log.Error("PDP request failed", "error", err)
+			return nil, ErrContractPolicyCheck
+		}
+		access = contract.Classify(fields, pdpResponse)
+	}
+
+	return contract.Build(schema, consumerInfo.ApplicationID, access, opts)
+}
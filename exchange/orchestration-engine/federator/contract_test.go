@@ -0,0 +1,42 @@
+package federator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/contract"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContract(t *testing.T) {
+	consumer := &auth.ConsumerAssertion{ClientID: "app-123", ApplicationID: "app-123"}
+
+	t.Run("Denied fields are left out and consent-required fields are marked", func(t *testing.T) {
+		f := newPreflightTestFederator(t, nil)
+
+		generated, err := f.Contract(context.Background(), consumer, contract.Options{})
+		require.NoError(t, err)
+		assert.Equal(t, "app-123", generated.ApplicationID)
+		require.Len(t, generated.Fields, 2)
+		assert.Equal(t, "personInfo.fullName", generated.Fields[0].Path)
+		assert.False(t, generated.Fields[0].ConsentRequired)
+		assert.Equal(t, "personInfo.address", generated.Fields[1].Path)
+		assert.True(t, generated.Fields[1].ConsentRequired)
+		assert.NotContains(t, generated.TypeScript, "photo")
+		assert.NotContains(t, generated.Go, "Photo")
+	})
+
+	t.Run("Every field is in the contract without a PDP", func(t *testing.T) {
+		cfg := &configs.Config{Environment: "test", TrustUpstream: true}
+		f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: preflightTestSchemaSDL})
+		require.NoError(t, err)
+
+		generated, err := f.Contract(context.Background(), consumer, contract.Options{})
+		require.NoError(t, err)
+		assert.Len(t, generated.Fields, 3)
+	})
+}
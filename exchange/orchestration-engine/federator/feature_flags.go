@@ -0,0 +1,151 @@
+package federator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/featureflags"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// providerFeatureFlag returns the key of the feature flag gating a provider, or "" if it is not gated
+func (f *Federator) providerFeatureFlag(providerKey string) string {
+	if f.Configs == nil {
+		return ""
+	}
+	for _, p := range f.Configs.Providers {
+		if p.ProviderKey == providerKey {
+			return p.FeatureFlag
+		}
+	}
+	return ""
+}
+
+// gateProviders drops the requests to providers whose feature flag is off for the consumer
+// application, returning the remaining requests and the flags of the dropped providers by provider key
+func (f *Federator) gateProviders(requests []*federationServiceRequest, applicationID string) ([]*federationServiceRequest, map[string]string) {
+	var notEnabled map[string]string
+	kept := make([]*federationServiceRequest, 0, len(requests))
+	for _, request := range requests {
+		flag := f.providerFeatureFlag(request.ServiceKey)
+		if flag == "" || f.FeatureFlags.Enabled(flag, applicationID) {
+			kept = append(kept, request)
+			continue
+		}
+		logger.Log.Info("Provider not enabled for the application", "Provider Key", request.ServiceKey,
+			"featureFlag", flag, "applicationId", applicationID)
+		if notEnabled == nil {
+			notEnabled = make(map[string]string)
+		}
+		notEnabled[request.ServiceKey] = flag
+	}
+	return kept, notEnabled
+}
+
+// applyNotEnabled adds a PROVIDER_NOT_ENABLED error for each provider that was not called because its
+// feature flag is off for the consumer, naming the fields it serves
+func applyNotEnabled(response *graphql.Response, notEnabled map[string]string, schemaInfoMap map[string]*SourceSchemaInfo) {
+	providerKeys := make([]string, 0, len(notEnabled))
+	for providerKey := range notEnabled {
+		providerKeys = append(providerKeys, providerKey)
+	}
+	sort.Strings(providerKeys)
+	for _, providerKey := range providerKeys {
+		response.Errors = append(response.Errors, map[string]interface{}{
+			"message": fmt.Sprintf("Provider %s is not enabled for this application", providerKey),
+			"extensions": map[string]interface{}{
+				"code":        errors.CodeProviderNotEnabled,
+				"providerKey": providerKey,
+				"featureFlag": notEnabled[providerKey],
+				"fields":      fieldsOfProvider(schemaInfoMap, providerKey),
+			},
+		})
+	}
+}
+
+// accumulate builds the response to the query from the provider responses, with array processing v2
+// if its feature flag is on for the consumer application
+func (f *Federator) accumulate(doc *ast.Document, responses *FederationResponse, schemaInfoMap map[string]*SourceSchemaInfo, codes CodeNormalizer, applicationID string) graphql.Response {
+	if f.FeatureFlags.Enabled(featureflags.FlagArrayProcessingV2, applicationID) {
+		return AccumulateResponseV2(doc, responses, schemaInfoMap, codes)
+	}
+	return AccumulateResponseWithCodes(doc, responses, schemaInfoMap, codes)
+}
+
+// resolveDeferredFragments merges the inline fragments the query defers with @defer into their
+// enclosing selections, so they are resolved eagerly and delivered in the initial response. It
+// returns a DEFER_NOT_ENABLED error if the query defers a fragment while defer support is off for the
+// consumer application.
+func (f *Federator) resolveDeferredFragments(doc *ast.Document, applicationID string) *graphql.Response {
+	if doc == nil || !defersFragments(doc) {
+		return nil
+	}
+	if !f.FeatureFlags.Enabled(featureflags.FlagDefer, applicationID) {
+		logger.Log.Info("Query uses @defer, which is not enabled for the application",
+			"featureFlag", featureflags.FlagDefer, "applicationId", applicationID)
+		response := createErrorResponse("The @defer directive is not enabled for this application", map[string]interface{}{
+			"code":        errors.CodeDeferNotEnabled,
+			"featureFlag": featureflags.FlagDefer,
+		})
+		return &response
+	}
+	for _, definition := range doc.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok && operation.SelectionSet != nil {
+			operation.SelectionSet.Selections = inlineDeferredFragments(operation.SelectionSet.Selections)
+		}
+	}
+	return nil
+}
+
+// defersFragments reports whether any operation of the query defers an inline fragment
+func defersFragments(doc *ast.Document) bool {
+	var defers func(selections []ast.Selection) bool
+	defers = func(selections []ast.Selection) bool {
+		for _, selection := range selections {
+			if fragment, ok := selection.(*ast.InlineFragment); ok && hasDeferDirective(fragment) {
+				return true
+			}
+			if set := selection.GetSelectionSet(); set != nil && defers(set.Selections) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, definition := range doc.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok && operation.SelectionSet != nil && defers(operation.SelectionSet.Selections) {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineDeferredFragments replaces the deferred inline fragments among selections by their selections
+func inlineDeferredFragments(selections []ast.Selection) []ast.Selection {
+	inlined := make([]ast.Selection, 0, len(selections))
+	for _, selection := range selections {
+		if set := selection.GetSelectionSet(); set != nil {
+			set.Selections = inlineDeferredFragments(set.Selections)
+		}
+		if fragment, ok := selection.(*ast.InlineFragment); ok && hasDeferDirective(fragment) {
+			if fragment.SelectionSet != nil {
+				inlined = append(inlined, fragment.SelectionSet.Selections...)
+			}
+			continue
+		}
+		inlined = append(inlined, selection)
+	}
+	return inlined
+}
+
+// hasDeferDirective reports whether an inline fragment is marked with @defer
+func hasDeferDirective(fragment *ast.InlineFragment) bool {
+	for _, directive := range fragment.Directives {
+		if directive.Name != nil && directive.Name.Value == "defer" {
+			return true
+		}
+	}
+	return false
+}
@@ -11,16 +11,28 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/activation"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/appcontext"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/canary"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/codes"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/consent"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/dataexport"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/featureflags"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/middleware"
 	auth2 "github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/auth"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/transform"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/provider"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/querylog"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/quota"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/signing"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/synthetic"
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
 	auditpkg "github.com/gov-dx-sandbox/shared/audit"
@@ -36,8 +48,25 @@ type Federator struct {
 	ProviderHandler *provider.Handler
 	Client          *http.Client
 	Schema          *ast.Document
-	SchemaService   interface{}          // Will be *services.SchemaService, using interface{} to avoid circular import
-	TokenValidator  *auth.TokenValidator // Cached validator for JWT token signature verification
+	SchemaService   interface{}                       // Will be *services.SchemaService, using interface{} to avoid circular import
+	TokenValidator  *auth.TokenValidator              // Cached validator for JWT token signature verification
+	Quotas          *quota.Enforcer                   // Per-application record quotas; nil when quotas are not enforced
+	Applications    appcontext.Source                 // Resolves consumer applications; nil when tokens are not resolved
+	Transformers    map[string]*transform.Transformer // Response mappings by provider key
+	Codes           *codes.Normalizer                 // Provider code mappings; nil when codes are not normalized
+	Synthetic       *synthetic.Generator              // Generates provider responses; nil when providers are called
+	Maintenance     *maintenance.Schedule             // Provider maintenance windows; nil when providers are always called
+	ResponseCache   *maintenance.ResponseCache        // Responses served during maintenance windows; nil when responses are not cached
+	QueryLog        *querylog.Recorder                // Logs executed queries; nil when queries are not logged
+	ResponseSigner  *signing.Signer                   // Signs GraphQL responses; nil when responses are not signed
+	Pdp             policy.Decider                    // Embedded PDP or gRPC client; nil when the PDP's HTTP API is called
+	SchemaCanary    *canary.Router                    // Routes queries to a candidate schema version; nil without a database
+	// SchemaActivations activates schema versions at their scheduled time; nil without a database
+	SchemaActivations *activation.Scheduler
+	// DataExports runs bulk data exports for approved use cases; nil when data exports are not enabled
+	DataExports *dataexport.Service
+	// FeatureFlags gates federation behaviors per consumer application; nil when every flag is off
+	FeatureFlags *featureflags.Evaluator
 }
 
 type FederationServiceAST struct {
@@ -60,11 +89,16 @@ type federationRequest struct {
 type ProviderResponse struct {
 	ServiceKey string
 	Response   graphql.Response `json:"response"`
+	// Maintenance is the window during which a cached response was served instead of calling the provider
+	Maintenance *maintenance.Window `json:"-"`
+	CachedAt    time.Time           `json:"-"`
 }
 
 type FederationResponse struct {
 	ServiceKey string              `json:"ProviderKey"`
 	Responses  []*ProviderResponse `json:"responses"`
+	// Maintenance holds the windows of the providers that were not called, by service key
+	Maintenance map[string]*maintenance.Window `json:"-"`
 }
 
 // GetProviderResponse Returns the specific provider response by service key
@@ -141,9 +175,57 @@ func Initialize(ctx context.Context, configs *configs.Config, providerHandler *p
 		}
 	}
 
+	if configs.Synthetic.Enabled {
+		var latency time.Duration
+		if configs.Synthetic.Latency != "" {
+			parsed, err := time.ParseDuration(configs.Synthetic.Latency)
+			if err != nil {
+				return nil, fmt.Errorf("fatal configuration error: invalid synthetic latency %q: %w", configs.Synthetic.Latency, err)
+			}
+			latency = parsed
+		}
+		federator.Synthetic = synthetic.NewGenerator(configs.Synthetic.Seed, latency)
+		logger.Log.Warn("Synthetic data mode is enabled - providers are not called and responses contain generated data")
+	}
+
+	if configs.Maintenance.CacheTTL != "" {
+		ttl, err := time.ParseDuration(configs.Maintenance.CacheTTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("fatal configuration error: invalid maintenance cache TTL %q", configs.Maintenance.CacheTTL)
+		}
+		federator.ResponseCache = maintenance.NewResponseCache(ttl, configs.Maintenance.CacheEntries)
+		logger.Log.Info("Provider responses are cached for maintenance windows", "cacheTtl", ttl)
+	}
+
+	if configs.Signing.KeyFile != "" {
+		signer, err := signing.LoadSigner(configs.Signing.KeyID, configs.Signing.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("fatal configuration error: invalid response signing key: %w", err)
+		}
+		federator.ResponseSigner = signer
+		logger.Log.Info("GraphQL responses are signed", "kid", signer.KeyID(), "alg", signer.Algorithm())
+	}
+
+	pdp, err := openPdp(ctx, configs.PdpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("fatal configuration error: %w", err)
+	}
+	federator.Pdp = pdp
+
 	// Initialize with providers from config if available
 	if configs.Providers != nil {
 		for _, p := range configs.Providers {
+			if p.Mapping != nil {
+				transformer, err := transform.Compile(p.Mapping)
+				if err != nil {
+					return nil, fmt.Errorf("fatal configuration error: invalid mapping for provider %s: %w", p.ProviderKey, err)
+				}
+				if federator.Transformers == nil {
+					federator.Transformers = make(map[string]*transform.Transformer)
+				}
+				federator.Transformers[p.ProviderKey] = transformer
+			}
+
 			// Convert ProviderConfig to Provider
 			providerInstance := &provider.Provider{
 				ServiceUrl: p.ProviderURL,
@@ -182,7 +264,25 @@ func Initialize(ctx context.Context, configs *configs.Config, providerHandler *p
 
 // FederateQuery takes a raw GraphQL query, splits it into sub-queries for each service,
 // sends them to the respective providers, and merges the responses.
+// During a schema canary, the query may be answered with the candidate schema version.
 func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request, consumerInfo *auth.ConsumerAssertion) graphql.Response {
+	schemaVersion := f.SchemaCanary.Route(consumerInfo.ApplicationID)
+	if f.QueryLog == nil {
+		response := f.federateQuery(ctx, request, consumerInfo, schemaVersion, nil)
+		f.SchemaCanary.Record(schemaVersion, len(response.Errors) > 0)
+		return response
+	}
+	start := time.Now()
+	var providers []string
+	response := f.federateQuery(ctx, request, consumerInfo, schemaVersion, &providers)
+	f.SchemaCanary.Record(schemaVersion, len(response.Errors) > 0)
+	f.logQuery(request, consumerInfo, providers, start, response)
+	return response
+}
+
+// federateQuery federates the query with the given candidate schema version, or the active schema for
+// "", setting providers, if not nil, to the providers that were called
+func (f *Federator) federateQuery(ctx context.Context, request graphql.Request, consumerInfo *auth.ConsumerAssertion, schemaVersion string, providers *[]string) graphql.Response {
 	// Ensure traceID is in context (should already be set by monitoring.TraceIDMiddleware, but ensure it)
 	traceID := monitoring.GetTraceIDFromContext(ctx)
 	if traceID == "" {
@@ -205,72 +305,27 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 		logger.Log.Error("Failed to parse query", "Error", err)
 	}
 
-	// Get schema document from database or config
-	var schema *ast.Document
-
-	// First try to get from database if schema service is available
-	if f.SchemaService != nil {
-		// Use reflection to call GetActiveSchema method
-		schemaServiceValue := reflect.ValueOf(f.SchemaService)
-		if schemaServiceValue.IsValid() && !schemaServiceValue.IsNil() {
-			getActiveSchemaMethod := schemaServiceValue.MethodByName("GetActiveSchema")
-			if getActiveSchemaMethod.IsValid() {
-				results := getActiveSchemaMethod.Call([]reflect.Value{})
-				if len(results) >= 2 && !results[1].IsNil() {
-					// Error occurred
-					logger.Log.Warn("Failed to get active schema from database", "Error", results[1].Interface())
-				} else if len(results) >= 1 && !results[0].IsNil() {
-					// Got schema from database
-					schemaRecord := results[0].Interface()
-					// Extract SDL from schema record using reflection
-					schemaRecordValue := reflect.ValueOf(schemaRecord)
-					// If it's a pointer, dereference it
-					if schemaRecordValue.Kind() == reflect.Ptr {
-						schemaRecordValue = schemaRecordValue.Elem()
-					}
-					sdlField := schemaRecordValue.FieldByName("SDL")
-					if sdlField.IsValid() && sdlField.Kind() == reflect.String {
-						sdlString := sdlField.String()
-						src := source.NewSource(&source.Source{
-							Body: []byte(sdlString),
-							Name: "ActiveSchema",
-						})
-						schema, err = parser.Parse(parser.ParseParams{Source: src})
-						if err != nil {
-							logger.Log.Error("Failed to parse active schema from database", "Error", err)
-							schema = nil
-						}
-					}
-				}
-			}
-		}
-	} else {
-		logger.Log.Info("SchemaService is nil, skipping database schema lookup")
+	// Deferred fragments are resolved eagerly if defer support is on for the consumer
+	if rejection := f.resolveDeferredFragments(doc, consumerInfo.ApplicationID); rejection != nil {
+		return *rejection
 	}
 
-	// Fallback to config if no schema from database
-	if schema == nil && f.Configs.Schema != nil {
-		schema, err = f.Configs.GetSchemaDocument()
+	var schema *ast.Document
+	if schemaVersion != "" {
+		schema, err = f.SchemaCanary.Schema(schemaVersion)
 		if err != nil {
-			logger.Log.Warn("Failed to get schema from config", "Error", err)
-			schema = nil
+			logger.Log.Error("Failed to load candidate schema", "version", schemaVersion, "Error", err)
+			return createErrorResponseWithCode("Failed to load the schema", errors.CodeInternalError)
 		}
-	}
-
-	// Final fallback to schema.graphql file if no schema from database or config
-	if schema == nil {
-		logger.Log.Info("No schema found in database or config, attempting to load schema.graphql file")
-		schema, err = f.loadSchemaFromFile()
-		if err != nil {
-			logger.Log.Error("Failed to load schema from file", "Error", err)
-			return graphql.Response{
-				Data: nil,
-				Errors: []interface{}{
-					&graphql.JSONError{
-						Message: "No active schema found. Please create and activate a schema using the schema management API first, or ensure schema.graphql file exists.",
-					},
+	} else if schema, err = f.resolveSchema(); err != nil {
+		logger.Log.Error("Failed to load schema from file", "Error", err)
+		return graphql.Response{
+			Data: nil,
+			Errors: []interface{}{
+				&graphql.JSONError{
+					Message: "No active schema found. Please create and activate a schema using the schema management API first, or ensure schema.graphql file exists.",
 				},
-			}
+			},
 		}
 	}
 
@@ -303,37 +358,21 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 	}
 
 	// Safely initialize PDP and CE clients with nil checks
-	var pdpClient *policy.PdpClient
+	pdpClient := f.pdp()
 	var ceClient *consent.CEServiceClient
 
-	if f.Configs.PdpConfig.ClientURL != "" {
-		pdpClient = policy.NewPdpClient(f.Configs.PdpConfig.ClientURL)
-	}
 	if f.Configs.CeConfig.ClientURL != "" {
 		ceClient = consent.NewCEServiceClient(f.Configs.CeConfig.ClientURL)
 	}
 
 	// Check if PDP client is available before making request
+	var pdpRequest *policy.PdpRequest
 	var pdpResponse *policy.PdpResponse
 	if pdpClient == nil {
 		logger.Log.Warn("PDP client not available, skipping policy check")
 		// Continue without PDP check - this allows the system to work without PDP
 	} else {
-		pdpRequest := &policy.PdpRequest{
-			AppId: consumerInfo.ApplicationID,
-		}
-
-		requiredFields := make([]policy.RequiredField, 0)
-
-		for _, field := range *schemaCollection.ProviderFieldMap {
-			requiredFields = append(requiredFields, policy.RequiredField{
-				SchemaID:  field.SchemaId,
-				FieldName: field.FieldPath,
-			})
-		}
-
-		pdpRequest.RequiredFields = requiredFields
-
+		pdpRequest = newPdpRequest(ctx, consumerInfo, schemaCollection.ProviderFieldMap)
 		pdpResponse, err = pdpClient.MakePdpRequest(ctx, pdpRequest)
 
 		// Log policy check audit event
@@ -355,14 +394,20 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 			"authorized", pdpResponse.AppAuthorized,
 			"consentRequired", pdpResponse.AppRequiresOwnerConsent,
 			"unauthorizedFieldsCount", len(pdpResponse.UnauthorizedFields),
-			"expiredFieldsCount", len(pdpResponse.ExpiredFields))
+			"conditionFailedFieldsCount", len(pdpResponse.ConditionFailedFields),
+			"expiredFieldsCount", len(pdpResponse.ExpiredFields),
+			"deprecatedFieldsCount", len(pdpResponse.DeprecatedFields))
 
 		if !pdpResponse.AppAuthorized {
 			logger.Log.Info("Request not authorized by PDP",
-				"unauthorizedFields", pdpResponse.UnauthorizedFields)
+				"unauthorizedFields", pdpResponse.UnauthorizedFields,
+				"conditionFailedFields", pdpResponse.ConditionFailedFields,
+				"blockedFields", pdpResponse.BlockedFields)
 			return createErrorResponse("Access denied", map[string]interface{}{
-				"code":               errors.CodePDPNotAllowed,
-				"unauthorizedFields": pdpResponse.UnauthorizedFields,
+				"code":                  errors.CodePDPNotAllowed,
+				"unauthorizedFields":    pdpResponse.UnauthorizedFields,
+				"conditionFailedFields": pdpResponse.ConditionFailedFields,
+				"blockedFields":         pdpResponse.BlockedFields,
 			})
 		}
 
@@ -377,18 +422,11 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 	}
 
 	// Check for Data Owner ID in extracted arguments
-	var dataOwnerID string
 	if len(extractedArgs) == 0 {
 		logger.Log.Info("Data Owner ID argument is missing: extractedArgs is empty or nil")
 		return createErrorResponseWithCode("Data Owner ID argument is missing", errors.CodeMissingEntityIdentifier)
 	}
-	val := extractedArgs[0].Value.GetValue()
-	if s, ok := val.(string); ok {
-		dataOwnerID = s
-	} else {
-		logger.Log.Error("CitizenID is not a string", "value", val)
-		dataOwnerID = ""
-	}
+	dataOwnerID := dataOwnerFromArguments(extractedArgs)
 	if dataOwnerID == "" {
 		logger.Log.Info("Data Owner ID argument is missing or invalid")
 		return createErrorResponseWithCode("Data Owner ID argument is missing or invalid", errors.CodeMissingEntityIdentifier)
@@ -414,33 +452,9 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 
 		ownerEmail := dataOwnerID // assuming dataOwnerID is ownerEmail for this example
 
-		// Map PDP response fields to Consent Engine request with all metadata
-		fields := make([]consent.ConsentField, len(pdpResponse.ConsentRequiredFields))
-		for i, f := range pdpResponse.ConsentRequiredFields {
-			fields[i].FieldName = f.FieldName
-			fields[i].SchemaID = f.SchemaID
-			fields[i].DisplayName = f.DisplayName
-			fields[i].Description = f.Description
-
-			// Map Owner from PDP response, default to citizen if not provided
-			if f.Owner != nil {
-				fields[i].Owner = consent.OwnerType(*f.Owner)
-			} else {
-				fields[i].Owner = consent.OwnerCitizen
-			}
-		}
-
-		typeRealTime := consent.TypeRealtime
-		ceRequest := &consent.CreateConsentRequest{
-			AppID: consumerInfo.ApplicationID,
-			ConsentRequirement: consent.ConsentRequirement{
-				Owner:      consent.OwnerCitizen,
-				OwnerID:    ownerEmail,
-				OwnerEmail: ownerEmail,
-				Fields:     fields,
-			},
-			ConsentType: &typeRealTime,
-		}
+		// Map PDP consent requirements to Consent Engine request with all metadata
+		requirement := consentRequirement(ctx, pdpClient, pdpRequest)
+		ceRequest := newConsentRequest(consumerInfo.ApplicationID, ownerEmail, pdpResponse.ConsentRequiredFields, requirement)
 
 		ceResp, err := ceClient.CreateConsent(ctx, ceRequest)
 
@@ -490,6 +504,9 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 		return createErrorResponse("No valid service queries found in the request", nil)
 	}
 
+	// Providers gated by a feature flag that is off for the consumer are not called
+	splitRequests, notEnabled := f.gateProviders(splitRequests, consumerInfo.ApplicationID)
+
 	federationRequest := &federationRequest{
 		FederationServiceRequest: splitRequests,
 	}
@@ -501,7 +518,10 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 	}
 	ctxWithAudit := middleware.NewContextWithMetadata(ctx, auditMetadata)
 
-	responses := f.performFederation(ctxWithAudit, federationRequest)
+	responses := f.performFederation(ctxWithAudit, federationRequest, schema)
+	if providers != nil {
+		*providers = f.calledProviders(federationRequest, responses)
+	}
 
 	// Build schema info map for array-aware processing
 	var schemaInfoMap map[string]*SourceSchemaInfo
@@ -513,13 +533,171 @@ func (f *Federator) FederateQuery(ctx context.Context, request graphql.Request,
 	}
 	// Error handling is done above in the if block
 
-	// Transform the federated responses back to the original query structure using array-aware processing
-	response := AccumulateResponseWithSchemaInfo(doc, responses, schemaInfoMap)
+	// Adapt provider responses to the unified schema, then transform them back to the original
+	// query structure using array-aware processing
+	applyProviderMappings(responses, f.Transformers)
+	var codeNormalizer CodeNormalizer
+	if f.Codes != nil {
+		codeNormalizer = f.Codes
+	}
+	response := f.accumulate(doc, responses, schemaInfoMap, codeNormalizer, consumerInfo.ApplicationID)
+
+	// Fields of providers in maintenance fail with a structured error rather than a timeout
+	applyMaintenance(&response, responses, schemaInfoMap)
+	applyNotEnabled(&response, notEnabled, schemaInfoMap)
+
+	// Fields of deprecated schemas are served, with a warning so consumers can move off them in time
+	if pdpResponse != nil && len(pdpResponse.DeprecatedFields) > 0 {
+		logger.Log.Warn("Request uses fields of deprecated schemas",
+			"applicationId", consumerInfo.ApplicationID,
+			"deprecatedFields", pdpResponse.DeprecatedFields)
+		response.Extensions = map[string]interface{}{
+			"warnings": deprecationWarnings(pdpResponse.DeprecatedFields),
+		}
+	}
+
+	// Generated data is flagged so that it is never mistaken for real records
+	if f.Synthetic != nil {
+		if response.Extensions == nil {
+			response.Extensions = make(map[string]interface{})
+		}
+		response.Extensions["synthetic"] = true
+	}
 
 	return response
 }
 
-func (f *Federator) performFederation(ctx context.Context, r *federationRequest) *FederationResponse {
+// dataOwnerFromArguments returns the data owner ID, which is the first required argument of the query
+func dataOwnerFromArguments(extractedArgs []*ArgSource) string {
+	if len(extractedArgs) == 0 {
+		return ""
+	}
+	val := extractedArgs[0].Value.GetValue()
+	s, ok := val.(string)
+	if !ok {
+		logger.Log.Error("CitizenID is not a string", "value", val)
+		return ""
+	}
+	return s
+}
+
+// newPdpRequest builds the policy decision request for the provider fields of a query
+func newPdpRequest(ctx context.Context, consumerInfo *auth.ConsumerAssertion, fieldMap *[]ProviderLevelFieldRecord) *policy.PdpRequest {
+	requiredFields := make([]policy.RequiredField, 0)
+	for _, field := range *fieldMap {
+		requiredFields = append(requiredFields, policy.RequiredField{
+			SchemaID:  field.SchemaId,
+			FieldName: field.FieldPath,
+		})
+	}
+
+	requestContext := map[string]interface{}{
+		"consumer.applicationId": consumerInfo.ApplicationID,
+		"consumer.clientId":      consumerInfo.ClientID,
+		"request.time":           time.Now().Format(time.RFC3339),
+	}
+	// Policy conditions can match the resolved application, e.g. consumer.sector
+	if app := appcontext.FromContext(ctx); app != nil {
+		for name, value := range app.Attributes() {
+			requestContext["consumer."+name] = value
+		}
+	}
+
+	return &policy.PdpRequest{
+		AppId:          consumerInfo.ApplicationID,
+		RequiredFields: requiredFields,
+		Context:        requestContext,
+	}
+}
+
+// consentRequirement fetches the PDP's consolidated consent requirement for the citizen owner. It
+// returns nil if the requirements cannot be fetched, so that consent is requested with the
+// consent engine's defaults.
+func consentRequirement(ctx context.Context, pdpClient policy.Decider, pdpRequest *policy.PdpRequest) *policy.ConsentRequirement {
+	requirements, err := pdpClient.GetConsentRequirements(ctx, pdpRequest)
+	if err != nil {
+		logger.Log.Warn("Failed to get consent requirements from PDP, using policy decision fields", "error", err)
+		return nil
+	}
+	requirement := requirements.Requirement(policy.OwnerCitizen)
+	if requirement == nil || len(requirement.Fields) == 0 {
+		return nil
+	}
+	logger.Log.Info("Consent requirements", "grantDuration", requirement.GrantDuration, "purposes", requirement.Purposes)
+	return requirement
+}
+
+// newConsentRequest builds the real-time consent request for the fields the PDP requires the
+// data owner's consent for. When the PDP's consent requirement is given, consent is requested for
+// its grant duration rather than the consent engine's default.
+func newConsentRequest(appID, ownerEmail string, consentRequiredFields []policy.ConsentRequiredField, requirement *policy.ConsentRequirement) *consent.CreateConsentRequest {
+	var grantDuration *string
+	var purposes []string
+	if requirement != nil {
+		if requirement.GrantDuration != "" {
+			grantDuration = &requirement.GrantDuration
+		}
+		purposes = requirement.Purposes
+	}
+
+	// Map PDP response fields to Consent Engine request with all metadata
+	fields := make([]consent.ConsentField, len(consentRequiredFields))
+	for i, f := range consentRequiredFields {
+		fields[i].FieldName = f.FieldName
+		fields[i].SchemaID = f.SchemaID
+		fields[i].DisplayName = f.DisplayName
+		fields[i].Description = f.Description
+
+		// Map Owner from PDP response, default to citizen if not provided
+		if f.Owner != nil {
+			fields[i].Owner = consent.OwnerType(*f.Owner)
+		} else {
+			fields[i].Owner = consent.OwnerCitizen
+		}
+	}
+
+	typeRealTime := consent.TypeRealtime
+	return &consent.CreateConsentRequest{
+		AppID: appID,
+		ConsentRequirement: consent.ConsentRequirement{
+			Owner:      consent.OwnerCitizen,
+			OwnerID:    ownerEmail,
+			OwnerEmail: ownerEmail,
+			Fields:     fields,
+		},
+		GrantDuration: grantDuration,
+		ConsentType:   &typeRealTime,
+		Purposes:      purposes,
+	}
+}
+
+// deprecationWarnings builds a response warning for each requested field of a deprecated schema
+func deprecationWarnings(fields []policy.DeprecatedField) []map[string]interface{} {
+	warnings := make([]map[string]interface{}, 0, len(fields))
+	for _, field := range fields {
+		message := fmt.Sprintf("Field %s of schema %s is %s", field.FieldName, field.SchemaID, field.Status)
+		if field.SunsetAt != nil {
+			message += fmt.Sprintf("; its sunset date is %s", field.SunsetAt.Format(time.RFC3339))
+		}
+		warning := map[string]interface{}{
+			"code":      errors.CodeSchemaDeprecated,
+			"message":   message,
+			"fieldName": field.FieldName,
+			"schemaId":  field.SchemaID,
+			"status":    field.Status,
+		}
+		if field.SunsetAt != nil {
+			warning["sunsetAt"] = field.SunsetAt.Format(time.RFC3339)
+		}
+		if field.Message != nil {
+			warning["deprecationMessage"] = *field.Message
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+func (f *Federator) performFederation(ctx context.Context, r *federationRequest, schema *ast.Document) *FederationResponse {
 	FederationResponse := &FederationResponse{
 		Responses: make([]*ProviderResponse, 0, len(r.FederationServiceRequest)),
 	}
@@ -547,34 +725,69 @@ func (f *Federator) performFederation(ctx context.Context, r *federationRequest)
 				middleware.LogProviderFetch(ctx, req.SchemaID, auditReq, response, err)
 			}
 
-			reqBody, err := json.Marshal(req.GraphQLRequest)
-			if err != nil {
-				logger.Log.Info("Failed to marshal request", "Provider Key", req.ServiceKey, "Error", err)
-				logAudit("failure", err, nil)
+			if window := f.activeMaintenance(req.ServiceKey); window != nil {
+				cached, ok := f.cachedResponse(req, window)
+				mu.Lock()
+				if ok {
+					FederationResponse.Responses = append(FederationResponse.Responses, cached)
+				} else {
+					if FederationResponse.Maintenance == nil {
+						FederationResponse.Maintenance = make(map[string]*maintenance.Window)
+					}
+					FederationResponse.Maintenance[req.ServiceKey] = window
+				}
+				mu.Unlock()
+				if !ok {
+					logAudit("failure", fmt.Errorf("provider %s is under maintenance until %s", req.ServiceKey, window.EndsAt.Format(time.RFC3339)), nil)
+				}
 				return
 			}
 
-			response, err := prov.PerformRequest(ctx, reqBody)
-			if err != nil {
-				logger.Log.Info("Request failed to the Provider", "Provider Key", req.ServiceKey, "Error", err)
-				logAudit("failure", err, nil)
-				return
-			}
-			defer response.Body.Close()
+			var bodyJson graphql.Response
+			if f.Synthetic != nil {
+				generated, err := f.Synthetic.Generate(ctx, schema, req.ServiceKey, req.GraphQLRequest)
+				if err != nil {
+					logger.Log.Error("Failed to generate synthetic response", "Provider Key", req.ServiceKey, "Error", err)
+					logAudit("failure", err, nil)
+					return
+				}
+				bodyJson = generated
+			} else {
+				reqBody, err := json.Marshal(req.GraphQLRequest)
+				if err != nil {
+					logger.Log.Info("Failed to marshal request", "Provider Key", req.ServiceKey, "Error", err)
+					logAudit("failure", err, nil)
+					return
+				}
 
-			body, err := io.ReadAll(response.Body)
-			if err != nil {
-				logger.Log.Error("Failed to read response body", "Provider Key", req.ServiceKey, "Error", err)
-				logAudit("failure", err, nil)
-				return
-			}
+				response, err := prov.PerformRequest(ctx, reqBody)
+				if err != nil {
+					logger.Log.Info("Request failed to the Provider", "Provider Key", req.ServiceKey, "Error", err)
+					f.ProviderHandler.RecordFetch(req.ServiceKey, err)
+					logAudit("failure", err, nil)
+					return
+				}
+				defer response.Body.Close()
+
+				body, err := io.ReadAll(response.Body)
+				if err != nil {
+					logger.Log.Error("Failed to read response body", "Provider Key", req.ServiceKey, "Error", err)
+					f.ProviderHandler.RecordFetch(req.ServiceKey, err)
+					logAudit("failure", err, nil)
+					return
+				}
 
-			var bodyJson graphql.Response
-			err = json.Unmarshal(body, &bodyJson)
-			if err != nil {
-				logger.Log.Error("Failed to unmarshal response", "Provider Key", req.ServiceKey, "Error", err)
-				logAudit("failure", err, nil)
-				return
+				err = json.Unmarshal(body, &bodyJson)
+				if err != nil {
+					logger.Log.Error("Failed to unmarshal response", "Provider Key", req.ServiceKey, "Error", err)
+					f.ProviderHandler.RecordFetch(req.ServiceKey, err)
+					logAudit("failure", err, nil)
+					return
+				}
+				f.ProviderHandler.RecordFetch(req.ServiceKey, nil)
+				if f.ResponseCache != nil && len(bodyJson.Errors) == 0 {
+					f.ResponseCache.Put(req.ServiceKey, reqBody, body)
+				}
 			}
 
 			// Log audit event with response
@@ -601,6 +814,7 @@ func (f *Federator) logOrchestrationRequestReceived(ctx context.Context, consume
 		"applicationId": consumerAppID,
 		"query":         query,
 	}
+	addApplicationMetadata(ctx, requestMetadata)
 	// No target for orchestration request received (it's the entry point)
 	return middleware.LogRequestReceived(ctx, "DATA_REQUEST", "APPLICATION", consumerAppID, requestMetadata)
 }
@@ -616,6 +830,7 @@ func (f *Federator) logPolicyCheck(ctx context.Context, applicationID string, re
 
 	// Populate request metadata
 	requestMetadata["applicationId"] = applicationID
+	addApplicationMetadata(ctx, requestMetadata)
 	if req != nil {
 		requestMetadata["requiredFields"] = req.RequiredFields
 	}
@@ -630,6 +845,7 @@ func (f *Federator) logPolicyCheck(ctx context.Context, applicationID string, re
 		if !resp.AppAuthorized {
 			status = auditpkg.StatusFailure
 			responseMetadata["unauthorizedFields"] = resp.UnauthorizedFields
+			responseMetadata["conditionFailedFields"] = resp.ConditionFailedFields
 		}
 		if resp.AppAccessExpired {
 			status = auditpkg.StatusFailure
@@ -706,6 +922,69 @@ func (f *Federator) mergeResponses(responses []*ProviderResponse) graphql.Respon
 	return merged
 }
 
+// resolveSchema returns the active schema from the schema service, falling back to the config
+// and then to the schema.graphql file
+func (f *Federator) resolveSchema() (*ast.Document, error) {
+	var schema *ast.Document
+	var err error
+
+	// First try to get from database if schema service is available
+	if f.SchemaService != nil {
+		// Use reflection to call GetActiveSchema method
+		schemaServiceValue := reflect.ValueOf(f.SchemaService)
+		if schemaServiceValue.IsValid() && !schemaServiceValue.IsNil() {
+			getActiveSchemaMethod := schemaServiceValue.MethodByName("GetActiveSchema")
+			if getActiveSchemaMethod.IsValid() {
+				results := getActiveSchemaMethod.Call([]reflect.Value{})
+				if len(results) >= 2 && !results[1].IsNil() {
+					// Error occurred
+					logger.Log.Warn("Failed to get active schema from database", "Error", results[1].Interface())
+				} else if len(results) >= 1 && !results[0].IsNil() {
+					// Got schema from database
+					schemaRecord := results[0].Interface()
+					// Extract SDL from schema record using reflection
+					schemaRecordValue := reflect.ValueOf(schemaRecord)
+					// If it's a pointer, dereference it
+					if schemaRecordValue.Kind() == reflect.Ptr {
+						schemaRecordValue = schemaRecordValue.Elem()
+					}
+					sdlField := schemaRecordValue.FieldByName("SDL")
+					if sdlField.IsValid() && sdlField.Kind() == reflect.String {
+						sdlString := sdlField.String()
+						src := source.NewSource(&source.Source{
+							Body: []byte(sdlString),
+							Name: "ActiveSchema",
+						})
+						schema, err = parser.Parse(parser.ParseParams{Source: src})
+						if err != nil {
+							logger.Log.Error("Failed to parse active schema from database", "Error", err)
+							schema = nil
+						}
+					}
+				}
+			}
+		}
+	} else {
+		logger.Log.Info("SchemaService is nil, skipping database schema lookup")
+	}
+
+	// Fallback to config if no schema from database
+	if schema == nil && f.Configs.Schema != nil {
+		schema, err = f.Configs.GetSchemaDocument()
+		if err != nil {
+			logger.Log.Warn("Failed to get schema from config", "Error", err)
+			schema = nil
+		}
+	}
+
+	// Final fallback to schema.graphql file if no schema from database or config
+	if schema == nil {
+		logger.Log.Info("No schema found in database or config, attempting to load schema.graphql file")
+		return f.loadSchemaFromFile()
+	}
+	return schema, nil
+}
+
 // loadSchemaFromFile loads the schema from schema.graphql file as a fallback
 func (f *Federator) loadSchemaFromFile() (*ast.Document, error) {
 	// Try to read schema.graphql file from current directory
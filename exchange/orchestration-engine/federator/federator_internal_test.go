@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/featureflags"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/provider"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/querylog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -135,6 +142,69 @@ func TestFederateQuery_WithMockSchema(t *testing.T) {
 	assert.Equal(t, "John Doe", personInfo["fullName"])
 }
 
+func TestFederateQuery_DeprecatedFields(t *testing.T) {
+	providerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(graphql.Response{
+			Data: map[string]interface{}{"person": map[string]interface{}{"fullName": "John Doe"}},
+		})
+	}))
+	defer providerServer.Close()
+
+	// Mock PDP that allows the request but reports the field's schema as deprecated
+	sunsetAt := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	message := "Use drp-schema-v2"
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(policy.PdpResponse{
+			AppAuthorized: true,
+			DeprecatedFields: []policy.DeprecatedField{
+				{FieldName: "person.fullName", SchemaID: "drp-schema", Status: "deprecated", SunsetAt: &sunsetAt, Message: &message},
+			},
+		})
+	}))
+	defer pdpServer.Close()
+
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Providers: []*configs.ProviderConfig{
+			{ProviderKey: "drp", ProviderURL: providerServer.URL, SchemaID: "drp-schema"},
+		},
+		PdpConfig: configs.PdpConfig{ClientURL: pdpServer.URL},
+		ArgMapping: []*graphql.ArgMapping{
+			{ProviderKey: "drp", SchemaID: "drp-schema", TargetArgName: "nic", SourceArgPath: "personInfo-nic", TargetArgPath: "person"},
+		},
+	}
+
+	schemaSDL := `
+		directive @sourceInfo(providerKey: String!, providerField: String!, schemaId: String) on FIELD_DEFINITION
+		type Query {
+			personInfo(nic: String!): PersonInfo @sourceInfo(providerKey: "drp", providerField: "person", schemaId: "drp-schema")
+		}
+		type PersonInfo {
+			fullName: String @sourceInfo(providerKey: "drp", providerField: "person.fullName", schemaId: "drp-schema")
+		}
+	`
+	f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: schemaSDL})
+	require.NoError(t, err)
+
+	resp := f.FederateQuery(context.Background(), graphql.Request{
+		Query: `query { personInfo(nic: "123") { fullName } }`,
+	}, &auth.ConsumerAssertion{Subscriber: "sub-123", ClientID: "app-123"})
+
+	require.Empty(t, resp.Errors)
+	personInfo, ok := resp.Data["personInfo"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "John Doe", personInfo["fullName"], "fields of deprecated schemas are still served")
+
+	warnings, ok := resp.Extensions["warnings"].([]map[string]interface{})
+	require.True(t, ok)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "SCHEMA_DEPRECATED", warnings[0]["code"])
+	assert.Equal(t, "person.fullName", warnings[0]["fieldName"])
+	assert.Equal(t, "2027-06-30T00:00:00Z", warnings[0]["sunsetAt"])
+	assert.Equal(t, message, warnings[0]["deprecationMessage"])
+}
+
 func TestFederateQuery_PDPDeny(t *testing.T) {
 	// Mock PDP to deny
 	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -255,3 +325,371 @@ func TestInitialize_FailsWithInvalidConfig(t *testing.T) {
 		require.NoError(t, err) // Should not fail, just log warning
 	})
 }
+
+func TestFederateQuery_SyntheticData(t *testing.T) {
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(policy.PdpResponse{AppAuthorized: true})
+	}))
+	defer pdpServer.Close()
+
+	providerCalled := false
+	providerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		providerCalled = true
+	}))
+	defer providerServer.Close()
+
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Providers: []*configs.ProviderConfig{
+			{ProviderKey: "drp", ProviderURL: providerServer.URL, SchemaID: "drp-schema"},
+		},
+		PdpConfig: configs.PdpConfig{ClientURL: pdpServer.URL},
+		ArgMapping: []*graphql.ArgMapping{
+			{ProviderKey: "drp", SchemaID: "drp-schema", TargetArgName: "nic", SourceArgPath: "personInfo-nic", TargetArgPath: "person"},
+		},
+		Synthetic: configs.SyntheticConfig{Enabled: true, Seed: "demo"},
+	}
+
+	schemaSDL := `
+		directive @sourceInfo(providerKey: String!, providerField: String!, schemaId: String) on FIELD_DEFINITION
+		type Query {
+			personInfo(nic: String!): PersonInfo @sourceInfo(providerKey: "drp", providerField: "person", schemaId: "drp-schema")
+		}
+		type PersonInfo {
+			fullName: String @sourceInfo(providerKey: "drp", providerField: "person.fullName", schemaId: "drp-schema")
+		}
+	`
+	f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: schemaSDL})
+	require.NoError(t, err)
+	require.NotNil(t, f.Synthetic)
+
+	query := func() graphql.Response {
+		return f.FederateQuery(context.Background(), graphql.Request{
+			Query: `query { personInfo(nic: "199012345678") { fullName } }`,
+		}, &auth.ConsumerAssertion{Subscriber: "sub-123", ClientID: "app-123"})
+	}
+	resp := query()
+
+	require.Empty(t, resp.Errors)
+	assert.False(t, providerCalled, "providers are not called in synthetic data mode")
+	assert.Equal(t, true, resp.Extensions["synthetic"])
+	personInfo, ok := resp.Data["personInfo"].(map[string]interface{})
+	require.True(t, ok)
+	assert.NotEmpty(t, personInfo["fullName"])
+	assert.Equal(t, resp.Data, query().Data, "the same NIC gets the same data")
+}
+
+func TestInitialize_InvalidSyntheticLatency(t *testing.T) {
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Synthetic:     configs.SyntheticConfig{Enabled: true, Latency: "soon"},
+	}
+
+	_, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid synthetic latency")
+}
+
+func TestFederateQuery_ProviderMaintenance(t *testing.T) {
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(policy.PdpResponse{AppAuthorized: true})
+	}))
+	defer pdpServer.Close()
+
+	var providerCalls atomic.Int32
+	providerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		providerCalls.Add(1)
+		json.NewEncoder(w).Encode(graphql.Response{
+			Data: map[string]interface{}{"person": map[string]interface{}{"fullName": "John Doe"}},
+		})
+	}))
+	defer providerServer.Close()
+
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Providers: []*configs.ProviderConfig{
+			{ProviderKey: "drp", ProviderURL: providerServer.URL, SchemaID: "drp-schema"},
+		},
+		PdpConfig: configs.PdpConfig{ClientURL: pdpServer.URL},
+		ArgMapping: []*graphql.ArgMapping{
+			{ProviderKey: "drp", SchemaID: "drp-schema", TargetArgName: "nic", SourceArgPath: "personInfo-nic", TargetArgPath: "person"},
+		},
+		Maintenance: configs.MaintenanceConfig{CacheTTL: "1h"},
+	}
+
+	schemaSDL := `
+		directive @sourceInfo(providerKey: String!, providerField: String!, schemaId: String) on FIELD_DEFINITION
+		type Query {
+			personInfo(nic: String!): PersonInfo @sourceInfo(providerKey: "drp", providerField: "person", schemaId: "drp-schema")
+		}
+		type PersonInfo {
+			fullName: String @sourceInfo(providerKey: "drp", providerField: "person.fullName", schemaId: "drp-schema")
+		}
+	`
+	f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: schemaSDL})
+	require.NoError(t, err)
+	require.NotNil(t, f.ResponseCache)
+	f.Maintenance = maintenance.NewSchedule(maintenance.NewMemoryStore(), time.Hour)
+	require.NoError(t, f.Maintenance.Refresh(context.Background()))
+
+	query := func(nic string) graphql.Response {
+		return f.FederateQuery(context.Background(), graphql.Request{
+			Query: `query { personInfo(nic: "` + nic + `") { fullName } }`,
+		}, &auth.ConsumerAssertion{Subscriber: "sub-123", ClientID: "app-123"})
+	}
+	declare := func(serveCached bool) *maintenance.Window {
+		window, err := f.Maintenance.Create(context.Background(), &maintenance.Window{
+			ProviderKey: "drp", EndsAt: time.Now().Add(time.Hour), Reason: "database upgrade", ServeCached: serveCached,
+		})
+		require.NoError(t, err)
+		return window
+	}
+
+	require.Empty(t, query("199012345678").Errors)
+	require.Equal(t, int32(1), providerCalls.Load())
+
+	t.Run("fields fail with a maintenance error", func(t *testing.T) {
+		window := declare(false)
+		defer func() { require.NoError(t, f.Maintenance.Delete(context.Background(), window.ID)) }()
+
+		resp := query("199012345678")
+		assert.Equal(t, int32(1), providerCalls.Load(), "providers in maintenance are not called")
+		require.Len(t, resp.Errors, 1)
+		extensions := resp.Errors[0].(map[string]interface{})["extensions"].(map[string]interface{})
+		assert.Equal(t, errors.CodeProviderMaintenance, extensions["code"])
+		assert.Equal(t, "drp", extensions["providerKey"])
+		assert.Equal(t, "database upgrade", extensions["reason"])
+		assert.Contains(t, extensions["fields"], "personInfo.fullName")
+	})
+
+	t.Run("cached responses are served if the window allows it", func(t *testing.T) {
+		window := declare(true)
+		defer func() { require.NoError(t, f.Maintenance.Delete(context.Background(), window.ID)) }()
+
+		resp := query("199012345678")
+		assert.Equal(t, int32(1), providerCalls.Load())
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, "John Doe", resp.Data["personInfo"].(map[string]interface{})["fullName"])
+		cached := resp.Extensions["cachedProviders"].([]map[string]interface{})
+		require.Len(t, cached, 1)
+		assert.Equal(t, "drp", cached[0]["providerKey"])
+
+		// Queries that were not cached still fail
+		resp = query("198811111111")
+		require.Len(t, resp.Errors, 1)
+	})
+
+	require.Empty(t, query("199012345678").Errors)
+	assert.Equal(t, int32(2), providerCalls.Load(), "providers are called once the window is removed")
+}
+
+func TestFederateQuery_ProviderFeatureFlag(t *testing.T) {
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(policy.PdpResponse{AppAuthorized: true})
+	}))
+	defer pdpServer.Close()
+
+	var providerCalls atomic.Int32
+	providerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		providerCalls.Add(1)
+		json.NewEncoder(w).Encode(graphql.Response{
+			Data: map[string]interface{}{"person": map[string]interface{}{"fullName": "John Doe"}},
+		})
+	}))
+	defer providerServer.Close()
+
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Providers: []*configs.ProviderConfig{
+			{ProviderKey: "drp", ProviderURL: providerServer.URL, SchemaID: "drp-schema", FeatureFlag: "provider.drp"},
+		},
+		PdpConfig: configs.PdpConfig{ClientURL: pdpServer.URL},
+		ArgMapping: []*graphql.ArgMapping{
+			{ProviderKey: "drp", SchemaID: "drp-schema", TargetArgName: "nic", SourceArgPath: "personInfo-nic", TargetArgPath: "person"},
+		},
+	}
+
+	schemaSDL := `
+		directive @sourceInfo(providerKey: String!, providerField: String!, schemaId: String) on FIELD_DEFINITION
+		type Query {
+			personInfo(nic: String!): PersonInfo @sourceInfo(providerKey: "drp", providerField: "person", schemaId: "drp-schema")
+		}
+		type PersonInfo {
+			fullName: String @sourceInfo(providerKey: "drp", providerField: "person.fullName", schemaId: "drp-schema")
+		}
+	`
+	f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: schemaSDL})
+	require.NoError(t, err)
+	f.FeatureFlags = featureflags.NewEvaluator(featureflags.NewMemoryStore(), time.Hour)
+	require.NoError(t, f.FeatureFlags.Refresh(context.Background()))
+	_, err = f.FeatureFlags.Put(context.Background(), &featureflags.Flag{Key: "provider.drp", Enabled: true, ApplicationIDs: []string{"app-pilot"}})
+	require.NoError(t, err)
+
+	query := func(applicationID string) graphql.Response {
+		return f.FederateQuery(context.Background(), graphql.Request{
+			Query: `query { personInfo(nic: "199012345678") { fullName } }`,
+		}, &auth.ConsumerAssertion{Subscriber: "sub-123", ClientID: applicationID, ApplicationID: applicationID})
+	}
+
+	resp := query("app-other")
+	assert.Equal(t, int32(0), providerCalls.Load(), "providers whose flag is off are not called")
+	require.Len(t, resp.Errors, 1)
+	extensions := resp.Errors[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	assert.Equal(t, errors.CodeProviderNotEnabled, extensions["code"])
+	assert.Equal(t, "provider.drp", extensions["featureFlag"])
+	assert.Contains(t, extensions["fields"], "personInfo.fullName")
+
+	resp = query("app-pilot")
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, int32(1), providerCalls.Load())
+	assert.Equal(t, "John Doe", resp.Data["personInfo"].(map[string]interface{})["fullName"])
+}
+
+func TestFederateQuery_DeferFeatureFlag(t *testing.T) {
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(policy.PdpResponse{AppAuthorized: true})
+	}))
+	defer pdpServer.Close()
+
+	var providerCalls atomic.Int32
+	providerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		providerCalls.Add(1)
+		json.NewEncoder(w).Encode(graphql.Response{
+			Data: map[string]interface{}{"person": map[string]interface{}{"fullName": "John Doe"}},
+		})
+	}))
+	defer providerServer.Close()
+
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Providers: []*configs.ProviderConfig{
+			{ProviderKey: "drp", ProviderURL: providerServer.URL, SchemaID: "drp-schema"},
+		},
+		PdpConfig: configs.PdpConfig{ClientURL: pdpServer.URL},
+		ArgMapping: []*graphql.ArgMapping{
+			{ProviderKey: "drp", SchemaID: "drp-schema", TargetArgName: "nic", SourceArgPath: "personInfo-nic", TargetArgPath: "person"},
+		},
+	}
+
+	schemaSDL := `
+		directive @sourceInfo(providerKey: String!, providerField: String!, schemaId: String) on FIELD_DEFINITION
+		type Query {
+			personInfo(nic: String!): PersonInfo @sourceInfo(providerKey: "drp", providerField: "person", schemaId: "drp-schema")
+		}
+		type PersonInfo {
+			fullName: String @sourceInfo(providerKey: "drp", providerField: "person.fullName", schemaId: "drp-schema")
+		}
+	`
+	f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: schemaSDL})
+	require.NoError(t, err)
+	f.FeatureFlags = featureflags.NewEvaluator(featureflags.NewMemoryStore(), time.Hour)
+	require.NoError(t, f.FeatureFlags.Refresh(context.Background()))
+	_, err = f.FeatureFlags.Put(context.Background(), &featureflags.Flag{Key: featureflags.FlagDefer, Enabled: true, ApplicationIDs: []string{"app-pilot"}})
+	require.NoError(t, err)
+
+	query := func(applicationID string) graphql.Response {
+		return f.FederateQuery(context.Background(), graphql.Request{
+			Query: `query { personInfo(nic: "199012345678") { ... @defer { fullName } } }`,
+		}, &auth.ConsumerAssertion{Subscriber: "sub-123", ClientID: applicationID, ApplicationID: applicationID})
+	}
+
+	resp := query("app-other")
+	assert.Equal(t, int32(0), providerCalls.Load(), "queries using @defer are rejected while the flag is off")
+	require.Len(t, resp.Errors, 1)
+	extensions := resp.Errors[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	assert.Equal(t, errors.CodeDeferNotEnabled, extensions["code"])
+	assert.Equal(t, featureflags.FlagDefer, extensions["featureFlag"])
+
+	resp = query("app-pilot")
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, int32(1), providerCalls.Load())
+	assert.Equal(t, "John Doe", resp.Data["personInfo"].(map[string]interface{})["fullName"], "deferred fields are delivered in the initial response")
+}
+
+func TestInitialize_InvalidMaintenanceCacheTTL(t *testing.T) {
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Maintenance:   configs.MaintenanceConfig{CacheTTL: "forever"},
+	}
+
+	_, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid maintenance cache TTL")
+}
+
+func TestInitialize_InvalidSigningKey(t *testing.T) {
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Signing:       configs.SigningConfig{KeyFile: filepath.Join(t.TempDir(), "missing.pem"), KeyID: "exchange-1"},
+	}
+
+	_, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid response signing key")
+}
+
+func TestFederateQuery_QueryLog(t *testing.T) {
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(policy.PdpResponse{AppAuthorized: true})
+	}))
+	defer pdpServer.Close()
+
+	providerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(graphql.Response{
+			Data: map[string]interface{}{"person": map[string]interface{}{"fullName": "John Doe"}},
+		})
+	}))
+	defer providerServer.Close()
+
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		Providers: []*configs.ProviderConfig{
+			{ProviderKey: "drp", ProviderURL: providerServer.URL, SchemaID: "drp-schema"},
+		},
+		PdpConfig: configs.PdpConfig{ClientURL: pdpServer.URL},
+		ArgMapping: []*graphql.ArgMapping{
+			{ProviderKey: "drp", SchemaID: "drp-schema", TargetArgName: "nic", SourceArgPath: "personInfo-nic", TargetArgPath: "person"},
+		},
+	}
+
+	schemaSDL := `
+		directive @sourceInfo(providerKey: String!, providerField: String!, schemaId: String) on FIELD_DEFINITION
+		type Query {
+			personInfo(nic: String!): PersonInfo @sourceInfo(providerKey: "drp", providerField: "person", schemaId: "drp-schema")
+		}
+		type PersonInfo {
+			fullName: String @sourceInfo(providerKey: "drp", providerField: "person.fullName", schemaId: "drp-schema")
+		}
+	`
+	f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: schemaSDL})
+	require.NoError(t, err)
+	store := querylog.NewMemoryStore(0)
+	f.QueryLog = querylog.NewRecorder(store, time.Hour, 0)
+
+	for _, nic := range []string{"199012345678", "198811111111"} {
+		resp := f.FederateQuery(context.Background(), graphql.Request{
+			Query: `query GetPerson { personInfo(nic: "` + nic + `") { fullName } }`,
+		}, &auth.ConsumerAssertion{ApplicationID: "app-123"})
+		require.Empty(t, resp.Errors)
+	}
+	f.QueryLog.Close()
+
+	entries, err := store.List(context.Background(), querylog.ListOptions{Since: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, entries[0].Fingerprint, entries[1].Fingerprint, "queries differing only in literals share a fingerprint")
+	assert.NotContains(t, entries[0].NormalizedQuery, "199012345678")
+	assert.Equal(t, "GetPerson", entries[0].OperationName)
+	assert.Equal(t, "app-123", entries[0].ConsumerID)
+	assert.Equal(t, []string{"drp"}, entries[0].Providers)
+	assert.Equal(t, int64(1), entries[0].RowCount)
+	assert.Zero(t, entries[0].ErrorCount)
+}
@@ -0,0 +1,104 @@
+package federator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+)
+
+// activeMaintenance returns the maintenance window a provider is in, or nil
+func (f *Federator) activeMaintenance(serviceKey string) *maintenance.Window {
+	if f.Maintenance == nil {
+		return nil
+	}
+	return f.Maintenance.Active(serviceKey, time.Now())
+}
+
+// cachedResponse returns the cached response of a request to a provider in a maintenance window,
+// if the window allows serving cached data and the response is cached
+func (f *Federator) cachedResponse(req *federationServiceRequest, window *maintenance.Window) (*ProviderResponse, bool) {
+	if !window.ServeCached || f.ResponseCache == nil {
+		return nil, false
+	}
+	reqBody, err := json.Marshal(req.GraphQLRequest)
+	if err != nil {
+		return nil, false
+	}
+	body, cachedAt, ok := f.ResponseCache.Get(req.ServiceKey, reqBody)
+	if !ok {
+		return nil, false
+	}
+	var response graphql.Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		logger.Log.Error("Failed to unmarshal cached response", "Provider Key", req.ServiceKey, "Error", err)
+		return nil, false
+	}
+	logger.Log.Info("Serving cached response of provider in maintenance", "Provider Key", req.ServiceKey, "cachedAt", cachedAt)
+	return &ProviderResponse{ServiceKey: req.ServiceKey, Response: response, Maintenance: window, CachedAt: cachedAt}, true
+}
+
+// applyMaintenance adds a PROVIDER_MAINTENANCE error for each provider that was not called, naming
+// the fields it serves, and flags the fields served from cached responses
+func applyMaintenance(response *graphql.Response, responses *FederationResponse, schemaInfoMap map[string]*SourceSchemaInfo) {
+	providerKeys := make([]string, 0, len(responses.Maintenance))
+	for providerKey := range responses.Maintenance {
+		providerKeys = append(providerKeys, providerKey)
+	}
+	sort.Strings(providerKeys)
+	for _, providerKey := range providerKeys {
+		window := responses.Maintenance[providerKey]
+		extensions := map[string]interface{}{
+			"code":              errors.CodeProviderMaintenance,
+			"providerKey":       providerKey,
+			"maintenanceEndsAt": window.EndsAt,
+			"fields":            fieldsOfProvider(schemaInfoMap, providerKey),
+		}
+		if window.Reason != "" {
+			extensions["reason"] = window.Reason
+		}
+		response.Errors = append(response.Errors, map[string]interface{}{
+			"message":    fmt.Sprintf("Provider %s is under maintenance until %s", providerKey, window.EndsAt.Format(time.RFC3339)),
+			"extensions": extensions,
+		})
+	}
+
+	var cached []map[string]interface{}
+	for _, resp := range responses.Responses {
+		if resp.Maintenance == nil {
+			continue
+		}
+		cached = append(cached, map[string]interface{}{
+			"providerKey":       resp.ServiceKey,
+			"cachedAt":          resp.CachedAt,
+			"maintenanceEndsAt": resp.Maintenance.EndsAt,
+			"fields":            fieldsOfProvider(schemaInfoMap, resp.ServiceKey),
+		})
+	}
+	if len(cached) > 0 {
+		sort.Slice(cached, func(i, j int) bool {
+			return cached[i]["providerKey"].(string) < cached[j]["providerKey"].(string)
+		})
+		if response.Extensions == nil {
+			response.Extensions = make(map[string]interface{})
+		}
+		response.Extensions["cachedProviders"] = cached
+	}
+}
+
+// fieldsOfProvider returns the paths of the queried fields a provider serves
+func fieldsOfProvider(schemaInfoMap map[string]*SourceSchemaInfo, providerKey string) []string {
+	fields := []string{}
+	for fieldPath, schemaInfo := range schemaInfoMap {
+		if schemaInfo.ProviderKey == providerKey {
+			fields = append(fields, fieldPath)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -24,6 +24,23 @@ type SourceSchemaInfo struct {
 	IsArray                bool                         // Flag to identify array fields
 	ProviderArrayFieldPath string                       // Path to the source array in the provider's response (e.g., "vehicle.getVehicleInfos.data")
 	SubFieldSchemaInfos    map[string]*SourceSchemaInfo // Schema info for fields inside array elements
+	CodeList               string                       // Code list normalizing the field's codes, from @codeList
+	ProviderElementField   string                       // For fields inside array elements, the provider path relative to the element (array processing v2)
+}
+
+// codeListOf returns the name given by the @codeList directive of a field definition, if any
+func codeListOf(fieldDef *ast.FieldDefinition) string {
+	for _, dir := range fieldDef.Directives {
+		if dir.Name.Value != "codeList" {
+			continue
+		}
+		for _, arg := range dir.Arguments {
+			if val, ok := arg.Value.(*ast.StringValue); ok && arg.Name.Value == "name" {
+				return val.Value
+			}
+		}
+	}
+	return ""
 }
 
 func QueryBuilder(maps *[]ProviderLevelFieldRecord, args []*ArgSource) ([]*federationServiceRequest, error) {
@@ -218,6 +235,7 @@ func buildSchemaInfoMapRecursive(
 							IsArray:                isArray,
 							ProviderArrayFieldPath: providerArrayFieldPath,
 							SubFieldSchemaInfos:    make(map[string]*SourceSchemaInfo),
+							CodeList:               codeListOf(fieldDef),
 						}
 
 						// If this is an array field, process nested fields
@@ -232,7 +250,7 @@ func buildSchemaInfoMapRecursive(
 
 							if nestedObjectDef != nil {
 								// Process nested fields for array elements
-								processNestedFieldsForArray(selection.GetSelectionSet(), schema, nestedObjectDef, schemaInfo.SubFieldSchemaInfos)
+								processNestedFieldsForArray(selection.GetSelectionSet(), schema, nestedObjectDef, providerArrayFieldPath, schemaInfo.SubFieldSchemaInfos)
 							}
 						}
 
@@ -262,6 +280,7 @@ func processNestedFieldsForArray(
 	selectionSet *ast.SelectionSet,
 	schema *ast.Document,
 	objectDefinition *ast.ObjectDefinition,
+	arrayFieldPath string,
 	subFieldSchemaInfos map[string]*SourceSchemaInfo,
 ) {
 	if selectionSet == nil {
@@ -304,10 +323,19 @@ func processNestedFieldsForArray(
 							relativeFieldPath = parts[len(parts)-1]
 						}
 
+						// Array processing v2 keeps the whole path below the array element (e.g.
+						// "owner.name" from "vehicle.getVehicleInfos.data.owner.name")
+						elementFieldPath := relativeFieldPath
+						if arrayFieldPath != "" && strings.HasPrefix(providerField, arrayFieldPath+".") {
+							elementFieldPath = strings.TrimPrefix(providerField, arrayFieldPath+".")
+						}
+
 						subFieldSchemaInfos[fieldName] = &SourceSchemaInfo{
-							ProviderKey:   providerKey,
-							ProviderField: relativeFieldPath,
-							IsArray:       false,
+							ProviderKey:          providerKey,
+							ProviderField:        relativeFieldPath,
+							IsArray:              false,
+							CodeList:             codeListOf(fieldDef),
+							ProviderElementField: elementFieldPath,
 						}
 						break
 					}
@@ -102,11 +102,12 @@ func TestProcessNestedFieldsForArray(t *testing.T) {
 	require.NotNil(t, vehicleInfo)
 
 	subFields := make(map[string]*SourceSchemaInfo)
-	processNestedFieldsForArray(selectionSet, schema, vehicleInfo, subFields)
+	processNestedFieldsForArray(selectionSet, schema, vehicleInfo, "vehicle.getVehicleInfos.data", subFields)
 
 	require.Len(t, subFields, 2)
 	assert.Equal(t, "registrationNumber", subFields["regNo"].ProviderField)
 	assert.Equal(t, "make", subFields["make"].ProviderField)
+	assert.Equal(t, "registrationNumber", subFields["regNo"].ProviderElementField)
 }
 
 func TestPushVariablesFromVariableDefinition(t *testing.T) {
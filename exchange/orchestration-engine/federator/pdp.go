@@ -0,0 +1,133 @@
+package federator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Default sync intervals of the embedded PDP, matching those of the PDP service
+const (
+	defaultPdpCachePollInterval      = 30 * time.Second
+	defaultPdpKillSwitchPollInterval = 2 * time.Second
+)
+
+// pdp returns the decider queries are checked against policies with, or nil if they are not checked
+func (f *Federator) pdp() policy.Decider {
+	if f.Pdp != nil {
+		return f.Pdp
+	}
+	if f.Configs.PdpConfig.ClientURL != "" {
+		return policy.NewPdpClient(f.Configs.PdpConfig.ClientURL)
+	}
+	return nil
+}
+
+// openPdp opens the in-process PDP in embedded mode or the PDP's gRPC client in grpc mode. In remote
+// mode it returns the client of the PDP's HTTP API, or nil if no PDP is configured.
+func openPdp(ctx context.Context, config configs.PdpConfig) (policy.Decider, error) {
+	switch config.Mode {
+	case "", configs.PdpModeRemote:
+		if config.ClientURL == "" {
+			return nil, nil
+		}
+		tokens, err := pdpTokenSource(config.Auth)
+		if err != nil {
+			return nil, err
+		}
+		client := policy.NewPdpClient(config.ClientURL)
+		client.SetTokenSource(tokens)
+		return client, nil
+	case configs.PdpModeGrpc:
+		return openGrpcPdp(config)
+	case configs.PdpModeEmbedded:
+		return openEmbeddedPdp(ctx, config)
+	default:
+		return nil, fmt.Errorf("invalid PDP mode %q", config.Mode)
+	}
+}
+
+// openGrpcPdp creates the client of the PDP's gRPC decision API
+func openGrpcPdp(config configs.PdpConfig) (*policy.PdpGrpcClient, error) {
+	if config.GrpcAddress == "" {
+		return nil, fmt.Errorf("grpc PDP mode requires a PDP gRPC address")
+	}
+	tokens, err := pdpTokenSource(config.Auth)
+	if err != nil {
+		return nil, err
+	}
+	client, err := policy.NewPdpGrpcClient(config.GrpcAddress)
+	if err != nil {
+		return nil, err
+	}
+	client.SetTokenSource(tokens)
+	logger.Log.Info("Policies are evaluated by the PDP over gRPC", "address", config.GrpcAddress)
+	return client, nil
+}
+
+// pdpTokenSource returns the source of the engine's PDP access tokens, which caches each token until
+// it expires, or nil if the PDP is called without authentication
+func pdpTokenSource(authConfig *auth.AuthConfig) (oauth2.TokenSource, error) {
+	if authConfig == nil {
+		return nil, nil
+	}
+	if authConfig.Type != auth.AuthTypeOAuth2 {
+		return nil, fmt.Errorf("PDP auth must be of type %s", auth.AuthTypeOAuth2)
+	}
+	if authConfig.TokenURL == "" || authConfig.ClientID == "" {
+		return nil, fmt.Errorf("PDP auth requires a token URL and client ID")
+	}
+	credentials := &clientcredentials.Config{
+		ClientID:     authConfig.ClientID,
+		ClientSecret: authConfig.ClientSecret,
+		TokenURL:     authConfig.TokenURL,
+		Scopes:       authConfig.Scopes,
+	}
+	return credentials.TokenSource(context.Background()), nil
+}
+
+// openEmbeddedPdp opens the in-process PDP
+func openEmbeddedPdp(ctx context.Context, config configs.PdpConfig) (*policy.EmbeddedPdp, error) {
+
+	if config.DatabaseURL == "" {
+		return nil, fmt.Errorf("embedded PDP mode requires a PDP database URL")
+	}
+	cachePollInterval, err := parsePollInterval(config.CachePollInterval, defaultPdpCachePollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PDP cache poll interval: %w", err)
+	}
+	killSwitchPollInterval, err := parsePollInterval(config.KillSwitchPollInterval, defaultPdpKillSwitchPollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PDP kill switch poll interval: %w", err)
+	}
+
+	embedded, err := policy.OpenEmbeddedPdp(ctx, config.DatabaseURL, cachePollInterval, killSwitchPollInterval)
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Policies are evaluated by the embedded PDP",
+		"cachePollInterval", cachePollInterval, "killSwitchPollInterval", killSwitchPollInterval)
+	return embedded, nil
+}
+
+// parsePollInterval parses a positive duration, or returns the default if value is empty
+func parsePollInterval(value string, defaultInterval time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultInterval, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("%q is not positive", value)
+	}
+	return interval, nil
+}
@@ -0,0 +1,49 @@
+package federator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenPdp(t *testing.T) {
+	ctx := context.Background()
+
+	for _, mode := range []string{"", configs.PdpModeRemote} {
+		pdp, err := openPdp(ctx, configs.PdpConfig{Mode: mode, ClientURL: "http://pdp:8082"})
+		assert.NoError(t, err)
+		assert.IsType(t, &policy.PdpClient{}, pdp, "the PDP service is called in remote mode")
+	}
+	pdp, err := openPdp(ctx, configs.PdpConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, pdp, "queries are not checked without a PDP")
+
+	pdp, err = openPdp(ctx, configs.PdpConfig{ClientURL: "http://pdp:8082", Auth: &auth.AuthConfig{
+		Type: auth.AuthTypeOAuth2, TokenURL: "https://idp.example.com/oauth2/token", ClientID: "orchestration-engine", ClientSecret: "secret",
+	}})
+	assert.NoError(t, err)
+	assert.IsType(t, &policy.PdpClient{}, pdp)
+
+	pdp, err = openPdp(ctx, configs.PdpConfig{Mode: configs.PdpModeGrpc, GrpcAddress: "pdp:9082"})
+	require.NoError(t, err)
+	require.IsType(t, &policy.PdpGrpcClient{}, pdp)
+	assert.NoError(t, pdp.(*policy.PdpGrpcClient).Close())
+
+	for name, config := range map[string]configs.PdpConfig{
+		"unknown mode":          {Mode: "sidecar"},
+		"missing grpc address":  {Mode: configs.PdpModeGrpc},
+		"missing database":      {Mode: configs.PdpModeEmbedded},
+		"invalid poll interval": {Mode: configs.PdpModeEmbedded, DatabaseURL: "postgres://pdp", CachePollInterval: "soon"},
+		"zero poll interval":    {Mode: configs.PdpModeEmbedded, DatabaseURL: "postgres://pdp", KillSwitchPollInterval: "0s"},
+		"API key auth":          {ClientURL: "http://pdp:8082", Auth: &auth.AuthConfig{Type: auth.AuthTypeAPIKey}},
+		"incomplete auth":       {Mode: configs.PdpModeGrpc, GrpcAddress: "pdp:9082", Auth: &auth.AuthConfig{Type: auth.AuthTypeOAuth2}},
+	} {
+		_, err := openPdp(ctx, config)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,254 @@
+package federator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/consent"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Reasons a preflight reports a field as denied
+const (
+	PreflightReasonUnauthorized    = "unauthorized"
+	PreflightReasonConditionFailed = "condition_failed"
+	PreflightReasonAccessExpired   = "access_expired"
+)
+
+// PreflightRequest is a query to check before it is executed
+type PreflightRequest struct {
+	graphql.Request
+	// DataOwnerID overrides the data owner taken from the query's arguments
+	DataOwnerID string `json:"dataOwnerId,omitempty"`
+}
+
+// PreflightField is a provider field requested by a query
+type PreflightField struct {
+	ProviderKey string  `json:"providerKey"`
+	SchemaID    string  `json:"schemaId"`
+	FieldName   string  `json:"fieldName"`
+	DisplayName *string `json:"displayName,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// Reason is set on denied fields
+	Reason string `json:"reason,omitempty"`
+}
+
+// PreflightConsent describes the consent the data owner has to give before the query can be executed
+type PreflightConsent struct {
+	// ConsentID, Status and ConsentPortalURL describe the owner's existing consent record, if any
+	ConsentID        string                `json:"consentId,omitempty"`
+	Status           consent.ConsentStatus `json:"status,omitempty"`
+	ConsentPortalURL *string               `json:"consentPortalUrl,omitempty"`
+	// Draft is the consent request for the fields that still need consent, ready to be sent to the Consent Engine
+	Draft *consent.CreateConsentRequest `json:"draft,omitempty"`
+}
+
+// PreflightResponse tells which fields of a query are available, need consent or are denied
+type PreflightResponse struct {
+	ApplicationID string `json:"applicationId"`
+	DataOwnerID   string `json:"dataOwnerId"`
+	// Executable is set when the query can be executed as is
+	Executable      bool                     `json:"executable"`
+	Available       []PreflightField         `json:"available"`
+	ConsentRequired []PreflightField         `json:"consentRequired"`
+	Denied          []PreflightField         `json:"denied"`
+	Consent         *PreflightConsent        `json:"consent,omitempty"`
+	Warnings        []map[string]interface{} `json:"warnings,omitempty"`
+}
+
+// PreflightError is a preflight failure with the HTTP status it is reported with
+type PreflightError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *PreflightError) Error() string {
+	return e.Message
+}
+
+// Preflight checks a query against the PDP and the data owner's consent without executing it, so that
+// consumer applications can obtain consent before they run the query
+func (f *Federator) Preflight(ctx context.Context, request PreflightRequest, consumerInfo *auth.ConsumerAssertion) (*PreflightResponse, error) {
+	src := source.NewSource(&source.Source{
+		Body: []byte(request.Query),
+		Name: "Query",
+	})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		logger.Log.Info("Failed to parse preflight query", "Error", err)
+		return nil, &PreflightError{Status: http.StatusBadRequest, Code: errors.CodeBadRequest, Message: "Invalid GraphQL query"}
+	}
+
+	schema, err := f.resolveSchema()
+	if err != nil {
+		logger.Log.Error("Failed to load schema from file", "Error", err)
+		return nil, &PreflightError{Status: http.StatusServiceUnavailable, Code: errors.CodeInternalError, Message: "No active schema found"}
+	}
+
+	schemaCollection, err := ProviderSchemaCollector(schema, doc)
+	if err != nil {
+		logger.Log.Info("Failed to collect provider schema", "Error", err)
+		return nil, &PreflightError{Status: http.StatusBadRequest, Code: errors.CodeBadRequest, Message: err.Error()}
+	}
+
+	dataOwnerID := request.DataOwnerID
+	if dataOwnerID == "" {
+		var argMapping []*graphql.ArgMapping
+		if f.Configs.ArgMapping != nil {
+			argMapping = f.Configs.ArgMapping
+		}
+		extractedArgs := ExtractRequiredArguments(FindRequiredArguments(schemaCollection.ProviderFieldMap, argMapping), schemaCollection.Arguments)
+		if request.Variables != nil {
+			PushVariablesFromVariableDefinition(request.Request, extractedArgs, schemaCollection.VariableDefinitions)
+		}
+		dataOwnerID = dataOwnerFromArguments(extractedArgs)
+	}
+	if dataOwnerID == "" {
+		return nil, &PreflightError{Status: http.StatusBadRequest, Code: errors.CodeMissingEntityIdentifier, Message: "Data Owner ID argument is missing or invalid"}
+	}
+
+	response := &PreflightResponse{
+		ApplicationID:   consumerInfo.ApplicationID,
+		DataOwnerID:     dataOwnerID,
+		Available:       make([]PreflightField, 0),
+		ConsentRequired: make([]PreflightField, 0),
+		Denied:          make([]PreflightField, 0),
+	}
+	requested := requestedFields(schemaCollection.ProviderFieldMap)
+
+	pdpClient := f.pdp()
+	if pdpClient == nil {
+		// Queries are not checked against policies without a PDP, so every field is available
+		logger.Log.Warn("PDP client not available, skipping policy check")
+		response.Available = requested
+		response.Executable = true
+		return response, nil
+	}
+
+	pdpRequest := newPdpRequest(ctx, consumerInfo, schemaCollection.ProviderFieldMap)
+	pdpResponse, err := pdpClient.MakePdpRequest(ctx, pdpRequest)
+	ctx = f.logPolicyCheck(ctx, consumerInfo.ApplicationID, pdpRequest, pdpResponse, err)
+	if err != nil || pdpResponse == nil {
+		logger.Log.Error("PDP request failed", "error", err)
+		return nil, &PreflightError{Status: http.StatusBadGateway, Code: errors.CodePDPError, Message: "Authorization check failed"}
+	}
+
+	denied := make(map[string]string)
+	for _, field := range pdpResponse.UnauthorizedFields {
+		denied[fieldKey(field.SchemaID, field.FieldName)] = PreflightReasonUnauthorized
+	}
+	for _, field := range pdpResponse.ConditionFailedFields {
+		denied[fieldKey(field.SchemaID, field.FieldName)] = PreflightReasonConditionFailed
+	}
+	for _, field := range pdpResponse.ExpiredFields {
+		denied[fieldKey(field.SchemaID, field.FieldName)] = PreflightReasonAccessExpired
+	}
+
+	needsConsent := make(map[string]policy.ConsentRequiredField)
+	if pdpResponse.AppRequiresOwnerConsent {
+		for _, field := range pdpResponse.ConsentRequiredFields {
+			needsConsent[fieldKey(field.SchemaID, field.FieldName)] = field
+		}
+	}
+
+	// Fields the owner has already consented to are available
+	var pendingConsent []policy.ConsentRequiredField
+	var existing *consent.ConsentResponseInternalView
+	if len(needsConsent) > 0 {
+		if f.Configs.CeConfig.ClientURL == "" {
+			logger.Log.Warn("CE client not available, cannot check consent")
+			return nil, &PreflightError{Status: http.StatusBadGateway, Code: errors.CodeCEUnavailable, Message: "Consent required but consent engine not available"}
+		}
+		existing, err = consent.NewCEServiceClient(f.Configs.CeConfig.ClientURL).GetConsent(ctx, consumerInfo.ApplicationID, dataOwnerID)
+		if err != nil {
+			logger.Log.Error("CE request failed", "error", err)
+			return nil, &PreflightError{Status: http.StatusBadGateway, Code: errors.CodeCEError, Message: "Consent check failed"}
+		}
+	}
+	consented := consentedFields(existing)
+
+	for _, field := range requested {
+		key := fieldKey(field.SchemaID, field.FieldName)
+		if reason, ok := denied[key]; ok {
+			field.Reason = reason
+			response.Denied = append(response.Denied, field)
+			continue
+		}
+		required, ok := needsConsent[key]
+		if !ok || consented[key] {
+			response.Available = append(response.Available, field)
+			continue
+		}
+		field.DisplayName = required.DisplayName
+		field.Description = required.Description
+		response.ConsentRequired = append(response.ConsentRequired, field)
+		pendingConsent = append(pendingConsent, required)
+	}
+
+	if len(pendingConsent) > 0 {
+		response.Consent = &PreflightConsent{
+			Draft: newConsentRequest(consumerInfo.ApplicationID, dataOwnerID, pendingConsent,
+				consentRequirement(ctx, pdpClient, pdpRequest)),
+		}
+		if existing != nil {
+			response.Consent.ConsentID = existing.ConsentID
+			response.Consent.Status = existing.Status
+			response.Consent.ConsentPortalURL = existing.ConsentPortalURL
+		}
+	}
+	if len(pdpResponse.DeprecatedFields) > 0 {
+		response.Warnings = deprecationWarnings(pdpResponse.DeprecatedFields)
+	}
+	response.Executable = len(response.Denied) == 0 && len(response.ConsentRequired) == 0
+
+	logger.Log.Info("Preflight completed",
+		"applicationId", consumerInfo.ApplicationID,
+		"availableCount", len(response.Available),
+		"consentRequiredCount", len(response.ConsentRequired),
+		"deniedCount", len(response.Denied))
+	return response, nil
+}
+
+// requestedFields lists the distinct provider fields of a query
+func requestedFields(fieldMap *[]ProviderLevelFieldRecord) []PreflightField {
+	fields := make([]PreflightField, 0, len(*fieldMap))
+	seen := make(map[string]bool)
+	for _, field := range *fieldMap {
+		key := fieldKey(field.SchemaId, field.FieldPath)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fields = append(fields, PreflightField{
+			ProviderKey: field.ServiceKey,
+			SchemaID:    field.SchemaId,
+			FieldName:   field.FieldPath,
+		})
+	}
+	return fields
+}
+
+// consentedFields returns the fields an approved consent record covers
+func consentedFields(record *consent.ConsentResponseInternalView) map[string]bool {
+	consented := make(map[string]bool)
+	if record == nil || record.Status != consent.StatusApproved || record.Fields == nil {
+		return consented
+	}
+	for _, field := range *record.Fields {
+		consented[fieldKey(field.SchemaID, field.FieldName)] = true
+	}
+	return consented
+}
+
+// fieldKey identifies a field across schemas
+func fieldKey(schemaID, fieldName string) string {
+	return fmt.Sprintf("%s/%s", schemaID, fieldName)
+}
@@ -0,0 +1,172 @@
+package federator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/consent"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/policy"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const preflightTestSchemaSDL = `
+	directive @sourceInfo(providerKey: String!, providerField: String!, schemaId: String) on FIELD_DEFINITION
+	type Query {
+		personInfo(nic: String!): PersonInfo
+	}
+	type PersonInfo {
+		fullName: String @sourceInfo(providerKey: "drp", providerField: "person.fullName", schemaId: "drp-schema")
+		address: String @sourceInfo(providerKey: "drp", providerField: "person.address", schemaId: "drp-schema")
+		photo: String @sourceInfo(providerKey: "drp", providerField: "person.photo", schemaId: "drp-schema")
+	}
+`
+
+// newPreflightTestFederator returns a federator backed by a PDP that requires consent for the address and
+// denies the photo and asks for consent valid for a day, and by a CE that answers consent lookups with the given record
+func newPreflightTestFederator(t *testing.T, consentRecord *consent.ConsentResponseInternalView) *Federator {
+	t.Helper()
+
+	addressName := "Permanent address"
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/policy/requirements" {
+			json.NewEncoder(w).Encode(policy.ConsentRequirementsResponse{
+				ConsentRequired: true,
+				Requirements: []policy.ConsentRequirement{{
+					Owner:         policy.OwnerCitizen,
+					GrantDuration: "P1D",
+					Purposes:      []string{"Verification of residence"},
+					Fields:        []policy.ConsentRequiredField{{FieldName: "person.address", SchemaID: "drp-schema"}},
+				}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(policy.PdpResponse{
+			AppAuthorized:           false,
+			UnauthorizedFields:      []policy.ConsentRequiredField{{FieldName: "person.photo", SchemaID: "drp-schema"}},
+			AppRequiresOwnerConsent: true,
+			ConsentRequiredFields:   []policy.ConsentRequiredField{{FieldName: "person.address", SchemaID: "drp-schema", DisplayName: &addressName}},
+		})
+	}))
+	t.Cleanup(pdpServer.Close)
+
+	ceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "preflight must not create consents")
+		assert.Equal(t, "app-123", r.URL.Query().Get("appId"))
+		assert.Equal(t, "123", r.URL.Query().Get("ownerId"))
+		if consentRecord == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(consentRecord)
+	}))
+	t.Cleanup(ceServer.Close)
+
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true,
+		PdpConfig:     configs.PdpConfig{ClientURL: pdpServer.URL},
+		CeConfig:      configs.CeConfig{ClientURL: ceServer.URL},
+		ArgMapping: []*graphql.ArgMapping{
+			{ProviderKey: "drp", SchemaID: "drp-schema", TargetArgName: "nic", SourceArgPath: "personInfo-nic", TargetArgPath: "person"},
+		},
+	}
+	f, err := Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), &MockSchemaServiceWithSignature{SDL: preflightTestSchemaSDL})
+	require.NoError(t, err)
+	return f
+}
+
+func TestPreflight(t *testing.T) {
+	consumer := &auth.ConsumerAssertion{Subscriber: "sub-123", ClientID: "app-123", ApplicationID: "app-123"}
+	request := PreflightRequest{Request: graphql.Request{
+		Query:     `query Person($nic: String!) { personInfo(nic: $nic) { fullName address photo } }`,
+		Variables: map[string]interface{}{"nic": "123"},
+	}}
+
+	t.Run("Fields are split by decision and a consent draft is returned", func(t *testing.T) {
+		f := newPreflightTestFederator(t, nil)
+
+		resp, err := f.Preflight(context.Background(), request, consumer)
+		require.NoError(t, err)
+		assert.Equal(t, "123", resp.DataOwnerID)
+		assert.False(t, resp.Executable)
+
+		require.Len(t, resp.Available, 1)
+		assert.Equal(t, "person.fullName", resp.Available[0].FieldName)
+		require.Len(t, resp.Denied, 1)
+		assert.Equal(t, "person.photo", resp.Denied[0].FieldName)
+		assert.Equal(t, PreflightReasonUnauthorized, resp.Denied[0].Reason)
+		require.Len(t, resp.ConsentRequired, 1)
+		assert.Equal(t, "person.address", resp.ConsentRequired[0].FieldName)
+		assert.Equal(t, "Permanent address", *resp.ConsentRequired[0].DisplayName)
+
+		require.NotNil(t, resp.Consent)
+		assert.Empty(t, resp.Consent.ConsentID)
+		draft := resp.Consent.Draft
+		require.NotNil(t, draft)
+		assert.Equal(t, "app-123", draft.AppID)
+		assert.Equal(t, "123", draft.ConsentRequirement.OwnerID)
+		require.Len(t, draft.ConsentRequirement.Fields, 1)
+		assert.Equal(t, "person.address", draft.ConsentRequirement.Fields[0].FieldName)
+		assert.Equal(t, consent.OwnerCitizen, draft.ConsentRequirement.Fields[0].Owner)
+		require.NotNil(t, draft.GrantDuration)
+		assert.Equal(t, "P1D", *draft.GrantDuration)
+		assert.Equal(t, []string{"Verification of residence"}, draft.Purposes)
+	})
+
+	t.Run("Pending consent is reported with its portal URL", func(t *testing.T) {
+		portalURL := "https://consent.example.com/consents/consent-1"
+		f := newPreflightTestFederator(t, &consent.ConsentResponseInternalView{
+			ConsentID:        "consent-1",
+			Status:           consent.StatusPending,
+			ConsentPortalURL: &portalURL,
+		})
+
+		resp, err := f.Preflight(context.Background(), request, consumer)
+		require.NoError(t, err)
+		require.NotNil(t, resp.Consent)
+		assert.Equal(t, "consent-1", resp.Consent.ConsentID)
+		assert.Equal(t, consent.StatusPending, resp.Consent.Status)
+		assert.Equal(t, portalURL, *resp.Consent.ConsentPortalURL)
+		assert.NotNil(t, resp.Consent.Draft)
+	})
+
+	t.Run("Approved consent makes its fields available", func(t *testing.T) {
+		fields := []consent.ConsentField{{FieldName: "person.address", SchemaID: "drp-schema", Owner: consent.OwnerCitizen}}
+		f := newPreflightTestFederator(t, &consent.ConsentResponseInternalView{
+			ConsentID: "consent-1",
+			Status:    consent.StatusApproved,
+			Fields:    &fields,
+		})
+
+		resp, err := f.Preflight(context.Background(), PreflightRequest{
+			Request:     graphql.Request{Query: `query { personInfo(nic: "ignored") { fullName address } }`},
+			DataOwnerID: "123",
+		}, consumer)
+		require.NoError(t, err)
+		assert.True(t, resp.Executable)
+		assert.Len(t, resp.Available, 2)
+		assert.Empty(t, resp.ConsentRequired)
+		assert.Nil(t, resp.Consent)
+	})
+
+	t.Run("Missing data owner is rejected", func(t *testing.T) {
+		f := newPreflightTestFederator(t, nil)
+		f.Configs.ArgMapping = nil
+
+		_, err := f.Preflight(context.Background(), PreflightRequest{
+			Request: graphql.Request{Query: `query { personInfo(nic: "123") { fullName } }`},
+		}, consumer)
+		var preflightErr *PreflightError
+		require.ErrorAs(t, err, &preflightErr)
+		assert.Equal(t, http.StatusBadRequest, preflightErr.Status)
+		assert.Equal(t, "MISSING_IDENTIFIER", preflightErr.Code)
+	})
+}
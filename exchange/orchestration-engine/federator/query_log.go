@@ -0,0 +1,65 @@
+package federator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/querylog"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/quota"
+)
+
+// logQuery records an executed query in the query log
+func (f *Federator) logQuery(request graphql.Request, consumerInfo *auth.ConsumerAssertion, providers []string, start time.Time, response graphql.Response) {
+	operation := querylog.Fingerprint(request.Query)
+	entry := &querylog.Entry{
+		Fingerprint:     operation.Fingerprint,
+		NormalizedQuery: operation.Normalized,
+		OperationName:   operation.Name,
+		LatencyMs:       time.Since(start).Milliseconds(),
+		Providers:       providers,
+		RowCount:        quota.CountRecords(response.Data),
+		ErrorCount:      len(response.Errors),
+		ExecutedAt:      start,
+	}
+	if request.OperationName != "" {
+		entry.OperationName = request.OperationName
+	}
+	if consumerInfo != nil {
+		entry.ConsumerID = consumerInfo.ApplicationID
+	}
+	if entry.Providers == nil {
+		entry.Providers = []string{}
+	}
+	f.QueryLog.Record(entry)
+}
+
+// calledProviders returns the providers a federation request was sent to, leaving out the providers
+// that are not registered and those in a maintenance window, which were not called
+func (f *Federator) calledProviders(r *federationRequest, responses *FederationResponse) []string {
+	skipped := make(map[string]bool)
+	for serviceKey := range responses.Maintenance {
+		skipped[serviceKey] = true
+	}
+	for _, response := range responses.Responses {
+		if response.Maintenance != nil {
+			skipped[response.ServiceKey] = true
+		}
+	}
+
+	called := make(map[string]bool)
+	providers := []string{}
+	for _, request := range r.FederationServiceRequest {
+		if skipped[request.ServiceKey] || called[request.ServiceKey] {
+			continue
+		}
+		if _, exists := f.ProviderHandler.GetProvider(request.ServiceKey, request.SchemaID); !exists {
+			continue
+		}
+		called[request.ServiceKey] = true
+		providers = append(providers, request.ServiceKey)
+	}
+	sort.Strings(providers)
+	return providers
+}
@@ -0,0 +1,44 @@
+package federator
+
+import (
+	"context"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/quota"
+)
+
+// CheckQuota returns the consumer application's usage against its record quotas, or nil if quotas are
+// not enforced. Quotas fail open: the query is allowed if the usage cannot be determined.
+func (f *Federator) CheckQuota(ctx context.Context, consumerInfo *auth.ConsumerAssertion) *quota.Usage {
+	if f.Quotas == nil || consumerInfo == nil {
+		return nil
+	}
+	usage, err := f.Quotas.Usage(ctx, consumerInfo.ApplicationID)
+	if err != nil {
+		logger.Log.Warn("Failed to check application quota, allowing request", "applicationId", consumerInfo.ApplicationID, "error", err)
+		return nil
+	}
+	if usage.Exceeded() {
+		logger.Log.Info("Application quota exceeded", "applicationId", consumerInfo.ApplicationID,
+			"dailyUsed", usage.Daily.Used, "monthlyUsed", usage.Monthly.Used)
+	}
+	return usage
+}
+
+// RecordUsage counts the records of a response against the consumer application's quotas and updates
+// usage, as returned by CheckQuota, to include them
+func (f *Federator) RecordUsage(ctx context.Context, consumerInfo *auth.ConsumerAssertion, usage *quota.Usage, response graphql.Response) {
+	if f.Quotas == nil || consumerInfo == nil {
+		return
+	}
+	records := quota.CountRecords(response.Data)
+	if err := f.Quotas.Record(ctx, consumerInfo.ApplicationID, records); err != nil {
+		logger.Log.Error("Failed to record application usage", "applicationId", consumerInfo.ApplicationID, "records", records, "error", err)
+		return
+	}
+	if usage != nil {
+		usage.Count(records)
+	}
+}
@@ -16,8 +16,14 @@ require (
 	github.com/MicahParks/keyfunc/v3 v3.7.0
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/google/uuid v1.6.0
-	github.com/gov-dx-sandbox/exchange/shared/monitoring v0.0.0-00010101000000-000000000000
+	github.com/gov-dx-sandbox/exchange/policy-decision-point v0.0.0
+	github.com/gov-dx-sandbox/exchange/shared/monitoring v0.0.0
 	github.com/gov-dx-sandbox/shared/audit v0.0.0
+	github.com/gov-dx-sandbox/shared/health v0.0.0
+	github.com/gov-dx-sandbox/shared/requestid v0.0.0
+	github.com/gov-dx-sandbox/shared/s3 v0.0.0
+	gorm.io/driver/postgres v1.6.0
+	gorm.io/gorm v1.31.0
 )
 
 require (
@@ -41,18 +47,31 @@ require (
 	go.opentelemetry.io/otel/sdk/metric v1.32.0 // indirect
 	go.opentelemetry.io/otel/trace v1.32.0 // indirect
 	go.opentelemetry.io/proto/otlp v1.3.1 // indirect
-	golang.org/x/net v0.30.0 // indirect
-	golang.org/x/sys v0.27.0 // indirect
-	golang.org/x/text v0.20.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20241104194629-dd2ea8efbc28 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20241104194629-dd2ea8efbc28 // indirect
-	google.golang.org/grpc v1.67.1 // indirect
+	google.golang.org/grpc v1.67.1
 	google.golang.org/protobuf v1.35.1 // indirect
 )
 
 require (
 	github.com/MicahParks/jwkset v0.11.0 // indirect
+	github.com/gov-dx-sandbox/exchange/shared/utils v0.0.0 // indirect
+	github.com/gov-dx-sandbox/shared/database v0.0.0 // indirect
+	github.com/gov-dx-sandbox/shared/response v0.0.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/pgx/v5 v5.6.0 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	github.com/mattn/go-sqlite3 v1.14.22 // indirect
+	golang.org/x/crypto v0.40.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/time v0.9.0 // indirect
+	gorm.io/driver/sqlite v1.6.0 // indirect
 )
 
 require (
@@ -64,4 +83,18 @@ require (
 
 replace github.com/gov-dx-sandbox/shared/audit => ../../shared/audit
 
+replace github.com/gov-dx-sandbox/shared/health => ../../shared/health
+
+replace github.com/gov-dx-sandbox/shared/requestid => ../../shared/requestid
+
+replace github.com/gov-dx-sandbox/shared/s3 => ../../shared/s3
+
 replace github.com/gov-dx-sandbox/exchange/shared/monitoring => ../shared/monitoring
+
+replace github.com/gov-dx-sandbox/exchange/policy-decision-point => ../policy-decision-point
+
+replace github.com/gov-dx-sandbox/exchange/shared/utils => ../shared/utils
+
+replace github.com/gov-dx-sandbox/shared/response => ../../shared/response
+
+replace github.com/gov-dx-sandbox/shared/database => ../../shared/database
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/codes"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// CodeMappingService defines the behavior CodeMappingHandler depends on.
+type CodeMappingService interface {
+	List(ctx context.Context, providerKey, codeList string) ([]*codes.Mapping, error)
+	Put(ctx context.Context, mapping *codes.Mapping) (*codes.Mapping, error)
+	Delete(ctx context.Context, providerKey, codeList, providerCode string) error
+}
+
+// CodeMappingHandler handles HTTP requests for managing provider code mappings
+type CodeMappingHandler struct {
+	codeMappingService CodeMappingService
+}
+
+// NewCodeMappingHandler creates a new code mapping handler
+func NewCodeMappingHandler(codeMappingService CodeMappingService) *CodeMappingHandler {
+	return &CodeMappingHandler{
+		codeMappingService: codeMappingService,
+	}
+}
+
+// PutCodeMappingRequest represents a request to create or replace a code mapping
+type PutCodeMappingRequest struct {
+	UnifiedCode string `json:"unifiedCode"`
+	Description string `json:"description"`
+}
+
+// GetCodeMappings handles GET /code-mappings - list code mappings, optionally filtered by the
+// providerKey and codeList query parameters
+func (h *CodeMappingHandler) GetCodeMappings(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.codeMappingService.List(r.Context(), r.URL.Query().Get("providerKey"), r.URL.Query().Get("codeList"))
+	if err != nil {
+		logger.Log.Error("Failed to get code mappings", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(mappings)
+}
+
+// PutCodeMapping handles PUT /code-mappings/{providerKey}/{codeList}/{providerCode} - create or
+// replace the mapping of a provider code
+func (h *CodeMappingHandler) PutCodeMapping(w http.ResponseWriter, r *http.Request) {
+	var req PutCodeMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.UnifiedCode == "" {
+		http.Error(w, "unifiedCode is required", http.StatusBadRequest)
+		return
+	}
+
+	mapping, err := h.codeMappingService.Put(r.Context(), &codes.Mapping{
+		ProviderKey:  chi.URLParam(r, "providerKey"),
+		CodeList:     chi.URLParam(r, "codeList"),
+		ProviderCode: chi.URLParam(r, "providerCode"),
+		UnifiedCode:  req.UnifiedCode,
+		Description:  req.Description,
+	})
+	if errors.Is(err, codes.ErrInvalidMapping) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to save code mapping", "error", err)
+		http.Error(w, "Failed to save code mapping", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(mapping)
+}
+
+// DeleteCodeMapping handles DELETE /code-mappings/{providerKey}/{codeList}/{providerCode} - remove
+// the mapping of a provider code
+func (h *CodeMappingHandler) DeleteCodeMapping(w http.ResponseWriter, r *http.Request) {
+	err := h.codeMappingService.Delete(r.Context(), chi.URLParam(r, "providerKey"), chi.URLParam(r, "codeList"), chi.URLParam(r, "providerCode"))
+	if errors.Is(err, codes.ErrNotFound) {
+		http.Error(w, "Code mapping not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to delete code mapping", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
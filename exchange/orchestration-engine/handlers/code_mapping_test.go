@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/codes"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withCodeMappingParams(req *http.Request, providerKey, codeList, providerCode string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("providerKey", providerKey)
+	rctx.URLParams.Add("codeList", codeList)
+	rctx.URLParams.Add("providerCode", providerCode)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestCodeMappingHandler_PutCodeMapping(t *testing.T) {
+	normalizer := codes.NewNormalizer(codes.NewMemoryStore(), 0)
+	handler := NewCodeMappingHandler(normalizer)
+
+	body, _ := json.Marshal(PutCodeMappingRequest{UnifiedCode: "LIGHT_MOTOR_VEHICLE", Description: "Light motor vehicle"})
+	req := withCodeMappingParams(httptest.NewRequest(http.MethodPut, "/code-mappings/dmt/vehicleClass/B1", bytes.NewBuffer(body)), "dmt", "vehicleClass", "B1")
+	w := httptest.NewRecorder()
+
+	handler.PutCodeMapping(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var mapping codes.Mapping
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &mapping))
+	assert.Equal(t, "B1", mapping.ProviderCode)
+	assert.Equal(t, "LIGHT_MOTOR_VEHICLE", mapping.UnifiedCode)
+	assert.Equal(t, "LIGHT_MOTOR_VEHICLE", normalizer.Normalize("dmt", "vehicleClass", "B1"))
+}
+
+func TestCodeMappingHandler_PutCodeMapping_MissingUnifiedCode_ReturnsBadRequest(t *testing.T) {
+	handler := NewCodeMappingHandler(codes.NewNormalizer(codes.NewMemoryStore(), 0))
+
+	req := withCodeMappingParams(httptest.NewRequest(http.MethodPut, "/code-mappings/dmt/vehicleClass/B1", bytes.NewBufferString(`{}`)), "dmt", "vehicleClass", "B1")
+	w := httptest.NewRecorder()
+
+	handler.PutCodeMapping(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unifiedCode is required")
+}
+
+func TestCodeMappingHandler_GetCodeMappings_FiltersByQuery(t *testing.T) {
+	normalizer := codes.NewNormalizer(codes.NewMemoryStore(), 0)
+	for _, mapping := range []*codes.Mapping{
+		{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1", UnifiedCode: "LIGHT_MOTOR_VEHICLE"},
+		{ProviderKey: "rgd", CodeList: "gender", ProviderCode: "M", UnifiedCode: "MALE"},
+	} {
+		_, err := normalizer.Put(context.Background(), mapping)
+		require.NoError(t, err)
+	}
+	handler := NewCodeMappingHandler(normalizer)
+
+	req := httptest.NewRequest(http.MethodGet, "/code-mappings?providerKey=rgd", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetCodeMappings(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var mappings []codes.Mapping
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &mappings))
+	require.Len(t, mappings, 1)
+	assert.Equal(t, "MALE", mappings[0].UnifiedCode)
+}
+
+func TestCodeMappingHandler_DeleteCodeMapping(t *testing.T) {
+	normalizer := codes.NewNormalizer(codes.NewMemoryStore(), 0)
+	_, err := normalizer.Put(context.Background(), &codes.Mapping{ProviderKey: "dmt", CodeList: "vehicleClass", ProviderCode: "B1", UnifiedCode: "LIGHT_MOTOR_VEHICLE"})
+	require.NoError(t, err)
+	handler := NewCodeMappingHandler(normalizer)
+
+	req := withCodeMappingParams(httptest.NewRequest(http.MethodDelete, "/code-mappings/dmt/vehicleClass/B1", nil), "dmt", "vehicleClass", "B1")
+	w := httptest.NewRecorder()
+	handler.DeleteCodeMapping(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = withCodeMappingParams(httptest.NewRequest(http.MethodDelete, "/code-mappings/dmt/vehicleClass/B1", nil), "dmt", "vehicleClass", "B1")
+	w = httptest.NewRecorder()
+	handler.DeleteCodeMapping(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
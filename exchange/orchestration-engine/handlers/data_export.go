@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/dataexport"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// DataExportService defines the behavior DataExportHandler depends on.
+type DataExportService interface {
+	RegisterQuery(ctx context.Context, query *dataexport.Query, createdBy string) (*dataexport.Query, error)
+	ListQueries(ctx context.Context) ([]*dataexport.Query, error)
+	Submit(ctx context.Context, consumer *auth.ConsumerAssertion, queryID, purpose string, parameters []map[string]interface{}) (*dataexport.Job, error)
+	Get(ctx context.Context, applicationID, id string) (*dataexport.Job, error)
+	List(ctx context.Context, applicationID, status string) ([]*dataexport.Job, error)
+	Approve(ctx context.Context, id, reviewedBy, note string) (*dataexport.Job, error)
+	Reject(ctx context.Context, id, reviewedBy, note string) (*dataexport.Job, error)
+	DownloadLink(ctx context.Context, applicationID, id string) (*dataexport.DownloadLink, error)
+}
+
+// ConsumerAuthenticator authenticates the consumer application of a request. When the request is
+// rejected it writes the response and returns nil.
+type ConsumerAuthenticator func(w http.ResponseWriter, r *http.Request) *auth.ConsumerAssertion
+
+// DataExportHandler handles HTTP requests for data exports: registering export queries and reviewing
+// jobs for admins, and submitting jobs and downloading their archives for consumer applications
+type DataExportHandler struct {
+	dataExportService DataExportService
+	authenticate      ConsumerAuthenticator
+}
+
+// NewDataExportHandler creates a new data export handler; a nil service means data exports are not enabled
+func NewDataExportHandler(dataExportService DataExportService, authenticate ConsumerAuthenticator) *DataExportHandler {
+	return &DataExportHandler{
+		dataExportService: dataExportService,
+		authenticate:      authenticate,
+	}
+}
+
+// RegisterExportQueryRequest represents a request to register a query for data exports
+type RegisterExportQueryRequest struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Query          string   `json:"query"`
+	ApplicationIDs []string `json:"applicationIds"`
+	CreatedBy      string   `json:"createdBy"`
+}
+
+// SubmitExportRequest represents a request to run a registered query once for each set of parameters
+type SubmitExportRequest struct {
+	QueryID    string                   `json:"queryId"`
+	Purpose    string                   `json:"purpose"`
+	Parameters []map[string]interface{} `json:"parameters"`
+}
+
+// ReviewExportRequest represents an admin's decision on the archive of a job
+type ReviewExportRequest struct {
+	ReviewedBy string `json:"reviewedBy"`
+	Note       string `json:"note"`
+}
+
+// GetExportQueries handles GET /admin/export-queries - list the registered export queries
+func (h *DataExportHandler) GetExportQueries(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	queries, err := h.dataExportService.ListQueries(r.Context())
+	if err != nil {
+		logger.Log.Error("Failed to get export queries", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(queries)
+}
+
+// RegisterExportQuery handles POST /admin/export-queries - register a query the given applications
+// may export data with
+func (h *DataExportHandler) RegisterExportQuery(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	var req RegisterExportQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	query, err := h.dataExportService.RegisterQuery(r.Context(), &dataexport.Query{
+		Name:           req.Name,
+		Description:    req.Description,
+		Query:          req.Query,
+		ApplicationIDs: req.ApplicationIDs,
+	}, req.CreatedBy)
+	if err != nil {
+		writeDataExportError(w, "Failed to register export query", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(query)
+}
+
+// GetExports handles GET /admin/exports - list the jobs, optionally filtered by the applicationId
+// and status query parameters
+func (h *DataExportHandler) GetExports(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	h.listJobs(w, r, r.URL.Query().Get("applicationId"))
+}
+
+// GetExport handles GET /admin/exports/{id} - get a job
+func (h *DataExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	h.getJob(w, r, "")
+}
+
+// ApproveExport handles POST /admin/exports/{id}/approve - release the archive of a job to its application
+func (h *DataExportHandler) ApproveExport(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, true)
+}
+
+// RejectExport handles POST /admin/exports/{id}/reject - refuse to release the archive of a job,
+// deleting it
+func (h *DataExportHandler) RejectExport(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, false)
+}
+
+// SubmitExport handles POST /public/exports - queue a job running a registered query for the
+// consumer application
+func (h *DataExportHandler) SubmitExport(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	consumer := h.consumer(w, r)
+	if consumer == nil {
+		return
+	}
+	var req SubmitExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.dataExportService.Submit(r.Context(), consumer, req.QueryID, req.Purpose, req.Parameters)
+	if err != nil {
+		writeDataExportError(w, "Failed to submit data export", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// GetConsumerExports handles GET /public/exports - list the jobs of the consumer application,
+// optionally filtered by the status query parameter
+func (h *DataExportHandler) GetConsumerExports(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	consumer := h.consumer(w, r)
+	if consumer == nil {
+		return
+	}
+	h.listJobs(w, r, consumer.ApplicationID)
+}
+
+// GetConsumerExport handles GET /public/exports/{id} - get a job of the consumer application
+func (h *DataExportHandler) GetConsumerExport(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	consumer := h.consumer(w, r)
+	if consumer == nil {
+		return
+	}
+	h.getJob(w, r, consumer.ApplicationID)
+}
+
+// GetExportDownload handles GET /public/exports/{id}/download - issue a signed link to the encrypted
+// archive of an approved job of the consumer application, with the key to decrypt it
+func (h *DataExportHandler) GetExportDownload(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	consumer := h.consumer(w, r)
+	if consumer == nil {
+		return
+	}
+
+	link, err := h.dataExportService.DownloadLink(r.Context(), consumer.ApplicationID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeDataExportError(w, "Failed to issue download link", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(link)
+}
+
+// consumer authenticates the consumer application of a request. Jobs are scoped to the application,
+// so tokens without one are rejected.
+func (h *DataExportHandler) consumer(w http.ResponseWriter, r *http.Request) *auth.ConsumerAssertion {
+	consumer := h.authenticate(w, r)
+	if consumer == nil {
+		return nil
+	}
+	if consumer.ApplicationID == "" {
+		http.Error(w, "Forbidden: the token does not identify an application", http.StatusForbidden)
+		return nil
+	}
+	return consumer
+}
+
+func (h *DataExportHandler) listJobs(w http.ResponseWriter, r *http.Request, applicationID string) {
+	jobs, err := h.dataExportService.List(r.Context(), applicationID, r.URL.Query().Get("status"))
+	if err != nil {
+		writeDataExportError(w, "Failed to get data exports", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+func (h *DataExportHandler) getJob(w http.ResponseWriter, r *http.Request, applicationID string) {
+	job, err := h.dataExportService.Get(r.Context(), applicationID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeDataExportError(w, "Failed to get data export", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *DataExportHandler) review(w http.ResponseWriter, r *http.Request, approve bool) {
+	if !h.enabled(w) {
+		return
+	}
+	var req ReviewExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.ReviewedBy == "" {
+		http.Error(w, "reviewedBy is required", http.StatusBadRequest)
+		return
+	}
+
+	decide := h.dataExportService.Reject
+	if approve {
+		decide = h.dataExportService.Approve
+	}
+	job, err := decide(r.Context(), chi.URLParam(r, "id"), req.ReviewedBy, req.Note)
+	if err != nil {
+		writeDataExportError(w, "Failed to review data export", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *DataExportHandler) enabled(w http.ResponseWriter) bool {
+	if h.dataExportService == nil {
+		http.Error(w, "Data exports are not enabled", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// writeDataExportError maps data export errors to responses, logging unexpected ones with message
+func writeDataExportError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, dataexport.ErrQueryNotFound):
+		http.Error(w, "Export query not found", http.StatusNotFound)
+	case errors.Is(err, dataexport.ErrJobNotFound):
+		http.Error(w, "Data export not found", http.StatusNotFound)
+	case errors.Is(err, dataexport.ErrInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, dataexport.ErrNotAllowed):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, dataexport.ErrQueryExists), errors.Is(err, dataexport.ErrNotAwaitingApproval),
+		errors.Is(err, dataexport.ErrNotApproved):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Log.Error(message, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
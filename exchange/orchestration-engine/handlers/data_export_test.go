@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/dataexport"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportStorage keeps data export archives in memory
+type exportStorage map[string][]byte
+
+func (s exportStorage) Put(_ context.Context, key, _ string, content []byte) error {
+	s[key] = content
+	return nil
+}
+
+func (s exportStorage) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s exportStorage) PresignGet(key, _ string, _ time.Duration) (string, error) {
+	return "https://storage.test/" + key, nil
+}
+
+func jsonBody(t *testing.T, v interface{}) *bytes.Buffer {
+	t.Helper()
+	body, err := json.Marshal(v)
+	require.NoError(t, err)
+	return bytes.NewBuffer(body)
+}
+
+func TestDataExportHandler_Workflow(t *testing.T) {
+	run := func(_ context.Context, request graphql.Request, _ *auth.ConsumerAssertion) graphql.Response {
+		return graphql.Response{Data: map[string]interface{}{"personInfo": map[string]interface{}{"nic": request.Variables["nic"]}}}
+	}
+	service, err := dataexport.NewService(dataexport.NewMemoryStore(), exportStorage{}, bytes.Repeat([]byte{1}, 32), run, dataexport.Options{})
+	require.NoError(t, err)
+	consumer := &auth.ConsumerAssertion{ApplicationID: "stats-app", ClientID: "stats-client"}
+	handler := NewDataExportHandler(service, func(http.ResponseWriter, *http.Request) *auth.ConsumerAssertion { return consumer })
+
+	// An admin registers the query for the statistics application
+	w := httptest.NewRecorder()
+	handler.RegisterExportQuery(w, httptest.NewRequest(http.MethodPost, "/admin/export-queries", jsonBody(t, RegisterExportQueryRequest{
+		Name:           "census-persons",
+		Query:          `query Person($nic: String!) { personInfo(nic: $nic) { fullName } }`,
+		ApplicationIDs: []string{"stats-app"},
+		CreatedBy:      "admin@example.com",
+	})))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var query dataexport.Query
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &query))
+	assert.Equal(t, []string{"nic"}, query.Parameters)
+
+	w = httptest.NewRecorder()
+	handler.GetExportQueries(w, httptest.NewRequest(http.MethodGet, "/admin/export-queries", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "census-persons")
+
+	// The application submits a job
+	w = httptest.NewRecorder()
+	handler.SubmitExport(w, httptest.NewRequest(http.MethodPost, "/public/exports", jsonBody(t, SubmitExportRequest{
+		QueryID:    query.ID,
+		Purpose:    "annual census",
+		Parameters: []map[string]interface{}{{"nic": "199012345678"}, {"nic": "198512345678"}},
+	})))
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var job dataexport.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, dataexport.StatusQueued, job.Status)
+
+	require.True(t, service.RunNext(context.Background()))
+
+	w = httptest.NewRecorder()
+	handler.GetConsumerExport(w, withURLParam(httptest.NewRequest(http.MethodGet, "/public/exports/"+job.ID, nil), "id", job.ID))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, dataexport.StatusAwaitingApproval, job.Status)
+	assert.Equal(t, 2, job.ProcessedRows)
+
+	// No download link until an admin approves the archive
+	w = httptest.NewRecorder()
+	handler.GetExportDownload(w, withURLParam(httptest.NewRequest(http.MethodGet, "/public/exports/"+job.ID+"/download", nil), "id", job.ID))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.GetExports(w, httptest.NewRequest(http.MethodGet, "/admin/exports?status=awaiting_approval", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var jobs []*dataexport.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jobs))
+	require.Len(t, jobs, 1)
+
+	w = httptest.NewRecorder()
+	handler.ApproveExport(w, withURLParam(httptest.NewRequest(http.MethodPost, "/admin/exports/"+job.ID+"/approve", jsonBody(t, ReviewExportRequest{})), "id", job.ID))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ApproveExport(w, withURLParam(httptest.NewRequest(http.MethodPost, "/admin/exports/"+job.ID+"/approve",
+		jsonBody(t, ReviewExportRequest{ReviewedBy: "reviewer@example.com"})), "id", job.ID))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.RejectExport(w, withURLParam(httptest.NewRequest(http.MethodPost, "/admin/exports/"+job.ID+"/reject",
+		jsonBody(t, ReviewExportRequest{ReviewedBy: "reviewer@example.com"})), "id", job.ID))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.GetExportDownload(w, withURLParam(httptest.NewRequest(http.MethodGet, "/public/exports/"+job.ID+"/download", nil), "id", job.ID))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	var link dataexport.DownloadLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	assert.Equal(t, "https://storage.test/data-exports/"+job.ID+".tar.gz.enc", link.URL)
+	assert.NotEmpty(t, link.EncryptionKey)
+
+	// Other applications cannot see the job
+	consumer = &auth.ConsumerAssertion{ApplicationID: "other-app"}
+	w = httptest.NewRecorder()
+	handler.GetExportDownload(w, withURLParam(httptest.NewRequest(http.MethodGet, "/public/exports/"+job.ID+"/download", nil), "id", job.ID))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.SubmitExport(w, httptest.NewRequest(http.MethodPost, "/public/exports", jsonBody(t, SubmitExportRequest{
+		QueryID: query.ID, Purpose: "census", Parameters: []map[string]interface{}{{"nic": "199012345678"}},
+	})))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Tokens without an application cannot list every application's jobs
+	consumer = &auth.ConsumerAssertion{}
+	w = httptest.NewRecorder()
+	handler.GetConsumerExports(w, httptest.NewRequest(http.MethodGet, "/public/exports", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDataExportHandler_Rejections(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewDataExportHandler(nil, nil).GetExports(w, httptest.NewRequest(http.MethodGet, "/admin/exports", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = httptest.NewRecorder()
+	NewDataExportHandler(nil, nil).ApproveExport(w, withURLParam(httptest.NewRequest(http.MethodPost, "/admin/exports/1/approve", nil), "id", "1"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	service, err := dataexport.NewService(dataexport.NewMemoryStore(), exportStorage{}, bytes.Repeat([]byte{1}, 32), nil, dataexport.Options{})
+	require.NoError(t, err)
+	handler := NewDataExportHandler(service, func(w http.ResponseWriter, _ *http.Request) *auth.ConsumerAssertion {
+		http.Error(w, "Unauthorized: invalid or expired token", http.StatusUnauthorized)
+		return nil
+	})
+
+	w = httptest.NewRecorder()
+	handler.SubmitExport(w, httptest.NewRequest(http.MethodPost, "/public/exports", jsonBody(t, SubmitExportRequest{})))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.GetExport(w, withURLParam(httptest.NewRequest(http.MethodGet, "/admin/exports/missing", nil), "id", "missing"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.RegisterExportQuery(w, httptest.NewRequest(http.MethodPost, "/admin/export-queries", jsonBody(t, RegisterExportQueryRequest{
+		Name: "bad", Query: "mutation { reset }", ApplicationIDs: []string{"stats-app"},
+	})))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
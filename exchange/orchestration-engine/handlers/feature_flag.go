@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/featureflags"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// FeatureFlagService defines the behavior FeatureFlagHandler depends on.
+type FeatureFlagService interface {
+	List(ctx context.Context) ([]*featureflags.Flag, error)
+	Put(ctx context.Context, flag *featureflags.Flag) (*featureflags.Flag, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// FeatureFlagHandler handles HTTP requests for managing feature flags
+type FeatureFlagHandler struct {
+	featureFlagService FeatureFlagService
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(featureFlagService FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		featureFlagService: featureFlagService,
+	}
+}
+
+// PutFeatureFlagRequest represents a request to create or replace a feature flag
+type PutFeatureFlagRequest struct {
+	Description    string   `json:"description"`
+	Enabled        bool     `json:"enabled"`
+	Percentage     float64  `json:"percentage"`
+	ApplicationIDs []string `json:"applicationIds"`
+}
+
+// GetFeatureFlags handles GET /admin/feature-flags - list the feature flags
+func (h *FeatureFlagHandler) GetFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.featureFlagService.List(r.Context())
+	if err != nil {
+		logger.Log.Error("Failed to get feature flags", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flags)
+}
+
+// PutFeatureFlag handles PUT /admin/feature-flags/{key} - create or replace a feature flag
+func (h *FeatureFlagHandler) PutFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	var req PutFeatureFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	flag, err := h.featureFlagService.Put(r.Context(), &featureflags.Flag{
+		Key:            chi.URLParam(r, "key"),
+		Description:    req.Description,
+		Enabled:        req.Enabled,
+		Percentage:     req.Percentage,
+		ApplicationIDs: req.ApplicationIDs,
+	})
+	if errors.Is(err, featureflags.ErrInvalidFlag) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to save feature flag", "error", err)
+		http.Error(w, "Failed to save feature flag", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flag)
+}
+
+// DeleteFeatureFlag handles DELETE /admin/feature-flags/{key} - remove a feature flag, turning it off
+// for every application
+func (h *FeatureFlagHandler) DeleteFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	err := h.featureFlagService.Delete(r.Context(), chi.URLParam(r, "key"))
+	if errors.Is(err, featureflags.ErrNotFound) {
+		http.Error(w, "Feature flag not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to delete feature flag", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
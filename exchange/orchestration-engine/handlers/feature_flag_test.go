@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/featureflags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagHandler_PutListAndDelete(t *testing.T) {
+	evaluator := featureflags.NewEvaluator(featureflags.NewMemoryStore(), time.Hour)
+	handler := NewFeatureFlagHandler(evaluator)
+
+	body, _ := json.Marshal(PutFeatureFlagRequest{Description: "New DMT provider", Enabled: true, ApplicationIDs: []string{"app-1"}})
+	w := httptest.NewRecorder()
+	handler.PutFeatureFlag(w, withURLParam(httptest.NewRequest(http.MethodPut, "/admin/feature-flags/provider.dmt-v2", bytes.NewBuffer(body)), "key", "provider.dmt-v2"))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var flag featureflags.Flag
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &flag))
+	assert.Equal(t, "provider.dmt-v2", flag.Key)
+	assert.Equal(t, []string{"app-1"}, flag.ApplicationIDs)
+	assert.True(t, evaluator.Enabled("provider.dmt-v2", "app-1"))
+
+	w = httptest.NewRecorder()
+	handler.GetFeatureFlags(w, httptest.NewRequest(http.MethodGet, "/admin/feature-flags", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var flags []*featureflags.Flag
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &flags))
+	require.Len(t, flags, 1)
+
+	w = httptest.NewRecorder()
+	handler.DeleteFeatureFlag(w, withURLParam(httptest.NewRequest(http.MethodDelete, "/admin/feature-flags/provider.dmt-v2", nil), "key", "provider.dmt-v2"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.False(t, evaluator.Enabled("provider.dmt-v2", "app-1"))
+
+	w = httptest.NewRecorder()
+	handler.DeleteFeatureFlag(w, withURLParam(httptest.NewRequest(http.MethodDelete, "/admin/feature-flags/provider.dmt-v2", nil), "key", "provider.dmt-v2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestFeatureFlagHandler_InvalidRequests_ReturnBadRequest(t *testing.T) {
+	handler := NewFeatureFlagHandler(featureflags.NewEvaluator(featureflags.NewMemoryStore(), time.Hour))
+
+	for name, tc := range map[string]struct{ key, body string }{
+		"invalid JSON":        {"defer", `{`},
+		"invalid key":         {"Defer Support", `{"enabled":true}`},
+		"percentage over 100": {"defer", `{"enabled":true,"percentage":150}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.PutFeatureFlag(w, withURLParam(httptest.NewRequest(http.MethodPut, "/admin/feature-flags/x", bytes.NewBufferString(tc.body)), "key", tc.key))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
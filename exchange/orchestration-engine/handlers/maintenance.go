@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
+	"github.com/go-chi/chi/v5"
+)
+
+// MaintenanceService defines the behavior MaintenanceHandler depends on.
+type MaintenanceService interface {
+	List(ctx context.Context, providerKey string, includeEnded bool) ([]*maintenance.Window, error)
+	Create(ctx context.Context, window *maintenance.Window) (*maintenance.Window, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// MaintenanceHandler handles HTTP requests for managing provider maintenance windows
+type MaintenanceHandler struct {
+	maintenanceService MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new maintenance window handler
+func NewMaintenanceHandler(maintenanceService MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// CreateMaintenanceWindowRequest represents a request to declare a maintenance window
+type CreateMaintenanceWindowRequest struct {
+	ProviderKey string `json:"providerKey"`
+	// StartsAt defaults to now
+	StartsAt    *time.Time `json:"startsAt,omitempty"`
+	EndsAt      time.Time  `json:"endsAt"`
+	Reason      string     `json:"reason"`
+	ServeCached bool       `json:"serveCached"`
+}
+
+// GetMaintenanceWindows handles GET /maintenance-windows - list the windows that have not ended,
+// optionally filtered by the providerKey query parameter, or all windows with includeEnded=true
+func (h *MaintenanceHandler) GetMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	includeEnded := false
+	if value := r.URL.Query().Get("includeEnded"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "includeEnded must be true or false", http.StatusBadRequest)
+			return
+		}
+		includeEnded = parsed
+	}
+
+	windows, err := h.maintenanceService.List(r.Context(), r.URL.Query().Get("providerKey"), includeEnded)
+	if err != nil {
+		logger.Log.Error("Failed to get maintenance windows", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(windows)
+}
+
+// CreateMaintenanceWindow handles POST /maintenance-windows - declare a maintenance window for a provider
+func (h *MaintenanceHandler) CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	var req CreateMaintenanceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	window := &maintenance.Window{
+		ProviderKey: req.ProviderKey,
+		EndsAt:      req.EndsAt,
+		Reason:      req.Reason,
+		ServeCached: req.ServeCached,
+	}
+	if req.StartsAt != nil {
+		window.StartsAt = *req.StartsAt
+	}
+	created, err := h.maintenanceService.Create(r.Context(), window)
+	if errors.Is(err, maintenance.ErrInvalidWindow) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to save maintenance window", "error", err)
+		http.Error(w, "Failed to save maintenance window", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// DeleteMaintenanceWindow handles DELETE /maintenance-windows/{id} - remove a maintenance window,
+// ending it early if it is active
+func (h *MaintenanceHandler) DeleteMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.maintenanceService.Delete(r.Context(), id)
+	if errors.Is(err, maintenance.ErrNotFound) {
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to delete maintenance window", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
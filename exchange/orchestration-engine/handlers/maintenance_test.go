@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withMaintenanceWindowID(req *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestMaintenanceHandler_CreateListAndDelete(t *testing.T) {
+	schedule := maintenance.NewSchedule(maintenance.NewMemoryStore(), time.Hour)
+	handler := NewMaintenanceHandler(schedule)
+
+	body, _ := json.Marshal(CreateMaintenanceWindowRequest{
+		ProviderKey: "drp", EndsAt: time.Now().Add(time.Hour), Reason: "database upgrade", ServeCached: true,
+	})
+	w := httptest.NewRecorder()
+	handler.CreateMaintenanceWindow(w, httptest.NewRequest(http.MethodPost, "/maintenance-windows", bytes.NewBuffer(body)))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created maintenance.Window
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "drp", created.ProviderKey)
+	assert.True(t, created.ServeCached)
+	assert.NotNil(t, schedule.Active("drp", time.Now()))
+
+	w = httptest.NewRecorder()
+	handler.GetMaintenanceWindows(w, httptest.NewRequest(http.MethodGet, "/maintenance-windows?providerKey=drp", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var windows []*maintenance.Window
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &windows))
+	require.Len(t, windows, 1)
+
+	id := strconv.FormatInt(created.ID, 10)
+	w = httptest.NewRecorder()
+	handler.DeleteMaintenanceWindow(w, withMaintenanceWindowID(httptest.NewRequest(http.MethodDelete, "/maintenance-windows/"+id, nil), id))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Nil(t, schedule.Active("drp", time.Now()))
+
+	w = httptest.NewRecorder()
+	handler.DeleteMaintenanceWindow(w, withMaintenanceWindowID(httptest.NewRequest(http.MethodDelete, "/maintenance-windows/"+id, nil), id))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMaintenanceHandler_InvalidRequests_ReturnBadRequest(t *testing.T) {
+	handler := NewMaintenanceHandler(maintenance.NewSchedule(maintenance.NewMemoryStore(), time.Hour))
+
+	for name, body := range map[string]string{
+		"invalid JSON":     `{`,
+		"missing provider": `{"endsAt":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`,
+		"ended window":     `{"providerKey":"drp","endsAt":"2020-01-01T00:00:00Z"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.CreateMaintenanceWindow(w, httptest.NewRequest(http.MethodPost, "/maintenance-windows", bytes.NewBufferString(body)))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	handler.GetMaintenanceWindows(w, httptest.NewRequest(http.MethodGet, "/maintenance-windows?includeEnded=maybe", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.DeleteMaintenanceWindow(w, withMaintenanceWindowID(httptest.NewRequest(http.MethodDelete, "/maintenance-windows/abc", nil), "abc"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
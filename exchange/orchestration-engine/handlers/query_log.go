@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/querylog"
+)
+
+// DefaultQueryLogPeriod is how far back the query log is searched when no since parameter is given
+const DefaultQueryLogPeriod = 24 * time.Hour
+
+// QueryLogService defines the behavior QueryLogHandler depends on.
+type QueryLogService interface {
+	Top(ctx context.Context, opts querylog.TopOptions) ([]*querylog.Summary, error)
+	List(ctx context.Context, opts querylog.ListOptions) ([]*querylog.Entry, error)
+}
+
+// QueryLogHandler handles HTTP requests for analysing the executed queries
+type QueryLogHandler struct {
+	queryLogService QueryLogService
+}
+
+// NewQueryLogHandler creates a new query log handler; a nil service means queries are not logged
+func NewQueryLogHandler(queryLogService QueryLogService) *QueryLogHandler {
+	return &QueryLogHandler{
+		queryLogService: queryLogService,
+	}
+}
+
+// GetTopQueries handles GET /admin/queries/top - aggregate the executed queries by fingerprint,
+// ordered by the orderBy query parameter, optionally filtered by consumerId and since
+func (h *QueryLogHandler) GetTopQueries(w http.ResponseWriter, r *http.Request) {
+	if h.queryLogService == nil {
+		http.Error(w, "Query logging is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+	query := r.URL.Query()
+	since, err := parseSince(query.Get("since"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := parseLimit(query.Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summaries, err := h.queryLogService.Top(r.Context(), querylog.TopOptions{
+		Since:      since,
+		ConsumerID: query.Get("consumerId"),
+		OrderBy:    query.Get("orderBy"),
+		Limit:      limit,
+	})
+	if errors.Is(err, querylog.ErrInvalidQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get top queries", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summaries)
+}
+
+// GetQueries handles GET /admin/queries - list the executed queries, most recent first, optionally
+// filtered by fingerprint, consumerId, minLatencyMs and since
+func (h *QueryLogHandler) GetQueries(w http.ResponseWriter, r *http.Request) {
+	if h.queryLogService == nil {
+		http.Error(w, "Query logging is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+	query := r.URL.Query()
+	since, err := parseSince(query.Get("since"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := parseLimit(query.Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var minLatency int64
+	if value := query.Get("minLatencyMs"); value != "" {
+		minLatency, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, "minLatencyMs must be a number of milliseconds", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := h.queryLogService.List(r.Context(), querylog.ListOptions{
+		Since:        since,
+		Fingerprint:  query.Get("fingerprint"),
+		ConsumerID:   query.Get("consumerId"),
+		MinLatencyMs: minLatency,
+		Limit:        limit,
+	})
+	if errors.Is(err, querylog.ErrInvalidQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get query log", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// parseSince accepts a duration back from now, e.g. "1h", or an RFC 3339 time, and defaults to
+// DefaultQueryLogPeriod
+func parseSince(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().Add(-DefaultQueryLogPeriod), nil
+	}
+	if period, err := time.ParseDuration(value); err == nil && period > 0 {
+		return time.Now().Add(-period), nil
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since must be a duration such as 24h or an RFC 3339 time")
+	}
+	return since, nil
+}
+
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("limit must be a positive number")
+	}
+	return limit, nil
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/querylog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQueryLog(t *testing.T) *querylog.MemoryStore {
+	store := querylog.NewMemoryStore(0)
+	now := time.Now()
+	require.NoError(t, store.Insert(context.Background(), []*querylog.Entry{
+		{Fingerprint: "a", ConsumerID: "app-1", LatencyMs: 10, ExecutedAt: now},
+		{Fingerprint: "a", ConsumerID: "app-1", LatencyMs: 20, ExecutedAt: now},
+		{Fingerprint: "b", ConsumerID: "app-2", LatencyMs: 900, ExecutedAt: now},
+		{Fingerprint: "c", ConsumerID: "app-1", LatencyMs: 5, ExecutedAt: now.Add(-48 * time.Hour)},
+	}))
+	return store
+}
+
+func TestQueryLogHandler_GetTopQueries(t *testing.T) {
+	handler := NewQueryLogHandler(newTestQueryLog(t))
+
+	w := httptest.NewRecorder()
+	handler.GetTopQueries(w, httptest.NewRequest(http.MethodGet, "/admin/queries/top?orderBy=p95Latency", nil))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var summaries []*querylog.Summary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summaries))
+	require.Len(t, summaries, 2, "only the last day is aggregated by default")
+	assert.Equal(t, "b", summaries[0].Fingerprint)
+
+	w = httptest.NewRecorder()
+	handler.GetTopQueries(w, httptest.NewRequest(http.MethodGet, "/admin/queries/top?since=72h&consumerId=app-1", nil))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summaries))
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "a", summaries[0].Fingerprint)
+	assert.Equal(t, int64(2), summaries[0].Executions)
+}
+
+func TestQueryLogHandler_GetQueries(t *testing.T) {
+	handler := NewQueryLogHandler(newTestQueryLog(t))
+
+	since := time.Now().Add(-72 * time.Hour).Format(time.RFC3339)
+	w := httptest.NewRecorder()
+	handler.GetQueries(w, httptest.NewRequest(http.MethodGet, "/admin/queries?minLatencyMs=8&consumerId=app-1&since="+since, nil))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var entries []*querylog.Entry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(20), entries[0].LatencyMs)
+}
+
+func TestQueryLogHandler_InvalidRequests_ReturnBadRequest(t *testing.T) {
+	handler := NewQueryLogHandler(newTestQueryLog(t))
+
+	for _, target := range []string{
+		"/admin/queries/top?orderBy=name",
+		"/admin/queries/top?since=yesterday",
+		"/admin/queries/top?limit=0",
+		"/admin/queries?minLatencyMs=slow",
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if req.URL.Path == "/admin/queries/top" {
+			handler.GetTopQueries(w, req)
+		} else {
+			handler.GetQueries(w, req)
+		}
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+}
+
+func TestQueryLogHandler_Disabled_ReturnsServiceUnavailable(t *testing.T) {
+	handler := NewQueryLogHandler(nil)
+
+	w := httptest.NewRecorder()
+	handler.GetTopQueries(w, httptest.NewRequest(http.MethodGet, "/admin/queries/top", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
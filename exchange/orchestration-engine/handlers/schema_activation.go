@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/activation"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// SchemaActivationService defines the behavior SchemaActivationHandler depends on.
+type SchemaActivationService interface {
+	Schedule(ctx context.Context, version string, at time.Time, scheduledBy string) (*activation.Activation, error)
+	List(ctx context.Context, status string) ([]*activation.Activation, error)
+	Cancel(ctx context.Context, id int64) error
+}
+
+// SchemaActivationHandler handles HTTP requests for scheduling the activation of schema versions
+type SchemaActivationHandler struct {
+	activationService SchemaActivationService
+	activateNow       http.HandlerFunc
+}
+
+// NewSchemaActivationHandler creates a new schema activation handler. Activations without a time are
+// handled by activateNow; a nil service means activations cannot be scheduled.
+func NewSchemaActivationHandler(activationService SchemaActivationService, activateNow http.HandlerFunc) *SchemaActivationHandler {
+	return &SchemaActivationHandler{
+		activationService: activationService,
+		activateNow:       activateNow,
+	}
+}
+
+// ActivateSchema handles POST /sdl/versions/{version}/activate - activate a schema version now, or
+// schedule its activation with the at query parameter, an RFC 3339 time in the future
+func (h *SchemaActivationHandler) ActivateSchema(w http.ResponseWriter, r *http.Request) {
+	value := r.URL.Query().Get("at")
+	if value == "" {
+		h.activateNow(w, r)
+		return
+	}
+	if h.activationService == nil {
+		http.Error(w, "Schema management not available - database not connected", http.StatusServiceUnavailable)
+		return
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		http.Error(w, "at must be an RFC 3339 time", http.StatusBadRequest)
+		return
+	}
+
+	version := chi.URLParam(r, "version")
+	scheduled, err := h.activationService.Schedule(r.Context(), version, at, r.URL.Query().Get("scheduledBy"))
+	if errors.Is(err, activation.ErrInvalidActivation) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, activation.ErrVersionNotFound) {
+		http.Error(w, "Schema not found or cannot be activated", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to schedule schema activation", "error", err, "version", version)
+		http.Error(w, "Failed to schedule schema activation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(scheduled)
+}
+
+// GetActivations handles GET /sdl/activations - list the scheduled activations and their outcome,
+// optionally filtered by the status query parameter
+func (h *SchemaActivationHandler) GetActivations(w http.ResponseWriter, r *http.Request) {
+	if h.activationService == nil {
+		http.Error(w, "Schema management not available - database not connected", http.StatusServiceUnavailable)
+		return
+	}
+
+	activations, err := h.activationService.List(r.Context(), r.URL.Query().Get("status"))
+	if errors.Is(err, activation.ErrInvalidActivation) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get schema activations", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activations)
+}
+
+// CancelActivation handles DELETE /sdl/activations/{id} - cancel a scheduled activation
+func (h *SchemaActivationHandler) CancelActivation(w http.ResponseWriter, r *http.Request) {
+	if h.activationService == nil {
+		http.Error(w, "Schema management not available - database not connected", http.StatusServiceUnavailable)
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid schema activation ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.activationService.Cancel(r.Context(), id)
+	if errors.Is(err, activation.ErrNotFound) {
+		http.Error(w, "Scheduled schema activation not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to cancel schema activation", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/activation"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activationSchemas is a single known schema version
+type activationSchemas struct{}
+
+func (activationSchemas) VersionSDL(version string) (string, error) {
+	if version != "2.0.0" {
+		return "", fmt.Errorf("%w: %s", activation.ErrVersionNotFound, version)
+	}
+	return "type Query { name: String }", nil
+}
+
+func (activationSchemas) ActivateSchema(string) error { return nil }
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestSchemaActivationHandler_ScheduleListAndCancel(t *testing.T) {
+	scheduler := activation.NewScheduler(activation.NewMemoryStore(), activationSchemas{}, nil, time.Hour)
+	activatedNow := false
+	handler := NewSchemaActivationHandler(scheduler, func(w http.ResponseWriter, r *http.Request) {
+		activatedNow = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	at := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+	w := httptest.NewRecorder()
+	handler.ActivateSchema(w, withURLParam(httptest.NewRequest(http.MethodPost, "/sdl/versions/2.0.0/activate?at="+at, nil), "version", "2.0.0"))
+
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.False(t, activatedNow)
+	var scheduled activation.Activation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scheduled))
+	assert.Equal(t, "2.0.0", scheduled.Version)
+	assert.Equal(t, activation.StatusScheduled, scheduled.Status)
+
+	w = httptest.NewRecorder()
+	handler.GetActivations(w, httptest.NewRequest(http.MethodGet, "/sdl/activations?status=scheduled", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var activations []*activation.Activation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &activations))
+	require.Len(t, activations, 1)
+
+	id := strconv.FormatInt(scheduled.ID, 10)
+	w = httptest.NewRecorder()
+	handler.CancelActivation(w, withURLParam(httptest.NewRequest(http.MethodDelete, "/sdl/activations/"+id, nil), "id", id))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.CancelActivation(w, withURLParam(httptest.NewRequest(http.MethodDelete, "/sdl/activations/"+id, nil), "id", id))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Without a time the version is activated immediately
+	w = httptest.NewRecorder()
+	handler.ActivateSchema(w, withURLParam(httptest.NewRequest(http.MethodPost, "/sdl/versions/2.0.0/activate", nil), "version", "2.0.0"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, activatedNow)
+}
+
+func TestSchemaActivationHandler_InvalidRequests(t *testing.T) {
+	scheduler := activation.NewScheduler(activation.NewMemoryStore(), activationSchemas{}, nil, time.Hour)
+	handler := NewSchemaActivationHandler(scheduler, nil)
+	future := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+	past := url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339))
+
+	for _, tc := range []struct {
+		version string
+		at      string
+		status  int
+	}{
+		{"2.0.0", "tonight", http.StatusBadRequest},
+		{"2.0.0", past, http.StatusBadRequest},
+		{"3.0.0", future, http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/sdl/versions/"+tc.version+"/activate?at="+tc.at, nil)
+		handler.ActivateSchema(w, withURLParam(req, "version", tc.version))
+		assert.Equal(t, tc.status, w.Code, tc.at)
+	}
+
+	w := httptest.NewRecorder()
+	handler.GetActivations(w, httptest.NewRequest(http.MethodGet, "/sdl/activations?status=pending", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	NewSchemaActivationHandler(nil, nil).ActivateSchema(w, httptest.NewRequest(http.MethodPost, "/sdl/versions/2.0.0/activate?at="+future, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
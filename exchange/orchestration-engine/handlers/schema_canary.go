@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/activation"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/canary"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+// SchemaCanaryService defines the behavior SchemaCanaryHandler depends on.
+type SchemaCanaryService interface {
+	Start(ctx context.Context, canary *canary.Canary) (*canary.Canary, error)
+	Stop(ctx context.Context) (*canary.Canary, error)
+	Status(ctx context.Context) (*canary.Status, error)
+}
+
+// SchemaCanaryHandler handles HTTP requests for routing traffic to a candidate schema version
+type SchemaCanaryHandler struct {
+	canaryService SchemaCanaryService
+}
+
+// NewSchemaCanaryHandler creates a new schema canary handler; a nil service means canaries cannot be run
+func NewSchemaCanaryHandler(canaryService SchemaCanaryService) *SchemaCanaryHandler {
+	return &SchemaCanaryHandler{
+		canaryService: canaryService,
+	}
+}
+
+// StartSchemaCanaryRequest represents a request to route traffic to a candidate schema version
+type StartSchemaCanaryRequest struct {
+	Version     string   `json:"version"`
+	Percentage  float64  `json:"percentage"`
+	ConsumerIDs []string `json:"consumerIds"`
+	// ErrorThreshold defaults to 0.05 and MinRequests to 100
+	ErrorThreshold float64 `json:"errorThreshold"`
+	MinRequests    int     `json:"minRequests"`
+	StartedBy      string  `json:"startedBy"`
+}
+
+// GetCanary handles GET /sdl/canary - get the latest canary and the error rates of the active and
+// candidate versions measured by this replica
+func (h *SchemaCanaryHandler) GetCanary(w http.ResponseWriter, r *http.Request) {
+	if h.canaryService == nil {
+		http.Error(w, "Schema management not available - database not connected", http.StatusServiceUnavailable)
+		return
+	}
+
+	status, err := h.canaryService.Status(r.Context())
+	if err != nil {
+		logger.Log.Error("Failed to get schema canary", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// StartCanary handles POST /sdl/canary - route a percentage of the traffic, and all the traffic of
+// some consumers, to a candidate schema version
+func (h *SchemaCanaryHandler) StartCanary(w http.ResponseWriter, r *http.Request) {
+	if h.canaryService == nil {
+		http.Error(w, "Schema management not available - database not connected", http.StatusServiceUnavailable)
+		return
+	}
+	var req StartSchemaCanaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	started, err := h.canaryService.Start(r.Context(), &canary.Canary{
+		Version:        req.Version,
+		Percentage:     req.Percentage,
+		ConsumerIDs:    req.ConsumerIDs,
+		ErrorThreshold: req.ErrorThreshold,
+		MinRequests:    req.MinRequests,
+		StartedBy:      req.StartedBy,
+	})
+	if errors.Is(err, canary.ErrInvalidCanary) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, activation.ErrVersionNotFound) {
+		http.Error(w, "Schema not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, canary.ErrAlreadyRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to start schema canary", "error", err, "version", req.Version)
+		http.Error(w, "Failed to start schema canary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(started)
+}
+
+// StopCanary handles DELETE /sdl/canary - stop the running canary, routing all traffic back to the
+// active version
+func (h *SchemaCanaryHandler) StopCanary(w http.ResponseWriter, r *http.Request) {
+	if h.canaryService == nil {
+		http.Error(w, "Schema management not available - database not connected", http.StatusServiceUnavailable)
+		return
+	}
+
+	stopped, err := h.canaryService.Stop(r.Context())
+	if errors.Is(err, canary.ErrNotFound) {
+		http.Error(w, "No schema canary is running", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to stop schema canary", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stopped)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/canary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaCanaryHandler_StartGetAndStop(t *testing.T) {
+	router := canary.NewRouter(canary.NewMemoryStore(), activationSchemas{}, time.Hour)
+	handler := NewSchemaCanaryHandler(router)
+
+	body, _ := json.Marshal(StartSchemaCanaryRequest{Version: "2.0.0", Percentage: 10, ConsumerIDs: []string{"app-beta"}})
+	w := httptest.NewRecorder()
+	handler.StartCanary(w, httptest.NewRequest(http.MethodPost, "/sdl/canary", bytes.NewBuffer(body)))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var started canary.Canary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	assert.Equal(t, "2.0.0", started.Version)
+	assert.Equal(t, canary.StatusRunning, started.Status)
+	assert.Equal(t, "2.0.0", router.Route("app-beta"))
+
+	w = httptest.NewRecorder()
+	handler.StartCanary(w, httptest.NewRequest(http.MethodPost, "/sdl/canary", bytes.NewBuffer(body)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	router.Record("2.0.0", true)
+	w = httptest.NewRecorder()
+	handler.GetCanary(w, httptest.NewRequest(http.MethodGet, "/sdl/canary", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var status canary.Status
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, started.ID, status.Canary.ID)
+	assert.Equal(t, int64(1), status.Candidate.Errors)
+
+	w = httptest.NewRecorder()
+	handler.StopCanary(w, httptest.NewRequest(http.MethodDelete, "/sdl/canary", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var stopped canary.Canary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stopped))
+	assert.Equal(t, canary.StatusStopped, stopped.Status)
+
+	w = httptest.NewRecorder()
+	handler.StopCanary(w, httptest.NewRequest(http.MethodDelete, "/sdl/canary", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSchemaCanaryHandler_InvalidRequests(t *testing.T) {
+	handler := NewSchemaCanaryHandler(canary.NewRouter(canary.NewMemoryStore(), activationSchemas{}, time.Hour))
+
+	tests := map[string]struct {
+		body string
+		code int
+	}{
+		"invalid JSON":    {body: "{", code: http.StatusBadRequest},
+		"nothing routed":  {body: `{"version": "2.0.0"}`, code: http.StatusBadRequest},
+		"unknown version": {body: `{"version": "9.9.9", "percentage": 5}`, code: http.StatusNotFound},
+		"bad threshold":   {body: `{"version": "2.0.0", "percentage": 5, "errorThreshold": 2}`, code: http.StatusBadRequest},
+		"bad percentage":  {body: `{"version": "2.0.0", "percentage": 150}`, code: http.StatusBadRequest},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.StartCanary(w, httptest.NewRequest(http.MethodPost, "/sdl/canary", bytes.NewBufferString(test.body)))
+			assert.Equal(t, test.code, w.Code, w.Body.String())
+		})
+	}
+
+	w := httptest.NewRecorder()
+	NewSchemaCanaryHandler(nil).GetCanary(w, httptest.NewRequest(http.MethodGet, "/sdl/canary", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -6,6 +6,8 @@ const (
 	CodePDPUnavailable = "PDP_UNAVAILABLE"
 	CodePDPError       = "PDP_ERROR"
 	CodePDPNoResponse  = "PDP_NO_RESPONSE"
+	// CodeSchemaDeprecated is the code of the warning about fields of deprecated or sunset schemas
+	CodeSchemaDeprecated = "SCHEMA_DEPRECATED"
 )
 
 // CE-related
@@ -21,12 +23,24 @@ const (
 // OE-related
 const (
 	CodeMissingEntityIdentifier = "MISSING_IDENTIFIER"
+	CodeQuotaExceeded           = "QUOTA_EXCEEDED"
+	CodeQuotaDisabled           = "QUOTA_DISABLED"
+	// CodeProviderMaintenance is the code of the errors of fields whose provider is in a maintenance window
+	CodeProviderMaintenance = "PROVIDER_MAINTENANCE"
+	// CodeProviderNotEnabled is the code of the errors of fields whose provider's feature flag is off for the consumer
+	CodeProviderNotEnabled = "PROVIDER_NOT_ENABLED"
+	// CodeDeferNotEnabled is the code of the error of queries using @defer while defer support is off for the consumer
+	CodeDeferNotEnabled = "DEFER_NOT_ENABLED"
 )
 
 // Auth-related
 const (
 	CodeUnauthorized = "UNAUTHORIZED"
 	CodeForbidden    = "FORBIDDEN"
+	// Codes of the tokens rejected because of their consumer application
+	CodeApplicationSuspended     = "APPLICATION_SUSPENDED"
+	CodeApplicationExpired       = "APPLICATION_EXPIRED"
+	CodeApplicationNotRegistered = "APPLICATION_NOT_REGISTERED"
 )
 
 // Generic
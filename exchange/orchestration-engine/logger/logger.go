@@ -3,6 +3,8 @@ package logger
 import (
 	"log/slog"
 	"os"
+
+	"github.com/gov-dx-sandbox/shared/requestid"
 )
 
 // Log is the global logger instance.
@@ -11,7 +13,8 @@ var Log *slog.Logger
 // Init Initializes the logger with the desired settings.
 func Init() {
 	// Example: JSON logs with Info level
-	handler := slog.NewTextHandler(os.Stderr, nil)
+	// Records logged with a request's context include its request ID
+	handler := requestid.NewLogHandler(slog.NewTextHandler(os.Stderr, nil))
 	Log = slog.New(handler)
 
 	Log.Info("Logger initialized")
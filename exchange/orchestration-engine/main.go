@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/federator"
@@ -52,4 +53,11 @@ func main() {
 	// Run server with graceful shutdown support
 	// Server will stop when ctx is cancelled (on SIGINT/SIGTERM)
 	server.RunServer(ctx, federationObject)
+
+	// Deliver buffered audit events; anything left is spooled to disk if AUDIT_SPOOL_DIR is set
+	auditCtx, auditCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer auditCancel()
+	if err := auditClient.Close(auditCtx); err != nil {
+		logger.Log.Warn("Timed out delivering buffered audit events", "error", err)
+	}
 }
@@ -0,0 +1,87 @@
+package maintenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// DefaultCacheEntries bounds the number of responses a cache keeps
+const DefaultCacheEntries = 10000
+
+// ResponseCache keeps the body of the last successful response of each provider query, to be
+// served while the provider is in a maintenance window that allows it. Queries are keyed by a hash,
+// so the arguments they carry are not kept in the cache keys.
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	body     []byte
+	cachedAt time.Time
+}
+
+// NewResponseCache creates a cache whose responses expire after ttl; non-positive maxEntries use
+// DefaultCacheEntries
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &ResponseCache{ttl: ttl, maxEntries: maxEntries, entries: make(map[string]cachedResponse)}
+}
+
+// Put keeps the response body of a provider query
+func (c *ResponseCache) Put(providerKey string, query, body []byte) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := cacheKey(providerKey, query)
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cachedResponse{body: body, cachedAt: now}
+}
+
+// Get returns the cached response body of a provider query and when it was cached, unless it expired
+func (c *ResponseCache) Get(providerKey string, query []byte) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := cacheKey(providerKey, query)
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	if time.Since(entry.cachedAt) >= c.ttl {
+		delete(c.entries, key)
+		return nil, time.Time{}, false
+	}
+	return entry.body, entry.cachedAt, true
+}
+
+// evict removes the expired responses, or the oldest one if none has expired
+func (c *ResponseCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.Sub(entry.cachedAt) >= c.ttl {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.cachedAt.Before(oldest) {
+			oldestKey, oldest = key, entry.cachedAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+}
+
+func cacheKey(providerKey string, query []byte) string {
+	sum := sha256.Sum256(query)
+	return providerKey + ":" + hex.EncodeToString(sum[:])
+}
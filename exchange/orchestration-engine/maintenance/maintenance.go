@@ -0,0 +1,109 @@
+// Package maintenance keeps the maintenance windows declared for providers. While a provider is in a
+// window the federator does not call it: the affected fields fail with a PROVIDER_MAINTENANCE error,
+// or are served from cached responses if the window allows it, and the provider's failures no
+// longer make the engine's health check fail.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a maintenance window does not exist
+	ErrNotFound = errors.New("maintenance window not found")
+	// ErrInvalidWindow is returned when a maintenance window is missing required fields or ends
+	// before it starts
+	ErrInvalidWindow = errors.New("invalid maintenance window")
+)
+
+// Window is a period during which a provider is not called
+type Window struct {
+	ID          int64     `json:"id"`
+	ProviderKey string    `json:"providerKey"`
+	StartsAt    time.Time `json:"startsAt"`
+	EndsAt      time.Time `json:"endsAt"`
+	Reason      string    `json:"reason,omitempty"`
+	// ServeCached serves the provider's last responses while the window is active, when the engine
+	// caches responses
+	ServeCached bool      `json:"serveCached"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ActiveAt reports whether the window covers the given time
+func (w *Window) ActiveAt(at time.Time) bool {
+	return !at.Before(w.StartsAt) && at.Before(w.EndsAt)
+}
+
+// Store persists maintenance windows
+type Store interface {
+	// List returns the windows ending after the given time, or all windows for the zero time
+	List(ctx context.Context, endingAfter time.Time) ([]*Window, error)
+	Create(ctx context.Context, window *Window) (*Window, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// MemoryStore keeps maintenance windows in memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	nextID  int64
+	windows map[int64]*Window
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: make(map[int64]*Window)}
+}
+
+// List returns the windows ending after the given time, ordered by start
+func (s *MemoryStore) List(_ context.Context, endingAfter time.Time) ([]*Window, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	windows := make([]*Window, 0, len(s.windows))
+	for _, window := range s.windows {
+		if window.EndsAt.After(endingAfter) {
+			copied := *window
+			windows = append(windows, &copied)
+		}
+	}
+	sortWindows(windows)
+	return windows, nil
+}
+
+// Create stores a new window
+func (s *MemoryStore) Create(_ context.Context, window *Window) (*Window, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	stored := *window
+	stored.ID = s.nextID
+	stored.CreatedAt = time.Now().UTC()
+	s.windows[stored.ID] = &stored
+	copied := stored
+	return &copied, nil
+}
+
+// Delete removes a window
+func (s *MemoryStore) Delete(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.windows[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.windows, id)
+	return nil
+}
+
+// sortWindows orders windows by start, then by ID
+func sortWindows(windows []*Window) {
+	sort.Slice(windows, func(i, j int) bool {
+		a, b := windows[i], windows[j]
+		if !a.StartsAt.Equal(b.StartsAt) {
+			return a.StartsAt.Before(b.StartsAt)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,178 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+const (
+	// DefaultRefreshInterval is how long the windows are used before they are reloaded from the
+	// store, so that windows declared through other replicas take effect
+	DefaultRefreshInterval = time.Minute
+	// refreshTimeout bounds a reload of the windows
+	refreshTimeout = 10 * time.Second
+)
+
+// Schedule answers which providers are in maintenance with the windows of a store. The windows that
+// have not ended are cached in memory, so checking a provider does not query the store.
+type Schedule struct {
+	store           Store
+	refreshInterval time.Duration
+
+	mu         sync.RWMutex
+	windows    []*Window
+	loadedAt   time.Time
+	refreshing bool
+}
+
+// NewSchedule creates a schedule for the windows of store; non-positive intervals use
+// DefaultRefreshInterval. The windows are loaded on the first call to Active, or by Refresh.
+func NewSchedule(store Store, refreshInterval time.Duration) *Schedule {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &Schedule{store: store, refreshInterval: refreshInterval}
+}
+
+// Refresh reloads the windows that have not ended from the store
+func (s *Schedule) Refresh(ctx context.Context) error {
+	windows, err := s.store.List(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to load maintenance windows: %w", err)
+	}
+
+	s.mu.Lock()
+	s.windows = windows
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// Active returns the window a provider is in at the given time, or nil. Of overlapping windows, the
+// one ending last is returned. Stale windows are reloaded in the background, so a request is never
+// held up by the store.
+func (s *Schedule) Active(providerKey string, at time.Time) *Window {
+	s.mu.RLock()
+	windows := s.windows
+	stale := time.Since(s.loadedAt) >= s.refreshInterval
+	s.mu.RUnlock()
+	if stale {
+		s.refreshInBackground()
+	}
+
+	var active *Window
+	for _, window := range windows {
+		if window.ProviderKey == providerKey && window.ActiveAt(at) && (active == nil || window.EndsAt.After(active.EndsAt)) {
+			active = window
+		}
+	}
+	return active
+}
+
+// refreshInBackground starts a reload of the windows unless one is already running
+func (s *Schedule) refreshInBackground() {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		if err := s.Refresh(ctx); err != nil {
+			logger.Log.Warn("Failed to refresh maintenance windows, using the previous windows", "error", err)
+			// Wait for the next interval before retrying
+			s.mu.Lock()
+			s.loadedAt = time.Now()
+			s.mu.Unlock()
+		}
+		s.mu.Lock()
+		s.refreshing = false
+		s.mu.Unlock()
+	}()
+}
+
+// List returns the windows of the store that have not ended, or all of them with includeEnded,
+// optionally only those of a provider
+func (s *Schedule) List(ctx context.Context, providerKey string, includeEnded bool) ([]*Window, error) {
+	var endingAfter time.Time
+	if !includeEnded {
+		endingAfter = time.Now()
+	}
+	windows, err := s.store.List(ctx, endingAfter)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*Window, 0, len(windows))
+	for _, window := range windows {
+		if providerKey == "" || window.ProviderKey == providerKey {
+			filtered = append(filtered, window)
+		}
+	}
+	return filtered, nil
+}
+
+// Create declares a maintenance window; windows without a start begin immediately. It takes effect
+// immediately on this replica, and on the others once they refresh their windows.
+func (s *Schedule) Create(ctx context.Context, window *Window) (*Window, error) {
+	window.ProviderKey = strings.TrimSpace(window.ProviderKey)
+	if window.ProviderKey == "" {
+		return nil, fmt.Errorf("%w: providerKey is required", ErrInvalidWindow)
+	}
+	now := time.Now()
+	if window.StartsAt.IsZero() {
+		window.StartsAt = now
+	}
+	if !window.EndsAt.After(window.StartsAt) {
+		return nil, fmt.Errorf("%w: endsAt must be after startsAt", ErrInvalidWindow)
+	}
+	if !window.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: endsAt must be in the future", ErrInvalidWindow)
+	}
+	window.StartsAt = window.StartsAt.UTC()
+	window.EndsAt = window.EndsAt.UTC()
+
+	stored, err := s.store.Create(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Provider maintenance window declared", "id", stored.ID, "providerKey", stored.ProviderKey,
+		"startsAt", stored.StartsAt, "endsAt", stored.EndsAt, "serveCached", stored.ServeCached)
+
+	s.update(func(windows []*Window) []*Window { return append(windows, stored) })
+	return stored, nil
+}
+
+// Delete removes a maintenance window, ending it early if it is active
+func (s *Schedule) Delete(ctx context.Context, id int64) error {
+	if err := s.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	logger.Log.Info("Provider maintenance window removed", "id", id)
+
+	s.update(func(windows []*Window) []*Window {
+		kept := windows[:0]
+		for _, window := range windows {
+			if window.ID != id {
+				kept = append(kept, window)
+			}
+		}
+		return kept
+	})
+	return nil
+}
+
+// update changes the cached windows, which are copied as Active may still be reading them
+func (s *Schedule) update(change func([]*Window) []*Window) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.windows = change(append([]*Window(nil), s.windows...))
+}
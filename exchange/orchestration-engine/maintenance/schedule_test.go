@@ -0,0 +1,129 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Initialize logger for tests
+	logger.Init()
+}
+
+func TestSchedule_Active(t *testing.T) {
+	ctx := context.Background()
+	schedule := NewSchedule(NewMemoryStore(), time.Hour)
+	require.NoError(t, schedule.Refresh(ctx))
+	now := time.Now()
+
+	short, err := schedule.Create(ctx, &Window{ProviderKey: "drp", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	long, err := schedule.Create(ctx, &Window{ProviderKey: "drp", StartsAt: now.Add(-time.Minute), EndsAt: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	_, err = schedule.Create(ctx, &Window{ProviderKey: "rgd", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+
+	assert.Equal(t, long.ID, schedule.Active("drp", time.Now()).ID, "the window ending last is returned")
+	assert.Nil(t, schedule.Active("rgd", now), "windows apply from their start")
+	assert.NotNil(t, schedule.Active("rgd", now.Add(90*time.Minute)))
+	assert.Nil(t, schedule.Active("dmt", now))
+
+	require.NoError(t, schedule.Delete(ctx, long.ID))
+	assert.Equal(t, short.ID, schedule.Active("drp", time.Now()).ID, "windows without a start begin immediately")
+	require.NoError(t, schedule.Delete(ctx, short.ID))
+	assert.Nil(t, schedule.Active("drp", time.Now()))
+
+	err = schedule.Delete(ctx, short.ID)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestSchedule_OtherReplicasSeeWindowsOnRefresh(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	schedule := NewSchedule(store, time.Hour)
+	replica := NewSchedule(store, time.Hour)
+	require.NoError(t, replica.Refresh(ctx))
+
+	_, err := schedule.Create(ctx, &Window{ProviderKey: "drp", EndsAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Nil(t, replica.Active("drp", time.Now()), "stale until the next refresh")
+
+	require.NoError(t, replica.Refresh(ctx))
+	assert.NotNil(t, replica.Active("drp", time.Now()))
+}
+
+func TestSchedule_List(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	schedule := NewSchedule(store, time.Hour)
+	now := time.Now()
+
+	_, err := schedule.Create(ctx, &Window{ProviderKey: "drp", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = schedule.Create(ctx, &Window{ProviderKey: "rgd", EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	// An ended window, stored directly as the schedule only accepts windows that have not ended
+	_, err = store.Create(ctx, &Window{ProviderKey: "drp", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)})
+	require.NoError(t, err)
+
+	windows, err := schedule.List(ctx, "drp", false)
+	require.NoError(t, err)
+	assert.Len(t, windows, 1)
+
+	windows, err = schedule.List(ctx, "drp", true)
+	require.NoError(t, err)
+	assert.Len(t, windows, 2)
+
+	windows, err = schedule.List(ctx, "", false)
+	require.NoError(t, err)
+	assert.Len(t, windows, 2)
+}
+
+func TestSchedule_CreateValidatesWindows(t *testing.T) {
+	schedule := NewSchedule(NewMemoryStore(), time.Hour)
+	now := time.Now()
+
+	for name, window := range map[string]*Window{
+		"missing provider":  {ProviderKey: " ", EndsAt: now.Add(time.Hour)},
+		"missing end":       {ProviderKey: "drp"},
+		"ends before start": {ProviderKey: "drp", StartsAt: now.Add(2 * time.Hour), EndsAt: now.Add(time.Hour)},
+		"ends in the past":  {ProviderKey: "drp", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := schedule.Create(context.Background(), window)
+			assert.True(t, errors.Is(err, ErrInvalidWindow), "%v", err)
+		})
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	cache := NewResponseCache(time.Hour, 2)
+
+	cache.Put("drp", []byte(`{"query":"a"}`), []byte(`{"data":{"a":1}}`))
+	body, cachedAt, ok := cache.Get("drp", []byte(`{"query":"a"}`))
+	require.True(t, ok)
+	assert.JSONEq(t, `{"data":{"a":1}}`, string(body))
+	assert.False(t, cachedAt.IsZero())
+
+	_, _, ok = cache.Get("rgd", []byte(`{"query":"a"}`))
+	assert.False(t, ok, "responses are cached per provider")
+
+	cache.Put("drp", []byte(`{"query":"b"}`), []byte(`{}`))
+	cache.Put("drp", []byte(`{"query":"c"}`), []byte(`{}`))
+	_, _, ok = cache.Get("drp", []byte(`{"query":"a"}`))
+	assert.False(t, ok, "the oldest response is evicted")
+	_, _, ok = cache.Get("drp", []byte(`{"query":"c"}`))
+	assert.True(t, ok)
+
+	expired := NewResponseCache(time.Nanosecond, 0)
+	expired.Put("drp", []byte(`{"query":"a"}`), []byte(`{}`))
+	time.Sleep(time.Millisecond)
+	_, _, ok = expired.Get("drp", []byte(`{"query":"a"}`))
+	assert.False(t, ok)
+}
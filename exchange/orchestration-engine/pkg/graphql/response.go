@@ -5,6 +5,8 @@ import "fmt"
 type Response struct {
 	Data   map[string]interface{} `json:"data"`
 	Errors []interface{}          `json:"errors,omitempty"`
+	// Extensions carries response metadata such as deprecation warnings
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
 type JSONError struct {
@@ -0,0 +1,385 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// pipeline is a parsed expression: a term whose value is passed through the stages in order
+type pipeline struct {
+	head   term
+	stages []*stage
+}
+
+// term is a value read from the data: a path, a literal or a function of other terms
+type term interface {
+	eval(data map[string]interface{}, t *Transformer) (interface{}, error)
+}
+
+type pathTerm []string
+
+type literalTerm struct {
+	value interface{}
+}
+
+type callTerm struct {
+	name string
+	args []term
+}
+
+// stage is a function applied to the piped value; its arguments are literals
+type stage struct {
+	name string
+	args []interface{}
+}
+
+// pipeFunc transforms the piped value. Null values pass through every function but default.
+type pipeFunc struct {
+	// args are the kinds of the literal arguments, "string" or "any"
+	args []string
+	fn   func(value interface{}, args []interface{}, t *Transformer) (interface{}, error)
+}
+
+var pipeFuncs = map[string]pipeFunc{
+	"upper": {fn: stringFunc(strings.ToUpper)},
+	"lower": {fn: stringFunc(strings.ToLower)},
+	"trim":  {fn: stringFunc(strings.TrimSpace)},
+	"date": {args: []string{"string", "string"}, fn: func(value interface{}, args []interface{}, t *Transformer) (interface{}, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("date: expected a string, got %T", value)
+		}
+		parsed, err := time.Parse(args[0].(string), s)
+		if err != nil {
+			return nil, fmt.Errorf("date: %w", err)
+		}
+		return parsed.Format(args[1].(string)), nil
+	}},
+	// code translates a code with a code list; unknown codes are kept
+	"code": {args: []string{"string"}, fn: func(value interface{}, args []interface{}, t *Transformer) (interface{}, error) {
+		if mapped, ok := t.codeLists[args[0].(string)][toString(value)]; ok {
+			return mapped, nil
+		}
+		return value, nil
+	}},
+	"default": {args: []string{"any"}},
+	"number": {fn: func(value interface{}, args []interface{}, t *Transformer) (interface{}, error) {
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case json.Number:
+			return v.Float64()
+		case string:
+			number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("number: invalid number %q", v)
+			}
+			return number, nil
+		}
+		return nil, fmt.Errorf("number: expected a string or number, got %T", value)
+	}},
+	"string": {fn: func(value interface{}, args []interface{}, t *Transformer) (interface{}, error) {
+		return toString(value), nil
+	}},
+	"split": {args: []string{"string"}, fn: func(value interface{}, args []interface{}, t *Transformer) (interface{}, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("split: expected a string, got %T", value)
+		}
+		parts := strings.Split(s, args[0].(string))
+		items := make([]interface{}, len(parts))
+		for i, part := range parts {
+			items[i] = part
+		}
+		return items, nil
+	}},
+	"join": {args: []string{"string"}, fn: func(value interface{}, args []interface{}, t *Transformer) (interface{}, error) {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("join: expected an array, got %T", value)
+		}
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			if item != nil {
+				parts = append(parts, toString(item))
+			}
+		}
+		return strings.Join(parts, args[0].(string)), nil
+	}},
+}
+
+// termFuncs are the functions that can start an expression; their arguments are terms
+var termFuncs = map[string]func(args []interface{}) interface{}{
+	// concat joins the string forms of its arguments, skipping null values
+	"concat": func(args []interface{}) interface{} {
+		var b strings.Builder
+		for _, arg := range args {
+			if arg != nil {
+				b.WriteString(toString(arg))
+			}
+		}
+		return b.String()
+	},
+	// coalesce returns its first argument that is not null
+	"coalesce": func(args []interface{}) interface{} {
+		for _, arg := range args {
+			if arg != nil {
+				return arg
+			}
+		}
+		return nil
+	},
+}
+
+func stringFunc(f func(string) string) func(interface{}, []interface{}, *Transformer) (interface{}, error) {
+	return func(value interface{}, args []interface{}, t *Transformer) (interface{}, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %T", value)
+		}
+		return f(s), nil
+	}
+}
+
+func toString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func (p *pipeline) eval(data map[string]interface{}, t *Transformer) (interface{}, error) {
+	value, err := p.head.eval(data, t)
+	if err != nil {
+		return nil, err
+	}
+	for _, stage := range p.stages {
+		if stage.name == "default" {
+			if value == nil {
+				value = stage.args[0]
+			}
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if value, err = pipeFuncs[stage.name].fn(value, stage.args, t); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func (p pathTerm) eval(data map[string]interface{}, t *Transformer) (interface{}, error) {
+	return getPath(data, p), nil
+}
+
+func (l literalTerm) eval(data map[string]interface{}, t *Transformer) (interface{}, error) {
+	return l.value, nil
+}
+
+func (c callTerm) eval(data map[string]interface{}, t *Transformer) (interface{}, error) {
+	args := make([]interface{}, len(c.args))
+	for i, arg := range c.args {
+		value, err := arg.eval(data, t)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return termFuncs[c.name](args), nil
+}
+
+// parser parses expressions with the grammar
+//
+//	pipeline = term { "|" name [ "(" literal { "," literal } ")" ] }
+//	term     = path | literal | name "(" [ term { "," term } ] ")"
+type parser struct {
+	input string
+	pos   int
+}
+
+func parse(input string) (*pipeline, error) {
+	p := &parser{input: input}
+	head, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	expr := &pipeline{head: head}
+	for p.skip('|') {
+		stage, err := p.stage()
+		if err != nil {
+			return nil, err
+		}
+		expr.stages = append(expr.stages, stage)
+	}
+	if p.peek() != 0 {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+	return expr, nil
+}
+
+func (p *parser) term() (term, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		s, err := p.str()
+		return literalTerm{value: s}, err
+	case c == '-' || unicode.IsDigit(rune(c)):
+		n, err := p.number()
+		return literalTerm{value: n}, err
+	}
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if !p.skip('(') {
+		return pathTerm(splitPath(name)), nil
+	}
+	if _, ok := termFuncs[name]; !ok {
+		return nil, p.errorf("unknown function %q", name)
+	}
+	call := callTerm{name: name}
+	if p.skip(')') {
+		return call, nil
+	}
+	for {
+		arg, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if p.skip(')') {
+			return call, nil
+		}
+		if !p.skip(',') {
+			return nil, p.errorf("expected \",\" or \")\"")
+		}
+	}
+}
+
+func (p *parser) stage() (*stage, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := pipeFuncs[name]
+	if !ok {
+		return nil, p.errorf("unknown function %q", name)
+	}
+
+	s := &stage{name: name}
+	if p.skip('(') && !p.skip(')') {
+		for {
+			arg, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			s.args = append(s.args, arg)
+			if p.skip(')') {
+				break
+			}
+			if !p.skip(',') {
+				return nil, p.errorf("expected \",\" or \")\"")
+			}
+		}
+	}
+
+	if len(s.args) != len(fn.args) {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", name, len(fn.args), len(s.args))
+	}
+	for i, kind := range fn.args {
+		if _, ok := s.args[i].(string); kind == "string" && !ok {
+			return nil, fmt.Errorf("argument %d of %s must be a string", i+1, name)
+		}
+	}
+	return s, nil
+}
+
+func (p *parser) literal() (interface{}, error) {
+	if p.peek() == '"' {
+		return p.str()
+	}
+	return p.number()
+}
+
+func (p *parser) str() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos++; p.pos < len(p.input); p.pos++ {
+		switch p.input[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			return strconv.Unquote(p.input[start:p.pos])
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) number() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && strings.ContainsRune("-+.0123456789eE", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", p.input[start:p.pos])
+	}
+	return n, nil
+}
+
+// ident reads a name or dot-separated path
+func (p *parser) ident() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := rune(p.input[p.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '.' {
+			break
+		}
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") || strings.Contains(name, "..") {
+		return "", p.errorf("expected a name or path")
+	}
+	return name, nil
+}
+
+// peek returns the next non-space character, or 0 at the end of the input
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// skip consumes c if it is the next non-space character
+func (p *parser) skip(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid expression at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
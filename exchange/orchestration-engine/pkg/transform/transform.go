@@ -0,0 +1,147 @@
+// Package transform adapts provider responses to the unified schema with declarative mapping
+// expressions, so that providers whose formats or structures differ can be integrated through
+// configuration.
+//
+// An expression reads a value and pipes it through functions:
+//
+//	dateOfBirth | date("02/01/2006", "2006-01-02")
+//	gender | code("gender") | default("UNKNOWN")
+//	concat(firstName, " ", lastName) | trim
+//
+// The first term is a dot-separated path into the provider response (or the array element, for
+// mappings with Each), a string or number literal, or one of the functions concat and coalesce.
+// Missing paths evaluate to null.
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Config is the mapping configuration of a provider
+type Config struct {
+	// Fields are applied in order, so a mapping can read the result of an earlier one
+	Fields []*FieldMapping `json:"fields"`
+	// CodeLists translate provider codes to the codes of the unified schema, by code list name
+	CodeLists map[string]map[string]string `json:"codeLists,omitempty"`
+}
+
+// FieldMapping sets a field of the provider response to the value of an expression
+type FieldMapping struct {
+	// Each is the path of an array whose elements are mapped one by one; Field and the paths of
+	// Expr are then relative to each element
+	Each string `json:"each,omitempty"`
+	// Field is the path of the field that is set, as used in the providerField of @sourceInfo
+	Field string `json:"field"`
+	// Expr computes the value of the field
+	Expr string `json:"expr"`
+}
+
+// Transformer applies the compiled mappings of a provider
+type Transformer struct {
+	mappings  []*compiledMapping
+	codeLists map[string]map[string]string
+}
+
+type compiledMapping struct {
+	each  []string
+	field []string
+	expr  *pipeline
+	// source is the mapping as configured, for error messages
+	source string
+}
+
+// Compile parses the expressions of a provider's mapping configuration
+func Compile(config *Config) (*Transformer, error) {
+	t := &Transformer{codeLists: config.CodeLists}
+	for i, mapping := range config.Fields {
+		if mapping == nil || mapping.Field == "" || mapping.Expr == "" {
+			return nil, fmt.Errorf("mapping %d: field and expr are required", i)
+		}
+		expr, err := parse(mapping.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %d (%s): %w", i, mapping.Field, err)
+		}
+		for _, stage := range expr.stages {
+			if stage.name != "code" {
+				continue
+			}
+			if _, ok := config.CodeLists[stage.args[0].(string)]; !ok {
+				return nil, fmt.Errorf("mapping %d (%s): unknown code list %q", i, mapping.Field, stage.args[0])
+			}
+		}
+
+		compiled := &compiledMapping{field: splitPath(mapping.Field), expr: expr, source: mapping.Field}
+		if mapping.Each != "" {
+			compiled.each = splitPath(mapping.Each)
+			compiled.source = mapping.Each + "[]." + mapping.Field
+		}
+		t.mappings = append(t.mappings, compiled)
+	}
+	return t, nil
+}
+
+// Apply rewrites the provider response data in place. A mapping that fails leaves its field as it
+// was; the failures are returned together once all mappings have been applied.
+func (t *Transformer) Apply(data map[string]interface{}) error {
+	var errs []error
+	for _, mapping := range t.mappings {
+		if mapping.each == nil {
+			if err := t.applyTo(data, mapping); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", mapping.source, err))
+			}
+			continue
+		}
+
+		items, ok := getPath(data, mapping.each).([]interface{})
+		if !ok {
+			continue
+		}
+		for i, item := range items {
+			element, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := t.applyTo(element, mapping); err != nil {
+				errs = append(errs, fmt.Errorf("%s (element %d): %w", mapping.source, i, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyTo sets the mapping's field of data. Fields are only added to objects the provider
+// returned, so a missing parent object is not created.
+func (t *Transformer) applyTo(data map[string]interface{}, mapping *compiledMapping) error {
+	parent, ok := getPath(data, mapping.field[:len(mapping.field)-1]).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	value, err := mapping.expr.eval(data, t)
+	if err != nil {
+		return err
+	}
+	key := mapping.field[len(mapping.field)-1]
+	if _, exists := parent[key]; !exists && value == nil {
+		return nil
+	}
+	parent[key] = value
+	return nil
+}
+
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// getPath returns the value at path, or nil if it does not exist
+func getPath(data interface{}, path []string) interface{} {
+	for _, key := range path {
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		data = object[key]
+	}
+	return data
+}
@@ -0,0 +1,125 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func apply(t *testing.T, config *Config, data map[string]interface{}) error {
+	t.Helper()
+	transformer, err := Compile(config)
+	require.NoError(t, err)
+	return transformer.Apply(data)
+}
+
+func TestApply_Functions(t *testing.T) {
+	data := map[string]interface{}{
+		"person": map[string]interface{}{
+			"firstName":   " Nimal ",
+			"lastName":    "Perera",
+			"dateOfBirth": "24/03/1990",
+			"gender":      "M",
+			"height":      "172.5",
+			"age":         float64(34),
+			"phones":      "0771234567,0112345678",
+			"nickname":    nil,
+		},
+	}
+	err := apply(t, &Config{
+		Fields: []*FieldMapping{
+			{Field: "person.firstName", Expr: "person.firstName | trim | upper"},
+			{Field: "person.fullName", Expr: `concat(person.firstName, " ", person.lastName)`},
+			{Field: "person.dateOfBirth", Expr: `person.dateOfBirth | date("02/01/2006", "2006-01-02")`},
+			{Field: "person.gender", Expr: `person.gender | code("gender")`},
+			{Field: "person.height", Expr: "person.height | number"},
+			{Field: "person.age", Expr: "person.age | string"},
+			{Field: "person.phones", Expr: `person.phones | split(",")`},
+			{Field: "person.nickname", Expr: `coalesce(person.nickname, person.lastName) | lower`},
+			{Field: "person.title", Expr: `person.title | default("N/A")`},
+		},
+		CodeLists: map[string]map[string]string{"gender": {"M": "MALE", "F": "FEMALE"}},
+	}, data)
+	require.NoError(t, err)
+
+	person := data["person"].(map[string]interface{})
+	assert.Equal(t, "NIMAL", person["firstName"])
+	assert.Equal(t, "NIMAL Perera", person["fullName"])
+	assert.Equal(t, "1990-03-24", person["dateOfBirth"])
+	assert.Equal(t, "MALE", person["gender"])
+	assert.Equal(t, 172.5, person["height"])
+	assert.Equal(t, "34", person["age"])
+	assert.Equal(t, []interface{}{"0771234567", "0112345678"}, person["phones"])
+	assert.Equal(t, "perera", person["nickname"])
+	assert.Equal(t, "N/A", person["title"])
+}
+
+func TestApply_Each(t *testing.T) {
+	data := map[string]interface{}{
+		"vehicle": map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{"class": "B1", "registered": "2020.01.15"},
+				map[string]interface{}{"class": "X", "registered": "2021.06.30"},
+			},
+		},
+	}
+	err := apply(t, &Config{
+		Fields: []*FieldMapping{
+			{Each: "vehicle.data", Field: "class", Expr: `class | code("classes")`},
+			{Each: "vehicle.data", Field: "registered", Expr: `registered | date("2006.01.02", "2006-01-02")`},
+		},
+		CodeLists: map[string]map[string]string{"classes": {"B1": "MOTOR_CAR"}},
+	}, data)
+	require.NoError(t, err)
+
+	items := data["vehicle"].(map[string]interface{})["data"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"class": "MOTOR_CAR", "registered": "2020-01-15"}, items[0])
+	// Unknown codes are kept
+	assert.Equal(t, map[string]interface{}{"class": "X", "registered": "2021-06-30"}, items[1])
+}
+
+func TestApply_MissingData(t *testing.T) {
+	data := map[string]interface{}{"person": nil}
+	err := apply(t, &Config{
+		Fields: []*FieldMapping{
+			{Field: "person.name", Expr: `person.name | default("unknown")`},
+			{Each: "vehicles", Field: "class", Expr: "class | upper"},
+		},
+	}, data)
+	require.NoError(t, err)
+	// A parent object the provider did not return is not created
+	assert.Equal(t, map[string]interface{}{"person": nil}, data)
+}
+
+func TestApply_FailuresKeepValue(t *testing.T) {
+	data := map[string]interface{}{"person": map[string]interface{}{"dateOfBirth": "unknown", "name": "a"}}
+	err := apply(t, &Config{
+		Fields: []*FieldMapping{
+			{Field: "person.dateOfBirth", Expr: `person.dateOfBirth | date("2006-01-02", "02/01/2006")`},
+			{Field: "person.name", Expr: "person.name | upper"},
+		},
+	}, data)
+	assert.ErrorContains(t, err, "person.dateOfBirth: date:")
+	assert.Equal(t, map[string]interface{}{"dateOfBirth": "unknown", "name": "A"}, data["person"])
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := map[string]*FieldMapping{
+		"missing expr":        {Field: "a"},
+		"unknown function":    {Field: "a", Expr: "a | reverse"},
+		"unknown term func":   {Field: "a", Expr: "join(a)"},
+		"wrong arity":         {Field: "a", Expr: `a | date("2006")`},
+		"non-string argument": {Field: "a", Expr: "a | split(1)"},
+		"unknown code list":   {Field: "a", Expr: `a | code("nope")`},
+		"unterminated string": {Field: "a", Expr: `a | default("x`},
+		"trailing input":      {Field: "a", Expr: "a b"},
+		"invalid path":        {Field: "a", Expr: "a..b"},
+	}
+	for name, mapping := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(&Config{Fields: []*FieldMapping{mapping}})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -9,5 +9,6 @@ const (
 
 // Endpoint paths
 const (
-	policyDecisionEndpointPath = "/api/v1/policy/decide"
+	policyDecisionEndpointPath      = "/api/v1/policy/decide"
+	consentRequirementsEndpointPath = "/api/v1/policy/requirements"
 )
@@ -1,5 +1,7 @@
 package policy
 
+import "time"
+
 // RequiredField represents a field that requires policy decision
 type RequiredField struct {
 	FieldName string `json:"fieldName"`
@@ -10,6 +12,8 @@ type RequiredField struct {
 type PdpRequest struct {
 	AppId          string          `json:"applicationId"`
 	RequiredFields []RequiredField `json:"requiredFields"`
+	// Context holds request attributes that PDP policy conditions are evaluated against
+	Context map[string]interface{} `json:"context,omitempty"`
 }
 
 // ConsentRequiredField represents a field that requires consent
@@ -22,6 +26,16 @@ type ConsentRequiredField struct {
 	Owner       *OwnerType `json:"owner,omitempty"`
 }
 
+// DeprecatedField represents a requested field whose provider schema is deprecated or sunset
+// Matches PolicyDecisionDeprecatedFieldRecord DTO structure from PolicyDecisionPoint
+type DeprecatedField struct {
+	FieldName string     `json:"fieldName"`
+	SchemaID  string     `json:"schemaId"`
+	Status    string     `json:"status"`
+	SunsetAt  *time.Time `json:"sunsetAt,omitempty"`
+	Message   *string    `json:"message,omitempty"`
+}
+
 // PdpResponse represents a policy decision response
 type PdpResponse struct {
 	AppAuthorized           bool                   `json:"appAuthorized"`
@@ -30,4 +44,40 @@ type PdpResponse struct {
 	ExpiredFields           []ConsentRequiredField `json:"expiredFields"`
 	AppRequiresOwnerConsent bool                   `json:"appRequiresOwnerConsent"`
 	ConsentRequiredFields   []ConsentRequiredField `json:"consentRequiredFields"`
+	ConditionFailedFields   []ConsentRequiredField `json:"conditionFailedFields"`
+	// DeprecatedFields do not affect the decision; consumers are warned about them
+	DeprecatedFields []DeprecatedField `json:"deprecatedFields,omitempty"`
+	// BlockedFields are denied by a platform kill switch on the application, its provider or the field
+	BlockedFields []ConsentRequiredField `json:"blockedFields,omitempty"`
+}
+
+// ConsentRequirement is the consent to request from one owner before their fields are released
+// Matches ConsentRequirement DTO structure from PolicyDecisionPoint
+type ConsentRequirement struct {
+	Owner       OwnerType `json:"owner"`
+	ConsentType string    `json:"consentType"`
+	// GrantDuration is the ISO 8601 duration of the consent
+	GrantDuration string                 `json:"grantDuration"`
+	Purposes      []string               `json:"purposes"`
+	Fields        []ConsentRequiredField `json:"fields"`
+}
+
+// ConsentRequirementsResponse represents the consolidated consent requirements of a policy decision request
+type ConsentRequirementsResponse struct {
+	ApplicationID   string               `json:"applicationId"`
+	ConsentRequired bool                 `json:"consentRequired"`
+	Requirements    []ConsentRequirement `json:"requirements"`
+}
+
+// Requirement returns the consent requirement of the owner, or nil if none of the owner's fields need consent
+func (r *ConsentRequirementsResponse) Requirement(owner OwnerType) *ConsentRequirement {
+	if r == nil {
+		return nil
+	}
+	for i := range r.Requirements {
+		if r.Requirements[i].Owner == owner {
+			return &r.Requirements[i]
+		}
+	}
+	return nil
 }
@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/gov-dx-sandbox/exchange/policy-decision-point/v1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/engine"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmbeddedPdp_Parity checks that the embedded PDP decides exactly like the PDP service over the
+// same policy store
+func TestEmbeddedPdp_Parity(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	policyService := services.NewPolicyMetadataService(db)
+	_, err := policyService.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{
+				FieldName:         "person.fullName",
+				Source:            models.SourcePrimary,
+				IsOwner:           true,
+				AccessControlType: models.AccessControlTypePublic,
+			},
+			{
+				FieldName:         "person.nic",
+				DisplayName:       testhelpers.StringPtr("NIC"),
+				Source:            models.SourcePrimary,
+				IsOwner:           false,
+				AccessControlType: models.AccessControlTypeRestricted,
+				Owner:             testhelpers.OwnerPtr(models.OwnerCitizen),
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = policyService.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records: []models.AllowListUpdateRequestRecord{
+			{SchemaID: "schema-123", FieldName: "person.fullName"},
+			{SchemaID: "schema-123", FieldName: "person.nic"},
+		},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	v1.NewHandler(db).SetupRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	remote := NewPdpClient(server.URL)
+	embedded := NewEmbeddedPdp(engine.New(db))
+	ctx := context.Background()
+
+	requests := map[string]*PdpRequest{
+		"authorized": {
+			AppId:          "app-1",
+			RequiredFields: []RequiredField{{SchemaID: "schema-123", FieldName: "person.fullName"}},
+		},
+		"consent required": {
+			AppId: "app-1",
+			RequiredFields: []RequiredField{
+				{SchemaID: "schema-123", FieldName: "person.fullName"},
+				{SchemaID: "schema-123", FieldName: "person.nic"},
+			},
+		},
+		"not allow-listed": {
+			AppId:          "app-2",
+			RequiredFields: []RequiredField{{SchemaID: "schema-123", FieldName: "person.nic"}},
+		},
+	}
+
+	for name, request := range requests {
+		t.Run(name, func(t *testing.T) {
+			remoteDecision, err := remote.MakePdpRequest(ctx, request)
+			require.NoError(t, err)
+			embeddedDecision, err := embedded.MakePdpRequest(ctx, request)
+			require.NoError(t, err)
+			assert.Equal(t, remoteDecision, embeddedDecision)
+
+			remoteRequirements, err := remote.GetConsentRequirements(ctx, request)
+			require.NoError(t, err)
+			embeddedRequirements, err := embedded.GetConsentRequirements(ctx, request)
+			require.NoError(t, err)
+			assert.Equal(t, remoteRequirements, embeddedRequirements)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		unknown := &PdpRequest{
+			AppId:          "app-1",
+			RequiredFields: []RequiredField{{SchemaID: "schema-123", FieldName: "person.unknown"}},
+		}
+		_, err := remote.MakePdpRequest(ctx, unknown)
+		assert.Error(t, err)
+		_, err = embedded.MakePdpRequest(ctx, unknown)
+		assert.Error(t, err)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = embedded.MakePdpRequest(cancelled, requests["authorized"])
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
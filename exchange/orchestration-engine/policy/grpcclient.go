@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi/pdpv1"
+	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcRequestTimeout bounds each call to the PDP's gRPC API, like the HTTP client's timeout
+const grpcRequestTimeout = 10 * time.Second
+
+// PdpGrpcClient calls the PDP's gRPC decision API, a typed protobuf contract that avoids the JSON
+// encoding of the HTTP API on every query
+type PdpGrpcClient struct {
+	conn   *grpc.ClientConn
+	client pdpv1.DecisionServiceClient
+	// tokens issues the access tokens the PDP authenticates the engine with; nil sends none
+	tokens oauth2.TokenSource
+}
+
+// NewPdpGrpcClient creates a client of the PDP's gRPC API at address (host:port). The connection is
+// established lazily and shared by all calls.
+func NewPdpGrpcClient(address string) (*PdpGrpcClient, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PDP gRPC client: %w", err)
+	}
+	return newPdpGrpcClient(conn), nil
+}
+
+// newPdpGrpcClient creates a client on an existing connection
+func newPdpGrpcClient(conn *grpc.ClientConn) *PdpGrpcClient {
+	return &PdpGrpcClient{conn: conn, client: pdpv1.NewDecisionServiceClient(conn)}
+}
+
+// SetTokenSource authenticates calls to the PDP with access tokens from tokens
+func (c *PdpGrpcClient) SetTokenSource(tokens oauth2.TokenSource) {
+	c.tokens = tokens
+}
+
+// Close closes the connection to the PDP
+func (c *PdpGrpcClient) Close() error {
+	return c.conn.Close()
+}
+
+// MakePdpRequest gets the policy decision for the requested fields
+func (c *PdpGrpcClient) MakePdpRequest(ctx context.Context, request *PdpRequest) (*PdpResponse, error) {
+	decisionRequest, err := c.decisionRequestOf(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, err := c.outgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	decision, err := c.client.Decide(ctx, decisionRequest)
+	if err != nil {
+		logger.Log.Error("PDP gRPC decision failed", "error", err)
+		return nil, fmt.Errorf("PDP decision failed: %w", err)
+	}
+
+	var pdpResponse PdpResponse
+	if err := convert(grpcapi.DecisionResponseFromProto(decision), &pdpResponse); err != nil {
+		return nil, err
+	}
+	return &pdpResponse, nil
+}
+
+// GetConsentRequirements gets the consolidated consent requirements for the requested fields
+func (c *PdpGrpcClient) GetConsentRequirements(ctx context.Context, request *PdpRequest) (*ConsentRequirementsResponse, error) {
+	decisionRequest, err := c.decisionRequestOf(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, err := c.outgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	requirements, err := c.client.GetConsentRequirements(ctx, decisionRequest)
+	if err != nil {
+		logger.Log.Error("PDP gRPC consent requirements failed", "error", err)
+		return nil, fmt.Errorf("PDP consent requirements failed: %w", err)
+	}
+
+	var response ConsentRequirementsResponse
+	if err := convert(grpcapi.ConsentRequirementsFromProto(requirements), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// decisionRequestOf converts the request to the PDP's protobuf message
+func (c *PdpGrpcClient) decisionRequestOf(ctx context.Context, request *PdpRequest) (*pdpv1.DecisionRequest, error) {
+	decisionRequest, err := decisionRequestOf(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return grpcapi.DecisionRequestToProto(decisionRequest)
+}
+
+// outgoingContext bounds the call and forwards the access token and the trace and request IDs of ctx
+// as metadata, as the HTTP client forwards them as headers
+func (c *PdpGrpcClient) outgoingContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if c.tokens != nil {
+		token, err := c.tokens.Token()
+		if err != nil {
+			logger.Log.Error("Failed to get PDP access token", "error", err)
+			return nil, nil, fmt.Errorf("failed to get PDP access token: %w", err)
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token.Type()+" "+token.AccessToken)
+	}
+	if traceID := monitoring.GetTraceIDFromContext(ctx); traceID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-trace-id", traceID)
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(requestid.Header), id)
+	}
+	ctx, cancel := context.WithTimeout(ctx, grpcRequestTimeout)
+	return ctx, cancel, nil
+}
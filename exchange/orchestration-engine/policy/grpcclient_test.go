@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/engine"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi/pdpv1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// TestPdpGrpcClient_Parity checks that decisions made over the PDP's gRPC API read exactly like
+// decisions of the embedded PDP over the same policy store
+func TestPdpGrpcClient_Parity(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	policyService := services.NewPolicyMetadataService(db)
+	_, err := policyService.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{
+				FieldName:         "person.fullName",
+				Source:            models.SourcePrimary,
+				IsOwner:           true,
+				AccessControlType: models.AccessControlTypePublic,
+			},
+			{
+				FieldName:         "person.nic",
+				DisplayName:       testhelpers.StringPtr("NIC"),
+				Source:            models.SourcePrimary,
+				IsOwner:           false,
+				AccessControlType: models.AccessControlTypeRestricted,
+				Owner:             testhelpers.OwnerPtr(models.OwnerCitizen),
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = policyService.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records: []models.AllowListUpdateRequestRecord{
+			{SchemaID: "schema-123", FieldName: "person.fullName"},
+			{SchemaID: "schema-123", FieldName: "person.nic"},
+		},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pdpv1.RegisterDecisionServiceServer(server, grpcapi.NewServer(engine.New(db)))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	remote := newPdpGrpcClient(conn)
+	defer remote.Close()
+
+	embedded := NewEmbeddedPdp(engine.New(db))
+	ctx := context.Background()
+
+	requests := map[string]*PdpRequest{
+		"authorized": {
+			AppId:          "app-1",
+			RequiredFields: []RequiredField{{SchemaID: "schema-123", FieldName: "person.fullName"}},
+			Context:        map[string]interface{}{"consumer.sector": "banking"},
+		},
+		"consent required": {
+			AppId: "app-1",
+			RequiredFields: []RequiredField{
+				{SchemaID: "schema-123", FieldName: "person.fullName"},
+				{SchemaID: "schema-123", FieldName: "person.nic"},
+			},
+		},
+		"not allow-listed": {
+			AppId:          "app-2",
+			RequiredFields: []RequiredField{{SchemaID: "schema-123", FieldName: "person.nic"}},
+		},
+	}
+
+	for name, request := range requests {
+		t.Run(name, func(t *testing.T) {
+			remoteDecision, err := remote.MakePdpRequest(ctx, request)
+			require.NoError(t, err)
+			embeddedDecision, err := embedded.MakePdpRequest(ctx, request)
+			require.NoError(t, err)
+			assert.Equal(t, embeddedDecision, remoteDecision)
+
+			remoteRequirements, err := remote.GetConsentRequirements(ctx, request)
+			require.NoError(t, err)
+			embeddedRequirements, err := embedded.GetConsentRequirements(ctx, request)
+			require.NoError(t, err)
+			assert.Equal(t, embeddedRequirements, remoteRequirements)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := remote.MakePdpRequest(ctx, &PdpRequest{
+			AppId:          "app-1",
+			RequiredFields: []RequiredField{{SchemaID: "schema-123", FieldName: "person.unknown"}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestPdpGrpcClient_SendsAccessToken(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+
+	var authorization []string
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		authorization = metadata.ValueFromIncomingContext(ctx, "authorization")
+		return handler(ctx, req)
+	}))
+	pdpv1.RegisterDecisionServiceServer(server, grpcapi.NewServer(engine.New(db)))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	client := newPdpGrpcClient(conn)
+	defer client.Close()
+	client.SetTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "engine-token", TokenType: "Bearer"}))
+
+	_, err = client.GetConsentRequirements(context.Background(), &PdpRequest{AppId: "app-1"})
+	assert.Error(t, err, "the request is invalid, but reaches the PDP")
+	assert.Equal(t, []string{"Bearer engine-token"}, authorization)
+}
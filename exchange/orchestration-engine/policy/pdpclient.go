@@ -10,12 +10,24 @@ import (
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
 	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"golang.org/x/oauth2"
 )
 
+// Decider makes policy decisions for the federator, either through the PDP service or in process
+type Decider interface {
+	// MakePdpRequest gets the policy decision for the requested fields
+	MakePdpRequest(ctx context.Context, request *PdpRequest) (*PdpResponse, error)
+	// GetConsentRequirements gets the consolidated consent requirements for the requested fields
+	GetConsentRequirements(ctx context.Context, request *PdpRequest) (*ConsentRequirementsResponse, error)
+}
+
 // PdpClient represents a client to interact with the Policy Decision Point service
 type PdpClient struct {
 	httpClient *http.Client
 	baseUrl    string
+	// tokens issues the access tokens the PDP authenticates the engine with; nil sends none
+	tokens oauth2.TokenSource
 }
 
 // NewPdpClient creates a new instance of PdpClient
@@ -28,38 +40,68 @@ func NewPdpClient(baseUrl string) *PdpClient {
 	}
 }
 
+// SetTokenSource authenticates requests to the PDP with access tokens from tokens
+func (p *PdpClient) SetTokenSource(tokens oauth2.TokenSource) {
+	p.tokens = tokens
+}
+
 // MakePdpRequest sends a request to get a policy decision
 func (p *PdpClient) MakePdpRequest(ctx context.Context, request *PdpRequest) (*PdpResponse, error) {
-	// Implement the logic to make a PDP request using p.httpClient
+	var pdpResponse PdpResponse
+	if err := p.post(ctx, policyDecisionEndpointPath, request, &pdpResponse); err != nil {
+		return nil, err
+	}
+	return &pdpResponse, nil
+}
+
+// GetConsentRequirements sends a request to get the consolidated consent requirements for the
+// requested fields, which consent requests are built from
+func (p *PdpClient) GetConsentRequirements(ctx context.Context, request *PdpRequest) (*ConsentRequirementsResponse, error) {
+	var requirements ConsentRequirementsResponse
+	if err := p.post(ctx, consentRequirementsEndpointPath, request, &requirements); err != nil {
+		return nil, err
+	}
+	return &requirements, nil
+}
+
+// post sends the request to the PDP endpoint at path and decodes the response into out
+func (p *PdpClient) post(ctx context.Context, path string, request *PdpRequest, out interface{}) error {
 	requestBody, err := json.Marshal(request)
 	if err != nil {
-		// handle error
 		logger.Log.Error("Failed to marshal PDP request", "error", err)
-		return nil, err
+		return err
 	}
 
 	// log the json request body
-	logger.Log.Info("PDP Request Body", "body", string(requestBody))
+	logger.Log.Info("PDP Request Body", "path", path, "body", string(requestBody))
 
 	// Create request with context for cancellation and timeout support
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseUrl+policyDecisionEndpointPath, bytes.NewReader(requestBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseUrl+path, bytes.NewReader(requestBody))
 	if err != nil {
 		logger.Log.Error("Failed to create PDP request", "error", err)
-		return nil, err
+		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if p.tokens != nil {
+		token, err := p.tokens.Token()
+		if err != nil {
+			logger.Log.Error("Failed to get PDP access token", "error", err)
+			return fmt.Errorf("failed to get PDP access token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	}
 
 	// Propagate traceID from context to header for audit correlation
 	traceID := monitoring.GetTraceIDFromContext(ctx)
 	if traceID != "" {
 		req.Header.Set("X-Trace-ID", traceID)
 	}
+	requestid.SetHeader(req)
 
 	response, err := p.httpClient.Do(req)
 	if err != nil {
-		// handle error
 		logger.Log.Error("Failed to make PDP request", "error", err)
-		return nil, err
+		return err
 	}
 	defer response.Body.Close()
 
@@ -70,16 +112,12 @@ func (p *PdpClient) MakePdpRequest(ctx context.Context, request *PdpRequest) (*P
 		}
 		errorMsg := errorBody.String()
 		logger.Log.Error("PDP request failed", "status", response.StatusCode, "response", errorMsg)
-		return nil, fmt.Errorf("PDP request failed, status code: %d, response: %s", response.StatusCode, errorMsg)
+		return fmt.Errorf("PDP request failed, status code: %d, response: %s", response.StatusCode, errorMsg)
 	}
 
-	var pdpResponse PdpResponse
-	err = json.NewDecoder(response.Body).Decode(&pdpResponse)
-	if err != nil {
-		// handle error
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
 		logger.Log.Error("Failed to decode PDP response", "error", err)
-		return nil, err
+		return err
 	}
-
-	return &pdpResponse, nil
+	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"golang.org/x/oauth2"
 )
 
 func init() {
@@ -254,6 +255,32 @@ func TestMakePdpRequest_AppAccessExpired(t *testing.T) {
 	}
 }
 
+func TestMakePdpRequest_SendsAccessToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer engine-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(PdpResponse{AppAuthorized: true})
+	}))
+	defer server.Close()
+
+	client := NewPdpClient(server.URL)
+	request := &PdpRequest{AppId: "app456", RequiredFields: []RequiredField{{SchemaID: "schema1", FieldName: "field1"}}}
+	if _, err := client.MakePdpRequest(context.Background(), request); err == nil {
+		t.Fatal("Expected the PDP to reject a request without an access token")
+	}
+
+	client.SetTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "engine-token", TokenType: "Bearer"}))
+	response, err := client.MakePdpRequest(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !response.AppAuthorized {
+		t.Error("Expected AppAuthorized to be true")
+	}
+}
+
 func TestMakePdpRequest_NetworkError(t *testing.T) {
 	client := NewPdpClient("http://invalid-url-that-does-not-exist:9999")
 
@@ -370,3 +397,56 @@ func TestMakePdpRequest_BadRequest(t *testing.T) {
 		t.Errorf("Expected nil response on error, got %v", response)
 	}
 }
+
+func TestGetConsentRequirements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/policy/requirements" {
+			t.Errorf("Expected path /api/v1/policy/requirements, got %s", r.URL.Path)
+		}
+
+		response := ConsentRequirementsResponse{
+			ApplicationID:   "app456",
+			ConsentRequired: true,
+			Requirements: []ConsentRequirement{
+				{
+					Owner:         OwnerCitizen,
+					ConsentType:   "realtime",
+					GrantDuration: "PT1H",
+					Purposes:      []string{"Release of personal data for the requested service"},
+					Fields:        []ConsentRequiredField{{FieldName: "field1", SchemaID: "schema1"}},
+				},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewPdpClient(server.URL)
+
+	request := &PdpRequest{
+		AppId:          "app456",
+		RequiredFields: []RequiredField{{SchemaID: "schema1", FieldName: "field1"}},
+	}
+
+	response, err := client.GetConsentRequirements(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	requirement := response.Requirement(OwnerCitizen)
+	if requirement == nil {
+		t.Fatal("Expected a consent requirement for the citizen")
+	}
+	if requirement.GrantDuration != "PT1H" {
+		t.Errorf("Expected grant duration PT1H, got %s", requirement.GrantDuration)
+	}
+	if len(requirement.Fields) != 1 {
+		t.Errorf("Expected 1 field, got %d", len(requirement.Fields))
+	}
+	if response.Requirement(OwnerType("provider")) != nil {
+		t.Error("Expected no consent requirement for another owner")
+	}
+}
@@ -11,6 +11,15 @@ type Handler struct {
 	mu         sync.RWMutex
 	Providers  []*Provider
 	HttpClient *http.Client
+
+	failuresMu sync.RWMutex
+	failures   map[string]Failure
+}
+
+// Failure describes why the last request to a provider failed
+type Failure struct {
+	Error string    `json:"error"`
+	Since time.Time `json:"since"`
 }
 
 // NewProviderHandler creates a new ProviderHandler with the given providers.
@@ -60,3 +69,34 @@ func (h *Handler) AddProvider(provider *Provider) {
 	h.Providers = append(h.Providers, provider)
 	provider.Client = h.HttpClient
 }
+
+// RecordFetch records the outcome of a request to a provider, so that the health check reports the
+// providers whose last request failed
+func (h *Handler) RecordFetch(serviceKey string, err error) {
+	h.failuresMu.Lock()
+	defer h.failuresMu.Unlock()
+	if err == nil {
+		delete(h.failures, serviceKey)
+		return
+	}
+	if h.failures == nil {
+		h.failures = make(map[string]Failure)
+	}
+	failure, failing := h.failures[serviceKey]
+	if !failing {
+		failure.Since = time.Now()
+	}
+	failure.Error = err.Error()
+	h.failures[serviceKey] = failure
+}
+
+// Failures returns the providers whose last request failed, by service key
+func (h *Handler) Failures() map[string]Failure {
+	h.failuresMu.RLock()
+	defer h.failuresMu.RUnlock()
+	failures := make(map[string]Failure, len(h.failures))
+	for key, failure := range h.failures {
+		failures[key] = failure
+	}
+	return failures
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/auth"
+	"github.com/gov-dx-sandbox/shared/requestid"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
@@ -55,6 +56,7 @@ func (p *Provider) PerformRequest(ctx context.Context, reqBody []byte) (*http.Re
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	requestid.SetHeader(req)
 
 	if p.Auth != nil {
 		switch p.Auth.Type {
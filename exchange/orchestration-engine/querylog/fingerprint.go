@@ -0,0 +1,113 @@
+package querylog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// UnparsableFingerprint is the fingerprint of queries that are not valid GraphQL. Their text is not
+// logged, as it may hold the literals of the query.
+const UnparsableFingerprint = "unparsable"
+
+// literalPlaceholder replaces the literals of normalized queries
+const literalPlaceholder = "?"
+
+// Operation is the normalized form of a query
+type Operation struct {
+	// Fingerprint identifies the queries with the same normalized form
+	Fingerprint string
+	// Normalized is the query with its literals replaced by "?" and its whitespace collapsed, so
+	// queries that only differ in their arguments, e.g. the NIC they look up, share a fingerprint
+	Normalized string
+	// Name is the name of the query's first operation, if it has one
+	Name string
+}
+
+// Fingerprint normalizes a query and computes its fingerprint. Variables are kept as they are, since
+// their values are not part of the query.
+func Fingerprint(query string) Operation {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query), Name: "Query"})})
+	if err != nil {
+		return Operation{Fingerprint: UnparsableFingerprint}
+	}
+
+	var name string
+	for _, definition := range doc.Definitions {
+		switch def := definition.(type) {
+		case *ast.OperationDefinition:
+			if name == "" && def.Name != nil {
+				name = def.Name.Value
+			}
+			stripVariableDefinitions(def.VariableDefinitions)
+			stripDirectives(def.Directives)
+			stripSelectionSet(def.SelectionSet)
+		case *ast.FragmentDefinition:
+			stripDirectives(def.Directives)
+			stripSelectionSet(def.SelectionSet)
+		}
+	}
+
+	normalized := strings.Join(strings.Fields(fmt.Sprint(printer.Print(doc))), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return Operation{Fingerprint: hex.EncodeToString(sum[:8]), Normalized: normalized, Name: name}
+}
+
+func stripSelectionSet(selectionSet *ast.SelectionSet) {
+	if selectionSet == nil {
+		return
+	}
+	for _, selection := range selectionSet.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			for _, argument := range sel.Arguments {
+				argument.Value = stripValue(argument.Value)
+			}
+			stripDirectives(sel.Directives)
+			stripSelectionSet(sel.SelectionSet)
+		case *ast.InlineFragment:
+			stripDirectives(sel.Directives)
+			stripSelectionSet(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			stripDirectives(sel.Directives)
+		}
+	}
+}
+
+func stripVariableDefinitions(definitions []*ast.VariableDefinition) {
+	for _, definition := range definitions {
+		if definition.DefaultValue != nil {
+			definition.DefaultValue = stripValue(definition.DefaultValue)
+		}
+	}
+}
+
+func stripDirectives(directives []*ast.Directive) {
+	for _, directive := range directives {
+		for _, argument := range directive.Arguments {
+			argument.Value = stripValue(argument.Value)
+		}
+	}
+}
+
+// stripValue replaces literals with the placeholder. Variables are kept, and object literals keep
+// their field names; lists are replaced as a whole, so their length does not change the fingerprint.
+func stripValue(value ast.Value) ast.Value {
+	switch v := value.(type) {
+	case *ast.Variable:
+		return v
+	case *ast.ObjectValue:
+		for _, field := range v.Fields {
+			field.Value = stripValue(field.Value)
+		}
+		return v
+	default:
+		return ast.NewEnumValue(&ast.EnumValue{Value: literalPlaceholder})
+	}
+}
@@ -0,0 +1,315 @@
+// Package querylog records every query the engine executes with the fingerprint of its normalized
+// form, the consumer that sent it, its latency, the providers it called and the records it returned,
+// so that slow queries and usage patterns can be analysed by fingerprint.
+package querylog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// OrderByExecutions ranks fingerprints by how often they were executed
+	OrderByExecutions = "executions"
+	// OrderByAvgLatency ranks fingerprints by their average latency
+	OrderByAvgLatency = "avgLatency"
+	// OrderByP95Latency ranks fingerprints by their 95th percentile latency
+	OrderByP95Latency = "p95Latency"
+	// OrderByRows ranks fingerprints by the records they returned
+	OrderByRows = "rows"
+
+	// DefaultLimit is the number of fingerprints or executions returned by default
+	DefaultLimit = 20
+	// MaxLimit bounds the number of fingerprints or executions returned
+	MaxLimit = 500
+)
+
+// ErrInvalidQuery is returned for an unknown ordering or a negative limit
+var ErrInvalidQuery = errors.New("invalid query log query")
+
+// Entry is one executed query
+type Entry struct {
+	ID              int64  `json:"id"`
+	Fingerprint     string `json:"fingerprint"`
+	NormalizedQuery string `json:"normalizedQuery"`
+	OperationName   string `json:"operationName,omitempty"`
+	ConsumerID      string `json:"consumerId"`
+	LatencyMs       int64  `json:"latencyMs"`
+	// Providers are the providers the query was sent to, excluding those in maintenance
+	Providers  []string  `json:"providers"`
+	RowCount   int64     `json:"rowCount"`
+	ErrorCount int       `json:"errorCount"`
+	ExecutedAt time.Time `json:"executedAt"`
+}
+
+// Summary aggregates the executions of a fingerprint
+type Summary struct {
+	Fingerprint     string  `json:"fingerprint"`
+	NormalizedQuery string  `json:"normalizedQuery"`
+	OperationName   string  `json:"operationName,omitempty"`
+	Executions      int64   `json:"executions"`
+	Consumers       int64   `json:"consumers"`
+	AvgLatencyMs    float64 `json:"avgLatencyMs"`
+	P95LatencyMs    float64 `json:"p95LatencyMs"`
+	MaxLatencyMs    int64   `json:"maxLatencyMs"`
+	TotalRows       int64   `json:"totalRows"`
+	// FailedExecutions counts the executions whose response had errors
+	FailedExecutions int64     `json:"failedExecutions"`
+	Providers        []string  `json:"providers"`
+	LastExecutedAt   time.Time `json:"lastExecutedAt"`
+}
+
+// TopOptions selects the executions aggregated by Top
+type TopOptions struct {
+	Since      time.Time
+	ConsumerID string
+	OrderBy    string
+	Limit      int
+}
+
+// ListOptions selects the executions returned by List, most recent first
+type ListOptions struct {
+	Since        time.Time
+	Fingerprint  string
+	ConsumerID   string
+	MinLatencyMs int64
+	Limit        int
+}
+
+// Normalize applies the defaults of the options and validates them
+func (o *TopOptions) Normalize() error {
+	switch o.OrderBy {
+	case "":
+		o.OrderBy = OrderByExecutions
+	case OrderByExecutions, OrderByAvgLatency, OrderByP95Latency, OrderByRows:
+	default:
+		return fmt.Errorf("%w: orderBy must be one of %s, %s, %s or %s", ErrInvalidQuery,
+			OrderByExecutions, OrderByAvgLatency, OrderByP95Latency, OrderByRows)
+	}
+	limit, err := normalizeLimit(o.Limit)
+	o.Limit = limit
+	return err
+}
+
+// Normalize applies the defaults of the options and validates them
+func (o *ListOptions) Normalize() error {
+	limit, err := normalizeLimit(o.Limit)
+	o.Limit = limit
+	return err
+}
+
+func normalizeLimit(limit int) (int, error) {
+	switch {
+	case limit < 0:
+		return 0, fmt.Errorf("%w: limit must not be negative", ErrInvalidQuery)
+	case limit == 0:
+		return DefaultLimit, nil
+	case limit > MaxLimit:
+		return MaxLimit, nil
+	}
+	return limit, nil
+}
+
+// Store persists executed queries
+type Store interface {
+	Insert(ctx context.Context, entries []*Entry) error
+	// Top aggregates the executions by fingerprint and returns the top fingerprints
+	Top(ctx context.Context, opts TopOptions) ([]*Summary, error)
+	List(ctx context.Context, opts ListOptions) ([]*Entry, error)
+	// DeleteBefore removes the executions older than the given time and returns how many it removed
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DefaultMemoryEntries bounds the number of executions a memory store keeps
+const DefaultMemoryEntries = 10000
+
+// MemoryStore keeps the most recent executions in memory
+type MemoryStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	nextID  int64
+	entries []*Entry
+}
+
+// NewMemoryStore creates an empty in-memory store; non-positive maxEntries use DefaultMemoryEntries
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryEntries
+	}
+	return &MemoryStore{maxEntries: maxEntries}
+}
+
+// Insert stores executions, dropping the oldest ones beyond the store's bound
+func (s *MemoryStore) Insert(_ context.Context, entries []*Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range entries {
+		s.nextID++
+		stored := *entry
+		stored.ID = s.nextID
+		s.entries = append(s.entries, &stored)
+	}
+	if excess := len(s.entries) - s.maxEntries; excess > 0 {
+		s.entries = append([]*Entry(nil), s.entries[excess:]...)
+	}
+	return nil
+}
+
+// Top aggregates the executions by fingerprint and returns the top fingerprints
+func (s *MemoryStore) Top(_ context.Context, opts TopOptions) ([]*Summary, error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type aggregate struct {
+		summary   *Summary
+		latencies []int64
+		consumers map[string]bool
+		providers map[string]bool
+	}
+	aggregates := make(map[string]*aggregate)
+	for _, entry := range s.entries {
+		if entry.ExecutedAt.Before(opts.Since) || (opts.ConsumerID != "" && entry.ConsumerID != opts.ConsumerID) {
+			continue
+		}
+		agg, ok := aggregates[entry.Fingerprint]
+		if !ok {
+			agg = &aggregate{
+				summary:   &Summary{Fingerprint: entry.Fingerprint},
+				consumers: make(map[string]bool),
+				providers: make(map[string]bool),
+			}
+			aggregates[entry.Fingerprint] = agg
+		}
+		summary := agg.summary
+		summary.Executions++
+		summary.TotalRows += entry.RowCount
+		if entry.ErrorCount > 0 {
+			summary.FailedExecutions++
+		}
+		if entry.LatencyMs > summary.MaxLatencyMs {
+			summary.MaxLatencyMs = entry.LatencyMs
+		}
+		if !entry.ExecutedAt.Before(summary.LastExecutedAt) {
+			summary.LastExecutedAt = entry.ExecutedAt
+			summary.NormalizedQuery = entry.NormalizedQuery
+			summary.OperationName = entry.OperationName
+		}
+		agg.latencies = append(agg.latencies, entry.LatencyMs)
+		agg.consumers[entry.ConsumerID] = true
+		for _, provider := range entry.Providers {
+			agg.providers[provider] = true
+		}
+	}
+
+	summaries := make([]*Summary, 0, len(aggregates))
+	for _, agg := range aggregates {
+		summary := agg.summary
+		summary.Consumers = int64(len(agg.consumers))
+		summary.AvgLatencyMs = average(agg.latencies)
+		summary.P95LatencyMs = percentile(agg.latencies, 0.95)
+		summary.Providers = make([]string, 0, len(agg.providers))
+		for provider := range agg.providers {
+			summary.Providers = append(summary.Providers, provider)
+		}
+		sort.Strings(summary.Providers)
+		summaries = append(summaries, summary)
+	}
+	SortSummaries(summaries, opts.OrderBy)
+	if len(summaries) > opts.Limit {
+		summaries = summaries[:opts.Limit]
+	}
+	return summaries, nil
+}
+
+// List returns the matching executions, most recent first
+func (s *MemoryStore) List(_ context.Context, opts ListOptions) ([]*Entry, error) {
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := []*Entry{}
+	for i := len(s.entries) - 1; i >= 0 && len(entries) < opts.Limit; i-- {
+		entry := s.entries[i]
+		if entry.ExecutedAt.Before(opts.Since) || entry.LatencyMs < opts.MinLatencyMs ||
+			(opts.Fingerprint != "" && entry.Fingerprint != opts.Fingerprint) ||
+			(opts.ConsumerID != "" && entry.ConsumerID != opts.ConsumerID) {
+			continue
+		}
+		copied := *entry
+		entries = append(entries, &copied)
+	}
+	return entries, nil
+}
+
+// DeleteBefore removes the executions older than the given time
+func (s *MemoryStore) DeleteBefore(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.entries[:0]
+	for _, entry := range s.entries {
+		if !entry.ExecutedAt.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	deleted := int64(len(s.entries) - len(kept))
+	s.entries = kept
+	return deleted, nil
+}
+
+// SortSummaries orders summaries by the given ordering, highest first, then by fingerprint
+func SortSummaries(summaries []*Summary, orderBy string) {
+	key := func(s *Summary) float64 {
+		switch orderBy {
+		case OrderByAvgLatency:
+			return s.AvgLatencyMs
+		case OrderByP95Latency:
+			return s.P95LatencyMs
+		case OrderByRows:
+			return float64(s.TotalRows)
+		default:
+			return float64(s.Executions)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if a, b := key(summaries[i]), key(summaries[j]); a != b {
+			return a > b
+		}
+		return summaries[i].Fingerprint < summaries[j].Fingerprint
+	})
+}
+
+func average(values []int64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum int64
+	for _, value := range values {
+		sum += value
+	}
+	return float64(sum) / float64(len(values))
+}
+
+// percentile returns the continuous percentile of values, interpolating between the closest ranks
+// like Postgres' percentile_cont
+func percentile(values []int64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return float64(sorted[lower]) + (rank-float64(lower))*float64(sorted[upper]-sorted[lower])
+}
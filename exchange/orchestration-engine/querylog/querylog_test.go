@@ -0,0 +1,137 @@
+package querylog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Initialize logger for tests
+	logger.Init()
+}
+
+func TestFingerprint_StripsLiterals(t *testing.T) {
+	first := Fingerprint(`query GetPerson { person(nic: "199512345678") { fullName vehicles(limit: 5, types: ["car", "van"]) { regNo } } }`)
+	second := Fingerprint(`query GetPerson {
+		person(nic: "200087654321") {
+			fullName
+			vehicles(limit: 10, types: ["bus"]) { regNo }
+		}
+	}`)
+
+	assert.Equal(t, first.Fingerprint, second.Fingerprint)
+	assert.Equal(t, "GetPerson", first.Name)
+	assert.NotContains(t, first.Normalized, "199512345678")
+	assert.Contains(t, first.Normalized, `person(nic: ?)`)
+	assert.Contains(t, first.Normalized, `vehicles(limit: ?, types: ?)`)
+
+	other := Fingerprint(`query GetPerson { person(nic: "199512345678") { fullName } }`)
+	assert.NotEqual(t, first.Fingerprint, other.Fingerprint, "different selections have different fingerprints")
+}
+
+func TestFingerprint_KeepsVariablesAndObjectFields(t *testing.T) {
+	operation := Fingerprint(`query ($nic: String = "1995") { person(nic: $nic, filter: {city: "Colombo"}) @include(if: true) { fullName } }`)
+
+	assert.Contains(t, operation.Normalized, "$nic: String = ?")
+	assert.Contains(t, operation.Normalized, "nic: $nic")
+	assert.Contains(t, operation.Normalized, "filter: {city: ?}")
+	assert.Contains(t, operation.Normalized, "@include(if: ?)")
+	assert.Empty(t, operation.Name)
+}
+
+func TestFingerprint_Unparsable(t *testing.T) {
+	operation := Fingerprint(`query { person(nic: "1995"`)
+
+	assert.Equal(t, UnparsableFingerprint, operation.Fingerprint)
+	assert.Empty(t, operation.Normalized, "the text of unparsable queries is not kept")
+}
+
+func TestMemoryStore_Top(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(0)
+	now := time.Now()
+	require.NoError(t, store.Insert(ctx, []*Entry{
+		{Fingerprint: "a", ConsumerID: "app-1", LatencyMs: 10, RowCount: 1, Providers: []string{"drp"}, ExecutedAt: now},
+		{Fingerprint: "a", ConsumerID: "app-2", LatencyMs: 30, RowCount: 1, Providers: []string{"rgd", "drp"}, ExecutedAt: now},
+		{Fingerprint: "a", ConsumerID: "app-1", LatencyMs: 20, RowCount: 1, ErrorCount: 1, ExecutedAt: now},
+		{Fingerprint: "b", ConsumerID: "app-1", LatencyMs: 500, RowCount: 40, ExecutedAt: now},
+		{Fingerprint: "c", ConsumerID: "app-1", LatencyMs: 900, ExecutedAt: now.Add(-48 * time.Hour)},
+	}))
+
+	summaries, err := store.Top(ctx, TopOptions{Since: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2, "executions before since are left out")
+	top := summaries[0]
+	assert.Equal(t, "a", top.Fingerprint, "fingerprints are ordered by executions by default")
+	assert.Equal(t, int64(3), top.Executions)
+	assert.Equal(t, int64(2), top.Consumers)
+	assert.Equal(t, 20.0, top.AvgLatencyMs)
+	assert.InDelta(t, 29.0, top.P95LatencyMs, 0.001)
+	assert.Equal(t, int64(30), top.MaxLatencyMs)
+	assert.Equal(t, int64(3), top.TotalRows)
+	assert.Equal(t, int64(1), top.FailedExecutions)
+	assert.Equal(t, []string{"drp", "rgd"}, top.Providers)
+
+	summaries, err = store.Top(ctx, TopOptions{Since: now.Add(-time.Hour), OrderBy: OrderByRows, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "b", summaries[0].Fingerprint)
+
+	summaries, err = store.Top(ctx, TopOptions{Since: now.Add(-time.Hour), ConsumerID: "app-2"})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, int64(1), summaries[0].Executions)
+
+	_, err = store.Top(ctx, TopOptions{OrderBy: "name"})
+	assert.True(t, errors.Is(err, ErrInvalidQuery))
+}
+
+func TestMemoryStore_ListAndDeleteBefore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(3)
+	now := time.Now()
+	require.NoError(t, store.Insert(ctx, []*Entry{
+		{Fingerprint: "a", LatencyMs: 10, ExecutedAt: now.Add(-3 * time.Hour)},
+		{Fingerprint: "a", LatencyMs: 200, ExecutedAt: now.Add(-2 * time.Hour)},
+		{Fingerprint: "b", LatencyMs: 300, ExecutedAt: now.Add(-time.Hour)},
+		{Fingerprint: "a", LatencyMs: 400, ExecutedAt: now},
+	}))
+
+	entries, err := store.List(ctx, ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "the oldest executions are dropped beyond the bound")
+	assert.Equal(t, int64(400), entries[0].LatencyMs, "executions are listed most recent first")
+
+	entries, err = store.List(ctx, ListOptions{Fingerprint: "a", MinLatencyMs: 250})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(400), entries[0].LatencyMs)
+
+	deleted, err := store.DeleteBefore(ctx, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	entries, err = store.List(ctx, ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestRecorder_WritesOnClose(t *testing.T) {
+	store := NewMemoryStore(0)
+	recorder := NewRecorder(store, 0, 0)
+
+	recorder.Record(&Entry{Fingerprint: "a", ExecutedAt: time.Now()})
+	recorder.Record(&Entry{Fingerprint: "a", ExecutedAt: time.Now()})
+	recorder.Close()
+	recorder.Record(&Entry{Fingerprint: "a", ExecutedAt: time.Now()})
+	recorder.Close()
+
+	entries, err := recorder.List(context.Background(), ListOptions{Since: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "executions recorded after Close are dropped")
+}
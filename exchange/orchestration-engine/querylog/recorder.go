@@ -0,0 +1,164 @@
+package querylog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+)
+
+const (
+	// DefaultRetention is how long executions are kept by default
+	DefaultRetention = 30 * 24 * time.Hour
+	// DefaultBufferSize bounds the executions waiting to be written by default
+	DefaultBufferSize = 1000
+
+	// batchSize is the most executions written at once
+	batchSize = 100
+	// flushInterval is how long an execution waits at most before it is written
+	flushInterval = time.Second
+	// pruneInterval is how often executions past the retention are removed
+	pruneInterval = time.Hour
+	// writeTimeout bounds a write or prune of the store
+	writeTimeout = 10 * time.Second
+	// closeTimeout bounds the flush of the buffered executions on Close
+	closeTimeout = 5 * time.Second
+)
+
+// Recorder writes executed queries to a store in the background, so that queries are not held up by
+// the store. Executions are dropped while the buffer is full.
+type Recorder struct {
+	store     Store
+	retention time.Duration
+
+	entries chan *Entry
+	done    chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int64
+}
+
+// NewRecorder creates a recorder writing to store and starts it. Non-positive values use
+// DefaultRetention and DefaultBufferSize.
+func NewRecorder(store Store, retention time.Duration, bufferSize int) *Recorder {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	r := &Recorder{
+		store:     store,
+		retention: retention,
+		entries:   make(chan *Entry, bufferSize),
+		done:      make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record queues an execution to be written. Executions recorded after Close are dropped.
+func (r *Recorder) Record(entry *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.entries <- entry:
+	default:
+		r.dropped++
+	}
+}
+
+// Close writes the queued executions and stops the recorder
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.entries)
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+	case <-time.After(closeTimeout):
+		logger.Log.Warn("Timed out writing the query log on shutdown")
+	}
+}
+
+// Top returns the top fingerprints of the store
+func (r *Recorder) Top(ctx context.Context, opts TopOptions) ([]*Summary, error) {
+	return r.store.Top(ctx, opts)
+}
+
+// List returns executions of the store, most recent first
+func (r *Recorder) List(ctx context.Context, opts ListOptions) ([]*Entry, error) {
+	return r.store.List(ctx, opts)
+}
+
+// run writes the queued executions in batches and removes the executions past the retention
+func (r *Recorder) run() {
+	defer close(r.done)
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+
+	batch := make([]*Entry, 0, batchSize)
+	for {
+		select {
+		case entry, ok := <-r.entries:
+			if !ok {
+				r.write(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= batchSize {
+				r.write(batch)
+				batch = batch[:0]
+			}
+		case <-flushTicker.C:
+			r.write(batch)
+			batch = batch[:0]
+		case <-pruneTicker.C:
+			r.prune()
+		}
+	}
+}
+
+func (r *Recorder) write(batch []*Entry) {
+	r.mu.Lock()
+	dropped := r.dropped
+	r.dropped = 0
+	r.mu.Unlock()
+	if dropped > 0 {
+		logger.Log.Warn("Query log buffer full, executions were not logged", "dropped", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	if err := r.store.Insert(ctx, batch); err != nil {
+		logger.Log.Error("Failed to write query log", "executions", len(batch), "error", err)
+	}
+}
+
+func (r *Recorder) prune() {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	deleted, err := r.store.DeleteBefore(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		logger.Log.Error("Failed to remove expired query log entries", "error", err)
+		return
+	}
+	if deleted > 0 {
+		logger.Log.Info("Removed expired query log entries", "deleted", deleted)
+	}
+}
@@ -0,0 +1,98 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
+	"github.com/gov-dx-sandbox/shared/requestid"
+)
+
+// DefaultCacheTTL is how long quotas read from the portal are reused by default
+const DefaultCacheTTL = time.Minute
+
+// PortalClient reads application quotas from the portal backend and caches them, so that quota changes
+// take effect within the cache TTL
+type PortalClient struct {
+	baseURL    string
+	httpClient *http.Client
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedLimits
+}
+
+type cachedLimits struct {
+	limits    *Limits
+	expiresAt time.Time
+}
+
+// NewPortalClient creates a client for the portal backend at baseURL
+func NewPortalClient(baseURL string, ttl time.Duration) *PortalClient {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &PortalClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		ttl:        ttl,
+		cache:      make(map[string]cachedLimits),
+	}
+}
+
+// GetLimits returns the quotas of an application. Applications the portal does not know are unlimited.
+func (c *PortalClient) GetLimits(ctx context.Context, applicationID string) (*Limits, error) {
+	c.mu.Lock()
+	cached, ok := c.cache[applicationID]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.limits, nil
+	}
+
+	limits, err := c.fetchLimits(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[applicationID] = cachedLimits{limits: limits, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return limits, nil
+}
+
+func (c *PortalClient) fetchLimits(ctx context.Context, applicationID string) (*Limits, error) {
+	endpoint := fmt.Sprintf("%s/internal/api/v1/applications/%s/quota", c.baseURL, url.PathEscape(applicationID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if traceID := monitoring.GetTraceIDFromContext(ctx); traceID != "" {
+		req.Header.Set("X-Trace-ID", traceID)
+	}
+	requestid.SetHeader(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return &Limits{ApplicationID: applicationID}, nil
+	default:
+		return nil, fmt.Errorf("failed to get application quota, status code: %d", resp.StatusCode)
+	}
+
+	var limits Limits
+	if err := json.NewDecoder(resp.Body).Decode(&limits); err != nil {
+		return nil, fmt.Errorf("failed to decode application quota: %w", err)
+	}
+	return &limits, nil
+}
@@ -0,0 +1,188 @@
+// Package quota enforces the daily and monthly record quotas of consumer applications. Quotas are
+// configured per application in the portal; the records returned to each application are counted
+// per UTC day and calendar month in a Store.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Windows a quota is enforced over
+const (
+	WindowDaily   = "daily"
+	WindowMonthly = "monthly"
+)
+
+// Limits are the quotas of an application; nil quotas are unlimited
+type Limits struct {
+	ApplicationID      string `json:"applicationId"`
+	DailyRecordQuota   *int64 `json:"dailyRecordQuota"`
+	MonthlyRecordQuota *int64 `json:"monthlyRecordQuota"`
+}
+
+// LimitsSource looks up the quotas of an application
+type LimitsSource interface {
+	GetLimits(ctx context.Context, applicationID string) (*Limits, error)
+}
+
+// Store keeps the record counts of applications per period. Periods are opaque keys such as
+// "daily:2025-01-31" that change when the window resets.
+type Store interface {
+	// Get returns the records counted for the application in the period
+	Get(ctx context.Context, applicationID, period string) (int64, error)
+	// Add adds records to the application's count for the period and returns the new count
+	Add(ctx context.Context, applicationID, period string, records int64) (int64, error)
+}
+
+// WindowUsage is the usage of an application in the current period of a window
+type WindowUsage struct {
+	Window string `json:"window"`
+	Period string `json:"period"`
+	Used   int64  `json:"used"`
+	// Limit and Remaining are nil when the window is unlimited
+	Limit     *int64    `json:"limit"`
+	Remaining *int64    `json:"remaining"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
+
+// Exceeded reports whether the application has used up the window's quota
+func (w *WindowUsage) Exceeded() bool {
+	return w.Limit != nil && w.Used >= *w.Limit
+}
+
+func (w *WindowUsage) updateRemaining() {
+	if w.Limit != nil {
+		remaining := max(*w.Limit-w.Used, 0)
+		w.Remaining = &remaining
+	}
+}
+
+// Usage is the usage of an application against its quotas
+type Usage struct {
+	ApplicationID string      `json:"applicationId"`
+	Daily         WindowUsage `json:"daily"`
+	Monthly       WindowUsage `json:"monthly"`
+}
+
+// Exceeded reports whether the application has used up any of its quotas
+func (u *Usage) Exceeded() bool {
+	return u.Daily.Exceeded() || u.Monthly.Exceeded()
+}
+
+// Count adds records returned after the usage was read
+func (u *Usage) Count(records int64) {
+	for _, window := range []*WindowUsage{&u.Daily, &u.Monthly} {
+		window.Used += records
+		window.updateRemaining()
+	}
+}
+
+// Binding returns the limited window with the fewest remaining records, preferring the monthly window
+// on ties since it resets later, or nil if the application is unlimited
+func (u *Usage) Binding() *WindowUsage {
+	var binding *WindowUsage
+	for _, window := range []*WindowUsage{&u.Monthly, &u.Daily} {
+		if window.Remaining == nil {
+			continue
+		}
+		if binding == nil || *window.Remaining < *binding.Remaining {
+			binding = window
+		}
+	}
+	return binding
+}
+
+// Enforcer checks and records the usage of applications against their quotas
+type Enforcer struct {
+	limits LimitsSource
+	store  Store
+	now    func() time.Time
+}
+
+// NewEnforcer creates an enforcer reading quotas from limits and counting records in store
+func NewEnforcer(limits LimitsSource, store Store) *Enforcer {
+	return &Enforcer{limits: limits, store: store, now: time.Now}
+}
+
+// Usage returns the usage of an application in the current day and month
+func (e *Enforcer) Usage(ctx context.Context, applicationID string) (*Usage, error) {
+	limits, err := e.limits.GetLimits(ctx, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota of application %s: %w", applicationID, err)
+	}
+
+	now := e.now().UTC()
+	usage := &Usage{ApplicationID: applicationID}
+	for _, window := range []struct {
+		usage *WindowUsage
+		name  string
+		limit *int64
+	}{
+		{&usage.Daily, WindowDaily, limits.DailyRecordQuota},
+		{&usage.Monthly, WindowMonthly, limits.MonthlyRecordQuota},
+	} {
+		period, resetsAt := currentPeriod(window.name, now)
+		used, err := e.store.Get(ctx, applicationID, period)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s usage of application %s: %w", window.name, applicationID, err)
+		}
+		*window.usage = WindowUsage{Window: window.name, Period: period, Used: used, Limit: window.limit, ResetsAt: resetsAt}
+		window.usage.updateRemaining()
+	}
+	return usage, nil
+}
+
+// Record counts records returned to an application against the current day and month
+func (e *Enforcer) Record(ctx context.Context, applicationID string, records int64) error {
+	if records <= 0 {
+		return nil
+	}
+	now := e.now().UTC()
+	for _, window := range []string{WindowDaily, WindowMonthly} {
+		period, _ := currentPeriod(window, now)
+		if _, err := e.store.Add(ctx, applicationID, period, records); err != nil {
+			return fmt.Errorf("failed to record %s usage of application %s: %w", window, applicationID, err)
+		}
+	}
+	return nil
+}
+
+// currentPeriod returns the key of the window's period containing now, which must be in UTC, and when
+// the period ends
+func currentPeriod(window string, now time.Time) (string, time.Time) {
+	if window == WindowDaily {
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return window + ":" + start.Format("2006-01-02"), start.AddDate(0, 0, 1)
+	}
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return window + ":" + start.Format("2006-01"), start.AddDate(0, 1, 0)
+}
+
+// CountRecords counts the records in a GraphQL response's data: a root field returning a list counts
+// its non-null elements, any other non-null root field counts as one record
+func CountRecords(data map[string]interface{}) int64 {
+	var count int64
+	for _, value := range data {
+		count += countValue(value)
+	}
+	return count
+}
+
+func countValue(value interface{}) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case []interface{}:
+		var count int64
+		for _, item := range v {
+			if item != nil {
+				count++
+			}
+		}
+		return count
+	default:
+		return 1
+	}
+}
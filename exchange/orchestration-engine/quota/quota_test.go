@@ -0,0 +1,128 @@
+package quota
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticLimits Limits
+
+func (l staticLimits) GetLimits(_ context.Context, applicationID string) (*Limits, error) {
+	limits := Limits(l)
+	limits.ApplicationID = applicationID
+	return &limits, nil
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestEnforcer(t *testing.T) {
+	now := time.Date(2025, time.January, 31, 22, 30, 0, 0, time.UTC)
+	enforcer := NewEnforcer(staticLimits{DailyRecordQuota: int64Ptr(10), MonthlyRecordQuota: int64Ptr(100)}, NewMemoryStore())
+	enforcer.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	usage, err := enforcer.Usage(ctx, "app-1")
+	require.NoError(t, err)
+	assert.False(t, usage.Exceeded())
+	assert.Equal(t, "daily:2025-01-31", usage.Daily.Period)
+	assert.Equal(t, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC), usage.Daily.ResetsAt)
+	assert.Equal(t, "monthly:2025-01", usage.Monthly.Period)
+	assert.Equal(t, int64(10), *usage.Binding().Remaining)
+	assert.Equal(t, WindowDaily, usage.Binding().Window)
+
+	require.NoError(t, enforcer.Record(ctx, "app-1", 12))
+	usage, err = enforcer.Usage(ctx, "app-1")
+	require.NoError(t, err)
+	assert.True(t, usage.Exceeded())
+	assert.Equal(t, int64(0), *usage.Daily.Remaining)
+	assert.Equal(t, int64(88), *usage.Monthly.Remaining)
+
+	// Both windows reset when a new month starts
+	now = time.Date(2025, time.February, 1, 0, 1, 0, 0, time.UTC)
+	usage, err = enforcer.Usage(ctx, "app-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), usage.Daily.Used)
+	assert.Equal(t, int64(0), usage.Monthly.Used)
+
+	now = time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+	usage, err = enforcer.Usage(ctx, "app-2")
+	require.NoError(t, err)
+	usage.Count(5)
+	assert.Equal(t, int64(5), *usage.Daily.Remaining)
+}
+
+func TestEnforcer_Unlimited(t *testing.T) {
+	enforcer := NewEnforcer(staticLimits{}, NewMemoryStore())
+	require.NoError(t, enforcer.Record(context.Background(), "app-1", 1000))
+
+	usage, err := enforcer.Usage(context.Background(), "app-1")
+	require.NoError(t, err)
+	assert.False(t, usage.Exceeded())
+	assert.Nil(t, usage.Binding())
+	assert.Equal(t, int64(1000), usage.Monthly.Used)
+}
+
+func TestMemoryStore_DropsEarlierPeriods(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	_, _ = store.Add(ctx, "app-1", "daily:2025-01-30", 3)
+	_, _ = store.Add(ctx, "app-1", "monthly:2025-01", 3)
+	total, err := store.Add(ctx, "app-1", "daily:2025-01-31", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	assert.Len(t, store.counts, 2)
+	count, _ := store.Get(ctx, "app-1", "monthly:2025-01")
+	assert.Equal(t, int64(3), count)
+}
+
+func TestCountRecords(t *testing.T) {
+	assert.Equal(t, int64(0), CountRecords(nil))
+	assert.Equal(t, int64(4), CountRecords(map[string]interface{}{
+		"personInfo":  map[string]interface{}{"fullName": "Jane"},
+		"vehicles":    []interface{}{map[string]interface{}{"id": 1}, nil, map[string]interface{}{"id": 2}},
+		"count":       7,
+		"missingInfo": nil,
+	}))
+}
+
+func TestPortalClient(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/internal/api/v1/applications/app-1/quota":
+			_, _ = w.Write([]byte(`{"applicationId":"app-1","dailyRecordQuota":10,"monthlyRecordQuota":null}`))
+		case "/internal/api/v1/applications/unknown/quota":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client := NewPortalClient(server.URL, time.Hour)
+	ctx := context.Background()
+
+	limits, err := client.GetLimits(ctx, "app-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), *limits.DailyRecordQuota)
+	assert.Nil(t, limits.MonthlyRecordQuota)
+	_, err = client.GetLimits(ctx, "app-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests, "quotas are cached")
+
+	limits, err = client.GetLimits(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, limits.DailyRecordQuota)
+
+	_, err = client.GetLimits(ctx, "broken")
+	assert.Error(t, err)
+}
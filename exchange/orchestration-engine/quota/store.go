@@ -0,0 +1,49 @@
+package quota
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// MemoryStore keeps record counts in memory, per process
+type MemoryStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counts: make(map[string]int64)}
+}
+
+// Get returns the records counted for the application in the period
+func (s *MemoryStore) Get(_ context.Context, applicationID, period string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[applicationID+"/"+period], nil
+}
+
+// Add adds records to the application's count for the period and returns the new count. Counts of
+// earlier periods are dropped once a later one is recorded.
+func (s *MemoryStore) Add(_ context.Context, applicationID, period string, records int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := applicationID + "/" + period
+	if _, ok := s.counts[key]; !ok {
+		s.dropEarlierPeriods(applicationID, period)
+	}
+	s.counts[key] += records
+	return s.counts[key], nil
+}
+
+// dropEarlierPeriods removes the application's counts of other periods of period's window
+func (s *MemoryStore) dropEarlierPeriods(applicationID, period string) {
+	window, _, _ := strings.Cut(period, ":")
+	prefix := applicationID + "/" + window + ":"
+	for key := range s.counts {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.counts, key)
+		}
+	}
+}
@@ -1,28 +1,58 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go/token"
 	"net/http"
 	"os"
 	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/activation"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/appcontext"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/auth"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/canary"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/codes"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/contract"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/database"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/dataexport"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/featureflags"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/federator"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/handlers"
+	oeerrors "github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/internals/errors"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/logger"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/querylog"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/quota"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/services"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/signing"
 	"github.com/go-chi/chi/v5"
+	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
 )
 
 type Response struct {
 	Message string `json:"message"`
 }
 
+// errorResponse is the body of the engine's JSON error responses
+type errorResponse struct {
+	Code      string `json:"code"`
+	Error     string `json:"error"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
 const DefaultPort = "4000"
 
 // RunServer starts an HTTP server with graceful shutdown support.
@@ -44,7 +74,7 @@ func RunServer(ctx context.Context, f *federator.Federator) {
 	// Create HTTP server with proper configuration
 	srv := &http.Server{
 		Addr:    port,
-		Handler: corsMiddleware(mux),
+		Handler: corsMiddleware(requestid.Middleware(monitoring.TraceIDMiddleware(mux))),
 	}
 
 	// Channel to signal server errors
@@ -80,6 +110,21 @@ func RunServer(ctx context.Context, f *federator.Federator) {
 			logger.Log.Info("Server stopped gracefully")
 		}
 	}
+
+	// Stop activating schema versions, letting a running activation finish
+	if f.SchemaActivations != nil {
+		f.SchemaActivations.Stop()
+	}
+
+	// Stop running data exports; an interrupted job is run again by the next replica to claim it
+	if f.DataExports != nil {
+		f.DataExports.Stop()
+	}
+
+	// Write the queries logged before the shutdown
+	if f.QueryLog != nil {
+		f.QueryLog.Close()
+	}
 }
 
 // SetupRouter initializes the router and registers all endpoints
@@ -97,8 +142,19 @@ func SetupRouter(f *federator.Federator) *chi.Mux {
 
 	// Initialize schema service and handler
 	var schemaService handlers.SchemaService
+	var activationService handlers.SchemaActivationService
+	var canaryService handlers.SchemaCanaryService
 	if schemaDB != nil {
-		schemaService = services.NewSchemaService(schemaDB)
+		service := services.NewSchemaService(schemaDB)
+		schemaService = service
+		if f.SchemaActivations == nil {
+			f.SchemaActivations = newSchemaActivationScheduler(service, schemaDB)
+		}
+		activationService = f.SchemaActivations
+		if f.SchemaCanary == nil {
+			f.SchemaCanary = canary.NewRouter(schemaDB.SchemaCanaryDB(), service, canary.DefaultRefreshInterval)
+		}
+		canaryService = f.SchemaCanary
 	} else {
 		// Fallback to in-memory service if database is not available
 		schemaService = nil
@@ -106,23 +162,62 @@ func SetupRouter(f *federator.Federator) *chi.Mux {
 	}
 
 	schemaHandler := handlers.NewSchemaHandler(schemaService)
+	schemaActivationHandler := handlers.NewSchemaActivationHandler(activationService, schemaHandler.ActivateSchema)
+	schemaCanaryHandler := handlers.NewSchemaCanaryHandler(canaryService)
 
 	// Set the schema service in the federator
 	f.SchemaService = schemaService
-	// /health route
-	mux.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		resp := Response{Message: "OpenDIF Server is Healthy!"}
-		w.Header().Set("Content-Type", "application/json")
-		err := json.NewEncoder(w).Encode(resp)
+
+	if f.Quotas == nil && f.Configs.QuotaConfig.PortalURL != "" {
+		f.Quotas = newQuotaEnforcer(f.Configs.QuotaConfig, schemaDB)
+	}
+	if f.Applications == nil && f.Configs.AppContext.PortalURL != "" {
+		f.Applications = newApplicationSource(f.Configs.AppContext)
+	}
+	if f.Codes == nil {
+		f.Codes = newCodeNormalizer(schemaDB)
+	}
+	codeMappingHandler := handlers.NewCodeMappingHandler(f.Codes)
+	if f.Maintenance == nil {
+		f.Maintenance = newMaintenanceSchedule(schemaDB)
+	}
+	maintenanceHandler := handlers.NewMaintenanceHandler(f.Maintenance)
+	if f.FeatureFlags == nil {
+		f.FeatureFlags = newFeatureFlagEvaluator(schemaDB)
+	}
+	featureFlagHandler := handlers.NewFeatureFlagHandler(f.FeatureFlags)
+	if f.QueryLog == nil && !f.Configs.QueryLog.Disabled {
+		f.QueryLog = newQueryRecorder(f.Configs.QueryLog, schemaDB)
+	}
+	var queryLogService handlers.QueryLogService
+	if f.QueryLog != nil {
+		queryLogService = f.QueryLog
+	}
+	queryLogHandler := handlers.NewQueryLogHandler(queryLogService)
+	if f.DataExports == nil && f.Configs.DataExport.Bucket != "" {
+		f.DataExports = newDataExportService(f, schemaDB)
+	}
+	var dataExportService handlers.DataExportService
+	if f.DataExports != nil {
+		dataExportService = f.DataExports
+	}
+	dataExportHandler := handlers.NewDataExportHandler(dataExportService, func(w http.ResponseWriter, r *http.Request) *auth.ConsumerAssertion {
+		consumerAssertion, err := auth.GetConsumerJwtFromTokenWithValidator(f.Configs.Environment, &f.Configs.JWT, f.Configs.TrustUpstream, r, f.TokenValidator)
 		if err != nil {
-			return
+			logger.Log.ErrorContext(r.Context(), "Failed to get consumer JWT from token", "error", err)
+			http.Error(w, "Unauthorized: invalid or expired token", http.StatusUnauthorized)
+			return nil
 		}
+		if _, rejection := f.ResolveApplication(r.Context(), consumerAssertion); rejection != nil {
+			writeError(w, http.StatusForbidden, rejection.Code, rejection.Message)
+			return nil
+		}
+		return consumerAssertion
 	})
 
+	// /health, /health/live and /health/ready routes
+	newHealthChecker(f, schemaDB).RegisterRoutes(mux)
+
 	// Schema management routes
 	mux.Get("/sdl", schemaHandler.GetActiveSchema)
 	mux.Post("/sdl", schemaHandler.CreateSchema)
@@ -130,15 +225,49 @@ func SetupRouter(f *federator.Federator) *chi.Mux {
 	mux.Post("/sdl/validate", schemaHandler.ValidateSDL)
 	mux.Post("/sdl/check-compatibility", schemaHandler.CheckCompatibility)
 
-	// Handle activation endpoint with proper path matching
-	mux.Post("/sdl/versions/{version}/activate", schemaHandler.ActivateSchema)
+	// Handle activation endpoint with proper path matching; activations with an at time are scheduled
+	mux.Post("/sdl/versions/{version}/activate", schemaActivationHandler.ActivateSchema)
+	mux.Get("/sdl/activations", schemaActivationHandler.GetActivations)
+	mux.Delete("/sdl/activations/{id}", schemaActivationHandler.CancelActivation)
+
+	// Schema canary routes, routing part of the traffic to a candidate version
+	mux.Get("/sdl/canary", schemaCanaryHandler.GetCanary)
+	mux.Post("/sdl/canary", schemaCanaryHandler.StartCanary)
+	mux.Delete("/sdl/canary", schemaCanaryHandler.StopCanary)
+
+	// Code mapping management routes
+	mux.Get("/code-mappings", codeMappingHandler.GetCodeMappings)
+	mux.Put("/code-mappings/{providerKey}/{codeList}/{providerCode}", codeMappingHandler.PutCodeMapping)
+	mux.Delete("/code-mappings/{providerKey}/{codeList}/{providerCode}", codeMappingHandler.DeleteCodeMapping)
+
+	// Provider maintenance window routes
+	mux.Get("/maintenance-windows", maintenanceHandler.GetMaintenanceWindows)
+	mux.Post("/maintenance-windows", maintenanceHandler.CreateMaintenanceWindow)
+	mux.Delete("/maintenance-windows/{id}", maintenanceHandler.DeleteMaintenanceWindow)
+
+	// Query log routes, for slow query and usage pattern analysis
+	mux.Get("/admin/queries", queryLogHandler.GetQueries)
+	mux.Get("/admin/queries/top", queryLogHandler.GetTopQueries)
+
+	// Feature flag routes, gating federation behaviors per consumer application
+	mux.Get("/admin/feature-flags", featureFlagHandler.GetFeatureFlags)
+	mux.Put("/admin/feature-flags/{key}", featureFlagHandler.PutFeatureFlag)
+	mux.Delete("/admin/feature-flags/{key}", featureFlagHandler.DeleteFeatureFlag)
+
+	// Data export routes, registering export queries and releasing the archives of jobs
+	mux.Get("/admin/export-queries", dataExportHandler.GetExportQueries)
+	mux.Post("/admin/export-queries", dataExportHandler.RegisterExportQuery)
+	mux.Get("/admin/exports", dataExportHandler.GetExports)
+	mux.Get("/admin/exports/{id}", dataExportHandler.GetExport)
+	mux.Post("/admin/exports/{id}/approve", dataExportHandler.ApproveExport)
+	mux.Post("/admin/exports/{id}/reject", dataExportHandler.RejectExport)
 
 	// Publicly accessible Endpoints
 	mux.Post("/public/graphql", func(w http.ResponseWriter, r *http.Request) {
 		// Parse request body
 		var req graphql.Request
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			logger.Log.Error("Failed to decode request body", "error", err)
+			logger.Log.ErrorContext(r.Context(), "Failed to decode request body", "error", err)
 			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
 			return
 		}
@@ -146,23 +275,62 @@ func SetupRouter(f *federator.Federator) *chi.Mux {
 		// decode the token using the cached TokenValidator
 		consumerAssertion, err := auth.GetConsumerJwtFromTokenWithValidator(f.Configs.Environment, &f.Configs.JWT, f.Configs.TrustUpstream, r, f.TokenValidator)
 		if err != nil {
-			logger.Log.Error("Failed to get consumer JWT from token", "error", err)
+			logger.Log.ErrorContext(r.Context(), "Failed to get consumer JWT from token", "error", err)
 			// Return generic error to client to avoid exposing internal details
 			http.Error(w, "Unauthorized: invalid or expired token", http.StatusUnauthorized)
 			return
 		}
 
+		ctx, rejection := f.ResolveApplication(r.Context(), consumerAssertion)
+		if rejection != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(graphql.Response{
+				Errors: []interface{}{
+					map[string]interface{}{
+						"message": rejection.Message,
+						"extensions": map[string]interface{}{
+							"code":      rejection.Code,
+							"requestId": requestid.FromContext(r.Context()),
+						},
+					},
+				},
+			})
+			return
+		}
+		r = r.WithContext(ctx)
+
+		usage := f.CheckQuota(r.Context(), consumerAssertion)
+		if usage != nil && usage.Exceeded() {
+			setQuotaHeaders(w, usage)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(graphql.Response{
+				Errors: []interface{}{
+					map[string]interface{}{
+						"message": "Record quota exceeded for this application",
+						"extensions": map[string]interface{}{
+							"code":      oeerrors.CodeQuotaExceeded,
+							"requestId": requestid.FromContext(r.Context()),
+						},
+					},
+				},
+			})
+			return
+		}
+
 		// Add panic recovery for federator calls
 		var response graphql.Response
 		func() {
 			defer func() {
-				if r := recover(); r != nil {
-					logger.Log.Error("Panic in FederateQuery", "panic", r, "stack", string(debug.Stack()))
+				if rec := recover(); rec != nil {
+					logger.Log.ErrorContext(r.Context(), "Panic in FederateQuery", "panic", rec, "stack", string(debug.Stack()))
 					response = graphql.Response{
 						Data: nil,
 						Errors: []interface{}{
 							map[string]interface{}{
-								"message": fmt.Sprintf("Internal server error: %v", r),
+								"message":    fmt.Sprintf("Internal server error: %v", rec),
+								"extensions": map[string]interface{}{"requestId": requestid.FromContext(r.Context())},
 							},
 						},
 					}
@@ -171,21 +339,458 @@ func SetupRouter(f *federator.Federator) *chi.Mux {
 			response = f.FederateQuery(r.Context(), req, consumerAssertion)
 		}()
 
+		f.RecordUsage(r.Context(), consumerAssertion, usage, response)
+		setQuotaHeaders(w, usage)
+
+		// The body is encoded before it is written so that it can be signed
+		var body bytes.Buffer
+		if err := json.NewEncoder(&body).Encode(response); err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to encode response", "error", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if f.ResponseSigner != nil {
+			signature, err := f.ResponseSigner.Sign(body.Bytes())
+			if err != nil {
+				logger.Log.ErrorContext(r.Context(), "Failed to sign response", "error", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(signing.Header, signature)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		// Set content type to application/json
+		if _, err := w.Write(body.Bytes()); err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to write response", "error", err)
+		}
+	})
 
+	// Keys consumers verify response signatures with
+	mux.Get("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		if f.ResponseSigner == nil {
+			http.Error(w, "Response signing is not enabled", http.StatusNotFound)
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(f.ResponseSigner.JWKS()); err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to write response", "error", err)
+		}
+	})
 
-		err = json.NewEncoder(w).Encode(response)
+	// Checks a query against policies and consent without executing it
+	mux.Post("/public/graphql/preflight", func(w http.ResponseWriter, r *http.Request) {
+		var req federator.PreflightRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to decode request body", "error", err)
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		consumerAssertion, err := auth.GetConsumerJwtFromTokenWithValidator(f.Configs.Environment, &f.Configs.JWT, f.Configs.TrustUpstream, r, f.TokenValidator)
+		if err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to get consumer JWT from token", "error", err)
+			http.Error(w, "Unauthorized: invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx, rejection := f.ResolveApplication(r.Context(), consumerAssertion)
+		if rejection != nil {
+			writeError(w, http.StatusForbidden, rejection.Code, rejection.Message)
+			return
+		}
+		r = r.WithContext(ctx)
+
+		response, err := f.Preflight(r.Context(), req, consumerAssertion)
+		if err != nil {
+			var preflightErr *federator.PreflightError
+			if !errors.As(err, &preflightErr) {
+				preflightErr = &federator.PreflightError{Status: http.StatusInternalServerError, Code: oeerrors.CodeInternalError, Message: "Internal server error"}
+			}
+			writeError(w, preflightErr.Status, preflightErr.Code, preflightErr.Message)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to write response", "error", err)
+		}
+	})
+
+	// Typed client artifacts for the part of the schema the application may read
+	mux.Get("/public/contract", func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "json" && format != "typescript" && format != "go" {
+			writeError(w, http.StatusBadRequest, oeerrors.CodeBadRequest, "format must be json, typescript or go")
+			return
+		}
+		goPackage := r.URL.Query().Get("package")
+		if goPackage != "" && !token.IsIdentifier(goPackage) {
+			writeError(w, http.StatusBadRequest, oeerrors.CodeBadRequest, "package must be a Go identifier")
+			return
+		}
+
+		consumerAssertion, err := auth.GetConsumerJwtFromTokenWithValidator(f.Configs.Environment, &f.Configs.JWT, f.Configs.TrustUpstream, r, f.TokenValidator)
 		if err != nil {
-			logger.Log.Error("Failed to write response", "error", err)
+			logger.Log.ErrorContext(r.Context(), "Failed to get consumer JWT from token", "error", err)
+			http.Error(w, "Unauthorized: invalid or expired token", http.StatusUnauthorized)
 			return
 		}
+
+		ctx, rejection := f.ResolveApplication(r.Context(), consumerAssertion)
+		if rejection != nil {
+			writeError(w, http.StatusForbidden, rejection.Code, rejection.Message)
+			return
+		}
+		r = r.WithContext(ctx)
+
+		generated, err := f.Contract(r.Context(), consumerAssertion, contract.Options{GoPackage: goPackage})
+		switch {
+		case errors.Is(err, federator.ErrContractSchemaUnavailable):
+			writeError(w, http.StatusServiceUnavailable, oeerrors.CodeInternalError, "No active schema found")
+			return
+		case errors.Is(err, federator.ErrContractPolicyCheck):
+			writeError(w, http.StatusBadGateway, oeerrors.CodePDPError, "Authorization check failed")
+			return
+		case err != nil:
+			logger.Log.ErrorContext(r.Context(), "Failed to generate contract", "error", err)
+			writeError(w, http.StatusInternalServerError, oeerrors.CodeInternalError, "Internal server error")
+			return
+		}
+
+		switch format {
+		case "typescript":
+			w.Header().Set("Content-Type", "application/typescript; charset=utf-8")
+			_, err = w.Write([]byte(generated.TypeScript))
+		case "go":
+			w.Header().Set("Content-Type", "text/x-go; charset=utf-8")
+			_, err = w.Write([]byte(generated.Go))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			err = json.NewEncoder(w).Encode(generated)
+		}
+		if err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to write response", "error", err)
+		}
+	})
+
+	// Bulk data exports of consumer applications
+	mux.Post("/public/exports", dataExportHandler.SubmitExport)
+	mux.Get("/public/exports", dataExportHandler.GetConsumerExports)
+	mux.Get("/public/exports/{id}", dataExportHandler.GetConsumerExport)
+	mux.Get("/public/exports/{id}/download", dataExportHandler.GetExportDownload)
+
+	// Usage of an application against its record quotas, for the portal
+	mux.Get("/usage/applications/{applicationId}", func(w http.ResponseWriter, r *http.Request) {
+		if f.Quotas == nil {
+			writeError(w, http.StatusServiceUnavailable, oeerrors.CodeQuotaDisabled, "Usage tracking is not enabled")
+			return
+		}
+
+		usage, err := f.Quotas.Usage(r.Context(), chi.URLParam(r, "applicationId"))
+		if err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to get application usage", "error", err)
+			writeError(w, http.StatusBadGateway, oeerrors.CodeInternalError, "Failed to get application usage")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(usage); err != nil {
+			logger.Log.ErrorContext(r.Context(), "Failed to write response", "error", err)
+		}
 	})
 
 	return mux
 }
 
+// newHealthChecker creates the health checks of the engine. Queries are answered without the
+// database, and fail only for the fields that need an unreachable PDP, Consent Engine, portal or
+// provider, so none of them makes the engine unready.
+func newHealthChecker(f *federator.Federator, schemaDB *database.SchemaDB) *health.Checker {
+	checker := health.NewChecker("orchestration-engine")
+	if schemaDB != nil {
+		checker.RegisterOptional("database", health.PingCheck(schemaDB.DB()))
+	} else {
+		checker.RegisterOptional("database", func(context.Context) error {
+			return errors.New("database not connected, schema management disabled")
+		})
+	}
+	if url := f.Configs.PdpConfig.ClientURL; url != "" {
+		checker.RegisterOptional("policy_decision_point", health.HTTPCheck(nil, url+"/health/live"))
+	}
+	if url := f.Configs.CeConfig.ClientURL; url != "" {
+		checker.RegisterOptional("consent_engine", health.HTTPCheck(nil, url+"/health/live"))
+	}
+	portalURL := f.Configs.QuotaConfig.PortalURL
+	if portalURL == "" {
+		portalURL = f.Configs.AppContext.PortalURL
+	}
+	if portalURL != "" {
+		checker.RegisterOptional("portal", health.HTTPCheck(nil, portalURL+"/health/live"))
+	}
+	if f.ProviderHandler != nil {
+		checker.RegisterOptional("providers", providerCheck(f))
+	}
+	return checker
+}
+
+// providerCheck fails while the last request to a provider failed. Providers in a maintenance
+// window are skipped, so planned downtime does not raise alerts.
+func providerCheck(f *federator.Federator) health.CheckFunc {
+	return func(context.Context) error {
+		failures := f.ProviderHandler.Failures()
+		providerKeys := make([]string, 0, len(failures))
+		for providerKey := range failures {
+			if f.Maintenance != nil && f.Maintenance.Active(providerKey, time.Now()) != nil {
+				continue
+			}
+			providerKeys = append(providerKeys, providerKey)
+		}
+		if len(providerKeys) == 0 {
+			return nil
+		}
+		sort.Strings(providerKeys)
+		failing := make([]string, len(providerKeys))
+		for i, providerKey := range providerKeys {
+			failing[i] = fmt.Sprintf("%s (since %s: %s)", providerKey, failures[providerKey].Since.Format(time.RFC3339), failures[providerKey].Error)
+		}
+		return fmt.Errorf("last request failed for providers: %s", strings.Join(failing, ", "))
+	}
+}
+
+// newQuotaEnforcer creates the quota enforcer, counting records in the database if it is available
+func newQuotaEnforcer(cfg configs.QuotaConfig, schemaDB *database.SchemaDB) *quota.Enforcer {
+	ttl := quota.DefaultCacheTTL
+	if cfg.CacheTTL != "" {
+		parsed, err := time.ParseDuration(cfg.CacheTTL)
+		if err != nil {
+			logger.Log.Warn("Invalid quota cache TTL, using default", "cacheTtl", cfg.CacheTTL, "error", err)
+		} else {
+			ttl = parsed
+		}
+	}
+
+	var store quota.Store
+	if schemaDB != nil {
+		store = schemaDB.UsageDB()
+	} else {
+		logger.Log.Warn("Running without database - application usage is counted in memory")
+		store = quota.NewMemoryStore()
+	}
+	logger.Log.Info("Application record quotas enabled", "portalUrl", cfg.PortalURL)
+	return quota.NewEnforcer(quota.NewPortalClient(cfg.PortalURL, ttl), store)
+}
+
+// newCodeNormalizer creates the code normalizer, keeping the mappings in the database if it is available
+func newCodeNormalizer(schemaDB *database.SchemaDB) *codes.Normalizer {
+	var store codes.Store
+	if schemaDB != nil {
+		store = schemaDB.CodeMappingDB()
+	} else {
+		logger.Log.Warn("Running without database - code mappings are kept in memory")
+		store = codes.NewMemoryStore()
+	}
+
+	normalizer := codes.NewNormalizer(store, codes.DefaultRefreshInterval)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := normalizer.Refresh(ctx); err != nil {
+		logger.Log.Warn("Failed to load code mappings, provider codes are kept until they load", "error", err)
+	}
+	return normalizer
+}
+
+// newMaintenanceSchedule creates the provider maintenance schedule, keeping the windows in the
+// database if it is available
+func newMaintenanceSchedule(schemaDB *database.SchemaDB) *maintenance.Schedule {
+	var store maintenance.Store
+	if schemaDB != nil {
+		store = schemaDB.MaintenanceWindowDB()
+	} else {
+		logger.Log.Warn("Running without database - maintenance windows are kept in memory")
+		store = maintenance.NewMemoryStore()
+	}
+
+	schedule := maintenance.NewSchedule(store, maintenance.DefaultRefreshInterval)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := schedule.Refresh(ctx); err != nil {
+		logger.Log.Warn("Failed to load maintenance windows, providers are called until they load", "error", err)
+	}
+	return schedule
+}
+
+// newFeatureFlagEvaluator creates the feature flag evaluator, keeping the flags in the database if it
+// is available
+func newFeatureFlagEvaluator(schemaDB *database.SchemaDB) *featureflags.Evaluator {
+	var store featureflags.Store
+	if schemaDB != nil {
+		store = schemaDB.FeatureFlagDB()
+	} else {
+		logger.Log.Warn("Running without database - feature flags are kept in memory")
+		store = featureflags.NewMemoryStore()
+	}
+
+	evaluator := featureflags.NewEvaluator(store, featureflags.DefaultRefreshInterval)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := evaluator.Refresh(ctx); err != nil {
+		logger.Log.Warn("Failed to load feature flags, gated behaviors are off until they load", "error", err)
+	}
+	return evaluator
+}
+
+// newSchemaActivationScheduler creates the scheduler of schema activations and starts it
+func newSchemaActivationScheduler(schemaService *services.SchemaService, schemaDB *database.SchemaDB) *activation.Scheduler {
+	scheduler := activation.NewScheduler(schemaDB.SchemaActivationDB(), schemaService, schemaService.ContractTests(),
+		activation.DefaultCheckInterval)
+	scheduler.Start()
+	return scheduler
+}
+
+// newQueryRecorder creates the query log, keeping the executed queries in the database if it is
+// available
+func newQueryRecorder(cfg configs.QueryLogConfig, schemaDB *database.SchemaDB) *querylog.Recorder {
+	var store querylog.Store
+	if schemaDB != nil {
+		store = schemaDB.QueryLogDB()
+	} else {
+		logger.Log.Warn("Running without database - the query log is kept in memory")
+		store = querylog.NewMemoryStore(querylog.DefaultMemoryEntries)
+	}
+
+	var retention time.Duration
+	if cfg.Retention != "" {
+		parsed, err := time.ParseDuration(cfg.Retention)
+		if err != nil || parsed <= 0 {
+			logger.Log.Warn("Invalid query log retention, using the default", "retention", cfg.Retention)
+		} else {
+			retention = parsed
+		}
+	}
+	return querylog.NewRecorder(store, retention, cfg.BufferSize)
+}
+
+// newDataExportService creates the data export service and starts running jobs, keeping the jobs in
+// the database if it is available. Data exports stay disabled if their configuration is invalid.
+func newDataExportService(f *federator.Federator, schemaDB *database.SchemaDB) *dataexport.Service {
+	cfg := f.Configs.DataExport
+	key, err := os.ReadFile(cfg.EncryptionKeyFile)
+	if err != nil {
+		logger.Log.Error("Failed to read data export encryption key, data exports are disabled", "error", err)
+		return nil
+	}
+	masterKey, err := hex.DecodeString(strings.TrimSpace(string(key)))
+	if err != nil {
+		logger.Log.Error("Data export encryption key is not hex encoded, data exports are disabled", "error", err)
+		return nil
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	storage, err := dataexport.NewS3Storage(endpoint, cfg.Bucket, region, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.PathStyle)
+	if err != nil {
+		logger.Log.Error("Invalid data export storage, data exports are disabled", "error", err)
+		return nil
+	}
+
+	var store dataexport.Store
+	if schemaDB != nil {
+		store = schemaDB.DataExportDB()
+	} else {
+		logger.Log.Warn("Running without database - data export jobs are kept in memory")
+		store = dataexport.NewMemoryStore()
+	}
+
+	opts := dataexport.Options{BatchSize: cfg.BatchSize, MaxRows: cfg.MaxRows}
+	if cfg.LinkTTL != "" {
+		parsed, err := time.ParseDuration(cfg.LinkTTL)
+		if err != nil || parsed <= 0 {
+			logger.Log.Warn("Invalid data export link TTL, using the default", "linkTtl", cfg.LinkTTL)
+		} else {
+			opts.LinkTTL = parsed
+		}
+	}
+
+	// Jobs run their queries as the application that submitted them, which must still be allowed to query
+	run := func(ctx context.Context, request graphql.Request, consumer *auth.ConsumerAssertion) graphql.Response {
+		ctx, rejection := f.ResolveApplication(ctx, consumer)
+		if rejection != nil {
+			return graphql.Response{Errors: []interface{}{
+				map[string]interface{}{"message": rejection.Message, "extensions": map[string]interface{}{"code": rejection.Code}},
+			}}
+		}
+		return f.FederateQuery(ctx, request, consumer)
+	}
+
+	service, err := dataexport.NewService(store, storage, masterKey, run, opts)
+	if err != nil {
+		logger.Log.Error("Invalid data export configuration, data exports are disabled", "error", err)
+		return nil
+	}
+	service.Start()
+	logger.Log.Info("Data exports enabled", "bucket", cfg.Bucket)
+	return service
+}
+
+// newApplicationSource creates the client resolving the applications of consumer tokens from the portal
+func newApplicationSource(cfg configs.AppContextConfig) *appcontext.PortalClient {
+	ttl := appcontext.DefaultCacheTTL
+	if cfg.CacheTTL != "" {
+		parsed, err := time.ParseDuration(cfg.CacheTTL)
+		if err != nil {
+			logger.Log.Warn("Invalid application context cache TTL, using default", "cacheTtl", cfg.CacheTTL, "error", err)
+		} else {
+			ttl = parsed
+		}
+	}
+	logger.Log.Info("Application context enabled", "portalUrl", cfg.PortalURL)
+	return appcontext.NewPortalClient(cfg.PortalURL, ttl)
+}
+
+// setQuotaHeaders reports the application's most restrictive quota window, telling clients whose
+// quota is exceeded when to retry
+func setQuotaHeaders(w http.ResponseWriter, usage *quota.Usage) {
+	if usage == nil {
+		return
+	}
+	window := usage.Binding()
+	if window == nil {
+		return
+	}
+	w.Header().Set("X-Quota-Limit", strconv.FormatInt(*window.Limit, 10))
+	w.Header().Set("X-Quota-Remaining", strconv.FormatInt(*window.Remaining, 10))
+	w.Header().Set("X-Quota-Reset", strconv.FormatInt(window.ResetsAt.Unix(), 10))
+	w.Header().Set("X-Quota-Window", window.Window)
+	if usage.Exceeded() {
+		retryAfter := int64(time.Until(latestReset(usage)).Seconds()) + 1
+		w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+	}
+}
+
+// latestReset returns when the last of the application's exceeded quota windows resets
+func latestReset(usage *quota.Usage) time.Time {
+	var reset time.Time
+	for _, window := range []quota.WindowUsage{usage.Daily, usage.Monthly} {
+		if window.Exceeded() && window.ResetsAt.After(reset) {
+			reset = window.ResetsAt
+		}
+	}
+	return reset
+}
+
+// writeError sends a JSON error response carrying the request ID
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Code: code, Error: message, RequestID: requestid.FromResponse(w)})
+}
+
 // corsMiddleware sets CORS headers
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -194,7 +799,8 @@ func corsMiddleware(next http.Handler) http.Handler {
 		// Allow specific methods
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		// Allow specific headers
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, X-Trace-ID, X-Request-ID, traceparent")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Trace-ID, X-Request-ID, X-Quota-Limit, X-Quota-Remaining, X-Quota-Reset, X-Quota-Window, Retry-After, X-JWS-Signature")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
 
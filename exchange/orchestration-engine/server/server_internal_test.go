@@ -3,15 +3,28 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/appcontext"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/configs"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/contract"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/federator"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/maintenance"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
 	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/provider"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/quota"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/signing"
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,13 +42,18 @@ func TestSetupRouter_Health(t *testing.T) {
 
 	mux := SetupRouter(f)
 
-	req := httptest.NewRequest(http.MethodGet, "/health", nil)
-	w := httptest.NewRecorder()
+	for _, path := range []string{"/health", "/health/live", "/health/ready"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
 
-	mux.ServeHTTP(w, req)
+		mux.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "OpenDIF Server is Healthy!")
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		var report health.Report
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, "orchestration-engine", report.Service)
+		assert.NotEqual(t, health.StatusDown, report.Status, path)
+	}
 }
 
 func TestSetupRouter_SDL_Endpoints(t *testing.T) {
@@ -122,3 +140,249 @@ func TestSetupRouter_PublicGraphQL_Unauthorized(t *testing.T) {
 	// Should be Unauthorized because GetConsumerJwtFromToken will fail
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestSetupRouter_Preflight(t *testing.T) {
+	cfg := &configs.Config{
+		Environment:   "test",
+		TrustUpstream: true, // Trust upstream to avoid JWT validation requirements
+	}
+	f, err := federator.Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+
+	mux := SetupRouter(f)
+
+	req := httptest.NewRequest(http.MethodPost, "/public/graphql/preflight", bytes.NewBufferString("invalid-json"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ := json.Marshal(federator.PreflightRequest{Request: graphql.Request{Query: "{ hello }"}})
+	req = httptest.NewRequest(http.MethodPost, "/public/graphql/preflight", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSetupRouter_Contract(t *testing.T) {
+	cfg := &configs.Config{
+		Environment:   "development", // Development mode authenticates every request as passport-app
+		TrustUpstream: true,
+	}
+	f, err := federator.Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+	mux := SetupRouter(f)
+
+	for _, target := range []string{"/public/contract?format=yaml", "/public/contract?format=go&package=bad-name"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+
+	// Without a PDP the contract covers the whole schema.graphql
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/contract", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var generated contract.Contract
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &generated))
+	assert.Equal(t, "passport-app", generated.ApplicationID)
+	assert.NotEmpty(t, generated.Fields)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/contract?format=go&package=passport", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "package passport")
+	assert.Contains(t, w.Body.String(), "type PersonInfo struct")
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/contract?format=typescript", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "export interface PersonInfo {")
+}
+
+type testQuotaLimits struct{ daily int64 }
+
+func (l testQuotaLimits) GetLimits(_ context.Context, applicationID string) (*quota.Limits, error) {
+	return &quota.Limits{ApplicationID: applicationID, DailyRecordQuota: &l.daily}, nil
+}
+
+func TestSetupRouter_Quota(t *testing.T) {
+	cfg := &configs.Config{
+		Environment:   "development", // Development mode authenticates every request as passport-app
+		TrustUpstream: true,
+	}
+	f, err := federator.Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+	f.Quotas = quota.NewEnforcer(testQuotaLimits{daily: 5}, quota.NewMemoryStore())
+	mux := SetupRouter(f)
+
+	req := httptest.NewRequest(http.MethodGet, "/usage/applications/passport-app", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var usage quota.Usage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	assert.Equal(t, int64(5), *usage.Daily.Remaining)
+
+	if err := f.Quotas.Record(context.Background(), "passport-app", 5); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+	body, _ := json.Marshal(graphql.Request{Query: "{ hello }"})
+	req = httptest.NewRequest(http.MethodPost, "/public/graphql", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "5", w.Header().Get("X-Quota-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-Quota-Remaining"))
+	assert.Equal(t, quota.WindowDaily, w.Header().Get("X-Quota-Window"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "QUOTA_EXCEEDED")
+}
+
+func TestSetupRouter_ResponseSigning(t *testing.T) {
+	cfg := &configs.Config{Environment: "development", TrustUpstream: true}
+	f, err := federator.Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+	mux := SetupRouter(f)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "no keys are published without signing")
+
+	body, _ := json.Marshal(graphql.Request{Query: "{ hello }"})
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/public/graphql", bytes.NewBuffer(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(signing.Header))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	if f.ResponseSigner, err = signing.NewSigner("exchange-1", key); err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/public/graphql", bytes.NewBuffer(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.NoError(t, signing.Verify(w.Header().Get(signing.Header), w.Body.Bytes(), key.Public()))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var jwks signing.JWKSet
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+	if assert.Len(t, jwks.Keys, 1) {
+		assert.Equal(t, "exchange-1", jwks.Keys[0].KeyID)
+		assert.Equal(t, signing.AlgorithmES256, jwks.Keys[0].Algorithm)
+	}
+}
+
+func TestSetupRouter_UsageWithoutQuotas(t *testing.T) {
+	cfg := &configs.Config{Environment: "test", TrustUpstream: true}
+	f, err := federator.Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/usage/applications/app-1", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	w := httptest.NewRecorder()
+	requestid.Middleware(SetupRouter(f)).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var body errorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, errorResponse{Code: "QUOTA_DISABLED", Error: "Usage tracking is not enabled", RequestID: "req-1"}, body)
+}
+
+func TestProviderCheck_SkipsProvidersInMaintenance(t *testing.T) {
+	f, err := federator.Initialize(context.Background(), &configs.Config{Environment: "test", TrustUpstream: true}, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+	f.Maintenance = maintenance.NewSchedule(maintenance.NewMemoryStore(), time.Hour)
+	check := providerCheck(f)
+
+	assert.NoError(t, check(context.Background()))
+
+	f.ProviderHandler.RecordFetch("drp", errors.New("connection refused"))
+	err = check(context.Background())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "drp")
+	}
+
+	_, err = f.Maintenance.Create(context.Background(), &maintenance.Window{ProviderKey: "drp", EndsAt: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.NoError(t, check(context.Background()), "planned downtime is not reported")
+
+	f.ProviderHandler.RecordFetch("rgd", errors.New("timeout"))
+	assert.Error(t, check(context.Background()))
+	f.ProviderHandler.RecordFetch("rgd", nil)
+	assert.NoError(t, check(context.Background()))
+}
+
+func TestNewHealthChecker_ProbesDependencyLiveness(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	dependency := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer dependency.Close()
+
+	cfg := &configs.Config{Environment: "test", TrustUpstream: true}
+	f, err := federator.Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+	f.Configs.PdpConfig.ClientURL = dependency.URL
+	f.Configs.CeConfig.ClientURL = dependency.URL
+	f.Configs.AppContext.PortalURL = dependency.URL
+
+	report := newHealthChecker(f, nil).Ready(context.Background())
+	assert.Equal(t, health.StatusUp, report.Checks["consent_engine"].Status)
+	assert.Equal(t, []string{"/health/live", "/health/live", "/health/live"}, paths, "every dependency is probed at its liveness endpoint")
+}
+
+type testApplications struct{ status string }
+
+func (s testApplications) GetApplication(_ context.Context, applicationID, _ string) (*appcontext.Application, error) {
+	return &appcontext.Application{ApplicationID: applicationID, Status: s.status}, nil
+}
+
+func TestSetupRouter_SuspendedApplication(t *testing.T) {
+	cfg := &configs.Config{Environment: "development", TrustUpstream: true}
+	f, err := federator.Initialize(context.Background(), cfg, provider.NewProviderHandler(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to initialize federator: %v", err)
+	}
+	f.Applications = testApplications{status: appcontext.StatusSuspended}
+	mux := SetupRouter(f)
+
+	body, _ := json.Marshal(graphql.Request{Query: "{ hello }"})
+	req := httptest.NewRequest(http.MethodPost, "/public/graphql", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "APPLICATION_SUSPENDED")
+
+	body, _ = json.Marshal(federator.PreflightRequest{Request: graphql.Request{Query: "{ hello }"}})
+	req = httptest.NewRequest(http.MethodPost, "/public/graphql/preflight", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "APPLICATION_SUSPENDED")
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/activation"
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/database"
+)
+
+// VersionSDL returns the SDL of a schema version
+func (s *SchemaService) VersionSDL(version string) (string, error) {
+	if s.db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+	schema, err := s.db.GetSchemaByVersion(version)
+	if errors.Is(err, database.ErrSchemaNotFound) {
+		return "", fmt.Errorf("%w: %s", activation.ErrVersionNotFound, version)
+	}
+	if err != nil {
+		return "", err
+	}
+	return schema.SDL, nil
+}
+
+// ContractTests returns the tests a schema version must pass before a scheduled activation makes it
+// the active schema: its SDL must be valid GraphQL, and it must be backward compatible with the
+// schema active at that time.
+func (s *SchemaService) ContractTests() []activation.ContractTest {
+	return []activation.ContractTest{
+		{Name: "valid-sdl", Run: s.checkValidSDL},
+		{Name: "backward-compatible", Run: s.checkBackwardCompatible},
+	}
+}
+
+func (s *SchemaService) checkValidSDL(_ context.Context, _, sdl string) error {
+	if !s.isValidSDL(sdl) {
+		return fmt.Errorf("SDL defines no types")
+	}
+	if _, err := s.parseSDL(sdl); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SchemaService) checkBackwardCompatible(_ context.Context, version, sdl string) error {
+	active, err := s.GetActiveSchema()
+	if err != nil {
+		return err
+	}
+	if active == nil || active.Version == version {
+		return nil
+	}
+	if compatible, reason, _ := s.analyzeCompatibility(active.SDL, sdl); !compatible {
+		return fmt.Errorf("not backward compatible with active version %s: %s", active.Version, reason)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Package signing signs GraphQL responses with detached JWS signatures (RFC 7515, Appendix F), so
+// that consumers can verify a response came from the exchange and was not modified in transit.
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Header is the response header carrying the detached JWS over the response body
+const Header = "X-JWS-Signature"
+
+// ErrInvalidSignature is returned when a detached JWS does not verify against a payload
+var ErrInvalidSignature = errors.New("invalid JWS signature")
+
+// Algorithms supported for response signatures, chosen by the type of the signing key
+const (
+	AlgorithmES256 = "ES256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// protectedHeader is the JWS protected header of a response signature
+type protectedHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// Signer signs payloads with a private key identified by a key ID
+type Signer struct {
+	keyID     string
+	key       crypto.Signer
+	algorithm string
+	header    string
+}
+
+// NewSigner creates a signer for an ECDSA P-256, RSA or Ed25519 private key
+func NewSigner(keyID string, key crypto.Signer) (*Signer, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key ID is required")
+	}
+	algorithm, err := algorithmFor(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	header, err := json.Marshal(protectedHeader{Algorithm: algorithm, KeyID: keyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JWS header: %w", err)
+	}
+	return &Signer{
+		keyID:     keyID,
+		key:       key,
+		algorithm: algorithm,
+		header:    base64.RawURLEncoding.EncodeToString(header),
+	}, nil
+}
+
+// LoadSigner creates a signer for the PEM encoded private key in keyFile
+func LoadSigner(keyID, keyFile string) (*Signer, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(keyID, key)
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8, SEC 1 (EC) or PKCS #1 (RSA) private key
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+	return signer, nil
+}
+
+// KeyID returns the ID of the signing key, sent as kid in the JWS header
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Algorithm returns the JWS algorithm of the signatures
+func (s *Signer) Algorithm() string {
+	return s.algorithm
+}
+
+// Sign returns the detached compact JWS over the payload, in the form header..signature
+func (s *Signer) Sign(payload []byte) (string, error) {
+	signingInput := s.header + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var signature []byte
+	var err error
+	switch s.algorithm {
+	case AlgorithmEdDSA:
+		signature, err = s.key.Sign(rand.Reader, []byte(signingInput), crypto.Hash(0))
+	case AlgorithmES256:
+		digest := sha256.Sum256([]byte(signingInput))
+		var der []byte
+		if der, err = s.key.Sign(rand.Reader, digest[:], crypto.SHA256); err == nil {
+			signature, err = rawECDSASignature(der)
+		}
+	default:
+		digest := sha256.Sum256([]byte(signingInput))
+		signature, err = s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to sign payload: %w", err)
+	}
+	return s.header + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks a detached compact JWS over the payload against a public key
+func Verify(jws string, payload []byte, publicKey crypto.PublicKey) error {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return fmt.Errorf("%w: not a detached compact JWS", ErrInvalidSignature)
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	var header protectedHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	if algorithm, err := algorithmFor(publicKey); err != nil || algorithm != header.Algorithm {
+		return fmt.Errorf("%w: algorithm %s does not match the key", ErrInvalidSignature, header.Algorithm)
+	}
+
+	signingInput := []byte(parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload))
+	digest := sha256.Sum256(signingInput)
+	valid := false
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, signingInput, signature)
+	case *ecdsa.PublicKey:
+		if len(signature) == 64 {
+			r := new(big.Int).SetBytes(signature[:32])
+			sv := new(big.Int).SetBytes(signature[32:])
+			valid = ecdsa.Verify(key, digest[:], r, sv)
+		}
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// algorithmFor returns the JWS algorithm used with a public key
+func algorithmFor(publicKey crypto.PublicKey) (string, error) {
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return "", fmt.Errorf("unsupported ECDSA curve %s, only P-256 is supported", key.Curve.Params().Name)
+		}
+		return AlgorithmES256, nil
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return "", fmt.Errorf("RSA signing keys must be at least 2048 bits")
+		}
+		return AlgorithmRS256, nil
+	default:
+		return "", fmt.Errorf("unsupported signing key type %T", publicKey)
+	}
+}
+
+// rawECDSASignature converts an ASN.1 DER ECDSA signature to the fixed-size r || s form JWS uses
+func rawECDSASignature(der []byte) ([]byte, error) {
+	var signature struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &signature); err != nil {
+		return nil, fmt.Errorf("failed to parse ECDSA signature: %w", err)
+	}
+	raw := make([]byte, 64)
+	signature.R.FillBytes(raw[:32])
+	signature.S.FillBytes(raw[32:])
+	return raw, nil
+}
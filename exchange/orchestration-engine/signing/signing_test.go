@@ -0,0 +1,105 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	payload := []byte(`{"data":{"personInfo":{"fullName":"Nimal Perera"}}}`)
+	for _, tc := range []struct {
+		algorithm string
+		key       crypto.Signer
+	}{
+		{AlgorithmES256, ecKey},
+		{AlgorithmRS256, rsaKey},
+		{AlgorithmEdDSA, edKey},
+	} {
+		t.Run(tc.algorithm, func(t *testing.T) {
+			signer, err := NewSigner("exchange-2026", tc.key)
+			require.NoError(t, err)
+			assert.Equal(t, tc.algorithm, signer.Algorithm())
+
+			jws, err := signer.Sign(payload)
+			require.NoError(t, err)
+			parts := strings.Split(jws, ".")
+			require.Len(t, parts, 3)
+			assert.Empty(t, parts[1], "the payload is detached")
+
+			header, err := base64.RawURLEncoding.DecodeString(parts[0])
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"alg":"`+tc.algorithm+`","kid":"exchange-2026"}`, string(header))
+
+			assert.NoError(t, Verify(jws, payload, tc.key.Public()))
+			tampered := []byte(strings.Replace(string(payload), "Nimal", "Kamal", 1))
+			assert.True(t, errors.Is(Verify(jws, tampered, tc.key.Public()), ErrInvalidSignature))
+		})
+	}
+
+	// A signature does not verify with another key
+	signer, err := NewSigner("exchange-2026", ecKey)
+	require.NoError(t, err)
+	jws, err := signer.Sign(payload)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(Verify(jws, payload, edKey.Public()), ErrInvalidSignature))
+	assert.True(t, errors.Is(Verify("not-a-jws", payload, ecKey.Public()), ErrInvalidSignature))
+}
+
+func TestNewSigner_RejectsUnsupportedKeys(t *testing.T) {
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewSigner("kid", p384Key)
+	assert.Error(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewSigner("", ecKey)
+	assert.Error(t, err, "a key ID is required")
+}
+
+func TestLoadSigner(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "signing.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+
+	signer, err := LoadSigner("exchange-2026", keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmES256, signer.Algorithm())
+
+	jwks, err := json.Marshal(signer.JWKS())
+	require.NoError(t, err)
+	assert.Contains(t, string(jwks), `"kid":"exchange-2026"`)
+	assert.Contains(t, string(jwks), `"crv":"P-256"`)
+
+	_, err = LoadSigner("exchange-2026", filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Error(t, err)
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0600))
+	_, err = LoadSigner("exchange-2026", keyFile)
+	assert.Error(t, err)
+}
@@ -0,0 +1,287 @@
+// Package synthetic answers provider queries with generated data, so that demo environments and load
+// tests never reach real provider systems. The data is deterministic: the same subject, identified by the
+// arguments of the provider query such as a NIC, always gets the same person, vehicles and codes.
+package synthetic
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// maxListLength is the largest number of elements generated for a list field
+const maxListLength = 3
+
+// Generator generates provider responses consistent with the unified schema
+type Generator struct {
+	seed    string
+	latency time.Duration
+}
+
+// NewGenerator creates a generator. Generators with different seeds produce different data for the same
+// subject; latency delays every response to simulate the provider's response time.
+func NewGenerator(seed string, latency time.Duration) *Generator {
+	return &Generator{seed: seed, latency: latency}
+}
+
+// Generate answers the provider query of request as the provider providerKey would. The unified schema
+// gives the type of each field through its @sourceInfo directives; fields it does not map are
+// generated as strings.
+func (g *Generator) Generate(ctx context.Context, schema *ast.Document, providerKey string, request graphql.Request) (graphql.Response, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(request.Query),
+		Name: "ProviderQuery",
+	})})
+	if err != nil {
+		return graphql.Response{}, fmt.Errorf("failed to parse provider query: %w", err)
+	}
+
+	if g.latency > 0 {
+		select {
+		case <-time.After(g.latency):
+		case <-ctx.Done():
+			return graphql.Response{}, ctx.Err()
+		}
+	}
+
+	w := &walker{
+		generator: g,
+		schema:    schema,
+		fields:    providerFields(schema, providerKey),
+		variables: request.Variables,
+	}
+	data := make(map[string]interface{})
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			for key, value := range w.object(op.SelectionSet, "", "") {
+				data[key] = value
+			}
+		}
+	}
+	return graphql.Response{Data: data}, nil
+}
+
+// providerFields indexes the fields of the unified schema mapped to a provider by their provider field path
+func providerFields(schema *ast.Document, providerKey string) map[string]*ast.FieldDefinition {
+	fields := make(map[string]*ast.FieldDefinition)
+	if schema == nil {
+		return fields
+	}
+	for _, def := range schema.Definitions {
+		objDef, ok := def.(*ast.ObjectDefinition)
+		if !ok {
+			continue
+		}
+		for _, fieldDef := range objDef.Fields {
+			if key, path := sourceInfo(fieldDef); key == providerKey && path != "" {
+				fields[path] = fieldDef
+			}
+		}
+	}
+	return fields
+}
+
+// sourceInfo returns the provider key and provider field of a field's @sourceInfo directive
+func sourceInfo(fieldDef *ast.FieldDefinition) (providerKey, providerField string) {
+	for _, dir := range fieldDef.Directives {
+		if dir.Name.Value != "sourceInfo" {
+			continue
+		}
+		for _, arg := range dir.Arguments {
+			if val, ok := arg.Value.(*ast.StringValue); ok {
+				switch arg.Name.Value {
+				case "providerKey":
+					providerKey = val.Value
+				case "providerField":
+					providerField = val.Value
+				}
+			}
+		}
+	}
+	return providerKey, providerField
+}
+
+// walker generates the data of one provider query
+type walker struct {
+	generator *Generator
+	schema    *ast.Document
+	fields    map[string]*ast.FieldDefinition
+	variables map[string]interface{}
+}
+
+// object generates the fields of a selection set. The subject is the first argument value seen on the
+// way down, which identifies whose data is generated.
+func (w *walker) object(selectionSet *ast.SelectionSet, path, subject string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if selectionSet == nil {
+		return result
+	}
+	for _, selection := range selectionSet.Selections {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+		fieldPath := field.Name.Value
+		if path != "" {
+			fieldPath = path + "." + field.Name.Value
+		}
+		fieldSubject := subject
+		if fieldSubject == "" {
+			fieldSubject = w.argumentValue(field.Arguments)
+		}
+		responseKey := field.Name.Value
+		if field.Alias != nil {
+			responseKey = field.Alias.Value
+		}
+
+		fieldDef := w.fields[fieldPath]
+		switch {
+		case fieldDef != nil && isList(fieldDef.Type):
+			result[responseKey] = w.list(fieldDef, fieldPath, fieldSubject, field.SelectionSet)
+		case field.SelectionSet != nil && len(field.SelectionSet.Selections) > 0:
+			result[responseKey] = w.object(field.SelectionSet, fieldPath, fieldSubject)
+		default:
+			result[responseKey] = w.scalar(fieldDef, field.Name.Value, fieldPath, fieldSubject)
+		}
+	}
+	return result
+}
+
+// list generates the elements of a list field. Elements of an object type get the fields of the query's
+// selection set, or else every field of the type mapped with @sourceInfo, named after the last part of
+// its provider field as the accumulator expects.
+func (w *walker) list(fieldDef *ast.FieldDefinition, path, subject string, selectionSet *ast.SelectionSet) []interface{} {
+	length := 1 + w.generator.rand(subject, path, "length").Intn(maxListLength)
+	elements := make([]interface{}, length)
+	elementType := namedType(fieldDef.Type)
+	for i := range elements {
+		elementSubject := subject + "#" + path + "[" + strconv.Itoa(i) + "]"
+		switch objDef := w.objectDefinition(elementType); {
+		case selectionSet != nil && len(selectionSet.Selections) > 0:
+			elements[i] = w.object(selectionSet, path, elementSubject)
+		case objDef != nil:
+			elements[i] = w.element(objDef, elementSubject)
+		default:
+			elements[i] = w.value(elementType, fieldDef.Name.Value, path, elementSubject)
+		}
+	}
+	return elements
+}
+
+// element generates a list element of an object type from the type's fields
+func (w *walker) element(objDef *ast.ObjectDefinition, subject string) map[string]interface{} {
+	element := make(map[string]interface{})
+	for _, fieldDef := range objDef.Fields {
+		_, providerField := sourceInfo(fieldDef)
+		if providerField == "" {
+			continue
+		}
+		key := providerField[strings.LastIndex(providerField, ".")+1:]
+		if isList(fieldDef.Type) {
+			element[key] = w.list(fieldDef, providerField, subject, nil)
+		} else {
+			element[key] = w.scalar(fieldDef, key, providerField, subject)
+		}
+	}
+	return element
+}
+
+// scalar generates a leaf field, typed by its definition in the unified schema when it has one
+func (w *walker) scalar(fieldDef *ast.FieldDefinition, name, path, subject string) interface{} {
+	typeName := "String"
+	if fieldDef != nil {
+		typeName = namedType(fieldDef.Type)
+		name = fieldDef.Name.Value + " " + name
+	}
+	return w.value(typeName, name, path, subject)
+}
+
+// value generates a value of a scalar type, chosen by the names of the field
+func (w *walker) value(typeName, name, path, subject string) interface{} {
+	r := w.generator.rand(subject, path)
+	switch typeName {
+	case "Int":
+		if strings.Contains(strings.ToLower(name), "year") {
+			return 1995 + r.Intn(30)
+		}
+		return r.Intn(100)
+	case "Float":
+		return float64(r.Intn(10000)) / 100
+	case "Boolean":
+		return r.Intn(2) == 1
+	}
+	return w.generator.text(name, path, subject, r)
+}
+
+// argumentValue returns the first argument value of a field, resolving variables
+func (w *walker) argumentValue(arguments []*ast.Argument) string {
+	for _, arg := range arguments {
+		switch v := arg.Value.(type) {
+		case *ast.StringValue:
+			return v.Value
+		case *ast.IntValue:
+			return v.Value
+		case *ast.Variable:
+			if value, ok := w.variables[v.Name.Value]; ok {
+				return fmt.Sprint(value)
+			}
+		}
+	}
+	return ""
+}
+
+// objectDefinition finds an object type of the unified schema
+func (w *walker) objectDefinition(name string) *ast.ObjectDefinition {
+	if w.schema == nil {
+		return nil
+	}
+	for _, def := range w.schema.Definitions {
+		if objDef, ok := def.(*ast.ObjectDefinition); ok && objDef.Name.Value == name {
+			return objDef
+		}
+	}
+	return nil
+}
+
+// rand returns a random source determined by the generator's seed and parts
+func (g *Generator) rand(parts ...string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(g.seed))
+	for _, part := range parts {
+		h.Write([]byte{0})
+		h.Write([]byte(part))
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+func isList(t ast.Type) bool {
+	if nonNull, ok := t.(*ast.NonNull); ok {
+		t = nonNull.Type
+	}
+	_, ok := t.(*ast.List)
+	return ok
+}
+
+func namedType(t ast.Type) string {
+	for {
+		switch v := t.(type) {
+		case *ast.NonNull:
+			t = v.Type
+		case *ast.List:
+			t = v.Type
+		case *ast.Named:
+			return v.Name.Value
+		default:
+			return "String"
+		}
+	}
+}
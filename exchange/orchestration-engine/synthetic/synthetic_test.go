@@ -0,0 +1,141 @@
+package synthetic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ginaxu1/gov-dx-sandbox/exchange/orchestration-engine/pkg/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchemaSDL = `
+type Query {
+	personInfo(nic: String!): PersonInfo
+}
+
+type PersonInfo {
+	fullName: String @sourceInfo(providerKey: "drp", schemaId: "drp-schema-v1", providerField: "person.fullName")
+	address: String @sourceInfo(providerKey: "drp", schemaId: "drp-schema-v1", providerField: "person.permanentAddress")
+	name: String @sourceInfo(providerKey: "rgd", schemaId: "rgd-schema-v1", providerField: "getPersonInfo.name")
+	dateOfBirth: String @sourceInfo(providerKey: "rgd", schemaId: "rgd-schema-v1", providerField: "getPersonInfo.birthDate")
+	ownedVehicles: [VehicleInfo] @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.getVehicleInfos.data")
+}
+
+type VehicleInfo {
+	regNo: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.getVehicleInfos.data.registrationNumber")
+	make: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.getVehicleInfos.data.make")
+	year: Int @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.getVehicleInfos.data.yearOfManufacture")
+	class: [VehicleClass] @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.getVehicleInfos.data.classes")
+}
+
+type VehicleClass {
+	classCode: String @sourceInfo(providerKey: "dmt", schemaId: "dmt-schema-v1", providerField: "vehicle.getVehicleInfos.data.classes.classCode")
+}
+`
+
+func testSchema(t *testing.T) *ast.Document {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(testSchemaSDL)})})
+	require.NoError(t, err)
+	return doc
+}
+
+func generate(t *testing.T, g *Generator, providerKey, query string) map[string]interface{} {
+	response, err := g.Generate(context.Background(), testSchema(t), providerKey, graphql.Request{Query: query})
+	require.NoError(t, err)
+	return response.Data
+}
+
+func TestGenerate_IsDeterministicPerNIC(t *testing.T) {
+	g := NewGenerator("demo", 0)
+	query := `query { person(nic: "199012345678") { fullName permanentAddress } }`
+
+	first := generate(t, g, "drp", query)
+	assert.Equal(t, first, generate(t, g, "drp", query))
+	assert.Equal(t, first, generate(t, NewGenerator("demo", 0), "drp", query))
+
+	person := first["person"].(map[string]interface{})
+	assert.NotEmpty(t, person["fullName"])
+	assert.NotEmpty(t, person["permanentAddress"])
+
+	other := generate(t, g, "drp", `query { person(nic: "198811111111") { fullName permanentAddress } }`)
+	assert.NotEqual(t, first, other)
+	assert.NotEqual(t, first, generate(t, NewGenerator("load-test", 0), "drp", query))
+}
+
+func TestGenerate_PersonIsConsistentAcrossProviders(t *testing.T) {
+	g := NewGenerator("demo", 0)
+
+	drp := generate(t, g, "drp", `query { person(nic: "199012345678") { fullName } }`)
+	rgd := generate(t, g, "rgd", `query { getPersonInfo(nic: "199012345678") { name birthDate } }`)
+
+	assert.Equal(t, drp["person"].(map[string]interface{})["fullName"], rgd["getPersonInfo"].(map[string]interface{})["name"])
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}$`, rgd["getPersonInfo"].(map[string]interface{})["birthDate"])
+}
+
+func TestGenerate_ListsFollowTheUnifiedSchema(t *testing.T) {
+	g := NewGenerator("demo", 0)
+
+	data := generate(t, g, "dmt", `query {
+		vehicle {
+			getVehicleInfos(ownerNic: "199012345678") {
+				data { registrationNumber make yearOfManufacture classes }
+			}
+		}
+	}`)
+
+	vehicles, err := valueAt(data, "vehicle", "getVehicleInfos", "data")
+	require.NoError(t, err)
+	require.IsType(t, []interface{}{}, vehicles)
+	list := vehicles.([]interface{})
+	assert.NotEmpty(t, list)
+	assert.LessOrEqual(t, len(list), maxListLength)
+
+	for _, item := range list {
+		vehicle := item.(map[string]interface{})
+		assert.Regexp(t, `^[A-Z]{3}-\d{4}$`, vehicle["registrationNumber"])
+		assert.Contains(t, vehicleMakes, vehicle["make"])
+		assert.IsType(t, 0, vehicle["yearOfManufacture"])
+
+		classes := vehicle["classes"].([]interface{})
+		assert.NotEmpty(t, classes)
+		assert.Contains(t, []string{"A", "B1", "B", "C1", "D", "G"}, classes[0].(map[string]interface{})["classCode"])
+	}
+}
+
+func TestGenerate_UnmappedFieldsAreStrings(t *testing.T) {
+	data := generate(t, NewGenerator("demo", 0), "drp", `query { person(nic: "199012345678") { nic favouriteColour } }`)
+
+	person := data["person"].(map[string]interface{})
+	assert.Equal(t, "199012345678", person["nic"])
+	assert.IsType(t, "", person["favouriteColour"])
+}
+
+func TestGenerate_Latency(t *testing.T) {
+	g := NewGenerator("demo", time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := g.Generate(ctx, testSchema(t), "drp", graphql.Request{Query: `query { person { fullName } }`})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGenerate_InvalidQuery(t *testing.T) {
+	_, err := NewGenerator("demo", 0).Generate(context.Background(), nil, "drp", graphql.Request{Query: "query {"})
+	assert.Error(t, err)
+}
+
+func valueAt(data interface{}, keys ...string) (interface{}, error) {
+	for _, key := range keys {
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, assert.AnError
+		}
+		data = object[key]
+	}
+	return data, nil
+}
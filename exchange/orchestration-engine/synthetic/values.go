@@ -0,0 +1,186 @@
+package synthetic
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var (
+	maleNames   = []string{"Nuwan", "Kasun", "Ruwan", "Tharindu", "Dinesh", "Chamara", "Suresh", "Arjun", "Mohamed", "Pradeep"}
+	femaleNames = []string{"Nimali", "Sachini", "Dilani", "Kavindi", "Fathima", "Priya", "Chathuri", "Anusha", "Ishara", "Malini"}
+	lastNames   = []string{"Perera", "Fernando", "Silva", "Jayasinghe", "Bandara", "Wickramasinghe", "Rajapaksa", "Kumar", "Dissanayake", "Herath"}
+	streets     = []string{"Galle Road", "Temple Road", "Station Road", "Lake Drive", "Flower Road", "Hill Street", "Church Lane", "Main Street"}
+	cities      = []string{"Colombo", "Kandy", "Galle", "Jaffna", "Matara", "Kurunegala", "Negombo", "Anuradhapura", "Badulla", "Trincomalee"}
+	professions = []string{"Teacher", "Engineer", "Nurse", "Accountant", "Farmer", "Software Developer", "Doctor", "Electrician", "Lawyer", "Driver"}
+	vehicles    = map[string][]string{
+		"Toyota":     {"Corolla", "Prius", "Aqua", "Hilux"},
+		"Honda":      {"Civic", "Fit", "Vezel", "CR-V"},
+		"Suzuki":     {"Alto", "Wagon R", "Swift"},
+		"Nissan":     {"Sunny", "Leaf", "X-Trail"},
+		"Mitsubishi": {"Lancer", "Montero", "L200"},
+	}
+	vehicleMakes   = []string{"Honda", "Mitsubishi", "Nissan", "Suzuki", "Toyota"}
+	vehicleClasses = [][2]string{
+		{"A", "Motorcycle"},
+		{"B1", "Light motor vehicle"},
+		{"B", "Dual purpose vehicle"},
+		{"C1", "Light motor lorry"},
+		{"D", "Heavy motor coach"},
+		{"G", "Heavy goods vehicle"},
+	}
+)
+
+// category is a kind of value, recognized by the names of a field
+type category struct {
+	names []string // Lower-case names, matched exactly, or by suffix when they start with "*"
+	value func(g *Generator, subject string, r *rand.Rand) interface{}
+}
+
+// categories are checked in order, so more specific names come first
+var categories = []category{
+	{[]string{"*nic", "nicnumber"}, func(g *Generator, subject string, r *rand.Rand) interface{} {
+		if nic := subjectOf(subject); nic != "" {
+			return nic
+		}
+		return fmt.Sprintf("%d%07dV", 50+r.Intn(50), r.Intn(10000000))
+	}},
+	{[]string{"*email"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		p := g.person(subject)
+		return strings.ToLower(p.first+"."+p.last) + "@example.com"
+	}},
+	{[]string{"*phone", "*mobile", "*phonenumber", "*mobilenumber"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).phone
+	}},
+	{[]string{"firstname", "givenname"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).first
+	}},
+	{[]string{"othernames", "middlename"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).middle
+	}},
+	{[]string{"lastname", "surname", "familyname"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).last
+	}},
+	{[]string{"brno", "*birthregistrationnumber"}, func(_ *Generator, _ string, r *rand.Rand) interface{} {
+		return fmt.Sprintf("BR-%06d", r.Intn(1000000))
+	}},
+	{[]string{"regno", "registrationnumber", "*plate"}, func(_ *Generator, _ string, r *rand.Rand) interface{} {
+		return fmt.Sprintf("%c%c%c-%04d", 'A'+r.Intn(26), 'A'+r.Intn(26), 'A'+r.Intn(26), r.Intn(10000))
+	}},
+	{[]string{"classcode"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.vehicleClass(subject)[0]
+	}},
+	{[]string{"classname"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.vehicleClass(subject)[1]
+	}},
+	{[]string{"make"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		vehicleMake, _ := g.vehicle(subject)
+		return vehicleMake
+	}},
+	{[]string{"model"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		_, model := g.vehicle(subject)
+		return model
+	}},
+	{[]string{"name", "fullname", "*fullname"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		p := g.person(subject)
+		return p.first + " " + p.middle + " " + p.last
+	}},
+	{[]string{"sex", "gender"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).sex
+	}},
+	{[]string{"dateofbirth", "birthdate", "dob"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).dateOfBirth
+	}},
+	{[]string{"*address"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).address
+	}},
+	{[]string{"birthplace", "district", "city", "town"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).city
+	}},
+	{[]string{"profession", "occupation"}, func(g *Generator, subject string, _ *rand.Rand) interface{} {
+		return g.person(subject).profession
+	}},
+	{[]string{"*date"}, func(_ *Generator, _ string, r *rand.Rand) interface{} {
+		return fmt.Sprintf("%04d-%02d-%02d", 2015+r.Intn(10), 1+r.Intn(12), 1+r.Intn(28))
+	}},
+	{[]string{"*no", "*number", "*id"}, func(_ *Generator, _ string, r *rand.Rand) interface{} {
+		return fmt.Sprintf("%08d", r.Intn(100000000))
+	}},
+}
+
+// text generates a string for a field. The names of the field, separated by spaces, pick the kind of value;
+// values describing the subject, like a person's name and address, are the same in every provider's data.
+func (g *Generator) text(name, path, subject string, r *rand.Rand) interface{} {
+	names := strings.Fields(strings.ToLower(name))
+	for _, c := range categories {
+		for _, n := range names {
+			if c.matches(n) {
+				return c.value(g, subject, r)
+			}
+		}
+	}
+	field := path[strings.LastIndex(path, ".")+1:]
+	return fmt.Sprintf("%s-%04d", field, r.Intn(10000))
+}
+
+func (c category) matches(name string) bool {
+	for _, candidate := range c.names {
+		if suffix, ok := strings.CutPrefix(candidate, "*"); ok {
+			if strings.HasSuffix(name, suffix) {
+				return true
+			}
+		} else if name == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// person is the generated identity of a subject
+type person struct {
+	first, middle, last string
+	sex                 string
+	dateOfBirth         string
+	address, city       string
+	phone               string
+	profession          string
+}
+
+// person generates the identity of the subject a value belongs to, ignoring the list element it is in
+func (g *Generator) person(subject string) person {
+	r := g.rand(subjectOf(subject), "person")
+	p := person{sex: "MALE", last: lastNames[r.Intn(len(lastNames))]}
+	names := maleNames
+	if r.Intn(2) == 1 {
+		p.sex, names = "FEMALE", femaleNames
+	}
+	p.first = names[r.Intn(len(names))]
+	p.middle = names[r.Intn(len(names))]
+	p.dateOfBirth = fmt.Sprintf("%04d-%02d-%02d", 1950+r.Intn(55), 1+r.Intn(12), 1+r.Intn(28))
+	p.city = cities[r.Intn(len(cities))]
+	p.address = fmt.Sprintf("%d, %s, %s", 1+r.Intn(250), streets[r.Intn(len(streets))], p.city)
+	p.phone = fmt.Sprintf("07%d%07d", r.Intn(9), r.Intn(10000000))
+	p.profession = professions[r.Intn(len(professions))]
+	return p
+}
+
+// vehicle generates the make and model of the vehicle a value belongs to
+func (g *Generator) vehicle(subject string) (string, string) {
+	r := g.rand(subject, "vehicle")
+	vehicleMake := vehicleMakes[r.Intn(len(vehicleMakes))]
+	models := vehicles[vehicleMake]
+	return vehicleMake, models[r.Intn(len(models))]
+}
+
+// vehicleClass generates the code and name of the vehicle class a value belongs to
+func (g *Generator) vehicleClass(subject string) [2]string {
+	return vehicleClasses[g.rand(subject, "vehicleClass").Intn(len(vehicleClasses))]
+}
+
+// subjectOf returns the subject a list element belongs to
+func subjectOf(subject string) string {
+	if i := strings.Index(subject, "#"); i >= 0 {
+		return subject[:i]
+	}
+	return subject
+}
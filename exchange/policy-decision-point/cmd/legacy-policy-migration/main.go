@@ -0,0 +1,108 @@
+// Command legacy-policy-migration imports the policies of the retired policy-governance service into the
+// PDP's policy metadata, so that the service can be switched off. It prints a JSON report and exits
+// with status 1 if the migration was stopped by invalid rows or its result failed verification.
+//
+//	legacy-policy-migration -legacy-dsn "host=... dbname=policy_governance" -schema-map "dmt=schema-1,drp=schema-2" -apply
+//
+// Without -apply the report is computed and nothing is written. The PDP database is configured with the
+// same DB_* environment variables as the service.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/internal/config"
+	v1 "github.com/gov-dx-sandbox/exchange/policy-decision-point/v1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"github.com/gov-dx-sandbox/exchange/shared/utils"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	legacyDSN := flag.String("legacy-dsn", utils.GetEnvOrDefault("LEGACY_POLICY_DB_DSN", ""), "Postgres DSN of the policy-governance database (default: the PDP database)")
+	schemaMap := flag.String("schema-map", "", "Comma-separated subgraph=schemaId pairs (default: subgraph names are schema IDs)")
+	tenantID := flag.String("tenant", "", "Tenant of the imported fields (default: the default tenant)")
+	providerID := flag.String("provider-id", "", "Provider that owns the imported fields")
+	grantDuration := flag.String("grant-duration", string(models.GrantDurationTypeOneYear), "Validity of the imported allow list entries: 30d or 365d")
+	apply := flag.Bool("apply", false, "Write the migration; without it only the report is printed")
+
+	// LoadConfig parses the flags above together with the service flags
+	cfg := config.LoadConfig("policy-decision-point")
+	utils.SetupLogging(cfg.Logging.Format, cfg.Logging.Level)
+
+	schemaIDs, err := parseSchemaMap(*schemaMap)
+	if err != nil {
+		slog.Error("Invalid schema map", "error", err)
+		os.Exit(2)
+	}
+
+	pdpDB, err := v1.ConnectGormDB(v1.NewDatabaseConfig(&cfg.DBConfigs))
+	if err != nil {
+		slog.Error("Failed to connect to PDP database", "error", err)
+		os.Exit(1)
+	}
+	legacyDB := pdpDB
+	if *legacyDSN != "" {
+		legacyDB, err = gorm.Open(postgres.Open(*legacyDSN), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+		if err != nil {
+			slog.Error("Failed to connect to legacy policy database", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	policies, err := services.LoadLegacyPolicies(legacyDB)
+	if err != nil {
+		slog.Error("Failed to load legacy policies", "error", err)
+		os.Exit(1)
+	}
+
+	report, err := services.NewPolicyMetadataService(pdpDB).MigrateLegacyPolicies(policies, models.LegacyMigrationOptions{
+		TenantID:      *tenantID,
+		ProviderID:    *providerID,
+		SchemaIDs:     schemaIDs,
+		GrantDuration: models.GrantDurationType(*grantDuration),
+		DryRun:        !*apply,
+	})
+	if err != nil {
+		slog.Error("Legacy policy migration failed", "error", err)
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		slog.Error("Failed to write migration report", "error", err)
+		os.Exit(1)
+	}
+	if report.HasErrors() {
+		os.Exit(1)
+	}
+	slog.Info("Legacy policy migration finished", "applied", report.Applied, "created", report.Created,
+		"merged", report.Merged, "grants", report.Grants, "verified", report.Verified)
+}
+
+// parseSchemaMap parses comma-separated subgraph=schemaId pairs
+func parseSchemaMap(value string) (map[string]string, error) {
+	schemaIDs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		subgraph, schemaID, ok := strings.Cut(pair, "=")
+		subgraph, schemaID = strings.TrimSpace(subgraph), strings.TrimSpace(schemaID)
+		if !ok || subgraph == "" || schemaID == "" {
+			return nil, fmt.Errorf("expected subgraph=schemaId, got %q", pair)
+		}
+		schemaIDs[subgraph] = schemaID
+	}
+	return schemaIDs, nil
+}
@@ -3,9 +3,11 @@ package config
 
 import (
 	"flag"
+	"log/slog"
 	"time"
 
 	"github.com/gov-dx-sandbox/exchange/shared/utils"
+	"github.com/gov-dx-sandbox/shared/database"
 )
 
 // Config holds all configuration for a service
@@ -16,6 +18,12 @@ type Config struct {
 	Security    SecurityConfig
 	IDPConfig   IDPConfig
 	DBConfigs   DBConfigs
+	AllowList   AllowListConfig
+	PolicyCache PolicyCacheConfig
+	KillSwitch  KillSwitchConfig
+	Fallback    DecisionFallbackConfig
+	Metrics     MetricsConfig
+	PolicyAdmin PolicyAdminConfig
 }
 
 // ServiceConfig holds service-specific configuration
@@ -24,6 +32,8 @@ type ServiceConfig struct {
 	Port    string
 	Host    string
 	Timeout time.Duration
+	// GRPCPort serves the gRPC decision API; empty disables it
+	GRPCPort string
 }
 
 // LoggingConfig holds logging configuration
@@ -40,10 +50,15 @@ type SecurityConfig struct {
 
 // IDPConfig holds IDP configuration
 type IDPConfig struct {
-	Issuer   string
-	JwksURL  string
+	Issuer  string
+	JwksURL string
+	// Audience is the comma-separated client IDs whose access tokens are accepted
 	Audience string
 	OrgName  string
+	// TenantClaim is the token claim naming the tenant a caller is bound to
+	TenantClaim string
+	// AuthDisabled leaves the API unauthenticated, for local deployments without an identity provider
+	AuthDisabled bool
 }
 
 // DBConfigs holds database configuration
@@ -54,6 +69,49 @@ type DBConfigs struct {
 	Password string
 	Database string
 	SSLMode  string
+	// ReplicaDSNs are the read replicas that read-only service methods are routed to
+	ReplicaDSNs []string
+}
+
+// AllowListConfig holds allow list expiry cleanup configuration
+type AllowListConfig struct {
+	CleanupInterval  time.Duration
+	ExpiredRetention time.Duration
+}
+
+// PolicyCacheConfig holds in-memory policy cache configuration
+type PolicyCacheConfig struct {
+	// PollInterval is how often the cache checks the database for changes; zero disables the cache
+	PollInterval time.Duration
+}
+
+// KillSwitchConfig holds kill switch configuration
+type KillSwitchConfig struct {
+	// PollInterval is how often the in-memory deny list is reloaded; zero reads the database on every decision
+	PollInterval time.Duration
+}
+
+// DecisionFallbackConfig holds the decision fallback used while the policy database is unavailable
+type DecisionFallbackConfig struct {
+	// Mode is fail_closed, fail_open_pinned or last_known_good
+	Mode string
+	// PinnedFields are the comma-separated "schemaId:fieldName" fields authorized in fail_open_pinned mode
+	PinnedFields string
+	// MaxStaleness is how long cached policies are served in last_known_good mode after they were last verified
+	MaxStaleness time.Duration
+}
+
+// PolicyAdminConfig holds policy administration configuration
+type PolicyAdminConfig struct {
+	// RequireChangeApproval routes policy writes through change sets, so that they are only applied once
+	// approved by a second admin
+	RequireChangeApproval bool
+}
+
+// MetricsConfig holds decision metrics and SLO configuration
+type MetricsConfig struct {
+	// SlowDecisionThreshold is the default latency above which decisions are listed as slow
+	SlowDecisionThreshold time.Duration
 }
 
 // LoadConfig loads configuration from flags and environment variables
@@ -64,12 +122,14 @@ func LoadConfig(serviceName string) *Config {
 	// Define flags
 	envFlag := flag.String("env", env, "Environment: local or production")
 	port := flag.String("port", utils.GetEnvOrDefault("PORT", "8082"), "Service port")
+	grpcPort := flag.String("grpc-port", utils.GetEnvOrDefault("GRPC_PORT", "9082"), "gRPC decision API port (empty disables it)")
 	host := flag.String("host", utils.GetEnvOrDefault("HOST", "0.0.0.0"), "Host address")
 	timeout := flag.Duration("timeout", 10*time.Second, "Request timeout")
 	logLevel := flag.String("log-level", getDefaultLogLevel(env), "Log level")
 	logFormat := flag.String("log-format", getDefaultLogFormat(env), "Log format")
 	enableCORS := flag.Bool("cors", getDefaultCORS(env), "Enable CORS")
 	rateLimit := flag.Int("rate-limit", getDefaultRateLimit(env), "Rate limit per minute")
+	slowDecisionThreshold := flag.Duration("slow-decision-threshold", getDefaultSlowDecisionThreshold(), "Latency above which decisions are reported as slow")
 
 	// Parse flags
 	flag.Parse()
@@ -79,6 +139,8 @@ func LoadConfig(serviceName string) *Config {
 	userIssuer := utils.GetEnvOrDefault("IDP_ISSUER", "https://api.asgardeo.io/t/"+orgName+"/oauth2/token")
 	userAudience := utils.GetEnvOrDefault("IDP_AUDIENCE", "YOUR_AUDIENCE")
 	userJwksURL := utils.GetEnvOrDefault("IDP_JWKS_URL", "https://api.asgardeo.io/t/"+orgName+"/oauth2/jwks")
+	tenantClaim := utils.GetEnvOrDefault("IDP_TENANT_CLAIM", "tenant_id")
+	authDisabled := utils.GetEnvOrDefault("PDP_AUTH_DISABLED", "false") == "true"
 
 	// Reading DB Configs
 	dbHost := utils.GetEnvOrDefault("DB_HOST", "localhost")
@@ -87,6 +149,25 @@ func LoadConfig(serviceName string) *Config {
 	dbPassword := utils.GetEnvOrDefault("DB_PASSWORD", "")
 	dbName := utils.GetEnvOrDefault("DB_NAME", "pdp")
 	dbSslMode := utils.GetEnvOrDefault("DB_SSLMODE", "require")
+	dbReplicaDSNs := database.ParseReplicaDSNs(utils.GetEnvOrDefault("DB_READ_REPLICA_DSNS", ""))
+
+	// Reading allow list cleanup configs
+	cleanupInterval := parseDurationOrDefault("ALLOWLIST_CLEANUP_INTERVAL", time.Hour)
+	expiredRetention := parseDurationOrDefault("ALLOWLIST_EXPIRED_RETENTION", 30*24*time.Hour)
+
+	// Reading policy cache configs
+	cachePollInterval := parseDurationOrDefault("POLICY_CACHE_POLL_INTERVAL", 30*time.Second)
+
+	// Reading kill switch configs
+	killSwitchPollInterval := parseDurationOrDefault("KILL_SWITCH_POLL_INTERVAL", 2*time.Second)
+
+	// Reading decision fallback configs
+	fallbackMode := utils.GetEnvOrDefault("DECISION_FALLBACK_MODE", "fail_closed")
+	fallbackPinnedFields := utils.GetEnvOrDefault("DECISION_FALLBACK_PINNED_FIELDS", "")
+	fallbackMaxStaleness := parseDurationOrDefault("DECISION_FALLBACK_MAX_STALENESS", 5*time.Minute)
+
+	// Reading policy administration configs
+	requireChangeApproval := utils.GetEnvOrDefault("POLICY_CHANGE_APPROVAL_REQUIRED", "true") != "false"
 
 	// Use flag value if provided, otherwise use environment default
 	finalEnv := *envFlag
@@ -94,10 +175,11 @@ func LoadConfig(serviceName string) *Config {
 	config := &Config{
 		Environment: finalEnv,
 		Service: ServiceConfig{
-			Name:    serviceName,
-			Port:    *port,
-			Host:    *host,
-			Timeout: *timeout,
+			Name:     serviceName,
+			Port:     *port,
+			Host:     *host,
+			Timeout:  *timeout,
+			GRPCPort: *grpcPort,
 		},
 		Logging: LoggingConfig{
 			Level:  *logLevel,
@@ -108,24 +190,62 @@ func LoadConfig(serviceName string) *Config {
 			RateLimit:  *rateLimit,
 		},
 		IDPConfig: IDPConfig{
-			Issuer:   userIssuer,
-			JwksURL:  userJwksURL,
-			Audience: userAudience,
-			OrgName:  orgName,
+			Issuer:       userIssuer,
+			JwksURL:      userJwksURL,
+			Audience:     userAudience,
+			OrgName:      orgName,
+			TenantClaim:  tenantClaim,
+			AuthDisabled: authDisabled,
 		},
 		DBConfigs: DBConfigs{
-			Host:     dbHost,
-			Port:     dbPort,
-			Username: dbUsername,
-			Password: dbPassword,
-			Database: dbName,
-			SSLMode:  dbSslMode,
+			Host:        dbHost,
+			Port:        dbPort,
+			Username:    dbUsername,
+			Password:    dbPassword,
+			Database:    dbName,
+			SSLMode:     dbSslMode,
+			ReplicaDSNs: dbReplicaDSNs,
+		},
+		AllowList: AllowListConfig{
+			CleanupInterval:  cleanupInterval,
+			ExpiredRetention: expiredRetention,
+		},
+		PolicyCache: PolicyCacheConfig{
+			PollInterval: cachePollInterval,
+		},
+		KillSwitch: KillSwitchConfig{
+			PollInterval: killSwitchPollInterval,
+		},
+		Fallback: DecisionFallbackConfig{
+			Mode:         fallbackMode,
+			PinnedFields: fallbackPinnedFields,
+			MaxStaleness: fallbackMaxStaleness,
+		},
+		Metrics: MetricsConfig{
+			SlowDecisionThreshold: *slowDecisionThreshold,
+		},
+		PolicyAdmin: PolicyAdminConfig{
+			RequireChangeApproval: requireChangeApproval,
 		},
 	}
 
 	return config
 }
 
+// parseDurationOrDefault reads a duration like "30d" or "1h" from the environment
+func parseDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value := utils.GetEnvOrDefault(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := utils.ParseExpiryTime(value)
+	if err != nil {
+		slog.Warn("Invalid duration, using default", "key", key, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
 func getDefaultLogLevel(env string) string {
 	if env == "production" {
 		return "warn"
@@ -150,3 +270,18 @@ func getDefaultRateLimit(env string) int {
 	}
 	return 1000
 }
+
+// getDefaultSlowDecisionThreshold reads a Go duration like "250ms" from SLOW_DECISION_THRESHOLD
+func getDefaultSlowDecisionThreshold() time.Duration {
+	const defaultThreshold = 250 * time.Millisecond
+	value := utils.GetEnvOrDefault("SLOW_DECISION_THRESHOLD", "")
+	if value == "" {
+		return defaultThreshold
+	}
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold <= 0 {
+		slog.Warn("Invalid duration, using default", "key", "SLOW_DECISION_THRESHOLD", "value", value, "default", defaultThreshold)
+		return defaultThreshold
+	}
+	return threshold
+}
@@ -2,14 +2,28 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/internal/config"
 	v1 "github.com/gov-dx-sandbox/exchange/policy-decision-point/v1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/auth"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi/pdpv1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/metrics"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"github.com/gov-dx-sandbox/exchange/shared/monitoring"
 	"github.com/gov-dx-sandbox/exchange/shared/utils"
+	"github.com/gov-dx-sandbox/shared/database"
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"google.golang.org/grpc"
 )
 
 // Build information - set during build
@@ -41,12 +55,22 @@ func main() {
 		"database", cfg.DBConfigs.Database,
 		"sslmode", cfg.DBConfigs.SSLMode)
 
-	// Log IDP configuration (for future use)
+	// Log IDP configuration
 	slog.Info("IDP configuration",
 		"org_name", cfg.IDPConfig.OrgName,
 		"issuer", cfg.IDPConfig.Issuer,
 		"audience", cfg.IDPConfig.Audience,
-		"jwks_url", cfg.IDPConfig.JwksURL)
+		"jwks_url", cfg.IDPConfig.JwksURL,
+		"tenant_claim", cfg.IDPConfig.TenantClaim,
+		"auth_disabled", cfg.IDPConfig.AuthDisabled)
+
+	// The HTTP and gRPC APIs require access tokens issued by the IDP to admins and internal services,
+	// unless PDP_AUTH_DISABLED=true for local deployments without an IDP
+	authenticator, err := newAuthenticator(cfg)
+	if err != nil {
+		slog.Error("Invalid authentication configuration", "error", err)
+		os.Exit(1)
+	}
 
 	// Initialize V1 GORM database connection
 	v1DbConfig := v1.NewDatabaseConfig(&cfg.DBConfigs)
@@ -64,6 +88,9 @@ func main() {
 		os.Exit(1)
 	}
 	defer func() {
+		if err := database.CloseReplicas(gormDB); err != nil {
+			slog.Error("Failed to close read replica connections", "error", err)
+		}
 		if err := v1SqlDB.Close(); err != nil {
 			slog.Error("Failed to close V1 database connection", "error", err)
 		} else {
@@ -71,15 +98,122 @@ func main() {
 		}
 	}()
 
+	// Initialize decision metrics; failures are logged and metrics stay disabled
+	if err := metrics.Init(monitoring.Meter()); err != nil {
+		slog.Error("Failed to initialize decision metrics", "error", err)
+	}
+	if err := metrics.RegisterGormCallbacks(gormDB); err != nil {
+		slog.Error("Failed to register database metrics", "error", err)
+	}
+
 	// Initialize V1 handlers
 	v1Handler := v1.NewHandler(gormDB)
+	v1Handler.SetSlowDecisionThreshold(cfg.Metrics.SlowDecisionThreshold)
+	v1Handler.SetRequireChangeApproval(cfg.PolicyAdmin.RequireChangeApproval)
+	if cfg.PolicyAdmin.RequireChangeApproval && authenticator == nil {
+		slog.Warn("Policy changes require approval but callers are not authenticated, so policy writes are rejected; " +
+			"set POLICY_CHANGE_APPROVAL_REQUIRED=false for local deployments without an IDP")
+	}
+	v1Handler.SetAuthenticator(authenticator)
+
+	// Decide as configured while the policy database is unavailable; decisions fail closed by default
+	decisionFallback, err := newDecisionFallback(cfg)
+	if err != nil {
+		slog.Error("Invalid decision fallback configuration", "error", err)
+		os.Exit(1)
+	}
+	v1Handler.Engine().SetDecisionFallback(decisionFallback)
+	slog.Info("Decision fallback configured",
+		"mode", decisionFallback.Mode,
+		"pinnedFields", len(decisionFallback.PinnedFields),
+		"maxStaleness", decisionFallback.MaxStaleness)
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+
+	// Start policy cache sync so decisions are served from memory
+	policyCache := v1Handler.PolicyCache()
+	go policyCache.Start(workerCtx, cfg.PolicyCache.PollInterval)
+	if err := metrics.RegisterPolicyCache(policyCache); err != nil {
+		slog.Error("Failed to register policy cache metrics", "error", err)
+	}
+
+	// Start kill switch sync so blocks set through any replica apply within seconds
+	go v1Handler.KillSwitches().Start(workerCtx, cfg.KillSwitch.PollInterval)
+
+	// Write the decision audit log in the background; queued decisions are written before the
+	// database connection is closed
+	decisionLogDone := make(chan struct{})
+	go func() {
+		defer close(decisionLogDone)
+		v1Handler.Engine().DecisionLog().Start(workerCtx)
+	}()
+	defer func() {
+		stopWorker()
+		<-decisionLogDone
+	}()
+
+	// Start allow list expiry cleanup worker
+	cleanupService := services.NewPolicyMetadataService(gormDB)
+	cleanupService.SetCache(policyCache)
+	cleanupWorker := services.NewAllowListCleanupWorker(
+		cleanupService,
+		cfg.AllowList.CleanupInterval,
+		cfg.AllowList.ExpiredRetention,
+	)
+	go cleanupWorker.Start(workerCtx)
+
+	// Start gRPC decision API for the orchestration engine
+	if cfg.Service.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.Service.GRPCPort)
+		if err != nil {
+			slog.Error("Failed to listen on gRPC port", "port", cfg.Service.GRPCPort, "error", err)
+			os.Exit(1)
+		}
+		grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcapi.RecoveryInterceptor, grpcapi.AuthInterceptor(authenticator)))
+		pdpv1.RegisterDecisionServiceServer(grpcServer, grpcapi.NewServer(v1Handler.Engine()))
+		go func() {
+			slog.Info("gRPC decision API listening", "port", cfg.Service.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				slog.Error("gRPC server failed", "error", err)
+			}
+		}()
+		defer grpcServer.GracefulStop()
+	}
 
 	// Setup routes
 	mux := http.NewServeMux()
 	v1Handler.SetupRoutes(mux) // V1 routes with /api/v1/policy/ prefix
 
-	// Health check endpoint
-	mux.Handle("/health", utils.PanicRecoveryMiddleware(utils.HealthHandler("policy-decision-point")))
+	// Metrics endpoint
+	mux.Handle("/metrics", monitoring.Handler())
+
+	// Health check endpoints: decisions need the database, unless a fallback mode can decide while it is
+	// down, in which case the replica stays ready as long as the fallback can. Until the policy cache
+	// loads, decisions are served from the database, more slowly.
+	healthChecker := utils.NewHealthChecker("policy-decision-point")
+	if decisionFallback.Mode == models.DecisionFallbackFailClosed {
+		healthChecker.Register("database", health.PingCheck(v1SqlDB))
+	} else {
+		databaseCheck := health.PingCheck(v1SqlDB)
+		healthChecker.RegisterOptional("database", databaseCheck)
+		policyService := v1Handler.Engine().PolicyService()
+		healthChecker.Register("decisions", func(ctx context.Context) error {
+			if err := databaseCheck(ctx); err != nil {
+				if fallbackErr := policyService.FallbackAvailable(); fallbackErr != nil {
+					return fmt.Errorf("%w; %s fallback unavailable: %v", err, decisionFallback.Mode, fallbackErr)
+				}
+			}
+			return nil
+		})
+	}
+	healthChecker.RegisterOptional("policy_cache", func(ctx context.Context) error {
+		if stats := policyCache.Stats(); !stats.Loaded {
+			return fmt.Errorf("policy cache not loaded: %s", stats.LastError)
+		}
+		return nil
+	})
+	healthChecker.RegisterRoutes(mux)
 
 	// Debug endpoint
 	mux.Handle("/debug", utils.PanicRecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -158,7 +292,7 @@ func main() {
 		WriteTimeout: cfg.Service.Timeout,
 		IdleTimeout:  60 * time.Second,
 	}
-	server := utils.CreateServer(serverConfig, mux)
+	server := utils.CreateServer(serverConfig, monitoring.HTTPMetricsMiddleware(requestid.Middleware(mux)))
 
 	// Start server with graceful shutdown
 	if err := utils.StartServerWithGracefulShutdown(server, "policy-decision-point"); err != nil {
@@ -166,3 +300,62 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newAuthenticator builds the authenticator of API callers from the IDP configuration; it returns nil
+// when authentication is disabled
+func newAuthenticator(cfg *config.Config) (*auth.Authenticator, error) {
+	if cfg.IDPConfig.AuthDisabled {
+		slog.Warn("PDP_AUTH_DISABLED is set, the PDP APIs are unauthenticated")
+		return nil, nil
+	}
+	var audiences []string
+	for _, audience := range strings.Split(cfg.IDPConfig.Audience, ",") {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			audiences = append(audiences, audience)
+		}
+	}
+	authConfig := auth.Config{
+		JWKSURL:     cfg.IDPConfig.JwksURL,
+		Issuer:      cfg.IDPConfig.Issuer,
+		Audiences:   audiences,
+		TenantClaim: cfg.IDPConfig.TenantClaim,
+	}
+	if err := authConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return auth.NewAuthenticator(authConfig), nil
+}
+
+// newDecisionFallback builds the decision fallback from the configuration. Serving the last-known-good
+// policies needs them, and the kill switches, to be kept in memory.
+func newDecisionFallback(cfg *config.Config) (services.DecisionFallback, error) {
+	mode, err := models.ParseDecisionFallbackMode(cfg.Fallback.Mode)
+	if err != nil {
+		return services.DecisionFallback{}, err
+	}
+	pinnedFields, err := models.ParsePinnedFields(cfg.Fallback.PinnedFields)
+	if err != nil {
+		return services.DecisionFallback{}, err
+	}
+	fallback := services.DecisionFallback{
+		Mode:         mode,
+		PinnedFields: pinnedFields,
+		MaxStaleness: cfg.Fallback.MaxStaleness,
+	}
+	if err := fallback.Validate(); err != nil {
+		return services.DecisionFallback{}, err
+	}
+
+	if mode == models.DecisionFallbackLastKnownGood {
+		if cfg.PolicyCache.PollInterval <= 0 || cfg.KillSwitch.PollInterval <= 0 {
+			return services.DecisionFallback{}, fmt.Errorf("last_known_good requires the policy cache and kill switch polling to be enabled")
+		}
+		if cfg.PolicyCache.PollInterval >= fallback.MaxStaleness || cfg.KillSwitch.PollInterval >= fallback.MaxStaleness {
+			slog.Warn("Decision fallback staleness limit is not longer than the poll intervals; cached policies may be too stale to serve between polls",
+				"maxStaleness", fallback.MaxStaleness,
+				"policyCachePollInterval", cfg.PolicyCache.PollInterval,
+				"killSwitchPollInterval", cfg.KillSwitch.PollInterval)
+		}
+	}
+	return fallback, nil
+}
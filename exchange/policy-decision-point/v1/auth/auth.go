@@ -0,0 +1,384 @@
+// Package auth authenticates the callers of the PDP's HTTP and gRPC APIs with access tokens issued by
+// the identity provider. The tenant a caller administers and the actor recorded for policy changes are
+// taken from the token's claims, never from request headers.
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/shared/utils"
+)
+
+// Roles issued by the identity provider, as used by portal-backend
+const (
+	RoleAdmin  = "OpenDIF_Admin"  // Administers policies, kill switches and change sets
+	RoleSystem = "OpenDIF_System" // Internal services: the portal and the orchestration engine
+)
+
+// DefaultTenantClaim is the token claim naming the tenant a caller is bound to
+const DefaultTenantClaim = "tenant_id"
+
+var (
+	// ErrUnauthenticated is returned for missing or invalid access tokens
+	ErrUnauthenticated = errors.New("invalid or missing access token")
+	// ErrForbidden is returned for callers without a PDP role, or acting for another tenant
+	ErrForbidden = errors.New("insufficient permissions")
+)
+
+// Principal is the authenticated caller
+type Principal struct {
+	Subject string
+	Roles   []string
+	// TenantID is the tenant the caller is bound to, from the tenant claim; callers without one are
+	// bound to the default tenant
+	TenantID string
+}
+
+// IsAdmin reports whether the caller is a policy administrator
+func (p *Principal) IsAdmin() bool {
+	return slices.Contains(p.Roles, RoleAdmin)
+}
+
+// canCallPDP reports whether the caller has a role the PDP serves
+func (p *Principal) canCallPDP() bool {
+	return p.IsAdmin() || slices.Contains(p.Roles, RoleSystem)
+}
+
+// CheckTenant returns ErrForbidden if a tenant requested explicitly is not the caller's tenant
+func (p *Principal) CheckTenant(requested string) error {
+	if requested != "" && requested != p.TenantID {
+		return fmt.Errorf("%w: the token is bound to tenant %s", ErrForbidden, p.TenantID)
+	}
+	return nil
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if the request was authenticated
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// RequireAdmin returns the authenticated policy administrator of ctx: ErrUnauthenticated if the
+// request was not authenticated, or ErrForbidden if the caller is not an administrator
+func RequireAdmin(ctx context.Context) (*Principal, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if !principal.IsAdmin() {
+		return nil, fmt.Errorf("%w: the %s role is required", ErrForbidden, RoleAdmin)
+	}
+	return principal, nil
+}
+
+// Config contains the identity provider the PDP's access tokens are issued by
+type Config struct {
+	JWKSURL string
+	Issuer  string
+	// Audiences are the client IDs whose tokens are accepted
+	Audiences []string
+	// TenantClaim defaults to DefaultTenantClaim
+	TenantClaim string
+	Timeout     time.Duration
+}
+
+// Validate checks that the configuration is complete
+func (c Config) Validate() error {
+	if c.JWKSURL == "" {
+		return fmt.Errorf("JWKS URL is required for authentication")
+	}
+	if c.Issuer == "" {
+		return fmt.Errorf("issuer is required for authentication")
+	}
+	if len(c.Audiences) == 0 {
+		return fmt.Errorf("at least one audience is required for authentication")
+	}
+	for i, audience := range c.Audiences {
+		if strings.TrimSpace(audience) == "" {
+			return fmt.Errorf("audience at index %d is empty", i)
+		}
+	}
+	return nil
+}
+
+// Authenticator validates access tokens against the identity provider's JWKS.
+// A nil Authenticator performs no authentication, for local deployments without an identity provider.
+// Thread-safe: All methods can be called concurrently from multiple goroutines
+type Authenticator struct {
+	config     Config
+	httpClient *http.Client
+
+	// keysMutex guards both keys and lastFetch
+	keysMutex sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	lastFetch time.Time
+}
+
+// NewAuthenticator creates an authenticator for tokens issued as configured
+func NewAuthenticator(config Config) *Authenticator {
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.TenantClaim == "" {
+		config.TenantClaim = DefaultTenantClaim
+	}
+	return &Authenticator{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		keys:       make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Middleware requires a valid access token from an admin or internal service on every request. A
+// request naming a tenant in the X-Tenant-ID header other than the token's is rejected.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.Authenticate(r.Header.Get("Authorization"))
+		if err == nil {
+			err = principal.CheckTenant(strings.TrimSpace(r.Header.Get(models.TenantIDHeader)))
+		}
+		if err != nil {
+			slog.Warn("PDP request rejected", "error", err, "path", r.URL.Path, "method", r.Method)
+			RespondWithError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// Authenticate validates the bearer token in an Authorization header value and returns the caller,
+// who must be an admin or internal service
+func (a *Authenticator) Authenticate(authorization string) (*Principal, error) {
+	tokenString, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || strings.TrimSpace(tokenString) == "" {
+		return nil, ErrUnauthenticated
+	}
+	principal, err := a.validateToken(strings.TrimSpace(tokenString))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	if !principal.canCallPDP() {
+		return nil, fmt.Errorf("%w: %s has no PDP role", ErrForbidden, principal.Subject)
+	}
+	return principal, nil
+}
+
+// RespondWithError writes the HTTP error for an authentication or authorization failure
+func RespondWithError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrForbidden):
+		utils.RespondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusUnauthorized, ErrUnauthenticated.Error())
+	}
+}
+
+// validateToken validates a JWT token and returns the caller it was issued to
+func (a *Authenticator) validateToken(tokenString string) (*Principal, error) {
+	if err := a.ensureKeysFresh(); err != nil {
+		return nil, fmt.Errorf("failed to ensure fresh keys: %w", err)
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, a.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(a.config.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	audience, err := claims.GetAudience()
+	if err != nil {
+		return nil, fmt.Errorf("invalid audience: %w", err)
+	}
+	if !slices.ContainsFunc(audience, func(aud string) bool { return slices.Contains(a.config.Audiences, aud) }) {
+		return nil, fmt.Errorf("invalid audience: expected one of %v, got %v", a.config.Audiences, audience)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("subject claim is missing")
+	}
+
+	tenantID, _ := claims[a.config.TenantClaim].(string)
+	if tenantID == "" {
+		tenantID = models.DefaultTenantID
+	}
+
+	return &Principal{
+		Subject:  subject,
+		Roles:    claimStrings(claims, "roles"),
+		TenantID: tenantID,
+	}, nil
+}
+
+// keyFunc returns the public key a token was signed with, refreshing the JWKS once for unknown key IDs
+func (a *Authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing 'kid' in token header")
+	}
+
+	a.keysMutex.RLock()
+	publicKey, exists := a.keys[kid]
+	a.keysMutex.RUnlock()
+	if exists {
+		return publicKey, nil
+	}
+
+	slog.Info("Key not found, refreshing JWKS", "kid", kid)
+	if err := a.fetchJWKS(); err != nil {
+		return nil, fmt.Errorf("failed to refresh JWKS: %w", err)
+	}
+
+	a.keysMutex.RLock()
+	publicKey, exists = a.keys[kid]
+	a.keysMutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no public key found for kid: %s", kid)
+	}
+	return publicKey, nil
+}
+
+// claimStrings reads a claim holding either a single string or an array of strings
+func claimStrings(claims jwt.MapClaims, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// jwks is the JSON Web Key Set served by the identity provider
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// fetchJWKS fetches the JWKS from the configured endpoint
+func (a *Authenticator) fetchJWKS() error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.config.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	var keySet jwks
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	newKeys := make(map[string]*rsa.PublicKey)
+	for _, key := range keySet.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		publicKey, err := buildRSAPublicKey(key.N, key.E)
+		if err != nil {
+			slog.Warn("Failed to build RSA public key", "kid", key.Kid, "error", err)
+			continue
+		}
+		newKeys[key.Kid] = publicKey
+	}
+
+	a.keysMutex.Lock()
+	a.keys = newKeys
+	a.lastFetch = time.Now()
+	a.keysMutex.Unlock()
+
+	slog.Info("Successfully fetched JWKS", "keys_count", len(newKeys))
+	return nil
+}
+
+// buildRSAPublicKey constructs an RSA public key from its base64url encoded modulus and exponent
+func buildRSAPublicKey(nStr, eStr string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(nStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(eStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode exponent: %w", err)
+	}
+
+	n := new(big.Int).SetBytes(nBytes)
+	e := new(big.Int).SetBytes(eBytes)
+	if n.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA modulus too small: %d bits, minimum 2048 required", n.BitLen())
+	}
+	if !e.IsInt64() || e.Int64() < 2 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+// ensureKeysFresh refreshes the JWKS if it has not been fetched in the last hour
+func (a *Authenticator) ensureKeysFresh() error {
+	a.keysMutex.RLock()
+	needsRefresh := len(a.keys) == 0 || time.Since(a.lastFetch) > time.Hour
+	a.keysMutex.RUnlock()
+
+	if needsRefresh {
+		return a.fetchJWKS()
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://idp.example.com/oauth2/token"
+	testAudience = "orchestration-engine"
+	testKeyID    = "test-key"
+)
+
+// setupAuthenticator starts a JWKS endpoint serving the public half of a new signing key
+func setupAuthenticator(t *testing.T) (*Authenticator, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(server.Close)
+
+	config := Config{JWKSURL: server.URL, Issuer: testIssuer, Audiences: []string{testAudience}}
+	require.NoError(t, config.Validate())
+	return NewAuthenticator(config), key
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	base := jwt.MapClaims{
+		"iss": testIssuer,
+		"aud": testAudience,
+		"sub": "service-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		base[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, base)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	authenticator, key := setupAuthenticator(t)
+
+	var principal *Principal
+	handler := authenticator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	call := func(token, tenantID string) int {
+		principal = nil
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/policy/export", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if tenantID != "" {
+			req.Header.Set(models.TenantIDHeader, tenantID)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("missing token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, call("", ""))
+	})
+
+	t.Run("invalid audience", func(t *testing.T) {
+		token := signToken(t, key, jwt.MapClaims{"aud": "other-client", "roles": RoleSystem})
+		assert.Equal(t, http.StatusUnauthorized, call(token, ""))
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		token := signToken(t, key, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix(), "roles": RoleSystem})
+		assert.Equal(t, http.StatusUnauthorized, call(token, ""))
+	})
+
+	t.Run("no PDP role", func(t *testing.T) {
+		token := signToken(t, key, jwt.MapClaims{"roles": []interface{}{"OpenDIF_Member"}})
+		assert.Equal(t, http.StatusForbidden, call(token, ""))
+	})
+
+	t.Run("service without a tenant claim is bound to the default tenant", func(t *testing.T) {
+		token := signToken(t, key, jwt.MapClaims{"roles": RoleSystem})
+		assert.Equal(t, http.StatusOK, call(token, ""))
+		require.NotNil(t, principal)
+		assert.Equal(t, "service-1", principal.Subject)
+		assert.Equal(t, models.DefaultTenantID, principal.TenantID)
+		assert.False(t, principal.IsAdmin())
+
+		assert.Equal(t, http.StatusForbidden, call(token, "tenant-b"))
+	})
+
+	t.Run("admin bound to a tenant", func(t *testing.T) {
+		token := signToken(t, key, jwt.MapClaims{"sub": "admin-1", "roles": []interface{}{RoleAdmin}, "tenant_id": "tenant-a"})
+		assert.Equal(t, http.StatusOK, call(token, ""))
+		require.NotNil(t, principal)
+		assert.Equal(t, "tenant-a", principal.TenantID)
+		assert.True(t, principal.IsAdmin())
+
+		assert.Equal(t, http.StatusOK, call(token, "tenant-a"))
+		assert.Equal(t, http.StatusForbidden, call(token, "tenant-b"))
+		assert.Nil(t, principal)
+	})
+}
+
+func TestAuthenticator_NilAdmitsEveryRequest(t *testing.T) {
+	var authenticator *Authenticator
+	called := false
+	handler := authenticator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_, ok := PrincipalFromContext(r.Context())
+		assert.False(t, ok)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/policy/export", nil))
+	assert.True(t, called)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{JWKSURL: "https://idp.example.com/oauth2/jwks", Issuer: testIssuer, Audiences: []string{testAudience}}
+	assert.NoError(t, valid.Validate())
+
+	for name, config := range map[string]Config{
+		"no JWKS URL":    {Issuer: testIssuer, Audiences: []string{testAudience}},
+		"no issuer":      {JWKSURL: valid.JWKSURL, Audiences: []string{testAudience}},
+		"no audience":    {JWKSURL: valid.JWKSURL, Issuer: testIssuer},
+		"blank audience": {JWKSURL: valid.JWKSURL, Issuer: testIssuer, Audiences: []string{" "}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, config.Validate())
+		})
+	}
+}
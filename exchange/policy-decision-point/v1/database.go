@@ -8,6 +8,7 @@ import (
 
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/internal/config"
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/shared/database"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -25,6 +26,8 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+	// ReplicaDSNs are the read replicas that read-only service methods are routed to
+	ReplicaDSNs []string
 }
 
 // NewDatabaseConfig creates a new GORM database configuration for V1
@@ -40,6 +43,7 @@ func NewDatabaseConfig(dbConfigs *config.DBConfigs) *DatabaseConfig {
 		MaxIdleConns:    5,
 		ConnMaxLifetime: time.Hour,
 		ConnMaxIdleTime: 30 * time.Minute,
+		ReplicaDSNs:     dbConfigs.ReplicaDSNs,
 	}
 }
 
@@ -80,6 +84,10 @@ func ConnectGormDB(config *DatabaseConfig) (*gorm.DB, error) {
 		"port", config.Port,
 		"database", config.Database)
 
+	if err := useReadReplicas(db, config); err != nil {
+		return nil, err
+	}
+
 	// Only run migration if environment variable is set
 	if os.Getenv("RUN_MIGRATION") == "true" {
 		slog.Info("Running GORM auto-migration for V1 models")
@@ -112,6 +120,12 @@ func ConnectGormDB(config *DatabaseConfig) (*gorm.DB, error) {
 
 		err = db.AutoMigrate(
 			&models.PolicyMetadata{},
+			&models.AllowListRenewalRequest{},
+			&models.PolicyDecisionLog{},
+			&models.PolicyDecisionLogField{},
+			&models.KillSwitch{},
+			&models.PolicyChangeSet{},
+			&models.PolicyVersion{},
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to run auto-migration: %w", err)
@@ -123,3 +137,38 @@ func ConnectGormDB(config *DatabaseConfig) (*gorm.DB, error) {
 
 	return db, nil
 }
+
+// useReadReplicas connects to the read replicas of the config and routes the reads made through
+// database.Reader to them
+func useReadReplicas(db *gorm.DB, config *DatabaseConfig) error {
+	if len(config.ReplicaDSNs) == 0 {
+		return nil
+	}
+
+	replicas := make([]database.Replica, 0, len(config.ReplicaDSNs))
+	for i, dsn := range config.ReplicaDSNs {
+		// Replicas that do not answer yet are kept; the router reads from them once they do
+		replicaDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger:               logger.Default.LogMode(logger.Warn),
+			DisableAutomaticPing: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open read replica %d: %w", i+1, err)
+		}
+		sqlDB, err := replicaDB.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get underlying sql.DB of read replica %d: %w", i+1, err)
+		}
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+		replicas = append(replicas, database.Replica{Name: fmt.Sprintf("replica-%d", i+1), DB: sqlDB})
+	}
+
+	if err := db.Use(database.NewReplicaRouter(replicas, database.DefaultHealthCheckInterval)); err != nil {
+		return fmt.Errorf("failed to register read replicas: %w", err)
+	}
+	slog.Info("Routing read-only queries to read replicas", "replicas", len(replicas))
+	return nil
+}
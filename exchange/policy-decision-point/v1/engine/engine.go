@@ -0,0 +1,100 @@
+// Package engine evaluates policy decisions in process, without HTTP. The PDP's HTTP and gRPC APIs
+// serve decisions through an Engine, and latency-critical deployments of the orchestration engine
+// embed one over the same policy database so that both modes always decide alike.
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"gorm.io/gorm"
+)
+
+// Engine evaluates policy decisions and consent requirements against the policy store
+type Engine struct {
+	policyService      *services.PolicyMetadataService
+	decisionLogService *services.DecisionLogService
+	policyCache        *services.PolicyCache
+	killSwitches       *services.KillSwitchService
+}
+
+// New creates an engine backed by the policy database. Until the engine is started, decisions read
+// policies and kill switches from the database.
+func New(db *gorm.DB) *Engine {
+	policyCache := services.NewPolicyCache(db)
+	killSwitches := services.NewKillSwitchService(db)
+	policyService := services.NewPolicyMetadataService(db)
+	policyService.SetCache(policyCache)
+	policyService.SetKillSwitches(killSwitches)
+	return &Engine{
+		policyService:      policyService,
+		decisionLogService: services.NewDecisionLogService(db),
+		policyCache:        policyCache,
+		killSwitches:       killSwitches,
+	}
+}
+
+// Start syncs the policy cache and kill switches in the background until ctx is cancelled, so that
+// decisions are served from memory and blocks set through any replica apply within seconds. Decisions
+// are recorded in the audit log in the background, too.
+func (e *Engine) Start(ctx context.Context, cachePollInterval, killSwitchPollInterval time.Duration) {
+	go e.policyCache.Start(ctx, cachePollInterval)
+	go e.killSwitches.Start(ctx, killSwitchPollInterval)
+	go e.decisionLogService.Start(ctx)
+}
+
+// SetDecisionFallback sets how decisions are made while the policy database is unavailable; by default
+// they fail closed
+func (e *Engine) SetDecisionFallback(fallback services.DecisionFallback) {
+	e.policyService.SetDecisionFallback(fallback)
+}
+
+// PolicyService returns the policy metadata service decisions are evaluated with
+func (e *Engine) PolicyService() *services.PolicyMetadataService {
+	return e.policyService
+}
+
+// PolicyCache returns the policy cache used for decisions
+func (e *Engine) PolicyCache() *services.PolicyCache {
+	return e.policyCache
+}
+
+// DecisionLog returns the decision audit log decisions are recorded in
+func (e *Engine) DecisionLog() *services.DecisionLogService {
+	return e.decisionLogService
+}
+
+// KillSwitches returns the kill switches checked before policy evaluation
+func (e *Engine) KillSwitches() *services.KillSwitchService {
+	return e.killSwitches
+}
+
+// Decide evaluates a policy decision request and records it in the decision audit log
+func (e *Engine) Decide(req *models.PolicyDecisionRequest) (*models.PolicyDecisionResponse, error) {
+	start := time.Now()
+	resp, err := e.policyService.GetPolicyDecision(req)
+	latency := time.Since(start)
+
+	// Recording failures must not block the decision itself
+	if recordErr := e.decisionLogService.RecordDecision(req, resp, err, latency); recordErr != nil {
+		slog.Error("Failed to record policy decision", "applicationId", req.ApplicationID, "error", recordErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ConsentRequirements consolidates the consent the orchestration engine must request before
+// releasing the requested fields
+func (e *Engine) ConsentRequirements(req *models.PolicyDecisionRequest) (*models.ConsentRequirementsResponse, error) {
+	if req.ApplicationID == "" || len(req.RequiredFields) == 0 {
+		return nil, fmt.Errorf("%w: applicationId and requiredFields are required", services.ErrInvalidInput)
+	}
+	return e.policyService.GetConsentRequirements(req)
+}
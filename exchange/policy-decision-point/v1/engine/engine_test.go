@@ -0,0 +1,196 @@
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/gov-dx-sandbox/exchange/policy-decision-point/v1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/engine"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi/pdpv1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// seedPolicies stores policies covering each decision outcome: a public field, a field that needs
+// the citizen's consent, and a blocked application
+func seedPolicies(t *testing.T, db *gorm.DB) {
+	policyService := services.NewPolicyMetadataService(db)
+	_, err := policyService.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{
+				FieldName:         "person.fullName",
+				Source:            models.SourcePrimary,
+				IsOwner:           true,
+				AccessControlType: models.AccessControlTypePublic,
+			},
+			{
+				FieldName:         "person.nic",
+				DisplayName:       testhelpers.StringPtr("NIC"),
+				Source:            models.SourcePrimary,
+				IsOwner:           false,
+				AccessControlType: models.AccessControlTypeRestricted,
+				Owner:             testhelpers.OwnerPtr(models.OwnerCitizen),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	for _, appID := range []string{"app-1", "app-blocked"} {
+		_, err = policyService.UpdateAllowList(&models.AllowListUpdateRequest{
+			ApplicationID: appID,
+			Records: []models.AllowListUpdateRequestRecord{
+				{SchemaID: "schema-123", FieldName: "person.fullName"},
+				{SchemaID: "schema-123", FieldName: "person.nic"},
+			},
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = services.NewKillSwitchService(db).SetKillSwitch(&models.KillSwitchRequest{
+		TargetType: models.KillSwitchTargetApplication,
+		TargetID:   "app-blocked",
+	})
+	require.NoError(t, err)
+}
+
+// grpcClient serves the decision service for the engine over an in-memory listener
+func grpcClient(t *testing.T, decisionEngine *engine.Engine) pdpv1.DecisionServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pdpv1.RegisterDecisionServiceServer(server, grpcapi.NewServer(decisionEngine))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return pdpv1.NewDecisionServiceClient(conn)
+}
+
+// post sends the request to the HTTP API and returns the response body
+func post(t *testing.T, mux *http.ServeMux, path string, req *models.PolicyDecisionRequest) (int, []byte) {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	return w.Code, w.Body.Bytes()
+}
+
+// TestEngine_Parity checks that the embedded engine decides exactly like the HTTP and gRPC APIs over
+// the same policy store
+func TestEngine_Parity(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	seedPolicies(t, db)
+
+	embedded := engine.New(db)
+	mux := http.NewServeMux()
+	v1.NewHandler(db).SetupRoutes(mux)
+	client := grpcClient(t, engine.New(db))
+
+	requests := map[string]*models.PolicyDecisionRequest{
+		"authorized": {
+			ApplicationID:  "app-1",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{SchemaID: "schema-123", FieldName: "person.fullName"}},
+		},
+		"consent required": {
+			ApplicationID: "app-1",
+			RequiredFields: []models.PolicyDecisionRequestRecord{
+				{SchemaID: "schema-123", FieldName: "person.fullName"},
+				{SchemaID: "schema-123", FieldName: "person.nic"},
+			},
+		},
+		"not allow-listed": {
+			ApplicationID:  "app-2",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{SchemaID: "schema-123", FieldName: "person.nic"}},
+		},
+		"blocked": {
+			ApplicationID:  "app-blocked",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{SchemaID: "schema-123", FieldName: "person.fullName"}},
+		},
+	}
+
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			decision, err := embedded.Decide(req)
+			require.NoError(t, err)
+			embeddedJSON, err := json.Marshal(decision)
+			require.NoError(t, err)
+
+			status, httpJSON := post(t, mux, "/api/v1/policy/decide", req)
+			require.Equal(t, http.StatusOK, status)
+			assert.JSONEq(t, string(embeddedJSON), string(httpJSON), "HTTP decision")
+
+			grpcRequest, err := grpcapi.DecisionRequestToProto(req)
+			require.NoError(t, err)
+			grpcDecision, err := client.Decide(context.Background(), grpcRequest)
+			require.NoError(t, err)
+			grpcJSON, err := json.Marshal(grpcapi.DecisionResponseFromProto(grpcDecision))
+			require.NoError(t, err)
+			assert.JSONEq(t, string(embeddedJSON), string(grpcJSON), "gRPC decision")
+
+			requirements, err := embedded.ConsentRequirements(req)
+			require.NoError(t, err)
+			requirementsJSON, err := json.Marshal(requirements)
+			require.NoError(t, err)
+
+			status, httpJSON = post(t, mux, "/api/v1/policy/requirements", req)
+			require.Equal(t, http.StatusOK, status)
+			assert.JSONEq(t, string(requirementsJSON), string(httpJSON), "HTTP consent requirements")
+
+			grpcRequirements, err := client.GetConsentRequirements(context.Background(), grpcRequest)
+			require.NoError(t, err)
+			grpcJSON, err = json.Marshal(grpcapi.ConsentRequirementsFromProto(grpcRequirements))
+			require.NoError(t, err)
+			assert.JSONEq(t, string(requirementsJSON), string(grpcJSON), "gRPC consent requirements")
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		unknown := &models.PolicyDecisionRequest{
+			ApplicationID:  "app-1",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{SchemaID: "schema-123", FieldName: "person.unknown"}},
+		}
+		_, err := embedded.Decide(unknown)
+		assert.Error(t, err)
+		status, _ := post(t, mux, "/api/v1/policy/decide", unknown)
+		assert.Equal(t, http.StatusInternalServerError, status)
+		grpcUnknown, err := grpcapi.DecisionRequestToProto(unknown)
+		require.NoError(t, err)
+		_, err = client.Decide(context.Background(), grpcUnknown)
+		assert.Error(t, err)
+
+		empty := &models.PolicyDecisionRequest{ApplicationID: "app-1"}
+		_, err = embedded.ConsentRequirements(empty)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		status, _ = post(t, mux, "/api/v1/policy/requirements", empty)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("decisions are recorded in every mode", func(t *testing.T) {
+		var count int64
+		require.NoError(t, db.Model(&models.PolicyDecisionLog{}).Count(&count).Error)
+		assert.Equal(t, int64(3*(len(requests)+1)), count)
+	})
+}
@@ -0,0 +1,269 @@
+package grpcapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi/pdpv1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The conversions below map the HTTP API's models to the protobuf messages and back, so Go callers
+// can keep working with the models. Empty lists convert to nil or empty slices the way the decision
+// engine returns them, so converted messages encode like the HTTP API's responses.
+
+// DecisionRequestToProto converts a decision request to its protobuf message
+func DecisionRequestToProto(req *models.PolicyDecisionRequest) (*pdpv1.DecisionRequest, error) {
+	out := &pdpv1.DecisionRequest{
+		ApplicationId:  req.ApplicationID,
+		RequiredFields: make([]*pdpv1.FieldRef, 0, len(req.RequiredFields)),
+	}
+	for _, field := range req.RequiredFields {
+		out.RequiredFields = append(out.RequiredFields, &pdpv1.FieldRef{SchemaId: field.SchemaID, FieldName: field.FieldName})
+	}
+	if req.Context != nil {
+		context, err := structpb.NewStruct(req.Context)
+		if err != nil {
+			return nil, fmt.Errorf("invalid decision context: %w", err)
+		}
+		out.Context = context
+	}
+	return out, nil
+}
+
+// DecisionRequestFromProto converts a decision request message to the model
+func DecisionRequestFromProto(req *pdpv1.DecisionRequest) *models.PolicyDecisionRequest {
+	out := &models.PolicyDecisionRequest{ApplicationID: req.GetApplicationId()}
+	for _, field := range req.GetRequiredFields() {
+		out.RequiredFields = append(out.RequiredFields, models.PolicyDecisionRequestRecord{
+			SchemaID:  field.GetSchemaId(),
+			FieldName: field.GetFieldName(),
+		})
+	}
+	if req.GetContext() != nil {
+		out.Context = req.GetContext().AsMap()
+	}
+	return out
+}
+
+// DecisionResponseToProto converts a decision to its protobuf message
+func DecisionResponseToProto(resp *models.PolicyDecisionResponse) (*pdpv1.DecisionResponse, error) {
+	out := &pdpv1.DecisionResponse{
+		AppAuthorized:           resp.AppAuthorized,
+		AppAccessExpired:        resp.AppAccessExpired,
+		AppRequiresOwnerConsent: resp.AppRequiresOwnerConsent,
+		PolicyVersion:           resp.PolicyVersion,
+		Fallback:                string(resp.Fallback),
+	}
+	var err error
+	if out.UnauthorizedFields, err = fieldsToProto(resp.UnauthorizedFields); err != nil {
+		return nil, err
+	}
+	if out.ExpiredFields, err = fieldsToProto(resp.ExpiredFields); err != nil {
+		return nil, err
+	}
+	if out.ConsentRequiredFields, err = fieldsToProto(resp.ConsentRequiredFields); err != nil {
+		return nil, err
+	}
+	if out.ConditionFailedFields, err = fieldsToProto(resp.ConditionFailedFields); err != nil {
+		return nil, err
+	}
+	if out.BlockedFields, err = fieldsToProto(resp.BlockedFields); err != nil {
+		return nil, err
+	}
+	for _, field := range resp.DeprecatedFields {
+		deprecated := &pdpv1.DeprecatedField{
+			FieldName: field.FieldName,
+			SchemaId:  field.SchemaID,
+			Status:    string(field.Status),
+			Message:   field.Message,
+		}
+		if field.SunsetAt != nil {
+			deprecated.SunsetAt = timestamppb.New(*field.SunsetAt)
+		}
+		out.DeprecatedFields = append(out.DeprecatedFields, deprecated)
+	}
+	return out, nil
+}
+
+// DecisionResponseFromProto converts a decision message to the model
+func DecisionResponseFromProto(resp *pdpv1.DecisionResponse) *models.PolicyDecisionResponse {
+	out := &models.PolicyDecisionResponse{
+		AppAuthorized:           resp.GetAppAuthorized(),
+		UnauthorizedFields:      fieldsFromProto(resp.GetUnauthorizedFields()),
+		AppAccessExpired:        resp.GetAppAccessExpired(),
+		ExpiredFields:           fieldsFromProto(resp.GetExpiredFields()),
+		AppRequiresOwnerConsent: resp.GetAppRequiresOwnerConsent(),
+		ConsentRequiredFields:   fieldsFromProto(resp.GetConsentRequiredFields()),
+		ConditionFailedFields:   fieldsFromProto(resp.GetConditionFailedFields()),
+		BlockedFields:           fieldsFromProto(resp.GetBlockedFields()),
+		PolicyVersion:           resp.GetPolicyVersion(),
+		Fallback:                models.DecisionFallbackMode(resp.GetFallback()),
+	}
+	for _, field := range resp.GetDeprecatedFields() {
+		deprecated := models.PolicyDecisionDeprecatedFieldRecord{
+			FieldName: field.GetFieldName(),
+			SchemaID:  field.GetSchemaId(),
+			Status:    models.SchemaLifecycleStatus(field.GetStatus()),
+			Message:   field.Message,
+		}
+		if field.GetSunsetAt() != nil {
+			sunsetAt := field.GetSunsetAt().AsTime()
+			deprecated.SunsetAt = &sunsetAt
+		}
+		out.DeprecatedFields = append(out.DeprecatedFields, deprecated)
+	}
+	return out
+}
+
+// ConsentRequirementsToProto converts consent requirements to their protobuf message
+func ConsentRequirementsToProto(resp *models.ConsentRequirementsResponse) (*pdpv1.ConsentRequirementsResponse, error) {
+	out := &pdpv1.ConsentRequirementsResponse{
+		ApplicationId:   resp.ApplicationID,
+		ConsentRequired: resp.ConsentRequired,
+		PolicyVersion:   resp.PolicyVersion,
+	}
+	for _, requirement := range resp.Requirements {
+		fields, err := fieldsToProto(requirement.Fields)
+		if err != nil {
+			return nil, err
+		}
+		out.Requirements = append(out.Requirements, &pdpv1.ConsentRequirement{
+			Owner:         string(requirement.Owner),
+			ConsentType:   requirement.ConsentType,
+			GrantDuration: requirement.GrantDuration,
+			Purposes:      requirement.Purposes,
+			Fields:        fields,
+		})
+	}
+	return out, nil
+}
+
+// ConsentRequirementsFromProto converts a consent requirements message to the model
+func ConsentRequirementsFromProto(resp *pdpv1.ConsentRequirementsResponse) *models.ConsentRequirementsResponse {
+	out := &models.ConsentRequirementsResponse{
+		ApplicationID:   resp.GetApplicationId(),
+		ConsentRequired: resp.GetConsentRequired(),
+		Requirements:    make([]models.ConsentRequirement, 0, len(resp.GetRequirements())),
+		PolicyVersion:   resp.GetPolicyVersion(),
+	}
+	for _, requirement := range resp.GetRequirements() {
+		out.Requirements = append(out.Requirements, models.ConsentRequirement{
+			Owner:         models.Owner(requirement.GetOwner()),
+			ConsentType:   requirement.GetConsentType(),
+			GrantDuration: requirement.GetGrantDuration(),
+			Purposes:      requirement.GetPurposes(),
+			Fields:        fieldsFromProto(requirement.GetFields()),
+		})
+	}
+	return out
+}
+
+// GrantsToProto converts a consumer's grants to their protobuf message
+func GrantsToProto(resp *models.ConsumerGrantListResponse) (*pdpv1.GetGrantsResponse, error) {
+	out := &pdpv1.GetGrantsResponse{Count: int32(resp.Count)}
+	for _, grant := range resp.Records {
+		expiresAt, err := time.Parse(time.RFC3339, grant.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grant expiry %q: %w", grant.ExpiresAt, err)
+		}
+		updatedAt, err := time.Parse(time.RFC3339, grant.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grant update time %q: %w", grant.UpdatedAt, err)
+		}
+		out.Records = append(out.Records, &pdpv1.Grant{
+			SchemaId:       grant.SchemaID,
+			FieldName:      grant.FieldName,
+			DisplayName:    grant.DisplayName,
+			Classification: string(grant.Classification),
+			ApplicationId:  grant.ApplicationID,
+			ConsumerId:     grant.ConsumerID,
+			ExpiresAt:      timestamppb.New(expiresAt),
+			UpdatedAt:      timestamppb.New(updatedAt),
+			Expired:        grant.Expired,
+		})
+	}
+	return out, nil
+}
+
+// GrantsFromProto converts a grants message to the model
+func GrantsFromProto(resp *pdpv1.GetGrantsResponse) *models.ConsumerGrantListResponse {
+	out := &models.ConsumerGrantListResponse{
+		Records: make([]models.ConsumerGrantResponse, 0, len(resp.GetRecords())),
+		Count:   int(resp.GetCount()),
+	}
+	for _, grant := range resp.GetRecords() {
+		out.Records = append(out.Records, models.ConsumerGrantResponse{
+			SchemaID:       grant.GetSchemaId(),
+			FieldName:      grant.GetFieldName(),
+			DisplayName:    grant.DisplayName,
+			Classification: models.Classification(grant.GetClassification()),
+			ApplicationID:  grant.GetApplicationId(),
+			ConsumerID:     grant.GetConsumerId(),
+			ExpiresAt:      grant.GetExpiresAt().AsTime().Format(time.RFC3339),
+			UpdatedAt:      grant.GetUpdatedAt().AsTime().Format(time.RFC3339),
+			Expired:        grant.GetExpired(),
+		})
+	}
+	return out
+}
+
+// fieldsToProto converts the fields listed in a decision
+func fieldsToProto(fields []models.PolicyDecisionResponseFieldRecord) ([]*pdpv1.DecisionField, error) {
+	var out []*pdpv1.DecisionField
+	for _, field := range fields {
+		converted := &pdpv1.DecisionField{
+			FieldName:   field.FieldName,
+			SchemaId:    field.SchemaID,
+			DisplayName: field.DisplayName,
+			Description: field.Description,
+			BlockedBy:   string(field.BlockedBy),
+		}
+		if field.Owner != nil {
+			owner := string(*field.Owner)
+			converted.Owner = &owner
+		}
+		if field.FailedCondition != nil {
+			value, err := structpb.NewValue(field.FailedCondition.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of condition on %s: %w", field.FailedCondition.Attribute, err)
+			}
+			converted.FailedCondition = &pdpv1.PolicyCondition{
+				Attribute: field.FailedCondition.Attribute,
+				Operator:  string(field.FailedCondition.Operator),
+				Value:     value,
+			}
+		}
+		out = append(out, converted)
+	}
+	return out, nil
+}
+
+// fieldsFromProto converts the fields listed in a decision message
+func fieldsFromProto(fields []*pdpv1.DecisionField) []models.PolicyDecisionResponseFieldRecord {
+	var out []models.PolicyDecisionResponseFieldRecord
+	for _, field := range fields {
+		converted := models.PolicyDecisionResponseFieldRecord{
+			FieldName:   field.GetFieldName(),
+			SchemaID:    field.GetSchemaId(),
+			DisplayName: field.DisplayName,
+			Description: field.Description,
+			BlockedBy:   models.KillSwitchTargetType(field.GetBlockedBy()),
+		}
+		if field.Owner != nil {
+			owner := models.Owner(field.GetOwner())
+			converted.Owner = &owner
+		}
+		if condition := field.GetFailedCondition(); condition != nil {
+			converted.FailedCondition = &models.PolicyCondition{
+				Attribute: condition.GetAttribute(),
+				Operator:  models.ConditionOperator(condition.GetOperator()),
+				Value:     condition.GetValue().AsInterface(),
+			}
+		}
+		out = append(out, converted)
+	}
+	return out
+}
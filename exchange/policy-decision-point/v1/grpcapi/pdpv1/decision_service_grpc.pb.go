@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pdp/v1/decision_service.proto
+
+// The PDP decision API for the orchestration engine. The HTTP API remains the interface for the
+// portal and administration; both evaluate decisions with the same decision engine.
+
+package pdpv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DecisionService_Decide_FullMethodName                 = "/pdp.v1.DecisionService/Decide"
+	DecisionService_DecideBulk_FullMethodName             = "/pdp.v1.DecisionService/DecideBulk"
+	DecisionService_GetGrants_FullMethodName              = "/pdp.v1.DecisionService/GetGrants"
+	DecisionService_GetConsentRequirements_FullMethodName = "/pdp.v1.DecisionService/GetConsentRequirements"
+)
+
+// DecisionServiceClient is the client API for DecisionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DecisionService makes policy decisions over the fields a consumer application requests
+type DecisionServiceClient interface {
+	// Decide evaluates a single decision request
+	Decide(ctx context.Context, in *DecisionRequest, opts ...grpc.CallOption) (*DecisionResponse, error)
+	// DecideBulk evaluates up to 100 decision requests in one round trip; the call fails if any decision fails
+	DecideBulk(ctx context.Context, in *DecideBulkRequest, opts ...grpc.CallOption) (*DecideBulkResponse, error)
+	// GetGrants lists the fields a consumer's applications are allow-listed for
+	GetGrants(ctx context.Context, in *GetGrantsRequest, opts ...grpc.CallOption) (*GetGrantsResponse, error)
+	// GetConsentRequirements consolidates the consent the caller must obtain before releasing the fields
+	GetConsentRequirements(ctx context.Context, in *DecisionRequest, opts ...grpc.CallOption) (*ConsentRequirementsResponse, error)
+}
+
+type decisionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDecisionServiceClient(cc grpc.ClientConnInterface) DecisionServiceClient {
+	return &decisionServiceClient{cc}
+}
+
+func (c *decisionServiceClient) Decide(ctx context.Context, in *DecisionRequest, opts ...grpc.CallOption) (*DecisionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecisionResponse)
+	err := c.cc.Invoke(ctx, DecisionService_Decide_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decisionServiceClient) DecideBulk(ctx context.Context, in *DecideBulkRequest, opts ...grpc.CallOption) (*DecideBulkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecideBulkResponse)
+	err := c.cc.Invoke(ctx, DecisionService_DecideBulk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decisionServiceClient) GetGrants(ctx context.Context, in *GetGrantsRequest, opts ...grpc.CallOption) (*GetGrantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGrantsResponse)
+	err := c.cc.Invoke(ctx, DecisionService_GetGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decisionServiceClient) GetConsentRequirements(ctx context.Context, in *DecisionRequest, opts ...grpc.CallOption) (*ConsentRequirementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConsentRequirementsResponse)
+	err := c.cc.Invoke(ctx, DecisionService_GetConsentRequirements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DecisionServiceServer is the server API for DecisionService service.
+// All implementations must embed UnimplementedDecisionServiceServer
+// for forward compatibility.
+//
+// DecisionService makes policy decisions over the fields a consumer application requests
+type DecisionServiceServer interface {
+	// Decide evaluates a single decision request
+	Decide(context.Context, *DecisionRequest) (*DecisionResponse, error)
+	// DecideBulk evaluates up to 100 decision requests in one round trip; the call fails if any decision fails
+	DecideBulk(context.Context, *DecideBulkRequest) (*DecideBulkResponse, error)
+	// GetGrants lists the fields a consumer's applications are allow-listed for
+	GetGrants(context.Context, *GetGrantsRequest) (*GetGrantsResponse, error)
+	// GetConsentRequirements consolidates the consent the caller must obtain before releasing the fields
+	GetConsentRequirements(context.Context, *DecisionRequest) (*ConsentRequirementsResponse, error)
+	mustEmbedUnimplementedDecisionServiceServer()
+}
+
+// UnimplementedDecisionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDecisionServiceServer struct{}
+
+func (UnimplementedDecisionServiceServer) Decide(context.Context, *DecisionRequest) (*DecisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decide not implemented")
+}
+func (UnimplementedDecisionServiceServer) DecideBulk(context.Context, *DecideBulkRequest) (*DecideBulkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecideBulk not implemented")
+}
+func (UnimplementedDecisionServiceServer) GetGrants(context.Context, *GetGrantsRequest) (*GetGrantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGrants not implemented")
+}
+func (UnimplementedDecisionServiceServer) GetConsentRequirements(context.Context, *DecisionRequest) (*ConsentRequirementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConsentRequirements not implemented")
+}
+func (UnimplementedDecisionServiceServer) mustEmbedUnimplementedDecisionServiceServer() {}
+func (UnimplementedDecisionServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeDecisionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DecisionServiceServer will
+// result in compilation errors.
+type UnsafeDecisionServiceServer interface {
+	mustEmbedUnimplementedDecisionServiceServer()
+}
+
+func RegisterDecisionServiceServer(s grpc.ServiceRegistrar, srv DecisionServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDecisionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DecisionService_ServiceDesc, srv)
+}
+
+func _DecisionService_Decide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecisionServiceServer).Decide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DecisionService_Decide_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecisionServiceServer).Decide(ctx, req.(*DecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DecisionService_DecideBulk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideBulkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecisionServiceServer).DecideBulk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DecisionService_DecideBulk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecisionServiceServer).DecideBulk(ctx, req.(*DecideBulkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DecisionService_GetGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGrantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecisionServiceServer).GetGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DecisionService_GetGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecisionServiceServer).GetGrants(ctx, req.(*GetGrantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DecisionService_GetConsentRequirements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecisionServiceServer).GetConsentRequirements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DecisionService_GetConsentRequirements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecisionServiceServer).GetConsentRequirements(ctx, req.(*DecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DecisionService_ServiceDesc is the grpc.ServiceDesc for DecisionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DecisionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pdp.v1.DecisionService",
+	HandlerType: (*DecisionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Decide",
+			Handler:    _DecisionService_Decide_Handler,
+		},
+		{
+			MethodName: "DecideBulk",
+			Handler:    _DecisionService_DecideBulk_Handler,
+		},
+		{
+			MethodName: "GetGrants",
+			Handler:    _DecisionService_GetGrants_Handler,
+		},
+		{
+			MethodName: "GetConsentRequirements",
+			Handler:    _DecisionService_GetConsentRequirements_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pdp/v1/decision_service.proto",
+}
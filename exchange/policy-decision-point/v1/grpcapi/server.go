@@ -0,0 +1,162 @@
+// Package grpcapi serves the PDP decision API over gRPC for the orchestration engine. The service
+// is defined in proto/pdp/v1/decision_service.proto; the stubs in pdpv1 are generated from it.
+// The HTTP API remains the interface for the portal and administration.
+package grpcapi
+
+//go:generate protoc --proto_path=../../proto --go_out=../.. --go_opt=module=github.com/gov-dx-sandbox/exchange/policy-decision-point --go-grpc_out=../.. --go-grpc_opt=module=github.com/gov-dx-sandbox/exchange/policy-decision-point pdp/v1/decision_service.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/auth"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/engine"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi/pdpv1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MaxBulkRequests is the largest number of decision requests accepted by DecideBulk
+const MaxBulkRequests = 100
+
+// Server implements pdpv1.DecisionServiceServer on top of the decision engine
+type Server struct {
+	pdpv1.UnimplementedDecisionServiceServer
+	engine *engine.Engine
+}
+
+// NewServer creates a decision service backed by the decision engine the HTTP API shares
+func NewServer(decisionEngine *engine.Engine) *Server {
+	return &Server{engine: decisionEngine}
+}
+
+// Decide evaluates a single decision request and records it like the HTTP endpoint does
+func (s *Server) Decide(ctx context.Context, req *pdpv1.DecisionRequest) (*pdpv1.DecisionResponse, error) {
+	return s.decide(req)
+}
+
+// DecideBulk evaluates each request in order; the call fails if any decision fails
+func (s *Server) DecideBulk(ctx context.Context, req *pdpv1.DecideBulkRequest) (*pdpv1.DecideBulkResponse, error) {
+	if len(req.GetRequests()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one request is required")
+	}
+	if len(req.GetRequests()) > MaxBulkRequests {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d requests are allowed", MaxBulkRequests)
+	}
+
+	responses := make([]*pdpv1.DecisionResponse, 0, len(req.GetRequests()))
+	for _, request := range req.GetRequests() {
+		resp, err := s.decide(request)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+	}
+	return &pdpv1.DecideBulkResponse{Responses: responses}, nil
+}
+
+// GetGrants lists the fields a consumer's applications are allow-listed for. An authenticated caller
+// lists the grants of its own tenant.
+func (s *Server) GetGrants(ctx context.Context, req *pdpv1.GetGrantsRequest) (*pdpv1.GetGrantsResponse, error) {
+	tenantID := req.GetTenantId()
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		if err := principal.CheckTenant(tenantID); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		tenantID = principal.TenantID
+	}
+	resp, err := s.engine.PolicyService().ListConsumerGrants(&models.ConsumerGrantFilter{
+		TenantID:       tenantID,
+		ConsumerID:     req.GetConsumerId(),
+		ApplicationID:  req.GetApplicationId(),
+		IncludeExpired: req.GetIncludeExpired(),
+	})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	out, err := GrantsToProto(resp)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return out, nil
+}
+
+// GetConsentRequirements consolidates the consent requirements for the requested fields
+func (s *Server) GetConsentRequirements(ctx context.Context, req *pdpv1.DecisionRequest) (*pdpv1.ConsentRequirementsResponse, error) {
+	resp, err := s.engine.ConsentRequirements(DecisionRequestFromProto(req))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	out, err := ConsentRequirementsToProto(resp)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return out, nil
+}
+
+// decide evaluates and records one decision
+func (s *Server) decide(req *pdpv1.DecisionRequest) (*pdpv1.DecisionResponse, error) {
+	resp, err := s.engine.Decide(DecisionRequestFromProto(req))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	out, err := DecisionResponseToProto(resp)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return out, nil
+}
+
+// RecoveryInterceptor converts handler panics into Internal errors
+func RecoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Panic in gRPC handler", "method", info.FullMethod, "panic", fmt.Sprint(r))
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// AuthInterceptor requires a valid access token from an admin or internal service in the
+// authorization metadata of every call. A nil authenticator admits every call.
+func AuthInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if authenticator == nil {
+			return handler(ctx, req)
+		}
+		var authorization string
+		if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+		principal, err := authenticator.Authenticate(authorization)
+		if err != nil {
+			slog.Warn("PDP gRPC call rejected", "method", info.FullMethod, "error", err)
+			if errors.Is(err, auth.ErrForbidden) {
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+			return nil, status.Error(codes.Unauthenticated, auth.ErrUnauthenticated.Error())
+		}
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// statusFromError maps service errors to gRPC status codes
+func statusFromError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, services.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
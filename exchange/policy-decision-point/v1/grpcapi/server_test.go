@@ -0,0 +1,155 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/auth"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/engine"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/grpcapi/pdpv1"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupClient serves the decision service over an in-memory listener
+func setupClient(t *testing.T) pdpv1.DecisionServiceClient {
+	db := testhelpers.SetupTestDB(t)
+
+	policyService := services.NewPolicyMetadataService(db)
+	_, err := policyService.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{{
+			FieldName:         "person.fullName",
+			Source:            models.SourcePrimary,
+			IsOwner:           true,
+			AccessControlType: models.AccessControlTypePublic,
+		}},
+	})
+	require.NoError(t, err)
+	_, err = policyService.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records:       []models.AllowListUpdateRequestRecord{{SchemaID: "schema-123", FieldName: "person.fullName"}},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(RecoveryInterceptor))
+	pdpv1.RegisterDecisionServiceServer(server, NewServer(engine.New(db)))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pdpv1.NewDecisionServiceClient(conn)
+}
+
+func TestServer_Decide(t *testing.T) {
+	client := setupClient(t)
+
+	resp, err := client.Decide(context.Background(), &pdpv1.DecisionRequest{
+		ApplicationId:  "app-1",
+		RequiredFields: []*pdpv1.FieldRef{{SchemaId: "schema-123", FieldName: "person.fullName"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.GetAppAuthorized())
+}
+
+func TestServer_DecideBulk(t *testing.T) {
+	client := setupClient(t)
+
+	t.Run("decisions in request order", func(t *testing.T) {
+		resp, err := client.DecideBulk(context.Background(), &pdpv1.DecideBulkRequest{
+			Requests: []*pdpv1.DecisionRequest{
+				{ApplicationId: "app-1", RequiredFields: []*pdpv1.FieldRef{{SchemaId: "schema-123", FieldName: "person.fullName"}}},
+				{ApplicationId: "app-2", RequiredFields: []*pdpv1.FieldRef{{SchemaId: "schema-123", FieldName: "person.fullName"}}},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.GetResponses(), 2)
+		assert.True(t, resp.GetResponses()[0].GetAppAuthorized())
+		assert.False(t, resp.GetResponses()[1].GetAppAuthorized())
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		_, err := client.DecideBulk(context.Background(), &pdpv1.DecideBulkRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		requests := make([]*pdpv1.DecisionRequest, MaxBulkRequests+1)
+		for i := range requests {
+			requests[i] = &pdpv1.DecisionRequest{}
+		}
+		_, err := client.DecideBulk(context.Background(), &pdpv1.DecideBulkRequest{Requests: requests})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_GetGrants(t *testing.T) {
+	client := setupClient(t)
+
+	resp, err := client.GetGrants(context.Background(), &pdpv1.GetGrantsRequest{ConsumerId: "member-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), resp.GetCount())
+
+	resp, err = client.GetGrants(context.Background(), &pdpv1.GetGrantsRequest{ApplicationId: "app-1"})
+	require.NoError(t, err)
+	require.Len(t, resp.GetRecords(), 1)
+	assert.Equal(t, "person.fullName", resp.GetRecords()[0].GetFieldName())
+	assert.True(t, resp.GetRecords()[0].GetExpiresAt().AsTime().After(time.Now()))
+
+	_, err = client.GetGrants(context.Background(), &pdpv1.GetGrantsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_GetGrants_TenantBoundToToken(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	server := NewServer(engine.New(db))
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "service-1", Roles: []string{auth.RoleSystem}, TenantID: "tenant-a"})
+
+	_, err := server.GetGrants(ctx, &pdpv1.GetGrantsRequest{TenantId: "tenant-b", ConsumerId: "member-1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	resp, err := server.GetGrants(ctx, &pdpv1.GetGrantsRequest{TenantId: "tenant-a", ConsumerId: "member-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), resp.GetCount())
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pdp.v1.DecisionService/Decide"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	t.Run("nil authenticator admits every call", func(t *testing.T) {
+		resp, err := AuthInterceptor(nil)(context.Background(), nil, info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		authenticator := auth.NewAuthenticator(auth.Config{JWKSURL: "http://127.0.0.1:0/jwks", Issuer: "issuer", Audiences: []string{"client"}})
+		_, err := AuthInterceptor(authenticator)(context.Background(), nil, info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
@@ -2,31 +2,142 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/auth"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/engine"
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/services"
 	"github.com/gov-dx-sandbox/exchange/shared/utils"
+	"gopkg.in/yaml.v3"
 	"gorm.io/gorm"
 )
 
 // Handler handles all API requests
 type Handler struct {
-	policyService *services.PolicyMetadataService
+	engine             *engine.Engine
+	policyService      *services.PolicyMetadataService
+	decisionLogService *services.DecisionLogService
+	simulationService  *services.PolicySimulationService
+	policyCache        *services.PolicyCache
+	killSwitchService  *services.KillSwitchService
+	// slowDecisionThreshold is the default latency for /debug/slow-decisions
+	slowDecisionThreshold time.Duration
+	// requireChangeApproval routes policy writes through change sets and rejects imports that are not dry runs
+	requireChangeApproval bool
+	// authenticator authenticates API callers; nil leaves the API unauthenticated
+	authenticator *auth.Authenticator
 }
 
+// DefaultSlowDecisionThreshold is used when no threshold is configured or requested
+const DefaultSlowDecisionThreshold = 250 * time.Millisecond
+
 // NewHandler creates a new API handler
 func NewHandler(db *gorm.DB) *Handler {
-	policyService := services.NewPolicyMetadataService(db)
+	decisionEngine := engine.New(db)
 	return &Handler{
-		policyService: policyService,
+		engine:             decisionEngine,
+		policyService:      decisionEngine.PolicyService(),
+		decisionLogService: decisionEngine.DecisionLog(),
+		simulationService:  services.NewPolicySimulationService(db),
+		policyCache:        decisionEngine.PolicyCache(),
+		killSwitchService:  decisionEngine.KillSwitches(),
+
+		slowDecisionThreshold: DefaultSlowDecisionThreshold,
 	}
 }
 
+// SetSlowDecisionThreshold sets the default latency threshold for listing slow decisions
+func (h *Handler) SetSlowDecisionThreshold(threshold time.Duration) {
+	if threshold > 0 {
+		h.slowDecisionThreshold = threshold
+	}
+}
+
+// SetRequireChangeApproval sets whether policy writes can only be applied through approved change sets
+func (h *Handler) SetRequireChangeApproval(required bool) {
+	h.requireChangeApproval = required
+}
+
+// SetAuthenticator requires the callers of the API to authenticate with access tokens
+func (h *Handler) SetAuthenticator(authenticator *auth.Authenticator) {
+	h.authenticator = authenticator
+}
+
+// Engine returns the decision engine the handler evaluates decisions with
+func (h *Handler) Engine() *engine.Engine {
+	return h.engine
+}
+
+// PolicyCache returns the policy cache used for decisions.
+// The cache stays empty, and decisions read from the database, until it is started or refreshed.
+func (h *Handler) PolicyCache() *services.PolicyCache {
+	return h.policyCache
+}
+
+// KillSwitches returns the kill switches checked before policy evaluation.
+// Until they are started, decisions read the active switches from the database.
+func (h *Handler) KillSwitches() *services.KillSwitchService {
+	return h.killSwitchService
+}
+
 // SetupRoutes configures all API routes
 func (h *Handler) SetupRoutes(mux *http.ServeMux) {
-	mux.Handle("/api/v1/policy/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handlePolicyService)))
+	mux.Handle("/api/v1/policy/", utils.PanicRecoveryMiddleware(h.authenticator.Middleware(http.HandlerFunc(h.handlePolicyService))))
+	mux.Handle("/admin/cache/", utils.PanicRecoveryMiddleware(h.authenticator.Middleware(http.HandlerFunc(h.handleCacheAdmin))))
+	mux.Handle("/debug/slow-decisions", utils.PanicRecoveryMiddleware(h.authenticator.Middleware(http.HandlerFunc(h.handleSlowDecisions))))
+}
+
+// handleSlowDecisions handles listing recent decisions over a latency threshold
+func (h *Handler) handleSlowDecisions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ListSlowDecisions(w, r)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCacheAdmin handles policy cache administration requests, which only policy administrators may make
+func (h *Handler) handleCacheAdmin(w http.ResponseWriter, r *http.Request) {
+	if _, err := auth.RequireAdmin(r.Context()); err != nil {
+		auth.RespondWithError(w, err)
+		return
+	}
+
+	switch strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/cache"), "/") {
+	case "refresh":
+		switch r.Method {
+		case http.MethodPost:
+			h.RefreshPolicyCache(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "stats":
+		switch r.Method {
+		case http.MethodGet:
+			utils.RespondWithSuccess(w, http.StatusOK, h.policyCache.Stats())
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
+
+// RefreshPolicyCache handles a manual reload of the policy cache
+func (h *Handler) RefreshPolicyCache(w http.ResponseWriter, r *http.Request) {
+	if err := h.policyCache.Refresh(); err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, h.policyCache.Stats())
 }
 
 // handlePolicyService handles policy metadata service requests
@@ -34,6 +145,24 @@ func (h *Handler) handlePolicyService(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/policy")
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 
+	// Renewal request routes: /api/v1/policy/renewal-requests[/{renewalId}]
+	if parts[0] == "renewal-requests" {
+		h.handleRenewalRequests(w, r, parts[1:])
+		return
+	}
+
+	// Policy change set routes: /api/v1/policy/change-sets[/{changeSetId}]
+	if parts[0] == "change-sets" {
+		h.handleChangeSets(w, r, parts[1:])
+		return
+	}
+
+	// Policy version routes: /api/v1/policy/versions[/{version}]
+	if parts[0] == "versions" {
+		h.handlePolicyVersions(w, r, parts[1:])
+		return
+	}
+
 	if len(parts) != 1 {
 		http.Error(w, "Not Found", http.StatusNotFound)
 		return
@@ -54,6 +183,13 @@ func (h *Handler) handlePolicyService(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 		}
+	case "schema-lifecycle":
+		switch r.Method {
+		case http.MethodPost:
+			h.UpdateSchemaLifecycle(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
 	case "decide":
 		switch r.Method {
 		case http.MethodPost:
@@ -61,6 +197,64 @@ func (h *Handler) handlePolicyService(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 		}
+	case "requirements":
+		switch r.Method {
+		case http.MethodPost:
+			h.GetConsentRequirements(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "classifications":
+		switch r.Method {
+		case http.MethodGet:
+			h.ListClassifications(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "grants":
+		switch r.Method {
+		case http.MethodGet:
+			h.ListConsumerGrants(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "simulate":
+		switch r.Method {
+		case http.MethodPost:
+			h.SimulatePolicyChange(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "export":
+		switch r.Method {
+		case http.MethodGet:
+			h.ExportPolicyDocument(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "import":
+		switch r.Method {
+		case http.MethodPost:
+			h.ImportPolicyDocument(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "decisions":
+		switch r.Method {
+		case http.MethodGet:
+			h.ListPolicyDecisions(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case "kill-switch":
+		switch r.Method {
+		case http.MethodGet:
+			h.ListKillSwitches(w, r)
+		case http.MethodPost:
+			h.SetKillSwitch(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
 	default:
 		http.Error(w, "Not Found", http.StatusNotFound)
 	}
@@ -75,9 +269,31 @@ func (h *Handler) CreatePolicyMetadata(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.TenantID = tenantFromRequest(r)
+
+	// When changes require approval the metadata is proposed as a change set, and applied once approved
+	if h.requireChangeApproval {
+		proposedBy, ok := proposerFromRequest(w, r)
+		if !ok {
+			return
+		}
+		changeSet, err := h.policyService.ProposePolicyMetadata(&req, proposedBy)
+		if err != nil {
+			respondWithServiceError(w, err)
+			return
+		}
+		if changeSet == nil {
+			// Nothing to approve, the policy already matches the request
+			utils.RespondWithSuccess(w, http.StatusOK, models.PolicyMetadataCreateResponse{Records: []models.PolicyMetadataResponse{}})
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusAccepted, changeSet)
+		return
+	}
+
 	resp, err := h.policyService.CreatePolicyMetadata(&req)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithServiceError(w, err)
 		return
 	}
 
@@ -92,6 +308,22 @@ func (h *Handler) UpdateAllowList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.TenantID = tenantFromRequest(r)
+
+	if h.requireChangeApproval {
+		proposedBy, ok := proposerFromRequest(w, r)
+		if !ok {
+			return
+		}
+		changeSet, err := h.policyService.ProposeAllowListUpdate(&req, proposedBy)
+		if err != nil {
+			respondWithServiceError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusAccepted, changeSet)
+		return
+	}
+
 	resp, err := h.policyService.UpdateAllowList(&req)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
@@ -101,6 +333,39 @@ func (h *Handler) UpdateAllowList(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithSuccess(w, http.StatusOK, resp)
 }
 
+// UpdateSchemaLifecycle handles deprecating, sunsetting, retiring or reactivating a schema
+func (h *Handler) UpdateSchemaLifecycle(w http.ResponseWriter, r *http.Request) {
+	var req models.SchemaLifecycleUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.TenantID = tenantFromRequest(r)
+
+	if h.requireChangeApproval {
+		proposedBy, ok := proposerFromRequest(w, r)
+		if !ok {
+			return
+		}
+		changeSet, err := h.policyService.ProposeSchemaLifecycleUpdate(&req, proposedBy)
+		if err != nil {
+			respondWithServiceError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusAccepted, changeSet)
+		return
+	}
+
+	resp, err := h.policyService.UpdateSchemaLifecycle(&req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
 // GetPolicyDecision handles getting a policy decision
 func (h *Handler) GetPolicyDecision(w http.ResponseWriter, r *http.Request) {
 	var req models.PolicyDecisionRequest
@@ -109,11 +374,553 @@ func (h *Handler) GetPolicyDecision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.policyService.GetPolicyDecision(&req)
+	resp, err := h.engine.Decide(&req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// GetConsentRequirements handles consolidating the consent requirements the OE should use when
+// constructing consent requests for a decision request
+func (h *Handler) GetConsentRequirements(w http.ResponseWriter, r *http.Request) {
+	var req models.PolicyDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	resp, err := h.engine.ConsentRequirements(&req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// ListConsumerGrants handles listing the fields a consumer's applications are allow-listed for
+func (h *Handler) ListConsumerGrants(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ConsumerGrantFilter{
+		TenantID:      tenantFromRequest(r),
+		ConsumerID:    query.Get("consumerId"),
+		ApplicationID: query.Get("applicationId"),
+	}
+	if includeExpired := query.Get("includeExpired"); includeExpired != "" {
+		value, err := strconv.ParseBool(includeExpired)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid includeExpired parameter")
+			return
+		}
+		filter.IncludeExpired = value
+	}
+
+	resp, err := h.policyService.ListConsumerGrants(&filter)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// SimulatePolicyChange handles evaluating hypothetical policy changes against recent decisions
+func (h *Handler) SimulatePolicyChange(w http.ResponseWriter, r *http.Request) {
+	var req models.PolicySimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	resp, err := h.simulationService.Simulate(&req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// ExportPolicyDocument handles exporting the policy set as a declarative document.
+// The format query parameter selects yaml (default) or json.
+func (h *Handler) ExportPolicyDocument(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid format parameter, expected yaml or json")
+		return
+	}
+
+	doc, err := h.policyService.ExportPolicyDocument(tenantFromRequest(r))
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if format == "json" {
+		utils.RespondWithSuccess(w, http.StatusOK, doc)
+		return
+	}
+
+	body, err := yaml.Marshal(doc)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to encode policy document")
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		slog.Error("Failed to write policy document", "error", err)
+	}
+}
+
+// ImportPolicyDocument handles importing a declarative policy document (YAML or JSON).
+// With dryRun=true the diff is returned without applying it. Only policy administrators may import.
+func (h *Handler) ImportPolicyDocument(w http.ResponseWriter, r *http.Request) {
+	if _, err := auth.RequireAdmin(r.Context()); err != nil {
+		auth.RespondWithError(w, err)
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid dryRun parameter")
+			return
+		}
+		dryRun = parsed
+	}
+	if !dryRun && h.requireChangeApproval {
+		utils.RespondWithError(w, http.StatusConflict, "Policy changes require approval, propose a change set instead")
+		return
+	}
+
+	// YAML is a superset of JSON, so both formats are decoded the same way
+	var doc models.PolicyDocument
+	if err := yaml.NewDecoder(r.Body).Decode(&doc); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid policy document")
+		return
+	}
+
+	resp, err := h.policyService.ImportPolicyDocument(tenantFromRequest(r), &doc, dryRun)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
 	utils.RespondWithSuccess(w, http.StatusOK, resp)
 }
+
+// SetKillSwitch handles blocking, or unblocking, an application, provider or field platform-wide.
+// Only policy administrators may set kill switches; the switch records the admin's token subject.
+func (h *Handler) SetKillSwitch(w http.ResponseWriter, r *http.Request) {
+	admin, err := auth.RequireAdmin(r.Context())
+	if err != nil {
+		auth.RespondWithError(w, err)
+		return
+	}
+
+	var req models.KillSwitchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.UpdatedBy = &admin.Subject
+
+	resp, err := h.killSwitchService.SetKillSwitch(&req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// ListKillSwitches handles listing the active kill switches, or all of them with includeInactive=true
+func (h *Handler) ListKillSwitches(w http.ResponseWriter, r *http.Request) {
+	if _, err := auth.RequireAdmin(r.Context()); err != nil {
+		auth.RespondWithError(w, err)
+		return
+	}
+
+	includeInactive := false
+	if value := r.URL.Query().Get("includeInactive"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid includeInactive parameter")
+			return
+		}
+		includeInactive = parsed
+	}
+
+	resp, err := h.killSwitchService.ListKillSwitches(includeInactive)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// ListClassifications handles listing the classification tiers and their default rules
+func (h *Handler) ListClassifications(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithSuccess(w, http.StatusOK, models.ClassificationListResponse{
+		Records: models.ClassificationRules(),
+	})
+}
+
+// ListPolicyDecisions handles querying recorded policy decisions
+func (h *Handler) ListPolicyDecisions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.PolicyDecisionLogFilter{
+		ApplicationID: query.Get("applicationId"),
+		SchemaID:      query.Get("schemaId"),
+		FieldName:     query.Get("fieldName"),
+		Outcome:       query.Get("outcome"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid from parameter: expected RFC3339 timestamp")
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid to parameter: expected RFC3339 timestamp")
+		return
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid limit parameter")
+			return
+		}
+		filter.Limit = limit
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid offset parameter")
+			return
+		}
+		filter.Offset = offset
+	}
+
+	resp, err := h.decisionLogService.ListDecisions(&filter)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// ListSlowDecisions handles listing the most recent decisions slower than a threshold
+func (h *Handler) ListSlowDecisions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.PolicyDecisionLogFilter{
+		ApplicationID: query.Get("applicationId"),
+		MinLatency:    h.slowDecisionThreshold,
+	}
+
+	if thresholdStr := query.Get("thresholdMs"); thresholdStr != "" {
+		thresholdMs, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil || thresholdMs <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid thresholdMs parameter")
+			return
+		}
+		filter.MinLatency = time.Duration(thresholdMs * float64(time.Millisecond))
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid limit parameter")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	resp, err := h.decisionLogService.ListDecisions(&filter)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// handleRenewalRequests handles allow list renewal request routes
+func (h *Handler) handleRenewalRequests(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch len(parts) {
+	case 0:
+		switch r.Method {
+		case http.MethodGet:
+			h.ListRenewalRequests(w, r)
+		case http.MethodPost:
+			h.CreateRenewalRequest(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case 1:
+		switch r.Method {
+		case http.MethodPut:
+			h.ReviewRenewalRequest(w, r, parts[0])
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
+
+// CreateRenewalRequest handles a consumer request to renew allow list grants
+func (h *Handler) CreateRenewalRequest(w http.ResponseWriter, r *http.Request) {
+	var req models.AllowListRenewalCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.TenantID = tenantFromRequest(r)
+
+	resp, err := h.policyService.CreateRenewalRequest(&req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, resp)
+}
+
+// ListRenewalRequests handles listing allow list renewal requests
+func (h *Handler) ListRenewalRequests(w http.ResponseWriter, r *http.Request) {
+	applicationID := r.URL.Query().Get("applicationId")
+	status := r.URL.Query().Get("status")
+
+	resp, err := h.policyService.ListRenewalRequests(tenantFromRequest(r), applicationID, status)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// ReviewRenewalRequest handles the api-server decision on a renewal request
+func (h *Handler) ReviewRenewalRequest(w http.ResponseWriter, r *http.Request, renewalID string) {
+	var req models.AllowListRenewalReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	admin, err := auth.RequireAdmin(r.Context())
+	if err != nil {
+		auth.RespondWithError(w, err)
+		return
+	}
+	req.TenantID = tenantFromRequest(r)
+	req.ReviewedBy = admin.Subject
+
+	// When changes require approval an approved renewal is proposed as a change set, and the grants
+	// are extended once another admin approves it
+	if h.requireChangeApproval && req.Status == models.RenewalRequestStatusApproved {
+		changeSet, err := h.policyService.ProposeRenewalApproval(renewalID, &req)
+		if err != nil {
+			respondWithServiceError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusAccepted, changeSet)
+		return
+	}
+
+	resp, err := h.policyService.ReviewRenewalRequest(renewalID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// handleChangeSets handles policy change set routes
+func (h *Handler) handleChangeSets(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch len(parts) {
+	case 0:
+		switch r.Method {
+		case http.MethodGet:
+			h.ListPolicyChangeSets(w, r)
+		case http.MethodPost:
+			h.CreatePolicyChangeSet(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	case 1:
+		switch r.Method {
+		case http.MethodGet:
+			h.GetPolicyChangeSet(w, r, parts[0])
+		case http.MethodPut:
+			h.ReviewPolicyChangeSet(w, r, parts[0])
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
+
+// CreatePolicyChangeSet handles proposing a policy document (YAML or JSON) for review
+func (h *Handler) CreatePolicyChangeSet(w http.ResponseWriter, r *http.Request) {
+	// YAML is a superset of JSON, so both formats are decoded the same way
+	var req models.PolicyChangeSetCreateRequest
+	if err := yaml.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	admin, err := auth.RequireAdmin(r.Context())
+	if err != nil {
+		auth.RespondWithError(w, err)
+		return
+	}
+	req.TenantID = tenantFromRequest(r)
+	req.ProposedBy = admin.Subject
+
+	resp, err := h.policyService.CreatePolicyChangeSet(&req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, resp)
+}
+
+// ListPolicyChangeSets handles listing policy change sets
+func (h *Handler) ListPolicyChangeSets(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.policyService.ListPolicyChangeSets(tenantFromRequest(r), r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// GetPolicyChangeSet handles getting a policy change set with its diff and impact
+func (h *Handler) GetPolicyChangeSet(w http.ResponseWriter, r *http.Request, changeSetID string) {
+	resp, err := h.policyService.GetPolicyChangeSet(tenantFromRequest(r), changeSetID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// ReviewPolicyChangeSet handles an admin's decision on a pending policy change set
+func (h *Handler) ReviewPolicyChangeSet(w http.ResponseWriter, r *http.Request, changeSetID string) {
+	var req models.PolicyChangeSetReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	admin, err := auth.RequireAdmin(r.Context())
+	if err != nil {
+		auth.RespondWithError(w, err)
+		return
+	}
+	req.TenantID = tenantFromRequest(r)
+	req.ReviewedBy = admin.Subject
+
+	resp, err := h.policyService.ReviewPolicyChangeSet(changeSetID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resp)
+}
+
+// handlePolicyVersions handles policy version routes
+func (h *Handler) handlePolicyVersions(w http.ResponseWriter, r *http.Request, parts []string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch len(parts) {
+	case 0:
+		resp, err := h.policyService.ListPolicyVersions(tenantFromRequest(r))
+		if err != nil {
+			respondWithServiceError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, resp)
+	case 1:
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || version < 1 {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid policy version")
+			return
+		}
+		resp, err := h.policyService.GetPolicyVersion(tenantFromRequest(r), version)
+		if err != nil {
+			respondWithServiceError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, resp)
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
+
+// parseTimeParam parses an optional RFC3339 query parameter
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// tenantFromRequest returns the tenant that policy administration is scoped to: the tenant the
+// caller's token is bound to, or without authentication the X-Tenant-ID header
+func tenantFromRequest(r *http.Request) string {
+	if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return principal.TenantID
+	}
+	if tenantID := strings.TrimSpace(r.Header.Get(models.TenantIDHeader)); tenantID != "" {
+		return tenantID
+	}
+	return models.DefaultTenantID
+}
+
+// proposerFromRequest returns the subject of the caller's access token, who proposes the writes that
+// require approval, or responds 401 if the request was not authenticated
+func proposerFromRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		auth.RespondWithError(w, auth.ErrUnauthenticated)
+		return "", false
+	}
+	return principal.Subject, true
+}
+
+// respondWithServiceError maps service errors to HTTP status codes
+func respondWithServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidInput):
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		utils.RespondWithError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/auth"
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -72,6 +76,18 @@ func TestHandler_SetupRoutes(t *testing.T) {
 	assert.NotEqual(t, http.StatusNotFound, w.Code)
 }
 
+func TestTenantFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/policy/export", nil)
+	assert.Equal(t, models.DefaultTenantID, tenantFromRequest(req))
+
+	req.Header.Set(models.TenantIDHeader, "tenant-a")
+	assert.Equal(t, "tenant-a", tenantFromRequest(req))
+
+	// An authenticated caller is scoped to the tenant of its token
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: "admin-1", TenantID: "tenant-b"}))
+	assert.Equal(t, "tenant-b", tenantFromRequest(req))
+}
+
 func TestHandler_NewHandler(t *testing.T) {
 	db := setupTestDB(t)
 	handler := NewHandler(db)
@@ -675,6 +691,168 @@ func TestHandler_handlePolicyService(t *testing.T) {
 			path:           "/api/v1/policy/decide",
 			expectedStatus: http.StatusMethodNotAllowed,
 		},
+		{
+			name:           "GET /api/v1/policy/grants - Missing consumer",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/grants",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "GET /api/v1/policy/grants",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/grants?consumerId=member-1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET /api/v1/policy/grants - Invalid includeExpired",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/grants?consumerId=member-1&includeExpired=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "POST /api/v1/policy/grants - Method not allowed",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/grants",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "POST /api/v1/policy/simulate - No changes",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/simulate",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "GET /api/v1/policy/simulate - Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/simulate",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "GET /api/v1/policy/export",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/export",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET /api/v1/policy/export - JSON",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/export?format=json",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET /api/v1/policy/export - Invalid format",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "POST /api/v1/policy/import - unauthenticated",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/import?dryRun=true",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "GET /api/v1/policy/import - Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/import",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "POST /api/v1/policy/requirements - Missing fields",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/requirements",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "GET /api/v1/policy/requirements - Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/requirements",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "GET /api/v1/policy/classifications",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/classifications",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "POST /api/v1/policy/classifications - Method not allowed",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/classifications",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "GET /api/v1/policy/decisions",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/decisions",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET /api/v1/policy/decisions - invalid time range",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/decisions?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "GET /api/v1/policy/decisions - invalid limit",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/decisions?limit=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "POST /api/v1/policy/decisions - Method not allowed",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/decisions",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "POST /api/v1/policy/schema-lifecycle - invalid input",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/schema-lifecycle",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "GET /api/v1/policy/schema-lifecycle - Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/schema-lifecycle",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "GET /api/v1/policy/renewal-requests",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/renewal-requests",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "POST /api/v1/policy/renewal-requests - invalid input",
+			method:         http.MethodPost,
+			path:           "/api/v1/policy/renewal-requests",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "PUT /api/v1/policy/renewal-requests/:id - unauthenticated",
+			method:         http.MethodPut,
+			path:           "/api/v1/policy/renewal-requests/some-id",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "DELETE /api/v1/policy/renewal-requests - Method not allowed",
+			method:         http.MethodDelete,
+			path:           "/api/v1/policy/renewal-requests",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "GET /api/v1/policy/renewal-requests/:id - Method not allowed",
+			method:         http.MethodGet,
+			path:           "/api/v1/policy/renewal-requests/some-id",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "Invalid path - renewal request sub-resource",
+			method:         http.MethodPut,
+			path:           "/api/v1/policy/renewal-requests/some-id/extra",
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name:           "Invalid path - single segment",
 			method:         http.MethodPost,
@@ -709,3 +887,418 @@ func TestHandler_handlePolicyService(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_RenewalRequests(t *testing.T) {
+	db := setupTestDB(t)
+	handler := NewHandler(db)
+
+	_, err := handler.policyService.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{
+				FieldName:         "person.fullName",
+				Source:            models.SourcePrimary,
+				IsOwner:           true,
+				AccessControlType: models.AccessControlTypeRestricted,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = handler.policyService.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-123",
+		Records:       []models.AllowListUpdateRequestRecord{{FieldName: "person.fullName", SchemaID: "schema-123"}},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	assert.NoError(t, err)
+
+	body, _ := json.Marshal(models.AllowListRenewalCreateRequest{
+		ApplicationID: "app-123",
+		Records:       []models.AllowListUpdateRequestRecord{{FieldName: "person.fullName", SchemaID: "schema-123"}},
+		GrantDuration: models.GrantDurationTypeOneYear,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/policy/renewal-requests", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handler.handlePolicyService(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.AllowListRenewalResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	assert.Equal(t, models.RenewalRequestStatusPending, created.Status)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/policy/renewal-requests?status=pending", nil)
+	w = httptest.NewRecorder()
+	handler.handlePolicyService(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var list models.AllowListRenewalListResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Equal(t, 1, list.Count)
+
+	// Only admins review renewals
+	review := func(principal *auth.Principal, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/policy/renewal-requests/"+created.ID, bytes.NewBufferString(body))
+		if principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		handler.handlePolicyService(w, req)
+		return w
+	}
+	admin := &auth.Principal{Subject: "admin@example.com", Roles: []string{auth.RoleAdmin}, TenantID: models.DefaultTenantID}
+	service := &auth.Principal{Subject: "orchestration-engine", Roles: []string{auth.RoleSystem}, TenantID: models.DefaultTenantID}
+	approval := `{"status":"approved"}`
+	assert.Equal(t, http.StatusUnauthorized, review(nil, approval).Code)
+	assert.Equal(t, http.StatusForbidden, review(service, approval).Code)
+	assert.Equal(t, http.StatusBadRequest, review(admin, `{"status":"pending"}`).Code)
+	assert.Equal(t, http.StatusOK, review(admin, approval).Code)
+	assert.Equal(t, http.StatusConflict, review(admin, approval).Code)
+}
+
+func TestHandler_GetPolicyDecision_RecordsDecision(t *testing.T) {
+	db := setupTestDB(t)
+	handler := NewHandler(db)
+
+	body, _ := json.Marshal(models.PolicyDecisionRequest{
+		ApplicationID:  "app-123",
+		RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "person.unknown", SchemaID: "schema-123"}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/policy/decide", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handler.GetPolicyDecision(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/policy/decisions?applicationId=app-123&outcome=error&fieldName=person.unknown", nil)
+	w = httptest.NewRecorder()
+	handler.handlePolicyService(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.PolicyDecisionLogListResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, int64(1), resp.Total)
+	if assert.Len(t, resp.Records, 1) {
+		assert.Equal(t, "app-123", resp.Records[0].ApplicationID)
+		assert.Equal(t, models.DecisionOutcomeError, resp.Records[0].Outcome)
+	}
+}
+
+func TestHandler_CacheAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	handler := NewHandler(db)
+	mux := http.NewServeMux()
+	handler.SetupRoutes(mux)
+
+	admin := &auth.Principal{Subject: "admin@example.com", Roles: []string{auth.RoleAdmin}, TenantID: models.DefaultTenantID}
+	service := &auth.Principal{Subject: "orchestration-engine", Roles: []string{auth.RoleSystem}, TenantID: models.DefaultTenantID}
+	serveAs := func(principal *auth.Principal, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// Only policy administrators manage the cache
+	assert.Equal(t, http.StatusUnauthorized, serveAs(nil, http.MethodPost, "/admin/cache/refresh").Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(service, http.MethodPost, "/admin/cache/refresh").Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(service, http.MethodGet, "/admin/cache/stats").Code)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"stats", http.MethodGet, "/admin/cache/stats", http.StatusOK},
+		{"refresh", http.MethodPost, "/admin/cache/refresh", http.StatusOK},
+		{"refresh wrong method", http.MethodGet, "/admin/cache/refresh", http.StatusMethodNotAllowed},
+		{"stats wrong method", http.MethodPost, "/admin/cache/stats", http.StatusMethodNotAllowed},
+		{"unknown", http.MethodGet, "/admin/cache/unknown", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStatus, serveAs(admin, tt.method, tt.path).Code)
+		})
+	}
+
+	w := serveAs(admin, http.MethodGet, "/admin/cache/stats")
+
+	var stats models.PolicyCacheStats
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&stats))
+	assert.True(t, stats.Loaded)
+	assert.Equal(t, int64(1), stats.Refreshes)
+}
+
+func TestHandler_SlowDecisions(t *testing.T) {
+	db := setupTestDB(t)
+	handler := NewHandler(db)
+	handler.SetSlowDecisionThreshold(100 * time.Millisecond)
+	mux := http.NewServeMux()
+	handler.SetupRoutes(mux)
+
+	req := &models.PolicyDecisionRequest{ApplicationID: "app-123"}
+	assert.NoError(t, handler.decisionLogService.RecordDecision(req, &models.PolicyDecisionResponse{}, nil, 50*time.Millisecond))
+	assert.NoError(t, handler.decisionLogService.RecordDecision(req, &models.PolicyDecisionResponse{}, nil, 300*time.Millisecond))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedTotal  int64
+	}{
+		{"default threshold", http.MethodGet, "/debug/slow-decisions", http.StatusOK, 1},
+		{"custom threshold", http.MethodGet, "/debug/slow-decisions?thresholdMs=10", http.StatusOK, 2},
+		{"invalid threshold", http.MethodGet, "/debug/slow-decisions?thresholdMs=abc", http.StatusBadRequest, 0},
+		{"invalid limit", http.MethodGet, "/debug/slow-decisions?limit=0", http.StatusBadRequest, 0},
+		{"wrong method", http.MethodPost, "/debug/slow-decisions", http.StatusMethodNotAllowed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp models.PolicyDecisionLogListResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedTotal, resp.Total)
+			}
+		})
+	}
+}
+
+func TestHandler_KillSwitch(t *testing.T) {
+	db := setupTestDB(t)
+	handler := NewHandler(db)
+	mux := http.NewServeMux()
+	handler.SetupRoutes(mux)
+
+	admin := &auth.Principal{Subject: "admin@example.com", Roles: []string{auth.RoleAdmin}, TenantID: models.DefaultTenantID}
+	serveAs := func(principal *auth.Principal, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		return serveAs(admin, method, path, body)
+	}
+
+	block := `{"targetType":"application","targetId":"app-123","reason":"incident"}`
+	service := &auth.Principal{Subject: "orchestration-engine", Roles: []string{auth.RoleSystem}, TenantID: models.DefaultTenantID}
+	assert.Equal(t, http.StatusUnauthorized, serveAs(nil, http.MethodPost, "/api/v1/policy/kill-switch", block).Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(service, http.MethodPost, "/api/v1/policy/kill-switch", block).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveAs(nil, http.MethodGet, "/api/v1/policy/kill-switch", "").Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(service, http.MethodGet, "/api/v1/policy/kill-switch", "").Code)
+
+	w := serve(http.MethodPost, "/api/v1/policy/kill-switch", block)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var ks models.KillSwitch
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&ks))
+	assert.True(t, ks.Active)
+	if assert.NotNil(t, ks.UpdatedBy) {
+		assert.Equal(t, "admin@example.com", *ks.UpdatedBy)
+	}
+
+	w = serve(http.MethodPost, "/api/v1/policy/decide", `{"applicationId":"app-123","requiredFields":[{"fieldName":"person.name","schemaId":"schema-123"}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var decision models.PolicyDecisionResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&decision))
+	assert.False(t, decision.AppAuthorized)
+	assert.Len(t, decision.BlockedFields, 1)
+
+	w = serve(http.MethodGet, "/api/v1/policy/kill-switch", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list models.KillSwitchListResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Len(t, list.Records, 1)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/api/v1/policy/kill-switch", `{"targetType":"tenant","targetId":"x"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/api/v1/policy/kill-switch", `not json`).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/api/v1/policy/kill-switch", `{"targetType":"provider","targetId":"drp","active":false}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/v1/policy/kill-switch?includeInactive=maybe", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodDelete, "/api/v1/policy/kill-switch", "").Code)
+}
+
+func TestHandler_PolicyChangeSets(t *testing.T) {
+	db := setupTestDB(t)
+	handler := NewHandler(db)
+	handler.SetRequireChangeApproval(true)
+	mux := http.NewServeMux()
+	handler.SetupRoutes(mux)
+
+	// The actor is the subject of the caller's token; an empty actor is an unauthenticated request
+	serveAs := func(method, path, actorID string, roles []string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if actorID != "" {
+			principal := &auth.Principal{Subject: actorID, Roles: roles, TenantID: models.DefaultTenantID}
+			req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	serve := func(method, path, actorID, body string) *httptest.ResponseRecorder {
+		return serveAs(method, path, actorID, []string{auth.RoleAdmin}, body)
+	}
+
+	document := `version: v1
+schemas:
+  - schemaId: schema-123
+    fields:
+      - fieldName: person.fullName
+        source: primary
+        isOwner: true
+`
+	// Only policy administrators import documents, and only as dry runs when changes require approval
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/api/v1/policy/import?dryRun=true", "", document).Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(http.MethodPost, "/api/v1/policy/import?dryRun=true", "portal", []string{auth.RoleSystem}, document).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/api/v1/policy/import?dryRun=maybe", "editor@example.com", document).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/api/v1/policy/import?dryRun=true", "editor@example.com", "schemas: []").Code)
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "/api/v1/policy/import", "editor@example.com", document).Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/v1/policy/import?dryRun=true", "editor@example.com", document).Code)
+
+	proposal := "title: Add person.fullName\ndocument:\n" + strings.ReplaceAll("\n"+document, "\n", "\n  ")
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/api/v1/policy/change-sets", "", proposal).Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(http.MethodPost, "/api/v1/policy/change-sets", "portal", []string{auth.RoleSystem}, proposal).Code)
+	w := serve(http.MethodPost, "/api/v1/policy/change-sets", "editor@example.com", proposal)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created models.PolicyChangeSetResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	assert.Equal(t, 1, created.Diff.Created)
+
+	w = serve(http.MethodGet, "/api/v1/policy/change-sets/"+created.ID, "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	review := `{"status":"approved"}`
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPut, "/api/v1/policy/change-sets/"+created.ID, "", review).Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(http.MethodPut, "/api/v1/policy/change-sets/"+created.ID, "portal", []string{auth.RoleSystem}, review).Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/api/v1/policy/change-sets/"+created.ID, "editor@example.com", review).Code)
+	w = serve(http.MethodPut, "/api/v1/policy/change-sets/"+created.ID, "reviewer@example.com", review)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPut, "/api/v1/policy/change-sets/"+created.ID, "reviewer@example.com", review).Code)
+
+	w = serve(http.MethodGet, "/api/v1/policy/versions", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var versions models.PolicyVersionListResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&versions))
+	assert.Len(t, versions.Records, 1)
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/policy/versions/1", "", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/policy/versions/2", "", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/v1/policy/versions/latest", "", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/policy/change-sets/unknown", "", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodDelete, "/api/v1/policy/change-sets/"+created.ID, "", "").Code)
+}
+
+func TestHandler_PolicyWritesRequireApproval(t *testing.T) {
+	db := setupTestDB(t)
+	handler := NewHandler(db)
+	handler.SetRequireChangeApproval(true)
+	mux := http.NewServeMux()
+	handler.SetupRoutes(mux)
+
+	portal := &auth.Principal{Subject: "portal", Roles: []string{auth.RoleSystem}, TenantID: models.DefaultTenantID}
+	admin := &auth.Principal{Subject: "admin@example.com", Roles: []string{auth.RoleAdmin}, TenantID: models.DefaultTenantID}
+	serve := func(principal *auth.Principal, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	// propose sends a policy write, which is proposed as a change set, and approves it as the admin
+	propose := func(path, body string, kind models.PolicyChangeSetKind) models.PolicyChangeSetResponse {
+		t.Helper()
+		w := serve(portal, http.MethodPost, path, body)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		var changeSet models.PolicyChangeSetResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&changeSet))
+		assert.Equal(t, kind, changeSet.Kind)
+		assert.Equal(t, models.PolicyChangeSetStatusPending, changeSet.Status)
+		assert.Equal(t, "portal", changeSet.ProposedBy)
+		return changeSet
+	}
+	approve := func(changeSet models.PolicyChangeSetResponse) models.PolicyChangeSetResponse {
+		t.Helper()
+		w := serve(admin, http.MethodPut, "/api/v1/policy/change-sets/"+changeSet.ID, `{"status":"approved"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var approved models.PolicyChangeSetResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&approved))
+		return approved
+	}
+	countFields := func() int64 {
+		var count int64
+		require.NoError(t, db.Model(&models.PolicyMetadata{}).Count(&count).Error)
+		return count
+	}
+
+	metadata := `{"schemaId":"schema-123","records":[{"fieldName":"person.name","source":"primary","isOwner":true,"accessControlType":"public"}]}`
+	assert.Equal(t, http.StatusUnauthorized, serve(nil, http.MethodPost, "/api/v1/policy/metadata", metadata).Code)
+
+	changeSet := propose("/api/v1/policy/metadata", metadata, models.PolicyChangeSetKindMetadata)
+	require.NotNil(t, changeSet.Diff)
+	assert.Equal(t, 1, changeSet.Diff.Created)
+	assert.Equal(t, int64(0), countFields(), "metadata is only written once approved")
+
+	approved := approve(changeSet)
+	assert.True(t, approved.Diff.Applied)
+	require.NotNil(t, approved.Version)
+	assert.Equal(t, int64(1), countFields())
+
+	// Metadata that the policy already matches has nothing to approve
+	assert.Equal(t, http.StatusOK, serve(portal, http.MethodPost, "/api/v1/policy/metadata", metadata).Code)
+
+	grant := `{"applicationId":"app-1","grantDuration":"30d","records":[{"schemaId":"schema-123","fieldName":"person.name"}]}`
+	assert.Equal(t, http.StatusNotFound, serve(portal, http.MethodPost, "/api/v1/policy/update-allowlist",
+		`{"applicationId":"app-1","grantDuration":"30d","records":[{"schemaId":"schema-123","fieldName":"person.age"}]}`).Code)
+	changeSet = propose("/api/v1/policy/update-allowlist", grant, models.PolicyChangeSetKindAllowList)
+	assert.Nil(t, changeSet.Diff)
+	var pm models.PolicyMetadata
+	require.NoError(t, db.First(&pm).Error)
+	assert.NotContains(t, pm.AllowList, "app-1")
+
+	approved = approve(changeSet)
+	assert.Nil(t, approved.Version)
+	require.NoError(t, db.First(&pm).Error)
+	assert.Contains(t, pm.AllowList, "app-1")
+
+	// An approved renewal is proposed as a change set too, and only extends the grants once approved
+	require.NoError(t, db.First(&pm).Error)
+	expiresAt := pm.AllowList["app-1"].ExpiresAt
+	w := serve(portal, http.MethodPost, "/api/v1/policy/renewal-requests",
+		`{"applicationId":"app-1","grantDuration":"365d","records":[{"schemaId":"schema-123","fieldName":"person.name"}]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var renewal models.AllowListRenewalResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&renewal))
+	assert.Equal(t, http.StatusForbidden, serve(portal, http.MethodPut, "/api/v1/policy/renewal-requests/"+renewal.ID, `{"status":"approved"}`).Code)
+	w = serve(admin, http.MethodPut, "/api/v1/policy/renewal-requests/"+renewal.ID, `{"status":"approved"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&changeSet))
+	assert.Equal(t, models.PolicyChangeSetKindAllowList, changeSet.Kind)
+	assert.Equal(t, "admin@example.com", changeSet.ProposedBy)
+	require.NoError(t, db.First(&pm).Error)
+	assert.Equal(t, expiresAt, pm.AllowList["app-1"].ExpiresAt, "grants are only extended once approved")
+
+	// Another admin approves the extension, as the proposer cannot approve their own change set
+	w = serve(&auth.Principal{Subject: "reviewer@example.com", Roles: []string{auth.RoleAdmin}, TenantID: models.DefaultTenantID},
+		http.MethodPut, "/api/v1/policy/change-sets/"+changeSet.ID, `{"status":"approved"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, db.First(&pm).Error)
+	assert.True(t, pm.AllowList["app-1"].ExpiresAt.After(expiresAt))
+
+	changeSet = propose("/api/v1/policy/schema-lifecycle", `{"schemaId":"schema-123","status":"retired"}`, models.PolicyChangeSetKindSchemaLifecycle)
+	approve(changeSet)
+	require.NoError(t, db.First(&pm).Error)
+	assert.Equal(t, models.SchemaLifecycleRetired, pm.LifecycleStatus)
+}
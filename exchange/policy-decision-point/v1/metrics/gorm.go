@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// RegisterGormCallbacks counts failed database operations in pdp_db_errors_total.
+// Record-not-found results are expected lookups and are not counted.
+func RegisterGormCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []struct {
+		operation string
+		register  func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range registrations {
+		if err := r.register("metrics:"+r.operation, dbErrorCallback(r.operation)); err != nil {
+			return fmt.Errorf("failed to register %s metrics callback: %w", r.operation, err)
+		}
+	}
+	return nil
+}
+
+// dbErrorCallback records the error of a finished database operation, if any
+func dbErrorCallback(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			RecordDBError(operation)
+		}
+	}
+}
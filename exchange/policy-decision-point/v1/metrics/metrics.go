@@ -0,0 +1,176 @@
+// Package metrics holds the PDP's decision and SLO instruments. They are created from the
+// shared monitoring meter in main and default to no-ops, so tests and tools need no exporter.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	attrApplicationID = "pdp.application_id"
+	attrFallbackMode  = "pdp.fallback_mode"
+	attrOutcome       = "pdp.outcome"
+	attrOperation     = "pdp.db.operation"
+	attrSchemaID      = "pdp.schema_id"
+)
+
+// decisionBuckets are the latency histogram boundaries in seconds; decisions are expected well under 50ms
+var decisionBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+// instruments are the synchronous instruments recorded by the PDP
+type instruments struct {
+	meter            metric.Meter
+	decisionDuration metric.Float64Histogram
+	decisions        metric.Int64Counter
+	dbErrors         metric.Int64Counter
+	fallbacks        metric.Int64Counter
+}
+
+var current atomic.Pointer[instruments]
+
+func init() {
+	inst, _ := newInstruments(noop.NewMeterProvider().Meter("pdp"))
+	current.Store(inst)
+}
+
+// Init creates the PDP instruments from the given meter
+func Init(meter metric.Meter) error {
+	inst, err := newInstruments(meter)
+	if err != nil {
+		return err
+	}
+	current.Store(inst)
+	return nil
+}
+
+func newInstruments(meter metric.Meter) (*instruments, error) {
+	decisionDuration, err := meter.Float64Histogram(
+		"pdp_decision_duration_seconds",
+		metric.WithDescription("Policy decision evaluation latency in seconds"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(decisionBuckets...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pdp_decision_duration_seconds histogram: %w", err)
+	}
+
+	decisions, err := meter.Int64Counter(
+		"pdp_decisions_total",
+		metric.WithDescription("Total number of policy decisions by application and outcome"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pdp_decisions_total counter: %w", err)
+	}
+
+	dbErrors, err := meter.Int64Counter(
+		"pdp_db_errors_total",
+		metric.WithDescription("Total number of failed database operations"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pdp_db_errors_total counter: %w", err)
+	}
+
+	fallbacks, err := meter.Int64Counter(
+		"pdp_decision_fallbacks_total",
+		metric.WithDescription("Total number of decisions made by a fallback mode while the policy database was unavailable"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pdp_decision_fallbacks_total counter: %w", err)
+	}
+
+	return &instruments{
+		meter:            meter,
+		decisionDuration: decisionDuration,
+		decisions:        decisions,
+		dbErrors:         dbErrors,
+		fallbacks:        fallbacks,
+	}, nil
+}
+
+// RecordDecision records the latency and outcome of a policy decision.
+// Deny rates per consumer application are derived from pdp_decisions_total.
+func RecordDecision(applicationID string, outcome models.DecisionOutcome, latency time.Duration) {
+	inst := current.Load()
+	ctx := context.Background()
+	inst.decisionDuration.Record(ctx, latency.Seconds(),
+		metric.WithAttributes(attribute.String(attrOutcome, string(outcome))))
+	inst.decisions.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String(attrApplicationID, applicationID),
+			attribute.String(attrOutcome, string(outcome)),
+		))
+}
+
+// RecordDBError records a failed database operation
+func RecordDBError(operation string) {
+	current.Load().dbErrors.Add(context.Background(), 1,
+		metric.WithAttributes(attribute.String(attrOperation, operation)))
+}
+
+// RecordDecisionFallback records a decision made by a fallback mode
+func RecordDecisionFallback(mode models.DecisionFallbackMode) {
+	current.Load().fallbacks.Add(context.Background(), 1,
+		metric.WithAttributes(attribute.String(attrFallbackMode, string(mode))))
+}
+
+// CacheSource is the policy cache as seen by the observable instruments
+type CacheSource interface {
+	Stats() models.PolicyCacheStats
+	AllowListSizes() map[string]int
+}
+
+// RegisterPolicyCache observes the cache hit and miss counts and the allow list size of each schema.
+// Call it after Init.
+func RegisterPolicyCache(cache CacheSource) error {
+	meter := current.Load().meter
+
+	hits, err := meter.Int64ObservableCounter(
+		"pdp_policy_cache_hits_total",
+		metric.WithDescription("Total number of decisions served from the policy cache"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pdp_policy_cache_hits_total counter: %w", err)
+	}
+	misses, err := meter.Int64ObservableCounter(
+		"pdp_policy_cache_misses_total",
+		metric.WithDescription("Total number of decisions that fell back to the database"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pdp_policy_cache_misses_total counter: %w", err)
+	}
+	allowListEntries, err := meter.Int64ObservableGauge(
+		"pdp_allow_list_entries",
+		metric.WithDescription("Number of allow list entries per schema"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pdp_allow_list_entries gauge: %w", err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := cache.Stats()
+		o.ObserveInt64(hits, stats.Hits)
+		o.ObserveInt64(misses, stats.Misses)
+		for schemaID, size := range cache.AllowListSizes() {
+			o.ObserveInt64(allowListEntries, int64(size), metric.WithAttributes(attribute.String(attrSchemaID, schemaID)))
+		}
+		return nil
+	}, hits, misses, allowListEntries)
+	if err != nil {
+		return fmt.Errorf("failed to register policy cache callback: %w", err)
+	}
+	return nil
+}
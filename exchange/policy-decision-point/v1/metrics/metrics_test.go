@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// recordingMeter counts Int64Counter additions by instrument name and attributes
+type recordingMeter struct {
+	noop.Meter
+	counts map[string]int64
+}
+
+type recordingCounter struct {
+	noop.Int64Counter
+	name   string
+	counts map[string]int64
+}
+
+func (m *recordingMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &recordingCounter{name: name, counts: m.counts}, nil
+}
+
+func (c *recordingCounter) Add(_ context.Context, incr int64, opts ...metric.AddOption) {
+	set := metric.NewAddConfig(opts).Attributes()
+	c.counts[c.name+"{"+set.Encoded(attribute.DefaultEncoder())+"}"] += incr
+}
+
+func useRecordingMeter(t *testing.T) *recordingMeter {
+	t.Helper()
+	meter := &recordingMeter{counts: map[string]int64{}}
+	previous := current.Load()
+	require.NoError(t, Init(meter))
+	t.Cleanup(func() { current.Store(previous) })
+	return meter
+}
+
+func TestRecordDecision(t *testing.T) {
+	meter := useRecordingMeter(t)
+
+	RecordDecision("app-1", models.DecisionOutcomeDenied, 5*time.Millisecond)
+	RecordDecision("app-1", models.DecisionOutcomeDenied, 5*time.Millisecond)
+	RecordDecision("app-2", models.DecisionOutcomeAllowed, time.Millisecond)
+
+	assert.Equal(t, int64(2), meter.counts["pdp_decisions_total{pdp.application_id=app-1,pdp.outcome=denied}"])
+	assert.Equal(t, int64(1), meter.counts["pdp_decisions_total{pdp.application_id=app-2,pdp.outcome=allowed}"])
+}
+
+func TestRegisterGormCallbacks(t *testing.T) {
+	meter := useRecordingMeter(t)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterGormCallbacks(db))
+
+	type record struct {
+		ID   string `gorm:"primaryKey"`
+		Name string
+	}
+	require.NoError(t, db.AutoMigrate(&record{}))
+
+	// Not found is an expected result, not a database error
+	var r record
+	assert.ErrorIs(t, db.First(&r, "id = ?", "missing").Error, gorm.ErrRecordNotFound)
+	assert.Empty(t, meter.counts)
+
+	assert.Error(t, db.Table("missing_table").Where("id = ?", "x").Find(&[]record{}).Error)
+	assert.Error(t, db.Exec("SELECT * FROM missing_table").Error)
+	assert.Equal(t, int64(1), meter.counts["pdp_db_errors_total{pdp.db.operation=query}"])
+	assert.Equal(t, int64(1), meter.counts["pdp_db_errors_total{pdp.db.operation=raw}"])
+}
+
+func TestRegisterPolicyCache_Noop(t *testing.T) {
+	// The default no-op meter accepts registrations without an exporter
+	assert.NoError(t, RegisterPolicyCache(stubCache{}))
+}
+
+type stubCache struct{}
+
+func (stubCache) Stats() models.PolicyCacheStats { return models.PolicyCacheStats{} }
+func (stubCache) AllowListSizes() map[string]int { return nil }
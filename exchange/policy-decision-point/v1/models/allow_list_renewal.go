@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RenewalFields represents the JSONB list of fields covered by a renewal request
+type RenewalFields []AllowListUpdateRequestRecord
+
+// Scan implements the sql.Scanner interface for RenewalFields
+func (rf *RenewalFields) Scan(value interface{}) error {
+	if value == nil {
+		*rf = RenewalFields{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into RenewalFields", value)
+	}
+
+	if len(bytes) == 0 {
+		*rf = RenewalFields{}
+		return nil
+	}
+
+	return json.Unmarshal(bytes, rf)
+}
+
+// Value implements the driver.Valuer interface for RenewalFields
+func (rf RenewalFields) Value() (driver.Value, error) {
+	if rf == nil {
+		return json.Marshal([]AllowListUpdateRequestRecord{})
+	}
+	return json.Marshal(rf)
+}
+
+// AllowListRenewalRequest represents the allow_list_renewal_requests table
+type AllowListRenewalRequest struct {
+	ID            uuid.UUID            `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID      string               `gorm:"column:tenant_id;type:varchar(255);not null;default:'default';index" json:"tenantId"`
+	ApplicationID string               `gorm:"column:application_id;type:varchar(255);not null;index" json:"applicationId"`
+	Fields        RenewalFields        `gorm:"column:fields;type:jsonb;not null;default:'[]'" json:"fields"`
+	GrantDuration GrantDurationType    `gorm:"column:grant_duration;type:varchar(10);not null" json:"grantDuration"`
+	Status        RenewalRequestStatus `gorm:"column:status;type:varchar(20);not null;default:'pending';index" json:"status"`
+	Reason        *string              `gorm:"column:reason;type:text" json:"reason,omitempty"`
+	ReviewComment *string              `gorm:"column:review_comment;type:text" json:"reviewComment,omitempty"`
+	CreatedAt     time.Time            `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt     time.Time            `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+// TableName specifies the table name for GORM
+func (AllowListRenewalRequest) TableName() string {
+	return "allow_list_renewal_requests"
+}
+
+// ToResponse converts AllowListRenewalRequest to AllowListRenewalResponse
+func (r *AllowListRenewalRequest) ToResponse() AllowListRenewalResponse {
+	return AllowListRenewalResponse{
+		ID:            r.ID.String(),
+		TenantID:      r.TenantID,
+		ApplicationID: r.ApplicationID,
+		Records:       r.Fields,
+		GrantDuration: r.GrantDuration,
+		Status:        r.Status,
+		Reason:        r.Reason,
+		ReviewComment: r.ReviewComment,
+		CreatedAt:     r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     r.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,110 @@
+package models
+
+import "fmt"
+
+// Classification represents the data classification tier of a field
+type Classification string
+
+const (
+	ClassificationPublic            Classification = "public"
+	ClassificationInternal          Classification = "internal"
+	ClassificationPersonal          Classification = "personal"
+	ClassificationSensitivePersonal Classification = "sensitive-personal"
+)
+
+// DefaultClassification is applied to fields created without an explicit classification
+const DefaultClassification = ClassificationPersonal
+
+// ClassificationRule holds the tier-level defaults applied to fields of a classification
+type ClassificationRule struct {
+	Classification Classification `json:"classification"`
+	// DefaultAccessControlType is used when a field is created without an access control type
+	DefaultAccessControlType AccessControlType `json:"defaultAccessControlType"`
+	// AlwaysRequiresConsent makes citizen-owned fields require consent even when marked public
+	AlwaysRequiresConsent bool `json:"alwaysRequiresConsent"`
+	// ConsentGrantDuration is how long citizen consent to fields of the tier stays valid,
+	// as an ISO 8601 duration accepted by the consent engine
+	ConsentGrantDuration string `json:"consentGrantDuration"`
+	// ConsentPurpose is the purpose stated to the owner when consent to fields of the tier is requested
+	ConsentPurpose string `json:"consentPurpose"`
+	Description    string `json:"description"`
+}
+
+// classificationRules holds the default rules for each classification tier, from least to most sensitive
+var classificationRules = []ClassificationRule{
+	{
+		Classification:           ClassificationPublic,
+		DefaultAccessControlType: AccessControlTypePublic,
+		ConsentGrantDuration:     "P30D",
+		ConsentPurpose:           "Release of openly published data",
+		Description:              "Openly published data; released to any allow-listed application",
+	},
+	{
+		Classification:           ClassificationInternal,
+		DefaultAccessControlType: AccessControlTypeRestricted,
+		ConsentGrantDuration:     "P1D",
+		ConsentPurpose:           "Release of government-internal data held about the owner",
+		Description:              "Government-internal data; restricted unless explicitly made public",
+	},
+	{
+		Classification:           ClassificationPersonal,
+		DefaultAccessControlType: AccessControlTypeRestricted,
+		ConsentGrantDuration:     "PT1H",
+		ConsentPurpose:           "Release of personal data for the requested service",
+		Description:              "Personal data about a citizen; requires consent unless explicitly made public",
+	},
+	{
+		Classification:           ClassificationSensitivePersonal,
+		DefaultAccessControlType: AccessControlTypeRestricted,
+		AlwaysRequiresConsent:    true,
+		ConsentGrantDuration:     "PT1H",
+		ConsentPurpose:           "Release of sensitive personal data for the requested service",
+		Description:              "Sensitive personal data (e.g. health, biometrics); always requires citizen consent",
+	},
+}
+
+// ClassificationRules returns the default rules of all classification tiers
+func ClassificationRules() []ClassificationRule {
+	rules := make([]ClassificationRule, len(classificationRules))
+	copy(rules, classificationRules)
+	return rules
+}
+
+// Rule returns the default rule for the classification, falling back to the default classification
+func (c Classification) Rule() ClassificationRule {
+	if c == "" {
+		c = DefaultClassification
+	}
+	for _, rule := range classificationRules {
+		if rule.Classification == c {
+			return rule
+		}
+	}
+	return DefaultClassification.Rule()
+}
+
+// Sensitivity returns the position of the classification among the tiers, higher for more
+// sensitive data. Unknown classifications rank as the default classification.
+func (c Classification) Sensitivity() int {
+	c = c.Rule().Classification
+	for i, rule := range classificationRules {
+		if rule.Classification == c {
+			return i
+		}
+	}
+	return 0
+}
+
+// Validate checks that the classification is a known tier. An empty classification is valid
+// and resolves to the default classification.
+func (c Classification) Validate() error {
+	if c == "" {
+		return nil
+	}
+	for _, rule := range classificationRules {
+		if rule.Classification == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid classification: %s", c)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassification_Rule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		classification        Classification
+		wantAccessControlType AccessControlType
+		wantAlwaysConsent     bool
+	}{
+		{"public", ClassificationPublic, AccessControlTypePublic, false},
+		{"internal", ClassificationInternal, AccessControlTypeRestricted, false},
+		{"personal", ClassificationPersonal, AccessControlTypeRestricted, false},
+		{"sensitive personal", ClassificationSensitivePersonal, AccessControlTypeRestricted, true},
+		{"empty uses default", "", AccessControlTypeRestricted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.classification.Rule()
+			assert.Equal(t, tt.wantAccessControlType, rule.DefaultAccessControlType)
+			assert.Equal(t, tt.wantAlwaysConsent, rule.AlwaysRequiresConsent)
+		})
+	}
+}
+
+func TestClassification_Validate(t *testing.T) {
+	assert.NoError(t, ClassificationSensitivePersonal.Validate())
+	assert.NoError(t, Classification("").Validate())
+	assert.Error(t, Classification("secret").Validate())
+}
+
+func TestClassificationRules(t *testing.T) {
+	rules := ClassificationRules()
+	assert.Len(t, rules, 4)
+
+	// Returned rules are a copy
+	rules[0].AlwaysRequiresConsent = true
+	assert.False(t, ClassificationPublic.Rule().AlwaysRequiresConsent)
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DecisionFallbackMode defines how decisions are made while the policy database is unavailable
+type DecisionFallbackMode string
+
+const (
+	// DecisionFallbackFailClosed fails decisions until the database is back (most secure, the default)
+	DecisionFallbackFailClosed DecisionFallbackMode = "fail_closed"
+	// DecisionFallbackFailOpenPinned authorizes a pinned list of low-sensitivity fields for every
+	// application and denies all other fields
+	DecisionFallbackFailOpenPinned DecisionFallbackMode = "fail_open_pinned"
+	// DecisionFallbackLastKnownGood evaluates decisions against the last policies loaded into memory,
+	// as long as they were confirmed current within a staleness limit
+	DecisionFallbackLastKnownGood DecisionFallbackMode = "last_known_good"
+)
+
+// ParseDecisionFallbackMode parses a fallback mode; an empty value is fail_closed
+func ParseDecisionFallbackMode(value string) (DecisionFallbackMode, error) {
+	switch mode := DecisionFallbackMode(strings.TrimSpace(value)); mode {
+	case "":
+		return DecisionFallbackFailClosed, nil
+	case DecisionFallbackFailClosed, DecisionFallbackFailOpenPinned, DecisionFallbackLastKnownGood:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid decision fallback mode %q: valid options are fail_closed, fail_open_pinned, last_known_good", value)
+}
+
+// FieldKey identifies a field of a schema as "schemaId:fieldName", as pinned fields are configured
+func FieldKey(schemaID, fieldName string) string {
+	return schemaID + ":" + fieldName
+}
+
+// ParsePinnedFields parses a comma-separated list of "schemaId:fieldName" entries into a set of field keys
+func ParsePinnedFields(value string) (map[string]struct{}, error) {
+	fields := make(map[string]struct{})
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		schemaID, fieldName, ok := strings.Cut(entry, ":")
+		schemaID, fieldName = strings.TrimSpace(schemaID), strings.TrimSpace(fieldName)
+		if !ok || schemaID == "" || fieldName == "" {
+			return nil, fmt.Errorf("invalid pinned field %q: expected schemaId:fieldName", entry)
+		}
+		fields[FieldKey(schemaID, fieldName)] = struct{}{}
+	}
+	return fields, nil
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecisionFallbackMode(t *testing.T) {
+	for value, expected := range map[string]DecisionFallbackMode{
+		"":                 DecisionFallbackFailClosed,
+		"fail_closed":      DecisionFallbackFailClosed,
+		"fail_open_pinned": DecisionFallbackFailOpenPinned,
+		" last_known_good": DecisionFallbackLastKnownGood,
+	} {
+		mode, err := ParseDecisionFallbackMode(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, mode)
+	}
+
+	_, err := ParseDecisionFallbackMode("fail_open")
+	assert.Error(t, err)
+}
+
+func TestParsePinnedFields(t *testing.T) {
+	fields, err := ParsePinnedFields(" schema-1:person.name, schema-2:vehicle.make ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{
+		"schema-1:person.name":  {},
+		"schema-2:vehicle.make": {},
+	}, fields)
+
+	fields, err = ParsePinnedFields("")
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	for _, invalid := range []string{"person.name", ":person.name", "schema-1:"} {
+		_, err := ParsePinnedFields(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -1,38 +1,55 @@
 package models
 
+import "time"
+
 // Request and Response DTOs
 
 // PolicyMetadataCreateRequestRecord represents the request to create policy metadata
 type PolicyMetadataCreateRequestRecord struct {
-	FieldName         string            `json:"fieldName" validate:"required"`
-	DisplayName       *string           `json:"displayName,omitempty"`
-	Description       *string           `json:"description,omitempty"`
-	Source            Source            `json:"source" validate:"required,source_enum"`
-	IsOwner           bool              `json:"isOwner" validate:"required"`
-	AccessControlType AccessControlType `json:"accessControlType" validate:"required,access_control_type_enum"`
+	FieldName   string  `json:"fieldName" validate:"required"`
+	DisplayName *string `json:"displayName,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Source      Source  `json:"source" validate:"required,source_enum"`
+	IsOwner     bool    `json:"isOwner" validate:"required"`
+	// AccessControlType defaults to the classification tier's default when omitted
+	AccessControlType AccessControlType `json:"accessControlType,omitempty" validate:"omitempty,access_control_type_enum"`
+	Classification    Classification    `json:"classification,omitempty"`
 	Owner             *Owner            `json:"owner,omitempty" validate:"omitempty,owner_enum"`
+	Conditions        PolicyConditions  `json:"conditions,omitempty"`
 }
 
 // PolicyMetadataCreateRequest represents the request to create policy metadata
 type PolicyMetadataCreateRequest struct {
 	SchemaID string                              `json:"schemaId" validate:"required"`
 	Records  []PolicyMetadataCreateRequestRecord `json:"records" validate:"required,dive"`
+	// ProviderID is the provider that owns the schema
+	ProviderID string `json:"providerId,omitempty"`
+	// TenantID is set to the caller's tenant
+	TenantID string `json:"-"`
 }
 
 // PolicyMetadataResponse represents the response from policy metadata operations
 type PolicyMetadataResponse struct {
-	ID                string            `json:"id"`
-	SchemaID          string            `json:"schemaId"`
-	FieldName         string            `json:"fieldName"`
-	DisplayName       *string           `json:"displayName,omitempty"`
-	Description       *string           `json:"description,omitempty"`
-	Source            Source            `json:"source"`
-	IsOwner           bool              `json:"isOwner"`
-	AccessControlType AccessControlType `json:"accessControlType"`
-	AllowList         AllowList         `json:"allowList"`
-	Owner             *Owner            `json:"owner,omitempty"`
-	CreatedAt         string            `json:"createdAt"`
-	UpdatedAt         string            `json:"updatedAt"`
+	ID                string                `json:"id"`
+	TenantID          string                `json:"tenantId"`
+	ProviderID        string                `json:"providerId,omitempty"`
+	Namespace         string                `json:"namespace"`
+	SchemaID          string                `json:"schemaId"`
+	FieldName         string                `json:"fieldName"`
+	DisplayName       *string               `json:"displayName,omitempty"`
+	Description       *string               `json:"description,omitempty"`
+	Source            Source                `json:"source"`
+	IsOwner           bool                  `json:"isOwner"`
+	AccessControlType AccessControlType     `json:"accessControlType"`
+	Classification    Classification        `json:"classification"`
+	AllowList         AllowList             `json:"allowList"`
+	Owner             *Owner                `json:"owner,omitempty"`
+	Conditions        PolicyConditions      `json:"conditions,omitempty"`
+	LifecycleStatus   SchemaLifecycleStatus `json:"lifecycleStatus,omitempty"`
+	SunsetAt          *time.Time            `json:"sunsetAt,omitempty"`
+	LifecycleMessage  *string               `json:"lifecycleMessage,omitempty"`
+	CreatedAt         string                `json:"createdAt"`
+	UpdatedAt         string                `json:"updatedAt"`
 }
 
 // PolicyMetadataCreateResponse represents the response from policy metadata creation
@@ -51,6 +68,10 @@ type AllowListUpdateRequest struct {
 	ApplicationID string                         `json:"applicationId" validate:"required"`
 	Records       []AllowListUpdateRequestRecord `json:"records" validate:"required,dive"`
 	GrantDuration GrantDurationType              `json:"grantDuration" validate:"required,grant_duration_type_enum"`
+	// ConsumerID is the consumer that owns the application; existing grants keep theirs when omitted
+	ConsumerID string `json:"consumerId,omitempty"`
+	// TenantID is set to the caller's tenant
+	TenantID string `json:"-"`
 }
 
 // AllowListUpdateResponseRecord represents one record in the allow list update response
@@ -76,6 +97,9 @@ type PolicyDecisionRequestRecord struct {
 type PolicyDecisionRequest struct {
 	ApplicationID  string                        `json:"applicationId" validate:"required"`
 	RequiredFields []PolicyDecisionRequestRecord `json:"requiredFields" validate:"required,dive"`
+	// Context holds request attributes (e.g. consumer.sector, citizen.age, request.time)
+	// that policy conditions are evaluated against
+	Context map[string]interface{} `json:"context,omitempty"`
 }
 
 // PolicyDecisionResponseFieldRecord represents a policy decision response record
@@ -85,6 +109,10 @@ type PolicyDecisionResponseFieldRecord struct {
 	DisplayName *string `json:"displayName,omitempty"`
 	Description *string `json:"description,omitempty"`
 	Owner       *Owner  `json:"owner,omitempty"`
+	// FailedCondition is the condition that did not hold, set only for condition failed fields
+	FailedCondition *PolicyCondition `json:"failedCondition,omitempty"`
+	// BlockedBy is the kind of kill switch that blocked the field, set only for blocked fields
+	BlockedBy KillSwitchTargetType `json:"blockedBy,omitempty"`
 }
 
 // PolicyDecisionResponse represents a policy decision response
@@ -95,4 +123,213 @@ type PolicyDecisionResponse struct {
 	ExpiredFields           []PolicyDecisionResponseFieldRecord `json:"expiredFields"`
 	AppRequiresOwnerConsent bool                                `json:"appRequiresOwnerConsent"`
 	ConsentRequiredFields   []PolicyDecisionResponseFieldRecord `json:"consentRequiredFields"`
+	ConditionFailedFields   []PolicyDecisionResponseFieldRecord `json:"conditionFailedFields"`
+	// DeprecatedFields are requested fields of deprecated or sunset schemas, which consumers should move off
+	DeprecatedFields []PolicyDecisionDeprecatedFieldRecord `json:"deprecatedFields,omitempty"`
+	// BlockedFields are requested fields blocked by a kill switch, for the application, provider or field
+	BlockedFields []PolicyDecisionResponseFieldRecord `json:"blockedFields,omitempty"`
+	PolicyVersion string                              `json:"policyVersion,omitempty"`
+	// Fallback is set when the policy database was unavailable and the decision was made by this fallback mode
+	Fallback DecisionFallbackMode `json:"fallback,omitempty"`
+}
+
+// ConsentRequirement is the consent the OE must request from one owner before releasing fields
+type ConsentRequirement struct {
+	Owner       Owner  `json:"owner"`
+	ConsentType string `json:"consentType"`
+	// GrantDuration is the ISO 8601 duration of the consent, the shortest of the fields' classification tiers
+	GrantDuration string `json:"grantDuration"`
+	// Purposes are the distinct purposes of the fields' classification tiers, least sensitive first
+	Purposes []string                            `json:"purposes"`
+	Fields   []PolicyDecisionResponseFieldRecord `json:"fields"`
+}
+
+// ConsentRequirementsResponse holds the consolidated consent requirements for a decision request
+type ConsentRequirementsResponse struct {
+	ApplicationID   string               `json:"applicationId"`
+	ConsentRequired bool                 `json:"consentRequired"`
+	Requirements    []ConsentRequirement `json:"requirements"`
+	PolicyVersion   string               `json:"policyVersion,omitempty"`
+}
+
+// AllowListRenewalCreateRequest represents a consumer request to renew existing allow list grants
+type AllowListRenewalCreateRequest struct {
+	ApplicationID string                         `json:"applicationId" validate:"required"`
+	Records       []AllowListUpdateRequestRecord `json:"records" validate:"required,dive"`
+	GrantDuration GrantDurationType              `json:"grantDuration" validate:"required,grant_duration_type_enum"`
+	Reason        *string                        `json:"reason,omitempty"`
+	// TenantID is set to the caller's tenant
+	TenantID string `json:"-"`
+}
+
+// AllowListRenewalReviewRequest represents the api-server decision on a pending renewal request
+type AllowListRenewalReviewRequest struct {
+	Status        RenewalRequestStatus `json:"status" validate:"required"`
+	ReviewComment *string              `json:"reviewComment,omitempty"`
+	// TenantID and ReviewedBy are taken from the reviewer's access token
+	TenantID   string `json:"-"`
+	ReviewedBy string `json:"-"`
+}
+
+// AllowListRenewalResponse represents an allow list renewal request
+type AllowListRenewalResponse struct {
+	ID            string                         `json:"id"`
+	TenantID      string                         `json:"tenantId"`
+	ApplicationID string                         `json:"applicationId"`
+	Records       []AllowListUpdateRequestRecord `json:"records"`
+	GrantDuration GrantDurationType              `json:"grantDuration"`
+	Status        RenewalRequestStatus           `json:"status"`
+	Reason        *string                        `json:"reason,omitempty"`
+	ReviewComment *string                        `json:"reviewComment,omitempty"`
+	CreatedAt     string                         `json:"createdAt"`
+	UpdatedAt     string                         `json:"updatedAt"`
+}
+
+// AllowListRenewalListResponse represents a list of allow list renewal requests
+type AllowListRenewalListResponse struct {
+	Records []AllowListRenewalResponse `json:"records"`
+	Count   int                        `json:"count"`
+}
+
+// PolicyDecisionLogFieldResponse represents one field evaluated in a recorded policy decision
+type PolicyDecisionLogFieldResponse struct {
+	SchemaID  string              `json:"schemaId"`
+	FieldName string              `json:"fieldName"`
+	Result    FieldDecisionResult `json:"result,omitempty"`
+}
+
+// PolicyDecisionLogResponse represents a recorded policy decision
+type PolicyDecisionLogResponse struct {
+	ID            string                           `json:"id"`
+	ApplicationID string                           `json:"applicationId"`
+	Outcome       DecisionOutcome                  `json:"outcome"`
+	LatencyMs     float64                          `json:"latencyMs"`
+	PolicyVersion *string                          `json:"policyVersion,omitempty"`
+	Error         *string                          `json:"error,omitempty"`
+	Context       map[string]interface{}           `json:"context,omitempty"`
+	Fields        []PolicyDecisionLogFieldResponse `json:"fields"`
+	CreatedAt     string                           `json:"createdAt"`
+}
+
+// PolicyDecisionLogFilter represents the filters for querying recorded policy decisions
+type PolicyDecisionLogFilter struct {
+	ApplicationID string
+	SchemaID      string
+	FieldName     string
+	Outcome       string
+	From          *time.Time
+	To            *time.Time
+	// MinLatency selects decisions that took at least this long
+	MinLatency time.Duration
+	Limit      int
+	Offset     int
+}
+
+// PolicyDecisionLogListResponse represents a page of recorded policy decisions
+type PolicyDecisionLogListResponse struct {
+	Records []PolicyDecisionLogResponse `json:"records"`
+	Total   int64                       `json:"total"`
+	Limit   int                         `json:"limit"`
+	Offset  int                         `json:"offset"`
+}
+
+// PolicyCacheStats represents the state of the in-memory policy cache
+type PolicyCacheStats struct {
+	Loaded          bool   `json:"loaded"`
+	Version         string `json:"version,omitempty"`
+	Schemas         int    `json:"schemas"`
+	Entries         int    `json:"entries"`
+	Hits            int64  `json:"hits"`
+	Misses          int64  `json:"misses"`
+	Refreshes       int64  `json:"refreshes"`
+	RefreshErrors   int64  `json:"refreshErrors"`
+	LastRefreshedAt string `json:"lastRefreshedAt,omitempty"`
+	// LastVerifiedAt is when the cached policies were last confirmed current, by a refresh or a version poll
+	LastVerifiedAt string `json:"lastVerifiedAt,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+}
+
+// ClassificationListResponse represents the classification tiers and their default rules
+type ClassificationListResponse struct {
+	Records []ClassificationRule `json:"records"`
+}
+
+// PolicySimulationChange represents a hypothetical change to the policy of one field.
+// Only the attributes that are set are changed.
+type PolicySimulationChange struct {
+	SchemaID          string             `json:"schemaId" validate:"required"`
+	FieldName         string             `json:"fieldName" validate:"required"`
+	IsOwner           *bool              `json:"isOwner,omitempty"`
+	AccessControlType *AccessControlType `json:"accessControlType,omitempty"`
+	Classification    *Classification    `json:"classification,omitempty"`
+	Conditions        *PolicyConditions  `json:"conditions,omitempty"`
+	// GrantApplications are added to the allow list for GrantDuration
+	GrantApplications []string          `json:"grantApplications,omitempty"`
+	GrantDuration     GrantDurationType `json:"grantDuration,omitempty"`
+	// RevokeApplications are removed from the allow list
+	RevokeApplications []string `json:"revokeApplications,omitempty"`
+}
+
+// PolicySimulationRequest represents a what-if evaluation of policy changes against recent decisions
+type PolicySimulationRequest struct {
+	Changes []PolicySimulationChange `json:"changes" validate:"required,dive"`
+	// ApplicationID limits the sample to decisions made for one application
+	ApplicationID string `json:"applicationId,omitempty"`
+	// SampleSize is the number of most recent recorded decisions to replay
+	SampleSize int `json:"sampleSize,omitempty"`
+}
+
+// PolicySimulationFieldChange represents a field whose result differs under the simulated policy
+type PolicySimulationFieldChange struct {
+	SchemaID        string              `json:"schemaId"`
+	FieldName       string              `json:"fieldName"`
+	CurrentResult   FieldDecisionResult `json:"currentResult"`
+	SimulatedResult FieldDecisionResult `json:"simulatedResult"`
+}
+
+// PolicySimulationResult represents a recorded decision that is affected by the simulated policy
+type PolicySimulationResult struct {
+	DecisionID       string                        `json:"decisionId"`
+	ApplicationID    string                        `json:"applicationId"`
+	DecidedAt        string                        `json:"decidedAt"`
+	RecordedOutcome  DecisionOutcome               `json:"recordedOutcome"`
+	CurrentOutcome   DecisionOutcome               `json:"currentOutcome"`
+	SimulatedOutcome DecisionOutcome               `json:"simulatedOutcome"`
+	OutcomeFlipped   bool                          `json:"outcomeFlipped"`
+	ChangedFields    []PolicySimulationFieldChange `json:"changedFields"`
+}
+
+// PolicySimulationResponse represents the blast radius of the simulated policy changes
+type PolicySimulationResponse struct {
+	Evaluated int                      `json:"evaluated"`
+	Affected  int                      `json:"affected"`
+	Flipped   int                      `json:"flipped"`
+	Results   []PolicySimulationResult `json:"results"`
+}
+
+// ConsumerGrantFilter represents the filters for listing consumer grants
+type ConsumerGrantFilter struct {
+	TenantID       string
+	ConsumerID     string
+	ApplicationID  string
+	IncludeExpired bool
+}
+
+// ConsumerGrantResponse represents one field an application is allow-listed for
+type ConsumerGrantResponse struct {
+	SchemaID       string         `json:"schemaId"`
+	FieldName      string         `json:"fieldName"`
+	DisplayName    *string        `json:"displayName,omitempty"`
+	Classification Classification `json:"classification"`
+	ApplicationID  string         `json:"applicationId"`
+	ConsumerID     string         `json:"consumerId,omitempty"`
+	ExpiresAt      string         `json:"expiresAt"`
+	UpdatedAt      string         `json:"updatedAt"`
+	Expired        bool           `json:"expired"`
+}
+
+// ConsumerGrantListResponse represents the grants held by a consumer's applications
+type ConsumerGrantListResponse struct {
+	Records []ConsumerGrantResponse `json:"records"`
+	Count   int                     `json:"count"`
 }
@@ -0,0 +1,138 @@
+package models
+
+import "strings"
+
+// LegacyPolicy is a row of the policies table of the retired policy-governance service, which granted
+// each consumer access to a field of a provider subgraph
+type LegacyPolicy struct {
+	ConsumerID     string `gorm:"column:consumer_id" json:"consumerId"`
+	Subgraph       string `gorm:"column:subgraph" json:"subgraph"`
+	Type           string `gorm:"column:type" json:"type"`
+	Field          string `gorm:"column:field" json:"field"`
+	Classification string `gorm:"column:classification" json:"classification"`
+}
+
+// TableName specifies the table name for GORM
+func (LegacyPolicy) TableName() string {
+	return "policies"
+}
+
+// FieldName is the PDP field name of the policy's field, e.g. PersonData.fullName
+func (p LegacyPolicy) FieldName() string {
+	typeName := strings.TrimSpace(p.Type)
+	field := strings.TrimSpace(p.Field)
+	if typeName == "" {
+		return field
+	}
+	return typeName + "." + field
+}
+
+// LegacyClassification is the access classification of a legacy policy
+type LegacyClassification string
+
+const (
+	LegacyClassificationAllow                LegacyClassification = "ALLOW"
+	LegacyClassificationAllowProviderConsent LegacyClassification = "ALLOW_PROVIDER_CONSENT"
+	LegacyClassificationAllowCitizenConsent  LegacyClassification = "ALLOW_CITIZEN_CONSENT"
+	LegacyClassificationAllowConsent         LegacyClassification = "ALLOW_CONSENT"
+	LegacyClassificationDenied               LegacyClassification = "DENIED"
+)
+
+// LegacyGrant is what a legacy classification means in the PDP
+type LegacyGrant struct {
+	// Allowed puts the consumer in the field's allow list
+	Allowed bool
+	// ConsentRequired makes the field citizen-owned, so that the citizen must consent to each release
+	ConsentRequired bool
+}
+
+// legacyGrants maps the legacy classifications to PDP grants. Provider consent was given when the
+// provider approved the policy, which the allow list entry stands for.
+var legacyGrants = map[LegacyClassification]LegacyGrant{
+	LegacyClassificationAllow:                {Allowed: true},
+	LegacyClassificationAllowProviderConsent: {Allowed: true},
+	LegacyClassificationAllowCitizenConsent:  {Allowed: true, ConsentRequired: true},
+	LegacyClassificationAllowConsent:         {Allowed: true, ConsentRequired: true},
+	LegacyClassificationDenied:               {},
+}
+
+// ParseLegacyClassification returns the grant of a legacy classification, which is matched case-insensitively
+func ParseLegacyClassification(value string) (LegacyGrant, bool) {
+	grant, ok := legacyGrants[LegacyClassification(strings.ToUpper(strings.TrimSpace(value)))]
+	return grant, ok
+}
+
+// LegacyMigrationOptions control how legacy policies are imported
+type LegacyMigrationOptions struct {
+	// TenantID and ProviderID are the namespace of the imported fields
+	TenantID   string
+	ProviderID string
+	// SchemaIDs maps legacy subgraph names to PDP schema IDs; subgraphs are used as schema IDs when it is empty
+	SchemaIDs map[string]string
+	// GrantDuration is how long the imported allow list entries are valid
+	GrantDuration GrantDurationType
+	DryRun        bool
+}
+
+// LegacyMigrationAction represents what a migration does to a field
+type LegacyMigrationAction string
+
+const (
+	LegacyMigrationActionCreate LegacyMigrationAction = "create"
+	// LegacyMigrationActionMerge adds consumers to the allow list of a field the PDP already has
+	LegacyMigrationActionMerge     LegacyMigrationAction = "merge"
+	LegacyMigrationActionUnchanged LegacyMigrationAction = "unchanged"
+)
+
+// LegacyMigrationField represents a field the migration creates or grants consumers access to
+type LegacyMigrationField struct {
+	SchemaID        string                `json:"schemaId"`
+	FieldName       string                `json:"fieldName"`
+	Action          LegacyMigrationAction `json:"action"`
+	ConsentRequired bool                  `json:"consentRequired"`
+	// GrantedConsumers are the consumers added to the field's allow list
+	GrantedConsumers []string `json:"grantedConsumers,omitempty"`
+}
+
+// LegacyMigrationSeverity tells whether a problem stops the migration
+type LegacyMigrationSeverity string
+
+const (
+	LegacyMigrationSeverityError   LegacyMigrationSeverity = "error"
+	LegacyMigrationSeverityWarning LegacyMigrationSeverity = "warning"
+)
+
+// LegacyMigrationProblem describes a legacy row or field that could not be migrated as is
+type LegacyMigrationProblem struct {
+	Severity   LegacyMigrationSeverity `json:"severity"`
+	ConsumerID string                  `json:"consumerId,omitempty"`
+	SchemaID   string                  `json:"schemaId,omitempty"`
+	FieldName  string                  `json:"fieldName,omitempty"`
+	Message    string                  `json:"message"`
+}
+
+// LegacyMigrationReport is the outcome of a legacy policy migration
+type LegacyMigrationReport struct {
+	Applied   bool `json:"applied"`
+	Rows      int  `json:"rows"`
+	Created   int  `json:"created"`
+	Merged    int  `json:"merged"`
+	Unchanged int  `json:"unchanged"`
+	// Grants is the number of allow list entries added
+	Grants int `json:"grants"`
+	// Verified is set when the stored policy was read back after the migration and grants every
+	// legacy consumer the access it had
+	Verified bool                     `json:"verified"`
+	Fields   []LegacyMigrationField   `json:"fields"`
+	Problems []LegacyMigrationProblem `json:"problems"`
+}
+
+// HasErrors reports whether any problem stopped the migration
+func (r *LegacyMigrationReport) HasErrors() bool {
+	for _, problem := range r.Problems {
+		if problem.Severity == LegacyMigrationSeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package models
+
+import "strings"
+
+// DefaultTenantID is the tenant that owns policy records when no tenant is given.
+// Single-organization deployments only ever use this tenant.
+const DefaultTenantID = "default"
+
+// TenantIDHeader is the request header that scopes policy administration to a tenant
+const TenantIDHeader = "X-Tenant-ID"
+
+// Namespace returns the fully qualified namespace of the record's schema, "tenant/provider/schema".
+// A schema belongs to exactly one tenant and provider, so identically named fields of different
+// providers never share a namespace.
+func (pm *PolicyMetadata) Namespace() string {
+	return strings.Join([]string{pm.TenantID, pm.ProviderID, pm.SchemaID}, "/")
+}
+
+// OwnedBy reports whether the record belongs to the given tenant and provider.
+// Records created before providers were recorded have no provider and can be claimed by any.
+func (pm *PolicyMetadata) OwnedBy(tenantID, providerID string) bool {
+	if pm.TenantID != tenantID {
+		return false
+	}
+	return pm.ProviderID == "" || providerID == "" || pm.ProviderID == providerID
+}
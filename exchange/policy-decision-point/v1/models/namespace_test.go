@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyMetadata_Namespace(t *testing.T) {
+	pm := PolicyMetadata{TenantID: "ministry-a", ProviderID: "drp", SchemaID: "schema-123"}
+	assert.Equal(t, "ministry-a/drp/schema-123", pm.Namespace())
+}
+
+func TestPolicyMetadata_OwnedBy(t *testing.T) {
+	tests := []struct {
+		name       string
+		record     PolicyMetadata
+		tenantID   string
+		providerID string
+		expected   bool
+	}{
+		{"same tenant and provider", PolicyMetadata{TenantID: "t1", ProviderID: "p1"}, "t1", "p1", true},
+		{"other tenant", PolicyMetadata{TenantID: "t1", ProviderID: "p1"}, "t2", "p1", false},
+		{"other provider", PolicyMetadata{TenantID: "t1", ProviderID: "p1"}, "t1", "p2", false},
+		{"record without provider", PolicyMetadata{TenantID: "t1"}, "t1", "p2", true},
+		{"caller without provider", PolicyMetadata{TenantID: "t1", ProviderID: "p1"}, "t1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.record.OwnedBy(tt.tenantID, tt.providerID))
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PolicyChangeSetStatus represents the review status of a policy change set
+type PolicyChangeSetStatus string
+
+const (
+	PolicyChangeSetStatusPending  PolicyChangeSetStatus = "pending"
+	PolicyChangeSetStatusApproved PolicyChangeSetStatus = "approved"
+	PolicyChangeSetStatusRejected PolicyChangeSetStatus = "rejected"
+)
+
+// PolicyChangeSetKind is the kind of policy write a change set proposes
+type PolicyChangeSetKind string
+
+const (
+	// PolicyChangeSetKindDocument applies a policy document, like an import
+	PolicyChangeSetKindDocument PolicyChangeSetKind = "document"
+	// PolicyChangeSetKindMetadata sets the policy metadata of a schema, like POST /metadata
+	PolicyChangeSetKindMetadata PolicyChangeSetKind = "metadata"
+	// PolicyChangeSetKindAllowList grants an application fields, like POST /update-allowlist
+	PolicyChangeSetKindAllowList PolicyChangeSetKind = "allow_list"
+	// PolicyChangeSetKindSchemaLifecycle sets the lifecycle stage of a schema, like POST /schema-lifecycle
+	PolicyChangeSetKindSchemaLifecycle PolicyChangeSetKind = "schema_lifecycle"
+)
+
+// PolicyFieldRef identifies a field policy
+type PolicyFieldRef struct {
+	SchemaID  string `json:"schemaId"`
+	FieldName string `json:"fieldName"`
+}
+
+// PolicyChangeImpact is a consumer application holding unexpired grants on fields a change set updates or deletes
+type PolicyChangeImpact struct {
+	ApplicationID string `json:"applicationId"`
+	// ConsumerID is the consumer (member) that owns the application, when known
+	ConsumerID string           `json:"consumerId,omitempty"`
+	Fields     []PolicyFieldRef `json:"fields"`
+}
+
+// PolicyChangeImpacts represents the JSONB list of consumers affected by a change set
+type PolicyChangeImpacts []PolicyChangeImpact
+
+// Scan implements the sql.Scanner interface for PolicyChangeImpacts
+func (pi *PolicyChangeImpacts) Scan(value interface{}) error {
+	return scanJSON(value, pi, "PolicyChangeImpacts")
+}
+
+// Value implements the driver.Valuer interface for PolicyChangeImpacts
+func (pi PolicyChangeImpacts) Value() (driver.Value, error) {
+	if pi == nil {
+		return json.Marshal([]PolicyChangeImpact{})
+	}
+	return json.Marshal(pi)
+}
+
+// Scan implements the sql.Scanner interface for PolicyDocument
+func (d *PolicyDocument) Scan(value interface{}) error {
+	return scanJSON(value, d, "PolicyDocument")
+}
+
+// Value implements the driver.Valuer interface for PolicyDocument
+func (d PolicyDocument) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements the sql.Scanner interface for PolicyImportResponse
+func (r *PolicyImportResponse) Scan(value interface{}) error {
+	return scanJSON(value, r, "PolicyImportResponse")
+}
+
+// Value implements the driver.Valuer interface for PolicyImportResponse
+func (r PolicyImportResponse) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for PolicyMetadataCreateRequest
+func (r *PolicyMetadataCreateRequest) Scan(value interface{}) error {
+	return scanJSON(value, r, "PolicyMetadataCreateRequest")
+}
+
+// Value implements the driver.Valuer interface for PolicyMetadataCreateRequest
+func (r PolicyMetadataCreateRequest) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for AllowListUpdateRequest
+func (r *AllowListUpdateRequest) Scan(value interface{}) error {
+	return scanJSON(value, r, "AllowListUpdateRequest")
+}
+
+// Value implements the driver.Valuer interface for AllowListUpdateRequest
+func (r AllowListUpdateRequest) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for SchemaLifecycleUpdateRequest
+func (r *SchemaLifecycleUpdateRequest) Scan(value interface{}) error {
+	return scanJSON(value, r, "SchemaLifecycleUpdateRequest")
+}
+
+// Value implements the driver.Valuer interface for SchemaLifecycleUpdateRequest
+func (r SchemaLifecycleUpdateRequest) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// scanJSON decodes a JSONB column into dest, leaving it unchanged when the column is empty
+func scanJSON(value interface{}, dest interface{}, typeName string) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into %s", value, typeName)
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, dest)
+}
+
+// PolicyChangeSet represents the policy_change_sets table. A change set proposes a policy write; it is
+// applied only when another admin approves it, and changes to the policy document are recorded as a
+// new policy version.
+type PolicyChangeSet struct {
+	ID          uuid.UUID           `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string              `gorm:"column:tenant_id;type:varchar(255);not null;default:'default';index" json:"tenantId"`
+	Title       string              `gorm:"column:title;type:text;not null" json:"title"`
+	Description *string             `gorm:"column:description;type:text" json:"description,omitempty"`
+	Kind        PolicyChangeSetKind `gorm:"column:kind;type:varchar(20);not null;default:'document'" json:"kind"`
+	// Document is the proposed document of document change sets, and empty for other kinds
+	Document PolicyDocument `gorm:"column:document;type:jsonb;not null" json:"document"`
+	// Metadata, AllowListUpdate and SchemaLifecycleUpdate are the requests proposed by change sets of those kinds
+	Metadata              *PolicyMetadataCreateRequest  `gorm:"column:metadata;type:jsonb" json:"metadata,omitempty"`
+	AllowListUpdate       *AllowListUpdateRequest       `gorm:"column:allow_list_update;type:jsonb" json:"allowListUpdate,omitempty"`
+	SchemaLifecycleUpdate *SchemaLifecycleUpdateRequest `gorm:"column:schema_lifecycle_update;type:jsonb" json:"schemaLifecycleUpdate,omitempty"`
+	Status                PolicyChangeSetStatus         `gorm:"column:status;type:varchar(20);not null;default:'pending';index" json:"status"`
+	ProposedBy            string                        `gorm:"column:proposed_by;type:varchar(255);not null" json:"proposedBy"`
+	ReviewedBy            *string                       `gorm:"column:reviewed_by;type:varchar(255)" json:"reviewedBy,omitempty"`
+	// ReviewComment is the reviewer's comment on the decision
+	ReviewComment *string `gorm:"column:review_comment;type:text" json:"reviewComment,omitempty"`
+	// Diff and Impact are recorded as the reviewer saw them when the change set was decided
+	Diff   *PolicyImportResponse `gorm:"column:diff;type:jsonb" json:"diff,omitempty"`
+	Impact PolicyChangeImpacts   `gorm:"column:impact;type:jsonb;not null;default:'[]'" json:"impact"`
+	// Version is the policy version created by approving the change set
+	Version   *int      `gorm:"column:version" json:"version,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+// TableName specifies the table name for GORM
+func (PolicyChangeSet) TableName() string {
+	return "policy_change_sets"
+}
+
+// ToResponse converts PolicyChangeSet to PolicyChangeSetResponse
+func (cs *PolicyChangeSet) ToResponse() PolicyChangeSetResponse {
+	impact := cs.Impact
+	if impact == nil {
+		impact = PolicyChangeImpacts{}
+	}
+	kind := cs.Kind
+	if kind == "" {
+		kind = PolicyChangeSetKindDocument
+	}
+	response := PolicyChangeSetResponse{
+		ID:                    cs.ID.String(),
+		Title:                 cs.Title,
+		Description:           cs.Description,
+		Kind:                  kind,
+		Status:                cs.Status,
+		ProposedBy:            cs.ProposedBy,
+		ReviewedBy:            cs.ReviewedBy,
+		ReviewComment:         cs.ReviewComment,
+		Metadata:              cs.Metadata,
+		AllowListUpdate:       cs.AllowListUpdate,
+		SchemaLifecycleUpdate: cs.SchemaLifecycleUpdate,
+		Diff:                  cs.Diff,
+		Impact:                impact,
+		Version:               cs.Version,
+		CreatedAt:             cs.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:             cs.UpdatedAt.Format(time.RFC3339),
+	}
+	if kind == PolicyChangeSetKindDocument {
+		document := cs.Document
+		response.Document = &document
+	}
+	return response
+}
+
+// PolicyVersion represents the policy_versions table: the tenant's policy as it was after an approved
+// change set was applied. Versions are numbered per tenant from 1.
+type PolicyVersion struct {
+	ID          uuid.UUID      `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string         `gorm:"column:tenant_id;type:varchar(255);not null;default:'default';uniqueIndex:idx_policy_versions_tenant_version" json:"tenantId"`
+	Version     int            `gorm:"column:version;not null;uniqueIndex:idx_policy_versions_tenant_version" json:"version"`
+	ChangeSetID uuid.UUID      `gorm:"column:change_set_id;type:uuid;not null" json:"changeSetId"`
+	Document    PolicyDocument `gorm:"column:document;type:jsonb;not null" json:"document"`
+	ProposedBy  string         `gorm:"column:proposed_by;type:varchar(255);not null" json:"proposedBy"`
+	ApprovedBy  string         `gorm:"column:approved_by;type:varchar(255);not null" json:"approvedBy"`
+	CreatedAt   time.Time      `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+}
+
+// TableName specifies the table name for GORM
+func (PolicyVersion) TableName() string {
+	return "policy_versions"
+}
+
+// PolicyChangeSetCreateRequest proposes a change to the policy. The document has the import format and is
+// authoritative for the schemas it lists.
+type PolicyChangeSetCreateRequest struct {
+	Title       string         `json:"title" yaml:"title" validate:"required"`
+	Description *string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Document    PolicyDocument `json:"document" yaml:"document" validate:"required"`
+	// TenantID and ProposedBy are taken from the caller's access token
+	TenantID   string `json:"-" yaml:"-"`
+	ProposedBy string `json:"-" yaml:"-"`
+}
+
+// PolicyChangeSetReviewRequest approves or rejects a pending change set
+type PolicyChangeSetReviewRequest struct {
+	Status        PolicyChangeSetStatus `json:"status" validate:"required"`
+	ReviewComment *string               `json:"reviewComment,omitempty"`
+	// TenantID and ReviewedBy are taken from the reviewer's access token
+	TenantID   string `json:"-"`
+	ReviewedBy string `json:"-"`
+}
+
+// PolicyChangeSetResponse represents a change set with the write it proposes. For pending change sets
+// the diff and impact are computed against the current policy; change sets that do not change the
+// policy document have no diff.
+type PolicyChangeSetResponse struct {
+	ID                    string                        `json:"id"`
+	Title                 string                        `json:"title"`
+	Description           *string                       `json:"description,omitempty"`
+	Kind                  PolicyChangeSetKind           `json:"kind"`
+	Status                PolicyChangeSetStatus         `json:"status"`
+	ProposedBy            string                        `json:"proposedBy"`
+	ReviewedBy            *string                       `json:"reviewedBy,omitempty"`
+	ReviewComment         *string                       `json:"reviewComment,omitempty"`
+	Document              *PolicyDocument               `json:"document,omitempty"`
+	Metadata              *PolicyMetadataCreateRequest  `json:"metadata,omitempty"`
+	AllowListUpdate       *AllowListUpdateRequest       `json:"allowListUpdate,omitempty"`
+	SchemaLifecycleUpdate *SchemaLifecycleUpdateRequest `json:"schemaLifecycleUpdate,omitempty"`
+	Diff                  *PolicyImportResponse         `json:"diff,omitempty"`
+	Impact                PolicyChangeImpacts           `json:"impact"`
+	Version               *int                          `json:"version,omitempty"`
+	CreatedAt             string                        `json:"createdAt"`
+	UpdatedAt             string                        `json:"updatedAt"`
+}
+
+// PolicyChangeSetListResponse represents a list of change sets
+type PolicyChangeSetListResponse struct {
+	Records []PolicyChangeSetResponse `json:"records"`
+	Count   int                       `json:"count"`
+}
+
+// PolicyVersionSummary represents a policy version without its document
+type PolicyVersionSummary struct {
+	Version     int    `json:"version"`
+	ChangeSetID string `json:"changeSetId"`
+	ProposedBy  string `json:"proposedBy"`
+	ApprovedBy  string `json:"approvedBy"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+// ToSummary converts PolicyVersion to PolicyVersionSummary
+func (v *PolicyVersion) ToSummary() PolicyVersionSummary {
+	return PolicyVersionSummary{
+		Version:     v.Version,
+		ChangeSetID: v.ChangeSetID.String(),
+		ProposedBy:  v.ProposedBy,
+		ApprovedBy:  v.ApprovedBy,
+		CreatedAt:   v.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// PolicyVersionListResponse represents the policy versions of a tenant, newest first
+type PolicyVersionListResponse struct {
+	Records []PolicyVersionSummary `json:"records"`
+}
@@ -0,0 +1,271 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConditionOperator represents the comparison applied by a policy condition
+type ConditionOperator string
+
+const (
+	ConditionOperatorEquals             ConditionOperator = "eq"
+	ConditionOperatorNotEquals          ConditionOperator = "neq"
+	ConditionOperatorIn                 ConditionOperator = "in"
+	ConditionOperatorNotIn              ConditionOperator = "not_in"
+	ConditionOperatorGreaterThan        ConditionOperator = "gt"
+	ConditionOperatorGreaterThanOrEqual ConditionOperator = "gte"
+	ConditionOperatorLessThan           ConditionOperator = "lt"
+	ConditionOperatorLessThanOrEqual    ConditionOperator = "lte"
+	// ConditionOperatorTimeBetween checks that a timestamp attribute falls within a daily
+	// "HH:MM-HH:MM" window, evaluated in the timestamp's own offset. Windows may wrap midnight.
+	ConditionOperatorTimeBetween ConditionOperator = "time_between"
+)
+
+// RequestTimeAttribute is the context attribute holding the request time (RFC3339).
+// When a time_between condition references it and it is absent, the evaluation time is used.
+const RequestTimeAttribute = "request.time"
+
+// PolicyCondition is a single attribute condition that must hold for a field to be released,
+// e.g. {"attribute": "consumer.sector", "operator": "eq", "value": "banking"}
+type PolicyCondition struct {
+	Attribute string            `json:"attribute" yaml:"attribute"`
+	Operator  ConditionOperator `json:"operator" yaml:"operator"`
+	Value     interface{}       `json:"value" yaml:"value"`
+}
+
+// Validate checks that the condition is well formed
+func (c PolicyCondition) Validate() error {
+	if c.Attribute == "" {
+		return fmt.Errorf("condition attribute is required")
+	}
+
+	switch c.Operator {
+	case ConditionOperatorEquals, ConditionOperatorNotEquals:
+		if c.Value == nil {
+			return fmt.Errorf("condition on %s requires a value", c.Attribute)
+		}
+	case ConditionOperatorIn, ConditionOperatorNotIn:
+		if _, ok := c.Value.([]interface{}); !ok {
+			return fmt.Errorf("condition on %s requires a list value for operator %s", c.Attribute, c.Operator)
+		}
+	case ConditionOperatorGreaterThan, ConditionOperatorGreaterThanOrEqual,
+		ConditionOperatorLessThan, ConditionOperatorLessThanOrEqual:
+		if _, ok := toFloat(c.Value); !ok {
+			return fmt.Errorf("condition on %s requires a numeric value for operator %s", c.Attribute, c.Operator)
+		}
+	case ConditionOperatorTimeBetween:
+		window, ok := c.Value.(string)
+		if !ok {
+			return fmt.Errorf("condition on %s requires a \"HH:MM-HH:MM\" value", c.Attribute)
+		}
+		if _, _, err := parseTimeWindow(window); err != nil {
+			return fmt.Errorf("condition on %s: %w", c.Attribute, err)
+		}
+	default:
+		return fmt.Errorf("unsupported condition operator: %s", c.Operator)
+	}
+	return nil
+}
+
+// Evaluate reports whether the condition holds for the given request attributes.
+// A condition referencing an attribute that is not present does not hold.
+func (c PolicyCondition) Evaluate(attributes map[string]interface{}, now time.Time) bool {
+	actual, exists := attributes[c.Attribute]
+
+	if c.Operator == ConditionOperatorTimeBetween {
+		at := now
+		if exists {
+			str, ok := actual.(string)
+			if !ok {
+				return false
+			}
+			parsed, err := time.Parse(time.RFC3339, str)
+			if err != nil {
+				return false
+			}
+			at = parsed
+		} else if c.Attribute != RequestTimeAttribute {
+			return false
+		}
+		window, _ := c.Value.(string)
+		start, end, err := parseTimeWindow(window)
+		if err != nil {
+			return false
+		}
+		minute := at.Hour()*60 + at.Minute()
+		if start <= end {
+			return minute >= start && minute < end
+		}
+		return minute >= start || minute < end
+	}
+
+	if !exists || actual == nil {
+		return false
+	}
+
+	switch c.Operator {
+	case ConditionOperatorEquals:
+		return valuesEqual(actual, c.Value)
+	case ConditionOperatorNotEquals:
+		return !valuesEqual(actual, c.Value)
+	case ConditionOperatorIn, ConditionOperatorNotIn:
+		values, _ := c.Value.([]interface{})
+		found := false
+		for _, v := range values {
+			if valuesEqual(actual, v) {
+				found = true
+				break
+			}
+		}
+		return found == (c.Operator == ConditionOperatorIn)
+	case ConditionOperatorGreaterThan, ConditionOperatorGreaterThanOrEqual,
+		ConditionOperatorLessThan, ConditionOperatorLessThanOrEqual:
+		left, ok := toFloat(actual)
+		if !ok {
+			return false
+		}
+		right, ok := toFloat(c.Value)
+		if !ok {
+			return false
+		}
+		switch c.Operator {
+		case ConditionOperatorGreaterThan:
+			return left > right
+		case ConditionOperatorGreaterThanOrEqual:
+			return left >= right
+		case ConditionOperatorLessThan:
+			return left < right
+		default:
+			return left <= right
+		}
+	default:
+		return false
+	}
+}
+
+// PolicyConditions represents the JSONB list of conditions on a policy record.
+// All conditions must hold for the field to be released.
+type PolicyConditions []PolicyCondition
+
+// Validate checks that every condition is well formed
+func (pc PolicyConditions) Validate() error {
+	for _, c := range pc {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the first condition that does not hold, or nil when all conditions hold
+func (pc PolicyConditions) Evaluate(attributes map[string]interface{}, now time.Time) *PolicyCondition {
+	for i := range pc {
+		if !pc[i].Evaluate(attributes, now) {
+			return &pc[i]
+		}
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface for PolicyConditions
+func (pc *PolicyConditions) Scan(value interface{}) error {
+	if value == nil {
+		*pc = PolicyConditions{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into PolicyConditions", value)
+	}
+
+	if len(bytes) == 0 {
+		*pc = PolicyConditions{}
+		return nil
+	}
+
+	return json.Unmarshal(bytes, pc)
+}
+
+// Value implements the driver.Valuer interface for PolicyConditions
+func (pc PolicyConditions) Value() (driver.Value, error) {
+	if pc == nil {
+		return json.Marshal([]PolicyCondition{})
+	}
+	return json.Marshal(pc)
+}
+
+// valuesEqual compares two attribute values, numerically when both are numbers
+func valuesEqual(a, b interface{}) bool {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return af == bf
+		}
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// toFloat converts JSON and Go numeric values to float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// parseTimeWindow parses "HH:MM-HH:MM" into start and end minutes of the day
+func parseTimeWindow(window string) (int, int, error) {
+	bounds := strings.Split(window, "-")
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("invalid time window %q: expected HH:MM-HH:MM", window)
+	}
+	start, err := parseClock(bounds[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q: %w", window, err)
+	}
+	end, err := parseClock(bounds[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time window %q: %w", window, err)
+	}
+	return start, end, nil
+}
+
+// parseClock parses "HH:MM" into minutes of the day
+func parseClock(clock string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(clock), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid clock time %q", clock)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, fmt.Errorf("invalid clock time %q", clock)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid clock time %q", clock)
+	}
+	return hours*60 + minutes, nil
+}
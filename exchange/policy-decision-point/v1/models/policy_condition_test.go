@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyCondition_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition PolicyCondition
+		wantErr   bool
+	}{
+		{"valid eq", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorEquals, Value: "banking"}, false},
+		{"valid in", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorIn, Value: []interface{}{"banking", "insurance"}}, false},
+		{"valid gte", PolicyCondition{Attribute: "citizen.age", Operator: ConditionOperatorGreaterThanOrEqual, Value: float64(18)}, false},
+		{"valid time window", PolicyCondition{Attribute: RequestTimeAttribute, Operator: ConditionOperatorTimeBetween, Value: "08:00-17:30"}, false},
+		{"missing attribute", PolicyCondition{Operator: ConditionOperatorEquals, Value: "banking"}, true},
+		{"unsupported operator", PolicyCondition{Attribute: "consumer.sector", Operator: "like", Value: "bank%"}, true},
+		{"eq without value", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorEquals}, true},
+		{"in with scalar", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorIn, Value: "banking"}, true},
+		{"gte with string", PolicyCondition{Attribute: "citizen.age", Operator: ConditionOperatorGreaterThanOrEqual, Value: "18"}, true},
+		{"malformed time window", PolicyCondition{Attribute: RequestTimeAttribute, Operator: ConditionOperatorTimeBetween, Value: "8am-5pm"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.condition.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicyCondition_Evaluate(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	attributes := map[string]interface{}{
+		"consumer.sector": "banking",
+		"citizen.age":     float64(21),
+	}
+
+	tests := []struct {
+		name       string
+		condition  PolicyCondition
+		attributes map[string]interface{}
+		want       bool
+	}{
+		{"eq match", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorEquals, Value: "banking"}, attributes, true},
+		{"eq mismatch", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorEquals, Value: "health"}, attributes, false},
+		{"neq", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorNotEquals, Value: "health"}, attributes, true},
+		{"in", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorIn, Value: []interface{}{"insurance", "banking"}}, attributes, true},
+		{"not_in", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorNotIn, Value: []interface{}{"banking"}}, attributes, false},
+		{"gte adult", PolicyCondition{Attribute: "citizen.age", Operator: ConditionOperatorGreaterThanOrEqual, Value: float64(18)}, attributes, true},
+		{"lt", PolicyCondition{Attribute: "citizen.age", Operator: ConditionOperatorLessThan, Value: float64(18)}, attributes, false},
+		{"numeric eq across types", PolicyCondition{Attribute: "citizen.age", Operator: ConditionOperatorEquals, Value: 21}, attributes, true},
+		{"missing attribute fails closed", PolicyCondition{Attribute: "citizen.region", Operator: ConditionOperatorNotEquals, Value: "north"}, attributes, false},
+		{"non-numeric comparison", PolicyCondition{Attribute: "consumer.sector", Operator: ConditionOperatorGreaterThan, Value: float64(1)}, attributes, false},
+		{"time window uses evaluation time", PolicyCondition{Attribute: RequestTimeAttribute, Operator: ConditionOperatorTimeBetween, Value: "09:00-17:00"}, attributes, true},
+		{"time window from context", PolicyCondition{Attribute: RequestTimeAttribute, Operator: ConditionOperatorTimeBetween, Value: "09:00-17:00"}, map[string]interface{}{RequestTimeAttribute: "2025-01-15T20:00:00+05:30"}, false},
+		{"time window wrapping midnight", PolicyCondition{Attribute: RequestTimeAttribute, Operator: ConditionOperatorTimeBetween, Value: "22:00-06:00"}, map[string]interface{}{RequestTimeAttribute: "2025-01-15T23:15:00Z"}, true},
+		{"invalid time in context", PolicyCondition{Attribute: RequestTimeAttribute, Operator: ConditionOperatorTimeBetween, Value: "09:00-17:00"}, map[string]interface{}{RequestTimeAttribute: "noon"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.condition.Evaluate(tt.attributes, now))
+		})
+	}
+}
+
+func TestPolicyConditions_Evaluate(t *testing.T) {
+	conditions := PolicyConditions{
+		{Attribute: "consumer.sector", Operator: ConditionOperatorEquals, Value: "banking"},
+		{Attribute: "citizen.age", Operator: ConditionOperatorGreaterThanOrEqual, Value: float64(18)},
+	}
+
+	failed := conditions.Evaluate(map[string]interface{}{"consumer.sector": "banking", "citizen.age": float64(30)}, time.Now())
+	assert.Nil(t, failed)
+
+	failed = conditions.Evaluate(map[string]interface{}{"consumer.sector": "banking", "citizen.age": float64(16)}, time.Now())
+	if assert.NotNil(t, failed) {
+		assert.Equal(t, "citizen.age", failed.Attribute)
+	}
+
+	assert.Nil(t, PolicyConditions(nil).Evaluate(nil, time.Now()), "no conditions always hold")
+}
+
+func TestPolicyConditions_ScanValue(t *testing.T) {
+	conditions := PolicyConditions{{Attribute: "consumer.sector", Operator: ConditionOperatorIn, Value: []interface{}{"banking"}}}
+	value, err := conditions.Value()
+	assert.NoError(t, err)
+
+	var scanned PolicyConditions
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, conditions, scanned)
+
+	value, err = PolicyConditions(nil).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("[]"), value)
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Empty(t, scanned)
+	assert.Error(t, scanned.Scan(123))
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DecisionOutcome represents the overall outcome of a policy decision
+type DecisionOutcome string
+
+const (
+	DecisionOutcomeAllowed         DecisionOutcome = "allowed"
+	DecisionOutcomeConsentRequired DecisionOutcome = "consent_required"
+	DecisionOutcomeExpired         DecisionOutcome = "expired"
+	DecisionOutcomeDenied          DecisionOutcome = "denied"
+	DecisionOutcomeBlocked         DecisionOutcome = "blocked"
+	DecisionOutcomeError           DecisionOutcome = "error"
+)
+
+// FieldDecisionResult represents the result of a policy decision for a single field
+type FieldDecisionResult string
+
+const (
+	FieldDecisionResultAuthorized      FieldDecisionResult = "authorized"
+	FieldDecisionResultConsentRequired FieldDecisionResult = "consent_required"
+	FieldDecisionResultExpired         FieldDecisionResult = "expired"
+	FieldDecisionResultUnauthorized    FieldDecisionResult = "unauthorized"
+	FieldDecisionResultConditionFailed FieldDecisionResult = "condition_failed"
+	FieldDecisionResultBlocked         FieldDecisionResult = "blocked"
+)
+
+// DecisionContext represents the JSONB request context attributes recorded with a decision
+type DecisionContext map[string]interface{}
+
+// Scan implements the sql.Scanner interface for DecisionContext
+func (dc *DecisionContext) Scan(value interface{}) error {
+	if value == nil {
+		*dc = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into DecisionContext", value)
+	}
+
+	if len(bytes) == 0 {
+		*dc = nil
+		return nil
+	}
+
+	return json.Unmarshal(bytes, dc)
+}
+
+// Value implements the driver.Valuer interface for DecisionContext
+func (dc DecisionContext) Value() (driver.Value, error) {
+	if dc == nil {
+		return nil, nil
+	}
+	return json.Marshal(dc)
+}
+
+// PolicyDecisionLog represents the policy_decisions table
+type PolicyDecisionLog struct {
+	ID            uuid.UUID                `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ApplicationID string                   `gorm:"column:application_id;type:varchar(255);not null;index:idx_policy_decisions_app_created" json:"applicationId"`
+	Outcome       DecisionOutcome          `gorm:"column:outcome;type:varchar(32);not null;index" json:"outcome"`
+	LatencyMs     float64                  `gorm:"column:latency_ms;type:double precision;not null" json:"latencyMs"`
+	PolicyVersion *string                  `gorm:"column:policy_version;type:varchar(64)" json:"policyVersion,omitempty"`
+	Error         *string                  `gorm:"column:error;type:text" json:"error,omitempty"`
+	Context       DecisionContext          `gorm:"column:context;type:jsonb" json:"context,omitempty"`
+	Fields        []PolicyDecisionLogField `gorm:"foreignKey:DecisionID;constraint:OnDelete:CASCADE" json:"fields"`
+	CreatedAt     time.Time                `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;not null;index:idx_policy_decisions_app_created" json:"createdAt"`
+}
+
+// TableName specifies the table name for GORM
+func (PolicyDecisionLog) TableName() string {
+	return "policy_decisions"
+}
+
+// PolicyDecisionLogField represents the policy_decision_fields table
+type PolicyDecisionLogField struct {
+	ID         uuid.UUID           `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"-"`
+	DecisionID uuid.UUID           `gorm:"column:decision_id;type:uuid;not null;index" json:"-"`
+	SchemaID   string              `gorm:"column:schema_id;type:varchar(255);not null;index:idx_policy_decision_fields_schema_field" json:"schemaId"`
+	FieldName  string              `gorm:"column:field_name;type:text;not null;index:idx_policy_decision_fields_schema_field" json:"fieldName"`
+	Result     FieldDecisionResult `gorm:"column:result;type:varchar(32)" json:"result,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (PolicyDecisionLogField) TableName() string {
+	return "policy_decision_fields"
+}
+
+// ToResponse converts PolicyDecisionLog to PolicyDecisionLogResponse
+func (d *PolicyDecisionLog) ToResponse() PolicyDecisionLogResponse {
+	fields := make([]PolicyDecisionLogFieldResponse, 0, len(d.Fields))
+	for _, f := range d.Fields {
+		fields = append(fields, PolicyDecisionLogFieldResponse{
+			SchemaID:  f.SchemaID,
+			FieldName: f.FieldName,
+			Result:    f.Result,
+		})
+	}
+	return PolicyDecisionLogResponse{
+		ID:            d.ID.String(),
+		ApplicationID: d.ApplicationID,
+		Outcome:       d.Outcome,
+		LatencyMs:     d.LatencyMs,
+		PolicyVersion: d.PolicyVersion,
+		Error:         d.Error,
+		Context:       d.Context,
+		Fields:        fields,
+		CreatedAt:     d.CreatedAt.Format(time.RFC3339Nano),
+	}
+}
@@ -0,0 +1,61 @@
+package models
+
+// PolicyDocumentVersion is the version of the declarative policy document format
+const PolicyDocumentVersion = "v1"
+
+// PolicyDocument is the declarative, environment-independent form of the policy set.
+// Allow lists are runtime grants and are not part of the document.
+type PolicyDocument struct {
+	Version string `json:"version" yaml:"version"`
+	// Tenant is the tenant the document was exported from; imports always apply to the caller's tenant
+	Tenant  string                 `json:"tenant,omitempty" yaml:"tenant,omitempty"`
+	Schemas []PolicyDocumentSchema `json:"schemas" yaml:"schemas"`
+}
+
+// PolicyDocumentSchema holds the field policies of one schema
+type PolicyDocumentSchema struct {
+	SchemaID   string                `json:"schemaId" yaml:"schemaId"`
+	ProviderID string                `json:"providerId,omitempty" yaml:"providerId,omitempty"`
+	Fields     []PolicyDocumentField `json:"fields" yaml:"fields"`
+}
+
+// PolicyDocumentField holds the policy of one field
+type PolicyDocumentField struct {
+	FieldName         string            `json:"fieldName" yaml:"fieldName"`
+	DisplayName       *string           `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description       *string           `json:"description,omitempty" yaml:"description,omitempty"`
+	Source            Source            `json:"source" yaml:"source"`
+	IsOwner           bool              `json:"isOwner" yaml:"isOwner"`
+	Owner             *Owner            `json:"owner,omitempty" yaml:"owner,omitempty"`
+	AccessControlType AccessControlType `json:"accessControlType,omitempty" yaml:"accessControlType,omitempty"`
+	Classification    Classification    `json:"classification,omitempty" yaml:"classification,omitempty"`
+	Conditions        PolicyConditions  `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+// PolicyImportAction represents what an import does to a field
+type PolicyImportAction string
+
+const (
+	PolicyImportActionCreate PolicyImportAction = "create"
+	PolicyImportActionUpdate PolicyImportAction = "update"
+	PolicyImportActionDelete PolicyImportAction = "delete"
+)
+
+// PolicyImportChange represents a field that an import creates, updates or deletes
+type PolicyImportChange struct {
+	SchemaID  string             `json:"schemaId"`
+	FieldName string             `json:"fieldName"`
+	Action    PolicyImportAction `json:"action"`
+	// ChangedAttributes lists the attributes that differ, for updates
+	ChangedAttributes []string `json:"changedAttributes,omitempty"`
+}
+
+// PolicyImportResponse represents the diff of an import and whether it was applied
+type PolicyImportResponse struct {
+	Applied   bool                 `json:"applied"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Deleted   int                  `json:"deleted"`
+	Unchanged int                  `json:"unchanged"`
+	Changes   []PolicyImportChange `json:"changes"`
+}
@@ -11,6 +11,8 @@ import (
 // PolicyMetadata represents the policy_metadata table
 type PolicyMetadata struct {
 	ID                uuid.UUID         `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID          string            `gorm:"column:tenant_id;type:varchar(255);not null;default:'default';index" json:"tenantId"`
+	ProviderID        string            `gorm:"column:provider_id;type:varchar(255);not null;default:''" json:"providerId"`
 	SchemaID          string            `gorm:"column:schema_id;type:varchar(255);not null;uniqueIndex:idx_policy_metadata_schema_field;" json:"schemaId"`
 	FieldName         string            `gorm:"column:field_name;type:text;not null;uniqueIndex:idx_policy_metadata_schema_field" json:"fieldName"`
 	DisplayName       *string           `gorm:"column:display_name;type:text" json:"displayName,omitempty"`
@@ -18,10 +20,16 @@ type PolicyMetadata struct {
 	Source            Source            `gorm:"column:source;type:source_enum;not null;default:'fallback'" json:"source"`
 	IsOwner           bool              `gorm:"column:is_owner;type:boolean;default:false;not null" json:"isOwner"`
 	AccessControlType AccessControlType `gorm:"column:access_control_type;type:access_control_type_enum;not null;default:'restricted'" json:"accessControlType"`
+	Classification    Classification    `gorm:"column:classification;type:varchar(32);not null;default:'personal'" json:"classification"`
 	AllowList         AllowList         `gorm:"column:allow_list;type:jsonb;not null;default:'{}'" json:"allowList"`
 	Owner             *Owner            `gorm:"column:owner;type:owner_enum;" json:"owner"`
-	CreatedAt         time.Time         `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
-	UpdatedAt         time.Time         `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	Conditions        PolicyConditions  `gorm:"column:conditions;type:jsonb;not null;default:'[]'" json:"conditions"`
+	// LifecycleStatus, SunsetAt and LifecycleMessage are set for all fields of a schema at once
+	LifecycleStatus  SchemaLifecycleStatus `gorm:"column:lifecycle_status;type:varchar(16);not null;default:'active'" json:"lifecycleStatus"`
+	SunsetAt         *time.Time            `gorm:"column:sunset_at;type:timestamp" json:"sunsetAt,omitempty"`
+	LifecycleMessage *string               `gorm:"column:lifecycle_message;type:text" json:"lifecycleMessage,omitempty"`
+	CreatedAt        time.Time             `gorm:"column:created_at;type:timestamp;default:CURRENT_TIMESTAMP;not null" json:"createdAt"`
+	UpdatedAt        time.Time             `gorm:"column:updated_at;type:timestamp;default:CURRENT_TIMESTAMP" json:"updatedAt"`
 }
 
 // TableName specifies the table name for GORM
@@ -51,6 +59,9 @@ func (pm *PolicyMetadata) validateOwnerConstraint() error {
 func (pm *PolicyMetadata) ToResponse() PolicyMetadataResponse {
 	return PolicyMetadataResponse{
 		ID:                pm.ID.String(),
+		TenantID:          pm.TenantID,
+		ProviderID:        pm.ProviderID,
+		Namespace:         pm.Namespace(),
 		SchemaID:          pm.SchemaID,
 		FieldName:         pm.FieldName,
 		DisplayName:       pm.DisplayName,
@@ -58,8 +69,13 @@ func (pm *PolicyMetadata) ToResponse() PolicyMetadataResponse {
 		Source:            pm.Source,
 		IsOwner:           pm.IsOwner,
 		AccessControlType: pm.AccessControlType,
+		Classification:    pm.Classification,
 		AllowList:         pm.AllowList,
 		Owner:             pm.Owner,
+		Conditions:        pm.Conditions,
+		LifecycleStatus:   pm.LifecycleStatus,
+		SunsetAt:          pm.SunsetAt,
+		LifecycleMessage:  pm.LifecycleMessage,
 		CreatedAt:         pm.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:         pm.UpdatedAt.Format(time.RFC3339),
 	}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SchemaLifecycleStatus is the lifecycle stage of the schema a field belongs to. Providers deprecate
+// a schema with a sunset date before retiring it, so consumers can move off its fields in time.
+type SchemaLifecycleStatus string
+
+const (
+	// SchemaLifecycleActive fields are served normally
+	SchemaLifecycleActive SchemaLifecycleStatus = "active"
+	// SchemaLifecycleDeprecated fields are still served, with a deprecation warning
+	SchemaLifecycleDeprecated SchemaLifecycleStatus = "deprecated"
+	// SchemaLifecycleSunset fields have passed their sunset date and are served with a warning until retired
+	SchemaLifecycleSunset SchemaLifecycleStatus = "sunset"
+	// SchemaLifecycleRetired fields are no longer served to any application
+	SchemaLifecycleRetired SchemaLifecycleStatus = "retired"
+)
+
+// Validate checks the lifecycle status is known
+func (s SchemaLifecycleStatus) Validate() error {
+	switch s {
+	case SchemaLifecycleActive, SchemaLifecycleDeprecated, SchemaLifecycleSunset, SchemaLifecycleRetired:
+		return nil
+	}
+	return fmt.Errorf("invalid lifecycle status %q", s)
+}
+
+// IsDeprecated reports whether fields in this stage are served with a deprecation warning
+func (s SchemaLifecycleStatus) IsDeprecated() bool {
+	return s == SchemaLifecycleDeprecated || s == SchemaLifecycleSunset
+}
+
+// SchemaLifecycleUpdateRequest sets the lifecycle stage of every field of a schema
+type SchemaLifecycleUpdateRequest struct {
+	SchemaID string                `json:"schemaId" validate:"required"`
+	Status   SchemaLifecycleStatus `json:"status" validate:"required"`
+	// SunsetAt is when a deprecated schema stops being supported
+	SunsetAt *time.Time `json:"sunsetAt,omitempty"`
+	// Message tells consumers what to use instead
+	Message  *string `json:"message,omitempty"`
+	TenantID string  `json:"-"`
+}
+
+// SchemaLifecycleUpdateResponse reports the lifecycle stage a schema was set to
+type SchemaLifecycleUpdateResponse struct {
+	SchemaID      string                `json:"schemaId"`
+	Status        SchemaLifecycleStatus `json:"status"`
+	SunsetAt      *time.Time            `json:"sunsetAt,omitempty"`
+	Message       *string               `json:"message,omitempty"`
+	FieldsUpdated int                   `json:"fieldsUpdated"`
+}
+
+// PolicyDecisionDeprecatedFieldRecord is a requested field whose schema is deprecated or sunset.
+// The field is still decided on as usual; the record lets the caller warn the consumer.
+type PolicyDecisionDeprecatedFieldRecord struct {
+	FieldName string                `json:"fieldName"`
+	SchemaID  string                `json:"schemaId"`
+	Status    SchemaLifecycleStatus `json:"status"`
+	SunsetAt  *time.Time            `json:"sunsetAt,omitempty"`
+	Message   *string               `json:"message,omitempty"`
+}
@@ -15,6 +15,27 @@ const (
 	GrantDurationTypeOneYear  GrantDurationType = "365d"
 )
 
+// ExpiresAtFrom calculates the expiry time of a grant of this duration starting at the given time
+func (g GrantDurationType) ExpiresAtFrom(start time.Time) (time.Time, error) {
+	switch g {
+	case GrantDurationTypeOneMonth:
+		return start.AddDate(0, 1, 0), nil
+	case GrantDurationTypeOneYear:
+		return start.AddDate(1, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid grant duration: %s", g)
+	}
+}
+
+// RenewalRequestStatus represents the status of an allow list renewal request
+type RenewalRequestStatus string
+
+const (
+	RenewalRequestStatusPending  RenewalRequestStatus = "pending"
+	RenewalRequestStatusApproved RenewalRequestStatus = "approved"
+	RenewalRequestStatusRejected RenewalRequestStatus = "rejected"
+)
+
 // AccessControlType represents the access control type enum
 type AccessControlType string
 
@@ -96,6 +117,13 @@ func (o Owner) Value() (driver.Value, error) {
 type AllowListEntry struct {
 	ExpiresAt time.Time `json:"expires_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// ConsumerID is the consumer (member) that owns the application, when known
+	ConsumerID string `json:"consumer_id,omitempty"`
+}
+
+// IsExpired reports whether the grant has expired as of the given time
+func (e AllowListEntry) IsExpired(now time.Time) bool {
+	return now.After(e.ExpiresAt)
 }
 
 // AllowList represents the JSONB allow list as a HashMap with custom scanning
@@ -282,3 +282,27 @@ func TestAllowList_Value(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowListEntry_IsExpired(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, AllowListEntry{ExpiresAt: now.Add(-time.Second)}.IsExpired(now))
+	assert.False(t, AllowListEntry{ExpiresAt: now.Add(time.Second)}.IsExpired(now))
+	assert.False(t, AllowListEntry{ExpiresAt: now}.IsExpired(now))
+}
+
+func TestGrantDurationType_ExpiresAtFrom(t *testing.T) {
+	start := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	expiresAt, err := GrantDurationTypeOneMonth.ExpiresAtFrom(start)
+	assert.NoError(t, err)
+	assert.Equal(t, start.AddDate(0, 1, 0), expiresAt)
+
+	expiresAt, err = GrantDurationTypeOneYear.ExpiresAtFrom(start)
+	assert.NoError(t, err)
+	assert.Equal(t, start.AddDate(1, 0, 0), expiresAt)
+
+	_, err = GrantDurationType("7d").ExpiresAtFrom(start)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid grant duration")
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// AllowListCleanupWorker periodically prunes expired allow list entries
+type AllowListCleanupWorker struct {
+	policyService *PolicyMetadataService
+	// interval is how often the cleanup runs
+	interval time.Duration
+	// retention is how long an expired entry is kept before it is pruned
+	retention time.Duration
+}
+
+// NewAllowListCleanupWorker creates a new allow list cleanup worker
+func NewAllowListCleanupWorker(policyService *PolicyMetadataService, interval, retention time.Duration) *AllowListCleanupWorker {
+	return &AllowListCleanupWorker{
+		policyService: policyService,
+		interval:      interval,
+		retention:     retention,
+	}
+}
+
+// Start runs the cleanup loop until the context is cancelled
+func (w *AllowListCleanupWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		slog.Info("Allow list cleanup worker disabled")
+		return
+	}
+
+	slog.Info("Allow list cleanup worker started", "interval", w.interval, "retention", w.retention)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Allow list cleanup worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce()
+		}
+	}
+}
+
+// RunOnce prunes allow list entries that expired longer ago than the retention period
+func (w *AllowListCleanupWorker) RunOnce() int {
+	removed, err := w.policyService.PruneExpiredAllowListEntries(time.Now().Add(-w.retention))
+	if err != nil {
+		slog.Error("Failed to prune expired allow list entries", "error", err)
+		return 0
+	}
+	if removed > 0 {
+		slog.Info("Pruned expired allow list entries", "removed", removed)
+	}
+	return removed
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PruneExpiredAllowListEntries removes allow list entries that expired before the given cutoff.
+// Entries that expired after the cutoff are kept so that decisions can still report them as
+// expired (rather than unauthorized) and consumers have a window in which to request renewal.
+func (s *PolicyMetadataService) PruneExpiredAllowListEntries(cutoff time.Time) (int, error) {
+	removed := 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// The records are read under lock so that allow list updates made meanwhile are not lost
+		var policyMetadataRecords []models.PolicyMetadata
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&policyMetadataRecords).Error; err != nil {
+			return fmt.Errorf("failed to fetch policy metadata records: %w", err)
+		}
+
+		now := time.Now()
+		for i := range policyMetadataRecords {
+			pm := &policyMetadataRecords[i]
+
+			pruned := 0
+			for applicationID, entry := range pm.AllowList {
+				if entry.ExpiresAt.Before(cutoff) {
+					delete(pm.AllowList, applicationID)
+					pruned++
+				}
+			}
+			if pruned == 0 {
+				continue
+			}
+
+			pm.UpdatedAt = now
+			if err := tx.Model(pm).Select("allow_list", "updated_at").Updates(map[string]interface{}{
+				"allow_list": pm.AllowList,
+				"updated_at": pm.UpdatedAt,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to prune allow list record: %w", err)
+			}
+			removed += pruned
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if removed > 0 {
+		s.refreshCache()
+	}
+
+	return removed, nil
+}
+
+// CreateRenewalRequest records a consumer request to renew existing allow list grants.
+// The request stays pending until the api-server approves or rejects it.
+func (s *PolicyMetadataService) CreateRenewalRequest(req *models.AllowListRenewalCreateRequest) (*models.AllowListRenewalResponse, error) {
+	if req.ApplicationID == "" {
+		return nil, fmt.Errorf("%w: applicationId is required", ErrInvalidInput)
+	}
+	if len(req.Records) == 0 {
+		return nil, fmt.Errorf("%w: at least one record is required", ErrInvalidInput)
+	}
+	if _, err := req.GrantDuration.ExpiresAtFrom(time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	// Renewal only applies to fields the application has previously been granted by the tenant
+	tenantID := tenantOrDefault(req.TenantID)
+	for _, record := range req.Records {
+		var pm models.PolicyMetadata
+		err := s.db.Where("tenant_id = ? AND schema_id = ? AND field_name = ?", tenantID, record.SchemaID, record.FieldName).First(&pm).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: policy metadata not found for schema_id %s and field_name %s", ErrInvalidInput, record.SchemaID, record.FieldName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch policy metadata record: %w", err)
+		}
+		if _, exists := pm.AllowList[req.ApplicationID]; !exists {
+			return nil, fmt.Errorf("%w: application %s has no grant to renew for schema_id %s and field_name %s", ErrInvalidInput, req.ApplicationID, record.SchemaID, record.FieldName)
+		}
+	}
+
+	now := time.Now()
+	renewal := models.AllowListRenewalRequest{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		ApplicationID: req.ApplicationID,
+		Fields:        req.Records,
+		GrantDuration: req.GrantDuration,
+		Status:        models.RenewalRequestStatusPending,
+		Reason:        req.Reason,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.db.Create(&renewal).Error; err != nil {
+		return nil, fmt.Errorf("failed to create renewal request: %w", err)
+	}
+
+	slog.Info("Allow list renewal requested", "renewalId", renewal.ID, "tenantId", tenantID, "applicationId", req.ApplicationID, "fields", len(req.Records))
+	response := renewal.ToResponse()
+	return &response, nil
+}
+
+// ListRenewalRequests lists the tenant's renewal requests, optionally filtered by application and status
+func (s *PolicyMetadataService) ListRenewalRequests(tenantID, applicationID, status string) (*models.AllowListRenewalListResponse, error) {
+	query := s.db.Model(&models.AllowListRenewalRequest{}).Where("tenant_id = ?", tenantOrDefault(tenantID))
+	if applicationID != "" {
+		query = query.Where("application_id = ?", applicationID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var renewals []models.AllowListRenewalRequest
+	if err := query.Order("created_at ASC").Find(&renewals).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch renewal requests: %w", err)
+	}
+
+	records := make([]models.AllowListRenewalResponse, 0, len(renewals))
+	for i := range renewals {
+		records = append(records, renewals[i].ToResponse())
+	}
+
+	return &models.AllowListRenewalListResponse{
+		Records: records,
+		Count:   len(records),
+	}, nil
+}
+
+// ReviewRenewalRequest applies the api-server decision to a pending renewal request.
+// Approving a request extends the allow list grants using the requested grant duration.
+func (s *PolicyMetadataService) ReviewRenewalRequest(id string, req *models.AllowListRenewalReviewRequest) (*models.AllowListRenewalResponse, error) {
+	var renewal *models.AllowListRenewalRequest
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if renewal, err = reviewRenewalRequest(tx, id, req); err != nil {
+			return err
+		}
+		if req.Status == models.RenewalRequestStatusApproved {
+			if _, err := updateAllowList(tx, renewalAllowListUpdate(renewal)); err != nil {
+				return fmt.Errorf("failed to renew allow list: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if req.Status == models.RenewalRequestStatusApproved {
+		s.refreshCache()
+	}
+
+	slog.Info("Allow list renewal reviewed", "renewalId", renewal.ID, "tenantId", renewal.TenantID, "applicationId", renewal.ApplicationID, "status", renewal.Status)
+	response := renewal.ToResponse()
+	return &response, nil
+}
+
+// ProposeRenewalApproval approves a pending renewal request and proposes the extension of its grants
+// for review, the form ReviewRenewalRequest takes when changes require approval. The grants are
+// extended when another admin approves the returned change set.
+func (s *PolicyMetadataService) ProposeRenewalApproval(id string, req *models.AllowListRenewalReviewRequest) (*models.PolicyChangeSetResponse, error) {
+	if req.Status != models.RenewalRequestStatusApproved {
+		return nil, fmt.Errorf("%w: only approvals are proposed for review", ErrInvalidInput)
+	}
+
+	var changeSet *models.PolicyChangeSetResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		renewal, err := reviewRenewalRequest(tx, id, req)
+		if err != nil {
+			return err
+		}
+		update := renewalAllowListUpdate(renewal)
+		if err := validateAllowListUpdate(tx, renewal.TenantID, update); err != nil {
+			return err
+		}
+		proposal := newPolicyChangeSet(renewal.TenantID, models.PolicyChangeSetKindAllowList,
+			fmt.Sprintf("Renew the grants of application %s to %d fields", renewal.ApplicationID, len(renewal.Fields)), req.ReviewedBy)
+		proposal.Description = renewal.Reason
+		proposal.AllowListUpdate = update
+		changeSet, err = proposePolicyChangeSet(tx, proposal, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Allow list renewal approved for review", "renewalId", id, "changeSetId", changeSet.ID)
+	return changeSet, nil
+}
+
+// reviewRenewalRequest records the decision on a pending renewal request of the reviewer's tenant
+func reviewRenewalRequest(tx *gorm.DB, id string, req *models.AllowListRenewalReviewRequest) (*models.AllowListRenewalRequest, error) {
+	if req.Status != models.RenewalRequestStatusApproved && req.Status != models.RenewalRequestStatusRejected {
+		return nil, fmt.Errorf("%w: status must be %s or %s", ErrInvalidInput, models.RenewalRequestStatusApproved, models.RenewalRequestStatusRejected)
+	}
+
+	var renewal models.AllowListRenewalRequest
+	err := tx.Where("tenant_id = ? AND id = ?", tenantOrDefault(req.TenantID), id).First(&renewal).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: renewal request %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch renewal request: %w", err)
+	}
+	if renewal.Status != models.RenewalRequestStatusPending {
+		return nil, fmt.Errorf("%w: renewal request %s is already %s", ErrConflict, id, renewal.Status)
+	}
+
+	// Only pending requests are updated, so that concurrent reviews cannot both extend the grants
+	renewal.Status = req.Status
+	renewal.ReviewComment = req.ReviewComment
+	renewal.UpdatedAt = time.Now()
+	result := tx.Model(&models.AllowListRenewalRequest{}).
+		Where("id = ? AND status = ?", renewal.ID, models.RenewalRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":         renewal.Status,
+			"review_comment": renewal.ReviewComment,
+			"updated_at":     renewal.UpdatedAt,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update renewal request: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: renewal request %s was reviewed concurrently", ErrConflict, id)
+	}
+	return &renewal, nil
+}
+
+// renewalAllowListUpdate returns the allow list update that extends the grants of a renewal request
+func renewalAllowListUpdate(renewal *models.AllowListRenewalRequest) *models.AllowListUpdateRequest {
+	return &models.AllowListUpdateRequest{
+		TenantID:      renewal.TenantID,
+		ApplicationID: renewal.ApplicationID,
+		Records:       renewal.Fields,
+		GrantDuration: renewal.GrantDuration,
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedAllowListFields creates public fields and sets the given allow list on each of them
+func seedAllowListFields(t *testing.T, db *gorm.DB, service *PolicyMetadataService, allowList models.AllowList, fieldNames ...string) {
+	var records []models.PolicyMetadataCreateRequestRecord
+	for _, fieldName := range fieldNames {
+		records = append(records, models.PolicyMetadataCreateRequestRecord{
+			FieldName:         fieldName,
+			Source:            models.SourcePrimary,
+			IsOwner:           true,
+			AccessControlType: models.AccessControlTypePublic,
+		})
+	}
+	_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{SchemaID: "schema-123", Records: records})
+	require.NoError(t, err)
+
+	for _, fieldName := range fieldNames {
+		var pm models.PolicyMetadata
+		require.NoError(t, db.Where("field_name = ?", fieldName).First(&pm).Error)
+		pm.AllowList = models.AllowList{}
+		for appID, entry := range allowList {
+			pm.AllowList[appID] = entry
+		}
+		require.NoError(t, db.Save(&pm).Error)
+	}
+}
+
+func TestPolicyMetadataService_PruneExpiredAllowListEntries(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	now := time.Now()
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-long-expired":   {ExpiresAt: now.AddDate(0, 0, -60), UpdatedAt: now},
+		"app-recent-expired": {ExpiresAt: now.AddDate(0, 0, -1), UpdatedAt: now},
+		"app-active":         {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now},
+	}, "field1", "field2")
+
+	removed, err := service.PruneExpiredAllowListEntries(now.AddDate(0, 0, -30))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	var pm models.PolicyMetadata
+	require.NoError(t, db.Where("field_name = ?", "field1").First(&pm).Error)
+	assert.NotContains(t, pm.AllowList, "app-long-expired")
+	assert.Contains(t, pm.AllowList, "app-recent-expired")
+	assert.Contains(t, pm.AllowList, "app-active")
+
+	// Running again is a no-op
+	removed, err = service.PruneExpiredAllowListEntries(now.AddDate(0, 0, -30))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
+
+func TestAllowListCleanupWorker_RunOnce(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	now := time.Now()
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-expired": {ExpiresAt: now.Add(-2 * time.Hour), UpdatedAt: now},
+		"app-active":  {ExpiresAt: now.Add(time.Hour), UpdatedAt: now},
+	}, "field1")
+
+	worker := NewAllowListCleanupWorker(service, time.Minute, time.Hour)
+	assert.Equal(t, 1, worker.RunOnce())
+	assert.Equal(t, 0, worker.RunOnce())
+}
+
+func TestPolicyMetadataService_RenewalRequests(t *testing.T) {
+	setup := func(t *testing.T) *PolicyMetadataService {
+		db := testhelpers.SetupTestDB(t)
+		service := NewPolicyMetadataService(db)
+		seedAllowListFields(t, db, service, models.AllowList{
+			"app-123": {ExpiresAt: time.Now().AddDate(0, 0, -1), UpdatedAt: time.Now()},
+		}, "field1")
+		return service
+	}
+	renewalFields := []models.AllowListUpdateRequestRecord{{FieldName: "field1", SchemaID: "schema-123"}}
+
+	t.Run("Create_Approve_RenewsGrant", func(t *testing.T) {
+		service := setup(t)
+
+		created, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       renewalFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+			Reason:        testhelpers.StringPtr("continued service"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.RenewalRequestStatusPending, created.Status)
+
+		decision, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+			ApplicationID:  "app-123",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "field1", SchemaID: "schema-123"}},
+		})
+		require.NoError(t, err)
+		assert.True(t, decision.AppAccessExpired, "grant should stay expired until the renewal is approved")
+
+		reviewed, err := service.ReviewRenewalRequest(created.ID, &models.AllowListRenewalReviewRequest{
+			Status: models.RenewalRequestStatusApproved,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.RenewalRequestStatusApproved, reviewed.Status)
+
+		decision, err = service.GetPolicyDecision(&models.PolicyDecisionRequest{
+			ApplicationID:  "app-123",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "field1", SchemaID: "schema-123"}},
+		})
+		require.NoError(t, err)
+		assert.False(t, decision.AppAccessExpired)
+		assert.True(t, decision.AppAuthorized)
+
+		// Reviewing twice is a conflict
+		_, err = service.ReviewRenewalRequest(created.ID, &models.AllowListRenewalReviewRequest{
+			Status: models.RenewalRequestStatusRejected,
+		})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("Create_Reject_LeavesGrantExpired", func(t *testing.T) {
+		service := setup(t)
+
+		created, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       renewalFields,
+			GrantDuration: models.GrantDurationTypeOneYear,
+		})
+		require.NoError(t, err)
+
+		reviewed, err := service.ReviewRenewalRequest(created.ID, &models.AllowListRenewalReviewRequest{
+			Status:        models.RenewalRequestStatusRejected,
+			ReviewComment: testhelpers.StringPtr("no longer required"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.RenewalRequestStatusRejected, reviewed.Status)
+		assert.Equal(t, "no longer required", *reviewed.ReviewComment)
+
+		decision, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+			ApplicationID:  "app-123",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "field1", SchemaID: "schema-123"}},
+		})
+		require.NoError(t, err)
+		assert.True(t, decision.AppAccessExpired)
+	})
+
+	t.Run("Create_NoExistingGrant", func(t *testing.T) {
+		service := setup(t)
+
+		_, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-other",
+			Records:       renewalFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		assert.Contains(t, err.Error(), "no grant to renew")
+	})
+
+	t.Run("Create_InvalidGrantDuration", func(t *testing.T) {
+		service := setup(t)
+
+		_, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       renewalFields,
+			GrantDuration: "7d",
+		})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("List_FiltersByStatusAndApplication", func(t *testing.T) {
+		service := setup(t)
+
+		first, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       renewalFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		})
+		require.NoError(t, err)
+		_, err = service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       renewalFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		})
+		require.NoError(t, err)
+		_, err = service.ReviewRenewalRequest(first.ID, &models.AllowListRenewalReviewRequest{Status: models.RenewalRequestStatusRejected})
+		require.NoError(t, err)
+
+		all, err := service.ListRenewalRequests("", "app-123", "")
+		require.NoError(t, err)
+		assert.Equal(t, 2, all.Count)
+
+		pending, err := service.ListRenewalRequests("", "", string(models.RenewalRequestStatusPending))
+		require.NoError(t, err)
+		assert.Equal(t, 1, pending.Count)
+
+		none, err := service.ListRenewalRequests("", "app-other", "")
+		require.NoError(t, err)
+		assert.Equal(t, 0, none.Count)
+		assert.NotNil(t, none.Records)
+	})
+
+	t.Run("Review_ConcurrentApprovalsRenewOnce", func(t *testing.T) {
+		service := setup(t)
+
+		created, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       renewalFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		})
+		require.NoError(t, err)
+
+		const reviewers = 5
+		var wg sync.WaitGroup
+		errs := make(chan error, reviewers)
+		for i := 0; i < reviewers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := service.ReviewRenewalRequest(created.ID, &models.AllowListRenewalReviewRequest{
+					Status: models.RenewalRequestStatusApproved,
+				})
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		approved := 0
+		for err := range errs {
+			if err == nil {
+				approved++
+				continue
+			}
+			assert.ErrorIs(t, err, ErrConflict)
+		}
+		assert.Equal(t, 1, approved)
+	})
+
+	t.Run("Review_FailedRenewalStaysPending", func(t *testing.T) {
+		service := setup(t)
+
+		created, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       renewalFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		})
+		require.NoError(t, err)
+
+		// The field is removed before the review, so the grant cannot be extended
+		require.NoError(t, service.db.Where("field_name = ?", "field1").Delete(&models.PolicyMetadata{}).Error)
+		_, err = service.ReviewRenewalRequest(created.ID, &models.AllowListRenewalReviewRequest{
+			Status: models.RenewalRequestStatusApproved,
+		})
+		require.Error(t, err)
+
+		pending, err := service.ListRenewalRequests("", "app-123", string(models.RenewalRequestStatusPending))
+		require.NoError(t, err)
+		assert.Equal(t, 1, pending.Count, "the status update is rolled back with the failed renewal")
+	})
+
+	t.Run("TenantsAreIsolated", func(t *testing.T) {
+		service := setup(t)
+		// tenant-b grants app-123 a field of its own schema
+		_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+			SchemaID: "schema-b",
+			TenantID: "tenant-b",
+			Records: []models.PolicyMetadataCreateRequestRecord{{
+				FieldName: "field1", Source: models.SourcePrimary, IsOwner: true, AccessControlType: models.AccessControlTypePublic,
+			}},
+		})
+		require.NoError(t, err)
+		expired := time.Now().AddDate(0, 0, -1)
+		var pm models.PolicyMetadata
+		require.NoError(t, service.db.Where("tenant_id = ? AND schema_id = ?", "tenant-b", "schema-b").First(&pm).Error)
+		pm.AllowList = models.AllowList{"app-123": {ExpiresAt: expired, UpdatedAt: expired}}
+		require.NoError(t, service.db.Save(&pm).Error)
+		tenantFields := []models.AllowListUpdateRequestRecord{{FieldName: "field1", SchemaID: "schema-b"}}
+
+		_, err = service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       tenantFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		})
+		assert.ErrorIs(t, err, ErrInvalidInput, "another tenant's fields cannot be renewed")
+
+		created, err := service.CreateRenewalRequest(&models.AllowListRenewalCreateRequest{
+			ApplicationID: "app-123",
+			Records:       tenantFields,
+			GrantDuration: models.GrantDurationTypeOneMonth,
+			TenantID:      "tenant-b",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-b", created.TenantID)
+
+		other, err := service.ListRenewalRequests(models.DefaultTenantID, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, 0, other.Count)
+		_, err = service.ReviewRenewalRequest(created.ID, &models.AllowListRenewalReviewRequest{Status: models.RenewalRequestStatusApproved})
+		assert.ErrorIs(t, err, ErrNotFound, "another tenant cannot review the request")
+
+		_, err = service.ReviewRenewalRequest(created.ID, &models.AllowListRenewalReviewRequest{
+			Status:   models.RenewalRequestStatusApproved,
+			TenantID: "tenant-b",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, service.db.Where("tenant_id = ? AND schema_id = ?", "tenant-b", "schema-b").First(&pm).Error)
+		assert.True(t, pm.AllowList["app-123"].ExpiresAt.After(time.Now()))
+	})
+
+	t.Run("Review_InvalidStatusAndNotFound", func(t *testing.T) {
+		service := setup(t)
+
+		_, err := service.ReviewRenewalRequest("missing", &models.AllowListRenewalReviewRequest{Status: models.RenewalRequestStatusPending})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+
+		_, err = service.ReviewRenewalRequest("missing", &models.AllowListRenewalReviewRequest{Status: models.RenewalRequestStatusApproved})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
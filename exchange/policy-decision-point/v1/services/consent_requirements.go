@@ -0,0 +1,97 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+)
+
+// consentTypeRealtime is the consent engine type of consent collected while the consumer's query waits
+const consentTypeRealtime = "realtime"
+
+// GetConsentRequirements consolidates the consent the OE must request before releasing the requested
+// fields: one requirement per owner, with the grant duration and purposes of the fields' classification tiers
+func (s *PolicyMetadataService) GetConsentRequirements(req *models.PolicyDecisionRequest) (*models.ConsentRequirementsResponse, error) {
+	decision, err := s.GetPolicyDecision(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ConsentRequirementsResponse{
+		ApplicationID: req.ApplicationID,
+		Requirements:  []models.ConsentRequirement{},
+		PolicyVersion: decision.PolicyVersion,
+	}
+	if len(decision.ConsentRequiredFields) == 0 {
+		return response, nil
+	}
+
+	schemaIDSet := make(map[string]struct{})
+	for _, field := range decision.ConsentRequiredFields {
+		schemaIDSet[field.SchemaID] = struct{}{}
+	}
+	schemaIDs := make([]string, 0, len(schemaIDSet))
+	for schemaID := range schemaIDSet {
+		schemaIDs = append(schemaIDs, schemaID)
+	}
+	allMetadata, err := s.findPolicyMetadataBySchemas(schemaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Requirements = consolidateConsentRequirements(decision.ConsentRequiredFields, allMetadata)
+	response.ConsentRequired = len(response.Requirements) > 0
+	return response, nil
+}
+
+// consolidateConsentRequirements groups consent required fields by owner. The most sensitive
+// classification among an owner's fields sets the grant duration of the owner's consent.
+func consolidateConsentRequirements(fields []models.PolicyDecisionResponseFieldRecord, allMetadata []models.PolicyMetadata) []models.ConsentRequirement {
+	classifications := make(map[string]models.Classification)
+	for _, pm := range allMetadata {
+		classifications[pm.SchemaID+":"+pm.FieldName] = pm.Classification
+	}
+
+	type ownerRequirement struct {
+		requirement   models.ConsentRequirement
+		mostSensitive models.Classification
+		tiers         map[models.Classification]struct{}
+	}
+	byOwner := make(map[models.Owner]*ownerRequirement)
+	for _, field := range fields {
+		owner := models.OwnerCitizen
+		if field.Owner != nil && *field.Owner != "" {
+			owner = *field.Owner
+		}
+		group, ok := byOwner[owner]
+		if !ok {
+			group = &ownerRequirement{
+				requirement: models.ConsentRequirement{Owner: owner, ConsentType: consentTypeRealtime},
+				tiers:       make(map[models.Classification]struct{}),
+			}
+			byOwner[owner] = group
+		}
+
+		classification := classifications[field.SchemaID+":"+field.FieldName].Rule().Classification
+		if len(group.tiers) == 0 || classification.Sensitivity() > group.mostSensitive.Sensitivity() {
+			group.mostSensitive = classification
+		}
+		group.tiers[classification] = struct{}{}
+		group.requirement.Fields = append(group.requirement.Fields, field)
+	}
+
+	requirements := make([]models.ConsentRequirement, 0, len(byOwner))
+	for _, group := range byOwner {
+		group.requirement.GrantDuration = group.mostSensitive.Rule().ConsentGrantDuration
+		for _, rule := range models.ClassificationRules() {
+			if _, ok := group.tiers[rule.Classification]; ok {
+				group.requirement.Purposes = append(group.requirement.Purposes, rule.ConsentPurpose)
+			}
+		}
+		requirements = append(requirements, group.requirement)
+	}
+	sort.Slice(requirements, func(i, j int) bool {
+		return requirements[i].Owner < requirements[j].Owner
+	})
+	return requirements
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyMetadataService_GetConsentRequirements(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	citizen := models.OwnerCitizen
+
+	_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{FieldName: "person.fullName", Source: models.SourcePrimary, IsOwner: true, Classification: models.ClassificationPublic},
+			{FieldName: "person.address", Source: models.SourcePrimary, Classification: models.ClassificationInternal, Owner: &citizen},
+			{FieldName: "person.nic", Source: models.SourcePrimary, Classification: models.ClassificationPersonal, Owner: &citizen},
+			{FieldName: "person.health", Source: models.SourcePrimary, Classification: models.ClassificationSensitivePersonal, Owner: &citizen},
+		},
+	})
+	require.NoError(t, err)
+	records := []models.AllowListUpdateRequestRecord{
+		{FieldName: "person.fullName", SchemaID: "schema-123"},
+		{FieldName: "person.address", SchemaID: "schema-123"},
+		{FieldName: "person.nic", SchemaID: "schema-123"},
+		{FieldName: "person.health", SchemaID: "schema-123"},
+	}
+	_, err = service.UpdateAllowList(&models.AllowListUpdateRequest{ApplicationID: "app-1", Records: records, GrantDuration: models.GrantDurationTypeOneMonth})
+	require.NoError(t, err)
+
+	request := func(fields ...string) *models.PolicyDecisionRequest {
+		req := &models.PolicyDecisionRequest{ApplicationID: "app-1"}
+		for _, field := range fields {
+			req.RequiredFields = append(req.RequiredFields, models.PolicyDecisionRequestRecord{FieldName: field, SchemaID: "schema-123"})
+		}
+		return req
+	}
+
+	t.Run("consolidated per owner", func(t *testing.T) {
+		resp, err := service.GetConsentRequirements(request("person.fullName", "person.address", "person.nic", "person.health"))
+		require.NoError(t, err)
+		assert.True(t, resp.ConsentRequired)
+		assert.NotEmpty(t, resp.PolicyVersion)
+		require.Len(t, resp.Requirements, 1)
+
+		requirement := resp.Requirements[0]
+		assert.Equal(t, models.OwnerCitizen, requirement.Owner)
+		assert.Equal(t, "realtime", requirement.ConsentType)
+		assert.Equal(t, "PT1H", requirement.GrantDuration, "the most sensitive tier sets the grant duration")
+		assert.Equal(t, []string{
+			models.ClassificationInternal.Rule().ConsentPurpose,
+			models.ClassificationPersonal.Rule().ConsentPurpose,
+			models.ClassificationSensitivePersonal.Rule().ConsentPurpose,
+		}, requirement.Purposes)
+		assert.Len(t, requirement.Fields, 3)
+	})
+
+	t.Run("grant duration of a single tier", func(t *testing.T) {
+		resp, err := service.GetConsentRequirements(request("person.address"))
+		require.NoError(t, err)
+		require.Len(t, resp.Requirements, 1)
+		assert.Equal(t, "P1D", resp.Requirements[0].GrantDuration)
+	})
+
+	t.Run("no consent required", func(t *testing.T) {
+		resp, err := service.GetConsentRequirements(request("person.fullName"))
+		require.NoError(t, err)
+		assert.False(t, resp.ConsentRequired)
+		assert.Empty(t, resp.Requirements)
+	})
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/shared/database"
+)
+
+// ListConsumerGrants lists every field the consumer's applications are allow-listed for.
+// Expired grants are omitted unless the filter asks for them.
+func (s *PolicyMetadataService) ListConsumerGrants(filter *models.ConsumerGrantFilter) (*models.ConsumerGrantListResponse, error) {
+	if filter.ConsumerID == "" && filter.ApplicationID == "" {
+		return nil, fmt.Errorf("%w: consumerId or applicationId is required", ErrInvalidInput)
+	}
+
+	// Allow lists are stored per field, so every record is scanned
+	var policyMetadataRecords []models.PolicyMetadata
+	if err := database.Reader(s.db).Where("tenant_id = ?", tenantOrDefault(filter.TenantID)).Find(&policyMetadataRecords).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
+	}
+
+	now := time.Now()
+	records := []models.ConsumerGrantResponse{}
+	for _, pm := range policyMetadataRecords {
+		for applicationID, entry := range pm.AllowList {
+			if filter.ConsumerID != "" && entry.ConsumerID != filter.ConsumerID {
+				continue
+			}
+			if filter.ApplicationID != "" && applicationID != filter.ApplicationID {
+				continue
+			}
+			expired := entry.IsExpired(now)
+			if expired && !filter.IncludeExpired {
+				continue
+			}
+
+			records = append(records, models.ConsumerGrantResponse{
+				SchemaID:       pm.SchemaID,
+				FieldName:      pm.FieldName,
+				DisplayName:    pm.DisplayName,
+				Classification: pm.Classification,
+				ApplicationID:  applicationID,
+				ConsumerID:     entry.ConsumerID,
+				ExpiresAt:      entry.ExpiresAt.Format(time.RFC3339),
+				UpdatedAt:      entry.UpdatedAt.Format(time.RFC3339),
+				Expired:        expired,
+			})
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].ApplicationID != records[j].ApplicationID {
+			return records[i].ApplicationID < records[j].ApplicationID
+		}
+		if records[i].SchemaID != records[j].SchemaID {
+			return records[i].SchemaID < records[j].SchemaID
+		}
+		return records[i].FieldName < records[j].FieldName
+	})
+
+	return &models.ConsumerGrantListResponse{
+		Records: records,
+		Count:   len(records),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyMetadataService_ListConsumerGrants(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	now := time.Now()
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-1": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now, ConsumerID: "member-1"},
+		"app-2": {ExpiresAt: now.AddDate(0, 0, -1), UpdatedAt: now, ConsumerID: "member-1"},
+		"app-3": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now, ConsumerID: "member-2"},
+	}, "field1", "field2")
+
+	t.Run("active grants for consumer", func(t *testing.T) {
+		resp, err := service.ListConsumerGrants(&models.ConsumerGrantFilter{ConsumerID: "member-1"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.Count)
+		for _, grant := range resp.Records {
+			assert.Equal(t, "app-1", grant.ApplicationID)
+			assert.False(t, grant.Expired)
+		}
+		assert.Equal(t, "field1", resp.Records[0].FieldName)
+	})
+
+	t.Run("include expired grants", func(t *testing.T) {
+		resp, err := service.ListConsumerGrants(&models.ConsumerGrantFilter{ConsumerID: "member-1", IncludeExpired: true})
+		require.NoError(t, err)
+		assert.Equal(t, 4, resp.Count)
+	})
+
+	t.Run("filter by application", func(t *testing.T) {
+		resp, err := service.ListConsumerGrants(&models.ConsumerGrantFilter{ApplicationID: "app-3"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.Count)
+		assert.Equal(t, "member-2", resp.Records[0].ConsumerID)
+	})
+
+	t.Run("consumer or application required", func(t *testing.T) {
+		_, err := service.ListConsumerGrants(&models.ConsumerGrantFilter{})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestPolicyMetadataService_UpdateAllowList_KeepsConsumer(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	seedAllowListFields(t, db, service, models.AllowList{}, "field1")
+
+	records := []models.AllowListUpdateRequestRecord{{FieldName: "field1", SchemaID: "schema-123"}}
+	_, err := service.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		ConsumerID:    "member-1",
+		Records:       records,
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	// A renewal without a consumer keeps the existing one
+	_, err = service.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records:       records,
+		GrantDuration: models.GrantDurationTypeOneYear,
+	})
+	require.NoError(t, err)
+
+	var pm models.PolicyMetadata
+	require.NoError(t, db.Where("field_name = ?", "field1").First(&pm).Error)
+	assert.Equal(t, "member-1", pm.AllowList["app-1"].ConsumerID)
+}
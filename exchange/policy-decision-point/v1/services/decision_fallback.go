@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/metrics"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+)
+
+// DecisionFallback configures how decisions are made while the policy database is unavailable
+type DecisionFallback struct {
+	Mode models.DecisionFallbackMode
+	// PinnedFields are the fields authorized for every application in fail_open_pinned mode, keyed by
+	// models.FieldKey. Only pin fields that are safe to release without a policy check.
+	PinnedFields map[string]struct{}
+	// MaxStaleness is how long after the cached policies and kill switches were last confirmed current
+	// they are still served in last_known_good mode
+	MaxStaleness time.Duration
+}
+
+// Validate checks the fallback can decide in its mode
+func (f DecisionFallback) Validate() error {
+	switch f.Mode {
+	case models.DecisionFallbackFailClosed:
+		return nil
+	case models.DecisionFallbackFailOpenPinned:
+		if len(f.PinnedFields) == 0 {
+			return errors.New("fail_open_pinned requires at least one pinned field")
+		}
+		return nil
+	case models.DecisionFallbackLastKnownGood:
+		if f.MaxStaleness <= 0 {
+			return errors.New("last_known_good requires a positive staleness limit")
+		}
+		return nil
+	}
+	_, err := models.ParseDecisionFallbackMode(string(f.Mode))
+	return err
+}
+
+// SetDecisionFallback sets how decisions are made while the policy database is unavailable.
+// Without one, decisions fail closed.
+func (s *PolicyMetadataService) SetDecisionFallback(fallback DecisionFallback) {
+	s.fallback = fallback
+}
+
+// FallbackAvailable reports whether decisions can currently be made without the policy database
+func (s *PolicyMetadataService) FallbackAvailable() error {
+	switch s.fallback.Mode {
+	case models.DecisionFallbackFailOpenPinned:
+		return nil
+	case models.DecisionFallbackLastKnownGood:
+		return s.checkLastKnownGood(time.Now())
+	}
+	return errors.New("decisions fail closed without the policy database")
+}
+
+// fallbackDecision decides a request whose policies could not be read from the database, as the fallback
+// mode allows. It returns the database error in fail_closed mode or when the fallback cannot decide.
+func (s *PolicyMetadataService) fallbackDecision(req *models.PolicyDecisionRequest, cause error) (*models.PolicyDecisionResponse, error) {
+	var resp *models.PolicyDecisionResponse
+	var err error
+	now := time.Now()
+	switch s.fallback.Mode {
+	case models.DecisionFallbackFailOpenPinned:
+		resp = s.pinnedFieldsDecision(req)
+	case models.DecisionFallbackLastKnownGood:
+		resp, err = s.lastKnownGoodDecision(req, now)
+	default:
+		return nil, cause
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s fallback unavailable: %v)", cause, s.fallback.Mode, err)
+	}
+
+	resp.Fallback = s.fallback.Mode
+	metrics.RecordDecisionFallback(s.fallback.Mode)
+	slog.Warn("Policy database unavailable, decision made by fallback",
+		"mode", s.fallback.Mode, "applicationId", req.ApplicationID, "error", cause)
+	return resp, nil
+}
+
+// pinnedFieldsDecision authorizes the pinned fields and denies all others. Kill switches loaded before
+// the outage still apply, since they only narrow what is released.
+func (s *PolicyMetadataService) pinnedFieldsDecision(req *models.PolicyDecisionRequest) *models.PolicyDecisionResponse {
+	var denyList *models.DenyList
+	if s.killSwitches != nil {
+		denyList, _, _ = s.killSwitches.LastKnownDenyList()
+	}
+	if ks, blocked := denyList.Application(req.ApplicationID); blocked {
+		return blockedPolicyDecision(req, ks)
+	}
+
+	var unauthorizedFields []models.PolicyDecisionResponseFieldRecord
+	var blockedFields []models.PolicyDecisionResponseFieldRecord
+	for _, record := range req.RequiredFields {
+		field := models.PolicyDecisionResponseFieldRecord{FieldName: record.FieldName, SchemaID: record.SchemaID}
+		if ks, blocked := denyList.Field(&models.PolicyMetadata{SchemaID: record.SchemaID, FieldName: record.FieldName}); blocked {
+			field.BlockedBy = ks.TargetType
+			blockedFields = append(blockedFields, field)
+			continue
+		}
+		if _, pinned := s.fallback.PinnedFields[models.FieldKey(record.SchemaID, record.FieldName)]; !pinned {
+			unauthorizedFields = append(unauthorizedFields, field)
+		}
+	}
+
+	return &models.PolicyDecisionResponse{
+		UnauthorizedFields: unauthorizedFields,
+		BlockedFields:      blockedFields,
+		AppAuthorized:      len(unauthorizedFields) == 0 && len(blockedFields) == 0,
+	}
+}
+
+// lastKnownGoodDecision evaluates a request against the last policies and kill switches loaded into
+// memory, if they were confirmed current within the staleness limit
+func (s *PolicyMetadataService) lastKnownGoodDecision(req *models.PolicyDecisionRequest, now time.Time) (*models.PolicyDecisionResponse, error) {
+	if err := s.checkLastKnownGood(now); err != nil {
+		return nil, err
+	}
+
+	var denyList *models.DenyList
+	if s.killSwitches != nil {
+		denyList, _, _ = s.killSwitches.LastKnownDenyList()
+	}
+	if ks, blocked := denyList.Application(req.ApplicationID); blocked {
+		return blockedPolicyDecision(req, ks), nil
+	}
+
+	records, _, _ := s.cache.Snapshot(requestSchemaIDs(req))
+	return evaluatePolicyDecision(req, records, denyList, now)
+}
+
+// checkLastKnownGood checks that the cached policies and kill switches were confirmed current within
+// the staleness limit
+func (s *PolicyMetadataService) checkLastKnownGood(now time.Time) error {
+	if s.cache == nil {
+		return errors.New("no policy cache is configured")
+	}
+	verifiedAt := s.cache.VerifiedAt()
+	if verifiedAt.IsZero() {
+		return errors.New("no policies have been loaded")
+	}
+	if age := now.Sub(verifiedAt); age > s.fallback.MaxStaleness {
+		return fmt.Errorf("cached policies were last verified %s ago, over the %s limit", age.Round(time.Second), s.fallback.MaxStaleness)
+	}
+
+	if s.killSwitches == nil {
+		return nil
+	}
+	_, loadedAt, ok := s.killSwitches.LastKnownDenyList()
+	if !ok {
+		return errors.New("no kill switches have been loaded")
+	}
+	if age := now.Sub(loadedAt); age > s.fallback.MaxStaleness {
+		return fmt.Errorf("kill switches were last loaded %s ago, over the %s limit", age.Round(time.Second), s.fallback.MaxStaleness)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupFallbackService seeds two fields authorized for app-1, loads the policy cache and kill switches,
+// and then takes the database down so that every further read fails
+func setupFallbackService(t *testing.T, fallback DecisionFallback) (*PolicyMetadataService, *PolicyCache, *KillSwitchService) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	cache := NewPolicyCache(db)
+	killSwitches := NewKillSwitchService(db)
+	service.SetCache(cache)
+	service.SetKillSwitches(killSwitches)
+	service.SetDecisionFallback(fallback)
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-1": {ExpiresAt: time.Now().AddDate(0, 1, 0), UpdatedAt: time.Now()},
+	}, "field1", "field2")
+	_, err := killSwitches.SetKillSwitch(&models.KillSwitchRequest{TargetType: models.KillSwitchTargetApplication, TargetID: "app-blocked"})
+	require.NoError(t, err)
+	require.NoError(t, cache.Refresh())
+	require.NoError(t, killSwitches.Refresh())
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	require.Error(t, cache.Refresh(), "the cache is invalidated once the database is down")
+	require.Error(t, killSwitches.Refresh())
+	return service, cache, killSwitches
+}
+
+func fallbackRequest(applicationID string, fieldNames ...string) *models.PolicyDecisionRequest {
+	req := &models.PolicyDecisionRequest{ApplicationID: applicationID}
+	for _, fieldName := range fieldNames {
+		req.RequiredFields = append(req.RequiredFields, models.PolicyDecisionRequestRecord{SchemaID: "schema-123", FieldName: fieldName})
+	}
+	return req
+}
+
+func TestDecisionFallback_FailClosed(t *testing.T) {
+	service, _, _ := setupFallbackService(t, DecisionFallback{Mode: models.DecisionFallbackFailClosed})
+
+	resp, err := service.GetPolicyDecision(fallbackRequest("app-1", "field1"))
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Error(t, service.FallbackAvailable())
+}
+
+func TestDecisionFallback_LastKnownGood(t *testing.T) {
+	service, _, _ := setupFallbackService(t, DecisionFallback{Mode: models.DecisionFallbackLastKnownGood, MaxStaleness: time.Minute})
+	require.NoError(t, service.FallbackAvailable())
+
+	resp, err := service.GetPolicyDecision(fallbackRequest("app-1", "field1", "field2"))
+	require.NoError(t, err)
+	assert.True(t, resp.AppAuthorized)
+	assert.Equal(t, models.DecisionFallbackLastKnownGood, resp.Fallback)
+	assert.NotEmpty(t, resp.PolicyVersion)
+
+	// The snapshot keeps its allow lists and the kill switches loaded before the outage
+	resp, err = service.GetPolicyDecision(fallbackRequest("app-2", "field1"))
+	require.NoError(t, err)
+	assert.False(t, resp.AppAuthorized)
+	assert.Len(t, resp.UnauthorizedFields, 1)
+
+	resp, err = service.GetPolicyDecision(fallbackRequest("app-blocked", "field1"))
+	require.NoError(t, err)
+	assert.False(t, resp.AppAuthorized)
+	assert.Len(t, resp.BlockedFields, 1)
+	assert.Equal(t, models.DecisionFallbackLastKnownGood, resp.Fallback)
+}
+
+func TestDecisionFallback_LastKnownGoodTooStale(t *testing.T) {
+	service, cache, _ := setupFallbackService(t, DecisionFallback{Mode: models.DecisionFallbackLastKnownGood, MaxStaleness: time.Minute})
+
+	// Decisions fail closed once the snapshot is older than the staleness limit
+	cache.mu.Lock()
+	cache.verifiedAt = time.Now().Add(-2 * time.Minute)
+	cache.mu.Unlock()
+
+	resp, err := service.GetPolicyDecision(fallbackRequest("app-1", "field1"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "last_known_good fallback unavailable")
+	assert.Nil(t, resp)
+	assert.Error(t, service.FallbackAvailable())
+}
+
+func TestDecisionFallback_LastKnownGoodStaleKillSwitches(t *testing.T) {
+	service, _, killSwitches := setupFallbackService(t, DecisionFallback{Mode: models.DecisionFallbackLastKnownGood, MaxStaleness: time.Minute})
+
+	killSwitches.mu.Lock()
+	killSwitches.lastLoadedAt = time.Now().Add(-2 * time.Minute)
+	killSwitches.mu.Unlock()
+
+	_, err := service.GetPolicyDecision(fallbackRequest("app-1", "field1"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kill switches")
+}
+
+func TestDecisionFallback_FailOpenPinned(t *testing.T) {
+	service, _, _ := setupFallbackService(t, DecisionFallback{
+		Mode:         models.DecisionFallbackFailOpenPinned,
+		PinnedFields: map[string]struct{}{models.FieldKey("schema-123", "field1"): {}},
+	})
+	require.NoError(t, service.FallbackAvailable())
+
+	// Pinned fields are authorized for any application, without consent
+	resp, err := service.GetPolicyDecision(fallbackRequest("app-2", "field1"))
+	require.NoError(t, err)
+	assert.True(t, resp.AppAuthorized)
+	assert.False(t, resp.AppRequiresOwnerConsent)
+	assert.Equal(t, models.DecisionFallbackFailOpenPinned, resp.Fallback)
+
+	// Every other field is denied, even for applications on its allow list
+	resp, err = service.GetPolicyDecision(fallbackRequest("app-1", "field1", "field2"))
+	require.NoError(t, err)
+	assert.False(t, resp.AppAuthorized)
+	require.Len(t, resp.UnauthorizedFields, 1)
+	assert.Equal(t, "field2", resp.UnauthorizedFields[0].FieldName)
+
+	// Kill switches loaded before the outage still apply
+	resp, err = service.GetPolicyDecision(fallbackRequest("app-blocked", "field1"))
+	require.NoError(t, err)
+	assert.False(t, resp.AppAuthorized)
+	assert.Len(t, resp.BlockedFields, 1)
+}
+
+func TestDecisionFallback_NotUsedWhileDatabaseIsUp(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	service.SetDecisionFallback(DecisionFallback{
+		Mode:         models.DecisionFallbackFailOpenPinned,
+		PinnedFields: map[string]struct{}{models.FieldKey("schema-123", "field1"): {}},
+	})
+	seedAllowListFields(t, db, service, models.AllowList{}, "field1")
+
+	resp, err := service.GetPolicyDecision(fallbackRequest("app-1", "field1"))
+	require.NoError(t, err)
+	assert.False(t, resp.AppAuthorized, "pinned fields follow their policy while the database is up")
+	assert.Empty(t, resp.Fallback)
+}
+
+func TestDecisionFallback_Validate(t *testing.T) {
+	assert.NoError(t, DecisionFallback{Mode: models.DecisionFallbackFailClosed}.Validate())
+	assert.Error(t, DecisionFallback{Mode: models.DecisionFallbackFailOpenPinned}.Validate())
+	assert.NoError(t, DecisionFallback{
+		Mode:         models.DecisionFallbackFailOpenPinned,
+		PinnedFields: map[string]struct{}{"schema-123:field1": {}},
+	}.Validate())
+	assert.Error(t, DecisionFallback{Mode: models.DecisionFallbackLastKnownGood}.Validate())
+	assert.NoError(t, DecisionFallback{Mode: models.DecisionFallbackLastKnownGood, MaxStaleness: time.Minute}.Validate())
+	assert.Error(t, DecisionFallback{Mode: "fail_open"}.Validate())
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/metrics"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/shared/database"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDecisionLogLimit = 100
+	maxDecisionLogLimit     = 1000
+
+	// decisionLogQueueSize is the number of recorded decisions waiting to be written; when the queue
+	// is full, decisions are written as they are recorded
+	decisionLogQueueSize = 1024
+	// decisionLogBatchSize is the largest number of queued decisions written in one insert
+	decisionLogBatchSize = 100
+)
+
+// DecisionLogService records policy decisions and serves them for compliance investigations
+type DecisionLogService struct {
+	db    *gorm.DB
+	queue chan *models.PolicyDecisionLog
+
+	mu sync.Mutex
+	// writing is set while Start writes queued decisions
+	writing bool
+}
+
+// NewDecisionLogService creates a new decision log service
+func NewDecisionLogService(db *gorm.DB) *DecisionLogService {
+	return &DecisionLogService{
+		db:    db,
+		queue: make(chan *models.PolicyDecisionLog, decisionLogQueueSize),
+	}
+}
+
+// Start writes recorded decisions from the queue in batches until ctx is cancelled, then writes the
+// decisions still queued and returns. Until Start is called and once it returns, RecordDecision
+// writes each decision itself.
+func (s *DecisionLogService) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.writing = true
+	s.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.writing = false
+			s.mu.Unlock()
+			s.flush()
+			return
+		case decision := <-s.queue:
+			s.writeBatch(s.batchFrom(decision))
+		}
+	}
+}
+
+// enqueue queues a decision for Start to write; it reports false when no writer is running or the queue is full
+func (s *DecisionLogService) enqueue(decision *models.PolicyDecisionLog) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.writing {
+		return false
+	}
+	select {
+	case s.queue <- decision:
+		return true
+	default:
+		return false
+	}
+}
+
+// batchFrom returns the decision with the decisions queued after it, up to the batch size
+func (s *DecisionLogService) batchFrom(decision *models.PolicyDecisionLog) []*models.PolicyDecisionLog {
+	batch := []*models.PolicyDecisionLog{decision}
+	for len(batch) < decisionLogBatchSize {
+		select {
+		case next := <-s.queue:
+			batch = append(batch, next)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// flush writes every queued decision
+func (s *DecisionLogService) flush() {
+	for {
+		select {
+		case decision := <-s.queue:
+			s.writeBatch(s.batchFrom(decision))
+		default:
+			return
+		}
+	}
+}
+
+// writeBatch writes queued decisions; failures are logged since the decisions were already served
+func (s *DecisionLogService) writeBatch(batch []*models.PolicyDecisionLog) {
+	if err := s.db.Create(&batch).Error; err != nil {
+		slog.Error("Failed to record policy decisions", "count", len(batch), "error", err)
+	}
+}
+
+// RecordDecision persists the outcome of a policy decision request.
+// decisionErr is the error returned by the evaluation, if any. While Start runs, the decision is
+// queued and written in the background; otherwise, or when the queue is full, it is written here.
+func (s *DecisionLogService) RecordDecision(req *models.PolicyDecisionRequest, resp *models.PolicyDecisionResponse, decisionErr error, latency time.Duration) error {
+	decisionID := uuid.New()
+	results := fieldResults(resp)
+
+	fields := make([]models.PolicyDecisionLogField, 0, len(req.RequiredFields))
+	for _, record := range req.RequiredFields {
+		fields = append(fields, models.PolicyDecisionLogField{
+			ID:         uuid.New(),
+			DecisionID: decisionID,
+			SchemaID:   record.SchemaID,
+			FieldName:  record.FieldName,
+			Result:     fieldResultOf(results, resp, record),
+		})
+	}
+
+	outcome := DecisionOutcomeOf(resp, decisionErr)
+	// Metrics are recorded even if persisting the decision fails
+	metrics.RecordDecision(req.ApplicationID, outcome, latency)
+
+	decision := models.PolicyDecisionLog{
+		ID:            decisionID,
+		ApplicationID: req.ApplicationID,
+		Outcome:       outcome,
+		LatencyMs:     float64(latency.Microseconds()) / 1000,
+		Context:       req.Context,
+		Fields:        fields,
+		CreatedAt:     time.Now(),
+	}
+	if resp != nil && resp.PolicyVersion != "" {
+		decision.PolicyVersion = &resp.PolicyVersion
+	}
+	if decisionErr != nil {
+		errMsg := decisionErr.Error()
+		decision.Error = &errMsg
+	}
+
+	if s.enqueue(&decision) {
+		return nil
+	}
+	if err := s.db.Create(&decision).Error; err != nil {
+		return fmt.Errorf("failed to record policy decision: %w", err)
+	}
+	return nil
+}
+
+// ListDecisions returns recorded policy decisions matching the filter, newest first
+func (s *DecisionLogService) ListDecisions(filter *models.PolicyDecisionLogFilter) (*models.PolicyDecisionLogListResponse, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDecisionLogLimit
+	}
+	if limit > maxDecisionLogLimit {
+		limit = maxDecisionLogLimit
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := database.Reader(s.db).Model(&models.PolicyDecisionLog{})
+	if filter.ApplicationID != "" {
+		query = query.Where("application_id = ?", filter.ApplicationID)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.MinLatency > 0 {
+		query = query.Where("latency_ms >= ?", float64(filter.MinLatency.Microseconds())/1000)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+	if filter.FieldName != "" || filter.SchemaID != "" {
+		fieldQuery := s.db.Model(&models.PolicyDecisionLogField{}).Select("decision_id")
+		if filter.FieldName != "" {
+			fieldQuery = fieldQuery.Where("field_name = ?", filter.FieldName)
+		}
+		if filter.SchemaID != "" {
+			fieldQuery = fieldQuery.Where("schema_id = ?", filter.SchemaID)
+		}
+		query = query.Where("id IN (?)", fieldQuery)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count policy decisions: %w", err)
+	}
+
+	var decisions []models.PolicyDecisionLog
+	if err := query.Preload("Fields").Order("created_at DESC").Limit(limit).Offset(offset).Find(&decisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy decisions: %w", err)
+	}
+
+	records := make([]models.PolicyDecisionLogResponse, 0, len(decisions))
+	for i := range decisions {
+		records = append(records, decisions[i].ToResponse())
+	}
+
+	return &models.PolicyDecisionLogListResponse{
+		Records: records,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}, nil
+}
+
+// DecisionOutcomeOf summarizes a policy decision response as a single outcome
+func DecisionOutcomeOf(resp *models.PolicyDecisionResponse, decisionErr error) models.DecisionOutcome {
+	switch {
+	case decisionErr != nil || resp == nil:
+		return models.DecisionOutcomeError
+	case len(resp.BlockedFields) > 0:
+		return models.DecisionOutcomeBlocked
+	case !resp.AppAuthorized:
+		return models.DecisionOutcomeDenied
+	case resp.AppAccessExpired:
+		return models.DecisionOutcomeExpired
+	case resp.AppRequiresOwnerConsent:
+		return models.DecisionOutcomeConsentRequired
+	default:
+		return models.DecisionOutcomeAllowed
+	}
+}
+
+// fieldResults maps (schema_id + field_name) to the per-field result in a decision response
+func fieldResults(resp *models.PolicyDecisionResponse) map[string]models.FieldDecisionResult {
+	results := make(map[string]models.FieldDecisionResult)
+	if resp == nil {
+		return results
+	}
+	for _, f := range resp.ConsentRequiredFields {
+		results[f.SchemaID+":"+f.FieldName] = models.FieldDecisionResultConsentRequired
+	}
+	for _, f := range resp.ExpiredFields {
+		results[f.SchemaID+":"+f.FieldName] = models.FieldDecisionResultExpired
+	}
+	for _, f := range resp.ConditionFailedFields {
+		results[f.SchemaID+":"+f.FieldName] = models.FieldDecisionResultConditionFailed
+	}
+	for _, f := range resp.UnauthorizedFields {
+		results[f.SchemaID+":"+f.FieldName] = models.FieldDecisionResultUnauthorized
+	}
+	for _, f := range resp.BlockedFields {
+		results[f.SchemaID+":"+f.FieldName] = models.FieldDecisionResultBlocked
+	}
+	return results
+}
+
+// fieldResultOf returns the result of a requested field; fields not listed in the response were authorized.
+// It returns an empty result when there is no response.
+func fieldResultOf(results map[string]models.FieldDecisionResult, resp *models.PolicyDecisionResponse, record models.PolicyDecisionRequestRecord) models.FieldDecisionResult {
+	if resp == nil {
+		return ""
+	}
+	if result, exists := results[record.SchemaID+":"+record.FieldName]; exists {
+		return result
+	}
+	return models.FieldDecisionResultAuthorized
+}
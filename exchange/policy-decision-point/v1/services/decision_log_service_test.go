@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecisionOutcomeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *models.PolicyDecisionResponse
+		err  error
+		want models.DecisionOutcome
+	}{
+		{name: "error", err: errors.New("boom"), want: models.DecisionOutcomeError},
+		{name: "nil response", want: models.DecisionOutcomeError},
+		{name: "denied", resp: &models.PolicyDecisionResponse{AppAuthorized: false, AppAccessExpired: true}, want: models.DecisionOutcomeDenied},
+		{name: "expired", resp: &models.PolicyDecisionResponse{AppAuthorized: true, AppAccessExpired: true, AppRequiresOwnerConsent: true}, want: models.DecisionOutcomeExpired},
+		{name: "consent required", resp: &models.PolicyDecisionResponse{AppAuthorized: true, AppRequiresOwnerConsent: true}, want: models.DecisionOutcomeConsentRequired},
+		{name: "allowed", resp: &models.PolicyDecisionResponse{AppAuthorized: true}, want: models.DecisionOutcomeAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DecisionOutcomeOf(tt.resp, tt.err))
+		})
+	}
+}
+
+func TestDecisionLogService_RecordAndListDecisions(t *testing.T) {
+	db := setupTestDB(t)
+	policyService := NewPolicyMetadataService(db)
+	service := NewDecisionLogService(db)
+
+	_, err := policyService.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{FieldName: "person.fullName", Source: models.SourcePrimary, IsOwner: true, AccessControlType: models.AccessControlTypePublic},
+			{FieldName: "person.photo", Source: models.SourcePrimary, IsOwner: true, AccessControlType: models.AccessControlTypeRestricted},
+		},
+	})
+	require.NoError(t, err)
+	_, err = policyService.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-allowed",
+		Records: []models.AllowListUpdateRequestRecord{
+			{FieldName: "person.fullName", SchemaID: "schema-123"},
+			{FieldName: "person.photo", SchemaID: "schema-123"},
+		},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	decide := func(appID string, fields ...string) {
+		req := &models.PolicyDecisionRequest{ApplicationID: appID}
+		for _, f := range fields {
+			req.RequiredFields = append(req.RequiredFields, models.PolicyDecisionRequestRecord{FieldName: f, SchemaID: "schema-123"})
+		}
+		resp, err := policyService.GetPolicyDecision(req)
+		require.NoError(t, service.RecordDecision(req, resp, err, 1500*time.Microsecond))
+	}
+
+	decide("app-allowed", "person.fullName")
+	decide("app-denied", "person.photo")
+	decide("app-denied", "person.missing")
+
+	all, err := service.ListDecisions(&models.PolicyDecisionLogFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), all.Total)
+	assert.Equal(t, defaultDecisionLogLimit, all.Limit)
+
+	allowed, err := service.ListDecisions(&models.PolicyDecisionLogFilter{ApplicationID: "app-allowed"})
+	require.NoError(t, err)
+	require.Len(t, allowed.Records, 1)
+	assert.Equal(t, models.DecisionOutcomeAllowed, allowed.Records[0].Outcome)
+	assert.Equal(t, 1.5, allowed.Records[0].LatencyMs)
+	assert.NotNil(t, allowed.Records[0].PolicyVersion)
+	require.Len(t, allowed.Records[0].Fields, 1)
+	assert.Equal(t, models.FieldDecisionResultAuthorized, allowed.Records[0].Fields[0].Result)
+
+	denied, err := service.ListDecisions(&models.PolicyDecisionLogFilter{Outcome: string(models.DecisionOutcomeDenied)})
+	require.NoError(t, err)
+	require.Len(t, denied.Records, 1)
+	assert.Equal(t, models.FieldDecisionResultUnauthorized, denied.Records[0].Fields[0].Result)
+
+	errored, err := service.ListDecisions(&models.PolicyDecisionLogFilter{Outcome: string(models.DecisionOutcomeError)})
+	require.NoError(t, err)
+	require.Len(t, errored.Records, 1)
+	assert.NotNil(t, errored.Records[0].Error)
+
+	byField, err := service.ListDecisions(&models.PolicyDecisionLogFilter{FieldName: "person.photo", SchemaID: "schema-123"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), byField.Total)
+
+	future := time.Now().Add(time.Hour)
+	none, err := service.ListDecisions(&models.PolicyDecisionLogFilter{From: &future})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), none.Total)
+	assert.NotNil(t, none.Records)
+
+	slow, err := service.ListDecisions(&models.PolicyDecisionLogFilter{MinLatency: 1500 * time.Microsecond})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), slow.Total)
+	none, err = service.ListDecisions(&models.PolicyDecisionLogFilter{MinLatency: 2 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), none.Total)
+
+	paged, err := service.ListDecisions(&models.PolicyDecisionLogFilter{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), paged.Total)
+	assert.Len(t, paged.Records, 1)
+}
+
+func TestDecisionLogService_Start(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewDecisionLogService(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.Start(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		service.mu.Lock()
+		defer service.mu.Unlock()
+		return service.writing
+	}, time.Second, time.Millisecond)
+
+	req := &models.PolicyDecisionRequest{
+		ApplicationID:  "app-1",
+		RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "person.fullName", SchemaID: "schema-123"}},
+	}
+	resp := &models.PolicyDecisionResponse{AppAuthorized: true, PolicyVersion: "v1"}
+	for i := 0; i < 3*decisionLogBatchSize; i++ {
+		require.NoError(t, service.RecordDecision(req, resp, nil, time.Millisecond))
+	}
+
+	// Decisions still queued are written before Start returns
+	cancel()
+	<-done
+
+	decisions, err := service.ListDecisions(&models.PolicyDecisionLogFilter{ApplicationID: "app-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3*decisionLogBatchSize), decisions.Total)
+	require.NotEmpty(t, decisions.Records)
+	require.Len(t, decisions.Records[0].Fields, 1)
+	assert.Equal(t, models.FieldDecisionResultAuthorized, decisions.Records[0].Fields[0].Result)
+
+	// Once Start returns, decisions are written as they are recorded
+	require.NoError(t, service.RecordDecision(req, resp, nil, time.Millisecond))
+	decisions, err = service.ListDecisions(&models.PolicyDecisionLogFilter{ApplicationID: "app-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3*decisionLogBatchSize+1), decisions.Total)
+}
@@ -0,0 +1,15 @@
+package services
+
+import "errors"
+
+// ErrInvalidInput represents an input validation error
+var ErrInvalidInput = errors.New("invalid input")
+
+// ErrNotFound represents a lookup of a resource that does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict represents an operation that conflicts with the current state of a resource
+var ErrConflict = errors.New("conflict")
+
+// ErrForbidden represents an operation the caller is not allowed to perform
+var ErrForbidden = errors.New("forbidden")
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KillSwitchService manages the kill switches that block applications, providers and fields platform-wide.
+//
+// Decisions read the active switches from an in-memory deny list. The list is small, so it is simply
+// reloaded on every poll, and right after this service changes a switch; switches changed through
+// another replica take effect here within one poll interval.
+type KillSwitchService struct {
+	db *gorm.DB
+
+	mu       sync.RWMutex
+	denyList *models.DenyList
+	loaded   bool
+	// lastKnown is the last loaded deny list, kept when a reload fails, and lastLoadedAt when it was loaded
+	lastKnown    *models.DenyList
+	lastLoadedAt time.Time
+
+	refreshMu sync.Mutex
+}
+
+// NewKillSwitchService creates a new kill switch service. Until it is started, decisions read the
+// active switches from the database.
+func NewKillSwitchService(db *gorm.DB) *KillSwitchService {
+	return &KillSwitchService{db: db}
+}
+
+// Start loads the deny list and reloads it every interval until the context is cancelled.
+// An interval of zero or less disables the in-memory deny list.
+func (s *KillSwitchService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("Kill switch cache disabled")
+		return
+	}
+
+	if err := s.Refresh(); err != nil {
+		slog.Error("Failed to load kill switches", "error", err)
+	}
+
+	slog.Info("Kill switch cache started", "pollInterval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Kill switch cache stopped")
+			return
+		case <-ticker.C:
+			if err := s.Refresh(); err != nil {
+				slog.Error("Failed to refresh kill switches", "error", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads the deny list. If loading fails, the in-memory list is dropped so that decisions
+// read the database rather than miss a switch.
+func (s *KillSwitchService) Refresh() error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	denyList, err := s.loadDenyList()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.denyList = nil
+		s.loaded = false
+		return err
+	}
+	s.denyList = denyList
+	s.loaded = true
+	s.lastKnown = denyList
+	s.lastLoadedAt = time.Now()
+	return nil
+}
+
+// LastKnownDenyList returns the last deny list loaded into memory, even after a reload failed, and when
+// it was loaded. The last return value is false when none has been loaded yet.
+func (s *KillSwitchService) LastKnownDenyList() (*models.DenyList, time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastKnown, s.lastLoadedAt, s.lastKnown != nil
+}
+
+// DenyList returns the active kill switches, from memory when loaded
+func (s *KillSwitchService) DenyList() (*models.DenyList, error) {
+	s.mu.RLock()
+	denyList, loaded := s.denyList, s.loaded
+	s.mu.RUnlock()
+	if loaded {
+		return denyList, nil
+	}
+	return s.loadDenyList()
+}
+
+// SetKillSwitch activates or deactivates the kill switch of a target
+func (s *KillSwitchService) SetKillSwitch(req *models.KillSwitchRequest) (*models.KillSwitch, error) {
+	if err := req.TargetType.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	targetID := strings.TrimSpace(req.TargetID)
+	if targetID == "" {
+		return nil, fmt.Errorf("%w: targetId is required", ErrInvalidInput)
+	}
+	fieldName := strings.TrimSpace(req.FieldName)
+	if req.TargetType == models.KillSwitchTargetField && fieldName == "" {
+		return nil, fmt.Errorf("%w: fieldName is required for field targets", ErrInvalidInput)
+	}
+	if req.TargetType != models.KillSwitchTargetField && fieldName != "" {
+		return nil, fmt.Errorf("%w: fieldName is only allowed for field targets", ErrInvalidInput)
+	}
+	active := req.Active == nil || *req.Active
+
+	target := func() *gorm.DB {
+		return s.db.Where("target_type = ? AND target_id = ? AND field_name = ?", req.TargetType, targetID, fieldName)
+	}
+	now := time.Now()
+	if active {
+		ks := models.KillSwitch{
+			ID:         uuid.New(),
+			TargetType: req.TargetType,
+			TargetID:   targetID,
+			FieldName:  fieldName,
+			Active:     true,
+			Reason:     req.Reason,
+			UpdatedBy:  req.UpdatedBy,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		err := s.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "target_type"}, {Name: "target_id"}, {Name: "field_name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"active", "reason", "updated_by", "updated_at"}),
+		}).Create(&ks).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to activate kill switch: %w", err)
+		}
+	} else {
+		result := target().Model(&models.KillSwitch{}).Updates(map[string]interface{}{
+			"active":     false,
+			"reason":     req.Reason,
+			"updated_by": req.UpdatedBy,
+			"updated_at": now,
+		})
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to deactivate kill switch: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil, fmt.Errorf("%w: no kill switch for %s %s", ErrNotFound, req.TargetType, targetID)
+		}
+	}
+
+	var ks models.KillSwitch
+	if err := target().First(&ks).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch kill switch: %w", err)
+	}
+	slog.Warn("Kill switch changed",
+		"targetType", ks.TargetType, "targetId", ks.TargetID, "fieldName", ks.FieldName, "active", ks.Active)
+
+	s.mu.RLock()
+	loaded := s.loaded
+	s.mu.RUnlock()
+	if loaded {
+		if err := s.Refresh(); err != nil {
+			slog.Warn("Failed to refresh kill switches after write", "error", err)
+		}
+	}
+	return &ks, nil
+}
+
+// ListKillSwitches lists the kill switches, most recently changed first
+func (s *KillSwitchService) ListKillSwitches(includeInactive bool) (*models.KillSwitchListResponse, error) {
+	query := s.db.Order("updated_at DESC")
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+
+	records := []models.KillSwitch{}
+	if err := query.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list kill switches: %w", err)
+	}
+	return &models.KillSwitchListResponse{Records: records}, nil
+}
+
+// loadDenyList reads the active kill switches from the database
+func (s *KillSwitchService) loadDenyList() (*models.DenyList, error) {
+	var switches []models.KillSwitch
+	if err := s.db.Where("active = ?", true).Find(&switches).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch kill switches: %w", err)
+	}
+	return models.NewDenyList(switches), nil
+}
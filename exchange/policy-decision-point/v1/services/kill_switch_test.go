@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKillSwitchService_BlocksDecisions(t *testing.T) {
+	db := setupTestDB(t)
+	killSwitches := NewKillSwitchService(db)
+	service := NewPolicyMetadataService(db)
+	service.SetKillSwitches(killSwitches)
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-1": {ExpiresAt: time.Now().AddDate(0, 1, 0), UpdatedAt: time.Now()},
+		"app-2": {ExpiresAt: time.Now().AddDate(0, 1, 0), UpdatedAt: time.Now()},
+	}, "field1", "field2")
+	require.NoError(t, db.Exec("UPDATE policy_metadata SET provider_id = ? WHERE field_name = ?", "drp", "field2").Error)
+	require.NoError(t, killSwitches.Refresh())
+
+	decide := func(applicationID string) *models.PolicyDecisionResponse {
+		resp, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+			ApplicationID: applicationID,
+			RequiredFields: []models.PolicyDecisionRequestRecord{
+				{SchemaID: "schema-123", FieldName: "field1"},
+				{SchemaID: "schema-123", FieldName: "field2"},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	blocked := func(resp *models.PolicyDecisionResponse) map[string]models.KillSwitchTargetType {
+		fields := make(map[string]models.KillSwitchTargetType)
+		for _, f := range resp.BlockedFields {
+			fields[f.FieldName] = f.BlockedBy
+		}
+		return fields
+	}
+	set := func(req models.KillSwitchRequest) {
+		_, err := killSwitches.SetKillSwitch(&req)
+		require.NoError(t, err)
+	}
+	off := false
+
+	assert.True(t, decide("app-1").AppAuthorized)
+
+	t.Run("provider", func(t *testing.T) {
+		set(models.KillSwitchRequest{TargetType: models.KillSwitchTargetProvider, TargetID: "drp"})
+		resp := decide("app-1")
+		assert.False(t, resp.AppAuthorized)
+		assert.Equal(t, map[string]models.KillSwitchTargetType{"field2": models.KillSwitchTargetProvider}, blocked(resp))
+		assert.Equal(t, models.DecisionOutcomeBlocked, DecisionOutcomeOf(resp, nil))
+
+		set(models.KillSwitchRequest{TargetType: models.KillSwitchTargetProvider, TargetID: "drp", Active: &off})
+		assert.True(t, decide("app-1").AppAuthorized)
+	})
+
+	t.Run("field", func(t *testing.T) {
+		set(models.KillSwitchRequest{TargetType: models.KillSwitchTargetField, TargetID: "schema-123", FieldName: "field1"})
+		assert.Equal(t, map[string]models.KillSwitchTargetType{"field1": models.KillSwitchTargetField}, blocked(decide("app-2")))
+
+		set(models.KillSwitchRequest{TargetType: models.KillSwitchTargetField, TargetID: "schema-123", FieldName: "field1", Active: &off})
+	})
+
+	t.Run("application", func(t *testing.T) {
+		set(models.KillSwitchRequest{TargetType: models.KillSwitchTargetApplication, TargetID: "app-1", Reason: testhelpers.StringPtr("leaked credentials")})
+		assert.Len(t, blocked(decide("app-1")), 2)
+		assert.True(t, decide("app-2").AppAuthorized)
+
+		// A blocked application is denied even for fields without policy metadata
+		resp, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+			ApplicationID:  "app-1",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{SchemaID: "schema-123", FieldName: "unknown"}},
+		})
+		require.NoError(t, err)
+		assert.False(t, resp.AppAuthorized)
+	})
+
+	t.Run("other replicas see changes on refresh", func(t *testing.T) {
+		replica := NewKillSwitchService(db)
+		require.NoError(t, replica.Refresh())
+		denyList, err := replica.DenyList()
+		require.NoError(t, err)
+		assert.Equal(t, 1, denyList.Len())
+
+		set(models.KillSwitchRequest{TargetType: models.KillSwitchTargetApplication, TargetID: "app-1", Active: &off})
+		denyList, err = replica.DenyList()
+		require.NoError(t, err)
+		assert.Equal(t, 1, denyList.Len(), "stale until the next poll")
+
+		require.NoError(t, replica.Refresh())
+		denyList, err = replica.DenyList()
+		require.NoError(t, err)
+		assert.Zero(t, denyList.Len())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		active, err := killSwitches.ListKillSwitches(false)
+		require.NoError(t, err)
+		assert.Empty(t, active.Records)
+
+		all, err := killSwitches.ListKillSwitches(true)
+		require.NoError(t, err)
+		require.Len(t, all.Records, 3)
+	})
+}
+
+func TestKillSwitchService_SetKillSwitch_Validation(t *testing.T) {
+	killSwitches := NewKillSwitchService(setupTestDB(t))
+	off := false
+
+	for name, req := range map[string]models.KillSwitchRequest{
+		"unknown target type":        {TargetType: "schema", TargetID: "schema-123"},
+		"missing target":             {TargetType: models.KillSwitchTargetApplication, TargetID: " "},
+		"field without name":         {TargetType: models.KillSwitchTargetField, TargetID: "schema-123"},
+		"field name for application": {TargetType: models.KillSwitchTargetApplication, TargetID: "app-1", FieldName: "field1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := killSwitches.SetKillSwitch(&req)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+		})
+	}
+
+	_, err := killSwitches.SetKillSwitch(&models.KillSwitchRequest{
+		TargetType: models.KillSwitchTargetApplication, TargetID: "app-1", Active: &off,
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"gorm.io/gorm"
+)
+
+// LoadLegacyPolicies reads the policies table of the retired policy-governance service from db
+func LoadLegacyPolicies(db *gorm.DB) ([]models.LegacyPolicy, error) {
+	var policies []models.LegacyPolicy
+	if err := db.Order("subgraph ASC").Order("type ASC").Order("field ASC").Order("consumer_id ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to read legacy policies: %w", err)
+	}
+	return policies, nil
+}
+
+// legacyField collects the legacy grants of one PDP field
+type legacyField struct {
+	schemaID  string
+	fieldName string
+	// grants holds the grant of each consumer named for the field
+	grants map[string]models.LegacyGrant
+}
+
+// consentRequired reports whether any consumer was only allowed the field with citizen consent
+func (f *legacyField) consentRequired() bool {
+	for _, grant := range f.grants {
+		if grant.Allowed && grant.ConsentRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedConsumers lists the consumers the field is released to, sorted
+func (f *legacyField) allowedConsumers() []string {
+	var consumers []string
+	for consumerID, grant := range f.grants {
+		if grant.Allowed {
+			consumers = append(consumers, consumerID)
+		}
+	}
+	sort.Strings(consumers)
+	return consumers
+}
+
+// MigrateLegacyPolicies imports the policies of the retired policy-governance service. Each legacy
+// field becomes a restricted field whose allow list holds the consumers it was allowed to, and which
+// requires citizen consent if any consumer needed it. Fields the PDP already has keep their policy and
+// only gain the missing consumers. Invalid rows stop the whole migration, and nothing is written on a
+// dry run; otherwise the result is read back and checked against the legacy grants.
+func (s *PolicyMetadataService) MigrateLegacyPolicies(policies []models.LegacyPolicy, opts models.LegacyMigrationOptions) (*models.LegacyMigrationReport, error) {
+	tenantID := tenantOrDefault(opts.TenantID)
+	report := &models.LegacyMigrationReport{
+		Rows:     len(policies),
+		Fields:   []models.LegacyMigrationField{},
+		Problems: []models.LegacyMigrationProblem{},
+	}
+	now := time.Now()
+	expiresAt, err := opts.GrantDuration.ExpiresAtFrom(now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	fields := groupLegacyPolicies(policies, opts.SchemaIDs, report)
+	if report.HasErrors() || len(fields) == 0 {
+		return report, nil
+	}
+
+	schemaIDs := make([]string, 0, len(fields))
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if !seen[field.schemaID] {
+			seen[field.schemaID] = true
+			schemaIDs = append(schemaIDs, field.schemaID)
+		}
+	}
+	var existingRecords []models.PolicyMetadata
+	if err := s.db.Where("schema_id IN ?", schemaIDs).Find(&existingRecords).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
+	}
+	existingMap := make(map[string]*models.PolicyMetadata, len(existingRecords))
+	for i := range existingRecords {
+		pm := &existingRecords[i]
+		existingMap[pm.SchemaID+":"+pm.FieldName] = pm
+	}
+
+	var newRecords, updatedRecords []models.PolicyMetadata
+	for _, field := range fields {
+		consentRequired := field.consentRequired()
+		consumers := field.allowedConsumers()
+		migrated := models.LegacyMigrationField{SchemaID: field.schemaID, FieldName: field.fieldName, ConsentRequired: consentRequired}
+
+		existing, exists := existingMap[field.schemaID+":"+field.fieldName]
+		if !exists {
+			pm := models.PolicyMetadata{
+				ID:                uuid.New(),
+				TenantID:          tenantID,
+				ProviderID:        opts.ProviderID,
+				SchemaID:          field.schemaID,
+				FieldName:         field.fieldName,
+				Source:            models.SourcePrimary,
+				IsOwner:           !consentRequired,
+				AccessControlType: models.AccessControlTypeRestricted,
+				Classification:    models.ClassificationInternal,
+				AllowList:         make(models.AllowList),
+				Conditions:        models.PolicyConditions{},
+				CreatedAt:         now,
+				UpdatedAt:         now,
+			}
+			if consentRequired {
+				owner := models.OwnerCitizen
+				pm.Owner = &owner
+				pm.Classification = models.ClassificationPersonal
+			}
+			for _, consumerID := range consumers {
+				pm.AllowList[consumerID] = models.AllowListEntry{ExpiresAt: expiresAt, UpdatedAt: now}
+			}
+			newRecords = append(newRecords, pm)
+			migrated.Action = models.LegacyMigrationActionCreate
+			migrated.GrantedConsumers = consumers
+			report.Created++
+			report.Grants += len(consumers)
+			report.Fields = append(report.Fields, migrated)
+			continue
+		}
+
+		if !existing.OwnedBy(tenantID, opts.ProviderID) {
+			report.Problems = append(report.Problems, models.LegacyMigrationProblem{
+				Severity: models.LegacyMigrationSeverityError, SchemaID: field.schemaID, FieldName: field.fieldName,
+				Message: fmt.Sprintf("field belongs to namespace %s", existing.Namespace()),
+			})
+			continue
+		}
+		if existingConsent := !existing.IsOwner && existing.AccessControlType != models.AccessControlTypePublic; existingConsent != consentRequired {
+			report.Problems = append(report.Problems, models.LegacyMigrationProblem{
+				Severity: models.LegacyMigrationSeverityWarning, SchemaID: field.schemaID, FieldName: field.fieldName,
+				Message: fmt.Sprintf("legacy policy has consent required %t but the PDP policy, which is kept, has %t", consentRequired, existingConsent),
+			})
+		}
+
+		pm := *existing
+		pm.AllowList = make(models.AllowList, len(existing.AllowList)+len(consumers))
+		for applicationID, entry := range existing.AllowList {
+			pm.AllowList[applicationID] = entry
+		}
+		for _, consumerID := range consumers {
+			if entry, ok := pm.AllowList[consumerID]; ok && !entry.IsExpired(now) {
+				continue
+			}
+			pm.AllowList[consumerID] = models.AllowListEntry{ExpiresAt: expiresAt, UpdatedAt: now}
+			migrated.GrantedConsumers = append(migrated.GrantedConsumers, consumerID)
+		}
+		for consumerID, grant := range field.grants {
+			if _, ok := existing.AllowList[consumerID]; ok && !grant.Allowed {
+				report.Problems = append(report.Problems, models.LegacyMigrationProblem{
+					Severity: models.LegacyMigrationSeverityWarning, ConsumerID: consumerID, SchemaID: field.schemaID, FieldName: field.fieldName,
+					Message: "consumer was denied by the legacy policy but is in the PDP allow list, which is kept",
+				})
+			}
+		}
+
+		if len(migrated.GrantedConsumers) == 0 {
+			migrated.Action = models.LegacyMigrationActionUnchanged
+			report.Unchanged++
+		} else {
+			pm.UpdatedAt = now
+			updatedRecords = append(updatedRecords, pm)
+			migrated.Action = models.LegacyMigrationActionMerge
+			report.Merged++
+			report.Grants += len(migrated.GrantedConsumers)
+		}
+		report.Fields = append(report.Fields, migrated)
+	}
+	sortLegacyProblems(report.Problems)
+
+	if report.HasErrors() || opts.DryRun {
+		return report, nil
+	}
+
+	if len(newRecords) > 0 || len(updatedRecords) > 0 {
+		tx := s.db.Begin()
+		if tx.Error != nil {
+			return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+			}
+		}()
+		if len(newRecords) > 0 {
+			if err := tx.Create(&newRecords).Error; err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to create policy metadata records: %w", err)
+			}
+		}
+		// Only the allow lists of existing fields change
+		for i := range updatedRecords {
+			pm := &updatedRecords[i]
+			if err := tx.Model(pm).Select("allow_list", "updated_at").Updates(map[string]interface{}{
+				"allow_list": pm.AllowList,
+				"updated_at": pm.UpdatedAt,
+			}).Error; err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to update allow list of field %s: %w", pm.FieldName, err)
+			}
+		}
+		if err := tx.Commit().Error; err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		s.refreshCache()
+		report.Applied = true
+	}
+
+	if err := s.verifyLegacyMigration(fields, schemaIDs, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// groupLegacyPolicies validates the legacy rows and groups their grants by PDP field, in field order.
+// Invalid rows are reported as errors; a consumer named more than once for a field keeps its most
+// restrictive grant.
+func groupLegacyPolicies(policies []models.LegacyPolicy, schemaIDs map[string]string, report *models.LegacyMigrationReport) []*legacyField {
+	fields := make(map[string]*legacyField)
+	var ordered []*legacyField
+	for i, policy := range policies {
+		consumerID := strings.TrimSpace(policy.ConsumerID)
+		subgraph := strings.TrimSpace(policy.Subgraph)
+		fieldName := policy.FieldName()
+		rowError := func(message string) {
+			report.Problems = append(report.Problems, models.LegacyMigrationProblem{
+				Severity: models.LegacyMigrationSeverityError, ConsumerID: consumerID, FieldName: fieldName,
+				Message: fmt.Sprintf("row %d: %s", i+1, message),
+			})
+		}
+
+		if consumerID == "" || subgraph == "" || strings.TrimSpace(policy.Field) == "" {
+			rowError("consumer_id, subgraph and field are required")
+			continue
+		}
+		grant, ok := models.ParseLegacyClassification(policy.Classification)
+		if !ok {
+			rowError(fmt.Sprintf("unknown classification %q", policy.Classification))
+			continue
+		}
+		schemaID := subgraph
+		if len(schemaIDs) > 0 {
+			if schemaID, ok = schemaIDs[subgraph]; !ok {
+				rowError(fmt.Sprintf("no schema ID is mapped for subgraph %q", subgraph))
+				continue
+			}
+		}
+
+		key := schemaID + ":" + fieldName
+		field, ok := fields[key]
+		if !ok {
+			field = &legacyField{schemaID: schemaID, fieldName: fieldName, grants: make(map[string]models.LegacyGrant)}
+			fields[key] = field
+			ordered = append(ordered, field)
+		}
+		if previous, duplicate := field.grants[consumerID]; duplicate {
+			if previous != grant {
+				report.Problems = append(report.Problems, models.LegacyMigrationProblem{
+					Severity: models.LegacyMigrationSeverityWarning, ConsumerID: consumerID, SchemaID: schemaID, FieldName: fieldName,
+					Message: "consumer has conflicting legacy policies for the field; the most restrictive is kept",
+				})
+			}
+			grant = models.LegacyGrant{
+				Allowed:         previous.Allowed && grant.Allowed,
+				ConsentRequired: previous.ConsentRequired || grant.ConsentRequired,
+			}
+		}
+		field.grants[consumerID] = grant
+	}
+
+	// Consent is a property of the field in the PDP, so consumers allowed without it will need it too
+	for _, field := range ordered {
+		if !field.consentRequired() {
+			continue
+		}
+		for _, consumerID := range field.allowedConsumers() {
+			if !field.grants[consumerID].ConsentRequired {
+				report.Problems = append(report.Problems, models.LegacyMigrationProblem{
+					Severity: models.LegacyMigrationSeverityWarning, ConsumerID: consumerID, SchemaID: field.schemaID, FieldName: field.fieldName,
+					Message: "consumer was allowed the field without citizen consent, but other consumers needed it; consent is now required for every consumer",
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].schemaID != ordered[j].schemaID {
+			return ordered[i].schemaID < ordered[j].schemaID
+		}
+		return ordered[i].fieldName < ordered[j].fieldName
+	})
+	sortLegacyProblems(report.Problems)
+	return ordered
+}
+
+// verifyLegacyMigration reads the migrated fields back and checks that every allowed legacy consumer
+// can access its fields and that created fields require consent as the legacy policy did
+func (s *PolicyMetadataService) verifyLegacyMigration(fields []*legacyField, schemaIDs []string, report *models.LegacyMigrationReport) error {
+	var records []models.PolicyMetadata
+	if err := s.db.Where("schema_id IN ?", schemaIDs).Find(&records).Error; err != nil {
+		return fmt.Errorf("failed to verify migrated policy metadata: %w", err)
+	}
+	stored := make(map[string]*models.PolicyMetadata, len(records))
+	for i := range records {
+		stored[records[i].SchemaID+":"+records[i].FieldName] = &records[i]
+	}
+	created := make(map[string]bool)
+	for _, field := range report.Fields {
+		if field.Action == models.LegacyMigrationActionCreate {
+			created[field.SchemaID+":"+field.FieldName] = true
+		}
+	}
+
+	now := time.Now()
+	verified := true
+	mismatch := func(consumerID string, field *legacyField, message string) {
+		verified = false
+		report.Problems = append(report.Problems, models.LegacyMigrationProblem{
+			Severity: models.LegacyMigrationSeverityError, ConsumerID: consumerID, SchemaID: field.schemaID, FieldName: field.fieldName,
+			Message: "verification failed: " + message,
+		})
+	}
+	for _, field := range fields {
+		key := field.schemaID + ":" + field.fieldName
+		pm, ok := stored[key]
+		if !ok {
+			mismatch("", field, "field is missing")
+			continue
+		}
+		if created[key] && pm.IsOwner == field.consentRequired() {
+			mismatch("", field, fmt.Sprintf("field has isOwner %t", pm.IsOwner))
+		}
+		for _, consumerID := range field.allowedConsumers() {
+			if entry, ok := pm.AllowList[consumerID]; !ok || entry.IsExpired(now) {
+				mismatch(consumerID, field, "consumer is not in the allow list")
+			}
+		}
+		if created[key] {
+			for consumerID, grant := range field.grants {
+				if _, ok := pm.AllowList[consumerID]; ok && !grant.Allowed {
+					mismatch(consumerID, field, "denied consumer is in the allow list")
+				}
+			}
+		}
+	}
+	sortLegacyProblems(report.Problems)
+	report.Verified = verified
+	return nil
+}
+
+// sortLegacyProblems orders problems by field and consumer, errors first
+func sortLegacyProblems(problems []models.LegacyMigrationProblem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.Severity != b.Severity {
+			return a.Severity == models.LegacyMigrationSeverityError
+		}
+		if a.SchemaID != b.SchemaID {
+			return a.SchemaID < b.SchemaID
+		}
+		if a.FieldName != b.FieldName {
+			return a.FieldName < b.FieldName
+		}
+		return a.ConsumerID < b.ConsumerID
+	})
+}
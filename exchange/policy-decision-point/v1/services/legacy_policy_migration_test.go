@@ -0,0 +1,151 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLegacyPolicies(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE policies (id INTEGER PRIMARY KEY, consumer_id TEXT, subgraph TEXT, type TEXT, field TEXT, classification TEXT)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO policies (consumer_id, subgraph, type, field, classification) VALUES
+		('app-2', 'drp', 'PersonData', 'fullName', 'ALLOW'),
+		('app-1', 'drp', 'PersonData', 'fullName', 'DENIED')`).Error)
+
+	policies, err := LoadLegacyPolicies(db)
+	require.NoError(t, err)
+	assert.Equal(t, []models.LegacyPolicy{
+		{ConsumerID: "app-1", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "DENIED"},
+		{ConsumerID: "app-2", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "ALLOW"},
+	}, policies)
+}
+
+func TestPolicyMetadataService_MigrateLegacyPolicies(t *testing.T) {
+	options := models.LegacyMigrationOptions{
+		SchemaIDs:     map[string]string{"drp": "schema-drp"},
+		GrantDuration: models.GrantDurationTypeOneYear,
+	}
+
+	t.Run("Legacy grants become allow lists and consent flags", func(t *testing.T) {
+		db := setupTestDB(t)
+		service := NewPolicyMetadataService(db)
+
+		report, err := service.MigrateLegacyPolicies([]models.LegacyPolicy{
+			{ConsumerID: "app-1", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "ALLOW"},
+			{ConsumerID: "app-2", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "allow_provider_consent"},
+			{ConsumerID: "app-3", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "DENIED"},
+			{ConsumerID: "app-1", Subgraph: "drp", Type: "PersonData", Field: "address", Classification: "ALLOW_CITIZEN_CONSENT"},
+			{ConsumerID: "app-2", Subgraph: "drp", Type: "PersonData", Field: "address", Classification: "ALLOW"},
+		}, options)
+		require.NoError(t, err)
+		assert.True(t, report.Applied)
+		assert.True(t, report.Verified)
+		assert.Equal(t, 5, report.Rows)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 4, report.Grants)
+		require.Len(t, report.Fields, 2)
+		assert.Equal(t, "PersonData.address", report.Fields[0].FieldName)
+		assert.True(t, report.Fields[0].ConsentRequired)
+		assert.Equal(t, []string{"app-1", "app-2"}, report.Fields[1].GrantedConsumers)
+
+		// app-2 was allowed the address without consent, which the PDP cannot express per consumer
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, models.LegacyMigrationSeverityWarning, report.Problems[0].Severity)
+		assert.Equal(t, "app-2", report.Problems[0].ConsumerID)
+
+		var fullName models.PolicyMetadata
+		require.NoError(t, db.Where("schema_id = ? AND field_name = ?", "schema-drp", "PersonData.fullName").First(&fullName).Error)
+		assert.True(t, fullName.IsOwner)
+		assert.Nil(t, fullName.Owner)
+		assert.Equal(t, models.AccessControlTypeRestricted, fullName.AccessControlType)
+		assert.Contains(t, fullName.AllowList, "app-1")
+		assert.Contains(t, fullName.AllowList, "app-2")
+		assert.NotContains(t, fullName.AllowList, "app-3")
+		assert.WithinDuration(t, time.Now().AddDate(1, 0, 0), fullName.AllowList["app-1"].ExpiresAt, time.Minute)
+
+		var address models.PolicyMetadata
+		require.NoError(t, db.Where("schema_id = ? AND field_name = ?", "schema-drp", "PersonData.address").First(&address).Error)
+		assert.False(t, address.IsOwner)
+		assert.Equal(t, models.OwnerCitizen, *address.Owner)
+		assert.Equal(t, models.ClassificationPersonal, address.Classification)
+	})
+
+	t.Run("Existing fields keep their policy and gain consumers", func(t *testing.T) {
+		db := setupTestDB(t)
+		service := NewPolicyMetadataService(db)
+		now := time.Now()
+		existing := models.PolicyMetadata{
+			ID: uuid.New(), TenantID: models.DefaultTenantID, SchemaID: "schema-drp", FieldName: "PersonData.fullName",
+			Source: models.SourcePrimary, Owner: testhelpers.OwnerPtr(models.OwnerCitizen), AccessControlType: models.AccessControlTypeRestricted,
+			Classification: models.ClassificationPersonal, Conditions: models.PolicyConditions{},
+			AllowList: models.AllowList{"app-1": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now}},
+		}
+		require.NoError(t, db.Create(&existing).Error)
+
+		policies := []models.LegacyPolicy{
+			{ConsumerID: "app-1", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "ALLOW"},
+			{ConsumerID: "app-2", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "ALLOW"},
+		}
+		dryRun := options
+		dryRun.DryRun = true
+		report, err := service.MigrateLegacyPolicies(policies, dryRun)
+		require.NoError(t, err)
+		assert.False(t, report.Applied)
+		assert.Equal(t, 1, report.Merged)
+
+		report, err = service.MigrateLegacyPolicies(policies, options)
+		require.NoError(t, err)
+		assert.True(t, report.Applied)
+		assert.True(t, report.Verified)
+		require.Len(t, report.Fields, 1)
+		assert.Equal(t, models.LegacyMigrationActionMerge, report.Fields[0].Action)
+		assert.Equal(t, []string{"app-2"}, report.Fields[0].GrantedConsumers)
+		require.Len(t, report.Problems, 1)
+		assert.Contains(t, report.Problems[0].Message, "consent required")
+
+		var stored models.PolicyMetadata
+		require.NoError(t, db.First(&stored, "id = ?", existing.ID).Error)
+		assert.False(t, stored.IsOwner)
+		assert.Equal(t, existing.AllowList["app-1"].ExpiresAt.Unix(), stored.AllowList["app-1"].ExpiresAt.Unix())
+		assert.Contains(t, stored.AllowList, "app-2")
+
+		// A second run finds nothing left to grant
+		report, err = service.MigrateLegacyPolicies(policies, options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Unchanged)
+		assert.False(t, report.Applied)
+		assert.True(t, report.Verified)
+	})
+
+	t.Run("Invalid rows stop the migration", func(t *testing.T) {
+		db := setupTestDB(t)
+		service := NewPolicyMetadataService(db)
+
+		report, err := service.MigrateLegacyPolicies([]models.LegacyPolicy{
+			{ConsumerID: "app-1", Subgraph: "drp", Type: "PersonData", Field: "fullName", Classification: "ALLOW"},
+			{ConsumerID: "app-1", Subgraph: "drp", Type: "PersonData", Field: "nic", Classification: "MAYBE"},
+			{ConsumerID: "app-1", Subgraph: "dmt", Type: "Vehicle", Field: "plate", Classification: "ALLOW"},
+			{ConsumerID: "", Subgraph: "drp", Type: "PersonData", Field: "address", Classification: "ALLOW"},
+		}, options)
+		require.NoError(t, err)
+		assert.True(t, report.HasErrors())
+		assert.False(t, report.Applied)
+		assert.Len(t, report.Problems, 3)
+
+		var count int64
+		require.NoError(t, db.Model(&models.PolicyMetadata{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Invalid grant durations are rejected", func(t *testing.T) {
+		service := NewPolicyMetadataService(setupTestDB(t))
+		_, err := service.MigrateLegacyPolicies(nil, models.LegacyMigrationOptions{GrantDuration: "1d"})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"gorm.io/gorm"
+)
+
+// PolicyCache keeps an in-memory copy of policy_metadata indexed by schema ID and field name
+// so that decisions do not hit the database on every request.
+//
+// The cache is refreshed when this service writes policy metadata and by polling a cheap
+// version fingerprint (row count + latest updated_at), which picks up writes made by other
+// replicas or directly in the database. Cached records are shared and must be treated as read-only.
+//
+// The last loaded records are kept as a last-known-good snapshot when the cache is invalidated, so
+// that decisions can fall back to them while the database is unavailable.
+type PolicyCache struct {
+	db *gorm.DB
+
+	mu      sync.RWMutex
+	schemas map[string]map[string]models.PolicyMetadata
+	version string
+	loaded  bool
+	entries int
+	// lastRefreshedAt is the time of the last successful refresh
+	lastRefreshedAt time.Time
+	lastError       string
+	// snapshot is the last loaded copy of schemas, kept across invalidations
+	snapshot map[string]map[string]models.PolicyMetadata
+	// verifiedAt is when the snapshot was last confirmed current, by a load or an unchanged version poll
+	verifiedAt time.Time
+
+	refreshMu     sync.Mutex
+	hits          atomic.Int64
+	misses        atomic.Int64
+	refreshes     atomic.Int64
+	refreshErrors atomic.Int64
+}
+
+// NewPolicyCache creates a new, empty policy cache. Lookups miss until the first refresh.
+func NewPolicyCache(db *gorm.DB) *PolicyCache {
+	return &PolicyCache{
+		db: db,
+	}
+}
+
+// Start loads the cache and polls for changes until the context is cancelled.
+// An interval of zero or less disables the cache, leaving decisions on the database.
+func (c *PolicyCache) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("Policy cache disabled")
+		return
+	}
+
+	if err := c.Refresh(); err != nil {
+		slog.Error("Failed to load policy cache", "error", err)
+	}
+
+	slog.Info("Policy cache started", "pollInterval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Policy cache stopped")
+			return
+		case <-ticker.C:
+			if _, err := c.RefreshIfChanged(); err != nil {
+				slog.Error("Failed to refresh policy cache", "error", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads all policy metadata into the cache. If loading fails the cache is
+// invalidated so that lookups fall back to the database instead of serving stale data.
+func (c *PolicyCache) Refresh() error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	version, err := c.currentVersion()
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.load(version)
+}
+
+// RefreshIfChanged reloads the cache only when the stored version differs from the database.
+// It reports whether a reload happened.
+func (c *PolicyCache) RefreshIfChanged() (bool, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	version, err := c.currentVersion()
+	if err != nil {
+		return false, c.fail(err)
+	}
+
+	c.mu.Lock()
+	unchanged := c.loaded && c.version == version
+	if unchanged {
+		c.verifiedAt = time.Now()
+	}
+	c.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+
+	if err := c.load(version); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Invalidate drops the cached records so that lookups fall back to the database until the next refresh.
+// The last-known-good snapshot is kept.
+func (c *PolicyCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemas = nil
+	c.entries = 0
+	c.loaded = false
+	c.version = ""
+}
+
+// Lookup returns the cached policy metadata for the given schemas.
+// The second return value is false when the cache is not loaded and the caller must query the database.
+func (c *PolicyCache) Lookup(schemaIDs []string) ([]models.PolicyMetadata, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.loaded {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	var records []models.PolicyMetadata
+	for _, schemaID := range schemaIDs {
+		for _, pm := range c.schemas[schemaID] {
+			records = append(records, pm)
+		}
+	}
+	c.hits.Add(1)
+	return records, true
+}
+
+// Snapshot returns the last loaded policy metadata for the given schemas, even while the cache is
+// invalidated, and when it was last confirmed current. The last return value is false when nothing
+// has been loaded yet.
+func (c *PolicyCache) Snapshot(schemaIDs []string) ([]models.PolicyMetadata, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.snapshot == nil {
+		return nil, time.Time{}, false
+	}
+	var records []models.PolicyMetadata
+	for _, schemaID := range schemaIDs {
+		for _, pm := range c.snapshot[schemaID] {
+			records = append(records, pm)
+		}
+	}
+	return records, c.verifiedAt, true
+}
+
+// VerifiedAt returns when the last-known-good snapshot was last confirmed current, or the zero time
+// when nothing has been loaded yet
+func (c *PolicyCache) VerifiedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.verifiedAt
+}
+
+// Stats returns a snapshot of the cache statistics
+func (c *PolicyCache) Stats() models.PolicyCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := models.PolicyCacheStats{
+		Loaded:        c.loaded,
+		Version:       c.version,
+		Schemas:       len(c.schemas),
+		Entries:       c.entries,
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Refreshes:     c.refreshes.Load(),
+		RefreshErrors: c.refreshErrors.Load(),
+		LastError:     c.lastError,
+	}
+	if !c.lastRefreshedAt.IsZero() {
+		stats.LastRefreshedAt = c.lastRefreshedAt.UTC().Format(time.RFC3339)
+	}
+	if !c.verifiedAt.IsZero() {
+		stats.LastVerifiedAt = c.verifiedAt.UTC().Format(time.RFC3339)
+	}
+	return stats
+}
+
+// AllowListSizes returns the number of allow list entries per schema, or nil while the cache is not loaded
+func (c *PolicyCache) AllowListSizes() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.loaded {
+		return nil
+	}
+	sizes := make(map[string]int, len(c.schemas))
+	for schemaID, fields := range c.schemas {
+		for _, pm := range fields {
+			sizes[schemaID] += len(pm.AllowList)
+		}
+	}
+	return sizes
+}
+
+// currentVersion computes the version fingerprint of the policy_metadata table
+func (c *PolicyCache) currentVersion() (string, error) {
+	var count int64
+	if err := c.db.Model(&models.PolicyMetadata{}).Count(&count).Error; err != nil {
+		return "", fmt.Errorf("failed to count policy metadata records: %w", err)
+	}
+
+	var latest []models.PolicyMetadata
+	if err := c.db.Select("updated_at").Order("updated_at DESC").Limit(1).Find(&latest).Error; err != nil {
+		return "", fmt.Errorf("failed to fetch latest policy metadata update: %w", err)
+	}
+
+	var latestUpdate time.Time
+	if len(latest) > 0 {
+		latestUpdate = latest[0].UpdatedAt
+	}
+	return fmt.Sprintf("%d:%s", count, latestUpdate.UTC().Format(time.RFC3339Nano)), nil
+}
+
+// load replaces the cached records with the current contents of policy_metadata
+func (c *PolicyCache) load(version string) error {
+	var records []models.PolicyMetadata
+	if err := c.db.Find(&records).Error; err != nil {
+		return c.fail(fmt.Errorf("failed to fetch policy metadata records: %w", err))
+	}
+
+	schemas := make(map[string]map[string]models.PolicyMetadata)
+	for _, pm := range records {
+		fields, exists := schemas[pm.SchemaID]
+		if !exists {
+			fields = make(map[string]models.PolicyMetadata)
+			schemas[pm.SchemaID] = fields
+		}
+		fields[pm.FieldName] = pm
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	c.schemas = schemas
+	c.snapshot = schemas
+	c.entries = len(records)
+	c.version = version
+	c.loaded = true
+	c.lastRefreshedAt = now
+	c.verifiedAt = now
+	c.lastError = ""
+	c.refreshes.Add(1)
+	return nil
+}
+
+// fail invalidates the cache and records the refresh error
+func (c *PolicyCache) fail(err error) error {
+	c.Invalidate()
+
+	c.mu.Lock()
+	c.lastError = err.Error()
+	c.mu.Unlock()
+	c.refreshErrors.Add(1)
+	return err
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCache_LookupBeforeLoad(t *testing.T) {
+	db := setupTestDB(t)
+	cache := NewPolicyCache(db)
+
+	records, ok := cache.Lookup([]string{"schema-123"})
+	assert.False(t, ok)
+	assert.Nil(t, records)
+
+	stats := cache.Stats()
+	assert.False(t, stats.Loaded)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Nil(t, cache.AllowListSizes())
+}
+
+func TestPolicyCache_RefreshAndLookup(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	now := time.Now()
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-1": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now},
+	}, "field1", "field2")
+
+	cache := NewPolicyCache(db)
+	require.NoError(t, cache.Refresh())
+
+	records, ok := cache.Lookup([]string{"schema-123", "unknown-schema"})
+	assert.True(t, ok)
+	assert.Len(t, records, 2)
+
+	stats := cache.Stats()
+	assert.True(t, stats.Loaded)
+	assert.Equal(t, 1, stats.Schemas)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.Refreshes)
+	assert.NotEmpty(t, stats.Version)
+	assert.NotEmpty(t, stats.LastRefreshedAt)
+	assert.Equal(t, map[string]int{"schema-123": 2}, cache.AllowListSizes())
+}
+
+func TestPolicyCache_RefreshIfChanged(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	seedAllowListFields(t, db, service, models.AllowList{}, "field1")
+
+	cache := NewPolicyCache(db)
+	changed, err := cache.RefreshIfChanged()
+	require.NoError(t, err)
+	assert.True(t, changed, "first poll loads the cache")
+
+	changed, err = cache.RefreshIfChanged()
+	require.NoError(t, err)
+	assert.False(t, changed, "unchanged table does not reload")
+
+	// A write made outside the service is picked up by the version poll
+	var pm models.PolicyMetadata
+	require.NoError(t, db.Where("field_name = ?", "field1").First(&pm).Error)
+	pm.AllowList = models.AllowList{"app-1": {ExpiresAt: time.Now().AddDate(0, 1, 0), UpdatedAt: time.Now()}}
+	pm.UpdatedAt = time.Now().Add(time.Second)
+	require.NoError(t, db.Save(&pm).Error)
+
+	changed, err = cache.RefreshIfChanged()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	records, ok := cache.Lookup([]string{"schema-123"})
+	require.True(t, ok)
+	require.Len(t, records, 1)
+	assert.Contains(t, records[0].AllowList, "app-1")
+}
+
+func TestPolicyCache_StartDisabled(t *testing.T) {
+	db := setupTestDB(t)
+	cache := NewPolicyCache(db)
+
+	// Returns immediately without loading
+	cache.Start(context.Background(), 0)
+	assert.False(t, cache.Stats().Loaded)
+}
+
+func TestPolicyMetadataService_DecisionUsesCache(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	cache := NewPolicyCache(db)
+	service.SetCache(cache)
+
+	seedAllowListFields(t, db, service, models.AllowList{}, "field1")
+	require.NoError(t, cache.Refresh())
+
+	// Writes through the service refresh the cache immediately
+	_, err := service.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records:       []models.AllowListUpdateRequestRecord{{FieldName: "field1", SchemaID: "schema-123"}},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	resp, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+		ApplicationID:  "app-1",
+		RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "field1", SchemaID: "schema-123"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.AppAuthorized)
+	assert.Equal(t, int64(1), cache.Stats().Hits)
+}
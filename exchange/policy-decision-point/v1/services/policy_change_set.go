@@ -0,0 +1,463 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"gorm.io/gorm"
+)
+
+// CreatePolicyChangeSet proposes a policy document for review. The document is validated and diffed
+// against the current policy, but applied only when another admin approves the change set.
+func (s *PolicyMetadataService) CreatePolicyChangeSet(req *models.PolicyChangeSetCreateRequest) (*models.PolicyChangeSetResponse, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+	tenantID := tenantOrDefault(req.TenantID)
+
+	plan, err := planPolicyImport(s.db, tenantID, &req.Document)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.response.Changes) == 0 {
+		return nil, fmt.Errorf("%w: the document does not change the policy", ErrInvalidInput)
+	}
+
+	changeSet := newPolicyChangeSet(tenantID, models.PolicyChangeSetKindDocument, title, req.ProposedBy)
+	changeSet.Description = req.Description
+	changeSet.Document = req.Document
+	return proposePolicyChangeSet(s.db, changeSet, plan)
+}
+
+// ProposePolicyMetadata proposes the policy metadata of a schema for review, the form CreatePolicyMetadata
+// takes when changes require approval. It returns nil if the metadata does not change the policy.
+func (s *PolicyMetadataService) ProposePolicyMetadata(req *models.PolicyMetadataCreateRequest, proposedBy string) (*models.PolicyChangeSetResponse, error) {
+	if err := validatePolicyMetadataRequest(req); err != nil {
+		return nil, err
+	}
+	tenantID := tenantOrDefault(req.TenantID)
+
+	plan, err := diffPolicyDocument(s.db, tenantID, metadataDocumentOf(req))
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.response.Changes) == 0 {
+		return nil, nil
+	}
+
+	changeSet := newPolicyChangeSet(tenantID, models.PolicyChangeSetKindMetadata,
+		fmt.Sprintf("Update the policy metadata of schema %s", req.SchemaID), proposedBy)
+	changeSet.Metadata = req
+	return proposePolicyChangeSet(s.db, changeSet, plan)
+}
+
+// ProposeAllowListUpdate proposes grants of fields to an application for review, the form UpdateAllowList
+// takes when changes require approval. The grant duration counts from the approval.
+func (s *PolicyMetadataService) ProposeAllowListUpdate(req *models.AllowListUpdateRequest, proposedBy string) (*models.PolicyChangeSetResponse, error) {
+	tenantID := tenantOrDefault(req.TenantID)
+	if err := validateAllowListUpdate(s.db, tenantID, req); err != nil {
+		return nil, err
+	}
+
+	changeSet := newPolicyChangeSet(tenantID, models.PolicyChangeSetKindAllowList,
+		fmt.Sprintf("Grant application %s access to %d fields", req.ApplicationID, len(req.Records)), proposedBy)
+	changeSet.AllowListUpdate = req
+	return proposePolicyChangeSet(s.db, changeSet, nil)
+}
+
+// ProposeSchemaLifecycleUpdate proposes a schema's lifecycle stage for review, the form UpdateSchemaLifecycle
+// takes when changes require approval
+func (s *PolicyMetadataService) ProposeSchemaLifecycleUpdate(req *models.SchemaLifecycleUpdateRequest, proposedBy string) (*models.PolicyChangeSetResponse, error) {
+	if err := validateSchemaLifecycleUpdate(s.db, req); err != nil {
+		return nil, err
+	}
+
+	changeSet := newPolicyChangeSet(tenantOrDefault(req.TenantID), models.PolicyChangeSetKindSchemaLifecycle,
+		fmt.Sprintf("Set schema %s to %s", req.SchemaID, req.Status), proposedBy)
+	changeSet.SchemaLifecycleUpdate = req
+	return proposePolicyChangeSet(s.db, changeSet, nil)
+}
+
+// newPolicyChangeSet creates a pending change set. Only document change sets carry a document.
+func newPolicyChangeSet(tenantID string, kind models.PolicyChangeSetKind, title, proposedBy string) *models.PolicyChangeSet {
+	now := time.Now()
+	return &models.PolicyChangeSet{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		Title:      title,
+		Kind:       kind,
+		Document:   models.PolicyDocument{Version: models.PolicyDocumentVersion, Schemas: []models.PolicyDocumentSchema{}},
+		Status:     models.PolicyChangeSetStatusPending,
+		ProposedBy: proposedBy,
+		Impact:     models.PolicyChangeImpacts{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// proposePolicyChangeSet stores a new change set, with the diff and impact of its plan if it changes the document
+func proposePolicyChangeSet(db *gorm.DB, changeSet *models.PolicyChangeSet, plan *policyImportPlan) (*models.PolicyChangeSetResponse, error) {
+	if changeSet.ProposedBy == "" {
+		return nil, fmt.Errorf("%w: the proposer is required", ErrInvalidInput)
+	}
+	if err := db.Create(changeSet).Error; err != nil {
+		return nil, fmt.Errorf("failed to create policy change set: %w", err)
+	}
+
+	slog.Info("Policy change set proposed", "changeSetId", changeSet.ID, "tenantId", changeSet.TenantID, "kind", changeSet.Kind,
+		"proposedBy", changeSet.ProposedBy)
+	if plan != nil {
+		changeSet.Diff = plan.response
+		changeSet.Impact = policyChangeImpact(plan, changeSet.CreatedAt)
+	}
+	response := changeSet.ToResponse()
+	return &response, nil
+}
+
+// planPolicyChangeSet diffs the document a change set results in against the policy stored in db. Change
+// sets that do not change the document have no plan.
+func planPolicyChangeSet(db *gorm.DB, changeSet *models.PolicyChangeSet) (*policyImportPlan, error) {
+	switch changeSet.Kind {
+	case models.PolicyChangeSetKindMetadata:
+		return diffPolicyDocument(db, changeSet.TenantID, metadataDocumentOf(changeSet.Metadata))
+	case models.PolicyChangeSetKindAllowList, models.PolicyChangeSetKindSchemaLifecycle:
+		return nil, nil
+	default:
+		return planPolicyImport(db, changeSet.TenantID, &changeSet.Document)
+	}
+}
+
+// applyPolicyChangeSet applies an approved change set within tx, the way the write it proposes is applied
+// when changes do not require approval
+func applyPolicyChangeSet(tx *gorm.DB, changeSet *models.PolicyChangeSet, plan *policyImportPlan) error {
+	switch changeSet.Kind {
+	case models.PolicyChangeSetKindMetadata:
+		req := *changeSet.Metadata
+		req.TenantID = changeSet.TenantID
+		_, err := createPolicyMetadata(tx, &req)
+		return err
+	case models.PolicyChangeSetKindAllowList:
+		req := *changeSet.AllowListUpdate
+		req.TenantID = changeSet.TenantID
+		_, err := updateAllowList(tx, &req)
+		return err
+	case models.PolicyChangeSetKindSchemaLifecycle:
+		req := *changeSet.SchemaLifecycleUpdate
+		req.TenantID = changeSet.TenantID
+		_, err := updateSchemaLifecycle(tx, &req)
+		return err
+	default:
+		return plan.apply(tx)
+	}
+}
+
+// metadataDocumentOf returns the document form of a policy metadata request: a document listing its schema
+func metadataDocumentOf(req *models.PolicyMetadataCreateRequest) *models.PolicyDocument {
+	schema := models.PolicyDocumentSchema{SchemaID: req.SchemaID, ProviderID: req.ProviderID}
+	for _, record := range req.Records {
+		schema.Fields = append(schema.Fields, models.PolicyDocumentField{
+			FieldName:         record.FieldName,
+			DisplayName:       record.DisplayName,
+			Description:       record.Description,
+			Source:            record.Source,
+			IsOwner:           record.IsOwner,
+			Owner:             record.Owner,
+			AccessControlType: record.AccessControlType,
+			Classification:    record.Classification,
+			Conditions:        record.Conditions,
+		})
+	}
+	return &models.PolicyDocument{Version: models.PolicyDocumentVersion, Schemas: []models.PolicyDocumentSchema{schema}}
+}
+
+// validateAllowListUpdate checks the grant duration and that every field exists in the tenant's policy
+func validateAllowListUpdate(db *gorm.DB, tenantID string, req *models.AllowListUpdateRequest) error {
+	if req.ApplicationID == "" {
+		return fmt.Errorf("%w: applicationId is required", ErrInvalidInput)
+	}
+	if len(req.Records) == 0 {
+		return fmt.Errorf("%w: records are required", ErrInvalidInput)
+	}
+	if _, err := req.GrantDuration.ExpiresAtFrom(time.Now()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	for _, record := range req.Records {
+		var count int64
+		if err := db.Model(&models.PolicyMetadata{}).
+			Where("tenant_id = ? AND schema_id = ? AND field_name = ?", tenantID, record.SchemaID, record.FieldName).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check policy metadata: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: policy metadata not found for schema_id %s and field_name %s", ErrNotFound, record.SchemaID, record.FieldName)
+		}
+	}
+	return nil
+}
+
+// ListPolicyChangeSets lists the tenant's change sets, newest first, optionally filtered by status.
+// The diffs of pending change sets are not computed; get a change set to review it.
+func (s *PolicyMetadataService) ListPolicyChangeSets(tenantID string, status string) (*models.PolicyChangeSetListResponse, error) {
+	query := s.db.Where("tenant_id = ?", tenantOrDefault(tenantID))
+	if status != "" {
+		switch models.PolicyChangeSetStatus(status) {
+		case models.PolicyChangeSetStatusPending, models.PolicyChangeSetStatusApproved, models.PolicyChangeSetStatusRejected:
+		default:
+			return nil, fmt.Errorf("%w: invalid status %s", ErrInvalidInput, status)
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var changeSets []models.PolicyChangeSet
+	if err := query.Order("created_at DESC").Find(&changeSets).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy change sets: %w", err)
+	}
+
+	records := make([]models.PolicyChangeSetResponse, 0, len(changeSets))
+	for i := range changeSets {
+		records = append(records, changeSets[i].ToResponse())
+	}
+	return &models.PolicyChangeSetListResponse{Records: records, Count: len(records)}, nil
+}
+
+// GetPolicyChangeSet returns a change set. The diff and impact of a pending change set are computed
+// against the current policy; decided change sets return them as they were when decided.
+func (s *PolicyMetadataService) GetPolicyChangeSet(tenantID, id string) (*models.PolicyChangeSetResponse, error) {
+	changeSet, err := findPolicyChangeSet(s.db, tenantOrDefault(tenantID), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeSet.Status == models.PolicyChangeSetStatusPending {
+		plan, err := planPolicyChangeSet(s.db, changeSet)
+		if err != nil {
+			return nil, err
+		}
+		if plan != nil {
+			changeSet.Diff = plan.response
+			changeSet.Impact = policyChangeImpact(plan, time.Now())
+		}
+	}
+	response := changeSet.ToResponse()
+	return &response, nil
+}
+
+// ReviewPolicyChangeSet approves or rejects a pending change set. Change sets cannot be reviewed by the
+// admin who proposed them. Approving applies the document and records the resulting policy as a new
+// version in a single transaction.
+func (s *PolicyMetadataService) ReviewPolicyChangeSet(id string, req *models.PolicyChangeSetReviewRequest) (*models.PolicyChangeSetResponse, error) {
+	if req.Status != models.PolicyChangeSetStatusApproved && req.Status != models.PolicyChangeSetStatusRejected {
+		return nil, fmt.Errorf("%w: status must be %s or %s", ErrInvalidInput, models.PolicyChangeSetStatusApproved, models.PolicyChangeSetStatusRejected)
+	}
+	if req.ReviewedBy == "" {
+		return nil, fmt.Errorf("%w: the reviewer is required", ErrInvalidInput)
+	}
+
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	changeSet, err := s.reviewPolicyChangeSet(tx, tenantOrDefault(req.TenantID), id, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if changeSet.Status == models.PolicyChangeSetStatusApproved {
+		s.refreshCache()
+	}
+
+	slog.Info("Policy change set reviewed", "changeSetId", changeSet.ID, "tenantId", changeSet.TenantID, "status", changeSet.Status,
+		"reviewedBy", req.ReviewedBy, "version", changeSet.Version)
+	response := changeSet.ToResponse()
+	return &response, nil
+}
+
+// reviewPolicyChangeSet records the decision on a change set, and applies and versions it when approved
+func (s *PolicyMetadataService) reviewPolicyChangeSet(tx *gorm.DB, tenantID, id string, req *models.PolicyChangeSetReviewRequest) (*models.PolicyChangeSet, error) {
+	changeSet, err := findPolicyChangeSet(tx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if changeSet.Status != models.PolicyChangeSetStatusPending {
+		return nil, fmt.Errorf("%w: change set %s is already %s", ErrConflict, id, changeSet.Status)
+	}
+	if changeSet.ProposedBy == req.ReviewedBy {
+		return nil, fmt.Errorf("%w: change sets must be reviewed by another admin than the one who proposed them", ErrForbidden)
+	}
+
+	now := time.Now()
+	plan, err := planPolicyChangeSet(tx, changeSet)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		changeSet.Diff = plan.response
+		changeSet.Impact = policyChangeImpact(plan, now)
+	}
+
+	if req.Status == models.PolicyChangeSetStatusApproved {
+		if plan != nil && len(plan.response.Changes) == 0 {
+			return nil, fmt.Errorf("%w: change set %s no longer changes the policy", ErrConflict, id)
+		}
+		if err := applyPolicyChangeSet(tx, changeSet, plan); err != nil {
+			return nil, err
+		}
+
+		if plan != nil {
+			plan.response.Applied = true
+			version, err := createPolicyVersion(tx, changeSet, req.ReviewedBy, now)
+			if err != nil {
+				return nil, err
+			}
+			changeSet.Version = &version
+		}
+	}
+
+	// Only pending change sets are updated, so that concurrent reviews cannot both succeed
+	changeSet.Status = req.Status
+	changeSet.ReviewedBy = &req.ReviewedBy
+	changeSet.ReviewComment = req.ReviewComment
+	changeSet.UpdatedAt = now
+	result := tx.Model(&models.PolicyChangeSet{}).
+		Where("id = ? AND status = ?", changeSet.ID, models.PolicyChangeSetStatusPending).
+		Updates(map[string]interface{}{
+			"status":         changeSet.Status,
+			"reviewed_by":    changeSet.ReviewedBy,
+			"review_comment": changeSet.ReviewComment,
+			"diff":           changeSet.Diff,
+			"impact":         changeSet.Impact,
+			"version":        changeSet.Version,
+			"updated_at":     changeSet.UpdatedAt,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update policy change set: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: change set %s was reviewed concurrently", ErrConflict, id)
+	}
+	return changeSet, nil
+}
+
+// createPolicyVersion records the tenant's policy after the change set was applied as its next version
+func createPolicyVersion(tx *gorm.DB, changeSet *models.PolicyChangeSet, approvedBy string, now time.Time) (int, error) {
+	var latest int
+	if err := tx.Model(&models.PolicyVersion{}).Where("tenant_id = ?", changeSet.TenantID).
+		Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch latest policy version: %w", err)
+	}
+
+	doc, err := exportPolicyDocument(tx, changeSet.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	version := models.PolicyVersion{
+		ID:          uuid.New(),
+		TenantID:    changeSet.TenantID,
+		Version:     latest + 1,
+		ChangeSetID: changeSet.ID,
+		Document:    *doc,
+		ProposedBy:  changeSet.ProposedBy,
+		ApprovedBy:  approvedBy,
+		CreatedAt:   now,
+	}
+	if err := tx.Create(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to create policy version: %w", err)
+	}
+	return version.Version, nil
+}
+
+// ListPolicyVersions lists the tenant's policy versions, newest first
+func (s *PolicyMetadataService) ListPolicyVersions(tenantID string) (*models.PolicyVersionListResponse, error) {
+	var versions []models.PolicyVersion
+	if err := s.db.Omit("document").Where("tenant_id = ?", tenantOrDefault(tenantID)).
+		Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy versions: %w", err)
+	}
+
+	records := make([]models.PolicyVersionSummary, 0, len(versions))
+	for i := range versions {
+		records = append(records, versions[i].ToSummary())
+	}
+	return &models.PolicyVersionListResponse{Records: records}, nil
+}
+
+// GetPolicyVersion returns a policy version of the tenant with its document
+func (s *PolicyMetadataService) GetPolicyVersion(tenantID string, version int) (*models.PolicyVersion, error) {
+	var policyVersion models.PolicyVersion
+	err := s.db.Where("tenant_id = ? AND version = ?", tenantOrDefault(tenantID), version).First(&policyVersion).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: policy version %d", ErrNotFound, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policy version: %w", err)
+	}
+	return &policyVersion, nil
+}
+
+// findPolicyChangeSet returns a change set of the tenant
+func findPolicyChangeSet(db *gorm.DB, tenantID, id string) (*models.PolicyChangeSet, error) {
+	var changeSet models.PolicyChangeSet
+	err := db.Where("id = ? AND tenant_id = ?", id, tenantID).First(&changeSet).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: change set %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policy change set: %w", err)
+	}
+	return &changeSet, nil
+}
+
+// policyChangeImpact lists the consumer applications holding unexpired grants on the fields the plan
+// updates or deletes, with the affected fields, sorted by application
+func policyChangeImpact(plan *policyImportPlan, now time.Time) models.PolicyChangeImpacts {
+	byApplication := make(map[string]*models.PolicyChangeImpact)
+	for _, change := range plan.response.Changes {
+		if change.Action == models.PolicyImportActionCreate {
+			continue
+		}
+		existing, ok := plan.existing[change.SchemaID+":"+change.FieldName]
+		if !ok {
+			continue
+		}
+		for applicationID, entry := range existing.AllowList {
+			if entry.IsExpired(now) {
+				continue
+			}
+			impact, ok := byApplication[applicationID]
+			if !ok {
+				impact = &models.PolicyChangeImpact{ApplicationID: applicationID}
+				byApplication[applicationID] = impact
+			}
+			if impact.ConsumerID == "" {
+				impact.ConsumerID = entry.ConsumerID
+			}
+			impact.Fields = append(impact.Fields, models.PolicyFieldRef{SchemaID: change.SchemaID, FieldName: change.FieldName})
+		}
+	}
+
+	impacts := make(models.PolicyChangeImpacts, 0, len(byApplication))
+	for _, impact := range byApplication {
+		impacts = append(impacts, *impact)
+	}
+	sort.Slice(impacts, func(i, j int) bool {
+		return impacts[i].ApplicationID < impacts[j].ApplicationID
+	})
+	return impacts
+}
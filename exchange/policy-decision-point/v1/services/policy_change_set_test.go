@@ -0,0 +1,153 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyMetadataService_PolicyChangeSets(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	now := time.Now()
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-1": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now, ConsumerID: "member-1"},
+		"app-2": {ExpiresAt: now.AddDate(0, -1, 0), UpdatedAt: now},
+	}, "field1", "field2")
+
+	citizen := models.OwnerCitizen
+	doc := models.PolicyDocument{
+		Version: models.PolicyDocumentVersion,
+		Schemas: []models.PolicyDocumentSchema{{
+			SchemaID: "schema-123",
+			Fields: []models.PolicyDocumentField{
+				{FieldName: "field1", Source: models.SourcePrimary, Owner: &citizen, Classification: models.ClassificationSensitivePersonal},
+				{FieldName: "field2", Source: models.SourcePrimary, IsOwner: true, AccessControlType: models.AccessControlTypePublic},
+				{FieldName: "field3", Source: models.SourceFallback, IsOwner: true},
+			},
+		}},
+	}
+
+	created, err := service.CreatePolicyChangeSet(&models.PolicyChangeSetCreateRequest{
+		Title:      "Restrict field1",
+		Document:   doc,
+		ProposedBy: "editor@example.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.PolicyChangeSetStatusPending, created.Status)
+	require.NotNil(t, created.Diff)
+	assert.False(t, created.Diff.Applied)
+	assert.Equal(t, 1, created.Diff.Created)
+	assert.Equal(t, 1, created.Diff.Updated)
+
+	// Only unexpired grants on updated fields are affected
+	require.Len(t, created.Impact, 1)
+	assert.Equal(t, "app-1", created.Impact[0].ApplicationID)
+	assert.Equal(t, "member-1", created.Impact[0].ConsumerID)
+	assert.Equal(t, []models.PolicyFieldRef{{SchemaID: "schema-123", FieldName: "field1"}}, created.Impact[0].Fields)
+
+	// Nothing is applied before the change set is approved
+	var pm models.PolicyMetadata
+	require.NoError(t, db.Where("field_name = ?", "field1").First(&pm).Error)
+	assert.Equal(t, models.AccessControlTypePublic, pm.AccessControlType)
+
+	t.Run("Rejects invalid proposals", func(t *testing.T) {
+		_, err := service.CreatePolicyChangeSet(&models.PolicyChangeSetCreateRequest{Title: "No actor", Document: doc})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		_, err = service.CreatePolicyChangeSet(&models.PolicyChangeSetCreateRequest{Title: "Invalid", ProposedBy: "editor@example.com"})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+
+		current, err := service.ExportPolicyDocument("")
+		require.NoError(t, err)
+		_, err = service.CreatePolicyChangeSet(&models.PolicyChangeSetCreateRequest{Title: "No-op", Document: *current, ProposedBy: "editor@example.com"})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("Cannot be approved by the proposer", func(t *testing.T) {
+		_, err := service.ReviewPolicyChangeSet(created.ID, &models.PolicyChangeSetReviewRequest{
+			Status:     models.PolicyChangeSetStatusApproved,
+			ReviewedBy: "editor@example.com",
+		})
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("Is listed and scoped to its tenant", func(t *testing.T) {
+		list, err := service.ListPolicyChangeSets("", string(models.PolicyChangeSetStatusPending))
+		require.NoError(t, err)
+		assert.Equal(t, 1, list.Count)
+
+		_, err = service.GetPolicyChangeSet("tenant-b", created.ID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = service.ListPolicyChangeSets("", "applied")
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("Approval applies and versions the policy", func(t *testing.T) {
+		comment := "Looks good"
+		approved, err := service.ReviewPolicyChangeSet(created.ID, &models.PolicyChangeSetReviewRequest{
+			Status:        models.PolicyChangeSetStatusApproved,
+			ReviewComment: &comment,
+			ReviewedBy:    "reviewer@example.com",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.PolicyChangeSetStatusApproved, approved.Status)
+		assert.True(t, approved.Diff.Applied)
+		require.NotNil(t, approved.Version)
+		assert.Equal(t, 1, *approved.Version)
+
+		require.NoError(t, db.Where("field_name = ?", "field1").First(&pm).Error)
+		assert.Equal(t, models.AccessControlTypeRestricted, pm.AccessControlType)
+		assert.Contains(t, pm.AllowList, "app-1")
+
+		versions, err := service.ListPolicyVersions("")
+		require.NoError(t, err)
+		require.Len(t, versions.Records, 1)
+		assert.Equal(t, "editor@example.com", versions.Records[0].ProposedBy)
+		assert.Equal(t, "reviewer@example.com", versions.Records[0].ApprovedBy)
+
+		version, err := service.GetPolicyVersion("", 1)
+		require.NoError(t, err)
+		require.Len(t, version.Document.Schemas, 1)
+		assert.Len(t, version.Document.Schemas[0].Fields, 3)
+
+		// The decided change set keeps the diff and impact it was approved with
+		decided, err := service.GetPolicyChangeSet("", created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, decided.Diff.Created)
+		assert.Len(t, decided.Impact, 1)
+
+		_, err = service.ReviewPolicyChangeSet(created.ID, &models.PolicyChangeSetReviewRequest{
+			Status:     models.PolicyChangeSetStatusRejected,
+			ReviewedBy: "reviewer@example.com",
+		})
+		assert.ErrorIs(t, err, ErrConflict)
+		_, err = service.GetPolicyVersion("", 2)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Rejection leaves the policy unchanged", func(t *testing.T) {
+		doc.Schemas[0].Fields = doc.Schemas[0].Fields[:2]
+		proposed, err := service.CreatePolicyChangeSet(&models.PolicyChangeSetCreateRequest{
+			Title:      "Delete field3",
+			Document:   doc,
+			ProposedBy: "editor@example.com",
+		})
+		require.NoError(t, err)
+
+		rejected, err := service.ReviewPolicyChangeSet(proposed.ID, &models.PolicyChangeSetReviewRequest{
+			Status:     models.PolicyChangeSetStatusRejected,
+			ReviewedBy: "reviewer@example.com",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.PolicyChangeSetStatusRejected, rejected.Status)
+		assert.Nil(t, rejected.Version)
+
+		var count int64
+		require.NoError(t, db.Model(&models.PolicyMetadata{}).Where("field_name = ?", "field3").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/gov-dx-sandbox/shared/database"
+	"gorm.io/gorm"
+)
+
+// ExportPolicyDocument returns the tenant's policy set as a canonical document,
+// with schemas and fields sorted so that exports of the same policy are identical
+func (s *PolicyMetadataService) ExportPolicyDocument(tenantID string) (*models.PolicyDocument, error) {
+	return exportPolicyDocument(database.Reader(s.db), tenantOrDefault(tenantID))
+}
+
+// exportPolicyDocument returns the tenant's policy set as a canonical document
+func exportPolicyDocument(db *gorm.DB, tenantID string) (*models.PolicyDocument, error) {
+	var records []models.PolicyMetadata
+	if err := db.Where("tenant_id = ?", tenantID).Order("schema_id ASC").Order("field_name ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
+	}
+
+	doc := &models.PolicyDocument{
+		Version: models.PolicyDocumentVersion,
+		Tenant:  tenantID,
+		Schemas: []models.PolicyDocumentSchema{},
+	}
+	for i := range records {
+		pm := &records[i]
+		if len(doc.Schemas) == 0 || doc.Schemas[len(doc.Schemas)-1].SchemaID != pm.SchemaID {
+			doc.Schemas = append(doc.Schemas, models.PolicyDocumentSchema{SchemaID: pm.SchemaID, ProviderID: pm.ProviderID})
+		}
+		schema := &doc.Schemas[len(doc.Schemas)-1]
+		schema.Fields = append(schema.Fields, documentFieldOf(pm))
+	}
+
+	return doc, nil
+}
+
+// ImportPolicyDocument diffs the document against the stored policy and, unless dryRun is set,
+// applies it in a single transaction. The document is authoritative for the schemas it lists:
+// fields missing from a listed schema are deleted. Schemas it does not list are left untouched,
+// and allow lists of existing fields are preserved. Schemas owned by another tenant or provider are rejected.
+func (s *PolicyMetadataService) ImportPolicyDocument(tenantID string, doc *models.PolicyDocument, dryRun bool) (*models.PolicyImportResponse, error) {
+	plan, err := planPolicyImport(s.db, tenantOrDefault(tenantID), doc)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(plan.response.Changes) == 0 {
+		return plan.response, nil
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := plan.apply(tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.refreshCache()
+
+	plan.response.Applied = true
+	return plan.response, nil
+}
+
+// policyImportPlan is the diff of a policy document against the stored policy, and the writes that apply it
+type policyImportPlan struct {
+	response *models.PolicyImportResponse
+	// existing are the stored records of the schemas the document lists, by schema ID and field name
+	existing       map[string]*models.PolicyMetadata
+	newRecords     []models.PolicyMetadata
+	updatedRecords []models.PolicyMetadata
+	idsToDelete    []uuid.UUID
+}
+
+// planPolicyImport validates the document and diffs it against the policy stored in db
+func planPolicyImport(db *gorm.DB, tenantID string, doc *models.PolicyDocument) (*policyImportPlan, error) {
+	if err := validatePolicyDocument(doc); err != nil {
+		return nil, err
+	}
+	return diffPolicyDocument(db, tenantID, doc)
+}
+
+// diffPolicyDocument diffs a document against the policy stored in db
+func diffPolicyDocument(db *gorm.DB, tenantID string, doc *models.PolicyDocument) (*policyImportPlan, error) {
+	var schemaIDs []string
+	providers := make(map[string]string)
+	for _, schema := range doc.Schemas {
+		schemaIDs = append(schemaIDs, schema.SchemaID)
+		providers[schema.SchemaID] = schema.ProviderID
+	}
+
+	var existingRecords []models.PolicyMetadata
+	if len(schemaIDs) > 0 {
+		if err := db.Where("schema_id IN ?", schemaIDs).Find(&existingRecords).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
+		}
+	}
+	existingMap := make(map[string]*models.PolicyMetadata)
+	for i := range existingRecords {
+		pm := &existingRecords[i]
+		if !pm.OwnedBy(tenantID, providers[pm.SchemaID]) {
+			return nil, fmt.Errorf("%w: schema %s belongs to namespace %s", ErrConflict, pm.SchemaID, pm.Namespace())
+		}
+		existingMap[pm.SchemaID+":"+pm.FieldName] = pm
+	}
+
+	now := time.Now()
+	response := &models.PolicyImportResponse{
+		Changes: []models.PolicyImportChange{},
+	}
+	var newRecords []models.PolicyMetadata
+	var updatedRecords []models.PolicyMetadata
+	var idsToDelete []uuid.UUID
+	processed := make(map[string]struct{})
+
+	for _, schema := range doc.Schemas {
+		for _, field := range schema.Fields {
+			key := schema.SchemaID + ":" + field.FieldName
+			processed[key] = struct{}{}
+
+			existing, exists := existingMap[key]
+			if !exists {
+				pm := models.PolicyMetadata{
+					ID:         uuid.New(),
+					TenantID:   tenantID,
+					ProviderID: schema.ProviderID,
+					SchemaID:   schema.SchemaID,
+					FieldName:  field.FieldName,
+					AllowList:  make(models.AllowList),
+					CreatedAt:  now,
+					UpdatedAt:  now,
+				}
+				applyDocumentField(&pm, &field)
+				newRecords = append(newRecords, pm)
+				response.Created++
+				response.Changes = append(response.Changes, models.PolicyImportChange{
+					SchemaID:  schema.SchemaID,
+					FieldName: field.FieldName,
+					Action:    models.PolicyImportActionCreate,
+				})
+				continue
+			}
+
+			changed := changedDocumentAttributes(documentFieldOf(existing), field)
+			if schema.ProviderID != "" && existing.ProviderID != schema.ProviderID {
+				changed = append(changed, "providerId")
+			}
+			if len(changed) == 0 {
+				response.Unchanged++
+				continue
+			}
+			pm := *existing
+			applyDocumentField(&pm, &field)
+			if schema.ProviderID != "" {
+				pm.ProviderID = schema.ProviderID
+			}
+			pm.UpdatedAt = now
+			updatedRecords = append(updatedRecords, pm)
+			response.Updated++
+			response.Changes = append(response.Changes, models.PolicyImportChange{
+				SchemaID:          schema.SchemaID,
+				FieldName:         field.FieldName,
+				Action:            models.PolicyImportActionUpdate,
+				ChangedAttributes: changed,
+			})
+		}
+	}
+
+	for i := range existingRecords {
+		pm := &existingRecords[i]
+		if _, ok := processed[pm.SchemaID+":"+pm.FieldName]; ok {
+			continue
+		}
+		idsToDelete = append(idsToDelete, pm.ID)
+		response.Deleted++
+		response.Changes = append(response.Changes, models.PolicyImportChange{
+			SchemaID:  pm.SchemaID,
+			FieldName: pm.FieldName,
+			Action:    models.PolicyImportActionDelete,
+		})
+	}
+
+	sort.SliceStable(response.Changes, func(i, j int) bool {
+		if response.Changes[i].SchemaID != response.Changes[j].SchemaID {
+			return response.Changes[i].SchemaID < response.Changes[j].SchemaID
+		}
+		return response.Changes[i].FieldName < response.Changes[j].FieldName
+	})
+
+	return &policyImportPlan{
+		response:       response,
+		existing:       existingMap,
+		newRecords:     newRecords,
+		updatedRecords: updatedRecords,
+		idsToDelete:    idsToDelete,
+	}, nil
+}
+
+// apply writes the planned changes in the transaction
+func (p *policyImportPlan) apply(tx *gorm.DB) error {
+	if len(p.idsToDelete) > 0 {
+		if err := tx.Where("id IN ?", p.idsToDelete).Delete(&models.PolicyMetadata{}).Error; err != nil {
+			return fmt.Errorf("failed to delete policy metadata records: %w", err)
+		}
+	}
+	if len(p.newRecords) > 0 {
+		if err := tx.Create(&p.newRecords).Error; err != nil {
+			return fmt.Errorf("failed to create policy metadata records: %w", err)
+		}
+	}
+	if len(p.updatedRecords) > 0 {
+		if err := tx.Save(&p.updatedRecords).Error; err != nil {
+			return fmt.Errorf("failed to update policy metadata records: %w", err)
+		}
+	}
+	return nil
+}
+
+// validatePolicyDocument checks the document format and every field policy,
+// so that an import either applies completely or not at all
+func validatePolicyDocument(doc *models.PolicyDocument) error {
+	if doc.Version != models.PolicyDocumentVersion {
+		return fmt.Errorf("%w: unsupported policy document version %q, expected %q", ErrInvalidInput, doc.Version, models.PolicyDocumentVersion)
+	}
+
+	schemas := make(map[string]struct{})
+	for _, schema := range doc.Schemas {
+		if schema.SchemaID == "" {
+			return fmt.Errorf("%w: schemaId is required", ErrInvalidInput)
+		}
+		if _, duplicate := schemas[schema.SchemaID]; duplicate {
+			return fmt.Errorf("%w: schema %s is listed more than once", ErrInvalidInput, schema.SchemaID)
+		}
+		schemas[schema.SchemaID] = struct{}{}
+
+		fields := make(map[string]struct{})
+		for _, field := range schema.Fields {
+			if field.FieldName == "" {
+				return fmt.Errorf("%w: fieldName is required in schema %s", ErrInvalidInput, schema.SchemaID)
+			}
+			if _, duplicate := fields[field.FieldName]; duplicate {
+				return fmt.Errorf("%w: field %s is listed more than once in schema %s", ErrInvalidInput, field.FieldName, schema.SchemaID)
+			}
+			fields[field.FieldName] = struct{}{}
+
+			if err := validateDocumentField(&field); err != nil {
+				return fmt.Errorf("%w: schema %s field %s: %v", ErrInvalidInput, schema.SchemaID, field.FieldName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateDocumentField checks a single field policy
+func validateDocumentField(field *models.PolicyDocumentField) error {
+	if field.Source != models.SourcePrimary && field.Source != models.SourceFallback {
+		return fmt.Errorf("invalid source: %s", field.Source)
+	}
+	if field.AccessControlType != "" &&
+		field.AccessControlType != models.AccessControlTypePublic &&
+		field.AccessControlType != models.AccessControlTypeRestricted {
+		return fmt.Errorf("invalid access control type: %s", field.AccessControlType)
+	}
+	if err := field.Classification.Validate(); err != nil {
+		return err
+	}
+	if err := field.Conditions.Validate(); err != nil {
+		return fmt.Errorf("invalid conditions: %v", err)
+	}
+	if (!field.IsOwner && field.Owner == nil) || (field.IsOwner && field.Owner != nil) {
+		return fmt.Errorf("owner must be specified when isOwner is false and must be omitted when isOwner is true")
+	}
+	return nil
+}
+
+// documentFieldOf converts a policy metadata record to its document form
+func documentFieldOf(pm *models.PolicyMetadata) models.PolicyDocumentField {
+	field := models.PolicyDocumentField{
+		FieldName:         pm.FieldName,
+		DisplayName:       pm.DisplayName,
+		Description:       pm.Description,
+		Source:            pm.Source,
+		IsOwner:           pm.IsOwner,
+		Owner:             pm.Owner,
+		AccessControlType: pm.AccessControlType,
+		Classification:    pm.Classification,
+	}
+	if len(pm.Conditions) > 0 {
+		field.Conditions = pm.Conditions
+	}
+	return field
+}
+
+// applyDocumentField sets the policy attributes of a record from its document form,
+// applying the classification tier defaults for omitted attributes
+func applyDocumentField(pm *models.PolicyMetadata, field *models.PolicyDocumentField) {
+	classification := field.Classification
+	if classification == "" {
+		classification = models.DefaultClassification
+	}
+	accessControlType := field.AccessControlType
+	if accessControlType == "" {
+		accessControlType = classification.Rule().DefaultAccessControlType
+	}
+
+	pm.DisplayName = field.DisplayName
+	pm.Description = field.Description
+	pm.Source = field.Source
+	pm.IsOwner = field.IsOwner
+	pm.Owner = field.Owner
+	pm.AccessControlType = accessControlType
+	pm.Classification = classification
+	pm.Conditions = field.Conditions
+	if pm.Conditions == nil {
+		pm.Conditions = models.PolicyConditions{}
+	}
+}
+
+// changedDocumentAttributes lists the attributes that differ between the stored and the imported field
+func changedDocumentAttributes(current, imported models.PolicyDocumentField) []string {
+	// Compare against the imported field with defaults applied, as it would be stored
+	var resolved models.PolicyMetadata
+	applyDocumentField(&resolved, &imported)
+	imported = documentFieldOf(&resolved)
+	imported.FieldName = current.FieldName
+
+	var changed []string
+	if !equalStringPtr(current.DisplayName, imported.DisplayName) {
+		changed = append(changed, "displayName")
+	}
+	if !equalStringPtr(current.Description, imported.Description) {
+		changed = append(changed, "description")
+	}
+	if current.Source != imported.Source {
+		changed = append(changed, "source")
+	}
+	if current.IsOwner != imported.IsOwner {
+		changed = append(changed, "isOwner")
+	}
+	if (current.Owner == nil) != (imported.Owner == nil) || (current.Owner != nil && *current.Owner != *imported.Owner) {
+		changed = append(changed, "owner")
+	}
+	if current.AccessControlType != imported.AccessControlType {
+		changed = append(changed, "accessControlType")
+	}
+	if current.Classification != imported.Classification {
+		changed = append(changed, "classification")
+	}
+	currentConditions, _ := json.Marshal(current.Conditions)
+	importedConditions, _ := json.Marshal(imported.Conditions)
+	if string(currentConditions) != string(importedConditions) {
+		changed = append(changed, "conditions")
+	}
+	return changed
+}
+
+// equalStringPtr reports whether two optional strings are equal
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPolicyMetadataService_ExportPolicyDocument(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	now := time.Now()
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-1": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now},
+	}, "field2", "field1")
+
+	doc, err := service.ExportPolicyDocument("")
+	require.NoError(t, err)
+	assert.Equal(t, models.PolicyDocumentVersion, doc.Version)
+	require.Len(t, doc.Schemas, 1)
+	assert.Equal(t, "schema-123", doc.Schemas[0].SchemaID)
+	require.Len(t, doc.Schemas[0].Fields, 2)
+	assert.Equal(t, "field1", doc.Schemas[0].Fields[0].FieldName)
+	assert.Equal(t, "field2", doc.Schemas[0].Fields[1].FieldName)
+
+	// Allow lists are not exported
+	out, err := yaml.Marshal(doc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "app-1")
+}
+
+func TestPolicyMetadataService_ImportPolicyDocument(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	now := time.Now()
+
+	seedAllowListFields(t, db, service, models.AllowList{
+		"app-1": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now},
+	}, "field1", "field2")
+
+	doc, err := service.ExportPolicyDocument("")
+	require.NoError(t, err)
+
+	t.Run("round trip is unchanged", func(t *testing.T) {
+		resp, err := service.ImportPolicyDocument("", doc, false)
+		require.NoError(t, err)
+		assert.False(t, resp.Applied)
+		assert.Equal(t, 2, resp.Unchanged)
+		assert.Empty(t, resp.Changes)
+	})
+
+	citizen := models.OwnerCitizen
+	changed := &models.PolicyDocument{
+		Version: models.PolicyDocumentVersion,
+		Schemas: []models.PolicyDocumentSchema{{
+			SchemaID: "schema-123",
+			Fields: []models.PolicyDocumentField{
+				{
+					FieldName:      "field1",
+					Source:         models.SourcePrimary,
+					IsOwner:        false,
+					Owner:          &citizen,
+					Classification: models.ClassificationSensitivePersonal,
+				},
+				{FieldName: "field3", Source: models.SourceFallback, IsOwner: true},
+			},
+		}},
+	}
+
+	t.Run("dry run reports diff without applying", func(t *testing.T) {
+		resp, err := service.ImportPolicyDocument("", changed, true)
+		require.NoError(t, err)
+		assert.False(t, resp.Applied)
+		assert.Equal(t, 1, resp.Created)
+		assert.Equal(t, 1, resp.Updated)
+		assert.Equal(t, 1, resp.Deleted)
+		require.Len(t, resp.Changes, 3)
+		assert.Equal(t, models.PolicyImportActionUpdate, resp.Changes[0].Action)
+		assert.ElementsMatch(t, []string{"isOwner", "owner", "accessControlType", "classification"}, resp.Changes[0].ChangedAttributes)
+		assert.Equal(t, models.PolicyImportActionDelete, resp.Changes[1].Action)
+		assert.Equal(t, models.PolicyImportActionCreate, resp.Changes[2].Action)
+
+		var count int64
+		require.NoError(t, db.Model(&models.PolicyMetadata{}).Where("field_name = ?", "field3").Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("import applies diff and keeps allow lists", func(t *testing.T) {
+		resp, err := service.ImportPolicyDocument("", changed, false)
+		require.NoError(t, err)
+		assert.True(t, resp.Applied)
+
+		var pm models.PolicyMetadata
+		require.NoError(t, db.Where("field_name = ?", "field1").First(&pm).Error)
+		assert.Equal(t, models.AccessControlTypeRestricted, pm.AccessControlType)
+		assert.Contains(t, pm.AllowList, "app-1")
+
+		var count int64
+		require.NoError(t, db.Model(&models.PolicyMetadata{}).Where("field_name = ?", "field2").Count(&count).Error)
+		assert.Zero(t, count)
+
+		// Re-importing the same document is a no-op
+		resp, err = service.ImportPolicyDocument("", changed, false)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Changes)
+	})
+
+	t.Run("invalid documents", func(t *testing.T) {
+		invalid := []models.PolicyDocument{
+			{},
+			{Version: models.PolicyDocumentVersion, Schemas: []models.PolicyDocumentSchema{{Fields: []models.PolicyDocumentField{{FieldName: "a"}}}}},
+			{Version: models.PolicyDocumentVersion, Schemas: []models.PolicyDocumentSchema{{SchemaID: "s", Fields: []models.PolicyDocumentField{{FieldName: "a", Source: "unknown", IsOwner: true}}}}},
+			{Version: models.PolicyDocumentVersion, Schemas: []models.PolicyDocumentSchema{{SchemaID: "s", Fields: []models.PolicyDocumentField{{FieldName: "a", Source: models.SourcePrimary}}}}},
+			{Version: models.PolicyDocumentVersion, Schemas: []models.PolicyDocumentSchema{{SchemaID: "s", Fields: []models.PolicyDocumentField{
+				{FieldName: "a", Source: models.SourcePrimary, IsOwner: true},
+				{FieldName: "a", Source: models.SourcePrimary, IsOwner: true},
+			}}}},
+		}
+		for _, doc := range invalid {
+			_, err := service.ImportPolicyDocument("", &doc, true)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+		}
+	})
+}
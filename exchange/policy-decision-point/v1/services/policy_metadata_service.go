@@ -1,17 +1,26 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PolicyMetadataService provides business logic for policy metadata operations
 type PolicyMetadataService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	cache        *PolicyCache
+	killSwitches *KillSwitchService
+	fallback     DecisionFallback
 }
 
 // NewPolicyMetadataService creates a new policy metadata service
@@ -21,8 +30,47 @@ func NewPolicyMetadataService(db *gorm.DB) *PolicyMetadataService {
 	}
 }
 
+// SetCache attaches a policy cache that serves decision lookups and is refreshed after writes
+func (s *PolicyMetadataService) SetCache(cache *PolicyCache) {
+	s.cache = cache
+}
+
+// SetKillSwitches attaches the kill switches checked before policy evaluation
+func (s *PolicyMetadataService) SetKillSwitches(killSwitches *KillSwitchService) {
+	s.killSwitches = killSwitches
+}
+
+// refreshCache reloads the policy cache after a write so decisions observe it immediately
+func (s *PolicyMetadataService) refreshCache() {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Refresh(); err != nil {
+		slog.Warn("Failed to refresh policy cache after write", "error", err)
+	}
+}
+
+// findPolicyMetadataBySchemas returns the policy metadata for the given schemas, from the cache when loaded
+func (s *PolicyMetadataService) findPolicyMetadataBySchemas(schemaIDs []string) ([]models.PolicyMetadata, error) {
+	if s.cache != nil {
+		if records, ok := s.cache.Lookup(schemaIDs); ok {
+			return records, nil
+		}
+	}
+
+	var records []models.PolicyMetadata
+	if err := s.db.Where("schema_id IN ?", schemaIDs).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
+	}
+	return records, nil
+}
+
 // CreatePolicyMetadata creates new policy metadata records with validation
 func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataCreateRequest) (*models.PolicyMetadataCreateResponse, error) {
+	if err := validatePolicyMetadataRequest(req); err != nil {
+		return nil, err
+	}
+
 	// Start transaction
 	tx := s.db.Begin()
 	if tx.Error != nil {
@@ -34,13 +82,60 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 		}
 	}()
 
+	response, err := createPolicyMetadata(tx, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.refreshCache()
+
+	return response, nil
+}
+
+// validatePolicyMetadataRequest validates the field policies of the request, and applies the
+// classification tier defaults to fields without an explicit policy
+func validatePolicyMetadataRequest(req *models.PolicyMetadataCreateRequest) error {
+	for i := range req.Records {
+		record := &req.Records[i]
+		if err := record.Conditions.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid conditions for field %s: %v", ErrInvalidInput, record.FieldName, err)
+		}
+		if err := record.Classification.Validate(); err != nil {
+			return fmt.Errorf("%w: field %s: %v", ErrInvalidInput, record.FieldName, err)
+		}
+
+		if record.Classification == "" {
+			record.Classification = models.DefaultClassification
+		}
+		if record.AccessControlType == "" {
+			record.AccessControlType = record.Classification.Rule().DefaultAccessControlType
+		}
+	}
+	return nil
+}
+
+// createPolicyMetadata writes the validated policy metadata of a schema within tx. Fields of the
+// schema missing from the request are deleted.
+func createPolicyMetadata(tx *gorm.DB, req *models.PolicyMetadataCreateRequest) (*models.PolicyMetadataCreateResponse, error) {
 	// Check if there are already records for the given schema ID
 	var existingMetadata []models.PolicyMetadata
 	if err := tx.Where("schema_id = ?", req.SchemaID).Find(&existingMetadata).Error; err != nil {
-		tx.Rollback()
 		return nil, fmt.Errorf("failed to check existing policy metadata: %w", err)
 	}
 
+	// A schema belongs to a single tenant and provider
+	tenantID := tenantOrDefault(req.TenantID)
+	for i := range existingMetadata {
+		if !existingMetadata[i].OwnedBy(tenantID, req.ProviderID) {
+			return nil, fmt.Errorf("%w: schema %s belongs to namespace %s", ErrConflict, req.SchemaID, existingMetadata[i].Namespace())
+		}
+	}
+
 	// Create a map for faster lookups of existing records by field name
 	existingMap := make(map[string]*models.PolicyMetadata)
 	for i := range existingMetadata {
@@ -64,7 +159,12 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 			existing.Source = record.Source
 			existing.IsOwner = record.IsOwner
 			existing.AccessControlType = record.AccessControlType
+			existing.Classification = record.Classification
 			existing.Owner = record.Owner
+			existing.Conditions = record.Conditions
+			if req.ProviderID != "" {
+				existing.ProviderID = req.ProviderID
+			}
 			existing.UpdatedAt = now
 
 			updatedRecords = append(updatedRecords, existing)
@@ -72,6 +172,8 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 			// Prepare new record
 			policyMetadata := models.PolicyMetadata{
 				ID:                uuid.New(),
+				TenantID:          tenantID,
+				ProviderID:        req.ProviderID,
 				SchemaID:          req.SchemaID,
 				FieldName:         record.FieldName,
 				DisplayName:       record.DisplayName,
@@ -79,11 +181,20 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 				Source:            record.Source,
 				IsOwner:           record.IsOwner,
 				AccessControlType: record.AccessControlType,
+				Classification:    record.Classification,
 				AllowList:         make(models.AllowList),
 				Owner:             record.Owner,
+				Conditions:        record.Conditions,
+				LifecycleStatus:   models.SchemaLifecycleActive,
 				CreatedAt:         now,
 				UpdatedAt:         now,
 			}
+			// Fields added to a deprecated schema share its lifecycle
+			if len(existingMetadata) > 0 {
+				policyMetadata.LifecycleStatus = existingMetadata[0].LifecycleStatus
+				policyMetadata.SunsetAt = existingMetadata[0].SunsetAt
+				policyMetadata.LifecycleMessage = existingMetadata[0].LifecycleMessage
+			}
 			newRecords = append(newRecords, policyMetadata)
 		}
 	}
@@ -98,7 +209,6 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 
 	if len(idsToDelete) > 0 {
 		if err := tx.Where("id IN ?", idsToDelete).Delete(&models.PolicyMetadata{}).Error; err != nil {
-			tx.Rollback()
 			return nil, fmt.Errorf("failed to delete obsolete policy metadata records: %w", err)
 		}
 	}
@@ -106,7 +216,6 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 	// Bulk create new records
 	if len(newRecords) > 0 {
 		if err := tx.Create(&newRecords).Error; err != nil {
-			tx.Rollback()
 			return nil, fmt.Errorf("failed to create policy metadata records: %w", err)
 		}
 	}
@@ -120,16 +229,10 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 		}
 
 		if err := tx.Save(&recordsToUpdate).Error; err != nil {
-			tx.Rollback()
 			return nil, fmt.Errorf("failed to update existing policy metadata: %w", err)
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	// Prepare response including both new and updated records
 	var responseRecords []models.PolicyMetadataResponse
 
@@ -150,6 +253,34 @@ func (s *PolicyMetadataService) CreatePolicyMetadata(req *models.PolicyMetadataC
 
 // UpdateAllowList updates the allow list for multiple fields with validation
 func (s *PolicyMetadataService) UpdateAllowList(req *models.AllowListUpdateRequest) (*models.AllowListUpdateResponse, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	response, err := updateAllowList(tx, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.refreshCache()
+
+	return response, nil
+}
+
+// updateAllowList updates the allow list for multiple fields within tx. The records are locked
+// while they are read, so concurrent updates of other applications' entries are not lost.
+func updateAllowList(tx *gorm.DB, req *models.AllowListUpdateRequest) (*models.AllowListUpdateResponse, error) {
 	// Collect all (schema_id, field_name) pairs from the request
 	var conditions []string
 	var args []interface{}
@@ -176,7 +307,10 @@ func (s *PolicyMetadataService) UpdateAllowList(req *models.AllowListUpdateReque
 	}
 	whereClause += ")"
 
-	if err := s.db.Where(whereClause, args...).Find(&policyMetadataRecords).Error; err != nil {
+	// Records of other tenants are treated as missing
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ?", tenantOrDefault(req.TenantID)).Where(whereClause, args...).
+		Find(&policyMetadataRecords).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
 	}
 
@@ -198,26 +332,10 @@ func (s *PolicyMetadataService) UpdateAllowList(req *models.AllowListUpdateReque
 
 	// Calculate expiration time based on grant duration
 	currentTime := time.Now()
-	var expiresAt time.Time
-	switch req.GrantDuration {
-	case models.GrantDurationTypeOneMonth:
-		expiresAt = currentTime.AddDate(0, 1, 0)
-	case models.GrantDurationTypeOneYear:
-		expiresAt = currentTime.AddDate(1, 0, 0)
-	default:
-		return nil, fmt.Errorf("invalid grant duration: %s", req.GrantDuration)
-	}
-
-	// Start transaction
-	tx := s.db.Begin()
-	if tx.Error != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	expiresAt, err := req.GrantDuration.ExpiresAtFrom(currentTime)
+	if err != nil {
+		return nil, err
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
 
 	var responseRecords []models.AllowListUpdateResponseRecord
 	var recordsToUpdate []*models.PolicyMetadata
@@ -231,9 +349,14 @@ func (s *PolicyMetadataService) UpdateAllowList(req *models.AllowListUpdateReque
 		if pm.AllowList == nil {
 			pm.AllowList = make(models.AllowList)
 		}
+		consumerID := req.ConsumerID
+		if consumerID == "" {
+			consumerID = pm.AllowList[req.ApplicationID].ConsumerID
+		}
 		pm.AllowList[req.ApplicationID] = models.AllowListEntry{
-			ExpiresAt: expiresAt,
-			UpdatedAt: currentTime,
+			ExpiresAt:  expiresAt,
+			UpdatedAt:  currentTime,
+			ConsumerID: consumerID,
 		}
 
 		recordsToUpdate = append(recordsToUpdate, pm)
@@ -262,41 +385,135 @@ func (s *PolicyMetadataService) UpdateAllowList(req *models.AllowListUpdateReque
 				"allow_list": pm.AllowList,
 				"updated_at": pm.UpdatedAt,
 			}).Error; err != nil {
-				tx.Rollback()
 				return nil, fmt.Errorf("failed to update allow list record: %w", err)
 			}
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return &models.AllowListUpdateResponse{
 		Records: responseRecords,
 	}, nil
 }
 
+// UpdateSchemaLifecycle sets the lifecycle stage of every field of a schema. Decisions on fields of
+// deprecated and sunset schemas report them as deprecated; fields of retired schemas are unauthorized.
+func (s *PolicyMetadataService) UpdateSchemaLifecycle(req *models.SchemaLifecycleUpdateRequest) (*models.SchemaLifecycleUpdateResponse, error) {
+	if err := validateSchemaLifecycleUpdate(s.db, req); err != nil {
+		return nil, err
+	}
+
+	response, err := updateSchemaLifecycle(s.db, req)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshCache()
+	return response, nil
+}
+
+// validateSchemaLifecycleUpdate checks the lifecycle stage and that the schema belongs to the tenant
+func validateSchemaLifecycleUpdate(db *gorm.DB, req *models.SchemaLifecycleUpdateRequest) error {
+	if req.SchemaID == "" {
+		return fmt.Errorf("%w: schemaId is required", ErrInvalidInput)
+	}
+	if err := req.Status.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if req.Status == models.SchemaLifecycleDeprecated && req.SunsetAt == nil {
+		return fmt.Errorf("%w: sunsetAt is required to deprecate a schema", ErrInvalidInput)
+	}
+	if req.Status == models.SchemaLifecycleActive {
+		req.SunsetAt, req.Message = nil, nil
+	}
+
+	var existing []models.PolicyMetadata
+	if err := db.Select("tenant_id", "provider_id", "schema_id").Where("schema_id = ?", req.SchemaID).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to check existing policy metadata: %w", err)
+	}
+	if len(existing) == 0 {
+		return fmt.Errorf("%w: no policy metadata for schema %s", ErrNotFound, req.SchemaID)
+	}
+	tenantID := tenantOrDefault(req.TenantID)
+	if !existing[0].OwnedBy(tenantID, "") {
+		return fmt.Errorf("%w: schema %s belongs to namespace %s", ErrConflict, req.SchemaID, existing[0].Namespace())
+	}
+	return nil
+}
+
+// updateSchemaLifecycle sets the lifecycle stage of a validated schema's fields within db
+func updateSchemaLifecycle(db *gorm.DB, req *models.SchemaLifecycleUpdateRequest) (*models.SchemaLifecycleUpdateResponse, error) {
+	// UpdateColumns skips the owner hooks, which only apply to whole records
+	result := db.Model(&models.PolicyMetadata{}).Where("schema_id = ?", req.SchemaID).UpdateColumns(map[string]interface{}{
+		"lifecycle_status":  req.Status,
+		"sunset_at":         req.SunsetAt,
+		"lifecycle_message": req.Message,
+		"updated_at":        time.Now(),
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update schema lifecycle: %w", result.Error)
+	}
+
+	slog.Info("Schema lifecycle updated", "schemaId", req.SchemaID, "status", req.Status, "fields", result.RowsAffected)
+	return &models.SchemaLifecycleUpdateResponse{
+		SchemaID:      req.SchemaID,
+		Status:        req.Status,
+		SunsetAt:      req.SunsetAt,
+		Message:       req.Message,
+		FieldsUpdated: int(result.RowsAffected),
+	}, nil
+}
+
 // GetPolicyDecision evaluates policy decision based on policy metadata
 func (s *PolicyMetadataService) GetPolicyDecision(req *models.PolicyDecisionRequest) (*models.PolicyDecisionResponse, error) {
-	// Collect all unique schema IDs from the request
-	schemaIDSet := make(map[string]struct{})
-	for _, record := range req.RequiredFields {
-		schemaIDSet[record.SchemaID] = struct{}{}
+	// Kill switches are checked first; a blocked application is denied without evaluating its policies
+	var denyList *models.DenyList
+	if s.killSwitches != nil {
+		var err error
+		if denyList, err = s.killSwitches.DenyList(); err != nil {
+			return s.fallbackDecision(req, err)
+		}
+	}
+	if ks, blocked := denyList.Application(req.ApplicationID); blocked {
+		return blockedPolicyDecision(req, ks), nil
+	}
+
+	// Fetch all PolicyMetadata records for the requested schemas in one lookup
+	allMetadata, err := s.findPolicyMetadataBySchemas(requestSchemaIDs(req))
+	if err != nil {
+		return s.fallbackDecision(req, err)
 	}
 
+	return evaluatePolicyDecision(req, allMetadata, denyList, time.Now())
+}
+
+// requestSchemaIDs returns the unique schema IDs of the fields of a decision request
+func requestSchemaIDs(req *models.PolicyDecisionRequest) []string {
+	schemaIDSet := make(map[string]struct{})
 	var schemaIDs []string
-	for schemaID := range schemaIDSet {
-		schemaIDs = append(schemaIDs, schemaID)
+	for _, record := range req.RequiredFields {
+		if _, seen := schemaIDSet[record.SchemaID]; !seen {
+			schemaIDSet[record.SchemaID] = struct{}{}
+			schemaIDs = append(schemaIDs, record.SchemaID)
+		}
 	}
+	return schemaIDs
+}
 
-	// Fetch all PolicyMetadata records for those schemas in one query
-	var allMetadata []models.PolicyMetadata
-	if err := s.db.Where("schema_id IN ?", schemaIDs).Find(&allMetadata).Error; err != nil {
-		return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
+// blockedPolicyDecision denies every requested field of an application blocked by a kill switch
+func blockedPolicyDecision(req *models.PolicyDecisionRequest, ks models.KillSwitch) *models.PolicyDecisionResponse {
+	blockedFields := make([]models.PolicyDecisionResponseFieldRecord, 0, len(req.RequiredFields))
+	for _, record := range req.RequiredFields {
+		blockedFields = append(blockedFields, models.PolicyDecisionResponseFieldRecord{
+			FieldName: record.FieldName,
+			SchemaID:  record.SchemaID,
+			BlockedBy: ks.TargetType,
+		})
 	}
+	return &models.PolicyDecisionResponse{BlockedFields: blockedFields}
+}
 
+// evaluatePolicyDecision evaluates a decision request against the given policy metadata as of now.
+// Fields blocked by a kill switch in denyList, which may be nil, are denied before any other check.
+func evaluatePolicyDecision(req *models.PolicyDecisionRequest, allMetadata []models.PolicyMetadata, denyList *models.DenyList, now time.Time) (*models.PolicyDecisionResponse, error) {
 	// Create map for fast lookup: (schema_id + field_name) -> &PolicyMetadata
 	metadataMap := make(map[string]*models.PolicyMetadata)
 	for i := range allMetadata {
@@ -308,6 +525,12 @@ func (s *PolicyMetadataService) GetPolicyDecision(req *models.PolicyDecisionRequ
 	var consentRequiredFields []models.PolicyDecisionResponseFieldRecord
 	var unauthorizedFields []models.PolicyDecisionResponseFieldRecord
 	var expiredFields []models.PolicyDecisionResponseFieldRecord
+	var conditionFailedFields []models.PolicyDecisionResponseFieldRecord
+	var deprecatedFields []models.PolicyDecisionDeprecatedFieldRecord
+	var blockedFields []models.PolicyDecisionResponseFieldRecord
+
+	// The policy version identifies the content of the metadata records consulted
+	consulted := make([]*models.PolicyMetadata, 0, len(req.RequiredFields))
 
 	// Iterate through required fields and perform logic using map lookup
 	for _, record := range req.RequiredFields {
@@ -316,9 +539,33 @@ func (s *PolicyMetadataService) GetPolicyDecision(req *models.PolicyDecisionRequ
 		if !exists {
 			return nil, fmt.Errorf("policy metadata not found for schema_id %s and field_name %s", record.SchemaID, record.FieldName)
 		}
+		consulted = append(consulted, pm)
 
-		// Check if application is authorized
-		if _, exists := pm.AllowList[req.ApplicationID]; !exists {
+		if ks, blocked := denyList.Field(pm); blocked {
+			blockedFields = append(blockedFields, models.PolicyDecisionResponseFieldRecord{
+				FieldName:   pm.FieldName,
+				SchemaID:    pm.SchemaID,
+				DisplayName: pm.DisplayName,
+				Description: pm.Description,
+				Owner:       pm.Owner,
+				BlockedBy:   ks.TargetType,
+			})
+			continue
+		}
+
+		// Fields of deprecated schemas are still decided on; the caller warns the consumer about them
+		if pm.LifecycleStatus.IsDeprecated() {
+			deprecatedFields = append(deprecatedFields, models.PolicyDecisionDeprecatedFieldRecord{
+				FieldName: pm.FieldName,
+				SchemaID:  pm.SchemaID,
+				Status:    pm.LifecycleStatus,
+				SunsetAt:  pm.SunsetAt,
+				Message:   pm.LifecycleMessage,
+			})
+		}
+
+		// Check if application is authorized; fields of retired schemas are not served to anyone
+		if _, exists := pm.AllowList[req.ApplicationID]; !exists || pm.LifecycleStatus == models.SchemaLifecycleRetired {
 			unauthorizedFields = append(unauthorizedFields, models.PolicyDecisionResponseFieldRecord{
 				FieldName:   pm.FieldName,
 				SchemaID:    pm.SchemaID,
@@ -331,7 +578,7 @@ func (s *PolicyMetadataService) GetPolicyDecision(req *models.PolicyDecisionRequ
 
 		// Check if access has expired
 		allowListEntry := pm.AllowList[req.ApplicationID]
-		if time.Now().After(allowListEntry.ExpiresAt) {
+		if allowListEntry.IsExpired(now) {
 			expiredFields = append(expiredFields, models.PolicyDecisionResponseFieldRecord{
 				FieldName:   pm.FieldName,
 				SchemaID:    pm.SchemaID,
@@ -342,8 +589,22 @@ func (s *PolicyMetadataService) GetPolicyDecision(req *models.PolicyDecisionRequ
 			continue
 		}
 
-		// Check if owner consent is required
-		if !pm.IsOwner && pm.AccessControlType == models.AccessControlTypeRestricted {
+		// Check attribute conditions against the request context
+		if failed := pm.Conditions.Evaluate(req.Context, now); failed != nil {
+			conditionFailedFields = append(conditionFailedFields, models.PolicyDecisionResponseFieldRecord{
+				FieldName:       pm.FieldName,
+				SchemaID:        pm.SchemaID,
+				DisplayName:     pm.DisplayName,
+				Description:     pm.Description,
+				Owner:           pm.Owner,
+				FailedCondition: failed,
+			})
+			continue
+		}
+
+		// Check if owner consent is required, either by the field's policy or its classification tier
+		requiresConsent := pm.AccessControlType == models.AccessControlTypeRestricted || pm.Classification.Rule().AlwaysRequiresConsent
+		if !pm.IsOwner && requiresConsent {
 			consentRequiredFields = append(consentRequiredFields, models.PolicyDecisionResponseFieldRecord{
 				FieldName:   pm.FieldName,
 				SchemaID:    pm.SchemaID,
@@ -358,10 +619,82 @@ func (s *PolicyMetadataService) GetPolicyDecision(req *models.PolicyDecisionRequ
 		ConsentRequiredFields:   consentRequiredFields,
 		UnauthorizedFields:      unauthorizedFields,
 		ExpiredFields:           expiredFields,
-		AppAuthorized:           !(len(unauthorizedFields) > 0),
+		ConditionFailedFields:   conditionFailedFields,
+		DeprecatedFields:        deprecatedFields,
+		BlockedFields:           blockedFields,
+		AppAuthorized:           !(len(unauthorizedFields) > 0) && !(len(conditionFailedFields) > 0) && !(len(blockedFields) > 0),
 		AppAccessExpired:        len(expiredFields) > 0,
 		AppRequiresOwnerConsent: len(consentRequiredFields) > 0,
 	}
+	if len(consulted) > 0 {
+		policyVersion, err := policyVersionOf(consulted, req.ApplicationID)
+		if err != nil {
+			return nil, err
+		}
+		response.PolicyVersion = policyVersion
+	}
 
 	return response, nil
 }
+
+// policyVersionOf returns the SHA-256 hash (hex) of the policy an application was decided on: the
+// decision-relevant content of each consulted metadata record, with the application's allow list
+// entry. Decisions made on the same policy share a version, whatever order the fields were requested in.
+func policyVersionOf(consulted []*models.PolicyMetadata, applicationID string) (string, error) {
+	type fieldPolicy struct {
+		SchemaID          string                       `json:"schemaId"`
+		FieldName         string                       `json:"fieldName"`
+		IsOwner           bool                         `json:"isOwner"`
+		Owner             *models.Owner                `json:"owner"`
+		AccessControlType models.AccessControlType     `json:"accessControlType"`
+		Classification    models.Classification        `json:"classification"`
+		Conditions        models.PolicyConditions      `json:"conditions"`
+		LifecycleStatus   models.SchemaLifecycleStatus `json:"lifecycleStatus"`
+		AllowListEntry    *models.AllowListEntry       `json:"allowListEntry"`
+	}
+
+	policies := make([]fieldPolicy, 0, len(consulted))
+	seen := make(map[string]bool, len(consulted))
+	for _, pm := range consulted {
+		key := pm.SchemaID + ":" + pm.FieldName
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		policy := fieldPolicy{
+			SchemaID:          pm.SchemaID,
+			FieldName:         pm.FieldName,
+			IsOwner:           pm.IsOwner,
+			Owner:             pm.Owner,
+			AccessControlType: pm.AccessControlType,
+			Classification:    pm.Classification,
+			Conditions:        pm.Conditions,
+			LifecycleStatus:   pm.LifecycleStatus,
+		}
+		if entry, exists := pm.AllowList[applicationID]; exists {
+			policy.AllowListEntry = &entry
+		}
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].SchemaID != policies[j].SchemaID {
+			return policies[i].SchemaID < policies[j].SchemaID
+		}
+		return policies[i].FieldName < policies[j].FieldName
+	})
+
+	content, err := json.Marshal(policies)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode policy version: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// tenantOrDefault returns the tenant ID, or the default tenant when none is given
+func tenantOrDefault(tenantID string) string {
+	if tenantID == "" {
+		return models.DefaultTenantID
+	}
+	return tenantID
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
 	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/testhelpers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -941,9 +942,10 @@ func TestPolicyMetadataService_UpdateAllowList_ErrorPaths(t *testing.T) {
 			},
 		}
 
+		// The records are read inside the transaction, which cannot begin on a closed connection
 		_, err = service.UpdateAllowList(req)
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to fetch policy metadata records")
+		assert.Contains(t, err.Error(), "failed to begin transaction")
 	})
 
 	// Note: Testing commit errors with SQLite in-memory is not feasible because:
@@ -983,3 +985,361 @@ func TestPolicyMetadataService_GetPolicyDecision_ErrorPaths(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to fetch policy metadata records")
 	})
 }
+
+func TestPolicyMetadataService_GetPolicyDecision_Conditions(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+
+	_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{
+				FieldName:         "person.creditScore",
+				Source:            models.SourcePrimary,
+				IsOwner:           true,
+				AccessControlType: models.AccessControlTypePublic,
+				Conditions: models.PolicyConditions{
+					{Attribute: "consumer.sector", Operator: models.ConditionOperatorEquals, Value: "banking"},
+					{Attribute: "citizen.age", Operator: models.ConditionOperatorGreaterThanOrEqual, Value: float64(18)},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = service.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records:       []models.AllowListUpdateRequestRecord{{FieldName: "person.creditScore", SchemaID: "schema-123"}},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		context        map[string]interface{}
+		wantAuthorized bool
+	}{
+		{"conditions hold", map[string]interface{}{"consumer.sector": "banking", "citizen.age": float64(30)}, true},
+		{"wrong sector", map[string]interface{}{"consumer.sector": "retail", "citizen.age": float64(30)}, false},
+		{"minor", map[string]interface{}{"consumer.sector": "banking", "citizen.age": float64(16)}, false},
+		{"no context", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+				ApplicationID:  "app-1",
+				RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "person.creditScore", SchemaID: "schema-123"}},
+				Context:        tt.context,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAuthorized, resp.AppAuthorized)
+			if tt.wantAuthorized {
+				assert.Empty(t, resp.ConditionFailedFields)
+			} else {
+				require.Len(t, resp.ConditionFailedFields, 1)
+				assert.NotNil(t, resp.ConditionFailedFields[0].FailedCondition)
+			}
+		})
+	}
+}
+
+func TestPolicyMetadataService_CreatePolicyMetadata_InvalidConditions(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+
+	_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{
+				FieldName:         "person.creditScore",
+				Source:            models.SourcePrimary,
+				IsOwner:           true,
+				AccessControlType: models.AccessControlTypePublic,
+				Conditions:        models.PolicyConditions{{Attribute: "citizen.age", Operator: "approximately", Value: float64(18)}},
+			},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestPolicyMetadataService_Classification(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+
+	resp, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{
+				FieldName: "person.address",
+				Source:    models.SourcePrimary,
+				Owner:     testhelpers.OwnerPtr(models.OwnerCitizen),
+			},
+			{
+				FieldName:         "person.medicalHistory",
+				Source:            models.SourcePrimary,
+				Owner:             testhelpers.OwnerPtr(models.OwnerCitizen),
+				AccessControlType: models.AccessControlTypePublic,
+				Classification:    models.ClassificationSensitivePersonal,
+			},
+			{
+				FieldName:      "person.postalCode",
+				Source:         models.SourcePrimary,
+				IsOwner:        true,
+				Classification: models.ClassificationPublic,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	byField := make(map[string]models.PolicyMetadataResponse)
+	for _, record := range resp.Records {
+		byField[record.FieldName] = record
+	}
+	assert.Equal(t, models.ClassificationPersonal, byField["person.address"].Classification)
+	assert.Equal(t, models.AccessControlTypeRestricted, byField["person.address"].AccessControlType)
+	assert.Equal(t, models.AccessControlTypePublic, byField["person.postalCode"].AccessControlType)
+
+	_, err = service.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records: []models.AllowListUpdateRequestRecord{
+			{FieldName: "person.medicalHistory", SchemaID: "schema-123"},
+			{FieldName: "person.postalCode", SchemaID: "schema-123"},
+		},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	decision, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+		ApplicationID: "app-1",
+		RequiredFields: []models.PolicyDecisionRequestRecord{
+			{FieldName: "person.medicalHistory", SchemaID: "schema-123"},
+			{FieldName: "person.postalCode", SchemaID: "schema-123"},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, decision.AppRequiresOwnerConsent)
+	require.Len(t, decision.ConsentRequiredFields, 1, "sensitive fields require consent even when marked public")
+	assert.Equal(t, "person.medicalHistory", decision.ConsentRequiredFields[0].FieldName)
+
+	_, err = service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{FieldName: "person.address", Source: models.SourcePrimary, IsOwner: true, Classification: "top-secret"},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestPolicyMetadataService_Namespaces(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+	records := []models.PolicyMetadataCreateRequestRecord{{
+		FieldName: "person.fullName",
+		Source:    models.SourcePrimary,
+		IsOwner:   true,
+	}}
+
+	resp, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID:   "schema-drp",
+		ProviderID: "drp",
+		TenantID:   "ministry-a",
+		Records:    records,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Records, 1)
+	assert.Equal(t, "ministry-a/drp/schema-drp", resp.Records[0].Namespace)
+
+	t.Run("identically named field of another provider", func(t *testing.T) {
+		resp, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+			SchemaID:   "schema-rgd",
+			ProviderID: "rgd",
+			Records:    records,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.DefaultTenantID, resp.Records[0].TenantID)
+	})
+
+	t.Run("schema claimed by another provider", func(t *testing.T) {
+		_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+			SchemaID:   "schema-drp",
+			ProviderID: "rgd",
+			TenantID:   "ministry-a",
+			Records:    records,
+		})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("schema claimed by another tenant", func(t *testing.T) {
+		_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+			SchemaID: "schema-drp",
+			Records:  records,
+		})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("allow list updates are scoped to the tenant", func(t *testing.T) {
+		req := &models.AllowListUpdateRequest{
+			ApplicationID: "app-1",
+			Records:       []models.AllowListUpdateRequestRecord{{SchemaID: "schema-drp", FieldName: "person.fullName"}},
+			GrantDuration: models.GrantDurationTypeOneMonth,
+		}
+		_, err := service.UpdateAllowList(req)
+		assert.Error(t, err)
+
+		req.TenantID = "ministry-a"
+		_, err = service.UpdateAllowList(req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("export is scoped to the tenant", func(t *testing.T) {
+		doc, err := service.ExportPolicyDocument("ministry-a")
+		require.NoError(t, err)
+		require.Len(t, doc.Schemas, 1)
+		assert.Equal(t, "drp", doc.Schemas[0].ProviderID)
+
+		_, err = service.ImportPolicyDocument(models.DefaultTenantID, doc, true)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestPolicyMetadataService_SchemaLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPolicyMetadataService(db)
+
+	_, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+		SchemaID: "schema-123",
+		Records: []models.PolicyMetadataCreateRequestRecord{
+			{FieldName: "person.fullName", Source: models.SourcePrimary, IsOwner: true, AccessControlType: models.AccessControlTypePublic},
+		},
+	})
+	require.NoError(t, err)
+	_, err = service.UpdateAllowList(&models.AllowListUpdateRequest{
+		ApplicationID: "app-1",
+		Records:       []models.AllowListUpdateRequestRecord{{FieldName: "person.fullName", SchemaID: "schema-123"}},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+
+	decide := func(t *testing.T) *models.PolicyDecisionResponse {
+		resp, err := service.GetPolicyDecision(&models.PolicyDecisionRequest{
+			ApplicationID:  "app-1",
+			RequiredFields: []models.PolicyDecisionRequestRecord{{FieldName: "person.fullName", SchemaID: "schema-123"}},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, err := service.UpdateSchemaLifecycle(&models.SchemaLifecycleUpdateRequest{SchemaID: "schema-123", Status: "archived"})
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		_, err = service.UpdateSchemaLifecycle(&models.SchemaLifecycleUpdateRequest{SchemaID: "schema-123", Status: models.SchemaLifecycleDeprecated})
+		assert.ErrorIs(t, err, ErrInvalidInput, "deprecating requires a sunset date")
+		_, err = service.UpdateSchemaLifecycle(&models.SchemaLifecycleUpdateRequest{SchemaID: "schema-missing", Status: models.SchemaLifecycleRetired})
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = service.UpdateSchemaLifecycle(&models.SchemaLifecycleUpdateRequest{SchemaID: "schema-123", Status: models.SchemaLifecycleRetired, TenantID: "ministry-a"})
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	sunsetAt := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	message := "use schema-456"
+	t.Run("deprecated fields are served with a warning", func(t *testing.T) {
+		resp, err := service.UpdateSchemaLifecycle(&models.SchemaLifecycleUpdateRequest{
+			SchemaID: "schema-123",
+			Status:   models.SchemaLifecycleDeprecated,
+			SunsetAt: &sunsetAt,
+			Message:  &message,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.FieldsUpdated)
+
+		decision := decide(t)
+		assert.True(t, decision.AppAuthorized)
+		require.Len(t, decision.DeprecatedFields, 1)
+		assert.Equal(t, models.SchemaLifecycleDeprecated, decision.DeprecatedFields[0].Status)
+		assert.True(t, sunsetAt.Equal(*decision.DeprecatedFields[0].SunsetAt))
+		assert.Equal(t, message, *decision.DeprecatedFields[0].Message)
+	})
+
+	t.Run("fields added to a deprecated schema are deprecated", func(t *testing.T) {
+		resp, err := service.CreatePolicyMetadata(&models.PolicyMetadataCreateRequest{
+			SchemaID: "schema-123",
+			Records: []models.PolicyMetadataCreateRequestRecord{
+				{FieldName: "person.fullName", Source: models.SourcePrimary, IsOwner: true, AccessControlType: models.AccessControlTypePublic},
+				{FieldName: "person.address", Source: models.SourcePrimary, IsOwner: true},
+			},
+		})
+		require.NoError(t, err)
+		for _, record := range resp.Records {
+			assert.Equal(t, models.SchemaLifecycleDeprecated, record.LifecycleStatus, record.FieldName)
+		}
+	})
+
+	t.Run("retired fields are not served", func(t *testing.T) {
+		_, err := service.UpdateSchemaLifecycle(&models.SchemaLifecycleUpdateRequest{SchemaID: "schema-123", Status: models.SchemaLifecycleRetired})
+		require.NoError(t, err)
+
+		decision := decide(t)
+		assert.False(t, decision.AppAuthorized)
+		assert.Len(t, decision.UnauthorizedFields, 1)
+		assert.Empty(t, decision.DeprecatedFields)
+	})
+
+	t.Run("reactivating clears the sunset date", func(t *testing.T) {
+		resp, err := service.UpdateSchemaLifecycle(&models.SchemaLifecycleUpdateRequest{
+			SchemaID: "schema-123",
+			Status:   models.SchemaLifecycleActive,
+			SunsetAt: &sunsetAt,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp.SunsetAt)
+
+		decision := decide(t)
+		assert.True(t, decision.AppAuthorized)
+		assert.Empty(t, decision.DeprecatedFields)
+	})
+}
+
+func TestEvaluatePolicyDecision_PolicyVersion(t *testing.T) {
+	now := time.Now()
+	metadata := func() []models.PolicyMetadata {
+		return []models.PolicyMetadata{
+			{
+				SchemaID: "schema-123", FieldName: "person.fullName", IsOwner: true,
+				AccessControlType: models.AccessControlTypePublic, UpdatedAt: now,
+				AllowList: models.AllowList{"app-1": {ExpiresAt: now.Add(time.Hour), UpdatedAt: now}},
+			},
+			{
+				SchemaID: "schema-123", FieldName: "person.photo", IsOwner: true,
+				AccessControlType: models.AccessControlTypeRestricted, UpdatedAt: now,
+				AllowList: models.AllowList{"app-1": {ExpiresAt: now.Add(time.Hour), UpdatedAt: now}},
+			},
+		}
+	}
+	decide := func(allMetadata []models.PolicyMetadata, fields ...string) string {
+		req := &models.PolicyDecisionRequest{ApplicationID: "app-1"}
+		for _, field := range fields {
+			req.RequiredFields = append(req.RequiredFields, models.PolicyDecisionRequestRecord{FieldName: field, SchemaID: "schema-123"})
+		}
+		resp, err := evaluatePolicyDecision(req, allMetadata, nil, now)
+		require.NoError(t, err)
+		return resp.PolicyVersion
+	}
+
+	version := decide(metadata(), "person.fullName", "person.photo")
+	assert.Len(t, version, 64)
+	assert.Equal(t, version, decide(metadata(), "person.photo", "person.fullName"), "field order does not change the version")
+
+	touched := metadata()
+	touched[0].UpdatedAt = now.Add(time.Minute)
+	assert.Equal(t, version, decide(touched, "person.fullName", "person.photo"), "updates that leave the policy unchanged keep the version")
+
+	changed := metadata()
+	changed[1].AccessControlType = models.AccessControlTypePublic
+	assert.NotEqual(t, version, decide(changed, "person.fullName", "person.photo"))
+
+	regranted := metadata()
+	regranted[0].AllowList["app-1"] = models.AllowListEntry{ExpiresAt: now.Add(2 * time.Hour), UpdatedAt: now}
+	assert.NotEqual(t, version, decide(regranted, "person.fullName", "person.photo"))
+
+	assert.NotEqual(t, version, decide(metadata(), "person.fullName"))
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"gorm.io/gorm"
+)
+
+// PolicySimulationService evaluates hypothetical policy changes against recently recorded decisions
+type PolicySimulationService struct {
+	db *gorm.DB
+}
+
+// NewPolicySimulationService creates a new policy simulation service
+func NewPolicySimulationService(db *gorm.DB) *PolicySimulationService {
+	return &PolicySimulationService{
+		db: db,
+	}
+}
+
+// Simulate replays a sample of recent decisions against the current policy and against the policy
+// with the requested changes applied, and reports the decisions whose result would change.
+// Nothing is written; both evaluations use the same clock so only the policy changes differ.
+func (s *PolicySimulationService) Simulate(req *models.PolicySimulationRequest) (*models.PolicySimulationResponse, error) {
+	if len(req.Changes) == 0 {
+		return nil, fmt.Errorf("%w: at least one change is required", ErrInvalidInput)
+	}
+	now := time.Now()
+	for _, change := range req.Changes {
+		if err := validateSimulationChange(&change, now); err != nil {
+			return nil, err
+		}
+	}
+
+	sampleSize := req.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = defaultDecisionLogLimit
+	}
+	if sampleSize > maxDecisionLogLimit {
+		sampleSize = maxDecisionLogLimit
+	}
+
+	// Load the most recent recorded decisions
+	query := s.db.Model(&models.PolicyDecisionLog{})
+	if req.ApplicationID != "" {
+		query = query.Where("application_id = ?", req.ApplicationID)
+	}
+	var decisions []models.PolicyDecisionLog
+	if err := query.Preload("Fields").Order("created_at DESC").Limit(sampleSize).Find(&decisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy decisions: %w", err)
+	}
+
+	// Load the current policy for every schema involved
+	schemaIDSet := make(map[string]struct{})
+	for _, change := range req.Changes {
+		schemaIDSet[change.SchemaID] = struct{}{}
+	}
+	for _, decision := range decisions {
+		for _, field := range decision.Fields {
+			schemaIDSet[field.SchemaID] = struct{}{}
+		}
+	}
+	var schemaIDs []string
+	for schemaID := range schemaIDSet {
+		schemaIDs = append(schemaIDs, schemaID)
+	}
+
+	var currentMetadata []models.PolicyMetadata
+	if err := s.db.Where("schema_id IN ?", schemaIDs).Find(&currentMetadata).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch policy metadata records: %w", err)
+	}
+
+	simulatedMetadata, err := applySimulationChanges(currentMetadata, req.Changes, now)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PolicySimulationResponse{
+		Evaluated: len(decisions),
+		Results:   []models.PolicySimulationResult{},
+	}
+	for i := range decisions {
+		decision := &decisions[i]
+		decisionReq := &models.PolicyDecisionRequest{
+			ApplicationID: decision.ApplicationID,
+			Context:       decision.Context,
+		}
+		for _, field := range decision.Fields {
+			decisionReq.RequiredFields = append(decisionReq.RequiredFields, models.PolicyDecisionRequestRecord{
+				SchemaID:  field.SchemaID,
+				FieldName: field.FieldName,
+			})
+		}
+
+		// Kill switches are emergency overrides rather than policy, so simulations ignore them
+		currentResp, currentErr := evaluatePolicyDecision(decisionReq, currentMetadata, nil, now)
+		simulatedResp, simulatedErr := evaluatePolicyDecision(decisionReq, simulatedMetadata, nil, now)
+
+		result := models.PolicySimulationResult{
+			DecisionID:       decision.ID.String(),
+			ApplicationID:    decision.ApplicationID,
+			DecidedAt:        decision.CreatedAt.Format(time.RFC3339Nano),
+			RecordedOutcome:  decision.Outcome,
+			CurrentOutcome:   DecisionOutcomeOf(currentResp, currentErr),
+			SimulatedOutcome: DecisionOutcomeOf(simulatedResp, simulatedErr),
+			ChangedFields:    []models.PolicySimulationFieldChange{},
+		}
+		result.OutcomeFlipped = result.CurrentOutcome != result.SimulatedOutcome
+
+		currentResults := fieldResults(currentResp)
+		simulatedResults := fieldResults(simulatedResp)
+		for _, record := range decisionReq.RequiredFields {
+			currentResult := fieldResultOf(currentResults, currentResp, record)
+			simulatedResult := fieldResultOf(simulatedResults, simulatedResp, record)
+			if currentResult != simulatedResult {
+				result.ChangedFields = append(result.ChangedFields, models.PolicySimulationFieldChange{
+					SchemaID:        record.SchemaID,
+					FieldName:       record.FieldName,
+					CurrentResult:   currentResult,
+					SimulatedResult: simulatedResult,
+				})
+			}
+		}
+
+		if !result.OutcomeFlipped && len(result.ChangedFields) == 0 {
+			continue
+		}
+		response.Affected++
+		if result.OutcomeFlipped {
+			response.Flipped++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// validateSimulationChange checks that a hypothetical change is well formed
+func validateSimulationChange(change *models.PolicySimulationChange, now time.Time) error {
+	if change.SchemaID == "" || change.FieldName == "" {
+		return fmt.Errorf("%w: schemaId and fieldName are required for each change", ErrInvalidInput)
+	}
+	if change.AccessControlType != nil &&
+		*change.AccessControlType != models.AccessControlTypePublic &&
+		*change.AccessControlType != models.AccessControlTypeRestricted {
+		return fmt.Errorf("%w: invalid access control type: %s", ErrInvalidInput, *change.AccessControlType)
+	}
+	if change.Classification != nil {
+		if err := change.Classification.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+	}
+	if change.Conditions != nil {
+		if err := change.Conditions.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid conditions for field %s: %v", ErrInvalidInput, change.FieldName, err)
+		}
+	}
+	if len(change.GrantApplications) > 0 {
+		if _, err := change.GrantDuration.ExpiresAtFrom(now); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+	}
+	return nil
+}
+
+// applySimulationChanges returns a copy of the policy metadata with the changes applied
+func applySimulationChanges(metadata []models.PolicyMetadata, changes []models.PolicySimulationChange, now time.Time) ([]models.PolicyMetadata, error) {
+	simulated := make([]models.PolicyMetadata, len(metadata))
+	indexByKey := make(map[string]int)
+	for i, pm := range metadata {
+		// Copy the allow list so the current policy is left untouched
+		pm.AllowList = make(models.AllowList, len(metadata[i].AllowList))
+		for applicationID, entry := range metadata[i].AllowList {
+			pm.AllowList[applicationID] = entry
+		}
+		simulated[i] = pm
+		indexByKey[pm.SchemaID+":"+pm.FieldName] = i
+	}
+
+	for _, change := range changes {
+		i, exists := indexByKey[change.SchemaID+":"+change.FieldName]
+		if !exists {
+			return nil, fmt.Errorf("%w: policy metadata not found for schema_id %s and field_name %s", ErrInvalidInput, change.SchemaID, change.FieldName)
+		}
+		pm := &simulated[i]
+
+		if change.IsOwner != nil {
+			pm.IsOwner = *change.IsOwner
+		}
+		if change.AccessControlType != nil {
+			pm.AccessControlType = *change.AccessControlType
+		}
+		if change.Classification != nil {
+			pm.Classification = *change.Classification
+		}
+		if change.Conditions != nil {
+			pm.Conditions = *change.Conditions
+		}
+		if len(change.GrantApplications) > 0 {
+			expiresAt, err := change.GrantDuration.ExpiresAtFrom(now)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+			}
+			for _, applicationID := range change.GrantApplications {
+				pm.AllowList[applicationID] = models.AllowListEntry{ExpiresAt: expiresAt, UpdatedAt: now}
+			}
+		}
+		for _, applicationID := range change.RevokeApplications {
+			delete(pm.AllowList, applicationID)
+		}
+	}
+
+	return simulated, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/exchange/policy-decision-point/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicySimulationService_Simulate(t *testing.T) {
+	db := setupTestDB(t)
+	policyService := NewPolicyMetadataService(db)
+	decisionLogService := NewDecisionLogService(db)
+	simulationService := NewPolicySimulationService(db)
+	now := time.Now()
+
+	seedAllowListFields(t, db, policyService, models.AllowList{
+		"app-1": {ExpiresAt: now.AddDate(0, 1, 0), UpdatedAt: now},
+	}, "field1", "field2")
+
+	// Record a decision for each application
+	for _, applicationID := range []string{"app-1", "app-2"} {
+		req := &models.PolicyDecisionRequest{
+			ApplicationID:  applicationID,
+			RequiredFields: []models.PolicyDecisionRequestRecord{{SchemaID: "schema-123", FieldName: "field1"}},
+		}
+		resp, err := policyService.GetPolicyDecision(req)
+		require.NoError(t, err)
+		require.NoError(t, decisionLogService.RecordDecision(req, resp, nil, time.Millisecond))
+	}
+
+	t.Run("revoke and grant flip decisions", func(t *testing.T) {
+		resp, err := simulationService.Simulate(&models.PolicySimulationRequest{
+			Changes: []models.PolicySimulationChange{{
+				SchemaID:           "schema-123",
+				FieldName:          "field1",
+				RevokeApplications: []string{"app-1"},
+				GrantApplications:  []string{"app-2"},
+				GrantDuration:      models.GrantDurationTypeOneMonth,
+			}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.Evaluated)
+		assert.Equal(t, 2, resp.Flipped)
+
+		outcomes := make(map[string]models.PolicySimulationResult)
+		for _, result := range resp.Results {
+			outcomes[result.ApplicationID] = result
+		}
+		assert.Equal(t, models.DecisionOutcomeAllowed, outcomes["app-1"].CurrentOutcome)
+		assert.Equal(t, models.DecisionOutcomeDenied, outcomes["app-1"].SimulatedOutcome)
+		assert.Equal(t, models.DecisionOutcomeDenied, outcomes["app-2"].CurrentOutcome)
+		assert.Equal(t, models.DecisionOutcomeAllowed, outcomes["app-2"].SimulatedOutcome)
+		require.Len(t, outcomes["app-1"].ChangedFields, 1)
+		assert.Equal(t, models.FieldDecisionResultUnauthorized, outcomes["app-1"].ChangedFields[0].SimulatedResult)
+	})
+
+	t.Run("change that affects no sampled decision", func(t *testing.T) {
+		resp, err := simulationService.Simulate(&models.PolicySimulationRequest{
+			Changes: []models.PolicySimulationChange{{SchemaID: "schema-123", FieldName: "field2", RevokeApplications: []string{"app-1"}}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.Evaluated)
+		assert.Equal(t, 0, resp.Affected)
+		assert.Empty(t, resp.Results)
+	})
+
+	t.Run("sample limited to application", func(t *testing.T) {
+		classification := models.ClassificationSensitivePersonal
+		isOwner := false
+		resp, err := simulationService.Simulate(&models.PolicySimulationRequest{
+			ApplicationID: "app-1",
+			Changes: []models.PolicySimulationChange{{
+				SchemaID:       "schema-123",
+				FieldName:      "field1",
+				IsOwner:        &isOwner,
+				Classification: &classification,
+			}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.Evaluated)
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, models.DecisionOutcomeConsentRequired, resp.Results[0].SimulatedOutcome)
+	})
+
+	t.Run("current policy is not modified", func(t *testing.T) {
+		var pm models.PolicyMetadata
+		require.NoError(t, db.Where("field_name = ?", "field1").First(&pm).Error)
+		assert.Contains(t, pm.AllowList, "app-1")
+		assert.NotContains(t, pm.AllowList, "app-2")
+	})
+
+	t.Run("invalid changes", func(t *testing.T) {
+		invalid := []models.PolicySimulationRequest{
+			{},
+			{Changes: []models.PolicySimulationChange{{FieldName: "field1"}}},
+			{Changes: []models.PolicySimulationChange{{SchemaID: "schema-123", FieldName: "unknown"}}},
+			{Changes: []models.PolicySimulationChange{{SchemaID: "schema-123", FieldName: "field1", GrantApplications: []string{"app-3"}}}},
+		}
+		for _, req := range invalid {
+			_, err := simulationService.Simulate(&req)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+		}
+	})
+}
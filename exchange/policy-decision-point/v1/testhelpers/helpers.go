@@ -22,7 +22,7 @@ func OwnerPtr(o models.Owner) *models.Owner {
 }
 
 // SetupTestDB creates an in-memory SQLite database for testing.
-// It creates the policy_metadata table with SQLite-compatible schema.
+// It creates the PDP tables with SQLite-compatible schema.
 // SQLite doesn't support PostgreSQL-specific features like gen_random_uuid(), enums, jsonb.
 func SetupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
@@ -34,6 +34,8 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	createTableSQL := `
 		CREATE TABLE IF NOT EXISTS policy_metadata (
 			id TEXT PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			provider_id TEXT NOT NULL DEFAULT '',
 			schema_id TEXT NOT NULL,
 			field_name TEXT NOT NULL,
 			display_name TEXT,
@@ -41,8 +43,13 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 			source TEXT NOT NULL DEFAULT 'fallback',
 			is_owner INTEGER NOT NULL DEFAULT 0,
 			access_control_type TEXT NOT NULL DEFAULT 'restricted',
+			classification TEXT NOT NULL DEFAULT 'personal',
 			allow_list TEXT NOT NULL DEFAULT '{}',
 			owner TEXT,
+			conditions TEXT NOT NULL DEFAULT '[]',
+			lifecycle_status TEXT NOT NULL DEFAULT 'active',
+			sunset_at DATETIME,
+			lifecycle_message TEXT,
 			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(schema_id, field_name)
@@ -52,5 +59,107 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to create table: %v", err)
 	}
 
+	createRenewalTableSQL := `
+		CREATE TABLE IF NOT EXISTS allow_list_renewal_requests (
+			id TEXT PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			application_id TEXT NOT NULL,
+			fields TEXT NOT NULL DEFAULT '[]',
+			grant_duration TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			reason TEXT,
+			review_comment TEXT,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+	if err := db.Exec(createRenewalTableSQL).Error; err != nil {
+		t.Fatalf("Failed to create allow_list_renewal_requests table: %v", err)
+	}
+
+	createDecisionTablesSQL := []string{`
+		CREATE TABLE IF NOT EXISTS policy_decisions (
+			id TEXT PRIMARY KEY,
+			application_id TEXT NOT NULL,
+			outcome TEXT NOT NULL,
+			latency_ms REAL NOT NULL,
+			policy_version TEXT,
+			error TEXT,
+			context TEXT,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`, `
+		CREATE TABLE IF NOT EXISTS policy_decision_fields (
+			id TEXT PRIMARY KEY,
+			decision_id TEXT NOT NULL REFERENCES policy_decisions(id) ON DELETE CASCADE,
+			schema_id TEXT NOT NULL,
+			field_name TEXT NOT NULL,
+			result TEXT
+		)
+	`}
+	for _, createSQL := range createDecisionTablesSQL {
+		if err := db.Exec(createSQL).Error; err != nil {
+			t.Fatalf("Failed to create policy decision tables: %v", err)
+		}
+	}
+
+	createKillSwitchTableSQL := `
+		CREATE TABLE IF NOT EXISTS policy_kill_switches (
+			id TEXT PRIMARY KEY,
+			target_type TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			field_name TEXT NOT NULL DEFAULT '',
+			active INTEGER NOT NULL DEFAULT 1,
+			reason TEXT,
+			updated_by TEXT,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(target_type, target_id, field_name)
+		)
+	`
+	if err := db.Exec(createKillSwitchTableSQL).Error; err != nil {
+		t.Fatalf("Failed to create policy_kill_switches table: %v", err)
+	}
+
+	createChangeSetTablesSQL := []string{`
+		CREATE TABLE IF NOT EXISTS policy_change_sets (
+			id TEXT PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			title TEXT NOT NULL,
+			description TEXT,
+			kind TEXT NOT NULL DEFAULT 'document',
+			document TEXT NOT NULL,
+			metadata TEXT,
+			allow_list_update TEXT,
+			schema_lifecycle_update TEXT,
+			status TEXT NOT NULL DEFAULT 'pending',
+			proposed_by TEXT NOT NULL,
+			reviewed_by TEXT,
+			review_comment TEXT,
+			diff TEXT,
+			impact TEXT NOT NULL DEFAULT '[]',
+			version INTEGER,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`, `
+		CREATE TABLE IF NOT EXISTS policy_versions (
+			id TEXT PRIMARY KEY,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			version INTEGER NOT NULL,
+			change_set_id TEXT NOT NULL,
+			document TEXT NOT NULL,
+			proposed_by TEXT NOT NULL,
+			approved_by TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(tenant_id, version)
+		)
+	`}
+	for _, createSQL := range createChangeSetTablesSQL {
+		if err := db.Exec(createSQL).Error; err != nil {
+			t.Fatalf("Failed to create policy change set tables: %v", err)
+		}
+	}
+
 	return db
 }
@@ -7,6 +7,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 )
 
 var (
@@ -286,6 +289,13 @@ func isCommonPathWord(word string) bool {
 	return commonWords[strings.ToLower(word)]
 }
 
+// Meter returns the shared meter for service-specific instruments (e.g. domain SLO metrics).
+// Instruments created from it are exported alongside the HTTP metrics on the same endpoint.
+func Meter() metric.Meter {
+	ensureInitialized()
+	return otel.Meter("opendif")
+}
+
 // RecordExternalCall records an external service call
 // This now uses OpenTelemetry under the hood, but maintains backward compatibility
 func RecordExternalCall(target, operation string, duration time.Duration, err error) {
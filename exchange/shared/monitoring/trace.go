@@ -3,6 +3,7 @@ package monitoring
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -10,6 +11,9 @@ import (
 // TraceIDHeader is the HTTP header name for trace ID
 const TraceIDHeader = "X-Trace-ID"
 
+// TraceparentHeader is the W3C Trace Context header set by OpenTelemetry-instrumented callers
+const TraceparentHeader = "traceparent"
+
 // traceIDKey is the context key for trace ID
 // This is used for distributed tracing and observability correlation
 type traceIDKey struct{}
@@ -30,30 +34,58 @@ func WithTraceID(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, traceIDKey{}, traceID)
 }
 
-// ExtractTraceIDFromRequest extracts trace ID from HTTP header and adds it to context
-// If no trace ID is found in header, generates a new one
+// requestTraceID returns the trace ID a request belongs to: the X-Trace-ID header if it holds a UUID,
+// otherwise the trace ID of a W3C traceparent header (OpenTelemetry trace IDs are 16 bytes, so they are
+// written in UUID form), otherwise a new UUID. Audit logs store trace IDs as UUIDs, so other values are not used.
+func requestTraceID(r *http.Request) string {
+	if traceID, err := uuid.Parse(r.Header.Get(TraceIDHeader)); err == nil {
+		return traceID.String()
+	}
+	if traceID, ok := ParseTraceparent(r.Header.Get(TraceparentHeader)); ok {
+		return traceID
+	}
+	return uuid.New().String()
+}
+
+// ParseTraceparent returns the trace ID of a W3C traceparent value (version-traceid-parentid-flags),
+// in UUID form, and whether the value was valid
+func ParseTraceparent(value string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return "", false
+	}
+	for _, part := range parts[:4] {
+		if strings.Trim(part, "0123456789abcdef") != "" {
+			return "", false
+		}
+	}
+	if parts[1] == strings.Repeat("0", 32) || parts[2] == strings.Repeat("0", 16) {
+		return "", false
+	}
+	traceID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return "", false
+	}
+	return traceID.String(), true
+}
+
+// ExtractTraceIDFromRequest extracts trace ID from HTTP headers and adds it to context
+// If no trace ID is found in the headers, generates a new one
 // This ensures trace ID propagation across HTTP service boundaries
 func ExtractTraceIDFromRequest(r *http.Request) context.Context {
-	traceID := r.Header.Get(TraceIDHeader)
-	if traceID == "" {
-		traceID = uuid.New().String()
-	}
-	return WithTraceID(r.Context(), traceID)
+	return WithTraceID(r.Context(), requestTraceID(r))
 }
 
 // TraceIDMiddleware extracts or generates a trace ID and adds it to the request context
-// It checks for X-Trace-ID header first, and if not present, generates a new UUID
+// It checks for the X-Trace-ID header first, then a W3C traceparent header, and if neither is present
+// generates a new UUID
 // The trace ID is also set in the response header for client visibility
 // This middleware should be applied early in the middleware chain to ensure trace ID
 // is available throughout the request lifecycle
 func TraceIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check for existing trace ID in header
-		traceID := r.Header.Get(TraceIDHeader)
-		if traceID == "" {
-			// Generate new trace ID if not present
-			traceID = uuid.New().String()
-		}
+		traceID := requestTraceID(r)
 
 		// Add trace ID to context using the shared traceIDKey
 		ctx := WithTraceID(r.Context(), traceID)
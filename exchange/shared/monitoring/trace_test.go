@@ -0,0 +1,66 @@
+package monitoring
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	traceID, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if !ok || traceID != "4bf92f35-77b3-4da6-a3ce-929d0e0e4736" {
+		t.Errorf("ParseTraceparent() = %q, %v", traceID, ok)
+	}
+	if _, ok := ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future"); !ok {
+		t.Error("later versions may append fields")
+	}
+
+	for _, value := range []string{
+		"",
+		"4bf92f3577b34da6a3ce929d0e0e4736",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		if _, ok := ParseTraceparent(value); ok {
+			t.Errorf("ParseTraceparent(%q) should be invalid", value)
+		}
+	}
+}
+
+func TestTraceIDMiddleware(t *testing.T) {
+	var traceID string
+	handler := TraceIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = GetTraceIDFromContext(r.Context())
+	}))
+	call := func(headers map[string]string) string {
+		req := httptest.NewRequest(http.MethodPost, "/public/graphql", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if got := w.Header().Get(TraceIDHeader); got != traceID {
+			t.Errorf("response %s = %q, context has %q", TraceIDHeader, got, traceID)
+		}
+		return traceID
+	}
+
+	explicit := uuid.New().String()
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got := call(map[string]string{TraceIDHeader: explicit, TraceparentHeader: traceparent}); got != explicit {
+		t.Errorf("X-Trace-ID should take precedence, got %q", got)
+	}
+	if got := call(map[string]string{TraceparentHeader: traceparent}); got != "4bf92f35-77b3-4da6-a3ce-929d0e0e4736" {
+		t.Errorf("traceparent trace ID not used, got %q", got)
+	}
+	if got := call(map[string]string{TraceIDHeader: "not-a-uuid"}); got == "not-a-uuid" {
+		t.Error("non-UUID trace IDs should be replaced")
+	} else if _, err := uuid.Parse(got); err != nil {
+		t.Errorf("generated trace ID %q is not a UUID", got)
+	}
+}
@@ -1,3 +1,15 @@
 module github.com/gov-dx-sandbox/exchange/shared/utils
 
 go 1.24.6
+
+require (
+	github.com/gov-dx-sandbox/shared/health v0.0.0
+	github.com/gov-dx-sandbox/shared/requestid v0.0.0
+	github.com/gov-dx-sandbox/shared/response v0.0.0
+)
+
+replace github.com/gov-dx-sandbox/shared/response => ../../../shared/response
+
+replace github.com/gov-dx-sandbox/shared/health => ../../../shared/health
+
+replace github.com/gov-dx-sandbox/shared/requestid => ../../../shared/requestid
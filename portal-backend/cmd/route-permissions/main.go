@@ -0,0 +1,74 @@
+// Command route-permissions prints the route permission report: every route of the portal's
+// authorization tables with the permission, roles and token scopes needed to call it.
+//
+//	route-permissions -format markdown > route-permissions.md
+//
+// The report is generated from the same tables the authorization middleware enforces, so it is
+// regenerated rather than edited whenever a route's requirements change.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	authutils "github.com/gov-dx-sandbox/portal-backend/v1/utils"
+)
+
+func main() {
+	format := flag.String("format", "markdown", "Output format: markdown or json")
+	flag.Parse()
+
+	if err := writeReport(os.Stdout, *format, authutils.RoutePermissionReport()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+
+func writeReport(w io.Writer, format string, report []models.RouteAccess) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "markdown":
+		return writeMarkdown(w, report)
+	default:
+		return fmt.Errorf("unknown format %q, expected markdown or json", format)
+	}
+}
+
+func writeMarkdown(w io.Writer, report []models.RouteAccess) error {
+	var b strings.Builder
+	b.WriteString("| Method | Path | Permission | Owner only | Roles | Scopes |\n")
+	b.WriteString("|--------|------|------------|------------|-------|--------|\n")
+	for _, route := range report {
+		roles := make([]string, len(route.Roles))
+		for i, role := range route.Roles {
+			roles[i] = role.String()
+		}
+		permission := string(route.Permission)
+		if permission == "" {
+			permission = "-"
+		}
+		ownerOnly := "no"
+		if route.OwnershipRequired {
+			ownerOnly = "yes"
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s | %s |\n",
+			route.Method, route.Path, permission, ownerOnly, orDash(strings.Join(roles, ", ")), orDash(strings.Join(route.Scopes, " ")))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
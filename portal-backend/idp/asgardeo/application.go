@@ -174,3 +174,58 @@ func (a *Client) DeleteApplication(ctx context.Context, applicationId string) er
 
 	return nil
 }
+
+func (a *Client) RegenerateApplicationSecret(ctx context.Context, applicationId string) (*idp.ApplicationOIDCInfo, error) {
+	url := fmt.Sprintf("%s/api/server/v1/applications/%s/inbound-protocols/oidc/regenerate-secret", a.BaseURL, applicationId)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := a.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed: %s", res.Status)
+	}
+	var oidcResponse AsgardeoApplicationOIDCResponse
+
+	err = json.NewDecoder(res.Body).Decode(&oidcResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	oidcInfo := idp.ApplicationOIDCInfo{
+		ClientId:     oidcResponse.ClientId,
+		ClientSecret: oidcResponse.ClientSecret,
+	}
+
+	return &oidcInfo, nil
+}
+
+func (a *Client) RevokeApplicationCredentials(ctx context.Context, applicationId string) error {
+	url := fmt.Sprintf("%s/api/server/v1/applications/%s/inbound-protocols/oidc/revoke", a.BaseURL, applicationId)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := a.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("request failed with status %s", res.Status)
+	}
+
+	return nil
+}
@@ -441,3 +441,115 @@ func TestClient_DeleteApplication(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestClient_RegenerateApplicationSecret(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Handle OAuth token request
+			if r.URL.Path == "/oauth2/token" && r.Method == "POST" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"access_token": "test-token",
+					"token_type":   "Bearer",
+					"expires_in":   3600,
+				})
+				return
+			}
+			// Handle secret regeneration
+			if r.URL.Path == "/api/server/v1/applications/app-123/inbound-protocols/oidc/regenerate-secret" && r.Method == "POST" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"clientId":     "client-123",
+					"clientSecret": "new-secret",
+				})
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "client-id", "client-secret", []string{})
+		oidcInfo, err := client.RegenerateApplicationSecret(context.Background(), "app-123")
+
+		assert.NoError(t, err)
+		assert.NotNil(t, oidcInfo)
+		assert.Equal(t, "client-123", oidcInfo.ClientId)
+		assert.Equal(t, "new-secret", oidcInfo.ClientSecret)
+	})
+
+	t.Run("Non200Status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Handle OAuth token request
+			if r.URL.Path == "/oauth2/token" && r.Method == "POST" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"access_token": "test-token",
+					"token_type":   "Bearer",
+					"expires_in":   3600,
+				})
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "client-id", "client-secret", []string{})
+		oidcInfo, err := client.RegenerateApplicationSecret(context.Background(), "app-123")
+
+		assert.Error(t, err)
+		assert.Nil(t, oidcInfo)
+		assert.Contains(t, err.Error(), "request failed")
+	})
+}
+
+func TestClient_RevokeApplicationCredentials(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Handle OAuth token request
+			if r.URL.Path == "/oauth2/token" && r.Method == "POST" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"access_token": "test-token",
+					"token_type":   "Bearer",
+					"expires_in":   3600,
+				})
+				return
+			}
+			// Handle credential revocation
+			if r.URL.Path == "/api/server/v1/applications/app-123/inbound-protocols/oidc/revoke" && r.Method == "POST" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "client-id", "client-secret", []string{})
+		err := client.RevokeApplicationCredentials(context.Background(), "app-123")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Non2xxStatus", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Handle OAuth token request
+			if r.URL.Path == "/oauth2/token" && r.Method == "POST" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"access_token": "test-token",
+					"token_type":   "Bearer",
+					"expires_in":   3600,
+				})
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "client-id", "client-secret", []string{})
+		err := client.RevokeApplicationCredentials(context.Background(), "app-123")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status")
+	})
+}
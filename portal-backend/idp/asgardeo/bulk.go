@@ -0,0 +1,216 @@
+package asgardeo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+)
+
+// maxBulkOperations is how many operations are sent in one SCIM bulk request; larger imports are
+// split into several requests
+const maxBulkOperations = 100
+
+type BulkOperation struct {
+	Method string      `json:"method"`
+	BulkID string      `json:"bulkId,omitempty"`
+	Path   string      `json:"path"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+type BulkRequestBody struct {
+	Schemas    []string        `json:"schemas"`
+	Operations []BulkOperation `json:"Operations"`
+}
+
+type BulkOperationResponse struct {
+	BulkID   string     `json:"bulkId"`
+	Method   string     `json:"method"`
+	Location string     `json:"location"`
+	Status   BulkStatus `json:"status"`
+	Response *struct {
+		Detail string `json:"detail"`
+	} `json:"response,omitempty"`
+}
+
+type BulkResponseBody struct {
+	Operations []BulkOperationResponse `json:"Operations"`
+}
+
+// BulkStatus is the HTTP status of a bulk operation. SCIM specifies it as a string, while Asgardeo
+// returns an object with a numeric code, so both are accepted.
+type BulkStatus int
+
+func (s *BulkStatus) UnmarshalJSON(data []byte) error {
+	var status struct {
+		Code json.Number `json:"code"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		var code json.Number
+		if err := json.Unmarshal(data, &code); err != nil {
+			return fmt.Errorf("invalid bulk operation status %s", data)
+		}
+		status.Code = code
+	}
+	code, err := strconv.Atoi(status.Code.String())
+	if err != nil {
+		return fmt.Errorf("invalid bulk operation status %s", data)
+	}
+	*s = BulkStatus(code)
+	return nil
+}
+
+// sendBulk sends operations in SCIM bulk requests of at most maxBulkOperations and returns the
+// response to each operation, keyed by bulk ID
+func (a *Client) sendBulk(ctx context.Context, operations []BulkOperation) (map[string]BulkOperationResponse, error) {
+	url := fmt.Sprintf("%s/scim2/Bulk", a.BaseURL)
+	responses := make(map[string]BulkOperationResponse, len(operations))
+
+	for start := 0; start < len(operations); start += maxBulkOperations {
+		end := min(start+maxBulkOperations, len(operations))
+		body := BulkRequestBody{
+			Schemas:    []string{"urn:ietf:params:scim:api:messages:2.0:BulkRequest"},
+			Operations: operations[start:end],
+		}
+
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/scim+json")
+
+		res, err := a.Client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return nil, fmt.Errorf("failed to send bulk request, status code: %d", res.StatusCode)
+		}
+
+		var response BulkResponseBody
+		err = json.NewDecoder(res.Body).Decode(&response)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for _, operation := range response.Operations {
+			responses[operation.BulkID] = operation
+		}
+	}
+
+	return responses, nil
+}
+
+// bulkOperationError returns why an operation failed, or nil if it answered with one of the wanted
+// statuses
+func bulkOperationError(response BulkOperationResponse, found bool, want ...int) error {
+	if !found {
+		return fmt.Errorf("no result for bulk operation")
+	}
+	if slices.Contains(want, int(response.Status)) {
+		return nil
+	}
+	if response.Response != nil && response.Response.Detail != "" {
+		return fmt.Errorf("status code: %d: %s", response.Status, response.Response.Detail)
+	}
+	return fmt.Errorf("status code: %d", response.Status)
+}
+
+// BulkCreateUsers creates users with SCIM bulk requests. Like CreateUser, each user is asked to set
+// their password.
+func (a *Client) BulkCreateUsers(ctx context.Context, users []*idp.User) ([]idp.BulkUserResult, error) {
+	operations := make([]BulkOperation, len(users))
+	for i, user := range users {
+		operations[i] = BulkOperation{
+			Method: http.MethodPost,
+			BulkID: fmt.Sprintf("user-%d", i),
+			Path:   "/Users",
+			Data:   newCreateUserRequestBody(user),
+		}
+	}
+
+	responses, err := a.sendBulk(ctx, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]idp.BulkUserResult, len(users))
+	for i, user := range users {
+		response, found := responses[operations[i].BulkID]
+		if err := bulkOperationError(response, found, http.StatusCreated); err != nil {
+			results[i].Err = fmt.Errorf("failed to create user: %w", err)
+			continue
+		}
+		if response.Location == "" {
+			results[i].Err = fmt.Errorf("failed to create user: created user has no location")
+			continue
+		}
+		// Bulk responses only carry the location of the created user
+		results[i].User = &idp.UserInfo{
+			Id:          path.Base(response.Location),
+			Email:       user.Email,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			PhoneNumber: user.PhoneNumber,
+		}
+	}
+	return results, nil
+}
+
+// BulkAddToGroup adds members to a group with SCIM bulk requests, one patch operation per member
+func (a *Client) BulkAddToGroup(ctx context.Context, groupId string, members []*idp.GroupMember) ([]error, error) {
+	operations := make([]BulkOperation, len(members))
+	for i, member := range members {
+		operations[i] = BulkOperation{
+			Method: http.MethodPatch,
+			BulkID: fmt.Sprintf("member-%d", i),
+			Path:   "/Groups/" + groupId,
+			Data: PatchGroupRequestBody{
+				Schemas: []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+				Operations: []PatchGroupOperation{
+					{
+						Op: "add",
+						Value: map[string]interface{}{
+							"members": []GroupMemberRequestBody{
+								{
+									Value:   member.Value,
+									Display: member.Display,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	responses, err := a.sendBulk(ctx, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(members))
+	for i := range members {
+		response, found := responses[operations[i].BulkID]
+		// Asgardeo answers group patches with 200, or 204 when no representation is returned
+		if err := bulkOperationError(response, found, http.StatusOK, http.StatusNoContent); err != nil {
+			errs[i] = fmt.Errorf("failed to add member to group: %w", err)
+		}
+	}
+	return errs, nil
+}
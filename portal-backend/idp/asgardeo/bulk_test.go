@@ -0,0 +1,149 @@
+package asgardeo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/stretchr/testify/assert"
+)
+
+// newBulkServer answers SCIM bulk requests with respond, counting the requests
+func newBulkServer(t *testing.T, requests *int, respond func(op BulkOperation) map[string]interface{}) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token" && r.Method == "POST" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "test-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+			return
+		}
+		if r.URL.Path == "/scim2/Bulk" && r.Method == "POST" {
+			*requests++
+			assert.Equal(t, "application/scim+json", r.Header.Get("Content-Type"))
+			var body struct {
+				Operations []struct {
+					BulkOperation
+					Data json.RawMessage `json:"data"`
+				} `json:"Operations"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			operations := []map[string]interface{}{}
+			for _, op := range body.Operations {
+				op.BulkOperation.Data = op.Data
+				response := respond(op.BulkOperation)
+				response["bulkId"] = op.BulkID
+				response["method"] = op.Method
+				operations = append(operations, response)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"Operations": operations})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_BulkCreateUsers(t *testing.T) {
+	t.Run("PerUserResults", func(t *testing.T) {
+		var requests int
+		server := newBulkServer(t, &requests, func(op BulkOperation) map[string]interface{} {
+			assert.Equal(t, "POST", op.Method)
+			assert.Equal(t, "/Users", op.Path)
+			var user CreateUserRequestBody
+			assert.NoError(t, json.Unmarshal(op.Data.(json.RawMessage), &user))
+			if user.Email == "taken@example.com" {
+				return map[string]interface{}{
+					"status":   map[string]interface{}{"code": 409},
+					"response": map[string]interface{}{"detail": "User already exists"},
+				}
+			}
+			return map[string]interface{}{
+				"status":   "201",
+				"location": "https://api.asgardeo.io/t/testorg/scim2/Users/id-" + user.Name.GivenName,
+			}
+		})
+		client := NewClient(server.URL, "client-id", "client-secret", []string{})
+
+		results, err := client.BulkCreateUsers(context.Background(), []*idp.User{
+			{FirstName: "Jane", Email: "jane@example.com"},
+			{FirstName: "Taken", Email: "taken@example.com"},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "id-Jane", results[0].User.Id)
+		assert.Equal(t, "jane@example.com", results[0].User.Email)
+		assert.Nil(t, results[1].User)
+		assert.EqualError(t, results[1].Err, "failed to create user: status code: 409: User already exists")
+	})
+
+	t.Run("SplitsLargeImports", func(t *testing.T) {
+		var requests int
+		server := newBulkServer(t, &requests, func(op BulkOperation) map[string]interface{} {
+			return map[string]interface{}{"status": map[string]interface{}{"code": 201}, "location": "/scim2/Users/" + op.BulkID}
+		})
+		client := NewClient(server.URL, "client-id", "client-secret", []string{})
+
+		users := make([]*idp.User, maxBulkOperations+1)
+		for i := range users {
+			users[i] = &idp.User{Email: "user@example.com"}
+		}
+		results, err := client.BulkCreateUsers(context.Background(), users)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, requests)
+		assert.Equal(t, "user-100", results[100].User.Id)
+	})
+
+	t.Run("RequestFailure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/oauth2/token" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "test-token", "token_type": "Bearer"})
+				return
+			}
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}))
+		defer server.Close()
+		client := NewClient(server.URL, "client-id", "client-secret", []string{})
+
+		results, err := client.BulkCreateUsers(context.Background(), []*idp.User{{Email: "jane@example.com"}})
+
+		assert.Nil(t, results)
+		assert.EqualError(t, err, "failed to send bulk request, status code: 413")
+	})
+}
+
+func TestClient_BulkAddToGroup(t *testing.T) {
+	var requests int
+	server := newBulkServer(t, &requests, func(op BulkOperation) map[string]interface{} {
+		assert.Equal(t, "PATCH", op.Method)
+		assert.Equal(t, "/Groups/group-123", op.Path)
+		if op.BulkID == "member-1" {
+			return map[string]interface{}{"status": map[string]interface{}{"code": 404}}
+		}
+		return map[string]interface{}{"status": map[string]interface{}{"code": 200}}
+	})
+	client := NewClient(server.URL, "client-id", "client-secret", []string{})
+
+	errs, err := client.BulkAddToGroup(context.Background(), "group-123", []*idp.GroupMember{
+		{Value: "user-1", Display: "jane@example.com"},
+		{Value: "missing", Display: "missing@example.com"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], "failed to add member to group: status code: 404")
+
+	// Providers detect bulk support through the optional interface
+	var _ idp.BulkUserManager = client
+}
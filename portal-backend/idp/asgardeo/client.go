@@ -4,13 +4,16 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/gov-dx-sandbox/portal-backend/idp"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
 type Client struct {
 	BaseURL     string
 	OAuthConfig *clientcredentials.Config
-	Client      *http.Client
+	// Tokens caches the access token authenticating Client's requests
+	Tokens *idp.TokenSource
+	Client *http.Client
 }
 
 func NewClient(baseUrl string, clientId string, clientSecret string, scopes []string) *Client {
@@ -20,10 +23,17 @@ func NewClient(baseUrl string, clientId string, clientSecret string, scopes []st
 		TokenURL:     baseUrl + "/oauth2/token",
 		Scopes:       scopes,
 	}
+	tokens := idp.NewTokenSource(oauthConfig)
 
 	return &Client{
 		BaseURL:     baseUrl,
 		OAuthConfig: oauthConfig,
-		Client:      oauthConfig.Client(context.Background()),
+		Tokens:      tokens,
+		Client:      tokens.Client(),
 	}
 }
+
+// CheckToken reports whether the management API access token can be refreshed
+func (a *Client) CheckToken(ctx context.Context) error {
+	return a.Tokens.Check(ctx)
+}
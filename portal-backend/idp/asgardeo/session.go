@@ -0,0 +1,100 @@
+package asgardeo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+)
+
+// UserSessionsResponseBody is the response of the user sessions API. Login and last access times are
+// milliseconds since the epoch, as strings.
+type UserSessionsResponseBody struct {
+	UserID   string `json:"userId"`
+	Sessions []struct {
+		ID             string `json:"id"`
+		IP             string `json:"ip"`
+		UserAgent      string `json:"userAgent"`
+		LoginTime      string `json:"loginTime"`
+		LastAccessTime string `json:"lastAccessTime"`
+		Applications   []struct {
+			AppName string `json:"appName"`
+			AppID   string `json:"appId"`
+		} `json:"applications"`
+	} `json:"sessions"`
+}
+
+// parseEpochMillis parses a time given in milliseconds since the epoch, returning the zero time if it is
+// empty or invalid
+func parseEpochMillis(value string) time.Time {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || millis <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis).UTC()
+}
+
+// GetUserSessions lists the active login sessions of a user with the user sessions API
+func (a *Client) GetUserSessions(ctx context.Context, userId string) ([]idp.UserSession, error) {
+	url := fmt.Sprintf("%s/api/users/v1/%s/sessions", a.BaseURL, userId)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := a.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get user sessions, status code: %d", res.StatusCode)
+	}
+
+	var response UserSessionsResponseBody
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	sessions := make([]idp.UserSession, len(response.Sessions))
+	for i, session := range response.Sessions {
+		sessions[i] = idp.UserSession{
+			Id:             session.ID,
+			LoginTime:      parseEpochMillis(session.LoginTime),
+			LastAccessTime: parseEpochMillis(session.LastAccessTime),
+			IPAddress:      session.IP,
+			UserAgent:      session.UserAgent,
+		}
+		for _, app := range session.Applications {
+			sessions[i].Applications = append(sessions[i].Applications, app.AppName)
+		}
+	}
+	return sessions, nil
+}
+
+// RevokeUserSessions terminates every login session of a user with the user sessions API
+func (a *Client) RevokeUserSessions(ctx context.Context, userId string) error {
+	url := fmt.Sprintf("%s/api/users/v1/%s/sessions", a.BaseURL, userId)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := a.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to revoke user sessions, status code: %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package asgardeo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSessionServer answers token requests and calls handler for the user sessions API
+func newSessionServer(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token" && r.Method == "POST" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "test-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+			return
+		}
+		if r.URL.Path == "/api/users/v1/user-123/sessions" {
+			handler(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, "client-id", "client-secret", []string{})
+}
+
+func TestClient_GetUserSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		client := newSessionServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "GET", r.Method)
+			w.Write([]byte(`{"userId":"user-123","sessions":[{"id":"session-1","ip":"10.0.0.1","userAgent":"Mozilla/5.0",
+				"loginTime":"1760000000000","lastAccessTime":"1760000600000","applications":[{"appName":"Member Portal","appId":"app-1"}]}]}`))
+		})
+
+		sessions, err := client.GetUserSessions(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 1)
+		assert.Equal(t, "session-1", sessions[0].Id)
+		assert.Equal(t, "10.0.0.1", sessions[0].IPAddress)
+		assert.Equal(t, "Mozilla/5.0", sessions[0].UserAgent)
+		assert.Equal(t, time.UnixMilli(1760000000000).UTC(), sessions[0].LoginTime)
+		assert.Equal(t, time.UnixMilli(1760000600000).UTC(), sessions[0].LastAccessTime)
+		assert.Equal(t, []string{"Member Portal"}, sessions[0].Applications)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		client := newSessionServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+
+		sessions, err := client.GetUserSessions(context.Background(), "user-123")
+
+		assert.Nil(t, sessions)
+		assert.EqualError(t, err, "failed to get user sessions, status code: 403")
+	})
+}
+
+func TestClient_RevokeUserSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var revoked bool
+		client := newSessionServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "DELETE", r.Method)
+			revoked = true
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		assert.NoError(t, client.RevokeUserSessions(context.Background(), "user-123"))
+		assert.True(t, revoked)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		client := newSessionServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		assert.EqualError(t, client.RevokeUserSessions(context.Background(), "user-123"), "failed to revoke user sessions, status code: 500")
+	})
+}
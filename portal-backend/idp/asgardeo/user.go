@@ -103,9 +103,8 @@ func (a *Client) GetUser(ctx context.Context, userId string) (*idp.UserInfo, err
 	return userInfo, nil
 }
 
-func (a *Client) CreateUser(ctx context.Context, userInfo *idp.User) (*idp.UserInfo, error) {
-	url := fmt.Sprintf("%s/scim2/Users", a.BaseURL)
-
+// newCreateUserRequestBody builds the SCIM user of a new user, who is asked to set their password
+func newCreateUserRequestBody(userInfo *idp.User) CreateUserRequestBody {
 	body := CreateUserRequestBody{
 		UserName: fmt.Sprintf("DEFAULT/%s", userInfo.Email),
 		Email:    userInfo.Email,
@@ -136,6 +135,14 @@ func (a *Client) CreateUser(ctx context.Context, userInfo *idp.User) (*idp.UserI
 		}
 	}
 
+	return body
+}
+
+func (a *Client) CreateUser(ctx context.Context, userInfo *idp.User) (*idp.UserInfo, error) {
+	url := fmt.Sprintf("%s/scim2/Users", a.BaseURL)
+
+	body := newCreateUserRequestBody(userInfo)
+
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
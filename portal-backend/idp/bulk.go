@@ -0,0 +1,44 @@
+package idp
+
+import "context"
+
+// BulkUserManager is implemented by providers that can create many users or group memberships in
+// a single request, such as with SCIM bulk operations. Results are in the order of the input and
+// each item succeeds or fails on its own.
+type BulkUserManager interface {
+	BulkCreateUsers(ctx context.Context, users []*User) ([]BulkUserResult, error)
+	BulkAddToGroup(ctx context.Context, groupId string, members []*GroupMember) ([]error, error)
+}
+
+// BulkUserResult is the outcome of creating one user of a bulk request: the created user, or why it
+// could not be created
+type BulkUserResult struct {
+	User *UserInfo
+	Err  error
+}
+
+// BulkCreateUsers creates users with a single bulk request if the provider supports it, and one at a
+// time otherwise. The error is only set if the bulk request as a whole failed.
+func BulkCreateUsers(ctx context.Context, provider UserManager, users []*User) ([]BulkUserResult, error) {
+	if bulk, ok := provider.(BulkUserManager); ok {
+		return bulk.BulkCreateUsers(ctx, users)
+	}
+	results := make([]BulkUserResult, len(users))
+	for i, user := range users {
+		results[i].User, results[i].Err = provider.CreateUser(ctx, user)
+	}
+	return results, nil
+}
+
+// BulkAddToGroup adds members to a group with a single bulk request if the provider supports it, and
+// one at a time otherwise. The returned slice holds the error of each member, nil if it was added.
+func BulkAddToGroup(ctx context.Context, provider GroupManager, groupId string, members []*GroupMember) ([]error, error) {
+	if bulk, ok := provider.(BulkUserManager); ok {
+		return bulk.BulkAddToGroup(ctx, groupId, members)
+	}
+	errs := make([]error, len(members))
+	for i, member := range members {
+		errs[i] = provider.AddMemberToGroup(ctx, groupId, member)
+	}
+	return errs, nil
+}
@@ -0,0 +1,54 @@
+package idp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// oneByOneProvider implements users and group membership without bulk support
+type oneByOneProvider struct {
+	GroupManager
+	UserManager
+	created []string
+	added   []string
+}
+
+func (p *oneByOneProvider) CreateUser(ctx context.Context, user *User) (*UserInfo, error) {
+	if user.Email == "taken@example.com" {
+		return nil, errors.New("user exists")
+	}
+	p.created = append(p.created, user.Email)
+	return &UserInfo{Id: "id-" + user.Email, Email: user.Email}, nil
+}
+
+func (p *oneByOneProvider) AddMemberToGroup(ctx context.Context, groupId string, member *GroupMember) error {
+	p.added = append(p.added, member.Value)
+	return nil
+}
+
+func TestBulkCreateUsers_FallsBackToSingleRequests(t *testing.T) {
+	provider := &oneByOneProvider{}
+
+	results, err := BulkCreateUsers(context.Background(), provider, []*User{
+		{Email: "jane@example.com"},
+		{Email: "taken@example.com"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id-jane@example.com", results[0].User.Id)
+	assert.EqualError(t, results[1].Err, "user exists")
+	assert.Equal(t, []string{"jane@example.com"}, provider.created)
+}
+
+func TestBulkAddToGroup_FallsBackToSingleRequests(t *testing.T) {
+	provider := &oneByOneProvider{}
+
+	errs, err := BulkAddToGroup(context.Background(), provider, "group-1", []*GroupMember{{Value: "a"}, {Value: "b"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, []string{"a", "b"}, provider.added)
+}
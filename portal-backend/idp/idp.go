@@ -21,6 +21,11 @@ type ApplicationManager interface {
 	CreateApplication(ctx context.Context, app *Application) (*string, error)
 	GetApplicationOIDC(ctx context.Context, applicationId string) (*ApplicationOIDCInfo, error)
 	DeleteApplication(ctx context.Context, applicationId string) error
+	// RegenerateApplicationSecret issues a new client secret, invalidating the previous one.
+	// Revoked client credentials are reactivated.
+	RegenerateApplicationSecret(ctx context.Context, applicationId string) (*ApplicationOIDCInfo, error)
+	// RevokeApplicationCredentials revokes the client ID and secret so no new tokens can be issued
+	RevokeApplicationCredentials(ctx context.Context, applicationId string) error
 }
 
 type User struct {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gov-dx-sandbox/portal-backend/idp"
 	"github.com/gov-dx-sandbox/portal-backend/idp/asgardeo"
+	"github.com/gov-dx-sandbox/portal-backend/idp/keycloak"
 )
 
 type FactoryConfig struct {
@@ -14,6 +15,8 @@ type FactoryConfig struct {
 	ClientID     string
 	ClientSecret string
 	Scopes       []string
+	// Realm is the Keycloak realm holding the portal's users, groups and clients; other providers ignore it
+	Realm string
 }
 
 // asgardeoAdapter adapts *asgardeo.Client to match the idp.IdentityProviderAPI
@@ -31,6 +34,11 @@ func NewIdpAPIProvider(cfg FactoryConfig) (idp.IdentityProviderAPI, error) {
 	switch cfg.ProviderType {
 	case idp.ProviderAsgardeo:
 		return &asgardeoAdapter{asgardeo.NewClient(cfg.BaseURL, cfg.ClientID, cfg.ClientSecret, cfg.Scopes)}, nil
+	case idp.ProviderKeycloak:
+		if cfg.Realm == "" {
+			return nil, errors.New("keycloak provider requires a realm")
+		}
+		return keycloak.NewClient(cfg.BaseURL, cfg.Realm, cfg.ClientID, cfg.ClientSecret, cfg.Scopes), nil
 	default:
 		return nil, errors.New("unsupported provider type")
 	}
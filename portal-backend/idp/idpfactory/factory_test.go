@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/idp/keycloak"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,6 +25,35 @@ func TestNewIdpAPIProvider(t *testing.T) {
 		assert.NotNil(t, provider)
 	})
 
+	t.Run("KeycloakProvider", func(t *testing.T) {
+		cfg := FactoryConfig{
+			ProviderType: idp.ProviderKeycloak,
+			BaseURL:      "https://keycloak.example.com",
+			Realm:        "opendif",
+			ClientID:     "test-client-id",
+			ClientSecret: "test-client-secret",
+		}
+
+		provider, err := NewIdpAPIProvider(cfg)
+
+		assert.NoError(t, err)
+		assert.IsType(t, &keycloak.Client{}, provider)
+	})
+
+	t.Run("KeycloakProviderWithoutRealm", func(t *testing.T) {
+		cfg := FactoryConfig{
+			ProviderType: idp.ProviderKeycloak,
+			BaseURL:      "https://keycloak.example.com",
+			ClientID:     "test-client-id",
+			ClientSecret: "test-client-secret",
+		}
+
+		provider, err := NewIdpAPIProvider(cfg)
+
+		assert.Nil(t, provider)
+		assert.EqualError(t, err, "keycloak provider requires a realm")
+	})
+
 	t.Run("UnsupportedProvider", func(t *testing.T) {
 		cfg := FactoryConfig{
 			ProviderType: idp.ProviderType("unsupported"),
@@ -0,0 +1,194 @@
+package keycloak
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+)
+
+// ClientRepresentation is the subset of Keycloak's client representation managed by the portal
+type ClientRepresentation struct {
+	ID                        string `json:"id,omitempty"`
+	ClientID                  string `json:"clientId"`
+	Name                      string `json:"name"`
+	Description               string `json:"description"`
+	Enabled                   bool   `json:"enabled"`
+	Protocol                  string `json:"protocol,omitempty"`
+	PublicClient              bool   `json:"publicClient"`
+	ClientAuthenticatorType   string `json:"clientAuthenticatorType,omitempty"`
+	ServiceAccountsEnabled    bool   `json:"serviceAccountsEnabled"`
+	StandardFlowEnabled       bool   `json:"standardFlowEnabled"`
+	DirectAccessGrantsEnabled bool   `json:"directAccessGrantsEnabled"`
+}
+
+// CredentialRepresentation is Keycloak's representation of a client secret
+type CredentialRepresentation struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// newClientID generates the OAuth client ID of an application, as Asgardeo does for M2M applications
+func newClientID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (k *Client) getClient(ctx context.Context, applicationId string) (*ClientRepresentation, error) {
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/clients/%s", applicationId), nil, http.StatusOK, "get application")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var client ClientRepresentation
+	if err := json.NewDecoder(res.Body).Decode(&client); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &client, nil
+}
+
+func (k *Client) GetApplicationInfo(ctx context.Context, applicationId string) (*idp.ApplicationInfo, error) {
+	client, err := k.getClient(ctx, applicationId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &idp.ApplicationInfo{
+		Id:          client.ID,
+		Name:        client.Name,
+		Description: client.Description,
+		ClientId:    client.ClientID,
+	}, nil
+}
+
+func (k *Client) GetApplicationOIDC(ctx context.Context, applicationId string) (*idp.ApplicationOIDCInfo, error) {
+	client, err := k.getClient(ctx, applicationId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/clients/%s/client-secret", applicationId), nil, http.StatusOK, "get client secret")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var secret CredentialRepresentation
+	if err := json.NewDecoder(res.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &idp.ApplicationOIDCInfo{
+		ClientId:     client.ClientID,
+		ClientSecret: secret.Value,
+	}, nil
+}
+
+// CreateApplication creates a confidential OIDC client that can only obtain tokens with the client
+// credentials grant. The template ID is Asgardeo-specific and ignored.
+func (k *Client) CreateApplication(ctx context.Context, app *idp.Application) (*string, error) {
+	clientId, err := newClientID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client ID: %w", err)
+	}
+
+	client := ClientRepresentation{
+		ClientID:                clientId,
+		Name:                    app.Name,
+		Description:             app.Description,
+		Enabled:                 true,
+		Protocol:                "openid-connect",
+		ClientAuthenticatorType: "client-secret",
+		ServiceAccountsEnabled:  true,
+	}
+
+	res, err := k.expect(ctx, http.MethodPost, k.adminURL("/clients"), client, http.StatusCreated, "create application")
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	applicationId, err := createdID(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create application: %w", err)
+	}
+	return &applicationId, nil
+}
+
+func (k *Client) DeleteApplication(ctx context.Context, applicationId string) error {
+	res, err := k.expect(ctx, http.MethodDelete, k.adminURL("/clients/%s", applicationId), nil, http.StatusNoContent, "delete application")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// setClientEnabled enables or disables a client. The full representation is sent back so that
+// settings not modelled by ClientRepresentation are kept.
+func (k *Client) setClientEnabled(ctx context.Context, applicationId string, enabled bool) error {
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/clients/%s", applicationId), nil, http.StatusOK, "get application")
+	if err != nil {
+		return err
+	}
+	var client map[string]interface{}
+	err = json.NewDecoder(res.Body).Decode(&client)
+	res.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if current, ok := client["enabled"].(bool); ok && current == enabled {
+		return nil
+	}
+	client["enabled"] = enabled
+
+	res, err = k.expect(ctx, http.MethodPut, k.adminURL("/clients/%s", applicationId), client, http.StatusNoContent, "update application")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (k *Client) RegenerateApplicationSecret(ctx context.Context, applicationId string) (*idp.ApplicationOIDCInfo, error) {
+	res, err := k.expect(ctx, http.MethodPost, k.adminURL("/clients/%s/client-secret", applicationId), nil, http.StatusOK, "regenerate client secret")
+	if err != nil {
+		return nil, err
+	}
+	var secret CredentialRepresentation
+	err = json.NewDecoder(res.Body).Decode(&secret)
+	res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// A revoked application is disabled; regenerating its secret reactivates it
+	if err := k.setClientEnabled(ctx, applicationId, true); err != nil {
+		return nil, err
+	}
+
+	client, err := k.getClient(ctx, applicationId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &idp.ApplicationOIDCInfo{
+		ClientId:     client.ClientID,
+		ClientSecret: secret.Value,
+	}, nil
+}
+
+// RevokeApplicationCredentials disables the client, so Keycloak rejects token requests made with its
+// credentials
+func (k *Client) RevokeApplicationCredentials(ctx context.Context, applicationId string) error {
+	return k.setClientEnabled(ctx, applicationId, false)
+}
@@ -0,0 +1,119 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CreateApplication(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /clients": func(w http.ResponseWriter, r *http.Request) {
+			var body ClientRepresentation
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "Test App", body.Name)
+			assert.Len(t, body.ClientID, 32)
+			assert.True(t, body.Enabled)
+			assert.True(t, body.ServiceAccountsEnabled)
+			assert.False(t, body.PublicClient)
+			assert.False(t, body.StandardFlowEnabled)
+			assert.False(t, body.DirectAccessGrantsEnabled)
+			w.Header().Set("Location", "/admin/realms/opendif/clients/app-123")
+			w.WriteHeader(http.StatusCreated)
+		},
+	})
+
+	applicationId, err := client.CreateApplication(context.Background(), &idp.Application{Name: "Test App", Description: "desc"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "app-123", *applicationId)
+}
+
+func TestClient_GetApplicationInfo(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /clients/app-123": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(ClientRepresentation{ID: "app-123", ClientID: "client-abc", Name: "Test App", Description: "desc"})
+		},
+	})
+
+	info, err := client.GetApplicationInfo(context.Background(), "app-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &idp.ApplicationInfo{Id: "app-123", Name: "Test App", Description: "desc", ClientId: "client-abc"}, info)
+
+	_, err = client.GetApplicationInfo(context.Background(), "missing")
+	assert.EqualError(t, err, "failed to get application, status code: 404")
+}
+
+func TestClient_GetApplicationOIDC(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /clients/app-123": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(ClientRepresentation{ID: "app-123", ClientID: "client-abc"})
+		},
+		"GET /clients/app-123/client-secret": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(CredentialRepresentation{Type: "secret", Value: "s3cret"})
+		},
+	})
+
+	oidc, err := client.GetApplicationOIDC(context.Background(), "app-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &idp.ApplicationOIDCInfo{ClientId: "client-abc", ClientSecret: "s3cret"}, oidc)
+}
+
+func TestClient_DeleteApplication(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"DELETE /clients/app-123": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	assert.NoError(t, client.DeleteApplication(context.Background(), "app-123"))
+}
+
+// newClientServer serves a client whose enabled flag is changed by PUT requests
+func newClientServer(t *testing.T, enabled *bool) *Client {
+	return newTestServer(t, map[string]http.HandlerFunc{
+		"GET /clients/app-123": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":           "app-123",
+				"clientId":     "client-abc",
+				"enabled":      *enabled,
+				"redirectUris": []string{},
+			})
+		},
+		"PUT /clients/app-123": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Contains(t, body, "redirectUris", "settings the portal does not manage are kept")
+			*enabled = body["enabled"].(bool)
+			w.WriteHeader(http.StatusNoContent)
+		},
+		"POST /clients/app-123/client-secret": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(CredentialRepresentation{Type: "secret", Value: "new-secret"})
+		},
+	})
+}
+
+func TestClient_RevokeApplicationCredentials(t *testing.T) {
+	enabled := true
+	client := newClientServer(t, &enabled)
+
+	assert.NoError(t, client.RevokeApplicationCredentials(context.Background(), "app-123"))
+	assert.False(t, enabled)
+}
+
+func TestClient_RegenerateApplicationSecret(t *testing.T) {
+	enabled := false
+	client := newClientServer(t, &enabled)
+
+	oidc, err := client.RegenerateApplicationSecret(context.Background(), "app-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &idp.ApplicationOIDCInfo{ClientId: "client-abc", ClientSecret: "new-secret"}, oidc)
+	assert.True(t, enabled, "regenerating the secret reactivates revoked credentials")
+}
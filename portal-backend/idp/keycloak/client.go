@@ -0,0 +1,112 @@
+// Package keycloak implements idp.IdentityProviderAPI with the Keycloak Admin REST API, for
+// deployments that run their own Keycloak instead of Asgardeo. Members are realm users, groups are
+// realm groups and applications are confidential OIDC clients with a service account.
+package keycloak
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type Client struct {
+	BaseURL     string
+	Realm       string
+	OAuthConfig *clientcredentials.Config
+	// Tokens caches the access token authenticating Client's requests
+	Tokens *idp.TokenSource
+	Client *http.Client
+}
+
+// NewClient creates a client for a realm of the Keycloak server at baseUrl. The client ID and secret
+// belong to a client of that realm whose service account has the realm-management roles manage-users
+// and manage-clients.
+func NewClient(baseUrl string, realm string, clientId string, clientSecret string, scopes []string) *Client {
+	oauthConfig := &clientcredentials.Config{
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		TokenURL:     fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", baseUrl, url.PathEscape(realm)),
+		Scopes:       scopes,
+	}
+	tokens := idp.NewTokenSource(oauthConfig)
+
+	return &Client{
+		BaseURL:     baseUrl,
+		Realm:       realm,
+		OAuthConfig: oauthConfig,
+		Tokens:      tokens,
+		Client:      tokens.Client(),
+	}
+}
+
+// CheckToken reports whether the Admin API access token can be refreshed
+func (k *Client) CheckToken(ctx context.Context) error {
+	return k.Tokens.Check(ctx)
+}
+
+// adminURL returns the URL of a resource of the realm's Admin REST API
+func (k *Client) adminURL(format string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = url.PathEscape(fmt.Sprint(arg))
+	}
+	return fmt.Sprintf("%s/admin/realms/%s", k.BaseURL, url.PathEscape(k.Realm)) + fmt.Sprintf(format, escaped...)
+}
+
+// send sends a request to the Admin REST API, encoding body as JSON if it is not nil. The caller
+// closes the response body.
+func (k *Client) send(ctx context.Context, method, target string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := k.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return res, nil
+}
+
+// expect sends a request and fails unless Keycloak answers with status; what describes the
+// operation in the error. The caller closes the response body.
+func (k *Client) expect(ctx context.Context, method, target string, body interface{}, status int, what string) (*http.Response, error) {
+	res, err := k.send(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != status {
+		res.Body.Close()
+		return nil, fmt.Errorf("failed to %s, status code: %d", what, res.StatusCode)
+	}
+	return res, nil
+}
+
+// createdID returns the ID of the resource created by a request answered with a Location header
+func createdID(res *http.Response) (string, error) {
+	location := res.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("created resource has no location")
+	}
+	return path.Base(location), nil
+}
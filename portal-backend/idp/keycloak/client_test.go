@@ -0,0 +1,56 @@
+package keycloak
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRealm = "opendif"
+
+// newTestServer returns a client of a server that serves the realm's token endpoint and the given Admin API routes, keyed by method
+// and path relative to /admin/realms/{realm}
+func newTestServer(t *testing.T, routes map[string]http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/realms/"+testRealm+"/protocol/openid-connect/token" && r.Method == http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "test-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+			return
+		}
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		if handler, ok := routes[r.Method+" "+r.URL.Path[len("/admin/realms/"+testRealm):]]; ok {
+			handler(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, testRealm, "client-id", "client-secret", []string{})
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("WithScopes", func(t *testing.T) {
+		client := NewClient("https://keycloak.example.com", "opendif", "client-id", "client-secret", []string{"openid"})
+		assert.NotNil(t, client)
+		assert.Equal(t, "https://keycloak.example.com", client.BaseURL)
+		assert.Equal(t, "opendif", client.Realm)
+		assert.Equal(t, "client-id", client.OAuthConfig.ClientID)
+		assert.Equal(t, "client-secret", client.OAuthConfig.ClientSecret)
+		assert.Equal(t, []string{"openid"}, client.OAuthConfig.Scopes)
+		assert.Equal(t, "https://keycloak.example.com/realms/opendif/protocol/openid-connect/token", client.OAuthConfig.TokenURL)
+		assert.NotNil(t, client.Client)
+	})
+
+	t.Run("AdminURLEscapesIDs", func(t *testing.T) {
+		client := NewClient("https://keycloak.example.com", "opendif", "client-id", "client-secret", nil)
+		assert.Equal(t, "https://keycloak.example.com/admin/realms/opendif/users/a%2Fb", client.adminURL("/users/%s", "a/b"))
+	})
+}
@@ -0,0 +1,187 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+)
+
+// GroupRepresentation is the subset of Keycloak's group representation managed by the portal
+type GroupRepresentation struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+func (k *Client) CreateGroup(ctx context.Context, group *idp.Group) (*idp.GroupInfo, error) {
+	res, err := k.expect(ctx, http.MethodPost, k.adminURL("/groups"), GroupRepresentation{Name: group.DisplayName}, http.StatusCreated, "create group")
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	groupId, err := createdID(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+
+	// Keycloak adds members to a group one user at a time
+	members := make([]idp.GroupMember, 0, len(group.Members))
+	for _, member := range group.Members {
+		if err := k.AddMemberToGroup(ctx, groupId, member); err != nil {
+			return nil, fmt.Errorf("failed to add member %s to group: %w", member.Value, err)
+		}
+		members = append(members, *member)
+	}
+
+	return &idp.GroupInfo{
+		Id:          groupId,
+		DisplayName: group.DisplayName,
+		Members:     members,
+	}, nil
+}
+
+func (k *Client) GetGroup(ctx context.Context, groupId string) (*idp.GroupInfo, error) {
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/groups/%s", groupId), nil, http.StatusOK, "get group")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var group GroupRepresentation
+	if err := json.NewDecoder(res.Body).Decode(&group); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	members, err := k.getGroupMembers(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &idp.GroupInfo{
+		Id:          group.ID,
+		DisplayName: group.Name,
+		Members:     members,
+	}, nil
+}
+
+// getGroupMembers lists the users of a group, displayed by username
+func (k *Client) getGroupMembers(ctx context.Context, groupId string) ([]idp.GroupMember, error) {
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/groups/%s/members", groupId)+"?briefRepresentation=true&max=-1", nil, http.StatusOK, "get group members")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var users []UserRepresentation
+	if err := json.NewDecoder(res.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	members := make([]idp.GroupMember, len(users))
+	for i, user := range users {
+		members[i] = idp.GroupMember{Value: user.ID, Display: user.Username}
+	}
+	return members, nil
+}
+
+func (k *Client) GetGroupByName(ctx context.Context, groupName string) (*string, error) {
+	query := url.Values{"search": {groupName}, "exact": {"true"}, "briefRepresentation": {"true"}}
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/groups")+"?"+query.Encode(), nil, http.StatusOK, "search groups")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var groups []GroupRepresentation
+	if err := json.NewDecoder(res.Body).Decode(&groups); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Older Keycloak versions ignore exact and match substrings
+	for _, group := range groups {
+		if group.Name == groupName {
+			return &group.ID, nil
+		}
+	}
+	return nil, fmt.Errorf("group with name %s not found", groupName)
+}
+
+// UpdateGroup renames the group and, if members are given, makes them its only members
+func (k *Client) UpdateGroup(ctx context.Context, groupId string, group *idp.Group) (*idp.GroupInfo, error) {
+	res, err := k.expect(ctx, http.MethodPut, k.adminURL("/groups/%s", groupId), GroupRepresentation{Name: group.DisplayName}, http.StatusNoContent, "update group")
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	if len(group.Members) > 0 {
+		current, err := k.getGroupMembers(ctx, groupId)
+		if err != nil {
+			return nil, err
+		}
+
+		wanted := make(map[string]bool, len(group.Members))
+		for _, member := range group.Members {
+			wanted[member.Value] = true
+		}
+		existing := make(map[string]bool, len(current))
+		for _, member := range current {
+			existing[member.Value] = true
+			if !wanted[member.Value] {
+				if err := k.RemoveMemberFromGroup(ctx, groupId, member.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, member := range group.Members {
+			if !existing[member.Value] {
+				if err := k.AddMemberToGroup(ctx, groupId, member); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return k.GetGroup(ctx, groupId)
+}
+
+func (k *Client) DeleteGroup(ctx context.Context, groupId string) error {
+	res, err := k.expect(ctx, http.MethodDelete, k.adminURL("/groups/%s", groupId), nil, http.StatusNoContent, "delete group")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// AddMemberToGroup adds the user identified by memberInfo.Value to the group
+func (k *Client) AddMemberToGroup(ctx context.Context, groupId string, memberInfo *idp.GroupMember) error {
+	res, err := k.expect(ctx, http.MethodPut, k.adminURL("/users/%s/groups/%s", memberInfo.Value, groupId), nil, http.StatusNoContent, "add member to group")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (k *Client) AddMemberToGroupByGroupName(ctx context.Context, groupName string, memberInfo *idp.GroupMember) (*string, error) {
+	groupId, err := k.GetGroupByName(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group by name: %w", err)
+	}
+
+	return groupId, k.AddMemberToGroup(ctx, *groupId, memberInfo)
+}
+
+func (k *Client) RemoveMemberFromGroup(ctx context.Context, groupId string, userId string) error {
+	res, err := k.expect(ctx, http.MethodDelete, k.adminURL("/users/%s/groups/%s", userId, groupId), nil, http.StatusNoContent, "remove member from group")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
@@ -0,0 +1,163 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CreateGroup(t *testing.T) {
+	var added []string
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /groups": func(w http.ResponseWriter, r *http.Request) {
+			var body GroupRepresentation
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "Test Group", body.Name)
+			w.Header().Set("Location", "/admin/realms/opendif/groups/group-123")
+			w.WriteHeader(http.StatusCreated)
+		},
+		"PUT /users/user-123/groups/group-123": func(w http.ResponseWriter, r *http.Request) {
+			added = append(added, "user-123")
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	group, err := client.CreateGroup(context.Background(), &idp.Group{
+		DisplayName: "Test Group",
+		Members:     []*idp.GroupMember{{Value: "user-123", Display: "jane@example.com"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "group-123", group.Id)
+	assert.Equal(t, "Test Group", group.DisplayName)
+	assert.Equal(t, []idp.GroupMember{{Value: "user-123", Display: "jane@example.com"}}, group.Members)
+	assert.Equal(t, []string{"user-123"}, added)
+}
+
+func TestClient_GetGroup(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /groups/group-123": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(GroupRepresentation{ID: "group-123", Name: "Test Group"})
+		},
+		"GET /groups/group-123/members": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]UserRepresentation{{ID: "user-123", Username: "jane@example.com"}})
+		},
+	})
+
+	group, err := client.GetGroup(context.Background(), "group-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &idp.GroupInfo{
+		Id:          "group-123",
+		DisplayName: "Test Group",
+		Members:     []idp.GroupMember{{Value: "user-123", Display: "jane@example.com"}},
+	}, group)
+}
+
+func TestClient_GetGroupByName(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /groups": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.URL.Query().Get("exact"))
+			groups := []GroupRepresentation{
+				{ID: "group-1", Name: "Test Group Admins"},
+				{ID: "group-2", Name: "Test Group"},
+			}
+			if r.URL.Query().Get("search") != "Test Group" {
+				groups = nil
+			}
+			json.NewEncoder(w).Encode(groups)
+		},
+	})
+
+	t.Run("Found", func(t *testing.T) {
+		groupId, err := client.GetGroupByName(context.Background(), "Test Group")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "group-2", *groupId)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		groupId, err := client.GetGroupByName(context.Background(), "Other")
+
+		assert.Nil(t, groupId)
+		assert.EqualError(t, err, "group with name Other not found")
+	})
+}
+
+func TestClient_UpdateGroup(t *testing.T) {
+	var calls []string
+	record := func(status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			w.WriteHeader(status)
+		}
+	}
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"PUT /groups/group-123": record(http.StatusNoContent),
+		"GET /groups/group-123": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(GroupRepresentation{ID: "group-123", Name: "Renamed"})
+		},
+		"GET /groups/group-123/members": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]UserRepresentation{{ID: "user-1"}, {ID: "user-2"}})
+		},
+		"DELETE /users/user-1/groups/group-123": record(http.StatusNoContent),
+		"PUT /users/user-3/groups/group-123":    record(http.StatusNoContent),
+	})
+
+	group, err := client.UpdateGroup(context.Background(), "group-123", &idp.Group{
+		DisplayName: "Renamed",
+		Members:     []*idp.GroupMember{{Value: "user-2"}, {Value: "user-3"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", group.DisplayName)
+	assert.Equal(t, []string{
+		"PUT /admin/realms/opendif/groups/group-123",
+		"DELETE /admin/realms/opendif/users/user-1/groups/group-123",
+		"PUT /admin/realms/opendif/users/user-3/groups/group-123",
+	}, calls)
+}
+
+func TestClient_DeleteGroup(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"DELETE /groups/group-123": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	assert.NoError(t, client.DeleteGroup(context.Background(), "group-123"))
+	assert.EqualError(t, client.DeleteGroup(context.Background(), "missing"), "failed to delete group, status code: 404")
+}
+
+func TestClient_AddMemberToGroupByGroupName(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"GET /groups": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]GroupRepresentation{{ID: "group-123", Name: "Members"}})
+		},
+		"PUT /users/user-123/groups/group-123": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	groupId, err := client.AddMemberToGroupByGroupName(context.Background(), "Members", &idp.GroupMember{Value: "user-123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "group-123", *groupId)
+
+	_, err = client.AddMemberToGroupByGroupName(context.Background(), "Unknown", &idp.GroupMember{Value: "user-123"})
+	assert.EqualError(t, err, "failed to get group by name: group with name Unknown not found")
+}
+
+func TestClient_RemoveMemberFromGroup(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"DELETE /users/user-123/groups/group-123": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	assert.NoError(t, client.RemoveMemberFromGroup(context.Background(), "group-123", "user-123"))
+}
@@ -0,0 +1,69 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+)
+
+// UserSessionRepresentation is a user session of the Admin REST API. Start and last access are
+// milliseconds since the epoch, and clients maps the IDs of the clients signed in to their client IDs.
+type UserSessionRepresentation struct {
+	ID         string            `json:"id"`
+	IPAddress  string            `json:"ipAddress"`
+	Start      int64             `json:"start"`
+	LastAccess int64             `json:"lastAccess"`
+	Clients    map[string]string `json:"clients"`
+}
+
+// epochMillis returns the time of milliseconds since the epoch, or the zero time if they are not set
+func epochMillis(millis int64) time.Time {
+	if millis <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis).UTC()
+}
+
+// GetUserSessions lists the active sessions of a user. Keycloak does not record their user agent.
+func (k *Client) GetUserSessions(ctx context.Context, userId string) ([]idp.UserSession, error) {
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/users/%s/sessions", userId), nil, http.StatusOK, "get user sessions")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var response []UserSessionRepresentation
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	sessions := make([]idp.UserSession, len(response))
+	for i, session := range response {
+		sessions[i] = idp.UserSession{
+			Id:             session.ID,
+			LoginTime:      epochMillis(session.Start),
+			LastAccessTime: epochMillis(session.LastAccess),
+			IPAddress:      session.IPAddress,
+		}
+		for _, clientId := range session.Clients {
+			sessions[i].Applications = append(sessions[i].Applications, clientId)
+		}
+		slices.Sort(sessions[i].Applications)
+	}
+	return sessions, nil
+}
+
+// RevokeUserSessions logs a user out of every session, revoking their refresh tokens
+func (k *Client) RevokeUserSessions(ctx context.Context, userId string) error {
+	res, err := k.expect(ctx, http.MethodPost, k.adminURL("/users/%s/logout", userId), nil, http.StatusNoContent, "revoke user sessions")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
@@ -0,0 +1,62 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetUserSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		client := newTestServer(t, map[string]http.HandlerFunc{
+			"GET /users/user-123/sessions": func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode([]UserSessionRepresentation{{
+					ID:         "session-1",
+					IPAddress:  "10.0.0.1",
+					Start:      1760000000000,
+					LastAccess: 1760000600000,
+					Clients:    map[string]string{"uuid-2": "member-portal", "uuid-1": "admin-portal"},
+				}})
+			},
+		})
+
+		sessions, err := client.GetUserSessions(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []idp.UserSession{{
+			Id:             "session-1",
+			LoginTime:      time.UnixMilli(1760000000000).UTC(),
+			LastAccessTime: time.UnixMilli(1760000600000).UTC(),
+			IPAddress:      "10.0.0.1",
+			Applications:   []string{"admin-portal", "member-portal"},
+		}}, sessions)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		client := newTestServer(t, nil)
+
+		sessions, err := client.GetUserSessions(context.Background(), "missing")
+
+		assert.Nil(t, sessions)
+		assert.EqualError(t, err, "failed to get user sessions, status code: 404")
+	})
+}
+
+func TestClient_RevokeUserSessions(t *testing.T) {
+	var loggedOut bool
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"POST /users/user-123/logout": func(w http.ResponseWriter, r *http.Request) {
+			loggedOut = true
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	assert.NoError(t, client.RevokeUserSessions(context.Background(), "user-123"))
+	assert.True(t, loggedOut)
+	assert.EqualError(t, client.RevokeUserSessions(context.Background(), "missing"), "failed to revoke user sessions, status code: 404")
+}
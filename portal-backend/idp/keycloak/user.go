@@ -0,0 +1,124 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+)
+
+// phoneNumberAttribute is the user attribute holding the phone number, as in Keycloak's user profile
+const phoneNumberAttribute = "phoneNumber"
+
+// UserRepresentation is the subset of Keycloak's user representation managed by the portal.
+// Empty optional fields are omitted so that updates leave them unchanged.
+type UserRepresentation struct {
+	ID              string              `json:"id,omitempty"`
+	Username        string              `json:"username,omitempty"`
+	Email           string              `json:"email"`
+	FirstName       string              `json:"firstName"`
+	LastName        string              `json:"lastName"`
+	Enabled         bool                `json:"enabled,omitempty"`
+	Attributes      map[string][]string `json:"attributes,omitempty"`
+	RequiredActions []string            `json:"requiredActions,omitempty"`
+}
+
+func toUserInfo(user *UserRepresentation) *idp.UserInfo {
+	userInfo := &idp.UserInfo{
+		Id:        user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+	if phoneNumbers := user.Attributes[phoneNumberAttribute]; len(phoneNumbers) > 0 {
+		userInfo.PhoneNumber = phoneNumbers[0]
+	}
+	return userInfo
+}
+
+func fromUser(userInfo *idp.User) UserRepresentation {
+	user := UserRepresentation{
+		Username:  userInfo.Email,
+		Email:     userInfo.Email,
+		FirstName: userInfo.FirstName,
+		LastName:  userInfo.LastName,
+		Enabled:   true,
+	}
+	if userInfo.PhoneNumber != "" {
+		user.Attributes = map[string][]string{phoneNumberAttribute: {userInfo.PhoneNumber}}
+	}
+	return user
+}
+
+func (k *Client) GetUser(ctx context.Context, userId string) (*idp.UserInfo, error) {
+	res, err := k.expect(ctx, http.MethodGet, k.adminURL("/users/%s", userId), nil, http.StatusOK, "get user")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var response UserRepresentation
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return toUserInfo(&response), nil
+}
+
+// CreateUser creates an enabled user named after their email and, like Asgardeo's askPassword flow,
+// emails them a link to set their password and verify their address
+func (k *Client) CreateUser(ctx context.Context, userInfo *idp.User) (*idp.UserInfo, error) {
+	body := fromUser(userInfo)
+	body.RequiredActions = []string{"UPDATE_PASSWORD", "VERIFY_EMAIL"}
+
+	res, err := k.expect(ctx, http.MethodPost, k.adminURL("/users"), body, http.StatusCreated, "create user")
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	userId, err := createdID(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	// The user exists even if the email cannot be sent; an administrator can resend it from Keycloak
+	actions, err := k.expect(ctx, http.MethodPut, k.adminURL("/users/%s/execute-actions-email", userId), body.RequiredActions, http.StatusNoContent, "send required actions email")
+	if err != nil {
+		slog.Warn("Failed to send account setup email", "userId", userId, "error", err)
+	} else {
+		actions.Body.Close()
+	}
+
+	body.ID = userId
+	return toUserInfo(&body), nil
+}
+
+func (k *Client) UpdateUser(ctx context.Context, userId string, userInfo *idp.User) (*idp.UserInfo, error) {
+	body := fromUser(userInfo)
+	// Keycloak only updates the fields sent: usernames may be read-only and the user stays
+	// enabled or disabled as it was
+	body.Username = ""
+	body.Enabled = false
+
+	res, err := k.expect(ctx, http.MethodPut, k.adminURL("/users/%s", userId), body, http.StatusNoContent, "update user")
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	body.ID = userId
+	return toUserInfo(&body), nil
+}
+
+func (k *Client) DeleteUser(ctx context.Context, userId string) error {
+	res, err := k.expect(ctx, http.MethodDelete, k.adminURL("/users/%s", userId), nil, http.StatusNoContent, "delete user")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
@@ -0,0 +1,151 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		client := newTestServer(t, map[string]http.HandlerFunc{
+			"GET /users/user-123": func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(UserRepresentation{
+					ID:         "user-123",
+					Username:   "jane@example.com",
+					Email:      "jane@example.com",
+					FirstName:  "Jane",
+					LastName:   "Doe",
+					Enabled:    true,
+					Attributes: map[string][]string{"phoneNumber": {"+94771234567"}},
+				})
+			},
+		})
+
+		user, err := client.GetUser(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &idp.UserInfo{
+			Id:          "user-123",
+			Email:       "jane@example.com",
+			FirstName:   "Jane",
+			LastName:    "Doe",
+			PhoneNumber: "+94771234567",
+		}, user)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		client := newTestServer(t, nil)
+
+		user, err := client.GetUser(context.Background(), "missing")
+
+		assert.Nil(t, user)
+		assert.EqualError(t, err, "failed to get user, status code: 404")
+	})
+}
+
+func TestClient_CreateUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var actions []string
+		client := newTestServer(t, map[string]http.HandlerFunc{
+			"POST /users": func(w http.ResponseWriter, r *http.Request) {
+				var body UserRepresentation
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, "jane@example.com", body.Username)
+				assert.True(t, body.Enabled)
+				assert.Equal(t, []string{"+94771234567"}, body.Attributes["phoneNumber"])
+				w.Header().Set("Location", "http://"+r.Host+r.URL.Path+"/user-123")
+				w.WriteHeader(http.StatusCreated)
+			},
+			"PUT /users/user-123/execute-actions-email": func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&actions))
+				w.WriteHeader(http.StatusNoContent)
+			},
+		})
+
+		user, err := client.CreateUser(context.Background(), &idp.User{
+			FirstName:   "Jane",
+			LastName:    "Doe",
+			Email:       "jane@example.com",
+			PhoneNumber: "+94771234567",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", user.Id)
+		assert.Equal(t, "jane@example.com", user.Email)
+		assert.Equal(t, "+94771234567", user.PhoneNumber)
+		assert.Equal(t, []string{"UPDATE_PASSWORD", "VERIFY_EMAIL"}, actions)
+	})
+
+	t.Run("EmailFailureKeepsUser", func(t *testing.T) {
+		client := newTestServer(t, map[string]http.HandlerFunc{
+			"POST /users": func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", "/admin/realms/opendif/users/user-123")
+				w.WriteHeader(http.StatusCreated)
+			},
+		})
+
+		user, err := client.CreateUser(context.Background(), &idp.User{Email: "jane@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", user.Id)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		client := newTestServer(t, map[string]http.HandlerFunc{
+			"POST /users": func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusConflict)
+			},
+		})
+
+		user, err := client.CreateUser(context.Background(), &idp.User{Email: "jane@example.com"})
+
+		assert.Nil(t, user)
+		assert.EqualError(t, err, "failed to create user, status code: 409")
+	})
+}
+
+func TestClient_UpdateUser(t *testing.T) {
+	client := newTestServer(t, map[string]http.HandlerFunc{
+		"PUT /users/user-123": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.NotContains(t, body, "username")
+			assert.NotContains(t, body, "enabled")
+			assert.Equal(t, "Janet", body["firstName"])
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+
+	user, err := client.UpdateUser(context.Background(), "user-123", &idp.User{
+		FirstName: "Janet",
+		LastName:  "Doe",
+		Email:     "jane@example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-123", user.Id)
+	assert.Equal(t, "Janet", user.FirstName)
+}
+
+func TestClient_DeleteUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		client := newTestServer(t, map[string]http.HandlerFunc{
+			"DELETE /users/user-123": func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		})
+
+		assert.NoError(t, client.DeleteUser(context.Background(), "user-123"))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		client := newTestServer(t, nil)
+
+		assert.EqualError(t, client.DeleteUser(context.Background(), "user-123"), "failed to delete user, status code: 404")
+	})
+}
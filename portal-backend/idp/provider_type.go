@@ -4,4 +4,5 @@ type ProviderType string
 
 const (
 	ProviderAsgardeo ProviderType = "asgardeo"
+	ProviderKeycloak ProviderType = "keycloak"
 )
@@ -0,0 +1,49 @@
+package idp
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSessionsNotSupported is returned when the provider cannot list or revoke user sessions
+var ErrSessionsNotSupported = errors.New("identity provider does not support user sessions")
+
+// SessionManager is implemented by providers that expose the login sessions of their users
+type SessionManager interface {
+	// GetUserSessions lists the active login sessions of a user
+	GetUserSessions(ctx context.Context, userId string) ([]UserSession, error)
+	// RevokeUserSessions terminates every login session of a user, so they must sign in again
+	RevokeUserSessions(ctx context.Context, userId string) error
+}
+
+// UserSession is an active login session of a user at the provider
+type UserSession struct {
+	Id             string
+	LoginTime      time.Time
+	LastAccessTime time.Time
+	IPAddress      string
+	UserAgent      string
+	// Applications are the names of the applications the session has signed in to
+	Applications []string
+}
+
+// GetUserSessions lists the active sessions of a user, or returns ErrSessionsNotSupported if the
+// provider does not expose them
+func GetUserSessions(ctx context.Context, provider UserManager, userId string) ([]UserSession, error) {
+	sessions, ok := provider.(SessionManager)
+	if !ok {
+		return nil, ErrSessionsNotSupported
+	}
+	return sessions.GetUserSessions(ctx, userId)
+}
+
+// RevokeUserSessions terminates every session of a user, or returns ErrSessionsNotSupported if the
+// provider does not expose them
+func RevokeUserSessions(ctx context.Context, provider UserManager, userId string) error {
+	sessions, ok := provider.(SessionManager)
+	if !ok {
+		return ErrSessionsNotSupported
+	}
+	return sessions.RevokeUserSessions(ctx, userId)
+}
@@ -0,0 +1,17 @@
+package idp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSessions_NotSupported(t *testing.T) {
+	provider := &oneByOneProvider{}
+
+	sessions, err := GetUserSessions(context.Background(), provider, "user-1")
+	assert.Nil(t, sessions)
+	assert.ErrorIs(t, err, ErrSessionsNotSupported)
+	assert.ErrorIs(t, RevokeUserSessions(context.Background(), provider, "user-1"), ErrSessionsNotSupported)
+}
@@ -0,0 +1,212 @@
+package idp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// DefaultTokenRefreshWindow is how long before expiry a cached token is refreshed in the background
+	DefaultTokenRefreshWindow = time.Minute
+	// tokenRequestTimeout bounds a request to the token endpoint
+	tokenRequestTimeout = 30 * time.Second
+)
+
+// TokenChecker is implemented by providers whose management API calls are authenticated by a
+// TokenSource, so that failing token refreshes can be reported by the health checks
+type TokenChecker interface {
+	CheckToken(ctx context.Context) error
+}
+
+// TokenStats counts the token requests of a TokenSource since the service started
+type TokenStats struct {
+	Refreshes           int64      `json:"refreshes"`
+	RefreshFailures     int64      `json:"refreshFailures"`
+	ConsecutiveFailures int64      `json:"consecutiveFailures"`
+	UnauthorizedRetries int64      `json:"unauthorizedRetries"`
+	LastRefreshAt       *time.Time `json:"lastRefreshAt,omitempty"`
+	LastFailureAt       *time.Time `json:"lastFailureAt,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
+}
+
+// tokenCall is a token request shared by the callers waiting for it
+type tokenCall struct {
+	done  chan struct{}
+	token *oauth2.Token
+	err   error
+}
+
+// TokenSource caches the client credentials access token used for management API calls.
+// The token is refreshed in the background shortly before it expires, and concurrent callers
+// needing a new token share a single token request.
+type TokenSource struct {
+	config        *clientcredentials.Config
+	refreshWindow time.Duration
+
+	mu         sync.Mutex
+	token      *oauth2.Token
+	refreshing *tokenCall
+	stats      TokenStats
+}
+
+// NewTokenSource creates a token source for the client credentials in config
+func NewTokenSource(config *clientcredentials.Config) *TokenSource {
+	return &TokenSource{config: config, refreshWindow: DefaultTokenRefreshWindow}
+}
+
+// SetRefreshWindow changes how long before expiry the token is refreshed; non-positive values keep the
+// current window
+func (s *TokenSource) SetRefreshWindow(window time.Duration) {
+	if window > 0 {
+		s.refreshWindow = window
+	}
+}
+
+// Token returns the cached token, requesting a new one if there is none or it has expired.
+// A token about to expire is returned while a new one is requested in the background.
+func (s *TokenSource) Token(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	token := s.token
+	now := time.Now()
+	if token != nil && (token.Expiry.IsZero() || now.Before(token.Expiry.Add(-s.refreshWindow))) {
+		s.mu.Unlock()
+		return token, nil
+	}
+	call := s.startRefresh()
+	s.mu.Unlock()
+
+	if token != nil && now.Before(token.Expiry) {
+		return token, nil
+	}
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startRefresh returns the token request in progress, starting one if there is none. The caller
+// holds s.mu.
+func (s *TokenSource) startRefresh() *tokenCall {
+	if s.refreshing != nil {
+		return s.refreshing
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	s.refreshing = call
+	go s.refresh(call)
+	return call
+}
+
+// refresh requests a new token. It is not tied to the context of the caller that started it, as
+// other callers may be waiting for the same token.
+func (s *TokenSource) refresh(call *tokenCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRequestTimeout)
+	defer cancel()
+	token, err := s.config.Token(ctx)
+
+	s.mu.Lock()
+	now := time.Now()
+	s.refreshing = nil
+	if err != nil {
+		s.stats.RefreshFailures++
+		s.stats.ConsecutiveFailures++
+		s.stats.LastFailureAt = &now
+		s.stats.LastError = err.Error()
+		slog.Error("Failed to refresh identity provider access token", "tokenUrl", s.config.TokenURL, "consecutiveFailures", s.stats.ConsecutiveFailures, "error", err)
+	} else {
+		s.token = token
+		s.stats.Refreshes++
+		s.stats.ConsecutiveFailures = 0
+		s.stats.LastRefreshAt = &now
+	}
+	s.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+}
+
+// Invalidate drops the cached token if it is still stale, after the identity provider rejected it,
+// so that the next call requests a new token. Tokens already replaced by a refresh are kept.
+func (s *TokenSource) Invalidate(stale *oauth2.Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && s.token.AccessToken == stale.AccessToken {
+		s.token = nil
+	}
+}
+
+// Stats returns the token request counters
+func (s *TokenSource) Stats() TokenStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Check reports the token source as unavailable while its latest token request failed
+func (s *TokenSource) Check(ctx context.Context) error {
+	stats := s.Stats()
+	if stats.ConsecutiveFailures > 0 {
+		return fmt.Errorf("%d consecutive token refreshes failed (%d since start): %s", stats.ConsecutiveFailures, stats.RefreshFailures, stats.LastError)
+	}
+	return nil
+}
+
+// Client returns an HTTP client authenticating requests with the source's tokens. A request
+// answered 401 is retried once with a new token, in case the cached token was revoked.
+func (s *TokenSource) Client() *http.Client {
+	return &http.Client{Transport: &tokenTransport{source: s, base: http.DefaultTransport}}
+}
+
+type tokenTransport struct {
+	source *TokenSource
+	base   http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	res, err := t.base.RoundTrip(authorize(req, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	// Requests whose body cannot be sent again are not retried
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return res, nil
+	}
+
+	t.source.Invalidate(token)
+	fresh, err := t.source.Token(req.Context())
+	if err != nil || fresh.AccessToken == token.AccessToken {
+		return res, nil
+	}
+	retry := authorize(req, fresh)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return res, nil
+		}
+	}
+	res.Body.Close()
+
+	t.source.mu.Lock()
+	t.source.stats.UnauthorizedRetries++
+	t.source.mu.Unlock()
+	return t.base.RoundTrip(retry)
+}
+
+// authorize returns a copy of req carrying the token, as round trippers must not modify requests
+func authorize(req *http.Request, token *oauth2.Token) *http.Request {
+	authorized := req.Clone(req.Context())
+	token.SetAuthHeader(authorized)
+	return authorized
+}
@@ -0,0 +1,169 @@
+package idp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenServer issues numbered tokens valid for expiresIn seconds and counts the token requests
+type tokenServer struct {
+	*httptest.Server
+	requests  atomic.Int64
+	expiresIn int
+	fail      atomic.Bool
+	// delay holds token requests until it is closed, if set
+	delay chan struct{}
+}
+
+func newTokenServer(t *testing.T, expiresIn int, api http.HandlerFunc) *tokenServer {
+	ts := &tokenServer{expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2/token" {
+			api(w, r)
+			return
+		}
+		n := ts.requests.Add(1)
+		if ts.delay != nil {
+			<-ts.delay
+		}
+		if ts.fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   ts.expiresIn,
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *tokenServer) tokenSource() *TokenSource {
+	return NewTokenSource(&clientcredentials.Config{ClientID: "id", ClientSecret: "secret", TokenURL: ts.URL + "/oauth2/token"})
+}
+
+func TestTokenSource_CachesToken(t *testing.T) {
+	ts := newTokenServer(t, 3600, nil)
+	source := ts.tokenSource()
+
+	for i := 0; i < 3; i++ {
+		token, err := source.Token(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token-1", token.AccessToken)
+	}
+	assert.Equal(t, int64(1), ts.requests.Load())
+	assert.Equal(t, int64(1), source.Stats().Refreshes)
+}
+
+func TestTokenSource_SingleFlight(t *testing.T) {
+	ts := newTokenServer(t, 3600, nil)
+	ts.delay = make(chan struct{})
+	source := ts.tokenSource()
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 10)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := source.Token(context.Background())
+			assert.NoError(t, err)
+			tokens[i] = token.AccessToken
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(ts.delay)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), ts.requests.Load())
+	for _, token := range tokens {
+		assert.Equal(t, "token-1", token)
+	}
+}
+
+func TestTokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	// Tokens expire in 30s, within the default one minute refresh window
+	ts := newTokenServer(t, 30, nil)
+	source := ts.tokenSource()
+
+	token, err := source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+
+	// The still valid token is returned while a new one is requested
+	token, err = source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+	assert.Eventually(t, func() bool { return source.Stats().Refreshes == 2 }, time.Second, 10*time.Millisecond)
+
+	token, err = source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-2", token.AccessToken)
+}
+
+func TestTokenSource_RefreshFailures(t *testing.T) {
+	ts := newTokenServer(t, 3600, nil)
+	ts.fail.Store(true)
+	source := ts.tokenSource()
+
+	_, err := source.Token(context.Background())
+	assert.Error(t, err)
+	_, err = source.Token(context.Background())
+	assert.Error(t, err)
+
+	stats := source.Stats()
+	assert.Equal(t, int64(2), stats.RefreshFailures)
+	assert.Equal(t, int64(2), stats.ConsecutiveFailures)
+	assert.NotNil(t, stats.LastFailureAt)
+	assert.ErrorContains(t, source.Check(context.Background()), "2 consecutive token refreshes failed")
+
+	ts.fail.Store(false)
+	_, err = source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, source.Check(context.Background()))
+	assert.Equal(t, int64(2), source.Stats().RefreshFailures)
+}
+
+func TestTokenSource_Client(t *testing.T) {
+	// The API rejects the first token, as if it had been revoked
+	var bodies []string
+	ts := newTokenServer(t, 3600, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	source := ts.tokenSource()
+	client := source.Client()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/users", bytes.NewReader([]byte(`{"name":"a"}`)))
+	res, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, []string{`{"name":"a"}`, `{"name":"a"}`}, bodies)
+	assert.Equal(t, int64(1), source.Stats().UnauthorizedRetries)
+
+	// The new token is cached
+	res, err = client.Get(ts.URL + "/users")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, int64(2), ts.requests.Load())
+}
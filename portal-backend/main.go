@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	_ "embed"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -14,16 +15,26 @@ import (
 	v1 "github.com/gov-dx-sandbox/portal-backend/v1"
 	v1handlers "github.com/gov-dx-sandbox/portal-backend/v1/handlers"
 	v1middleware "github.com/gov-dx-sandbox/portal-backend/v1/middleware"
+	v1migrations "github.com/gov-dx-sandbox/portal-backend/v1/migrations"
 	v1models "github.com/gov-dx-sandbox/portal-backend/v1/models"
 	auditclient "github.com/gov-dx-sandbox/shared/audit"
+	"github.com/gov-dx-sandbox/shared/database"
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
 	"github.com/joho/godotenv"
 )
 
+// openAPISpec is the OpenAPI document of the v1 API; requests are validated against it and it is
+// served at /api/v1/openapi.json
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
 func main() {
 	// Load .env file if it exists (optional - fails silently if not found)
 	_ = godotenv.Load()
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+	logger := slog.New(requestid.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true})))
 	slog.SetDefault(logger)
 
 	slog.Info("Starting Portal Backend initialization")
@@ -43,6 +54,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Start the PDP worker that delivers queued PDP sync jobs
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go v1Handler.PDPWorker().Start(workerCtx)
+
+	// Start the notification worker that sends notification emails and credential expiry notices
+	go v1Handler.NotificationWorker().Start(workerCtx)
+
+	// Start the grant renewal worker that opens renewals for application grants about to expire
+	go v1Handler.GrantRenewalWorker().Start(workerCtx)
+
+	// Start the webhook worker that sends queued webhook deliveries
+	go v1Handler.WebhookWorker().Start(workerCtx)
+
+	// Start the attachment scan worker that scans uploaded submission attachments for viruses
+	go v1Handler.AttachmentScanWorker().Start(workerCtx)
+
 	// Create a mux for API routes
 	apiMux := http.NewServeMux()
 	v1Handler.SetupV1Routes(apiMux) // All /api/v1/... routes go here
@@ -76,11 +104,14 @@ func main() {
 	}
 
 	jwtConfig := v1middleware.JWTAuthConfig{
-		JWKSURL:        utils.GetEnvOrDefault("ASGARDEO_JWKS_URL", asgardeoBaseURL+"/oauth2/jwks"),
-		ExpectedIssuer: utils.GetEnvOrDefault("ASGARDEO_TOKEN_URL", asgardeoBaseURL+"/oauth2/token"),
-		ValidClientIDs: validClientIDs,
-		OrgName:        utils.GetEnvOrDefault("ASGARDEO_ORG_NAME", ""),
-		Timeout:        10 * time.Second,
+		JWKSURL:            utils.GetEnvOrDefault("ASGARDEO_JWKS_URL", asgardeoBaseURL+"/oauth2/jwks"),
+		ExpectedIssuer:     utils.GetEnvOrDefault("ASGARDEO_TOKEN_URL", asgardeoBaseURL+"/oauth2/token"),
+		ValidClientIDs:     validClientIDs,
+		OrgName:            utils.GetEnvOrDefault("ASGARDEO_ORG_NAME", ""),
+		Timeout:            durationFromEnv("JWT_TIMEOUT", 10*time.Second),
+		RefreshInterval:    durationFromEnv("JWT_CACHE_DURATION", v1middleware.DefaultJWKSRefreshInterval),
+		MaxKeyStaleness:    durationFromEnv("JWT_KEY_MAX_STALENESS", v1middleware.DefaultJWKSMaxStaleness),
+		MinRefreshInterval: durationFromEnv("JWT_KEY_MIN_REFRESH_INTERVAL", v1middleware.DefaultJWKSMinRefreshInterval),
 	}
 
 	// Validate JWT configuration before proceeding
@@ -91,6 +122,10 @@ func main() {
 
 	jwtAuthMiddleware := v1middleware.NewJWTAuthMiddleware(jwtConfig)
 
+	// Refresh the JWKS in the background; tokens keep being validated with the cached keys while the
+	// identity provider is unreachable
+	go jwtAuthMiddleware.Start(workerCtx)
+
 	// Setup Authorization middleware with configurable security policy
 	authMode := utils.GetEnvOrDefault("AUTHORIZATION_MODE", "fail_open_admin_system")
 	strictMode := utils.GetEnvOrDefault("AUTHORIZATION_STRICT_MODE", "false") == "true"
@@ -117,11 +152,38 @@ func main() {
 	auditServiceURL := utils.GetEnvOrDefault("CHOREO_AUDIT_CONNECTION_SERVICEURL", "http://localhost:3001")
 	auditClient := auditclient.NewClient(auditServiceURL)
 	auditclient.InitializeGlobalAudit(auditClient)
+	auditMiddleware := v1middleware.NewAuditMiddleware(auditClient)
+	securityEventMiddleware := v1middleware.NewSecurityEventMiddleware(auditClient)
+
+	// Admins acting as a member name their impersonation session in a header; the middleware swaps in
+	// the member's identity after the admin's token has been verified
+	impersonationMiddleware := v1middleware.NewImpersonationMiddleware(v1Handler.ImpersonationService())
+
+	// Requests are checked against the OpenAPI document and rejected with a 422 when they do not match it.
+	// Responses are only checked when OPENAPI_RESPONSE_VALIDATION is enabled, e.g. in development.
+	openAPIMiddleware, err := v1middleware.NewOpenAPIValidationMiddleware(openAPISpec, v1middleware.OpenAPIValidationConfig{
+		ValidateRequests:  utils.GetEnvOrDefault("OPENAPI_REQUEST_VALIDATION", "true") == "true",
+		ValidateResponses: utils.GetEnvOrDefault("OPENAPI_RESPONSE_VALIDATION", "false") == "true",
+	})
+	if err != nil {
+		slog.Error("Failed to initialize OpenAPI validation", "error", err)
+		os.Exit(1)
+	}
+	validatedAPIMux := openAPIMiddleware.Validate(apiMux)
 
-	// Apply middleware chain (CORS -> JWT Auth -> Authorization) to the API mux ONLY
+	// Apply middleware chain (CORS -> JWT Auth -> Security events -> Impersonation -> Audit -> Authorization ->
+	// OpenAPI validation) to the API mux ONLY. Audit sits outside authorization so that denied writes are recorded
+	// too, and validation sits inside it so that callers without access are refused before their requests are
+	// inspected. Security events are logged for the token's owner, before impersonation swaps in the member.
 	protectedAPIHandler := corsMiddleware(
 		jwtAuthMiddleware.AuthenticateJWT(
-			authorizationMiddleware.AuthorizeRequest(apiMux),
+			securityEventMiddleware.RecordSecurityEvents(
+				impersonationMiddleware.Impersonate(
+					auditMiddleware.AuditRequest(
+						authorizationMiddleware.AuthorizeRequest(validatedAPIMux),
+					),
+				),
+			),
 		),
 	)
 
@@ -130,53 +192,17 @@ func main() {
 
 	// Register public routes directly on the top-level mux
 	// These routes will bypass the audit middleware
-	topLevelMux.Handle("/health", utils.PanicRecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		type DBHealth struct {
-			Status   string `json:"status"`
-			Error    string `json:"error,omitempty"`
-			Database string `json:"database,omitempty"`
-		}
-		type HealthStatus struct {
-			Status    string              `json:"status"`
-			Service   string              `json:"service"`
-			Databases map[string]DBHealth `json:"databases"`
-		}
-
-		status := HealthStatus{
-			Status:  "healthy",
-			Service: "portal-backend",
-			Databases: map[string]DBHealth{
-				"v1": {Status: "unknown"},
-			},
-		}
-
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		// Test V1 GORM database connection
-		if gormDB == nil {
-			status.Databases["v1"] = DBHealth{Status: "unhealthy", Error: "GORM connection is nil"}
-			status.Status = "unhealthy"
-		} else {
-			sqlDB, err := gormDB.DB()
-			if err != nil {
-				status.Databases["v1"] = DBHealth{Status: "unhealthy", Error: fmt.Sprintf("failed to get sql.DB: %v", err)}
-				status.Status = "unhealthy"
-			} else if err := sqlDB.PingContext(ctx); err != nil {
-				status.Databases["v1"] = DBHealth{Status: "unhealthy", Error: err.Error()}
-				status.Status = "unhealthy"
-			} else {
-				status.Databases["v1"] = DBHealth{Status: "healthy", Database: v1DbConfig.Database}
-			}
-		}
-
-		statusCode := http.StatusOK
-		if status.Status != "healthy" {
-			statusCode = http.StatusServiceUnavailable
-		}
-
-		utils.RespondWithJSON(w, statusCode, status)
-	})))
+	// /health, /health/live and /health/ready: the portal cannot serve requests without its database
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		slog.Error("Failed to get database connection", "error", err)
+		os.Exit(1)
+	}
+	healthChecker := utils.NewHealthChecker("portal-backend")
+	healthChecker.Register("database", health.PingCheck(sqlDB))
+	v1Handler.RegisterHealthChecks(healthChecker)
+	healthChecker.RegisterOptional("jwks", jwtAuthMiddleware.CheckKeys)
+	healthChecker.RegisterRoutes(topLevelMux)
 
 	topLevelMux.Handle("/debug", utils.PanicRecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithJSON(w, http.StatusOK, map[string]string{"path": r.URL.Path, "method": r.Method})
@@ -240,16 +266,43 @@ func main() {
 		utils.RespondWithJSON(w, http.StatusOK, debugInfo)
 	})))
 
+	// Reports the schema version of the V1 database against the migrations in this build
+	topLevelMux.Handle("/debug/migrations", utils.PanicRecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		migrator, err := v1migrations.NewMigrator(gormDB)
+		if err != nil {
+			utils.RespondWithJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			utils.RespondWithJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		utils.RespondWithJSON(w, http.StatusOK, status)
+	})))
+
 	// Register the protected API routes to the top-level mux
 	// All traffic to /api/v1/ (and its sub-paths) will pass through the middleware chain
 	topLevelMux.Handle("/api/v1/", protectedAPIHandler)
 
+	// Register public V1 routes, such as accepting a member invitation, which authenticate with a
+	// token in the request instead of a JWT. Their patterns are more specific than /api/v1/, so they win.
+	publicAPIMux := http.NewServeMux()
+	v1Handler.SetupPublicRoutes(publicAPIMux)
+	topLevelMux.Handle("/api/v1/invitations/accept", corsMiddleware(openAPIMiddleware.Validate(publicAPIMux)))
+
+	// The OpenAPI document is public so that clients and tooling can fetch it without a token
+	topLevelMux.Handle("/api/v1/openapi.json", corsMiddleware(http.HandlerFunc(openAPIMiddleware.ServeDocument)))
+
 	// Register internal API routes (no authentication required for internal services)
 	// SECURITY WARNING: These endpoints are exposed WITHOUT authentication!
 	// MUST be protected at network level (VPC, firewall, service mesh, etc.)
 	// See README.md "Deployment Security" section for required security measures.
 	// DO NOT expose this service directly to public internet without proper network isolation.
-	topLevelMux.Handle("/internal/api/v1/", http.StripPrefix("", apiMux))
+	topLevelMux.Handle("/internal/api/v1/", http.StripPrefix("", validatedAPIMux))
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -260,7 +313,7 @@ func main() {
 	addr := ":" + port
 	server := &http.Server{
 		Addr:         addr,
-		Handler:      topLevelMux,
+		Handler:      requestid.Middleware(topLevelMux),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -292,8 +345,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Deliver buffered audit events; anything left is spooled to disk if AUDIT_SPOOL_DIR is set
+	if err := auditClient.Close(ctx); err != nil {
+		slog.Warn("Timed out delivering buffered audit events", "error", err)
+	}
+
 	// Gracefully close database connection
 	if gormDB != nil {
+		if err := database.CloseReplicas(gormDB); err != nil {
+			slog.Error("Failed to close read replica connections", "error", err)
+		}
 		if sqlDB, err := gormDB.DB(); err == nil {
 			if err := sqlDB.Close(); err != nil {
 				slog.Error("Failed to close database connection", "error", err)
@@ -303,3 +364,17 @@ func main() {
 
 	slog.Info("Portal Backend exited")
 }
+
+// durationFromEnv reads a duration such as "15m" from the environment variable name, exiting if it is invalid
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Error("Invalid duration", "variable", name, "value", value, "error", err)
+		os.Exit(1)
+	}
+	return duration
+}
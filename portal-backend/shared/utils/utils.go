@@ -12,37 +12,64 @@ import (
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"github.com/gov-dx-sandbox/shared/response"
 )
 
 // ErrorResponse represents a standard error response structure
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
+type ErrorResponse = response.ErrorResponse
 
 // SuccessResponse represents a standard success response structure
-type SuccessResponse struct {
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-}
+type SuccessResponse = response.SuccessResponse
+
+// CollectionResponse represents a standard collection response structure
+type CollectionResponse = response.CollectionResponse
+
+// Pagination describes the page returned in a CollectionResponse
+type Pagination = response.Pagination
 
 // RespondWithJSON sends a JSON response with the given status code and data
 func RespondWithJSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		slog.Error("Failed to encode JSON response", "error", err)
-	}
+	response.RespondWithJSON(w, statusCode, data)
 }
 
 // RespondWithError sends a JSON error response
 func RespondWithError(w http.ResponseWriter, statusCode int, message string) {
-	RespondWithJSON(w, statusCode, ErrorResponse{Error: message})
+	response.RespondWithError(w, statusCode, message)
+}
+
+// RespondWithErrorCode sends a JSON error response with a machine-readable code
+func RespondWithErrorCode(w http.ResponseWriter, statusCode int, code, message string) {
+	response.RespondWithErrorCode(w, statusCode, code, message)
 }
 
 // RespondWithSuccess sends a JSON success response
 func RespondWithSuccess(w http.ResponseWriter, statusCode int, data interface{}) {
-	RespondWithJSON(w, statusCode, data)
+	response.RespondWithSuccess(w, statusCode, data)
+}
+
+// RespondWithCollection sends a 200 collection response with the page it belongs to, if any
+func RespondWithCollection(w http.ResponseWriter, items interface{}, count int, pagination *Pagination) {
+	response.RespondWithCollection(w, items, count, pagination)
+}
+
+// HealthChecker runs the health checks a service registers and serves /health, /health/live and
+// /health/ready
+type HealthChecker = health.Checker
+
+// HealthCheckFunc checks a dependency of a service
+type HealthCheckFunc = health.CheckFunc
+
+// NewHealthChecker creates a health checker without checks for the named service
+func NewHealthChecker(serviceName string) *HealthChecker {
+	return health.NewChecker(serviceName)
+}
+
+// HealthHandler creates a health check handler for a service without dependencies to check
+func HealthHandler(serviceName string) http.HandlerFunc {
+	return health.NewChecker(serviceName).ReadyHandler()
 }
 
 // PanicRecoveryMiddleware provides panic recovery for HTTP handlers
@@ -50,7 +77,7 @@ func PanicRecoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				slog.Error("Handler panicked", "error", err, "path", r.URL.Path)
+				slog.ErrorContext(r.Context(), "Handler panicked", "error", err, "path", r.URL.Path)
 				RespondWithError(w, http.StatusInternalServerError, "Internal server error")
 			}
 		}()
@@ -167,11 +194,8 @@ func ParseJSONRequest(r *http.Request, target interface{}) error {
 }
 
 // CreateCollectionResponse creates a standardized collection response with count
-func CreateCollectionResponse(items interface{}, count int) map[string]interface{} {
-	return map[string]interface{}{
-		"items": items,
-		"count": count,
-	}
+func CreateCollectionResponse(items interface{}, count int) CollectionResponse {
+	return response.NewCollectionResponse(items, count)
 }
 
 // ServerConfig holds configuration for HTTP servers
@@ -268,7 +292,8 @@ func ParseExpiryTime(expiryStr string) (time.Duration, error) {
 	return time.Duration(multiplier) * duration, nil
 }
 
-// SetupLogging configures logging based on the configuration
+// SetupLogging configures logging based on the configuration. Records logged with a request's
+// context include its request ID.
 func SetupLogging(format, level string) {
 	var handler slog.Handler
 
@@ -283,7 +308,7 @@ func SetupLogging(format, level string) {
 		})
 	}
 
-	slog.SetDefault(slog.New(handler))
+	slog.SetDefault(slog.New(requestid.NewLogHandler(handler)))
 }
 
 // getLogLevel converts string level to slog.Level
@@ -1,12 +1,15 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
 	"time"
 
-	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/portal-backend/v1/migrations"
+	"github.com/gov-dx-sandbox/portal-backend/v1/services"
+	"github.com/gov-dx-sandbox/shared/database"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -24,6 +27,8 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+	// ReplicaDSNs are the read replicas that read-only service methods are routed to
+	ReplicaDSNs []string
 }
 
 // NewDatabaseConfig creates a new GORM database configuration for V1
@@ -39,6 +44,7 @@ func NewDatabaseConfig() *DatabaseConfig {
 		MaxIdleConns:    5,
 		ConnMaxLifetime: time.Hour,
 		ConnMaxIdleTime: 30 * time.Minute,
+		ReplicaDSNs:     database.ParseReplicaDSNs(os.Getenv("DB_READ_REPLICA_DSNS")),
 	}
 }
 
@@ -87,23 +93,79 @@ func ConnectGormDB(config *DatabaseConfig) (*gorm.DB, error) {
 		"port", config.Port,
 		"database", config.Database)
 
-	// Only run migration if environment variable is set
+	if err := useReadReplicas(db, config); err != nil {
+		return nil, err
+	}
+
+	if err := prepareSchema(context.Background(), db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// useReadReplicas connects to the read replicas of the config and routes the reads made through
+// database.Reader to them
+func useReadReplicas(db *gorm.DB, config *DatabaseConfig) error {
+	if len(config.ReplicaDSNs) == 0 {
+		return nil
+	}
+
+	replicas := make([]database.Replica, 0, len(config.ReplicaDSNs))
+	for i, dsn := range config.ReplicaDSNs {
+		// Replicas that do not answer yet are kept; the router reads from them once they do
+		replicaDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger:               logger.Default.LogMode(logger.Warn),
+			DisableAutomaticPing: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open read replica %d: %w", i+1, err)
+		}
+		sqlDB, err := replicaDB.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get underlying sql.DB of read replica %d: %w", i+1, err)
+		}
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+		replicas = append(replicas, database.Replica{Name: fmt.Sprintf("replica-%d", i+1), DB: sqlDB})
+	}
+
+	if err := db.Use(database.NewReplicaRouter(replicas, database.DefaultHealthCheckInterval)); err != nil {
+		return fmt.Errorf("failed to register read replicas: %w", err)
+	}
+	slog.Info("Routing read-only queries to read replicas", "replicas", len(replicas))
+	return nil
+}
+
+// prepareSchema applies pending migrations when RUN_MIGRATION is set, then refuses to continue unless
+// the database is at the schema version this build was written for
+func prepareSchema(ctx context.Context, db *gorm.DB) error {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
 	if os.Getenv("RUN_MIGRATION") == "true" {
-		slog.Info("Running GORM auto-migration for V1 models")
-		err = db.AutoMigrate(
-			&models.Member{},
-			&models.Schema{},
-			&models.SchemaSubmission{},
-			&models.Application{},
-			&models.ApplicationSubmission{},
-		)
+		slog.Info("Applying database migrations for V1", "latestVersion", migrator.LatestVersion())
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+		migrated, err := services.MigrateMembersToOrganizations(ctx, db)
 		if err != nil {
-			return nil, fmt.Errorf("failed to run auto-migration: %w", err)
+			return fmt.Errorf("failed to migrate members to organizations: %w", err)
 		}
-		slog.Info("GORM auto-migration completed successfully")
-	} else {
-		slog.Info("Database connected (migration skipped)")
+		if migrated > 0 {
+			slog.Info("Migrated members to organizations", "count", migrated)
+		}
+		slog.Info("Database migrations completed successfully", "applied", applied)
 	}
 
-	return db, nil
+	if err := migrator.Verify(ctx); err != nil {
+		return fmt.Errorf("%w; set RUN_MIGRATION=true to apply the migrations in v1/migrations/sql", err)
+	}
+	slog.Info("Database schema is up to date", "version", migrator.LatestVersion())
+	return nil
 }
@@ -21,6 +21,7 @@ func TestNewDatabaseConfig(t *testing.T) {
 	assert.Equal(t, 5, config.MaxIdleConns)
 	assert.Equal(t, time.Hour, config.ConnMaxLifetime)
 	assert.Equal(t, 30*time.Minute, config.ConnMaxIdleTime)
+	assert.Empty(t, config.ReplicaDSNs)
 }
 
 func TestNewDatabaseConfig_WithEnvVars(t *testing.T) {
@@ -30,6 +31,7 @@ func TestNewDatabaseConfig_WithEnvVars(t *testing.T) {
 	os.Setenv("CHOREO_OPENDIF_DB_PASSWORD", "test-pass")
 	os.Setenv("CHOREO_OPENDIF_DB_DATABASENAME", "test-db")
 	os.Setenv("DB_SSLMODE", "disable")
+	os.Setenv("DB_READ_REPLICA_DSNS", "host=replica-1 dbname=test-db, host=replica-2 dbname=test-db")
 	defer func() {
 		os.Unsetenv("CHOREO_OPENDIF_DB_HOSTNAME")
 		os.Unsetenv("CHOREO_OPENDIF_DB_PORT")
@@ -37,6 +39,7 @@ func TestNewDatabaseConfig_WithEnvVars(t *testing.T) {
 		os.Unsetenv("CHOREO_OPENDIF_DB_PASSWORD")
 		os.Unsetenv("CHOREO_OPENDIF_DB_DATABASENAME")
 		os.Unsetenv("DB_SSLMODE")
+		os.Unsetenv("DB_READ_REPLICA_DSNS")
 	}()
 
 	config := NewDatabaseConfig()
@@ -46,6 +49,7 @@ func TestNewDatabaseConfig_WithEnvVars(t *testing.T) {
 	assert.Equal(t, "test-pass", config.Password)
 	assert.Equal(t, "test-db", config.Database)
 	assert.Equal(t, "disable", config.SSLMode)
+	assert.Equal(t, []string{"host=replica-1 dbname=test-db", "host=replica-2 dbname=test-db"}, config.ReplicaDSNs)
 }
 
 func TestGetEnvOrDefault(t *testing.T) {
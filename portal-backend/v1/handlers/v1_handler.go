@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gov-dx-sandbox/portal-backend/idp"
 	"github.com/gov-dx-sandbox/portal-backend/idp/idpfactory"
@@ -14,15 +17,51 @@ import (
 	"github.com/gov-dx-sandbox/portal-backend/v1/middleware"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
 	"github.com/gov-dx-sandbox/portal-backend/v1/services"
+	"github.com/gov-dx-sandbox/shared/health"
+	"github.com/gov-dx-sandbox/shared/requestid"
 
+	"golang.org/x/oauth2/clientcredentials"
 	"gorm.io/gorm"
 )
 
 // V1Handler handles all V1 API routes
 type V1Handler struct {
-	memberService      *services.MemberService
-	applicationService *services.ApplicationService
-	schemaService      *services.SchemaService
+	memberService        *services.MemberService
+	applicationService   *services.ApplicationService
+	credentialService    *services.ApplicationCredentialService
+	reviewService        *services.SubmissionReviewService
+	schemaService        *services.SchemaService
+	pdpService           *services.PDPService
+	pdpJobService        *services.PDPJobService
+	pdpWorker            *services.PDPWorker
+	pdpSyncService       *services.PDPSyncService
+	metricsService       *services.SubmissionMetricsService
+	sandboxService       *services.SandboxService
+	notificationService  *services.NotificationService
+	notificationWorker   *services.NotificationWorker
+	grantRenewalWorker   *services.GrantRenewalWorker
+	invitationService    *services.InvitationService
+	organizationService  *services.OrganizationService
+	usageService         *services.UsageService
+	auditEventService    *services.AuditEventService
+	catalogService       *services.CatalogService
+	bulkService          *services.BulkService
+	adminGraphService    *services.AdminGraphService
+	webhookService       *services.WebhookService
+	webhookWorker        *services.WebhookWorker
+	impersonationService *services.ImpersonationService
+	approvalService      *services.ApprovalService
+	savedViewService     *services.SavedViewService
+	templateService      *services.FieldConfigurationTemplateService
+	exportService        *services.ExportService
+	attachmentService    *services.AttachmentService
+	attachmentScanWorker *services.AttachmentScanWorker
+
+	// auditServiceURL and pdpJobQueueThreshold configure the health checks
+	auditServiceURL      string
+	pdpJobQueueThreshold int64
+	// idpTokens reports failing identity provider token refreshes, if the provider caches its tokens
+	idpTokens idp.TokenChecker
 }
 
 // getUserMemberID gets the member ID for the authenticated user with caching
@@ -56,34 +95,219 @@ func (h *V1Handler) getUserMemberID(r *http.Request, user *models.AuthenticatedU
 	return memberID, nil
 }
 
-// NewV1Handler creates a new V1 handler
-func NewV1Handler(db *gorm.DB) (*V1Handler, error) {
-	// Get scopes from environment variable, fallback to default if not set
-	asgScopesEnv := os.Getenv("ASGARDEO_SCOPES")
-	var scopes []string
-	if asgScopesEnv != "" {
-		// Split by space to handle multiple scopes
-		scopes = strings.Fields(asgScopesEnv)
+// getUserOrganizationID returns the organization of the caller's member record, if any
+func (h *V1Handler) getUserOrganizationID(r *http.Request, userMemberID string) (*string, error) {
+	member, err := h.memberService.GetMember(r.Context(), userMemberID)
+	if err != nil {
+		return nil, err
+	}
+	return member.OrganizationID, nil
+}
+
+// callerOwns reports whether the caller owns a resource that ownerMemberID created in organizationID:
+// either they created it, or they belong to the organization that owns it
+func (h *V1Handler) callerOwns(r *http.Request, userMemberID, ownerMemberID string, organizationID *string) bool {
+	if ownerMemberID == userMemberID {
+		return true
+	}
+	if organizationID == nil {
+		return false
+	}
+	callerOrganizationID, err := h.getUserOrganizationID(r, userMemberID)
+	if err != nil {
+		slog.Error("Failed to get caller organization", "memberID", userMemberID, "error", err)
+		return false
+	}
+	return callerOrganizationID != nil && *callerOrganizationID == *organizationID
+}
+
+// parseListQuery reads the paging, sorting, name search and creation-time filters shared by all
+// collection endpoints. Resource-specific filters such as memberId are applied by each handler.
+func parseListQuery(r *http.Request) (models.ListQuery, error) {
+	params := r.URL.Query()
+	q := models.ListQuery{
+		Sort:   params.Get("sort"),
+		Order:  models.SortOrder(strings.ToLower(params.Get("order"))),
+		Search: strings.TrimSpace(params.Get("search")),
+	}
+
+	var err error
+	if value := params.Get("page"); value != "" {
+		if q.Page, err = strconv.Atoi(value); err != nil || q.Page < 1 {
+			return q, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "page", "invalid page: must be a positive integer")
+		}
+	}
+	if value := params.Get("limit"); value != "" {
+		if q.Limit, err = strconv.Atoi(value); err != nil || q.Limit < 1 {
+			return q, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "limit", "invalid limit: must be a positive integer")
+		}
+	}
+	if q.Order != "" && q.Order != models.SortOrderAsc && q.Order != models.SortOrderDesc {
+		return q, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "order", "invalid order: must be asc or desc")
+	}
+	for name, dest := range map[string]**time.Time{"createdAfter": &q.CreatedAfter, "createdBefore": &q.CreatedBefore} {
+		if value := params.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return q, models.NewValidationError(nil, models.ValidationErrorInvalidFormat, name, fmt.Sprintf("invalid %s: must be an RFC 3339 timestamp", name))
+			}
+			*dest = &parsed
+		}
+	}
+	return q, nil
+}
+
+// requireRevision sets *revision to the revision named by the If-Match header, which takes
+// precedence over a revision in the request body. An update must name the revision it is based on,
+// so it responds 428 when neither is given; "If-Match: *" updates whatever revision is current.
+func requireRevision(w http.ResponseWriter, r *http.Request, revision **int64) bool {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch {
+	case ifMatch == "*":
+		*revision = nil
+	case ifMatch != "":
+		parsed, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+		if err != nil {
+			respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidFormat, "", "invalid If-Match: must be the ETag of the resource"))
+			return false
+		}
+		*revision = &parsed
+	case *revision == nil:
+		utils.RespondWithError(w, http.StatusPreconditionRequired, "If-Match header or revision is required")
+		return false
+	}
+	return true
+}
+
+// setETag sets the ETag of a response to the revision of the resource it returns
+func setETag(w http.ResponseWriter, revision int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(revision, 10)))
+}
+
+// respondWithRevisionConflict responds 409 with the current state of the resource if err is a
+// stale update, and reports whether it did
+func respondWithRevisionConflict(w http.ResponseWriter, err error) bool {
+	var conflict *services.RevisionConflictError
+	if errors.As(err, &conflict) {
+		utils.RespondWithJSON(w, http.StatusConflict, models.RevisionConflictResponse{Error: conflict.Error(), Current: conflict.Current})
+		return true
+	}
+	if errors.Is(err, services.ErrRevisionConflict) {
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+		return true
 	}
-	// Create the NewIdpProvider
-	baseURL := os.Getenv("ASGARDEO_BASE_URL")
-	clientID := os.Getenv("ASGARDEO_CLIENT_ID")
-	clientSecret := os.Getenv("ASGARDEO_CLIENT_SECRET")
+	return false
+}
+
+// respondWithListError maps collection query failures to HTTP responses
+func respondWithListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrInvalidListQuery) {
+		respondWithBadRequest(w, err)
+		return
+	}
+	utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+}
+
+// respondWithBadRequest responds 400 with the structured validation error envelope, listing the
+// rejected fields when err is a *models.ValidationError
+func respondWithBadRequest(w http.ResponseWriter, err error) {
+	body := models.NewValidationErrorResponse(err)
+	body.RequestID = requestid.FromResponse(w)
+	utils.RespondWithJSON(w, http.StatusBadRequest, body)
+}
+
+// decodeRequestBody decodes the JSON request body into req and responds 400 if it is malformed,
+// naming the offending field when the body has a value of the wrong type
+func decodeRequestBody(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err == nil {
+		return true
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorMalformedBody, typeErr.Field,
+			fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type)))
+		return false
+	}
+	respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorMalformedBody, "", "Invalid request body"))
+	return false
+}
 
-	if baseURL == "" || clientID == "" || clientSecret == "" {
-		return nil, fmt.Errorf("failed to create IDP provider: missing required environment variables (ASGARDEO_BASE_URL, ASGARDEO_CLIENT_ID, ASGARDEO_CLIENT_SECRET)")
+// validateRequest checks req against its validate tags and responds 400 listing the invalid fields
+func validateRequest(w http.ResponseWriter, req interface{}) bool {
+	if err := models.Validate(req); err != nil {
+		respondWithBadRequest(w, err)
+		return false
 	}
+	return true
+}
 
-	idpProvider, err := idpfactory.NewIdpAPIProvider(idpfactory.FactoryConfig{
-		ProviderType: idp.ProviderAsgardeo,
-		BaseURL:      baseURL,
+// newPDPTokenSource creates the source of the portal's PDP access tokens from PDP_OAUTH_TOKEN_URL,
+// PDP_OAUTH_CLIENT_ID, PDP_OAUTH_CLIENT_SECRET and PDP_OAUTH_SCOPES; it returns nil when no client is
+// configured, for PDPs that do not require authentication
+func newPDPTokenSource() (*idp.TokenSource, error) {
+	clientID := os.Getenv("PDP_OAUTH_CLIENT_ID")
+	if clientID == "" {
+		slog.Warn("PDP_OAUTH_CLIENT_ID not set, requests to the PDP are not authenticated")
+		return nil, nil
+	}
+	tokenURL, clientSecret := os.Getenv("PDP_OAUTH_TOKEN_URL"), os.Getenv("PDP_OAUTH_CLIENT_SECRET")
+	if tokenURL == "" || clientSecret == "" {
+		return nil, fmt.Errorf("PDP_OAUTH_TOKEN_URL and PDP_OAUTH_CLIENT_SECRET are required with PDP_OAUTH_CLIENT_ID")
+	}
+	return idp.NewTokenSource(&clientcredentials.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
-		Scopes:       scopes,
-	})
+		TokenURL:     tokenURL,
+		Scopes:       strings.Fields(os.Getenv("PDP_OAUTH_SCOPES")),
+	}), nil
+}
+
+// newIdpProvider creates the identity provider selected by IDP_PROVIDER: asgardeo (the default) or keycloak
+func newIdpProvider() (idp.IdentityProviderAPI, error) {
+	var cfg idpfactory.FactoryConfig
+	switch providerType := idp.ProviderType(os.Getenv("IDP_PROVIDER")); providerType {
+	case "", idp.ProviderAsgardeo:
+		cfg = idpfactory.FactoryConfig{
+			ProviderType: idp.ProviderAsgardeo,
+			BaseURL:      os.Getenv("ASGARDEO_BASE_URL"),
+			ClientID:     os.Getenv("ASGARDEO_CLIENT_ID"),
+			ClientSecret: os.Getenv("ASGARDEO_CLIENT_SECRET"),
+			// Split by space to handle multiple scopes
+			Scopes: strings.Fields(os.Getenv("ASGARDEO_SCOPES")),
+		}
+		if cfg.BaseURL == "" || cfg.ClientID == "" || cfg.ClientSecret == "" {
+			return nil, fmt.Errorf("failed to create IDP provider: missing required environment variables (ASGARDEO_BASE_URL, ASGARDEO_CLIENT_ID, ASGARDEO_CLIENT_SECRET)")
+		}
+	case idp.ProviderKeycloak:
+		cfg = idpfactory.FactoryConfig{
+			ProviderType: idp.ProviderKeycloak,
+			BaseURL:      strings.TrimSuffix(os.Getenv("KEYCLOAK_BASE_URL"), "/"),
+			Realm:        os.Getenv("KEYCLOAK_REALM"),
+			ClientID:     os.Getenv("KEYCLOAK_CLIENT_ID"),
+			ClientSecret: os.Getenv("KEYCLOAK_CLIENT_SECRET"),
+		}
+		if cfg.BaseURL == "" || cfg.Realm == "" || cfg.ClientID == "" || cfg.ClientSecret == "" {
+			return nil, fmt.Errorf("failed to create IDP provider: missing required environment variables (KEYCLOAK_BASE_URL, KEYCLOAK_REALM, KEYCLOAK_CLIENT_ID, KEYCLOAK_CLIENT_SECRET)")
+		}
+	default:
+		return nil, fmt.Errorf("failed to create IDP provider: unsupported IDP_PROVIDER %q", providerType)
+	}
+
+	idpProvider, err := idpfactory.NewIdpAPIProvider(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create IDP provider: %w", err)
 	}
+	return idpProvider, nil
+}
+
+// NewV1Handler creates a new V1 handler
+func NewV1Handler(db *gorm.DB) (*V1Handler, error) {
+	idpProvider, err := newIdpProvider()
+	if err != nil {
+		return nil, err
+	}
+	idpTokens, _ := idpProvider.(idp.TokenChecker)
 	memberService := services.NewMemberService(db, idpProvider)
 
 	pdpServiceURL := os.Getenv("CHOREO_PDP_CONNECTION_SERVICEURL")
@@ -99,13 +323,270 @@ func NewV1Handler(db *gorm.DB) (*V1Handler, error) {
 	pdpService := services.NewPDPService(pdpServiceURL, pdpServiceAPIKey)
 	slog.Info("PDP Service URL", "url", pdpServiceURL)
 
+	// The PDP authenticates the portal with access tokens issued to PDP_OAUTH_CLIENT_ID, which the
+	// identity provider must issue with the OpenDIF_System role
+	pdpTokens, err := newPDPTokenSource()
+	if err != nil {
+		return nil, err
+	}
+	pdpService.SetTokenSource(pdpTokens)
+
+	// PDP sync jobs are polled every PDP_JOB_POLL_INTERVAL; 0s disables the worker
+	pdpJobPollInterval, err := durationFromEnv("PDP_JOB_POLL_INTERVAL", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	pdpJobService := services.NewPDPJobService(db, pdpService)
+
+	// Credentials must be rotated within APPLICATION_CREDENTIAL_LIFETIME; 0s means they do not expire
+	credentialLifetime, err := durationFromEnv("APPLICATION_CREDENTIAL_LIFETIME", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Notification emails are sent through SMTP_HOST; without it members only get in-app notifications
+	var emailSender services.EmailSender
+	smtpSender, err := services.NewSMTPEmailSenderFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP configuration: %w", err)
+	}
+	if smtpSender != nil {
+		emailSender = smtpSender
+	}
+	notificationPollInterval, err := durationFromEnv("NOTIFICATION_POLL_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	credentialExpiryNotice, err := durationFromEnv("CREDENTIAL_EXPIRY_NOTICE", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renewals are opened for application grants expiring within GRANT_RENEWAL_NOTICE, checked every
+	// GRANT_RENEWAL_POLL_INTERVAL; 0s disables the worker
+	grantRenewalPollInterval, err := durationFromEnv("GRANT_RENEWAL_POLL_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	grantRenewalNotice, err := durationFromEnv("GRANT_RENEWAL_NOTICE", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	// Webhook deliveries are polled every WEBHOOK_POLL_INTERVAL; 0s disables the worker
+	webhookPollInterval, err := durationFromEnv("WEBHOOK_POLL_INTERVAL", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookService := services.NewWebhookService(db)
+	notificationService := services.NewNotificationService(db, emailSender, webhookService)
+
+	// Member invitations can be accepted for INVITATION_TTL after they are created
+	invitationTTL, err := durationFromEnv("INVITATION_TTL", 72*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	// Support admins can act as a member for IMPERSONATION_TTL before starting a new session
+	impersonationTTL, err := durationFromEnv("IMPERSONATION_TTL", 30*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	// The admin actions in FOUR_EYES_ACTIONS, e.g. "delete_member,approve_sensitive_schema,rotate_credential",
+	// only run once a second admin confirms them, within FOUR_EYES_APPROVAL_TTL of the request
+	approvalActions, err := models.ParseApprovalActions(os.Getenv("FOUR_EYES_ACTIONS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FOUR_EYES_ACTIONS: %w", err)
+	}
+	approvalTTL, err := durationFromEnv("FOUR_EYES_APPROVAL_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	// Submissions pass the review steps in SUBMISSION_REVIEW_WORKFLOW, e.g. "technical_review:1,security_review:2"
+	reviewWorkflow := services.DefaultReviewWorkflow
+	if value := os.Getenv("SUBMISSION_REVIEW_WORKFLOW"); value != "" {
+		reviewWorkflow, err = services.ParseReviewWorkflow(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SUBMISSION_REVIEW_WORKFLOW: %w", err)
+		}
+	}
+	schemaService := services.NewSchemaService(db, pdpService)
+	applicationService := services.NewApplicationService(db, pdpService, idpProvider)
+
+	// Schema submissions are only approved once their endpoint serves the submitted SDL; each probe
+	// query times out after SCHEMA_ENDPOINT_PROBE_TIMEOUT, and 0s disables the check
+	schemaEndpointProbeTimeout, err := durationFromEnv("SCHEMA_ENDPOINT_PROBE_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	var endpointProber *services.SchemaEndpointProber
+	if schemaEndpointProbeTimeout > 0 {
+		endpointProber = services.NewSchemaEndpointProber(schemaEndpointProbeTimeout)
+	}
+
+	// With SANDBOX_ENABLED, approved applications get a sandbox client whose requests the exchange
+	// routes to mock providers served here, at SANDBOX_PROVIDER_BASE_URL
+	var sandboxService *services.SandboxService
+	if value := os.Getenv("SANDBOX_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SANDBOX_ENABLED: %w", err)
+		}
+		if enabled {
+			providerBaseURL := utils.GetEnvOrDefault("SANDBOX_PROVIDER_BASE_URL", "http://localhost:3000")
+			sandboxService = services.NewSandboxService(db, idpProvider, providerBaseURL)
+		}
+	}
+
+	// Application usage comes from the statistics of the audit service that portal-backend audits to.
+	// Its read APIs need an admin or system user's token when authentication is enabled there.
+	auditServiceURL := utils.GetEnvOrDefault("CHOREO_AUDIT_CONNECTION_SERVICEURL", "http://localhost:3001")
+	usageService := services.NewUsageService(db, auditServiceURL, os.Getenv("AUDIT_SERVICE_READ_TOKEN"))
+	auditEventService := services.NewAuditEventService(db, auditServiceURL, os.Getenv("AUDIT_SERVICE_READ_TOKEN"))
+	reviewService := services.NewSubmissionReviewService(db, schemaService, applicationService, endpointProber, sandboxService, reviewWorkflow)
+	credentialService := services.NewApplicationCredentialService(db, idpProvider, credentialLifetime)
+	approvalService := services.NewApprovalService(db, approvalActions, approvalTTL, memberService, reviewService, credentialService)
+
+	// Schema submission attachments are stored in the S3-compatible bucket ATTACHMENT_STORAGE_BUCKET
+	// and scanned by the clamd daemon at CLAMAV_ADDRESS every ATTACHMENT_SCAN_POLL_INTERVAL; without a
+	// bucket attachments are disabled, and without clamd they are stored unscanned
+	attachmentStorage, err := services.NewS3AttachmentStorageFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachment storage configuration: %w", err)
+	}
+	var storage services.AttachmentStorage
+	if attachmentStorage != nil {
+		storage = attachmentStorage
+	}
+	var virusScanner services.VirusScanner
+	if clamAVScanner := services.NewClamAVScannerFromEnv(); clamAVScanner != nil {
+		virusScanner = clamAVScanner
+	}
+	attachmentMaxSize := int64(services.DefaultAttachmentMaxSize)
+	if value := os.Getenv("ATTACHMENT_MAX_SIZE"); value != "" {
+		attachmentMaxSize, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || attachmentMaxSize <= 0 {
+			return nil, fmt.Errorf("invalid ATTACHMENT_MAX_SIZE: %s", value)
+		}
+	}
+	attachmentURLTTL, err := durationFromEnv("ATTACHMENT_URL_TTL", services.DefaultAttachmentURLTTL)
+	if err != nil {
+		return nil, err
+	}
+	attachmentScanPollInterval, err := durationFromEnv("ATTACHMENT_SCAN_POLL_INTERVAL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	attachmentService := services.NewAttachmentService(db, storage, virusScanner, attachmentMaxSize, attachmentURLTTL)
+
+	// The service reports itself degraded while more than PDP_JOB_QUEUE_HEALTH_THRESHOLD PDP sync
+	// jobs are pending
+	pdpJobQueueThreshold := int64(1000)
+	if value := os.Getenv("PDP_JOB_QUEUE_HEALTH_THRESHOLD"); value != "" {
+		pdpJobQueueThreshold, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || pdpJobQueueThreshold < 0 {
+			return nil, fmt.Errorf("invalid PDP_JOB_QUEUE_HEALTH_THRESHOLD: %s", value)
+		}
+	}
+
 	return &V1Handler{
-		memberService:      memberService,
-		schemaService:      services.NewSchemaService(db, pdpService),
-		applicationService: services.NewApplicationService(db, pdpService, idpProvider),
+		memberService:        memberService,
+		schemaService:        schemaService,
+		applicationService:   applicationService,
+		credentialService:    credentialService,
+		reviewService:        reviewService,
+		pdpService:           pdpService,
+		pdpJobService:        pdpJobService,
+		pdpWorker:            services.NewPDPWorker(pdpJobService, pdpJobPollInterval),
+		pdpSyncService:       services.NewPDPSyncService(db, pdpService),
+		sandboxService:       sandboxService,
+		metricsService:       services.NewSubmissionMetricsService(db),
+		notificationService:  notificationService,
+		notificationWorker:   services.NewNotificationWorker(notificationService, notificationPollInterval, credentialExpiryNotice),
+		grantRenewalWorker:   services.NewGrantRenewalWorker(applicationService, notificationService, grantRenewalPollInterval, grantRenewalNotice),
+		invitationService:    services.NewInvitationService(db, memberService, invitationTTL),
+		organizationService:  services.NewOrganizationService(db, memberService),
+		usageService:         usageService,
+		auditEventService:    auditEventService,
+		catalogService:       services.NewCatalogService(db, pdpService),
+		bulkService:          services.NewBulkService(db, reviewService, approvalService),
+		adminGraphService:    services.NewAdminGraphService(db),
+		webhookService:       webhookService,
+		webhookWorker:        services.NewWebhookWorker(webhookService, webhookPollInterval),
+		impersonationService: services.NewImpersonationService(db, impersonationTTL),
+		approvalService:      approvalService,
+		savedViewService:     services.NewSavedViewService(db),
+		templateService:      services.NewFieldConfigurationTemplateService(db),
+		exportService:        services.NewExportService(memberService, applicationService, schemaService),
+		attachmentService:    attachmentService,
+		attachmentScanWorker: services.NewAttachmentScanWorker(attachmentService, attachmentScanPollInterval),
+		auditServiceURL:      auditServiceURL,
+		pdpJobQueueThreshold: pdpJobQueueThreshold,
+		idpTokens:            idpTokens,
 	}, nil
 }
 
+// durationFromEnv reads a duration such as "10s" from the environment variable name
+func durationFromEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return duration, nil
+}
+
+// RegisterHealthChecks adds the checks of the services the portal depends on. Members can keep
+// using the portal while the PDP or the audit service is unreachable, since PDP changes are queued
+// and audit events are spooled, so these checks only degrade the portal.
+func (h *V1Handler) RegisterHealthChecks(checker *utils.HealthChecker) {
+	checker.RegisterOptional("policy_decision_point", h.pdpService.CheckHealth)
+	checker.RegisterOptional("pdp_job_queue", health.ThresholdCheck(h.pdpJobService.CountPendingJobs, h.pdpJobQueueThreshold))
+	checker.RegisterOptional("audit_service", health.HTTPCheck(nil, h.auditServiceURL+"/health/live"))
+	if h.idpTokens != nil {
+		checker.RegisterOptional("identity_provider_token", h.idpTokens.CheckToken)
+	}
+}
+
+// PDPWorker returns the worker that delivers queued PDP sync jobs; the caller starts it
+func (h *V1Handler) PDPWorker() *services.PDPWorker {
+	return h.pdpWorker
+}
+
+// NotificationWorker returns the worker that sends notification emails and credential expiry
+// notices; the caller starts it
+func (h *V1Handler) NotificationWorker() *services.NotificationWorker {
+	return h.notificationWorker
+}
+
+// GrantRenewalWorker returns the worker that opens renewals for expiring application grants; the
+// caller starts it
+func (h *V1Handler) GrantRenewalWorker() *services.GrantRenewalWorker {
+	return h.grantRenewalWorker
+}
+
+// WebhookWorker returns the worker that sends queued webhook deliveries; the caller starts it
+func (h *V1Handler) WebhookWorker() *services.WebhookWorker {
+	return h.webhookWorker
+}
+
+// AttachmentScanWorker returns the worker that scans submission attachments for viruses; the caller
+// starts it
+func (h *V1Handler) AttachmentScanWorker() *services.AttachmentScanWorker {
+	return h.attachmentScanWorker
+}
+
+// ImpersonationService returns the service that resolves the impersonation sessions named in
+// requests; the caller wires it into the impersonation middleware
+func (h *V1Handler) ImpersonationService() *services.ImpersonationService {
+	return h.impersonationService
+}
+
 // SetupV1Routes configures all V1 API routes
 func (h *V1Handler) SetupV1Routes(mux *http.ServeMux) {
 	// Schema routes
@@ -119,6 +600,7 @@ func (h *V1Handler) SetupV1Routes(mux *http.ServeMux) {
 	// Application routes
 	mux.Handle("/internal/api/v1/applications", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleInternalApplications)))
 	mux.Handle("/internal/api/v1/applications/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleInternalApplications)))
+	mux.Handle("/internal/api/v1/sandbox/providers/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleSandboxProviders)))
 	mux.Handle("/api/v1/applications", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleApplications)))
 	mux.Handle("/api/v1/applications/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleApplications)))
 
@@ -129,82 +611,85 @@ func (h *V1Handler) SetupV1Routes(mux *http.ServeMux) {
 	// Member routes
 	mux.Handle("/api/v1/members", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleMembers)))
 	mux.Handle("/api/v1/members/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleMembers)))
-}
 
-// handleMembers handles member-related routes
-func (h *V1Handler) handleMembers(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/v1/members")
-	parts := strings.Split(strings.Trim(path, "/"), "/")
+	// PDP sync job admin routes
+	mux.Handle("/api/v1/admin/pdp-jobs", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handlePDPJobs)))
+	mux.Handle("/api/v1/admin/pdp-jobs/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handlePDPJobs)))
 
-	// Handle collection endpoint: GET /api/v1/members and POST /api/v1/members
-	if len(parts) == 1 && parts[0] == "" {
-		switch r.Method {
-		case http.MethodGet:
-			idpUserId := r.URL.Query().Get("idpUserId")
-			email := r.URL.Query().Get("email")
-			h.getAllMembers(w, r, &idpUserId, &email)
-		case http.MethodPost:
-			h.createMember(w, r)
-		default:
-			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		}
-		return
-	}
+	// PDP grant reconciliation routes
+	mux.Handle("/api/v1/pdp-sync/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handlePDPSync)))
 
-	if len(parts) < 1 || parts[0] == "" {
-		utils.RespondWithError(w, http.StatusBadRequest, "Member ID is required")
-		return
-	}
+	// Submission throughput metrics routes
+	mux.Handle("/api/v1/admin/metrics/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleAdminMetrics)))
 
-	memberId := parts[0]
+	// Bulk admin operation routes
+	mux.Handle("/api/v1/admin/bulk/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleBulkOperations)))
 
-	// Handle base member endpoint: GET /api/v1/members/:memberId and PUT /api/v1/members/:memberId
-	if len(parts) == 1 {
-		switch r.Method {
-		case http.MethodGet:
-			h.getMember(w, r, memberId)
-		case http.MethodPut:
-			h.updateMember(w, r, memberId)
-		default:
-			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		}
-		return
-	}
+	// Admin GraphQL API route
+	mux.Handle("/api/v1/admin/graphql", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleAdminGraph)))
 
-	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	// Impersonation routes
+	mux.Handle("/api/v1/admin/impersonation", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleImpersonation)))
+
+	// Two-person approval routes
+	mux.Handle("/api/v1/admin/approvals", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleApprovals)))
+	mux.Handle("/api/v1/admin/approvals/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleApprovals)))
+
+	// Saved view and export routes
+	mux.Handle("/api/v1/admin/saved-views", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleSavedViews)))
+	mux.Handle("/api/v1/admin/saved-views/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleSavedViews)))
+	mux.Handle("/api/v1/admin/exports/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleExports)))
+
+	// Field configuration template routes
+	mux.Handle("/api/v1/admin/field-configuration-templates", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleFieldConfigurationTemplates)))
+	mux.Handle("/api/v1/admin/field-configuration-templates/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleFieldConfigurationTemplates)))
+
+	// Notification routes
+	mux.Handle("/api/v1/notifications", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleNotifications)))
+	mux.Handle("/api/v1/notifications/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleNotifications)))
+
+	// Invitation admin routes
+	mux.Handle("/api/v1/invitations", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleInvitations)))
+	mux.Handle("/api/v1/invitations/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleInvitations)))
+
+	// Organization routes
+	mux.Handle("/api/v1/organizations", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleOrganizations)))
+	mux.Handle("/api/v1/organizations/", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.handleOrganizations)))
+
+	// Field catalog routes
+	mux.Handle("/api/v1/catalog/fields", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.getCatalogFields)))
+
+	// Audit event routes
+	mux.Handle("/api/v1/audit-events", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.getAuditEvents)))
 }
 
-// handleSchemas handles schema-related routes
-func (h *V1Handler) handleSchemas(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/v1/schemas")
+// SetupPublicRoutes configures the V1 routes that are called without a JWT. An invitee has no
+// account until they accept their invitation, so the invitation token authenticates them instead.
+func (h *V1Handler) SetupPublicRoutes(mux *http.ServeMux) {
+	mux.Handle("/api/v1/invitations/accept", utils.PanicRecoveryMiddleware(http.HandlerFunc(h.acceptInvitation)))
+}
+
+// handlePDPJobs handles PDP sync job admin routes
+func (h *V1Handler) handlePDPJobs(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/pdp-jobs")
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 
-	// Handle collection endpoint: GET /api/v1/schemas and POST /api/v1/schemas
+	// Handle collection endpoint: GET /api/v1/admin/pdp-jobs
 	if len(parts) == 1 && parts[0] == "" {
 		switch r.Method {
 		case http.MethodGet:
-			memberId := r.URL.Query().Get("memberId")
-			h.getAllSchemas(w, r, &memberId)
-		case http.MethodPost:
-			h.createSchema(w, r)
+			h.getAllPDPJobs(w, r)
 		default:
 			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		}
 		return
 	}
-	if len(parts) < 1 || parts[0] == "" {
-		utils.RespondWithError(w, http.StatusBadRequest, "Schema ID is required")
-		return
-	}
-	schemaId := parts[0]
 
-	// Handle specific schema endpoint: GET /api/v1/schemas/:schemaId and PUT /api/v1/schemas/:schemaId
-	if len(parts) == 1 {
+	// Handle requeue endpoint: POST /api/v1/admin/pdp-jobs/:jobId/requeue
+	if len(parts) == 2 && parts[0] != "" && parts[1] == "requeue" {
 		switch r.Method {
-		case http.MethodGet:
-			h.getSchema(w, r, schemaId)
-		case http.MethodPut:
-			h.updateSchema(w, r, schemaId)
+		case http.MethodPost:
+			h.requeuePDPJob(w, r, parts[0])
 		default:
 			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		}
@@ -214,434 +699,3727 @@ func (h *V1Handler) handleSchemas(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
 }
 
-// handleSchemaSubmissions handles schema submission-related routes
-func (h *V1Handler) handleSchemaSubmissions(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/v1/schema-submissions")
-	parts := strings.Split(strings.Trim(path, "/"), "/")
-
-	// Handle collection endpoint: GET /api/v1/schema-submissions and POST /api/v1/schema-submissions
-	if len(parts) == 1 && parts[0] == "" {
-		switch r.Method {
-		case http.MethodGet:
-			status := r.URL.Query()["status"]
-			memberId := r.URL.Query().Get("memberId")
-			h.getAllSchemaSubmissions(w, r, &memberId, &status)
-		case http.MethodPost:
-			h.createSchemaSubmission(w, r, nil)
-		default:
+// handlePDPSync handles the PDP grant reconciliation routes: GET /api/v1/pdp-sync/status and
+// POST /api/v1/pdp-sync/repair
+func (h *V1Handler) handlePDPSync(w http.ResponseWriter, r *http.Request) {
+	switch strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/pdp-sync"), "/") {
+	case "status":
+		if r.Method != http.MethodGet {
 			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
 		}
-		return
-	}
-	if len(parts) < 1 || parts[0] == "" {
-		utils.RespondWithError(w, http.StatusBadRequest, "Submission ID is required")
-		return
-	}
-	submissionId := parts[0]
-	// Handle specific schema submission endpoint: GET /api/v1/schema-submissions/:submissionId and PUT /api/v1/schema-submissions/:submissionId
-	if len(parts) == 1 {
-		switch r.Method {
-		case http.MethodGet:
-			h.getSchemaSubmission(w, r, submissionId)
-		case http.MethodPut:
-			h.updateSchemaSubmission(w, r, submissionId)
-		default:
+		h.getPDPSyncStatus(w, r)
+	case "repair":
+		if r.Method != http.MethodPost {
 			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
 		}
-		return
+		h.repairPDPSync(w, r)
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
 	}
-
-	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
 }
 
-// handleInternalApplications handles internal application-related routes
-func (h *V1Handler) handleInternalApplications(w http.ResponseWriter, r *http.Request) {
-	// Only internal operation currently needed is getApplicationId by IdpClientId
-	if r.URL.Path != "/internal/api/v1/applications" && r.URL.Path != "/internal/api/v1/applications/" {
+// handleAdminMetrics handles the submission throughput metrics routes: GET /api/v1/admin/metrics/submissions
+// and GET /api/v1/admin/metrics/reviews
+func (h *V1Handler) handleAdminMetrics(w http.ResponseWriter, r *http.Request) {
+	switch strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/metrics"), "/") {
+	case "submissions":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.getSubmissionMetrics(w, r)
+	case "reviews":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.getReviewMetrics(w, r)
+	default:
 		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
-		return
 	}
-	switch r.Method {
+}
+
+// handleBulkOperations handles bulk admin operation routes: POST /api/v1/admin/bulk/:operation
+func (h *V1Handler) handleBulkOperations(w http.ResponseWriter, r *http.Request) {
+	operation := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/bulk"), "/")
+	switch operation {
+	case services.BulkOperationReviewSubmissions, services.BulkOperationExpireApplications, services.BulkOperationReassign,
+		services.BulkOperationImportMembers:
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	h.executeBulkOperation(w, r, operation)
+}
+
+// handleImpersonation starts, shows and stops the caller's impersonation session. These requests are
+// always made as the admin, so a session can be stopped while the client still sends its header.
+func (h *V1Handler) handleImpersonation(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission - only admin users can impersonate members
+	if !user.HasPermission(models.PermissionImpersonateMember) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	switch r.Method {
 	case http.MethodGet:
-		h.getApplicationIdByClientId(w, r)
+		session, err := h.impersonationService.GetActiveImpersonation(r.Context(), user.IdpUserID)
+		if err != nil {
+			respondWithImpersonationError(w, err, "No active impersonation session")
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, session)
+	case http.MethodPost:
+		var req models.StartImpersonationRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		if !validateRequest(w, &req) {
+			return
+		}
+		session, err := h.impersonationService.StartImpersonation(r.Context(), user, &req)
+		if err != nil {
+			respondWithImpersonationError(w, err, "Member not found")
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusCreated, session)
+	case http.MethodDelete:
+		if _, err := h.impersonationService.StopImpersonation(r.Context(), user.IdpUserID); err != nil {
+			respondWithImpersonationError(w, err, "No active impersonation session")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// respondWithImpersonationError maps impersonation failures to HTTP responses, answering notFound when
+// the member or session does not exist
+func respondWithImpersonationError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, services.ErrSelfImpersonation):
+		respondWithBadRequest(w, err)
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, notFound)
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// handleApprovals handles the two-person approval routes:
+//
+//	GET  /api/v1/admin/approvals?status=pending
+//	GET  /api/v1/admin/approvals/:approvalId
+//	POST /api/v1/admin/approvals/:approvalId/confirm
+//	POST /api/v1/admin/approvals/:approvalId/reject
+func (h *V1Handler) handleApprovals(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	permission := models.PermissionReadApprovals
+	if r.Method == http.MethodPost {
+		permission = models.PermissionDecideApprovals
+	}
+	if !user.HasPermission(permission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/approvals")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.getAllApprovals(w, r)
+	case len(parts) == 1:
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		approval, err := h.approvalService.GetApproval(r.Context(), parts[0])
+		if err != nil {
+			respondWithApprovalError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, approval)
+	case len(parts) == 2 && (parts[1] == "confirm" || parts[1] == "reject"):
+		if r.Method != http.MethodPost {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if parts[1] == "confirm" {
+			h.confirmApproval(w, r, user, parts[0])
+			return
+		}
+		approval, err := h.approvalService.RejectApproval(r.Context(), parts[0], user)
+		if err != nil {
+			respondWithApprovalError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, approval)
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	}
+}
+
+func (h *V1Handler) getAllApprovals(w http.ResponseWriter, r *http.Request) {
+	status := models.ApprovalStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", models.ApprovalStatusPending, models.ApprovalStatusConfirmed, models.ApprovalStatusRejected, models.ApprovalStatusExpired:
+	default:
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "status",
+			"status must be pending, confirmed, rejected or expired"))
+		return
+	}
+
+	approvals, err := h.approvalService.ListApprovals(r.Context(), status)
+	if err != nil {
+		respondWithApprovalError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items: approvals,
+		Count: len(approvals),
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+// confirmApproval confirms an approval as user, running its action. An approved schema submission
+// notifies its owner like any other review decision.
+func (h *V1Handler) confirmApproval(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser, approvalId string) {
+	approval, err := h.approvalService.GetApproval(r.Context(), approvalId)
+	if err != nil {
+		respondWithApprovalError(w, err)
+		return
+	}
+	var previousStatus string
+	if approval.Action == models.ApprovalActionApproveSensitiveSchema {
+		if submission, err := h.schemaService.GetSchemaSubmission(approval.ResourceID); err == nil {
+			previousStatus = submission.Status
+		}
+	}
+
+	confirmed, err := h.approvalService.ConfirmApproval(r.Context(), approvalId, user)
+	if err != nil {
+		respondWithApprovalError(w, err)
+		return
+	}
+	if review, ok := confirmed.Result.(*models.SubmissionReviewResponse); ok {
+		h.notifySubmissionStatusChanged(r, models.SubmissionTypeSchema, approval.ResourceID, previousStatus, review.Status)
+	}
+	if credential, ok := confirmed.Result.(*models.ApplicationCredentialResponse); ok {
+		logCredentialEvent(r, models.SecurityActionCredentialRotated, credential)
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, confirmed)
+}
+
+// requiresApproval reports whether user's action only runs once a second admin confirms it. Only
+// admins' actions do; members acting on their own resources have no second person to ask.
+func (h *V1Handler) requiresApproval(user *models.AuthenticatedUser, action models.ApprovalAction) bool {
+	return user.IsAdmin() && h.approvalService.Requires(action)
+}
+
+// requestApproval records user's request for an action instead of running it, and answers 202
+// Accepted with the approval a second admin has to confirm
+func (h *V1Handler) requestApproval(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser, action models.ApprovalAction, resourceId string, payload models.ApprovalPayload) {
+	approval, err := h.approvalService.RequestApproval(r.Context(), user, action, resourceId, payload)
+	if err != nil {
+		respondWithApprovalError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusAccepted, approval)
+}
+
+// respondWithApprovalError maps approval failures, and failures of the actions confirmed approvals
+// run, to HTTP responses
+func respondWithApprovalError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrSelfApproval):
+		utils.RespondWithError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrApprovalNotPending), errors.Is(err, services.ErrCredentialNotActive),
+		errors.Is(err, services.ErrApplicationNotProvisioned):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		respondWithReviewError(w, err)
+	}
+}
+
+// handleSavedViews handles the saved view routes:
+//
+//	GET    /api/v1/admin/saved-views?collection=members
+//	POST   /api/v1/admin/saved-views
+//	GET    /api/v1/admin/saved-views/:viewId
+//	PUT    /api/v1/admin/saved-views/:viewId
+//	DELETE /api/v1/admin/saved-views/:viewId
+//	GET    /api/v1/admin/saved-views/:viewId/export?format=csv
+func (h *V1Handler) handleSavedViews(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/saved-views")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	permission := models.PermissionManageSavedViews
+	if len(parts) == 2 && parts[1] == "export" {
+		permission = models.PermissionExportCollections
+	}
+	if !user.HasPermission(permission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getAllSavedViews(w, r, user)
+		case http.MethodPost:
+			h.createSavedView(w, r, user)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case len(parts) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			view, err := h.savedViewService.GetSavedView(r.Context(), user.IdpUserID, parts[0])
+			if err != nil {
+				respondWithSavedViewError(w, err)
+				return
+			}
+			utils.RespondWithSuccess(w, http.StatusOK, view)
+		case http.MethodPut:
+			h.updateSavedView(w, r, user, parts[0])
+		case http.MethodDelete:
+			if err := h.savedViewService.DeleteSavedView(r.Context(), user.IdpUserID, parts[0]); err != nil {
+				respondWithSavedViewError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case len(parts) == 2 && parts[1] == "export":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		view, err := h.savedViewService.GetSavedView(r.Context(), user.IdpUserID, parts[0])
+		if err != nil {
+			respondWithSavedViewError(w, err)
+			return
+		}
+		h.exportCollection(w, r, view.Collection, view.Query)
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	}
+}
+
+func (h *V1Handler) getAllSavedViews(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser) {
+	collection := models.SavedViewCollection(r.URL.Query().Get("collection"))
+	if collection != "" && !collection.IsValid() {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "collection",
+			"collection must be members, applications, application-submissions or schema-submissions"))
+		return
+	}
+
+	views, err := h.savedViewService.ListSavedViews(r.Context(), user.IdpUserID, collection)
+	if err != nil {
+		respondWithSavedViewError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items: views,
+		Count: len(views),
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) createSavedView(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser) {
+	var req models.CreateSavedViewRequest
+	if !decodeRequestBody(w, r, &req) || !validateRequest(w, &req) {
+		return
+	}
+
+	view, err := h.savedViewService.CreateSavedView(r.Context(), user.IdpUserID, &req)
+	if err != nil {
+		respondWithSavedViewError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusCreated, view)
+}
+
+func (h *V1Handler) updateSavedView(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser, viewId string) {
+	var req models.UpdateSavedViewRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	view, err := h.savedViewService.UpdateSavedView(r.Context(), user.IdpUserID, viewId, &req)
+	if err != nil {
+		respondWithSavedViewError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, view)
+}
+
+// respondWithSavedViewError maps saved view failures to HTTP responses
+func respondWithSavedViewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Saved view not found")
+	case errors.Is(err, services.ErrSavedViewNotOwned):
+		utils.RespondWithError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrSavedViewNameTaken):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		respondWithListError(w, err)
+	}
+}
+
+// handleFieldConfigurationTemplates handles the field configuration template routes:
+//
+//	GET    /api/v1/admin/field-configuration-templates
+//	POST   /api/v1/admin/field-configuration-templates
+//	GET    /api/v1/admin/field-configuration-templates/:templateId
+//	PUT    /api/v1/admin/field-configuration-templates/:templateId
+//	DELETE /api/v1/admin/field-configuration-templates/:templateId
+func (h *V1Handler) handleFieldConfigurationTemplates(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if !user.HasPermission(models.PermissionManageFieldConfigurationTemplates) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	templateID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/field-configuration-templates"), "/")
+	switch {
+	case templateID == "":
+		switch r.Method {
+		case http.MethodGet:
+			templates, err := h.templateService.ListTemplates(r.Context())
+			if err != nil {
+				respondWithFieldConfigurationTemplateError(w, err)
+				return
+			}
+			utils.RespondWithSuccess(w, http.StatusOK, models.CollectionResponse{Items: templates, Count: len(templates)})
+		case http.MethodPost:
+			var req models.CreateFieldConfigurationTemplateRequest
+			if !decodeRequestBody(w, r, &req) || !validateRequest(w, &req) {
+				return
+			}
+			template, err := h.templateService.CreateTemplate(r.Context(), &req)
+			if err != nil {
+				respondWithFieldConfigurationTemplateError(w, err)
+				return
+			}
+			utils.RespondWithSuccess(w, http.StatusCreated, template)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case !strings.Contains(templateID, "/"):
+		switch r.Method {
+		case http.MethodGet:
+			template, err := h.templateService.GetTemplate(r.Context(), templateID)
+			if err != nil {
+				respondWithFieldConfigurationTemplateError(w, err)
+				return
+			}
+			utils.RespondWithSuccess(w, http.StatusOK, template)
+		case http.MethodPut:
+			var req models.UpdateFieldConfigurationTemplateRequest
+			if !decodeRequestBody(w, r, &req) {
+				return
+			}
+			template, err := h.templateService.UpdateTemplate(r.Context(), templateID, &req)
+			if err != nil {
+				respondWithFieldConfigurationTemplateError(w, err)
+				return
+			}
+			utils.RespondWithSuccess(w, http.StatusOK, template)
+		case http.MethodDelete:
+			if err := h.templateService.DeleteTemplate(r.Context(), templateID); err != nil {
+				respondWithFieldConfigurationTemplateError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	}
+}
+
+// respondWithFieldConfigurationTemplateError maps field configuration template failures to HTTP responses
+func respondWithFieldConfigurationTemplateError(w http.ResponseWriter, err error) {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Field configuration template not found")
+	case errors.Is(err, services.ErrFieldConfigurationTemplateConflict):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	case errors.As(err, &validationErr):
+		respondWithBadRequest(w, err)
 	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// handleExports exports an admin collection, filtered and sorted by the same query parameters as its
+// list endpoint: GET /api/v1/admin/exports/:collection?format=csv
+func (h *V1Handler) handleExports(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if !user.HasPermission(models.PermissionExportCollections) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	collection := models.SavedViewCollection(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/exports"), "/"))
+	if !collection.IsValid() {
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+		return
+	}
+	if r.Method != http.MethodGet {
 		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q, err := parseListQuery(r)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+	params := r.URL.Query()
+	query := models.SavedViewQuery{
+		Sort:          q.Sort,
+		Order:         q.Order,
+		Search:        q.Search,
+		Status:        params["status"],
+		CreatedAfter:  q.CreatedAfter,
+		CreatedBefore: q.CreatedBefore,
+	}
+	for name, dest := range map[string]**string{"memberId": &query.MemberID, "idpUserId": &query.IdpUserID, "email": &query.Email} {
+		if value := params.Get(name); value != "" {
+			*dest = &value
+		}
+	}
+	h.exportCollection(w, r, collection, query)
+}
+
+// exportCollection streams every item of a collection matching query as the file format named by
+// the format parameter, CSV by default. The query is checked before the response starts; a failure
+// while streaming can only end the response early, so it is logged.
+func (h *V1Handler) exportCollection(w http.ResponseWriter, r *http.Request, collection models.SavedViewCollection, query models.SavedViewQuery) {
+	format := models.ExportFormat(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		format = models.ExportFormatCSV
+	}
+	if !format.IsValid() {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "format", "format must be csv or xlsx"))
+		return
+	}
+	if err := services.ValidateCollectionQuery(collection, query); err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", collection, time.Now().UTC().Format("20060102-150405"), format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if err := h.exportService.ExportCollection(r.Context(), collection, query.ToListQuery(), format, w); err != nil {
+		slog.Error("Failed to export collection", "collection", collection, "format", format, "error", err)
+	}
+}
+
+// handleAdminGraph handles the admin GraphQL API: POST runs a query and GET returns the schema's SDL
+func (h *V1Handler) handleAdminGraph(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission - only admin users can query the admin GraphQL API
+	if !user.HasPermission(models.PermissionQueryAdminGraph) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(services.AdminGraphSDL)); err != nil {
+			slog.Error("Failed to write admin GraphQL schema", "error", err)
+		}
+	case http.MethodPost:
+		var req models.GraphQLRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		if strings.TrimSpace(req.Query) == "" {
+			respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorRequired, "query", "query is required"))
+			return
+		}
+		// Query errors are reported in the GraphQL response itself
+		utils.RespondWithSuccess(w, http.StatusOK, h.adminGraphService.Execute(r.Context(), &req))
+	default:
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleMembers handles member-related routes
+func (h *V1Handler) handleMembers(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/members")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Handle collection endpoint: GET /api/v1/members and POST /api/v1/members
+	if len(parts) == 1 && parts[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			idpUserId := r.URL.Query().Get("idpUserId")
+			email := r.URL.Query().Get("email")
+			h.getAllMembers(w, r, &idpUserId, &email)
+		case http.MethodPost:
+			h.createMember(w, r)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	if len(parts) < 1 || parts[0] == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Member ID is required")
+		return
+	}
+
+	memberId := parts[0]
+
+	// Handle base member endpoint: GET, PUT and DELETE /api/v1/members/:memberId
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			h.getMember(w, r, memberId)
+		case http.MethodPut:
+			h.updateMember(w, r, memberId)
+		case http.MethodDelete:
+			h.deleteMember(w, r, memberId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle restore endpoint: POST /api/v1/members/:memberId/restore
+	if len(parts) == 2 && parts[1] == "restore" {
+		switch r.Method {
+		case http.MethodPost:
+			h.restoreMember(w, r, memberId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle session endpoints: GET and DELETE /api/v1/members/:memberId/sessions
+	if len(parts) == 2 && parts[1] == "sessions" {
+		switch r.Method {
+		case http.MethodGet:
+			h.getMemberSessions(w, r, memberId)
+		case http.MethodDelete:
+			h.revokeMemberSessions(w, r, memberId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle security events endpoint: GET /api/v1/members/:memberId/security-events
+	if len(parts) == 2 && parts[1] == "security-events" {
+		switch r.Method {
+		case http.MethodGet:
+			h.getMemberSecurityEvents(w, r, memberId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+}
+
+// handleSchemas handles schema-related routes
+func (h *V1Handler) handleSchemas(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/schemas")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Handle collection endpoint: GET /api/v1/schemas and POST /api/v1/schemas
+	if len(parts) == 1 && parts[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			memberId := r.URL.Query().Get("memberId")
+			h.getAllSchemas(w, r, &memberId)
+		case http.MethodPost:
+			h.createSchema(w, r)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	if len(parts) < 1 || parts[0] == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Schema ID is required")
+		return
+	}
+	schemaId := parts[0]
+
+	// Handle specific schema endpoint: GET, PUT and DELETE /api/v1/schemas/:schemaId
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			h.getSchema(w, r, schemaId)
+		case http.MethodPut:
+			h.updateSchema(w, r, schemaId)
+		case http.MethodDelete:
+			h.deleteSchema(w, r, schemaId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle restore endpoint: POST /api/v1/schemas/:schemaId/restore
+	if len(parts) == 2 && parts[1] == "restore" {
+		switch r.Method {
+		case http.MethodPost:
+			h.restoreSchema(w, r, schemaId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle consumers endpoint: GET /api/v1/schemas/:schemaId/consumers
+	if len(parts) == 2 && parts[1] == "consumers" {
+		switch r.Method {
+		case http.MethodGet:
+			h.getSchemaConsumers(w, r, schemaId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+}
+
+// handleSchemaSubmissions handles schema submission-related routes
+func (h *V1Handler) handleSchemaSubmissions(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/schema-submissions")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Handle collection endpoint: GET /api/v1/schema-submissions and POST /api/v1/schema-submissions
+	if len(parts) == 1 && parts[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			status := r.URL.Query()["status"]
+			memberId := r.URL.Query().Get("memberId")
+			h.getAllSchemaSubmissions(w, r, &memberId, &status)
+		case http.MethodPost:
+			h.createSchemaSubmission(w, r, nil)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	if len(parts) < 1 || parts[0] == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Submission ID is required")
+		return
+	}
+	submissionId := parts[0]
+	// Handle specific schema submission endpoint: GET, PUT and DELETE /api/v1/schema-submissions/:submissionId
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			h.getSchemaSubmission(w, r, submissionId)
+		case http.MethodPut:
+			h.updateSchemaSubmission(w, r, submissionId)
+		case http.MethodDelete:
+			h.deleteSchemaSubmission(w, r, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle restore endpoint: POST /api/v1/schema-submissions/:submissionId/restore
+	if len(parts) == 2 && parts[1] == "restore" {
+		switch r.Method {
+		case http.MethodPost:
+			h.restoreSchemaSubmission(w, r, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle submit endpoint: POST /api/v1/schema-submissions/:submissionId/submit
+	if len(parts) == 2 && parts[1] == "submit" {
+		switch r.Method {
+		case http.MethodPost:
+			h.submitSubmission(w, r, models.SubmissionTypeSchema, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle diff endpoint: GET /api/v1/schema-submissions/:submissionId/diff
+	if len(parts) == 2 && parts[1] == "diff" {
+		switch r.Method {
+		case http.MethodGet:
+			h.getSchemaSubmissionDiff(w, r, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle endpoint check: POST /api/v1/schema-submissions/:submissionId/review/endpoint-check
+	if len(parts) == 3 && parts[1] == "review" && parts[2] == "endpoint-check" {
+		switch r.Method {
+		case http.MethodPost:
+			h.checkSchemaSubmissionEndpoint(w, r, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle attachment endpoints: /api/v1/schema-submissions/:submissionId/attachments/...
+	if parts[1] == "attachments" {
+		h.handleSchemaSubmissionAttachments(w, r, submissionId, parts[2:])
+		return
+	}
+
+	if h.handleSubmissionReview(w, r, models.SubmissionTypeSchema, submissionId, parts[1:]) {
+		return
+	}
+
+	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+}
+
+// attachmentFormMemory is how much of an attachment upload is held in memory before the multipart
+// form spills to temporary files, and attachmentFormOverhead is what the upload may add to the file
+// size for the multipart framing and the other form fields
+const (
+	attachmentFormMemory   = 1 << 20
+	attachmentFormOverhead = 64 << 10
+)
+
+// handleSchemaSubmissionAttachments handles the documents attached to a schema submission, which only
+// admins and the members owning the submission can reach:
+//   - GET and POST /api/v1/schema-submissions/:submissionId/attachments
+//   - DELETE /api/v1/schema-submissions/:submissionId/attachments/:attachmentId
+//   - GET /api/v1/schema-submissions/:submissionId/attachments/:attachmentId/download
+func (h *V1Handler) handleSchemaSubmissionAttachments(w http.ResponseWriter, r *http.Request, submissionId string, parts []string) {
+	permission := models.PermissionReadSchemaSubmission
+	if r.Method != http.MethodGet {
+		permission = models.PermissionUpdateSchemaSubmission
+	}
+	user, _, ok := h.authorizeSubmissionAccess(w, r, models.SubmissionTypeSchema, submissionId, permission)
+	if !ok {
+		return
+	}
+
+	switch {
+	case len(parts) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			attachments, err := h.attachmentService.ListAttachments(r.Context(), submissionId)
+			if err != nil {
+				respondWithAttachmentError(w, err)
+				return
+			}
+			utils.RespondWithSuccess(w, http.StatusOK, models.CollectionResponse{Items: attachments, Count: len(attachments)})
+		case http.MethodPost:
+			h.uploadSchemaSubmissionAttachment(w, r, user, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case len(parts) == 1:
+		if r.Method != http.MethodDelete {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if err := h.attachmentService.DeleteAttachment(r.Context(), submissionId, parts[0]); err != nil {
+			respondWithAttachmentError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "download":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		download, err := h.attachmentService.GetDownloadURL(r.Context(), submissionId, parts[0])
+		if err != nil {
+			respondWithAttachmentError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, download)
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	}
+}
+
+// uploadSchemaSubmissionAttachment stores the file of a multipart/form-data upload, with its file
+// and category fields, as an attachment of a schema submission
+func (h *V1Handler) uploadSchemaSubmissionAttachment(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser, submissionId string) {
+	if !h.attachmentService.Enabled() {
+		respondWithAttachmentError(w, services.ErrAttachmentsDisabled)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.attachmentService.MaxSize()+attachmentFormOverhead)
+	if err := r.ParseMultipartForm(attachmentFormMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithAttachmentError(w, services.ErrAttachmentTooLarge)
+			return
+		}
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorMalformedBody, "",
+			"Request body must be multipart/form-data"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorRequired, "file", "file is required"))
+		return
+	}
+	defer file.Close()
+
+	category := models.AttachmentCategory(r.FormValue("category"))
+	attachment, err := h.attachmentService.UploadAttachment(r.Context(), submissionId, user.IdpUserID, category, header.Filename, file)
+	if err != nil {
+		respondWithAttachmentError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusCreated, attachment)
+}
+
+// respondWithAttachmentError maps attachment failures to HTTP responses
+func respondWithAttachmentError(w http.ResponseWriter, err error) {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		respondWithBadRequest(w, err)
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Attachment not found")
+	case errors.Is(err, services.ErrAttachmentsDisabled):
+		utils.RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		utils.RespondWithError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, services.ErrAttachmentNotDownloadable):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// handleInternalApplications handles internal application-related routes
+func (h *V1Handler) handleInternalApplications(w http.ResponseWriter, r *http.Request) {
+	// The orchestration engine reads application quotas: GET /internal/api/v1/applications/:applicationId/quota
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/internal/api/v1/applications"), "/")
+	parts := strings.Split(path, "/")
+	if len(parts) == 2 && parts[0] != "" && parts[1] == "quota" {
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		quota, err := h.applicationService.GetApplicationQuota(r.Context(), parts[0])
+		if err != nil {
+			respondWithQuotaError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, quota)
+		return
+	}
+
+	// and the context of the application behind a consumer token:
+	// GET /internal/api/v1/applications/:applicationId/context?idpClientId=
+	if len(parts) == 2 && parts[0] != "" && parts[1] == "context" {
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		applicationContext, err := h.applicationService.GetApplicationContext(r.Context(), parts[0], r.URL.Query().Get("idpClientId"))
+		if err != nil {
+			respondWithApplicationStatusError(w, err)
+			return
+		}
+		utils.RespondWithSuccess(w, http.StatusOK, applicationContext)
+		return
+	}
+
+	// Otherwise the only internal operation is getApplicationId by IdpClientId
+	if path != "" {
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		h.getApplicationIdByClientId(w, r)
+	default:
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleApplications handles application-related routes
+func (h *V1Handler) handleApplications(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/applications")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Handle collection endpoint: GET /api/v1/applications and POST /api/v1/applications
+	if len(parts) == 1 && parts[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			memberId := r.URL.Query().Get("memberId")
+			h.getAllApplications(w, r, &memberId)
+		case http.MethodPost:
+			h.createApplication(w, r)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	if len(parts) < 1 || parts[0] == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Application ID is required")
+		return
+	}
+
+	applicationId := parts[0]
+	// Handle specific application endpoint: GET, PUT and DELETE /api/v1/applications/:applicationId
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			h.getApplication(w, r, applicationId)
+		case http.MethodPut:
+			h.updateApplication(w, r, applicationId)
+		case http.MethodDelete:
+			h.deleteApplication(w, r, applicationId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle restore endpoint: POST /api/v1/applications/:applicationId/restore
+	if len(parts) == 2 && parts[1] == "restore" {
+		switch r.Method {
+		case http.MethodPost:
+			h.restoreApplication(w, r, applicationId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle renewal endpoint: POST /api/v1/applications/:applicationId/renew
+	if len(parts) == 2 && parts[1] == "renew" {
+		if r.Method != http.MethodPost {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.renewApplication(w, r, applicationId)
+		return
+	}
+
+	// Handle quota endpoint: GET and PUT /api/v1/applications/:applicationId/quota
+	if len(parts) == 2 && parts[1] == "quota" {
+		switch r.Method {
+		case http.MethodGet:
+			h.getApplicationQuota(w, r, applicationId)
+		case http.MethodPut:
+			h.updateApplicationQuota(w, r, applicationId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	// Handle status endpoint: PUT /api/v1/applications/:applicationId/status
+	if len(parts) == 2 && parts[1] == "status" {
+		if r.Method != http.MethodPut {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.updateApplicationStatus(w, r, applicationId)
+		return
+	}
+
+	// Handle usage endpoint: GET /api/v1/applications/:applicationId/usage
+	if len(parts) == 2 && parts[1] == "usage" {
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.getApplicationUsage(w, r, applicationId)
+		return
+	}
+
+	// Handle credential endpoints: /api/v1/applications/:applicationId/credentials[/:credentialId[/rotate]]
+	if parts[1] == "credentials" {
+		switch {
+		case len(parts) == 2 && r.Method == http.MethodGet:
+			h.getApplicationCredentials(w, r, applicationId)
+		case len(parts) == 2 && r.Method == http.MethodPost:
+			h.generateApplicationCredential(w, r, applicationId)
+		case len(parts) == 3 && r.Method == http.MethodDelete:
+			h.revokeApplicationCredential(w, r, applicationId, parts[2])
+		case len(parts) == 4 && parts[3] == "rotate" && r.Method == http.MethodPost:
+			h.rotateApplicationCredential(w, r, applicationId, parts[2])
+		case len(parts) <= 3 || (len(parts) == 4 && parts[3] == "rotate"):
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		default:
+			utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+		}
+		return
+	}
+
+	// Handle sandbox endpoints: /api/v1/applications/:applicationId/sandbox[/secret]
+	if parts[1] == "sandbox" {
+		switch {
+		case len(parts) == 2 && r.Method == http.MethodGet:
+			h.getApplicationSandbox(w, r, applicationId)
+		case len(parts) == 2 && r.Method == http.MethodPost:
+			h.provisionApplicationSandbox(w, r, applicationId)
+		case len(parts) == 3 && parts[2] == "secret" && r.Method == http.MethodPost:
+			h.regenerateSandboxSecret(w, r, applicationId)
+		case len(parts) == 2 || (len(parts) == 3 && parts[2] == "secret"):
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		default:
+			utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+		}
+		return
+	}
+
+	// Handle webhook endpoints: /api/v1/applications/:applicationId/webhooks[/:webhookId[/deliveries]]
+	if parts[1] == "webhooks" {
+		switch {
+		case len(parts) == 2 && r.Method == http.MethodGet:
+			h.getApplicationWebhooks(w, r, applicationId)
+		case len(parts) == 2 && r.Method == http.MethodPost:
+			h.createApplicationWebhook(w, r, applicationId)
+		case len(parts) == 3 && r.Method == http.MethodDelete:
+			h.deleteApplicationWebhook(w, r, applicationId, parts[2])
+		case len(parts) == 4 && parts[3] == "deliveries" && r.Method == http.MethodGet:
+			h.getApplicationWebhookDeliveries(w, r, applicationId, parts[2])
+		case len(parts) <= 3 || (len(parts) == 4 && parts[3] == "deliveries"):
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		default:
+			utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+		}
+		return
+	}
+
+	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+}
+
+// handleApplicationSubmissions handles application submission-related routes
+func (h *V1Handler) handleApplicationSubmissions(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/application-submissions")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Handle collection endpoint: GET /api/v1/application-submissions and POST /api/v1/application-submissions
+	if len(parts) == 1 && parts[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			status := r.URL.Query()["status"]
+			memberId := r.URL.Query().Get("memberId")
+			h.getAllApplicationSubmissions(w, r, &memberId, &status)
+		case http.MethodPost:
+			h.createApplicationSubmission(w, r, nil)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	if len(parts) < 1 || parts[0] == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Submission ID is required")
+		return
+	}
+
+	submissionId := parts[0]
+	// Handle specific application submission endpoint: GET, PUT and DELETE /api/v1/application-submissions/:submissionId
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			h.getApplicationSubmission(w, r, submissionId)
+		case http.MethodPut:
+			h.updateApplicationSubmission(w, r, submissionId)
+		case http.MethodDelete:
+			h.deleteApplicationSubmission(w, r, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	// Handle restore endpoint: POST /api/v1/application-submissions/:submissionId/restore
+	if len(parts) == 2 && parts[1] == "restore" {
+		switch r.Method {
+		case http.MethodPost:
+			h.restoreApplicationSubmission(w, r, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	// Handle submit endpoint: POST /api/v1/application-submissions/:submissionId/submit
+	if len(parts) == 2 && parts[1] == "submit" {
+		switch r.Method {
+		case http.MethodPost:
+			h.submitSubmission(w, r, models.SubmissionTypeApplication, submissionId)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	if h.handleSubmissionReview(w, r, models.SubmissionTypeApplication, submissionId, parts[1:]) {
+		return
+	}
+
+	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+}
+
+// Member handlers
+func (h *V1Handler) createMember(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission - only admin users can create members
+	if !user.HasPermission(models.PermissionCreateMember) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	var req models.CreateMemberRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	// Only admin users should reach this point due to permission check above
+	// Admin users can create members for any user if IdpUserID is provided in the request
+
+	member, err := h.memberService.CreateMember(r.Context(), &req)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	// An admin onboarding a member approves their membership
+	if err := h.notificationService.NotifyMembershipApproved(r.Context(), member.MemberID); err != nil {
+		slog.Error("Failed to notify member of membership approval", "memberID", member.MemberID, "error", err)
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, member)
+}
+
+func (h *V1Handler) updateMember(w http.ResponseWriter, r *http.Request, memberId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Get the existing member to check ownership
+	existingMember, err := h.memberService.GetMember(r.Context(), memberId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Check if user can update this member resource
+	// Admin can update any member, regular members can only update their own
+	if !user.IsAdmin() && existingMember.IdpUserID != user.IdpUserID {
+		utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
+		return
+	}
+
+	var req models.UpdateMemberRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if !requireRevision(w, r, &req.Revision) {
+		return
+	}
+
+	// Pass request context to service for proper context propagation
+	member, err := h.memberService.UpdateMember(r.Context(), memberId, &req)
+	if err != nil {
+		if respondWithRevisionConflict(w, err) {
+			return
+		}
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	setETag(w, member.Revision)
+	utils.RespondWithSuccess(w, http.StatusOK, member)
+}
+
+func (h *V1Handler) getMember(w http.ResponseWriter, r *http.Request, memberId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Get the member from database
+	// Pass request context to service for proper context propagation
+	member, err := h.memberService.GetMember(r.Context(), memberId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Check if user can access this member resource
+	// Admin can access any member, regular members can only access their own
+	if !user.IsAdmin() && member.IdpUserID != user.IdpUserID {
+		utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+		return
+	}
+
+	setETag(w, member.Revision)
+	utils.RespondWithSuccess(w, http.StatusOK, member)
+}
+
+func (h *V1Handler) getAllMembers(w http.ResponseWriter, r *http.Request, idpUserId *string, email *string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission - admin can read all members, regular users need specific permission
+	var filteredIdpUserId, filteredEmail *string
+
+	if user.HasPermission(models.PermissionReadAllMembers) {
+		// Admin can use provided filters or see all
+		filteredIdpUserId = idpUserId
+		filteredEmail = email
+	} else if user.HasPermission(models.PermissionReadMember) {
+		// Regular users can only see their own member record
+		// IdpUserID is unique, so no need to also filter by email
+		filteredIdpUserId = &user.IdpUserID
+	} else {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	q, err := parseListQuery(r)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+	q.IdpUserID = filteredIdpUserId
+	q.Email = filteredEmail
+
+	// Pass request context to service for proper context propagation
+	members, pagination, err := h.memberService.ListMembers(r.Context(), q)
+	if err != nil {
+		respondWithListError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items:      members,
+		Count:      len(members),
+		Pagination: pagination,
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+// Schema handlers
+func (h *V1Handler) getAllSchemaSubmissions(w http.ResponseWriter, r *http.Request, memberId *string, statusFilter *[]string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	var filteredMemberId *string
+	var callerOrganizationID *string
+	if user.HasPermission(models.PermissionReadAllSchemaSubmissions) {
+		// Admin/System can use provided filters or see all
+		filteredMemberId = memberId
+	} else if user.HasPermission(models.PermissionReadSchemaSubmission) {
+		// Regular users can only see their own submissions
+		// Get member ID for the authenticated user (cached)
+		userMemberId, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+		filteredMemberId = &userMemberId
+		callerOrganizationID, err = h.getUserOrganizationID(r, userMemberId)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+	} else {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	q, err := parseListQuery(r)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+	q.MemberID = filteredMemberId
+	q.OrganizationID = callerOrganizationID
+	q.Status = *statusFilter
+
+	submissions, pagination, err := h.schemaService.GetSchemaSubmissions(q)
+	if err != nil {
+		respondWithListError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items:      submissions,
+		Count:      len(submissions),
+		Pagination: pagination,
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) getSchemaSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadSchemaSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	submission, err := h.schemaService.GetSchemaSubmission(submissionId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if submission belongs to the user
+		if !h.callerOwns(r, userMemberID, submission.MemberID, submission.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return
+		}
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, submission)
+}
+
+// respondWithSchemaSubmissionError maps schema submission write failures to HTTP responses. SDL
+// validation failures carry their lint errors so members can fix the schema before resubmitting.
+func respondWithSchemaSubmissionError(w http.ResponseWriter, err error) {
+	var validationErr *services.SDLValidationError
+	if errors.As(err, &validationErr) {
+		utils.RespondWithJSON(w, http.StatusUnprocessableEntity, models.SDLValidationErrorResponse{
+			Error:      services.ErrInvalidSDL.Error(),
+			LintErrors: validationErr.Errors,
+			RequestID:  requestid.FromResponse(w),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrSubmissionReviewRequired) || errors.Is(err, services.ErrDraftSubmitRequired) ||
+		errors.Is(err, services.ErrSubmissionNotDraft) {
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrResourceNotFound) {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondWithBadRequest(w, err)
+}
+
+func (h *V1Handler) getSchemaSubmissionDiff(w http.ResponseWriter, r *http.Request, submissionId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadSchemaSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		submission, err := h.schemaService.GetSchemaSubmission(submissionId)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if submission belongs to the user
+		if !h.callerOwns(r, userMemberID, submission.MemberID, submission.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return
+		}
+	}
+
+	diff, err := h.schemaService.GetSchemaSubmissionDiff(submissionId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrResourceNotFound), errors.Is(err, services.ErrNoPreviousSchema):
+			utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrInvalidSDL):
+			utils.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, diff)
+}
+
+func (h *V1Handler) createSchemaSubmission(w http.ResponseWriter, r *http.Request, memberId *string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionCreateSchemaSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	var req models.CreateSchemaSubmissionRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	// If memberId is provided from URL parameter, use it
+	if memberId != nil {
+		req.MemberID = *memberId
+	}
+
+	// For non-admin users, ensure they can only create submissions for themselves
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// If MemberID is provided, validate ownership
+		if req.MemberID != "" {
+			// Check if the provided MemberID belongs to the authenticated user
+			if req.MemberID != userMemberID {
+				utils.RespondWithError(w, http.StatusForbidden, "Access denied: cannot create submission for another user")
+				return
+			}
+		} else {
+			// If no MemberID provided, set it to the authenticated user's member ID
+			req.MemberID = userMemberID
+		}
+	}
+
+	submission, err := h.schemaService.CreateSchemaSubmission(&req)
+	if err != nil {
+		respondWithSchemaSubmissionError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, submission)
+}
+
+func (h *V1Handler) updateSchemaSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionUpdateSchemaSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	// Get existing submission to check ownership
+	existingSubmission, err := h.schemaService.GetSchemaSubmission(submissionId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if submission belongs to the user
+		if !h.callerOwns(r, userMemberID, existingSubmission.MemberID, existingSubmission.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
+			return
+		}
+	}
+
+	var req models.UpdateSchemaSubmissionRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	submission, err := h.schemaService.UpdateSchemaSubmission(submissionId, &req)
+	if err != nil {
+		respondWithSchemaSubmissionError(w, err)
+		return
+	}
+	h.notifySubmissionStatusChanged(r, models.SubmissionTypeSchema, submissionId, existingSubmission.Status, submission.Status)
+
+	utils.RespondWithSuccess(w, http.StatusOK, submission)
+}
+
+func (h *V1Handler) getAllSchemas(w http.ResponseWriter, r *http.Request, memberId *string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadSchema) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	// For non-admin users, filter results to only their own schemas
+	var filteredMemberId *string
+	var callerOrganizationID *string
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberId, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+		filteredMemberId = &userMemberId
+		callerOrganizationID, err = h.getUserOrganizationID(r, userMemberId)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+	} else {
+		// Admin can specify memberId or see all
+		filteredMemberId = memberId
+	}
+
+	q, err := parseListQuery(r)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+	q.MemberID = filteredMemberId
+	q.OrganizationID = callerOrganizationID
+
+	schemas, pagination, err := h.schemaService.GetSchemas(q)
+	if err != nil {
+		respondWithListError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items:      schemas,
+		Count:      len(schemas),
+		Pagination: pagination,
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) getSchema(w http.ResponseWriter, r *http.Request, schemaId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadSchema) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	schema, err := h.schemaService.GetSchema(schemaId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if schema belongs to the user
+		if !h.callerOwns(r, userMemberID, schema.MemberID, schema.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return
+		}
+	}
+
+	setETag(w, schema.Revision)
+	utils.RespondWithSuccess(w, http.StatusOK, schema)
+}
+
+// getSchemaConsumers lists the applications that selected fields of a schema, so that its provider
+// can see who depends on it before making breaking changes
+func (h *V1Handler) getSchemaConsumers(w http.ResponseWriter, r *http.Request, schemaId string) {
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if !user.HasPermission(models.PermissionReadSchema) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	schema, err := h.schemaService.GetSchema(schemaId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+		if !h.callerOwns(r, userMemberID, schema.MemberID, schema.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return
+		}
+	}
+
+	consumers, err := h.schemaService.GetSchemaConsumers(r.Context(), schemaId)
+	if err != nil {
+		if errors.Is(err, services.ErrPolicyServiceUnavailable) {
+			slog.Error("Failed to read PDP grants", "schemaId", schemaId, "error", err)
+			utils.RespondWithError(w, http.StatusBadGateway, "The PDP is unavailable")
+			return
+		}
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items: consumers,
+		Count: len(consumers),
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) createSchema(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionCreateSchema) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	var req models.CreateSchemaRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	// For non-admin users, ensure they can only create schemas for themselves
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Set the member ID to the authenticated user's member ID
+		req.MemberID = userMemberID
+	}
+
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	schema, err := h.schemaService.CreateSchema(&req)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, schema)
+}
+
+func (h *V1Handler) updateSchema(w http.ResponseWriter, r *http.Request, schemaId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionUpdateSchema) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	// Get existing schema to check ownership
+	existingSchema, err := h.schemaService.GetSchema(schemaId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if schema belongs to the user
+		if !h.callerOwns(r, userMemberID, existingSchema.MemberID, existingSchema.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
+			return
+		}
+	}
+
+	var req models.UpdateSchemaRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if !requireRevision(w, r, &req.Revision) {
+		return
+	}
+
+	schema, err := h.schemaService.UpdateSchema(schemaId, &req)
+	if err != nil {
+		if respondWithRevisionConflict(w, err) {
+			return
+		}
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	// Owners of applications that selected fields of a schema being phased out are notified, and
+	// the applications are told through their webhooks
+	if existingSchema.Version != schema.Version && models.Version(schema.Version).IsPhasedOut() {
+		if _, err := h.notificationService.NotifySchemaDeprecated(r.Context(), schemaId); err != nil {
+			slog.Error("Failed to notify consumers of schema deprecation", "schemaID", schemaId, "error", err)
+		}
+		if err := h.webhookService.EnqueueSchemaDeprecated(r.Context(), schemaId); err != nil {
+			slog.Error("Failed to queue schema deprecation webhooks", "schemaID", schemaId, "error", err)
+		}
+	}
+
+	setETag(w, schema.Revision)
+	utils.RespondWithSuccess(w, http.StatusOK, schema)
+}
+
+// Application handlers
+func (h *V1Handler) getAllApplicationSubmissions(w http.ResponseWriter, r *http.Request, memberId *string, statusFilter *[]string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadApplicationSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	var finalMemberId *string = memberId
+	var callerOrganizationID *string
+
+	// For non-admin users, force filtering to their own submissions only
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Force the memberId to the authenticated user's member ID
+		finalMemberId = &userMemberID
+		callerOrganizationID, err = h.getUserOrganizationID(r, userMemberID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+	}
+
+	q, err := parseListQuery(r)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+	q.MemberID = finalMemberId
+	q.OrganizationID = callerOrganizationID
+	q.Status = *statusFilter
+
+	submissions, pagination, err := h.applicationService.GetApplicationSubmissions(r.Context(), q)
+	if err != nil {
+		respondWithListError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items:      submissions,
+		Count:      len(submissions),
+		Pagination: pagination,
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) getApplicationSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadApplicationSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	submission, err := h.applicationService.GetApplicationSubmission(r.Context(), submissionId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if submission belongs to the user
+		if !h.callerOwns(r, userMemberID, submission.MemberID, submission.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return
+		}
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, submission)
+}
+
+func (h *V1Handler) createApplicationSubmission(w http.ResponseWriter, r *http.Request, memberId *string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionCreateApplicationSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	var req models.CreateApplicationSubmissionRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	// If memberId is provided from URL parameter, use it
+	if memberId != nil {
+		req.MemberID = *memberId
+	}
+
+	// For non-admin users, ensure they can only create submissions for themselves
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// If MemberID is provided, validate ownership
+		if req.MemberID != "" {
+			// Check if the provided MemberID belongs to the authenticated user
+			if req.MemberID != userMemberID {
+				utils.RespondWithError(w, http.StatusForbidden, "Access denied: cannot create submission for another user")
+				return
+			}
+		} else {
+			// If no MemberID provided, set it to the authenticated user's member ID
+			req.MemberID = userMemberID
+		}
+	}
+
+	submission, err := h.applicationService.CreateApplicationSubmission(r.Context(), &req)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, submission)
+}
+
+func (h *V1Handler) updateApplicationSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionUpdateApplicationSubmission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	// Get existing submission to check ownership
+	existingSubmission, err := h.applicationService.GetApplicationSubmission(r.Context(), submissionId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, "Application submission not found")
+		return
+	}
+
+	// For non-admin users, check ownership before updating
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if submission belongs to the user
+		if !h.callerOwns(r, userMemberID, existingSubmission.MemberID, existingSubmission.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return
+		}
+	}
+
+	var req models.UpdateApplicationSubmissionRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	submission, err := h.applicationService.UpdateApplicationSubmission(r.Context(), submissionId, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrSubmissionReviewRequired) || errors.Is(err, services.ErrDraftSubmitRequired) {
+			utils.RespondWithError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondWithBadRequest(w, err)
+		return
+	}
+	h.notifySubmissionStatusChanged(r, models.SubmissionTypeApplication, submissionId, existingSubmission.Status, submission.Status)
+
+	utils.RespondWithSuccess(w, http.StatusOK, submission)
+}
+
+func (h *V1Handler) getAllApplications(w http.ResponseWriter, r *http.Request, memberId *string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	var filteredMemberId *string
+	var callerOrganizationID *string
+	if user.HasPermission(models.PermissionReadAllApplications) {
+		// Admin/System can use provided filters or see all
+		filteredMemberId = memberId
+	} else if user.HasPermission(models.PermissionReadApplication) {
+		// Regular users can only see their own applications
+		// Get member ID for the authenticated user (cached)
+		userMemberId, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+		filteredMemberId = &userMemberId
+		callerOrganizationID, err = h.getUserOrganizationID(r, userMemberId)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+	} else {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	q, err := parseListQuery(r)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+	q.MemberID = filteredMemberId
+	q.OrganizationID = callerOrganizationID
+
+	applications, pagination, err := h.applicationService.GetApplications(r.Context(), q)
+	if err != nil {
+		respondWithListError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items:      applications,
+		Count:      len(applications),
+		Pagination: pagination,
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) getApplication(w http.ResponseWriter, r *http.Request, applicationId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadApplication) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	application, err := h.applicationService.GetApplication(r.Context(), applicationId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if application belongs to the user
+		if !h.callerOwns(r, userMemberID, application.MemberID, application.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return
+		}
+	}
+
+	setETag(w, application.Revision)
+	utils.RespondWithSuccess(w, http.StatusOK, application)
+}
+
+func (h *V1Handler) getApplicationIdByClientId(w http.ResponseWriter, r *http.Request) {
+	idpClientId := r.URL.Query().Get("idpClientId")
+	if idpClientId == "" {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorRequired, "idpClientId", "idpClientId query parameter is required"))
+		return
+	}
+
+	applicationId, err := h.applicationService.GetApplicationIdByIdpClientId(r.Context(), idpClientId)
+	if err != nil {
+		// The client may be the sandbox client of an application instead
+		if sandbox, sandboxErr := h.resolveSandboxClient(r, idpClientId); sandbox != nil {
+			utils.RespondWithSuccess(w, http.StatusOK, sandbox)
+			return
+		} else if sandboxErr != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, sandboxErr.Error())
+			return
+		}
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, applicationId)
+}
+
+// resolveSandboxClient returns the application a sandbox client belongs to, or nil when sandbox mode
+// is off or idpClientId is not a sandbox client
+func (h *V1Handler) resolveSandboxClient(r *http.Request, idpClientId string) (*models.ApplicationIDResponse, error) {
+	if h.sandboxService == nil {
+		return nil, nil
+	}
+	applicationId, providerEndpoints, err := h.sandboxService.ResolveClient(r.Context(), idpClientId)
+	if err != nil {
+		if errors.Is(err, services.ErrResourceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &models.ApplicationIDResponse{
+		ApplicationID:     applicationId,
+		Environment:       models.EnvironmentSandbox,
+		ProviderEndpoints: providerEndpoints,
+	}, nil
+}
+
+func (h *V1Handler) createApplication(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionCreateApplication) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	var req models.CreateApplicationRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	// For non-admin users, ensure they can only create applications for themselves
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Set the member ID to the authenticated user's member ID
+		req.MemberID = userMemberID
+	}
+
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	application, err := h.applicationService.CreateApplication(r.Context(), &req)
+	if err != nil {
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, application)
+}
+
+func (h *V1Handler) updateApplication(w http.ResponseWriter, r *http.Request, applicationId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionUpdateApplication) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	// Get existing application to check ownership
+	existingApplication, err := h.applicationService.GetApplication(r.Context(), applicationId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		// Get member ID for the authenticated user (cached)
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+
+		// Check if application belongs to the user
+		if !h.callerOwns(r, userMemberID, existingApplication.MemberID, existingApplication.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
+			return
+		}
+	}
+
+	var req models.UpdateApplicationRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if !requireRevision(w, r, &req.Revision) {
+		return
+	}
+
+	application, err := h.applicationService.UpdateApplication(r.Context(), applicationId, &req)
+	if err != nil {
+		if respondWithRevisionConflict(w, err) {
+			return
+		}
+		respondWithBadRequest(w, err)
+		return
+	}
+
+	setETag(w, application.Revision)
+	utils.RespondWithSuccess(w, http.StatusOK, application)
+}
+
+func (h *V1Handler) getAllPDPJobs(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadPDPJobs) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && !models.PDPJobStatus(status).IsValid() {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "status", "status must be pending, completed or dead"))
+		return
+	}
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "limit", "limit must be a positive integer"))
+			return
+		}
+	}
+
+	jobs, err := h.pdpJobService.ListJobs(status, limit)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items: jobs,
+		Count: len(jobs),
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) requeuePDPJob(w http.ResponseWriter, r *http.Request, jobId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionRequeuePDPJob) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	job, err := h.pdpJobService.RequeueJob(jobId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPDPJobNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrPDPJobNotDead):
+			utils.RespondWithError(w, http.StatusConflict, err.Error())
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, job)
+}
+
+// getSubmissionMetrics counts the submissions by status and the review backlog by age
+func (h *V1Handler) getSubmissionMetrics(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadSubmissionMetrics) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	metrics, err := h.metricsService.GetSubmissionMetrics(r.Context())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, metrics)
+}
+
+// getReviewMetrics reports review times and approval rates over the startTime/endTime window
+func (h *V1Handler) getReviewMetrics(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadSubmissionMetrics) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	query := r.URL.Query()
+	metrics, err := h.metricsService.GetReviewMetrics(r.Context(), query.Get("startTime"), query.Get("endTime"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMetricsWindow) {
+			respondWithBadRequest(w, err)
+			return
+		}
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, metrics)
+}
+
+func (h *V1Handler) getPDPSyncStatus(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionReadPDPSync) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	status, err := h.pdpSyncService.Status(r.Context())
+	if err != nil {
+		respondWithPDPSyncError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, status)
+}
+
+func (h *V1Handler) repairPDPSync(w http.ResponseWriter, r *http.Request) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(models.PermissionRepairPDPSync) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	// An empty body repairs every drifted application
+	var req models.PDPSyncRepairRequest
+	if r.ContentLength != 0 && !decodeRequestBody(w, r, &req) {
+		return
+	}
+
+	response, err := h.pdpSyncService.Repair(r.Context(), &req)
+	if err != nil {
+		respondWithPDPSyncError(w, err)
+		return
+	}
+
+	slog.Info("PDP grants repaired", "repairedBy", user.IdpUserID, "succeeded", response.Succeeded, "failed", response.Failed)
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+// respondWithPDPSyncError maps PDP reconciliation failures to HTTP responses
+func respondWithPDPSyncError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrPolicyServiceUnavailable) {
+		slog.Error("Failed to read PDP grants", "error", err)
+		utils.RespondWithError(w, http.StatusBadGateway, "The PDP is unavailable")
+		return
+	}
+	utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+}
+
+func (h *V1Handler) executeBulkOperation(w http.ResponseWriter, r *http.Request, operation string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission - only admin users can run bulk operations
+	if !user.HasPermission(models.PermissionExecuteBulkOperation) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	var response *models.BulkOperationResponse
+	switch operation {
+	case services.BulkOperationReviewSubmissions:
+		var req models.BulkReviewSubmissionsRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		response, err = h.bulkService.ReviewSubmissions(r.Context(), user.IdpUserID, &req)
+		if err == nil {
+			for _, result := range response.Results {
+				if result.Success {
+					submissionType := models.SubmissionTypeSchema
+					if result.Type == models.BulkResourceApplicationSubmission {
+						submissionType = models.SubmissionTypeApplication
+					}
+					h.notifySubmissionStatusChanged(r, submissionType, result.ID, result.PreviousStatus, result.Status)
+				}
+			}
+		}
+	case services.BulkOperationExpireApplications:
+		var req models.BulkExpireApplicationsRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		response, err = h.bulkService.ExpireApplications(r.Context(), &req)
+	case services.BulkOperationReassign:
+		var req models.BulkReassignRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		response, err = h.bulkService.ReassignResources(r.Context(), &req)
+	case services.BulkOperationImportMembers:
+		var req models.BulkImportMembersRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		response, err = h.memberService.ImportMembers(r.Context(), &req)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidBulkRequest):
+			respondWithBadRequest(w, err)
+		case errors.Is(err, services.ErrResourceNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+// deleteResource checks the caller holds permission and soft-deletes a resource on their behalf
+func (h *V1Handler) deleteResource(w http.ResponseWriter, r *http.Request, permission models.Permission, deleteFn func(deletedBy string) error) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(permission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	if err := deleteFn(user.IdpUserID); err != nil {
+		respondWithSoftDeleteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreResource checks the caller holds permission and restores a soft-deleted resource
+func (h *V1Handler) restoreResource(w http.ResponseWriter, r *http.Request, permission models.Permission, restoreFn func() (interface{}, error)) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Check permission
+	if !user.HasPermission(permission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	resource, err := restoreFn()
+	if err != nil {
+		respondWithSoftDeleteError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, resource)
+}
+
+// respondWithSoftDeleteError maps delete and restore failures to HTTP responses
+func respondWithSoftDeleteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrResourceNotDeleted), errors.Is(err, services.ErrOwnerDeleted):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *V1Handler) deleteMember(w http.ResponseWriter, r *http.Request, memberId string) {
+	if user, err := middleware.GetUserFromRequest(r); err == nil && user.HasPermission(models.PermissionDeleteMember) &&
+		h.requiresApproval(user, models.ApprovalActionDeleteMember) {
+		h.requestApproval(w, r, user, models.ApprovalActionDeleteMember, memberId, models.ApprovalPayload{})
+		return
+	}
+	h.deleteResource(w, r, models.PermissionDeleteMember, func(deletedBy string) error {
+		return h.memberService.DeleteMember(r.Context(), memberId, deletedBy)
+	})
+}
+
+func (h *V1Handler) restoreMember(w http.ResponseWriter, r *http.Request, memberId string) {
+	h.restoreResource(w, r, models.PermissionRestoreMember, func() (interface{}, error) {
+		return h.memberService.RestoreMember(r.Context(), memberId)
+	})
+}
+
+// authorizeMemberAccess checks that the caller has permission and is either an admin or the member
+// itself. Returns false if the request was denied and a response was sent.
+func (h *V1Handler) authorizeMemberAccess(w http.ResponseWriter, r *http.Request, permission models.Permission, memberId string) (*models.AuthenticatedUser, bool) {
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return nil, false
+	}
+	if !user.HasPermission(permission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return nil, false
+	}
+
+	// Admin can access any member, regular members can only access their own
+	if !user.IsAdmin() {
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return nil, false
+		}
+		if userMemberID != memberId {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return nil, false
+		}
+	}
+	return user, true
+}
+
+// respondWithSessionError maps member session failures to HTTP responses
+func respondWithSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Member not found")
+	case errors.Is(err, idp.ErrSessionsNotSupported):
+		utils.RespondWithError(w, http.StatusNotImplemented, "The identity provider does not expose sessions")
+	default:
+		slog.Error("Failed to manage member sessions", "error", err)
+		utils.RespondWithError(w, http.StatusBadGateway, "The identity provider is unavailable")
+	}
+}
+
+// getMemberSessions lists the active sign-in sessions of a member at the identity provider
+func (h *V1Handler) getMemberSessions(w http.ResponseWriter, r *http.Request, memberId string) {
+	if _, ok := h.authorizeMemberAccess(w, r, models.PermissionReadMemberSessions, memberId); !ok {
+		return
+	}
+
+	sessions, err := h.memberService.GetMemberSessions(r.Context(), memberId)
+	if err != nil {
+		respondWithSessionError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, sessions)
+}
+
+// revokeMemberSessions signs a member out of every session at the identity provider, e.g. after a
+// suspected account compromise, and logs it as a security event
+func (h *V1Handler) revokeMemberSessions(w http.ResponseWriter, r *http.Request, memberId string) {
+	if _, ok := h.authorizeMemberAccess(w, r, models.PermissionRevokeMemberSessions, memberId); !ok {
+		return
+	}
+
+	revoked, err := h.memberService.RevokeMemberSessions(r.Context(), memberId)
+	if err != nil {
+		respondWithSessionError(w, err)
+		return
+	}
+	middleware.LogSecurityEvent(r, models.SecurityActionSessionsRevoked, models.AuditStatusSuccess, memberId, map[string]interface{}{
+		"revokedSessions": revoked.RevokedSessions,
+	})
+
+	utils.RespondWithSuccess(w, http.StatusOK, revoked)
+}
+
+// getMemberSecurityEvents lists a member's sign-ins, token issuance, credential changes and denied
+// requests from the audit service
+func (h *V1Handler) getMemberSecurityEvents(w http.ResponseWriter, r *http.Request, memberId string) {
+	if _, ok := h.authorizeMemberAccess(w, r, models.PermissionReadSecurityEvents, memberId); !ok {
+		return
+	}
+
+	page, err := h.auditEventService.ListSecurityEvents(r.Context(), memberId, r.URL.Query())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrResourceNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, "Member not found")
+		case errors.Is(err, services.ErrInvalidAuditEventQuery):
+			respondWithBadRequest(w, err)
+		case errors.Is(err, services.ErrAuditServiceUnavailable):
+			slog.Error("Failed to list security events", "error", err)
+			utils.RespondWithError(w, http.StatusBadGateway, "Security events are unavailable")
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	utils.RespondWithSuccess(w, http.StatusOK, page)
+}
+
+func (h *V1Handler) deleteSchema(w http.ResponseWriter, r *http.Request, schemaId string) {
+	h.deleteResource(w, r, models.PermissionDeleteSchema, func(deletedBy string) error {
+		return h.schemaService.DeleteSchema(schemaId, deletedBy)
+	})
+}
+
+func (h *V1Handler) restoreSchema(w http.ResponseWriter, r *http.Request, schemaId string) {
+	h.restoreResource(w, r, models.PermissionRestoreSchema, func() (interface{}, error) {
+		return h.schemaService.RestoreSchema(schemaId)
+	})
+}
+
+func (h *V1Handler) deleteSchemaSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	h.deleteResource(w, r, models.PermissionDeleteSchemaSubmission, func(deletedBy string) error {
+		return h.schemaService.DeleteSchemaSubmission(submissionId, deletedBy)
+	})
+}
+
+func (h *V1Handler) restoreSchemaSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	h.restoreResource(w, r, models.PermissionRestoreSchemaSubmission, func() (interface{}, error) {
+		return h.schemaService.RestoreSchemaSubmission(submissionId)
+	})
+}
+
+func (h *V1Handler) deleteApplication(w http.ResponseWriter, r *http.Request, applicationId string) {
+	h.deleteResource(w, r, models.PermissionDeleteApplication, func(deletedBy string) error {
+		return h.applicationService.DeleteApplication(r.Context(), applicationId, deletedBy)
+	})
+}
+
+func (h *V1Handler) restoreApplication(w http.ResponseWriter, r *http.Request, applicationId string) {
+	h.restoreResource(w, r, models.PermissionRestoreApplication, func() (interface{}, error) {
+		return h.applicationService.RestoreApplication(r.Context(), applicationId)
+	})
+}
+
+func (h *V1Handler) deleteApplicationSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	h.deleteResource(w, r, models.PermissionDeleteApplicationSubmission, func(deletedBy string) error {
+		return h.applicationService.DeleteApplicationSubmission(r.Context(), submissionId, deletedBy)
+	})
+}
+
+func (h *V1Handler) restoreApplicationSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+	h.restoreResource(w, r, models.PermissionRestoreApplicationSubmission, func() (interface{}, error) {
+		return h.applicationService.RestoreApplicationSubmission(r.Context(), submissionId)
+	})
+}
+
+// authorizeApplicationAccess checks the caller holds permission and, unless they are an admin,
+// owns the application. It returns the caller on success and writes the error response otherwise.
+func (h *V1Handler) authorizeApplicationAccess(w http.ResponseWriter, r *http.Request, permission models.Permission, applicationId string) (*models.AuthenticatedUser, bool) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return nil, false
+	}
+
+	// Check permission
+	if !user.HasPermission(permission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return nil, false
+	}
+
+	application, err := h.applicationService.GetApplication(r.Context(), applicationId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, "Application not found")
+		return nil, false
+	}
+
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return nil, false
+		}
+		if !h.callerOwns(r, userMemberID, application.MemberID, application.OrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return nil, false
+		}
+	}
+
+	return user, true
+}
+
+// getApplicationUsage reports the exchange usage of an application from the audit service
+func (h *V1Handler) getApplicationUsage(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionReadApplication, applicationId); !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	usage, err := h.usageService.GetApplicationUsage(r.Context(), applicationId, query.Get("startTime"), query.Get("endTime"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidUsageWindow):
+			respondWithBadRequest(w, err)
+		case errors.Is(err, services.ErrResourceNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, "Application not found")
+		case errors.Is(err, services.ErrAuditServiceUnavailable):
+			slog.Error("Failed to get application usage", "applicationId", applicationId, "error", err)
+			utils.RespondWithError(w, http.StatusBadGateway, "Usage data is unavailable")
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, usage)
+}
+
+// getApplicationQuota returns the data usage quota of an application
+func (h *V1Handler) getApplicationQuota(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionReadApplication, applicationId); !ok {
+		return
+	}
+
+	quota, err := h.applicationService.GetApplicationQuota(r.Context(), applicationId)
+	if err != nil {
+		respondWithQuotaError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, quota)
+}
+
+// updateApplicationQuota replaces the data usage quota of an application
+func (h *V1Handler) updateApplicationQuota(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationQuota, applicationId); !ok {
+		return
+	}
+
+	var req models.UpdateApplicationQuotaRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	quota, err := h.applicationService.UpdateApplicationQuota(r.Context(), applicationId, &req)
+	if err != nil {
+		respondWithQuotaError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, quota)
+}
+
+// respondWithQuotaError maps application quota errors to HTTP responses
+func respondWithQuotaError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidQuota):
+		respondWithBadRequest(w, err)
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Application not found")
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// updateApplicationStatus suspends or reactivates an application
+func (h *V1Handler) updateApplicationStatus(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationStatus, applicationId); !ok {
+		return
+	}
+
+	var req models.UpdateApplicationStatusRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	application, err := h.applicationService.UpdateApplicationStatus(r.Context(), applicationId, &req)
+	if err != nil {
+		respondWithApplicationStatusError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, application)
+}
+
+// respondWithApplicationStatusError maps application status and context errors to HTTP responses
+func respondWithApplicationStatusError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidApplicationStatus):
+		respondWithBadRequest(w, err)
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Application not found")
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// renewApplication opens a renewal submission for the grants of an application
+func (h *V1Handler) renewApplication(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionCreateApplicationSubmission, applicationId); !ok {
+		return
+	}
+
+	submission, err := h.applicationService.RenewApplication(r.Context(), applicationId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrResourceNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, "Application not found")
+		case errors.Is(err, services.ErrRenewalPending):
+			utils.RespondWithError(w, http.StatusConflict, err.Error())
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, submission)
+}
+
+// respondWithCredentialError maps application credential failures to HTTP responses
+func respondWithCredentialError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrActiveCredentialExists), errors.Is(err, services.ErrCredentialNotActive),
+		errors.Is(err, services.ErrApplicationNotProvisioned):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *V1Handler) getApplicationCredentials(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionReadApplicationCredentials, applicationId); !ok {
+		return
+	}
+
+	credentials, err := h.credentialService.ListCredentials(r.Context(), applicationId)
+	if err != nil {
+		respondWithCredentialError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items: credentials,
+		Count: len(credentials),
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+func (h *V1Handler) generateApplicationCredential(w http.ResponseWriter, r *http.Request, applicationId string) {
+	user, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationCredentials, applicationId)
+	if !ok {
+		return
+	}
+
+	credential, err := h.credentialService.GenerateCredential(r.Context(), applicationId, user.IdpUserID)
+	if err != nil {
+		respondWithCredentialError(w, err)
+		return
+	}
+	logCredentialEvent(r, models.SecurityActionCredentialGenerated, credential)
+
+	utils.RespondWithSuccess(w, http.StatusCreated, credential)
+}
+
+func (h *V1Handler) rotateApplicationCredential(w http.ResponseWriter, r *http.Request, applicationId, credentialId string) {
+	user, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationCredentials, applicationId)
+	if !ok {
+		return
+	}
+
+	if h.requiresApproval(user, models.ApprovalActionRotateCredential) {
+		h.requestApproval(w, r, user, models.ApprovalActionRotateCredential, credentialId, models.ApprovalPayload{ApplicationID: applicationId})
+		return
+	}
+
+	credential, err := h.credentialService.RotateCredential(r.Context(), applicationId, credentialId, user.IdpUserID)
+	if err != nil {
+		respondWithCredentialError(w, err)
+		return
+	}
+	logCredentialEvent(r, models.SecurityActionCredentialRotated, credential)
+
+	utils.RespondWithSuccess(w, http.StatusOK, credential)
+}
+
+func (h *V1Handler) revokeApplicationCredential(w http.ResponseWriter, r *http.Request, applicationId, credentialId string) {
+	user, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationCredentials, applicationId)
+	if !ok {
+		return
+	}
+
+	credential, err := h.credentialService.RevokeCredential(r.Context(), applicationId, credentialId, user.IdpUserID)
+	if err != nil {
+		respondWithCredentialError(w, err)
+		return
+	}
+	logCredentialEvent(r, models.SecurityActionCredentialRevoked, credential)
+
+	utils.RespondWithSuccess(w, http.StatusOK, credential)
+}
+
+// logCredentialEvent logs a security event for a change to an application's credential
+func logCredentialEvent(r *http.Request, action models.SecurityEventAction, credential *models.ApplicationCredentialResponse) {
+	middleware.LogSecurityEvent(r, action, models.AuditStatusSuccess, credential.ApplicationID, map[string]interface{}{
+		"credentialId": credential.CredentialID,
+		"clientId":     credential.ClientID,
+	})
+}
+
+// respondWithWebhookError maps webhook failures to HTTP responses
+func respondWithWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook):
+		respondWithBadRequest(w, err)
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Webhook not found")
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *V1Handler) getApplicationWebhooks(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionReadApplicationWebhooks, applicationId); !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(r.Context(), applicationId)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items: webhooks,
+		Count: len(webhooks),
 	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
 }
 
-// handleApplications handles application-related routes
-func (h *V1Handler) handleApplications(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/v1/applications")
-	parts := strings.Split(strings.Trim(path, "/"), "/")
+func (h *V1Handler) createApplicationWebhook(w http.ResponseWriter, r *http.Request, applicationId string) {
+	user, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationWebhooks, applicationId)
+	if !ok {
+		return
+	}
 
-	// Handle collection endpoint: GET /api/v1/applications and POST /api/v1/applications
-	if len(parts) == 1 && parts[0] == "" {
-		switch r.Method {
-		case http.MethodGet:
-			memberId := r.URL.Query().Get("memberId")
-			h.getAllApplications(w, r, &memberId)
-		case http.MethodPost:
-			h.createApplication(w, r)
-		default:
-			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		}
+	var req models.CreateWebhookRequest
+	if !decodeRequestBody(w, r, &req) {
 		return
 	}
-	if len(parts) < 1 || parts[0] == "" {
-		utils.RespondWithError(w, http.StatusBadRequest, "Application ID is required")
+	if !validateRequest(w, &req) {
 		return
 	}
 
-	applicationId := parts[0]
-	// Handle specific application endpoint: GET /api/v1/applications/:applicationId and PUT /api/v1/applications/:applicationId
-	if len(parts) == 1 {
-		switch r.Method {
-		case http.MethodGet:
-			h.getApplication(w, r, applicationId)
-		case http.MethodPut:
-			h.updateApplication(w, r, applicationId)
-		default:
-			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		}
+	webhook, err := h.webhookService.CreateWebhook(r.Context(), applicationId, user.IdpUserID, &req)
+	if err != nil {
+		respondWithWebhookError(w, err)
 		return
 	}
 
-	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	utils.RespondWithSuccess(w, http.StatusCreated, webhook)
 }
 
-// handleApplicationSubmissions handles application submission-related routes
-func (h *V1Handler) handleApplicationSubmissions(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/v1/application-submissions")
-	parts := strings.Split(strings.Trim(path, "/"), "/")
+func (h *V1Handler) deleteApplicationWebhook(w http.ResponseWriter, r *http.Request, applicationId, webhookId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationWebhooks, applicationId); !ok {
+		return
+	}
 
-	// Handle collection endpoint: GET /api/v1/application-submissions and POST /api/v1/application-submissions
-	if len(parts) == 1 && parts[0] == "" {
-		switch r.Method {
-		case http.MethodGet:
-			status := r.URL.Query()["status"]
-			memberId := r.URL.Query().Get("memberId")
-			h.getAllApplicationSubmissions(w, r, &memberId, &status)
-		case http.MethodPost:
-			h.createApplicationSubmission(w, r, nil)
-		default:
-			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		}
+	if err := h.webhookService.DeleteWebhook(r.Context(), applicationId, webhookId); err != nil {
+		respondWithWebhookError(w, err)
 		return
 	}
 
-	if len(parts) < 1 || parts[0] == "" {
-		utils.RespondWithError(w, http.StatusBadRequest, "Submission ID is required")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getApplicationWebhookDeliveries returns the delivery log of a webhook, newest first
+func (h *V1Handler) getApplicationWebhookDeliveries(w http.ResponseWriter, r *http.Request, applicationId, webhookId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionReadApplicationWebhooks, applicationId); !ok {
 		return
 	}
 
-	submissionId := parts[0]
-	// Handle specific application submission endpoint: GET /api/v1/application-submissions/:submissionId and PUT /api/v1/application-submissions/:submissionId
-	if len(parts) == 1 {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "limit", "limit must be a positive integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), applicationId, webhookId, r.URL.Query().Get("status"), limit)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	response := models.CollectionResponse{
+		Items: deliveries,
+		Count: len(deliveries),
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
+
+// handleSubmissionReview routes the review workflow endpoints of a submission and reports whether
+// the path was one of them:
+//
+//	GET  /:submissionId/review
+//	POST /:submissionId/review/decisions
+//	PUT  /:submissionId/review/steps/:step/reviewers
+//	GET  /:submissionId/comments
+//	POST /:submissionId/comments
+func (h *V1Handler) handleSubmissionReview(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId string, parts []string) bool {
+	switch {
+	case len(parts) == 1 && parts[0] == "review":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return true
+		}
+		h.getSubmissionReview(w, r, submissionType, submissionId)
+	case len(parts) == 2 && parts[0] == "review" && parts[1] == "decisions":
+		if r.Method != http.MethodPost {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return true
+		}
+		h.reviewSubmission(w, r, submissionType, submissionId)
+	case len(parts) == 4 && parts[0] == "review" && parts[1] == "steps" && parts[3] == "reviewers":
+		if r.Method != http.MethodPut {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return true
+		}
+		h.assignSubmissionReviewers(w, r, submissionType, submissionId, parts[2])
+	case len(parts) == 1 && parts[0] == "comments":
 		switch r.Method {
 		case http.MethodGet:
-			h.getApplicationSubmission(w, r, submissionId)
-		case http.MethodPut:
-			h.updateApplicationSubmission(w, r, submissionId)
+			h.getSubmissionComments(w, r, submissionType, submissionId)
+		case http.MethodPost:
+			h.createSubmissionComment(w, r, submissionType, submissionId)
 		default:
 			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		}
-		return
+	default:
+		return false
 	}
-	utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	return true
 }
 
-// Member handlers
-func (h *V1Handler) createMember(w http.ResponseWriter, r *http.Request) {
+// authorizeSubmissionAccess checks the caller holds permission and, unless they are an admin, owns
+// the submission. It returns the caller and the submission's status on success and writes the
+// error response otherwise.
+func (h *V1Handler) authorizeSubmissionAccess(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId string, permission models.Permission) (*models.AuthenticatedUser, string, bool) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
-		return
+		return nil, "", false
 	}
 
-	// Check permission - only admin users can create members
-	if !user.HasPermission(models.PermissionCreateMember) {
+	// Check permission
+	if !user.HasPermission(permission) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
-		return
+		return nil, "", false
 	}
 
-	var req models.CreateMemberRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
-		return
+	var ownerMemberID, status string
+	var ownerOrganizationID *string
+	switch submissionType {
+	case models.SubmissionTypeSchema:
+		submission, err := h.schemaService.GetSchemaSubmission(submissionId)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusNotFound, err.Error())
+			return nil, "", false
+		}
+		ownerMemberID, ownerOrganizationID, status = submission.MemberID, submission.OrganizationID, submission.Status
+	case models.SubmissionTypeApplication:
+		submission, err := h.applicationService.GetApplicationSubmission(r.Context(), submissionId)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusNotFound, err.Error())
+			return nil, "", false
+		}
+		ownerMemberID, ownerOrganizationID, status = submission.MemberID, submission.OrganizationID, submission.Status
 	}
 
-	// Only admin users should reach this point due to permission check above
-	// Admin users can create members for any user if IdpUserID is provided in the request
+	// For non-admin users, check ownership
+	if !user.IsAdmin() {
+		userMemberID, err := h.getUserMemberID(r, user)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return nil, "", false
+		}
+		if !h.callerOwns(r, userMemberID, ownerMemberID, ownerOrganizationID) {
+			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+			return nil, "", false
+		}
+	}
 
-	member, err := h.memberService.CreateMember(r.Context(), &req)
+	return user, status, true
+}
+
+// submitSubmission validates a draft submission and submits it for review. Drafts skip the
+// validation submissions are created with, so it runs here instead.
+func (h *V1Handler) submitSubmission(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId string) {
+	permission := models.PermissionUpdateSchemaSubmission
+	if submissionType == models.SubmissionTypeApplication {
+		permission = models.PermissionUpdateApplicationSubmission
+	}
+	if _, _, ok := h.authorizeSubmissionAccess(w, r, submissionType, submissionId, permission); !ok {
+		return
+	}
+
+	var submission interface{}
+	var err error
+	switch submissionType {
+	case models.SubmissionTypeSchema:
+		submission, err = h.schemaService.SubmitSchemaSubmission(submissionId)
+	case models.SubmissionTypeApplication:
+		submission, err = h.applicationService.SubmitApplicationSubmission(r.Context(), submissionId)
+	}
 	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeMembers), nil, string(models.AuditStatusFailure))
+		respondWithSchemaSubmissionError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, submission)
+}
 
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+// notifySubmissionStatusChanged notifies the owner of a submission whose status moved from previous
+// to status. The change itself already succeeded, so a failure to notify is only logged.
+func (h *V1Handler) notifySubmissionStatusChanged(r *http.Request, submissionType models.SubmissionType, submissionId, previous, status string) {
+	if previous == status {
 		return
 	}
+	if err := h.notificationService.NotifySubmissionStatusChanged(r.Context(), submissionType, submissionId, status); err != nil {
+		slog.Error("Failed to notify submission status change",
+			"submissionType", submissionType,
+			"submissionID", submissionId,
+			"status", status,
+			"error", err)
+	}
+	if err := h.webhookService.EnqueueSubmissionStatusChanged(r.Context(), submissionType, submissionId, previous, status); err != nil {
+		slog.Error("Failed to queue submission status webhooks",
+			"submissionType", submissionType,
+			"submissionID", submissionId,
+			"status", status,
+			"error", err)
+	}
+}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeMembers), &member.MemberID, string(models.AuditStatusSuccess))
+// submissionReadPermission is the permission needed to read a submission of submissionType
+func submissionReadPermission(submissionType models.SubmissionType) models.Permission {
+	if submissionType == models.SubmissionTypeApplication {
+		return models.PermissionReadApplicationSubmission
+	}
+	return models.PermissionReadSchemaSubmission
+}
 
-	utils.RespondWithSuccess(w, http.StatusCreated, member)
+// respondWithReviewError maps review workflow failures to HTTP responses
+func respondWithReviewError(w http.ResponseWriter, err error) {
+	var mismatchErr *services.SchemaEndpointMismatchError
+	if errors.As(err, &mismatchErr) {
+		utils.RespondWithJSON(w, http.StatusUnprocessableEntity, models.SchemaEndpointMismatchResponse{
+			Error:  mismatchErr.Error(),
+			Report: mismatchErr.Report,
+		})
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound), errors.Is(err, services.ErrUnknownReviewStep),
+		errors.Is(err, services.ErrSchemaEndpointProbeDisabled):
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrNotAssignedReviewer):
+		utils.RespondWithError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrSubmissionNotInReview), errors.Is(err, services.ErrAlreadyReviewed):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidReviewers):
+		respondWithBadRequest(w, err)
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
 }
 
-func (h *V1Handler) updateMember(w http.ResponseWriter, r *http.Request, memberId string) {
-	// Get authenticated user
-	user, err := middleware.GetUserFromRequest(r)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+func (h *V1Handler) getSubmissionReview(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId string) {
+	if _, _, ok := h.authorizeSubmissionAccess(w, r, submissionType, submissionId, submissionReadPermission(submissionType)); !ok {
 		return
 	}
 
-	// Get the existing member to check ownership
-	existingMember, err := h.memberService.GetMember(r.Context(), memberId)
+	review, err := h.reviewService.GetReview(r.Context(), submissionType, submissionId)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		respondWithReviewError(w, err)
 		return
 	}
 
-	// Check if user can update this member resource
-	// Admin can update any member, regular members can only update their own
-	if !user.IsAdmin() && existingMember.IdpUserID != user.IdpUserID {
-		utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
+	utils.RespondWithSuccess(w, http.StatusOK, review)
+}
+
+func (h *V1Handler) reviewSubmission(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId string) {
+	user, previousStatus, ok := h.authorizeSubmissionAccess(w, r, submissionType, submissionId, models.PermissionReviewSubmission)
+	if !ok {
 		return
 	}
 
-	var req models.UpdateMemberRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	var req models.SubmissionReviewDecisionRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
 		return
 	}
 
-	// Pass request context to service for proper context propagation
-	member, err := h.memberService.UpdateMember(r.Context(), memberId, &req)
-	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeMembers), &existingMember.MemberID, string(models.AuditStatusFailure))
+	if h.requiresApproval(user, models.ApprovalActionApproveSensitiveSchema) {
+		requiresApproval, err := h.approvalService.RequiresReviewApproval(r.Context(), submissionType, submissionId, req.Decision)
+		if err != nil {
+			respondWithReviewError(w, err)
+			return
+		}
+		if requiresApproval {
+			h.requestApproval(w, r, user, models.ApprovalActionApproveSensitiveSchema, submissionId, models.ApprovalPayload{Review: &req})
+			return
+		}
+	}
 
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+	review, err := h.reviewService.Review(r.Context(), submissionType, submissionId, user.IdpUserID, &req)
+	if err != nil {
+		respondWithReviewError(w, err)
 		return
 	}
+	h.notifySubmissionStatusChanged(r, submissionType, submissionId, previousStatus, review.Status)
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeMembers), &member.MemberID, string(models.AuditStatusSuccess))
-
-	utils.RespondWithSuccess(w, http.StatusOK, member)
+	utils.RespondWithSuccess(w, http.StatusOK, review)
 }
 
-func (h *V1Handler) getMember(w http.ResponseWriter, r *http.Request, memberId string) {
-	// Get authenticated user
-	user, err := middleware.GetUserFromRequest(r)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+func (h *V1Handler) checkSchemaSubmissionEndpoint(w http.ResponseWriter, r *http.Request, submissionId string) {
+	if _, _, ok := h.authorizeSubmissionAccess(w, r, models.SubmissionTypeSchema, submissionId, models.PermissionReviewSubmission); !ok {
 		return
 	}
 
-	// Get the member from database
-	// Pass request context to service for proper context propagation
-	member, err := h.memberService.GetMember(r.Context(), memberId)
+	report, err := h.reviewService.CheckSchemaEndpoint(r.Context(), submissionId)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		respondWithReviewError(w, err)
 		return
 	}
 
-	// Check if user can access this member resource
-	// Admin can access any member, regular members can only access their own
-	if !user.IsAdmin() && member.IdpUserID != user.IdpUserID {
-		utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+	utils.RespondWithSuccess(w, http.StatusOK, report)
+}
+
+func (h *V1Handler) assignSubmissionReviewers(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId, step string) {
+	user, previousStatus, ok := h.authorizeSubmissionAccess(w, r, submissionType, submissionId, models.PermissionReviewSubmission)
+	if !ok {
 		return
 	}
 
-	utils.RespondWithSuccess(w, http.StatusOK, member)
-}
+	var req models.AssignSubmissionReviewersRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
 
-func (h *V1Handler) getAllMembers(w http.ResponseWriter, r *http.Request, idpUserId *string, email *string) {
-	// Get authenticated user
-	user, err := middleware.GetUserFromRequest(r)
+	review, err := h.reviewService.AssignReviewers(r.Context(), submissionType, submissionId, step, req.ReviewerIDs, user.IdpUserID)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		respondWithReviewError(w, err)
 		return
 	}
+	h.notifySubmissionStatusChanged(r, submissionType, submissionId, previousStatus, review.Status)
 
-	// Check permission - admin can read all members, regular users need specific permission
-	var filteredIdpUserId *string
+	utils.RespondWithSuccess(w, http.StatusOK, review)
+}
 
-	if user.HasPermission(models.PermissionReadAllMembers) {
-		// Admin can use provided filters or see all
-		filteredIdpUserId = idpUserId
-		// Note: We still accept email parameter from query but don't use it
-		// since IdpUserID filtering is sufficient for uniqueness
-	} else if user.HasPermission(models.PermissionReadMember) {
-		// Regular users can only see their own member record
-		// IdpUserID is unique, so no need to also filter by email
-		filteredIdpUserId = &user.IdpUserID
-	} else {
-		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+func (h *V1Handler) getSubmissionComments(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId string) {
+	if _, _, ok := h.authorizeSubmissionAccess(w, r, submissionType, submissionId, submissionReadPermission(submissionType)); !ok {
 		return
 	}
 
-	// Pass request context to service for proper context propagation
-	// Since IdpUserID is unique, we don't need to pass email parameter
-	members, err := h.memberService.GetAllMembers(r.Context(), filteredIdpUserId, nil)
+	comments, err := h.reviewService.ListComments(r.Context(), submissionType, submissionId)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithReviewError(w, err)
 		return
 	}
 
 	response := models.CollectionResponse{
-		Items: members,
-		Count: len(members),
+		Items: comments,
+		Count: len(comments),
 	}
 	utils.RespondWithSuccess(w, http.StatusOK, response)
 }
 
-// Schema handlers
-func (h *V1Handler) getAllSchemaSubmissions(w http.ResponseWriter, r *http.Request, memberId *string, statusFilter *[]string) {
+func (h *V1Handler) createSubmissionComment(w http.ResponseWriter, r *http.Request, submissionType models.SubmissionType, submissionId string) {
+	user, _, ok := h.authorizeSubmissionAccess(w, r, submissionType, submissionId, models.PermissionCommentSubmission)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSubmissionCommentRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if !validateRequest(w, &req) {
+		return
+	}
+	if len(req.Body) > models.MaxDescriptionLength {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorTooLong, "body", fmt.Sprintf("body must be at most %d characters", models.MaxDescriptionLength)))
+		return
+	}
+
+	comment, err := h.reviewService.AddComment(r.Context(), submissionType, submissionId, user.IdpUserID, req.Body)
+	if err != nil {
+		respondWithReviewError(w, err)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, comment)
+}
+
+// handleNotifications handles the caller's notification routes
+func (h *V1Handler) handleNotifications(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/notifications")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	// Handle collection endpoint: GET /api/v1/notifications
+	case len(parts) == 1 && parts[0] == "":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.getNotifications(w, r)
+	// Handle preferences endpoint: GET and PUT /api/v1/notifications/preferences
+	case len(parts) == 1 && parts[0] == "preferences":
+		switch r.Method {
+		case http.MethodGet:
+			h.getNotificationPreferences(w, r)
+		case http.MethodPut:
+			h.updateNotificationPreferences(w, r)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	// Handle read endpoint: POST /api/v1/notifications/:notificationId/read
+	case len(parts) == 2 && parts[0] != "" && parts[1] == "read":
+		if r.Method != http.MethodPost {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.markNotificationRead(w, r, parts[0])
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
+	}
+}
+
+// authorizeNotificationAccess checks the caller holds permission and returns their member ID.
+// Notifications belong to members, so callers without a member record have none.
+func (h *V1Handler) authorizeNotificationAccess(w http.ResponseWriter, r *http.Request, permission models.Permission) (string, bool) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
-		return
+		return "", false
 	}
 
 	// Check permission
-	var filteredMemberId *string
-	if user.HasPermission(models.PermissionReadAllSchemaSubmissions) {
-		// Admin/System can use provided filters or see all
-		filteredMemberId = memberId
-	} else if user.HasPermission(models.PermissionReadSchemaSubmission) {
-		// Regular users can only see their own submissions
-		// Get member ID for the authenticated user (cached)
-		userMemberId, err := h.getUserMemberID(r, user)
+	if !user.HasPermission(permission) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return "", false
+	}
+
+	memberID, err := h.getUserMemberID(r, user)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+		return "", false
+	}
+	return memberID, true
+}
+
+func (h *V1Handler) getNotifications(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := h.authorizeNotificationAccess(w, r, models.PermissionReadNotifications)
+	if !ok {
+		return
+	}
+
+	unreadOnly := false
+	if value := r.URL.Query().Get("unread"); value != "" {
+		parsed, err := strconv.ParseBool(value)
 		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "unread", "unread must be true or false"))
 			return
 		}
-		filteredMemberId = &userMemberId
-	} else {
-		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
-		return
+		unreadOnly = parsed
 	}
 
-	submissions, err := h.schemaService.GetSchemaSubmissions(filteredMemberId, statusFilter)
+	notifications, err := h.notificationService.ListNotifications(r.Context(), memberID, unreadOnly)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	response := models.CollectionResponse{
-		Items: submissions,
-		Count: len(submissions),
+		Items: notifications,
+		Count: len(notifications),
 	}
 	utils.RespondWithSuccess(w, http.StatusOK, response)
 }
 
-func (h *V1Handler) getSchemaSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
-	// Get authenticated user
-	user, err := middleware.GetUserFromRequest(r)
+func (h *V1Handler) markNotificationRead(w http.ResponseWriter, r *http.Request, notificationId string) {
+	memberID, ok := h.authorizeNotificationAccess(w, r, models.PermissionReadNotifications)
+	if !ok {
+		return
+	}
+
+	notification, err := h.notificationService.MarkRead(r.Context(), memberID, notificationId)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+		if errors.Is(err, services.ErrResourceNotFound) {
+			utils.RespondWithError(w, http.StatusNotFound, "Notification not found")
+			return
+		}
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Check permission
-	if !user.HasPermission(models.PermissionReadSchemaSubmission) {
-		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+	utils.RespondWithSuccess(w, http.StatusOK, notification)
+}
+
+func (h *V1Handler) getNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := h.authorizeNotificationAccess(w, r, models.PermissionReadNotifications)
+	if !ok {
 		return
 	}
 
-	submission, err := h.schemaService.GetSchemaSubmission(submissionId)
+	preferences, err := h.notificationService.GetPreferences(r.Context(), memberID)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// For non-admin users, check ownership
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-
-		// Check if submission belongs to the user
-		if submission.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
-			return
-		}
+	response := models.CollectionResponse{
+		Items: preferences,
+		Count: len(preferences),
 	}
-
-	utils.RespondWithSuccess(w, http.StatusOK, submission)
+	utils.RespondWithSuccess(w, http.StatusOK, response)
 }
 
-func (h *V1Handler) createSchemaSubmission(w http.ResponseWriter, r *http.Request, memberId *string) {
-	// Get authenticated user
-	user, err := middleware.GetUserFromRequest(r)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+func (h *V1Handler) updateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := h.authorizeNotificationAccess(w, r, models.PermissionUpdateNotificationPreferences)
+	if !ok {
 		return
 	}
 
-	// Check permission
-	if !user.HasPermission(models.PermissionCreateSchemaSubmission) {
-		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+	var req models.UpdateNotificationPreferencesRequest
+	if !decodeRequestBody(w, r, &req) {
 		return
 	}
-
-	var req models.CreateSchemaSubmissionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !validateRequest(w, &req) {
 		return
 	}
 
-	// If memberId is provided from URL parameter, use it
-	if memberId != nil {
-		req.MemberID = *memberId
-	}
-
-	// For non-admin users, ensure they can only create submissions for themselves
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+	preferences, err := h.notificationService.UpdatePreferences(r.Context(), memberID, req.Preferences)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNotificationEvent) {
+			respondWithBadRequest(w, err)
 			return
 		}
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-		// If MemberID is provided, validate ownership
-		if req.MemberID != "" {
-			// Check if the provided MemberID belongs to the authenticated user
-			if req.MemberID != userMemberID {
-				utils.RespondWithError(w, http.StatusForbidden, "Access denied: cannot create submission for another user")
-				return
-			}
-		} else {
-			// If no MemberID provided, set it to the authenticated user's member ID
-			req.MemberID = userMemberID
-		}
+	response := models.CollectionResponse{
+		Items: preferences,
+		Count: len(preferences),
 	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
 
-	submission, err := h.schemaService.CreateSchemaSubmission(&req)
-	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeSchemaSubmissions), nil, string(models.AuditStatusFailure))
+// handleInvitations handles member invitation admin routes
+func (h *V1Handler) handleInvitations(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/invitations")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
 
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
-		return
+	switch {
+	// Handle collection endpoint: GET and POST /api/v1/invitations
+	case len(parts) == 1 && parts[0] == "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getAllInvitations(w, r)
+		case http.MethodPost:
+			h.createInvitation(w, r)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	// Handle revoke endpoint: DELETE /api/v1/invitations/:invitationId
+	case len(parts) == 1:
+		if r.Method != http.MethodDelete {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		h.revokeInvitation(w, r, parts[0])
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
 	}
+}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeSchemaSubmissions), &submission.SubmissionID, string(models.AuditStatusSuccess))
-
-	utils.RespondWithSuccess(w, http.StatusCreated, submission)
+// respondWithInvitationError maps invitation service errors to HTTP responses
+func respondWithInvitationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidInvitationRole), errors.Is(err, services.ErrInvalidOrganizationRole),
+		errors.Is(err, services.ErrInvitationOrganizationNotFound):
+		respondWithBadRequest(w, err)
+	case errors.Is(err, services.ErrResourceNotFound), errors.Is(err, services.ErrInvalidInvitationToken):
+		utils.RespondWithError(w, http.StatusNotFound, "Invitation not found")
+	case errors.Is(err, services.ErrInvitationConflict), errors.Is(err, services.ErrInvitationNotPending):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvitationExpired):
+		utils.RespondWithError(w, http.StatusGone, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
 }
 
-func (h *V1Handler) updateSchemaSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+func (h *V1Handler) createInvitation(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -649,57 +4427,30 @@ func (h *V1Handler) updateSchemaSubmission(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Check permission
-	if !user.HasPermission(models.PermissionUpdateSchemaSubmission) {
+	// Check permission - only admin users can invite members
+	if !user.HasPermission(models.PermissionCreateInvitation) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
 
-	// Get existing submission to check ownership
-	existingSubmission, err := h.schemaService.GetSchemaSubmission(submissionId)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	var req models.CreateInvitationRequest
+	if !decodeRequestBody(w, r, &req) {
 		return
 	}
-
-	// For non-admin users, check ownership
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-
-		// Check if submission belongs to the user
-		if existingSubmission.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
-			return
-		}
-	}
-
-	var req models.UpdateSchemaSubmissionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !validateRequest(w, &req) {
 		return
 	}
 
-	submission, err := h.schemaService.UpdateSchemaSubmission(submissionId, &req)
+	invitation, err := h.invitationService.CreateInvitation(r.Context(), &req, user.IdpUserID)
 	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeSchemaSubmissions), &existingSubmission.SubmissionID, string(models.AuditStatusFailure))
-
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		respondWithInvitationError(w, err)
 		return
 	}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeSchemaSubmissions), &submission.SubmissionID, string(models.AuditStatusSuccess))
-
-	utils.RespondWithSuccess(w, http.StatusOK, submission)
+	utils.RespondWithSuccess(w, http.StatusCreated, invitation)
 }
 
-func (h *V1Handler) getAllSchemas(w http.ResponseWriter, r *http.Request, memberId *string) {
+func (h *V1Handler) getAllInvitations(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -708,40 +4459,25 @@ func (h *V1Handler) getAllSchemas(w http.ResponseWriter, r *http.Request, member
 	}
 
 	// Check permission
-	if !user.HasPermission(models.PermissionReadSchema) {
+	if !user.HasPermission(models.PermissionReadInvitation) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
 
-	// For non-admin users, filter results to only their own schemas
-	var filteredMemberId *string
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberId, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-		filteredMemberId = &userMemberId
-	} else {
-		// Admin can specify memberId or see all
-		filteredMemberId = memberId
-	}
-
-	schemas, err := h.schemaService.GetSchemas(filteredMemberId)
+	invitations, err := h.invitationService.ListInvitations(r.Context())
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	response := models.CollectionResponse{
-		Items: schemas,
-		Count: len(schemas),
+		Items: invitations,
+		Count: len(invitations),
 	}
 	utils.RespondWithSuccess(w, http.StatusOK, response)
 }
 
-func (h *V1Handler) getSchema(w http.ResponseWriter, r *http.Request, schemaId string) {
+func (h *V1Handler) revokeInvitation(w http.ResponseWriter, r *http.Request, invitationId string) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -750,85 +4486,143 @@ func (h *V1Handler) getSchema(w http.ResponseWriter, r *http.Request, schemaId s
 	}
 
 	// Check permission
-	if !user.HasPermission(models.PermissionReadSchema) {
+	if !user.HasPermission(models.PermissionRevokeInvitation) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
 
-	schema, err := h.schemaService.GetSchema(schemaId)
+	invitation, err := h.invitationService.RevokeInvitation(r.Context(), invitationId)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		respondWithInvitationError(w, err)
 		return
 	}
 
-	// For non-admin users, check ownership
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-
-		// Check if schema belongs to the user
-		if schema.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
-			return
-		}
-	}
-
-	utils.RespondWithSuccess(w, http.StatusOK, schema)
+	utils.RespondWithSuccess(w, http.StatusOK, invitation)
 }
 
-func (h *V1Handler) createSchema(w http.ResponseWriter, r *http.Request) {
-	// Get authenticated user
-	user, err := middleware.GetUserFromRequest(r)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+// acceptInvitation provisions the invitee as a member. It is a public route: the invitation token
+// in the request body is the caller's only credential.
+func (h *V1Handler) acceptInvitation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	// Check permission
-	if !user.HasPermission(models.PermissionCreateSchema) {
-		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+	var req models.AcceptInvitationRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
 		return
 	}
 
-	var req models.CreateSchemaRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	member, err := h.invitationService.AcceptInvitation(r.Context(), &req)
+	if err != nil {
+		respondWithInvitationError(w, err)
 		return
 	}
 
-	// For non-admin users, ensure they can only create schemas for themselves
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+	if err := h.notificationService.NotifyMembershipApproved(r.Context(), member.MemberID); err != nil {
+		slog.Error("Failed to notify member of membership approval", "memberID", member.MemberID, "error", err)
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, member)
+}
+
+// handleOrganizations handles organization routes
+func (h *V1Handler) handleOrganizations(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/organizations")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	// Handle collection endpoint: GET and POST /api/v1/organizations
+	case len(parts) == 1 && parts[0] == "":
+		switch r.Method {
+		case http.MethodGet:
+			h.getAllOrganizations(w, r)
+		case http.MethodPost:
+			h.createOrganization(w, r)
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	// Handle individual organization: GET and PUT /api/v1/organizations/:organizationId
+	case len(parts) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			h.getOrganization(w, r, parts[0])
+		case http.MethodPut:
+			h.updateOrganization(w, r, parts[0])
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	// Handle membership list: GET /api/v1/organizations/:organizationId/members
+	case len(parts) == 2 && parts[1] == "members":
+		if r.Method != http.MethodGet {
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			return
 		}
-
-		// Set the member ID to the authenticated user's member ID
-		req.MemberID = userMemberID
+		h.getOrganizationMembers(w, r, parts[0])
+	// Handle membership: PUT and DELETE /api/v1/organizations/:organizationId/members/:memberId
+	case len(parts) == 3 && parts[1] == "members":
+		switch r.Method {
+		case http.MethodPut:
+			h.setOrganizationMember(w, r, parts[0], parts[2])
+		case http.MethodDelete:
+			h.removeOrganizationMember(w, r, parts[0], parts[2])
+		default:
+			utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	default:
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
 	}
+}
 
-	schema, err := h.schemaService.CreateSchema(&req)
-	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeSchemas), nil, string(models.AuditStatusFailure))
+// respondWithOrganizationError maps organization service errors to HTTP responses
+func respondWithOrganizationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidOrganizationRole):
+		respondWithBadRequest(w, err)
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrOrganizationConflict), errors.Is(err, services.ErrMemberInAnotherOrganization),
+		errors.Is(err, services.ErrLastOrganizationAdmin):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
 
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
-		return
+// authorizeOrganizationAccess checks that the caller may act on an organization: admins always may,
+// other users only on the organization they belong to, and only as its org_admin when requireOrgAdmin
+// is set. It writes the error response and returns false when access is denied.
+func (h *V1Handler) authorizeOrganizationAccess(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser, organizationID string, requireOrgAdmin bool) bool {
+	if user.IsAdmin() {
+		return true
 	}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeSchemas), &schema.SchemaID, string(models.AuditStatusSuccess))
+	userMemberID, err := h.getUserMemberID(r, user)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+		return false
+	}
+	member, err := h.memberService.GetMember(r.Context(), userMemberID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+		return false
+	}
 
-	utils.RespondWithSuccess(w, http.StatusCreated, schema)
+	if member.OrganizationID == nil || *member.OrganizationID != organizationID {
+		utils.RespondWithError(w, http.StatusForbidden, "Access denied: not a member of this organization")
+		return false
+	}
+	if requireOrgAdmin && member.OrganizationRole != models.OrganizationRoleAdmin {
+		utils.RespondWithError(w, http.StatusForbidden, "Access denied: org_admin role required")
+		return false
+	}
+	return true
 }
 
-func (h *V1Handler) updateSchema(w http.ResponseWriter, r *http.Request, schemaId string) {
+func (h *V1Handler) createOrganization(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -836,58 +4630,30 @@ func (h *V1Handler) updateSchema(w http.ResponseWriter, r *http.Request, schemaI
 		return
 	}
 
-	// Check permission
-	if !user.HasPermission(models.PermissionUpdateSchema) {
+	// Check permission - only admin users can create organizations
+	if !user.HasPermission(models.PermissionCreateOrganization) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
 
-	// Get existing schema to check ownership
-	existingSchema, err := h.schemaService.GetSchema(schemaId)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	var req models.CreateOrganizationRequest
+	if !decodeRequestBody(w, r, &req) {
 		return
 	}
-
-	// For non-admin users, check ownership
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-
-		// Check if schema belongs to the user
-		if existingSchema.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
-			return
-		}
-	}
-
-	var req models.UpdateSchemaRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !validateRequest(w, &req) {
 		return
 	}
 
-	schema, err := h.schemaService.UpdateSchema(schemaId, &req)
+	organization, err := h.organizationService.CreateOrganization(r.Context(), &req)
 	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeSchemas), &existingSchema.SchemaID, string(models.AuditStatusFailure))
-
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		respondWithOrganizationError(w, err)
 		return
 	}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeSchemas), &schema.SchemaID, string(models.AuditStatusSuccess))
-
-	utils.RespondWithSuccess(w, http.StatusOK, schema)
+	utils.RespondWithSuccess(w, http.StatusCreated, organization)
 }
 
-// Application handlers
-func (h *V1Handler) getAllApplicationSubmissions(w http.ResponseWriter, r *http.Request, memberId *string, statusFilter *[]string) {
+func (h *V1Handler) getAllOrganizations(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -896,40 +4662,44 @@ func (h *V1Handler) getAllApplicationSubmissions(w http.ResponseWriter, r *http.
 	}
 
 	// Check permission
-	if !user.HasPermission(models.PermissionReadApplicationSubmission) {
+	if !user.HasPermission(models.PermissionReadOrganization) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
 
-	var finalMemberId *string = memberId
-
-	// For non-admin users, force filtering to their own submissions only
+	// Non-admin users only see the organization they belong to
+	var organizationID *string
 	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
 		userMemberID, err := h.getUserMemberID(r, user)
 		if err != nil {
 			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
 			return
 		}
-
-		// Force the memberId to the authenticated user's member ID
-		finalMemberId = &userMemberID
+		organizationID, err = h.getUserOrganizationID(r, userMemberID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			return
+		}
+		if organizationID == nil {
+			utils.RespondWithSuccess(w, http.StatusOK, models.CollectionResponse{Items: []models.OrganizationResponse{}, Count: 0})
+			return
+		}
 	}
 
-	submissions, err := h.applicationService.GetApplicationSubmissions(r.Context(), finalMemberId, statusFilter)
+	organizations, err := h.organizationService.ListOrganizations(r.Context(), organizationID)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	response := models.CollectionResponse{
-		Items: submissions,
-		Count: len(submissions),
+		Items: organizations,
+		Count: len(organizations),
 	}
 	utils.RespondWithSuccess(w, http.StatusOK, response)
 }
 
-func (h *V1Handler) getApplicationSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+func (h *V1Handler) getOrganization(w http.ResponseWriter, r *http.Request, organizationId string) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -938,37 +4708,24 @@ func (h *V1Handler) getApplicationSubmission(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Check permission
-	if !user.HasPermission(models.PermissionReadApplicationSubmission) {
+	if !user.HasPermission(models.PermissionReadOrganization) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
-
-	submission, err := h.applicationService.GetApplicationSubmission(r.Context(), submissionId)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	if !h.authorizeOrganizationAccess(w, r, user, organizationId, false) {
 		return
 	}
 
-	// For non-admin users, check ownership
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-
-		// Check if submission belongs to the user
-		if submission.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
-			return
-		}
+	organization, err := h.organizationService.GetOrganization(r.Context(), organizationId)
+	if err != nil {
+		respondWithOrganizationError(w, err)
+		return
 	}
 
-	utils.RespondWithSuccess(w, http.StatusOK, submission)
+	utils.RespondWithSuccess(w, http.StatusOK, organization)
 }
 
-func (h *V1Handler) createApplicationSubmission(w http.ResponseWriter, r *http.Request, memberId *string) {
+func (h *V1Handler) updateOrganization(w http.ResponseWriter, r *http.Request, organizationId string) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -977,60 +4734,33 @@ func (h *V1Handler) createApplicationSubmission(w http.ResponseWriter, r *http.R
 	}
 
 	// Check permission
-	if !user.HasPermission(models.PermissionCreateApplicationSubmission) {
+	if !user.HasPermission(models.PermissionUpdateOrganization) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
-
-	var req models.CreateApplicationSubmissionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !h.authorizeOrganizationAccess(w, r, user, organizationId, true) {
 		return
 	}
 
-	// If memberId is provided from URL parameter, use it
-	if memberId != nil {
-		req.MemberID = *memberId
+	var req models.UpdateOrganizationRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
 	}
-
-	// For non-admin users, ensure they can only create submissions for themselves
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-
-		// If MemberID is provided, validate ownership
-		if req.MemberID != "" {
-			// Check if the provided MemberID belongs to the authenticated user
-			if req.MemberID != userMemberID {
-				utils.RespondWithError(w, http.StatusForbidden, "Access denied: cannot create submission for another user")
-				return
-			}
-		} else {
-			// If no MemberID provided, set it to the authenticated user's member ID
-			req.MemberID = userMemberID
-		}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorRequired, "name", "name cannot be empty"))
+		return
 	}
 
-	submission, err := h.applicationService.CreateApplicationSubmission(r.Context(), &req)
+	organization, err := h.organizationService.UpdateOrganization(r.Context(), organizationId, &req)
 	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeApplicationSubmissions), nil, string(models.AuditStatusFailure))
-
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		respondWithOrganizationError(w, err)
 		return
 	}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeApplicationSubmissions), &submission.SubmissionID, string(models.AuditStatusSuccess))
-
-	utils.RespondWithSuccess(w, http.StatusCreated, submission)
+	utils.RespondWithSuccess(w, http.StatusOK, organization)
 }
 
-func (h *V1Handler) updateApplicationSubmission(w http.ResponseWriter, r *http.Request, submissionId string) {
+func (h *V1Handler) getOrganizationMembers(w http.ResponseWriter, r *http.Request, organizationId string) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -1039,56 +4769,62 @@ func (h *V1Handler) updateApplicationSubmission(w http.ResponseWriter, r *http.R
 	}
 
 	// Check permission
-	if !user.HasPermission(models.PermissionUpdateApplicationSubmission) {
+	if !user.HasPermission(models.PermissionReadOrganization) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
+	if !h.authorizeOrganizationAccess(w, r, user, organizationId, false) {
+		return
+	}
 
-	// Get existing submission to check ownership
-	existingSubmission, err := h.applicationService.GetApplicationSubmission(r.Context(), submissionId)
+	members, err := h.organizationService.ListOrganizationMembers(r.Context(), organizationId)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, "Application submission not found")
+		respondWithOrganizationError(w, err)
 		return
 	}
 
-	// For non-admin users, check ownership before updating
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-
-		// Check if submission belongs to the user
-		if existingSubmission.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
-			return
-		}
+	response := models.CollectionResponse{
+		Items: members,
+		Count: len(members),
 	}
+	utils.RespondWithSuccess(w, http.StatusOK, response)
+}
 
-	var req models.UpdateApplicationSubmissionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+func (h *V1Handler) setOrganizationMember(w http.ResponseWriter, r *http.Request, organizationId, memberId string) {
+	// Get authenticated user
+	user, err := middleware.GetUserFromRequest(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
-	submission, err := h.applicationService.UpdateApplicationSubmission(r.Context(), submissionId, &req)
-	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeApplicationSubmissions), &existingSubmission.SubmissionID, string(models.AuditStatusFailure))
+	// Check permission
+	if !user.HasPermission(models.PermissionManageOrganizationMember) {
+		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+	if !h.authorizeOrganizationAccess(w, r, user, organizationId, true) {
+		return
+	}
 
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+	var req models.SetOrganizationMemberRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if !validateRequest(w, &req) {
 		return
 	}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeApplicationSubmissions), &submission.SubmissionID, string(models.AuditStatusSuccess))
+	member, err := h.organizationService.SetOrganizationMember(r.Context(), organizationId, memberId, req.Role)
+	if err != nil {
+		respondWithOrganizationError(w, err)
+		return
+	}
 
-	utils.RespondWithSuccess(w, http.StatusOK, submission)
+	utils.RespondWithSuccess(w, http.StatusOK, member)
 }
 
-func (h *V1Handler) getAllApplications(w http.ResponseWriter, r *http.Request, memberId *string) {
+func (h *V1Handler) removeOrganizationMember(w http.ResponseWriter, r *http.Request, organizationId, memberId string) {
 	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
@@ -1097,193 +4833,223 @@ func (h *V1Handler) getAllApplications(w http.ResponseWriter, r *http.Request, m
 	}
 
 	// Check permission
-	var filteredMemberId *string
-	if user.HasPermission(models.PermissionReadAllApplications) {
-		// Admin/System can use provided filters or see all
-		filteredMemberId = memberId
-	} else if user.HasPermission(models.PermissionReadApplication) {
-		// Regular users can only see their own applications
-		// Get member ID for the authenticated user (cached)
-		userMemberId, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
-		filteredMemberId = &userMemberId
-	} else {
+	if !user.HasPermission(models.PermissionManageOrganizationMember) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
-
-	applications, err := h.applicationService.GetApplications(r.Context(), filteredMemberId)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	if !h.authorizeOrganizationAccess(w, r, user, organizationId, true) {
 		return
 	}
 
-	response := models.CollectionResponse{
-		Items: applications,
-		Count: len(applications),
+	if err := h.organizationService.RemoveOrganizationMember(r.Context(), organizationId, memberId); err != nil {
+		respondWithOrganizationError(w, err)
+		return
 	}
-	utils.RespondWithSuccess(w, http.StatusOK, response)
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *V1Handler) getApplication(w http.ResponseWriter, r *http.Request, applicationId string) {
-	// Get authenticated user
+// getCatalogFields lists the fields of approved schemas for the field picker, with their providers,
+// classifications and consent requirements from the PDP
+func (h *V1Handler) getCatalogFields(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
-
-	// Check permission
-	if !user.HasPermission(models.PermissionReadApplication) {
+	if !user.HasPermission(models.PermissionReadCatalog) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
 
-	application, err := h.applicationService.GetApplication(r.Context(), applicationId)
+	q, err := parseListQuery(r)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		respondWithBadRequest(w, err)
 		return
 	}
-
-	// For non-admin users, check ownership
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
+	params := r.URL.Query()
+	filters := models.CatalogFieldFilters{
+		SchemaID:          params.Get("schemaId"),
+		ProviderID:        params.Get("providerId"),
+		Classification:    models.Classification(params.Get("classification")),
+		AccessControlType: models.AccessControlType(params.Get("accessControlType")),
+	}
+	if value := params.Get("consentRequired"); value != "" {
+		consentRequired, err := strconv.ParseBool(value)
 		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
+			respondWithBadRequest(w, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "consentRequired", "consentRequired must be true or false"))
 			return
 		}
+		filters.ConsentRequired = &consentRequired
+	}
 
-		// Check if application belongs to the user
-		if application.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to this resource")
+	fields, err := h.catalogService.ListCatalogFields(r.Context(), q, filters)
+	if err != nil {
+		if errors.Is(err, services.ErrPolicyServiceUnavailable) {
+			slog.Error("Failed to get field policies", "error", err)
+			utils.RespondWithError(w, http.StatusBadGateway, "Field policies are unavailable")
 			return
 		}
+		respondWithListError(w, err)
+		return
 	}
 
-	utils.RespondWithSuccess(w, http.StatusOK, application)
+	utils.RespondWithSuccess(w, http.StatusOK, fields)
 }
 
-func (h *V1Handler) getApplicationIdByClientId(w http.ResponseWriter, r *http.Request) {
-	idpClientId := r.URL.Query().Get("idpClientId")
-	if idpClientId == "" {
-		utils.RespondWithError(w, http.StatusBadRequest, "idpClientId query parameter is required")
-		return
-	}
-
-	applicationId, err := h.applicationService.GetApplicationIdByIdpClientId(r.Context(), idpClientId)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+// getAuditEvents lists audit events from the audit service. Members only see the events of their own
+// and their organization's entities, whichever filters they pass.
+func (h *V1Handler) getAuditEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
-	utils.RespondWithSuccess(w, http.StatusOK, applicationId)
-}
 
-func (h *V1Handler) createApplication(w http.ResponseWriter, r *http.Request) {
-	// Get authenticated user
 	user, err := middleware.GetUserFromRequest(r)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
-
-	// Check permission
-	if !user.HasPermission(models.PermissionCreateApplication) {
+	if !user.HasPermission(models.PermissionReadAuditEvents) {
 		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
 		return
 	}
 
-	var req models.CreateApplicationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	// For non-admin users, ensure they can only create applications for themselves
+	// Admins see every entity's events
+	var scope []string
 	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
 		userMemberID, err := h.getUserMemberID(r, user)
 		if err != nil {
 			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
 			return
 		}
-
-		// Set the member ID to the authenticated user's member ID
-		req.MemberID = userMemberID
+		scope, err = h.auditEventService.GetMemberScope(r.Context(), userMemberID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 	}
 
-	application, err := h.applicationService.CreateApplication(r.Context(), &req)
+	page, err := h.auditEventService.ListAuditEvents(r.Context(), r.URL.Query(), scope)
 	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeApplications), nil, string(models.AuditStatusFailure))
-
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		switch {
+		case errors.Is(err, services.ErrInvalidAuditEventQuery):
+			respondWithBadRequest(w, err)
+		case errors.Is(err, services.ErrAuditEventScopeDenied):
+			utils.RespondWithError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrAuditServiceUnavailable):
+			slog.Error("Failed to list audit events", "error", err)
+			utils.RespondWithError(w, http.StatusBadGateway, "Audit events are unavailable")
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeApplications), &application.ApplicationID, string(models.AuditStatusSuccess))
-
-	utils.RespondWithSuccess(w, http.StatusCreated, application)
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	utils.RespondWithSuccess(w, http.StatusOK, page)
 }
 
-func (h *V1Handler) updateApplication(w http.ResponseWriter, r *http.Request, applicationId string) {
-	// Get authenticated user
-	user, err := middleware.GetUserFromRequest(r)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+// handleSandboxProviders serves the mock providers of sandbox mode:
+// POST /internal/api/v1/sandbox/providers/:schemaId/graphql
+func (h *V1Handler) handleSandboxProviders(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/internal/api/v1/sandbox/providers")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if h.sandboxService == nil || len(parts) != 2 || parts[0] == "" || parts[1] != "graphql" {
+		utils.RespondWithError(w, http.StatusNotFound, "Endpoint not found")
 		return
 	}
-
-	// Check permission
-	if !user.HasPermission(models.PermissionUpdateApplication) {
-		utils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions")
+	if r.Method != http.MethodPost {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	// Get existing application to check ownership
-	existingApplication, err := h.applicationService.GetApplication(r.Context(), applicationId)
+	var req models.GraphQLRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	response, err := h.sandboxService.ExecuteMockProvider(r.Context(), parts[0], &req)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+		switch {
+		case errors.Is(err, services.ErrResourceNotFound):
+			utils.RespondWithError(w, http.StatusNotFound, "Schema not found")
+		default:
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
 
-	// For non-admin users, check ownership
-	if !user.IsAdmin() {
-		// Get member ID for the authenticated user (cached)
-		userMemberID, err := h.getUserMemberID(r, user)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusForbidden, "User member record not found")
-			return
-		}
+// getApplicationSandbox returns the sandbox of an application
+func (h *V1Handler) getApplicationSandbox(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionReadApplicationCredentials, applicationId); !ok {
+		return
+	}
+	if h.sandboxService == nil {
+		utils.RespondWithError(w, http.StatusNotFound, services.ErrSandboxDisabled.Error())
+		return
+	}
 
-		// Check if application belongs to the user
-		if existingApplication.MemberID != userMemberID {
-			utils.RespondWithError(w, http.StatusForbidden, "Access denied to update this resource")
-			return
-		}
+	sandbox, err := h.sandboxService.GetSandbox(r.Context(), applicationId)
+	if err != nil {
+		respondWithSandboxError(w, err)
+		return
 	}
+	utils.RespondWithSuccess(w, http.StatusOK, sandbox)
+}
 
-	var req models.UpdateApplicationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+// provisionApplicationSandbox provisions the sandbox of an application approved before sandbox mode
+// was enabled, or whose provisioning on approval failed
+func (h *V1Handler) provisionApplicationSandbox(w http.ResponseWriter, r *http.Request, applicationId string) {
+	user, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationCredentials, applicationId)
+	if !ok {
+		return
+	}
+	if h.sandboxService == nil {
+		utils.RespondWithError(w, http.StatusNotFound, services.ErrSandboxDisabled.Error())
 		return
 	}
 
-	application, err := h.applicationService.UpdateApplication(r.Context(), applicationId, &req)
+	sandbox, err := h.sandboxService.ProvisionSandbox(r.Context(), applicationId, user.IdpUserID)
 	if err != nil {
-		// Log audit event for failure
-		middleware.LogAuditEvent(r, string(models.ResourceTypeApplications), &existingApplication.ApplicationID, string(models.AuditStatusFailure))
+		respondWithSandboxError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusCreated, sandbox)
+}
 
-		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+// regenerateSandboxSecret issues a new secret for the sandbox client of an application
+func (h *V1Handler) regenerateSandboxSecret(w http.ResponseWriter, r *http.Request, applicationId string) {
+	if _, ok := h.authorizeApplicationAccess(w, r, models.PermissionManageApplicationCredentials, applicationId); !ok {
+		return
+	}
+	if h.sandboxService == nil {
+		utils.RespondWithError(w, http.StatusNotFound, services.ErrSandboxDisabled.Error())
 		return
 	}
 
-	// Log audit event
-	middleware.LogAuditEvent(r, string(models.ResourceTypeApplications), &application.ApplicationID, string(models.AuditStatusSuccess))
+	sandbox, err := h.sandboxService.RegenerateSandboxSecret(r.Context(), applicationId)
+	if err != nil {
+		respondWithSandboxError(w, err)
+		return
+	}
+	utils.RespondWithSuccess(w, http.StatusOK, sandbox)
+}
 
-	utils.RespondWithSuccess(w, http.StatusOK, application)
+// respondWithSandboxError maps sandbox service errors to HTTP responses
+func respondWithSandboxError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrResourceNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, "Sandbox not found")
+	case errors.Is(err, services.ErrSandboxExists):
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+	default:
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
 }
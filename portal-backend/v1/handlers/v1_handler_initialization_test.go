@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/gov-dx-sandbox/portal-backend/idp/keycloak"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
 	"github.com/gov-dx-sandbox/portal-backend/v1/services"
 	"github.com/stretchr/testify/assert"
@@ -77,6 +78,39 @@ func TestNewV1Handler_MissingEnvVars(t *testing.T) {
 	assert.NotNil(t, handler)
 }
 
+func TestNewIdpProvider(t *testing.T) {
+	t.Run("Keycloak", func(t *testing.T) {
+		t.Setenv("IDP_PROVIDER", "keycloak")
+		t.Setenv("KEYCLOAK_BASE_URL", "https://keycloak.example.com/")
+		t.Setenv("KEYCLOAK_REALM", "opendif")
+		t.Setenv("KEYCLOAK_CLIENT_ID", "client-id")
+		t.Setenv("KEYCLOAK_CLIENT_SECRET", "client-secret")
+
+		provider, err := newIdpProvider()
+		assert.NoError(t, err)
+		assert.IsType(t, &keycloak.Client{}, provider)
+		assert.Equal(t, "https://keycloak.example.com", provider.(*keycloak.Client).BaseURL)
+	})
+
+	t.Run("KeycloakMissingRealm", func(t *testing.T) {
+		t.Setenv("IDP_PROVIDER", "keycloak")
+		t.Setenv("KEYCLOAK_BASE_URL", "https://keycloak.example.com")
+		t.Setenv("KEYCLOAK_REALM", "")
+		t.Setenv("KEYCLOAK_CLIENT_ID", "client-id")
+		t.Setenv("KEYCLOAK_CLIENT_SECRET", "client-secret")
+
+		_, err := newIdpProvider()
+		assert.ErrorContains(t, err, "KEYCLOAK_REALM")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		t.Setenv("IDP_PROVIDER", "okta")
+
+		_, err := newIdpProvider()
+		assert.EqualError(t, err, `failed to create IDP provider: unsupported IDP_PROVIDER "okta"`)
+	})
+}
+
 func TestGetUserMemberID_Caching(t *testing.T) {
 	testHandler := NewTestV1Handler(t)
 
@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -145,6 +150,19 @@ func (m *MockIdentityProviderAPI) GetApplicationOIDC(ctx context.Context, applic
 	return args.Get(0).(*idp.ApplicationOIDCInfo), args.Error(1)
 }
 
+func (m *MockIdentityProviderAPI) RegenerateApplicationSecret(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error) {
+	args := m.Called(ctx, applicationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*idp.ApplicationOIDCInfo), args.Error(1)
+}
+
+func (m *MockIdentityProviderAPI) RevokeApplicationCredentials(ctx context.Context, applicationID string) error {
+	args := m.Called(ctx, applicationID)
+	return args.Error(0)
+}
+
 // TestV1Handler tests the V1 API handler
 type TestV1Handler struct {
 	*testing.T
@@ -184,10 +202,36 @@ func NewTestV1HandlerWithMockPDP(t *testing.T, db *gorm.DB) *V1Handler {
 	// Note: In a real scenario, you'd set up a test HTTP server to handle PDP requests
 	// For now, the tests will need to handle PDP failures gracefully or skip PDP-dependent operations
 
+	schemaService := services.NewSchemaService(db, mockPDP)
+	applicationService := services.NewApplicationService(db, mockPDP, mockIDPStore)
+	reviewService := services.NewSubmissionReviewService(db, schemaService, applicationService, nil, nil, services.DefaultReviewWorkflow)
+	credentialService := services.NewApplicationCredentialService(db, mockIDPStore, 0)
+	approvalService := services.NewApprovalService(db, nil, 24*time.Hour, memberService, reviewService, credentialService)
+
 	return &V1Handler{
-		memberService:      memberService,
-		schemaService:      services.NewSchemaService(db, mockPDP),
-		applicationService: services.NewApplicationService(db, mockPDP, mockIDPStore),
+		memberService:        memberService,
+		schemaService:        schemaService,
+		applicationService:   applicationService,
+		credentialService:    credentialService,
+		reviewService:        reviewService,
+		pdpJobService:        services.NewPDPJobService(db, mockPDP),
+		pdpSyncService:       services.NewPDPSyncService(db, mockPDP),
+		metricsService:       services.NewSubmissionMetricsService(db),
+		notificationService:  services.NewNotificationService(db, nil, nil),
+		invitationService:    services.NewInvitationService(db, memberService, 72*time.Hour),
+		organizationService:  services.NewOrganizationService(db, memberService),
+		usageService:         services.NewUsageService(db, "http://localhost:3001", ""),
+		auditEventService:    services.NewAuditEventService(db, "http://localhost:3001", ""),
+		catalogService:       services.NewCatalogService(db, mockPDP),
+		bulkService:          services.NewBulkService(db, reviewService, approvalService),
+		adminGraphService:    services.NewAdminGraphService(db),
+		webhookService:       services.NewWebhookService(db),
+		impersonationService: services.NewImpersonationService(db, 30*time.Minute),
+		approvalService:      approvalService,
+		savedViewService:     services.NewSavedViewService(db),
+		templateService:      services.NewFieldConfigurationTemplateService(db),
+		exportService:        services.NewExportService(memberService, applicationService, schemaService),
+		attachmentService:    services.NewAttachmentService(db, nil, nil, services.DefaultAttachmentMaxSize, services.DefaultAttachmentURLTTL),
 	}
 }
 
@@ -550,6 +594,7 @@ func TestSchemaEndpoints(t *testing.T) {
 		reqBody, _ := json.Marshal(req)
 		httpReq := NewAdminRequest(http.MethodPut, fmt.Sprintf("/api/v1/schemas/%s", schema.SchemaID), bytes.NewBuffer(reqBody))
 		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		mux := http.NewServeMux()
@@ -599,7 +644,7 @@ func TestSchemaSubmissionEndpoints(t *testing.T) {
 		req := models.CreateSchemaSubmissionRequest{
 			SchemaName:        "Test Schema Submission",
 			SchemaDescription: &desc,
-			SDL:               "type Query { test: String }",
+			SDL:               `type Query { "Test field" test: String }`,
 			SchemaEndpoint:    "http://example.com/graphql",
 			MemberID:          testMemberID,
 		}
@@ -622,6 +667,34 @@ func TestSchemaSubmissionEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("POST /api/v1/schema-submissions - CreateSchemaSubmission_LintErrors", func(t *testing.T) {
+		req := models.CreateSchemaSubmissionRequest{
+			SchemaName:     "Test Schema Submission",
+			SDL:            "type Query { Test: String }",
+			SchemaEndpoint: "http://example.com/graphql",
+			MemberID:       testMemberID,
+		}
+
+		reqBody, _ := json.Marshal(req)
+		httpReq := NewAdminRequest(http.MethodPost, "/api/v1/schema-submissions", bytes.NewBuffer(reqBody))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		mux := http.NewServeMux()
+		testHandler.handler.SetupV1Routes(mux)
+		mux.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response models.SDLValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid SDL", response.Error)
+		if assert.Len(t, response.LintErrors, 2) {
+			assert.Equal(t, models.SDLLintRuleFieldName, response.LintErrors[0].Rule)
+			assert.Equal(t, "Query.Test", response.LintErrors[0].Path)
+			assert.Equal(t, models.SDLLintRuleFieldDescription, response.LintErrors[1].Rule)
+		}
+	})
+
 	t.Run("GET /api/v1/schema-submissions - GetAllSchemaSubmissions", func(t *testing.T) {
 		httpReq := NewAdminRequest(http.MethodGet, "/api/v1/schema-submissions", nil)
 		w := httptest.NewRecorder()
@@ -847,6 +920,7 @@ func TestApplicationEndpoints(t *testing.T) {
 		reqBody, _ := json.Marshal(req)
 		httpReq := NewAdminRequest(http.MethodPut, fmt.Sprintf("/api/v1/applications/%s", applicationID), bytes.NewBuffer(reqBody))
 		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		mux := http.NewServeMux()
@@ -1488,3 +1562,2046 @@ func TestV1Handler_SetupV1Routes(t *testing.T) {
 	handler.SetupV1Routes(mux)
 	assert.NotNil(t, mux)
 }
+
+// TestPDPJobEndpoints tests the PDP sync job admin endpoints
+func TestPDPJobEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	lastError := "PDP returned status 503"
+	deadJob := models.PDPJob{
+		JobID:       "pdpjob_dead",
+		JobType:     models.PDPJobTypeUpdateAllowList,
+		ResourceID:  "app-123",
+		Payload:     `{"applicationId":"app-123"}`,
+		Status:      models.PDPJobStatusDead,
+		Attempts:    8,
+		MaxAttempts: 8,
+		NextRetryAt: time.Now(),
+		LastError:   &lastError,
+	}
+	assert.NoError(t, testHandler.db.Create(&deadJob).Error)
+
+	t.Run("GET /api/v1/admin/pdp-jobs?status=dead", func(t *testing.T) {
+		httpReq := NewAdminRequest(http.MethodGet, "/api/v1/admin/pdp-jobs?status=dead", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Items []models.PDPJobResponse `json:"items"`
+			Count int                     `json:"count"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, 1, response.Count)
+		if assert.Len(t, response.Items, 1) {
+			assert.Equal(t, deadJob.JobID, response.Items[0].JobID)
+			assert.Equal(t, &lastError, response.Items[0].LastError)
+		}
+	})
+
+	t.Run("GET /api/v1/admin/pdp-jobs - Invalid status", func(t *testing.T) {
+		httpReq := NewAdminRequest(http.MethodGet, "/api/v1/admin/pdp-jobs?status=failed", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET /api/v1/admin/pdp-jobs - Member forbidden", func(t *testing.T) {
+		httpReq := NewMemberRequest(http.MethodGet, "/api/v1/admin/pdp-jobs", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("POST /api/v1/admin/pdp-jobs/:id/requeue", func(t *testing.T) {
+		httpReq := NewAdminRequest(http.MethodPost, "/api/v1/admin/pdp-jobs/"+deadJob.JobID+"/requeue", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var job models.PDPJobResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+		assert.Equal(t, models.PDPJobStatusPending, job.Status)
+		assert.Equal(t, 0, job.Attempts)
+
+		// The job is no longer dead
+		httpReq = NewAdminRequest(http.MethodPost, "/api/v1/admin/pdp-jobs/"+deadJob.JobID+"/requeue", nil)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httpReq)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("POST /api/v1/admin/pdp-jobs/:id/requeue - NotFound", func(t *testing.T) {
+		httpReq := NewAdminRequest(http.MethodPost, "/api/v1/admin/pdp-jobs/missing/requeue", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("GET /api/v1/admin/pdp-jobs/:id/requeue - Method not allowed", func(t *testing.T) {
+		httpReq := NewAdminRequest(http.MethodGet, "/api/v1/admin/pdp-jobs/"+deadJob.JobID+"/requeue", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestSoftDeleteEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	member := models.Member{MemberID: "mem_soft", Name: "Soft", Email: "soft@example.com", PhoneNumber: "1", IdpUserID: "idp-soft"}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	schema := models.Schema{SchemaID: "sch_soft", MemberID: member.MemberID, SchemaName: "Soft", SDL: "type Query { a: String }", Endpoint: "http://soft", Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&schema).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("DELETE /api/v1/schemas/:id - Member forbidden", func(t *testing.T) {
+		w := serve(NewMemberRequest(http.MethodDelete, "/api/v1/schemas/"+schema.SchemaID, nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("DELETE /api/v1/schemas/:id", func(t *testing.T) {
+		w := serve(NewAdminRequest(http.MethodDelete, "/api/v1/schemas/"+schema.SchemaID, nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodGet, "/api/v1/schemas/"+schema.SchemaID, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/schemas/"+schema.SchemaID, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("POST /api/v1/schemas/:id/restore", func(t *testing.T) {
+		w := serve(NewMemberRequest(http.MethodPost, "/api/v1/schemas/"+schema.SchemaID+"/restore", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodPost, "/api/v1/schemas/"+schema.SchemaID+"/restore", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var restored models.SchemaResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&restored))
+		assert.Equal(t, schema.SchemaID, restored.SchemaID)
+
+		w = serve(NewAdminRequest(http.MethodPost, "/api/v1/schemas/"+schema.SchemaID+"/restore", nil))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("DELETE /api/v1/members/:id cascades and restores", func(t *testing.T) {
+		w := serve(NewAdminRequest(http.MethodDelete, "/api/v1/members/"+member.MemberID, nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodGet, "/api/v1/schemas/"+schema.SchemaID, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodPost, "/api/v1/members/"+member.MemberID+"/restore", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodGet, "/api/v1/schemas/"+schema.SchemaID, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestApplicationCredentialEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-cred-owner", "owner@example.com", []models.Role{models.RoleMember})
+	stranger := CreateCustomTestUser("idp-cred-stranger", "stranger@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_cred", Name: "Owner", Email: "owner@example.com", PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	idpAppID := "idp-app-cred"
+	application := models.Application{
+		ApplicationID:    "app_cred",
+		ApplicationName:  "Credential App",
+		MemberID:         member.MemberID,
+		IdpApplicationID: &idpAppID,
+		Version:          string(models.ActiveVersion),
+	}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+
+	mockIDPStore.On("RegenerateApplicationSecret", mock.Anything, idpAppID).
+		Return(&idp.ApplicationOIDCInfo{ClientId: "client-cred", ClientSecret: "secret-abcd"}, nil).Once()
+	mockIDPStore.On("RegenerateApplicationSecret", mock.Anything, idpAppID).
+		Return(&idp.ApplicationOIDCInfo{ClientId: "client-cred", ClientSecret: "secret-wxyz"}, nil).Once()
+	mockIDPStore.On("RevokeApplicationCredentials", mock.Anything, idpAppID).Return(nil)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	basePath := "/api/v1/applications/" + application.ApplicationID + "/credentials"
+
+	var issued models.ApplicationCredentialResponse
+	t.Run("POST generates a credential for the owner", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodPost, basePath, nil, stranger))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPost, basePath, nil, owner))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&issued))
+		assert.Equal(t, "secret-abcd", issued.ClientSecret)
+		assert.Equal(t, "abcd", issued.SecretHint)
+		assert.Equal(t, models.ApplicationCredentialStatusActive, issued.Status)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPost, basePath, nil, owner))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	var rotated models.ApplicationCredentialResponse
+	t.Run("POST rotate replaces the credential", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodPost, basePath+"/"+issued.CredentialID+"/rotate", nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&rotated))
+		assert.Equal(t, "secret-wxyz", rotated.ClientSecret)
+		assert.NotEqual(t, issued.CredentialID, rotated.CredentialID)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPost, basePath+"/"+issued.CredentialID+"/rotate", nil, owner))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("GET lists credentials without secrets", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodGet, basePath, nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "clientSecret")
+
+		var response struct {
+			Items []models.ApplicationCredentialResponse `json:"items"`
+			Count int                                    `json:"count"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, 2, response.Count)
+		statuses := map[string]models.ApplicationCredentialStatus{}
+		for _, credential := range response.Items {
+			statuses[credential.CredentialID] = credential.Status
+		}
+		assert.Equal(t, models.ApplicationCredentialStatusRotated, statuses[issued.CredentialID])
+		assert.Equal(t, models.ApplicationCredentialStatusActive, statuses[rotated.CredentialID])
+	})
+
+	t.Run("DELETE revokes the credential", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodDelete, basePath+"/"+rotated.CredentialID, nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var revoked models.ApplicationCredentialResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&revoked))
+		assert.Equal(t, models.ApplicationCredentialStatusRevoked, revoked.Status)
+		assert.NotNil(t, revoked.RevokedAt)
+
+		w = serve(NewAuthenticatedRequest(http.MethodDelete, basePath+"/"+rotated.CredentialID, nil, owner))
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodDelete, basePath+"/cred_missing", nil, owner))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	mockIDPStore.AssertExpectations(t)
+}
+
+func TestSubmissionReviewEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-review-owner", "review-owner@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_review", Name: "Owner", Email: "review-owner@example.com", PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	submission := models.ApplicationSubmission{
+		SubmissionID:    "sub_review",
+		ApplicationName: "Review App",
+		SelectedFields:  models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_1"}},
+		MemberID:        member.MemberID,
+		Status:          string(models.StatusPending),
+	}
+	assert.NoError(t, testHandler.db.Create(&submission).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	jsonBody := func(v interface{}) *bytes.Buffer {
+		body, _ := json.Marshal(v)
+		return bytes.NewBuffer(body)
+	}
+	basePath := "/api/v1/application-submissions/" + submission.SubmissionID
+
+	t.Run("PUT status approved requires review", func(t *testing.T) {
+		status := string(models.StatusApproved)
+		w := serve(NewAdminRequest(http.MethodPut, basePath, jsonBody(models.UpdateApplicationSubmissionRequest{Status: &status})))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("POST review decisions", func(t *testing.T) {
+		decision := models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove}
+		w := serve(NewAuthenticatedRequest(http.MethodPost, basePath+"/review/decisions", jsonBody(decision), owner))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodPost, basePath+"/review/decisions", jsonBody(map[string]string{"decision": "maybe"})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodPost, basePath+"/review/decisions", jsonBody(decision)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var review models.SubmissionReviewResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&review))
+		assert.Equal(t, "security_review", review.Status)
+	})
+
+	t.Run("PUT step reviewers", func(t *testing.T) {
+		w := serve(NewAdminRequest(http.MethodPut, basePath+"/review/steps/unknown/reviewers",
+			jsonBody(models.AssignSubmissionReviewersRequest{})))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodPut, basePath+"/review/steps/security_review/reviewers",
+			jsonBody(models.AssignSubmissionReviewersRequest{ReviewerIDs: []string{"security-admin"}})))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// The default admin is not assigned to the security review
+		w = serve(NewAdminRequest(http.MethodPost, basePath+"/review/decisions",
+			jsonBody(models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove})))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Failed final approval keeps the submission in review", func(t *testing.T) {
+		mockIDPStore.On("CreateApplication", mock.Anything, mock.Anything).Return(nil, errors.New("idp unavailable")).Once()
+		securityAdmin := CreateCustomTestUser("security-admin", "security-admin@example.com", []models.Role{models.RoleAdmin})
+
+		w := serve(NewAuthenticatedRequest(http.MethodPost, basePath+"/review/decisions",
+			jsonBody(models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove}), securityAdmin))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockIDPStore.AssertExpectations(t)
+	})
+
+	t.Run("GET review for the owner", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodGet, basePath+"/review", nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var review models.SubmissionReviewResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&review))
+		assert.Equal(t, "security_review", review.Status)
+		assert.Len(t, review.Steps, len(services.DefaultReviewWorkflow))
+		assert.Equal(t, []string{"security-admin"}, review.Steps[1].Reviewers)
+		assert.Equal(t, 0, review.Steps[1].Approvals)
+	})
+
+	t.Run("Comments thread", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodPost, basePath+"/comments", jsonBody(models.CreateSubmissionCommentRequest{Body: " "}), owner))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPost, basePath+"/comments", jsonBody(models.CreateSubmissionCommentRequest{Body: "Ready for security review"}), owner))
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodGet, basePath+"/comments", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Items []models.SubmissionCommentResponse `json:"items"`
+			Count int                                `json:"count"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, 1, response.Count)
+		assert.Equal(t, owner.IdpUserID, response.Items[0].AuthorID)
+	})
+}
+
+func TestNotificationEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-notified-owner", "notified-owner@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_notified", Name: "Owner", Email: "notified-owner@example.com", PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	submission := models.ApplicationSubmission{
+		SubmissionID:    "sub_notified",
+		ApplicationName: "Notified App",
+		SelectedFields:  models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_1"}},
+		MemberID:        member.MemberID,
+		Status:          string(models.StatusPending),
+	}
+	assert.NoError(t, testHandler.db.Create(&submission).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	type notificationList struct {
+		Items []models.NotificationResponse `json:"items"`
+		Count int                           `json:"count"`
+	}
+
+	// Rejecting the submission notifies its owner
+	status := string(models.StatusRejected)
+	body, _ := json.Marshal(models.UpdateApplicationSubmissionRequest{Status: &status})
+	w := serve(NewAdminRequest(http.MethodPut, "/api/v1/application-submissions/"+submission.SubmissionID, bytes.NewBuffer(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var notifications notificationList
+	t.Run("GET notifications", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/notifications?unread=true", nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&notifications))
+		assert.Equal(t, 1, notifications.Count)
+		assert.Equal(t, models.NotificationEventSubmissionStatusChanged, notifications.Items[0].Event)
+		assert.Equal(t, submission.SubmissionID, notifications.Items[0].ResourceID)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/notifications?unread=maybe", nil, owner))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// The default admin has no member record and so no notifications
+		w = serve(NewAdminRequest(http.MethodGet, "/api/v1/notifications", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("POST notification read", func(t *testing.T) {
+		if len(notifications.Items) == 0 {
+			t.Skip("no notification to read")
+		}
+		w := serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/notifications/"+notifications.Items[0].NotificationID+"/read", nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/notifications/ntf_missing/read", nil, owner))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/notifications?unread=true", nil, owner))
+		var unread notificationList
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&unread))
+		assert.Equal(t, 0, unread.Count)
+	})
+
+	t.Run("Notification preferences", func(t *testing.T) {
+		body, _ := json.Marshal(models.UpdateNotificationPreferencesRequest{Preferences: []models.NotificationPreferenceSetting{
+			{Event: models.NotificationEventCredentialExpiring, InApp: true, Email: false},
+		}})
+		w := serve(NewAuthenticatedRequest(http.MethodPut, "/api/v1/notifications/preferences", bytes.NewBuffer(body), owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/notifications/preferences", nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var preferences struct {
+			Items []models.NotificationPreferenceSetting `json:"items"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&preferences))
+		assert.Contains(t, preferences.Items, models.NotificationPreferenceSetting{Event: models.NotificationEventCredentialExpiring, InApp: true, Email: false})
+
+		body, _ = json.Marshal(models.UpdateNotificationPreferencesRequest{Preferences: []models.NotificationPreferenceSetting{{Event: "unknown"}}})
+		w = serve(NewAuthenticatedRequest(http.MethodPut, "/api/v1/notifications/preferences", bytes.NewBuffer(body), owner))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestInvitationEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	testHandler.handler.SetupPublicRoutes(mux)
+	setupMockIDPForMemberCreation("invitee@example.com", "idp-invitee")
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	var invitation models.InvitationResponse
+	t.Run("POST invitations", func(t *testing.T) {
+		body, _ := json.Marshal(models.CreateInvitationRequest{Email: "invitee@example.com", Role: models.RoleMember})
+		w := serve(NewAdminRequest(http.MethodPost, "/api/v1/invitations", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&invitation))
+		assert.NotEmpty(t, invitation.Token)
+		assert.Equal(t, models.InvitationStatusPending, invitation.Status)
+
+		w = serve(NewAdminRequest(http.MethodPost, "/api/v1/invitations", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		body, _ = json.Marshal(models.CreateInvitationRequest{Email: "system@example.com", Role: models.RoleSystem})
+		w = serve(NewAdminRequest(http.MethodPost, "/api/v1/invitations", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		member := CreateCustomTestUser("idp-not-admin", "not-admin@example.com", []models.Role{models.RoleMember})
+		w = serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/invitations", bytes.NewBuffer(body), member))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("GET invitations", func(t *testing.T) {
+		w := serve(NewAdminRequest(http.MethodGet, "/api/v1/invitations", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var list struct {
+			Items []models.InvitationResponse `json:"items"`
+			Count int                         `json:"count"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+		assert.Equal(t, 1, list.Count)
+		assert.Empty(t, list.Items[0].Token)
+	})
+
+	t.Run("POST invitations accept", func(t *testing.T) {
+		body, _ := json.Marshal(models.AcceptInvitationRequest{Token: "wrong", Name: "Invitee", PhoneNumber: "1234567890"})
+		w := serve(httptest.NewRequest(http.MethodPost, "/api/v1/invitations/accept", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		body, _ = json.Marshal(models.AcceptInvitationRequest{Token: invitation.Token})
+		w = serve(httptest.NewRequest(http.MethodPost, "/api/v1/invitations/accept", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// The invitee has no JWT; the token authenticates them
+		body, _ = json.Marshal(models.AcceptInvitationRequest{Token: invitation.Token, Name: "Invitee", PhoneNumber: "1234567890"})
+		w = serve(httptest.NewRequest(http.MethodPost, "/api/v1/invitations/accept", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var member models.MemberResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&member))
+		assert.Equal(t, "invitee@example.com", member.Email)
+		assert.Equal(t, "idp-invitee", member.IdpUserID)
+
+		w = serve(httptest.NewRequest(http.MethodPost, "/api/v1/invitations/accept", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("DELETE invitation", func(t *testing.T) {
+		body, _ := json.Marshal(models.CreateInvitationRequest{Email: "withdrawn@example.com", Role: models.RoleAdmin})
+		w := serve(NewAdminRequest(http.MethodPost, "/api/v1/invitations", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var withdrawn models.InvitationResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&withdrawn))
+
+		w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/invitations/"+withdrawn.InvitationID, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/invitations/"+invitation.InvitationID, nil))
+		assert.Equal(t, http.StatusConflict, w.Code, "accepted invitations cannot be revoked")
+
+		w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/invitations/inv_missing", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestOrganizationEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	orgAdmin := CreateCustomTestUser("idp-org-admin", "org-admin@example.com", []models.Role{models.RoleMember})
+	colleague := CreateCustomTestUser("idp-org-colleague", "org-colleague@example.com", []models.Role{models.RoleMember})
+	outsider := CreateCustomTestUser("idp-org-outsider", "org-outsider@example.com", []models.Role{models.RoleMember})
+	for _, member := range []models.Member{
+		{MemberID: "mem_org_admin", Name: "Org Admin", Email: orgAdmin.Email, PhoneNumber: "1", IdpUserID: orgAdmin.IdpUserID},
+		{MemberID: "mem_org_colleague", Name: "Colleague", Email: colleague.Email, PhoneNumber: "2", IdpUserID: colleague.IdpUserID},
+		{MemberID: "mem_org_outsider", Name: "Outsider", Email: outsider.Email, PhoneNumber: "3", IdpUserID: outsider.IdpUserID},
+	} {
+		assert.NoError(t, testHandler.db.Create(&member).Error)
+	}
+	schema := models.Schema{SchemaID: "sch_org", MemberID: "mem_org_admin", SchemaName: "Births", SDL: "type Query { name: String }", Endpoint: "http://registrar", Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&schema).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	var organization models.OrganizationResponse
+	t.Run("POST organizations", func(t *testing.T) {
+		body, _ := json.Marshal(models.CreateOrganizationRequest{Name: "Registrar General"})
+		w := serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/organizations", bytes.NewBuffer(body), orgAdmin))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(NewAdminRequest(http.MethodPost, "/api/v1/organizations", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&organization))
+
+		w = serve(NewAdminRequest(http.MethodPost, "/api/v1/organizations", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	membersPath := "/api/v1/organizations/" + organization.OrganizationID + "/members/"
+	t.Run("PUT organization members", func(t *testing.T) {
+		body, _ := json.Marshal(models.SetOrganizationMemberRequest{Role: models.OrganizationRoleAdmin})
+		w := serve(NewAuthenticatedRequest(http.MethodPut, membersPath+"mem_org_admin", bytes.NewBuffer(body), orgAdmin))
+		assert.Equal(t, http.StatusForbidden, w.Code, "not yet a member of the organization")
+
+		w = serve(NewAdminRequest(http.MethodPut, membersPath+"mem_org_admin", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// The org_admin now manages the membership of their organization
+		body, _ = json.Marshal(models.SetOrganizationMemberRequest{Role: models.OrganizationRoleMember})
+		w = serve(NewAuthenticatedRequest(http.MethodPut, membersPath+"mem_org_colleague", bytes.NewBuffer(body), orgAdmin))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPut, membersPath+"mem_org_outsider", bytes.NewBuffer(body), colleague))
+		assert.Equal(t, http.StatusForbidden, w.Code, "org_member cannot manage membership")
+
+		body, _ = json.Marshal(models.SetOrganizationMemberRequest{Role: "owner"})
+		w = serve(NewAdminRequest(http.MethodPut, membersPath+"mem_org_colleague", bytes.NewBuffer(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET organizations", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/organizations", nil, colleague))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var list struct {
+			Items []models.OrganizationResponse `json:"items"`
+			Count int                           `json:"count"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+		assert.Equal(t, 1, list.Count)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/organizations/"+organization.OrganizationID, nil, outsider))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/organizations/"+organization.OrganizationID+"/members", nil, colleague))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Colleagues share ownership", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/schemas/"+schema.SchemaID, nil, colleague))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/schemas", nil, colleague))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var list struct {
+			Items []models.SchemaResponse `json:"items"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+		assert.Len(t, list.Items, 1)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/schemas/"+schema.SchemaID, nil, outsider))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("DELETE organization member", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodDelete, membersPath+"mem_org_admin", nil, orgAdmin))
+		assert.Equal(t, http.StatusConflict, w.Code, "the last org_admin cannot leave")
+
+		w = serve(NewAuthenticatedRequest(http.MethodDelete, membersPath+"mem_org_colleague", nil, orgAdmin))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/schemas/"+schema.SchemaID, nil, colleague))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestApplicationUsageEndpoint(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	auditService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "app_usage", r.URL.Query().Get("consumerAppId"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"totals": {"events": 4, "failures": 1}, "groups": [{"key": "DATA_REQUEST", "events": 1}], "topFields": [{"field": "person.name", "count": 1}]}`))
+	}))
+	defer auditService.Close()
+	testHandler.handler.usageService = services.NewUsageService(testHandler.db, auditService.URL, "")
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-usage-owner", "usage-owner@example.com", []models.Role{models.RoleMember})
+	stranger := CreateCustomTestUser("idp-usage-stranger", "usage-stranger@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_usage", Name: "Owner", Email: owner.Email, PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	application := models.Application{ApplicationID: "app_usage", ApplicationName: "Usage App", MemberID: member.MemberID, Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	usagePath := "/api/v1/applications/" + application.ApplicationID + "/usage"
+
+	w := serve(NewAuthenticatedRequest(http.MethodGet, usagePath, nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var usage models.ApplicationUsageResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&usage))
+	assert.Equal(t, int64(1), usage.Requests)
+	assert.Equal(t, 0.25, usage.ErrorRate)
+	assert.Len(t, usage.Fields, 1)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, usagePath, nil, stranger))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, usagePath+"?startTime=yesterday", nil, owner))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/applications/app_missing/usage", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAuditEventsEndpoint(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	var tenantIDs []string
+	auditService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/audit-logs", r.URL.Path)
+		assert.Equal(t, "DATA_REQUEST", r.URL.Query().Get("eventType"))
+		tenantIDs = r.URL.Query()["tenantId"]
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"logs": [{"id": "log-1", "eventType": "DATA_REQUEST"}], "total": 1, "limit": 100, "offset": 0}`))
+	}))
+	defer auditService.Close()
+	testHandler.handler.auditEventService = services.NewAuditEventService(testHandler.db, auditService.URL, "")
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-audit-owner", "audit-owner@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_audit", Name: "Owner", Email: owner.Email, PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	application := models.Application{ApplicationID: "app_audit", ApplicationName: "Audit App", MemberID: member.MemberID, Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// A member's events are confined to their entities, even when they ask for another tenant
+	w := serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/audit-events?eventType=DATA_REQUEST", nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+	var page models.AuditEventPage
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+	assert.Len(t, page.Events, 1)
+	assert.ElementsMatch(t, []string{"app_audit", "idp-audit-owner", "mem_audit"}, tenantIDs)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/audit-events?eventType=DATA_REQUEST&tenantId=app_audit", nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"app_audit"}, tenantIDs)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/audit-events?eventType=DATA_REQUEST&tenantId=app_other", nil, owner))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Admins see every tenant
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/audit-events?eventType=DATA_REQUEST", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, tenantIDs)
+
+	w = serve(NewSystemRequest(http.MethodGet, "/api/v1/audit-events", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// sessionIdentityProvider is a mock identity provider that also exposes user sessions
+type sessionIdentityProvider struct {
+	*MockIdentityProviderAPI
+}
+
+func (m sessionIdentityProvider) GetUserSessions(ctx context.Context, userID string) ([]idp.UserSession, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]idp.UserSession), args.Error(1)
+}
+
+func (m sessionIdentityProvider) RevokeUserSessions(ctx context.Context, userID string) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func TestMemberSessionEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	provider := sessionIdentityProvider{mockIDPStore}
+	testHandler.handler.memberService = services.NewMemberService(testHandler.db, provider)
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-session-owner", "session-owner@example.com", []models.Role{models.RoleMember})
+	assert.NoError(t, testHandler.db.Create(&models.Member{MemberID: "mem_sessions", Name: "Owner", Email: owner.Email, PhoneNumber: "1", IdpUserID: owner.IdpUserID}).Error)
+	other := CreateCustomTestUser("idp-session-other", "session-other@example.com", []models.Role{models.RoleMember})
+	assert.NoError(t, testHandler.db.Create(&models.Member{MemberID: "mem_sessions_other", Name: "Other", Email: other.Email, PhoneNumber: "2", IdpUserID: other.IdpUserID}).Error)
+
+	login := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	provider.On("GetUserSessions", mock.Anything, owner.IdpUserID).Return([]idp.UserSession{
+		{Id: "session-1", LoginTime: login, LastAccessTime: login.Add(time.Minute), IPAddress: "10.0.0.1", Applications: []string{"Member Portal"}},
+	}, nil)
+	provider.On("RevokeUserSessions", mock.Anything, owner.IdpUserID).Return(nil).Once()
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// A member lists their own sessions
+	w := serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/members/mem_sessions/sessions", nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var sessions models.MemberSessionsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	assert.Equal(t, 1, sessions.Count)
+	assert.Equal(t, "session-1", sessions.Sessions[0].SessionID)
+	assert.Equal(t, "2026-10-01T08:00:00Z", *sessions.Sessions[0].LoginTime)
+
+	// but not another member's
+	w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/members/mem_sessions/sessions", nil, other))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(NewAuthenticatedRequest(http.MethodDelete, "/api/v1/members/mem_sessions/sessions", nil, other))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Admins revoke any member's sessions
+	w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/members/mem_sessions/sessions", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var revoked models.RevokeMemberSessionsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&revoked))
+	assert.Equal(t, 1, revoked.RevokedSessions)
+	provider.AssertCalled(t, "RevokeUserSessions", mock.Anything, owner.IdpUserID)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/members/mem_missing/sessions", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Providers without sessions answer 501
+	testHandler.handler.memberService = services.NewMemberService(testHandler.db, mockIDPStore)
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/members/mem_sessions/sessions", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestMemberSecurityEventsEndpoint(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	var received url.Values
+	auditService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"logs": [{"id": "log-1", "eventType": "SECURITY_EVENT", "eventAction": "LOGIN"}], "total": 1, "limit": 100, "offset": 0}`))
+	}))
+	defer auditService.Close()
+	testHandler.handler.auditEventService = services.NewAuditEventService(testHandler.db, auditService.URL, "")
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-security-owner", "security-owner@example.com", []models.Role{models.RoleMember})
+	assert.NoError(t, testHandler.db.Create(&models.Member{MemberID: "mem_security", Name: "Owner", Email: owner.Email, PhoneNumber: "1", IdpUserID: owner.IdpUserID}).Error)
+	other := CreateCustomTestUser("idp-security-other", "security-other@example.com", []models.Role{models.RoleMember})
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/members/mem_security/security-events?eventAction=LOGIN", nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, models.SecurityEventType, received.Get("eventType"))
+	assert.Equal(t, "LOGIN", received.Get("eventAction"))
+	assert.ElementsMatch(t, []string{"idp-security-owner", "mem_security"}, received["tenantId"])
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/members/mem_security/security-events", nil, other))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/members/mem_security/security-events", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.ElementsMatch(t, []string{"idp-security-owner", "mem_security"}, received["tenantId"], "admins see the member's events only")
+}
+
+func TestApplicationQuotaEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-quota-owner", "quota-owner@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_quota", Name: "Owner", Email: owner.Email, PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	application := models.Application{ApplicationID: "app_quota", ApplicationName: "Quota App", MemberID: member.MemberID, Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	quotaPath := "/api/v1/applications/" + application.ApplicationID + "/quota"
+
+	// Only admins set quotas
+	body := `{"dailyRecordQuota": 100, "monthlyRecordQuota": 2000}`
+	w := serve(NewAuthenticatedRequest(http.MethodPut, quotaPath, bytes.NewBufferString(body), owner))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPut, quotaPath, bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, quotaPath, nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var quota models.ApplicationQuota
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&quota))
+	if assert.NotNil(t, quota.DailyRecordQuota) && assert.NotNil(t, quota.MonthlyRecordQuota) {
+		assert.Equal(t, int64(100), *quota.DailyRecordQuota)
+		assert.Equal(t, int64(2000), *quota.MonthlyRecordQuota)
+	}
+
+	// The orchestration engine reads the quota from the internal API
+	w = serve(httptest.NewRequest(http.MethodGet, "/internal/api/v1/applications/"+application.ApplicationID+"/quota", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"monthlyRecordQuota":2000`)
+	w = serve(httptest.NewRequest(http.MethodGet, "/internal/api/v1/applications/app_missing/quota", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPut, quotaPath, bytes.NewBufferString(`{"dailyRecordQuota": 5000, "monthlyRecordQuota": 2000}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "dailyRecordQuota")
+
+	// Omitted quotas are unlimited
+	w = serve(NewAdminRequest(http.MethodPut, quotaPath, bytes.NewBufferString(`{"monthlyRecordQuota": 2000}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"dailyRecordQuota":null`)
+
+	w = serve(NewAdminRequest(http.MethodPut, "/api/v1/applications/app_missing/quota", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestApplicationStatusEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	owner := CreateCustomTestUser("idp-status-owner", "status-owner@example.com", []models.Role{models.RoleMember})
+	sector := "banking"
+	organization := models.Organization{OrganizationID: "org_status", Name: "Status Bank", Sector: &sector}
+	assert.NoError(t, testHandler.db.Create(&organization).Error)
+	member := models.Member{MemberID: "mem_status", Name: "Owner", Email: owner.Email, PhoneNumber: "1", IdpUserID: owner.IdpUserID,
+		OrganizationID: &organization.OrganizationID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	clientID := "client_status"
+	application := models.Application{ApplicationID: "app_status", ApplicationName: "Status App", MemberID: member.MemberID,
+		OrganizationID: &organization.OrganizationID, IdpClientID: &clientID, Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+	sandbox := models.ApplicationSandbox{ApplicationID: application.ApplicationID, IdpApplicationID: "idp_sandbox", IdpClientID: "client_status_sandbox", ProvisionedBy: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&sandbox).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	contextPath := "/internal/api/v1/applications/" + application.ApplicationID + "/context"
+	statusPath := "/api/v1/applications/" + application.ApplicationID + "/status"
+
+	// The orchestration engine reads the context of the application behind a token
+	w := serve(httptest.NewRequest(http.MethodGet, contextPath+"?idpClientId="+clientID, nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var applicationContext models.ApplicationContext
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&applicationContext))
+	assert.Equal(t, "Status App", applicationContext.ApplicationName)
+	assert.Equal(t, "Owner", applicationContext.MemberName)
+	if assert.NotNil(t, applicationContext.Sector) && assert.NotNil(t, applicationContext.OrganizationName) {
+		assert.Equal(t, "banking", *applicationContext.Sector)
+		assert.Equal(t, "Status Bank", *applicationContext.OrganizationName)
+	}
+	assert.Equal(t, models.EnvironmentProduction, applicationContext.Environment)
+	assert.Equal(t, models.ApplicationStatusActive, applicationContext.Status)
+
+	w = serve(httptest.NewRequest(http.MethodGet, contextPath+"?idpClientId="+sandbox.IdpClientID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"environment":"sandbox"`)
+	w = serve(httptest.NewRequest(http.MethodGet, "/internal/api/v1/applications/app_missing/context", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Only admins suspend applications
+	body := `{"status": "suspended", "reason": "Data sharing agreement under review"}`
+	w = serve(NewAuthenticatedRequest(http.MethodPut, statusPath, bytes.NewBufferString(body), owner))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPut, statusPath, bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"suspended"`)
+
+	w = serve(httptest.NewRequest(http.MethodGet, contextPath, nil))
+	assert.Contains(t, w.Body.String(), `"status":"suspended"`)
+	assert.Contains(t, w.Body.String(), `"statusReason":"Data sharing agreement under review"`)
+
+	w = serve(NewAdminRequest(http.MethodPut, statusPath, bytes.NewBufferString(`{"status": "paused"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Reactivating clears the reason
+	w = serve(NewAdminRequest(http.MethodPut, statusPath, bytes.NewBufferString(`{"status": "active"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "statusReason")
+
+	w = serve(NewAdminRequest(http.MethodPut, "/api/v1/applications/app_missing/status", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCatalogFieldsEndpoint(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	pdp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/policy/classifications" {
+			_, _ = w.Write([]byte(`{"records": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"version": "1", "schemas": [{"schemaId": "sch_catalog", "fields": [
+			{"fieldName": "person.name", "source": "primary", "isOwner": false, "accessControlType": "public"},
+			{"fieldName": "person.nic", "source": "primary", "isOwner": false, "accessControlType": "restricted"}
+		]}]}`))
+	}))
+	defer pdp.Close()
+	testHandler.handler.catalogService = services.NewCatalogService(testHandler.db, services.NewPDPService(pdp.URL, ""))
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+
+	provider := models.Member{MemberID: "mem_catalog", Name: "Registrar", Email: "catalog@example.com", PhoneNumber: "1", IdpUserID: "idp-catalog"}
+	assert.NoError(t, testHandler.db.Create(&provider).Error)
+	schema := models.Schema{SchemaID: "sch_catalog", MemberID: provider.MemberID, SchemaName: "Person", SDL: "type Query { name: String }", Endpoint: "http://provider", Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&schema).Error)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	member := CreateCustomTestUser("idp-catalog-reader", "catalog-reader@example.com", []models.Role{models.RoleMember})
+
+	w := serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/catalog/fields?consentRequired=true", nil, member))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.CatalogFieldsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	if assert.Len(t, response.Items, 1) {
+		assert.Equal(t, "person.nic", response.Items[0].FieldName)
+		assert.Equal(t, "Registrar", response.Items[0].ProviderName)
+	}
+	assert.Len(t, response.Facets.ConsentRequired, 2)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/catalog/fields?consentRequired=maybe", nil, member))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/catalog/fields?sort=sdl", nil, member))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/catalog/fields", nil, member))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	pdp.Close()
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/catalog/fields", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+func TestSubmissionDraftEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	owner := CreateCustomTestUser("idp-draft-owner", "draft-owner@example.com", []models.Role{models.RoleMember})
+	stranger := CreateCustomTestUser("idp-draft-stranger", "draft-stranger@example.com", []models.Role{models.RoleMember})
+	for _, user := range []TestUser{owner, stranger} {
+		member := models.Member{MemberID: "mem_" + user.IdpUserID, Name: user.Email, Email: user.Email, PhoneNumber: "1", IdpUserID: user.IdpUserID}
+		assert.NoError(t, testHandler.db.Create(&member).Error)
+	}
+
+	// An incomplete draft is saved, then submitted once it is complete
+	w := serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/application-submissions",
+		bytes.NewBufferString(`{"applicationName": "Draft App", "status": "draft"}`), owner))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var draft models.ApplicationSubmissionResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&draft))
+	assert.Equal(t, string(models.StatusDraft), draft.Status)
+	submitPath := "/api/v1/application-submissions/" + draft.SubmissionID + "/submit"
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, "/api/v1/application-submissions?status=draft", nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), draft.SubmissionID)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, submitPath, nil, owner))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPut, "/api/v1/application-submissions/"+draft.SubmissionID,
+		bytes.NewBufferString(`{"selectedFields": [{"fieldName": "person.name", "schemaId": "sch_1"}]}`), owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, submitPath, nil, stranger))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, submitPath, nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, submitPath, nil, owner))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/schema-submissions/sub_missing/submit", nil, owner))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOptimisticLockingEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	member := models.Member{MemberID: "mem_locking", Name: "Owner", Email: "locking@example.com", PhoneNumber: "1", IdpUserID: "idp-locking"}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	application := models.Application{ApplicationID: "app_locking", ApplicationName: "Locking App", MemberID: member.MemberID, Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+	path := "/api/v1/applications/" + application.ApplicationID
+
+	w := serve(NewAdminRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	// Updates must name the revision they are based on
+	w = serve(NewAdminRequest(http.MethodPut, path, bytes.NewBufferString(`{"applicationName": "Admin Edit"}`)))
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+	req := NewAdminRequest(http.MethodPut, path, bytes.NewBufferString(`{"applicationName": "Admin Edit"}`))
+	req.Header.Set("If-Match", etag)
+	w = serve(req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	// A second edit based on the same revision gets the current state instead of overwriting it
+	w = serve(NewAdminRequest(http.MethodPut, path, bytes.NewBufferString(`{"applicationName": "Member Edit", "revision": 1}`)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var conflict struct {
+		Error   string                     `json:"error"`
+		Current models.ApplicationResponse `json:"current"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&conflict))
+	assert.Equal(t, "Admin Edit", conflict.Current.ApplicationName)
+	assert.Equal(t, int64(2), conflict.Current.Revision)
+
+	req = NewAdminRequest(http.MethodPut, path, bytes.NewBufferString(`{"applicationName": "Member Edit"}`))
+	req.Header.Set("If-Match", "not-an-etag")
+	w = serve(req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBulkOperationEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	member := models.Member{MemberID: "mem_bulk", Name: "Owner", Email: "bulk@example.com", PhoneNumber: "1", IdpUserID: "idp-bulk"}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	application := models.Application{ApplicationID: "app_bulk", ApplicationName: "Bulk App", MemberID: member.MemberID, Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+	submission := models.SchemaSubmission{SubmissionID: "sub_bulk", SchemaName: "Bulk", SDL: "type Query { a: String }", SchemaEndpoint: "http://bulk", Status: string(models.StatusPending), MemberID: member.MemberID}
+	assert.NoError(t, testHandler.db.Create(&submission).Error)
+
+	body := `{"applicationIds": ["app_bulk", "app_missing"]}`
+	owner := CreateCustomTestUser(member.IdpUserID, member.Email, []models.Role{models.RoleMember})
+	w := serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/admin/bulk/expire-applications", bytes.NewBufferString(body), owner))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Items succeed or fail on their own
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/bulk/expire-applications", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.BulkOperationResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, 1, response.Failed)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/bulk/review-submissions",
+		bytes.NewBufferString(`{"submissions": [{"type": "schema-submission", "id": "sub_bulk"}], "decision": "reject", "comment": "Backlog cleanup"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"rejected"`)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/bulk/review-submissions", bytes.NewBufferString(`{"submissions": []}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/bulk/reassign", bytes.NewBufferString(`{"fromMemberId": "mem_bulk", "toMemberId": "mem_missing"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/bulk/delete-everything", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/bulk/reassign", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestAdminGraphEndpoint(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	member := models.Member{MemberID: "mem_graph", Name: "Graph Owner", Email: "graph@example.com", PhoneNumber: "1", IdpUserID: "idp-graph"}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	schema := models.Schema{SchemaID: "sch_graph", MemberID: member.MemberID, SchemaName: "Graph", SDL: "type Query { a: String }", Endpoint: "http://graph", Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&schema).Error)
+
+	query := `{"query": "query($id: ID!) { member(memberId: $id) { name schemas { schemaName } } }", "variables": {"id": "mem_graph"}}`
+	w := serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/graphql", bytes.NewBufferString(query)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"member": {"name": "Graph Owner", "schemas": [{"schemaName": "Graph"}]}}}`, w.Body.String())
+
+	// Query errors come back in the GraphQL response
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/graphql", bytes.NewBufferString(`{"query": "{ member { name } }"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"errors"`)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/graphql", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/graphql", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "type Member {")
+
+	owner := CreateCustomTestUser(member.IdpUserID, member.Email, []models.Role{models.RoleMember})
+	w = serve(NewAuthenticatedRequest(http.MethodPost, "/api/v1/admin/graphql", bytes.NewBufferString(query), owner))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestApplicationWebhookEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	owner := CreateCustomTestUser("idp-webhook-owner", "webhook-owner@example.com", []models.Role{models.RoleMember})
+	stranger := CreateCustomTestUser("idp-webhook-stranger", "webhook-stranger@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_webhook", Name: "Owner", Email: owner.Email, PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	application := models.Application{
+		ApplicationID:   "app_webhook",
+		ApplicationName: "Webhook App",
+		SelectedFields:  models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_webhook"}},
+		MemberID:        member.MemberID,
+		Version:         string(models.ActiveVersion),
+	}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+	basePath := "/api/v1/applications/" + application.ApplicationID + "/webhooks"
+
+	body := `{"url": "https://consumer.example.com/hooks", "events": ["credential_expiring"]}`
+	w := serve(NewAuthenticatedRequest(http.MethodPost, basePath, bytes.NewBufferString(body), stranger))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, basePath, bytes.NewBufferString(`{"url": "https://consumer.example.com/hooks", "events": ["nope"]}`), owner))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodPost, basePath, bytes.NewBufferString(body), owner))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var webhook models.WebhookResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&webhook))
+	assert.NotEmpty(t, webhook.Secret)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, basePath, nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+	assert.Contains(t, w.Body.String(), webhook.WebhookID)
+
+	// The delivery log lists queued events
+	assert.NoError(t, testHandler.handler.webhookService.EnqueueCredentialExpiring(context.Background(), application.ApplicationID, "cred_webhook", time.Now()))
+	w = serve(NewAuthenticatedRequest(http.MethodGet, basePath+"/"+webhook.WebhookID+"/deliveries?status=pending", nil, owner))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var deliveries struct {
+		Items []models.WebhookDeliveryResponse `json:"items"`
+		Count int                              `json:"count"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&deliveries))
+	assert.Equal(t, 1, deliveries.Count)
+	assert.Equal(t, models.WebhookEventCredentialExpiring, deliveries.Items[0].Event)
+
+	w = serve(NewAuthenticatedRequest(http.MethodGet, basePath+"/"+webhook.WebhookID+"/deliveries?status=lost", nil, owner))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAuthenticatedRequest(http.MethodDelete, basePath+"/"+webhook.WebhookID, nil, owner))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(NewAuthenticatedRequest(http.MethodGet, basePath+"/"+webhook.WebhookID+"/deliveries", nil, owner))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestValidationErrorResponses(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) (int, models.ValidationErrorResponse) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var response models.ValidationErrorResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return w.Code, response
+	}
+	fields := func(response models.ValidationErrorResponse) []string {
+		var names []string
+		for _, fieldErr := range response.Errors {
+			assert.Equal(t, models.ValidationErrorDocs, fieldErr.Docs)
+			names = append(names, fieldErr.Field)
+		}
+		return names
+	}
+
+	t.Run("Create requests list every invalid field", func(t *testing.T) {
+		code, response := serve(NewAdminRequest(http.MethodPost, "/api/v1/members", bytes.NewBufferString(`{"name": "Jane", "email": "jane"}`)))
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, models.ValidationFailedCode, response.Code)
+		assert.NotEmpty(t, response.Error)
+		assert.Equal(t, []string{"email", "phoneNumber"}, fields(response))
+		assert.Equal(t, models.ValidationErrorInvalidFormat, response.Errors[0].Code)
+	})
+
+	t.Run("Malformed bodies name the field with the wrong type", func(t *testing.T) {
+		code, response := serve(NewAdminRequest(http.MethodPost, "/api/v1/schemas", bytes.NewBufferString(`{"schemaName": 42}`)))
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, []string{"schemaName"}, fields(response))
+		assert.Equal(t, models.ValidationErrorMalformedBody, response.Errors[0].Code)
+	})
+
+	t.Run("Submissions report the fields review needs", func(t *testing.T) {
+		code, response := serve(NewAdminRequest(http.MethodPost, "/api/v1/schema-submissions",
+			bytes.NewBufferString(`{"schemaName": " ", "memberId": "mem_1"}`)))
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, []string{"schemaName", "schemaEndpoint"}, fields(response))
+	})
+
+	t.Run("Query parameters are reported by name", func(t *testing.T) {
+		code, response := serve(NewAdminRequest(http.MethodGet, "/api/v1/members?limit=0", nil))
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, []string{"limit"}, fields(response))
+	})
+}
+
+func TestImpersonationEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	member := models.Member{MemberID: "mem_impersonated", Name: "Member", Email: "impersonated@example.com", PhoneNumber: "1", IdpUserID: "idp-impersonated"}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+
+	w := serve(NewMemberRequest(http.MethodPost, "/api/v1/admin/impersonation", bytes.NewBufferString(`{"memberId": "mem_impersonated", "reason": "ticket"}`)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/impersonation", bytes.NewBufferString(`{"memberId": "mem_impersonated"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"reason"`)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/impersonation", bytes.NewBufferString(`{"memberId": "mem_missing", "reason": "ticket"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/impersonation", bytes.NewBufferString(`{"memberId": "mem_impersonated", "reason": "ticket"}`)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var session models.ImpersonationSessionResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&session))
+	assert.True(t, session.Active)
+	assert.Equal(t, AdminUser.IdpUserID, session.AdminIdpUserID)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/impersonation", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), session.SessionID)
+
+	w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/admin/impersonation", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/impersonation", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSchemaLifecycleEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := NewAdminRequest(http.MethodPut, "/api/v1/schemas/sch_lifecycle", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	records := []interface{}{
+		&models.Member{MemberID: "mem_lifecycle_consumer", Name: "Consumer", Email: "lifecycle-consumer@example.com", PhoneNumber: "1", IdpUserID: "idp-lifecycle-consumer"},
+		&models.Schema{SchemaID: "sch_lifecycle", MemberID: "mem_lifecycle_provider", SchemaName: "Person", SDL: "type Query { name: String }", Endpoint: "http://provider", Version: string(models.ActiveVersion)},
+		&models.Application{ApplicationID: "app_lifecycle", ApplicationName: "Passports", MemberID: "mem_lifecycle_consumer", Version: string(models.ActiveVersion),
+			SelectedFields: models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_lifecycle"}}},
+	}
+	for _, record := range records {
+		assert.NoError(t, testHandler.db.Create(record).Error)
+	}
+
+	w := serve(`{"version": "deprecated", "revision": 1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"sunsetAt"`)
+
+	sunsetAt := time.Now().Add(30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	w = serve(fmt.Sprintf(`{"version": "deprecated", "sunsetAt": %q, "deprecationMessage": "Use Person v2", "revision": 1}`, sunsetAt))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response models.SchemaResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, string(models.DeprecatedVersion), response.Version)
+	if assert.NotNil(t, response.SunsetAt) {
+		assert.Equal(t, sunsetAt, *response.SunsetAt)
+	}
+
+	var notifications []models.Notification
+	assert.NoError(t, testHandler.db.Where("member_id = ? AND event = ?", "mem_lifecycle_consumer", models.NotificationEventSchemaDeprecated).Find(&notifications).Error)
+	if assert.Len(t, notifications, 1, "the owner of the consuming application is notified") {
+		assert.Equal(t, "app_lifecycle", notifications[0].ResourceID)
+	}
+}
+
+func TestRenewApplicationEndpoint(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	var renewalRequests []models.AllowListRenewalCreateRequest
+	pdp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.AllowListRenewalCreateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		renewalRequests = append(renewalRequests, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(models.AllowListRenewal{ID: "ren_1", ApplicationID: req.ApplicationID, Status: models.RenewalRequestPending})
+	}))
+	defer pdp.Close()
+	testHandler.handler.applicationService = services.NewApplicationService(testHandler.db, services.NewPDPService(pdp.URL, ""), mockIDPStore)
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(method, applicationID string) *httptest.ResponseRecorder {
+		req := NewAdminRequest(method, "/api/v1/applications/"+applicationID+"/renew", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	grantExpiresAt := time.Now().Add(10 * 24 * time.Hour)
+	records := []interface{}{
+		&models.Member{MemberID: "mem_renewal", Name: "Consumer", Email: "renewal@example.com", PhoneNumber: "1", IdpUserID: "idp-renewal"},
+		&models.Application{ApplicationID: "app_renewal", ApplicationName: "Passports", MemberID: "mem_renewal", Version: string(models.ActiveVersion),
+			SelectedFields: models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_1"}}, GrantExpiresAt: &grantExpiresAt},
+	}
+	for _, record := range records {
+		assert.NoError(t, testHandler.db.Create(record).Error)
+	}
+
+	w := serve(http.MethodPost, "app_renewal")
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var submission models.ApplicationSubmissionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &submission))
+	assert.True(t, submission.Renewal)
+	assert.Equal(t, string(models.StatusPending), submission.Status)
+	if assert.Len(t, renewalRequests, 1, "the renewal opens a PDP renewal request") {
+		assert.Equal(t, "app_renewal", renewalRequests[0].ApplicationID)
+	}
+
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "app_renewal").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "app_missing").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "app_renewal").Code)
+
+	req := NewAdminRequest(http.MethodGet, "/api/v1/applications/app_renewal", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var application models.ApplicationResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &application))
+	if assert.NotNil(t, application.GrantExpiresAt) {
+		expiresAt, err := time.Parse(time.RFC3339, *application.GrantExpiresAt)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, grantExpiresAt, expiresAt, time.Second)
+	}
+}
+
+func TestPDPSyncEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	var allowListUpdates []models.AllowListUpdateRequest
+	pdp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var req models.AllowListUpdateRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			allowListUpdates = append(allowListUpdates, req)
+			_, _ = w.Write([]byte(`{"records": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"records": [], "count": 0}`))
+	}))
+	defer pdp.Close()
+	testHandler.handler.pdpSyncService = services.NewPDPSyncService(testHandler.db, services.NewPDPService(pdp.URL, ""))
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	application := models.Application{ApplicationID: "app_sync", ApplicationName: "Passports", MemberID: "mem_sync", Version: string(models.ActiveVersion),
+		SelectedFields: models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_1"}}}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+
+	w := serve(NewAdminRequest(http.MethodGet, "/api/v1/pdp-sync/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var status models.PDPSyncStatusResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+	assert.False(t, status.InSync)
+	if assert.Len(t, status.Drift, 1) {
+		assert.Equal(t, models.PDPSyncDriftMissing, status.Drift[0].Type)
+	}
+
+	// An empty body repairs every drifted application
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/pdp-sync/repair", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var repair models.PDPSyncRepairResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&repair))
+	assert.Equal(t, 1, repair.Succeeded)
+	if assert.Len(t, allowListUpdates, 1) {
+		assert.Equal(t, "app_sync", allowListUpdates[0].ApplicationID)
+	}
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/pdp-sync/repair", bytes.NewBufferString(`{"applicationIds": "app_sync"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewMemberRequest(http.MethodGet, "/api/v1/pdp-sync/status", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPut, "/api/v1/pdp-sync/status", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	pdp.Close()
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/pdp-sync/status", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+func TestSchemaSubmissionEndpointCheck(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	endpoint := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"__schema": {"types": [{"kind": "OBJECT", "name": "Query", "fields": []}]}}}`))
+	}))
+	defer endpoint.Close()
+	// The test endpoint listens on loopback, which the prober's own client refuses to reach
+	prober := services.NewSchemaEndpointProber(5 * time.Second)
+	prober.HTTPClient = endpoint.Client()
+	testHandler.handler.reviewService = services.NewSubmissionReviewService(testHandler.db, testHandler.handler.schemaService,
+		testHandler.handler.applicationService, prober, nil, []models.ReviewStep{{Name: "technical_review", RequiredApprovals: 1}})
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	records := []interface{}{
+		&models.Member{MemberID: "mem_probe", Name: "Provider", Email: "probe@example.com", PhoneNumber: "1", IdpUserID: "idp-probe"},
+		&models.SchemaSubmission{SubmissionID: "sub_probe", SchemaName: "Person", SDL: "type Query { name: String }",
+			SchemaEndpoint: endpoint.URL, Status: string(models.StatusPending), MemberID: "mem_probe"},
+	}
+	for _, record := range records {
+		assert.NoError(t, testHandler.db.Create(record).Error)
+	}
+
+	w := serve(NewAdminRequest(http.MethodPost, "/api/v1/schema-submissions/sub_probe/review/endpoint-check", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report models.SchemaEndpointReport
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	assert.True(t, report.Reachable)
+	assert.False(t, report.Matches)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/schema-submissions/sub_probe/review/decisions",
+		bytes.NewBufferString(`{"decision": "approve"}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	var mismatch models.SchemaEndpointMismatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&mismatch))
+	if assert.NotNil(t, mismatch.Report) && assert.NotNil(t, mismatch.Report.Diff) {
+		assert.Len(t, mismatch.Report.Diff.RemovedFields, 1)
+	}
+
+	w = serve(NewMemberRequest(http.MethodPost, "/api/v1/schema-submissions/sub_probe/review/endpoint-check", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/schema-submissions/sub_probe/review/endpoint-check", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestApplicationSandboxEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	owner := CreateCustomTestUser("idp-sandbox-owner", "sandbox@example.com", []models.Role{models.RoleMember})
+	stranger := CreateCustomTestUser("idp-sandbox-stranger", "stranger@example.com", []models.Role{models.RoleMember})
+	member := models.Member{MemberID: "mem_sandbox", Name: "Owner", Email: "sandbox@example.com", PhoneNumber: "1", IdpUserID: owner.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	schema := models.Schema{SchemaID: "sch_sandbox", MemberID: member.MemberID, SchemaName: "Person",
+		SDL: "type Query { person: Person } type Person { fullName: String }", Endpoint: "http://provider", Version: string(models.ActiveVersion)}
+	assert.NoError(t, testHandler.db.Create(&schema).Error)
+	application := models.Application{ApplicationID: "app_sandbox", ApplicationName: "Sandbox App", MemberID: member.MemberID, Version: string(models.ActiveVersion),
+		SelectedFields: models.SelectedFieldRecords{{FieldName: "person.fullName", SchemaID: schema.SchemaID}}}
+	assert.NoError(t, testHandler.db.Create(&application).Error)
+	basePath := "/api/v1/applications/" + application.ApplicationID + "/sandbox"
+
+	// Sandbox mode is off unless SANDBOX_ENABLED is set
+	w := serve(NewAuthenticatedRequest(http.MethodPost, basePath, nil, owner))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(httptest.NewRequest(http.MethodPost, "/internal/api/v1/sandbox/providers/sch_sandbox/graphql", bytes.NewBufferString(`{"query": "{ person { fullName } }"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	testHandler.handler.sandboxService = services.NewSandboxService(testHandler.db, mockIDPStore, "http://portal-backend")
+	mockIDPStore.On("CreateApplication", mock.Anything, mock.AnythingOfType("*idp.Application")).Return("idp-sandbox", nil).Once()
+	mockIDPStore.On("GetApplicationOIDC", mock.Anything, "idp-sandbox").
+		Return(&idp.ApplicationOIDCInfo{ClientId: "client-sandbox", ClientSecret: "sandbox-secret"}, nil).Once()
+
+	t.Run("POST provisions the sandbox for the owner", func(t *testing.T) {
+		w := serve(NewAuthenticatedRequest(http.MethodPost, basePath, nil, stranger))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPost, basePath, nil, owner))
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var sandbox models.ApplicationSandboxResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&sandbox))
+		assert.Equal(t, "client-sandbox", sandbox.ClientID)
+		assert.Equal(t, "sandbox-secret", sandbox.ClientSecret)
+
+		w = serve(NewAuthenticatedRequest(http.MethodPost, basePath, nil, owner))
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = serve(NewAuthenticatedRequest(http.MethodGet, basePath, nil, owner))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "sandbox-secret")
+	})
+
+	t.Run("Sandbox clients resolve to mock providers", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/internal/api/v1/applications?idpClientId=client-sandbox", nil))
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resolved models.ApplicationIDResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resolved))
+		assert.Equal(t, application.ApplicationID, resolved.ApplicationID)
+		assert.Equal(t, models.EnvironmentSandbox, resolved.Environment)
+		assert.Equal(t, map[string]string{schema.SchemaID: "http://portal-backend/internal/api/v1/sandbox/providers/sch_sandbox/graphql"},
+			resolved.ProviderEndpoints)
+
+		w = serve(httptest.NewRequest(http.MethodPost, "/internal/api/v1/sandbox/providers/sch_sandbox/graphql",
+			bytes.NewBufferString(`{"query": "{ person { fullName } }"}`)))
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"data": {"person": {"fullName": "Sandbox User"}}}`, w.Body.String())
+
+		w = serve(httptest.NewRequest(http.MethodGet, "/internal/api/v1/applications?idpClientId=client-unknown", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSubmissionMetricsEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	submission := models.SchemaSubmission{SubmissionID: "sub_metrics", SchemaName: "Metrics", SDL: "type Query { name: String }",
+		SchemaEndpoint: "http://provider", Status: string(models.StatusPending), MemberID: "mem_metrics"}
+	assert.NoError(t, testHandler.db.Create(&submission).Error)
+
+	w := serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/metrics/submissions", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var metrics models.SubmissionMetricsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&metrics))
+	assert.Equal(t, int64(1), metrics.SchemaSubmissions.ByStatus["pending"])
+	assert.Equal(t, int64(1), metrics.Backlog)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/metrics/reviews?startTime=2026-01-01T00:00:00Z&endTime=2026-02-01T00:00:00Z", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var reviews models.ReviewMetricsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&reviews))
+	assert.Equal(t, int64(0), reviews.Decided)
+	assert.Empty(t, reviews.Members)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/metrics/reviews?startTime=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewMemberRequest(http.MethodGet, "/api/v1/admin/metrics/submissions", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/metrics/reviews", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/metrics/members", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSavedViewEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	submission := models.SchemaSubmission{SubmissionID: "sub_export", SchemaName: "Export", SDL: "type Query { name: String }",
+		SchemaEndpoint: "http://provider", Status: string(models.StatusPending), MemberID: "mem_export"}
+	assert.NoError(t, testHandler.db.Create(&submission).Error)
+
+	w := serve(NewMemberRequest(http.MethodPost, "/api/v1/admin/saved-views", bytes.NewBufferString(`{"name": "Pending", "collection": "schema-submissions"}`)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/saved-views", bytes.NewBufferString(`{"name": "Pending", "collection": "members", "query": {"status": ["pending"]}}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"status"`)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/saved-views",
+		bytes.NewBufferString(`{"name": "Pending", "collection": "schema-submissions", "query": {"status": ["pending"], "sort": "schemaName", "order": "asc"}}`)))
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var view models.SavedViewResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&view))
+	assert.Equal(t, AdminUser.IdpUserID, view.OwnerIdpUserID)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/saved-views", bytes.NewBufferString(`{"name": "Pending", "collection": "schema-submissions"}`)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/saved-views?collection=schema-submissions", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), view.ViewID)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/saved-views/"+view.ViewID+"/export", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="schema-submissions-`)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[1], "sub_export,Export,mem_export"))
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/saved-views/"+view.ViewID+"/export?format=pdf", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/exports/schema-submissions?format=xlsx&status=approved", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ExportFormatXLSX.ContentType(), w.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("PK")))
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/exports/members?sort=password", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/exports/schemas", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(NewMemberRequest(http.MethodGet, "/api/v1/admin/exports/members", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPut, "/api/v1/admin/saved-views/"+view.ViewID, bytes.NewBufferString(`{"name": "Still pending"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Still pending"`)
+
+	w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/admin/saved-views/"+view.ViewID, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/saved-views/"+view.ViewID, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSchemaSubmissionAttachmentEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	objects := map[string][]byte{}
+	storageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			objects[r.URL.Path], _ = io.ReadAll(r.Body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer storageServer.Close()
+	storage, err := services.NewS3AttachmentStorage(storageServer.URL, "attachments", "us-east-1", "access", "secret", true)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	upload := func(newRequest func(string, string, io.Reader) *http.Request, submissionId, category, fileName string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		assert.NoError(t, form.WriteField("category", category))
+		file, err := form.CreateFormFile("file", fileName)
+		assert.NoError(t, err)
+		_, _ = file.Write(content)
+		assert.NoError(t, form.Close())
+		req := newRequest(http.MethodPost, "/api/v1/schema-submissions/"+submissionId+"/attachments", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return serve(req)
+	}
+	pdf := []byte("%PDF-1.7\n1 0 obj\n<<>>\nendobj\n")
+
+	member := models.Member{MemberID: "mem_attach", Name: "Provider", Email: "provider@example.com", PhoneNumber: "1", IdpUserID: MemberUser.IdpUserID}
+	assert.NoError(t, testHandler.db.Create(&member).Error)
+	for _, submission := range []models.SchemaSubmission{
+		{SubmissionID: "sub_attach", SchemaName: "Attach", SDL: "type Query { name: String }", SchemaEndpoint: "http://provider",
+			Status: string(models.StatusPending), MemberID: member.MemberID},
+		{SubmissionID: "sub_other", SchemaName: "Other", SDL: "type Query { name: String }", SchemaEndpoint: "http://other",
+			Status: string(models.StatusPending), MemberID: "mem_other"},
+	} {
+		assert.NoError(t, testHandler.db.Create(&submission).Error)
+	}
+
+	w := upload(NewMemberRequest, "sub_attach", "authorization_letter", "letter.pdf", pdf)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	testHandler.handler.attachmentService = services.NewAttachmentService(testHandler.db, storage, nil, 1024, time.Minute)
+
+	w = upload(NewMemberRequest, "sub_attach", "authorization_letter", "letter.pdf", pdf)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var attachment models.SubmissionAttachmentResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&attachment))
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, MemberUser.IdpUserID, attachment.UploadedBy)
+	assert.Len(t, objects, 1)
+
+	w = upload(NewMemberRequest, "sub_other", "authorization_letter", "letter.pdf", pdf)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = upload(NewMemberRequest, "sub_attach", "data_sharing_mou", "mou.exe", []byte("MZ\x90\x00executable"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"file"`)
+
+	w = upload(NewMemberRequest, "sub_attach", "data_sharing_mou", "mou.pdf", append(pdf, make([]byte, 2048)...))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/schema-submissions/sub_attach/attachments", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), attachment.AttachmentID)
+
+	w = serve(NewMemberRequest(http.MethodGet, "/api/v1/schema-submissions/sub_attach/attachments/"+attachment.AttachmentID+"/download", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var download models.AttachmentDownloadResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&download))
+	assert.True(t, strings.HasPrefix(download.URL, storageServer.URL+"/attachments/schema-submissions/sub_attach/"+attachment.AttachmentID+"?"))
+	assert.Contains(t, download.URL, "X-Amz-Signature=")
+
+	w = serve(NewMemberRequest(http.MethodGet, "/api/v1/schema-submissions/sub_other/attachments", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewMemberRequest(http.MethodDelete, "/api/v1/schema-submissions/sub_attach/attachments/"+attachment.AttachmentID, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, objects)
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/schema-submissions/sub_attach/attachments/"+attachment.AttachmentID+"/download", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestFieldConfigurationTemplateEndpoints(t *testing.T) {
+	testHandler := NewTestV1Handler(t)
+	if testHandler == nil {
+		t.Skip("Skipping test: database connection failed")
+		return
+	}
+
+	mux := http.NewServeMux()
+	testHandler.handler.SetupV1Routes(mux)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	body := `{"name": "Person registry defaults", "providerType": "person-registry",
+		"rules": [{"field": "nic", "accessControlType": "restricted", "classification": "personal"}]}`
+
+	w := serve(NewMemberRequest(http.MethodPost, "/api/v1/admin/field-configuration-templates", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/field-configuration-templates",
+		bytes.NewBufferString(`{"name": "Empty", "providerType": "vehicle-registry", "rules": [{"field": "nic"}]}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"rules[0].classification"`)
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/field-configuration-templates", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var template models.FieldConfigurationTemplateResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&template))
+
+	w = serve(NewAdminRequest(http.MethodPost, "/api/v1/admin/field-configuration-templates", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = serve(NewAdminRequest(http.MethodPut, "/api/v1/admin/field-configuration-templates/"+template.TemplateID,
+		bytes.NewBufferString(`{"description": "Defaults of the citizen registries"}`)))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "Defaults of the citizen registries")
+
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/field-configuration-templates", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), template.TemplateID)
+
+	w = serve(NewAdminRequest(http.MethodDelete, "/api/v1/admin/field-configuration-templates/"+template.TemplateID, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(NewAdminRequest(http.MethodGet, "/api/v1/admin/field-configuration-templates/"+template.TemplateID, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
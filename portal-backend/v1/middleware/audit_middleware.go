@@ -1,16 +1,312 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	authutils "github.com/gov-dx-sandbox/portal-backend/v1/utils"
 	auditpkg "github.com/gov-dx-sandbox/shared/audit"
 )
 
+// maxAuditedBodyBytes caps how much of a create or bulk response is buffered to resolve the new
+// resource's ID or the bulk items; it fits the results of a member import of MaxMemberImportRows rows
+const maxAuditedBodyBytes = 256 << 10
+
+// auditedResource is a portal route whose writes are audited
+type auditedResource struct {
+	prefix   string
+	resource models.ResourceType
+	// idField is the JSON field holding the new resource's ID in a create response
+	idField string
+	// bulk routes act on many resources at once: the path after the prefix names the operation, and
+	// the items of the response's results are recorded in the operation's single event
+	bulk bool
+	// approval routes decide two-person approvals: the approval in the response, naming the admin who
+	// requested it and the one who decided it, is recorded in the event
+	approval bool
+	// related names the fields of the JSON response holding the IDs of other resources the write concerns;
+	// they are recorded as relatedResources so that the write shows in those resources' timelines
+	related []relatedField
+}
+
+// relatedField is a JSON response field holding the ID of a related resource of the given type
+type relatedField struct {
+	field    string
+	resource models.ResourceType
+}
+
+// approvalResources is the type of the resource each approval action applies to, so that decisions on
+// approvals show in that resource's timeline; credentials have no timeline of their own
+var approvalResources = map[models.ApprovalAction]models.ResourceType{
+	models.ApprovalActionDeleteMember:           models.ResourceTypeMembers,
+	models.ApprovalActionApproveSensitiveSchema: models.ResourceTypeSchemaSubmissions,
+}
+
+// auditedResources lists the routes audited by AuditMiddleware; the path segment after the prefix is the resource ID
+// and any segments after it name the operation, e.g. "restore" or "credentials/cred_1/rotate"
+var auditedResources = []auditedResource{
+	{prefix: "/api/v1/members", resource: models.ResourceTypeMembers, idField: "memberId"},
+	{prefix: "/api/v1/schemas", resource: models.ResourceTypeSchemas, idField: "schemaId"},
+	{prefix: "/api/v1/schema-submissions", resource: models.ResourceTypeSchemaSubmissions, idField: "submissionId",
+		related: []relatedField{{field: "previousSchemaId", resource: models.ResourceTypeSchemas}}},
+	{prefix: "/api/v1/applications", resource: models.ResourceTypeApplications, idField: "applicationId"},
+	{prefix: "/api/v1/application-submissions", resource: models.ResourceTypeApplicationSubmissions, idField: "submissionId",
+		related: []relatedField{{field: "previousApplicationId", resource: models.ResourceTypeApplications}}},
+	{prefix: "/api/v1/admin/pdp-jobs", resource: models.ResourceTypePDPJobs, idField: "jobId"},
+	{prefix: "/api/v1/invitations", resource: models.ResourceTypeInvitations, idField: "invitationId"},
+	{prefix: "/api/v1/organizations", resource: models.ResourceTypeOrganizations, idField: "organizationId"},
+	{prefix: "/api/v1/admin/bulk", resource: models.ResourceTypeBulkOperations, bulk: true},
+	{prefix: "/api/v1/admin/impersonation", resource: models.ResourceTypeImpersonationSessions, idField: "sessionId"},
+	{prefix: "/api/v1/admin/approvals", resource: models.ResourceTypeAdminApprovals, idField: "approvalId", approval: true},
+	{prefix: "/api/v1/admin/saved-views", resource: models.ResourceTypeSavedViews, idField: "viewId"},
+	{prefix: "/api/v1/admin/field-configuration-templates", resource: models.ResourceTypeFieldConfigurationTemplates, idField: "templateId"},
+}
+
+// AuditMiddleware records a MANAGEMENT_EVENT for every write to a portal resource,
+// taking the outcome from the response the handler wrote
+type AuditMiddleware struct {
+	client auditpkg.AuditClient
+}
+
+// NewAuditMiddleware creates an audit middleware that logs through the given client
+func NewAuditMiddleware(client auditpkg.AuditClient) *AuditMiddleware {
+	return &AuditMiddleware{client: client}
+}
+
+// AuditRequest wraps next so that each write is audited with its HTTP status, handler latency,
+// response size and resource ID. For creates the ID is read from the response body, and for bulk
+// operations the outcome of each item is. Writes answered 202 Accepted await a second admin, so the
+// pending approval in their response is recorded too. Resources the response names as related, such as
+// the application a submission follows or the member an approval deletes, are recorded as relatedResources.
+func (m *AuditMiddleware) AuditRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.client == nil || !m.client.IsEnabled() || !isWriteOperation(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route, resourceID, operation, ok := matchAuditedResource(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if route.bulk {
+			operation = strings.Trim(resourceID+"/"+operation, "/")
+			resourceID = ""
+		}
+
+		recorder := &auditResponseRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+			captureBody:    (resourceID == "" && r.Method == http.MethodPost) || route.bulk || route.approval || len(route.related) > 0,
+		}
+		start := time.Now()
+		next.ServeHTTP(recorder, r)
+		latency := time.Since(start)
+
+		var items []auditedBulkItem
+		var approval *auditedApproval
+		var related []auditedRelatedResource
+		if recorder.captureBody && recorder.statusCode < http.StatusBadRequest {
+			if route.approval || recorder.statusCode == http.StatusAccepted {
+				approval = recorder.approval()
+			}
+			related = recorder.relatedResources(route.related)
+			if route.approval && approval != nil {
+				if resource, ok := approvalResources[models.ApprovalAction(approval.Action)]; ok {
+					related = append(related, auditedRelatedResource{Type: string(resource), ID: approval.ResourceID})
+				}
+			}
+			if route.bulk {
+				items = recorder.bulkItems()
+			} else if resourceID == "" {
+				resourceID = recorder.resourceID(route.idField)
+			}
+		}
+
+		status := models.AuditStatusSuccess
+		if recorder.statusCode >= http.StatusBadRequest {
+			status = models.AuditStatusFailure
+		}
+
+		var resourceIDPtr *string
+		if resourceID != "" {
+			resourceIDPtr = &resourceID
+		}
+
+		extra := map[string]interface{}{
+			"httpStatus":   recorder.statusCode,
+			"latencyMs":    latency.Milliseconds(),
+			"responseSize": recorder.bytesWritten,
+		}
+		if operation != "" {
+			extra["operation"] = operation
+		}
+		if items != nil {
+			extra["items"] = items
+		}
+		if approval != nil {
+			extra["approval"] = approval
+		}
+		if related != nil {
+			extra["relatedResources"] = related
+		}
+		logAudit(m.client, r, string(route.resource), resourceIDPtr, string(status), extra)
+	})
+}
+
+// matchAuditedResource finds the audited route for path, the resource ID segment and the operation
+// segments after it, if any
+func matchAuditedResource(path string) (auditedResource, string, string, bool) {
+	for _, route := range auditedResources {
+		if path != route.prefix && !strings.HasPrefix(path, route.prefix+"/") {
+			continue
+		}
+		rest := strings.Trim(strings.TrimPrefix(path, route.prefix), "/")
+		resourceID, operation, _ := strings.Cut(rest, "/")
+		return route, resourceID, operation, true
+	}
+	return auditedResource{}, "", "", false
+}
+
+// auditResponseRecorder captures the status and size of a response, and optionally the start of its body
+type auditResponseRecorder struct {
+	http.ResponseWriter
+	statusCode    int
+	wroteHeader   bool
+	bytesWritten  int
+	captureBody   bool
+	body          bytes.Buffer
+	bodyTruncated bool
+}
+
+func (rec *auditResponseRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.statusCode = code
+		rec.wroteHeader = true
+		// An action awaiting a second admin answers with the approval recorded in its event
+		if code == http.StatusAccepted {
+			rec.captureBody = true
+		}
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *auditResponseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	if rec.captureBody && !rec.bodyTruncated {
+		if rec.body.Len()+len(b) > maxAuditedBodyBytes {
+			rec.bodyTruncated = true
+		} else {
+			rec.body.Write(b)
+		}
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytesWritten += n
+	return n, err
+}
+
+// resourceID reads idField from the captured JSON response body, returning "" if it is absent
+func (rec *auditResponseRecorder) resourceID(idField string) string {
+	if rec.bodyTruncated || rec.body.Len() == 0 {
+		return ""
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.body.Bytes(), &body); err != nil {
+		return ""
+	}
+	var id string
+	if err := json.Unmarshal(body[idField], &id); err != nil {
+		return ""
+	}
+	return id
+}
+
+// auditedBulkItem is the outcome of one item of a bulk operation, as recorded in its audit event
+type auditedBulkItem struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Row     int    `json:"row,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// bulkItems reads the results of a bulk operation from the captured JSON response body
+func (rec *auditResponseRecorder) bulkItems() []auditedBulkItem {
+	if rec.bodyTruncated || rec.body.Len() == 0 {
+		return nil
+	}
+	var body struct {
+		Results []auditedBulkItem `json:"results"`
+	}
+	if err := json.Unmarshal(rec.body.Bytes(), &body); err != nil {
+		return nil
+	}
+	return body.Results
+}
+
+// auditedApproval is the two-person approval a write requested or decided, as recorded in its audit event
+type auditedApproval struct {
+	ApprovalID  string  `json:"approvalId"`
+	Action      string  `json:"action"`
+	ResourceID  string  `json:"resourceId"`
+	Status      string  `json:"status"`
+	RequestedBy string  `json:"requestedBy"`
+	DecidedBy   *string `json:"decidedBy,omitempty"`
+}
+
+// approval reads the approval from the captured JSON response body, returning nil if there is none
+func (rec *auditResponseRecorder) approval() *auditedApproval {
+	if rec.bodyTruncated || rec.body.Len() == 0 {
+		return nil
+	}
+	var approval auditedApproval
+	if err := json.Unmarshal(rec.body.Bytes(), &approval); err != nil || approval.ApprovalID == "" {
+		return nil
+	}
+	return &approval
+}
+
+// auditedRelatedResource is another resource a write concerns, as recorded in its audit event
+type auditedRelatedResource struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// relatedResources reads the IDs of related resources from the given fields of the captured JSON response
+// body, skipping fields that are absent or empty
+func (rec *auditResponseRecorder) relatedResources(fields []relatedField) []auditedRelatedResource {
+	if len(fields) == 0 || rec.bodyTruncated || rec.body.Len() == 0 {
+		return nil
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.body.Bytes(), &body); err != nil {
+		return nil
+	}
+	var related []auditedRelatedResource
+	for _, field := range fields {
+		var id string
+		if err := json.Unmarshal(body[field.field], &id); err != nil || id == "" {
+			continue
+		}
+		related = append(related, auditedRelatedResource{Type: string(field.resource), ID: id})
+	}
+	return related
+}
+
 // LogAudit logs an audit event for portal-backend operations by extracting request info and creating an audit log
 func LogAudit(client auditpkg.AuditClient, r *http.Request, resource string, resourceID *string, status string) {
+	logAudit(client, r, resource, resourceID, status, nil)
+}
+
+// logAudit logs a MANAGEMENT_EVENT, merging extra into its additional metadata
+func logAudit(client auditpkg.AuditClient, r *http.Request, resource string, resourceID *string, status string, extra map[string]interface{}) {
 	// Skip if audit client is not enabled
 	if client == nil || !client.IsEnabled() {
 		return
@@ -46,10 +342,23 @@ func LogAudit(client auditpkg.AuditClient, r *http.Request, resource string, res
 	// Create audit event using shared/audit DTO
 	// Use shared utilities for timestamp and metadata marshaling
 	timestamp := auditpkg.CurrentTimestamp()
-	additionalMetadata := auditpkg.MarshalMetadata(map[string]interface{}{
+	metadata := map[string]interface{}{
 		"resource":   resource,
 		"resourceId": resourceID,
-	})
+	}
+	for key, value := range extra {
+		metadata[key] = value
+	}
+	// Impersonated writes are attributed to the admin and name the member they acted as
+	if impersonation, ok := authutils.GetImpersonation(r.Context()); ok {
+		metadata["impersonation"] = map[string]interface{}{
+			"sessionId":       impersonation.SessionID,
+			"adminIdpUserId":  impersonation.AdminIdpUserID,
+			"memberId":        impersonation.MemberID,
+			"memberIdpUserId": impersonation.MemberIdpUserID,
+		}
+	}
+	additionalMetadata := auditpkg.MarshalMetadata(metadata)
 
 	auditRequest := &auditpkg.AuditLogRequest{
 		TraceID:            nil, // No trace ID for standalone management events
@@ -86,6 +395,13 @@ func extractActorInfoFromRequest(r *http.Request) (actorType string, actorID *st
 		return systemActorType, &systemActorID, &systemActorType
 	}
 
+	// An admin impersonating a member remains the actor of everything they do
+	if impersonation, ok := authutils.GetImpersonation(r.Context()); ok {
+		adminActorID := impersonation.AdminIdpUserID
+		adminActorType := string(models.ActorTypeAdmin)
+		return adminActorType, &adminActorID, &adminActorType
+	}
+
 	// Authenticated user found - extract actor information
 	userID := user.IdpUserID
 	actorID = &userID
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -252,3 +253,274 @@ func TestLogAudit_SendsRequest(t *testing.T) {
 		t.Error("Expected non-empty body")
 	}
 }
+
+// auditedRequest builds a request carrying an authenticated admin user
+func auditedRequest(t *testing.T, method, path string) *http.Request {
+	t.Helper()
+	now := time.Now().Unix()
+	user, err := models.NewAuthenticatedUser(&models.UserClaims{
+		IdpUserID: "admin-user-id",
+		Email:     "admin@example.com",
+		Roles:     models.FlexibleStringSlice([]string{"OpenDIF_Admin"}),
+		IssuedAt:  now,
+		ExpiresAt: now + 3600,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create authenticated user: %v", err)
+	}
+	req := httptest.NewRequest(method, path, nil)
+	return req.WithContext(utils.SetAuthenticatedUser(req.Context(), user))
+}
+
+// auditMetadata decodes the additional metadata of the single event the mock client received
+func auditMetadata(t *testing.T, client *mockAuditClient) (*auditpkg.AuditLogRequest, map[string]interface{}) {
+	t.Helper()
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.receivedEvents) != 1 {
+		t.Fatalf("Expected 1 audit event, got %d", len(client.receivedEvents))
+	}
+	event := client.receivedEvents[0]
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(event.AdditionalMetadata, &metadata); err != nil {
+		t.Fatalf("Failed to decode additional metadata: %v", err)
+	}
+	return event, metadata
+}
+
+func TestAuditMiddleware_CreateResolvesResourceIDFromResponse(t *testing.T) {
+	mockClient := newMockAuditClient(true)
+	handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"schemaId": "sch_123", "schemaName": "test"})
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, auditedRequest(t, http.MethodPost, "/api/v1/schemas"))
+
+	event, metadata := auditMetadata(t, mockClient)
+	if event.Status != string(models.AuditStatusSuccess) {
+		t.Errorf("Expected status SUCCESS, got %s", event.Status)
+	}
+	if *event.EventAction != "CREATE" {
+		t.Errorf("Expected action CREATE, got %s", *event.EventAction)
+	}
+	if event.ActorType != string(models.ActorTypeAdmin) || event.ActorID != "admin-user-id" {
+		t.Errorf("Unexpected actor %s/%s", event.ActorType, event.ActorID)
+	}
+	if metadata["resource"] != string(models.ResourceTypeSchemas) {
+		t.Errorf("Expected resource SCHEMAS, got %v", metadata["resource"])
+	}
+	if metadata["resourceId"] != "sch_123" {
+		t.Errorf("Expected resourceId sch_123, got %v", metadata["resourceId"])
+	}
+	if metadata["httpStatus"] != float64(http.StatusCreated) {
+		t.Errorf("Expected httpStatus 201, got %v", metadata["httpStatus"])
+	}
+	if metadata["responseSize"] != float64(rec.Body.Len()) {
+		t.Errorf("Expected responseSize %d, got %v", rec.Body.Len(), metadata["responseSize"])
+	}
+	if _, ok := metadata["latencyMs"]; !ok {
+		t.Error("Expected latencyMs in metadata")
+	}
+}
+
+func TestAuditMiddleware_UpdateTakesResourceIDFromPath(t *testing.T) {
+	mockClient := newMockAuditClient(true)
+	handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPut, "/api/v1/application-submissions/sub_456"))
+
+	event, metadata := auditMetadata(t, mockClient)
+	if event.Status != string(models.AuditStatusFailure) {
+		t.Errorf("Expected status FAILURE, got %s", event.Status)
+	}
+	if *event.EventAction != "UPDATE" {
+		t.Errorf("Expected action UPDATE, got %s", *event.EventAction)
+	}
+	if metadata["resource"] != string(models.ResourceTypeApplicationSubmissions) {
+		t.Errorf("Expected resource APPLICATION-SUBMISSIONS, got %v", metadata["resource"])
+	}
+	if metadata["resourceId"] != "sub_456" {
+		t.Errorf("Expected resourceId sub_456, got %v", metadata["resourceId"])
+	}
+	if metadata["httpStatus"] != float64(http.StatusBadRequest) {
+		t.Errorf("Expected httpStatus 400, got %v", metadata["httpStatus"])
+	}
+}
+
+func TestAuditMiddleware_RecordsSubresourceOperation(t *testing.T) {
+	mockClient := newMockAuditClient(true)
+	handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPost, "/api/v1/applications/app_1/credentials/cred_1/rotate"))
+
+	_, metadata := auditMetadata(t, mockClient)
+	if metadata["resource"] != string(models.ResourceTypeApplications) {
+		t.Errorf("Expected resource APPLICATIONS, got %v", metadata["resource"])
+	}
+	if metadata["resourceId"] != "app_1" {
+		t.Errorf("Expected resourceId app_1, got %v", metadata["resourceId"])
+	}
+	if metadata["operation"] != "credentials/cred_1/rotate" {
+		t.Errorf("Expected operation credentials/cred_1/rotate, got %v", metadata["operation"])
+	}
+}
+
+func TestAuditMiddleware_GroupsBulkOperationItems(t *testing.T) {
+	mockClient := newMockAuditClient(true)
+	handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"operation": "expire-applications", "results": [
+			{"type": "application", "id": "app_1", "success": true, "status": "deprecated"},
+			{"type": "application", "id": "app_2", "success": false, "error": "resource not found"}
+		], "succeeded": 1, "failed": 1}`))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPost, "/api/v1/admin/bulk/expire-applications"))
+
+	_, metadata := auditMetadata(t, mockClient)
+	if metadata["resource"] != string(models.ResourceTypeBulkOperations) {
+		t.Errorf("Expected resource BULK-OPERATIONS, got %v", metadata["resource"])
+	}
+	if metadata["resourceId"] != nil {
+		t.Errorf("Expected no resourceId, got %v", metadata["resourceId"])
+	}
+	if metadata["operation"] != "expire-applications" {
+		t.Errorf("Expected operation expire-applications, got %v", metadata["operation"])
+	}
+	items, ok := metadata["items"].([]interface{})
+	if !ok || len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %v", metadata["items"])
+	}
+	second := items[1].(map[string]interface{})
+	if second["id"] != "app_2" || second["success"] != false || second["error"] != "resource not found" {
+		t.Errorf("Unexpected item %v", second)
+	}
+}
+
+func TestAuditMiddleware_RecordsApprovals(t *testing.T) {
+	t.Run("A write awaiting a second admin records the pending approval", func(t *testing.T) {
+		mockClient := newMockAuditClient(true)
+		handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"approvalId": "apr_1", "action": "delete_member", "resourceId": "mem_1",
+				"status": "pending", "requestedBy": "admin-user-id"}`))
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodDelete, "/api/v1/members/mem_1"))
+
+		_, metadata := auditMetadata(t, mockClient)
+		if metadata["resource"] != string(models.ResourceTypeMembers) || metadata["resourceId"] != "mem_1" {
+			t.Errorf("Expected member mem_1, got %v/%v", metadata["resource"], metadata["resourceId"])
+		}
+		approval, ok := metadata["approval"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected approval in metadata, got %v", metadata["approval"])
+		}
+		if approval["approvalId"] != "apr_1" || approval["status"] != "pending" || approval["requestedBy"] != "admin-user-id" {
+			t.Errorf("Unexpected approval %v", approval)
+		}
+		if _, found := approval["decidedBy"]; found {
+			t.Errorf("Expected no decidedBy for a pending approval, got %v", approval["decidedBy"])
+		}
+	})
+
+	t.Run("A confirmation records both admins", func(t *testing.T) {
+		mockClient := newMockAuditClient(true)
+		handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"approvalId": "apr_1", "action": "delete_member", "resourceId": "mem_1",
+				"status": "confirmed", "requestedBy": "other-admin-id", "decidedBy": "admin-user-id"}`))
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPost, "/api/v1/admin/approvals/apr_1/confirm"))
+
+		event, metadata := auditMetadata(t, mockClient)
+		if event.ActorID != "admin-user-id" {
+			t.Errorf("Expected the confirming admin as the actor, got %s", event.ActorID)
+		}
+		if metadata["resource"] != string(models.ResourceTypeAdminApprovals) || metadata["resourceId"] != "apr_1" {
+			t.Errorf("Expected approval apr_1, got %v/%v", metadata["resource"], metadata["resourceId"])
+		}
+		if metadata["operation"] != "confirm" {
+			t.Errorf("Expected operation confirm, got %v", metadata["operation"])
+		}
+		approval, ok := metadata["approval"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected approval in metadata, got %v", metadata["approval"])
+		}
+		if approval["requestedBy"] != "other-admin-id" || approval["decidedBy"] != "admin-user-id" || approval["resourceId"] != "mem_1" {
+			t.Errorf("Unexpected approval %v", approval)
+		}
+		related, ok := metadata["relatedResources"].([]interface{})
+		if !ok || len(related) != 1 {
+			t.Fatalf("Expected the member as related resource, got %v", metadata["relatedResources"])
+		}
+		if member := related[0].(map[string]interface{}); member["type"] != string(models.ResourceTypeMembers) || member["id"] != "mem_1" {
+			t.Errorf("Unexpected related resource %v", member)
+		}
+	})
+}
+
+func TestAuditMiddleware_RecordsRelatedResources(t *testing.T) {
+	mockClient := newMockAuditClient(true)
+	handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"submissionId": "sub_1", "previousApplicationId": "app_1", "status": "pending"}`))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPost, "/api/v1/application-submissions/sub_1/submit"))
+
+	_, metadata := auditMetadata(t, mockClient)
+	if metadata["resourceId"] != "sub_1" || metadata["operation"] != "submit" {
+		t.Errorf("Expected submission sub_1 submitted, got %v/%v", metadata["resourceId"], metadata["operation"])
+	}
+	related, ok := metadata["relatedResources"].([]interface{})
+	if !ok || len(related) != 1 {
+		t.Fatalf("Expected the previous application as related resource, got %v", metadata["relatedResources"])
+	}
+	if application := related[0].(map[string]interface{}); application["type"] != string(models.ResourceTypeApplications) || application["id"] != "app_1" {
+		t.Errorf("Unexpected related resource %v", application)
+	}
+}
+
+func TestAuditMiddleware_SkipsReadsAndUnauditedRoutes(t *testing.T) {
+	mockClient := newMockAuditClient(true)
+	handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodGet, "/api/v1/members/mem_1"))
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPost, "/api/v1/schemas-extra"))
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPost, "/internal/api/v1/applications"))
+
+	mockClient.mu.Lock()
+	defer mockClient.mu.Unlock()
+	if len(mockClient.receivedEvents) != 0 {
+		t.Errorf("Expected no audit events, got %d", len(mockClient.receivedEvents))
+	}
+}
+
+func TestAuditMiddleware_SkipsWhenDisabled(t *testing.T) {
+	mockClient := newMockAuditClient(false)
+	called := false
+	handler := NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), auditedRequest(t, http.MethodPost, "/api/v1/members"))
+
+	if !called {
+		t.Error("Expected wrapped handler to be called")
+	}
+	if len(mockClient.receivedEvents) != 0 {
+		t.Errorf("Expected no audit events, got %d", len(mockClient.receivedEvents))
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -56,9 +57,10 @@ func (a *AuthorizationMiddleware) AuthorizeRequest(next http.Handler) http.Handl
 			return
 		}
 
-		// Find the endpoint permission requirement
-		endpointPermission, found := authutils.FindEndpointPermission(r.Method, r.URL.Path)
-		if !found {
+		// Find the endpoint permission and route policy requirements
+		endpointPermission, hasPermission := authutils.FindEndpointPermission(r.Method, r.URL.Path)
+		policy, hasPolicy := authutils.FindRoutePolicy(r.Method, r.URL.Path)
+		if !hasPermission && !hasPolicy {
 			// Handle undefined endpoints based on configuration
 			if a.handleUndefinedEndpoint(w, r, user) {
 				return // Response already sent
@@ -68,6 +70,20 @@ func (a *AuthorizationMiddleware) AuthorizeRequest(next http.Handler) http.Handl
 			return
 		}
 
+		// Check the roles and token scopes declared by the route policy
+		if hasPolicy && !a.checkRoutePolicy(w, r, user, policy) {
+			return // Response already sent
+		}
+		if !hasPermission {
+			slog.Info("Access granted by route policy",
+				"user", user.Email,
+				"role", user.GetPrimaryRole(),
+				"path", r.URL.Path,
+				"method", r.Method)
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Check if user has the required permission
 		if !user.HasPermission(endpointPermission.Permission) {
 			slog.Warn("Access denied: insufficient permissions",
@@ -95,6 +111,34 @@ func (a *AuthorizationMiddleware) AuthorizeRequest(next http.Handler) http.Handl
 	})
 }
 
+// checkRoutePolicy checks that the user has one of the roles and the token all of the scopes
+// declared by the route policy. Returns false if the request was denied and a response was sent.
+func (a *AuthorizationMiddleware) checkRoutePolicy(w http.ResponseWriter, r *http.Request, user *models.AuthenticatedUser, policy *models.RoutePolicy) bool {
+	if len(policy.Roles) > 0 && !user.HasAnyRole(policy.Roles...) {
+		slog.Warn("Access denied: route requires another role",
+			"user", user.Email,
+			"user_roles", user.Roles,
+			"required_roles", policy.Roles,
+			"path", r.URL.Path,
+			"method", r.Method)
+		sharedutils.RespondWithError(w, http.StatusForbidden, "Insufficient privileges")
+		return false
+	}
+
+	if missing := authutils.MissingScopes(user, policy); len(missing) > 0 {
+		slog.Warn("Access denied: token lacks required scopes",
+			"user", user.Email,
+			"missing_scopes", missing,
+			"path", r.URL.Path,
+			"method", r.Method)
+		// RFC 6750 tells the client which scopes to request a new token with
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, strings.Join(policy.Scopes, " ")))
+		sharedutils.RespondWithError(w, http.StatusForbidden, "Insufficient scope")
+		return false
+	}
+	return true
+}
+
 // RequireRole returns a middleware that requires a specific role
 func (a *AuthorizationMiddleware) RequireRole(requiredRole models.Role) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	authutils "github.com/gov-dx-sandbox/portal-backend/v1/utils"
 )
 
 func TestAuthorizationMiddleware_HandleUndefinedEndpoint(t *testing.T) {
@@ -173,3 +174,59 @@ func TestAuthorizationMiddleware_Configuration(t *testing.T) {
 		t.Errorf("Custom strict mode should be true, got %v", customMiddleware.config.StrictMode)
 	}
 }
+
+func TestAuthorizationMiddleware_RoutePolicies(t *testing.T) {
+	original := models.RoutePolicies
+	defer func() { models.RoutePolicies = original }()
+	models.RoutePolicies = []models.RoutePolicy{
+		{Method: "GET", Path: "/api/v1/admin/*", Roles: []models.Role{models.RoleAdmin}},
+		{Method: "GET", Path: "/api/v1/catalog/fields", Scopes: []string{"catalog:read"}},
+	}
+
+	adminUser := &models.AuthenticatedUser{Email: "admin@example.com", Roles: []models.Role{models.RoleAdmin}}
+	systemUser := &models.AuthenticatedUser{Email: "system@example.com", Roles: []models.Role{models.RoleSystem}}
+	scopedUser := &models.AuthenticatedUser{Email: "member@example.com", Roles: []models.Role{models.RoleMember}, Scopes: []string{"openid", "catalog:read"}}
+	unscopedUser := &models.AuthenticatedUser{Email: "member@example.com", Roles: []models.Role{models.RoleMember}, Scopes: []string{"openid"}}
+
+	tests := []struct {
+		name           string
+		user           *models.AuthenticatedUser
+		path           string
+		expectedStatus int
+	}{
+		{"Policy-only route allows required role", adminUser, "/api/v1/admin/routes", http.StatusOK},
+		// The fail-open mode would let system users reach routes without a permission
+		{"Policy-only route denies other roles", systemUser, "/api/v1/admin/routes", http.StatusForbidden},
+		{"Policy and permission both satisfied", scopedUser, "/api/v1/catalog/fields", http.StatusOK},
+		{"Missing scope denied", unscopedUser, "/api/v1/catalog/fields", http.StatusForbidden},
+		{"Route without policy uses permission only", unscopedUser, "/api/v1/schemas", http.StatusOK},
+	}
+
+	middleware := NewAuthorizationMiddlewareWithConfig(AuthorizationConfig{Mode: models.AuthorizationModeFailOpenAdminSystem})
+	handler := middleware.AuthorizeRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req = req.WithContext(authutils.SetAuthenticatedUser(req.Context(), tt.user))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("AuthorizeRequest() status = %v, want %v, body: %s", w.Code, tt.expectedStatus, w.Body.String())
+			}
+		})
+	}
+
+	// Clients are told which scopes to request
+	req := httptest.NewRequest("GET", "/api/v1/catalog/fields", nil)
+	req = req.WithContext(authutils.SetAuthenticatedUser(req.Context(), unscopedUser))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("WWW-Authenticate"); got != `Bearer error="insufficient_scope", scope="catalog:read"` {
+		t.Errorf("WWW-Authenticate = %q", got)
+	}
+}
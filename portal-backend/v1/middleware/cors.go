@@ -36,10 +36,10 @@ func DefaultCORSConfig() CORSConfig {
 		},
 		AllowedHeaders: []string{
 			"Origin", "Content-Type", "Accept", "Authorization",
-			"X-Requested-With", "X-CSRF-Token", "X-Request-ID",
+			"X-Requested-With", "X-CSRF-Token", "X-Request-ID", "If-Match", "X-Impersonation-Session",
 		},
 		ExposedHeaders: []string{
-			"Content-Length", "X-Request-ID",
+			"Content-Length", "X-Request-ID", "ETag", "X-Impersonation-Session",
 		},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24 hours
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	sharedutils "github.com/gov-dx-sandbox/portal-backend/shared/utils"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	authutils "github.com/gov-dx-sandbox/portal-backend/v1/utils"
+)
+
+// impersonationPath is where admins start and stop impersonation sessions; requests to it are always
+// made as the admin, even if they still carry the session header
+const impersonationPath = "/api/v1/admin/impersonation"
+
+// ImpersonationResolver resolves the impersonation session an admin names in a request
+type ImpersonationResolver interface {
+	// ResolveImpersonation returns the user the admin acts as and the session details, or an error
+	// wrapping models.ErrImpersonationInactive if the session is not an active session of the admin
+	ResolveImpersonation(ctx context.Context, admin *models.AuthenticatedUser, sessionID string) (*models.AuthenticatedUser, *models.Impersonation, error)
+}
+
+// ImpersonationMiddleware lets support admins act as a member. When an authenticated admin names an
+// active impersonation session in the X-Impersonation-Session header, the request continues as the
+// session's member, with member permissions only, and the session is recorded in the request context
+// so that audit events name both the admin and the member.
+type ImpersonationMiddleware struct {
+	resolver ImpersonationResolver
+}
+
+// NewImpersonationMiddleware creates an impersonation middleware that resolves sessions with resolver
+func NewImpersonationMiddleware(resolver ImpersonationResolver) *ImpersonationMiddleware {
+	return &ImpersonationMiddleware{resolver: resolver}
+}
+
+// Impersonate returns a middleware function that applies the impersonation session of a request.
+// It must run after JWT authentication.
+func (m *ImpersonationMiddleware) Impersonate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := strings.TrimSpace(r.Header.Get(models.ImpersonationSessionHeader))
+		if sessionID == "" || strings.HasPrefix(r.URL.Path, impersonationPath) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		admin, err := GetUserFromRequest(r)
+		if err != nil {
+			sharedutils.RespondWithError(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		if !admin.HasPermission(models.PermissionImpersonateMember) {
+			slog.Warn("Impersonation denied: user cannot impersonate members", "user_id", admin.IdpUserID, "path", r.URL.Path)
+			sharedutils.RespondWithError(w, http.StatusForbidden, "Insufficient permissions to impersonate members")
+			return
+		}
+
+		user, impersonation, err := m.resolver.ResolveImpersonation(r.Context(), admin, sessionID)
+		if err != nil {
+			if errors.Is(err, models.ErrImpersonationInactive) {
+				sharedutils.RespondWithError(w, http.StatusForbidden, err.Error())
+				return
+			}
+			slog.Error("Failed to resolve impersonation session", "sessionID", sessionID, "error", err)
+			sharedutils.RespondWithError(w, http.StatusInternalServerError, "Failed to resolve impersonation session")
+			return
+		}
+
+		slog.Info("Impersonated request",
+			"sessionID", impersonation.SessionID,
+			"admin_user_id", impersonation.AdminIdpUserID,
+			"member_id", impersonation.MemberID,
+			"path", r.URL.Path,
+			"method", r.Method)
+
+		w.Header().Set(models.ImpersonationSessionHeader, impersonation.SessionID)
+		ctx := authutils.SetAuthenticatedUser(r.Context(), user)
+		ctx = authutils.SetImpersonation(ctx, impersonation)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/portal-backend/v1/utils"
+)
+
+// fakeImpersonationResolver resolves a single session of admin-user-id to the member mem_1
+type fakeImpersonationResolver struct{}
+
+func (fakeImpersonationResolver) ResolveImpersonation(_ context.Context, admin *models.AuthenticatedUser, sessionID string) (*models.AuthenticatedUser, *models.Impersonation, error) {
+	if sessionID != "imp_1" || admin.IdpUserID != "admin-user-id" {
+		return nil, nil, models.ErrImpersonationInactive
+	}
+	impersonation := &models.Impersonation{
+		SessionID:       sessionID,
+		AdminIdpUserID:  admin.IdpUserID,
+		MemberID:        "mem_1",
+		MemberIdpUserID: "member-user-id",
+		ExpiresAt:       time.Now().Add(time.Minute),
+	}
+	member := &models.Member{MemberID: "mem_1", IdpUserID: "member-user-id", Email: "member@example.com"}
+	return models.NewImpersonatedUser(admin, member, impersonation), impersonation, nil
+}
+
+func TestImpersonationMiddleware(t *testing.T) {
+	mockClient := newMockAuditClient(true)
+	var seen *models.AuthenticatedUser
+	handler := NewImpersonationMiddleware(fakeImpersonationResolver{}).Impersonate(
+		NewAuditMiddleware(mockClient).AuditRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen, _ = GetUserFromRequest(r)
+			w.WriteHeader(http.StatusOK)
+		})),
+	)
+
+	t.Run("An active session acts as the member and audits both people", func(t *testing.T) {
+		req := auditedRequest(t, http.MethodPut, "/api/v1/schemas/sch_1")
+		req.Header.Set(models.ImpersonationSessionHeader, "imp_1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", rec.Code)
+		}
+		if seen.IdpUserID != "member-user-id" || seen.IsAdmin() {
+			t.Errorf("Expected the request to be made as the member, got %s with roles %v", seen.IdpUserID, seen.Roles)
+		}
+		if memberID, cached := seen.GetCachedMemberID(); !cached || memberID != "mem_1" {
+			t.Errorf("Expected cached member ID mem_1, got %q", memberID)
+		}
+
+		event, metadata := auditMetadata(t, mockClient)
+		if event.ActorType != string(models.ActorTypeAdmin) || event.ActorID != "admin-user-id" {
+			t.Errorf("Expected the admin to be the actor, got %s/%s", event.ActorType, event.ActorID)
+		}
+		impersonation, ok := metadata["impersonation"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected impersonation in metadata, got %v", metadata)
+		}
+		if impersonation["memberId"] != "mem_1" || impersonation["adminIdpUserId"] != "admin-user-id" || impersonation["sessionId"] != "imp_1" {
+			t.Errorf("Unexpected impersonation metadata %v", impersonation)
+		}
+	})
+
+	t.Run("An inactive session is rejected", func(t *testing.T) {
+		req := auditedRequest(t, http.MethodGet, "/api/v1/schemas")
+		req.Header.Set(models.ImpersonationSessionHeader, "imp_other")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Members cannot impersonate", func(t *testing.T) {
+		now := time.Now().Unix()
+		member, err := models.NewAuthenticatedUser(&models.UserClaims{
+			IdpUserID: "admin-user-id",
+			Roles:     models.FlexibleStringSlice([]string{"OpenDIF_Member"}),
+			IssuedAt:  now,
+			ExpiresAt: now + 3600,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create authenticated user: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/schemas", nil)
+		req = req.WithContext(utils.SetAuthenticatedUser(req.Context(), member))
+		req.Header.Set(models.ImpersonationSessionHeader, "imp_1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Impersonation endpoints are always called as the admin", func(t *testing.T) {
+		req := auditedRequest(t, http.MethodGet, "/api/v1/admin/impersonation")
+		req.Header.Set(models.ImpersonationSessionHeader, "imp_other")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || seen.IdpUserID != "admin-user-id" {
+			t.Errorf("Expected the admin to reach the handler, got %d as %s", rec.Code, seen.IdpUserID)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -35,20 +37,39 @@ type JWK struct {
 	E   string `json:"e"`
 }
 
+const (
+	// DefaultJWKSRefreshInterval is how often the JWKS is refreshed when no interval is configured
+	DefaultJWKSRefreshInterval = 15 * time.Minute
+	// DefaultJWKSMaxStaleness is how long cached keys are trusted while the JWKS cannot be fetched
+	DefaultJWKSMaxStaleness = 24 * time.Hour
+	// DefaultJWKSMinRefreshInterval is the minimum time between JWKS requests triggered by tokens
+	DefaultJWKSMinRefreshInterval = 30 * time.Second
+)
+
 // JWTAuthMiddleware provides JWT authentication functionality
 // Thread-safe: All methods can be called concurrently from multiple goroutines
 type JWTAuthMiddleware struct {
-	jwksURL        string
-	expectedIssuer string
-	validClientIDs []string
-	orgName        string
-	httpClient     *http.Client
+	jwksURL            string
+	expectedIssuer     string
+	validClientIDs     []string
+	orgName            string
+	httpClient         *http.Client
+	refreshInterval    time.Duration
+	maxStaleness       time.Duration
+	minRefreshInterval time.Duration
 
 	// Protected by keysMutex to prevent race conditions
-	// keysMutex guards both keys map and lastFetch time to ensure atomic updates
-	keysMutex sync.RWMutex
-	keys      map[string]*rsa.PublicKey
-	lastFetch time.Time
+	// keysMutex guards the keys map and the fetch state to ensure atomic updates
+	keysMutex    sync.RWMutex
+	keys         map[string]*rsa.PublicKey
+	lastFetch    time.Time // Last successful JWKS fetch
+	lastAttempt  time.Time // Last JWKS request, successful or not
+	lastFetchErr error     // Error of the last JWKS request
+
+	// fetchMutex lets one JWKS request run at a time; callers waiting for it use its result
+	fetchMutex sync.Mutex
+	// refreshing is set while a background refresh runs
+	refreshing atomic.Bool
 }
 
 // JWTAuthConfig contains configuration for JWT authentication
@@ -58,6 +79,15 @@ type JWTAuthConfig struct {
 	ValidClientIDs []string // Multiple valid client IDs for different portals
 	OrgName        string
 	Timeout        time.Duration
+
+	// RefreshInterval is how often the JWKS is refreshed in the background (default 15m)
+	RefreshInterval time.Duration
+	// MaxKeyStaleness is how long the last fetched keys keep being used while the JWKS cannot be
+	// fetched, such as during identity provider downtime (default 24h)
+	MaxKeyStaleness time.Duration
+	// MinRefreshInterval is the minimum time between JWKS requests triggered by tokens signed with
+	// an unknown key ID or arriving after a refresh is due (default 30s)
+	MinRefreshInterval time.Duration
 }
 
 // Validate checks if the JWT configuration is valid
@@ -81,6 +111,18 @@ func (c JWTAuthConfig) Validate() error {
 		}
 	}
 
+	if c.RefreshInterval < 0 || c.MaxKeyStaleness < 0 || c.MinRefreshInterval < 0 {
+		return fmt.Errorf("JWKS refresh intervals and staleness must not be negative")
+	}
+
+	refreshInterval := c.RefreshInterval
+	if refreshInterval == 0 {
+		refreshInterval = DefaultJWKSRefreshInterval
+	}
+	if c.MaxKeyStaleness > 0 && refreshInterval > c.MaxKeyStaleness {
+		return fmt.Errorf("MaxKeyStaleness (%s) must not be shorter than the JWKS refresh interval (%s)", c.MaxKeyStaleness, refreshInterval)
+	}
+
 	return nil
 }
 
@@ -91,6 +133,21 @@ func NewJWTAuthMiddleware(config JWTAuthConfig) *JWTAuthMiddleware {
 		timeout = 10 * time.Second
 	}
 
+	refreshInterval := config.RefreshInterval
+	if refreshInterval == 0 {
+		refreshInterval = DefaultJWKSRefreshInterval
+	}
+
+	maxStaleness := config.MaxKeyStaleness
+	if maxStaleness == 0 {
+		maxStaleness = max(DefaultJWKSMaxStaleness, refreshInterval)
+	}
+
+	minRefreshInterval := config.MinRefreshInterval
+	if minRefreshInterval == 0 {
+		minRefreshInterval = DefaultJWKSMinRefreshInterval
+	}
+
 	return &JWTAuthMiddleware{
 		jwksURL:        config.JWKSURL,
 		expectedIssuer: config.ExpectedIssuer,
@@ -99,10 +156,64 @@ func NewJWTAuthMiddleware(config JWTAuthConfig) *JWTAuthMiddleware {
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		keys: make(map[string]*rsa.PublicKey),
+		refreshInterval:    refreshInterval,
+		maxStaleness:       maxStaleness,
+		minRefreshInterval: minRefreshInterval,
+		keys:               make(map[string]*rsa.PublicKey),
 	}
 }
 
+// Start refreshes the JWKS every refresh interval until ctx is done, so that rotated keys are
+// known before tokens signed with them arrive. A failed refresh keeps the cached keys.
+func (j *JWTAuthMiddleware) Start(ctx context.Context) {
+	slog.Info("JWKS refresh started", "interval", j.refreshInterval, "maxStaleness", j.maxStaleness)
+	if err := j.refreshKeys(time.Now()); err != nil {
+		slog.Warn("Initial JWKS fetch failed, retrying on the next request", "error", err)
+	}
+
+	ticker := time.NewTicker(j.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("JWKS refresh stopped")
+			return
+		case <-ticker.C:
+			if err := j.refreshKeys(time.Now()); err != nil {
+				slog.Warn("JWKS refresh failed, using cached keys", "error", err, "lastFetch", j.LastFetch())
+			}
+		}
+	}
+}
+
+// LastFetch returns when the JWKS was last fetched successfully, zero if it never was
+func (j *JWTAuthMiddleware) LastFetch() time.Time {
+	j.keysMutex.RLock()
+	defer j.keysMutex.RUnlock()
+	return j.lastFetch
+}
+
+// CheckKeys reports whether tokens can be validated: the JWKS was fetched and its keys are not
+// older than the staleness window
+func (j *JWTAuthMiddleware) CheckKeys(ctx context.Context) error {
+	j.keysMutex.RLock()
+	defer j.keysMutex.RUnlock()
+
+	var problem string
+	if j.lastFetch.IsZero() {
+		problem = "JWKS has not been fetched"
+	} else if age := time.Since(j.lastFetch); age > j.maxStaleness {
+		problem = fmt.Sprintf("JWKS keys are %s old, older than the %s staleness window", age.Round(time.Second), j.maxStaleness)
+	} else {
+		return nil
+	}
+	if j.lastFetchErr != nil {
+		return fmt.Errorf("%s: %w", problem, j.lastFetchErr)
+	}
+	return errors.New(problem)
+}
+
 // AuthenticateJWT returns a middleware function that validates JWT tokens
 func (j *JWTAuthMiddleware) AuthenticateJWT(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -154,11 +265,6 @@ func (j *JWTAuthMiddleware) AuthenticateJWT(next http.Handler) http.Handler {
 
 // validateToken validates a JWT token and returns the authenticated user
 func (j *JWTAuthMiddleware) validateToken(tokenString string) (*models.AuthenticatedUser, *models.AuthContext, error) {
-	// Ensure we have fresh JWKS keys
-	if err := j.ensureKeysFresh(); err != nil {
-		return nil, nil, fmt.Errorf("failed to ensure fresh keys: %w", err)
-	}
-
 	// Parse and validate the token
 	token, err := jwt.ParseWithClaims(tokenString, &models.UserClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
@@ -172,29 +278,7 @@ func (j *JWTAuthMiddleware) validateToken(tokenString string) (*models.Authentic
 			return nil, fmt.Errorf("missing 'kid' in token header")
 		}
 
-		// Find the public key with read lock
-		j.keysMutex.RLock()
-		publicKey, exists := j.keys[kid]
-		j.keysMutex.RUnlock()
-
-		if !exists {
-			// Try to refresh keys once
-			slog.Info("Key not found, refreshing JWKS", "kid", kid)
-			if err := j.fetchJWKS(); err != nil {
-				return nil, fmt.Errorf("failed to refresh JWKS: %w", err)
-			}
-
-			// Check again after refresh with read lock
-			j.keysMutex.RLock()
-			publicKey, exists = j.keys[kid]
-			j.keysMutex.RUnlock()
-
-			if !exists {
-				return nil, fmt.Errorf("no public key found for kid: %s", kid)
-			}
-		}
-
-		return publicKey, nil
+		return j.publicKey(kid)
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse token: %w", err)
@@ -283,6 +367,90 @@ func (j *JWTAuthMiddleware) containsValidClientID(audiences models.FlexibleStrin
 	return false
 }
 
+// publicKey returns the cached key with the given key ID. Keys due for a refresh are used while
+// the JWKS is refreshed in the background, and keys past the staleness window only if it cannot be
+// fetched. An unknown key ID, as after a key rotation, refreshes the JWKS once.
+func (j *JWTAuthMiddleware) publicKey(kid string) (*rsa.PublicKey, error) {
+	j.keysMutex.RLock()
+	publicKey, exists := j.keys[kid]
+	lastFetch, lastAttempt := j.lastFetch, j.lastAttempt
+	j.keysMutex.RUnlock()
+
+	now := time.Now()
+	age := now.Sub(lastFetch)
+	canRefresh := now.Sub(lastAttempt) >= j.minRefreshInterval
+
+	if exists && age <= j.maxStaleness {
+		if age > j.refreshInterval && canRefresh {
+			j.refreshInBackground()
+		}
+		return publicKey, nil
+	}
+
+	// Requests are not let through to the JWKS endpoint faster than the minimum refresh interval,
+	// so tokens with made-up key IDs or an identity provider outage do not flood it
+	if !canRefresh {
+		if exists {
+			return nil, fmt.Errorf("JWKS keys are older than %s and could not be refreshed", j.maxStaleness)
+		}
+		return nil, fmt.Errorf("no public key found for kid: %s", kid)
+	}
+
+	if exists {
+		slog.Info("JWKS keys are stale, refreshing JWKS", "kid", kid, "lastFetch", lastFetch)
+	} else {
+		slog.Info("Key not found, refreshing JWKS", "kid", kid)
+	}
+	if err := j.refreshKeys(now); err != nil {
+		return nil, fmt.Errorf("failed to refresh JWKS: %w", err)
+	}
+
+	j.keysMutex.RLock()
+	publicKey, exists = j.keys[kid]
+	j.keysMutex.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no public key found for kid: %s", kid)
+	}
+	return publicKey, nil
+}
+
+// refreshInBackground refreshes the JWKS without blocking the caller, unless a refresh is already
+// running
+func (j *JWTAuthMiddleware) refreshInBackground() {
+	if !j.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer j.refreshing.Store(false)
+		if err := j.refreshKeys(time.Now()); err != nil {
+			slog.Warn("JWKS refresh failed, using cached keys", "error", err, "lastFetch", j.LastFetch())
+		}
+	}()
+}
+
+// refreshKeys fetches the JWKS unless it was requested after since, in which case the caller
+// waited for that request and shares its result
+func (j *JWTAuthMiddleware) refreshKeys(since time.Time) error {
+	j.fetchMutex.Lock()
+	defer j.fetchMutex.Unlock()
+
+	j.keysMutex.RLock()
+	lastAttempt, lastErr := j.lastAttempt, j.lastFetchErr
+	j.keysMutex.RUnlock()
+	if lastAttempt.After(since) {
+		return lastErr
+	}
+
+	err := j.fetchJWKS()
+
+	j.keysMutex.Lock()
+	j.lastAttempt = time.Now()
+	j.lastFetchErr = err
+	j.keysMutex.Unlock()
+	return err
+}
+
 // fetchJWKS fetches the JWKS from the configured endpoint
 // Thread-safe: Updates keys and lastFetch atomically under write lock
 func (j *JWTAuthMiddleware) fetchJWKS() error {
@@ -329,6 +497,11 @@ func (j *JWTAuthMiddleware) fetchJWKS() error {
 		}
 	}
 
+	// A key set without usable keys would reject every token, so the cached keys are kept
+	if len(newKeys) == 0 {
+		return fmt.Errorf("JWKS contains no usable RSA signing keys")
+	}
+
 	// Update keys and lastFetch atomically with write lock
 	j.keysMutex.Lock()
 	j.keys = newKeys
@@ -373,20 +546,6 @@ func (j *JWTAuthMiddleware) buildRSAPublicKey(nStr, eStr string) (*rsa.PublicKey
 	}, nil
 }
 
-// ensureKeysFresh ensures we have fresh JWKS keys (refreshes if older than 1 hour)
-// Thread-safe: Uses read lock to check freshness, delegates to fetchJWKS for updates
-func (j *JWTAuthMiddleware) ensureKeysFresh() error {
-	// Check if refresh is needed with read lock
-	j.keysMutex.RLock()
-	needsRefresh := len(j.keys) == 0 || time.Since(j.lastFetch) > time.Hour
-	j.keysMutex.RUnlock()
-
-	if needsRefresh {
-		return j.fetchJWKS()
-	}
-	return nil
-}
-
 // shouldSkipAuth determines if authentication should be skipped for this path
 func (j *JWTAuthMiddleware) shouldSkipAuth(path string) bool {
 	skipPaths := []string{
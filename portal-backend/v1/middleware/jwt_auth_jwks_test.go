@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jwksServer serves the JWKS set with setKey and counts the requests made to it
+type jwksServer struct {
+	*httptest.Server
+	requests atomic.Int64
+
+	mu   sync.Mutex
+	body []byte
+	down bool
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(s.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) setKey(t *testing.T, pubKey *rsa.PublicKey, kid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body = createJWKSResponse(t, pubKey, kid)
+}
+
+func (s *jwksServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *jwksServer) middleware(config JWTAuthConfig) *JWTAuthMiddleware {
+	config.JWKSURL = s.URL
+	config.ExpectedIssuer = "https://example.com"
+	config.ValidClientIDs = []string{"client-1"}
+	return NewJWTAuthMiddleware(config)
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   "https://example.com",
+		"aud":   "client-1",
+		"email": "test@example.com",
+		"sub":   "user-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"OpenDIF_Member"},
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// backdateKeys makes the cached keys look as if they were fetched age ago
+func backdateKeys(j *JWTAuthMiddleware, age time.Duration) {
+	j.keysMutex.Lock()
+	defer j.keysMutex.Unlock()
+	j.lastFetch = time.Now().Add(-age)
+	j.lastAttempt = j.lastFetch
+}
+
+func TestJWTAuthMiddleware_CachesKeys(t *testing.T) {
+	privKey, pubKey := generateTestKeys(t)
+	server := newJWKSServer(t)
+	server.setKey(t, pubKey, "key-1")
+	middleware := server.middleware(JWTAuthConfig{})
+
+	for i := 0; i < 3; i++ {
+		_, _, err := middleware.validateToken(signTestToken(t, privKey, "key-1"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(1), server.requests.Load())
+	assert.NoError(t, middleware.CheckKeys(context.Background()))
+}
+
+func TestJWTAuthMiddleware_KeyRotation(t *testing.T) {
+	oldPrivKey, oldPubKey := generateTestKeys(t)
+	newPrivKey, newPubKey := generateTestKeys(t)
+	server := newJWKSServer(t)
+	server.setKey(t, oldPubKey, "key-1")
+	middleware := server.middleware(JWTAuthConfig{MinRefreshInterval: time.Hour})
+
+	_, _, err := middleware.validateToken(signTestToken(t, oldPrivKey, "key-1"))
+	require.NoError(t, err)
+
+	// Once the minimum interval has passed, a token signed with the rotated key refreshes the JWKS
+	backdateKeys(middleware, 2*time.Hour)
+	server.setKey(t, newPubKey, "key-2")
+	_, _, err = middleware.validateToken(signTestToken(t, newPrivKey, "key-2"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), server.requests.Load())
+
+	// Unknown key IDs do not refresh the JWKS again within the minimum interval
+	for i := 0; i < 3; i++ {
+		_, _, err = middleware.validateToken(signTestToken(t, newPrivKey, "key-3"))
+		assert.ErrorContains(t, err, "no public key found for kid: key-3")
+	}
+	assert.Equal(t, int64(2), server.requests.Load())
+}
+
+func TestJWTAuthMiddleware_IdentityProviderDowntime(t *testing.T) {
+	privKey, pubKey := generateTestKeys(t)
+	server := newJWKSServer(t)
+	server.setKey(t, pubKey, "key-1")
+	middleware := server.middleware(JWTAuthConfig{RefreshInterval: time.Minute, MaxKeyStaleness: time.Hour})
+
+	_, _, err := middleware.validateToken(signTestToken(t, privKey, "key-1"))
+	require.NoError(t, err)
+
+	// Keys due for a refresh are still used while the background refresh fails
+	server.setDown(true)
+	backdateKeys(middleware, 30*time.Minute)
+	_, _, err = middleware.validateToken(signTestToken(t, privKey, "key-1"))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return server.requests.Load() == 2 && !middleware.refreshing.Load() }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, middleware.CheckKeys(context.Background()))
+
+	// Past the staleness window the keys are no longer trusted
+	backdateKeys(middleware, 2*time.Hour)
+	_, _, err = middleware.validateToken(signTestToken(t, privKey, "key-1"))
+	assert.ErrorContains(t, err, "failed to refresh JWKS")
+	assert.ErrorContains(t, middleware.CheckKeys(context.Background()), "staleness window")
+
+	// Once the identity provider is back the keys are refreshed
+	server.setDown(false)
+	backdateKeys(middleware, 2*time.Hour)
+	_, _, err = middleware.validateToken(signTestToken(t, privKey, "key-1"))
+	require.NoError(t, err)
+	assert.NoError(t, middleware.CheckKeys(context.Background()))
+}
+
+func TestJWTAuthMiddleware_BackgroundRefresh(t *testing.T) {
+	privKey, pubKey := generateTestKeys(t)
+	server := newJWKSServer(t)
+	server.setKey(t, pubKey, "key-1")
+	middleware := server.middleware(JWTAuthConfig{RefreshInterval: 20 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go middleware.Start(ctx)
+
+	assert.Eventually(t, func() bool { return server.requests.Load() >= 3 }, time.Second, 10*time.Millisecond)
+	_, _, err := middleware.validateToken(signTestToken(t, privKey, "key-1"))
+	assert.NoError(t, err)
+}
+
+func TestJWTAuthMiddleware_EmptyKeySetKeepsKeys(t *testing.T) {
+	privKey, pubKey := generateTestKeys(t)
+	server := newJWKSServer(t)
+	server.setKey(t, pubKey, "key-1")
+	middleware := server.middleware(JWTAuthConfig{})
+	require.NoError(t, middleware.refreshKeys(time.Now()))
+
+	server.mu.Lock()
+	server.body = []byte(`{"keys":[]}`)
+	server.mu.Unlock()
+	assert.ErrorContains(t, middleware.refreshKeys(time.Now()), "no usable RSA signing keys")
+
+	_, _, err := middleware.validateToken(signTestToken(t, privKey, "key-1"))
+	assert.NoError(t, err)
+}
+
+func TestJWTAuthMiddleware_ConcurrentFirstFetch(t *testing.T) {
+	privKey, pubKey := generateTestKeys(t)
+	server := newJWKSServer(t)
+	server.setKey(t, pubKey, "key-1")
+	middleware := server.middleware(JWTAuthConfig{})
+	token := signTestToken(t, privKey, "key-1")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := middleware.validateToken(token)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1), server.requests.Load())
+}
@@ -95,6 +95,26 @@ func TestJWTAuthConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Negative refresh interval",
+			config: JWTAuthConfig{
+				JWKSURL:         "https://example.com/jwks",
+				ExpectedIssuer:  "https://example.com",
+				ValidClientIDs:  []string{"client-1"},
+				RefreshInterval: -time.Minute,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Staleness window shorter than refresh interval",
+			config: JWTAuthConfig{
+				JWKSURL:         "https://example.com/jwks",
+				ExpectedIssuer:  "https://example.com",
+				ValidClientIDs:  []string{"client-1"},
+				MaxKeyStaleness: time.Minute,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	sharedutils "github.com/gov-dx-sandbox/portal-backend/shared/utils"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/requestid"
+)
+
+// OpenAPIValidationConfig controls which messages OpenAPIValidationMiddleware checks against the document
+type OpenAPIValidationConfig struct {
+	// ValidateRequests rejects requests whose parameters or body do not match their operation with a 422
+	ValidateRequests bool
+	// ValidateResponses logs responses that do not match their operation. Responses are buffered to be
+	// checked, so it is meant for development.
+	ValidateResponses bool
+}
+
+// OpenAPIValidationMiddleware checks API traffic against the OpenAPI document of the v1 API and serves
+// the document. Requests to routes the document does not describe pass through unchecked.
+type OpenAPIValidationMiddleware struct {
+	config   OpenAPIValidationConfig
+	router   routers.Router
+	document []byte
+}
+
+// NewOpenAPIValidationMiddleware creates a validation middleware for spec, an OpenAPI 3 document in
+// YAML or JSON. It returns an error if the document is invalid.
+func NewOpenAPIValidationMiddleware(spec []byte, config OpenAPIValidationConfig) (*OpenAPIValidationMiddleware, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	document, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	// Routes are matched on their path only, whichever host the service is deployed behind
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+	return &OpenAPIValidationMiddleware{config: config, router: router, document: document}, nil
+}
+
+// ServeDocument responds with the OpenAPI document as JSON
+func (m *OpenAPIValidationMiddleware) ServeDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		sharedutils.RespondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(m.document)
+	}
+}
+
+// Validate returns a middleware function that checks requests, and optionally responses, against their
+// operation in the document. It should run after authentication and authorization, so that callers
+// without access are refused before their requests are inspected.
+func (m *OpenAPIValidationMiddleware) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.config.ValidateRequests && !m.config.ValidateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+		route, pathParams, err := m.router.FindRoute(r)
+		if err != nil {
+			// Routes missing from the document, and methods it does not list, are left to the handlers
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if m.config.ValidateRequests {
+			if err := openapi3filter.ValidateRequest(r.Context(), requestInput); err != nil {
+				slog.Debug("Request does not match the OpenAPI document", "method", r.Method, "path", r.URL.Path, "error", err)
+				body := models.NewValidationErrorResponse(&models.ValidationError{Fields: requestFieldErrors(err)})
+				body.RequestID = requestid.FromResponse(w)
+				sharedutils.RespondWithJSON(w, http.StatusUnprocessableEntity, body)
+				return
+			}
+		}
+
+		if !m.config.ValidateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+		recorder := &openAPIResponseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 recorder.statusCode,
+			Header:                 recorder.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options:                &openapi3filter.Options{MultiError: true},
+		}
+		if err := openapi3filter.ValidateResponse(r.Context(), responseInput); err != nil {
+			slog.Warn("Response does not match the OpenAPI document", "method", r.Method, "path", r.URL.Path,
+				"operation", route.Operation.OperationID, "status", recorder.statusCode, "error", err)
+		}
+	})
+}
+
+// requestFieldErrors converts the errors of openapi3filter.ValidateRequest into field errors
+func requestFieldErrors(err error) []models.FieldError {
+	// Only the top-level list is unpacked here; a request error wrapping a list keeps its context
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var fields []models.FieldError
+		for _, e := range multi {
+			fields = append(fields, requestFieldErrors(e)...)
+		}
+		return fields
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return []models.FieldError{models.NewFieldError(models.ValidationErrorInvalidRequest, "", err.Error())}
+	}
+
+	if parameter := requestErr.Parameter; parameter != nil {
+		if errors.Is(requestErr.Err, openapi3filter.ErrInvalidRequired) || errors.Is(requestErr.Err, openapi3filter.ErrInvalidEmptyValue) {
+			return []models.FieldError{models.NewFieldError(models.ValidationErrorRequired, parameter.Name,
+				fmt.Sprintf("%s %s parameter is required", parameter.Name, parameter.In))}
+		}
+		return schemaFieldErrors(requestErr.Err, parameter.Name,
+			fmt.Sprintf("%s %s parameter is invalid: %s", parameter.Name, parameter.In, requestErr.Error()))
+	}
+
+	if requestErr.RequestBody != nil {
+		if errors.Is(requestErr.Err, openapi3filter.ErrInvalidRequired) {
+			return []models.FieldError{models.NewFieldError(models.ValidationErrorRequired, "", "Request body is required")}
+		}
+		var parseErr *openapi3filter.ParseError
+		if errors.As(requestErr.Err, &parseErr) {
+			return []models.FieldError{models.NewFieldError(models.ValidationErrorMalformedBody, "", "Invalid request body")}
+		}
+		return schemaFieldErrors(requestErr.Err, "", requestErr.Error())
+	}
+	return []models.FieldError{models.NewFieldError(models.ValidationErrorInvalidRequest, "", requestErr.Error())}
+}
+
+// schemaFieldErrors converts the schema errors in err into field errors, naming body fields by their
+// JSON path below prefix. Errors that are not schema errors are reported with message.
+func schemaFieldErrors(err error, prefix, message string) []models.FieldError {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var fields []models.FieldError
+		for _, e := range multi {
+			fields = append(fields, schemaFieldErrors(e, prefix, message)...)
+		}
+		return fields
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return []models.FieldError{models.NewFieldError(models.ValidationErrorInvalidValue, prefix, message)}
+	}
+	field := prefix
+	for _, segment := range schemaErr.JSONPointer() {
+		switch {
+		case isIndex(segment):
+			field += "[" + segment + "]"
+		case field == "":
+			field = segment
+		default:
+			field += "." + segment
+		}
+	}
+
+	if schemaErr.SchemaField == "required" {
+		return []models.FieldError{models.NewFieldError(models.ValidationErrorRequired, field, field+" is required")}
+	}
+	code := models.ValidationErrorInvalidValue
+	switch schemaErr.SchemaField {
+	case "format", "pattern":
+		code = models.ValidationErrorInvalidFormat
+	case "minItems", "minLength":
+		code = models.ValidationErrorTooShort
+	case "maxItems", "maxLength":
+		code = models.ValidationErrorTooLong
+	case "type":
+		code = models.ValidationErrorMalformedBody
+	}
+	if field == "" {
+		return []models.FieldError{models.NewFieldError(code, field, schemaErr.Reason)}
+	}
+	return []models.FieldError{models.NewFieldError(code, field, field+": "+schemaErr.Reason)}
+}
+
+// isIndex reports whether a JSON pointer segment is a list index
+func isIndex(segment string) bool {
+	return segment != "" && strings.Trim(segment, "0123456789") == ""
+}
+
+// openAPIResponseRecorder passes a response through while keeping a copy of its status and body
+type openAPIResponseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *openAPIResponseRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.statusCode = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *openAPIResponseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIValidationMiddleware(t *testing.T) {
+	spec, err := os.ReadFile("../../openapi.yaml")
+	require.NoError(t, err)
+	middleware, err := NewOpenAPIValidationMiddleware(spec, OpenAPIValidationConfig{ValidateRequests: true, ValidateResponses: true})
+	require.NoError(t, err)
+
+	called := false
+	handler := middleware.Validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"memberId":"mem_1"}`))
+	}))
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		called = false
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	fields := func(w *httptest.ResponseRecorder) map[string]models.ValidationErrorCode {
+		var response models.ValidationErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, models.ValidationFailedCode, response.Code)
+		codes := make(map[string]models.ValidationErrorCode)
+		for _, field := range response.Errors {
+			codes[field.Field] = field.Code
+		}
+		return codes
+	}
+
+	t.Run("Valid requests reach the handler", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/members", `{"name":"Member","email":"member@example.com","phoneNumber":"0771234567"}`)
+		assert.True(t, called)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"memberId":"mem_1"}`, w.Body.String())
+	})
+
+	t.Run("Missing body fields are rejected", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/members", `{"name":"Member"}`)
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, map[string]models.ValidationErrorCode{
+			"email":       models.ValidationErrorRequired,
+			"phoneNumber": models.ValidationErrorRequired,
+		}, fields(w))
+	})
+
+	t.Run("Malformed bodies are rejected", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/members", `{"name":`)
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, map[string]models.ValidationErrorCode{"": models.ValidationErrorMalformedBody}, fields(w))
+	})
+
+	t.Run("Invalid query parameters are rejected", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/v1/members?limit=1000", "")
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, map[string]models.ValidationErrorCode{"limit": models.ValidationErrorInvalidValue}, fields(w))
+	})
+
+	t.Run("Rejections carry the request ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/members?limit=1000", nil)
+		req.Header.Set(requestid.Header, "req-1")
+		w := httptest.NewRecorder()
+		requestid.Middleware(handler).ServeHTTP(w, req)
+
+		var response models.ValidationErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "req-1", response.RequestID)
+	})
+
+	t.Run("Undocumented routes pass through", func(t *testing.T) {
+		serve(http.MethodPost, "/api/v1/undocumented", `{"anything":true}`)
+		assert.True(t, called)
+	})
+
+	t.Run("The document is served as JSON", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		middleware.ServeDocument(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+		assert.Equal(t, "3.0.3", document["openapi"])
+		assert.Contains(t, document["paths"], "/api/v1/members")
+	})
+
+	t.Run("Invalid documents are refused", func(t *testing.T) {
+		_, err := NewOpenAPIValidationMiddleware([]byte("openapi: 3.0.3\npaths: {}\n"), OpenAPIValidationConfig{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	authutils "github.com/gov-dx-sandbox/portal-backend/v1/utils"
+	auditpkg "github.com/gov-dx-sandbox/shared/audit"
+)
+
+// maxSeenSecurityKeys bounds how many sessions and tokens a replica remembers having logged
+const maxSeenSecurityKeys = 10000
+
+// defaultSeenSecurityKeyLifetime is how long a token without an expiry is remembered
+const defaultSeenSecurityKeyLifetime = time.Hour
+
+// SecurityEventMiddleware logs the SECURITY_EVENTs of authenticated requests: the first request of each
+// IdP session as a LOGIN, the first request with each access token as TOKEN_ISSUED, and requests answered
+// 403 Forbidden as AUTHORIZATION_DENIED. A replica logs each session and token once while it remembers
+// it; the events are keyed by the session or token ID, so the audit service stores those logged by
+// several replicas only once.
+type SecurityEventMiddleware struct {
+	client auditpkg.AuditClient
+
+	// seenMutex guards seen, the keys of the logged sessions and tokens and until when they are remembered
+	seenMutex sync.Mutex
+	seen      map[string]time.Time
+}
+
+// NewSecurityEventMiddleware creates a security event middleware that logs through the given client
+func NewSecurityEventMiddleware(client auditpkg.AuditClient) *SecurityEventMiddleware {
+	return &SecurityEventMiddleware{client: client, seen: make(map[string]time.Time)}
+}
+
+// RecordSecurityEvents wraps next so that the sign-ins, new access tokens and denied requests of
+// authenticated users are logged. It runs after JWT authentication, before an impersonating admin's
+// identity is swapped for the member's, so events are attributed to the token's owner.
+func (m *SecurityEventMiddleware) RecordSecurityEvents(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.client == nil || !m.client.IsEnabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		user, err := GetUserFromRequest(r)
+		if err != nil || user == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		m.recordToken(r, user)
+
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.statusCode == http.StatusForbidden {
+			logSecurityEvent(m.client, r, securityEvent{
+				action:     models.SecurityActionAuthorizationDenied,
+				status:     models.AuditStatusFailure,
+				targetType: models.TargetTypeResource,
+				metadata: map[string]interface{}{
+					"method":     r.Method,
+					"path":       r.URL.Path,
+					"httpStatus": recorder.statusCode,
+				},
+			})
+		}
+	})
+}
+
+// recordToken logs a LOGIN for the user's session and a TOKEN_ISSUED for their access token, unless this
+// replica already logged them. Both are dated when the token was issued.
+func (m *SecurityEventMiddleware) recordToken(r *http.Request, user *models.AuthenticatedUser) {
+	tokenID := user.TokenID
+	if tokenID == "" {
+		// Without a jti the token itself identifies it; only its digest is recorded
+		authCtx, err := GetAuthContextFromRequest(r)
+		if err != nil || authCtx.Token == "" {
+			return
+		}
+		digest := sha256.Sum256([]byte(authCtx.Token))
+		tokenID = hex.EncodeToString(digest[:16])
+	}
+
+	now := time.Now()
+	until := user.ExpiresAt
+	if until.IsZero() {
+		until = now.Add(defaultSeenSecurityKeyLifetime)
+	}
+	issuedAt := user.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = now
+	}
+	client := map[string]interface{}{
+		"ipAddress": authutils.GetRequestIP(r),
+		"userAgent": r.UserAgent(),
+	}
+	if user.SessionID != "" {
+		client["sessionId"] = user.SessionID
+	}
+
+	if user.SessionID != "" && m.firstSeen("session:"+user.SessionID, until, now) {
+		logSecurityEvent(m.client, r, securityEvent{
+			eventID:    "security-login-" + user.SessionID,
+			timestamp:  issuedAt,
+			action:     models.SecurityActionLogin,
+			status:     models.AuditStatusSuccess,
+			targetType: models.TargetTypeService,
+			metadata:   client,
+		})
+	}
+	if m.firstSeen("token:"+tokenID, until, now) {
+		metadata := maps.Clone(client)
+		metadata["tokenId"] = tokenID
+		metadata["scopes"] = user.Scopes
+		if !user.ExpiresAt.IsZero() {
+			metadata["expiresAt"] = user.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		logSecurityEvent(m.client, r, securityEvent{
+			eventID:    "security-token-" + tokenID,
+			timestamp:  issuedAt,
+			action:     models.SecurityActionTokenIssued,
+			status:     models.AuditStatusSuccess,
+			targetType: models.TargetTypeService,
+			metadata:   metadata,
+		})
+	}
+}
+
+// firstSeen remembers key until the given time, reporting whether it was not already remembered. When
+// the replica remembers too many keys the expired ones are forgotten, and all of them if none expired.
+func (m *SecurityEventMiddleware) firstSeen(key string, until, now time.Time) bool {
+	m.seenMutex.Lock()
+	defer m.seenMutex.Unlock()
+
+	if expiry, ok := m.seen[key]; ok && now.Before(expiry) {
+		return false
+	}
+	if len(m.seen) >= maxSeenSecurityKeys {
+		for seenKey, expiry := range m.seen {
+			if !now.Before(expiry) {
+				delete(m.seen, seenKey)
+			}
+		}
+		if len(m.seen) >= maxSeenSecurityKeys {
+			clear(m.seen)
+		}
+	}
+	m.seen[key] = until
+	return true
+}
+
+// statusRecorder captures the status of a response. It forwards flushes, so streamed exports still reach
+// the client page by page.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.statusCode = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// securityEvent is a SECURITY_EVENT of the user of a request
+type securityEvent struct {
+	// eventID makes the audit service store the event once however often it is logged; empty lets the
+	// client assign one
+	eventID string
+	// timestamp is when the event happened; zero is now
+	timestamp  time.Time
+	action     models.SecurityEventAction
+	status     models.AuditStatus
+	targetType models.TargetType
+	targetID   string
+	metadata   map[string]interface{}
+}
+
+// LogSecurityEvent logs a SECURITY_EVENT of the request's user on the resource targetID through the
+// global audit client, e.g. the rotation of an application's credential
+func LogSecurityEvent(r *http.Request, action models.SecurityEventAction, status models.AuditStatus, targetID string, metadata map[string]interface{}) {
+	globalMiddleware := auditpkg.GetGlobalAuditMiddleware()
+	if globalMiddleware == nil {
+		slog.Warn("Security event logging skipped: globalAuditMiddleware is not initialized")
+		return
+	}
+	logSecurityEvent(globalMiddleware.Client(), r, securityEvent{
+		action:     action,
+		status:     status,
+		targetType: models.TargetTypeResource,
+		targetID:   targetID,
+		metadata:   metadata,
+	})
+}
+
+// logSecurityEvent logs event as a SECURITY_EVENT of the request's actor
+func logSecurityEvent(client auditpkg.AuditClient, r *http.Request, event securityEvent) {
+	if client == nil || !client.IsEnabled() {
+		return
+	}
+
+	actorType, actorIDPtr, _ := extractActorInfoFromRequest(r)
+	if actorIDPtr == nil {
+		slog.Warn("Cannot log security event: no actor ID found")
+		return
+	}
+
+	eventType := models.SecurityEventType
+	eventAction := string(event.action)
+	timestamp := auditpkg.CurrentTimestamp()
+	if !event.timestamp.IsZero() {
+		timestamp = event.timestamp.UTC().Format(time.RFC3339)
+	}
+
+	auditRequest := &auditpkg.AuditLogRequest{
+		Timestamp:          timestamp,
+		EventType:          &eventType,
+		EventAction:        &eventAction,
+		Status:             string(event.status),
+		ActorType:          actorType,
+		ActorID:            *actorIDPtr,
+		TargetType:         string(event.targetType),
+		AdditionalMetadata: auditpkg.MarshalMetadata(event.metadata),
+	}
+	if event.eventID != "" {
+		auditRequest.EventID = &event.eventID
+	}
+	if event.targetID != "" {
+		auditRequest.TargetID = &event.targetID
+	}
+
+	// Log asynchronously using background context, as the request may finish before the event is sent
+	client.LogEvent(context.Background(), auditRequest)
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/portal-backend/v1/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSecurityEventRequest returns a request authenticated with a token of the given jti and sid
+func newSecurityEventRequest(t *testing.T, tokenID, sessionID string) *http.Request {
+	t.Helper()
+	issuedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	user, err := models.NewAuthenticatedUser(&models.UserClaims{
+		IdpUserID: "idp-user-1",
+		Email:     "member@example.com",
+		Roles:     models.FlexibleStringSlice{string(models.RoleMember)},
+		TokenID:   tokenID,
+		SessionID: sessionID,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/members/mem_1", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	ctx := utils.SetAuthenticatedUser(req.Context(), user)
+	ctx = utils.SetAuthContext(ctx, &models.AuthContext{User: user, Token: "token-" + tokenID})
+	return req.WithContext(ctx)
+}
+
+func TestSecurityEventMiddleware_LogsSessionAndTokenOnce(t *testing.T) {
+	client := newMockAuditClient(true)
+	handler := NewSecurityEventMiddleware(client).RecordSecurityEvents(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newSecurityEventRequest(t, "jti-1", "sid-1"))
+	handler.ServeHTTP(httptest.NewRecorder(), newSecurityEventRequest(t, "jti-1", "sid-1"))
+	// A refreshed token of the same session is a new token but not a new login
+	handler.ServeHTTP(httptest.NewRecorder(), newSecurityEventRequest(t, "jti-2", "sid-1"))
+
+	require.Len(t, client.receivedEvents, 3)
+	login := client.receivedEvents[0]
+	assert.Equal(t, models.SecurityEventType, *login.EventType)
+	assert.Equal(t, string(models.SecurityActionLogin), *login.EventAction)
+	assert.Equal(t, "security-login-sid-1", *login.EventID)
+	assert.Equal(t, "idp-user-1", login.ActorID)
+	assert.Equal(t, string(models.ActorTypeMember), login.ActorType)
+	assert.Equal(t, "2026-10-01T08:00:00Z", login.Timestamp, "dated when the token was issued")
+
+	token := client.receivedEvents[1]
+	assert.Equal(t, string(models.SecurityActionTokenIssued), *token.EventAction)
+	assert.Equal(t, "security-token-jti-1", *token.EventID)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(token.AdditionalMetadata, &metadata))
+	assert.Equal(t, "jti-1", metadata["tokenId"])
+	assert.Equal(t, "sid-1", metadata["sessionId"])
+	assert.Equal(t, "test-agent", metadata["userAgent"])
+
+	assert.Equal(t, "security-token-jti-2", *client.receivedEvents[2].EventID)
+}
+
+func TestSecurityEventMiddleware_TokenWithoutID(t *testing.T) {
+	client := newMockAuditClient(true)
+	handler := NewSecurityEventMiddleware(client).RecordSecurityEvents(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newSecurityEventRequest(t, "", ""))
+
+	require.Len(t, client.receivedEvents, 1)
+	event := client.receivedEvents[0]
+	assert.Equal(t, string(models.SecurityActionTokenIssued), *event.EventAction)
+	assert.Regexp(t, "^security-token-[0-9a-f]{32}$", *event.EventID, "identified by the token's digest")
+	assert.NotContains(t, string(event.AdditionalMetadata), "token-", "the token itself is not recorded")
+}
+
+func TestSecurityEventMiddleware_LogsDeniedRequests(t *testing.T) {
+	client := newMockAuditClient(true)
+	middleware := NewSecurityEventMiddleware(client)
+	denied := middleware.RecordSecurityEvents(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	denied.ServeHTTP(httptest.NewRecorder(), newSecurityEventRequest(t, "jti-1", ""))
+
+	require.Len(t, client.receivedEvents, 2)
+	event := client.receivedEvents[1]
+	assert.Equal(t, string(models.SecurityActionAuthorizationDenied), *event.EventAction)
+	assert.Equal(t, string(models.AuditStatusFailure), event.Status)
+	assert.Nil(t, event.EventID, "every denial is its own event")
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(event.AdditionalMetadata, &metadata))
+	assert.Equal(t, "/api/v1/members/mem_1", metadata["path"])
+	assert.Equal(t, float64(http.StatusForbidden), metadata["httpStatus"])
+}
+
+func TestSecurityEventMiddleware_SkipsUnauthenticatedRequests(t *testing.T) {
+	client := newMockAuditClient(true)
+	handler := NewSecurityEventMiddleware(client).RecordSecurityEvents(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/members", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, client.receivedEvents)
+}
+
+func TestSecurityEventMiddleware_FirstSeenIsBounded(t *testing.T) {
+	middleware := NewSecurityEventMiddleware(newMockAuditClient(true))
+	now := time.Now()
+
+	assert.True(t, middleware.firstSeen("token:a", now.Add(time.Minute), now))
+	assert.False(t, middleware.firstSeen("token:a", now.Add(time.Minute), now))
+	assert.True(t, middleware.firstSeen("token:a", now.Add(time.Hour), now.Add(2*time.Minute)), "expired keys are seen again")
+
+	for i := len(middleware.seen); i < maxSeenSecurityKeys; i++ {
+		middleware.seen[fmt.Sprintf("token:filler-%d", i)] = now.Add(time.Hour)
+	}
+	assert.True(t, middleware.firstSeen("token:b", now.Add(time.Hour), now))
+	assert.LessOrEqual(t, len(middleware.seen), maxSeenSecurityKeys)
+}
@@ -0,0 +1,300 @@
+// Package migrations applies the versioned SQL migrations that define the V1 database schema.
+//
+// Migrations live in sql/ as pairs of NNNNNN_name.up.sql and NNNNNN_name.down.sql files and are run
+// with golang-migrate, which keeps the version a database is at in its schema_migrations table. The
+// migrate CLI can be pointed at the same directory and database, e.g. to force a version after a
+// migration was fixed by hand.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var embedded embed.FS
+
+var (
+	// ErrDirty is returned when the schema_migrations table records a migration that failed halfway
+	ErrDirty = errors.New("database schema is dirty")
+	// ErrSchemaMismatch is returned when the database is not at the latest version of the migrations
+	ErrSchemaMismatch = errors.New("database schema version does not match the migrations")
+	// ErrUnknownVersion is returned when the database is at a version none of the migrations define,
+	// e.g. because it was migrated by a newer build
+	ErrUnknownVersion = errors.New("database schema is at an unknown version")
+)
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// drivers open the golang-migrate driver of a database on one of its connections, by GORM dialect.
+// Closing the driver returns the connection to the pool and leaves the pool open.
+var drivers = map[string]func(ctx context.Context, db *sql.DB) (database.Driver, error){
+	"postgres": func(ctx context.Context, db *sql.DB) (database.Driver, error) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return driver, nil
+	},
+}
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version uint64
+	Name    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version uint64 `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// Status reports the version a database is at compared to the migrations of this build
+type Status struct {
+	CurrentVersion uint64            `json:"currentVersion"`
+	LatestVersion  uint64            `json:"latestVersion"`
+	Dirty          bool              `json:"dirty"`
+	UpToDate       bool              `json:"upToDate"`
+	Migrations     []MigrationStatus `json:"migrations"`
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	db         *gorm.DB
+	fsys       fs.FS
+	dir        string
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the migrations embedded in this build
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	return newMigrator(db, embedded, "sql")
+}
+
+func newMigrator(db *gorm.DB, fsys fs.FS, dir string) (*Migrator, error) {
+	migrations, err := load(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, fsys: fsys, dir: dir, migrations: migrations}, nil
+}
+
+// load lists the migrations in dir ordered by version. Every version needs both an up and a down file.
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	files := make(map[uint64]int)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+		files[version]++
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if files[migration.Version] != 2 {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// LatestVersion returns the version of the newest migration, or 0 if there are none
+func (m *Migrator) LatestVersion() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status returns the version the database is at and which migrations have been applied
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	current, dirty, err := readVersion(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{
+		CurrentVersion: current,
+		LatestVersion:  m.LatestVersion(),
+		Dirty:          dirty,
+		Migrations:     make([]MigrationStatus, 0, len(m.migrations)),
+	}
+	status.UpToDate = !dirty && current == status.LatestVersion
+	for _, migration := range m.migrations {
+		status.Migrations = append(status.Migrations, MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= current,
+		})
+	}
+	return status, nil
+}
+
+// Verify returns an error wrapping ErrSchemaMismatch unless the database is at the latest version.
+// It is used at startup so that a build never runs against a schema it was not written for.
+func (m *Migrator) Verify(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.UpToDate {
+		return fmt.Errorf("%w: database is at version %d (dirty: %t), this build expects version %d",
+			ErrSchemaMismatch, status.CurrentVersion, status.Dirty, status.LatestVersion)
+	}
+	return nil
+}
+
+// Up applies every pending migration and returns how many were applied. A failing migration leaves
+// the database dirty at its version, to be fixed by hand and forced with the migrate CLI.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.migrate(ctx, func(instance *migrate.Migrate, index int) error {
+		return instance.Up()
+	})
+}
+
+// Down rolls back the newest steps applied migrations and returns how many were rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack, err := m.migrate(ctx, func(instance *migrate.Migrate, index int) error {
+		// Only the applied migrations can be rolled back
+		if steps > index+1 {
+			steps = index + 1
+		}
+		if steps <= 0 {
+			return nil
+		}
+		return instance.Steps(-steps)
+	})
+	return -rolledBack, err
+}
+
+// indexOf returns the index of the migration at version, or -1 for version 0
+func (m *Migrator) indexOf(version uint64) (int, error) {
+	if version == 0 {
+		return -1, nil
+	}
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %d, the latest migration is %d", ErrUnknownVersion, version, m.LatestVersion())
+}
+
+// migrate runs fn with a golang-migrate instance and the index of the migration the database is at,
+// and returns by how many migrations the database moved forward. golang-migrate holds its advisory
+// lock while it migrates, so instances starting together apply each migration once.
+func (m *Migrator) migrate(ctx context.Context, fn func(instance *migrate.Migrate, index int) error) (int, error) {
+	open, ok := drivers[m.db.Dialector.Name()]
+	if !ok {
+		return 0, fmt.Errorf("migrations are not supported on %s databases", m.db.Dialector.Name())
+	}
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+	driver, err := open(ctx, sqlDB)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open migration driver: %w", err)
+	}
+	source, err := iofs.New(m.fsys, m.dir)
+	if err != nil {
+		driver.Close()
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	instance, err := migrate.NewWithInstance("iofs", source, m.db.Dialector.Name(), driver)
+	if err != nil {
+		source.Close()
+		driver.Close()
+		return 0, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	defer instance.Close()
+
+	before, err := m.versionIndex(instance)
+	if err != nil {
+		return 0, err
+	}
+	if err := fn(instance, before); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return 0, err
+	}
+	after, err := m.versionIndex(instance)
+	if err != nil {
+		return 0, err
+	}
+	return after - before, nil
+}
+
+// versionIndex returns the index of the migration the database is at, refusing dirty and unknown versions
+func (m *Migrator) versionIndex(instance *migrate.Migrate) (int, error) {
+	version, dirty, err := instance.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d: fix the schema by hand, then force the version with the migrate CLI", ErrDirty, version)
+	}
+	return m.indexOf(uint64(version))
+}
+
+// readVersion returns the version recorded in schema_migrations, or 0 if no migration was applied
+func readVersion(db *gorm.DB) (uint64, bool, error) {
+	if !db.Migrator().HasTable("schema_migrations") {
+		return 0, false, nil
+	}
+	var rows []struct {
+		Version uint64
+		Dirty   bool
+	}
+	if err := db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&rows).Error; err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Version, rows[0].Dirty, nil
+}
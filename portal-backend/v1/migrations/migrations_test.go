@@ -0,0 +1,203 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func init() {
+	drivers["sqlite"] = func(ctx context.Context, db *sql.DB) (database.Driver, error) {
+		driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+		if err != nil {
+			return nil, err
+		}
+		return sharedDriver{driver}, nil
+	}
+}
+
+// sharedDriver keeps the in-memory test database open when golang-migrate closes its driver
+type sharedDriver struct {
+	database.Driver
+}
+
+func (sharedDriver) Close() error { return nil }
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	// Every connection to :memory: opens a separate database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+// TestMigrationsMatchModels guards against schema drift: every model must have a table with exactly
+// the columns of its fields once all migrations are applied
+func TestMigrationsMatchModels(t *testing.T) {
+	db := openTestDB(t)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	for _, model := range []interface{}{
+		&models.Organization{},
+		&models.Member{},
+		&models.Schema{},
+		&models.SchemaSubmission{},
+		&models.Application{},
+		&models.ApplicationSubmission{},
+		&models.PDPJob{},
+		&models.ApplicationCredential{},
+		&models.SubmissionReview{},
+		&models.SubmissionReviewer{},
+		&models.SubmissionComment{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.Invitation{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.ImpersonationSession{},
+		&models.ApplicationSandbox{},
+		&models.AdminApproval{},
+		&models.SavedView{},
+		&models.SubmissionAttachment{},
+		&models.FieldConfigurationTemplate{},
+	} {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		table := stmt.Schema.Table
+
+		columnTypes, err := db.Migrator().ColumnTypes(table)
+		require.NoError(t, err, table)
+		var columns []string
+		for _, columnType := range columnTypes {
+			columns = append(columns, columnType.Name())
+		}
+		var fields []string
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				fields = append(fields, field.DBName)
+			}
+		}
+		sort.Strings(columns)
+		sort.Strings(fields)
+		assert.Equal(t, fields, columns, "columns of %s", table)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"sql/000001_create_widgets.up.sql":   {Data: []byte("CREATE TABLE widgets (id text PRIMARY KEY);")},
+		"sql/000001_create_widgets.down.sql": {Data: []byte("DROP TABLE widgets;")},
+		"sql/000002_add_size.up.sql":         {Data: []byte("ALTER TABLE widgets ADD COLUMN size bigint;")},
+		"sql/000002_add_size.down.sql":       {Data: []byte("ALTER TABLE widgets DROP COLUMN size;")},
+	}
+	db := openTestDB(t)
+	migrator, err := newMigrator(db, fsys, "sql")
+	require.NoError(t, err)
+
+	t.Run("A new database is behind", func(t *testing.T) {
+		status, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), status.CurrentVersion)
+		assert.Equal(t, uint64(2), status.LatestVersion)
+		assert.False(t, status.UpToDate)
+		assert.ErrorIs(t, migrator.Verify(ctx), ErrSchemaMismatch)
+	})
+
+	t.Run("Up applies pending migrations once", func(t *testing.T) {
+		applied, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, applied)
+		assert.True(t, db.Migrator().HasColumn("widgets", "size"))
+		assert.NoError(t, migrator.Verify(ctx))
+
+		applied, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, applied)
+	})
+
+	t.Run("Down rolls back the newest migrations", func(t *testing.T) {
+		rolledBack, err := migrator.Down(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rolledBack)
+		assert.False(t, db.Migrator().HasColumn("widgets", "size"))
+
+		status, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), status.CurrentVersion)
+		assert.Equal(t, []MigrationStatus{
+			{Version: 1, Name: "create_widgets", Applied: true},
+			{Version: 2, Name: "add_size", Applied: false},
+		}, status.Migrations)
+
+		rolledBack, err = migrator.Down(ctx, 5)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rolledBack)
+		assert.False(t, db.Migrator().HasTable("widgets"))
+	})
+
+	t.Run("A failing migration leaves the database dirty at its version", func(t *testing.T) {
+		broken := fstest.MapFS{
+			"sql/000001_create_widgets.up.sql":   fsys["sql/000001_create_widgets.up.sql"],
+			"sql/000001_create_widgets.down.sql": fsys["sql/000001_create_widgets.down.sql"],
+			"sql/000002_broken.up.sql":           {Data: []byte("ALTER TABLE missing ADD COLUMN size bigint;")},
+			"sql/000002_broken.down.sql":         {Data: []byte("SELECT 1;")},
+		}
+		brokenMigrator, err := newMigrator(db, broken, "sql")
+		require.NoError(t, err)
+		_, err = brokenMigrator.Up(ctx)
+		assert.Error(t, err)
+
+		status, err := brokenMigrator.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), status.CurrentVersion)
+		assert.True(t, status.Dirty)
+		assert.ErrorIs(t, brokenMigrator.Verify(ctx), ErrSchemaMismatch)
+		assert.True(t, db.Migrator().HasTable("widgets"))
+
+		_, err = brokenMigrator.Up(ctx)
+		assert.ErrorIs(t, err, ErrDirty)
+	})
+
+	t.Run("Unknown versions are refused", func(t *testing.T) {
+		require.NoError(t, db.Exec("UPDATE schema_migrations SET version = ?, dirty = ?", 3, false).Error)
+		_, err := migrator.Up(ctx)
+		assert.ErrorIs(t, err, ErrUnknownVersion)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Every migration needs a down file", func(t *testing.T) {
+		_, err := load(fstest.MapFS{"sql/000001_a.up.sql": {Data: []byte("SELECT 1;")}}, "sql")
+		assert.ErrorContains(t, err, "needs both an up and a down file")
+	})
+
+	t.Run("Versions are unique", func(t *testing.T) {
+		_, err := load(fstest.MapFS{
+			"sql/000001_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"sql/000001_b.down.sql": {Data: []byte("SELECT 1;")},
+		}, "sql")
+		assert.ErrorContains(t, err, "is used by both")
+	})
+
+	t.Run("File names follow the migration layout", func(t *testing.T) {
+		_, err := load(fstest.MapFS{"sql/create_widgets.sql": {Data: []byte("SELECT 1;")}}, "sql")
+		assert.ErrorContains(t, err, "invalid migration file name")
+	})
+}
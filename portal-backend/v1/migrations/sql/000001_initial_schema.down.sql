@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS impersonation_sessions;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS invitations;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS submission_comments;
+DROP TABLE IF EXISTS submission_reviewers;
+DROP TABLE IF EXISTS submission_reviews;
+DROP TABLE IF EXISTS application_credentials;
+DROP TABLE IF EXISTS pdp_jobs;
+DROP TABLE IF EXISTS application_submissions;
+DROP TABLE IF EXISTS applications;
+DROP TABLE IF EXISTS schema_submissions;
+DROP TABLE IF EXISTS schemas;
+DROP TABLE IF EXISTS members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,282 @@
+-- Baseline schema of the V1 models as previously created by GORM AutoMigrate. Every statement is
+-- idempotent so that databases created by AutoMigrate can adopt versioned migrations by applying it.
+
+CREATE TABLE IF NOT EXISTS organizations (
+    organization_id text,
+    name text NOT NULL,
+    description text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id),
+    CONSTRAINT uni_organizations_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS members (
+    member_id text,
+    name text NOT NULL,
+    email text NOT NULL,
+    phone_number text NOT NULL,
+    idp_user_id text NOT NULL,
+    organization_id text,
+    organization_role text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamptz,
+    deleted_by text,
+    revision bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY (member_id),
+    CONSTRAINT uni_members_email UNIQUE (email),
+    CONSTRAINT uni_members_idp_user_id UNIQUE (idp_user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_members_deleted_at ON members (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_members_organization_id ON members (organization_id);
+
+CREATE TABLE IF NOT EXISTS schemas (
+    schema_id text,
+    member_id text NOT NULL,
+    organization_id text,
+    schema_name text NOT NULL,
+    sdl text NOT NULL,
+    endpoint text NOT NULL,
+    version text NOT NULL,
+    schema_description text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamptz,
+    deleted_by text,
+    revision bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY (schema_id)
+);
+CREATE INDEX IF NOT EXISTS idx_schemas_deleted_at ON schemas (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_schemas_organization_id ON schemas (organization_id);
+
+CREATE TABLE IF NOT EXISTS schema_submissions (
+    submission_id text,
+    previous_schema_id text,
+    schema_name text NOT NULL,
+    schema_description text,
+    sdl text NOT NULL,
+    schema_endpoint text NOT NULL,
+    status text NOT NULL,
+    member_id text NOT NULL,
+    organization_id text,
+    review text,
+    diff jsonb,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamptz,
+    deleted_by text,
+    PRIMARY KEY (submission_id),
+    CONSTRAINT fk_schema_submissions_previous_schema FOREIGN KEY (previous_schema_id) REFERENCES schemas(schema_id)
+);
+CREATE INDEX IF NOT EXISTS idx_schema_submissions_deleted_at ON schema_submissions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_schema_submissions_organization_id ON schema_submissions (organization_id);
+
+CREATE TABLE IF NOT EXISTS applications (
+    application_id text,
+    application_name text NOT NULL,
+    application_description text,
+    selected_fields jsonb NOT NULL,
+    member_id text NOT NULL,
+    organization_id text,
+    version text NOT NULL,
+    idp_application_id text,
+    idp_client_id text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamptz,
+    deleted_by text,
+    revision bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY (application_id)
+);
+CREATE INDEX IF NOT EXISTS idx_applications_deleted_at ON applications (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_applications_organization_id ON applications (organization_id);
+
+CREATE TABLE IF NOT EXISTS application_submissions (
+    submission_id text,
+    previous_application_id text,
+    application_name text NOT NULL,
+    application_description text,
+    selected_fields jsonb NOT NULL,
+    member_id text NOT NULL,
+    organization_id text,
+    status text NOT NULL,
+    review text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamptz,
+    deleted_by text,
+    PRIMARY KEY (submission_id),
+    CONSTRAINT fk_application_submissions_previous_application FOREIGN KEY (previous_application_id) REFERENCES applications(application_id)
+);
+CREATE INDEX IF NOT EXISTS idx_application_submissions_deleted_at ON application_submissions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_application_submissions_organization_id ON application_submissions (organization_id);
+
+CREATE TABLE IF NOT EXISTS pdp_jobs (
+    job_id text,
+    job_type text NOT NULL,
+    resource_id text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    max_attempts bigint NOT NULL,
+    next_retry_at timestamptz NOT NULL,
+    last_error text,
+    completed_at timestamptz,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_id)
+);
+CREATE INDEX IF NOT EXISTS idx_pdp_jobs_status_next_retry ON pdp_jobs (status,next_retry_at);
+CREATE INDEX IF NOT EXISTS idx_pdp_jobs_resource_id ON pdp_jobs (resource_id);
+
+CREATE TABLE IF NOT EXISTS application_credentials (
+    credential_id text,
+    application_id text NOT NULL,
+    client_id text NOT NULL,
+    secret_hint text NOT NULL,
+    status text NOT NULL,
+    issued_by text NOT NULL,
+    revoked_at timestamptz,
+    revoked_by text,
+    expires_at timestamptz,
+    expiry_notified_at timestamptz,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (credential_id)
+);
+CREATE INDEX IF NOT EXISTS idx_application_credentials_expires_at ON application_credentials (expires_at);
+CREATE INDEX IF NOT EXISTS idx_application_credentials_application_id ON application_credentials (application_id);
+
+CREATE TABLE IF NOT EXISTS submission_reviews (
+    review_id text,
+    submission_type text NOT NULL,
+    submission_id text NOT NULL,
+    step text NOT NULL,
+    reviewer_id text NOT NULL,
+    decision text NOT NULL,
+    comment text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (review_id)
+);
+CREATE INDEX IF NOT EXISTS idx_submission_reviews_submission ON submission_reviews (submission_type,submission_id);
+
+CREATE TABLE IF NOT EXISTS submission_reviewers (
+    submission_type text,
+    submission_id text,
+    step text,
+    reviewer_id text,
+    assigned_by text NOT NULL,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (submission_type,submission_id,step,reviewer_id)
+);
+
+CREATE TABLE IF NOT EXISTS submission_comments (
+    comment_id text,
+    submission_type text NOT NULL,
+    submission_id text NOT NULL,
+    author_id text NOT NULL,
+    step text NOT NULL,
+    body text NOT NULL,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id)
+);
+CREATE INDEX IF NOT EXISTS idx_submission_comments_submission ON submission_comments (submission_type,submission_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    notification_id text,
+    member_id text NOT NULL,
+    event text NOT NULL,
+    title text NOT NULL,
+    message text NOT NULL,
+    resource_type text NOT NULL,
+    resource_id text NOT NULL,
+    in_app boolean NOT NULL,
+    email_status text NOT NULL,
+    email_error text,
+    read_at timestamptz,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (notification_id)
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_email_status ON notifications (email_status);
+CREATE INDEX IF NOT EXISTS idx_notifications_member_id ON notifications (member_id);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    member_id text,
+    event text,
+    in_app boolean NOT NULL,
+    email boolean NOT NULL,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (member_id,event)
+);
+
+CREATE TABLE IF NOT EXISTS invitations (
+    invitation_id text,
+    email text NOT NULL,
+    role text NOT NULL,
+    organization_id text,
+    organization_role text,
+    token_hash text NOT NULL,
+    status text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    invited_by text NOT NULL,
+    accepted_at timestamptz,
+    member_id text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (invitation_id),
+    CONSTRAINT uni_invitations_token_hash UNIQUE (token_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_invitations_email ON invitations (email);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    webhook_id text,
+    application_id text NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events jsonb NOT NULL,
+    created_by text NOT NULL,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (webhook_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_application_id ON webhook_subscriptions (application_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    delivery_id text,
+    webhook_id text NOT NULL,
+    event text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    max_attempts bigint NOT NULL,
+    next_retry_at timestamptz NOT NULL,
+    response_status bigint,
+    last_error text,
+    delivered_at timestamptz,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (delivery_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status_next_retry ON webhook_deliveries (status,next_retry_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    session_id text,
+    admin_idp_user_id text NOT NULL,
+    admin_email text NOT NULL,
+    member_id text NOT NULL,
+    reason text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    ended_at timestamptz,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id)
+);
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_member_id ON impersonation_sessions (member_id);
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_admin_idp_user_id ON impersonation_sessions (admin_idp_user_id);
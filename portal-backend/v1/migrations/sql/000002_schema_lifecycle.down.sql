@@ -0,0 +1,2 @@
+ALTER TABLE schemas DROP COLUMN deprecation_message;
+ALTER TABLE schemas DROP COLUMN sunset_at;
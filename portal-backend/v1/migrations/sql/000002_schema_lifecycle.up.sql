@@ -0,0 +1,3 @@
+-- Sunset date and deprecation message of schemas being phased out
+ALTER TABLE schemas ADD COLUMN sunset_at timestamptz;
+ALTER TABLE schemas ADD COLUMN deprecation_message text;
@@ -0,0 +1,3 @@
+ALTER TABLE application_submissions DROP COLUMN renewal;
+ALTER TABLE applications DROP COLUMN grant_renewal_flagged_at;
+ALTER TABLE applications DROP COLUMN grant_expires_at;
@@ -0,0 +1,4 @@
+-- Expiry of the PDP grants of applications, and renewal submissions that extend them
+ALTER TABLE applications ADD COLUMN grant_expires_at timestamptz;
+ALTER TABLE applications ADD COLUMN grant_renewal_flagged_at timestamptz;
+ALTER TABLE application_submissions ADD COLUMN renewal boolean NOT NULL DEFAULT false;
@@ -0,0 +1,11 @@
+-- Sandbox IDP clients of applications, whose requests are routed to mock providers
+CREATE TABLE IF NOT EXISTS application_sandboxes (
+    application_id text,
+    idp_application_id text NOT NULL,
+    idp_client_id text NOT NULL,
+    provisioned_by text NOT NULL,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id),
+    CONSTRAINT uni_application_sandboxes_idp_client_id UNIQUE (idp_client_id)
+);
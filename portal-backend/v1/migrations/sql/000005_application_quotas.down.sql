@@ -0,0 +1,2 @@
+ALTER TABLE applications DROP COLUMN monthly_record_quota;
+ALTER TABLE applications DROP COLUMN daily_record_quota;
@@ -0,0 +1,3 @@
+-- Record-count quotas the orchestration engine enforces per application; NULL is unlimited
+ALTER TABLE applications ADD COLUMN daily_record_quota bigint;
+ALTER TABLE applications ADD COLUMN monthly_record_quota bigint;
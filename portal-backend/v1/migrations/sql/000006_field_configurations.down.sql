@@ -0,0 +1,2 @@
+ALTER TABLE schemas DROP COLUMN field_configurations;
+ALTER TABLE schema_submissions DROP COLUMN field_configurations;
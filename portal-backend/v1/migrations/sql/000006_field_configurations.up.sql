@@ -0,0 +1,4 @@
+-- Field configurations suggested from a submission's SDL and adjusted by its provider; they are
+-- applied to the policy metadata of the schema the submission is approved as
+ALTER TABLE schema_submissions ADD COLUMN field_configurations jsonb;
+ALTER TABLE schemas ADD COLUMN field_configurations jsonb;
@@ -0,0 +1,20 @@
+-- Sensitive admin actions requested by one admin and waiting for a second admin to confirm them
+CREATE TABLE IF NOT EXISTS admin_approvals (
+    approval_id text,
+    action text NOT NULL,
+    resource_id text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL,
+    requested_by text NOT NULL,
+    requested_by_email text NOT NULL,
+    decided_by text,
+    decided_by_email text,
+    decided_at timestamptz,
+    expires_at timestamptz NOT NULL,
+    last_error text,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (approval_id)
+);
+CREATE INDEX IF NOT EXISTS idx_admin_approvals_resource_id ON admin_approvals (resource_id);
+CREATE INDEX IF NOT EXISTS idx_admin_approvals_status ON admin_approvals (status);
@@ -0,0 +1,14 @@
+-- Named filters and sorts of the admin collections
+CREATE TABLE IF NOT EXISTS saved_views (
+    view_id text,
+    name text NOT NULL,
+    collection text NOT NULL,
+    query jsonb NOT NULL,
+    owner_idp_user_id text NOT NULL,
+    shared boolean NOT NULL DEFAULT false,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (view_id)
+);
+CREATE INDEX IF NOT EXISTS idx_saved_views_collection ON saved_views (collection);
+CREATE INDEX IF NOT EXISTS idx_saved_views_owner_idp_user_id ON saved_views (owner_idp_user_id);
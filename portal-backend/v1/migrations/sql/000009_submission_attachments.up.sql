@@ -0,0 +1,20 @@
+-- Documents attached to schema submissions, stored in object storage and scanned for viruses
+CREATE TABLE IF NOT EXISTS submission_attachments (
+    attachment_id text,
+    submission_id text NOT NULL,
+    category text NOT NULL,
+    file_name text NOT NULL,
+    content_type text NOT NULL,
+    size bigint NOT NULL,
+    storage_key text NOT NULL,
+    scan_status text NOT NULL DEFAULT 'pending',
+    scan_result text,
+    scan_attempts bigint NOT NULL DEFAULT 0,
+    scanned_at timestamptz,
+    uploaded_by text NOT NULL,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (attachment_id)
+);
+CREATE INDEX IF NOT EXISTS idx_submission_attachments_submission_id ON submission_attachments (submission_id);
+CREATE INDEX IF NOT EXISTS idx_submission_attachments_scan_status ON submission_attachments (scan_status);
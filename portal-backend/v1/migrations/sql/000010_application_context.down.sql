@@ -0,0 +1,3 @@
+ALTER TABLE organizations DROP COLUMN sector;
+ALTER TABLE applications DROP COLUMN status_reason;
+ALTER TABLE applications DROP COLUMN status;
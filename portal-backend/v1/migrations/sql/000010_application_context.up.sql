@@ -0,0 +1,5 @@
+-- Applications the orchestration engine rejects until they are reactivated, and the sector of
+-- organizations for PDP policy conditions
+ALTER TABLE applications ADD COLUMN status text NOT NULL DEFAULT 'active';
+ALTER TABLE applications ADD COLUMN status_reason text;
+ALTER TABLE organizations ADD COLUMN sector text;
@@ -0,0 +1,3 @@
+ALTER TABLE schema_submissions DROP COLUMN provider_type;
+ALTER TABLE organizations DROP COLUMN provider_type;
+DROP TABLE IF EXISTS field_configuration_templates;
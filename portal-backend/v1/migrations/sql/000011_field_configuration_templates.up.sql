@@ -0,0 +1,14 @@
+-- Default field configurations per provider type, and the provider type of organizations and of
+-- the schema submissions they pre-fill
+CREATE TABLE IF NOT EXISTS field_configuration_templates (
+    template_id text,
+    name text NOT NULL UNIQUE,
+    description text,
+    provider_type text NOT NULL UNIQUE,
+    rules jsonb NOT NULL,
+    created_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_id)
+);
+ALTER TABLE organizations ADD COLUMN provider_type text;
+ALTER TABLE schema_submissions ADD COLUMN provider_type text;
@@ -0,0 +1 @@
+ALTER TABLE application_submissions DROP COLUMN pdp_renewal_id;
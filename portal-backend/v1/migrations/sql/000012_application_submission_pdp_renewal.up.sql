@@ -0,0 +1,2 @@
+-- The PDP renewal request opened by a renewal submission
+ALTER TABLE application_submissions ADD COLUMN pdp_renewal_id text;
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ApplicationCredentialStatus represents the lifecycle state of an application client credential
+type ApplicationCredentialStatus string
+
+const (
+	// ApplicationCredentialStatusActive credentials can be used to obtain tokens from the IDP
+	ApplicationCredentialStatusActive ApplicationCredentialStatus = "active"
+	// ApplicationCredentialStatusRotated credentials were replaced by a newer secret
+	ApplicationCredentialStatusRotated ApplicationCredentialStatus = "rotated"
+	// ApplicationCredentialStatusRevoked credentials were revoked and can no longer be used
+	ApplicationCredentialStatusRevoked ApplicationCredentialStatus = "revoked"
+)
+
+// ApplicationCredential represents the application_credentials table. It records every client
+// secret issued for an application; the secret itself is only held by the IDP and the consumer.
+type ApplicationCredential struct {
+	CredentialID  string                      `gorm:"primarykey;column:credential_id" json:"credentialId"`
+	ApplicationID string                      `gorm:"column:application_id;not null;index" json:"applicationId"`
+	ClientID      string                      `gorm:"column:client_id;not null" json:"clientId"`
+	SecretHint    string                      `gorm:"column:secret_hint;not null" json:"secretHint"`
+	Status        ApplicationCredentialStatus `gorm:"column:status;not null" json:"status"`
+	IssuedBy      string                      `gorm:"column:issued_by;not null" json:"issuedBy"`
+	RevokedAt     *time.Time                  `gorm:"column:revoked_at" json:"revokedAt,omitempty"`
+	RevokedBy     *string                     `gorm:"column:revoked_by" json:"revokedBy,omitempty"`
+	// ExpiresAt is when the credential should have been rotated by; nil when credentials do not expire
+	ExpiresAt *time.Time `gorm:"column:expires_at;index" json:"expiresAt,omitempty"`
+	// ExpiryNotifiedAt is when the application owner was told the credential is about to expire
+	ExpiryNotifiedAt *time.Time `gorm:"column:expiry_notified_at" json:"-"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (ApplicationCredential) TableName() string {
+	return "application_credentials"
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ApprovalAction is a sensitive admin action that can be made to need the confirmation of a second admin
+type ApprovalAction string
+
+const (
+	// ApprovalActionDeleteMember deletes a member
+	ApprovalActionDeleteMember ApprovalAction = "delete_member"
+	// ApprovalActionApproveSensitiveSchema approves the last review step of a schema submission whose
+	// fields include sensitive personal data
+	ApprovalActionApproveSensitiveSchema ApprovalAction = "approve_sensitive_schema"
+	// ApprovalActionRotateCredential resets an application's client secret on behalf of its owner
+	ApprovalActionRotateCredential ApprovalAction = "rotate_credential"
+)
+
+// IsValid checks if the approval action is known
+func (a ApprovalAction) IsValid() bool {
+	switch a {
+	case ApprovalActionDeleteMember, ApprovalActionApproveSensitiveSchema, ApprovalActionRotateCredential:
+		return true
+	}
+	return false
+}
+
+// ParseApprovalActions parses a comma-separated list of approval actions, such as the value of
+// FOUR_EYES_ACTIONS
+func ParseApprovalActions(value string) ([]ApprovalAction, error) {
+	var actions []ApprovalAction
+	for _, entry := range strings.Split(value, ",") {
+		action := ApprovalAction(strings.TrimSpace(entry))
+		if action == "" {
+			continue
+		}
+		if !action.IsValid() {
+			return nil, fmt.Errorf("unknown approval action %q, must be delete_member, approve_sensitive_schema or rotate_credential", action)
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// ApprovalStatus is the state of an admin approval
+type ApprovalStatus string
+
+const (
+	// ApprovalStatusPending approvals wait for a second admin until they expire
+	ApprovalStatusPending ApprovalStatus = "pending"
+	// ApprovalStatusConfirmed approvals were confirmed by a second admin and their action ran
+	ApprovalStatusConfirmed ApprovalStatus = "confirmed"
+	// ApprovalStatusRejected approvals were rejected by an admin, or withdrawn by the one who requested them
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+	// ApprovalStatusExpired approvals were not decided in time; they are only reported, not stored
+	ApprovalStatusExpired ApprovalStatus = "expired"
+)
+
+// AdminApproval represents the admin_approvals table. A sensitive action requested by one admin is
+// recorded here instead of running, and only runs once a second admin confirms it; both are recorded.
+type AdminApproval struct {
+	ApprovalID string         `gorm:"primarykey;column:approval_id" json:"approvalId"`
+	Action     ApprovalAction `gorm:"column:action;not null" json:"action"`
+	// ResourceID is the member, schema submission or credential the action applies to
+	ResourceID string `gorm:"column:resource_id;not null;index" json:"resourceId"`
+	// Payload holds the rest of the request the action runs with, as an ApprovalPayload
+	Payload          string         `gorm:"column:payload;type:jsonb;not null" json:"-"`
+	Status           ApprovalStatus `gorm:"column:status;not null;index" json:"status"`
+	RequestedBy      string         `gorm:"column:requested_by;not null" json:"requestedBy"`
+	RequestedByEmail string         `gorm:"column:requested_by_email;not null" json:"requestedByEmail"`
+	DecidedBy        *string        `gorm:"column:decided_by" json:"decidedBy,omitempty"`
+	DecidedByEmail   *string        `gorm:"column:decided_by_email" json:"decidedByEmail,omitempty"`
+	DecidedAt        *time.Time     `gorm:"column:decided_at" json:"decidedAt,omitempty"`
+	ExpiresAt        time.Time      `gorm:"column:expires_at;not null" json:"expiresAt"`
+	// LastError is why the action failed the last time the approval was confirmed; it stays pending
+	LastError *string `gorm:"column:last_error" json:"lastError,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (AdminApproval) TableName() string {
+	return "admin_approvals"
+}
+
+// ApprovalPayload is the payload of an approval: the parts of the original request other than the
+// resource ID that its action runs with
+type ApprovalPayload struct {
+	// ApplicationID is the application of a rotated credential
+	ApplicationID string `json:"applicationId,omitempty"`
+	// Review is the decision on an approved schema submission
+	Review *SubmissionReviewDecisionRequest `json:"review,omitempty"`
+}
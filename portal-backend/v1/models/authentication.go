@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,7 +19,10 @@ type UserClaims struct {
 	Roles       FlexibleStringSlice `json:"roles"`
 	Groups      FlexibleStringSlice `json:"groups"`
 	OrgName     string              `json:"org_name"`
-	IdpUserID   string              `json:"sub"` // Subject is typically the user ID from IdP
+	Scope       string              `json:"scope"` // Space separated OAuth scopes granted to the token
+	IdpUserID   string              `json:"sub"`   // Subject is typically the user ID from IdP
+	TokenID     string              `json:"jti"`   // Unique ID of the token, when the IdP issues one
+	SessionID   string              `json:"sid"`   // ID of the IdP sign-in session the token belongs to
 	// Standard JWT claims - using int64 for Unix timestamps
 	Issuer    string              `json:"iss"`
 	Audience  FlexibleStringSlice `json:"aud"`
@@ -76,8 +80,12 @@ type AuthenticatedUser struct {
 	Roles       []Role    `json:"roles"`
 	Groups      []string  `json:"groups"`
 	OrgName     string    `json:"orgName"`
+	Scopes      []string  `json:"scopes"`
 	IssuedAt    time.Time `json:"issuedAt"`
 	ExpiresAt   time.Time `json:"expiresAt"`
+	// TokenID and SessionID are the jti and sid claims of the access token, if present
+	TokenID   string `json:"-"`
+	SessionID string `json:"-"`
 
 	// Cached permissions - computed once during user creation for performance
 	permissions []Permission `json:"-"` // Don't expose in JSON, use GetPermissions() method
@@ -130,6 +138,16 @@ func (u *AuthenticatedUser) HasPermission(permission Permission) bool {
 	return false
 }
 
+// HasScope checks if the user's access token was granted a specific OAuth scope
+func (u *AuthenticatedUser) HasScope(scope string) bool {
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAdmin checks if the user has admin role
 func (u *AuthenticatedUser) IsAdmin() bool {
 	return u.HasRole(RoleAdmin)
@@ -272,8 +290,11 @@ func NewAuthenticatedUser(claims *UserClaims) (*AuthenticatedUser, error) {
 		Roles:       roles,
 		Groups:      claims.Groups.ToStringSlice(),
 		OrgName:     claims.OrgName,
+		Scopes:      strings.Fields(claims.Scope),
 		IssuedAt:    issuedAt,
 		ExpiresAt:   expiresAt,
+		TokenID:     claims.TokenID,
+		SessionID:   claims.SessionID,
 		permissions: permissions,
 	}, nil
 }
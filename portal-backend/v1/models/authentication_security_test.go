@@ -107,3 +107,27 @@ func TestNewAuthenticatedUser_MixedRoles(t *testing.T) {
 		}
 	}
 }
+
+// TestNewAuthenticatedUser_Scopes tests that the space separated scope claim is split into scopes
+func TestNewAuthenticatedUser_Scopes(t *testing.T) {
+	claims := &UserClaims{
+		Email:     "scopes@example.com",
+		IdpUserID: "scopes-123",
+		Roles:     FlexibleStringSlice{"OpenDIF_Admin"},
+		Scope:     "openid  portal:admin profile",
+	}
+
+	user, err := NewAuthenticatedUser(claims)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	if len(user.Scopes) != 3 {
+		t.Errorf("Expected 3 scopes, got: %v", user.Scopes)
+	}
+	if !user.HasScope("portal:admin") {
+		t.Errorf("Expected user to have scope portal:admin, got: %v", user.Scopes)
+	}
+	if user.HasScope("portal") {
+		t.Errorf("Expected user not to have scope portal, got: %v", user.Scopes)
+	}
+}
@@ -33,6 +33,7 @@ const (
 	PermissionUpdateSchema   Permission = "schema:update"
 	PermissionDeleteSchema   Permission = "schema:delete"
 	PermissionReadAllSchemas Permission = "schema:read:all"
+	PermissionRestoreSchema  Permission = "schema:restore"
 
 	// Schema submission permissions
 	PermissionCreateSchemaSubmission   Permission = "schema_submission:create"
@@ -41,6 +42,7 @@ const (
 	PermissionDeleteSchemaSubmission   Permission = "schema_submission:delete"
 	PermissionReadAllSchemaSubmissions Permission = "schema_submission:read:all"
 	PermissionApproveSchemaSubmission  Permission = "schema_submission:approve"
+	PermissionRestoreSchemaSubmission  Permission = "schema_submission:restore"
 
 	// Application permissions
 	PermissionCreateApplication   Permission = "application:create"
@@ -48,6 +50,11 @@ const (
 	PermissionUpdateApplication   Permission = "application:update"
 	PermissionDeleteApplication   Permission = "application:delete"
 	PermissionReadAllApplications Permission = "application:read:all"
+	PermissionRestoreApplication  Permission = "application:restore"
+	// PermissionManageApplicationQuota sets the data usage quotas of applications
+	PermissionManageApplicationQuota Permission = "application_quota:manage"
+	// PermissionManageApplicationStatus suspends and reactivates applications
+	PermissionManageApplicationStatus Permission = "application_status:manage"
 
 	// Application submission permissions
 	PermissionCreateApplicationSubmission   Permission = "application_submission:create"
@@ -56,6 +63,7 @@ const (
 	PermissionDeleteApplicationSubmission   Permission = "application_submission:delete"
 	PermissionReadAllApplicationSubmissions Permission = "application_submission:read:all"
 	PermissionApproveApplicationSubmission  Permission = "application_submission:approve"
+	PermissionRestoreApplicationSubmission  Permission = "application_submission:restore"
 
 	// Member permissions
 	PermissionCreateMember   Permission = "member:create"
@@ -63,6 +71,77 @@ const (
 	PermissionUpdateMember   Permission = "member:update"
 	PermissionDeleteMember   Permission = "member:delete"
 	PermissionReadAllMembers Permission = "member:read:all"
+	PermissionRestoreMember  Permission = "member:restore"
+
+	// Member session permissions; members only see and revoke their own sessions and security events
+	PermissionReadMemberSessions   Permission = "member_session:read"
+	PermissionRevokeMemberSessions Permission = "member_session:revoke"
+	PermissionReadSecurityEvents   Permission = "security_event:read"
+
+	// PDP sync job permissions
+	PermissionReadPDPJobs   Permission = "pdp_job:read"
+	PermissionRequeuePDPJob Permission = "pdp_job:requeue"
+
+	// PDP grant reconciliation permissions
+	PermissionReadPDPSync   Permission = "pdp_sync:read"
+	PermissionRepairPDPSync Permission = "pdp_sync:repair"
+
+	// Submission throughput metrics permissions
+	PermissionReadSubmissionMetrics Permission = "submission_metrics:read"
+
+	// Application credential permissions
+	PermissionReadApplicationCredentials   Permission = "application_credential:read"
+	PermissionManageApplicationCredentials Permission = "application_credential:manage"
+
+	// Application webhook permissions
+	PermissionReadApplicationWebhooks   Permission = "application_webhook:read"
+	PermissionManageApplicationWebhooks Permission = "application_webhook:manage"
+
+	// Submission review workflow permissions
+	PermissionReviewSubmission  Permission = "submission:review"
+	PermissionCommentSubmission Permission = "submission_comment:create"
+
+	// Notification permissions; notifications are always scoped to the caller's own member record
+	PermissionReadNotifications             Permission = "notification:read"
+	PermissionUpdateNotificationPreferences Permission = "notification_preference:update"
+
+	// Member invitation permissions
+	PermissionCreateInvitation Permission = "invitation:create"
+	PermissionReadInvitation   Permission = "invitation:read"
+	PermissionRevokeInvitation Permission = "invitation:revoke"
+
+	// Organization permissions; members hold them for their own organization, and managing it
+	// additionally requires the org_admin organization role
+	PermissionCreateOrganization       Permission = "organization:create"
+	PermissionReadOrganization         Permission = "organization:read"
+	PermissionUpdateOrganization       Permission = "organization:update"
+	PermissionManageOrganizationMember Permission = "organization_member:manage"
+
+	// Field catalog permissions
+	PermissionReadCatalog Permission = "catalog:read"
+
+	// Bulk admin operation permissions
+	PermissionExecuteBulkOperation Permission = "bulk_operation:execute"
+
+	// Admin GraphQL API permissions
+	PermissionQueryAdminGraph Permission = "admin_graph:query"
+
+	// Impersonation permissions
+	PermissionImpersonateMember Permission = "member:impersonate"
+
+	// Audit event permissions; members only read the events of their own entities
+	PermissionReadAuditEvents Permission = "audit_event:read"
+
+	// Two-person approval permissions; an admin never confirms an approval they requested
+	PermissionReadApprovals   Permission = "admin_approval:read"
+	PermissionDecideApprovals Permission = "admin_approval:decide"
+
+	// Saved view and collection export permissions
+	PermissionManageSavedViews  Permission = "saved_view:manage"
+	PermissionExportCollections Permission = "collection:export"
+
+	// Field configuration template permissions
+	PermissionManageFieldConfigurationTemplates Permission = "field_configuration_template:manage"
 )
 
 // RolePermissions defines what permissions each role has
@@ -77,6 +156,23 @@ var RolePermissions = map[Role][]Permission{
 		PermissionUpdateApplicationSubmission, PermissionDeleteApplicationSubmission, PermissionReadAllApplicationSubmissions,
 		PermissionApproveApplicationSubmission, PermissionCreateMember, PermissionReadMember, PermissionUpdateMember,
 		PermissionDeleteMember, PermissionReadAllMembers,
+		PermissionReadMemberSessions, PermissionRevokeMemberSessions, PermissionReadSecurityEvents,
+		PermissionReadPDPJobs, PermissionRequeuePDPJob,
+		PermissionReadPDPSync, PermissionRepairPDPSync, PermissionReadSubmissionMetrics,
+		PermissionRestoreSchema, PermissionRestoreSchemaSubmission, PermissionRestoreApplication,
+		PermissionRestoreApplicationSubmission, PermissionRestoreMember,
+		PermissionReadApplicationCredentials, PermissionManageApplicationCredentials,
+		PermissionReadApplicationWebhooks, PermissionManageApplicationWebhooks,
+		PermissionReviewSubmission, PermissionCommentSubmission,
+		PermissionReadNotifications, PermissionUpdateNotificationPreferences,
+		PermissionCreateInvitation, PermissionReadInvitation, PermissionRevokeInvitation,
+		PermissionCreateOrganization, PermissionReadOrganization, PermissionUpdateOrganization,
+		PermissionManageOrganizationMember, PermissionReadCatalog, PermissionExecuteBulkOperation,
+		PermissionQueryAdminGraph, PermissionImpersonateMember, PermissionManageApplicationQuota,
+		PermissionManageApplicationStatus,
+		PermissionReadAuditEvents, PermissionReadApprovals, PermissionDecideApprovals,
+		PermissionManageSavedViews, PermissionExportCollections,
+		PermissionManageFieldConfigurationTemplates,
 	},
 	RoleMember: {
 		// Members can create, read, and update their own resources
@@ -85,6 +181,13 @@ var RolePermissions = map[Role][]Permission{
 		PermissionCreateApplication, PermissionReadApplication, PermissionUpdateApplication,
 		PermissionCreateApplicationSubmission, PermissionReadApplicationSubmission, PermissionUpdateApplicationSubmission,
 		PermissionReadMember, PermissionUpdateMember,
+		PermissionReadMemberSessions, PermissionRevokeMemberSessions, PermissionReadSecurityEvents,
+		PermissionReadApplicationCredentials, PermissionManageApplicationCredentials,
+		PermissionReadApplicationWebhooks, PermissionManageApplicationWebhooks,
+		PermissionCommentSubmission,
+		PermissionReadNotifications, PermissionUpdateNotificationPreferences,
+		PermissionReadOrganization, PermissionUpdateOrganization, PermissionManageOrganizationMember,
+		PermissionReadCatalog, PermissionReadAuditEvents,
 	},
 	RoleSystem: {
 		// System role has broad read access for internal services
@@ -93,6 +196,7 @@ var RolePermissions = map[Role][]Permission{
 		PermissionReadApplication, PermissionReadAllApplications,
 		PermissionReadApplicationSubmission, PermissionReadAllApplicationSubmissions,
 		PermissionReadMember, PermissionReadAllMembers,
+		PermissionReadCatalog,
 	},
 }
 
@@ -112,12 +216,48 @@ var EndpointPermissions = []EndpointPermission{
 	{"GET", "/api/v1/schemas/*", PermissionReadSchema, true},
 	{"PUT", "/api/v1/schemas/*", PermissionUpdateSchema, true},
 	{"DELETE", "/api/v1/schemas/*", PermissionDeleteSchema, true},
+	{"POST", "/api/v1/schemas/*", PermissionRestoreSchema, false},
+
+	// Schema submission review and attachment endpoints; listed before the submission wildcards so they match first
+	{"POST", "/api/v1/schema-submissions/*/review*", PermissionReviewSubmission, false},
+	{"PUT", "/api/v1/schema-submissions/*/review*", PermissionReviewSubmission, false},
+	{"POST", "/api/v1/schema-submissions/*/comments", PermissionCommentSubmission, true},
+	{"POST", "/api/v1/schema-submissions/*/submit", PermissionUpdateSchemaSubmission, true},
+	{"GET", "/api/v1/schema-submissions/*/attachments*", PermissionReadSchemaSubmission, true},
+	{"POST", "/api/v1/schema-submissions/*/attachments", PermissionUpdateSchemaSubmission, true},
+	{"DELETE", "/api/v1/schema-submissions/*/attachments/*", PermissionUpdateSchemaSubmission, true},
 
 	// Schema submission endpoints
 	{"GET", "/api/v1/schema-submissions", PermissionReadSchemaSubmission, false},
 	{"POST", "/api/v1/schema-submissions", PermissionCreateSchemaSubmission, false},
 	{"GET", "/api/v1/schema-submissions/*", PermissionReadSchemaSubmission, true},
 	{"PUT", "/api/v1/schema-submissions/*", PermissionUpdateSchemaSubmission, true},
+	{"DELETE", "/api/v1/schema-submissions/*", PermissionDeleteSchemaSubmission, true},
+	{"POST", "/api/v1/schema-submissions/*", PermissionRestoreSchemaSubmission, false},
+
+	// Application credential endpoints; listed before the application wildcards so they match first
+	{"GET", "/api/v1/applications/*/credentials*", PermissionReadApplicationCredentials, true},
+	{"POST", "/api/v1/applications/*/credentials*", PermissionManageApplicationCredentials, true},
+	{"DELETE", "/api/v1/applications/*/credentials*", PermissionManageApplicationCredentials, true},
+
+	// Application webhook endpoints; listed before the application wildcards so they match first
+	{"GET", "/api/v1/applications/*/webhooks*", PermissionReadApplicationWebhooks, true},
+	{"POST", "/api/v1/applications/*/webhooks*", PermissionManageApplicationWebhooks, true},
+	{"DELETE", "/api/v1/applications/*/webhooks*", PermissionManageApplicationWebhooks, true},
+
+	// Application sandbox endpoints; listed before the application wildcards so they match first
+	{"GET", "/api/v1/applications/*/sandbox*", PermissionReadApplicationCredentials, true},
+	{"POST", "/api/v1/applications/*/sandbox*", PermissionManageApplicationCredentials, true},
+
+	// Application quota endpoints; listed before the application wildcards so they match first
+	{"GET", "/api/v1/applications/*/quota", PermissionReadApplication, true},
+	{"PUT", "/api/v1/applications/*/quota", PermissionManageApplicationQuota, false},
+
+	// Application status endpoint; listed before the application wildcards so it matches first
+	{"PUT", "/api/v1/applications/*/status", PermissionManageApplicationStatus, false},
+
+	// Application grant renewal endpoint; listed before the application wildcards so it matches first
+	{"POST", "/api/v1/applications/*/renew", PermissionCreateApplicationSubmission, true},
 
 	// Application endpoints
 	{"GET", "/api/v1/applications", PermissionReadApplication, false},
@@ -125,18 +265,135 @@ var EndpointPermissions = []EndpointPermission{
 	{"GET", "/api/v1/applications/*", PermissionReadApplication, true},
 	{"PUT", "/api/v1/applications/*", PermissionUpdateApplication, true},
 	{"DELETE", "/api/v1/applications/*", PermissionDeleteApplication, true},
+	{"POST", "/api/v1/applications/*", PermissionRestoreApplication, false},
+
+	// Application submission review endpoints; listed before the submission wildcards so they match first
+	{"POST", "/api/v1/application-submissions/*/review*", PermissionReviewSubmission, false},
+	{"PUT", "/api/v1/application-submissions/*/review*", PermissionReviewSubmission, false},
+	{"POST", "/api/v1/application-submissions/*/comments", PermissionCommentSubmission, true},
+	{"POST", "/api/v1/application-submissions/*/submit", PermissionUpdateApplicationSubmission, true},
 
 	// Application submission endpoints
 	{"GET", "/api/v1/application-submissions", PermissionReadApplicationSubmission, false},
 	{"POST", "/api/v1/application-submissions", PermissionCreateApplicationSubmission, false},
 	{"GET", "/api/v1/application-submissions/*", PermissionReadApplicationSubmission, true},
 	{"PUT", "/api/v1/application-submissions/*", PermissionUpdateApplicationSubmission, true},
+	{"DELETE", "/api/v1/application-submissions/*", PermissionDeleteApplicationSubmission, true},
+	{"POST", "/api/v1/application-submissions/*", PermissionRestoreApplicationSubmission, false},
 
 	// Member endpoints
 	{"GET", "/api/v1/members", PermissionReadMember, false},
 	{"POST", "/api/v1/members", PermissionCreateMember, false},
+	{"GET", "/api/v1/members/*/sessions", PermissionReadMemberSessions, true},
+	{"DELETE", "/api/v1/members/*/sessions", PermissionRevokeMemberSessions, true},
+	{"GET", "/api/v1/members/*/security-events", PermissionReadSecurityEvents, true},
 	{"GET", "/api/v1/members/*", PermissionReadMember, true},
 	{"PUT", "/api/v1/members/*", PermissionUpdateMember, true},
+	{"DELETE", "/api/v1/members/*", PermissionDeleteMember, false},
+	{"POST", "/api/v1/members/*", PermissionRestoreMember, false},
+
+	// PDP sync job admin endpoints
+	{"GET", "/api/v1/admin/pdp-jobs", PermissionReadPDPJobs, false},
+	{"POST", "/api/v1/admin/pdp-jobs/*", PermissionRequeuePDPJob, false},
+
+	// PDP grant reconciliation endpoints
+	{"GET", "/api/v1/pdp-sync/status", PermissionReadPDPSync, false},
+	{"POST", "/api/v1/pdp-sync/repair", PermissionRepairPDPSync, false},
+
+	// Submission throughput metrics endpoints
+	{"GET", "/api/v1/admin/metrics/*", PermissionReadSubmissionMetrics, false},
+
+	// Bulk admin operation endpoints
+	{"POST", "/api/v1/admin/bulk/*", PermissionExecuteBulkOperation, false},
+
+	// Admin GraphQL API endpoints
+	{"GET", "/api/v1/admin/graphql", PermissionQueryAdminGraph, false},
+	{"POST", "/api/v1/admin/graphql", PermissionQueryAdminGraph, false},
+
+	// Impersonation endpoints
+	{"GET", "/api/v1/admin/impersonation", PermissionImpersonateMember, false},
+	{"POST", "/api/v1/admin/impersonation", PermissionImpersonateMember, false},
+	{"DELETE", "/api/v1/admin/impersonation", PermissionImpersonateMember, false},
+
+	// Two-person approval endpoints
+	{"GET", "/api/v1/admin/approvals", PermissionReadApprovals, false},
+	{"GET", "/api/v1/admin/approvals/*", PermissionReadApprovals, false},
+	{"POST", "/api/v1/admin/approvals/*", PermissionDecideApprovals, false},
+
+	// Saved view endpoints; exporting a view is listed first so it matches first
+	{"GET", "/api/v1/admin/saved-views/*/export", PermissionExportCollections, false},
+	{"GET", "/api/v1/admin/saved-views", PermissionManageSavedViews, false},
+	{"POST", "/api/v1/admin/saved-views", PermissionManageSavedViews, false},
+	{"GET", "/api/v1/admin/saved-views/*", PermissionManageSavedViews, false},
+	{"PUT", "/api/v1/admin/saved-views/*", PermissionManageSavedViews, false},
+	{"DELETE", "/api/v1/admin/saved-views/*", PermissionManageSavedViews, false},
+
+	// Collection export endpoints
+	{"GET", "/api/v1/admin/exports/*", PermissionExportCollections, false},
+
+	// Field configuration template endpoints
+	{"GET", "/api/v1/admin/field-configuration-templates", PermissionManageFieldConfigurationTemplates, false},
+	{"POST", "/api/v1/admin/field-configuration-templates", PermissionManageFieldConfigurationTemplates, false},
+	{"GET", "/api/v1/admin/field-configuration-templates/*", PermissionManageFieldConfigurationTemplates, false},
+	{"PUT", "/api/v1/admin/field-configuration-templates/*", PermissionManageFieldConfigurationTemplates, false},
+	{"DELETE", "/api/v1/admin/field-configuration-templates/*", PermissionManageFieldConfigurationTemplates, false},
+
+	// Notification endpoints
+	{"GET", "/api/v1/notifications", PermissionReadNotifications, false},
+	{"GET", "/api/v1/notifications/*", PermissionReadNotifications, false},
+	{"PUT", "/api/v1/notifications/*", PermissionUpdateNotificationPreferences, false},
+	{"POST", "/api/v1/notifications/*", PermissionReadNotifications, false},
+
+	// Member invitation endpoints; accepting an invitation is a public route outside this table
+	{"GET", "/api/v1/invitations", PermissionReadInvitation, false},
+	{"POST", "/api/v1/invitations", PermissionCreateInvitation, false},
+	{"DELETE", "/api/v1/invitations/*", PermissionRevokeInvitation, false},
+
+	// Organization member endpoints; listed before the organization wildcards so they match first
+	{"GET", "/api/v1/organizations/*/members*", PermissionReadOrganization, true},
+	{"PUT", "/api/v1/organizations/*/members*", PermissionManageOrganizationMember, true},
+	{"DELETE", "/api/v1/organizations/*/members*", PermissionManageOrganizationMember, true},
+
+	// Organization endpoints
+	{"GET", "/api/v1/organizations", PermissionReadOrganization, false},
+	{"POST", "/api/v1/organizations", PermissionCreateOrganization, false},
+	{"GET", "/api/v1/organizations/*", PermissionReadOrganization, true},
+	{"PUT", "/api/v1/organizations/*", PermissionUpdateOrganization, true},
+
+	// Field catalog endpoints
+	{"GET", "/api/v1/catalog/fields", PermissionReadCatalog, false},
+
+	// Audit event endpoints
+	{"GET", "/api/v1/audit-events", PermissionReadAuditEvents, false},
+}
+
+// RoutePolicy declares the roles and token scopes a route requires on top of its endpoint
+// permission. A route without an endpoint permission is authorized by its policy alone.
+type RoutePolicy struct {
+	Method string
+	Path   string
+	Roles  []Role   // The user needs any one of these roles; empty allows every role
+	Scopes []string // The access token needs all of these scopes
+}
+
+// RoutePolicies declares the role and scope requirements of routes. Paths are matched like
+// EndpointPermissions and the first matching policy applies.
+var RoutePolicies = []RoutePolicy{
+	// Admin endpoints are reserved to admins, including those without an endpoint permission
+	{Method: "GET", Path: "/api/v1/admin/*", Roles: []Role{RoleAdmin}},
+	{Method: "POST", Path: "/api/v1/admin/*", Roles: []Role{RoleAdmin}},
+	{Method: "PUT", Path: "/api/v1/admin/*", Roles: []Role{RoleAdmin}},
+	{Method: "DELETE", Path: "/api/v1/admin/*", Roles: []Role{RoleAdmin}},
+}
+
+// RouteAccess describes who can call a route, as listed in the route permission report
+type RouteAccess struct {
+	Method            string     `json:"method"`
+	Path              string     `json:"path"`
+	Permission        Permission `json:"permission,omitempty"`
+	OwnershipRequired bool       `json:"ownershipRequired"`
+	Roles             []Role     `json:"roles"`
+	Scopes            []string   `json:"scopes,omitempty"`
 }
 
 // HasPermission checks if a role has a specific permission
@@ -25,3 +25,16 @@ func (b *BaseModel) BeforeUpdate(tx *gorm.DB) error {
 	b.UpdatedAt = time.Now()
 	return nil
 }
+
+// SoftDeleteModel marks a model as soft-deletable. GORM leaves rows with deleted_at set out of
+// every query unless it is run Unscoped, so a deleted row can be restored by clearing both fields.
+type SoftDeleteModel struct {
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
+	DeletedBy *string        `gorm:"column:deleted_by" json:"-"`
+}
+
+// RevisionModel numbers the versions of a model for optimistic locking. Every update increments
+// Revision, and an update based on an older revision is rejected instead of overwriting newer changes.
+type RevisionModel struct {
+	Revision int64 `gorm:"column:revision;not null;default:1" json:"revision"`
+}
@@ -0,0 +1,74 @@
+package models
+
+// BulkResourceType names the kind of resource an item of a bulk operation refers to
+type BulkResourceType string
+
+const (
+	BulkResourceSchema                BulkResourceType = "schema"
+	BulkResourceSchemaSubmission      BulkResourceType = "schema-submission"
+	BulkResourceApplication           BulkResourceType = "application"
+	BulkResourceApplicationSubmission BulkResourceType = "application-submission"
+	BulkResourceMember                BulkResourceType = "member"
+)
+
+// MaxBulkItems caps the number of items a single bulk operation may act on
+const MaxBulkItems = 100
+
+// MaxMemberImportRows caps the rows of a member import, which migrates whole staff lists
+const MaxMemberImportRows = 1000
+
+// BulkResourceRef identifies one resource of a bulk operation
+type BulkResourceRef struct {
+	Type BulkResourceType `json:"type"`
+	ID   string           `json:"id"`
+}
+
+// BulkReviewSubmissionsRequest records the same review decision on several submissions
+type BulkReviewSubmissionsRequest struct {
+	// Submissions are schema-submission or application-submission references
+	Submissions []BulkResourceRef `json:"submissions"`
+	Decision    ReviewDecision    `json:"decision"`
+	Comment     *string           `json:"comment,omitempty"`
+}
+
+// BulkExpireApplicationsRequest deprecates several applications
+type BulkExpireApplicationsRequest struct {
+	ApplicationIDs []string `json:"applicationIds"`
+}
+
+// BulkReassignRequest moves resources from one member to another
+type BulkReassignRequest struct {
+	FromMemberID string `json:"fromMemberId"`
+	ToMemberID   string `json:"toMemberId"`
+	// Resources limits the reassignment to the listed resources; all of FromMemberID's
+	// schemas, applications and submissions are moved when it is empty
+	Resources []BulkResourceRef `json:"resources,omitempty"`
+}
+
+// BulkImportMembersRequest creates a member, and its IDP user, for each row of a staff list
+type BulkImportMembersRequest struct {
+	Members []CreateMemberRequest `json:"members"`
+}
+
+// BulkItemResult is the outcome of one item of a bulk operation. Items are processed
+// independently, so a failed item does not undo the others.
+type BulkItemResult struct {
+	Type    BulkResourceType `json:"type"`
+	ID      string           `json:"id"`
+	Success bool             `json:"success"`
+	// PreviousStatus and Status are the item's status before and after the operation, where it has one
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	Status         string `json:"status,omitempty"`
+	// Row is the 1-based position of the item in an import request, which identifies rows that
+	// failed before getting an ID
+	Row   int    `json:"row,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkOperationResponse reports the per-item results of a bulk operation
+type BulkOperationResponse struct {
+	Operation string           `json:"operation"`
+	Results   []BulkItemResult `json:"results"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+}
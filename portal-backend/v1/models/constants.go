@@ -15,6 +15,8 @@ const (
 	StatusPending  Status = "pending"
 	StatusApproved Status = "approved"
 	StatusRejected Status = "rejected"
+	// StatusDraft submissions are saved by their member and not yet submitted for review
+	StatusDraft Status = "draft"
 )
 
 // Version represents application versioning states
@@ -23,6 +25,10 @@ type Version string
 const (
 	ActiveVersion     Version = "active"
 	DeprecatedVersion Version = "deprecated"
+	// SunsetVersion schemas are past their sunset date; their fields are still served with a warning
+	SunsetVersion Version = "sunset"
+	// RetiredVersion schemas no longer serve their fields to any application
+	RetiredVersion Version = "retired"
 )
 
 // AuditStatus represents the status of audit events
@@ -42,6 +48,23 @@ const (
 	ActorTypeSystem ActorType = "SYSTEM"
 )
 
+// SecurityEventType is the audit event type of the sign-ins, token issuance, credential changes and
+// denied requests of portal users
+const SecurityEventType = "SECURITY_EVENT"
+
+// SecurityEventAction is the action of a SECURITY_EVENT
+type SecurityEventAction string
+
+const (
+	SecurityActionLogin               SecurityEventAction = "LOGIN"
+	SecurityActionTokenIssued         SecurityEventAction = "TOKEN_ISSUED"
+	SecurityActionAuthorizationDenied SecurityEventAction = "AUTHORIZATION_DENIED"
+	SecurityActionCredentialGenerated SecurityEventAction = "CREDENTIAL_GENERATED"
+	SecurityActionCredentialRotated   SecurityEventAction = "CREDENTIAL_ROTATED"
+	SecurityActionCredentialRevoked   SecurityEventAction = "CREDENTIAL_REVOKED"
+	SecurityActionSessionsRevoked     SecurityEventAction = "SESSIONS_REVOKED"
+)
+
 // TargetType represents different target types for auditing
 type TargetType string
 
@@ -54,11 +77,19 @@ const (
 type ResourceType string
 
 const (
-	ResourceTypeMembers                ResourceType = "MEMBERS"
-	ResourceTypeSchemas                ResourceType = "SCHEMAS"
-	ResourceTypeSchemaSubmissions      ResourceType = "SCHEMA-SUBMISSIONS"
-	ResourceTypeApplications           ResourceType = "APPLICATIONS"
-	ResourceTypeApplicationSubmissions ResourceType = "APPLICATION-SUBMISSIONS"
+	ResourceTypeMembers                     ResourceType = "MEMBERS"
+	ResourceTypeSchemas                     ResourceType = "SCHEMAS"
+	ResourceTypeSchemaSubmissions           ResourceType = "SCHEMA-SUBMISSIONS"
+	ResourceTypeApplications                ResourceType = "APPLICATIONS"
+	ResourceTypeApplicationSubmissions      ResourceType = "APPLICATION-SUBMISSIONS"
+	ResourceTypePDPJobs                     ResourceType = "PDP-JOBS"
+	ResourceTypeInvitations                 ResourceType = "INVITATIONS"
+	ResourceTypeOrganizations               ResourceType = "ORGANIZATIONS"
+	ResourceTypeBulkOperations              ResourceType = "BULK-OPERATIONS"
+	ResourceTypeImpersonationSessions       ResourceType = "IMPERSONATION-SESSIONS"
+	ResourceTypeAdminApprovals              ResourceType = "ADMIN-APPROVALS"
+	ResourceTypeSavedViews                  ResourceType = "SAVED-VIEWS"
+	ResourceTypeFieldConfigurationTemplates ResourceType = "FIELD-CONFIGURATION-TEMPLATES"
 )
 
 // Field length constraints remain as regular constants
@@ -1,5 +1,13 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gov-dx-sandbox/shared/response"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
 // Request/Response DTOs for V1 API endpoints
 
 // CreateSchemaSubmissionRequest Provider Schema Submission DTOs
@@ -10,6 +18,13 @@ type CreateSchemaSubmissionRequest struct {
 	SchemaEndpoint    string  `json:"schemaEndpoint" validate:"required"`
 	PreviousSchemaID  *string `json:"previousSchemaId,omitempty"`
 	MemberID          string  `json:"memberId" validate:"required"`
+	// Status is draft to save an incomplete submission without validating it; otherwise it is pending
+	Status *string `json:"status,omitempty"`
+	// FieldConfigurations adjusts the field configurations suggested from the SDL
+	FieldConfigurations FieldConfigurations `json:"fieldConfigurations,omitempty"`
+	// ProviderType selects the field configuration template that pre-fills the configurations;
+	// omitted, it is the provider type of the member's organization
+	ProviderType *string `json:"providerType,omitempty"`
 }
 
 // UpdateSchemaSubmissionRequest updates the status of a provider schema submission
@@ -21,6 +36,8 @@ type UpdateSchemaSubmissionRequest struct {
 	Status            *string `json:"status,omitempty"`
 	PreviousSchemaID  *string `json:"previousSchemaId,omitempty"`
 	Review            *string `json:"review,omitempty"`
+	// FieldConfigurations adjusts field configurations; the others keep their current configuration
+	FieldConfigurations FieldConfigurations `json:"fieldConfigurations,omitempty"`
 }
 
 // CreateSchemaRequest creates a new provider schema
@@ -30,6 +47,8 @@ type CreateSchemaRequest struct {
 	SDL               string  `json:"sdl" validate:"required"`
 	Endpoint          string  `json:"endpoint" validate:"required"`
 	MemberID          string  `json:"memberId" validate:"required"`
+	// FieldConfigurations configures the policy metadata of fields beyond the SDL's directives
+	FieldConfigurations FieldConfigurations `json:"fieldConfigurations,omitempty"`
 }
 
 // UpdateSchemaRequest updates an existing provider schema
@@ -38,7 +57,14 @@ type UpdateSchemaRequest struct {
 	SchemaDescription *string `json:"schemaDescription,omitempty"`
 	SDL               *string `json:"sdl,omitempty"`
 	Endpoint          *string `json:"endpoint,omitempty"`
-	Version           *string `json:"version,omitempty"`
+	// Version moves the schema through its lifecycle: active, deprecated, sunset and retired
+	Version *string `json:"version,omitempty"`
+	// SunsetAt is required when deprecating a schema and must be in the future
+	SunsetAt *time.Time `json:"sunsetAt,omitempty"`
+	// DeprecationMessage tells consumers of a deprecated schema what to use instead
+	DeprecationMessage *string `json:"deprecationMessage,omitempty"`
+	// Revision is the revision the update is based on; the If-Match header takes precedence over it
+	Revision *int64 `json:"revision,omitempty"`
 }
 
 // CreateApplicationSubmissionRequest Consumer Application Submission DTOs
@@ -48,6 +74,8 @@ type CreateApplicationSubmissionRequest struct {
 	SelectedFields         []SelectedFieldRecord `json:"selectedFields" validate:"required,min=1"`
 	PreviousApplicationID  *string               `json:"previousApplicationId,omitempty"`
 	MemberID               string                `json:"memberId" validate:"required"`
+	// Status is draft to save an incomplete submission without validating it; otherwise it is pending
+	Status *string `json:"status,omitempty"`
 }
 
 // UpdateApplicationSubmissionRequest updates the status of a consumer application submission
@@ -73,6 +101,8 @@ type UpdateApplicationRequest struct {
 	ApplicationName        *string `json:"applicationName,omitempty"`
 	ApplicationDescription *string `json:"applicationDescription,omitempty"`
 	Version                *string `json:"version,omitempty"`
+	// Revision is the revision the update is based on; the If-Match header takes precedence over it
+	Revision *int64 `json:"revision,omitempty"`
 	// Note: SelectedFields is intentionally omitted from UpdateApplicationRequest.
 	// Field updates should be handled through a separate endpoint or process. That is not implemented yet.
 }
@@ -86,39 +116,87 @@ type CreateMemberRequest struct {
 type UpdateMemberRequest struct {
 	Name        *string `json:"name,omitempty"`
 	PhoneNumber *string `json:"phoneNumber,omitempty"`
+	// Revision is the revision the update is based on; the If-Match header takes precedence over it
+	Revision *int64 `json:"revision,omitempty"`
 }
 
 type MemberResponse struct {
-	MemberID    string `json:"memberId"`
-	Name        string `json:"name"`
-	Email       string `json:"email"`
-	PhoneNumber string `json:"phoneNumber"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
-	IdpUserID   string `json:"idpUserId"`
+	MemberID         string           `json:"memberId"`
+	Name             string           `json:"name"`
+	Email            string           `json:"email"`
+	PhoneNumber      string           `json:"phoneNumber"`
+	OrganizationID   *string          `json:"organizationId,omitempty"`
+	OrganizationRole OrganizationRole `json:"organizationRole,omitempty"`
+	CreatedAt        string           `json:"createdAt"`
+	UpdatedAt        string           `json:"updatedAt"`
+	IdpUserID        string           `json:"idpUserId"`
+	// Revision is the version updates must be based on, also returned as the ETag
+	Revision int64 `json:"revision"`
 }
 
 // ToMember converts a MemberResponse to a Member model (for internal use)
 func (e *MemberResponse) ToMember() Member {
 	return Member{
-		MemberID:    e.MemberID,
-		Name:        e.Name,
-		Email:       e.Email,
-		PhoneNumber: e.PhoneNumber,
-		IdpUserID:   e.IdpUserID,
+		MemberID:         e.MemberID,
+		Name:             e.Name,
+		Email:            e.Email,
+		PhoneNumber:      e.PhoneNumber,
+		IdpUserID:        e.IdpUserID,
+		OrganizationID:   e.OrganizationID,
+		OrganizationRole: e.OrganizationRole,
+		RevisionModel:    RevisionModel{Revision: e.Revision},
 	}
 }
 
 type SchemaResponse struct {
 	SchemaID          string  `json:"schemaId"`
 	MemberID          string  `json:"memberId"`
+	OrganizationID    *string `json:"organizationId,omitempty"`
 	SchemaName        string  `json:"schemaName"`
 	SDL               string  `json:"sdl"`
 	Endpoint          string  `json:"endpoint"`
 	Version           string  `json:"version"`
 	SchemaDescription *string `json:"schemaDescription,omitempty"`
-	CreatedAt         string  `json:"createdAt"`
-	UpdatedAt         string  `json:"updatedAt"`
+	// SunsetAt and DeprecationMessage are set while the schema is deprecated, sunset or retired
+	SunsetAt           *string `json:"sunsetAt,omitempty"`
+	DeprecationMessage *string `json:"deprecationMessage,omitempty"`
+	CreatedAt          string  `json:"createdAt"`
+	UpdatedAt          string  `json:"updatedAt"`
+	// Revision is the version updates must be based on, also returned as the ETag
+	Revision int64 `json:"revision"`
+	// FieldConfigurations are the field configurations the schema was approved with
+	FieldConfigurations FieldConfigurations `json:"fieldConfigurations,omitempty"`
+}
+
+// GrantStatus is the state of an application's PDP grant for a field
+type GrantStatus string
+
+const (
+	// GrantStatusActive grants allow the application to receive the field
+	GrantStatusActive GrantStatus = "active"
+	// GrantStatusExpired grants are held by the PDP but have expired
+	GrantStatusExpired GrantStatus = "expired"
+	// GrantStatusMissing fields were selected by the application but are not granted by the PDP
+	GrantStatusMissing GrantStatus = "missing"
+)
+
+// SchemaConsumerResponse is an application that selected fields of a schema, with its owning member
+type SchemaConsumerResponse struct {
+	ApplicationID   string `json:"applicationId"`
+	ApplicationName string `json:"applicationName"`
+	MemberID        string `json:"memberId"`
+	MemberName      string `json:"memberName"`
+	MemberEmail     string `json:"memberEmail"`
+	// Fields are the application's selected fields that the schema provides
+	Fields []SchemaConsumerField `json:"fields"`
+}
+
+// SchemaConsumerField is a field of a schema selected by a consuming application
+type SchemaConsumerField struct {
+	FieldName   string      `json:"fieldName"`
+	GrantStatus GrantStatus `json:"grantStatus"`
+	// GrantExpiresAt is when the PDP grant expires or expired (RFC3339)
+	GrantExpiresAt *string `json:"grantExpiresAt,omitempty"`
 }
 
 type SchemaSubmissionResponse struct {
@@ -130,9 +208,13 @@ type SchemaSubmissionResponse struct {
 	SchemaEndpoint    string  `json:"schemaEndpoint"`
 	Status            string  `json:"status"`
 	MemberID          string  `json:"memberId"`
+	OrganizationID    *string `json:"organizationId,omitempty"`
 	CreatedAt         string  `json:"createdAt"`
 	UpdatedAt         string  `json:"updatedAt"`
 	Review            *string `json:"review,omitempty"`
+	// FieldConfigurations are the configurations of the SDL's fields, suggested and adjusted
+	FieldConfigurations FieldConfigurations `json:"fieldConfigurations,omitempty"`
+	ProviderType        *string             `json:"providerType,omitempty"`
 }
 
 type ApplicationResponse struct {
@@ -141,15 +223,64 @@ type ApplicationResponse struct {
 	ApplicationDescription *string               `json:"applicationDescription,omitempty"`
 	SelectedFields         []SelectedFieldRecord `json:"selectedFields"`
 	MemberID               string                `json:"memberId"`
+	OrganizationID         *string               `json:"organizationId,omitempty"`
 	Version                string                `json:"version"`
 	IdpApplicationID       *string               `json:"idpApplicationId,omitempty"`
 	IdpClientID            *string               `json:"idpClientId,omitempty"`
-	CreatedAt              string                `json:"createdAt"`
-	UpdatedAt              string                `json:"updatedAt"`
+	// GrantExpiresAt is when the application's access to its selected fields expires (RFC3339)
+	GrantExpiresAt *string `json:"grantExpiresAt,omitempty"`
+	// Status is whether the orchestration engine serves the application's requests
+	Status       ApplicationStatus `json:"status"`
+	StatusReason *string           `json:"statusReason,omitempty"`
+	CreatedAt    string            `json:"createdAt"`
+	UpdatedAt    string            `json:"updatedAt"`
+	// Revision is the version updates must be based on, also returned as the ETag
+	Revision int64 `json:"revision"`
+}
+
+// ApplicationQuota is the data usage quota of an application; a nil quota is unlimited
+type ApplicationQuota struct {
+	ApplicationID      string `json:"applicationId"`
+	DailyRecordQuota   *int64 `json:"dailyRecordQuota"`
+	MonthlyRecordQuota *int64 `json:"monthlyRecordQuota"`
+}
+
+// UpdateApplicationQuotaRequest replaces the quota of an application; omitted or null quotas are unlimited
+type UpdateApplicationQuotaRequest struct {
+	DailyRecordQuota   *int64 `json:"dailyRecordQuota"`
+	MonthlyRecordQuota *int64 `json:"monthlyRecordQuota"`
+}
+
+// UpdateApplicationStatusRequest suspends or reactivates an application
+type UpdateApplicationStatusRequest struct {
+	Status ApplicationStatus `json:"status"`
+	// Reason is why the application is suspended; it is cleared when the application is reactivated
+	Reason *string `json:"reason,omitempty"`
+}
+
+// ApplicationContext describes the application behind a consumer token, for the orchestration engine
+// to enrich the policy and audit context of its requests with
+type ApplicationContext struct {
+	ApplicationID    string            `json:"applicationId"`
+	ApplicationName  string            `json:"applicationName"`
+	MemberID         string            `json:"memberId"`
+	MemberName       string            `json:"memberName"`
+	OrganizationID   *string           `json:"organizationId,omitempty"`
+	OrganizationName *string           `json:"organizationName,omitempty"`
+	Sector           *string           `json:"sector,omitempty"`
+	Environment      Environment       `json:"environment"`
+	Status           ApplicationStatus `json:"status"`
+	StatusReason     *string           `json:"statusReason,omitempty"`
+	// GrantExpiresAt is when the application's access to its selected fields expires (RFC3339)
+	GrantExpiresAt *string `json:"grantExpiresAt,omitempty"`
 }
 
 type ApplicationIDResponse struct {
-	ApplicationID string `json:"applicationId"`
+	ApplicationID string      `json:"applicationId"`
+	Environment   Environment `json:"environment"`
+	// ProviderEndpoints overrides the provider endpoint of each schema, keyed by schema ID. Sandbox
+	// clients are routed to mock providers.
+	ProviderEndpoints map[string]string `json:"providerEndpoints,omitempty"`
 }
 
 type ApplicationSubmissionResponse struct {
@@ -159,14 +290,530 @@ type ApplicationSubmissionResponse struct {
 	ApplicationDescription *string               `json:"applicationDescription,omitempty"`
 	SelectedFields         []SelectedFieldRecord `json:"selectedFields"`
 	MemberID               string                `json:"memberId"`
+	OrganizationID         *string               `json:"organizationId,omitempty"`
 	Status                 string                `json:"status"`
 	CreatedAt              string                `json:"createdAt"`
 	UpdatedAt              string                `json:"updatedAt"`
 	Review                 *string               `json:"review,omitempty"`
+	// Renewal is set on submissions that renew the grants of the previous application
+	Renewal bool `json:"renewal,omitempty"`
+}
+
+// PDPJobResponse represents a PDP sync job for the admin endpoints
+type PDPJobResponse struct {
+	JobID       string       `json:"jobId"`
+	JobType     PDPJobType   `json:"jobType"`
+	ResourceID  string       `json:"resourceId"`
+	Status      PDPJobStatus `json:"status"`
+	Attempts    int          `json:"attempts"`
+	MaxAttempts int          `json:"maxAttempts"`
+	NextRetryAt string       `json:"nextRetryAt"`
+	LastError   *string      `json:"lastError,omitempty"`
+	CompletedAt *string      `json:"completedAt,omitempty"`
+	CreatedAt   string       `json:"createdAt"`
+	UpdatedAt   string       `json:"updatedAt"`
+}
+
+// ApplicationCredentialResponse represents a client credential of an application. ClientSecret is
+// only set in the response that issued the secret; it cannot be retrieved again.
+type ApplicationCredentialResponse struct {
+	CredentialID  string                      `json:"credentialId"`
+	ApplicationID string                      `json:"applicationId"`
+	ClientID      string                      `json:"clientId"`
+	ClientSecret  string                      `json:"clientSecret,omitempty"`
+	SecretHint    string                      `json:"secretHint"`
+	Status        ApplicationCredentialStatus `json:"status"`
+	IssuedBy      string                      `json:"issuedBy"`
+	RevokedAt     *string                     `json:"revokedAt,omitempty"`
+	RevokedBy     *string                     `json:"revokedBy,omitempty"`
+	ExpiresAt     *string                     `json:"expiresAt,omitempty"`
+	CreatedAt     string                      `json:"createdAt"`
+	UpdatedAt     string                      `json:"updatedAt"`
+}
+
+// CreateWebhookRequest registers a webhook for an application
+type CreateWebhookRequest struct {
+	URL    string         `json:"url" validate:"required"`
+	Events []WebhookEvent `json:"events" validate:"required"`
+}
+
+// WebhookResponse represents a webhook subscription of an application. Secret is only set in the
+// response that created the subscription; it cannot be retrieved again.
+type WebhookResponse struct {
+	WebhookID     string         `json:"webhookId"`
+	ApplicationID string         `json:"applicationId"`
+	URL           string         `json:"url"`
+	Events        []WebhookEvent `json:"events"`
+	Secret        string         `json:"secret,omitempty"`
+	CreatedBy     string         `json:"createdBy"`
+	CreatedAt     string         `json:"createdAt"`
+	UpdatedAt     string         `json:"updatedAt"`
+}
+
+// WebhookDeliveryResponse represents an entry of a webhook's delivery log
+type WebhookDeliveryResponse struct {
+	DeliveryID     string                `json:"deliveryId"`
+	WebhookID      string                `json:"webhookId"`
+	Event          WebhookEvent          `json:"event"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	MaxAttempts    int                   `json:"maxAttempts"`
+	NextRetryAt    string                `json:"nextRetryAt"`
+	ResponseStatus *int                  `json:"responseStatus,omitempty"`
+	LastError      *string               `json:"lastError,omitempty"`
+	DeliveredAt    *string               `json:"deliveredAt,omitempty"`
+	CreatedAt      string                `json:"createdAt"`
+	UpdatedAt      string                `json:"updatedAt"`
+}
+
+// SubmissionReviewDecisionRequest records a reviewer's decision on the current review step
+type SubmissionReviewDecisionRequest struct {
+	Decision ReviewDecision `json:"decision" validate:"required"`
+	Comment  *string        `json:"comment,omitempty"`
+}
+
+// AssignSubmissionReviewersRequest replaces the reviewers assigned to a review step
+type AssignSubmissionReviewersRequest struct {
+	ReviewerIDs []string `json:"reviewerIds"`
+}
+
+// CreateSubmissionCommentRequest adds a comment to the discussion thread of a submission
+type CreateSubmissionCommentRequest struct {
+	Body string `json:"body" validate:"required"`
+}
+
+// SubmissionReviewResponse describes where a submission is in the review workflow
+type SubmissionReviewResponse struct {
+	SubmissionID   string                 `json:"submissionId"`
+	SubmissionType SubmissionType         `json:"submissionType"`
+	Status         string                 `json:"status"`
+	CurrentStep    *string                `json:"currentStep,omitempty"`
+	Steps          []ReviewStepResponse   `json:"steps"`
+	Decisions      []ReviewDecisionRecord `json:"decisions"`
+}
+
+// ReviewStepResponse is the progress of one review step
+type ReviewStepResponse struct {
+	Name              string   `json:"name"`
+	RequiredApprovals int      `json:"requiredApprovals"`
+	Approvals         int      `json:"approvals"`
+	Reviewers         []string `json:"reviewers"`
+	Complete          bool     `json:"complete"`
+}
+
+// ReviewDecisionRecord is a decision made by a reviewer
+type ReviewDecisionRecord struct {
+	ReviewID   string         `json:"reviewId"`
+	Step       string         `json:"step"`
+	ReviewerID string         `json:"reviewerId"`
+	Decision   ReviewDecision `json:"decision"`
+	Comment    *string        `json:"comment,omitempty"`
+	CreatedAt  string         `json:"createdAt"`
+}
+
+// SubmissionCommentResponse is a comment on a submission
+type SubmissionCommentResponse struct {
+	CommentID string `json:"commentId"`
+	AuthorID  string `json:"authorId"`
+	Step      string `json:"step"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// NotificationResponse is an in-app notification of the caller
+type NotificationResponse struct {
+	NotificationID string            `json:"notificationId"`
+	Event          NotificationEvent `json:"event"`
+	Title          string            `json:"title"`
+	Message        string            `json:"message"`
+	ResourceType   ResourceType      `json:"resourceType"`
+	ResourceID     string            `json:"resourceId"`
+	Read           bool              `json:"read"`
+	ReadAt         *string           `json:"readAt,omitempty"`
+	CreatedAt      string            `json:"createdAt"`
+}
+
+// NotificationPreferenceSetting is how a member receives notifications of one event
+type NotificationPreferenceSetting struct {
+	Event NotificationEvent `json:"event"`
+	InApp bool              `json:"inApp"`
+	Email bool              `json:"email"`
+}
+
+// UpdateNotificationPreferencesRequest changes the caller's notification preferences. Events that
+// are not listed keep their current setting.
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceSetting `json:"preferences" validate:"required"`
+}
+
+// CreateInvitationRequest invites a person to join the portal with a role and, optionally, to join
+// an organization. OrganizationRole defaults to org_member.
+type CreateInvitationRequest struct {
+	Email            string           `json:"email" validate:"required,email"`
+	Role             Role             `json:"role" validate:"required"`
+	OrganizationID   *string          `json:"organizationId,omitempty"`
+	OrganizationRole OrganizationRole `json:"organizationRole,omitempty"`
+}
+
+// AcceptInvitationRequest accepts an invitation and provides the new member's details
+type AcceptInvitationRequest struct {
+	Token       string `json:"token" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	PhoneNumber string `json:"phoneNumber" validate:"required"`
+}
+
+// InvitationResponse represents an invitation. Token is only set in the response that created it.
+type InvitationResponse struct {
+	InvitationID     string           `json:"invitationId"`
+	Email            string           `json:"email"`
+	Role             Role             `json:"role"`
+	OrganizationID   *string          `json:"organizationId,omitempty"`
+	OrganizationRole OrganizationRole `json:"organizationRole,omitempty"`
+	Status           InvitationStatus `json:"status"`
+	Token            string           `json:"token,omitempty"`
+	ExpiresAt        string           `json:"expiresAt"`
+	InvitedBy        string           `json:"invitedBy"`
+	AcceptedAt       *string          `json:"acceptedAt,omitempty"`
+	MemberID         *string          `json:"memberId,omitempty"`
+	CreatedAt        string           `json:"createdAt"`
+}
+
+// CreateOrganizationRequest creates a new organization
+type CreateOrganizationRequest struct {
+	Name         string  `json:"name" validate:"required"`
+	Description  *string `json:"description,omitempty"`
+	Sector       *string `json:"sector,omitempty"`
+	ProviderType *string `json:"providerType,omitempty"`
+}
+
+// UpdateOrganizationRequest updates an existing organization
+type UpdateOrganizationRequest struct {
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	Sector       *string `json:"sector,omitempty"`
+	ProviderType *string `json:"providerType,omitempty"`
+}
+
+// SetOrganizationMemberRequest adds a member to an organization or changes their role in it
+type SetOrganizationMemberRequest struct {
+	Role OrganizationRole `json:"role" validate:"required"`
+}
+
+// OrganizationResponse represents an organization
+type OrganizationResponse struct {
+	OrganizationID string  `json:"organizationId"`
+	Name           string  `json:"name"`
+	Description    *string `json:"description,omitempty"`
+	Sector         *string `json:"sector,omitempty"`
+	ProviderType   *string `json:"providerType,omitempty"`
+	CreatedAt      string  `json:"createdAt"`
+	UpdatedAt      string  `json:"updatedAt"`
+}
+
+// ApplicationUsageResponse describes how an application used the exchange over a time window,
+// aggregated from the exchange events in the audit log
+type ApplicationUsageResponse struct {
+	ApplicationID string    `json:"applicationId"`
+	StartTime     time.Time `json:"startTime"`
+	EndTime       time.Time `json:"endTime"`
+	UsageCounts
+	// Daily has one entry per UTC day in the window, including days without requests
+	Daily []DailyUsage `json:"daily"`
+	// Fields are the fields the application was authorized to access, most requested first
+	Fields []FieldUsage `json:"fields"`
+}
+
+// UsageCounts counts the exchange activity of an application
+type UsageCounts struct {
+	// Requests is the number of data requests the application made
+	Requests int64 `json:"requests"`
+	// Events counts every exchange event of those requests: policy and consent checks and provider fetches
+	Events    int64   `json:"events"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	// ConsentDenied is the number of consent checks the data owner rejected
+	ConsentDenied int64 `json:"consentDenied"`
+}
+
+// DailyUsage holds the usage counts of one day
+type DailyUsage struct {
+	Date string `json:"date"`
+	UsageCounts
+}
+
+// FieldUsage is the number of requests that accessed a field
+type FieldUsage struct {
+	Field string `json:"field"`
+	Count int64  `json:"count"`
+}
+
+// AuditEventPage is a page of audit events from the audit service. The events are passed through as
+// the audit service returns them.
+type AuditEventPage struct {
+	Events []json.RawMessage `json:"events"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+	// NextCursor fetches the following page; it is omitted once a page comes back short
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// MemberSessionResponse is an active sign-in session of a member at the identity provider
+type MemberSessionResponse struct {
+	SessionID string `json:"sessionId"`
+	// LoginTime and LastAccessTime are omitted when the identity provider does not report them
+	LoginTime      *string  `json:"loginTime,omitempty"`
+	LastAccessTime *string  `json:"lastAccessTime,omitempty"`
+	IPAddress      string   `json:"ipAddress,omitempty"`
+	UserAgent      string   `json:"userAgent,omitempty"`
+	Applications   []string `json:"applications"`
+}
+
+// MemberSessionsResponse lists the active sessions of a member
+type MemberSessionsResponse struct {
+	MemberID string                  `json:"memberId"`
+	Sessions []MemberSessionResponse `json:"sessions"`
+	Count    int                     `json:"count"`
+}
+
+// RevokeMemberSessionsResponse reports the sessions of a member that were revoked
+type RevokeMemberSessionsResponse struct {
+	MemberID string `json:"memberId"`
+	// RevokedSessions is how many sessions were active before the revocation
+	RevokedSessions int    `json:"revokedSessions"`
+	RevokedAt       string `json:"revokedAt"`
+}
+
+// CatalogField is a field of an approved schema that applications can request
+type CatalogField struct {
+	FieldName         string            `json:"fieldName"`
+	DisplayName       *string           `json:"displayName,omitempty"`
+	Description       *string           `json:"description,omitempty"`
+	SchemaID          string            `json:"schemaId"`
+	SchemaName        string            `json:"schemaName"`
+	ProviderID        string            `json:"providerId"`
+	ProviderName      string            `json:"providerName"`
+	Source            Source            `json:"source"`
+	Owner             *Owner            `json:"owner,omitempty"`
+	AccessControlType AccessControlType `json:"accessControlType"`
+	Classification    Classification    `json:"classification,omitempty"`
+	// ConsentRequired is set when the data owner must consent before an application receives the field
+	ConsentRequired bool `json:"consentRequired"`
+}
+
+// CatalogFieldFilters narrows the field catalog to the given facet values; empty filters match every field
+type CatalogFieldFilters struct {
+	SchemaID          string
+	ProviderID        string
+	Classification    Classification
+	AccessControlType AccessControlType
+	ConsentRequired   *bool
+}
+
+// FacetCount is the number of catalog fields with one facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	// Label is the display name of the value, e.g. the provider's name
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// CatalogFacets counts the fields matching the search per facet value, before the facet filters apply
+type CatalogFacets struct {
+	Providers          []FacetCount `json:"providers"`
+	Schemas            []FacetCount `json:"schemas"`
+	Classifications    []FacetCount `json:"classifications"`
+	AccessControlTypes []FacetCount `json:"accessControlTypes"`
+	ConsentRequired    []FacetCount `json:"consentRequired"`
+}
+
+// CatalogFieldsResponse is a page of the field catalog with its facets
+type CatalogFieldsResponse struct {
+	Items      []CatalogField      `json:"items"`
+	Count      int                 `json:"count"`
+	Pagination *PaginationMetadata `json:"pagination"`
+	Facets     CatalogFacets       `json:"facets"`
+}
+
+// RevisionConflictResponse is returned with 409 when an update is based on a stale revision
+type RevisionConflictResponse struct {
+	Error string `json:"error"`
+	// Current is the resource as it is now, for the client to merge its changes into
+	Current interface{} `json:"current"`
+}
+
+// SDLValidationErrorResponse is returned when a submitted SDL fails validation or linting
+type SDLValidationErrorResponse struct {
+	Error      string         `json:"error"`
+	LintErrors []SDLLintError `json:"lintErrors"`
+	RequestID  string         `json:"requestId,omitempty"`
+}
+
+// GraphQLRequest is a GraphQL query sent to the admin GraphQL API
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse is the result of a GraphQL query; Data is omitted when the query is invalid
+type GraphQLResponse struct {
+	Data   interface{}   `json:"data,omitempty"`
+	Errors gqlerror.List `json:"errors,omitempty"`
+}
+
+// CollectionResponse Generic collection response, shared with the exchange services
+type CollectionResponse = response.CollectionResponse
+
+// Collection paging limits
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// SortOrder is the direction of a collection sort
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+// ListQuery holds the paging, sorting and filters of a collection request.
+// Each collection applies only the filters that make sense for its resource.
+type ListQuery struct {
+	Page  int
+	Limit int
+	// Sort is the JSON name of the field to sort by, e.g. createdAt
+	Sort  string
+	Order SortOrder
+
+	MemberID *string
+	// OrganizationID widens the MemberID filter to the resources of the member's organization
+	OrganizationID *string
+	IdpUserID      *string
+	Email          *string
+	Status         []string
+	// Search matches the resource name case-insensitively
+	Search        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// PaginationMetadata describes the page returned in a CollectionResponse
+type PaginationMetadata = response.Pagination
+
+// StartImpersonationRequest starts an impersonation session in which an admin acts as a member
+type StartImpersonationRequest struct {
+	MemberID string `json:"memberId" validate:"required"`
+	Reason   string `json:"reason" validate:"required"`
+}
+
+// ImpersonationSessionResponse represents an impersonation session
+type ImpersonationSessionResponse struct {
+	SessionID      string  `json:"sessionId"`
+	AdminIdpUserID string  `json:"adminIdpUserId"`
+	AdminEmail     string  `json:"adminEmail"`
+	MemberID       string  `json:"memberId"`
+	Reason         string  `json:"reason"`
+	Active         bool    `json:"active"`
+	ExpiresAt      string  `json:"expiresAt"`
+	EndedAt        *string `json:"endedAt,omitempty"`
+	CreatedAt      string  `json:"createdAt"`
+}
+
+// AdminApprovalResponse represents a sensitive admin action waiting for, or decided by, a second admin
+type AdminApprovalResponse struct {
+	ApprovalID string         `json:"approvalId"`
+	Action     ApprovalAction `json:"action"`
+	ResourceID string         `json:"resourceId"`
+	ApprovalPayload
+	// Status is expired once a pending approval was not decided in time
+	Status           ApprovalStatus `json:"status"`
+	RequestedBy      string         `json:"requestedBy"`
+	RequestedByEmail string         `json:"requestedByEmail"`
+	DecidedBy        *string        `json:"decidedBy,omitempty"`
+	DecidedByEmail   *string        `json:"decidedByEmail,omitempty"`
+	DecidedAt        *string        `json:"decidedAt,omitempty"`
+	ExpiresAt        string         `json:"expiresAt"`
+	LastError        *string        `json:"lastError,omitempty"`
+	CreatedAt        string         `json:"createdAt"`
+	// Result is the response of the action, returned once to the admin who confirms the approval
+	Result interface{} `json:"result,omitempty"`
+}
+
+// CreateSavedViewRequest saves a named filter and sort of an admin collection
+type CreateSavedViewRequest struct {
+	Name       string              `json:"name" validate:"required"`
+	Collection SavedViewCollection `json:"collection" validate:"required"`
+	Query      SavedViewQuery      `json:"query"`
+	Shared     bool                `json:"shared"`
+}
+
+// UpdateSavedViewRequest changes a saved view; omitted fields are kept and a query replaces the saved one
+type UpdateSavedViewRequest struct {
+	Name   *string         `json:"name,omitempty"`
+	Query  *SavedViewQuery `json:"query,omitempty"`
+	Shared *bool           `json:"shared,omitempty"`
+}
+
+// SavedViewResponse represents a saved view
+type SavedViewResponse struct {
+	ViewID         string              `json:"viewId"`
+	Name           string              `json:"name"`
+	Collection     SavedViewCollection `json:"collection"`
+	Query          SavedViewQuery      `json:"query"`
+	OwnerIdpUserID string              `json:"ownerIdpUserId"`
+	Shared         bool                `json:"shared"`
+	CreatedAt      string              `json:"createdAt"`
+	UpdatedAt      string              `json:"updatedAt"`
+}
+
+// CreateFieldConfigurationTemplateRequest creates the field configuration template of a provider type
+type CreateFieldConfigurationTemplateRequest struct {
+	Name         string                  `json:"name" validate:"required"`
+	Description  *string                 `json:"description,omitempty"`
+	ProviderType string                  `json:"providerType" validate:"required"`
+	Rules        FieldConfigurationRules `json:"rules" validate:"required"`
+}
+
+// UpdateFieldConfigurationTemplateRequest changes a field configuration template; omitted fields are
+// kept and rules replace the template's rules
+type UpdateFieldConfigurationTemplateRequest struct {
+	Name         *string                 `json:"name,omitempty"`
+	Description  *string                 `json:"description,omitempty"`
+	ProviderType *string                 `json:"providerType,omitempty"`
+	Rules        FieldConfigurationRules `json:"rules,omitempty"`
+}
+
+// FieldConfigurationTemplateResponse represents a field configuration template
+type FieldConfigurationTemplateResponse struct {
+	TemplateID   string                  `json:"templateId"`
+	Name         string                  `json:"name"`
+	Description  *string                 `json:"description,omitempty"`
+	ProviderType string                  `json:"providerType"`
+	Rules        FieldConfigurationRules `json:"rules"`
+	CreatedAt    string                  `json:"createdAt"`
+	UpdatedAt    string                  `json:"updatedAt"`
+}
+
+// SubmissionAttachmentResponse represents a document attached to a submission
+type SubmissionAttachmentResponse struct {
+	AttachmentID string               `json:"attachmentId"`
+	SubmissionID string               `json:"submissionId"`
+	Category     AttachmentCategory   `json:"category"`
+	FileName     string               `json:"fileName"`
+	ContentType  string               `json:"contentType"`
+	Size         int64                `json:"size"`
+	ScanStatus   AttachmentScanStatus `json:"scanStatus"`
+	ScanResult   *string              `json:"scanResult,omitempty"`
+	ScannedAt    *string              `json:"scannedAt,omitempty"`
+	UploadedBy   string               `json:"uploadedBy"`
+	CreatedAt    string               `json:"createdAt"`
 }
 
-// CollectionResponse Generic collection response
-type CollectionResponse struct {
-	Items interface{} `json:"items"`
-	Count int         `json:"count"`
+// AttachmentDownloadResponse is a signed URL that downloads an attachment until it expires
+type AttachmentDownloadResponse struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expiresAt"`
 }
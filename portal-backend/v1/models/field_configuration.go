@@ -0,0 +1,112 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// FieldConfiguration is the access policy of one field of a provider schema. The configurations of
+// a schema submission are suggested from its SDL when it is submitted and can be adjusted by the
+// provider; when the submission is approved they become the field's policy metadata in the PDP.
+type FieldConfiguration struct {
+	// FieldName is the dot-notation path of the field, as in policy metadata, e.g. "person.nic"
+	FieldName string `json:"fieldName"`
+	// Type is the GraphQL type of the field, e.g. "String!"
+	Type              string            `json:"type"`
+	DisplayName       *string           `json:"displayName,omitempty"`
+	Description       *string           `json:"description,omitempty"`
+	Source            Source            `json:"source"`
+	IsOwner           bool              `json:"isOwner"`
+	Owner             *Owner            `json:"owner,omitempty"`
+	AccessControlType AccessControlType `json:"accessControlType,omitempty"`
+	Classification    Classification    `json:"classification,omitempty"`
+	// ConsentRequired is whether consumers will need the data owner's consent to read the field. It
+	// is derived from the other settings and ignored in requests.
+	ConsentRequired bool `json:"consentRequired"`
+	// Reason explains where a suggested configuration came from
+	Reason string `json:"reason,omitempty"`
+	// Adjusted is set once the provider has changed the suggested configuration. Adjusted
+	// configurations are kept when the SDL is changed.
+	Adjusted bool `json:"adjusted"`
+}
+
+// RequiresConsent reports whether the field needs the data owner's consent under the PDP's rules:
+// fields not owned by the provider need consent when restricted, which classified fields without
+// an access control type are unless they are public, and sensitive personal fields always do.
+func (c *FieldConfiguration) RequiresConsent() bool {
+	if c.IsOwner {
+		return false
+	}
+	if c.Classification == ClassificationSensitivePersonal {
+		return true
+	}
+	if c.AccessControlType == "" {
+		return c.Classification != ClassificationPublic
+	}
+	return c.AccessControlType == AccessControlTypeRestricted
+}
+
+// PolicyRecord returns the policy metadata record the configuration describes
+func (c *FieldConfiguration) PolicyRecord() PolicyMetadataCreateRequestRecord {
+	return PolicyMetadataCreateRequestRecord{
+		FieldName:         c.FieldName,
+		DisplayName:       c.DisplayName,
+		Description:       c.Description,
+		Source:            c.Source,
+		IsOwner:           c.IsOwner,
+		AccessControlType: c.AccessControlType,
+		Classification:    c.Classification,
+		Owner:             c.Owner,
+	}
+}
+
+// FieldConfigurations is the JSONB list of field configurations of a schema or schema submission
+type FieldConfigurations []FieldConfiguration
+
+// HasSensitivePersonalData reports whether any field is classified as sensitive personal data
+func (f FieldConfigurations) HasSensitivePersonalData() bool {
+	for _, configuration := range f {
+		if configuration.Classification == ClassificationSensitivePersonal {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan implements the sql.Scanner interface for FieldConfigurations
+func (f *FieldConfigurations) Scan(value interface{}) error {
+	if value == nil {
+		*f = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into FieldConfigurations", value)
+	}
+
+	return json.Unmarshal(bytes, f)
+}
+
+// Value implements the driver.Valuer interface for FieldConfigurations
+func (f FieldConfigurations) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// GormDataType gorm common data type
+func (FieldConfigurations) GormDataType() string {
+	return "jsonb"
+}
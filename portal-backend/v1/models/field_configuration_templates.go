@@ -0,0 +1,119 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// FieldConfigurationTemplate represents the field_configuration_templates table: the default field
+// configurations of the providers of one type, e.g. person registries. When a provider of the type
+// submits a schema, the template's rules pre-fill the owner and access settings of the fields they
+// match, so that registries of the same kind are not configured field by field.
+type FieldConfigurationTemplate struct {
+	TemplateID  string  `gorm:"primarykey;column:template_id" json:"templateId"`
+	Name        string  `gorm:"column:name;not null;unique" json:"name"`
+	Description *string `gorm:"column:description" json:"description,omitempty"`
+	// ProviderType is the lower-case type of the providers the template applies to, e.g.
+	// "person-registry"; each provider type has at most one template
+	ProviderType string                  `gorm:"column:provider_type;not null;unique" json:"providerType"`
+	Rules        FieldConfigurationRules `gorm:"column:rules;not null" json:"rules"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (FieldConfigurationTemplate) TableName() string {
+	return "field_configuration_templates"
+}
+
+// FieldConfigurationRule configures the fields that match its pattern. Settings the rule leaves
+// empty keep the value of the field's directives, if it has any.
+type FieldConfigurationRule struct {
+	// Field is a pattern like "person.nic", "person.*" or "nic". A pattern with a dot is matched
+	// against the dot-notation path of a field, one without against the field's own name; "*"
+	// matches any characters, including dots.
+	Field             string            `json:"field"`
+	IsOwner           *bool             `json:"isOwner,omitempty"`
+	Owner             *Owner            `json:"owner,omitempty"`
+	AccessControlType AccessControlType `json:"accessControlType"`
+	Classification    Classification    `json:"classification"`
+}
+
+// Matches reports whether the rule's pattern matches the field at fieldPath
+func (r *FieldConfigurationRule) Matches(fieldPath string) bool {
+	name := fieldPath
+	if !strings.Contains(r.Field, ".") {
+		name = fieldPath[strings.LastIndex(fieldPath, ".")+1:]
+	}
+	matched, err := path.Match(r.Field, name)
+	return err == nil && matched
+}
+
+// Apply sets the rule's settings on a field configuration
+func (r *FieldConfigurationRule) Apply(configuration *FieldConfiguration) {
+	if r.IsOwner != nil {
+		configuration.IsOwner = *r.IsOwner
+	}
+	if r.Owner != nil {
+		owner := *r.Owner
+		configuration.Owner = &owner
+	}
+	configuration.AccessControlType = r.AccessControlType
+	configuration.Classification = r.Classification
+}
+
+// Match returns the first rule of the template that matches the field at fieldPath, or nil if none does
+func (t *FieldConfigurationTemplate) Match(fieldPath string) *FieldConfigurationRule {
+	if t == nil {
+		return nil
+	}
+	for i := range t.Rules {
+		if t.Rules[i].Matches(fieldPath) {
+			return &t.Rules[i]
+		}
+	}
+	return nil
+}
+
+// FieldConfigurationRules is the JSONB list of rules of a field configuration template, in the order
+// they are matched
+type FieldConfigurationRules []FieldConfigurationRule
+
+// Scan implements the sql.Scanner interface for FieldConfigurationRules
+func (f *FieldConfigurationRules) Scan(value interface{}) error {
+	if value == nil {
+		*f = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into FieldConfigurationRules", value)
+	}
+
+	return json.Unmarshal(bytes, f)
+}
+
+// Value implements the driver.Valuer interface for FieldConfigurationRules
+func (f FieldConfigurationRules) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// GormDataType gorm common data type
+func (FieldConfigurationRules) GormDataType() string {
+	return "jsonb"
+}
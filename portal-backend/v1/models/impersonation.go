@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrImpersonationInactive is returned when a request names an impersonation session that is unknown,
+// belongs to another admin, was stopped or has expired
+var ErrImpersonationInactive = errors.New("impersonation session is not active")
+
+// ImpersonationSessionHeader names the header an admin sends, alongside their own access token, to
+// act as the member of an active impersonation session
+const ImpersonationSessionHeader = "X-Impersonation-Session"
+
+// ImpersonationSession represents the impersonation_sessions table. A support admin acting as a
+// member keeps their own token; the session only scopes their requests to the member's identity and
+// permissions until it expires or is stopped.
+type ImpersonationSession struct {
+	SessionID      string `gorm:"primarykey;column:session_id" json:"sessionId"`
+	AdminIdpUserID string `gorm:"column:admin_idp_user_id;not null;index" json:"adminIdpUserId"`
+	AdminEmail     string `gorm:"column:admin_email;not null" json:"adminEmail"`
+	MemberID       string `gorm:"column:member_id;not null;index" json:"memberId"`
+	// Reason records why support needed to act as the member, e.g. a ticket reference
+	Reason    string     `gorm:"column:reason;not null" json:"reason"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null" json:"expiresAt"`
+	EndedAt   *time.Time `gorm:"column:ended_at" json:"endedAt,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (ImpersonationSession) TableName() string {
+	return "impersonation_sessions"
+}
+
+// IsActive reports whether the session can still be used at now
+func (s *ImpersonationSession) IsActive(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Impersonation describes the impersonation session a request is made in. It is stored in the request
+// context next to the impersonated user so that actions and audit events name both people.
+type Impersonation struct {
+	SessionID       string    `json:"sessionId"`
+	AdminIdpUserID  string    `json:"adminIdpUserId"`
+	AdminEmail      string    `json:"adminEmail"`
+	MemberID        string    `json:"memberId"`
+	MemberIdpUserID string    `json:"memberIdpUserId"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+}
+
+// NewImpersonatedUser returns the user an admin's requests are made as during an impersonation
+// session: the member's identity with member permissions only, valid until the admin's token or the
+// session expires, whichever is first
+func NewImpersonatedUser(admin *AuthenticatedUser, member *Member, impersonation *Impersonation) *AuthenticatedUser {
+	roles := []Role{RoleMember}
+	expiresAt := admin.ExpiresAt
+	if impersonation.ExpiresAt.Before(expiresAt) {
+		expiresAt = impersonation.ExpiresAt
+	}
+	user := &AuthenticatedUser{
+		IdpUserID:   member.IdpUserID,
+		Email:       member.Email,
+		FirstName:   member.Name,
+		PhoneNumber: member.PhoneNumber,
+		Roles:       roles,
+		IssuedAt:    admin.IssuedAt,
+		ExpiresAt:   expiresAt,
+		permissions: computePermissions(roles),
+	}
+	user.SetCachedMemberID(member.MemberID, nil)
+	return user
+}
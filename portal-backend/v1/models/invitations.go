@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// InvitationStatus represents the lifecycle state of a member invitation
+type InvitationStatus string
+
+const (
+	// InvitationStatusPending invitations can be accepted until they expire
+	InvitationStatusPending InvitationStatus = "pending"
+	// InvitationStatusAccepted invitations provisioned a member
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	// InvitationStatusRevoked invitations were withdrawn by an admin
+	InvitationStatusRevoked InvitationStatus = "revoked"
+	// InvitationStatusExpired is reported for pending invitations past their expiry; it is never stored
+	InvitationStatusExpired InvitationStatus = "expired"
+)
+
+// Invitation represents the invitations table. Only a hash of the invitation token is stored; the
+// token itself is returned once, when the invitation is created.
+type Invitation struct {
+	InvitationID string `gorm:"primarykey;column:invitation_id" json:"invitationId"`
+	Email        string `gorm:"column:email;not null;index" json:"email"`
+	Role         Role   `gorm:"column:role;not null" json:"role"`
+	// OrganizationID is the organization the invitee joins with OrganizationRole, if any
+	OrganizationID   *string          `gorm:"column:organization_id" json:"organizationId,omitempty"`
+	OrganizationRole OrganizationRole `gorm:"column:organization_role" json:"organizationRole,omitempty"`
+	TokenHash        string           `gorm:"column:token_hash;not null;unique" json:"-"`
+	Status           InvitationStatus `gorm:"column:status;not null" json:"status"`
+	ExpiresAt        time.Time        `gorm:"column:expires_at;not null" json:"expiresAt"`
+	InvitedBy        string           `gorm:"column:invited_by;not null" json:"invitedBy"`
+	AcceptedAt       *time.Time       `gorm:"column:accepted_at" json:"acceptedAt,omitempty"`
+	// MemberID is the member provisioned when the invitation was accepted
+	MemberID *string `gorm:"column:member_id" json:"memberId,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (Invitation) TableName() string {
+	return "invitations"
+}
+
+// UserGroup returns the IDP group that grants an invited role
+func (r Role) UserGroup() (UserGroup, bool) {
+	switch r {
+	case RoleAdmin:
+		return UserGroupAdmin, true
+	case RoleMember:
+		return UserGroupMember, true
+	}
+	return "", false
+}
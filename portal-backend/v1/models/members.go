@@ -7,7 +7,12 @@ type Member struct {
 	Email       string `gorm:"column:email;not null;unique" json:"email"`
 	PhoneNumber string `gorm:"column:phone_number;not null" json:"phoneNumber"`
 	IdpUserID   string `gorm:"column:idp_user_id;not null;unique" json:"idpUserId"`
+	// OrganizationID is the organization the member belongs to, if any
+	OrganizationID   *string          `gorm:"column:organization_id;index" json:"organizationId,omitempty"`
+	OrganizationRole OrganizationRole `gorm:"column:organization_role" json:"organizationRole,omitempty"`
 	BaseModel
+	SoftDeleteModel
+	RevisionModel
 }
 
 // TableName sets the table name for GORM
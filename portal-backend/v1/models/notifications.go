@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// NotificationEvent identifies what a notification is about
+type NotificationEvent string
+
+const (
+	// NotificationEventSubmissionStatusChanged is sent to the owner of a submission when its status changes
+	NotificationEventSubmissionStatusChanged NotificationEvent = "submission_status_changed"
+	// NotificationEventMembershipApproved is sent to a member when an admin onboards them
+	NotificationEventMembershipApproved NotificationEvent = "membership_approved"
+	// NotificationEventCredentialExpiring is sent to the owner of an application whose credential is about to expire
+	NotificationEventCredentialExpiring NotificationEvent = "credential_expiring"
+	// NotificationEventSchemaDeprecated is sent to the owner of an application that uses fields of a schema being phased out
+	NotificationEventSchemaDeprecated NotificationEvent = "schema_deprecated"
+	// NotificationEventGrantExpiring is sent to the owner of an application whose field grants are about to expire
+	NotificationEventGrantExpiring NotificationEvent = "grant_expiring"
+)
+
+// NotificationEvents lists every notification event in display order
+var NotificationEvents = []NotificationEvent{
+	NotificationEventSubmissionStatusChanged,
+	NotificationEventMembershipApproved,
+	NotificationEventCredentialExpiring,
+	NotificationEventSchemaDeprecated,
+	NotificationEventGrantExpiring,
+}
+
+// IsValid checks if the notification event is known
+func (e NotificationEvent) IsValid() bool {
+	switch e {
+	case NotificationEventSubmissionStatusChanged, NotificationEventMembershipApproved, NotificationEventCredentialExpiring,
+		NotificationEventSchemaDeprecated, NotificationEventGrantExpiring:
+		return true
+	}
+	return false
+}
+
+// NotificationEmailStatus represents the delivery state of the email copy of a notification
+type NotificationEmailStatus string
+
+const (
+	// NotificationEmailStatusSkipped notifications have no email copy, because the member opted out or email is not configured
+	NotificationEmailStatusSkipped NotificationEmailStatus = "skipped"
+	// NotificationEmailStatusPending emails are waiting for the notification worker
+	NotificationEmailStatusPending NotificationEmailStatus = "pending"
+	// NotificationEmailStatusSent emails were accepted by the SMTP server
+	NotificationEmailStatusSent NotificationEmailStatus = "sent"
+	// NotificationEmailStatusFailed emails were rejected by the SMTP server; see email_error
+	NotificationEmailStatusFailed NotificationEmailStatus = "failed"
+)
+
+// Notification represents the notifications table. A notification is shown in-app when InApp is
+// set, and its email copy is delivered by the notification worker.
+type Notification struct {
+	NotificationID string                  `gorm:"primarykey;column:notification_id" json:"notificationId"`
+	MemberID       string                  `gorm:"column:member_id;not null;index" json:"memberId"`
+	Event          NotificationEvent       `gorm:"column:event;not null" json:"event"`
+	Title          string                  `gorm:"column:title;not null" json:"title"`
+	Message        string                  `gorm:"column:message;not null" json:"message"`
+	ResourceType   ResourceType            `gorm:"column:resource_type;not null" json:"resourceType"`
+	ResourceID     string                  `gorm:"column:resource_id;not null" json:"resourceId"`
+	InApp          bool                    `gorm:"column:in_app;not null" json:"inApp"`
+	EmailStatus    NotificationEmailStatus `gorm:"column:email_status;not null;index" json:"emailStatus"`
+	EmailError     *string                 `gorm:"column:email_error" json:"emailError,omitempty"`
+	ReadAt         *time.Time              `gorm:"column:read_at" json:"readAt,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationPreference represents the notification_preferences table. A member without a
+// preference for an event receives it both in-app and by email.
+type NotificationPreference struct {
+	MemberID string            `gorm:"primarykey;column:member_id" json:"memberId"`
+	Event    NotificationEvent `gorm:"primarykey;column:event" json:"event"`
+	InApp    bool              `gorm:"column:in_app;not null" json:"inApp"`
+	Email    bool              `gorm:"column:email;not null" json:"email"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,40 @@
+package models
+
+// OrganizationRole is the role of a member within their organization
+type OrganizationRole string
+
+const (
+	// OrganizationRoleAdmin members manage their organization's details and membership
+	OrganizationRoleAdmin OrganizationRole = "org_admin"
+	// OrganizationRoleMember members share ownership of their organization's schemas and applications
+	OrganizationRoleMember OrganizationRole = "org_member"
+)
+
+// IsValid checks if the organization role is known
+func (r OrganizationRole) IsValid() bool {
+	switch r {
+	case OrganizationRoleAdmin, OrganizationRoleMember:
+		return true
+	}
+	return false
+}
+
+// Organization represents the organizations table. An organization is a government entity whose
+// members share ownership of the schemas and applications any of them create.
+type Organization struct {
+	OrganizationID string  `gorm:"primarykey;column:organization_id" json:"organizationId"`
+	Name           string  `gorm:"column:name;not null;unique" json:"name"`
+	Description    *string `gorm:"column:description" json:"description,omitempty"`
+	// Sector is the sector the organization operates in, e.g. "banking"; PDP policy conditions can
+	// match it as consumer.sector
+	Sector *string `gorm:"column:sector" json:"sector,omitempty"`
+	// ProviderType is the kind of data the organization provides, e.g. "person-registry"; the field
+	// configuration template of the type pre-fills the configurations of its schema submissions
+	ProviderType *string `gorm:"column:provider_type" json:"providerType,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (Organization) TableName() string {
+	return "organizations"
+}
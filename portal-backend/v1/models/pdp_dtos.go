@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Request and Response DTOs for Policy Metadata and Allow List Management
 
 // PolicyMetadataCreateRequestRecord represents the request to create policy metadata
@@ -9,7 +11,8 @@ type PolicyMetadataCreateRequestRecord struct {
 	Description       *string           `json:"description,omitempty"`
 	Source            Source            `json:"source" validate:"required,source_enum"`
 	IsOwner           bool              `json:"isOwner" validate:"required"`
-	AccessControlType AccessControlType `json:"accessControlType" validate:"required,access_control_type_enum"`
+	AccessControlType AccessControlType `json:"accessControlType,omitempty" validate:"omitempty,access_control_type_enum"`
+	Classification    Classification    `json:"classification,omitempty"`
 	Owner             *Owner            `json:"owner,omitempty" validate:"omitempty,owner_enum"`
 }
 
@@ -17,6 +20,8 @@ type PolicyMetadataCreateRequestRecord struct {
 type PolicyMetadataCreateRequest struct {
 	SchemaID string                              `json:"schemaId" validate:"required"`
 	Records  []PolicyMetadataCreateRequestRecord `json:"records" validate:"required,dive"`
+	// ProviderID namespaces the schema's policy records in the PDP
+	ProviderID string `json:"providerId,omitempty"`
 }
 
 // PolicyMetadataResponse represents the response from policy metadata operations
@@ -29,6 +34,7 @@ type PolicyMetadataResponse struct {
 	Source            Source            `json:"source"`
 	IsOwner           bool              `json:"isOwner"`
 	AccessControlType AccessControlType `json:"accessControlType"`
+	Classification    Classification    `json:"classification"`
 	AllowList         AllowList         `json:"allowList"`
 	Owner             *Owner            `json:"owner,omitempty"`
 	CreatedAt         string            `json:"createdAt"`
@@ -40,11 +46,119 @@ type PolicyMetadataCreateResponse struct {
 	Records []PolicyMetadataResponse `json:"records"`
 }
 
+// PolicyDocument is the PDP's export of the field policies of every schema
+type PolicyDocument struct {
+	Version string                 `json:"version"`
+	Schemas []PolicyDocumentSchema `json:"schemas"`
+}
+
+// PolicyDocumentSchema holds the field policies of one schema
+type PolicyDocumentSchema struct {
+	SchemaID   string                `json:"schemaId"`
+	ProviderID string                `json:"providerId,omitempty"`
+	Fields     []PolicyDocumentField `json:"fields"`
+}
+
+// PolicyDocumentField holds the policy of one field
+type PolicyDocumentField struct {
+	FieldName         string            `json:"fieldName"`
+	DisplayName       *string           `json:"displayName,omitempty"`
+	Description       *string           `json:"description,omitempty"`
+	Source            Source            `json:"source"`
+	IsOwner           bool              `json:"isOwner"`
+	Owner             *Owner            `json:"owner,omitempty"`
+	AccessControlType AccessControlType `json:"accessControlType,omitempty"`
+	Classification    Classification    `json:"classification,omitempty"`
+}
+
+// ClassificationRule holds the PDP's defaults for the fields of a classification tier
+type ClassificationRule struct {
+	Classification Classification `json:"classification"`
+	// AlwaysRequiresConsent makes citizen-owned fields require consent even when marked public
+	AlwaysRequiresConsent bool   `json:"alwaysRequiresConsent"`
+	Description           string `json:"description"`
+}
+
+// ClassificationListResponse lists the PDP's classification tiers
+type ClassificationListResponse struct {
+	Records []ClassificationRule `json:"records"`
+}
+
 // AllowListUpdateRequest represents the request to update allow list
 type AllowListUpdateRequest struct {
 	ApplicationID string                `json:"applicationId" validate:"required"`
 	Records       []SelectedFieldRecord `json:"records" validate:"required,dive"`
 	GrantDuration GrantDurationType     `json:"grantDuration" validate:"required,grant_duration_type_enum"`
+	ConsumerID    string                `json:"consumerId,omitempty"`
+}
+
+// RenewalRequestStatus is the review status of a PDP allow list renewal request
+type RenewalRequestStatus string
+
+// PDP allow list renewal request statuses
+const (
+	RenewalRequestPending  RenewalRequestStatus = "pending"
+	RenewalRequestApproved RenewalRequestStatus = "approved"
+	RenewalRequestRejected RenewalRequestStatus = "rejected"
+)
+
+// AllowListRenewalCreateRequest asks the PDP to renew an application's existing grants once reviewed
+type AllowListRenewalCreateRequest struct {
+	ApplicationID string                `json:"applicationId" validate:"required"`
+	Records       []SelectedFieldRecord `json:"records" validate:"required,dive"`
+	GrantDuration GrantDurationType     `json:"grantDuration" validate:"required,grant_duration_type_enum"`
+	Reason        *string               `json:"reason,omitempty"`
+}
+
+// AllowListRenewalReviewRequest is the review decision on a pending PDP renewal request
+type AllowListRenewalReviewRequest struct {
+	Status        RenewalRequestStatus `json:"status" validate:"required"`
+	ReviewComment *string              `json:"reviewComment,omitempty"`
+}
+
+// AllowListRenewal is a PDP allow list renewal request
+type AllowListRenewal struct {
+	ID            string                `json:"id"`
+	ApplicationID string                `json:"applicationId"`
+	Records       []SelectedFieldRecord `json:"records"`
+	GrantDuration GrantDurationType     `json:"grantDuration"`
+	Status        RenewalRequestStatus  `json:"status"`
+	Reason        *string               `json:"reason,omitempty"`
+	ReviewComment *string               `json:"reviewComment,omitempty"`
+}
+
+// ConsumerGrant is one field an application is allow-listed for in the PDP
+type ConsumerGrant struct {
+	SchemaID      string `json:"schemaId"`
+	FieldName     string `json:"fieldName"`
+	ApplicationID string `json:"applicationId"`
+	ConsumerID    string `json:"consumerId,omitempty"`
+	ExpiresAt     string `json:"expiresAt"`
+	UpdatedAt     string `json:"updatedAt"`
+	Expired       bool   `json:"expired"`
+}
+
+// ConsumerGrantListResponse lists the PDP grants of an application or consumer
+type ConsumerGrantListResponse struct {
+	Records []ConsumerGrant `json:"records"`
+	Count   int             `json:"count"`
+}
+
+// SchemaLifecycleUpdateRequest sets the lifecycle stage of a schema's fields in the PDP. Status is
+// the schema's version; the PDP warns about fields of deprecated and sunset schemas and stops
+// serving fields of retired ones.
+type SchemaLifecycleUpdateRequest struct {
+	SchemaID string     `json:"schemaId" validate:"required"`
+	Status   Version    `json:"status" validate:"required"`
+	SunsetAt *time.Time `json:"sunsetAt,omitempty"`
+	Message  *string    `json:"message,omitempty"`
+}
+
+// SchemaLifecycleUpdateResponse reports how many fields of the schema the PDP updated
+type SchemaLifecycleUpdateResponse struct {
+	SchemaID      string  `json:"schemaId"`
+	Status        Version `json:"status"`
+	FieldsUpdated int     `json:"fieldsUpdated"`
 }
 
 // AllowListUpdateResponseRecord represents one record in the allow list update response
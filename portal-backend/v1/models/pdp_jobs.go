@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// PDPJobType identifies the PDP call a job performs
+type PDPJobType string
+
+const (
+	PDPJobTypeCreatePolicyMetadata  PDPJobType = "create_policy_metadata"
+	PDPJobTypeUpdateAllowList       PDPJobType = "update_allow_list"
+	PDPJobTypeUpdateSchemaLifecycle PDPJobType = "update_schema_lifecycle"
+)
+
+// PDPJobStatus represents the delivery state of a PDP sync job
+type PDPJobStatus string
+
+const (
+	// PDPJobStatusPending jobs are waiting for their first attempt or for a retry at next_retry_at
+	PDPJobStatusPending PDPJobStatus = "pending"
+	// PDPJobStatusCompleted jobs were accepted by the PDP
+	PDPJobStatusCompleted PDPJobStatus = "completed"
+	// PDPJobStatusDead jobs exhausted their attempts and wait for an admin to requeue them
+	PDPJobStatusDead PDPJobStatus = "dead"
+)
+
+// IsValid checks if the job status is known
+func (s PDPJobStatus) IsValid() bool {
+	switch s {
+	case PDPJobStatusPending, PDPJobStatusCompleted, PDPJobStatusDead:
+		return true
+	}
+	return false
+}
+
+// PDPJob represents the pdp_jobs table, a durable queue of calls to the PDP
+type PDPJob struct {
+	JobID       string       `gorm:"primarykey;column:job_id" json:"jobId"`
+	JobType     PDPJobType   `gorm:"column:job_type;not null" json:"jobType"`
+	ResourceID  string       `gorm:"column:resource_id;not null;index" json:"resourceId"`
+	Payload     string       `gorm:"column:payload;type:jsonb;not null" json:"-"`
+	Status      PDPJobStatus `gorm:"column:status;not null;index:idx_pdp_jobs_status_next_retry" json:"status"`
+	Attempts    int          `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	MaxAttempts int          `gorm:"column:max_attempts;not null" json:"maxAttempts"`
+	NextRetryAt time.Time    `gorm:"column:next_retry_at;not null;index:idx_pdp_jobs_status_next_retry" json:"nextRetryAt"`
+	LastError   *string      `gorm:"column:last_error" json:"lastError,omitempty"`
+	CompletedAt *time.Time   `gorm:"column:completed_at" json:"completedAt,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (PDPJob) TableName() string {
+	return "pdp_jobs"
+}
+
+// PolicyMetadataJobPayload is the payload of a create_policy_metadata job
+type PolicyMetadataJobPayload struct {
+	SchemaID   string `json:"schemaId"`
+	ProviderID string `json:"providerId"`
+	SDL        string `json:"sdl"`
+	// FieldConfigurations are applied to the policy metadata derived from the SDL
+	FieldConfigurations FieldConfigurations `json:"fieldConfigurations,omitempty"`
+}
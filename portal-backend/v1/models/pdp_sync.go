@@ -0,0 +1,63 @@
+package models
+
+// PDPSyncDriftType describes how the PDP's grants for an application differ from what the portal expects
+type PDPSyncDriftType string
+
+const (
+	// PDPSyncDriftMissing grants are selected by an approved application but absent from the PDP
+	PDPSyncDriftMissing PDPSyncDriftType = "missing"
+	// PDPSyncDriftExpired grants have expired in the PDP while the application's grants have not
+	PDPSyncDriftExpired PDPSyncDriftType = "expired"
+	// PDPSyncDriftUnexpected grants are held in the PDP for fields the application did not select
+	PDPSyncDriftUnexpected PDPSyncDriftType = "unexpected"
+)
+
+// IsRepairable reports whether re-pushing the application's grants fixes this drift. The PDP has no
+// way to revoke a grant, so unexpected grants are only reported.
+func (t PDPSyncDriftType) IsRepairable() bool {
+	return t == PDPSyncDriftMissing || t == PDPSyncDriftExpired
+}
+
+// PDPSyncDrift is one field whose PDP grant differs from what the portal expects
+type PDPSyncDrift struct {
+	Type            PDPSyncDriftType `json:"type"`
+	ApplicationID   string           `json:"applicationId"`
+	ApplicationName string           `json:"applicationName"`
+	MemberID        string           `json:"memberId"`
+	SchemaID        string           `json:"schemaId"`
+	FieldName       string           `json:"fieldName"`
+	// PDPExpiresAt is when the PDP grant expires (RFC3339); absent for missing grants
+	PDPExpiresAt *string `json:"pdpExpiresAt,omitempty"`
+}
+
+// PDPSyncStatusResponse compares the grants of approved applications with the PDP's allow lists
+type PDPSyncStatusResponse struct {
+	CheckedAt           string `json:"checkedAt"`
+	ApplicationsChecked int    `json:"applicationsChecked"`
+	GrantsExpected      int    `json:"grantsExpected"`
+	InSync              bool   `json:"inSync"`
+	// Drift lists every differing field, ordered by application, schema and field
+	Drift []PDPSyncDrift `json:"drift"`
+}
+
+// PDPSyncRepairRequest limits a repair to some applications; every drifted application is repaired
+// when ApplicationIDs is empty
+type PDPSyncRepairRequest struct {
+	ApplicationIDs []string `json:"applicationIds,omitempty"`
+}
+
+// PDPSyncRepairResult is the outcome of re-pushing the grants of one application
+type PDPSyncRepairResult struct {
+	ApplicationID  string `json:"applicationId"`
+	Success        bool   `json:"success"`
+	GrantsRepushed int    `json:"grantsRepushed"`
+	Error          string `json:"error,omitempty"`
+}
+
+// PDPSyncRepairResponse reports the per-application results of a repair. Applications are repaired
+// independently, so a failed application does not undo the others.
+type PDPSyncRepairResponse struct {
+	Results   []PDPSyncRepairResult `json:"results"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// Environment is where the exchange routes an application's requests
+type Environment string
+
+const (
+	// EnvironmentProduction requests reach the real providers and are checked against the application's grants
+	EnvironmentProduction Environment = "production"
+	// EnvironmentSandbox requests reach mock providers generated from the schemas' SDL
+	EnvironmentSandbox Environment = "sandbox"
+)
+
+// ApplicationSandbox is the sandbox environment of an application: an IDP client of its own whose
+// requests the exchange routes to mock providers, so consumers can integrate against the fields
+// they selected without touching real data
+type ApplicationSandbox struct {
+	ApplicationID    string `gorm:"primarykey;column:application_id" json:"applicationId"`
+	IdpApplicationID string `gorm:"column:idp_application_id;not null" json:"-"`
+	IdpClientID      string `gorm:"column:idp_client_id;not null;unique" json:"clientId"`
+	ProvisionedBy    string `gorm:"column:provisioned_by;not null" json:"provisionedBy"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (ApplicationSandbox) TableName() string {
+	return "application_sandboxes"
+}
+
+// SandboxProvider is the mock provider standing in for a schema in the sandbox
+type SandboxProvider struct {
+	SchemaID   string `json:"schemaId"`
+	SchemaName string `json:"schemaName"`
+	Endpoint   string `json:"endpoint"`
+}
+
+// ApplicationSandboxResponse describes the sandbox of an application. ClientSecret is only returned
+// when the sandbox is provisioned.
+type ApplicationSandboxResponse struct {
+	ApplicationID string            `json:"applicationId"`
+	ClientID      string            `json:"clientId"`
+	ClientSecret  string            `json:"clientSecret,omitempty"`
+	Providers     []SandboxProvider `json:"providers"`
+	ProvisionedBy string            `json:"provisionedBy"`
+	CreatedAt     string            `json:"createdAt"`
+}
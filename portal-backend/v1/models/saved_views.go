@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+)
+
+// SavedViewCollection is an admin collection whose filters and sort can be saved as a view and exported
+type SavedViewCollection string
+
+const (
+	SavedViewCollectionMembers                SavedViewCollection = "members"
+	SavedViewCollectionApplications           SavedViewCollection = "applications"
+	SavedViewCollectionApplicationSubmissions SavedViewCollection = "application-submissions"
+	SavedViewCollectionSchemaSubmissions      SavedViewCollection = "schema-submissions"
+)
+
+// IsValid checks if the collection is known
+func (c SavedViewCollection) IsValid() bool {
+	switch c {
+	case SavedViewCollectionMembers, SavedViewCollectionApplications, SavedViewCollectionApplicationSubmissions,
+		SavedViewCollectionSchemaSubmissions:
+		return true
+	}
+	return false
+}
+
+// HasStatus reports whether the collection's items have a status they can be filtered by
+func (c SavedViewCollection) HasStatus() bool {
+	return c == SavedViewCollectionApplicationSubmissions || c == SavedViewCollectionSchemaSubmissions
+}
+
+// ExportFormat is the file format a collection is exported in
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+// IsValid checks if the export format is known
+func (f ExportFormat) IsValid() bool {
+	return f == ExportFormatCSV || f == ExportFormatXLSX
+}
+
+// ContentType returns the media type of files in the format
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// SavedView represents the saved_views table: a named filter and sort of an admin collection, so that
+// recurring reviews can reopen or export the same list without rebuilding its query
+type SavedView struct {
+	ViewID     string              `gorm:"primarykey;column:view_id" json:"viewId"`
+	Name       string              `gorm:"column:name;not null" json:"name"`
+	Collection SavedViewCollection `gorm:"column:collection;not null;index" json:"collection"`
+	// Query holds the filters and sort of the view, as a SavedViewQuery
+	Query string `gorm:"column:query;type:jsonb;not null" json:"-"`
+	// OwnerIdpUserID is the admin who saved the view; only they can change or delete it
+	OwnerIdpUserID string `gorm:"column:owner_idp_user_id;not null;index" json:"ownerIdpUserId"`
+	// Shared views are listed for every admin, not only their owner
+	Shared bool `gorm:"column:shared;not null;default:false" json:"shared"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+// SavedViewQuery holds the filters and sort of a saved view. Page and limit are not saved: a view
+// lists from the first page, and an export includes every matching item.
+type SavedViewQuery struct {
+	// Sort is the JSON name of the field to sort by, e.g. createdAt
+	Sort   string    `json:"sort,omitempty"`
+	Order  SortOrder `json:"order,omitempty"`
+	Search string    `json:"search,omitempty"`
+	// Status filters submissions by status
+	Status []string `json:"status,omitempty"`
+	// MemberID filters applications and submissions by owner
+	MemberID *string `json:"memberId,omitempty"`
+	// IdpUserID and Email filter members
+	IdpUserID     *string    `json:"idpUserId,omitempty"`
+	Email         *string    `json:"email,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
+// ToListQuery returns the list query of the view, starting at the first page
+func (q SavedViewQuery) ToListQuery() ListQuery {
+	return ListQuery{
+		Sort:          q.Sort,
+		Order:         q.Order,
+		Search:        q.Search,
+		Status:        q.Status,
+		MemberID:      q.MemberID,
+		IdpUserID:     q.IdpUserID,
+		Email:         q.Email,
+		CreatedAfter:  q.CreatedAfter,
+		CreatedBefore: q.CreatedBefore,
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaDiff describes how the SDL of a schema submission differs from the schema it replaces
+type SchemaDiff struct {
+	PreviousSchemaID string `json:"previousSchemaId"`
+	// Breaking is set when any change can break existing consumers
+	Breaking      bool          `json:"breaking"`
+	AddedTypes    []TypeChange  `json:"addedTypes"`
+	RemovedTypes  []TypeChange  `json:"removedTypes"`
+	ChangedTypes  []TypeChange  `json:"changedTypes"`
+	AddedFields   []FieldChange `json:"addedFields"`
+	RemovedFields []FieldChange `json:"removedFields"`
+	ChangedFields []FieldChange `json:"changedFields"`
+}
+
+// TypeChange is a named type that was added, removed or changed kind
+type TypeChange struct {
+	Name string `json:"name"`
+	// Kind is the GraphQL kind of the type, e.g. OBJECT or ENUM. For changed types it is the new kind.
+	Kind     string `json:"kind"`
+	OldKind  string `json:"oldKind,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// FieldChange is a field, argument or enum value that was added, removed or changed type.
+// Path is "Type.field", "Type.field(argument)" or "Enum.VALUE".
+type FieldChange struct {
+	Path     string `json:"path"`
+	OldType  string `json:"oldType,omitempty"`
+	NewType  string `json:"newType,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// HasChanges reports whether the diff contains any change
+func (d *SchemaDiff) HasChanges() bool {
+	return len(d.AddedTypes)+len(d.RemovedTypes)+len(d.ChangedTypes)+
+		len(d.AddedFields)+len(d.RemovedFields)+len(d.ChangedFields) > 0
+}
+
+// Scan implements the sql.Scanner interface for SchemaDiff
+func (d *SchemaDiff) Scan(value interface{}) error {
+	if value == nil {
+		*d = SchemaDiff{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into SchemaDiff", value)
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// Value implements the driver.Valuer interface for SchemaDiff
+func (d SchemaDiff) Value() (driver.Value, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// GormDataType gorm common data type
+func (SchemaDiff) GormDataType() string {
+	return "jsonb"
+}
@@ -0,0 +1,26 @@
+package models
+
+// SchemaEndpointReport is the result of probing the GraphQL endpoint a schema submission declares
+type SchemaEndpointReport struct {
+	Endpoint  string `json:"endpoint"`
+	CheckedAt string `json:"checkedAt"`
+	// Reachable is set when the endpoint answered a GraphQL query
+	Reachable bool `json:"reachable"`
+	// SDLVerified is set when the endpoint allowed introspection, so its schema could be compared
+	// with the submitted SDL. Endpoints with introspection disabled are only checked for reachability.
+	SDLVerified bool `json:"sdlVerified"`
+	// Matches is set when the endpoint is reachable and serves everything the submitted SDL declares.
+	// Types and fields the endpoint serves beyond the SDL are tolerated.
+	Matches   bool  `json:"matches"`
+	LatencyMs int64 `json:"latencyMs"`
+	// Diff is how the live schema differs from the submitted SDL, when it could be introspected
+	Diff  *SchemaDiff `json:"diff,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// SchemaEndpointMismatchResponse is returned when approval is blocked because the endpoint of a
+// schema submission does not serve its SDL
+type SchemaEndpointMismatchResponse struct {
+	Error  string                `json:"error"`
+	Report *SchemaEndpointReport `json:"report"`
+}
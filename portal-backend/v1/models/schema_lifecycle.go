@@ -0,0 +1,25 @@
+package models
+
+// schemaTransitions lists the versions a schema can move to from each version. A schema is
+// deprecated with a sunset date before it is sunset or retired; only a deprecated schema can be
+// reactivated, and a retired schema stays retired.
+var schemaTransitions = map[Version][]Version{
+	ActiveVersion:     {DeprecatedVersion},
+	DeprecatedVersion: {ActiveVersion, SunsetVersion, RetiredVersion},
+	SunsetVersion:     {RetiredVersion},
+}
+
+// CanTransitionSchema reports whether a schema at version from can be moved to version to
+func CanTransitionSchema(from, to Version) bool {
+	for _, next := range schemaTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPhasedOut reports whether a schema at this version is deprecated, sunset or retired
+func (v Version) IsPhasedOut() bool {
+	return v == DeprecatedVersion || v == SunsetVersion || v == RetiredVersion
+}
@@ -1,15 +1,26 @@
 package models
 
+import "time"
+
 // Schema represents the provider_schemas table
 type Schema struct {
 	SchemaID          string  `gorm:"primarykey;column:schema_id" json:"schemaId"`
 	MemberID          string  `gorm:"column:member_id;not null" json:"memberId"`
+	OrganizationID    *string `gorm:"column:organization_id;index" json:"organizationId,omitempty"`
 	SchemaName        string  `gorm:"column:schema_name;not null" json:"schemaName"`
 	SDL               string  `gorm:"column:sdl;not null" json:"sdl"`
 	Endpoint          string  `gorm:"column:endpoint;not null" json:"endpoint"`
 	Version           string  `gorm:"column:version;not null" json:"version"`
 	SchemaDescription *string `gorm:"column:schema_description" json:"schemaDescription,omitempty"`
+	// SunsetAt is when a deprecated schema stops being supported
+	SunsetAt *time.Time `gorm:"column:sunset_at" json:"sunsetAt,omitempty"`
+	// DeprecationMessage tells consumers of a deprecated schema what to use instead
+	DeprecationMessage *string `gorm:"column:deprecation_message" json:"deprecationMessage,omitempty"`
+	// FieldConfigurations are applied to the policy metadata derived from the SDL
+	FieldConfigurations FieldConfigurations `gorm:"column:field_configurations" json:"fieldConfigurations,omitempty"`
 	BaseModel
+	SoftDeleteModel
+	RevisionModel
 
 	// Relationships
 	Member Member `gorm:"foreignKey:MemberID;references:MemberID" json:"member"`
@@ -30,8 +41,17 @@ type SchemaSubmission struct {
 	SchemaEndpoint    string  `gorm:"column:schema_endpoint;not null" json:"schemaEndpoint"`
 	Status            string  `gorm:"column:status;not null" json:"status"`
 	MemberID          string  `gorm:"column:member_id;not null" json:"memberId"`
+	OrganizationID    *string `gorm:"column:organization_id;index" json:"organizationId,omitempty"`
 	Review            *string `gorm:"column:review" json:"review,omitempty"`
+	// Diff is the SDL diff against PreviousSchema, computed when the submission is made
+	Diff *SchemaDiff `gorm:"column:diff" json:"diff,omitempty"`
+	// FieldConfigurations are suggested from the SDL and adjusted by the provider
+	FieldConfigurations FieldConfigurations `gorm:"column:field_configurations" json:"fieldConfigurations,omitempty"`
+	// ProviderType selects the field configuration template that pre-fills FieldConfigurations; it
+	// defaults to the provider type of the submitter's organization
+	ProviderType *string `gorm:"column:provider_type" json:"providerType,omitempty"`
 	BaseModel
+	SoftDeleteModel
 
 	// Relationships
 	Member         Member  `gorm:"foreignKey:MemberID;references:MemberID" json:"member"`
@@ -43,6 +63,21 @@ func (SchemaSubmission) TableName() string {
 	return "schema_submissions"
 }
 
+// ApplicationStatus is whether the orchestration engine serves an application's requests
+type ApplicationStatus string
+
+const (
+	// ApplicationStatusActive applications are served
+	ApplicationStatusActive ApplicationStatus = "active"
+	// ApplicationStatusSuspended applications are rejected until they are reactivated
+	ApplicationStatusSuspended ApplicationStatus = "suspended"
+)
+
+// IsValid checks if the application status is known
+func (s ApplicationStatus) IsValid() bool {
+	return s == ApplicationStatusActive || s == ApplicationStatusSuspended
+}
+
 // Application represents the consumer_applications table
 type Application struct {
 	ApplicationID          string               `gorm:"primarykey;column:application_id" json:"applicationId"`
@@ -50,10 +85,26 @@ type Application struct {
 	ApplicationDescription *string              `gorm:"column:application_description" json:"applicationDescription,omitempty"`
 	SelectedFields         SelectedFieldRecords `gorm:"column:selected_fields;type:jsonb;not null" json:"selectedFields"`
 	MemberID               string               `gorm:"column:member_id;not null" json:"memberId"`
+	OrganizationID         *string              `gorm:"column:organization_id;index" json:"organizationId,omitempty"`
 	Version                string               `gorm:"column:version;not null" json:"version"`
 	IdpApplicationID       *string              `gorm:"column:idp_application_id" json:"idpApplicationId,omitempty"` // Until the data migration is done this can be nullable
 	IdpClientID            *string              `gorm:"column:idp_client_id" json:"idpClientId,omitempty"`           // Until the data migration is done this can be nullable
+	// GrantExpiresAt is when the PDP grants of the selected fields expire unless they are renewed
+	GrantExpiresAt *time.Time `gorm:"column:grant_expires_at" json:"grantExpiresAt,omitempty"`
+	// GrantRenewalFlaggedAt is when the grant renewal worker opened a renewal for the expiring grant;
+	// it is cleared when the grant is renewed
+	GrantRenewalFlaggedAt *time.Time `gorm:"column:grant_renewal_flagged_at" json:"-"`
+	// DailyRecordQuota and MonthlyRecordQuota cap the records the orchestration engine returns to the
+	// application per UTC day and calendar month; nil means unlimited
+	DailyRecordQuota   *int64 `gorm:"column:daily_record_quota" json:"dailyRecordQuota,omitempty"`
+	MonthlyRecordQuota *int64 `gorm:"column:monthly_record_quota" json:"monthlyRecordQuota,omitempty"`
+	// Status is whether the orchestration engine serves the application's requests
+	Status ApplicationStatus `gorm:"column:status;not null;default:active" json:"status"`
+	// StatusReason is why the application was suspended
+	StatusReason *string `gorm:"column:status_reason" json:"statusReason,omitempty"`
 	BaseModel
+	SoftDeleteModel
+	RevisionModel
 
 	// Relationships
 	Member Member `gorm:"foreignKey:MemberID;references:MemberID" json:"member"`
@@ -72,9 +123,17 @@ type ApplicationSubmission struct {
 	ApplicationDescription *string              `gorm:"column:application_description" json:"applicationDescription,omitempty"`
 	SelectedFields         SelectedFieldRecords `gorm:"column:selected_fields;type:jsonb;not null" json:"selectedFields"`
 	MemberID               string               `gorm:"column:member_id;not null" json:"memberId"`
+	OrganizationID         *string              `gorm:"column:organization_id;index" json:"organizationId,omitempty"`
 	Status                 string               `gorm:"column:status;not null" json:"status"`
 	Review                 *string              `gorm:"column:review" json:"review,omitempty"`
+	// Renewal submissions ask to extend the grants of PreviousApplication; approving one renews the
+	// grants instead of creating a new application
+	Renewal bool `gorm:"column:renewal;not null;default:false" json:"renewal"`
+	// PDPRenewalID is the PDP renewal request a renewal submission opened; reviewing the submission
+	// approves or rejects it
+	PDPRenewalID *string `gorm:"column:pdp_renewal_id" json:"-"`
 	BaseModel
+	SoftDeleteModel
 
 	// Relationships
 	Member              Member       `gorm:"foreignKey:MemberID;references:MemberID" json:"member"`
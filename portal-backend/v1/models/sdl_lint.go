@@ -0,0 +1,25 @@
+package models
+
+// SDLLintRule identifies the check an SDL lint error comes from
+type SDLLintRule string
+
+const (
+	// SDLLintRuleInvalidSchema is reported for SDL that does not parse or is not a valid GraphQL schema
+	SDLLintRuleInvalidSchema SDLLintRule = "invalid-schema"
+	// SDLLintRuleTypeName requires type names to be PascalCase
+	SDLLintRuleTypeName SDLLintRule = "type-name-pascal-case"
+	// SDLLintRuleFieldName requires field names to be camelCase
+	SDLLintRuleFieldName SDLLintRule = "field-name-camel-case"
+	// SDLLintRuleFieldDescription requires every field to have a description
+	SDLLintRuleFieldDescription SDLLintRule = "field-description-required"
+)
+
+// SDLLintError is a problem found in a submitted SDL
+type SDLLintError struct {
+	Rule    SDLLintRule `json:"rule"`
+	Message string      `json:"message"`
+	// Path is the type or "Type.field" the error applies to, if any
+	Path   string `json:"path,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+)
+
+// AttachmentCategory is the kind of document attached to a submission
+type AttachmentCategory string
+
+const (
+	AttachmentCategoryAuthorizationLetter AttachmentCategory = "authorization_letter"
+	AttachmentCategoryDataSharingMOU      AttachmentCategory = "data_sharing_mou"
+	AttachmentCategoryOther               AttachmentCategory = "other"
+)
+
+// IsValid checks if the attachment category is known
+func (c AttachmentCategory) IsValid() bool {
+	switch c {
+	case AttachmentCategoryAuthorizationLetter, AttachmentCategoryDataSharingMOU, AttachmentCategoryOther:
+		return true
+	}
+	return false
+}
+
+// AttachmentScanStatus is the outcome of scanning an attachment for viruses
+type AttachmentScanStatus string
+
+const (
+	// AttachmentScanStatusPending attachments are waiting for the scan worker
+	AttachmentScanStatusPending AttachmentScanStatus = "pending"
+	AttachmentScanStatusClean   AttachmentScanStatus = "clean"
+	// AttachmentScanStatusInfected attachments are kept for review but can never be downloaded
+	AttachmentScanStatusInfected AttachmentScanStatus = "infected"
+	// AttachmentScanStatusFailed attachments could not be scanned after every retry
+	AttachmentScanStatusFailed AttachmentScanStatus = "failed"
+	// AttachmentScanStatusSkipped attachments were uploaded while no virus scanner was configured
+	AttachmentScanStatusSkipped AttachmentScanStatus = "skipped"
+)
+
+// IsDownloadable reports whether attachments with the status can be downloaded
+func (s AttachmentScanStatus) IsDownloadable() bool {
+	return s == AttachmentScanStatusClean || s == AttachmentScanStatusSkipped
+}
+
+// SubmissionAttachment represents the submission_attachments table: a document, such as an
+// authorization letter or a data-sharing MOU, attached to a schema submission. The file itself is
+// kept in object storage under StorageKey.
+type SubmissionAttachment struct {
+	AttachmentID string               `gorm:"primarykey;column:attachment_id" json:"attachmentId"`
+	SubmissionID string               `gorm:"column:submission_id;not null;index" json:"submissionId"`
+	Category     AttachmentCategory   `gorm:"column:category;not null" json:"category"`
+	FileName     string               `gorm:"column:file_name;not null" json:"fileName"`
+	ContentType  string               `gorm:"column:content_type;not null" json:"contentType"`
+	Size         int64                `gorm:"column:size;not null" json:"size"`
+	StorageKey   string               `gorm:"column:storage_key;not null" json:"-"`
+	ScanStatus   AttachmentScanStatus `gorm:"column:scan_status;not null;default:pending;index" json:"scanStatus"`
+	// ScanResult names the virus found, or the last error of a failed scan
+	ScanResult   *string    `gorm:"column:scan_result" json:"scanResult,omitempty"`
+	ScanAttempts int        `gorm:"column:scan_attempts;not null;default:0" json:"-"`
+	ScannedAt    *time.Time `gorm:"column:scanned_at" json:"scannedAt,omitempty"`
+	// UploadedBy is the IdP user ID of the admin or member who uploaded the file
+	UploadedBy string `gorm:"column:uploaded_by;not null" json:"uploadedBy"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (SubmissionAttachment) TableName() string {
+	return "submission_attachments"
+}
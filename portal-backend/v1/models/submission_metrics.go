@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// SubmissionMetricsResponse is a snapshot of the schema and application submission queues
+type SubmissionMetricsResponse struct {
+	GeneratedAt            time.Time              `json:"generatedAt"`
+	SchemaSubmissions      SubmissionQueueMetrics `json:"schemaSubmissions"`
+	ApplicationSubmissions SubmissionQueueMetrics `json:"applicationSubmissions"`
+	// Backlog is the number of submissions of both types waiting for a review decision
+	Backlog int64 `json:"backlog"`
+}
+
+// SubmissionQueueMetrics counts the submissions of one type
+type SubmissionQueueMetrics struct {
+	// ByStatus counts submissions per status; submissions in review are counted under their step
+	ByStatus map[string]int64 `json:"byStatus"`
+	// Backlog counts submissions waiting for a review decision: pending or in a review step
+	Backlog int64 `json:"backlog"`
+	// BacklogByAge buckets the backlog by how long ago the submissions were created, youngest first
+	BacklogByAge []SubmissionAgeBucket `json:"backlogByAge"`
+	// OldestBacklogAt is when the oldest submission in the backlog was created
+	OldestBacklogAt *time.Time `json:"oldestBacklogAt,omitempty"`
+}
+
+// SubmissionAgeBucket counts the backlog submissions created between MinAgeHours and MaxAgeHours ago
+type SubmissionAgeBucket struct {
+	Label       string `json:"label"`
+	MinAgeHours int    `json:"minAgeHours"`
+	// MaxAgeHours is absent for the last, open-ended bucket
+	MaxAgeHours *int  `json:"maxAgeHours,omitempty"`
+	Count       int64 `json:"count"`
+}
+
+// ReviewMetricsResponse describes the review decisions made over a time window
+type ReviewMetricsResponse struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	ReviewOutcomeMetrics
+	SchemaSubmissions      ReviewOutcomeMetrics `json:"schemaSubmissions"`
+	ApplicationSubmissions ReviewOutcomeMetrics `json:"applicationSubmissions"`
+	// Members are the members whose submissions were decided in the window, most decisions first
+	Members []MemberReviewMetrics `json:"members"`
+}
+
+// ReviewOutcomeMetrics counts the submissions approved or rejected over a time window
+type ReviewOutcomeMetrics struct {
+	Decided  int64 `json:"decided"`
+	Approved int64 `json:"approved"`
+	Rejected int64 `json:"rejected"`
+	// ApprovalRate is Approved / Decided, or 0 without decisions
+	ApprovalRate float64 `json:"approvalRate"`
+	// AverageReviewHours is the mean time from a submission's creation to its decision
+	AverageReviewHours float64 `json:"averageReviewHours"`
+}
+
+// MemberReviewMetrics are the review outcomes of one member's submissions
+type MemberReviewMetrics struct {
+	MemberID   string `json:"memberId"`
+	MemberName string `json:"memberName"`
+	ReviewOutcomeMetrics
+}
@@ -0,0 +1,79 @@
+package models
+
+// SubmissionType identifies which kind of submission a review record belongs to
+type SubmissionType string
+
+const (
+	SubmissionTypeSchema      SubmissionType = "schema"
+	SubmissionTypeApplication SubmissionType = "application"
+)
+
+// ReviewDecision is a reviewer's verdict on the current step of a submission
+type ReviewDecision string
+
+const (
+	ReviewDecisionApprove ReviewDecision = "approve"
+	ReviewDecisionReject  ReviewDecision = "reject"
+)
+
+// IsValid checks if the review decision is known
+func (d ReviewDecision) IsValid() bool {
+	return d == ReviewDecisionApprove || d == ReviewDecisionReject
+}
+
+// ReviewStep is a stage of the submission review workflow. While a submission is in a step its
+// status is the step name, and it moves on once RequiredApprovals distinct reviewers approve it.
+type ReviewStep struct {
+	Name              string `json:"name"`
+	RequiredApprovals int    `json:"requiredApprovals"`
+}
+
+// SubmissionReview represents the submission_reviews table, one row per reviewer decision
+type SubmissionReview struct {
+	ReviewID       string         `gorm:"primarykey;column:review_id" json:"reviewId"`
+	SubmissionType SubmissionType `gorm:"column:submission_type;not null;index:idx_submission_reviews_submission" json:"submissionType"`
+	SubmissionID   string         `gorm:"column:submission_id;not null;index:idx_submission_reviews_submission" json:"submissionId"`
+	Step           string         `gorm:"column:step;not null" json:"step"`
+	ReviewerID     string         `gorm:"column:reviewer_id;not null" json:"reviewerId"`
+	Decision       ReviewDecision `gorm:"column:decision;not null" json:"decision"`
+	Comment        *string        `gorm:"column:comment" json:"comment,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (SubmissionReview) TableName() string {
+	return "submission_reviews"
+}
+
+// SubmissionReviewer represents the submission_reviewers table, the reviewers assigned to a step.
+// A step without assigned reviewers can be reviewed by any admin.
+type SubmissionReviewer struct {
+	SubmissionType SubmissionType `gorm:"primarykey;column:submission_type" json:"submissionType"`
+	SubmissionID   string         `gorm:"primarykey;column:submission_id" json:"submissionId"`
+	Step           string         `gorm:"primarykey;column:step" json:"step"`
+	ReviewerID     string         `gorm:"primarykey;column:reviewer_id" json:"reviewerId"`
+	AssignedBy     string         `gorm:"column:assigned_by;not null" json:"assignedBy"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (SubmissionReviewer) TableName() string {
+	return "submission_reviewers"
+}
+
+// SubmissionComment represents the submission_comments table, the discussion thread of a submission
+type SubmissionComment struct {
+	CommentID      string         `gorm:"primarykey;column:comment_id" json:"commentId"`
+	SubmissionType SubmissionType `gorm:"column:submission_type;not null;index:idx_submission_comments_submission" json:"submissionType"`
+	SubmissionID   string         `gorm:"column:submission_id;not null;index:idx_submission_comments_submission" json:"submissionId"`
+	AuthorID       string         `gorm:"column:author_id;not null" json:"authorId"`
+	// Step is the status of the submission when the comment was posted
+	Step string `gorm:"column:step;not null" json:"step"`
+	Body string `gorm:"column:body;not null" json:"body"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (SubmissionComment) TableName() string {
+	return "submission_comments"
+}
@@ -15,6 +15,8 @@ import (
 type SelectedFieldRecord struct {
 	FieldName string `json:"fieldName"`
 	SchemaID  string `json:"schemaId"`
+	// Invalid is set while the schema providing the field is deleted
+	Invalid bool `json:"invalid,omitempty"`
 }
 
 // SelectedFieldRecords represents an array of SelectedFieldRecord with custom scanning
@@ -87,6 +89,19 @@ const (
 	GrantDurationTypeOneYear  GrantDurationType = "365d"
 )
 
+// ExpiresAtFrom calculates the expiry time of a grant of this duration starting at the given time,
+// the same way the PDP does
+func (g GrantDurationType) ExpiresAtFrom(start time.Time) (time.Time, error) {
+	switch g {
+	case GrantDurationTypeOneMonth:
+		return start.AddDate(0, 1, 0), nil
+	case GrantDurationTypeOneYear:
+		return start.AddDate(1, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid grant duration: %s", g)
+	}
+}
+
 // AccessControlType represents the access control type enum
 type AccessControlType string
 
@@ -139,6 +154,16 @@ func (s *Source) Value() (driver.Value, error) {
 	return string(*s), nil
 }
 
+// Classification represents the data classification tier of a field
+type Classification string
+
+const (
+	ClassificationPublic            Classification = "public"
+	ClassificationInternal          Classification = "internal"
+	ClassificationPersonal          Classification = "personal"
+	ClassificationSensitivePersonal Classification = "sensitive-personal"
+)
+
 // Owner represents the owner enum
 type Owner string
 
@@ -166,8 +191,9 @@ func (o *Owner) Value() (driver.Value, error) {
 
 // AllowListEntry represents an entry in the allow list
 type AllowListEntry struct {
-	ExpiresAt time.Time `json:"expires_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	ConsumerID string    `json:"consumer_id,omitempty"`
 }
 
 // AllowList represents the JSONB allow list as a HashMap with custom scanning
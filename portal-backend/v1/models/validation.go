@@ -0,0 +1,218 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValidationErrorCode identifies why a request field was rejected
+type ValidationErrorCode string
+
+const (
+	// ValidationErrorRequired is reported for a missing or blank field
+	ValidationErrorRequired ValidationErrorCode = "required"
+	// ValidationErrorInvalidFormat is reported for a value that does not have the expected format, e.g. an email or URL
+	ValidationErrorInvalidFormat ValidationErrorCode = "invalid_format"
+	// ValidationErrorTooShort is reported for a list with fewer items than required
+	ValidationErrorTooShort ValidationErrorCode = "too_short"
+	// ValidationErrorTooLong is reported for a value that exceeds its maximum length
+	ValidationErrorTooLong ValidationErrorCode = "too_long"
+	// ValidationErrorInvalidValue is reported for a value outside of the allowed set
+	ValidationErrorInvalidValue ValidationErrorCode = "invalid_value"
+	// ValidationErrorMalformedBody is reported when the request body is not valid JSON or a field has the wrong JSON type
+	ValidationErrorMalformedBody ValidationErrorCode = "malformed_body"
+	// ValidationErrorInvalidRequest is reported for a request that is invalid as a whole rather than in one field
+	ValidationErrorInvalidRequest ValidationErrorCode = "invalid_request"
+)
+
+// ValidationFailedCode is the top-level code of every ValidationErrorResponse
+const ValidationFailedCode = "validation_failed"
+
+// ValidationErrorDocs links to the description of the validation error codes
+const ValidationErrorDocs = "/openapi.yaml#/components/schemas/ValidationErrorCode"
+
+// ErrValidationFailed is the error a ValidationError unwraps to when it has no more specific cause
+var ErrValidationFailed = errors.New("validation failed")
+
+// FieldError describes a problem with one request field. Field is the JSON path of the field, e.g.
+// selectedFields[0].fieldName, and is empty for problems that concern the request as a whole.
+type FieldError struct {
+	Code    ValidationErrorCode `json:"code"`
+	Field   string              `json:"field,omitempty"`
+	Message string              `json:"message"`
+	Docs    string              `json:"docs"`
+}
+
+// NewFieldError creates a FieldError linked to the validation error documentation
+func NewFieldError(code ValidationErrorCode, field, message string) FieldError {
+	return FieldError{Code: code, Field: field, Message: message, Docs: ValidationErrorDocs}
+}
+
+// ValidationError is returned when a request is rejected because of one or more invalid fields.
+// Err is the sentinel callers match with errors.Is, e.g. a service's ErrIncomplete... error.
+type ValidationError struct {
+	Err    error
+	Fields []FieldError
+}
+
+// NewValidationError creates a ValidationError for a single field
+func NewValidationError(err error, code ValidationErrorCode, field, message string) *ValidationError {
+	return &ValidationError{Err: err, Fields: []FieldError{NewFieldError(code, field, message)}}
+}
+
+// Error lists the field messages, prefixed with the cause when there is one
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	if e.Err == nil {
+		return strings.Join(messages, "; ")
+	}
+	return fmt.Sprintf("%v: %s", e.Err, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the cause of the validation error
+func (e *ValidationError) Unwrap() error {
+	if e.Err == nil {
+		return ErrValidationFailed
+	}
+	return e.Err
+}
+
+// ValidationErrorResponse is the body of a 400 response. Error summarizes the problem for clients
+// that only read the message; Errors lists every rejected field.
+type ValidationErrorResponse struct {
+	Error     string       `json:"error"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors"`
+	RequestID string       `json:"requestId,omitempty"`
+}
+
+// NewValidationErrorResponse builds the 400 response body for err. Errors that are not a
+// ValidationError are reported as a single invalid_request problem.
+func NewValidationErrorResponse(err error) ValidationErrorResponse {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ValidationErrorResponse{Error: err.Error(), Code: ValidationFailedCode, Errors: validationErr.Fields}
+	}
+	return ValidationErrorResponse{
+		Error:  err.Error(),
+		Code:   ValidationFailedCode,
+		Errors: []FieldError{NewFieldError(ValidationErrorInvalidRequest, "", err.Error())},
+	}
+}
+
+// enumValue is implemented by the string enums of the API, e.g. Role and WebhookEvent
+type enumValue interface {
+	IsValid() bool
+}
+
+// Validate checks v, a pointer to a request DTO, against the validate tags of its fields and returns a
+// *ValidationError listing every invalid field. Supported rules are required, omitempty, email, min=N
+// for lists and dive for lists of structs; enum fields are also checked with their IsValid method.
+// required is not applied to bools, whose zero value is a valid answer.
+func Validate(v interface{}) error {
+	var fields []FieldError
+	validateStruct(reflect.Indirect(reflect.ValueOf(v)), "", &fields)
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: fields}
+}
+
+func validateStruct(value reflect.Value, prefix string, fields *[]FieldError) {
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		structField := valueType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		name := jsonFieldName(structField)
+		if name == "" {
+			continue
+		}
+		validateField(value.Field(i), prefix+name, structField.Tag.Get("validate"), fields)
+	}
+}
+
+func validateField(value reflect.Value, path, tag string, fields *[]FieldError) {
+	rules := map[string]string{}
+	if tag != "" {
+		for _, rule := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(rule, "=")
+			rules[name] = param
+		}
+	}
+
+	if isBlank(value) {
+		if _, required := rules["required"]; required && value.Kind() != reflect.Bool {
+			*fields = append(*fields, NewFieldError(ValidationErrorRequired, path, path+" is required"))
+		}
+		return
+	}
+
+	field := reflect.Indirect(value)
+	if _, ok := rules["email"]; ok {
+		if _, err := mail.ParseAddress(field.String()); err != nil {
+			*fields = append(*fields, NewFieldError(ValidationErrorInvalidFormat, path, path+" must be a valid email address"))
+		}
+	}
+	if param, ok := rules["min"]; ok && (field.Kind() == reflect.Slice || field.Kind() == reflect.Array) {
+		if minimum, err := strconv.Atoi(param); err == nil && field.Len() < minimum {
+			*fields = append(*fields, NewFieldError(ValidationErrorTooShort, path, fmt.Sprintf("%s must contain at least %d item(s)", path, minimum)))
+		}
+	}
+	if enum, ok := field.Interface().(enumValue); ok && !enum.IsValid() {
+		*fields = append(*fields, NewFieldError(ValidationErrorInvalidValue, path, fmt.Sprintf("%s has an invalid value %q", path, fmt.Sprint(field.Interface()))))
+	}
+
+	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+		return
+	}
+	_, dive := rules["dive"]
+	for i := 0; i < field.Len(); i++ {
+		item := reflect.Indirect(field.Index(i))
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case item.Kind() == reflect.Struct && dive:
+			validateStruct(item, itemPath+".", fields)
+		case item.Kind() != reflect.Struct:
+			if enum, ok := item.Interface().(enumValue); ok && !enum.IsValid() {
+				*fields = append(*fields, NewFieldError(ValidationErrorInvalidValue, itemPath, fmt.Sprintf("%s has an invalid value %q", itemPath, fmt.Sprint(item.Interface()))))
+			}
+		}
+	}
+}
+
+// isBlank reports whether a field was left out of the request; strings are trimmed first
+func isBlank(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// jsonFieldName returns the name a struct field has in JSON, or "" if it is not serialized
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid request", func(t *testing.T) {
+		req := &CreateMemberRequest{Name: "Jane", Email: "jane@example.com", PhoneNumber: "123"}
+		assert.NoError(t, Validate(req))
+	})
+
+	t.Run("lists every invalid field by its JSON name", func(t *testing.T) {
+		req := &CreateMemberRequest{Name: "  ", Email: "not-an-email"}
+		err := Validate(req)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, []FieldError{
+			NewFieldError(ValidationErrorRequired, "name", "name is required"),
+			NewFieldError(ValidationErrorInvalidFormat, "email", "email must be a valid email address"),
+			NewFieldError(ValidationErrorRequired, "phoneNumber", "phoneNumber is required"),
+		}, validationErr.Fields)
+	})
+
+	t.Run("checks enums, including list items", func(t *testing.T) {
+		err := Validate(&CreateWebhookRequest{URL: "https://example.com", Events: []WebhookEvent{WebhookEventSchemaDeprecated, "unknown"}})
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Len(t, validationErr.Fields, 1)
+		assert.Equal(t, ValidationErrorInvalidValue, validationErr.Fields[0].Code)
+		assert.Equal(t, "events[1]", validationErr.Fields[0].Field)
+
+		err = Validate(&SetOrganizationMemberRequest{Role: "owner"})
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "role", validationErr.Fields[0].Field)
+	})
+
+	t.Run("dives into lists of structs", func(t *testing.T) {
+		req := &PolicyMetadataCreateRequest{
+			SchemaID: "sch_1",
+			Records:  []PolicyMetadataCreateRequestRecord{{FieldName: "person.name", Source: "primary"}, {Source: "primary"}},
+		}
+		var validationErr *ValidationError
+		require.True(t, errors.As(Validate(req), &validationErr))
+		require.Len(t, validationErr.Fields, 1)
+		assert.Equal(t, "records[1].fieldName", validationErr.Fields[0].Field)
+	})
+
+	t.Run("min applies to lists", func(t *testing.T) {
+		var validationErr *ValidationError
+		require.True(t, errors.As(Validate(&CreateApplicationRequest{ApplicationName: "App", MemberID: "mem_1"}), &validationErr))
+		assert.Equal(t, []FieldError{NewFieldError(ValidationErrorRequired, "selectedFields", "selectedFields is required")}, validationErr.Fields)
+	})
+}
+
+func TestNewValidationErrorResponse(t *testing.T) {
+	cause := errors.New("submission is incomplete")
+	err := NewValidationError(cause, ValidationErrorRequired, "sdl", "sdl is required")
+	assert.ErrorIs(t, err, cause)
+
+	response := NewValidationErrorResponse(err)
+	assert.Equal(t, ValidationFailedCode, response.Code)
+	assert.Equal(t, "submission is incomplete: sdl is required", response.Error)
+	assert.Equal(t, []FieldError{{Code: ValidationErrorRequired, Field: "sdl", Message: "sdl is required", Docs: ValidationErrorDocs}}, response.Errors)
+
+	// Other errors are reported as a problem with the request as a whole
+	response = NewValidationErrorResponse(errors.New("member not found"))
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, ValidationErrorInvalidRequest, response.Errors[0].Code)
+	assert.Empty(t, response.Errors[0].Field)
+	assert.Equal(t, "member not found", response.Errors[0].Message)
+}
@@ -0,0 +1,126 @@
+package models
+
+import "time"
+
+// WebhookEvent identifies an event that can be delivered to an application's webhooks
+type WebhookEvent string
+
+const (
+	// WebhookEventSubmissionStatusChanged is sent when a submission that changes the application moves to another status
+	WebhookEventSubmissionStatusChanged WebhookEvent = "submission_status_changed"
+	// WebhookEventSchemaDeprecated is sent when a schema providing one of the application's selected fields is deprecated, sunset or retired
+	WebhookEventSchemaDeprecated WebhookEvent = "schema_deprecated"
+	// WebhookEventCredentialExpiring is sent when a client credential of the application is about to expire
+	WebhookEventCredentialExpiring WebhookEvent = "credential_expiring"
+)
+
+// WebhookEvents lists every webhook event in display order
+var WebhookEvents = []WebhookEvent{
+	WebhookEventSubmissionStatusChanged,
+	WebhookEventSchemaDeprecated,
+	WebhookEventCredentialExpiring,
+}
+
+// IsValid checks if the webhook event is known
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventSubmissionStatusChanged, WebhookEventSchemaDeprecated, WebhookEventCredentialExpiring:
+		return true
+	}
+	return false
+}
+
+// WebhookDeliveryStatus represents the delivery state of a webhook event
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending deliveries are waiting for their first attempt or for a retry at next_retry_at
+	WebhookDeliveryStatusPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryStatusDelivered deliveries were answered with a 2xx status
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryStatusDead deliveries exhausted their attempts and are not retried
+	WebhookDeliveryStatusDead WebhookDeliveryStatus = "dead"
+)
+
+// IsValid checks if the delivery status is known
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusDead:
+		return true
+	}
+	return false
+}
+
+// WebhookSubscription represents the webhook_subscriptions table. Events are POSTed to URL with an
+// HMAC-SHA256 signature made with Secret, which is only returned when the subscription is created.
+type WebhookSubscription struct {
+	WebhookID     string `gorm:"primarykey;column:webhook_id" json:"webhookId"`
+	ApplicationID string `gorm:"column:application_id;not null;index" json:"applicationId"`
+	URL           string `gorm:"column:url;not null" json:"url"`
+	Secret        string `gorm:"column:secret;not null" json:"-"`
+	// Events is the JSON array of the WebhookEvent values the subscription receives
+	Events    string `gorm:"column:events;type:jsonb;not null" json:"-"`
+	CreatedBy string `gorm:"column:created_by;not null" json:"createdBy"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery represents the webhook_deliveries table, the durable queue and log of events
+// sent to a webhook subscription
+type WebhookDelivery struct {
+	DeliveryID  string                `gorm:"primarykey;column:delivery_id" json:"deliveryId"`
+	WebhookID   string                `gorm:"column:webhook_id;not null;index" json:"webhookId"`
+	Event       WebhookEvent          `gorm:"column:event;not null" json:"event"`
+	Payload     string                `gorm:"column:payload;type:jsonb;not null" json:"-"`
+	Status      WebhookDeliveryStatus `gorm:"column:status;not null;index:idx_webhook_deliveries_status_next_retry" json:"status"`
+	Attempts    int                   `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	MaxAttempts int                   `gorm:"column:max_attempts;not null" json:"maxAttempts"`
+	NextRetryAt time.Time             `gorm:"column:next_retry_at;not null;index:idx_webhook_deliveries_status_next_retry" json:"nextRetryAt"`
+	// ResponseStatus is the HTTP status of the last attempt; nil if the endpoint could not be reached
+	ResponseStatus *int       `gorm:"column:response_status" json:"responseStatus,omitempty"`
+	LastError      *string    `gorm:"column:last_error" json:"lastError,omitempty"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at" json:"deliveredAt,omitempty"`
+	BaseModel
+}
+
+// TableName sets the table name for GORM
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookPayload is the JSON body POSTed to a webhook
+type WebhookPayload struct {
+	DeliveryID    string       `json:"deliveryId"`
+	Event         WebhookEvent `json:"event"`
+	ApplicationID string       `json:"applicationId"`
+	OccurredAt    string       `json:"occurredAt"`
+	Data          interface{}  `json:"data"`
+}
+
+// SubmissionStatusChangedWebhookData is the data of a submission_status_changed webhook event
+type SubmissionStatusChangedWebhookData struct {
+	SubmissionID   string `json:"submissionId"`
+	PreviousStatus string `json:"previousStatus"`
+	Status         string `json:"status"`
+}
+
+// SchemaDeprecatedWebhookData is the data of a schema_deprecated webhook event, sent when the schema
+// is deprecated, sunset or retired; Fields are the application's selected fields that the schema provides
+type SchemaDeprecatedWebhookData struct {
+	SchemaID           string   `json:"schemaId"`
+	SchemaName         string   `json:"schemaName"`
+	Version            string   `json:"version"`
+	SunsetAt           *string  `json:"sunsetAt,omitempty"`
+	DeprecationMessage *string  `json:"deprecationMessage,omitempty"`
+	Fields             []string `json:"fields"`
+}
+
+// CredentialExpiringWebhookData is the data of a credential_expiring webhook event
+type CredentialExpiringWebhookData struct {
+	CredentialID string `json:"credentialId"`
+	ExpiresAt    string `json:"expiresAt"`
+}
@@ -0,0 +1,576 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+	"gorm.io/gorm"
+)
+
+// MaxAdminGraphDepth bounds how deeply an admin GraphQL query may nest relations
+const MaxAdminGraphDepth = 6
+
+// AdminGraphSDL is the schema of the admin GraphQL API. Lists take a limit (default and maximum
+// models.MaxPageLimit) and an offset, and are ordered newest first.
+const AdminGraphSDL = `
+type Query {
+  members(search: String, limit: Int, offset: Int): [Member!]!
+  member(memberId: ID!): Member
+  schemas(memberId: ID, limit: Int, offset: Int): [Schema!]!
+  schema(schemaId: ID!): Schema
+  schemaSubmissions(memberId: ID, status: [String!], limit: Int, offset: Int): [SchemaSubmission!]!
+  schemaSubmission(submissionId: ID!): SchemaSubmission
+  applications(memberId: ID, limit: Int, offset: Int): [Application!]!
+  application(applicationId: ID!): Application
+  applicationSubmissions(memberId: ID, status: [String!], limit: Int, offset: Int): [ApplicationSubmission!]!
+  applicationSubmission(submissionId: ID!): ApplicationSubmission
+}
+
+type Member {
+  memberId: ID!
+  name: String!
+  email: String!
+  phoneNumber: String!
+  idpUserId: String!
+  organizationId: ID
+  organizationRole: String
+  createdAt: String!
+  updatedAt: String!
+  revision: Int!
+  schemas(limit: Int, offset: Int): [Schema!]!
+  schemaSubmissions(status: [String!], limit: Int, offset: Int): [SchemaSubmission!]!
+  applications(limit: Int, offset: Int): [Application!]!
+  applicationSubmissions(status: [String!], limit: Int, offset: Int): [ApplicationSubmission!]!
+}
+
+type Schema {
+  schemaId: ID!
+  schemaName: String!
+  schemaDescription: String
+  sdl: String!
+  endpoint: String!
+  version: String!
+  memberId: ID!
+  organizationId: ID
+  createdAt: String!
+  updatedAt: String!
+  revision: Int!
+  member: Member
+  "Submissions proposing a new version of this schema"
+  submissions(status: [String!], limit: Int, offset: Int): [SchemaSubmission!]!
+}
+
+type SchemaSubmission {
+  submissionId: ID!
+  previousSchemaId: ID
+  schemaName: String!
+  schemaDescription: String
+  sdl: String!
+  schemaEndpoint: String!
+  status: String!
+  review: String
+  memberId: ID!
+  organizationId: ID
+  createdAt: String!
+  updatedAt: String!
+  member: Member
+  previousSchema: Schema
+}
+
+type SelectedField {
+  fieldName: String!
+  schemaId: ID!
+  invalid: Boolean!
+}
+
+type Application {
+  applicationId: ID!
+  applicationName: String!
+  applicationDescription: String
+  selectedFields: [SelectedField!]!
+  memberId: ID!
+  organizationId: ID
+  version: String!
+  idpClientId: String
+  createdAt: String!
+  updatedAt: String!
+  revision: Int!
+  member: Member
+  "Submissions proposing a new version of this application"
+  submissions(status: [String!], limit: Int, offset: Int): [ApplicationSubmission!]!
+}
+
+type ApplicationSubmission {
+  submissionId: ID!
+  previousApplicationId: ID
+  applicationName: String!
+  applicationDescription: String
+  selectedFields: [SelectedField!]!
+  status: String!
+  review: String
+  memberId: ID!
+  organizationId: ID
+  createdAt: String!
+  updatedAt: String!
+  member: Member
+  previousApplication: Application
+}
+`
+
+var adminGraphSchema = gqlparser.MustLoadSchema(&ast.Source{Name: "admin", Input: AdminGraphSDL})
+
+// graphResolver resolves a field of source, the parent object (nil for Query fields)
+type graphResolver func(e *graphExecution, source interface{}, args map[string]interface{}) (interface{}, error)
+
+// adminGraphResolvers resolves the fields that are not read straight from the model by their JSON name
+var adminGraphResolvers = map[string]map[string]graphResolver{
+	"Query": {
+		"members": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.Member](e, args, func(query *gorm.DB) *gorm.DB {
+				if search, _ := args["search"].(string); search != "" {
+					query = query.Where("LOWER(name) LIKE ? ESCAPE '\\'", "%"+likeEscaper.Replace(strings.ToLower(search))+"%")
+				}
+				return query
+			})
+		},
+		"member": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return e.member(args["memberId"].(string))
+		},
+		"schemas": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.Schema](e, args, filterByArg(args, "memberId", "member_id"))
+		},
+		"schema": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return first[models.Schema](e, "schema_id = ?", args["schemaId"])
+		},
+		"schemaSubmissions": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.SchemaSubmission](e, args, filterByArg(args, "memberId", "member_id"))
+		},
+		"schemaSubmission": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return first[models.SchemaSubmission](e, "submission_id = ?", args["submissionId"])
+		},
+		"applications": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.Application](e, args, filterByArg(args, "memberId", "member_id"))
+		},
+		"application": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return first[models.Application](e, "application_id = ?", args["applicationId"])
+		},
+		"applicationSubmissions": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.ApplicationSubmission](e, args, filterByArg(args, "memberId", "member_id"))
+		},
+		"applicationSubmission": func(e *graphExecution, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return first[models.ApplicationSubmission](e, "submission_id = ?", args["submissionId"])
+		},
+	},
+	"Member": {
+		"schemas": func(e *graphExecution, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.Schema](e, args, whereColumn("member_id", source.(models.Member).MemberID))
+		},
+		"schemaSubmissions": func(e *graphExecution, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.SchemaSubmission](e, args, whereColumn("member_id", source.(models.Member).MemberID))
+		},
+		"applications": func(e *graphExecution, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.Application](e, args, whereColumn("member_id", source.(models.Member).MemberID))
+		},
+		"applicationSubmissions": func(e *graphExecution, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.ApplicationSubmission](e, args, whereColumn("member_id", source.(models.Member).MemberID))
+		},
+	},
+	"Schema": {
+		"member": func(e *graphExecution, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return e.member(source.(models.Schema).MemberID)
+		},
+		"submissions": func(e *graphExecution, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.SchemaSubmission](e, args, whereColumn("previous_schema_id", source.(models.Schema).SchemaID))
+		},
+	},
+	"SchemaSubmission": {
+		"member": func(e *graphExecution, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return e.member(source.(models.SchemaSubmission).MemberID)
+		},
+		"previousSchema": func(e *graphExecution, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			if id := source.(models.SchemaSubmission).PreviousSchemaID; id != nil {
+				return first[models.Schema](e, "schema_id = ?", *id)
+			}
+			return nil, nil
+		},
+	},
+	"Application": {
+		"member": func(e *graphExecution, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return e.member(source.(models.Application).MemberID)
+		},
+		"submissions": func(e *graphExecution, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return listOf[models.ApplicationSubmission](e, args, whereColumn("previous_application_id", source.(models.Application).ApplicationID))
+		},
+	},
+	"ApplicationSubmission": {
+		"member": func(e *graphExecution, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return e.member(source.(models.ApplicationSubmission).MemberID)
+		},
+		"previousApplication": func(e *graphExecution, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			if id := source.(models.ApplicationSubmission).PreviousApplicationID; id != nil {
+				return first[models.Application](e, "application_id = ?", *id)
+			}
+			return nil, nil
+		},
+	},
+}
+
+// AdminGraphService answers GraphQL queries over members, schemas, applications and their
+// submissions, so that the admin portal can fetch a composed view in one request
+type AdminGraphService struct {
+	db *gorm.DB
+}
+
+// NewAdminGraphService creates a new admin GraphQL service
+func NewAdminGraphService(db *gorm.DB) *AdminGraphService {
+	return &AdminGraphService{db: db}
+}
+
+// Execute validates and runs a GraphQL query. As in any GraphQL response, failures are reported in
+// the response's errors; a field that fails to resolve is null and does not fail the rest.
+func (s *AdminGraphService) Execute(ctx context.Context, req *models.GraphQLRequest) *models.GraphQLResponse {
+	doc, errs := gqlparser.LoadQueryWithRules(adminGraphSchema, req.Query, nil)
+	if len(errs) > 0 {
+		return &models.GraphQLResponse{Errors: errs}
+	}
+	operation := doc.Operations.ForName(req.OperationName)
+	if operation == nil {
+		return &models.GraphQLResponse{Errors: gqlerror.List{gqlerror.Errorf("operation %q not found", req.OperationName)}}
+	}
+	if operation.Operation != ast.Query {
+		return &models.GraphQLResponse{Errors: gqlerror.List{gqlerror.Errorf("only queries are supported")}}
+	}
+	variables, err := validator.VariableValues(adminGraphSchema, operation, req.Variables)
+	if err != nil {
+		return &models.GraphQLResponse{Errors: gqlerror.List{toGraphError(err)}}
+	}
+
+	e := &graphExecution{db: s.db.WithContext(ctx), variables: variables, members: make(map[string]*models.Member)}
+	fields := e.collectFields("Query", operation.SelectionSet)
+	if depth := selectionDepth(fields, e); depth > MaxAdminGraphDepth {
+		return &models.GraphQLResponse{Errors: gqlerror.List{gqlerror.Errorf("query depth %d exceeds the maximum of %d", depth, MaxAdminGraphDepth)}}
+	}
+	data := e.executeFields("Query", nil, fields, nil)
+	return &models.GraphQLResponse{Data: data, Errors: e.errors}
+}
+
+// graphExecution holds the state of one query
+type graphExecution struct {
+	db        *gorm.DB
+	variables map[string]interface{}
+	errors    gqlerror.List
+	// members caches member lookups, since many objects of a view usually share a few members
+	members map[string]*models.Member
+}
+
+// graphField is a response key with the fields selected under it, which are merged
+type graphField struct {
+	alias  string
+	fields []*ast.Field
+}
+
+// collectFields flattens fragments and applies @skip/@include, grouping the fields by response key
+func (e *graphExecution) collectFields(typeName string, set ast.SelectionSet) []*graphField {
+	var collected []*graphField
+	byAlias := make(map[string]*graphField)
+	var collect func(set ast.SelectionSet)
+	collect = func(set ast.SelectionSet) {
+		for _, selection := range set {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				if !e.included(selection.Directives) {
+					continue
+				}
+				field, ok := byAlias[selection.Alias]
+				if !ok {
+					field = &graphField{alias: selection.Alias}
+					byAlias[selection.Alias] = field
+					collected = append(collected, field)
+				}
+				field.fields = append(field.fields, selection)
+			case *ast.InlineFragment:
+				if e.included(selection.Directives) && (selection.TypeCondition == "" || selection.TypeCondition == typeName) {
+					collect(selection.SelectionSet)
+				}
+			case *ast.FragmentSpread:
+				if e.included(selection.Directives) && selection.Definition.TypeCondition == typeName {
+					collect(selection.Definition.SelectionSet)
+				}
+			}
+		}
+	}
+	collect(set)
+	return collected
+}
+
+// included evaluates the @skip and @include directives of a selection
+func (e *graphExecution) included(directives ast.DirectiveList) bool {
+	if skip := directives.ForName("skip"); skip != nil {
+		if value, _ := skip.ArgumentMap(e.variables)["if"].(bool); value {
+			return false
+		}
+	}
+	if include := directives.ForName("include"); include != nil {
+		if value, _ := include.ArgumentMap(e.variables)["if"].(bool); !value {
+			return false
+		}
+	}
+	return true
+}
+
+// subselection merges the selection sets of the fields under one response key
+func (f *graphField) subselection() ast.SelectionSet {
+	var set ast.SelectionSet
+	for _, field := range f.fields {
+		set = append(set, field.SelectionSet...)
+	}
+	return set
+}
+
+// selectionDepth returns how many levels of objects fields select
+func selectionDepth(fields []*graphField, e *graphExecution) int {
+	depth := 0
+	for _, field := range fields {
+		set := field.subselection()
+		if len(set) == 0 {
+			continue
+		}
+		if d := 1 + selectionDepth(e.collectFields(field.fields[0].Definition.Type.Name(), set), e); d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// executeFields resolves fields on source, an object of typeName
+func (e *graphExecution) executeFields(typeName string, source interface{}, fields []*graphField, path ast.Path) *graphObject {
+	object := &graphObject{values: make(map[string]interface{}, len(fields))}
+	for _, field := range fields {
+		definition := field.fields[0]
+		fieldPath := append(append(ast.Path{}, path...), ast.PathName(field.alias))
+		if definition.Name == "__typename" {
+			object.set(field.alias, typeName)
+			continue
+		}
+
+		var value interface{}
+		var err error
+		if resolve, ok := adminGraphResolvers[typeName][definition.Name]; ok {
+			value, err = resolve(e, source, definition.ArgumentMap(e.variables))
+		} else if strings.HasPrefix(definition.Name, "__") {
+			err = errors.New("introspection is not supported; the schema is served by GET /api/v1/admin/graphql")
+		} else {
+			value = jsonFieldValue(source, definition.Name)
+		}
+		if err != nil {
+			e.errors = append(e.errors, &gqlerror.Error{
+				Message:   err.Error(),
+				Path:      fieldPath,
+				Locations: []gqlerror.Location{{Line: definition.Position.Line, Column: definition.Position.Column}},
+			})
+			object.set(field.alias, nil)
+			continue
+		}
+		object.set(field.alias, e.complete(definition.Definition.Type.Name(), value, field, fieldPath))
+	}
+	return object
+}
+
+// complete resolves the selection of an object or list value; scalars are returned as they are
+func (e *graphExecution) complete(typeName string, value interface{}, field *graphField, path ast.Path) interface{} {
+	set := field.subselection()
+	if len(set) == 0 || value == nil {
+		return value
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return e.complete(typeName, v.Elem().Interface(), field, path)
+	case reflect.Slice:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = e.complete(typeName, v.Index(i).Interface(), field, append(append(ast.Path{}, path...), ast.PathIndex(i)))
+		}
+		return items
+	}
+	return e.executeFields(typeName, value, e.collectFields(typeName, set), path)
+}
+
+// listOf loads a page of the rows selected by filter, newest first
+func listOf[T any](e *graphExecution, args map[string]interface{}, filter func(*gorm.DB) *gorm.DB) ([]T, error) {
+	limit := models.MaxPageLimit
+	if value, ok := intArg(args, "limit"); ok && value > 0 && value < limit {
+		limit = value
+	}
+	offset, _ := intArg(args, "offset")
+	if offset < 0 {
+		offset = 0
+	}
+	var rows []T
+	query := filter(e.db.Model(&rows))
+	if statuses, ok := args["status"].([]interface{}); ok && len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load %T: %w", rows, err)
+	}
+	return rows, nil
+}
+
+// member loads a member once per query
+func (e *graphExecution) member(memberID string) (interface{}, error) {
+	if member, ok := e.members[memberID]; ok {
+		return member, nil
+	}
+	member, err := first[models.Member](e, "member_id = ?", memberID)
+	if err != nil {
+		return nil, err
+	}
+	e.members[memberID] = member
+	return member, nil
+}
+
+// first loads the row matching the condition, or nil if there is none
+func first[T any](e *graphExecution, query string, args ...interface{}) (*T, error) {
+	var row T
+	if err := e.db.Where(query, args...).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load %T: %w", row, err)
+	}
+	return &row, nil
+}
+
+// whereColumn filters rows on a column value
+func whereColumn(column string, value interface{}) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		return query.Where(column+" = ?", value)
+	}
+}
+
+// filterByArg filters rows on a column when the argument is given
+func filterByArg(args map[string]interface{}, name, column string) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if value, ok := args[name].(string); ok && value != "" {
+			return query.Where(column+" = ?", value)
+		}
+		return query
+	}
+}
+
+// intArg reads an Int argument, which is an int64 as a literal or a decoded JSON number as a variable
+func intArg(args map[string]interface{}, name string) (int, bool) {
+	switch value := args[name].(type) {
+	case int64:
+		return int(value), true
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	case json.Number:
+		n, err := value.Int64()
+		return int(n), err == nil
+	}
+	return 0, false
+}
+
+// jsonFieldValue reads the field of a model struct with the given JSON name, looking into embedded
+// structs. Times are formatted as RFC3339 like the REST API's responses.
+func jsonFieldValue(source interface{}, name string) interface{} {
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		fieldType := v.Type().Field(i)
+		if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct {
+			if value := jsonFieldValue(v.Field(i).Interface(), name); value != nil {
+				return value
+			}
+			continue
+		}
+		tag, _, _ := strings.Cut(fieldType.Tag.Get("json"), ",")
+		if tag != name {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				return nil
+			}
+			field = field.Elem()
+		}
+		if t, ok := field.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339)
+		}
+		return field.Interface()
+	}
+	return nil
+}
+
+// toGraphError converts an error to a GraphQL error
+func toGraphError(err error) *gqlerror.Error {
+	var graphErr *gqlerror.Error
+	if errors.As(err, &graphErr) {
+		return graphErr
+	}
+	return gqlerror.Wrap(err)
+}
+
+// graphObject is a result object whose fields marshal in the order they were selected
+type graphObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *graphObject) set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON writes the object's fields in selection order
+func (o *graphObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
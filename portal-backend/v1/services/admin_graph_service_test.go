@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminGraphService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	require.NoError(t, db.Model(&models.SchemaSubmission{}).Where("submission_id = ?", "sub_schema").
+		Update("previous_schema_id", "sch_1").Error)
+	ctx := context.Background()
+
+	service := NewAdminGraphService(db)
+	execute := func(t *testing.T, req *models.GraphQLRequest) (*models.GraphQLResponse, string) {
+		t.Helper()
+		response := service.Execute(ctx, req)
+		body, err := json.Marshal(response)
+		require.NoError(t, err)
+		return response, string(body)
+	}
+
+	t.Run("Composed view", func(t *testing.T) {
+		response, body := execute(t, &models.GraphQLRequest{
+			Query: `query Provider($id: ID!) {
+				member(memberId: $id) {
+					name
+					owned: schemas { schemaId ...Submissions }
+					applications { applicationId }
+				}
+			}
+			fragment Submissions on Schema { submissions { submissionId status member { memberId } } }`,
+			Variables: map[string]interface{}{"id": "mem_provider"},
+		})
+		require.Empty(t, response.Errors)
+		assert.JSONEq(t, `{"data": {"member": {
+			"name": "Provider",
+			"owned": [{"schemaId": "sch_1", "submissions": [{"submissionId": "sub_schema", "status": "pending", "member": {"memberId": "mem_provider"}}]}],
+			"applications": []
+		}}}`, body)
+		assert.Regexp(t, `^\{"data":\{"member":\{"name":.*"owned":.*"applications"`, body, "fields keep their selection order")
+	})
+
+	t.Run("Lists and nested objects", func(t *testing.T) {
+		response, body := execute(t, &models.GraphQLRequest{
+			Query: `{
+				applications(memberId: "mem_consumer") { applicationName selectedFields { fieldName schemaId } member { email } }
+				applicationSubmissions(status: ["approved"]) { submissionId }
+				missing: schema(schemaId: "sch_missing") { schemaId }
+				members(search: "cons", limit: 1) { memberId __typename }
+			}`,
+		})
+		require.Empty(t, response.Errors)
+		assert.JSONEq(t, `{"data": {
+			"applications": [{"applicationName": "App", "selectedFields": [{"fieldName": "person.name", "schemaId": "sch_1"}, {"fieldName": "vehicle.plate", "schemaId": "sch_2"}], "member": {"email": "consumer@example.com"}}],
+			"applicationSubmissions": [],
+			"missing": null,
+			"members": [{"memberId": "mem_consumer", "__typename": "Member"}]
+		}}`, body)
+	})
+
+	t.Run("Invalid queries", func(t *testing.T) {
+		response, body := execute(t, &models.GraphQLRequest{Query: `{ members { password } }`})
+		require.Len(t, response.Errors, 1)
+		assert.NotContains(t, body, `"data"`)
+
+		response, _ = execute(t, &models.GraphQLRequest{Query: `query($id: ID!) { member(memberId: $id) { name } }`})
+		require.Len(t, response.Errors, 1, "required variables must be given")
+
+		response, _ = execute(t, &models.GraphQLRequest{
+			Query: `{ members { schemas { member { schemas { member { schemas { member { name } } } } } } } }`,
+		})
+		require.Len(t, response.Errors, 1)
+		assert.Contains(t, response.Errors[0].Message, "exceeds the maximum")
+	})
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// secretHintLength is the number of trailing secret characters kept to help consumers tell secrets apart
+const secretHintLength = 4
+
+var (
+	// ErrApplicationNotProvisioned is returned when an application has no IDP application to issue credentials for
+	ErrApplicationNotProvisioned = errors.New("application is not registered with the identity provider")
+	// ErrActiveCredentialExists is returned when generating a credential while another one is active
+	ErrActiveCredentialExists = errors.New("application already has an active credential; rotate or revoke it instead")
+	// ErrCredentialNotActive is returned when rotating or revoking a credential that was already rotated or revoked
+	ErrCredentialNotActive = errors.New("credential is not active")
+)
+
+// ApplicationCredentialService manages the client credentials of applications. The IDP holds a single
+// client secret per application; this service records each secret it issues so that members can see
+// the history of their credentials without the secret ever being stored.
+type ApplicationCredentialService struct {
+	db  *gorm.DB
+	idp idp.IdentityProviderAPI
+	// lifetime is how long a credential may be used before it must be rotated; 0 means credentials do not expire
+	lifetime time.Duration
+}
+
+// NewApplicationCredentialService creates a new application credential service
+func NewApplicationCredentialService(db *gorm.DB, idp idp.IdentityProviderAPI, lifetime time.Duration) *ApplicationCredentialService {
+	return &ApplicationCredentialService{db: db, idp: idp, lifetime: lifetime}
+}
+
+// GenerateCredential issues a new client secret for an application that has no active credential.
+// A secret issued outside this service, e.g. when the application was created, is invalidated.
+func (s *ApplicationCredentialService) GenerateCredential(ctx context.Context, applicationID, issuedBy string) (*models.ApplicationCredentialResponse, error) {
+	application, err := s.getProvisionedApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var activeCount int64
+	if err := s.db.WithContext(ctx).Model(&models.ApplicationCredential{}).
+		Where("application_id = ? AND status = ?", applicationID, models.ApplicationCredentialStatusActive).
+		Count(&activeCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to check active credentials: %w", err)
+	}
+	if activeCount > 0 {
+		return nil, ErrActiveCredentialExists
+	}
+
+	oidcInfo, err := s.idp.RegenerateApplicationSecret(ctx, *application.IdpApplicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client secret: %w", err)
+	}
+
+	credential := s.newApplicationCredential(applicationID, oidcInfo, issuedBy)
+	if err := s.db.WithContext(ctx).Create(&credential).Error; err != nil {
+		return nil, fmt.Errorf("failed to record credential: %w", err)
+	}
+
+	slog.Info("Application credential generated", "applicationID", applicationID, "credentialID", credential.CredentialID)
+	response := applicationCredentialResponseOf(credential)
+	response.ClientSecret = oidcInfo.ClientSecret
+	return &response, nil
+}
+
+// ListCredentials returns the credentials issued for an application, newest first. Secrets are never returned.
+func (s *ApplicationCredentialService) ListCredentials(ctx context.Context, applicationID string) ([]models.ApplicationCredentialResponse, error) {
+	if err := s.db.WithContext(ctx).Select("application_id").First(&models.Application{}, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	var credentials []models.ApplicationCredential
+	if err := s.db.WithContext(ctx).Where("application_id = ?", applicationID).
+		Order("created_at DESC").Find(&credentials).Error; err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	responses := make([]models.ApplicationCredentialResponse, 0, len(credentials))
+	for _, credential := range credentials {
+		responses = append(responses, applicationCredentialResponseOf(credential))
+	}
+	return responses, nil
+}
+
+// RotateCredential replaces an active credential with a new client secret. The previous secret stops
+// working as soon as the IDP issues the new one.
+func (s *ApplicationCredentialService) RotateCredential(ctx context.Context, applicationID, credentialID, rotatedBy string) (*models.ApplicationCredentialResponse, error) {
+	application, err := s.getProvisionedApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getActiveCredential(ctx, applicationID, credentialID); err != nil {
+		return nil, err
+	}
+
+	oidcInfo, err := s.idp.RegenerateApplicationSecret(ctx, *application.IdpApplicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate client secret: %w", err)
+	}
+
+	credential := s.newApplicationCredential(applicationID, oidcInfo, rotatedBy)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ApplicationCredential{}).
+			Where("credential_id = ? AND status = ?", credentialID, models.ApplicationCredentialStatusActive).
+			Updates(map[string]interface{}{
+				"status":     models.ApplicationCredentialStatusRotated,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		return tx.Create(&credential).Error
+	})
+	if err != nil {
+		// The IDP already issued the new secret, so the consumer must still receive it
+		slog.Error("Failed to record rotated credential",
+			"applicationID", applicationID,
+			"credentialID", credentialID,
+			"error", err)
+		return nil, fmt.Errorf("client secret was rotated but could not be recorded: %w", err)
+	}
+
+	slog.Info("Application credential rotated",
+		"applicationID", applicationID,
+		"previousCredentialID", credentialID,
+		"credentialID", credential.CredentialID)
+	response := applicationCredentialResponseOf(credential)
+	response.ClientSecret = oidcInfo.ClientSecret
+	return &response, nil
+}
+
+// RevokeCredential revokes an active credential so that no new tokens can be issued with it
+func (s *ApplicationCredentialService) RevokeCredential(ctx context.Context, applicationID, credentialID, revokedBy string) (*models.ApplicationCredentialResponse, error) {
+	application, err := s.getProvisionedApplication(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.getActiveCredential(ctx, applicationID, credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.idp.RevokeApplicationCredentials(ctx, *application.IdpApplicationID); err != nil {
+		return nil, fmt.Errorf("failed to revoke client credentials: %w", err)
+	}
+
+	now := time.Now()
+	credential.Status = models.ApplicationCredentialStatusRevoked
+	credential.RevokedAt = &now
+	credential.RevokedBy = &revokedBy
+	credential.UpdatedAt = now
+	if err := s.db.WithContext(ctx).Model(&models.ApplicationCredential{}).
+		Where("credential_id = ?", credentialID).
+		Updates(map[string]interface{}{
+			"status":     credential.Status,
+			"revoked_at": credential.RevokedAt,
+			"revoked_by": credential.RevokedBy,
+			"updated_at": credential.UpdatedAt,
+		}).Error; err != nil {
+		return nil, fmt.Errorf("failed to record revoked credential: %w", err)
+	}
+
+	slog.Info("Application credential revoked", "applicationID", applicationID, "credentialID", credentialID)
+	response := applicationCredentialResponseOf(*credential)
+	return &response, nil
+}
+
+// getProvisionedApplication loads an application that is registered with the IDP
+func (s *ApplicationCredentialService) getProvisionedApplication(ctx context.Context, applicationID string) (*models.Application, error) {
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if application.IdpApplicationID == nil || *application.IdpApplicationID == "" {
+		return nil, ErrApplicationNotProvisioned
+	}
+	return &application, nil
+}
+
+// getActiveCredential loads a credential of the application and checks that it is active
+func (s *ApplicationCredentialService) getActiveCredential(ctx context.Context, applicationID, credentialID string) (*models.ApplicationCredential, error) {
+	var credential models.ApplicationCredential
+	if err := s.db.WithContext(ctx).
+		First(&credential, "credential_id = ? AND application_id = ?", credentialID, applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get credential: %w", err)
+	}
+	if credential.Status != models.ApplicationCredentialStatusActive {
+		return nil, ErrCredentialNotActive
+	}
+	return &credential, nil
+}
+
+func (s *ApplicationCredentialService) newApplicationCredential(applicationID string, oidcInfo *idp.ApplicationOIDCInfo, issuedBy string) models.ApplicationCredential {
+	hint := oidcInfo.ClientSecret
+	if len(hint) > secretHintLength {
+		hint = hint[len(hint)-secretHintLength:]
+	}
+	credential := models.ApplicationCredential{
+		CredentialID:  "cred_" + uuid.New().String(),
+		ApplicationID: applicationID,
+		ClientID:      oidcInfo.ClientId,
+		SecretHint:    hint,
+		Status:        models.ApplicationCredentialStatusActive,
+		IssuedBy:      issuedBy,
+	}
+	if s.lifetime > 0 {
+		expiresAt := time.Now().Add(s.lifetime)
+		credential.ExpiresAt = &expiresAt
+	}
+	return credential
+}
+
+func applicationCredentialResponseOf(credential models.ApplicationCredential) models.ApplicationCredentialResponse {
+	response := models.ApplicationCredentialResponse{
+		CredentialID:  credential.CredentialID,
+		ApplicationID: credential.ApplicationID,
+		ClientID:      credential.ClientID,
+		SecretHint:    credential.SecretHint,
+		Status:        credential.Status,
+		IssuedBy:      credential.IssuedBy,
+		RevokedBy:     credential.RevokedBy,
+		CreatedAt:     credential.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     credential.UpdatedAt.Format(time.RFC3339),
+	}
+	if credential.RevokedAt != nil {
+		revokedAt := credential.RevokedAt.Format(time.RFC3339)
+		response.RevokedAt = &revokedAt
+	}
+	if credential.ExpiresAt != nil {
+		expiresAt := credential.ExpiresAt.Format(time.RFC3339)
+		response.ExpiresAt = &expiresAt
+	}
+	return response
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationCredentialService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	secrets := []string{"first-secret-1111", "second-secret-2222"}
+	var revokedIdpApp string
+	service := NewApplicationCredentialService(db, &MockIDP{
+		RegenerateApplicationSecretFunc: func(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error) {
+			secret := secrets[0]
+			secrets = secrets[1:]
+			return &idp.ApplicationOIDCInfo{ClientId: "client-1", ClientSecret: secret}, nil
+		},
+		RevokeApplicationCredentialsFunc: func(ctx context.Context, applicationID string) error {
+			revokedIdpApp = applicationID
+			return nil
+		},
+	}, 30*24*time.Hour)
+
+	t.Run("ApplicationNotProvisioned", func(t *testing.T) {
+		_, err := service.GenerateCredential(ctx, "app_1", "idp-consumer")
+		assert.ErrorIs(t, err, ErrApplicationNotProvisioned)
+
+		_, err = service.GenerateCredential(ctx, "app_missing", "idp-consumer")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	idpAppID := "idp-app-1"
+	require.NoError(t, db.Model(&models.Application{}).Where("application_id = ?", "app_1").
+		Update("idp_application_id", idpAppID).Error)
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		generated, err := service.GenerateCredential(ctx, "app_1", "idp-consumer")
+		require.NoError(t, err)
+		assert.Equal(t, "first-secret-1111", generated.ClientSecret)
+		assert.Equal(t, "1111", generated.SecretHint)
+		assert.Equal(t, "idp-consumer", generated.IssuedBy)
+		require.NotNil(t, generated.ExpiresAt)
+		expiresAt, err := time.Parse(time.RFC3339, *generated.ExpiresAt)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), expiresAt, time.Minute)
+
+		_, err = service.GenerateCredential(ctx, "app_1", "idp-consumer")
+		assert.ErrorIs(t, err, ErrActiveCredentialExists)
+
+		rotated, err := service.RotateCredential(ctx, "app_1", generated.CredentialID, "idp-consumer")
+		require.NoError(t, err)
+		assert.Equal(t, "second-secret-2222", rotated.ClientSecret)
+
+		_, err = service.RotateCredential(ctx, "app_1", generated.CredentialID, "idp-consumer")
+		assert.ErrorIs(t, err, ErrCredentialNotActive)
+
+		revoked, err := service.RevokeCredential(ctx, "app_1", rotated.CredentialID, "idp-admin")
+		require.NoError(t, err)
+		assert.Equal(t, models.ApplicationCredentialStatusRevoked, revoked.Status)
+		assert.Equal(t, idpAppID, revokedIdpApp)
+		require.NotNil(t, revoked.RevokedBy)
+		assert.Equal(t, "idp-admin", *revoked.RevokedBy)
+
+		credentials, err := service.ListCredentials(ctx, "app_1")
+		require.NoError(t, err)
+		require.Len(t, credentials, 2)
+		statuses := map[string]models.ApplicationCredentialStatus{}
+		for _, credential := range credentials {
+			assert.Empty(t, credential.ClientSecret)
+			statuses[credential.CredentialID] = credential.Status
+		}
+		assert.Equal(t, models.ApplicationCredentialStatusRotated, statuses[generated.CredentialID])
+		assert.Equal(t, models.ApplicationCredentialStatusRevoked, statuses[rotated.CredentialID])
+	})
+
+	t.Run("IDPFailureRecordsNothing", func(t *testing.T) {
+		failing := NewApplicationCredentialService(db, &MockIDP{
+			RegenerateApplicationSecretFunc: func(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error) {
+				return nil, errors.New("idp unavailable")
+			},
+		}, 0)
+		_, err := failing.GenerateCredential(ctx, "app_1", "idp-consumer")
+		assert.Error(t, err)
+
+		credentials, err := service.ListCredentials(ctx, "app_1")
+		require.NoError(t, err)
+		assert.Len(t, credentials, 2)
+	})
+}
@@ -5,14 +5,33 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/portal-backend/idp"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/database"
 	"gorm.io/gorm"
 )
 
+// DefaultGrantDuration is how long the PDP grants an approved or renewed application access to its
+// selected fields
+const DefaultGrantDuration = models.GrantDurationTypeOneYear
+
+var (
+	// ErrRenewalPending is returned when renewing the grants of an application that already has a
+	// renewal submission in review
+	ErrRenewalPending = errors.New("application already has a renewal submission in review")
+	// ErrNotRenewalSubmission is returned when renewing grants from a submission that is not a renewal
+	ErrNotRenewalSubmission = errors.New("submission is not a renewal submission")
+	// ErrInvalidApplicationStatus is returned when setting an application to an unknown status
+	ErrInvalidApplicationStatus = errors.New("invalid application status")
+	// ErrInvalidQuota is returned for an application quota that is not positive or whose daily quota
+	// exceeds its monthly quota
+	ErrInvalidQuota = errors.New("invalid application quota")
+)
+
 // ApplicationService handles application-related operations
 type ApplicationService struct {
 	db            *gorm.DB
@@ -27,6 +46,12 @@ func NewApplicationService(db *gorm.DB, pdpService *PDPService, idp idp.Identity
 
 // CreateApplication creates a new application
 func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.CreateApplicationRequest) (*models.ApplicationResponse, error) {
+	// The application belongs to its member's organization
+	organizationID, err := memberOrganizationID(s.db.WithContext(ctx), req.MemberID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Step 1: Create Application in the IDP
 	description := ""
 	if req.ApplicationDescription != nil {
@@ -48,6 +73,10 @@ func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.
 	}
 
 	// Step 2: Create application in database
+	grantExpiresAt, err := DefaultGrantDuration.ExpiresAtFrom(time.Now())
+	if err != nil {
+		return nil, err
+	}
 	application := models.Application{
 		ApplicationID:          uuid.New().String(),
 		ApplicationName:        req.ApplicationName,
@@ -56,7 +85,10 @@ func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.
 		IdpApplicationID:       idpApplicationID,
 		IdpClientID:            &appOIDCInfo.ClientId,
 		MemberID:               req.MemberID,
+		OrganizationID:         organizationID,
 		Version:                string(models.ActiveVersion),
+		GrantExpiresAt:         &grantExpiresAt,
+		Status:                 models.ApplicationStatusActive,
 	}
 
 	if err := s.db.WithContext(ctx).Create(&application).Error; err != nil {
@@ -78,7 +110,8 @@ func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.
 	policyReq := models.AllowListUpdateRequest{
 		ApplicationID: application.ApplicationID,
 		Records:       application.SelectedFields,
-		GrantDuration: models.GrantDurationTypeOneMonth, // Default duration
+		GrantDuration: DefaultGrantDuration,
+		ConsumerID:    application.MemberID,
 	}
 
 	_, err = s.policyService.UpdateAllowList(policyReq)
@@ -87,8 +120,8 @@ func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.
 		// This ensures we don't leave orphaned resources in either system
 		var dbDeleteErr, idpDeleteErr error
 
-		// Attempt to delete from database
-		dbDeleteErr = s.db.Delete(&application).Error
+		// Attempt to delete from database; the row never went live, so it is removed rather than soft-deleted
+		dbDeleteErr = s.db.Unscoped().Delete(&application).Error
 		if dbDeleteErr != nil {
 			slog.Error("Failed to delete application from database during compensation",
 				"applicationID", application.ApplicationID,
@@ -119,20 +152,7 @@ func (s *ApplicationService) CreateApplication(ctx context.Context, req *models.
 		return nil, fmt.Errorf("failed to update allow list: %w", err)
 	}
 
-	response := &models.ApplicationResponse{
-		ApplicationID:          application.ApplicationID,
-		ApplicationName:        application.ApplicationName,
-		ApplicationDescription: application.ApplicationDescription,
-		SelectedFields:         application.SelectedFields,
-		MemberID:               application.MemberID,
-		Version:                application.Version,
-		IdpApplicationID:       application.IdpApplicationID,
-		IdpClientID:            application.IdpClientID,
-		CreatedAt:              application.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:              application.UpdatedAt.Format(time.RFC3339),
-	}
-
-	return response, nil
+	return applicationResponseOf(&application), nil
 }
 
 // UpdateApplication updates an existing application
@@ -142,6 +162,9 @@ func (s *ApplicationService) UpdateApplication(ctx context.Context, applicationI
 	if err != nil {
 		return nil, err
 	}
+	if err := checkRevision(req.Revision, application.Revision); err != nil {
+		return nil, revisionConflict(s.GetApplication(ctx, applicationID))
+	}
 
 	// Update fields if provided
 	// Note: SelectedFields updates are intentionally not supported for approved applications
@@ -156,27 +179,14 @@ func (s *ApplicationService) UpdateApplication(ctx context.Context, applicationI
 		application.Version = *req.Version
 	}
 
-	if err := s.db.WithContext(ctx).Save(&application).Error; err != nil {
+	if err := saveRevision(s.db.WithContext(ctx), &application, &application.Revision); err != nil {
+		if errors.Is(err, ErrRevisionConflict) {
+			return nil, revisionConflict(s.GetApplication(ctx, applicationID))
+		}
 		return nil, err
 	}
 
-	response := &models.ApplicationResponse{
-		ApplicationID:          application.ApplicationID,
-		ApplicationName:        application.ApplicationName,
-		ApplicationDescription: application.ApplicationDescription,
-		SelectedFields:         application.SelectedFields,
-		MemberID:               application.MemberID,
-		Version:                application.Version,
-		IdpApplicationID:       application.IdpApplicationID,
-		IdpClientID:            application.IdpClientID,
-		CreatedAt:              application.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:              application.UpdatedAt.Format(time.RFC3339),
-	}
-	if application.ApplicationDescription != nil && *application.ApplicationDescription != "" {
-		response.ApplicationDescription = application.ApplicationDescription
-	}
-
-	return response, nil
+	return applicationResponseOf(&application), nil
 }
 
 // GetApplication retrieves an application by ID
@@ -187,23 +197,175 @@ func (s *ApplicationService) GetApplication(ctx context.Context, applicationID s
 		return nil, err
 	}
 
+	return applicationResponseOf(&application), nil
+}
+
+// applicationResponseOf builds the API response of an application
+func applicationResponseOf(application *models.Application) *models.ApplicationResponse {
 	response := &models.ApplicationResponse{
-		ApplicationID:          application.ApplicationID,
-		ApplicationName:        application.ApplicationName,
-		ApplicationDescription: application.ApplicationDescription,
-		SelectedFields:         application.SelectedFields,
-		MemberID:               application.MemberID,
-		Version:                application.Version,
-		IdpApplicationID:       application.IdpApplicationID,
-		IdpClientID:            application.IdpClientID,
-		CreatedAt:              application.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:              application.UpdatedAt.Format(time.RFC3339),
+		ApplicationID:    application.ApplicationID,
+		ApplicationName:  application.ApplicationName,
+		SelectedFields:   application.SelectedFields,
+		MemberID:         application.MemberID,
+		OrganizationID:   application.OrganizationID,
+		Version:          application.Version,
+		IdpApplicationID: application.IdpApplicationID,
+		IdpClientID:      application.IdpClientID,
+		Status:           application.Status,
+		StatusReason:     application.StatusReason,
+		CreatedAt:        application.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        application.UpdatedAt.Format(time.RFC3339),
+		Revision:         application.Revision,
 	}
 	if application.ApplicationDescription != nil && *application.ApplicationDescription != "" {
 		response.ApplicationDescription = application.ApplicationDescription
 	}
+	if application.GrantExpiresAt != nil {
+		grantExpiresAt := application.GrantExpiresAt.Format(time.RFC3339)
+		response.GrantExpiresAt = &grantExpiresAt
+	}
+	return response
+}
+
+// GetApplicationQuota returns the data usage quota of an application
+func (s *ApplicationService) GetApplicationQuota(ctx context.Context, applicationID string) (*models.ApplicationQuota, error) {
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return &models.ApplicationQuota{
+		ApplicationID:      application.ApplicationID,
+		DailyRecordQuota:   application.DailyRecordQuota,
+		MonthlyRecordQuota: application.MonthlyRecordQuota,
+	}, nil
+}
+
+// UpdateApplicationQuota replaces the data usage quota of an application
+func (s *ApplicationService) UpdateApplicationQuota(ctx context.Context, applicationID string, req *models.UpdateApplicationQuotaRequest) (*models.ApplicationQuota, error) {
+	var problems []models.FieldError
+	quotas := []struct {
+		name  string
+		value *int64
+	}{{"dailyRecordQuota", req.DailyRecordQuota}, {"monthlyRecordQuota", req.MonthlyRecordQuota}}
+	for _, quota := range quotas {
+		if quota.value != nil && *quota.value <= 0 {
+			problems = append(problems, models.NewFieldError(models.ValidationErrorInvalidValue, quota.name, quota.name+" must be positive"))
+		}
+	}
+	if len(problems) == 0 && req.DailyRecordQuota != nil && req.MonthlyRecordQuota != nil && *req.DailyRecordQuota > *req.MonthlyRecordQuota {
+		problems = append(problems, models.NewFieldError(models.ValidationErrorInvalidValue, "dailyRecordQuota", "dailyRecordQuota must not exceed monthlyRecordQuota"))
+	}
+	if len(problems) > 0 {
+		return nil, &models.ValidationError{Err: ErrInvalidQuota, Fields: problems}
+	}
+
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	err := s.db.WithContext(ctx).Model(&application).Updates(map[string]interface{}{
+		"daily_record_quota":   req.DailyRecordQuota,
+		"monthly_record_quota": req.MonthlyRecordQuota,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return &models.ApplicationQuota{
+		ApplicationID:      application.ApplicationID,
+		DailyRecordQuota:   req.DailyRecordQuota,
+		MonthlyRecordQuota: req.MonthlyRecordQuota,
+	}, nil
+}
+
+// UpdateApplicationStatus suspends or reactivates an application. The orchestration engine rejects the
+// requests of suspended applications once its cache of the application context expires.
+func (s *ApplicationService) UpdateApplicationStatus(ctx context.Context, applicationID string, req *models.UpdateApplicationStatusRequest) (*models.ApplicationResponse, error) {
+	if !req.Status.IsValid() {
+		return nil, &models.ValidationError{Err: ErrInvalidApplicationStatus, Fields: []models.FieldError{
+			models.NewFieldError(models.ValidationErrorInvalidValue, "status", "status must be active or suspended"),
+		}}
+	}
+	var reason *string
+	if req.Status == models.ApplicationStatusSuspended && req.Reason != nil && strings.TrimSpace(*req.Reason) != "" {
+		trimmed := strings.TrimSpace(*req.Reason)
+		reason = &trimmed
+	}
+
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	err := s.db.WithContext(ctx).Model(&application).Updates(map[string]interface{}{
+		"status":        req.Status,
+		"status_reason": reason,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update application status: %w", err)
+	}
+	application.Status = req.Status
+	application.StatusReason = reason
 
-	return response, nil
+	slog.Info("Application status updated", "applicationID", applicationID, "status", req.Status)
+	return applicationResponseOf(&application), nil
+}
+
+// GetApplicationContext describes the application for the orchestration engine. The environment is
+// sandbox when idpClientID is the client of the application's sandbox, and production otherwise.
+func (s *ApplicationService) GetApplicationContext(ctx context.Context, applicationID, idpClientID string) (*models.ApplicationContext, error) {
+	var application models.Application
+	if err := s.db.WithContext(ctx).Preload("Member").First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	applicationContext := &models.ApplicationContext{
+		ApplicationID:   application.ApplicationID,
+		ApplicationName: application.ApplicationName,
+		MemberID:        application.MemberID,
+		MemberName:      application.Member.Name,
+		OrganizationID:  application.OrganizationID,
+		Environment:     models.EnvironmentProduction,
+		Status:          application.Status,
+		StatusReason:    application.StatusReason,
+	}
+	if application.GrantExpiresAt != nil {
+		grantExpiresAt := application.GrantExpiresAt.Format(time.RFC3339)
+		applicationContext.GrantExpiresAt = &grantExpiresAt
+	}
+	if application.OrganizationID != nil {
+		var organization models.Organization
+		err := s.db.WithContext(ctx).First(&organization, "organization_id = ?", *application.OrganizationID).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get organization: %w", err)
+		}
+		if err == nil {
+			applicationContext.OrganizationName = &organization.Name
+			applicationContext.Sector = organization.Sector
+		}
+	}
+	if idpClientID != "" && (application.IdpClientID == nil || *application.IdpClientID != idpClientID) {
+		var sandboxes int64
+		err := s.db.WithContext(ctx).Model(&models.ApplicationSandbox{}).
+			Where("application_id = ? AND idp_client_id = ?", applicationID, idpClientID).Count(&sandboxes).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get application sandbox: %w", err)
+		}
+		if sandboxes > 0 {
+			applicationContext.Environment = models.EnvironmentSandbox
+		}
+	}
+	return applicationContext, nil
 }
 
 // GetApplicationIdByIdpClientId retrieves applicationId by idpClientId
@@ -218,50 +380,63 @@ func (s *ApplicationService) GetApplicationIdByIdpClientId(ctx context.Context,
 	}
 	return &models.ApplicationIDResponse{
 		ApplicationID: application.ApplicationID,
+		Environment:   models.EnvironmentProduction,
 	}, nil
 }
 
-// GetApplications retrieves all applications and filters by member ID if provided
-func (s *ApplicationService) GetApplications(ctx context.Context, MemberID *string) ([]models.ApplicationResponse, error) {
-	var applications []models.Application
-	query := s.db.WithContext(ctx).Preload("Member")
-	if MemberID != nil && *MemberID != "" {
-		query = query.Where("member_id = ?", *MemberID)
+// applicationListColumns describes how application collections are searched and sorted
+var applicationListColumns = listColumns{
+	sortable: map[string]string{
+		"createdAt":       "created_at",
+		"updatedAt":       "updated_at",
+		"applicationName": "application_name",
+		"version":         "version",
+	},
+	search: "application_name",
+	key:    "application_id",
+}
+
+// GetApplications retrieves a page of applications, filtered by member, name and creation time
+func (s *ApplicationService) GetApplications(ctx context.Context, q models.ListQuery) ([]models.ApplicationResponse, *models.PaginationMetadata, error) {
+	order, err := applicationListColumns.normalize(&q)
+	if err != nil {
+		return nil, nil, err
 	}
+	query := applicationListColumns.filter(database.Reader(s.db).WithContext(ctx).Model(&models.Application{}), q).Session(&gorm.Session{})
 
-	// Order by created_at descending
-	query = query.Order("created_at DESC")
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
 
-	err := query.Find(&applications).Error
+	var applications []models.Application
+	err = page(query.Preload("Member").Order(order), q).Find(&applications).Error
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Pre-allocate slice with known capacity for better performance
 	responses := make([]models.ApplicationResponse, 0, len(applications))
 	for _, application := range applications {
-		resp := models.ApplicationResponse{
-			ApplicationID:    application.ApplicationID,
-			ApplicationName:  application.ApplicationName,
-			SelectedFields:   application.SelectedFields,
-			MemberID:         application.MemberID,
-			IdpApplicationID: application.IdpApplicationID,
-			IdpClientID:      application.IdpClientID,
-			Version:          application.Version,
-			CreatedAt:        application.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:        application.UpdatedAt.Format(time.RFC3339),
-		}
-		if application.ApplicationDescription != nil && *application.ApplicationDescription != "" {
-			resp.ApplicationDescription = application.ApplicationDescription
-		}
-		responses = append(responses, resp)
+		responses = append(responses, *applicationResponseOf(&application))
 	}
 
-	return responses, nil
+	return responses, paginationMetadata(q, total), nil
 }
 
 // CreateApplicationSubmission creates a new application submission
 func (s *ApplicationService) CreateApplicationSubmission(ctx context.Context, req *models.CreateApplicationSubmissionRequest) (*models.ApplicationSubmissionResponse, error) {
+	status, err := initialSubmissionStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+	// Drafts may be incomplete; they are validated when they are submitted
+	if status == string(models.StatusPending) {
+		if err := validateApplicationSubmission(req.ApplicationName, req.SelectedFields); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate previous application ID if provided
 	if req.PreviousApplicationID != nil {
 		var prevApp models.Application
@@ -273,7 +448,7 @@ func (s *ApplicationService) CreateApplicationSubmission(ctx context.Context, re
 
 	// Validate member ID
 	var member models.Member
-	err := s.db.WithContext(ctx).First(&member, "member_id = ?", req.MemberID).Error
+	err = s.db.WithContext(ctx).First(&member, "member_id = ?", req.MemberID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -285,26 +460,15 @@ func (s *ApplicationService) CreateApplicationSubmission(ctx context.Context, re
 		ApplicationName:        req.ApplicationName,
 		ApplicationDescription: req.ApplicationDescription,
 		SelectedFields:         models.SelectedFieldRecords(req.SelectedFields),
-		Status:                 string(models.StatusPending),
+		Status:                 status,
 		MemberID:               req.MemberID,
+		OrganizationID:         member.OrganizationID,
 	}
 	if err := s.db.WithContext(ctx).Create(&submission).Error; err != nil {
 		return nil, err
 	}
 
-	response := &models.ApplicationSubmissionResponse{
-		SubmissionID:           submission.SubmissionID,
-		PreviousApplicationID:  submission.PreviousApplicationID,
-		ApplicationName:        submission.ApplicationName,
-		ApplicationDescription: submission.ApplicationDescription,
-		SelectedFields:         submission.SelectedFields,
-		Status:                 submission.Status,
-		MemberID:               submission.MemberID,
-		CreatedAt:              submission.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:              submission.UpdatedAt.Format(time.RFC3339),
-	}
-
-	return response, nil
+	return applicationSubmissionResponseOf(&submission), nil
 }
 
 // UpdateApplicationSubmission updates an existing application submission
@@ -341,13 +505,17 @@ func (s *ApplicationService) UpdateApplicationSubmission(ctx context.Context, su
 		submission.PreviousApplicationID = req.PreviousApplicationID
 	}
 
-	var shouldCreateApplication bool
+	rejected := false
 	if req.Status != nil {
-		submission.Status = *req.Status
-		// Mark that we need to create an application after saving
-		if *req.Status == string(models.StatusApproved) {
-			shouldCreateApplication = true
+		if submission.Status == string(models.StatusDraft) && *req.Status != submission.Status {
+			return nil, ErrDraftSubmitRequired
+		}
+		// Approval and step changes go through the review workflow; a submission can only be rejected here
+		if *req.Status != submission.Status && *req.Status != string(models.StatusRejected) {
+			return nil, ErrSubmissionReviewRequired
 		}
+		rejected = *req.Status != submission.Status
+		submission.Status = *req.Status
 	}
 
 	if req.Review != nil {
@@ -358,32 +526,43 @@ func (s *ApplicationService) UpdateApplicationSubmission(ctx context.Context, su
 	if err := s.db.WithContext(ctx).Save(&submission).Error; err != nil {
 		return nil, fmt.Errorf("failed to update application submission: %w", err)
 	}
+	if rejected && submission.Renewal {
+		// The submission stays rejected either way; a renewal request left pending in the PDP extends no grants
+		if err := s.RejectApplicationRenewal(ctx, submissionID); err != nil {
+			slog.Error("Failed to reject PDP renewal request", "submissionID", submissionID, "error", err)
+		}
+	}
 
-	// Create application outside of transaction if approval was successful
-	if shouldCreateApplication {
-		var createApplicationRequest models.CreateApplicationRequest
-		createApplicationRequest.ApplicationName = submission.ApplicationName
-		createApplicationRequest.ApplicationDescription = submission.ApplicationDescription
-		createApplicationRequest.SelectedFields = models.SelectedFieldRecords(submission.SelectedFields)
-		createApplicationRequest.MemberID = submission.MemberID
+	return applicationSubmissionResponseOf(&submission), nil
+}
 
-		_, err := s.CreateApplication(ctx, &createApplicationRequest)
-		if err != nil {
-			// Compensation: Update submission status back to pending
-			submission.Status = string(models.StatusPending)
-			if updateErr := s.db.WithContext(ctx).Save(&submission).Error; updateErr != nil {
-				slog.Error("Failed to compensate submission status after application creation failure",
-					"submissionID", submission.SubmissionID,
-					"originalError", err,
-					"compensationError", updateErr)
-				return nil, fmt.Errorf("failed to create application from approved submission: %w, and failed to compensate submission status: %w", err, updateErr)
-			}
-			slog.Info("Successfully compensated submission status after application creation failure", "submissionID", submission.SubmissionID)
-			return nil, fmt.Errorf("failed to create application from approved submission: %w", err)
+// SubmitApplicationSubmission validates a draft application submission and submits it for review
+func (s *ApplicationService) SubmitApplicationSubmission(ctx context.Context, submissionID string) (*models.ApplicationSubmissionResponse, error) {
+	var submission models.ApplicationSubmission
+	if err := s.db.WithContext(ctx).First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
 		}
+		return nil, fmt.Errorf("failed to get application submission: %w", err)
+	}
+	if submission.Status != string(models.StatusDraft) {
+		return nil, ErrSubmissionNotDraft
 	}
+	if err := validateApplicationSubmission(submission.ApplicationName, submission.SelectedFields); err != nil {
+		return nil, err
+	}
+
+	submission.Status = string(models.StatusPending)
+	if err := s.db.WithContext(ctx).Save(&submission).Error; err != nil {
+		return nil, fmt.Errorf("failed to submit application submission: %w", err)
+	}
+
+	return applicationSubmissionResponseOf(&submission), nil
+}
 
-	response := &models.ApplicationSubmissionResponse{
+// applicationSubmissionResponseOf builds the API response of an application submission
+func applicationSubmissionResponseOf(submission *models.ApplicationSubmission) *models.ApplicationSubmissionResponse {
+	return &models.ApplicationSubmissionResponse{
 		SubmissionID:           submission.SubmissionID,
 		PreviousApplicationID:  submission.PreviousApplicationID,
 		ApplicationName:        submission.ApplicationName,
@@ -391,12 +570,21 @@ func (s *ApplicationService) UpdateApplicationSubmission(ctx context.Context, su
 		SelectedFields:         submission.SelectedFields,
 		Status:                 submission.Status,
 		MemberID:               submission.MemberID,
+		OrganizationID:         submission.OrganizationID,
 		CreatedAt:              submission.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:              submission.UpdatedAt.Format(time.RFC3339),
 		Review:                 submission.Review,
+		Renewal:                submission.Renewal,
 	}
+}
 
-	return response, nil
+// validateApplicationSubmission returns an error if an application submission is not ready for review
+func validateApplicationSubmission(applicationName string, selectedFields []models.SelectedFieldRecord) error {
+	problems := missingFields(requiredField{name: "applicationName", value: applicationName})
+	if len(selectedFields) == 0 {
+		problems = append(problems, models.NewFieldError(models.ValidationErrorTooShort, "selectedFields", "selectedFields must not be empty"))
+	}
+	return incompleteSubmission(problems)
 }
 
 // GetApplicationSubmission retrieves an application submission by ID
@@ -407,56 +595,345 @@ func (s *ApplicationService) GetApplicationSubmission(ctx context.Context, submi
 		return nil, err
 	}
 
-	response := &models.ApplicationSubmissionResponse{
-		SubmissionID:           submission.SubmissionID,
-		PreviousApplicationID:  submission.PreviousApplicationID,
-		ApplicationName:        submission.ApplicationName,
-		ApplicationDescription: submission.ApplicationDescription,
-		SelectedFields:         submission.SelectedFields,
-		Status:                 submission.Status,
-		MemberID:               submission.MemberID,
-		CreatedAt:              submission.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:              submission.UpdatedAt.Format(time.RFC3339),
-		Review:                 submission.Review,
-	}
+	return applicationSubmissionResponseOf(&submission), nil
+}
 
-	return response, nil
+// applicationSubmissionListColumns describes how application submission collections are searched and sorted
+var applicationSubmissionListColumns = listColumns{
+	sortable: map[string]string{
+		"createdAt":       "created_at",
+		"updatedAt":       "updated_at",
+		"applicationName": "application_name",
+		"status":          "status",
+	},
+	search: "application_name",
+	key:    "submission_id",
 }
 
-// GetApplicationSubmissions retrieves all application submissions and filters by member ID if provided
-func (s *ApplicationService) GetApplicationSubmissions(ctx context.Context, MemberID *string, statusFilter *[]string) ([]models.ApplicationSubmissionResponse, error) {
+// GetApplicationSubmissions retrieves a page of application submissions, filtered by member, status, name and creation time
+func (s *ApplicationService) GetApplicationSubmissions(ctx context.Context, q models.ListQuery) ([]models.ApplicationSubmissionResponse, *models.PaginationMetadata, error) {
+	order, err := applicationSubmissionListColumns.normalize(&q)
+	if err != nil {
+		return nil, nil, err
+	}
+	query := applicationSubmissionListColumns.filter(database.Reader(s.db).WithContext(ctx).Model(&models.ApplicationSubmission{}), q).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
 	var submissions []models.ApplicationSubmission
-	query := s.db.WithContext(ctx).Preload("Member").Preload("PreviousApplication")
-	if MemberID != nil && *MemberID != "" {
-		query = query.Where("member_id = ?", *MemberID)
+	err = page(query.Preload("Member").Preload("PreviousApplication").Order(order), q).Find(&submissions).Error
+	if err != nil {
+		return nil, nil, err
 	}
-	if statusFilter != nil && len(*statusFilter) > 0 {
-		query = query.Where("status IN ?", *statusFilter)
+
+	responses := make([]models.ApplicationSubmissionResponse, 0, len(submissions))
+	for _, submission := range submissions {
+		responses = append(responses, *applicationSubmissionResponseOf(&submission))
 	}
 
-	// Order by created_at descending
-	query = query.Order("created_at DESC")
+	return responses, paginationMetadata(q, total), nil
+}
+
+// DeleteApplication soft-deletes an application. Its IdP application and PDP allow list are kept so
+// that it can be restored, but the application can no longer be resolved from its client ID.
+func (s *ApplicationService) DeleteApplication(ctx context.Context, applicationID, deletedBy string) error {
+	affected, err := softDelete(s.db.WithContext(ctx), &models.Application{}, deletionTime(), deletedBy, "application_id = ?", applicationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete application: %w", err)
+	}
+	if affected == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
 
-	err := query.Find(&submissions).Error
+// RestoreApplication restores a deleted application
+func (s *ApplicationService) RestoreApplication(ctx context.Context, applicationID string) (*models.ApplicationResponse, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var application models.Application
+		if err := findDeleted(tx, &application, &application.DeletedAt, "application_id = ?", applicationID); err != nil {
+			return err
+		}
+		if err := ensureMemberLive(tx, application.MemberID); err != nil {
+			return err
+		}
+		if err := restoreDeleted(tx, &models.Application{}, "application_id = ?", applicationID); err != nil {
+			return fmt.Errorf("failed to restore application: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return s.GetApplication(ctx, applicationID)
+}
 
-	var responses []models.ApplicationSubmissionResponse
-	for _, submission := range submissions {
-		responses = append(responses, models.ApplicationSubmissionResponse{
-			SubmissionID:           submission.SubmissionID,
-			PreviousApplicationID:  submission.PreviousApplicationID,
-			ApplicationName:        submission.ApplicationName,
-			ApplicationDescription: submission.ApplicationDescription,
-			SelectedFields:         submission.SelectedFields,
-			Status:                 submission.Status,
-			MemberID:               submission.MemberID,
-			CreatedAt:              submission.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:              submission.UpdatedAt.Format(time.RFC3339),
-			Review:                 submission.Review,
+// DeleteApplicationSubmission soft-deletes an application submission
+func (s *ApplicationService) DeleteApplicationSubmission(ctx context.Context, submissionID, deletedBy string) error {
+	affected, err := softDelete(s.db.WithContext(ctx), &models.ApplicationSubmission{}, deletionTime(), deletedBy, "submission_id = ?", submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete application submission: %w", err)
+	}
+	if affected == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// RestoreApplicationSubmission restores a deleted application submission
+func (s *ApplicationService) RestoreApplicationSubmission(ctx context.Context, submissionID string) (*models.ApplicationSubmissionResponse, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var submission models.ApplicationSubmission
+		if err := findDeleted(tx, &submission, &submission.DeletedAt, "submission_id = ?", submissionID); err != nil {
+			return err
+		}
+		if err := ensureMemberLive(tx, submission.MemberID); err != nil {
+			return err
+		}
+		if err := restoreDeleted(tx, &models.ApplicationSubmission{}, "submission_id = ?", submissionID); err != nil {
+			return fmt.Errorf("failed to restore application submission: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetApplicationSubmission(ctx, submissionID)
+}
+
+// ExpiringGrant is an application whose grants FlagExpiringGrants flagged for renewal
+type ExpiringGrant struct {
+	ApplicationID   string
+	ApplicationName string
+	MemberID        string
+	ExpiresAt       time.Time
+	// SubmissionID is the renewal submission in review for the grants
+	SubmissionID string
+}
+
+// RenewApplication opens a renewal submission for the grants of an application. The submission is
+// reviewed like any other; approving it renews the grants for DefaultGrantDuration.
+func (s *ApplicationService) RenewApplication(ctx context.Context, applicationID string) (*models.ApplicationSubmissionResponse, error) {
+	var submission *models.ApplicationSubmission
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var application models.Application
+		if err := tx.First(&application, "application_id = ?", applicationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrResourceNotFound
+			}
+			return fmt.Errorf("failed to get application: %w", err)
+		}
+		open, err := openRenewalSubmission(tx, applicationID)
+		if err != nil {
+			return err
+		}
+		if open != nil {
+			return ErrRenewalPending
+		}
+		submission, err = s.createRenewalSubmission(tx, &application)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applicationSubmissionResponseOf(submission), nil
+}
+
+// FlagExpiringGrants opens a renewal submission for every active application whose grants expire
+// within notice, reusing a renewal the member already opened. Each grant is flagged once until it is
+// renewed; it returns the grants flagged.
+func (s *ApplicationService) FlagExpiringGrants(ctx context.Context, notice time.Duration) ([]ExpiringGrant, error) {
+	var applications []models.Application
+	if err := s.db.WithContext(ctx).
+		Where("version = ? AND grant_expires_at <= ? AND grant_renewal_flagged_at IS NULL", models.ActiveVersion, time.Now().Add(notice)).
+		Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("failed to find expiring grants: %w", err)
+	}
+
+	var flagged []ExpiringGrant
+	for i := range applications {
+		application := &applications[i]
+		var submissionID string
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Claim the application first so that a concurrent worker does not flag it twice
+			result := tx.Model(&models.Application{}).
+				Where("application_id = ? AND grant_renewal_flagged_at IS NULL", application.ApplicationID).
+				UpdateColumn("grant_renewal_flagged_at", time.Now())
+			if result.Error != nil {
+				return fmt.Errorf("failed to flag application grants: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+
+			submission, err := openRenewalSubmission(tx, application.ApplicationID)
+			if err != nil {
+				return err
+			}
+			if submission == nil {
+				if submission, err = s.createRenewalSubmission(tx, application); err != nil {
+					return err
+				}
+			}
+			submissionID = submission.SubmissionID
+			return nil
+		})
+		if err != nil {
+			return flagged, err
+		}
+		if submissionID == "" {
+			continue
+		}
+		flagged = append(flagged, ExpiringGrant{
+			ApplicationID:   application.ApplicationID,
+			ApplicationName: application.ApplicationName,
+			MemberID:        application.MemberID,
+			ExpiresAt:       *application.GrantExpiresAt,
+			SubmissionID:    submissionID,
 		})
 	}
+	return flagged, nil
+}
+
+// RenewApplicationGrant approves the PDP renewal request of an approved renewal submission, which
+// renews the application's grants for DefaultGrantDuration from now. The grants can be flagged for
+// renewal again once they near their new expiry.
+func (s *ApplicationService) RenewApplicationGrant(ctx context.Context, submissionID string) (*models.ApplicationResponse, error) {
+	submission, application, err := s.getRenewalSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Submissions opened before renewals went through the PDP have no renewal request yet
+	renewalID := submission.PDPRenewalID
+	if renewalID == nil {
+		renewal, err := s.openPDPRenewal(application)
+		if err != nil {
+			return nil, err
+		}
+		renewalID = &renewal.ID
+		if err := s.db.WithContext(ctx).Model(&models.ApplicationSubmission{}).
+			Where("submission_id = ?", submissionID).
+			UpdateColumn("pdp_renewal_id", renewal.ID).Error; err != nil {
+			return nil, fmt.Errorf("failed to record PDP renewal request: %w", err)
+		}
+	}
+
+	grantExpiresAt, err := DefaultGrantDuration.ExpiresAtFrom(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.policyService.ReviewRenewalRequest(*renewalID, models.AllowListRenewalReviewRequest{
+		Status:        models.RenewalRequestApproved,
+		ReviewComment: submission.Review,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to approve PDP renewal request: %w", err)
+	}
+
+	if err := s.db.WithContext(ctx).Model(&models.Application{}).
+		Where("application_id = ?", application.ApplicationID).
+		UpdateColumns(map[string]interface{}{"grant_expires_at": grantExpiresAt, "grant_renewal_flagged_at": nil}).Error; err != nil {
+		return nil, fmt.Errorf("failed to record renewed grants: %w", err)
+	}
+	slog.Info("Application grants renewed", "applicationID", application.ApplicationID, "submissionID", submissionID, "renewalID", *renewalID, "grantExpiresAt", grantExpiresAt)
+	return s.GetApplication(ctx, application.ApplicationID)
+}
+
+// RejectApplicationRenewal rejects the PDP renewal request of a rejected renewal submission, so the
+// PDP does not keep it pending
+func (s *ApplicationService) RejectApplicationRenewal(ctx context.Context, submissionID string) error {
+	submission, _, err := s.getRenewalSubmission(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+	if submission.PDPRenewalID == nil {
+		return nil
+	}
+	if _, err := s.policyService.ReviewRenewalRequest(*submission.PDPRenewalID, models.AllowListRenewalReviewRequest{
+		Status:        models.RenewalRequestRejected,
+		ReviewComment: submission.Review,
+	}); err != nil {
+		return fmt.Errorf("failed to reject PDP renewal request: %w", err)
+	}
+	return nil
+}
+
+// getRenewalSubmission returns a renewal submission and the application whose grants it renews
+func (s *ApplicationService) getRenewalSubmission(ctx context.Context, submissionID string) (*models.ApplicationSubmission, *models.Application, error) {
+	var submission models.ApplicationSubmission
+	if err := s.db.WithContext(ctx).First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrResourceNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get application submission: %w", err)
+	}
+	if !submission.Renewal || submission.PreviousApplicationID == nil {
+		return nil, nil, ErrNotRenewalSubmission
+	}
+
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", *submission.PreviousApplicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrResourceNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return &submission, &application, nil
+}
+
+// openPDPRenewal opens a PDP renewal request for the application's selected fields
+func (s *ApplicationService) openPDPRenewal(application *models.Application) (*models.AllowListRenewal, error) {
+	reason := "Renewal of application " + application.ApplicationName
+	renewal, err := s.policyService.CreateRenewalRequest(models.AllowListRenewalCreateRequest{
+		ApplicationID: application.ApplicationID,
+		Records:       application.SelectedFields,
+		GrantDuration: DefaultGrantDuration,
+		Reason:        &reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDP renewal request: %w", err)
+	}
+	return renewal, nil
+}
+
+// openRenewalSubmission returns the renewal submission of an application that is still in review, if any
+func openRenewalSubmission(tx *gorm.DB, applicationID string) (*models.ApplicationSubmission, error) {
+	var submissions []models.ApplicationSubmission
+	if err := tx.Where("previous_application_id = ? AND renewal = ? AND status NOT IN ?",
+		applicationID, true, []string{string(models.StatusApproved), string(models.StatusRejected)}).
+		Limit(1).Find(&submissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to find renewal submissions: %w", err)
+	}
+	if len(submissions) == 0 {
+		return nil, nil
+	}
+	return &submissions[0], nil
+}
 
-	return responses, nil
+// createRenewalSubmission submits a renewal of an application's grants for review, and opens the PDP
+// renewal request that reviewing the submission approves or rejects
+func (s *ApplicationService) createRenewalSubmission(tx *gorm.DB, application *models.Application) (*models.ApplicationSubmission, error) {
+	renewal, err := s.openPDPRenewal(application)
+	if err != nil {
+		return nil, err
+	}
+	submission := models.ApplicationSubmission{
+		SubmissionID:           "sub_" + uuid.New().String(),
+		PreviousApplicationID:  &application.ApplicationID,
+		ApplicationName:        application.ApplicationName,
+		ApplicationDescription: application.ApplicationDescription,
+		SelectedFields:         application.SelectedFields,
+		Status:                 string(models.StatusPending),
+		MemberID:               application.MemberID,
+		OrganizationID:         application.OrganizationID,
+		Renewal:                true,
+		PDPRenewalID:           &renewal.ID,
+	}
+	if err := tx.Create(&submission).Error; err != nil {
+		return nil, fmt.Errorf("failed to create renewal submission: %w", err)
+	}
+	return &submission, nil
 }
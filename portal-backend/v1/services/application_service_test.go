@@ -46,6 +46,10 @@ func TestApplicationService_CreateApplication(t *testing.T) {
 			MemberID: "member-123",
 		}
 
+		// Mock: Look up the member's organization
+		mock.ExpectQuery(`SELECT .* FROM "members"`).
+			WillReturnRows(sqlmock.NewRows([]string{"member_id", "organization_id"}).AddRow("member-123", nil))
+
 		// Mock DB expectations
 		mock.ExpectQuery(`INSERT INTO "applications"`).
 			WillReturnRows(sqlmock.NewRows([]string{"application_id"}).AddRow("app_123"))
@@ -101,6 +105,10 @@ func TestApplicationService_CreateApplication(t *testing.T) {
 		}
 
 		// Mock DB expectations
+		// Mock: Look up the member's organization
+		mock.ExpectQuery(`SELECT .* FROM "members"`).
+			WillReturnRows(sqlmock.NewRows([]string{"member_id", "organization_id"}).AddRow("member-123", nil))
+
 		// 1. Create application
 		mock.ExpectQuery(`INSERT INTO "applications"`).
 			WillReturnRows(sqlmock.NewRows([]string{"application_id"}).AddRow("app_123"))
@@ -252,7 +260,10 @@ func TestApplicationService_GetApplications(t *testing.T) {
 		service := NewApplicationService(db, pdpService, mockIDP)
 
 		// Mock DB expectations
-		mock.ExpectQuery(`SELECT .* FROM "applications" ORDER BY created_at DESC`).
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "applications" WHERE "applications"."deleted_at" IS NULL`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(`SELECT .* FROM "applications" WHERE "applications"."deleted_at" IS NULL ORDER BY created_at DESC`).
+			WithArgs(models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"application_id", "application_name", "member_id", "version"}).
 				AddRow("app_1", "App 1", "member-1", "v1").
 				AddRow("app_2", "App 2", "member-2", "v1"))
@@ -266,10 +277,11 @@ func TestApplicationService_GetApplications(t *testing.T) {
 				AddRow("member-1", "Member 1").
 				AddRow("member-2", "Member 2"))
 
-		result, err := service.GetApplications(context.Background(), nil)
+		result, pagination, err := service.GetApplications(context.Background(), models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
+		assert.Equal(t, &models.PaginationMetadata{Page: 1, Limit: models.DefaultPageLimit, Total: 2, TotalPages: 1, Sort: "createdAt", Order: string(models.SortOrderDesc)}, pagination)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -285,8 +297,11 @@ func TestApplicationService_GetApplications(t *testing.T) {
 		memberID := "member-123"
 
 		// Mock DB expectations
-		mock.ExpectQuery(`SELECT .* FROM "applications" WHERE member_id = .* ORDER BY created_at DESC`).
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "applications" WHERE member_id = .*`).
 			WithArgs(memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM "applications" WHERE member_id = .* ORDER BY created_at DESC`).
+			WithArgs(memberID, models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"application_id", "application_name", "member_id", "version"}).
 				AddRow("app_1", "App 1", memberID, "v1"))
 
@@ -296,7 +311,7 @@ func TestApplicationService_GetApplications(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"member_id", "name"}).
 				AddRow(memberID, "Member 1"))
 
-		result, err := service.GetApplications(context.Background(), &memberID)
+		result, _, err := service.GetApplications(context.Background(), models.ListQuery{MemberID: &memberID})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
@@ -436,48 +451,19 @@ func TestApplicationService_UpdateApplicationSubmission(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("UpdateApplicationSubmission_ApprovalWithApplicationCreationFailure", func(t *testing.T) {
+	t.Run("UpdateApplicationSubmission_ApprovalRequiresReview", func(t *testing.T) {
 		db, mock, cleanup := SetupMockDB(t)
 		defer cleanup()
 
-		// Mock PDP failure
-		mockTransport := &MockRoundTripper{
-			RoundTripFunc: func(req *http.Request) (*http.Response, error) {
-				return &http.Response{
-					StatusCode: http.StatusInternalServerError,
-					Body:       io.NopCloser(bytes.NewBufferString(`{"error": "pdp error"}`)),
-					Header:     make(http.Header),
-				}, nil
-			},
-		}
 		pdpService := NewPDPService("http://mock-pdp", "mock-key")
-		pdpService.HTTPClient = &http.Client{Transport: mockTransport}
-
 		mockIDP := &MockIDP{}
 		service := NewApplicationService(db, pdpService, mockIDP)
 
-		// Mock DB expectations
-		// 1. Find submission
+		// Mock DB expectations: the submission is read but never saved
 		mock.ExpectQuery(`SELECT .*`).
 			WillReturnRows(sqlmock.NewRows([]string{"submission_id", "application_name", "member_id", "status"}).
 				AddRow("sub_123", "Original", "member-123", string(models.StatusPending)))
 
-		// 2. Save submission (status update to Approved)
-		mock.ExpectExec(`UPDATE "application_submissions"`).
-			WillReturnResult(sqlmock.NewResult(0, 1))
-
-		// 3. Create Application (will fail on PDP)
-		mock.ExpectQuery(`INSERT INTO "applications"`).
-			WillReturnRows(sqlmock.NewRows([]string{"application_id"}).AddRow("app_new"))
-
-		// 4. Compensation: Delete application (from CreateApplication compensation)
-		mock.ExpectExec(`DELETE FROM "applications"`).
-			WillReturnResult(sqlmock.NewResult(0, 1))
-
-		// 5. Compensation: Update submission status back to Pending
-		mock.ExpectExec(`UPDATE "application_submissions"`).
-			WillReturnResult(sqlmock.NewResult(0, 1))
-
 		status := string(models.StatusApproved)
 		req := &models.UpdateApplicationSubmissionRequest{
 			Status: &status,
@@ -485,9 +471,8 @@ func TestApplicationService_UpdateApplicationSubmission(t *testing.T) {
 
 		result, err := service.UpdateApplicationSubmission(context.Background(), "sub_123", req)
 
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrSubmissionReviewRequired)
 		assert.Nil(t, result)
-		assert.Contains(t, err.Error(), "failed to create application")
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -561,6 +546,8 @@ func TestApplicationService_GetApplicationSubmissions(t *testing.T) {
 		service := NewApplicationService(db, pdpService, mockIDP)
 
 		// Mock DB expectations
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "application_submissions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 		mock.ExpectQuery(`SELECT .*`).
 			WillReturnRows(sqlmock.NewRows([]string{"submission_id", "application_name", "member_id", "status"}).
 				AddRow("sub_1", "Sub 1", "member-1", string(models.StatusPending)).
@@ -572,7 +559,7 @@ func TestApplicationService_GetApplicationSubmissions(t *testing.T) {
 
 		// Preload PreviousApplication (none)
 
-		result, err := service.GetApplicationSubmissions(context.Background(), nil, nil)
+		result, _, err := service.GetApplicationSubmissions(context.Background(), models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
@@ -591,8 +578,11 @@ func TestApplicationService_GetApplicationSubmissions(t *testing.T) {
 		memberID := "member-123"
 
 		// Mock DB expectations
-		mock.ExpectQuery(`SELECT .* FROM "application_submissions" WHERE member_id = .* ORDER BY created_at DESC`).
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "application_submissions" WHERE member_id = .*`).
 			WithArgs(memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM "application_submissions" WHERE member_id = .* ORDER BY created_at DESC`).
+			WithArgs(memberID, models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"submission_id", "application_name", "member_id", "status"}).
 				AddRow("sub_1", "Sub 1", memberID, string(models.StatusPending)))
 
@@ -601,7 +591,7 @@ func TestApplicationService_GetApplicationSubmissions(t *testing.T) {
 			WithArgs(memberID).
 			WillReturnRows(sqlmock.NewRows([]string{"member_id", "name"}).AddRow(memberID, "Test Member"))
 
-		result, err := service.GetApplicationSubmissions(context.Background(), &memberID, nil)
+		result, _, err := service.GetApplicationSubmissions(context.Background(), models.ListQuery{MemberID: &memberID})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
@@ -621,8 +611,11 @@ func TestApplicationService_GetApplicationSubmissions(t *testing.T) {
 		statusFilter := []string{string(models.StatusApproved)}
 
 		// Mock DB expectations
-		mock.ExpectQuery(`SELECT .* FROM "application_submissions" WHERE status IN .* ORDER BY created_at DESC`).
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "application_submissions" WHERE status IN .*`).
 			WithArgs(statusFilter[0]).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM "application_submissions" WHERE status IN .* ORDER BY created_at DESC`).
+			WithArgs(statusFilter[0], models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"submission_id", "application_name", "member_id", "status"}).
 				AddRow("sub_2", "Sub 2", "member-123", string(models.StatusApproved)))
 
@@ -631,7 +624,7 @@ func TestApplicationService_GetApplicationSubmissions(t *testing.T) {
 			WithArgs("member-123").
 			WillReturnRows(sqlmock.NewRows([]string{"member_id", "name"}).AddRow("member-123", "Test Member"))
 
-		result, err := service.GetApplicationSubmissions(context.Background(), nil, &statusFilter)
+		result, _, err := service.GetApplicationSubmissions(context.Background(), models.ListQuery{Status: statusFilter})
 
 		assert.NoError(t, err)
 		if len(result) > 0 {
@@ -658,6 +651,10 @@ func TestApplicationService_CreateApplication_EdgeCases(t *testing.T) {
 		}
 
 		// Mock DB expectations
+		// Mock: Look up the member's organization
+		mock.ExpectQuery(`SELECT .* FROM "members"`).
+			WillReturnRows(sqlmock.NewRows([]string{"member_id", "organization_id"}).AddRow("member-123", nil))
+
 		// 1. Create application
 		mock.ExpectQuery(`INSERT INTO "applications"`).
 			WillReturnRows(sqlmock.NewRows([]string{"application_id"}).AddRow("app_123"))
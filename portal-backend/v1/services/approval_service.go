@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSelfApproval is returned when an admin confirms an approval they requested themselves
+	ErrSelfApproval = errors.New("approvals must be confirmed by a different admin")
+	// ErrApprovalNotPending is returned when deciding an approval that was already decided or has expired
+	ErrApprovalNotPending = errors.New("approval is not pending")
+	// ErrApprovalRequired is returned when an action that needs a second admin's confirmation is part
+	// of a bulk operation
+	ErrApprovalRequired = errors.New("action requires the confirmation of a second admin")
+)
+
+// ApprovalService enforces two-person (four-eyes) approval of sensitive admin actions. Requesting an
+// action that needs approval records a pending approval instead of running it; the action runs once
+// a different admin confirms the approval, on behalf of the admin who requested it.
+type ApprovalService struct {
+	db *gorm.DB
+	// actions are the actions that need a second admin's confirmation
+	actions map[models.ApprovalAction]bool
+	// lifetime is how long an approval waits for a second admin before it has to be requested again
+	lifetime time.Duration
+
+	memberService     *MemberService
+	reviewService     *SubmissionReviewService
+	credentialService *ApplicationCredentialService
+}
+
+// NewApprovalService creates a new approval service requiring a second admin for actions
+func NewApprovalService(db *gorm.DB, actions []models.ApprovalAction, lifetime time.Duration, memberService *MemberService,
+	reviewService *SubmissionReviewService, credentialService *ApplicationCredentialService) *ApprovalService {
+	required := make(map[models.ApprovalAction]bool, len(actions))
+	for _, action := range actions {
+		required[action] = true
+	}
+	return &ApprovalService{
+		db:                db,
+		actions:           required,
+		lifetime:          lifetime,
+		memberService:     memberService,
+		reviewService:     reviewService,
+		credentialService: credentialService,
+	}
+}
+
+// Requires reports whether action needs a second admin's confirmation
+func (s *ApprovalService) Requires(action models.ApprovalAction) bool {
+	return s.actions[action]
+}
+
+// RequiresReviewApproval reports whether a review decision needs a second admin's confirmation: that
+// is approving a schema submission on its last review step when its fields include sensitive personal data
+func (s *ApprovalService) RequiresReviewApproval(ctx context.Context, submissionType models.SubmissionType, submissionID string, decision models.ReviewDecision) (bool, error) {
+	if !s.Requires(models.ApprovalActionApproveSensitiveSchema) || submissionType != models.SubmissionTypeSchema ||
+		decision != models.ReviewDecisionApprove {
+		return false, nil
+	}
+	var submission models.SchemaSubmission
+	err := s.db.WithContext(ctx).Select("status", "field_configurations").First(&submission, "submission_id = ?", submissionID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrResourceNotFound
+		}
+		return false, fmt.Errorf("failed to get schema submission: %w", err)
+	}
+	return s.reviewService.IsLastStep(submission.Status) && submission.FieldConfigurations.HasSensitivePersonalData(), nil
+}
+
+// RequestApproval records requester's request to run action on a resource once a second admin confirms
+// it. While an approval of the same action on the resource is pending, it is returned instead.
+func (s *ApprovalService) RequestApproval(ctx context.Context, requester *models.AuthenticatedUser, action models.ApprovalAction, resourceID string, payload models.ApprovalPayload) (*models.AdminApprovalResponse, error) {
+	if err := s.checkResource(ctx, action, resourceID, payload); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var existing models.AdminApproval
+	err := s.db.WithContext(ctx).
+		Where("action = ? AND resource_id = ? AND status = ? AND expires_at > ?", action, resourceID, models.ApprovalStatusPending, now).
+		Order("created_at").
+		First(&existing).Error
+	if err == nil {
+		response := approvalResponseOf(existing, now)
+		return &response, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check pending approvals: %w", err)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal approval payload: %w", err)
+	}
+	approval := models.AdminApproval{
+		ApprovalID:       "apr_" + uuid.New().String(),
+		Action:           action,
+		ResourceID:       resourceID,
+		Payload:          string(data),
+		Status:           models.ApprovalStatusPending,
+		RequestedBy:      requester.IdpUserID,
+		RequestedByEmail: requester.Email,
+		ExpiresAt:        now.Add(s.lifetime),
+	}
+	if err := s.db.WithContext(ctx).Create(&approval).Error; err != nil {
+		return nil, fmt.Errorf("failed to create approval: %w", err)
+	}
+
+	slog.Info("Admin approval requested", "approvalID", approval.ApprovalID, "action", action,
+		"resourceID", resourceID, "requestedBy", requester.IdpUserID, "expiresAt", approval.ExpiresAt)
+	response := approvalResponseOf(approval, now)
+	return &response, nil
+}
+
+// GetApproval returns an approval
+func (s *ApprovalService) GetApproval(ctx context.Context, approvalID string) (*models.AdminApprovalResponse, error) {
+	approval, err := s.getApproval(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	response := approvalResponseOf(*approval, time.Now())
+	return &response, nil
+}
+
+// ListApprovals returns the approvals in status, or all approvals when status is empty, newest first
+func (s *ApprovalService) ListApprovals(ctx context.Context, status models.ApprovalStatus) ([]models.AdminApprovalResponse, error) {
+	now := time.Now()
+	query := s.db.WithContext(ctx).Order("created_at DESC")
+	switch status {
+	case "":
+	case models.ApprovalStatusPending:
+		query = query.Where("status = ? AND expires_at > ?", models.ApprovalStatusPending, now)
+	case models.ApprovalStatusExpired:
+		query = query.Where("status = ? AND expires_at <= ?", models.ApprovalStatusPending, now)
+	default:
+		query = query.Where("status = ?", status)
+	}
+
+	var approvals []models.AdminApproval
+	if err := query.Find(&approvals).Error; err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	responses := make([]models.AdminApprovalResponse, 0, len(approvals))
+	for _, approval := range approvals {
+		responses = append(responses, approvalResponseOf(approval, now))
+	}
+	return responses, nil
+}
+
+// ConfirmApproval records confirmer's confirmation of a pending approval and runs its action. The
+// response carries the action's result. If the action fails, the approval stays pending with the error.
+func (s *ApprovalService) ConfirmApproval(ctx context.Context, approvalID string, confirmer *models.AuthenticatedUser) (*models.AdminApprovalResponse, error) {
+	approval, err := s.getApproval(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.RequestedBy == confirmer.IdpUserID {
+		return nil, ErrSelfApproval
+	}
+
+	// Claim the approval before running its action, so that two admins confirming it at once cannot
+	// both run it
+	now := time.Now()
+	if err := s.decide(ctx, approval, models.ApprovalStatusConfirmed, confirmer, now); err != nil {
+		return nil, err
+	}
+
+	result, err := s.execute(ctx, approval)
+	if err != nil {
+		lastError := err.Error()
+		resetErr := s.db.WithContext(ctx).Model(&models.AdminApproval{}).Where("approval_id = ?", approvalID).Updates(map[string]interface{}{
+			"status":           models.ApprovalStatusPending,
+			"decided_by":       nil,
+			"decided_by_email": nil,
+			"decided_at":       nil,
+			"last_error":       lastError,
+			"updated_at":       time.Now(),
+		}).Error
+		if resetErr != nil {
+			slog.Error("Failed to return approval to pending after its action failed",
+				"approvalID", approvalID, "actionError", err, "error", resetErr)
+		}
+		return nil, err
+	}
+
+	slog.Info("Admin approval confirmed", "approvalID", approvalID, "action", approval.Action, "resourceID", approval.ResourceID,
+		"requestedBy", approval.RequestedBy, "confirmedBy", confirmer.IdpUserID)
+	response := approvalResponseOf(*approval, now)
+	response.Result = result
+	return &response, nil
+}
+
+// RejectApproval records admin's rejection of a pending approval, so that its action never runs. The
+// admin who requested it can reject it to withdraw the request.
+func (s *ApprovalService) RejectApproval(ctx context.Context, approvalID string, admin *models.AuthenticatedUser) (*models.AdminApprovalResponse, error) {
+	approval, err := s.getApproval(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.decide(ctx, approval, models.ApprovalStatusRejected, admin, now); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Admin approval rejected", "approvalID", approvalID, "action", approval.Action, "resourceID", approval.ResourceID,
+		"requestedBy", approval.RequestedBy, "rejectedBy", admin.IdpUserID)
+	response := approvalResponseOf(*approval, now)
+	return &response, nil
+}
+
+// checkResource fails when an action could not run on its resource, so that no admin is asked to
+// confirm it
+func (s *ApprovalService) checkResource(ctx context.Context, action models.ApprovalAction, resourceID string, payload models.ApprovalPayload) error {
+	switch action {
+	case models.ApprovalActionDeleteMember:
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.Member{}).Where("member_id = ?", resourceID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to get member: %w", err)
+		}
+		if count == 0 {
+			return ErrResourceNotFound
+		}
+	case models.ApprovalActionRotateCredential:
+		if _, err := s.credentialService.getActiveCredential(ctx, payload.ApplicationID, resourceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ApprovalService) getApproval(ctx context.Context, approvalID string) (*models.AdminApproval, error) {
+	var approval models.AdminApproval
+	if err := s.db.WithContext(ctx).First(&approval, "approval_id = ?", approvalID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get approval: %w", err)
+	}
+	return &approval, nil
+}
+
+// decide moves a pending, unexpired approval to status, failing if it was decided concurrently
+func (s *ApprovalService) decide(ctx context.Context, approval *models.AdminApproval, status models.ApprovalStatus, admin *models.AuthenticatedUser, now time.Time) error {
+	result := s.db.WithContext(ctx).Model(&models.AdminApproval{}).
+		Where("approval_id = ? AND status = ? AND expires_at > ?", approval.ApprovalID, models.ApprovalStatusPending, now).
+		Updates(map[string]interface{}{
+			"status":           status,
+			"decided_by":       admin.IdpUserID,
+			"decided_by_email": admin.Email,
+			"decided_at":       now,
+			"last_error":       nil,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update approval: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrApprovalNotPending
+	}
+	approval.Status = status
+	approval.DecidedBy = &admin.IdpUserID
+	approval.DecidedByEmail = &admin.Email
+	approval.DecidedAt = &now
+	approval.LastError = nil
+	return nil
+}
+
+// execute runs the action of an approval as the admin who requested it
+func (s *ApprovalService) execute(ctx context.Context, approval *models.AdminApproval) (interface{}, error) {
+	var payload models.ApprovalPayload
+	if err := json.Unmarshal([]byte(approval.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid approval payload: %w", err)
+	}
+
+	switch approval.Action {
+	case models.ApprovalActionDeleteMember:
+		return nil, s.memberService.DeleteMember(ctx, approval.ResourceID, approval.RequestedBy)
+	case models.ApprovalActionApproveSensitiveSchema:
+		if payload.Review == nil {
+			return nil, fmt.Errorf("invalid approval payload: review decision is missing")
+		}
+		return s.reviewService.Review(ctx, models.SubmissionTypeSchema, approval.ResourceID, approval.RequestedBy, payload.Review)
+	case models.ApprovalActionRotateCredential:
+		return s.credentialService.RotateCredential(ctx, payload.ApplicationID, approval.ResourceID, approval.RequestedBy)
+	default:
+		return nil, fmt.Errorf("unknown approval action %q", approval.Action)
+	}
+}
+
+func approvalResponseOf(approval models.AdminApproval, now time.Time) models.AdminApprovalResponse {
+	var payload models.ApprovalPayload
+	if err := json.Unmarshal([]byte(approval.Payload), &payload); err != nil {
+		slog.Error("Failed to unmarshal approval payload", "approvalID", approval.ApprovalID, "error", err)
+	}
+	response := models.AdminApprovalResponse{
+		ApprovalID:       approval.ApprovalID,
+		Action:           approval.Action,
+		ResourceID:       approval.ResourceID,
+		ApprovalPayload:  payload,
+		Status:           approval.Status,
+		RequestedBy:      approval.RequestedBy,
+		RequestedByEmail: approval.RequestedByEmail,
+		DecidedBy:        approval.DecidedBy,
+		DecidedByEmail:   approval.DecidedByEmail,
+		ExpiresAt:        approval.ExpiresAt.Format(time.RFC3339),
+		LastError:        approval.LastError,
+		CreatedAt:        approval.CreatedAt.Format(time.RFC3339),
+	}
+	if approval.Status == models.ApprovalStatusPending && !now.Before(approval.ExpiresAt) {
+		response.Status = models.ApprovalStatusExpired
+	}
+	if approval.DecidedAt != nil {
+		decidedAt := approval.DecidedAt.Format(time.RFC3339)
+		response.DecidedAt = &decidedAt
+	}
+	return response
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	reviewService := NewSubmissionReviewService(db, nil, nil, nil, nil, []models.ReviewStep{{Name: "technical_review", RequiredApprovals: 1}})
+	service := NewApprovalService(db, []models.ApprovalAction{models.ApprovalActionDeleteMember, models.ApprovalActionApproveSensitiveSchema},
+		time.Hour, NewMemberService(db, nil), reviewService, nil)
+
+	requester := &models.AuthenticatedUser{IdpUserID: "idp-admin-1", Email: "admin1@example.com", Roles: []models.Role{models.RoleAdmin}}
+	confirmer := &models.AuthenticatedUser{IdpUserID: "idp-admin-2", Email: "admin2@example.com", Roles: []models.Role{models.RoleAdmin}}
+
+	t.Run("Requires only the configured actions", func(t *testing.T) {
+		assert.True(t, service.Requires(models.ApprovalActionDeleteMember))
+		assert.False(t, service.Requires(models.ApprovalActionRotateCredential))
+	})
+
+	t.Run("RequiresReviewApproval flags final approvals of sensitive schemas", func(t *testing.T) {
+		required, err := service.RequiresReviewApproval(ctx, models.SubmissionTypeSchema, "sub_schema", models.ReviewDecisionApprove)
+		require.NoError(t, err)
+		assert.False(t, required, "the submission has no sensitive fields")
+
+		sensitive := models.FieldConfigurations{{FieldName: "person.health", Classification: models.ClassificationSensitivePersonal}}
+		require.NoError(t, db.Model(&models.SchemaSubmission{}).Where("submission_id = ?", "sub_schema").
+			Update("field_configurations", sensitive).Error)
+		required, err = service.RequiresReviewApproval(ctx, models.SubmissionTypeSchema, "sub_schema", models.ReviewDecisionApprove)
+		require.NoError(t, err)
+		assert.True(t, required)
+
+		required, err = service.RequiresReviewApproval(ctx, models.SubmissionTypeSchema, "sub_schema", models.ReviewDecisionReject)
+		require.NoError(t, err)
+		assert.False(t, required, "rejections need no second admin")
+
+		required, err = service.RequiresReviewApproval(ctx, models.SubmissionTypeApplication, "sub_app", models.ReviewDecisionApprove)
+		require.NoError(t, err)
+		assert.False(t, required)
+
+		_, err = service.RequiresReviewApproval(ctx, models.SubmissionTypeSchema, "sub_missing", models.ReviewDecisionApprove)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	t.Run("RequestApproval checks the resource", func(t *testing.T) {
+		_, err := service.RequestApproval(ctx, requester, models.ApprovalActionDeleteMember, "mem_missing", models.ApprovalPayload{})
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	approval, err := service.RequestApproval(ctx, requester, models.ApprovalActionDeleteMember, "mem_consumer", models.ApprovalPayload{})
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStatusPending, approval.Status)
+	assert.Equal(t, requester.IdpUserID, approval.RequestedBy)
+	assert.Equal(t, requester.Email, approval.RequestedByEmail)
+
+	t.Run("A pending approval is requested once", func(t *testing.T) {
+		again, err := service.RequestApproval(ctx, confirmer, models.ApprovalActionDeleteMember, "mem_consumer", models.ApprovalPayload{})
+		require.NoError(t, err)
+		assert.Equal(t, approval.ApprovalID, again.ApprovalID)
+		assert.Equal(t, requester.IdpUserID, again.RequestedBy)
+	})
+
+	t.Run("The requester cannot confirm their own approval", func(t *testing.T) {
+		_, err := service.ConfirmApproval(ctx, approval.ApprovalID, requester)
+		assert.ErrorIs(t, err, ErrSelfApproval)
+
+		var member models.Member
+		require.NoError(t, db.First(&member, "member_id = ?", "mem_consumer").Error, "the member is not deleted")
+	})
+
+	t.Run("A second admin confirms and the action runs for the requester", func(t *testing.T) {
+		confirmed, err := service.ConfirmApproval(ctx, approval.ApprovalID, confirmer)
+		require.NoError(t, err)
+		assert.Equal(t, models.ApprovalStatusConfirmed, confirmed.Status)
+		require.NotNil(t, confirmed.DecidedBy)
+		assert.Equal(t, confirmer.IdpUserID, *confirmed.DecidedBy)
+		assert.NotNil(t, confirmed.DecidedAt)
+
+		var member models.Member
+		require.NoError(t, db.Unscoped().First(&member, "member_id = ?", "mem_consumer").Error)
+		assert.True(t, member.DeletedAt.Valid)
+		require.NotNil(t, member.DeletedBy)
+		assert.Equal(t, requester.IdpUserID, *member.DeletedBy)
+
+		_, err = service.ConfirmApproval(ctx, approval.ApprovalID, confirmer)
+		assert.ErrorIs(t, err, ErrApprovalNotPending)
+	})
+
+	t.Run("An approval whose action fails stays pending", func(t *testing.T) {
+		review := &models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove}
+		schemaApproval, err := service.RequestApproval(ctx, requester, models.ApprovalActionApproveSensitiveSchema, "sub_schema", models.ApprovalPayload{Review: review})
+		require.NoError(t, err)
+		require.NotNil(t, schemaApproval.Review)
+		assert.Equal(t, models.ReviewDecisionApprove, schemaApproval.Review.Decision)
+
+		require.NoError(t, db.Model(&models.SchemaSubmission{}).Where("submission_id = ?", "sub_schema").
+			Update("status", models.StatusRejected).Error)
+		_, err = service.ConfirmApproval(ctx, schemaApproval.ApprovalID, confirmer)
+		assert.ErrorIs(t, err, ErrSubmissionNotInReview)
+
+		pending, err := service.GetApproval(ctx, schemaApproval.ApprovalID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ApprovalStatusPending, pending.Status)
+		assert.Nil(t, pending.DecidedBy)
+		require.NotNil(t, pending.LastError)
+		assert.Contains(t, *pending.LastError, ErrSubmissionNotInReview.Error())
+
+		rejected, err := service.RejectApproval(ctx, schemaApproval.ApprovalID, requester)
+		require.NoError(t, err)
+		assert.Equal(t, models.ApprovalStatusRejected, rejected.Status)
+		assert.Equal(t, requester.IdpUserID, *rejected.DecidedBy, "the requester can withdraw their request")
+		assert.Nil(t, rejected.LastError)
+
+		_, err = service.ConfirmApproval(ctx, schemaApproval.ApprovalID, confirmer)
+		assert.ErrorIs(t, err, ErrApprovalNotPending)
+	})
+
+	t.Run("Approvals expire", func(t *testing.T) {
+		expiring, err := service.RequestApproval(ctx, requester, models.ApprovalActionDeleteMember, "mem_provider", models.ApprovalPayload{})
+		require.NoError(t, err)
+		require.NoError(t, db.Model(&models.AdminApproval{}).Where("approval_id = ?", expiring.ApprovalID).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		expired, err := service.GetApproval(ctx, expiring.ApprovalID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ApprovalStatusExpired, expired.Status)
+
+		_, err = service.ConfirmApproval(ctx, expiring.ApprovalID, confirmer)
+		assert.ErrorIs(t, err, ErrApprovalNotPending)
+
+		renewed, err := service.RequestApproval(ctx, requester, models.ApprovalActionDeleteMember, "mem_provider", models.ApprovalPayload{})
+		require.NoError(t, err)
+		assert.NotEqual(t, expiring.ApprovalID, renewed.ApprovalID, "an expired approval is requested again")
+	})
+
+	t.Run("ListApprovals filters by status", func(t *testing.T) {
+		all, err := service.ListApprovals(ctx, "")
+		require.NoError(t, err)
+		assert.Len(t, all, 4)
+
+		for status, count := range map[models.ApprovalStatus]int{
+			models.ApprovalStatusPending:   1,
+			models.ApprovalStatusConfirmed: 1,
+			models.ApprovalStatusRejected:  1,
+			models.ApprovalStatusExpired:   1,
+		} {
+			approvals, err := service.ListApprovals(ctx, status)
+			require.NoError(t, err)
+			require.Len(t, approvals, count, status)
+			assert.Equal(t, status, approvals[0].Status)
+		}
+	})
+
+	_, err = service.GetApproval(ctx, "apr_missing")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultAttachmentScanWorkerBatchSize is how many pending attachments the worker scans per poll
+const DefaultAttachmentScanWorkerBatchSize = 20
+
+// AttachmentScanWorker periodically scans newly uploaded submission attachments for viruses
+type AttachmentScanWorker struct {
+	attachmentService *AttachmentService
+	// interval is how often the worker polls for pending attachments
+	interval time.Duration
+	// batchSize is the maximum number of attachments scanned per poll
+	batchSize int
+}
+
+// NewAttachmentScanWorker creates a new attachment scan worker
+func NewAttachmentScanWorker(attachmentService *AttachmentService, interval time.Duration) *AttachmentScanWorker {
+	return &AttachmentScanWorker{
+		attachmentService: attachmentService,
+		interval:          interval,
+		batchSize:         DefaultAttachmentScanWorkerBatchSize,
+	}
+}
+
+// Start runs the scan loop until the context is cancelled
+func (w *AttachmentScanWorker) Start(ctx context.Context) {
+	if w.interval <= 0 || w.attachmentService.scanner == nil {
+		slog.Info("Attachment scan worker disabled")
+		return
+	}
+
+	slog.Info("Attachment scan worker started", "interval", w.interval, "batchSize", w.batchSize)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Attachment scan worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce scans the pending attachments and returns how many were scanned
+func (w *AttachmentScanWorker) RunOnce(ctx context.Context) int {
+	scanned, err := w.attachmentService.ScanPendingAttachments(ctx, w.batchSize)
+	if err != nil {
+		slog.Error("Failed to scan attachments", "error", err)
+	}
+	if scanned > 0 {
+		slog.Info("Scanned attachments", "count", scanned)
+	}
+	return scanned
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAttachmentsDisabled is returned when no attachment storage is configured
+	ErrAttachmentsDisabled = errors.New("attachment storage is not configured")
+	// ErrAttachmentTooLarge is returned when an uploaded file exceeds the maximum attachment size
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrAttachmentTypeNotAllowed is returned when an uploaded file is not a PDF, PNG, JPEG or DOCX file
+	ErrAttachmentTypeNotAllowed = errors.New("attachments must be PDF, PNG, JPEG or DOCX files")
+	// ErrAttachmentNotDownloadable is returned when an attachment is downloaded before it passed the virus scan
+	ErrAttachmentNotDownloadable = errors.New("attachment cannot be downloaded until it passes the virus scan")
+)
+
+const (
+	// DefaultAttachmentMaxSize is the default maximum size of an attachment, in bytes
+	DefaultAttachmentMaxSize = 10 << 20
+	// DefaultAttachmentURLTTL is how long download URLs are valid by default
+	DefaultAttachmentURLTTL = 5 * time.Minute
+	// MaxAttachmentScanAttempts is how many times an attachment is scanned before it is marked failed
+	MaxAttachmentScanAttempts = 5
+	// maxAttachmentFileNameLength is the length file names are truncated to
+	maxAttachmentFileNameLength = 255
+)
+
+// docxContentType is the media type of Word documents, which are sniffed as zip archives
+const docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// allowedAttachmentTypes are the sniffed media types attachments may have
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+}
+
+// AttachmentService stores documents attached to schema submissions. Files are kept in object storage
+// and scanned for viruses by the scan worker; they can only be downloaded, through short-lived signed
+// URLs, once the scan found them clean.
+type AttachmentService struct {
+	db      *gorm.DB
+	storage AttachmentStorage
+	// scanner is nil when no virus scanner is configured, in which case uploads are marked skipped
+	scanner VirusScanner
+	maxSize int64
+	urlTTL  time.Duration
+}
+
+// NewAttachmentService creates a new attachment service. storage may be nil, which disables
+// attachments, and scanner may be nil, which stores attachments without scanning them.
+func NewAttachmentService(db *gorm.DB, storage AttachmentStorage, scanner VirusScanner, maxSize int64, urlTTL time.Duration) *AttachmentService {
+	return &AttachmentService{
+		db:      db,
+		storage: storage,
+		scanner: scanner,
+		maxSize: maxSize,
+		urlTTL:  urlTTL,
+	}
+}
+
+// Enabled reports whether attachment storage is configured
+func (s *AttachmentService) Enabled() bool {
+	return s.storage != nil
+}
+
+// MaxSize returns the maximum size of an attachment, in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadAttachment stores the file read from content as an attachment of a submission. The file type
+// is detected from its content, not taken from the client.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, submissionID, uploadedBy string, category models.AttachmentCategory, fileName string, content io.Reader) (*models.SubmissionAttachmentResponse, error) {
+	if s.storage == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	if !category.IsValid() {
+		return nil, models.NewValidationError(nil, models.ValidationErrorInvalidValue, "category",
+			"category must be authorization_letter, data_sharing_mou or other")
+	}
+	fileName = sanitizeAttachmentFileName(fileName)
+	if fileName == "" {
+		return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "file", "file must have a name")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	if len(data) == 0 {
+		return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "file", "file must not be empty")
+	}
+	contentType, err := attachmentContentType(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentID := "att_" + uuid.New().String()
+	attachment := models.SubmissionAttachment{
+		AttachmentID: attachmentID,
+		SubmissionID: submissionID,
+		Category:     category,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		StorageKey:   "schema-submissions/" + submissionID + "/" + attachmentID,
+		ScanStatus:   models.AttachmentScanStatusPending,
+		UploadedBy:   uploadedBy,
+	}
+	if s.scanner == nil {
+		attachment.ScanStatus = models.AttachmentScanStatusSkipped
+	}
+
+	if err := s.storage.Put(ctx, attachment.StorageKey, contentType, data); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(&attachment).Error; err != nil {
+		if deleteErr := s.storage.Delete(ctx, attachment.StorageKey); deleteErr != nil {
+			slog.Error("Failed to delete orphaned attachment", "storageKey", attachment.StorageKey, "error", deleteErr)
+		}
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return attachmentResponseOf(attachment), nil
+}
+
+// ListAttachments returns the attachments of a submission, oldest first
+func (s *AttachmentService) ListAttachments(ctx context.Context, submissionID string) ([]models.SubmissionAttachmentResponse, error) {
+	var attachments []models.SubmissionAttachment
+	err := s.db.WithContext(ctx).Where("submission_id = ?", submissionID).
+		Order("created_at, attachment_id").Find(&attachments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	responses := make([]models.SubmissionAttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		responses[i] = *attachmentResponseOf(attachment)
+	}
+	return responses, nil
+}
+
+// GetDownloadURL returns a signed URL that downloads an attachment of a submission until it expires
+func (s *AttachmentService) GetDownloadURL(ctx context.Context, submissionID, attachmentID string) (*models.AttachmentDownloadResponse, error) {
+	if s.storage == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	attachment, err := s.getAttachment(ctx, submissionID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !attachment.ScanStatus.IsDownloadable() {
+		return nil, ErrAttachmentNotDownloadable
+	}
+
+	expiresAt := time.Now().Add(s.urlTTL)
+	url, err := s.storage.PresignGet(attachment.StorageKey, attachment.FileName, s.urlTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign download URL: %w", err)
+	}
+	return &models.AttachmentDownloadResponse{
+		URL:       url,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// DeleteAttachment deletes an attachment of a submission and its file. The row is kept if the file
+// cannot be deleted, so that the delete can be retried.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, submissionID, attachmentID string) error {
+	if s.storage == nil {
+		return ErrAttachmentsDisabled
+	}
+	attachment, err := s.getAttachment(ctx, submissionID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Delete(attachment).Error; err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
+
+// ScanPendingAttachments scans up to batchSize attachments waiting for a scan and returns how many
+// were scanned. An attachment that cannot be scanned stays pending until it has been tried
+// MaxAttachmentScanAttempts times, and is then marked failed.
+func (s *AttachmentService) ScanPendingAttachments(ctx context.Context, batchSize int) (int, error) {
+	if s.storage == nil || s.scanner == nil {
+		return 0, nil
+	}
+	var attachments []models.SubmissionAttachment
+	err := s.db.WithContext(ctx).Where("scan_status = ?", models.AttachmentScanStatusPending).
+		Order("created_at, attachment_id").Limit(batchSize).Find(&attachments).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending attachments: %w", err)
+	}
+
+	scanned := 0
+	for i := range attachments {
+		if ctx.Err() != nil {
+			return scanned, ctx.Err()
+		}
+		attachment := &attachments[i]
+		virus, err := s.scan(ctx, attachment)
+		now := time.Now()
+		updates := map[string]interface{}{
+			"scan_attempts": attachment.ScanAttempts + 1,
+			"updated_at":    now,
+		}
+		switch {
+		case err != nil:
+			slog.Warn("Failed to scan attachment", "attachmentId", attachment.AttachmentID, "attempt", attachment.ScanAttempts+1, "error", err)
+			updates["scan_result"] = err.Error()
+			if attachment.ScanAttempts+1 >= MaxAttachmentScanAttempts {
+				updates["scan_status"] = models.AttachmentScanStatusFailed
+				updates["scanned_at"] = now
+			}
+		case virus != "":
+			slog.Warn("Virus found in attachment", "attachmentId", attachment.AttachmentID, "submissionId", attachment.SubmissionID, "virus", virus)
+			updates["scan_status"] = models.AttachmentScanStatusInfected
+			updates["scan_result"] = virus
+			updates["scanned_at"] = now
+		default:
+			updates["scan_status"] = models.AttachmentScanStatusClean
+			updates["scan_result"] = nil
+			updates["scanned_at"] = now
+		}
+		if err := s.db.WithContext(ctx).Model(attachment).Updates(updates).Error; err != nil {
+			return scanned, fmt.Errorf("failed to record attachment scan: %w", err)
+		}
+		scanned++
+	}
+	return scanned, nil
+}
+
+// scan downloads an attachment and scans it for viruses
+func (s *AttachmentService) scan(ctx context.Context, attachment *models.SubmissionAttachment) (string, error) {
+	body, err := s.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	return s.scanner.Scan(ctx, body)
+}
+
+// getAttachment loads an attachment of a submission
+func (s *AttachmentService) getAttachment(ctx context.Context, submissionID, attachmentID string) (*models.SubmissionAttachment, error) {
+	var attachment models.SubmissionAttachment
+	err := s.db.WithContext(ctx).Where("submission_id = ?", submissionID).
+		First(&attachment, "attachment_id = ?", attachmentID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+func attachmentResponseOf(attachment models.SubmissionAttachment) *models.SubmissionAttachmentResponse {
+	response := &models.SubmissionAttachmentResponse{
+		AttachmentID: attachment.AttachmentID,
+		SubmissionID: attachment.SubmissionID,
+		Category:     attachment.Category,
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		ScanStatus:   attachment.ScanStatus,
+		ScanResult:   attachment.ScanResult,
+		UploadedBy:   attachment.UploadedBy,
+		CreatedAt:    attachment.CreatedAt.Format(time.RFC3339),
+	}
+	if attachment.ScannedAt != nil {
+		scannedAt := attachment.ScannedAt.Format(time.RFC3339)
+		response.ScannedAt = &scannedAt
+	}
+	return response
+}
+
+// attachmentContentType detects the media type of an attachment from its content. Word documents are
+// zip archives, so a zip is accepted as DOCX only when it is named as one and holds a Word document.
+func attachmentContentType(fileName string, data []byte) (string, error) {
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	if allowedAttachmentTypes[contentType] {
+		return contentType, nil
+	}
+	if contentType == "application/zip" && strings.EqualFold(path.Ext(fileName), ".docx") &&
+		bytes.Contains(data, []byte("word/document.xml")) {
+		return docxContentType, nil
+	}
+	return "", models.NewValidationError(ErrAttachmentTypeNotAllowed, models.ValidationErrorInvalidValue, "file",
+		ErrAttachmentTypeNotAllowed.Error())
+}
+
+// sanitizeAttachmentFileName keeps only the base name of an uploaded file, without control characters
+func sanitizeAttachmentFileName(fileName string) string {
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	fileName = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, fileName)
+	fileName = strings.TrimSpace(fileName)
+	if fileName == "." || fileName == "/" {
+		return ""
+	}
+	if len(fileName) > maxAttachmentFileNameLength {
+		fileName = strings.ToValidUTF8(fileName[:maxAttachmentFileNameLength], "")
+	}
+	return fileName
+}
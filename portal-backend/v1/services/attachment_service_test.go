@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAttachmentStorage keeps attachments in memory
+type memoryAttachmentStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryAttachmentStorage() *memoryAttachmentStorage {
+	return &memoryAttachmentStorage{objects: map[string][]byte{}}
+}
+
+func (m *memoryAttachmentStorage) Put(ctx context.Context, key, contentType string, content []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = content
+	return nil
+}
+
+func (m *memoryAttachmentStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	content, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", key)
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (m *memoryAttachmentStorage) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memoryAttachmentStorage) PresignGet(key, fileName string, ttl time.Duration) (string, error) {
+	return "https://storage.example.com/" + key + "?expires=" + ttl.String(), nil
+}
+
+// fakeVirusScanner reports files containing "EICAR" as infected, and fails while err is set
+type fakeVirusScanner struct {
+	err error
+}
+
+func (f *fakeVirusScanner) Scan(ctx context.Context, r io.Reader) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Contains(content, []byte("EICAR")) {
+		return "Eicar-Test-Signature", nil
+	}
+	return "", nil
+}
+
+// testPDF is the start of a PDF file, enough for its type to be detected
+var testPDF = []byte("%PDF-1.7\n1 0 obj\n<<>>\nendobj\n")
+
+func TestAttachmentService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	storage := newMemoryAttachmentStorage()
+	scanner := &fakeVirusScanner{}
+	service := NewAttachmentService(db, storage, scanner, 1024, time.Minute)
+	ctx := context.Background()
+
+	attachment, err := service.UploadAttachment(ctx, "sub_1", "idp-user-1", models.AttachmentCategoryAuthorizationLetter,
+		`C:\Users\provider\letter.pdf`, bytes.NewReader(testPDF))
+	require.NoError(t, err)
+	assert.Equal(t, "letter.pdf", attachment.FileName)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, int64(len(testPDF)), attachment.Size)
+	assert.Equal(t, models.AttachmentScanStatusPending, attachment.ScanStatus)
+	assert.Len(t, storage.objects, 1)
+
+	t.Run("Rejects invalid uploads", func(t *testing.T) {
+		_, err := service.UploadAttachment(ctx, "sub_1", "idp-user-1", "contract", "letter.pdf", bytes.NewReader(testPDF))
+		assert.Error(t, err)
+
+		_, err = service.UploadAttachment(ctx, "sub_1", "idp-user-1", models.AttachmentCategoryOther, "script.pdf",
+			strings.NewReader("#!/bin/sh\nrm -rf /\n"))
+		assert.ErrorIs(t, err, ErrAttachmentTypeNotAllowed)
+
+		_, err = service.UploadAttachment(ctx, "sub_1", "idp-user-1", models.AttachmentCategoryOther, "large.pdf",
+			bytes.NewReader(append(testPDF, make([]byte, 1024)...)))
+		assert.ErrorIs(t, err, ErrAttachmentTooLarge)
+		assert.Len(t, storage.objects, 1)
+	})
+
+	t.Run("Downloads only scanned attachments", func(t *testing.T) {
+		_, err := service.GetDownloadURL(ctx, "sub_1", attachment.AttachmentID)
+		assert.ErrorIs(t, err, ErrAttachmentNotDownloadable)
+
+		scanned, err := service.ScanPendingAttachments(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, scanned)
+
+		download, err := service.GetDownloadURL(ctx, "sub_1", attachment.AttachmentID)
+		require.NoError(t, err)
+		assert.Contains(t, download.URL, attachment.AttachmentID)
+		assert.NotEmpty(t, download.ExpiresAt)
+
+		_, err = service.GetDownloadURL(ctx, "sub_2", attachment.AttachmentID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	t.Run("Marks infected attachments", func(t *testing.T) {
+		infected, err := service.UploadAttachment(ctx, "sub_1", "idp-user-1", models.AttachmentCategoryOther, "mou.pdf",
+			bytes.NewReader(append(append([]byte{}, testPDF...), "EICAR"...)))
+		require.NoError(t, err)
+		_, err = service.ScanPendingAttachments(ctx, 10)
+		require.NoError(t, err)
+
+		attachments, err := service.ListAttachments(ctx, "sub_1")
+		require.NoError(t, err)
+		require.Len(t, attachments, 2)
+		assert.Equal(t, models.AttachmentScanStatusClean, attachments[0].ScanStatus)
+		assert.Equal(t, models.AttachmentScanStatusInfected, attachments[1].ScanStatus)
+		assert.Equal(t, "Eicar-Test-Signature", *attachments[1].ScanResult)
+
+		_, err = service.GetDownloadURL(ctx, "sub_1", infected.AttachmentID)
+		assert.ErrorIs(t, err, ErrAttachmentNotDownloadable)
+	})
+
+	t.Run("Marks attachments failed after every retry", func(t *testing.T) {
+		scanner.err = errors.New("clamd unavailable")
+		defer func() { scanner.err = nil }()
+		failing, err := service.UploadAttachment(ctx, "sub_3", "idp-user-1", models.AttachmentCategoryOther, "mou.pdf", bytes.NewReader(testPDF))
+		require.NoError(t, err)
+
+		for attempt := 1; attempt <= MaxAttachmentScanAttempts; attempt++ {
+			_, err := service.ScanPendingAttachments(ctx, 10)
+			require.NoError(t, err)
+			attachments, err := service.ListAttachments(ctx, "sub_3")
+			require.NoError(t, err)
+			expected := models.AttachmentScanStatusPending
+			if attempt == MaxAttachmentScanAttempts {
+				expected = models.AttachmentScanStatusFailed
+			}
+			assert.Equal(t, expected, attachments[0].ScanStatus, "attempt %d", attempt)
+		}
+
+		scanned, err := service.ScanPendingAttachments(ctx, 10)
+		require.NoError(t, err)
+		assert.Zero(t, scanned)
+		_, err = service.GetDownloadURL(ctx, "sub_3", failing.AttachmentID)
+		assert.ErrorIs(t, err, ErrAttachmentNotDownloadable)
+	})
+
+	t.Run("Deletes the attachment and its file", func(t *testing.T) {
+		require.NoError(t, service.DeleteAttachment(ctx, "sub_1", attachment.AttachmentID))
+		_, ok := storage.objects["schema-submissions/sub_1/"+attachment.AttachmentID]
+		assert.False(t, ok)
+		assert.ErrorIs(t, service.DeleteAttachment(ctx, "sub_1", attachment.AttachmentID), ErrResourceNotFound)
+	})
+
+	t.Run("Skips scanning without a scanner", func(t *testing.T) {
+		unscanned := NewAttachmentService(db, storage, nil, 1024, time.Minute)
+		attachment, err := unscanned.UploadAttachment(ctx, "sub_4", "idp-user-1", models.AttachmentCategoryOther, "scan.png",
+			bytes.NewReader([]byte("\x89PNG\r\n\x1a\n0000")))
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", attachment.ContentType)
+		assert.Equal(t, models.AttachmentScanStatusSkipped, attachment.ScanStatus)
+		_, err = unscanned.GetDownloadURL(ctx, "sub_4", attachment.AttachmentID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Is disabled without storage", func(t *testing.T) {
+		disabled := NewAttachmentService(db, nil, nil, 1024, time.Minute)
+		_, err := disabled.UploadAttachment(ctx, "sub_1", "idp-user-1", models.AttachmentCategoryOther, "letter.pdf", bytes.NewReader(testPDF))
+		assert.ErrorIs(t, err, ErrAttachmentsDisabled)
+	})
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gov-dx-sandbox/shared/s3"
+)
+
+// AttachmentStorage stores the files attached to submissions
+type AttachmentStorage interface {
+	Put(ctx context.Context, key, contentType string, content []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a URL that downloads the object as fileName, without credentials, until ttl passes
+	PresignGet(key, fileName string, ttl time.Duration) (string, error)
+}
+
+// S3AttachmentStorage stores attachments in a bucket of an S3-compatible object store, such as AWS
+// S3 or MinIO
+type S3AttachmentStorage struct {
+	client *s3.Client
+}
+
+// NewS3AttachmentStorage creates an S3 attachment storage for the bucket served at endpoint
+func NewS3AttachmentStorage(endpoint, bucket, region, accessKey, secretKey string, pathStyle bool) (*S3AttachmentStorage, error) {
+	client, err := s3.NewClient(s3.Config{
+		Endpoint:        endpoint,
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		PathStyle:       pathStyle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &S3AttachmentStorage{client: client}, nil
+}
+
+// NewS3AttachmentStorageFromEnv creates an S3 attachment storage from ATTACHMENT_STORAGE_BUCKET,
+// ATTACHMENT_STORAGE_ENDPOINT, ATTACHMENT_STORAGE_REGION, ATTACHMENT_STORAGE_ACCESS_KEY,
+// ATTACHMENT_STORAGE_SECRET_KEY and ATTACHMENT_STORAGE_PATH_STYLE. It returns nil when
+// ATTACHMENT_STORAGE_BUCKET is not set, which disables attachments. Without an endpoint AWS S3 is
+// used; path-style addressing defaults to on for any other endpoint.
+func NewS3AttachmentStorageFromEnv() (*S3AttachmentStorage, error) {
+	bucket := os.Getenv("ATTACHMENT_STORAGE_BUCKET")
+	if bucket == "" {
+		return nil, nil
+	}
+	accessKey := os.Getenv("ATTACHMENT_STORAGE_ACCESS_KEY")
+	secretKey := os.Getenv("ATTACHMENT_STORAGE_SECRET_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("ATTACHMENT_STORAGE_ACCESS_KEY and ATTACHMENT_STORAGE_SECRET_KEY environment variables must be set")
+	}
+	region := os.Getenv("ATTACHMENT_STORAGE_REGION")
+	if region == "" {
+		region = s3.DefaultRegion
+	}
+	endpoint := os.Getenv("ATTACHMENT_STORAGE_ENDPOINT")
+	pathStyle := endpoint != ""
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	if value := os.Getenv("ATTACHMENT_STORAGE_PATH_STYLE"); value != "" {
+		var err error
+		pathStyle, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ATTACHMENT_STORAGE_PATH_STYLE: %w", err)
+		}
+	}
+	return NewS3AttachmentStorage(endpoint, bucket, region, accessKey, secretKey, pathStyle)
+}
+
+// Put uploads an object, replacing any object with the same key
+func (s *S3AttachmentStorage) Put(ctx context.Context, key, contentType string, content []byte) error {
+	if err := s.client.Put(ctx, key, contentType, content); err != nil {
+		return fmt.Errorf("failed to upload attachment: %w", err)
+	}
+	return nil
+}
+
+// Get downloads an object; the caller closes the returned body
+func (s *S3AttachmentStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.client.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment: %w", err)
+	}
+	return body, nil
+}
+
+// Delete removes an object. Deleting a missing object succeeds, as it does in S3.
+func (s *S3AttachmentStorage) Delete(ctx context.Context, key string) error {
+	if err := s.client.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
+
+// PresignGet returns a presigned GET URL. When fileName is set, the response asks browsers to save
+// the object under that name instead of displaying it.
+func (s *S3AttachmentStorage) PresignGet(key, fileName string, ttl time.Duration) (string, error) {
+	return s.client.PresignGet(key, fileName, ttl)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3AttachmentStorage(t *testing.T) {
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") ||
+			r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(body)
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte("<Error><Code>NoSuchKey</Code></Error>"))
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	storage, err := NewS3AttachmentStorage(server.URL, "attachments", "us-east-1", "access", "secret", true)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, "schema-submissions/sub_1/att_1", "application/pdf", testPDF))
+	assert.Contains(t, objects, "/attachments/schema-submissions/sub_1/att_1")
+
+	body, err := storage.Get(ctx, "schema-submissions/sub_1/att_1")
+	require.NoError(t, err)
+	content, err := io.ReadAll(body)
+	body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, testPDF, content)
+
+	require.NoError(t, storage.Delete(ctx, "schema-submissions/sub_1/att_1"))
+	_, err = storage.Get(ctx, "schema-submissions/sub_1/att_1")
+	assert.ErrorContains(t, err, "NoSuchKey")
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidAuditEventQuery is returned when the audit service rejects the filters of an audit event query
+	ErrInvalidAuditEventQuery = errors.New("invalid audit event query")
+	// ErrAuditEventScopeDenied is returned when a member selects the audit events of an entity they do not own
+	ErrAuditEventScopeDenied = errors.New("access to audit events denied")
+)
+
+// auditEventFilters are the filter, sort and pagination parameters of the audit service's log list
+// that are passed through; any other parameter is dropped
+var auditEventFilters = []string{
+	"traceId", "correlationId", "eventType", "eventAction", "status", "actorType", "actorId",
+	"consumerAppId", "targetType", "targetId", "startTime", "endTime", "q", "sortOrder", "cursor",
+	"limit", "offset",
+}
+
+// AuditEventService lists the audit service's events to portal users. Members only see the events of
+// the entities they own; the scope is resolved here, so member portals never query the audit service
+// themselves.
+type AuditEventService struct {
+	db *gorm.DB
+	// baseURL is the endpoint of the audit service
+	baseURL string
+	// token is the bearer token for the audit service's read APIs, which require an admin or system user
+	token string
+	// HTTPClient is used to make requests to the audit service
+	HTTPClient *http.Client
+}
+
+// NewAuditEventService creates a new audit event service; token may be empty when the audit service's read APIs are unauthenticated
+func NewAuditEventService(db *gorm.DB, baseURL, token string) *AuditEventService {
+	return &AuditEventService{
+		db:         db,
+		baseURL:    baseURL,
+		token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetMemberScope returns the entity IDs the audit events of a member are recorded under: their member
+// and IDP user IDs, and the IDs and client IDs of the applications and the IDs of the schemas they or
+// their organization own. Deleted resources are included, so their history stays visible.
+func (s *AuditEventService) GetMemberScope(ctx context.Context, memberID string) ([]string, error) {
+	var member models.Member
+	if err := s.db.WithContext(ctx).Select("member_id", "idp_user_id", "organization_id").
+		First(&member, "member_id = ?", memberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	owned := func(model interface{}) *gorm.DB {
+		query := s.db.WithContext(ctx).Unscoped().Model(model).Where("member_id = ?", memberID)
+		if member.OrganizationID != nil {
+			query = query.Or("organization_id = ?", *member.OrganizationID)
+		}
+		return query
+	}
+	var applications []models.Application
+	if err := owned(&models.Application{}).Select("application_id", "idp_client_id").Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("failed to get applications: %w", err)
+	}
+	var schemaIDs []string
+	if err := owned(&models.Schema{}).Pluck("schema_id", &schemaIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get schemas: %w", err)
+	}
+
+	scope := append([]string{member.MemberID, member.IdpUserID}, schemaIDs...)
+	for _, application := range applications {
+		scope = append(scope, application.ApplicationID)
+		if application.IdpClientID != nil && *application.IdpClientID != "" {
+			scope = append(scope, *application.IdpClientID)
+		}
+	}
+	scope = slices.DeleteFunc(scope, func(id string) bool { return id == "" })
+	slices.Sort(scope)
+	return slices.Compact(scope), nil
+}
+
+// ListAuditEvents lists the audit events matching the filters of query. A nil scope lists the events of
+// every entity; otherwise the events are confined to the partitions of the scope's entities, which the
+// tenantId parameter of query may narrow down to some of them.
+func (s *AuditEventService) ListAuditEvents(ctx context.Context, query url.Values, scope []string) (*models.AuditEventPage, error) {
+	auditQuery := url.Values{}
+	for _, name := range auditEventFilters {
+		if value := strings.TrimSpace(query.Get(name)); value != "" {
+			auditQuery.Set(name, value)
+		}
+	}
+	tenantIDs := slices.DeleteFunc(slices.Clone(query["tenantId"]), func(id string) bool { return strings.TrimSpace(id) == "" })
+	if scope != nil {
+		for _, tenantID := range tenantIDs {
+			if !slices.Contains(scope, tenantID) {
+				return nil, fmt.Errorf("%w: tenantId %s is not one of your entities", ErrAuditEventScopeDenied, tenantID)
+			}
+		}
+		if len(tenantIDs) == 0 {
+			tenantIDs = scope
+		}
+	}
+	auditQuery["tenantId"] = tenantIDs
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/api/audit-logs?"+auditQuery.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if s.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	requestid.SetHeader(httpReq)
+
+	resp, err := s.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAuditServiceUnavailable, err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			slog.Error("failed to close response body", "error", err)
+		}
+	}(resp.Body)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response body: %w", ErrAuditServiceUnavailable, err)
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		var errorResponse struct {
+			Error   string `json:"error"`
+			Details string `json:"details"`
+		}
+		_ = json.Unmarshal(respBody, &errorResponse)
+		if errorResponse.Details != "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAuditEventQuery, errorResponse.Details)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAuditEventQuery, errorResponse.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("Audit service returned error", "status", resp.StatusCode, "body", string(respBody))
+		return nil, fmt.Errorf("%w: audit service returned status %d", ErrAuditServiceUnavailable, resp.StatusCode)
+	}
+
+	var logs struct {
+		Logs       []json.RawMessage `json:"logs"`
+		Total      int64             `json:"total"`
+		Limit      int               `json:"limit"`
+		Offset     int               `json:"offset"`
+		NextCursor *string           `json:"nextCursor"`
+	}
+	if err := json.Unmarshal(respBody, &logs); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse response: %w", ErrAuditServiceUnavailable, err)
+	}
+	page := &models.AuditEventPage{
+		Events:     logs.Logs,
+		Total:      logs.Total,
+		Limit:      logs.Limit,
+		Offset:     logs.Offset,
+		NextCursor: logs.NextCursor,
+	}
+	if page.Events == nil {
+		page.Events = []json.RawMessage{}
+	}
+	return page, nil
+}
+
+// ListSecurityEvents lists the SECURITY_EVENTs of a member: sign-ins, token issuance and denied requests
+// of their IDP user, and changes to the credentials of their applications and to their sessions, whoever
+// made them. The eventAction, startTime, endTime, cursor and limit filters of query apply.
+func (s *AuditEventService) ListSecurityEvents(ctx context.Context, memberID string, query url.Values) (*models.AuditEventPage, error) {
+	scope, err := s.GetMemberScope(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	securityQuery := url.Values{"eventType": {models.SecurityEventType}}
+	for _, name := range []string{"eventAction", "status", "startTime", "endTime", "sortOrder", "cursor", "limit", "offset"} {
+		if value := query.Get(name); value != "" {
+			securityQuery.Set(name, value)
+		}
+	}
+	return s.ListAuditEvents(ctx, securityQuery, scope)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEventService_GetMemberScope(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	clientID := "client-app-1"
+	require.NoError(t, db.Model(&models.Application{}).Where("application_id = ?", "app_1").Update("idp_client_id", clientID).Error)
+	service := NewAuditEventService(db, "http://localhost:3001", "")
+	ctx := context.Background()
+
+	scope, err := service.GetMemberScope(ctx, "mem_consumer")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app_1", clientID, "idp-consumer", "mem_consumer"}, scope)
+
+	scope, err = service.GetMemberScope(ctx, "mem_provider")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"idp-provider", "mem_provider", "sch_1"}, scope)
+
+	// Deleted applications stay in scope, so their history remains visible
+	require.NoError(t, db.Delete(&models.Application{}, "application_id = ?", "app_1").Error)
+	scope, err = service.GetMemberScope(ctx, "mem_consumer")
+	require.NoError(t, err)
+	assert.Contains(t, scope, "app_1")
+
+	_, err = service.GetMemberScope(ctx, "mem_missing")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestAuditEventService_ListAuditEvents(t *testing.T) {
+	var received url.Values
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/audit-logs", r.URL.Path)
+		assert.Equal(t, "Bearer audit-token", r.Header.Get("Authorization"))
+		received = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		switch status {
+		case http.StatusOK:
+			_, _ = w.Write([]byte(`{"logs": [{"id": "log-1"}, {"id": "log-2"}], "total": 5, "limit": 2, "offset": 0, "nextCursor": "c2"}`))
+		case http.StatusBadRequest:
+			_, _ = w.Write([]byte(`{"error": "Invalid query parameters", "details": "invalid startTime"}`))
+		}
+	}))
+	defer server.Close()
+
+	service := NewAuditEventService(nil, server.URL, "audit-token")
+	ctx := context.Background()
+	scope := []string{"app_1", "mem_consumer"}
+
+	t.Run("Passes filters and injects the scope", func(t *testing.T) {
+		query := url.Values{"eventType": {"DATA_REQUEST"}, "limit": {"2"}, "unknown": {"x"}}
+		page, err := service.ListAuditEvents(ctx, query, scope)
+		require.NoError(t, err)
+		assert.Len(t, page.Events, 2)
+		assert.Equal(t, int64(5), page.Total)
+		require.NotNil(t, page.NextCursor)
+		assert.Equal(t, "c2", *page.NextCursor)
+
+		assert.Equal(t, "DATA_REQUEST", received.Get("eventType"))
+		assert.Equal(t, "2", received.Get("limit"))
+		assert.False(t, received.Has("unknown"))
+		assert.Equal(t, scope, received["tenantId"])
+	})
+
+	t.Run("Narrows the scope", func(t *testing.T) {
+		_, err := service.ListAuditEvents(ctx, url.Values{"tenantId": {"app_1"}}, scope)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app_1"}, received["tenantId"])
+
+		_, err = service.ListAuditEvents(ctx, url.Values{"tenantId": {"app_other"}}, scope)
+		assert.ErrorIs(t, err, ErrAuditEventScopeDenied)
+	})
+
+	t.Run("Unscoped", func(t *testing.T) {
+		_, err := service.ListAuditEvents(ctx, url.Values{}, nil)
+		require.NoError(t, err)
+		assert.False(t, received.Has("tenantId"))
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		status = http.StatusBadRequest
+		defer func() { status = http.StatusOK }()
+		_, err := service.ListAuditEvents(ctx, url.Values{"startTime": {"yesterday"}}, scope)
+		assert.ErrorIs(t, err, ErrInvalidAuditEventQuery)
+		assert.Contains(t, err.Error(), "invalid startTime")
+	})
+
+	t.Run("Audit service unavailable", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		defer func() { status = http.StatusOK }()
+		_, err := service.ListAuditEvents(ctx, url.Values{}, scope)
+		assert.ErrorIs(t, err, ErrAuditServiceUnavailable)
+	})
+}
+
+func TestAuditEventService_ListSecurityEvents(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		_, _ = w.Write([]byte(`{"logs": [{"id": "log-1"}], "total": 1, "limit": 50, "offset": 0}`))
+	}))
+	defer server.Close()
+
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewAuditEventService(db, server.URL, "")
+
+	query := url.Values{"eventType": {"DATA_REQUEST"}, "eventAction": {"LOGIN"}, "actorId": {"someone-else"}, "limit": {"50"}}
+	page, err := service.ListSecurityEvents(context.Background(), "mem_consumer", query)
+	require.NoError(t, err)
+	assert.Len(t, page.Events, 1)
+	assert.Equal(t, models.SecurityEventType, received.Get("eventType"), "only security events are listed")
+	assert.Equal(t, "LOGIN", received.Get("eventAction"))
+	assert.Equal(t, "50", received.Get("limit"))
+	assert.False(t, received.Has("actorId"))
+	assert.Equal(t, []string{"app_1", "idp-consumer", "mem_consumer"}, received["tenantId"])
+
+	_, err = service.ListSecurityEvents(context.Background(), "mem_missing", url.Values{})
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidBulkRequest is returned for a bulk operation with no items, too many items or invalid parameters
+var ErrInvalidBulkRequest = errors.New("invalid bulk request")
+
+// Bulk operation names, reported in the response and recorded as the audit event's operation
+const (
+	BulkOperationReviewSubmissions  = "review-submissions"
+	BulkOperationExpireApplications = "expire-applications"
+	BulkOperationReassign           = "reassign"
+	BulkOperationImportMembers      = "import-members"
+)
+
+// bulkResourceTable describes the table holding a bulk resource type
+type bulkResourceTable struct {
+	model    func() interface{}
+	idColumn string
+	// revisioned tables carry a revision that every change increments
+	revisioned bool
+}
+
+var bulkResourceTables = map[models.BulkResourceType]bulkResourceTable{
+	models.BulkResourceSchema:                {func() interface{} { return &models.Schema{} }, "schema_id", true},
+	models.BulkResourceSchemaSubmission:      {func() interface{} { return &models.SchemaSubmission{} }, "submission_id", false},
+	models.BulkResourceApplication:           {func() interface{} { return &models.Application{} }, "application_id", true},
+	models.BulkResourceApplicationSubmission: {func() interface{} { return &models.ApplicationSubmission{} }, "submission_id", false},
+}
+
+// BulkService applies admin operations to many resources in one call. Each item is processed on its
+// own, so one failing item is reported in its result without undoing the others.
+type BulkService struct {
+	db              *gorm.DB
+	reviewService   *SubmissionReviewService
+	approvalService *ApprovalService
+}
+
+// NewBulkService creates a new bulk operation service
+func NewBulkService(db *gorm.DB, reviewService *SubmissionReviewService, approvalService *ApprovalService) *BulkService {
+	return &BulkService{db: db, reviewService: reviewService, approvalService: approvalService}
+}
+
+// ReviewSubmissions records reviewerID's decision on the current review step of each submission.
+// Approving moves a submission to its next step, or approves it after the last one. Decisions that
+// need a second admin's confirmation fail; they have to be requested one at a time.
+func (s *BulkService) ReviewSubmissions(ctx context.Context, reviewerID string, req *models.BulkReviewSubmissionsRequest) (*models.BulkOperationResponse, error) {
+	if err := checkBulkItemCount(len(req.Submissions)); err != nil {
+		return nil, err
+	}
+	if !req.Decision.IsValid() {
+		return nil, fmt.Errorf("%w: decision must be approve or reject", ErrInvalidBulkRequest)
+	}
+
+	response := &models.BulkOperationResponse{Operation: BulkOperationReviewSubmissions}
+	for _, ref := range req.Submissions {
+		result := models.BulkItemResult{Type: ref.Type, ID: ref.ID}
+		err := func() error {
+			submissionType, err := bulkSubmissionType(ref.Type)
+			if err != nil {
+				return err
+			}
+			result.PreviousStatus, err = submissionStatus(s.db.WithContext(ctx), submissionType, ref.ID)
+			if err != nil {
+				return err
+			}
+			requiresApproval, err := s.approvalService.RequiresReviewApproval(ctx, submissionType, ref.ID, req.Decision)
+			if err != nil {
+				return err
+			}
+			if requiresApproval {
+				return ErrApprovalRequired
+			}
+			review, err := s.reviewService.Review(ctx, submissionType, ref.ID, reviewerID, &models.SubmissionReviewDecisionRequest{
+				Decision: req.Decision,
+				Comment:  req.Comment,
+			})
+			if err != nil {
+				return err
+			}
+			result.Status = review.Status
+			return nil
+		}()
+		addBulkResult(response, result, err)
+	}
+
+	slog.Info("Bulk submission review completed",
+		"reviewerID", reviewerID,
+		"decision", req.Decision,
+		"succeeded", response.Succeeded,
+		"failed", response.Failed)
+	return response, nil
+}
+
+// ExpireApplications deprecates each application. Applications that are already deprecated are
+// reported as failed so that the caller can tell them apart.
+func (s *BulkService) ExpireApplications(ctx context.Context, req *models.BulkExpireApplicationsRequest) (*models.BulkOperationResponse, error) {
+	if err := checkBulkItemCount(len(req.ApplicationIDs)); err != nil {
+		return nil, err
+	}
+
+	response := &models.BulkOperationResponse{Operation: BulkOperationExpireApplications}
+	for _, applicationID := range req.ApplicationIDs {
+		result := models.BulkItemResult{Type: models.BulkResourceApplication, ID: applicationID}
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var application models.Application
+			if err := tx.Select("application_id", "version").First(&application, "application_id = ?", applicationID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrResourceNotFound
+				}
+				return fmt.Errorf("failed to get application: %w", err)
+			}
+			result.PreviousStatus = application.Version
+			if application.Version == string(models.DeprecatedVersion) {
+				return fmt.Errorf("application is already %s", models.DeprecatedVersion)
+			}
+			if err := tx.Model(&models.Application{}).Where("application_id = ?", applicationID).Updates(map[string]interface{}{
+				"version":    string(models.DeprecatedVersion),
+				"revision":   gorm.Expr("revision + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to expire application: %w", err)
+			}
+			result.Status = string(models.DeprecatedVersion)
+			return nil
+		})
+		addBulkResult(response, result, err)
+	}
+
+	slog.Info("Bulk application expiry completed", "succeeded", response.Succeeded, "failed", response.Failed)
+	return response, nil
+}
+
+// ReassignResources moves schemas, applications and submissions from one member to another. The
+// resources join the organization of the new member, or leave their organization if it has none.
+func (s *BulkService) ReassignResources(ctx context.Context, req *models.BulkReassignRequest) (*models.BulkOperationResponse, error) {
+	if req.FromMemberID == "" || req.ToMemberID == "" {
+		return nil, fmt.Errorf("%w: fromMemberId and toMemberId are required", ErrInvalidBulkRequest)
+	}
+	if req.FromMemberID == req.ToMemberID {
+		return nil, fmt.Errorf("%w: fromMemberId and toMemberId must differ", ErrInvalidBulkRequest)
+	}
+	if len(req.Resources) > models.MaxBulkItems {
+		return nil, fmt.Errorf("%w: at most %d items are allowed", ErrInvalidBulkRequest, models.MaxBulkItems)
+	}
+
+	db := s.db.WithContext(ctx)
+	var from models.Member
+	if err := db.Select("member_id").First(&from, "member_id = ?", req.FromMemberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+	var to models.Member
+	if err := db.Select("member_id", "organization_id").First(&to, "member_id = ?", req.ToMemberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	resources := req.Resources
+	if len(resources) == 0 {
+		var err error
+		if resources, err = s.memberResources(ctx, req.FromMemberID); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &models.BulkOperationResponse{Operation: BulkOperationReassign, Results: []models.BulkItemResult{}}
+	for _, ref := range resources {
+		result := models.BulkItemResult{Type: ref.Type, ID: ref.ID}
+		err := func() error {
+			table, ok := bulkResourceTables[ref.Type]
+			if !ok {
+				return fmt.Errorf("unknown resource type: %s", ref.Type)
+			}
+			updates := map[string]interface{}{
+				"member_id":       to.MemberID,
+				"organization_id": to.OrganizationID,
+				"updated_at":      time.Now(),
+			}
+			if table.revisioned {
+				updates["revision"] = gorm.Expr("revision + 1")
+			}
+			update := db.Model(table.model()).
+				Where(table.idColumn+" = ? AND member_id = ?", ref.ID, req.FromMemberID).
+				Updates(updates)
+			if update.Error != nil {
+				return fmt.Errorf("failed to reassign resource: %w", update.Error)
+			}
+			if update.RowsAffected == 0 {
+				return fmt.Errorf("%w: not owned by member %s", ErrResourceNotFound, req.FromMemberID)
+			}
+			return nil
+		}()
+		addBulkResult(response, result, err)
+	}
+
+	slog.Info("Bulk reassignment completed",
+		"fromMemberID", req.FromMemberID,
+		"toMemberID", req.ToMemberID,
+		"succeeded", response.Succeeded,
+		"failed", response.Failed)
+	return response, nil
+}
+
+// memberResources lists the live resources a member owns, in bulkResourceOrder
+func (s *BulkService) memberResources(ctx context.Context, memberID string) ([]models.BulkResourceRef, error) {
+	var resources []models.BulkResourceRef
+	for _, resourceType := range bulkResourceOrder {
+		table := bulkResourceTables[resourceType]
+		var ids []string
+		if err := s.db.WithContext(ctx).Model(table.model()).Where("member_id = ?", memberID).
+			Order(table.idColumn).Pluck(table.idColumn, &ids).Error; err != nil {
+			return nil, fmt.Errorf("failed to list member resources: %w", err)
+		}
+		for _, id := range ids {
+			resources = append(resources, models.BulkResourceRef{Type: resourceType, ID: id})
+		}
+	}
+	return resources, nil
+}
+
+// bulkResourceOrder is the order in which a member's resources are reassigned
+var bulkResourceOrder = []models.BulkResourceType{
+	models.BulkResourceSchema,
+	models.BulkResourceSchemaSubmission,
+	models.BulkResourceApplication,
+	models.BulkResourceApplicationSubmission,
+}
+
+// bulkSubmissionType maps a bulk submission reference to the review workflow's submission type
+func bulkSubmissionType(resourceType models.BulkResourceType) (models.SubmissionType, error) {
+	switch resourceType {
+	case models.BulkResourceSchemaSubmission:
+		return models.SubmissionTypeSchema, nil
+	case models.BulkResourceApplicationSubmission:
+		return models.SubmissionTypeApplication, nil
+	}
+	return "", fmt.Errorf("not a submission type: %s", resourceType)
+}
+
+func checkBulkItemCount(count int) error {
+	if count == 0 {
+		return fmt.Errorf("%w: no items given", ErrInvalidBulkRequest)
+	}
+	if count > models.MaxBulkItems {
+		return fmt.Errorf("%w: at most %d items are allowed", ErrInvalidBulkRequest, models.MaxBulkItems)
+	}
+	return nil
+}
+
+// addBulkResult records the outcome of an item, failed when err is not nil
+func addBulkResult(response *models.BulkOperationResponse, result models.BulkItemResult, err error) {
+	if err != nil {
+		result.Error = err.Error()
+		response.Failed++
+	} else {
+		result.Success = true
+		response.Succeeded++
+	}
+	response.Results = append(response.Results, result)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	reviewService := NewSubmissionReviewService(db, nil, nil, nil, nil, DefaultReviewWorkflow)
+	service := NewBulkService(db, reviewService, NewApprovalService(db, nil, time.Hour, nil, reviewService, nil))
+
+	t.Run("ReviewSubmissions", func(t *testing.T) {
+		_, err := service.ReviewSubmissions(ctx, "admin", &models.BulkReviewSubmissionsRequest{Decision: models.ReviewDecisionApprove})
+		assert.ErrorIs(t, err, ErrInvalidBulkRequest)
+		_, err = service.ReviewSubmissions(ctx, "admin", &models.BulkReviewSubmissionsRequest{
+			Submissions: []models.BulkResourceRef{{Type: models.BulkResourceSchemaSubmission, ID: "sub_schema"}},
+			Decision:    "maybe",
+		})
+		assert.ErrorIs(t, err, ErrInvalidBulkRequest)
+
+		response, err := service.ReviewSubmissions(ctx, "admin", &models.BulkReviewSubmissionsRequest{
+			Submissions: []models.BulkResourceRef{
+				{Type: models.BulkResourceSchemaSubmission, ID: "sub_schema"},
+				{Type: models.BulkResourceApplicationSubmission, ID: "sub_app"},
+				{Type: models.BulkResourceApplicationSubmission, ID: "sub_missing"},
+				{Type: models.BulkResourceSchema, ID: "sch_1"},
+			},
+			Decision: models.ReviewDecisionApprove,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, BulkOperationReviewSubmissions, response.Operation)
+		assert.Equal(t, 2, response.Succeeded)
+		assert.Equal(t, 2, response.Failed)
+		require.Len(t, response.Results, 4)
+		assert.Equal(t, string(models.StatusPending), response.Results[0].PreviousStatus)
+		assert.Equal(t, "security_review", response.Results[0].Status)
+		assert.True(t, response.Results[1].Success)
+		assert.False(t, response.Results[2].Success)
+		assert.Contains(t, response.Results[2].Error, ErrResourceNotFound.Error())
+		assert.False(t, response.Results[3].Success)
+
+		// A failed item does not undo the others
+		var submission models.ApplicationSubmission
+		require.NoError(t, db.First(&submission, "submission_id = ?", "sub_app").Error)
+		assert.Equal(t, "security_review", submission.Status)
+	})
+
+	t.Run("ExpireApplications", func(t *testing.T) {
+		response, err := service.ExpireApplications(ctx, &models.BulkExpireApplicationsRequest{ApplicationIDs: []string{"app_1", "app_missing"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, models.BulkItemResult{
+			Type: models.BulkResourceApplication, ID: "app_1", Success: true,
+			PreviousStatus: string(models.ActiveVersion), Status: string(models.DeprecatedVersion),
+		}, response.Results[0])
+
+		var application models.Application
+		require.NoError(t, db.First(&application, "application_id = ?", "app_1").Error)
+		assert.Equal(t, string(models.DeprecatedVersion), application.Version)
+		assert.Equal(t, int64(2), application.Revision)
+
+		response, err = service.ExpireApplications(ctx, &models.BulkExpireApplicationsRequest{ApplicationIDs: []string{"app_1"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Failed, "an already deprecated application is reported")
+	})
+
+	t.Run("ReassignResources", func(t *testing.T) {
+		_, err := service.ReassignResources(ctx, &models.BulkReassignRequest{FromMemberID: "mem_consumer", ToMemberID: "mem_consumer"})
+		assert.ErrorIs(t, err, ErrInvalidBulkRequest)
+		_, err = service.ReassignResources(ctx, &models.BulkReassignRequest{FromMemberID: "mem_consumer", ToMemberID: "mem_missing"})
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		organizationID := "org_1"
+		require.NoError(t, db.Create(&models.Organization{OrganizationID: organizationID, Name: "Org"}).Error)
+		require.NoError(t, db.Model(&models.Member{}).Where("member_id = ?", "mem_provider").Update("organization_id", organizationID).Error)
+
+		response, err := service.ReassignResources(ctx, &models.BulkReassignRequest{
+			FromMemberID: "mem_consumer",
+			ToMemberID:   "mem_provider",
+			Resources: []models.BulkResourceRef{
+				{Type: models.BulkResourceApplication, ID: "app_1"},
+				{Type: models.BulkResourceSchema, ID: "sch_1"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Contains(t, response.Results[1].Error, "not owned by member mem_consumer")
+
+		var application models.Application
+		require.NoError(t, db.First(&application, "application_id = ?", "app_1").Error)
+		assert.Equal(t, "mem_provider", application.MemberID)
+		require.NotNil(t, application.OrganizationID)
+		assert.Equal(t, organizationID, *application.OrganizationID)
+
+		// Without resources, everything the member still owns is moved
+		response, err = service.ReassignResources(ctx, &models.BulkReassignRequest{FromMemberID: "mem_consumer", ToMemberID: "mem_provider"})
+		require.NoError(t, err)
+		require.Len(t, response.Results, 1)
+		assert.Equal(t, models.BulkItemResult{Type: models.BulkResourceApplicationSubmission, ID: "sub_app", Success: true}, response.Results[0])
+	})
+}
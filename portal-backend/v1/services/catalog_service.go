@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/database"
+	"gorm.io/gorm"
+)
+
+// ErrPolicyServiceUnavailable is returned when the PDP cannot provide the field policies
+var ErrPolicyServiceUnavailable = errors.New("policy service unavailable")
+
+// catalogSortFields are the fields the catalog can be sorted by; it is sorted by fieldName by default
+var catalogSortFields = map[string]func(a, b *models.CatalogField) int{
+	"fieldName":    func(a, b *models.CatalogField) int { return strings.Compare(a.FieldName, b.FieldName) },
+	"schemaName":   func(a, b *models.CatalogField) int { return strings.Compare(a.SchemaName, b.SchemaName) },
+	"providerName": func(a, b *models.CatalogField) int { return strings.Compare(a.ProviderName, b.ProviderName) },
+}
+
+// CatalogService assembles the fields applications can request from the approved schemas and their PDP policies
+type CatalogService struct {
+	db         *gorm.DB
+	pdpService *PDPService
+}
+
+// NewCatalogService creates a new catalog service
+func NewCatalogService(db *gorm.DB, pdpService *PDPService) *CatalogService {
+	return &CatalogService{db: db, pdpService: pdpService}
+}
+
+// ListCatalogFields returns a page of the fields of the active schemas. Search matches the field name, display
+// name and description; the facets count the fields matching the search before filters narrow them.
+func (s *CatalogService) ListCatalogFields(ctx context.Context, q models.ListQuery, filters models.CatalogFieldFilters) (*models.CatalogFieldsResponse, error) {
+	if q.Sort == "" {
+		q.Sort = "fieldName"
+		if q.Order == "" {
+			q.Order = models.SortOrderAsc
+		}
+	}
+	compare, ok := catalogSortFields[q.Sort]
+	if !ok {
+		return nil, models.NewValidationError(ErrInvalidListQuery, models.ValidationErrorInvalidValue, "sort", fmt.Sprintf("cannot sort by %q", q.Sort))
+	}
+	// The catalog is sorted in memory, so normalize only fills in the paging defaults and checks the order
+	if _, err := (listColumns{sortable: map[string]string{q.Sort: q.Sort}}).normalize(&q); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.catalogFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	search := strings.ToLower(strings.TrimSpace(q.Search))
+	matched := make([]models.CatalogField, 0, len(fields))
+	for _, field := range fields {
+		if search == "" || matchesCatalogSearch(field, search) {
+			matched = append(matched, field)
+		}
+	}
+
+	items := make([]models.CatalogField, 0, len(matched))
+	for _, field := range matched {
+		if matchesCatalogFilters(field, filters) {
+			items = append(items, field)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compare(&items[i], &items[j])
+		if c == 0 {
+			c = strings.Compare(items[i].SchemaID+"."+items[i].FieldName, items[j].SchemaID+"."+items[j].FieldName)
+		}
+		if q.Order == models.SortOrderDesc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	total := len(items)
+	start := min((q.Page-1)*q.Limit, total)
+	end := min(start+q.Limit, total)
+	return &models.CatalogFieldsResponse{
+		Items:      items[start:end],
+		Count:      end - start,
+		Pagination: paginationMetadata(q, int64(total)),
+		Facets:     catalogFacets(matched),
+	}, nil
+}
+
+// catalogFields joins the fields of the PDP's policy document to the active schemas they belong to
+func (s *CatalogService) catalogFields(ctx context.Context) ([]models.CatalogField, error) {
+	var schemas []models.Schema
+	if err := database.Reader(s.db).WithContext(ctx).Preload("Member").
+		Where("version = ?", string(models.ActiveVersion)).Find(&schemas).Error; err != nil {
+		return nil, fmt.Errorf("failed to get schemas: %w", err)
+	}
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	schemasByID := make(map[string]*models.Schema, len(schemas))
+	for i := range schemas {
+		schemasByID[schemas[i].SchemaID] = &schemas[i]
+	}
+
+	document, err := s.pdpService.ExportPolicyDocument()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPolicyServiceUnavailable, err)
+	}
+	rules, err := s.pdpService.ListClassifications()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPolicyServiceUnavailable, err)
+	}
+	alwaysRequiresConsent := make(map[models.Classification]bool, len(rules))
+	for _, rule := range rules {
+		alwaysRequiresConsent[rule.Classification] = rule.AlwaysRequiresConsent
+	}
+
+	var fields []models.CatalogField
+	for _, policies := range document.Schemas {
+		schema, ok := schemasByID[policies.SchemaID]
+		if !ok {
+			continue
+		}
+		for _, policy := range policies.Fields {
+			accessControlType := policy.AccessControlType
+			if accessControlType == "" {
+				accessControlType = models.AccessControlTypePublic
+			}
+			fields = append(fields, models.CatalogField{
+				FieldName:         policy.FieldName,
+				DisplayName:       policy.DisplayName,
+				Description:       policy.Description,
+				SchemaID:          schema.SchemaID,
+				SchemaName:        schema.SchemaName,
+				ProviderID:        schema.MemberID,
+				ProviderName:      schema.Member.Name,
+				Source:            policy.Source,
+				Owner:             policy.Owner,
+				AccessControlType: accessControlType,
+				Classification:    policy.Classification,
+				// Mirrors the PDP: fields the provider does not own need consent when restricted or always-consent
+				ConsentRequired: !policy.IsOwner &&
+					(accessControlType == models.AccessControlTypeRestricted || alwaysRequiresConsent[policy.Classification]),
+			})
+		}
+	}
+	return fields, nil
+}
+
+func matchesCatalogSearch(field models.CatalogField, search string) bool {
+	if strings.Contains(strings.ToLower(field.FieldName), search) {
+		return true
+	}
+	if field.DisplayName != nil && strings.Contains(strings.ToLower(*field.DisplayName), search) {
+		return true
+	}
+	return field.Description != nil && strings.Contains(strings.ToLower(*field.Description), search)
+}
+
+func matchesCatalogFilters(field models.CatalogField, filters models.CatalogFieldFilters) bool {
+	return (filters.SchemaID == "" || field.SchemaID == filters.SchemaID) &&
+		(filters.ProviderID == "" || field.ProviderID == filters.ProviderID) &&
+		(filters.Classification == "" || field.Classification == filters.Classification) &&
+		(filters.AccessControlType == "" || field.AccessControlType == filters.AccessControlType) &&
+		(filters.ConsentRequired == nil || field.ConsentRequired == *filters.ConsentRequired)
+}
+
+// catalogFacets counts fields per facet value, sorted by value
+func catalogFacets(fields []models.CatalogField) models.CatalogFacets {
+	providers := newFacetCounter()
+	schemas := newFacetCounter()
+	classifications := newFacetCounter()
+	accessControlTypes := newFacetCounter()
+	consentRequired := newFacetCounter()
+	for _, field := range fields {
+		providers.add(field.ProviderID, field.ProviderName)
+		schemas.add(field.SchemaID, field.SchemaName)
+		if field.Classification != "" {
+			classifications.add(string(field.Classification), "")
+		}
+		accessControlTypes.add(string(field.AccessControlType), "")
+		consentRequired.add(strconv.FormatBool(field.ConsentRequired), "")
+	}
+	return models.CatalogFacets{
+		Providers:          providers.counts(),
+		Schemas:            schemas.counts(),
+		Classifications:    classifications.counts(),
+		AccessControlTypes: accessControlTypes.counts(),
+		ConsentRequired:    consentRequired.counts(),
+	}
+}
+
+type facetCounter map[string]*models.FacetCount
+
+func newFacetCounter() facetCounter {
+	return make(facetCounter)
+}
+
+func (c facetCounter) add(value, label string) {
+	if facet, ok := c[value]; ok {
+		facet.Count++
+		return
+	}
+	c[value] = &models.FacetCount{Value: value, Label: label, Count: 1}
+}
+
+func (c facetCounter) counts() []models.FacetCount {
+	counts := make([]models.FacetCount, 0, len(c))
+	for _, facet := range c {
+		counts = append(counts, *facet)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Value < counts[j].Value })
+	return counts
+}
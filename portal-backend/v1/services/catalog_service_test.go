@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogService_ListCatalogFields(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+
+	// sch_retired has policies in the PDP but no active schema, so its fields are not listed
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.Header.Get("apikey"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/policy/export":
+			assert.Equal(t, "json", r.URL.Query().Get("format"))
+			_, _ = w.Write([]byte(`{"version": "1", "schemas": [
+				{"schemaId": "sch_1", "fields": [
+					{"fieldName": "person.name", "displayName": "Full name", "source": "primary", "isOwner": false, "accessControlType": "public", "classification": "personal"},
+					{"fieldName": "person.nic", "description": "National identity card number", "source": "primary", "isOwner": false, "accessControlType": "restricted", "classification": "personal"},
+					{"fieldName": "person.medicalHistory", "source": "primary", "isOwner": false, "accessControlType": "public", "classification": "sensitive-personal"},
+					{"fieldName": "person.registrationOffice", "source": "primary", "isOwner": true, "accessControlType": "restricted", "classification": "internal"}
+				]},
+				{"schemaId": "sch_retired", "fields": [{"fieldName": "vehicle.plate", "source": "primary", "isOwner": true}]}
+			]}`))
+		case "/api/v1/policy/classifications":
+			_, _ = w.Write([]byte(`{"records": [
+				{"classification": "personal", "alwaysRequiresConsent": false},
+				{"classification": "sensitive-personal", "alwaysRequiresConsent": true}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := NewCatalogService(db, NewPDPService(server.URL, "test-key"))
+	ctx := context.Background()
+
+	t.Run("Lists fields of active schemas", func(t *testing.T) {
+		response, err := service.ListCatalogFields(ctx, models.ListQuery{}, models.CatalogFieldFilters{})
+		require.NoError(t, err)
+		require.Equal(t, 4, response.Count)
+		assert.Equal(t, int64(4), response.Pagination.Total)
+		assert.Equal(t, "fieldName", response.Pagination.Sort)
+
+		consentRequired := make(map[string]bool)
+		for _, field := range response.Items {
+			assert.Equal(t, "sch_1", field.SchemaID)
+			assert.Equal(t, "Provider", field.ProviderName)
+			consentRequired[field.FieldName] = field.ConsentRequired
+		}
+		assert.Equal(t, map[string]bool{
+			"person.name":               false,
+			"person.nic":                true,
+			"person.medicalHistory":     true,
+			"person.registrationOffice": false,
+		}, consentRequired)
+		assert.Equal(t, "person.medicalHistory", response.Items[0].FieldName)
+
+		assert.Equal(t, []models.FacetCount{{Value: "mem_provider", Label: "Provider", Count: 4}}, response.Facets.Providers)
+		assert.Equal(t, []models.FacetCount{{Value: "false", Count: 2}, {Value: "true", Count: 2}}, response.Facets.ConsentRequired)
+	})
+
+	t.Run("Search and filters", func(t *testing.T) {
+		response, err := service.ListCatalogFields(ctx, models.ListQuery{Search: "IDENTITY"}, models.CatalogFieldFilters{})
+		require.NoError(t, err)
+		require.Equal(t, 1, response.Count)
+		assert.Equal(t, "person.nic", response.Items[0].FieldName)
+
+		// Facets count the search matches before the filters narrow them
+		consentRequired := true
+		response, err = service.ListCatalogFields(ctx, models.ListQuery{Search: "person"},
+			models.CatalogFieldFilters{Classification: models.ClassificationPersonal, ConsentRequired: &consentRequired})
+		require.NoError(t, err)
+		require.Equal(t, 1, response.Count)
+		assert.Equal(t, "person.nic", response.Items[0].FieldName)
+		assert.Len(t, response.Facets.Classifications, 3)
+	})
+
+	t.Run("Paging and sorting", func(t *testing.T) {
+		response, err := service.ListCatalogFields(ctx, models.ListQuery{Page: 2, Limit: 3, Order: models.SortOrderDesc}, models.CatalogFieldFilters{})
+		require.NoError(t, err)
+		require.Equal(t, 1, response.Count)
+		assert.Equal(t, "person.medicalHistory", response.Items[0].FieldName)
+		assert.Equal(t, 2, response.Pagination.TotalPages)
+
+		_, err = service.ListCatalogFields(ctx, models.ListQuery{Sort: "createdAt"}, models.CatalogFieldFilters{})
+		assert.ErrorIs(t, err, ErrInvalidListQuery)
+	})
+
+	t.Run("PDP failure", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+
+		_, err := NewCatalogService(db, NewPDPService(failing.URL, "")).ListCatalogFields(ctx, models.ListQuery{}, models.CatalogFieldFilters{})
+		assert.ErrorIs(t, err, ErrPolicyServiceUnavailable)
+	})
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// EmailSender delivers notification emails
+type EmailSender interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPEmailSender sends plain-text emails through an SMTP server
+type SMTPEmailSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPEmailSender creates an SMTP email sender. PLAIN authentication is used when username is set.
+func NewSMTPEmailSender(host, port, username, password, from string) *SMTPEmailSender {
+	sender := &SMTPEmailSender{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+// NewSMTPEmailSenderFromEnv creates an SMTP email sender from SMTP_HOST, SMTP_PORT, SMTP_USERNAME,
+// SMTP_PASSWORD and SMTP_FROM. It returns nil when SMTP_HOST is not set, which disables email.
+func NewSMTPEmailSenderFromEnv() (*SMTPEmailSender, error) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil, nil
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		return nil, fmt.Errorf("SMTP_FROM environment variable not set")
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return NewSMTPEmailSender(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+}
+
+// Send sends a plain-text email to a single recipient
+func (s *SMTPEmailSender) Send(to, subject, body string) error {
+	// Header values come from the portal's own notification templates, but strip line breaks so
+	// that a resource name can never inject extra headers
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s\r\n",
+		s.from, to, subject, body)
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
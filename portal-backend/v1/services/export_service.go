@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+)
+
+// collectionListColumns maps the exportable collections to how they are searched and sorted
+var collectionListColumns = map[models.SavedViewCollection]listColumns{
+	models.SavedViewCollectionMembers:                memberListColumns,
+	models.SavedViewCollectionApplications:           applicationListColumns,
+	models.SavedViewCollectionApplicationSubmissions: applicationSubmissionListColumns,
+	models.SavedViewCollectionSchemaSubmissions:      schemaSubmissionListColumns,
+}
+
+// exportHeaders are the column headers of each exported collection, in the order of the row cells
+var exportHeaders = map[models.SavedViewCollection][]string{
+	models.SavedViewCollectionMembers: {
+		"memberId", "name", "email", "phoneNumber", "organizationId", "organizationRole", "idpUserId", "createdAt", "updatedAt",
+	},
+	models.SavedViewCollectionApplications: {
+		"applicationId", "applicationName", "memberId", "organizationId", "version", "selectedFields", "grantExpiresAt",
+		"createdAt", "updatedAt",
+	},
+	models.SavedViewCollectionApplicationSubmissions: {
+		"submissionId", "applicationName", "memberId", "organizationId", "status", "previousApplicationId", "renewal",
+		"selectedFields", "review", "createdAt", "updatedAt",
+	},
+	models.SavedViewCollectionSchemaSubmissions: {
+		"submissionId", "schemaName", "memberId", "organizationId", "status", "previousSchemaId", "schemaEndpoint",
+		"review", "createdAt", "updatedAt",
+	},
+}
+
+// ExportService exports admin collections as CSV or XLSX files
+type ExportService struct {
+	memberService      *MemberService
+	applicationService *ApplicationService
+	schemaService      *SchemaService
+}
+
+// NewExportService creates a new export service over the services listing the collections
+func NewExportService(memberService *MemberService, applicationService *ApplicationService, schemaService *SchemaService) *ExportService {
+	return &ExportService{
+		memberService:      memberService,
+		applicationService: applicationService,
+		schemaService:      schemaService,
+	}
+}
+
+// ExportCollection writes every item of a collection matching q to w, with a header row. Items are
+// fetched and written a page at a time, so the collection is never held in memory, and w is flushed
+// after each page when it is an http.Flusher. Pages are read one after another rather than from a
+// snapshot, so items created or deleted during a long export may be missed or repeated.
+func (s *ExportService) ExportCollection(ctx context.Context, collection models.SavedViewCollection, q models.ListQuery, format models.ExportFormat, w io.Writer) error {
+	headers, ok := exportHeaders[collection]
+	if !ok {
+		return fmt.Errorf("%w: unknown collection %q", ErrInvalidListQuery, collection)
+	}
+	writer, err := newExportWriter(format, w, string(collection))
+	if err != nil {
+		return fmt.Errorf("failed to start export: %w", err)
+	}
+	if err := writer.WriteRow(headers); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	q.Page = 1
+	q.Limit = models.MaxPageLimit
+	for {
+		rows, pagination, err := s.exportPage(ctx, collection, q)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := writer.WriteRow(row); err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if len(rows) < q.Limit || q.Page >= pagination.TotalPages {
+			break
+		}
+		q.Page++
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// exportPage lists a page of a collection as export rows
+func (s *ExportService) exportPage(ctx context.Context, collection models.SavedViewCollection, q models.ListQuery) ([][]string, *models.PaginationMetadata, error) {
+	var rows [][]string
+	switch collection {
+	case models.SavedViewCollectionMembers:
+		members, pagination, err := s.memberService.ListMembers(ctx, q)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, member := range members {
+			rows = append(rows, []string{
+				member.MemberID, member.Name, member.Email, member.PhoneNumber, optionalCell(member.OrganizationID),
+				string(member.OrganizationRole), member.IdpUserID, member.CreatedAt, member.UpdatedAt,
+			})
+		}
+		return rows, pagination, nil
+	case models.SavedViewCollectionApplications:
+		applications, pagination, err := s.applicationService.GetApplications(ctx, q)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, application := range applications {
+			rows = append(rows, []string{
+				application.ApplicationID, application.ApplicationName, application.MemberID, optionalCell(application.OrganizationID),
+				application.Version, selectedFieldsCell(application.SelectedFields), optionalCell(application.GrantExpiresAt),
+				application.CreatedAt, application.UpdatedAt,
+			})
+		}
+		return rows, pagination, nil
+	case models.SavedViewCollectionApplicationSubmissions:
+		submissions, pagination, err := s.applicationService.GetApplicationSubmissions(ctx, q)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, submission := range submissions {
+			rows = append(rows, []string{
+				submission.SubmissionID, submission.ApplicationName, submission.MemberID, optionalCell(submission.OrganizationID),
+				submission.Status, optionalCell(submission.PreviousApplicationID), strconv.FormatBool(submission.Renewal),
+				selectedFieldsCell(submission.SelectedFields), optionalCell(submission.Review), submission.CreatedAt, submission.UpdatedAt,
+			})
+		}
+		return rows, pagination, nil
+	case models.SavedViewCollectionSchemaSubmissions:
+		submissions, pagination, err := s.schemaService.GetSchemaSubmissions(q)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, submission := range submissions {
+			rows = append(rows, []string{
+				submission.SubmissionID, submission.SchemaName, submission.MemberID, optionalCell(submission.OrganizationID),
+				submission.Status, optionalCell(submission.PreviousSchemaID), submission.SchemaEndpoint, optionalCell(submission.Review),
+				submission.CreatedAt, submission.UpdatedAt,
+			})
+		}
+		return rows, pagination, nil
+	}
+	return nil, nil, fmt.Errorf("%w: unknown collection %q", ErrInvalidListQuery, collection)
+}
+
+// optionalCell returns the value of an optional field, or an empty cell
+func optionalCell(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// selectedFieldsCell lists the selected fields of an application in one cell
+func selectedFieldsCell(fields []models.SelectedFieldRecord) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.FieldName
+	}
+	return strings.Join(names, "; ")
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportService_ExportCollection(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	pdpService := NewPDPService("http://localhost:9999", "test-key")
+	service := NewExportService(NewMemberService(db, nil), NewApplicationService(db, pdpService, nil), NewSchemaService(db, pdpService))
+	ctx := context.Background()
+
+	t.Run("CSV of every page with a header row", func(t *testing.T) {
+		// More members than fit on one page
+		for i := 0; i < models.MaxPageLimit+5; i++ {
+			require.NoError(t, db.Create(&models.Member{MemberID: fmt.Sprintf("mem_bulk_%03d", i), Name: fmt.Sprintf("Bulk %03d", i),
+				Email: fmt.Sprintf("bulk%03d@example.com", i), PhoneNumber: "3", IdpUserID: fmt.Sprintf("idp-bulk-%03d", i)}).Error)
+		}
+
+		var out bytes.Buffer
+		q := models.ListQuery{Search: "bulk", Sort: "name", Order: models.SortOrderAsc}
+		require.NoError(t, service.ExportCollection(ctx, models.SavedViewCollectionMembers, q, models.ExportFormatCSV, &out))
+
+		records, err := csv.NewReader(&out).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, models.MaxPageLimit+6)
+		assert.Equal(t, exportHeaders[models.SavedViewCollectionMembers], records[0])
+		assert.Equal(t, "mem_bulk_000", records[1][0])
+		assert.Equal(t, "mem_bulk_104", records[len(records)-1][0])
+	})
+
+	t.Run("CSV cells cannot inject formulas", func(t *testing.T) {
+		require.NoError(t, db.Create(&models.Member{MemberID: "mem_formula", Name: "=HYPERLINK(\"http://evil\")",
+			Email: "formula@example.com", PhoneNumber: "+94 11", IdpUserID: "idp-formula"}).Error)
+
+		var out bytes.Buffer
+		email := "formula@example.com"
+		require.NoError(t, service.ExportCollection(ctx, models.SavedViewCollectionMembers, models.ListQuery{Email: &email}, models.ExportFormatCSV, &out))
+		records, err := csv.NewReader(&out).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, `'=HYPERLINK("http://evil")`, records[1][1])
+		assert.Equal(t, "'+94 11", records[1][3])
+	})
+
+	t.Run("XLSX workbook of filtered submissions", func(t *testing.T) {
+		var out bytes.Buffer
+		q := models.ListQuery{Status: []string{string(models.StatusPending)}}
+		require.NoError(t, service.ExportCollection(ctx, models.SavedViewCollectionApplicationSubmissions, q, models.ExportFormatXLSX, &out))
+
+		archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		parts := map[string]string{}
+		for _, file := range archive.File {
+			reader, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			parts[file.Name] = string(content)
+		}
+		assert.Contains(t, parts, "[Content_Types].xml")
+		assert.Contains(t, parts["xl/workbook.xml"], `name="application-submissions"`)
+		sheet := parts["xl/worksheets/sheet1.xml"]
+		assert.Equal(t, 2, strings.Count(sheet, "<row>"))
+		assert.Contains(t, sheet, "<t xml:space=\"preserve\">sub_app</t>")
+		assert.Contains(t, sheet, "person.name; vehicle.plate")
+		assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+	})
+
+	t.Run("Invalid sort", func(t *testing.T) {
+		err := service.ExportCollection(ctx, models.SavedViewCollectionApplications, models.ListQuery{Sort: "clientSecret"}, models.ExportFormatCSV, io.Discard)
+		assert.ErrorIs(t, err, ErrInvalidListQuery)
+	})
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+)
+
+// exportWriter writes the rows of an export in a file format
+type exportWriter interface {
+	WriteRow(cells []string) error
+	// Flush writes the buffered rows to the underlying writer
+	Flush() error
+	// Close completes the file
+	Close() error
+}
+
+// newExportWriter creates a writer of the format whose rows are written to w; sheet names the XLSX worksheet
+func newExportWriter(format models.ExportFormat, w io.Writer, sheet string) (exportWriter, error) {
+	if format == models.ExportFormatXLSX {
+		return newXLSXWriter(w, sheet)
+	}
+	return &csvExportWriter{writer: csv.NewWriter(w)}, nil
+}
+
+// csvFormulaPrefixes are the first characters that make spreadsheets evaluate a CSV cell as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvExportWriter writes RFC 4180 CSV
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+// WriteRow writes a record. Cells that spreadsheets would evaluate as formulas are prefixed with a
+// quote, so that exported values cannot inject formulas.
+func (c *csvExportWriter) WriteRow(cells []string) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		if cell != "" && strings.ContainsRune(csvFormulaPrefixes, rune(cell[0])) {
+			cell = "'" + cell
+		}
+		record[i] = cell
+	}
+	return c.writer.Write(record)
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	return c.Flush()
+}
+
+// The fixed parts of an XLSX workbook with a single worksheet
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter streams a single-sheet XLSX workbook. The fixed parts are written first and the worksheet
+// last, so rows go straight to the zip stream; cells are inline strings, so no shared string table has
+// to be built in memory.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+}
+
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	var escapedName strings.Builder
+	if err := xml.EscapeText(&escapedName, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	archive := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRelationships},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapedName.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRelationships},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (x *xlsxWriter) WriteRow(cells []string) error {
+	var row strings.Builder
+	row.WriteString("<row>")
+	for _, cell := range cells {
+		row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		// EscapeText replaces characters XML cannot carry, such as control characters
+		if err := xml.EscapeText(&row, []byte(cell)); err != nil {
+			return err
+		}
+		row.WriteString("</t></is></c>")
+	}
+	row.WriteString("</row>")
+	_, err := io.WriteString(x.sheet, row.String())
+	return err
+}
+
+func (x *xlsxWriter) Flush() error {
+	return x.archive.Flush()
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return x.archive.Close()
+}
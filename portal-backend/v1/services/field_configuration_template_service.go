@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// ErrFieldConfigurationTemplateConflict is returned when a template would share its name or provider
+// type with another template
+var ErrFieldConfigurationTemplateConflict = errors.New("a field configuration template with this name or provider type already exists")
+
+// FieldConfigurationTemplateService manages the field configuration templates of provider types
+type FieldConfigurationTemplateService struct {
+	db *gorm.DB
+}
+
+// NewFieldConfigurationTemplateService creates a new field configuration template service
+func NewFieldConfigurationTemplateService(db *gorm.DB) *FieldConfigurationTemplateService {
+	return &FieldConfigurationTemplateService{db: db}
+}
+
+// CreateTemplate creates the template of a provider type
+func (s *FieldConfigurationTemplateService) CreateTemplate(ctx context.Context, req *models.CreateFieldConfigurationTemplateRequest) (*models.FieldConfigurationTemplateResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "name", "name is required")
+	}
+	providerType := normalizeProviderType(&req.ProviderType)
+	if providerType == nil {
+		return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "providerType", "providerType is required")
+	}
+	if err := validateFieldConfigurationRules(req.Rules); err != nil {
+		return nil, err
+	}
+	if err := s.checkAvailable(ctx, name, *providerType, ""); err != nil {
+		return nil, err
+	}
+
+	template := models.FieldConfigurationTemplate{
+		TemplateID:   "fct_" + uuid.New().String(),
+		Name:         name,
+		Description:  req.Description,
+		ProviderType: *providerType,
+		Rules:        req.Rules,
+	}
+	if err := s.db.WithContext(ctx).Create(&template).Error; err != nil {
+		return nil, fmt.Errorf("failed to create field configuration template: %w", err)
+	}
+	return fieldConfigurationTemplateResponseOf(template), nil
+}
+
+// ListTemplates returns every template, by name
+func (s *FieldConfigurationTemplateService) ListTemplates(ctx context.Context) ([]models.FieldConfigurationTemplateResponse, error) {
+	var templates []models.FieldConfigurationTemplate
+	if err := s.db.WithContext(ctx).Order("name, template_id").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to list field configuration templates: %w", err)
+	}
+
+	responses := make([]models.FieldConfigurationTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		responses = append(responses, *fieldConfigurationTemplateResponseOf(template))
+	}
+	return responses, nil
+}
+
+// GetTemplate returns a template by ID
+func (s *FieldConfigurationTemplateService) GetTemplate(ctx context.Context, templateID string) (*models.FieldConfigurationTemplateResponse, error) {
+	template, err := s.findTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return fieldConfigurationTemplateResponseOf(*template), nil
+}
+
+// UpdateTemplate changes a template. Submissions already made keep the configurations the template
+// suggested; the change applies to submissions made or edited afterwards.
+func (s *FieldConfigurationTemplateService) UpdateTemplate(ctx context.Context, templateID string, req *models.UpdateFieldConfigurationTemplateRequest) (*models.FieldConfigurationTemplateResponse, error) {
+	template, err := s.findTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "name", "name must not be empty")
+		}
+		template.Name = name
+	}
+	if req.Description != nil {
+		template.Description = req.Description
+	}
+	if req.ProviderType != nil {
+		providerType := normalizeProviderType(req.ProviderType)
+		if providerType == nil {
+			return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "providerType", "providerType must not be empty")
+		}
+		template.ProviderType = *providerType
+	}
+	if req.Rules != nil {
+		if err := validateFieldConfigurationRules(req.Rules); err != nil {
+			return nil, err
+		}
+		template.Rules = req.Rules
+	}
+	if err := s.checkAvailable(ctx, template.Name, template.ProviderType, template.TemplateID); err != nil {
+		return nil, err
+	}
+
+	template.UpdatedAt = time.Now()
+	if err := s.db.WithContext(ctx).Save(template).Error; err != nil {
+		return nil, fmt.Errorf("failed to update field configuration template: %w", err)
+	}
+	return fieldConfigurationTemplateResponseOf(*template), nil
+}
+
+// DeleteTemplate deletes a template; submissions of its provider type are then configured from their
+// field names
+func (s *FieldConfigurationTemplateService) DeleteTemplate(ctx context.Context, templateID string) error {
+	template, err := s.findTemplate(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Delete(template).Error; err != nil {
+		return fmt.Errorf("failed to delete field configuration template: %w", err)
+	}
+	return nil
+}
+
+func (s *FieldConfigurationTemplateService) findTemplate(ctx context.Context, templateID string) (*models.FieldConfigurationTemplate, error) {
+	var template models.FieldConfigurationTemplate
+	if err := s.db.WithContext(ctx).First(&template, "template_id = ?", templateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get field configuration template: %w", err)
+	}
+	return &template, nil
+}
+
+// checkAvailable checks that no other template has the name or the provider type
+func (s *FieldConfigurationTemplateService) checkAvailable(ctx context.Context, name, providerType, exceptTemplateID string) error {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.FieldConfigurationTemplate{}).
+		Where("(LOWER(name) = ? OR provider_type = ?) AND template_id <> ?", strings.ToLower(name), providerType, exceptTemplateID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to check field configuration templates: %w", err)
+	}
+	if count > 0 {
+		return ErrFieldConfigurationTemplateConflict
+	}
+	return nil
+}
+
+// providerTypeTemplate returns the template of a provider type, or nil if the type is unset or has none
+func providerTypeTemplate(db *gorm.DB, providerType *string) (*models.FieldConfigurationTemplate, error) {
+	if providerType == nil {
+		return nil, nil
+	}
+	var template models.FieldConfigurationTemplate
+	if err := db.First(&template, "provider_type = ?", *providerType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get field configuration template: %w", err)
+	}
+	return &template, nil
+}
+
+// normalizeProviderType lower-cases a provider type so that it selects its template regardless of
+// case; an empty provider type clears it
+func normalizeProviderType(providerType *string) *string {
+	if providerType == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*providerType))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// validateFieldConfigurationRules returns a ValidationError if a rule has no valid pattern or an
+// unknown setting. Unlike field configurations, rules must set the access control type and the
+// classification, since they replace the suggestion from the field's name.
+func validateFieldConfigurationRules(rules models.FieldConfigurationRules) error {
+	var fieldErrors []models.FieldError
+	if len(rules) == 0 {
+		fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorRequired, "rules",
+			"at least one rule is required"))
+	}
+	for i, rule := range rules {
+		prefix := fmt.Sprintf("rules[%d].", i)
+		if _, err := path.Match(rule.Field, ""); strings.TrimSpace(rule.Field) == "" || err != nil {
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"field",
+				"field must be a field name, a dot-notation path or a pattern of either"))
+		}
+		switch rule.AccessControlType {
+		case models.AccessControlTypePublic, models.AccessControlTypeRestricted:
+		default:
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"accessControlType",
+				"accessControlType must be public or restricted"))
+		}
+		switch rule.Classification {
+		case models.ClassificationPublic, models.ClassificationInternal, models.ClassificationPersonal, models.ClassificationSensitivePersonal:
+		default:
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"classification",
+				"classification must be public, internal, personal or sensitive-personal"))
+		}
+		if rule.Owner != nil && *rule.Owner != models.OwnerCitizen {
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"owner",
+				"owner must be citizen"))
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return &models.ValidationError{Err: ErrInvalidFieldConfiguration, Fields: fieldErrors}
+	}
+	return nil
+}
+
+func fieldConfigurationTemplateResponseOf(template models.FieldConfigurationTemplate) *models.FieldConfigurationTemplateResponse {
+	return &models.FieldConfigurationTemplateResponse{
+		TemplateID:   template.TemplateID,
+		Name:         template.Name,
+		Description:  template.Description,
+		ProviderType: template.ProviderType,
+		Rules:        template.Rules,
+		CreatedAt:    template.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    template.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldConfigurationTemplateService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	service := NewFieldConfigurationTemplateService(db)
+	ctx := context.Background()
+
+	template, err := service.CreateTemplate(ctx, &models.CreateFieldConfigurationTemplateRequest{
+		Name:         "Person registry defaults",
+		ProviderType: " Person-Registry ",
+		Rules: models.FieldConfigurationRules{
+			{Field: "nic", AccessControlType: models.AccessControlTypeRestricted, Classification: models.ClassificationPersonal},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "person-registry", template.ProviderType)
+	assert.Len(t, template.Rules, 1)
+
+	t.Run("Names and provider types are unique", func(t *testing.T) {
+		_, err := service.CreateTemplate(ctx, &models.CreateFieldConfigurationTemplateRequest{
+			Name: "person registry defaults", ProviderType: "vehicle-registry", Rules: template.Rules,
+		})
+		assert.ErrorIs(t, err, ErrFieldConfigurationTemplateConflict)
+
+		_, err = service.CreateTemplate(ctx, &models.CreateFieldConfigurationTemplateRequest{
+			Name: "Citizen defaults", ProviderType: "person-registry", Rules: template.Rules,
+		})
+		assert.ErrorIs(t, err, ErrFieldConfigurationTemplateConflict)
+	})
+
+	t.Run("Rules must have a pattern and known settings", func(t *testing.T) {
+		_, err := service.CreateTemplate(ctx, &models.CreateFieldConfigurationTemplateRequest{
+			Name: "Vehicle registry defaults", ProviderType: "vehicle-registry",
+			Rules: models.FieldConfigurationRules{{Field: "[", AccessControlType: "secret"}},
+		})
+		assert.ErrorIs(t, err, ErrInvalidFieldConfiguration)
+		var validationErr *models.ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Len(t, validationErr.Fields, 3)
+		assert.Equal(t, "rules[0].field", validationErr.Fields[0].Field)
+
+		_, err = service.CreateTemplate(ctx, &models.CreateFieldConfigurationTemplateRequest{
+			Name: "Vehicle registry defaults", ProviderType: "vehicle-registry",
+		})
+		assert.ErrorIs(t, err, ErrInvalidFieldConfiguration)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		name := "Registry defaults"
+		updated, err := service.UpdateTemplate(ctx, template.TemplateID, &models.UpdateFieldConfigurationTemplateRequest{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, name, updated.Name)
+		assert.Equal(t, template.Rules, updated.Rules)
+
+		templates, err := service.ListTemplates(ctx)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		assert.Equal(t, name, templates[0].Name)
+
+		require.NoError(t, service.DeleteTemplate(ctx, template.TemplateID))
+		_, err = service.GetTemplate(ctx, template.TemplateID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+}
+
+func TestSchemaService_SchemaSubmissionFieldConfigurationTemplate(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	providerType := "vehicle-registry"
+	require.NoError(t, db.Create(&models.Organization{OrganizationID: "org_dmt", Name: "DMT", ProviderType: &providerType}).Error)
+	require.NoError(t, db.Model(&models.Member{}).Where("member_id = ?", "mem_provider").Update("organization_id", "org_dmt").Error)
+
+	isOwner := true
+	_, err := NewFieldConfigurationTemplateService(db).CreateTemplate(context.Background(), &models.CreateFieldConfigurationTemplateRequest{
+		Name:         "Vehicle registry defaults",
+		ProviderType: providerType,
+		Rules: models.FieldConfigurationRules{
+			{Field: "vehicle.*", IsOwner: &isOwner, AccessControlType: models.AccessControlTypePublic, Classification: models.ClassificationInternal},
+		},
+	})
+	require.NoError(t, err)
+	service := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+	sdl := `type Query { "Vehicle" vehicle: Vehicle }
+		type Vehicle { "Number" registrationNumber: String! "Owner NIC" nic: String }`
+
+	// Submissions take the provider type of the member's organization
+	created, err := service.CreateSchemaSubmission(&models.CreateSchemaSubmissionRequest{
+		SchemaName: "Vehicles", SDL: sdl, SchemaEndpoint: "http://provider", MemberID: "mem_provider",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.ProviderType)
+	assert.Equal(t, providerType, *created.ProviderType)
+	require.Len(t, created.FieldConfigurations, 2)
+	nic := created.FieldConfigurations[1]
+	assert.Equal(t, "vehicle.nic", nic.FieldName)
+	assert.True(t, nic.IsOwner)
+	assert.Equal(t, models.ClassificationInternal, nic.Classification)
+	assert.False(t, nic.ConsentRequired)
+	assert.Equal(t, `configured by the "Vehicle registry defaults" template (rule "vehicle.*")`, nic.Reason)
+
+	// A provider type given with the submission overrides the organization's
+	other := "person-registry"
+	created, err = service.CreateSchemaSubmission(&models.CreateSchemaSubmissionRequest{
+		SchemaName: "Vehicles", SDL: sdl, SchemaEndpoint: "http://provider", MemberID: "mem_provider", ProviderType: &other,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, other, *created.ProviderType)
+	assert.Equal(t, models.ClassificationPersonal, created.FieldConfigurations[1].Classification)
+}
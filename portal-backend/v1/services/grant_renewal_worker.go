@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// GrantRenewalWorker periodically opens renewal submissions for application grants that are about
+// to expire and notifies the applications' owners
+type GrantRenewalWorker struct {
+	applicationService  *ApplicationService
+	notificationService *NotificationService
+	// interval is how often the worker polls
+	interval time.Duration
+	// notice is how long before grants expire a renewal is opened
+	notice time.Duration
+}
+
+// NewGrantRenewalWorker creates a new grant renewal worker
+func NewGrantRenewalWorker(applicationService *ApplicationService, notificationService *NotificationService, interval, notice time.Duration) *GrantRenewalWorker {
+	return &GrantRenewalWorker{
+		applicationService:  applicationService,
+		notificationService: notificationService,
+		interval:            interval,
+		notice:              notice,
+	}
+}
+
+// Start runs the renewal loop until the context is cancelled
+func (w *GrantRenewalWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		slog.Info("Grant renewal worker disabled")
+		return
+	}
+
+	slog.Info("Grant renewal worker started", "interval", w.interval, "notice", w.notice)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Grant renewal worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce flags expiring grants and notifies their owners, returning how many grants were flagged
+func (w *GrantRenewalWorker) RunOnce(ctx context.Context) int {
+	grants, err := w.applicationService.FlagExpiringGrants(ctx, w.notice)
+	if err != nil {
+		slog.Error("Failed to flag expiring grants", "error", err)
+	}
+	for _, grant := range grants {
+		if err := w.notificationService.NotifyGrantExpiring(ctx, grant); err != nil {
+			slog.Error("Failed to notify expiring grant", "applicationID", grant.ApplicationID, "error", err)
+		}
+	}
+	if len(grants) > 0 {
+		slog.Info("Flagged expiring grants", "count", len(grants))
+	}
+	return len(grants)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantRenewalWorker(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	var renewalRequests []models.AllowListRenewalCreateRequest
+	reviews := map[string]models.AllowListRenewalReviewRequest{}
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/policy/renewal-requests":
+			var req models.AllowListRenewalCreateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			renewalRequests = append(renewalRequests, req)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(models.AllowListRenewal{ID: fmt.Sprintf("ren_%d", len(renewalRequests)),
+				ApplicationID: req.ApplicationID, Status: models.RenewalRequestPending})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/v1/policy/renewal-requests/"):
+			var req models.AllowListRenewalReviewRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			id := strings.TrimPrefix(r.URL.Path, "/api/v1/policy/renewal-requests/")
+			reviews[id] = req
+			_ = json.NewEncoder(w).Encode(models.AllowListRenewal{ID: id, Status: req.Status})
+		default:
+			t.Errorf("unexpected PDP request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer pdpServer.Close()
+
+	applicationService := NewApplicationService(db, NewPDPService(pdpServer.URL, "test-key"), &MockIDP{})
+	notificationService := NewNotificationService(db, nil, nil)
+	reviewService := NewSubmissionReviewService(db, nil, applicationService, nil, nil, DefaultReviewWorkflow)
+	worker := NewGrantRenewalWorker(applicationService, notificationService, time.Hour, 30*24*time.Hour)
+
+	soon := time.Now().Add(10 * 24 * time.Hour)
+	later := time.Now().Add(90 * 24 * time.Hour)
+	fields := models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_1"}}
+	require.NoError(t, db.Model(&models.Application{}).Where("application_id = ?", "app_1").Update("grant_expires_at", soon).Error)
+	require.NoError(t, db.Create(&models.Application{ApplicationID: "app_later", ApplicationName: "Later", SelectedFields: fields,
+		MemberID: "mem_consumer", Version: string(models.ActiveVersion), GrantExpiresAt: &later}).Error)
+	require.NoError(t, db.Create(&models.Application{ApplicationID: "app_deprecated", ApplicationName: "Deprecated", SelectedFields: fields,
+		MemberID: "mem_consumer", Version: string(models.DeprecatedVersion), GrantExpiresAt: &soon}).Error)
+
+	t.Run("Grants expiring within the notice are flagged once", func(t *testing.T) {
+		assert.Equal(t, 1, worker.RunOnce(ctx))
+		assert.Zero(t, worker.RunOnce(ctx))
+
+		var submissions []models.ApplicationSubmission
+		require.NoError(t, db.Where("renewal = ?", true).Find(&submissions).Error)
+		require.Len(t, submissions, 1)
+		assert.Equal(t, "app_1", *submissions[0].PreviousApplicationID)
+		assert.Equal(t, string(models.StatusPending), submissions[0].Status)
+		require.Len(t, renewalRequests, 1, "the submission opens a PDP renewal request")
+		assert.Equal(t, "app_1", renewalRequests[0].ApplicationID)
+		assert.Equal(t, DefaultGrantDuration, renewalRequests[0].GrantDuration)
+		if assert.NotNil(t, submissions[0].PDPRenewalID) {
+			assert.Equal(t, "ren_1", *submissions[0].PDPRenewalID)
+		}
+		assert.Empty(t, reviews)
+
+		var notifications []models.Notification
+		require.NoError(t, db.Where("event = ?", models.NotificationEventGrantExpiring).Find(&notifications).Error)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "mem_consumer", notifications[0].MemberID)
+		assert.Equal(t, submissions[0].SubmissionID, notifications[0].ResourceID)
+
+		_, err := applicationService.RenewApplication(ctx, "app_1")
+		assert.ErrorIs(t, err, ErrRenewalPending)
+	})
+
+	t.Run("Approving a renewal extends the grants", func(t *testing.T) {
+		var submission models.ApplicationSubmission
+		require.NoError(t, db.First(&submission, "renewal = ? AND previous_application_id = ?", true, "app_1").Error)
+		// The default workflow has two steps that need one approval each
+		for range DefaultReviewWorkflow {
+			_, err := reviewService.Review(ctx, models.SubmissionTypeApplication, submission.SubmissionID, "reviewer-1",
+				&models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove})
+			require.NoError(t, err)
+		}
+
+		assert.Len(t, renewalRequests, 1)
+		assert.Equal(t, map[string]models.AllowListRenewalReviewRequest{"ren_1": {Status: models.RenewalRequestApproved}}, reviews,
+			"approving the submission approves its PDP renewal request")
+
+		var application models.Application
+		require.NoError(t, db.First(&application, "application_id = ?", "app_1").Error)
+		require.NotNil(t, application.GrantExpiresAt)
+		assert.True(t, application.GrantExpiresAt.After(later))
+		assert.Nil(t, application.GrantRenewalFlaggedAt)
+
+		var applications int64
+		require.NoError(t, db.Model(&models.Application{}).Count(&applications).Error)
+		assert.Equal(t, int64(3), applications, "a renewal does not create an application")
+	})
+
+	t.Run("Members can renew before the worker flags the grants", func(t *testing.T) {
+		submission, err := applicationService.RenewApplication(ctx, "app_later")
+		require.NoError(t, err)
+		assert.True(t, submission.Renewal)
+		assert.Equal(t, "app_later", *submission.PreviousApplicationID)
+
+		_, err = applicationService.RenewApplication(ctx, "app_missing")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		// The worker reuses the member's renewal instead of opening another
+		flagged, err := applicationService.FlagExpiringGrants(ctx, 100*24*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, flagged, 1)
+		assert.Equal(t, "app_later", flagged[0].ApplicationID)
+		assert.Equal(t, submission.SubmissionID, flagged[0].SubmissionID)
+	})
+
+	t.Run("Rejecting a renewal rejects the PDP renewal request", func(t *testing.T) {
+		var submission models.ApplicationSubmission
+		require.NoError(t, db.First(&submission, "renewal = ? AND previous_application_id = ?", true, "app_later").Error)
+		require.NotNil(t, submission.PDPRenewalID)
+		_, err := reviewService.Review(ctx, models.SubmissionTypeApplication, submission.SubmissionID, "reviewer-1",
+			&models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionReject})
+		require.NoError(t, err)
+		assert.Equal(t, models.RenewalRequestRejected, reviews[*submission.PDPRenewalID].Status)
+	})
+
+	t.Run("Approving a renewal submitted before PDP renewal requests opens one", func(t *testing.T) {
+		require.NoError(t, db.Create(&models.ApplicationSubmission{SubmissionID: "sub_legacy_renewal", PreviousApplicationID: stringPtr("app_later"),
+			ApplicationName: "Later", SelectedFields: fields, MemberID: "mem_consumer", Status: string(models.StatusPending), Renewal: true}).Error)
+		renewals := len(renewalRequests)
+		for range DefaultReviewWorkflow {
+			_, err := reviewService.Review(ctx, models.SubmissionTypeApplication, "sub_legacy_renewal", "reviewer-1",
+				&models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove})
+			require.NoError(t, err)
+		}
+
+		require.Len(t, renewalRequests, renewals+1)
+		var submission models.ApplicationSubmission
+		require.NoError(t, db.First(&submission, "submission_id = ?", "sub_legacy_renewal").Error)
+		require.NotNil(t, submission.PDPRenewalID)
+		assert.Equal(t, models.RenewalRequestApproved, reviews[*submission.PDPRenewalID].Status)
+	})
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// ErrSelfImpersonation is returned when an admin tries to impersonate their own member record
+var ErrSelfImpersonation = errors.New("admins cannot impersonate themselves")
+
+// ImpersonationService manages the sessions in which support admins act as members. A session never
+// issues a token: the admin keeps authenticating with their own and names the session in the
+// X-Impersonation-Session header, so every impersonated request remains attributable to them.
+type ImpersonationService struct {
+	db *gorm.DB
+	// lifetime is how long a session lasts before it has to be started again
+	lifetime time.Duration
+}
+
+// NewImpersonationService creates a new impersonation service
+func NewImpersonationService(db *gorm.DB, lifetime time.Duration) *ImpersonationService {
+	return &ImpersonationService{db: db, lifetime: lifetime}
+}
+
+// StartImpersonation starts a session in which admin acts as the requested member. An admin has at
+// most one active session; starting another ends the previous one.
+func (s *ImpersonationService) StartImpersonation(ctx context.Context, admin *models.AuthenticatedUser, req *models.StartImpersonationRequest) (*models.ImpersonationSessionResponse, error) {
+	var member models.Member
+	if err := s.db.WithContext(ctx).First(&member, "member_id = ?", req.MemberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+	if member.IdpUserID == admin.IdpUserID {
+		return nil, models.NewValidationError(ErrSelfImpersonation, models.ValidationErrorInvalidValue, "memberId", "memberId must be another member")
+	}
+
+	now := time.Now()
+	session := models.ImpersonationSession{
+		SessionID:      "imp_" + uuid.New().String(),
+		AdminIdpUserID: admin.IdpUserID,
+		AdminEmail:     admin.Email,
+		MemberID:       member.MemberID,
+		Reason:         strings.TrimSpace(req.Reason),
+		ExpiresAt:      now.Add(s.lifetime),
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := endActiveSessions(tx, admin.IdpUserID, now); err != nil {
+			return err
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return fmt.Errorf("failed to create impersonation session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Impersonation started", "sessionID", session.SessionID, "admin", admin.IdpUserID,
+		"memberID", member.MemberID, "reason", session.Reason, "expiresAt", session.ExpiresAt)
+	response := impersonationSessionResponseOf(session, now)
+	return &response, nil
+}
+
+// GetActiveImpersonation returns the admin's active session
+func (s *ImpersonationService) GetActiveImpersonation(ctx context.Context, adminIdpUserID string) (*models.ImpersonationSessionResponse, error) {
+	now := time.Now()
+	var session models.ImpersonationSession
+	err := s.db.WithContext(ctx).
+		Where("admin_idp_user_id = ? AND ended_at IS NULL AND expires_at > ?", adminIdpUserID, now).
+		Order("created_at DESC").
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get impersonation session: %w", err)
+	}
+	response := impersonationSessionResponseOf(session, now)
+	return &response, nil
+}
+
+// StopImpersonation ends the admin's active session and returns it
+func (s *ImpersonationService) StopImpersonation(ctx context.Context, adminIdpUserID string) (*models.ImpersonationSessionResponse, error) {
+	session, err := s.GetActiveImpersonation(ctx, adminIdpUserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := endActiveSessions(s.db.WithContext(ctx), adminIdpUserID, now); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Impersonation stopped", "sessionID", session.SessionID, "admin", adminIdpUserID, "memberID", session.MemberID)
+	ended := now.Format(time.RFC3339)
+	session.EndedAt = &ended
+	session.Active = false
+	return session, nil
+}
+
+// ResolveImpersonation checks that sessionID is an active session of admin and returns the user the
+// admin's request is made as, along with the session details to record next to it
+func (s *ImpersonationService) ResolveImpersonation(ctx context.Context, admin *models.AuthenticatedUser, sessionID string) (*models.AuthenticatedUser, *models.Impersonation, error) {
+	var session models.ImpersonationSession
+	err := s.db.WithContext(ctx).First(&session, "session_id = ? AND admin_idp_user_id = ?", sessionID, admin.IdpUserID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, models.ErrImpersonationInactive
+		}
+		return nil, nil, fmt.Errorf("failed to get impersonation session: %w", err)
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, nil, models.ErrImpersonationInactive
+	}
+
+	var member models.Member
+	if err := s.db.WithContext(ctx).First(&member, "member_id = ?", session.MemberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The member was deleted during the session
+			return nil, nil, models.ErrImpersonationInactive
+		}
+		return nil, nil, fmt.Errorf("failed to get impersonated member: %w", err)
+	}
+
+	impersonation := &models.Impersonation{
+		SessionID:       session.SessionID,
+		AdminIdpUserID:  session.AdminIdpUserID,
+		AdminEmail:      session.AdminEmail,
+		MemberID:        member.MemberID,
+		MemberIdpUserID: member.IdpUserID,
+		ExpiresAt:       session.ExpiresAt,
+	}
+	return models.NewImpersonatedUser(admin, &member, impersonation), impersonation, nil
+}
+
+// endActiveSessions ends every session of the admin that is still open
+func endActiveSessions(db *gorm.DB, adminIdpUserID string, now time.Time) error {
+	err := db.Model(&models.ImpersonationSession{}).
+		Where("admin_idp_user_id = ? AND ended_at IS NULL", adminIdpUserID).
+		Updates(map[string]interface{}{"ended_at": now, "updated_at": now}).Error
+	if err != nil {
+		return fmt.Errorf("failed to end impersonation sessions: %w", err)
+	}
+	return nil
+}
+
+func impersonationSessionResponseOf(session models.ImpersonationSession, now time.Time) models.ImpersonationSessionResponse {
+	response := models.ImpersonationSessionResponse{
+		SessionID:      session.SessionID,
+		AdminIdpUserID: session.AdminIdpUserID,
+		AdminEmail:     session.AdminEmail,
+		MemberID:       session.MemberID,
+		Reason:         session.Reason,
+		Active:         session.IsActive(now),
+		ExpiresAt:      session.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:      session.CreatedAt.Format(time.RFC3339),
+	}
+	if session.EndedAt != nil {
+		endedAt := session.EndedAt.Format(time.RFC3339)
+		response.EndedAt = &endedAt
+	}
+	return response
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+	service := NewImpersonationService(db, time.Hour)
+
+	admin := &models.AuthenticatedUser{IdpUserID: "idp-admin", Email: "admin@example.com", Roles: []models.Role{models.RoleAdmin}, ExpiresAt: time.Now().Add(2 * time.Hour)}
+	var member models.Member
+	require.NoError(t, db.First(&member, "member_id = ?", "mem_consumer").Error)
+
+	t.Run("StartImpersonation validates the member", func(t *testing.T) {
+		_, err := service.StartImpersonation(ctx, admin, &models.StartImpersonationRequest{MemberID: "mem_missing", Reason: "ticket"})
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		self := &models.AuthenticatedUser{IdpUserID: member.IdpUserID, Roles: []models.Role{models.RoleAdmin}}
+		_, err = service.StartImpersonation(ctx, self, &models.StartImpersonationRequest{MemberID: member.MemberID, Reason: "ticket"})
+		assert.ErrorIs(t, err, ErrSelfImpersonation)
+	})
+
+	first, err := service.StartImpersonation(ctx, admin, &models.StartImpersonationRequest{MemberID: "mem_provider", Reason: "ticket 1"})
+	require.NoError(t, err)
+	session, err := service.StartImpersonation(ctx, admin, &models.StartImpersonationRequest{MemberID: member.MemberID, Reason: " ticket 2 "})
+	require.NoError(t, err)
+	assert.True(t, session.Active)
+	assert.Equal(t, "ticket 2", session.Reason)
+
+	t.Run("Starting a session ends the previous one", func(t *testing.T) {
+		_, _, err := service.ResolveImpersonation(ctx, admin, first.SessionID)
+		assert.ErrorIs(t, err, models.ErrImpersonationInactive)
+
+		active, err := service.GetActiveImpersonation(ctx, admin.IdpUserID)
+		require.NoError(t, err)
+		assert.Equal(t, session.SessionID, active.SessionID)
+	})
+
+	t.Run("ResolveImpersonation scopes the admin to the member", func(t *testing.T) {
+		user, impersonation, err := service.ResolveImpersonation(ctx, admin, session.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, member.IdpUserID, user.IdpUserID)
+		assert.Equal(t, []models.Role{models.RoleMember}, user.Roles)
+		assert.False(t, user.HasPermission(models.PermissionReadAllMembers))
+		assert.False(t, user.ExpiresAt.After(impersonation.ExpiresAt), "the user expires with the session")
+		assert.Equal(t, admin.IdpUserID, impersonation.AdminIdpUserID)
+		assert.Equal(t, member.MemberID, impersonation.MemberID)
+
+		other := &models.AuthenticatedUser{IdpUserID: "idp-other-admin", Roles: []models.Role{models.RoleAdmin}}
+		_, _, err = service.ResolveImpersonation(ctx, other, session.SessionID)
+		assert.ErrorIs(t, err, models.ErrImpersonationInactive, "sessions cannot be used by another admin")
+	})
+
+	t.Run("Expired sessions cannot be used", func(t *testing.T) {
+		require.NoError(t, db.Model(&models.ImpersonationSession{}).Where("session_id = ?", session.SessionID).
+			Update("expires_at", time.Now().Add(-time.Second)).Error)
+		_, _, err := service.ResolveImpersonation(ctx, admin, session.SessionID)
+		assert.ErrorIs(t, err, models.ErrImpersonationInactive)
+		_, err = service.GetActiveImpersonation(ctx, admin.IdpUserID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	t.Run("StopImpersonation", func(t *testing.T) {
+		session, err := service.StartImpersonation(ctx, admin, &models.StartImpersonationRequest{MemberID: member.MemberID, Reason: "ticket 3"})
+		require.NoError(t, err)
+		stopped, err := service.StopImpersonation(ctx, admin.IdpUserID)
+		require.NoError(t, err)
+		assert.False(t, stopped.Active)
+		assert.NotNil(t, stopped.EndedAt)
+
+		_, _, err = service.ResolveImpersonation(ctx, admin, session.SessionID)
+		assert.ErrorIs(t, err, models.ErrImpersonationInactive)
+		_, err = service.StopImpersonation(ctx, admin.IdpUserID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+}
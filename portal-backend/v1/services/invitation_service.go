@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// invitationTokenBytes is the number of random bytes in an invitation token
+const invitationTokenBytes = 32
+
+var (
+	// ErrInvalidInvitationRole is returned when inviting someone with a role that cannot be invited
+	ErrInvalidInvitationRole = errors.New("invitation role must be OpenDIF_Admin or OpenDIF_Member")
+	// ErrInvitationConflict is returned when the email already belongs to a member or has a pending invitation
+	ErrInvitationConflict = errors.New("email already belongs to a member or has a pending invitation")
+	// ErrInvitationOrganizationNotFound is returned when inviting someone into an organization that does not exist
+	ErrInvitationOrganizationNotFound = errors.New("organization not found")
+	// ErrInvalidInvitationToken is returned when no invitation matches a token
+	ErrInvalidInvitationToken = errors.New("invalid invitation token")
+	// ErrInvitationExpired is returned when accepting an invitation after it expired
+	ErrInvitationExpired = errors.New("invitation has expired")
+	// ErrInvitationNotPending is returned when accepting or revoking an invitation that was already accepted or revoked
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+)
+
+// InvitationService onboards members through invitations. An admin invites an email address with a
+// role; the invitee accepts with the token from the invitation, which provisions their IDP user,
+// adds it to the role's group and creates their member record.
+type InvitationService struct {
+	db            *gorm.DB
+	memberService *MemberService
+	// lifetime is how long an invitation can be accepted
+	lifetime time.Duration
+}
+
+// NewInvitationService creates a new invitation service
+func NewInvitationService(db *gorm.DB, memberService *MemberService, lifetime time.Duration) *InvitationService {
+	return &InvitationService{db: db, memberService: memberService, lifetime: lifetime}
+}
+
+// CreateInvitation invites an email address to join with a role. The returned invitation carries
+// the token, which cannot be retrieved again.
+func (s *InvitationService) CreateInvitation(ctx context.Context, req *models.CreateInvitationRequest, invitedBy string) (*models.InvitationResponse, error) {
+	if _, ok := req.Role.UserGroup(); !ok {
+		return nil, models.NewValidationError(ErrInvalidInvitationRole, models.ValidationErrorInvalidValue, "role", fmt.Sprintf("role %q cannot be invited", req.Role))
+	}
+	email := strings.TrimSpace(req.Email)
+
+	// The invitee joins the organization, if any, as an org_member unless another role is given
+	var organizationRole models.OrganizationRole
+	if req.OrganizationID != nil {
+		organizationRole = req.OrganizationRole
+		if organizationRole == "" {
+			organizationRole = models.OrganizationRoleMember
+		}
+		if !organizationRole.IsValid() {
+			return nil, models.NewValidationError(ErrInvalidOrganizationRole, models.ValidationErrorInvalidValue, "organizationRole", fmt.Sprintf("unknown organization role %s", organizationRole))
+		}
+		var organizations int64
+		if err := s.db.WithContext(ctx).Model(&models.Organization{}).
+			Where("organization_id = ?", *req.OrganizationID).Count(&organizations).Error; err != nil {
+			return nil, fmt.Errorf("failed to check organization: %w", err)
+		}
+		if organizations == 0 {
+			return nil, ErrInvitationOrganizationNotFound
+		}
+	}
+
+	var members int64
+	if err := s.db.WithContext(ctx).Model(&models.Member{}).Where("email = ?", email).Count(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing members: %w", err)
+	}
+	var pending int64
+	if err := s.db.WithContext(ctx).Model(&models.Invitation{}).
+		Where("email = ? AND status = ? AND expires_at > ?", email, models.InvitationStatusPending, time.Now()).
+		Count(&pending).Error; err != nil {
+		return nil, fmt.Errorf("failed to check pending invitations: %w", err)
+	}
+	if members > 0 || pending > 0 {
+		return nil, ErrInvitationConflict
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation := models.Invitation{
+		InvitationID:     "inv_" + uuid.New().String(),
+		Email:            email,
+		Role:             req.Role,
+		OrganizationID:   req.OrganizationID,
+		OrganizationRole: organizationRole,
+		TokenHash:        hashInvitationToken(token),
+		Status:           models.InvitationStatusPending,
+		ExpiresAt:        time.Now().Add(s.lifetime),
+		InvitedBy:        invitedBy,
+	}
+	if err := s.db.WithContext(ctx).Create(&invitation).Error; err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	slog.Info("Invitation created", "invitationID", invitation.InvitationID, "role", invitation.Role, "expiresAt", invitation.ExpiresAt)
+	response := invitationResponseOf(invitation)
+	response.Token = token
+	return &response, nil
+}
+
+// ListInvitations returns all invitations, newest first
+func (s *InvitationService) ListInvitations(ctx context.Context) ([]models.InvitationResponse, error) {
+	var invitations []models.Invitation
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	responses := make([]models.InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		responses = append(responses, invitationResponseOf(invitation))
+	}
+	return responses, nil
+}
+
+// RevokeInvitation withdraws a pending invitation so that its token can no longer be accepted
+func (s *InvitationService) RevokeInvitation(ctx context.Context, invitationID string) (*models.InvitationResponse, error) {
+	result := s.db.WithContext(ctx).Model(&models.Invitation{}).
+		Where("invitation_id = ? AND status = ?", invitationID, models.InvitationStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.InvitationStatusRevoked,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to revoke invitation: %w", result.Error)
+	}
+
+	var invitation models.Invitation
+	if err := s.db.WithContext(ctx).First(&invitation, "invitation_id = ?", invitationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvitationNotPending
+	}
+
+	slog.Info("Invitation revoked", "invitationID", invitationID)
+	response := invitationResponseOf(invitation)
+	return &response, nil
+}
+
+// AcceptInvitation provisions the invited member. The IDP user and group membership are created
+// first; the invitation is then marked accepted in the same transaction that creates the member, so
+// a concurrent acceptance or a database failure rolls the IDP changes back.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, req *models.AcceptInvitationRequest) (*models.MemberResponse, error) {
+	var invitation models.Invitation
+	if err := s.db.WithContext(ctx).First(&invitation, "token_hash = ?", hashInvitationToken(req.Token)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitationToken
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if invitation.Status != models.InvitationStatusPending {
+		return nil, ErrInvitationNotPending
+	}
+	if !time.Now().Before(invitation.ExpiresAt) {
+		return nil, ErrInvitationExpired
+	}
+	group, ok := invitation.Role.UserGroup()
+	if !ok {
+		return nil, ErrInvalidInvitationRole
+	}
+
+	member := models.Member{
+		MemberID:         "mem_" + uuid.New().String(),
+		Name:             req.Name,
+		Email:            invitation.Email,
+		PhoneNumber:      req.PhoneNumber,
+		OrganizationID:   invitation.OrganizationID,
+		OrganizationRole: invitation.OrganizationRole,
+	}
+	err := s.memberService.provisionMember(ctx, &member, group, func(member *models.Member) error {
+		return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			now := time.Now()
+			result := tx.Model(&models.Invitation{}).
+				Where("invitation_id = ? AND status = ?", invitation.InvitationID, models.InvitationStatusPending).
+				Updates(map[string]interface{}{
+					"status":      models.InvitationStatusAccepted,
+					"accepted_at": now,
+					"member_id":   member.MemberID,
+					"updated_at":  now,
+				})
+			if result.Error != nil {
+				return fmt.Errorf("failed to accept invitation: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return ErrInvitationNotPending
+			}
+			return tx.Create(member).Error
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Invitation accepted", "invitationID", invitation.InvitationID, "memberID", member.MemberID)
+	return s.memberService.buildMemberResponse(&member), nil
+}
+
+// newInvitationToken returns a random URL-safe invitation token
+func newInvitationToken() (string, error) {
+	buf := make([]byte, invitationTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashInvitationToken returns the stored form of an invitation token
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func invitationResponseOf(invitation models.Invitation) models.InvitationResponse {
+	status := invitation.Status
+	if status == models.InvitationStatusPending && !time.Now().Before(invitation.ExpiresAt) {
+		status = models.InvitationStatusExpired
+	}
+	response := models.InvitationResponse{
+		InvitationID:     invitation.InvitationID,
+		Email:            invitation.Email,
+		Role:             invitation.Role,
+		OrganizationID:   invitation.OrganizationID,
+		OrganizationRole: invitation.OrganizationRole,
+		Status:           status,
+		ExpiresAt:        invitation.ExpiresAt.Format(time.RFC3339),
+		InvitedBy:        invitation.InvitedBy,
+		MemberID:         invitation.MemberID,
+		CreatedAt:        invitation.CreatedAt.Format(time.RFC3339),
+	}
+	if invitation.AcceptedAt != nil {
+		acceptedAt := invitation.AcceptedAt.Format(time.RFC3339)
+		response.AcceptedAt = &acceptedAt
+	}
+	return response
+}
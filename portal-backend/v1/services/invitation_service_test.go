@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	mockIDP := &MockIDP{}
+	service := NewInvitationService(db, NewMemberService(db, mockIDP), 72*time.Hour)
+	organization := "org_registrar"
+	require.NoError(t, db.Create(&models.Organization{OrganizationID: organization, Name: "Registrar General"}).Error)
+
+	t.Run("CreateInvitation", func(t *testing.T) {
+		_, err := service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "new@example.com", Role: models.RoleSystem}, "idp-admin")
+		assert.ErrorIs(t, err, ErrInvalidInvitationRole)
+
+		missing := "org_missing"
+		_, err = service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "new@example.com", Role: models.RoleMember, OrganizationID: &missing}, "idp-admin")
+		assert.ErrorIs(t, err, ErrInvitationOrganizationNotFound)
+
+		_, err = service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "provider@example.com", Role: models.RoleMember}, "idp-admin")
+		assert.ErrorIs(t, err, ErrInvitationConflict, "already a member")
+
+		invitation, err := service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "new@example.com", Role: models.RoleMember, OrganizationID: &organization}, "idp-admin")
+		require.NoError(t, err)
+		assert.NotEmpty(t, invitation.Token)
+		assert.Equal(t, models.InvitationStatusPending, invitation.Status)
+		assert.Equal(t, "idp-admin", invitation.InvitedBy)
+		assert.Equal(t, models.OrganizationRoleMember, invitation.OrganizationRole, "org_member by default")
+
+		_, err = service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "new@example.com", Role: models.RoleAdmin}, "idp-admin")
+		assert.ErrorIs(t, err, ErrInvitationConflict, "already invited")
+
+		var stored models.Invitation
+		require.NoError(t, db.First(&stored, "invitation_id = ?", invitation.InvitationID).Error)
+		assert.NotEqual(t, invitation.Token, stored.TokenHash, "only the token hash is stored")
+
+		invitations, err := service.ListInvitations(ctx)
+		require.NoError(t, err)
+		require.Len(t, invitations, 1)
+		assert.Empty(t, invitations[0].Token)
+	})
+
+	t.Run("AcceptInvitation", func(t *testing.T) {
+		invitation, err := service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "accept@example.com", Role: models.RoleAdmin, OrganizationID: &organization, OrganizationRole: models.OrganizationRoleAdmin}, "idp-admin")
+		require.NoError(t, err)
+
+		var group string
+		mockIDP.AddMemberToGroupByGroupNameFunc = func(ctx context.Context, groupName string, member *idp.GroupMember) (*string, error) {
+			group = groupName
+			groupID := "group_admin"
+			return &groupID, nil
+		}
+		defer func() { mockIDP.AddMemberToGroupByGroupNameFunc = nil }()
+
+		_, err = service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: "unknown", Name: "New Admin", PhoneNumber: "3"})
+		assert.ErrorIs(t, err, ErrInvalidInvitationToken)
+
+		member, err := service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: invitation.Token, Name: "New Admin", PhoneNumber: "3"})
+		require.NoError(t, err)
+		assert.Equal(t, "accept@example.com", member.Email)
+		assert.Equal(t, "idp_123", member.IdpUserID)
+		require.NotNil(t, member.OrganizationID)
+		assert.Equal(t, organization, *member.OrganizationID)
+		assert.Equal(t, models.OrganizationRoleAdmin, member.OrganizationRole)
+		assert.Equal(t, string(models.UserGroupAdmin), group)
+
+		var accepted models.Invitation
+		require.NoError(t, db.First(&accepted, "invitation_id = ?", invitation.InvitationID).Error)
+		assert.Equal(t, models.InvitationStatusAccepted, accepted.Status)
+		require.NotNil(t, accepted.MemberID)
+		assert.Equal(t, member.MemberID, *accepted.MemberID)
+
+		_, err = service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: invitation.Token, Name: "New Admin", PhoneNumber: "3"})
+		assert.ErrorIs(t, err, ErrInvitationNotPending)
+	})
+
+	t.Run("AcceptInvitationRollsBackIDP", func(t *testing.T) {
+		invitation, err := service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "rollback@example.com", Role: models.RoleMember}, "idp-admin")
+		require.NoError(t, err)
+
+		// Another member already holds the IDP user ID, so creating the member record fails
+		mockIDP.CreateUserFunc = func(ctx context.Context, user *idp.User) (*idp.UserInfo, error) {
+			return &idp.UserInfo{Id: "idp-provider", Email: user.Email}, nil
+		}
+		var removed, deleted bool
+		mockIDP.RemoveMemberFromGroupFunc = func(ctx context.Context, groupID, userID string) error {
+			removed = true
+			return nil
+		}
+		mockIDP.DeleteUserFunc = func(ctx context.Context, userID string) error {
+			deleted = true
+			return nil
+		}
+		defer func() {
+			mockIDP.CreateUserFunc, mockIDP.RemoveMemberFromGroupFunc, mockIDP.DeleteUserFunc = nil, nil, nil
+		}()
+
+		_, err = service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: invitation.Token, Name: "Rollback", PhoneNumber: "4"})
+		require.Error(t, err)
+		assert.True(t, removed)
+		assert.True(t, deleted)
+
+		var pending models.Invitation
+		require.NoError(t, db.First(&pending, "invitation_id = ?", invitation.InvitationID).Error)
+		assert.Equal(t, models.InvitationStatusPending, pending.Status, "the invitation is not consumed")
+		assert.Nil(t, pending.MemberID)
+	})
+
+	t.Run("AcceptInvitationIDPFailure", func(t *testing.T) {
+		invitation, err := service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "idpdown@example.com", Role: models.RoleMember}, "idp-admin")
+		require.NoError(t, err)
+
+		mockIDP.CreateUserFunc = func(ctx context.Context, user *idp.User) (*idp.UserInfo, error) {
+			return nil, errors.New("idp unavailable")
+		}
+		defer func() { mockIDP.CreateUserFunc = nil }()
+
+		_, err = service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: invitation.Token, Name: "Down", PhoneNumber: "5"})
+		assert.ErrorContains(t, err, "idp unavailable")
+
+		var members int64
+		require.NoError(t, db.Model(&models.Member{}).Where("email = ?", "idpdown@example.com").Count(&members).Error)
+		assert.Zero(t, members)
+	})
+
+	t.Run("ExpiredInvitation", func(t *testing.T) {
+		expiring := NewInvitationService(db, NewMemberService(db, mockIDP), -time.Minute)
+		invitation, err := expiring.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "late@example.com", Role: models.RoleMember}, "idp-admin")
+		require.NoError(t, err)
+		assert.Equal(t, models.InvitationStatusExpired, invitation.Status)
+
+		_, err = service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: invitation.Token, Name: "Late", PhoneNumber: "6"})
+		assert.ErrorIs(t, err, ErrInvitationExpired)
+
+		// An expired invitation does not block inviting the same email again
+		_, err = service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "late@example.com", Role: models.RoleMember}, "idp-admin")
+		assert.NoError(t, err)
+	})
+
+	t.Run("RevokeInvitation", func(t *testing.T) {
+		invitation, err := service.CreateInvitation(ctx, &models.CreateInvitationRequest{Email: "revoke@example.com", Role: models.RoleMember}, "idp-admin")
+		require.NoError(t, err)
+
+		revoked, err := service.RevokeInvitation(ctx, invitation.InvitationID)
+		require.NoError(t, err)
+		assert.Equal(t, models.InvitationStatusRevoked, revoked.Status)
+
+		_, err = service.RevokeInvitation(ctx, invitation.InvitationID)
+		assert.ErrorIs(t, err, ErrInvitationNotPending)
+		_, err = service.RevokeInvitation(ctx, "inv_missing")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		_, err = service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: invitation.Token, Name: "Revoked", PhoneNumber: "7"})
+		assert.ErrorIs(t, err, ErrInvitationNotPending)
+	})
+}
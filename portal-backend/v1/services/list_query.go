@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/response"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidListQuery is returned when a collection request cannot be applied, e.g. an unknown sort field
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// defaultSortField is the field collections are sorted by when the request names none
+const defaultSortField = "createdAt"
+
+// likeEscaper escapes the LIKE wildcards in a search term
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// listColumns describes how a collection is searched and sorted
+type listColumns struct {
+	// sortable maps the JSON field names a collection can be sorted by to their columns
+	sortable map[string]string
+	// search is the name column matched by ListQuery.Search
+	search string
+	// key is the primary key, used to break sort ties so pages are stable
+	key string
+}
+
+// normalize fills in the paging defaults of q and returns its ORDER BY clause
+func (c listColumns) normalize(q *models.ListQuery) (string, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = models.DefaultPageLimit
+	}
+	if q.Limit > models.MaxPageLimit {
+		q.Limit = models.MaxPageLimit
+	}
+	if q.Sort == "" {
+		q.Sort = defaultSortField
+	}
+	if q.Order == "" {
+		q.Order = models.SortOrderDesc
+	}
+
+	column, ok := c.sortable[q.Sort]
+	if !ok {
+		return "", models.NewValidationError(ErrInvalidListQuery, models.ValidationErrorInvalidValue, "sort", fmt.Sprintf("cannot sort by %q", q.Sort))
+	}
+	direction := "ASC"
+	switch q.Order {
+	case models.SortOrderAsc:
+	case models.SortOrderDesc:
+		direction = "DESC"
+	default:
+		return "", models.NewValidationError(ErrInvalidListQuery, models.ValidationErrorInvalidValue, "order", "order must be asc or desc")
+	}
+	return fmt.Sprintf("%s %s, %s %s", column, direction, c.key, direction), nil
+}
+
+// filter applies the filters of q shared by all collections
+func (c listColumns) filter(query *gorm.DB, q models.ListQuery) *gorm.DB {
+	if q.MemberID != nil && *q.MemberID != "" {
+		if q.OrganizationID != nil && *q.OrganizationID != "" {
+			query = query.Where("(member_id = ? OR organization_id = ?)", *q.MemberID, *q.OrganizationID)
+		} else {
+			query = query.Where("member_id = ?", *q.MemberID)
+		}
+	}
+	if len(q.Status) > 0 {
+		query = query.Where("status IN ?", q.Status)
+	}
+	if q.Search != "" && c.search != "" {
+		query = query.Where("LOWER("+c.search+`) LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(strings.ToLower(q.Search))+"%")
+	}
+	if q.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *q.CreatedAfter)
+	}
+	if q.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *q.CreatedBefore)
+	}
+	return query
+}
+
+// page limits query to the page requested by a normalized q
+func page(query *gorm.DB, q models.ListQuery) *gorm.DB {
+	return query.Offset((q.Page - 1) * q.Limit).Limit(q.Limit)
+}
+
+// paginationMetadata describes the page of a normalized q out of total matching rows
+func paginationMetadata(q models.ListQuery, total int64) *models.PaginationMetadata {
+	return response.NewPagination(q.Page, q.Limit, total, q.Sort, string(q.Order))
+}
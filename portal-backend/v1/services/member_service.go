@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/portal-backend/idp"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/database"
 	"gorm.io/gorm"
 )
 
@@ -27,52 +28,66 @@ func NewMemberService(db *gorm.DB, idp idp.IdentityProviderAPI) *MemberService {
 // CreateMember creates a new Member and automatically adds them to the "OpenDIF_Members" group in the IDP.
 // This ensures all newly created members are automatically assigned to the member group.
 func (s *MemberService) CreateMember(ctx context.Context, req *models.CreateMemberRequest) (*models.MemberResponse, error) {
+	member := models.Member{
+		MemberID:    "mem_" + uuid.New().String(),
+		Name:        req.Name,
+		Email:       req.Email,
+		PhoneNumber: req.PhoneNumber,
+	}
+	err := s.provisionMember(ctx, &member, models.UserGroupMember, func(member *models.Member) error {
+		return s.db.Create(member).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Created member successfully", "memberID", member.MemberID, "email", member.Email)
+	return s.buildMemberResponse(&member), nil
+}
+
+// provisionMember creates the IDP user of a new member, adds it to group and then stores the member
+// with store, which receives the member with its IdpUserID set. If any step fails, the IDP changes
+// made so far are rolled back.
+func (s *MemberService) provisionMember(ctx context.Context, member *models.Member, group models.UserGroup, store func(member *models.Member) error) error {
 	// Create user in the IDP
 	userInstance := &idp.User{
-		Email:       req.Email,
-		FirstName:   req.Name,
+		Email:       member.Email,
+		FirstName:   member.Name,
 		LastName:    "",
-		PhoneNumber: req.PhoneNumber,
+		PhoneNumber: member.PhoneNumber,
 	}
 	createdUser, err := s.idp.CreateUser(ctx, userInstance)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user in IDP: %w", err)
+		return fmt.Errorf("failed to create user in IDP: %w", err)
 	}
 	if createdUser.Email != userInstance.Email {
 		deleteErr := (s.idp).DeleteUser(ctx, createdUser.Id)
 		if deleteErr != nil {
-			return nil, fmt.Errorf("IDP user email mismatch, and failed to rollback user creation in IDP: %w", deleteErr)
+			return fmt.Errorf("IDP user email mismatch, and failed to rollback user creation in IDP: %w", deleteErr)
 		}
-		return nil, fmt.Errorf("IDP user email mismatch: expected %s, got %s", userInstance.Email, createdUser.Email)
+		return fmt.Errorf("IDP user email mismatch: expected %s, got %s", userInstance.Email, createdUser.Email)
 	}
 	slog.Info("Created user in IDP", "userID", createdUser.Id, "email", createdUser.Email)
 
-	// Automatically add user to "OpenDIF_Members" group in the IDP
-	// This is a core requirement: all new members must be assigned to the member group
+	// Add the user to the group that grants its role
 	groupMember := &idp.GroupMember{
 		Value:   createdUser.Id,
 		Display: createdUser.Email,
 	}
-	groupId, err := s.idp.AddMemberToGroupByGroupName(ctx, string(models.UserGroupMember), groupMember)
+	groupId, err := s.idp.AddMemberToGroupByGroupName(ctx, string(group), groupMember)
 	if err != nil {
 		// Rollback: Delete the user we just created
 		deleteErr := s.idp.DeleteUser(ctx, createdUser.Id)
 		if deleteErr != nil {
-			return nil, fmt.Errorf("failed to add user to group %s: %w (rollback also failed: %v)", models.UserGroupMember, err, deleteErr)
+			return fmt.Errorf("failed to add user to group %s: %w (rollback also failed: %v)", group, err, deleteErr)
 		}
-		return nil, fmt.Errorf("failed to add user to group %s: %w", models.UserGroupMember, err)
+		return fmt.Errorf("failed to add user to group %s: %w", group, err)
 	}
-	slog.Info("Added user to group", "userID", createdUser.Id, "groupId", *groupId, "groupName", models.UserGroupMember)
+	slog.Info("Added user to group", "userID", createdUser.Id, "groupId", *groupId, "groupName", group)
 
 	// Create Member in the database
-	member := models.Member{
-		MemberID:    "mem_" + uuid.New().String(),
-		Name:        req.Name,
-		Email:       req.Email,
-		PhoneNumber: req.PhoneNumber,
-		IdpUserID:   createdUser.Id,
-	}
-	if dbErr := s.db.Create(&member).Error; dbErr != nil {
+	member.IdpUserID = createdUser.Id
+	if dbErr := store(member); dbErr != nil {
 		// Rollback: Remove user from group and delete user from IDP
 		var rollbackErrs []error
 		if removeErr := s.idp.RemoveMemberFromGroup(ctx, *groupId, createdUser.Id); removeErr != nil {
@@ -82,13 +97,168 @@ func (s *MemberService) CreateMember(ctx context.Context, req *models.CreateMemb
 			rollbackErrs = append(rollbackErrs, fmt.Errorf("rollback user deletion: %w", deleteErr))
 		}
 		if len(rollbackErrs) > 0 {
-			return nil, fmt.Errorf("failed to create member in database: %w, rollback errors: %v", dbErr, errors.Join(rollbackErrs...))
+			return fmt.Errorf("failed to create member in database: %w, rollback errors: %v", dbErr, errors.Join(rollbackErrs...))
 		}
-		return nil, fmt.Errorf("failed to create member in database: %w", dbErr)
+		return fmt.Errorf("failed to create member in database: %w", dbErr)
 	}
+	return nil
+}
 
-	slog.Info("Created member successfully", "memberID", member.MemberID, "email", member.Email)
-	return s.buildMemberResponse(&member), nil
+// ImportMembers creates a member for each row of a staff list. The IDP users are created and added to
+// the members group with bulk requests where the IDP supports them. Each row succeeds or fails on its
+// own: invalid rows and emails already used by a member are reported without calling the IDP, and a
+// row failing after its IDP user was created has the user deleted again.
+func (s *MemberService) ImportMembers(ctx context.Context, req *models.BulkImportMembersRequest) (*models.BulkOperationResponse, error) {
+	if len(req.Members) == 0 {
+		return nil, fmt.Errorf("%w: no members given", ErrInvalidBulkRequest)
+	}
+	if len(req.Members) > models.MaxMemberImportRows {
+		return nil, fmt.Errorf("%w: at most %d members can be imported at once", ErrInvalidBulkRequest, models.MaxMemberImportRows)
+	}
+
+	rowErrs := make([]error, len(req.Members))
+	memberIDs := make([]string, len(req.Members))
+
+	// Reject invalid rows and emails repeated in the import
+	rowByEmail := make(map[string]int, len(req.Members))
+	for i := range req.Members {
+		row := &req.Members[i]
+		if err := models.Validate(row); err != nil {
+			rowErrs[i] = err
+			continue
+		}
+		if first, ok := rowByEmail[row.Email]; ok {
+			rowErrs[i] = fmt.Errorf("email %s is already used by row %d", row.Email, first+1)
+			continue
+		}
+		rowByEmail[row.Email] = i
+	}
+
+	// Reject emails of existing members; deleted members keep their email
+	if len(rowByEmail) > 0 {
+		emails := make([]string, 0, len(rowByEmail))
+		for email := range rowByEmail {
+			emails = append(emails, email)
+		}
+		var existing []string
+		if err := s.db.WithContext(ctx).Unscoped().Model(&models.Member{}).Where("email IN ?", emails).Pluck("email", &existing).Error; err != nil {
+			return nil, fmt.Errorf("failed to look up existing members: %w", err)
+		}
+		for _, email := range existing {
+			rowErrs[rowByEmail[email]] = fmt.Errorf("a member with email %s already exists", email)
+		}
+	}
+
+	var rows []int
+	var users []*idp.User
+	for i, row := range req.Members {
+		if rowErrs[i] == nil {
+			rows = append(rows, i)
+			users = append(users, &idp.User{Email: row.Email, FirstName: row.Name, PhoneNumber: row.PhoneNumber})
+		}
+	}
+	if len(rows) > 0 {
+		s.importRows(ctx, req, rows, users, rowErrs, memberIDs)
+	}
+
+	response := &models.BulkOperationResponse{Operation: BulkOperationImportMembers, Results: make([]models.BulkItemResult, 0, len(req.Members))}
+	for i := range req.Members {
+		result := models.BulkItemResult{Type: models.BulkResourceMember, ID: memberIDs[i], Row: i + 1}
+		if rowErrs[i] == nil {
+			result.Status = "created"
+		}
+		addBulkResult(response, result, rowErrs[i])
+	}
+	slog.Info("Imported members", "succeeded", response.Succeeded, "failed", response.Failed)
+	return response, nil
+}
+
+// importRows provisions the validated rows of an import, whose IDP users are users, recording the
+// member ID or the error of each row
+func (s *MemberService) importRows(ctx context.Context, req *models.BulkImportMembersRequest, rows []int, users []*idp.User, rowErrs []error, memberIDs []string) {
+	failAll := func(err error) {
+		for _, i := range rows {
+			if rowErrs[i] == nil {
+				rowErrs[i] = err
+			}
+		}
+	}
+
+	created, err := idp.BulkCreateUsers(ctx, s.idp, users)
+	if err != nil {
+		failAll(fmt.Errorf("failed to create user in IDP: %w", err))
+		return
+	}
+	userIDs := make(map[int]string, len(rows))
+	var members []*idp.GroupMember
+	var memberRows []int
+	for j, i := range rows {
+		if created[j].Err != nil {
+			rowErrs[i] = fmt.Errorf("failed to create user in IDP: %w", created[j].Err)
+			continue
+		}
+		userIDs[i] = created[j].User.Id
+		members = append(members, &idp.GroupMember{Value: created[j].User.Id, Display: created[j].User.Email})
+		memberRows = append(memberRows, i)
+	}
+	if len(members) == 0 {
+		return
+	}
+
+	// Add the users to the group that grants the member role
+	group := models.UserGroupMember
+	groupId, err := s.idp.GetGroupByName(ctx, string(group))
+	var groupErrs []error
+	if err == nil {
+		groupErrs, err = idp.BulkAddToGroup(ctx, s.idp, *groupId, members)
+	}
+	for j, i := range memberRows {
+		addErr := err
+		if addErr == nil {
+			addErr = groupErrs[j]
+		}
+		if addErr != nil {
+			rowErrs[i] = s.rollbackImportedUser(ctx, fmt.Errorf("failed to add user to group %s: %w", group, addErr), userIDs[i], nil)
+		}
+	}
+
+	// Create the members in the database
+	for _, i := range memberRows {
+		if rowErrs[i] != nil {
+			continue
+		}
+		row := req.Members[i]
+		member := models.Member{
+			MemberID:    "mem_" + uuid.New().String(),
+			Name:        row.Name,
+			Email:       row.Email,
+			PhoneNumber: row.PhoneNumber,
+			IdpUserID:   userIDs[i],
+		}
+		if dbErr := s.db.WithContext(ctx).Create(&member).Error; dbErr != nil {
+			rowErrs[i] = s.rollbackImportedUser(ctx, fmt.Errorf("failed to create member in database: %w", dbErr), userIDs[i], groupId)
+			continue
+		}
+		memberIDs[i] = member.MemberID
+	}
+}
+
+// rollbackImportedUser removes the IDP user of a failed import row from the group, if it was added to
+// it, and deletes it, returning the row's error together with any rollback errors
+func (s *MemberService) rollbackImportedUser(ctx context.Context, rowErr error, userId string, groupId *string) error {
+	var rollbackErrs []error
+	if groupId != nil {
+		if err := s.idp.RemoveMemberFromGroup(ctx, *groupId, userId); err != nil {
+			rollbackErrs = append(rollbackErrs, fmt.Errorf("rollback group removal: %w", err))
+		}
+	}
+	if err := s.idp.DeleteUser(ctx, userId); err != nil {
+		rollbackErrs = append(rollbackErrs, fmt.Errorf("rollback user deletion: %w", err))
+	}
+	if len(rollbackErrs) > 0 {
+		return fmt.Errorf("%w, rollback errors: %v", rowErr, errors.Join(rollbackErrs...))
+	}
+	return rowErr
 }
 
 // UpdateMember updates an existing Member
@@ -98,6 +268,10 @@ func (s *MemberService) UpdateMember(ctx context.Context, memberID string, req *
 	if err != nil {
 		return nil, fmt.Errorf("member not found: %w", err)
 	}
+	// Reject a stale update before it reaches the IDP
+	if err := checkRevision(req.Revision, member.Revision); err != nil {
+		return nil, revisionConflict(s.GetMember(ctx, memberID))
+	}
 
 	// Store original values for rollback if needed
 	originalName := member.Name
@@ -134,7 +308,7 @@ func (s *MemberService) UpdateMember(ctx context.Context, memberID string, req *
 	}
 
 	// Update member in database
-	if err := s.db.Save(&member).Error; err != nil {
+	if err := saveRevision(s.db, &member, &member.Revision); err != nil {
 		// Rollback IDP user update if DB operation fails
 		if needsIdpUpdate {
 			rollbackUser := &idp.User{
@@ -149,6 +323,9 @@ func (s *MemberService) UpdateMember(ctx context.Context, memberID string, req *
 			}
 			slog.Warn("Rolled back IDP user update due to database failure", "userID", member.IdpUserID)
 		}
+		if errors.Is(err, ErrRevisionConflict) {
+			return nil, revisionConflict(s.GetMember(ctx, memberID))
+		}
 		return nil, fmt.Errorf("failed to update member in database: %w", err)
 	}
 
@@ -212,15 +389,128 @@ func (s *MemberService) getAllMembers(ctx context.Context) ([]models.MemberRespo
 	return response, nil
 }
 
+// memberListColumns describes how member collections are searched and sorted
+var memberListColumns = listColumns{
+	sortable: map[string]string{
+		"createdAt": "created_at",
+		"updatedAt": "updated_at",
+		"name":      "name",
+		"email":     "email",
+	},
+	search: "name",
+	key:    "member_id",
+}
+
+// ListMembers retrieves a page of members, filtered by IdP user ID, email, name and creation time
+func (s *MemberService) ListMembers(ctx context.Context, q models.ListQuery) ([]models.MemberResponse, *models.PaginationMetadata, error) {
+	order, err := memberListColumns.normalize(&q)
+	if err != nil {
+		return nil, nil, err
+	}
+	query := memberListColumns.filter(database.Reader(s.db).WithContext(ctx).Model(&models.Member{}), q)
+	if q.IdpUserID != nil && *q.IdpUserID != "" {
+		query = query.Where("idp_user_id = ?", *q.IdpUserID)
+	}
+	if q.Email != nil && *q.Email != "" {
+		query = query.Where("email = ?", *q.Email)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count members: %w", err)
+	}
+
+	var members []models.Member
+	if err := page(query.Order(order), q).Find(&members).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch members: %w", err)
+	}
+
+	response := make([]models.MemberResponse, len(members))
+	for i, member := range members {
+		response[i] = *s.buildMemberResponse(&member)
+	}
+	return response, paginationMetadata(q, total), nil
+}
+
 // buildMemberResponse converts a Member model to MemberResponse
 func (s *MemberService) buildMemberResponse(member *models.Member) *models.MemberResponse {
 	return &models.MemberResponse{
-		MemberID:    member.MemberID,
-		IdpUserID:   member.IdpUserID,
-		Name:        member.Name,
-		Email:       member.Email,
-		PhoneNumber: member.PhoneNumber,
-		CreatedAt:   member.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   member.UpdatedAt.Format(time.RFC3339),
+		MemberID:         member.MemberID,
+		IdpUserID:        member.IdpUserID,
+		Name:             member.Name,
+		Email:            member.Email,
+		PhoneNumber:      member.PhoneNumber,
+		OrganizationID:   member.OrganizationID,
+		OrganizationRole: member.OrganizationRole,
+		CreatedAt:        member.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        member.UpdatedAt.Format(time.RFC3339),
+		Revision:         member.Revision,
+	}
+}
+
+// memberOwnedModels are the resources soft-deleted and restored together with their member
+var memberOwnedModels = []interface{}{
+	&models.Schema{},
+	&models.SchemaSubmission{},
+	&models.Application{},
+	&models.ApplicationSubmission{},
+}
+
+// DeleteMember soft-deletes a member together with the schemas, applications and submissions it owns,
+// and flags the application fields provided by its schemas as invalid. The IdP user is left in place;
+// a deleted member can no longer be resolved, so its user loses access to member resources.
+func (s *MemberService) DeleteMember(ctx context.Context, memberID, deletedBy string) error {
+	deletedAt := deletionTime()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		affected, err := softDelete(tx, &models.Member{}, deletedAt, deletedBy, "member_id = ?", memberID)
+		if err != nil {
+			return fmt.Errorf("failed to delete member: %w", err)
+		}
+		if affected == 0 {
+			return ErrResourceNotFound
+		}
+
+		var schemaIDs []string
+		if err := tx.Model(&models.Schema{}).Where("member_id = ?", memberID).Pluck("schema_id", &schemaIDs).Error; err != nil {
+			return fmt.Errorf("failed to list member schemas: %w", err)
+		}
+		for _, model := range memberOwnedModels {
+			if _, err := softDelete(tx, model, deletedAt, deletedBy, "member_id = ?", memberID); err != nil {
+				return fmt.Errorf("failed to delete member resources: %w", err)
+			}
+		}
+		return setSchemaFieldsInvalid(tx, schemaIDs, true)
+	})
+}
+
+// RestoreMember restores a deleted member and the resources that were deleted along with it.
+// Resources deleted individually before the member keep their deletion.
+func (s *MemberService) RestoreMember(ctx context.Context, memberID string) (*models.MemberResponse, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var member models.Member
+		if err := findDeleted(tx, &member, &member.DeletedAt, "member_id = ?", memberID); err != nil {
+			return err
+		}
+		deletedAt := member.DeletedAt.Time
+
+		var schemaIDs []string
+		if err := tx.Unscoped().Model(&models.Schema{}).Where("member_id = ? AND deleted_at = ?", memberID, deletedAt).
+			Pluck("schema_id", &schemaIDs).Error; err != nil {
+			return fmt.Errorf("failed to list member schemas: %w", err)
+		}
+		for _, model := range memberOwnedModels {
+			if err := restoreDeleted(tx, model, "member_id = ? AND deleted_at = ?", memberID, deletedAt); err != nil {
+				return fmt.Errorf("failed to restore member resources: %w", err)
+			}
+		}
+		if err := restoreDeleted(tx, &models.Member{}, "member_id = ?", memberID); err != nil {
+			return fmt.Errorf("failed to restore member: %w", err)
+		}
+		return setSchemaFieldsInvalid(tx, schemaIDs, false)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return s.GetMember(ctx, memberID)
 }
@@ -34,6 +34,9 @@ type MockIDP struct {
 	DeleteGroupFunc        func(ctx context.Context, groupID string) error
 	GetApplicationInfoFunc func(ctx context.Context, applicationID string) (*idp.ApplicationInfo, error)
 	GetApplicationOIDCFunc func(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error)
+
+	RegenerateApplicationSecretFunc  func(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error)
+	RevokeApplicationCredentialsFunc func(ctx context.Context, applicationID string) error
 }
 
 func (m *MockIDP) CreateUser(ctx context.Context, user *idp.User) (*idp.UserInfo, error) {
@@ -80,6 +83,9 @@ func (m *MockIDP) GetGroup(ctx context.Context, groupID string) (*idp.GroupInfo,
 }
 
 func (m *MockIDP) GetGroupByName(ctx context.Context, groupName string) (*string, error) {
+	if m.GetGroupByNameFunc != nil {
+		return m.GetGroupByNameFunc(ctx, groupName)
+	}
 	return nil, nil
 }
 
@@ -92,6 +98,9 @@ func (m *MockIDP) UpdateGroup(ctx context.Context, groupID string, group *idp.Gr
 }
 
 func (m *MockIDP) AddMemberToGroup(ctx context.Context, groupID string, memberInfo *idp.GroupMember) error {
+	if m.AddMemberToGroupFunc != nil {
+		return m.AddMemberToGroupFunc(ctx, groupID, memberInfo)
+	}
 	return nil
 }
 
@@ -129,6 +138,23 @@ func (m *MockIDP) GetApplicationOIDC(ctx context.Context, applicationID string)
 	}, nil
 }
 
+func (m *MockIDP) RegenerateApplicationSecret(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error) {
+	if m.RegenerateApplicationSecretFunc != nil {
+		return m.RegenerateApplicationSecretFunc(ctx, applicationID)
+	}
+	return &idp.ApplicationOIDCInfo{
+		ClientId:     "mock-client-id",
+		ClientSecret: "mock-regenerated-secret",
+	}, nil
+}
+
+func (m *MockIDP) RevokeApplicationCredentials(ctx context.Context, applicationID string) error {
+	if m.RevokeApplicationCredentialsFunc != nil {
+		return m.RevokeApplicationCredentialsFunc(ctx, applicationID)
+	}
+	return nil
+}
+
 // setupMemberMockDB creates a mock database for testing
 func setupMemberMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
 	var db *sql.DB
@@ -578,3 +604,81 @@ func TestGetAllMembers_WithEmailFilter(t *testing.T) {
 	assert.Equal(t, "john@example.com", result[0].Email)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestImportMembers(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	assert.NoError(t, db.Create(&models.Member{MemberID: "mem_existing", Name: "Existing", Email: "existing@example.com", PhoneNumber: "1", IdpUserID: "idp_existing"}).Error)
+
+	var deleted, created []string
+	groupID := "group_members"
+	mockIDP := &MockIDP{
+		CreateUserFunc: func(ctx context.Context, user *idp.User) (*idp.UserInfo, error) {
+			if user.Email == "rejected@example.com" {
+				return nil, errors.New("user already exists in IDP")
+			}
+			created = append(created, user.Email)
+			return &idp.UserInfo{Id: "idp_" + user.Email, Email: user.Email}, nil
+		},
+		GetGroupByNameFunc: func(ctx context.Context, groupName string) (*string, error) {
+			assert.Equal(t, string(models.UserGroupMember), groupName)
+			return &groupID, nil
+		},
+		AddMemberToGroupFunc: func(ctx context.Context, groupID string, member *idp.GroupMember) error {
+			if member.Display == "nogroup@example.com" {
+				return errors.New("group patch failed")
+			}
+			return nil
+		},
+		DeleteUserFunc: func(ctx context.Context, userID string) error {
+			deleted = append(deleted, userID)
+			return nil
+		},
+	}
+	service := NewMemberService(db, mockIDP)
+
+	response, err := service.ImportMembers(context.Background(), &models.BulkImportMembersRequest{Members: []models.CreateMemberRequest{
+		{Name: "Jane", Email: "jane@example.com", PhoneNumber: "+94771234567"},
+		{Name: "No Phone", Email: "nophone@example.com"},
+		{Name: "Jane Again", Email: "jane@example.com", PhoneNumber: "2"},
+		{Name: "Existing", Email: "existing@example.com", PhoneNumber: "3"},
+		{Name: "Rejected", Email: "rejected@example.com", PhoneNumber: "4"},
+		{Name: "No Group", Email: "nogroup@example.com", PhoneNumber: "5"},
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, BulkOperationImportMembers, response.Operation)
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, 5, response.Failed)
+	assert.Len(t, response.Results, 6)
+	for i, result := range response.Results {
+		assert.Equal(t, i+1, result.Row)
+		assert.Equal(t, models.BulkResourceMember, result.Type)
+	}
+
+	assert.True(t, response.Results[0].Success)
+	var member models.Member
+	assert.NoError(t, db.First(&member, "member_id = ?", response.Results[0].ID).Error)
+	assert.Equal(t, "idp_jane@example.com", member.IdpUserID)
+
+	assert.Contains(t, response.Results[1].Error, "phoneNumber is required")
+	assert.Equal(t, "email jane@example.com is already used by row 1", response.Results[2].Error)
+	assert.Equal(t, "a member with email existing@example.com already exists", response.Results[3].Error)
+	assert.Equal(t, "failed to create user in IDP: user already exists in IDP", response.Results[4].Error)
+	assert.Equal(t, "failed to add user to group OpenDIF_Members: group patch failed", response.Results[5].Error)
+	assert.Empty(t, response.Results[5].ID)
+
+	// Only valid new rows reach the IDP, and users that could not join the group are deleted
+	assert.Equal(t, []string{"jane@example.com", "nogroup@example.com"}, created)
+	assert.Equal(t, []string{"idp_nogroup@example.com"}, deleted)
+}
+
+func TestImportMembers_RowLimits(t *testing.T) {
+	service := NewMemberService(SetupSQLiteTestDB(t), &MockIDP{})
+
+	_, err := service.ImportMembers(context.Background(), &models.BulkImportMembersRequest{})
+	assert.ErrorIs(t, err, ErrInvalidBulkRequest)
+
+	rows := make([]models.CreateMemberRequest, models.MaxMemberImportRows+1)
+	_, err = service.ImportMembers(context.Background(), &models.BulkImportMembersRequest{Members: rows})
+	assert.ErrorIs(t, err, ErrInvalidBulkRequest)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// GetMemberSessions lists the active sign-in sessions of a member's IDP user, most recently used first
+func (s *MemberService) GetMemberSessions(ctx context.Context, memberID string) (*models.MemberSessionsResponse, error) {
+	member, err := s.getMemberRecord(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := idp.GetUserSessions(ctx, s.idp, member.IdpUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions from IDP: %w", err)
+	}
+
+	response := &models.MemberSessionsResponse{
+		MemberID: member.MemberID,
+		Sessions: make([]models.MemberSessionResponse, len(sessions)),
+		Count:    len(sessions),
+	}
+	for i, session := range sortSessionsByLastAccess(sessions) {
+		applications := session.Applications
+		if applications == nil {
+			applications = []string{}
+		}
+		response.Sessions[i] = models.MemberSessionResponse{
+			SessionID:      session.Id,
+			LoginTime:      formatSessionTime(session.LoginTime),
+			LastAccessTime: formatSessionTime(session.LastAccessTime),
+			IPAddress:      session.IPAddress,
+			UserAgent:      session.UserAgent,
+			Applications:   applications,
+		}
+	}
+	return response, nil
+}
+
+// RevokeMemberSessions terminates every sign-in session of a member's IDP user. Access tokens already
+// issued stay valid until they expire; the member has to sign in again to get new ones.
+func (s *MemberService) RevokeMemberSessions(ctx context.Context, memberID string) (*models.RevokeMemberSessionsResponse, error) {
+	member, err := s.getMemberRecord(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The count is informational, so revocation goes ahead when the sessions cannot be listed
+	sessions, err := idp.GetUserSessions(ctx, s.idp, member.IdpUserID)
+	if err != nil && !errors.Is(err, idp.ErrSessionsNotSupported) {
+		slog.Warn("Failed to count member sessions before revoking them", "memberID", memberID, "error", err)
+	}
+	if err := idp.RevokeUserSessions(ctx, s.idp, member.IdpUserID); err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions in IDP: %w", err)
+	}
+
+	slog.Info("Revoked member sessions", "memberID", memberID, "sessions", len(sessions))
+	return &models.RevokeMemberSessionsResponse{
+		MemberID:        member.MemberID,
+		RevokedSessions: len(sessions),
+		RevokedAt:       time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// getMemberRecord fetches a member, returning ErrResourceNotFound if there is none
+func (s *MemberService) getMemberRecord(ctx context.Context, memberID string) (*models.Member, error) {
+	var member models.Member
+	if err := s.db.WithContext(ctx).First(&member, "member_id = ?", memberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch member: %w", err)
+	}
+	return &member, nil
+}
+
+// sortSessionsByLastAccess orders sessions by their last access, most recent first
+func sortSessionsByLastAccess(sessions []idp.UserSession) []idp.UserSession {
+	sorted := slices.Clone(sessions)
+	slices.SortStableFunc(sorted, func(a, b idp.UserSession) int {
+		return b.LastAccessTime.Compare(a.LastAccessTime)
+	})
+	return sorted
+}
+
+// formatSessionTime formats a session time as RFC 3339, or returns nil if it is not known
+func formatSessionTime(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	formatted := t.UTC().Format(time.RFC3339)
+	return &formatted
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionIDP is a fake identity provider that exposes user sessions
+type sessionIDP struct {
+	MockIDP
+	sessions  map[string][]idp.UserSession
+	revoked   []string
+	revokeErr error
+}
+
+func (m *sessionIDP) GetUserSessions(ctx context.Context, userID string) ([]idp.UserSession, error) {
+	return m.sessions[userID], nil
+}
+
+func (m *sessionIDP) RevokeUserSessions(ctx context.Context, userID string) error {
+	if m.revokeErr != nil {
+		return m.revokeErr
+	}
+	m.revoked = append(m.revoked, userID)
+	return nil
+}
+
+func TestMemberService_GetMemberSessions(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	login := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	provider := &sessionIDP{sessions: map[string][]idp.UserSession{
+		"idp-consumer": {
+			{Id: "s1", LoginTime: login, LastAccessTime: login.Add(time.Hour), IPAddress: "10.0.0.1"},
+			{Id: "s2", LoginTime: login, LastAccessTime: login.Add(2 * time.Hour), Applications: []string{"Member Portal"}},
+			{Id: "s3"},
+		},
+	}}
+	service := NewMemberService(db, provider)
+
+	response, err := service.GetMemberSessions(context.Background(), "mem_consumer")
+	require.NoError(t, err)
+	assert.Equal(t, "mem_consumer", response.MemberID)
+	assert.Equal(t, 3, response.Count)
+	assert.Equal(t, "s2", response.Sessions[0].SessionID, "most recently used first")
+	assert.Equal(t, []string{"Member Portal"}, response.Sessions[0].Applications)
+	assert.Equal(t, "2026-10-01T10:00:00Z", *response.Sessions[0].LastAccessTime)
+	assert.Equal(t, "10.0.0.1", response.Sessions[1].IPAddress)
+	assert.Nil(t, response.Sessions[2].LoginTime, "unknown times are omitted")
+	assert.Equal(t, []string{}, response.Sessions[2].Applications)
+
+	_, err = service.GetMemberSessions(context.Background(), "mem_missing")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestMemberService_GetMemberSessions_NotSupported(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewMemberService(db, &MockIDP{})
+
+	_, err := service.GetMemberSessions(context.Background(), "mem_consumer")
+	assert.ErrorIs(t, err, idp.ErrSessionsNotSupported)
+	_, err = service.RevokeMemberSessions(context.Background(), "mem_consumer")
+	assert.ErrorIs(t, err, idp.ErrSessionsNotSupported)
+}
+
+func TestMemberService_RevokeMemberSessions(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	provider := &sessionIDP{sessions: map[string][]idp.UserSession{"idp-provider": {{Id: "s1"}, {Id: "s2"}}}}
+	service := NewMemberService(db, provider)
+
+	response, err := service.RevokeMemberSessions(context.Background(), "mem_provider")
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.RevokedSessions)
+	assert.NotEmpty(t, response.RevokedAt)
+	assert.Equal(t, []string{"idp-provider"}, provider.revoked)
+
+	provider.revokeErr = errors.New("status code: 500")
+	_, err = service.RevokeMemberSessions(context.Background(), "mem_provider")
+	assert.ErrorContains(t, err, "failed to revoke sessions in IDP")
+}
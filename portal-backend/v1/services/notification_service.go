@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidNotificationEvent is returned when a preference names an unknown notification event
+var ErrInvalidNotificationEvent = errors.New("unknown notification event")
+
+// NotificationService notifies members of events that concern them. Each notification is stored
+// in the notifications table, where it is listed in-app; its email copy is queued on the same row
+// and delivered by the notification worker, so a slow or failing SMTP server never blocks the
+// request that caused the notification.
+type NotificationService struct {
+	db *gorm.DB
+	// email sends notification emails; nil disables email
+	email EmailSender
+	// webhooks queues the webhook events of credential expiry notices; nil disables them
+	webhooks *WebhookService
+}
+
+// NewNotificationService creates a new notification service. A nil email sender disables email,
+// and a nil webhook service disables credential expiry webhooks.
+func NewNotificationService(db *gorm.DB, email EmailSender, webhooks *WebhookService) *NotificationService {
+	return &NotificationService{db: db, email: email, webhooks: webhooks}
+}
+
+// NotifySubmissionStatusChanged tells the owner of a submission that its status changed to status
+func (s *NotificationService) NotifySubmissionStatusChanged(ctx context.Context, submissionType models.SubmissionType, submissionID, status string) error {
+	var memberID, name string
+	var resourceType models.ResourceType
+	switch submissionType {
+	case models.SubmissionTypeSchema:
+		var submission models.SchemaSubmission
+		if err := s.db.WithContext(ctx).Select("member_id", "schema_name").
+			First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+			return fmt.Errorf("failed to get schema submission: %w", err)
+		}
+		memberID, name, resourceType = submission.MemberID, submission.SchemaName, models.ResourceTypeSchemaSubmissions
+	case models.SubmissionTypeApplication:
+		var submission models.ApplicationSubmission
+		if err := s.db.WithContext(ctx).Select("member_id", "application_name").
+			First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+			return fmt.Errorf("failed to get application submission: %w", err)
+		}
+		memberID, name, resourceType = submission.MemberID, submission.ApplicationName, models.ResourceTypeApplicationSubmissions
+	default:
+		return fmt.Errorf("unknown submission type: %s", submissionType)
+	}
+
+	var title, message string
+	switch models.Status(status) {
+	case models.StatusApproved:
+		title = fmt.Sprintf("Your %s submission was approved", submissionType)
+		message = fmt.Sprintf("Your %s submission %q was approved.", submissionType, name)
+	case models.StatusRejected:
+		title = fmt.Sprintf("Your %s submission was rejected", submissionType)
+		message = fmt.Sprintf("Your %s submission %q was rejected. See the submission for the review.", submissionType, name)
+	default:
+		title = fmt.Sprintf("Your %s submission moved to %s", submissionType, status)
+		message = fmt.Sprintf("Your %s submission %q is now at the %s step.", submissionType, name, status)
+	}
+	return s.notify(ctx, memberID, models.NotificationEventSubmissionStatusChanged, title, message, resourceType, submissionID)
+}
+
+// NotifyMembershipApproved welcomes a member that an admin has onboarded
+func (s *NotificationService) NotifyMembershipApproved(ctx context.Context, memberID string) error {
+	var member models.Member
+	if err := s.db.WithContext(ctx).Select("member_id", "name").First(&member, "member_id = ?", memberID).Error; err != nil {
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+	message := fmt.Sprintf("Welcome %s, your membership was approved. You can now submit schemas and applications.", member.Name)
+	return s.notify(ctx, memberID, models.NotificationEventMembershipApproved,
+		"Your membership was approved", message, models.ResourceTypeMembers, memberID)
+}
+
+// NotifySchemaDeprecated tells the owner of every application that selected fields of a schema that
+// the schema was deprecated, sunset or retired, and returns how many applications were notified
+func (s *NotificationService) NotifySchemaDeprecated(ctx context.Context, schemaID string) (int, error) {
+	var schema models.Schema
+	if err := s.db.WithContext(ctx).Select("schema_id", "schema_name", "version", "sunset_at", "deprecation_message").
+		First(&schema, "schema_id = ?", schemaID).Error; err != nil {
+		return 0, fmt.Errorf("failed to get schema: %w", err)
+	}
+	consumers, err := schemaConsumers(ctx, s.db, schemaID)
+	if err != nil {
+		return 0, err
+	}
+
+	var title, detail string
+	switch models.Version(schema.Version) {
+	case models.RetiredVersion:
+		title = "A schema your application uses was retired"
+		detail = "was retired and no longer serves data"
+	case models.SunsetVersion:
+		title = "A schema your application uses has reached its sunset date"
+		detail = "has reached its sunset date and will be retired"
+	default:
+		title = "A schema your application uses was deprecated"
+		detail = "was deprecated"
+		if schema.SunsetAt != nil {
+			detail += " and will be sunset on " + schema.SunsetAt.Format(time.RFC1123)
+		}
+	}
+
+	notified := 0
+	for _, consumer := range consumers {
+		message := fmt.Sprintf("Schema %q, which provides %s to application %q, %s.",
+			schema.SchemaName, strings.Join(consumer.Fields, ", "), consumer.ApplicationName, detail)
+		if schema.DeprecationMessage != nil && *schema.DeprecationMessage != "" {
+			message += " " + *schema.DeprecationMessage
+		}
+		if err := s.notify(ctx, consumer.MemberID, models.NotificationEventSchemaDeprecated,
+			title, message, models.ResourceTypeApplications, consumer.ApplicationID); err != nil {
+			return notified, err
+		}
+		notified++
+	}
+	return notified, nil
+}
+
+// NotifyExpiringCredentials tells application owners about active credentials that expire within
+// notice. Each credential is notified once; it returns how many were notified.
+func (s *NotificationService) NotifyExpiringCredentials(ctx context.Context, notice time.Duration) (int, error) {
+	type expiringCredential struct {
+		CredentialID    string
+		ApplicationID   string
+		ApplicationName string
+		MemberID        string
+		ExpiresAt       time.Time
+	}
+	var credentials []expiringCredential
+	if err := s.db.WithContext(ctx).Model(&models.ApplicationCredential{}).
+		Select("application_credentials.credential_id, application_credentials.application_id, applications.application_name, applications.member_id, application_credentials.expires_at").
+		Joins("JOIN applications ON applications.application_id = application_credentials.application_id AND applications.deleted_at IS NULL").
+		Where("application_credentials.status = ? AND application_credentials.expires_at <= ? AND application_credentials.expiry_notified_at IS NULL",
+			models.ApplicationCredentialStatusActive, time.Now().Add(notice)).
+		Scan(&credentials).Error; err != nil {
+		return 0, fmt.Errorf("failed to find expiring credentials: %w", err)
+	}
+
+	notified := 0
+	for _, credential := range credentials {
+		// Claim the credential first so that a concurrent worker does not notify it twice
+		result := s.db.WithContext(ctx).Model(&models.ApplicationCredential{}).
+			Where("credential_id = ? AND expiry_notified_at IS NULL", credential.CredentialID).
+			Update("expiry_notified_at", time.Now())
+		if result.Error != nil {
+			return notified, fmt.Errorf("failed to mark credential as notified: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		message := fmt.Sprintf("The client credential of application %q expires on %s. Rotate it before then to keep access.",
+			credential.ApplicationName, credential.ExpiresAt.Format(time.RFC1123))
+		if err := s.notify(ctx, credential.MemberID, models.NotificationEventCredentialExpiring,
+			"Application credential expiring", message, models.ResourceTypeApplications, credential.ApplicationID); err != nil {
+			return notified, err
+		}
+		if s.webhooks != nil {
+			if err := s.webhooks.EnqueueCredentialExpiring(ctx, credential.ApplicationID, credential.CredentialID, credential.ExpiresAt); err != nil {
+				return notified, err
+			}
+		}
+		notified++
+	}
+	return notified, nil
+}
+
+// NotifyGrantExpiring tells the owner of an application that its field grants are about to expire and
+// points them to the renewal submission opened for them
+func (s *NotificationService) NotifyGrantExpiring(ctx context.Context, grant ExpiringGrant) error {
+	message := fmt.Sprintf("The access of application %q to its selected fields expires on %s. A renewal submission was opened for review; once it is approved the access is renewed.",
+		grant.ApplicationName, grant.ExpiresAt.Format(time.RFC1123))
+	return s.notify(ctx, grant.MemberID, models.NotificationEventGrantExpiring,
+		"Application access expiring", message, models.ResourceTypeApplicationSubmissions, grant.SubmissionID)
+}
+
+// DeliverPendingEmails sends up to batchSize queued notification emails and returns how many were
+// attempted. A failed email is not retried; its error is kept on the notification.
+func (s *NotificationService) DeliverPendingEmails(ctx context.Context, batchSize int) (int, error) {
+	if s.email == nil {
+		return 0, nil
+	}
+
+	var notifications []models.Notification
+	if err := s.db.WithContext(ctx).Where("email_status = ?", models.NotificationEmailStatusPending).
+		Order("created_at ASC").Limit(batchSize).Find(&notifications).Error; err != nil {
+		return 0, fmt.Errorf("failed to get pending notification emails: %w", err)
+	}
+
+	for _, notification := range notifications {
+		updates := map[string]interface{}{
+			"email_status": models.NotificationEmailStatusSent,
+			"updated_at":   time.Now(),
+		}
+		if err := s.sendEmail(ctx, notification); err != nil {
+			slog.Warn("Failed to send notification email",
+				"notificationID", notification.NotificationID,
+				"memberID", notification.MemberID,
+				"error", err)
+			updates["email_status"] = models.NotificationEmailStatusFailed
+			updates["email_error"] = err.Error()
+		}
+		if err := s.db.WithContext(ctx).Model(&models.Notification{}).
+			Where("notification_id = ?", notification.NotificationID).
+			Updates(updates).Error; err != nil {
+			return 0, fmt.Errorf("failed to record notification email status: %w", err)
+		}
+	}
+	return len(notifications), nil
+}
+
+// ListNotifications returns the in-app notifications of a member, newest first
+func (s *NotificationService) ListNotifications(ctx context.Context, memberID string, unreadOnly bool) ([]models.NotificationResponse, error) {
+	query := s.db.WithContext(ctx).Where("member_id = ? AND in_app = ?", memberID, true)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC").Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	responses := make([]models.NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		responses = append(responses, notificationResponseOf(notification))
+	}
+	return responses, nil
+}
+
+// MarkRead marks an in-app notification of a member as read
+func (s *NotificationService) MarkRead(ctx context.Context, memberID, notificationID string) (*models.NotificationResponse, error) {
+	var notification models.Notification
+	if err := s.db.WithContext(ctx).
+		First(&notification, "notification_id = ? AND member_id = ? AND in_app = ?", notificationID, memberID, true).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		if err := s.db.WithContext(ctx).Model(&notification).Update("read_at", now).Error; err != nil {
+			return nil, fmt.Errorf("failed to mark notification as read: %w", err)
+		}
+	}
+
+	response := notificationResponseOf(notification)
+	return &response, nil
+}
+
+// GetPreferences returns how a member receives each notification event
+func (s *NotificationService) GetPreferences(ctx context.Context, memberID string) ([]models.NotificationPreferenceSetting, error) {
+	var preferences []models.NotificationPreference
+	if err := s.db.WithContext(ctx).Where("member_id = ?", memberID).Find(&preferences).Error; err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	stored := make(map[models.NotificationEvent]models.NotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		stored[preference.Event] = preference
+	}
+
+	settings := make([]models.NotificationPreferenceSetting, 0, len(models.NotificationEvents))
+	for _, event := range models.NotificationEvents {
+		setting := models.NotificationPreferenceSetting{Event: event, InApp: true, Email: true}
+		if preference, ok := stored[event]; ok {
+			setting.InApp, setting.Email = preference.InApp, preference.Email
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// UpdatePreferences stores a member's preferences for the listed events and returns all of them
+func (s *NotificationService) UpdatePreferences(ctx context.Context, memberID string, settings []models.NotificationPreferenceSetting) ([]models.NotificationPreferenceSetting, error) {
+	for i, setting := range settings {
+		if !setting.Event.IsValid() {
+			return nil, models.NewValidationError(ErrInvalidNotificationEvent, models.ValidationErrorInvalidValue,
+				fmt.Sprintf("preferences[%d].event", i), fmt.Sprintf("unknown event %s", setting.Event))
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, setting := range settings {
+			preference := models.NotificationPreference{
+				MemberID: memberID,
+				Event:    setting.Event,
+				InApp:    setting.InApp,
+				Email:    setting.Email,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "member_id"}, {Name: "event"}},
+				DoUpdates: clause.AssignmentColumns([]string{"in_app", "email", "updated_at"}),
+			}).Create(&preference).Error; err != nil {
+				return fmt.Errorf("failed to save notification preference: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, memberID)
+}
+
+// notify records a notification for a member according to their preferences
+func (s *NotificationService) notify(ctx context.Context, memberID string, event models.NotificationEvent, title, message string, resourceType models.ResourceType, resourceID string) error {
+	inApp, email := true, true
+	var preference models.NotificationPreference
+	err := s.db.WithContext(ctx).First(&preference, "member_id = ? AND event = ?", memberID, event).Error
+	switch {
+	case err == nil:
+		inApp, email = preference.InApp, preference.Email
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("failed to get notification preference: %w", err)
+	}
+
+	emailStatus := models.NotificationEmailStatusSkipped
+	if email && s.email != nil {
+		emailStatus = models.NotificationEmailStatusPending
+	}
+	if !inApp && emailStatus == models.NotificationEmailStatusSkipped {
+		return nil
+	}
+
+	notification := models.Notification{
+		NotificationID: "ntf_" + uuid.New().String(),
+		MemberID:       memberID,
+		Event:          event,
+		Title:          title,
+		Message:        message,
+		ResourceType:   resourceType,
+		ResourceID:     resourceID,
+		InApp:          inApp,
+		EmailStatus:    emailStatus,
+	}
+	if err := s.db.WithContext(ctx).Create(&notification).Error; err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// sendEmail emails a notification to its member
+func (s *NotificationService) sendEmail(ctx context.Context, notification models.Notification) error {
+	var member models.Member
+	if err := s.db.WithContext(ctx).Select("email").First(&member, "member_id = ?", notification.MemberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("member %s not found", notification.MemberID)
+		}
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+	return s.email.Send(member.Email, notification.Title, notification.Message)
+}
+
+func notificationResponseOf(notification models.Notification) models.NotificationResponse {
+	response := models.NotificationResponse{
+		NotificationID: notification.NotificationID,
+		Event:          notification.Event,
+		Title:          notification.Title,
+		Message:        notification.Message,
+		ResourceType:   notification.ResourceType,
+		ResourceID:     notification.ResourceID,
+		Read:           notification.ReadAt != nil,
+		CreatedAt:      notification.CreatedAt.Format(time.RFC3339),
+	}
+	if notification.ReadAt != nil {
+		readAt := notification.ReadAt.Format(time.RFC3339)
+		response.ReadAt = &readAt
+	}
+	return response
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmailSender records the emails it is asked to send and fails for addresses in failFor
+type fakeEmailSender struct {
+	sent    []string
+	failFor map[string]bool
+}
+
+func (f *fakeEmailSender) Send(to, subject, body string) error {
+	if f.failFor[to] {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, to+": "+subject)
+	return nil
+}
+
+func TestNotificationService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	email := &fakeEmailSender{failFor: map[string]bool{}}
+	webhookService := NewWebhookService(db)
+	service := NewNotificationService(db, email, webhookService)
+
+	t.Run("SubmissionStatusChanged", func(t *testing.T) {
+		require.NoError(t, service.NotifySubmissionStatusChanged(ctx, models.SubmissionTypeSchema, "sub_schema", "technical_review"))
+		require.NoError(t, service.NotifySubmissionStatusChanged(ctx, models.SubmissionTypeSchema, "sub_schema", string(models.StatusRejected)))
+
+		notifications, err := service.ListNotifications(ctx, "mem_provider", false)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		assert.Equal(t, models.NotificationEventSubmissionStatusChanged, notifications[0].Event)
+		assert.Equal(t, models.ResourceTypeSchemaSubmissions, notifications[0].ResourceType)
+		assert.Equal(t, "sub_schema", notifications[0].ResourceID)
+		assert.False(t, notifications[0].Read)
+
+		others, err := service.ListNotifications(ctx, "mem_consumer", false)
+		require.NoError(t, err)
+		assert.Empty(t, others)
+
+		assert.Error(t, service.NotifySubmissionStatusChanged(ctx, models.SubmissionTypeApplication, "sub_missing", "approved"))
+	})
+
+	t.Run("MarkRead", func(t *testing.T) {
+		notifications, err := service.ListNotifications(ctx, "mem_provider", true)
+		require.NoError(t, err)
+		require.NotEmpty(t, notifications)
+		notificationID := notifications[0].NotificationID
+
+		_, err = service.MarkRead(ctx, "mem_consumer", notificationID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		read, err := service.MarkRead(ctx, "mem_provider", notificationID)
+		require.NoError(t, err)
+		assert.True(t, read.Read)
+		assert.NotNil(t, read.ReadAt)
+
+		unread, err := service.ListNotifications(ctx, "mem_provider", true)
+		require.NoError(t, err)
+		assert.Len(t, unread, len(notifications)-1)
+	})
+
+	t.Run("Preferences", func(t *testing.T) {
+		preferences, err := service.GetPreferences(ctx, "mem_consumer")
+		require.NoError(t, err)
+		require.Len(t, preferences, len(models.NotificationEvents))
+		for _, preference := range preferences {
+			assert.True(t, preference.InApp)
+			assert.True(t, preference.Email)
+		}
+
+		_, err = service.UpdatePreferences(ctx, "mem_consumer", []models.NotificationPreferenceSetting{{Event: "unknown"}})
+		assert.ErrorIs(t, err, ErrInvalidNotificationEvent)
+
+		// Email only, then updating the same event again replaces the stored preference
+		for _, inApp := range []bool{true, false} {
+			preferences, err = service.UpdatePreferences(ctx, "mem_consumer", []models.NotificationPreferenceSetting{
+				{Event: models.NotificationEventSubmissionStatusChanged, InApp: inApp, Email: true},
+			})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, models.NotificationPreferenceSetting{Event: models.NotificationEventSubmissionStatusChanged, InApp: false, Email: true}, preferences[0])
+
+		require.NoError(t, service.NotifySubmissionStatusChanged(ctx, models.SubmissionTypeApplication, "sub_app", string(models.StatusApproved)))
+		notifications, err := service.ListNotifications(ctx, "mem_consumer", false)
+		require.NoError(t, err)
+		assert.Empty(t, notifications, "in-app notifications are turned off")
+
+		var queued int64
+		require.NoError(t, db.Model(&models.Notification{}).
+			Where("member_id = ? AND email_status = ?", "mem_consumer", models.NotificationEmailStatusPending).Count(&queued).Error)
+		assert.Equal(t, int64(1), queued)
+	})
+
+	t.Run("DeliverPendingEmails", func(t *testing.T) {
+		email.failFor["provider@example.com"] = true
+		sent, err := service.DeliverPendingEmails(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 3, sent)
+		assert.Equal(t, []string{"consumer@example.com: Your application submission was approved"}, email.sent)
+
+		var failed []models.Notification
+		require.NoError(t, db.Where("email_status = ?", models.NotificationEmailStatusFailed).Find(&failed).Error)
+		require.Len(t, failed, 2)
+		require.NotNil(t, failed[0].EmailError)
+		assert.Contains(t, *failed[0].EmailError, "mailbox unavailable")
+
+		// Failed emails are not retried
+		sent, err = service.DeliverPendingEmails(ctx, 10)
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+
+	t.Run("WithoutEmail", func(t *testing.T) {
+		inAppOnly := NewNotificationService(db, nil, nil)
+		require.NoError(t, inAppOnly.NotifyMembershipApproved(ctx, "mem_provider"))
+
+		var notification models.Notification
+		require.NoError(t, db.Where("member_id = ? AND event = ?", "mem_provider", models.NotificationEventMembershipApproved).
+			First(&notification).Error)
+		assert.Equal(t, models.NotificationEmailStatusSkipped, notification.EmailStatus)
+		assert.True(t, notification.InApp)
+	})
+
+	t.Run("ExpiringCredentials", func(t *testing.T) {
+		soon := time.Now().Add(24 * time.Hour)
+		later := time.Now().Add(60 * 24 * time.Hour)
+		credentials := []models.ApplicationCredential{
+			{CredentialID: "cred_soon", ApplicationID: "app_1", ClientID: "client-1", SecretHint: "1111", Status: models.ApplicationCredentialStatusActive, IssuedBy: "idp-consumer", ExpiresAt: &soon},
+			{CredentialID: "cred_later", ApplicationID: "app_1", ClientID: "client-1", SecretHint: "2222", Status: models.ApplicationCredentialStatusActive, IssuedBy: "idp-consumer", ExpiresAt: &later},
+			{CredentialID: "cred_rotated", ApplicationID: "app_1", ClientID: "client-1", SecretHint: "3333", Status: models.ApplicationCredentialStatusRotated, IssuedBy: "idp-consumer", ExpiresAt: &soon},
+			{CredentialID: "cred_forever", ApplicationID: "app_1", ClientID: "client-1", SecretHint: "4444", Status: models.ApplicationCredentialStatusActive, IssuedBy: "idp-consumer"},
+		}
+		require.NoError(t, db.Create(&credentials).Error)
+		webhook, err := webhookService.CreateWebhook(ctx, "app_1", "idp-consumer", &models.CreateWebhookRequest{
+			URL:    "https://consumer.example.com/hooks",
+			Events: []models.WebhookEvent{models.WebhookEventCredentialExpiring},
+		})
+		require.NoError(t, err)
+
+		worker := NewNotificationWorker(service, time.Minute, 7*24*time.Hour)
+		worker.RunOnce(ctx)
+
+		var notifications []models.Notification
+		require.NoError(t, db.Where("event = ?", models.NotificationEventCredentialExpiring).Find(&notifications).Error)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "mem_consumer", notifications[0].MemberID)
+		assert.Equal(t, "app_1", notifications[0].ResourceID)
+		assert.Equal(t, models.NotificationEmailStatusSent, notifications[0].EmailStatus)
+
+		// The application's webhook gets the notice too
+		var deliveries []models.WebhookDelivery
+		require.NoError(t, db.Where("webhook_id = ?", webhook.WebhookID).Find(&deliveries).Error)
+		require.Len(t, deliveries, 1)
+		assert.Contains(t, deliveries[0].Payload, `"credentialId":"cred_soon"`)
+
+		// A credential is only notified once
+		notified, err := service.NotifyExpiringCredentials(ctx, 7*24*time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, notified)
+	})
+}
+
+func TestNotificationService_NotifySchemaDeprecated(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+	service := NewNotificationService(db, nil, nil)
+
+	sunsetAt := time.Now().Add(30 * 24 * time.Hour)
+	message := "Use the Person v2 schema."
+	require.NoError(t, db.Model(&models.Schema{}).Where("schema_id = ?", "sch_1").Updates(map[string]interface{}{
+		"version": string(models.DeprecatedVersion), "sunset_at": sunsetAt, "deprecation_message": message,
+	}).Error)
+
+	notified, err := service.NotifySchemaDeprecated(ctx, "sch_1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, notified)
+
+	notifications, err := service.ListNotifications(ctx, "mem_consumer", false)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, models.NotificationEventSchemaDeprecated, notifications[0].Event)
+	assert.Equal(t, models.ResourceTypeApplications, notifications[0].ResourceType)
+	assert.Equal(t, "app_1", notifications[0].ResourceID)
+	assert.Contains(t, notifications[0].Message, "person.name")
+	assert.Contains(t, notifications[0].Message, message)
+
+	providerNotifications, err := service.ListNotifications(ctx, "mem_provider", false)
+	require.NoError(t, err)
+	assert.Empty(t, providerNotifications, "only consumers of the schema are notified")
+
+	_, err = service.NotifySchemaDeprecated(ctx, "sch_missing")
+	assert.Error(t, err)
+}
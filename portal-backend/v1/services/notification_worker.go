@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultNotificationWorkerBatchSize is how many queued emails the worker sends per poll
+const DefaultNotificationWorkerBatchSize = 50
+
+// NotificationWorker periodically notifies owners of expiring credentials and delivers queued
+// notification emails
+type NotificationWorker struct {
+	notificationService *NotificationService
+	// interval is how often the worker polls
+	interval time.Duration
+	// expiryNotice is how long before a credential expires its owner is notified
+	expiryNotice time.Duration
+	// batchSize is the maximum number of emails sent per poll
+	batchSize int
+}
+
+// NewNotificationWorker creates a new notification worker
+func NewNotificationWorker(notificationService *NotificationService, interval, expiryNotice time.Duration) *NotificationWorker {
+	return &NotificationWorker{
+		notificationService: notificationService,
+		interval:            interval,
+		expiryNotice:        expiryNotice,
+		batchSize:           DefaultNotificationWorkerBatchSize,
+	}
+}
+
+// Start runs the notification loop until the context is cancelled
+func (w *NotificationWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		slog.Info("Notification worker disabled")
+		return
+	}
+
+	slog.Info("Notification worker started", "interval", w.interval, "expiryNotice", w.expiryNotice, "batchSize", w.batchSize)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Notification worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce notifies expiring credentials and sends queued emails, returning how many emails were sent
+func (w *NotificationWorker) RunOnce(ctx context.Context) int {
+	notified, err := w.notificationService.NotifyExpiringCredentials(ctx, w.expiryNotice)
+	if err != nil {
+		slog.Error("Failed to notify expiring credentials", "error", err)
+	}
+	if notified > 0 {
+		slog.Info("Notified expiring credentials", "count", notified)
+	}
+
+	sent, err := w.notificationService.DeliverPendingEmails(ctx, w.batchSize)
+	if err != nil {
+		slog.Error("Failed to deliver notification emails", "error", err)
+	}
+	if sent > 0 {
+		slog.Info("Delivered notification emails", "count", sent)
+	}
+	return sent
+}
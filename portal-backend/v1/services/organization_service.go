@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOrganizationConflict is returned when another organization already has the requested name
+	ErrOrganizationConflict = errors.New("an organization with this name already exists")
+	// ErrInvalidOrganizationRole is returned for an organization role other than org_admin or org_member
+	ErrInvalidOrganizationRole = errors.New("organization role must be org_admin or org_member")
+	// ErrMemberInAnotherOrganization is returned when adding a member that already belongs to another organization
+	ErrMemberInAnotherOrganization = errors.New("member already belongs to another organization")
+	// ErrLastOrganizationAdmin is returned when removing or demoting the only org_admin of an organization
+	ErrLastOrganizationAdmin = errors.New("an organization must keep at least one org_admin")
+)
+
+// OrganizationService manages organizations and their membership. Schemas, applications and
+// submissions belong to the organization of the member who created them, so every member of an
+// organization shares their ownership.
+type OrganizationService struct {
+	db            *gorm.DB
+	memberService *MemberService
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(db *gorm.DB, memberService *MemberService) *OrganizationService {
+	return &OrganizationService{db: db, memberService: memberService}
+}
+
+// CreateOrganization creates a new organization without members
+func (s *OrganizationService) CreateOrganization(ctx context.Context, req *models.CreateOrganizationRequest) (*models.OrganizationResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkNameAvailable(ctx, name, ""); err != nil {
+		return nil, err
+	}
+
+	organization := models.Organization{
+		OrganizationID: "org_" + uuid.New().String(),
+		Name:           name,
+		Description:    req.Description,
+		Sector:         normalizeSector(req.Sector),
+		ProviderType:   normalizeProviderType(req.ProviderType),
+	}
+	if err := s.db.WithContext(ctx).Create(&organization).Error; err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	slog.Info("Organization created", "organizationID", organization.OrganizationID)
+	return organizationResponseOf(organization), nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (s *OrganizationService) GetOrganization(ctx context.Context, organizationID string) (*models.OrganizationResponse, error) {
+	organization, err := s.findOrganization(s.db.WithContext(ctx), organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return organizationResponseOf(*organization), nil
+}
+
+// ListOrganizations returns organizations sorted by name; when organizationID is set only that
+// organization is returned
+func (s *OrganizationService) ListOrganizations(ctx context.Context, organizationID *string) ([]models.OrganizationResponse, error) {
+	query := s.db.WithContext(ctx).Order("name ASC")
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	}
+	var organizations []models.Organization
+	if err := query.Find(&organizations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	responses := make([]models.OrganizationResponse, 0, len(organizations))
+	for _, organization := range organizations {
+		responses = append(responses, *organizationResponseOf(organization))
+	}
+	return responses, nil
+}
+
+// UpdateOrganization updates the name, description, sector and provider type of an organization
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, organizationID string, req *models.UpdateOrganizationRequest) (*models.OrganizationResponse, error) {
+	organization, err := s.findOrganization(s.db.WithContext(ctx), organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := s.checkNameAvailable(ctx, name, organizationID); err != nil {
+			return nil, err
+		}
+		organization.Name = name
+	}
+	if req.Description != nil {
+		organization.Description = req.Description
+	}
+	if req.Sector != nil {
+		organization.Sector = normalizeSector(req.Sector)
+	}
+	if req.ProviderType != nil {
+		organization.ProviderType = normalizeProviderType(req.ProviderType)
+	}
+	if err := s.db.WithContext(ctx).Save(organization).Error; err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	return organizationResponseOf(*organization), nil
+}
+
+// ListOrganizationMembers returns the members of an organization
+func (s *OrganizationService) ListOrganizationMembers(ctx context.Context, organizationID string) ([]models.MemberResponse, error) {
+	if _, err := s.findOrganization(s.db.WithContext(ctx), organizationID); err != nil {
+		return nil, err
+	}
+
+	var members []models.Member
+	if err := s.db.WithContext(ctx).Where("organization_id = ?", organizationID).Order("name ASC").Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+
+	responses := make([]models.MemberResponse, 0, len(members))
+	for i := range members {
+		responses = append(responses, *s.memberService.buildMemberResponse(&members[i]))
+	}
+	return responses, nil
+}
+
+// SetOrganizationMember adds a member to an organization with role, or changes the role of one of
+// its members. A member joining an organization brings along the schemas, applications and
+// submissions they created outside of any organization.
+func (s *OrganizationService) SetOrganizationMember(ctx context.Context, organizationID, memberID string, role models.OrganizationRole) (*models.MemberResponse, error) {
+	if !role.IsValid() {
+		return nil, models.NewValidationError(ErrInvalidOrganizationRole, models.ValidationErrorInvalidValue, "role", fmt.Sprintf("unknown organization role %s", role))
+	}
+
+	var member models.Member
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := s.findOrganization(tx, organizationID); err != nil {
+			return err
+		}
+		if err := tx.First(&member, "member_id = ?", memberID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrResourceNotFound
+			}
+			return fmt.Errorf("failed to get member: %w", err)
+		}
+
+		joining := member.OrganizationID == nil
+		if !joining && *member.OrganizationID != organizationID {
+			return ErrMemberInAnotherOrganization
+		}
+		if !joining && member.OrganizationRole == models.OrganizationRoleAdmin && role != models.OrganizationRoleAdmin {
+			if err := ensureOtherOrganizationAdmin(tx, organizationID, memberID); err != nil {
+				return err
+			}
+		}
+
+		member.OrganizationID = &organizationID
+		member.OrganizationRole = role
+		if err := tx.Model(&member).Updates(map[string]interface{}{
+			"organization_id":   organizationID,
+			"organization_role": role,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update member organization: %w", err)
+		}
+		if joining {
+			return adoptMemberResources(tx, memberID, organizationID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Organization member set", "organizationID", organizationID, "memberID", memberID, "role", role)
+	return s.memberService.buildMemberResponse(&member), nil
+}
+
+// RemoveOrganizationMember removes a member from an organization. The schemas, applications and
+// submissions they created stay with the organization.
+func (s *OrganizationService) RemoveOrganizationMember(ctx context.Context, organizationID, memberID string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var member models.Member
+		if err := tx.First(&member, "member_id = ? AND organization_id = ?", memberID, organizationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrResourceNotFound
+			}
+			return fmt.Errorf("failed to get member: %w", err)
+		}
+		if member.OrganizationRole == models.OrganizationRoleAdmin {
+			if err := ensureOtherOrganizationAdmin(tx, organizationID, memberID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&member).Updates(map[string]interface{}{
+			"organization_id":   nil,
+			"organization_role": "",
+		}).Error; err != nil {
+			return fmt.Errorf("failed to remove member from organization: %w", err)
+		}
+		slog.Info("Organization member removed", "organizationID", organizationID, "memberID", memberID)
+		return nil
+	})
+}
+
+// MigrateMembersToOrganizations moves the resources of existing members into organizations. It only
+// runs while no organization exists: each member gets an organization named after them, which they
+// administer and which adopts their schemas, applications and submissions. It returns the number of
+// organizations created.
+func MigrateMembersToOrganizations(ctx context.Context, db *gorm.DB) (int, error) {
+	created := 0
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var organizations int64
+		if err := tx.Model(&models.Organization{}).Count(&organizations).Error; err != nil {
+			return fmt.Errorf("failed to count organizations: %w", err)
+		}
+		if organizations > 0 {
+			return nil
+		}
+
+		var members []models.Member
+		if err := tx.Where("organization_id IS NULL").Order("created_at ASC").Find(&members).Error; err != nil {
+			return fmt.Errorf("failed to list members: %w", err)
+		}
+		names := make(map[string]bool, len(members))
+		for _, member := range members {
+			// Organization names are unique, member names are not
+			name := member.Name
+			if names[name] {
+				name = fmt.Sprintf("%s (%s)", member.Name, member.MemberID)
+			}
+			names[name] = true
+
+			organization := models.Organization{OrganizationID: "org_" + uuid.New().String(), Name: name}
+			if err := tx.Create(&organization).Error; err != nil {
+				return fmt.Errorf("failed to create organization for member %s: %w", member.MemberID, err)
+			}
+			if err := tx.Model(&member).Updates(map[string]interface{}{
+				"organization_id":   organization.OrganizationID,
+				"organization_role": models.OrganizationRoleAdmin,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to move member %s to organization: %w", member.MemberID, err)
+			}
+			if err := adoptMemberResources(tx, member.MemberID, organization.OrganizationID); err != nil {
+				return err
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// memberOrganizationID returns the organization of a member, which owns the resources they create
+func memberOrganizationID(db *gorm.DB, memberID string) (*string, error) {
+	var member models.Member
+	if err := db.Select("member_id", "organization_id").First(&member, "member_id = ?", memberID).Error; err != nil {
+		return nil, fmt.Errorf("member not found: %w", err)
+	}
+	return member.OrganizationID, nil
+}
+
+// adoptMemberResources moves the resources a member created outside of any organization, including
+// soft-deleted ones, into organizationID
+func adoptMemberResources(tx *gorm.DB, memberID, organizationID string) error {
+	for _, model := range memberOwnedModels {
+		if err := tx.Unscoped().Model(model).
+			Where("member_id = ? AND organization_id IS NULL", memberID).
+			Update("organization_id", organizationID).Error; err != nil {
+			return fmt.Errorf("failed to move member resources to organization: %w", err)
+		}
+	}
+	return nil
+}
+
+// ensureOtherOrganizationAdmin returns ErrLastOrganizationAdmin unless the organization has an
+// org_admin other than memberID
+func ensureOtherOrganizationAdmin(tx *gorm.DB, organizationID, memberID string) error {
+	var admins int64
+	if err := tx.Model(&models.Member{}).
+		Where("organization_id = ? AND organization_role = ? AND member_id <> ?", organizationID, models.OrganizationRoleAdmin, memberID).
+		Count(&admins).Error; err != nil {
+		return fmt.Errorf("failed to count organization admins: %w", err)
+	}
+	if admins == 0 {
+		return ErrLastOrganizationAdmin
+	}
+	return nil
+}
+
+func (s *OrganizationService) findOrganization(db *gorm.DB, organizationID string) (*models.Organization, error) {
+	var organization models.Organization
+	if err := db.First(&organization, "organization_id = ?", organizationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &organization, nil
+}
+
+// checkNameAvailable returns ErrOrganizationConflict if an organization other than exceptID is named name
+func (s *OrganizationService) checkNameAvailable(ctx context.Context, name, exceptID string) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Organization{}).
+		Where("name = ? AND organization_id <> ?", name, exceptID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check organization name: %w", err)
+	}
+	if count > 0 {
+		return ErrOrganizationConflict
+	}
+	return nil
+}
+
+// normalizeSector lower-cases a sector so that policy conditions match it regardless of case; an
+// empty sector clears it
+func normalizeSector(sector *string) *string {
+	if sector == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*sector))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+func organizationResponseOf(organization models.Organization) *models.OrganizationResponse {
+	return &models.OrganizationResponse{
+		OrganizationID: organization.OrganizationID,
+		Name:           organization.Name,
+		Description:    organization.Description,
+		Sector:         organization.Sector,
+		ProviderType:   organization.ProviderType,
+		CreatedAt:      organization.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      organization.UpdatedAt.Format(time.RFC3339),
+	}
+}
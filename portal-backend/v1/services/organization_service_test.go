@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	service := NewOrganizationService(db, NewMemberService(db, &MockIDP{}))
+
+	var organization *models.OrganizationResponse
+	t.Run("CreateOrganization", func(t *testing.T) {
+		var err error
+		organization, err = service.CreateOrganization(ctx, &models.CreateOrganizationRequest{Name: " Registrar General "})
+		require.NoError(t, err)
+		assert.Equal(t, "Registrar General", organization.Name)
+
+		_, err = service.CreateOrganization(ctx, &models.CreateOrganizationRequest{Name: "Registrar General"})
+		assert.ErrorIs(t, err, ErrOrganizationConflict)
+
+		other, err := service.CreateOrganization(ctx, &models.CreateOrganizationRequest{Name: "Motor Traffic"})
+		require.NoError(t, err)
+		name := "Registrar General"
+		_, err = service.UpdateOrganization(ctx, other.OrganizationID, &models.UpdateOrganizationRequest{Name: &name})
+		assert.ErrorIs(t, err, ErrOrganizationConflict)
+
+		// Sectors are matched by policy conditions regardless of case; an empty sector clears it
+		sector := " Banking "
+		updated, err := service.UpdateOrganization(ctx, other.OrganizationID, &models.UpdateOrganizationRequest{Sector: &sector})
+		require.NoError(t, err)
+		require.NotNil(t, updated.Sector)
+		assert.Equal(t, "banking", *updated.Sector)
+		sector = ""
+		updated, err = service.UpdateOrganization(ctx, other.OrganizationID, &models.UpdateOrganizationRequest{Sector: &sector})
+		require.NoError(t, err)
+		assert.Nil(t, updated.Sector)
+
+		scoped, err := service.ListOrganizations(ctx, &organization.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, scoped, 1)
+		assert.Equal(t, organization.OrganizationID, scoped[0].OrganizationID)
+	})
+
+	t.Run("SetOrganizationMember", func(t *testing.T) {
+		_, err := service.SetOrganizationMember(ctx, organization.OrganizationID, "mem_provider", "owner")
+		assert.ErrorIs(t, err, ErrInvalidOrganizationRole)
+		_, err = service.SetOrganizationMember(ctx, "org_missing", "mem_provider", models.OrganizationRoleAdmin)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		member, err := service.SetOrganizationMember(ctx, organization.OrganizationID, "mem_provider", models.OrganizationRoleAdmin)
+		require.NoError(t, err)
+		require.NotNil(t, member.OrganizationID)
+		assert.Equal(t, organization.OrganizationID, *member.OrganizationID)
+
+		// Joining brings along the member's schemas and submissions
+		var schema models.Schema
+		require.NoError(t, db.First(&schema, "schema_id = ?", "sch_1").Error)
+		require.NotNil(t, schema.OrganizationID)
+		assert.Equal(t, organization.OrganizationID, *schema.OrganizationID)
+		var submission models.SchemaSubmission
+		require.NoError(t, db.First(&submission, "submission_id = ?", "sub_schema").Error)
+		require.NotNil(t, submission.OrganizationID)
+
+		_, err = service.SetOrganizationMember(ctx, organization.OrganizationID, "mem_provider", models.OrganizationRoleMember)
+		assert.ErrorIs(t, err, ErrLastOrganizationAdmin)
+
+		other, err := service.CreateOrganization(ctx, &models.CreateOrganizationRequest{Name: "Elsewhere"})
+		require.NoError(t, err)
+		_, err = service.SetOrganizationMember(ctx, other.OrganizationID, "mem_provider", models.OrganizationRoleMember)
+		assert.ErrorIs(t, err, ErrMemberInAnotherOrganization)
+
+		members, err := service.ListOrganizationMembers(ctx, organization.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.Equal(t, models.OrganizationRoleAdmin, members[0].OrganizationRole)
+	})
+
+	t.Run("RemoveOrganizationMember", func(t *testing.T) {
+		assert.ErrorIs(t, service.RemoveOrganizationMember(ctx, organization.OrganizationID, "mem_provider"), ErrLastOrganizationAdmin)
+		assert.ErrorIs(t, service.RemoveOrganizationMember(ctx, organization.OrganizationID, "mem_consumer"), ErrResourceNotFound)
+
+		_, err := service.SetOrganizationMember(ctx, organization.OrganizationID, "mem_consumer", models.OrganizationRoleAdmin)
+		require.NoError(t, err)
+		require.NoError(t, service.RemoveOrganizationMember(ctx, organization.OrganizationID, "mem_provider"))
+
+		var member models.Member
+		require.NoError(t, db.First(&member, "member_id = ?", "mem_provider").Error)
+		assert.Nil(t, member.OrganizationID)
+
+		// The schema stays with the organization
+		var schema models.Schema
+		require.NoError(t, db.First(&schema, "schema_id = ?", "sch_1").Error)
+		require.NotNil(t, schema.OrganizationID)
+		assert.Equal(t, organization.OrganizationID, *schema.OrganizationID)
+	})
+}
+
+func TestMigrateMembersToOrganizations(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	created, err := MigrateMembersToOrganizations(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, created)
+
+	var member models.Member
+	require.NoError(t, db.First(&member, "member_id = ?", "mem_consumer").Error)
+	require.NotNil(t, member.OrganizationID)
+	assert.Equal(t, models.OrganizationRoleAdmin, member.OrganizationRole)
+
+	var application models.Application
+	require.NoError(t, db.First(&application, "application_id = ?", "app_1").Error)
+	require.NotNil(t, application.OrganizationID)
+	assert.Equal(t, *member.OrganizationID, *application.OrganizationID)
+
+	// Once organizations exist the migration is a no-op
+	created, err = MigrateMembersToOrganizations(ctx, db)
+	require.NoError(t, err)
+	assert.Zero(t, created)
+}
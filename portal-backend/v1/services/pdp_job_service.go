@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// pdpJobMaxAttempts is how often a job is tried before it is dead-lettered
+	pdpJobMaxAttempts = 8
+	// defaultPDPJobListLimit caps the number of jobs returned by ListJobs
+	defaultPDPJobListLimit = 100
+	// pdpJobBaseBackoff is the delay before the first retry; it doubles with every attempt
+	pdpJobBaseBackoff = 30 * time.Second
+	// pdpJobMaxBackoff caps the delay between retries
+	pdpJobMaxBackoff = time.Hour
+	// pdpJobLease is how long a claimed job is hidden from other workers while it runs
+	pdpJobLease = 5 * time.Minute
+)
+
+var (
+	// ErrPDPJobNotFound is returned when a job does not exist
+	ErrPDPJobNotFound = errors.New("pdp job not found")
+	// ErrPDPJobNotDead is returned when requeueing a job that has not been dead-lettered
+	ErrPDPJobNotDead = errors.New("only dead pdp jobs can be requeued")
+)
+
+// PDPJobService manages the durable queue of PDP sync jobs
+type PDPJobService struct {
+	db         *gorm.DB
+	pdpService *PDPService
+}
+
+// NewPDPJobService creates a new PDP job service
+func NewPDPJobService(db *gorm.DB, pdpService *PDPService) *PDPJobService {
+	return &PDPJobService{db: db, pdpService: pdpService}
+}
+
+// EnqueueCreatePolicyMetadata queues a policy metadata sync for a schema.
+// Pass the caller's transaction so the job is only queued if the schema change commits.
+func (s *PDPJobService) EnqueueCreatePolicyMetadata(tx *gorm.DB, schemaID, providerID, sdl string, fieldConfigurations models.FieldConfigurations) (*models.PDPJob, error) {
+	payload := models.PolicyMetadataJobPayload{SchemaID: schemaID, ProviderID: providerID, SDL: sdl, FieldConfigurations: fieldConfigurations}
+	return s.enqueue(tx, models.PDPJobTypeCreatePolicyMetadata, schemaID, payload)
+}
+
+// EnqueueUpdateAllowList queues an allow list update for an application.
+// Pass the caller's transaction so the job is only queued if the application change commits.
+func (s *PDPJobService) EnqueueUpdateAllowList(tx *gorm.DB, request models.AllowListUpdateRequest) (*models.PDPJob, error) {
+	return s.enqueue(tx, models.PDPJobTypeUpdateAllowList, request.ApplicationID, request)
+}
+
+// EnqueueUpdateSchemaLifecycle queues a schema lifecycle update. Pass the caller's transaction
+// so the job is only queued if the schema change commits.
+func (s *PDPJobService) EnqueueUpdateSchemaLifecycle(tx *gorm.DB, request models.SchemaLifecycleUpdateRequest) (*models.PDPJob, error) {
+	return s.enqueue(tx, models.PDPJobTypeUpdateSchemaLifecycle, request.SchemaID, request)
+}
+
+func (s *PDPJobService) enqueue(tx *gorm.DB, jobType models.PDPJobType, resourceID string, payload interface{}) (*models.PDPJob, error) {
+	if tx == nil {
+		tx = s.db
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pdp job payload: %w", err)
+	}
+
+	job := models.PDPJob{
+		JobID:       "pdpjob_" + uuid.New().String(),
+		JobType:     jobType,
+		ResourceID:  resourceID,
+		Payload:     string(body),
+		Status:      models.PDPJobStatusPending,
+		MaxAttempts: pdpJobMaxAttempts,
+		NextRetryAt: time.Now(),
+	}
+	if err := tx.Create(&job).Error; err != nil {
+		return nil, fmt.Errorf("failed to enqueue pdp job: %w", err)
+	}
+	return &job, nil
+}
+
+// ProcessDueJobs runs up to limit pending jobs whose retry time has passed and returns how many ran.
+// Each job is claimed with a lease first, so several replicas can run workers against the same table.
+func (s *PDPJobService) ProcessDueJobs(limit int) (int, error) {
+	now := time.Now()
+	var jobs []models.PDPJob
+	err := s.db.Where("status = ? AND next_retry_at <= ?", models.PDPJobStatusPending, now).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due pdp jobs: %w", err)
+	}
+
+	processed := 0
+	for i := range jobs {
+		claimed, err := s.claim(&jobs[i], now)
+		if err != nil {
+			return processed, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.run(&jobs[i]); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// claim pushes the job's retry time past the lease, unless another worker already did
+func (s *PDPJobService) claim(job *models.PDPJob, now time.Time) (bool, error) {
+	leaseUntil := now.Add(pdpJobLease)
+	result := s.db.Model(&models.PDPJob{}).
+		Where("job_id = ? AND status = ? AND attempts = ? AND next_retry_at = ?",
+			job.JobID, models.PDPJobStatusPending, job.Attempts, job.NextRetryAt).
+		Updates(map[string]interface{}{"next_retry_at": leaseUntil, "updated_at": now})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim pdp job %s: %w", job.JobID, result.Error)
+	}
+	job.NextRetryAt = leaseUntil
+	return result.RowsAffected == 1, nil
+}
+
+// run performs the job's PDP call and records the result
+func (s *PDPJobService) run(job *models.PDPJob) error {
+	callErr := s.execute(job)
+	now := time.Now()
+	attempts := job.Attempts + 1
+
+	updates := map[string]interface{}{"attempts": attempts, "updated_at": now}
+	switch {
+	case callErr == nil:
+		updates["status"] = models.PDPJobStatusCompleted
+		updates["completed_at"] = now
+		updates["last_error"] = nil
+	case attempts >= job.MaxAttempts:
+		updates["status"] = models.PDPJobStatusDead
+		updates["last_error"] = callErr.Error()
+		slog.Error("PDP job dead-lettered", "jobID", job.JobID, "jobType", job.JobType, "attempts", attempts, "error", callErr)
+	default:
+		updates["next_retry_at"] = now.Add(pdpJobBackoff(attempts))
+		updates["last_error"] = callErr.Error()
+		slog.Warn("PDP job failed, will retry", "jobID", job.JobID, "jobType", job.JobType, "attempts", attempts, "error", callErr)
+	}
+
+	if err := s.db.Model(&models.PDPJob{}).Where("job_id = ?", job.JobID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to record pdp job %s result: %w", job.JobID, err)
+	}
+	return nil
+}
+
+// execute decodes the job payload and calls the PDP
+func (s *PDPJobService) execute(job *models.PDPJob) error {
+	switch job.JobType {
+	case models.PDPJobTypeCreatePolicyMetadata:
+		var payload models.PolicyMetadataJobPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		_, err := s.pdpService.CreatePolicyMetadata(payload.SchemaID, payload.ProviderID, payload.SDL, payload.FieldConfigurations)
+		return err
+	case models.PDPJobTypeUpdateAllowList:
+		var payload models.AllowListUpdateRequest
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		_, err := s.pdpService.UpdateAllowList(payload)
+		return err
+	case models.PDPJobTypeUpdateSchemaLifecycle:
+		var payload models.SchemaLifecycleUpdateRequest
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		_, err := s.pdpService.UpdateSchemaLifecycle(payload)
+		return err
+	default:
+		return fmt.Errorf("unknown pdp job type: %s", job.JobType)
+	}
+}
+
+// pdpJobBackoff returns the delay before the next attempt after the given number of failed attempts
+func pdpJobBackoff(attempts int) time.Duration {
+	backoff := pdpJobBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= pdpJobMaxBackoff {
+			return pdpJobMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// ListJobs returns the most recently updated jobs, optionally filtered by status
+func (s *PDPJobService) ListJobs(status string, limit int) ([]models.PDPJobResponse, error) {
+	if status != "" && !models.PDPJobStatus(status).IsValid() {
+		return nil, fmt.Errorf("invalid pdp job status: %s", status)
+	}
+	if limit <= 0 || limit > defaultPDPJobListLimit {
+		limit = defaultPDPJobListLimit
+	}
+
+	query := s.db.Model(&models.PDPJob{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var jobs []models.PDPJob
+	if err := query.Order("updated_at DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list pdp jobs: %w", err)
+	}
+
+	responses := make([]models.PDPJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		responses = append(responses, pdpJobResponseOf(job))
+	}
+	return responses, nil
+}
+
+// CountPendingJobs returns the number of jobs waiting to be delivered to the PDP
+func (s *PDPJobService) CountPendingJobs(ctx context.Context) (int64, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.PDPJob{}).Where("status = ?", models.PDPJobStatusPending).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count pending pdp jobs: %w", err)
+	}
+	return count, nil
+}
+
+// RequeueJob resets a dead job so the worker tries it again with a fresh attempt budget
+func (s *PDPJobService) RequeueJob(jobID string) (*models.PDPJobResponse, error) {
+	var job models.PDPJob
+	if err := s.db.First(&job, "job_id = ?", jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPDPJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get pdp job: %w", err)
+	}
+	if job.Status != models.PDPJobStatusDead {
+		return nil, ErrPDPJobNotDead
+	}
+
+	now := time.Now()
+	job.Status = models.PDPJobStatusPending
+	job.Attempts = 0
+	job.NextRetryAt = now
+	result := s.db.Model(&models.PDPJob{}).
+		Where("job_id = ? AND status = ?", jobID, models.PDPJobStatusDead).
+		Updates(map[string]interface{}{
+			"status":        job.Status,
+			"attempts":      job.Attempts,
+			"next_retry_at": job.NextRetryAt,
+			"updated_at":    now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to requeue pdp job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPDPJobNotDead
+	}
+	job.UpdatedAt = now
+
+	slog.Info("PDP job requeued", "jobID", job.JobID, "jobType", job.JobType)
+	response := pdpJobResponseOf(job)
+	return &response, nil
+}
+
+func pdpJobResponseOf(job models.PDPJob) models.PDPJobResponse {
+	response := models.PDPJobResponse{
+		JobID:       job.JobID,
+		JobType:     job.JobType,
+		ResourceID:  job.ResourceID,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		NextRetryAt: job.NextRetryAt.Format(time.RFC3339),
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   job.UpdatedAt.Format(time.RFC3339),
+	}
+	if job.CompletedAt != nil {
+		completedAt := job.CompletedAt.Format(time.RFC3339)
+		response.CompletedAt = &completedAt
+	}
+	return response
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newPDPJobTestService returns a job service backed by SQLite, since claiming and retry
+// scheduling depend on real conditional updates, and a PDP stub answering with status
+func newPDPJobTestService(t *testing.T, status *atomic.Int32) (*PDPJobService, *gorm.DB) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(int(status.Load()))
+		_ = json.NewEncoder(w).Encode(models.AllowListUpdateResponse{})
+	}))
+	t.Cleanup(server.Close)
+
+	db := SetupSQLiteTestDB(t)
+	return NewPDPJobService(db, NewPDPService(server.URL, "test-key")), db
+}
+
+func TestPDPJobService_ProcessDueJobs(t *testing.T) {
+	t.Run("completes successful jobs", func(t *testing.T) {
+		var status atomic.Int32
+		status.Store(http.StatusOK)
+		service, db := newPDPJobTestService(t, &status)
+
+		job, err := service.EnqueueUpdateAllowList(nil, models.AllowListUpdateRequest{ApplicationID: "app-1"})
+		require.NoError(t, err)
+
+		processed, err := service.ProcessDueJobs(10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		var stored models.PDPJob
+		require.NoError(t, db.First(&stored, "job_id = ?", job.JobID).Error)
+		assert.Equal(t, models.PDPJobStatusCompleted, stored.Status)
+		assert.Equal(t, 1, stored.Attempts)
+		assert.NotNil(t, stored.CompletedAt)
+		assert.Nil(t, stored.LastError)
+
+		// Completed jobs are not run again
+		processed, err = service.ProcessDueJobs(10)
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+	})
+
+	t.Run("retries with backoff and dead-letters after max attempts", func(t *testing.T) {
+		var status atomic.Int32
+		status.Store(http.StatusServiceUnavailable)
+		service, db := newPDPJobTestService(t, &status)
+
+		job, err := service.EnqueueUpdateAllowList(nil, models.AllowListUpdateRequest{ApplicationID: "app-1"})
+		require.NoError(t, err)
+
+		before := time.Now()
+		processed, err := service.ProcessDueJobs(10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		var stored models.PDPJob
+		require.NoError(t, db.First(&stored, "job_id = ?", job.JobID).Error)
+		assert.Equal(t, models.PDPJobStatusPending, stored.Status)
+		assert.Equal(t, 1, stored.Attempts)
+		require.NotNil(t, stored.LastError)
+		assert.Contains(t, *stored.LastError, "503")
+		assert.True(t, stored.NextRetryAt.After(before.Add(pdpJobBaseBackoff-time.Second)))
+
+		// Not due yet
+		processed, err = service.ProcessDueJobs(10)
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+
+		// Make the job due on its last attempt
+		require.NoError(t, db.Model(&models.PDPJob{}).Where("job_id = ?", job.JobID).
+			Updates(map[string]interface{}{"attempts": pdpJobMaxAttempts - 1, "next_retry_at": time.Now().Add(-time.Second)}).Error)
+		processed, err = service.ProcessDueJobs(10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		require.NoError(t, db.First(&stored, "job_id = ?", job.JobID).Error)
+		assert.Equal(t, models.PDPJobStatusDead, stored.Status)
+		assert.Equal(t, pdpJobMaxAttempts, stored.Attempts)
+
+		// Dead jobs are listed and can be requeued
+		dead, err := service.ListJobs(string(models.PDPJobStatusDead), 0)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, job.JobID, dead[0].JobID)
+
+		requeued, err := service.RequeueJob(job.JobID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PDPJobStatusPending, requeued.Status)
+		assert.Zero(t, requeued.Attempts)
+
+		status.Store(http.StatusOK)
+		processed, err = service.ProcessDueJobs(10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		require.NoError(t, db.First(&stored, "job_id = ?", job.JobID).Error)
+		assert.Equal(t, models.PDPJobStatusCompleted, stored.Status)
+	})
+
+	t.Run("claimed jobs are skipped", func(t *testing.T) {
+		var status atomic.Int32
+		status.Store(http.StatusOK)
+		service, db := newPDPJobTestService(t, &status)
+
+		job, err := service.EnqueueUpdateAllowList(nil, models.AllowListUpdateRequest{ApplicationID: "app-1"})
+		require.NoError(t, err)
+
+		var stored models.PDPJob
+		require.NoError(t, db.First(&stored, "job_id = ?", job.JobID).Error)
+		claimed, err := service.claim(&stored, time.Now())
+		require.NoError(t, err)
+		assert.True(t, claimed)
+
+		// A second worker holding the stale row loses the claim
+		stale := *job
+		claimed, err = service.claim(&stale, time.Now())
+		require.NoError(t, err)
+		assert.False(t, claimed)
+	})
+}
+
+func TestPDPJobService_RequeueJob_Errors(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	service, _ := newPDPJobTestService(t, &status)
+
+	_, err := service.RequeueJob("missing")
+	assert.ErrorIs(t, err, ErrPDPJobNotFound)
+
+	job, err := service.EnqueueUpdateAllowList(nil, models.AllowListUpdateRequest{ApplicationID: "app-1"})
+	require.NoError(t, err)
+	_, err = service.RequeueJob(job.JobID)
+	assert.ErrorIs(t, err, ErrPDPJobNotDead)
+}
+
+func TestPDPJobBackoff(t *testing.T) {
+	assert.Equal(t, pdpJobBaseBackoff, pdpJobBackoff(1))
+	assert.Equal(t, 2*pdpJobBaseBackoff, pdpJobBackoff(2))
+	assert.Equal(t, 8*pdpJobBaseBackoff, pdpJobBackoff(4))
+	assert.Equal(t, pdpJobMaxBackoff, pdpJobBackoff(20))
+}
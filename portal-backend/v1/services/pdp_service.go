@@ -2,13 +2,16 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/gov-dx-sandbox/portal-backend/idp"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
 	"github.com/gov-dx-sandbox/portal-backend/v1/utils"
 )
@@ -19,6 +22,8 @@ type PDPService struct {
 	baseURL string
 	// apiKey is the Choreo API key for internal auth
 	apiKey string
+	// tokens issues the access tokens the PDP authenticates the portal with; nil sends none
+	tokens *idp.TokenSource
 	// HTTPClient is used to make requests to the PDP
 	HTTPClient *http.Client
 }
@@ -32,19 +37,39 @@ func NewPDPService(baseURL string, apiKey string) *PDPService {
 	}
 }
 
-// setAuthHeader is a helper function to add the Choreo API key
-func (s *PDPService) setAuthHeader(req *http.Request) {
+// SetTokenSource authenticates requests to the PDP with access tokens from tokens
+func (s *PDPService) SetTokenSource(tokens *idp.TokenSource) {
+	s.tokens = tokens
+}
+
+// setAuthHeader is a helper function to add the Choreo API key and, when configured, the access token
+func (s *PDPService) setAuthHeader(req *http.Request) error {
 	req.Header.Set("apikey", s.apiKey)
+	if s.tokens == nil {
+		return nil
+	}
+	token, err := s.tokens.Token(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get PDP access token: %w", err)
+	}
+	token.SetAuthHeader(req)
+	return nil
 }
 
-// CreatePolicyMetadata sends a request to create policy metadata in the PDP
-func (s *PDPService) CreatePolicyMetadata(schemaId string, sdl string) (*models.PolicyMetadataCreateResponse, error) {
+// CreatePolicyMetadata sends a request to create policy metadata in the PDP. The field
+// configurations, if any, override the policy directives of the SDL.
+func (s *PDPService) CreatePolicyMetadata(schemaId string, providerId string, sdl string, fieldConfigurations models.FieldConfigurations) (*models.PolicyMetadataCreateResponse, error) {
 	// parse SDL and create policy metadata request
 	handler := utils.NewGraphQLHandler()
 	policyRequest, err := handler.ParseSDLToPolicyRequest(schemaId, sdl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse SDL: %w", err)
 	}
+	policyRequest.Records, err = utils.ApplyFieldConfigurations(policyRequest.Records, sdl, fieldConfigurations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply field configurations: %w", err)
+	}
+	policyRequest.ProviderID = providerId
 
 	// Marshal request to JSON
 	reqBody, err := json.Marshal(policyRequest)
@@ -60,7 +85,9 @@ func (s *PDPService) CreatePolicyMetadata(schemaId string, sdl string) (*models.
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	s.setAuthHeader(httpReq)
+	if err := s.setAuthHeader(httpReq); err != nil {
+		return nil, err
+	}
 
 	// Send request to PDP
 	resp, err := s.HTTPClient.Do(httpReq)
@@ -81,7 +108,10 @@ func (s *PDPService) CreatePolicyMetadata(schemaId string, sdl string) (*models.
 	}
 
 	// Check status code
-	if resp.StatusCode != http.StatusOK {
+	if pendingApproval(resp.StatusCode, respBody) {
+		return &models.PolicyMetadataCreateResponse{}, nil
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		slog.Error("PDP returned error", "status", resp.StatusCode, "body", string(respBody))
 		return nil, fmt.Errorf("PDP returned status %d: %s", resp.StatusCode, string(respBody))
 	}
@@ -112,7 +142,9 @@ func (s *PDPService) UpdateAllowList(request models.AllowListUpdateRequest) (*mo
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	s.setAuthHeader(httpReq)
+	if err := s.setAuthHeader(httpReq); err != nil {
+		return nil, err
+	}
 
 	// Send request
 	slog.Debug("Sending allow list update request to PDP", "url", url, "applicationId", request.ApplicationID)
@@ -134,6 +166,9 @@ func (s *PDPService) UpdateAllowList(request models.AllowListUpdateRequest) (*mo
 	}
 
 	// Check status code
+	if pendingApproval(resp.StatusCode, respBody) {
+		return &models.AllowListUpdateResponse{}, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		slog.Error("PDP returned error", "status", resp.StatusCode, "body", string(respBody))
 		return nil, fmt.Errorf("PDP returned status %d: %s", resp.StatusCode, string(respBody))
@@ -148,3 +183,160 @@ func (s *PDPService) UpdateAllowList(request models.AllowListUpdateRequest) (*mo
 	slog.Info("Successfully updated allow list in PDP", "applicationId", request.ApplicationID, "recordsUpdated", len(response.Records))
 	return &response, nil
 }
+
+// CreateRenewalRequest opens a PDP renewal request for an application's existing grants. The grants
+// are extended only once ReviewRenewalRequest approves it.
+func (s *PDPService) CreateRenewalRequest(request models.AllowListRenewalCreateRequest) (*models.AllowListRenewal, error) {
+	var renewal models.AllowListRenewal
+	if err := s.post("/api/v1/policy/renewal-requests", request, &renewal); err != nil {
+		return nil, err
+	}
+	slog.Info("Successfully opened renewal request in PDP", "applicationId", request.ApplicationID, "renewalId", renewal.ID)
+	return &renewal, nil
+}
+
+// ReviewRenewalRequest approves or rejects a pending PDP renewal request. Approving it extends the
+// application's grants by the requested grant duration.
+func (s *PDPService) ReviewRenewalRequest(renewalID string, request models.AllowListRenewalReviewRequest) (*models.AllowListRenewal, error) {
+	var renewal models.AllowListRenewal
+	if err := s.put("/api/v1/policy/renewal-requests/"+url.PathEscape(renewalID), request, &renewal); err != nil {
+		return nil, err
+	}
+	slog.Info("Successfully reviewed renewal request in PDP", "applicationId", renewal.ApplicationID, "renewalId", renewalID, "status", renewal.Status)
+	return &renewal, nil
+}
+
+// ExportPolicyDocument fetches the field policies of every schema from the PDP
+func (s *PDPService) ExportPolicyDocument() (*models.PolicyDocument, error) {
+	var document models.PolicyDocument
+	if err := s.get("/api/v1/policy/export?format=json", &document); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// ListClassifications fetches the PDP's classification tiers and their consent rules
+func (s *PDPService) ListClassifications() ([]models.ClassificationRule, error) {
+	var response models.ClassificationListResponse
+	if err := s.get("/api/v1/policy/classifications", &response); err != nil {
+		return nil, err
+	}
+	return response.Records, nil
+}
+
+// ListApplicationGrants fetches every field the PDP allow-lists an application for, including
+// expired grants
+func (s *PDPService) ListApplicationGrants(applicationID string) ([]models.ConsumerGrant, error) {
+	var response models.ConsumerGrantListResponse
+	if err := s.get("/api/v1/policy/grants?includeExpired=true&applicationId="+url.QueryEscape(applicationID), &response); err != nil {
+		return nil, err
+	}
+	return response.Records, nil
+}
+
+// UpdateSchemaLifecycle sends a schema's lifecycle stage to the PDP
+func (s *PDPService) UpdateSchemaLifecycle(request models.SchemaLifecycleUpdateRequest) (*models.SchemaLifecycleUpdateResponse, error) {
+	var response models.SchemaLifecycleUpdateResponse
+	if err := s.post("/api/v1/policy/schema-lifecycle", request, &response); err != nil {
+		return nil, err
+	}
+	slog.Info("Successfully updated schema lifecycle in PDP", "schemaId", request.SchemaID, "status", request.Status, "fieldsUpdated", response.FieldsUpdated)
+	return &response, nil
+}
+
+// CheckHealth checks that the PDP is reachable through the gateway
+func (s *PDPService) CheckHealth(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/health/live", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	var report struct {
+		Status string `json:"status"`
+	}
+	return s.send(httpReq, &report)
+}
+
+// get sends a GET request to the PDP and decodes its JSON response into out
+func (s *PDPService) get(path string, out interface{}) error {
+	httpReq, err := http.NewRequest(http.MethodGet, s.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return s.send(httpReq, out)
+}
+
+// post sends body as JSON to the PDP and decodes its JSON response into out
+func (s *PDPService) post(path string, body interface{}, out interface{}) error {
+	return s.sendJSON(http.MethodPost, path, body, out)
+}
+
+// put sends body as JSON to the PDP and decodes its JSON response into out
+func (s *PDPService) put(path string, body interface{}, out interface{}) error {
+	return s.sendJSON(http.MethodPut, path, body, out)
+}
+
+// sendJSON sends body as JSON to the PDP with method and decodes its JSON response into out
+func (s *PDPService) sendJSON(method string, path string, body interface{}, out interface{}) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequest(method, s.baseURL+path, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	return s.send(httpReq, out)
+}
+
+// send authenticates and sends a request to the PDP and decodes its JSON response into out
+func (s *PDPService) send(httpReq *http.Request, out interface{}) error {
+	if err := s.setAuthHeader(httpReq); err != nil {
+		return err
+	}
+
+	resp, err := s.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request to PDP: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			slog.Error("failed to close response body", "error", err)
+		}
+	}(resp.Body)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if pendingApproval(resp.StatusCode, respBody) {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		slog.Error("PDP returned error", "status", resp.StatusCode, "body", string(respBody))
+		return fmt.Errorf("PDP returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// pendingApproval reports whether the PDP accepted a policy write as a change set awaiting a second
+// admin's approval, rather than applying it. The write is delivered; the PDP applies it once approved.
+func pendingApproval(statusCode int, respBody []byte) bool {
+	if statusCode != http.StatusAccepted {
+		return false
+	}
+	var changeSet struct {
+		ID   string `json:"id"`
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(respBody, &changeSet); err != nil {
+		slog.Warn("Failed to parse the PDP change set", "error", err)
+	}
+	slog.Info("PDP change set awaits approval", "changeSetId", changeSet.ID, "kind", changeSet.Kind)
+	return true
+}
@@ -7,9 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gov-dx-sandbox/portal-backend/idp"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 func TestNewPDPService(t *testing.T) {
@@ -55,6 +57,7 @@ func TestPDPService_CreatePolicyMetadata_Success(t *testing.T) {
 		err := json.NewDecoder(r.Body).Decode(&req)
 		require.NoError(t, err)
 		assert.Equal(t, schemaID, req.SchemaID)
+		assert.Equal(t, "member-123", req.ProviderID)
 		// Note: Records may be empty if SDL has no directives, which is valid
 
 		// Send response
@@ -77,7 +80,7 @@ func TestPDPService_CreatePolicyMetadata_Success(t *testing.T) {
 		}
 	`
 
-	response, err := service.CreatePolicyMetadata(schemaID, sdl)
+	response, err := service.CreatePolicyMetadata(schemaID, "member-123", sdl, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, response)
 	// The response will have records from the mock server regardless of SDL parsing
@@ -92,7 +95,7 @@ func TestPDPService_CreatePolicyMetadata_InvalidSDL(t *testing.T) {
 	// Use invalid SDL
 	invalidSDL := "invalid graphql syntax {"
 
-	response, err := service.CreatePolicyMetadata("test-schema", invalidSDL)
+	response, err := service.CreatePolicyMetadata("test-schema", "member-123", invalidSDL, nil)
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "failed to parse SDL")
@@ -114,7 +117,7 @@ func TestPDPService_CreatePolicyMetadata_Non200Status(t *testing.T) {
 		}
 	`
 
-	response, err := service.CreatePolicyMetadata("test-schema", sdl)
+	response, err := service.CreatePolicyMetadata("test-schema", "member-123", sdl, nil)
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "PDP returned status 400")
@@ -137,7 +140,7 @@ func TestPDPService_CreatePolicyMetadata_InvalidJSONResponse(t *testing.T) {
 		}
 	`
 
-	response, err := service.CreatePolicyMetadata("test-schema", sdl)
+	response, err := service.CreatePolicyMetadata("test-schema", "member-123", sdl, nil)
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "failed to parse response")
@@ -153,7 +156,7 @@ func TestPDPService_CreatePolicyMetadata_NetworkError(t *testing.T) {
 		}
 	`
 
-	response, err := service.CreatePolicyMetadata("test-schema", sdl)
+	response, err := service.CreatePolicyMetadata("test-schema", "member-123", sdl, nil)
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "failed to send request to PDP")
@@ -217,6 +220,57 @@ func TestPDPService_UpdateAllowList_Success(t *testing.T) {
 	assert.Equal(t, expectedRecords[0].SchemaID, response.Records[0].SchemaID)
 }
 
+func TestPDPService_ChangeSetAwaitingApproval(t *testing.T) {
+	// A PDP that requires approval answers policy writes with the change set it queued
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "change-set-1", "kind": "allow_list", "status": "pending"})
+	}))
+	defer server.Close()
+
+	service := NewPDPService(server.URL, "test-api-key")
+	response, err := service.UpdateAllowList(models.AllowListUpdateRequest{
+		ApplicationID: "test-app-123",
+		Records:       []models.SelectedFieldRecord{{FieldName: "personInfo.name", SchemaID: "test-schema-123"}},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, response)
+	assert.Empty(t, response.Records)
+}
+
+func TestPDPService_SendsAccessToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "portal-token", "token_type": "Bearer", "expires_in": 3600})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer portal-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(models.AllowListUpdateResponse{})
+	}))
+	defer server.Close()
+
+	service := NewPDPService(server.URL, "test-api-key")
+	request := models.AllowListUpdateRequest{
+		ApplicationID: "test-app-123",
+		Records:       []models.SelectedFieldRecord{{FieldName: "personInfo.name", SchemaID: "test-schema-123"}},
+		GrantDuration: models.GrantDurationTypeOneMonth,
+	}
+	_, err := service.UpdateAllowList(request)
+	assert.Error(t, err, "the PDP rejects requests without an access token")
+
+	service.SetTokenSource(idp.NewTokenSource(&clientcredentials.Config{ClientID: "portal", ClientSecret: "secret", TokenURL: server.URL + "/oauth2/token"}))
+	_, err = service.UpdateAllowList(request)
+	require.NoError(t, err)
+	_, err = service.ListClassifications()
+	require.NoError(t, err)
+}
+
 func TestPDPService_UpdateAllowList_Non200Status(t *testing.T) {
 	// Create a mock HTTP server that returns 400
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// PDPSyncService compares the grants of approved applications with the PDP's allow lists and
+// re-pushes grants that went missing, e.g. after the PDP database was restored from an older backup
+type PDPSyncService struct {
+	db         *gorm.DB
+	pdpService *PDPService
+}
+
+// NewPDPSyncService creates a new PDP sync service
+func NewPDPSyncService(db *gorm.DB, pdpService *PDPService) *PDPSyncService {
+	return &PDPSyncService{db: db, pdpService: pdpService}
+}
+
+// Status reports every field whose PDP grant differs from what the approved applications selected
+func (s *PDPSyncService) Status(ctx context.Context) (*models.PDPSyncStatusResponse, error) {
+	applications, err := s.approvedApplications(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	response := &models.PDPSyncStatusResponse{
+		CheckedAt:           now.Format(time.RFC3339),
+		ApplicationsChecked: len(applications),
+		Drift:               []models.PDPSyncDrift{},
+	}
+	for i := range applications {
+		expected, drift, err := s.applicationDrift(&applications[i], now)
+		if err != nil {
+			return nil, err
+		}
+		response.GrantsExpected += expected
+		response.Drift = append(response.Drift, drift...)
+	}
+	response.InSync = len(response.Drift) == 0
+	return response, nil
+}
+
+// Repair re-pushes the missing and expired grants of drifted applications to the PDP for
+// DefaultGrantDuration. Applications without repairable drift are skipped.
+func (s *PDPSyncService) Repair(ctx context.Context, req *models.PDPSyncRepairRequest) (*models.PDPSyncRepairResponse, error) {
+	applications, err := s.approvedApplications(ctx, req.ApplicationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PDPSyncRepairResponse{Results: []models.PDPSyncRepairResult{}}
+	record := func(result models.PDPSyncRepairResult) {
+		response.Results = append(response.Results, result)
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	found := make(map[string]bool, len(applications))
+	now := time.Now()
+	for i := range applications {
+		application := &applications[i]
+		found[application.ApplicationID] = true
+
+		_, drift, err := s.applicationDrift(application, now)
+		if err != nil {
+			record(models.PDPSyncRepairResult{ApplicationID: application.ApplicationID, Error: err.Error()})
+			continue
+		}
+		var records []models.SelectedFieldRecord
+		for _, field := range drift {
+			if field.Type.IsRepairable() {
+				records = append(records, models.SelectedFieldRecord{SchemaID: field.SchemaID, FieldName: field.FieldName})
+			}
+		}
+		if len(records) == 0 {
+			continue
+		}
+
+		_, err = s.pdpService.UpdateAllowList(models.AllowListUpdateRequest{
+			ApplicationID: application.ApplicationID,
+			Records:       records,
+			GrantDuration: DefaultGrantDuration,
+			ConsumerID:    application.MemberID,
+		})
+		if err != nil {
+			record(models.PDPSyncRepairResult{ApplicationID: application.ApplicationID, Error: err.Error()})
+			continue
+		}
+		slog.Info("Repaired PDP grants", "applicationID", application.ApplicationID, "grants", len(records))
+		record(models.PDPSyncRepairResult{ApplicationID: application.ApplicationID, Success: true, GrantsRepushed: len(records)})
+	}
+
+	for _, applicationID := range req.ApplicationIDs {
+		if !found[applicationID] {
+			found[applicationID] = true
+			record(models.PDPSyncRepairResult{ApplicationID: applicationID, Error: "application not found or not active"})
+		}
+	}
+	return response, nil
+}
+
+// approvedApplications returns the live applications with an active version, limited to
+// applicationIDs when any are given
+func (s *PDPSyncService) approvedApplications(ctx context.Context, applicationIDs []string) ([]models.Application, error) {
+	query := s.db.WithContext(ctx).Where("version = ?", models.ActiveVersion)
+	if len(applicationIDs) > 0 {
+		query = query.Where("application_id IN ?", applicationIDs)
+	}
+	var applications []models.Application
+	if err := query.Order("application_id ASC").Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("failed to get applications: %w", err)
+	}
+	return applications, nil
+}
+
+// applicationDrift compares the selected fields of an application with its PDP grants. It returns
+// how many grants the application should hold and the fields that differ.
+func (s *PDPSyncService) applicationDrift(application *models.Application, now time.Time) (int, []models.PDPSyncDrift, error) {
+	grants, err := s.pdpService.ListApplicationGrants(application.ApplicationID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %w", ErrPolicyServiceUnavailable, err)
+	}
+	granted := make(map[string]models.ConsumerGrant, len(grants))
+	for _, grant := range grants {
+		granted[grant.SchemaID+":"+grant.FieldName] = grant
+	}
+	// PDP grants expire with the application's grants, so they are only drift while the application's are live
+	grantsLive := application.GrantExpiresAt == nil || application.GrantExpiresAt.After(now)
+
+	drift := []models.PDPSyncDrift{}
+	add := func(driftType models.PDPSyncDriftType, schemaID, fieldName string, pdpExpiresAt *string) {
+		drift = append(drift, models.PDPSyncDrift{
+			Type:            driftType,
+			ApplicationID:   application.ApplicationID,
+			ApplicationName: application.ApplicationName,
+			MemberID:        application.MemberID,
+			SchemaID:        schemaID,
+			FieldName:       fieldName,
+			PDPExpiresAt:    pdpExpiresAt,
+		})
+	}
+
+	expected := 0
+	selected := make(map[string]bool, len(application.SelectedFields))
+	for _, field := range application.SelectedFields {
+		key := field.SchemaID + ":" + field.FieldName
+		selected[key] = true
+		// Fields of deleted schemas are neither expected nor unexpected
+		if field.Invalid {
+			continue
+		}
+		expected++
+		grant, ok := granted[key]
+		switch {
+		case !ok:
+			add(models.PDPSyncDriftMissing, field.SchemaID, field.FieldName, nil)
+		case grant.Expired && grantsLive:
+			add(models.PDPSyncDriftExpired, field.SchemaID, field.FieldName, &grant.ExpiresAt)
+		}
+	}
+	for _, grant := range grants {
+		if !selected[grant.SchemaID+":"+grant.FieldName] {
+			add(models.PDPSyncDriftUnexpected, grant.SchemaID, grant.FieldName, &grant.ExpiresAt)
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].SchemaID != drift[j].SchemaID {
+			return drift[i].SchemaID < drift[j].SchemaID
+		}
+		return drift[i].FieldName < drift[j].FieldName
+	})
+	return expected, drift, nil
+}
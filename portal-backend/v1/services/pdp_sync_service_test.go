@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPDPSyncService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	grants := map[string][]models.ConsumerGrant{
+		// app_1 selected person.name and vehicle.plate; its person.name grant expired and it holds a
+		// grant it never selected
+		"app_1": {
+			{SchemaID: "sch_1", FieldName: "person.name", ApplicationID: "app_1", ExpiresAt: expiresAt, Expired: true},
+			{SchemaID: "sch_1", FieldName: "person.nic", ApplicationID: "app_1", ExpiresAt: expiresAt},
+			{SchemaID: "sch_2", FieldName: "vehicle.plate", ApplicationID: "app_1", ExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339)},
+		},
+	}
+	var allowListUpdates []models.AllowListUpdateRequest
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var req models.AllowListUpdateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			allowListUpdates = append(allowListUpdates, req)
+			_, _ = w.Write([]byte(`{"records": []}`))
+			return
+		}
+		assert.Equal(t, "true", r.URL.Query().Get("includeExpired"))
+		records := grants[r.URL.Query().Get("applicationId")]
+		require.NoError(t, json.NewEncoder(w).Encode(models.ConsumerGrantListResponse{Records: records, Count: len(records)}))
+	}))
+	defer pdpServer.Close()
+	service := NewPDPSyncService(db, NewPDPService(pdpServer.URL, "test-key"))
+
+	require.NoError(t, db.Create(&models.Application{ApplicationID: "app_missing_grants", ApplicationName: "Missing", MemberID: "mem_consumer",
+		Version: string(models.ActiveVersion), SelectedFields: models.SelectedFieldRecords{
+			{FieldName: "person.name", SchemaID: "sch_1"},
+			{FieldName: "person.address", SchemaID: "sch_deleted", Invalid: true},
+		}}).Error)
+
+	t.Run("Status reports missing, expired and unexpected grants", func(t *testing.T) {
+		status, err := service.Status(ctx)
+		require.NoError(t, err)
+		assert.False(t, status.InSync)
+		assert.Equal(t, 2, status.ApplicationsChecked)
+		assert.Equal(t, 3, status.GrantsExpected, "fields of deleted schemas are not expected")
+
+		require.Len(t, status.Drift, 3)
+		assert.Equal(t, models.PDPSyncDriftExpired, status.Drift[0].Type)
+		assert.Equal(t, "app_1", status.Drift[0].ApplicationID)
+		assert.Equal(t, &expiresAt, status.Drift[0].PDPExpiresAt)
+		assert.Equal(t, models.PDPSyncDriftUnexpected, status.Drift[1].Type)
+		assert.Equal(t, "person.nic", status.Drift[1].FieldName)
+		assert.Equal(t, models.PDPSyncDriftMissing, status.Drift[2].Type)
+		assert.Equal(t, "app_missing_grants", status.Drift[2].ApplicationID)
+	})
+
+	t.Run("Expired PDP grants are not drift once the application's grants expired", func(t *testing.T) {
+		expired := time.Now().Add(-time.Minute)
+		require.NoError(t, db.Model(&models.Application{}).Where("application_id = ?", "app_1").Update("grant_expires_at", expired).Error)
+		defer db.Model(&models.Application{}).Where("application_id = ?", "app_1").Update("grant_expires_at", nil)
+
+		status, err := service.Status(ctx)
+		require.NoError(t, err)
+		for _, drift := range status.Drift {
+			assert.NotEqual(t, models.PDPSyncDriftExpired, drift.Type)
+		}
+	})
+
+	t.Run("Repair re-pushes only missing and expired grants", func(t *testing.T) {
+		response, err := service.Repair(ctx, &models.PDPSyncRepairRequest{ApplicationIDs: []string{"app_1", "app_unknown"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, 1, response.Failed)
+		require.Len(t, response.Results, 2)
+		assert.Equal(t, models.PDPSyncRepairResult{ApplicationID: "app_1", Success: true, GrantsRepushed: 1}, response.Results[0])
+		assert.Equal(t, "app_unknown", response.Results[1].ApplicationID)
+
+		require.Len(t, allowListUpdates, 1)
+		assert.Equal(t, "app_1", allowListUpdates[0].ApplicationID)
+		assert.Equal(t, "mem_consumer", allowListUpdates[0].ConsumerID)
+		assert.Equal(t, DefaultGrantDuration, allowListUpdates[0].GrantDuration)
+		assert.Equal(t, []models.SelectedFieldRecord{{SchemaID: "sch_1", FieldName: "person.name"}}, allowListUpdates[0].Records)
+	})
+
+	t.Run("Repair without application IDs covers every drifted application", func(t *testing.T) {
+		allowListUpdates = nil
+		response, err := service.Repair(ctx, &models.PDPSyncRepairRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Succeeded)
+		require.Len(t, allowListUpdates, 2)
+		assert.Equal(t, "app_missing_grants", allowListUpdates[1].ApplicationID)
+		assert.Len(t, allowListUpdates[1].Records, 1, "fields of deleted schemas are not re-pushed")
+	})
+
+	t.Run("An unavailable PDP fails the report", func(t *testing.T) {
+		pdpServer.Close()
+		_, err := service.Status(ctx)
+		assert.ErrorIs(t, err, ErrPolicyServiceUnavailable)
+	})
+}
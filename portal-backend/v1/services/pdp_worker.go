@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultPDPWorkerBatchSize is how many due jobs the worker runs per poll
+const DefaultPDPWorkerBatchSize = 20
+
+// PDPWorker periodically delivers queued PDP sync jobs
+type PDPWorker struct {
+	jobService *PDPJobService
+	// interval is how often the worker polls for due jobs
+	interval time.Duration
+	// batchSize is the maximum number of jobs run per poll
+	batchSize int
+}
+
+// NewPDPWorker creates a new PDP worker
+func NewPDPWorker(jobService *PDPJobService, interval time.Duration) *PDPWorker {
+	return &PDPWorker{
+		jobService: jobService,
+		interval:   interval,
+		batchSize:  DefaultPDPWorkerBatchSize,
+	}
+}
+
+// Start runs the delivery loop until the context is cancelled
+func (w *PDPWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		slog.Info("PDP worker disabled")
+		return
+	}
+
+	slog.Info("PDP worker started", "interval", w.interval, "batchSize", w.batchSize)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("PDP worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce()
+		}
+	}
+}
+
+// RunOnce runs the jobs that are due and returns how many ran
+func (w *PDPWorker) RunOnce() int {
+	processed, err := w.jobService.ProcessDueJobs(w.batchSize)
+	if err != nil {
+		slog.Error("Failed to process PDP jobs", "error", err)
+	}
+	if processed > 0 {
+		slog.Info("Processed PDP jobs", "count", processed)
+	}
+	return processed
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// newPublicHTTPClient creates a client for URLs chosen by users, such as webhooks and schema
+// endpoints. It only connects to public addresses, checked after DNS resolution so that a name
+// cannot be rebound to an internal address, and does not follow redirects. Connections to other
+// addresses fail with blockedErr.
+func newPublicHTTPClient(timeout time.Duration, blockedErr error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || blockedIP(ip) {
+				return fmt.Errorf("%w: %s", blockedErr, host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// No proxy, so that the dialer checks the destination's own address
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// blockedIP reports whether ip is an address user-chosen URLs must not reach: loopback, private,
+// link-local (including the 169.254.169.254 metadata endpoint of cloud providers), unspecified or
+// multicast
+func blockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
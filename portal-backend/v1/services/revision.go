@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRevisionConflict is returned when an update is based on a revision other than the current one
+var ErrRevisionConflict = errors.New("resource was modified by another request")
+
+// RevisionConflictError is returned when an update is based on a stale revision. It carries the
+// resource as it is now and wraps ErrRevisionConflict.
+type RevisionConflictError struct {
+	Current interface{}
+}
+
+func (e *RevisionConflictError) Error() string {
+	return ErrRevisionConflict.Error()
+}
+
+func (e *RevisionConflictError) Unwrap() error {
+	return ErrRevisionConflict
+}
+
+// revisionConflict builds the RevisionConflictError for the current state of a resource, as
+// returned by its getter
+func revisionConflict(current interface{}, err error) error {
+	if err != nil {
+		return fmt.Errorf("%w: failed to get current state: %w", ErrRevisionConflict, err)
+	}
+	return &RevisionConflictError{Current: current}
+}
+
+// checkRevision returns ErrRevisionConflict unless expected is nil or the current revision
+func checkRevision(expected *int64, current int64) error {
+	if expected != nil && *expected != current {
+		return ErrRevisionConflict
+	}
+	return nil
+}
+
+// saveRevision saves model, whose loaded revision is *revision, and increments the revision. The
+// save only applies while the row is still at the loaded revision, so of two concurrent updates
+// based on the same revision one returns ErrRevisionConflict instead of overwriting the other.
+func saveRevision(tx *gorm.DB, model interface{}, revision *int64) error {
+	loaded := *revision
+	*revision = loaded + 1
+	result := tx.Model(model).Where("revision = ?", loaded).Select("*").Omit(clause.Associations).Updates(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrRevisionConflict
+	}
+	if result.Error != nil {
+		*revision = loaded
+		return result.Error
+	}
+	return nil
+}
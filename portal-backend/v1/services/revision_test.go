@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimisticLocking(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+	revision := func(n int64) *int64 { return &n }
+
+	t.Run("Updates increment the revision", func(t *testing.T) {
+		service := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+		schema, err := service.GetSchema("sch_1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), schema.Revision)
+
+		name := "Person Registry"
+		updated, err := service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{SchemaName: &name, Revision: revision(1)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Revision)
+
+		// An update based on the old revision is rejected with the current state
+		stale := "Stale Name"
+		_, err = service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{SchemaName: &stale, Revision: revision(1)})
+		var conflict *RevisionConflictError
+		require.True(t, errors.As(err, &conflict))
+		current, ok := conflict.Current.(*models.SchemaResponse)
+		require.True(t, ok)
+		assert.Equal(t, name, current.SchemaName)
+		assert.Equal(t, int64(2), current.Revision)
+	})
+
+	t.Run("Concurrent updates of the same revision", func(t *testing.T) {
+		service := NewApplicationService(db, NewPDPService("http://localhost:9999", "test-key"), &MockIDP{})
+
+		var application models.Application
+		require.NoError(t, db.First(&application, "application_id = ?", "app_1").Error)
+		// Another request saves the application after this one loaded it
+		require.NoError(t, db.Model(&models.Application{}).Where("application_id = ?", "app_1").Update("revision", application.Revision+1).Error)
+
+		application.ApplicationName = "Lost Update"
+		err := saveRevision(db, &application, &application.Revision)
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+		assert.Equal(t, int64(1), application.Revision)
+
+		name := "Benefits"
+		_, err = service.UpdateApplication(ctx, "app_1", &models.UpdateApplicationRequest{ApplicationName: &name, Revision: revision(1)})
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+		updated, err := service.UpdateApplication(ctx, "app_1", &models.UpdateApplicationRequest{ApplicationName: &name, Revision: revision(2)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), updated.Revision)
+	})
+
+	t.Run("Stale member updates do not reach the IDP", func(t *testing.T) {
+		idpCalled := false
+		service := NewMemberService(db, &MockIDP{
+			UpdateUserFunc: func(ctx context.Context, userID string, user *idp.User) (*idp.UserInfo, error) {
+				idpCalled = true
+				return &idp.UserInfo{}, nil
+			},
+		})
+		name := "Renamed"
+		_, err := service.UpdateMember(ctx, "mem_provider", &models.UpdateMemberRequest{Name: &name, Revision: revision(5)})
+		assert.ErrorIs(t, err, ErrRevisionConflict)
+		assert.False(t, idpCalled)
+	})
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSandboxExists is returned when provisioning a sandbox for an application that already has one
+	ErrSandboxExists = errors.New("application already has a sandbox")
+	// ErrSandboxDisabled is returned by sandbox operations when sandbox mode is off
+	ErrSandboxDisabled = errors.New("sandbox mode is disabled")
+)
+
+// sandboxProviderPath is the path of the mock provider of a schema, relative to the provider base URL
+const sandboxProviderPath = "/internal/api/v1/sandbox/providers/%s/graphql"
+
+// SandboxService provisions sandbox environments for applications. A sandbox is an IDP client of its
+// own; the exchange routes its requests to mock providers that answer from the schemas' SDL, so
+// consumers can integrate before they rely on production data.
+type SandboxService struct {
+	db  *gorm.DB
+	idp idp.IdentityProviderAPI
+	// providerBaseURL is where the exchange reaches the mock providers this service serves
+	providerBaseURL string
+}
+
+// NewSandboxService creates a new sandbox service whose mock providers are reached at providerBaseURL
+func NewSandboxService(db *gorm.DB, idp idp.IdentityProviderAPI, providerBaseURL string) *SandboxService {
+	return &SandboxService{db: db, idp: idp, providerBaseURL: strings.TrimSuffix(providerBaseURL, "/")}
+}
+
+// ProvisionSandbox creates the sandbox IDP client of an application. The client secret is only
+// returned here.
+func (s *SandboxService) ProvisionSandbox(ctx context.Context, applicationID, provisionedBy string) (*models.ApplicationSandboxResponse, error) {
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&models.ApplicationSandbox{}).Where("application_id = ?", applicationID).Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check sandbox: %w", err)
+	}
+	if existing > 0 {
+		return nil, ErrSandboxExists
+	}
+
+	idpApplicationID, err := s.idp.CreateApplication(ctx, &idp.Application{
+		Name:        application.ApplicationName + " (sandbox)",
+		Description: "Sandbox client of application " + application.ApplicationID,
+		TemplateId:  models.TemplateIDM2M,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox client: %w", err)
+	}
+	oidcInfo, err := s.idp.GetApplicationOIDC(ctx, *idpApplicationID)
+	if err != nil {
+		return nil, s.compensateProvisioning(ctx, applicationID, *idpApplicationID, fmt.Errorf("failed to get sandbox client OIDC: %w", err))
+	}
+	sandbox := models.ApplicationSandbox{
+		ApplicationID:    applicationID,
+		IdpApplicationID: *idpApplicationID,
+		IdpClientID:      oidcInfo.ClientId,
+		ProvisionedBy:    provisionedBy,
+	}
+	if err := s.db.WithContext(ctx).Create(&sandbox).Error; err != nil {
+		return nil, s.compensateProvisioning(ctx, applicationID, *idpApplicationID, fmt.Errorf("failed to record sandbox: %w", err))
+	}
+
+	slog.Info("Application sandbox provisioned", "applicationID", applicationID, "provisionedBy", provisionedBy)
+	response, err := s.sandboxResponseOf(ctx, &application, &sandbox)
+	if err != nil {
+		return nil, err
+	}
+	response.ClientSecret = oidcInfo.ClientSecret
+	return response, nil
+}
+
+// compensateProvisioning deletes the sandbox client of a failed provisioning so a retry does not
+// leave it orphaned, and returns err with any compensation failure
+func (s *SandboxService) compensateProvisioning(ctx context.Context, applicationID, idpApplicationID string, err error) error {
+	if deleteErr := s.idp.DeleteApplication(ctx, idpApplicationID); deleteErr != nil {
+		slog.Error("Failed to compensate sandbox provisioning",
+			"applicationID", applicationID,
+			"originalError", err,
+			"compensationError", deleteErr)
+		return fmt.Errorf("%w, and failed to compensate: %w", err, deleteErr)
+	}
+	return err
+}
+
+// GetSandbox returns the sandbox of an application with the mock providers of its selected schemas
+func (s *SandboxService) GetSandbox(ctx context.Context, applicationID string) (*models.ApplicationSandboxResponse, error) {
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	var sandbox models.ApplicationSandbox
+	if err := s.db.WithContext(ctx).First(&sandbox, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get sandbox: %w", err)
+	}
+	return s.sandboxResponseOf(ctx, &application, &sandbox)
+}
+
+// RegenerateSandboxSecret issues a new secret for the sandbox client of an application, invalidating
+// the previous one. Sandboxes provisioned on approval have to do this to learn their secret.
+func (s *SandboxService) RegenerateSandboxSecret(ctx context.Context, applicationID string) (*models.ApplicationSandboxResponse, error) {
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	var sandbox models.ApplicationSandbox
+	if err := s.db.WithContext(ctx).First(&sandbox, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get sandbox: %w", err)
+	}
+	oidcInfo, err := s.idp.RegenerateApplicationSecret(ctx, sandbox.IdpApplicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to regenerate sandbox client secret: %w", err)
+	}
+
+	slog.Info("Application sandbox secret regenerated", "applicationID", applicationID)
+	response, err := s.sandboxResponseOf(ctx, &application, &sandbox)
+	if err != nil {
+		return nil, err
+	}
+	response.ClientSecret = oidcInfo.ClientSecret
+	return response, nil
+}
+
+// ResolveClient returns the application a sandbox client belongs to and the mock provider endpoint
+// of each of its schemas, keyed by schema ID
+func (s *SandboxService) ResolveClient(ctx context.Context, idpClientID string) (string, map[string]string, error) {
+	var sandbox models.ApplicationSandbox
+	if err := s.db.WithContext(ctx).First(&sandbox, "idp_client_id = ?", idpClientID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, ErrResourceNotFound
+		}
+		return "", nil, fmt.Errorf("failed to get sandbox: %w", err)
+	}
+	var application models.Application
+	if err := s.db.WithContext(ctx).First(&application, "application_id = ?", sandbox.ApplicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, ErrResourceNotFound
+		}
+		return "", nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	providers, err := s.providersOf(ctx, &application)
+	if err != nil {
+		return "", nil, err
+	}
+	endpoints := make(map[string]string, len(providers))
+	for _, provider := range providers {
+		endpoints[provider.SchemaID] = provider.Endpoint
+	}
+	return sandbox.ApplicationID, endpoints, nil
+}
+
+func (s *SandboxService) sandboxResponseOf(ctx context.Context, application *models.Application, sandbox *models.ApplicationSandbox) (*models.ApplicationSandboxResponse, error) {
+	providers, err := s.providersOf(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ApplicationSandboxResponse{
+		ApplicationID: sandbox.ApplicationID,
+		ClientID:      sandbox.IdpClientID,
+		Providers:     providers,
+		ProvisionedBy: sandbox.ProvisionedBy,
+		CreatedAt:     sandbox.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// providersOf returns the mock providers of the schemas an application selected fields from
+func (s *SandboxService) providersOf(ctx context.Context, application *models.Application) ([]models.SandboxProvider, error) {
+	var schemaIDs []string
+	seen := make(map[string]bool)
+	for _, field := range application.SelectedFields {
+		if !field.Invalid && !seen[field.SchemaID] {
+			seen[field.SchemaID] = true
+			schemaIDs = append(schemaIDs, field.SchemaID)
+		}
+	}
+	sort.Strings(schemaIDs)
+
+	providers := []models.SandboxProvider{}
+	if len(schemaIDs) == 0 {
+		return providers, nil
+	}
+	var schemas []models.Schema
+	if err := s.db.WithContext(ctx).Where("schema_id IN ?", schemaIDs).Order("schema_id ASC").Find(&schemas).Error; err != nil {
+		return nil, fmt.Errorf("failed to get schemas: %w", err)
+	}
+	for _, schema := range schemas {
+		providers = append(providers, models.SandboxProvider{
+			SchemaID:   schema.SchemaID,
+			SchemaName: schema.SchemaName,
+			Endpoint:   s.providerBaseURL + fmt.Sprintf(sandboxProviderPath, url.PathEscape(schema.SchemaID)),
+		})
+	}
+	return providers, nil
+}
+
+// ExecuteMockProvider answers a query against the mock provider of a schema. Every field resolves to
+// a sample value of its type, so responses have the shape the real provider's would.
+func (s *SandboxService) ExecuteMockProvider(ctx context.Context, schemaID string, req *models.GraphQLRequest) (*models.GraphQLResponse, error) {
+	var schema models.Schema
+	if err := s.db.WithContext(ctx).First(&schema, "schema_id = ?", schemaID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+	graphSchema, err := gqlparser.LoadSchema(&ast.Source{Name: schema.SchemaID, Input: schema.SDL})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSDL, err)
+	}
+
+	doc, errs := gqlparser.LoadQueryWithRules(graphSchema, req.Query, nil)
+	if len(errs) > 0 {
+		return &models.GraphQLResponse{Errors: errs}, nil
+	}
+	operation := doc.Operations.ForName(req.OperationName)
+	if operation == nil {
+		return &models.GraphQLResponse{Errors: gqlerror.List{gqlerror.Errorf("operation %q not found", req.OperationName)}}, nil
+	}
+	if operation.Operation != ast.Query {
+		return &models.GraphQLResponse{Errors: gqlerror.List{gqlerror.Errorf("only queries are supported")}}, nil
+	}
+	variables, err := validator.VariableValues(graphSchema, operation, req.Variables)
+	if err != nil {
+		return &models.GraphQLResponse{Errors: gqlerror.List{toGraphError(err)}}, nil
+	}
+
+	m := &mockExecution{schema: graphSchema, fields: &graphExecution{variables: variables}}
+	rootType := graphSchema.Query.Name
+	data := m.object(rootType, m.fields.collectFields(rootType, operation.SelectionSet))
+	return &models.GraphQLResponse{Data: data, Errors: m.errors}, nil
+}
+
+// mockExecution answers one query against a mock provider
+type mockExecution struct {
+	schema *ast.Schema
+	// fields collects the selections of the query the way the admin GraphQL API does
+	fields *graphExecution
+	errors gqlerror.List
+}
+
+// object resolves the selected fields of an object of typeName to sample values
+func (m *mockExecution) object(typeName string, fields []*graphField) *graphObject {
+	object := &graphObject{values: make(map[string]interface{}, len(fields))}
+	for _, field := range fields {
+		definition := field.fields[0]
+		switch {
+		case definition.Name == "__typename":
+			object.set(field.alias, typeName)
+		case strings.HasPrefix(definition.Name, "__"):
+			m.errors = append(m.errors, gqlerror.Errorf("introspection is not supported by sandbox providers"))
+			object.set(field.alias, nil)
+		default:
+			object.set(field.alias, m.value(definition.Definition.Type, definition.Name, field))
+		}
+	}
+	return object
+}
+
+// value returns a sample value of fieldType for the field named fieldName. Lists hold one item.
+func (m *mockExecution) value(fieldType *ast.Type, fieldName string, field *graphField) interface{} {
+	if fieldType.Elem != nil {
+		return []interface{}{m.value(fieldType.Elem, fieldName, field)}
+	}
+	def := m.schema.Types[fieldType.NamedType]
+	if def == nil {
+		return nil
+	}
+	switch def.Kind {
+	case ast.Object:
+		return m.object(def.Name, m.fields.collectFields(def.Name, field.subselection()))
+	case ast.Interface, ast.Union:
+		possible := m.schema.GetPossibleTypes(def)
+		if len(possible) == 0 {
+			return nil
+		}
+		concrete := possible[0]
+		return m.object(concrete.Name, m.fields.collectFields(concrete.Name, field.subselection()))
+	case ast.Enum:
+		if len(def.EnumValues) == 0 {
+			return nil
+		}
+		return def.EnumValues[0].Name
+	}
+	return mockScalar(def.Name, fieldName)
+}
+
+// mockScalar returns a sample value of a scalar; custom scalars are sampled as strings
+func mockScalar(typeName, fieldName string) interface{} {
+	switch typeName {
+	case "Int":
+		return 42
+	case "Float":
+		return 4.2
+	case "Boolean":
+		return true
+	case "ID":
+		return "sandbox-" + fieldName + "-1"
+	}
+	name := strings.ToLower(fieldName)
+	switch {
+	case strings.Contains(name, "email"):
+		return "sandbox.user@example.com"
+	case strings.Contains(name, "phone"):
+		return "+94770000000"
+	case strings.Contains(name, "name"):
+		return "Sandbox User"
+	}
+	return "sample " + fieldName
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	var deleted []string
+	failOIDC := false
+	mockIDP := &MockIDP{
+		CreateApplicationFunc: func(ctx context.Context, app *idp.Application) (*string, error) {
+			id := "idp-sandbox"
+			return &id, nil
+		},
+		GetApplicationOIDCFunc: func(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error) {
+			if failOIDC {
+				return nil, errors.New("idp unavailable")
+			}
+			return &idp.ApplicationOIDCInfo{ClientId: "sandbox-client", ClientSecret: "sandbox-secret"}, nil
+		},
+		RegenerateApplicationSecretFunc: func(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error) {
+			return &idp.ApplicationOIDCInfo{ClientId: "sandbox-client", ClientSecret: "new-secret"}, nil
+		},
+		DeleteApplicationFunc: func(ctx context.Context, applicationID string) error {
+			deleted = append(deleted, applicationID)
+			return nil
+		},
+	}
+	service := NewSandboxService(db, mockIDP, "http://portal-backend/")
+
+	t.Run("Failed provisioning deletes the sandbox client", func(t *testing.T) {
+		failOIDC = true
+		defer func() { failOIDC = false }()
+
+		_, err := service.ProvisionSandbox(ctx, "app_1", "mem_consumer")
+		require.Error(t, err)
+		assert.Equal(t, []string{"idp-sandbox"}, deleted)
+		_, err = service.GetSandbox(ctx, "app_1")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	t.Run("Provisioning returns the secret once", func(t *testing.T) {
+		sandbox, err := service.ProvisionSandbox(ctx, "app_1", "mem_consumer")
+		require.NoError(t, err)
+		assert.Equal(t, "sandbox-client", sandbox.ClientID)
+		assert.Equal(t, "sandbox-secret", sandbox.ClientSecret)
+		assert.Equal(t, []models.SandboxProvider{{
+			SchemaID:   "sch_1",
+			SchemaName: "Person",
+			Endpoint:   "http://portal-backend/internal/api/v1/sandbox/providers/sch_1/graphql",
+		}}, sandbox.Providers)
+
+		_, err = service.ProvisionSandbox(ctx, "app_1", "mem_consumer")
+		assert.ErrorIs(t, err, ErrSandboxExists)
+		_, err = service.ProvisionSandbox(ctx, "app_missing", "mem_consumer")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		sandbox, err = service.GetSandbox(ctx, "app_1")
+		require.NoError(t, err)
+		assert.Empty(t, sandbox.ClientSecret)
+
+		sandbox, err = service.RegenerateSandboxSecret(ctx, "app_1")
+		require.NoError(t, err)
+		assert.Equal(t, "new-secret", sandbox.ClientSecret)
+	})
+
+	t.Run("Sandbox clients resolve to mock providers", func(t *testing.T) {
+		applicationID, endpoints, err := service.ResolveClient(ctx, "sandbox-client")
+		require.NoError(t, err)
+		assert.Equal(t, "app_1", applicationID)
+		assert.Equal(t, map[string]string{"sch_1": "http://portal-backend/internal/api/v1/sandbox/providers/sch_1/graphql"}, endpoints)
+
+		_, _, err = service.ResolveClient(ctx, "production-client")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+}
+
+func TestSandboxService_ExecuteMockProvider(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+	service := NewSandboxService(db, &MockIDP{}, "http://portal-backend")
+
+	require.NoError(t, db.Model(&models.Schema{}).Where("schema_id = ?", "sch_1").Update("sdl", `
+		type Query { person(nic: ID!): Person search: [Result!]! }
+		type Person { id: ID! fullName: String email: String age: Int status: Status vehicles: [Vehicle] }
+		type Vehicle { plate: String }
+		enum Status { ACTIVE INACTIVE }
+		union Result = Person | Vehicle`).Error)
+
+	t.Run("Fields resolve to sample values of their types", func(t *testing.T) {
+		response, err := service.ExecuteMockProvider(ctx, "sch_1", &models.GraphQLRequest{
+			Query:     `query ($nic: ID!) { person(nic: $nic) { id fullName email age status vehicles { plate } } search { __typename } }`,
+			Variables: map[string]interface{}{"nic": "123"},
+		})
+		require.NoError(t, err)
+		require.Empty(t, response.Errors)
+
+		data, err := json.Marshal(response.Data)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"person": {
+				"id": "sandbox-id-1", "fullName": "Sandbox User", "email": "sandbox.user@example.com",
+				"age": 42, "status": "ACTIVE", "vehicles": [{"plate": "sample plate"}]
+			},
+			"search": [{"__typename": "Person"}]
+		}`, string(data))
+	})
+
+	t.Run("Invalid queries are answered with errors", func(t *testing.T) {
+		response, err := service.ExecuteMockProvider(ctx, "sch_1", &models.GraphQLRequest{Query: `{ person { unknown } }`})
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.Errors)
+	})
+
+	t.Run("Unknown schemas are not found", func(t *testing.T) {
+		_, err := service.ExecuteMockProvider(ctx, "sch_missing", &models.GraphQLRequest{Query: `{ name }`})
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+}
+
+func TestSandboxProvisionedOnApproval(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer pdpServer.Close()
+
+	var created []string
+	mockIDP := &MockIDP{
+		CreateApplicationFunc: func(ctx context.Context, app *idp.Application) (*string, error) {
+			created = append(created, app.Name)
+			id := fmt.Sprintf("idp-app-%d", len(created))
+			return &id, nil
+		},
+		GetApplicationOIDCFunc: func(ctx context.Context, applicationID string) (*idp.ApplicationOIDCInfo, error) {
+			return &idp.ApplicationOIDCInfo{ClientId: applicationID + "-client", ClientSecret: "secret"}, nil
+		},
+	}
+	pdpService := NewPDPService(pdpServer.URL, "test-key")
+	sandboxService := NewSandboxService(db, mockIDP, "http://portal-backend")
+	workflow := []models.ReviewStep{{Name: "technical_review", RequiredApprovals: 1}}
+	service := NewSubmissionReviewService(db, nil, NewApplicationService(db, pdpService, mockIDP), nil, sandboxService, workflow)
+
+	review, err := service.Review(ctx, models.SubmissionTypeApplication, "sub_app", "reviewer-1",
+		&models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove})
+	require.NoError(t, err)
+	assert.Equal(t, string(models.StatusApproved), review.Status)
+	assert.Equal(t, []string{"App v2", "App v2 (sandbox)"}, created)
+
+	var application models.Application
+	require.NoError(t, db.First(&application, "application_name = ?", "App v2").Error)
+	sandbox, err := sandboxService.GetSandbox(ctx, application.ApplicationID)
+	require.NoError(t, err)
+	assert.Equal(t, "idp-app-2-client", sandbox.ClientID)
+	assert.Equal(t, "mem_consumer", sandbox.ProvisionedBy)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSavedViewNameTaken is returned when an admin saves two views of a collection with the same name
+	ErrSavedViewNameTaken = errors.New("a saved view with this name already exists")
+	// ErrSavedViewNotOwned is returned when an admin changes or deletes a shared view saved by another admin
+	ErrSavedViewNotOwned = errors.New("saved views can only be changed by the admin who saved them")
+)
+
+// SavedViewService stores named filters and sorts of the admin collections. Views are private to the
+// admin who saved them unless they are shared, in which case every admin can list and export them.
+type SavedViewService struct {
+	db *gorm.DB
+}
+
+// NewSavedViewService creates a new saved view service
+func NewSavedViewService(db *gorm.DB) *SavedViewService {
+	return &SavedViewService{db: db}
+}
+
+// CreateSavedView saves a view owned by ownerIdpUserID
+func (s *SavedViewService) CreateSavedView(ctx context.Context, ownerIdpUserID string, req *models.CreateSavedViewRequest) (*models.SavedViewResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "name", "name is required")
+	}
+	if err := ValidateCollectionQuery(req.Collection, req.Query); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(ctx, ownerIdpUserID, req.Collection, name, ""); err != nil {
+		return nil, err
+	}
+
+	query, err := json.Marshal(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal saved view query: %w", err)
+	}
+	view := models.SavedView{
+		ViewID:         "view_" + uuid.New().String(),
+		Name:           name,
+		Collection:     req.Collection,
+		Query:          string(query),
+		OwnerIdpUserID: ownerIdpUserID,
+		Shared:         req.Shared,
+	}
+	if err := s.db.WithContext(ctx).Create(&view).Error; err != nil {
+		return nil, fmt.Errorf("failed to create saved view: %w", err)
+	}
+	return savedViewResponseOf(view)
+}
+
+// ListSavedViews returns the views the admin saved and the views shared by other admins, by name,
+// optionally only those of one collection
+func (s *SavedViewService) ListSavedViews(ctx context.Context, idpUserID string, collection models.SavedViewCollection) ([]models.SavedViewResponse, error) {
+	query := s.db.WithContext(ctx).Where("owner_idp_user_id = ? OR shared = ?", idpUserID, true)
+	if collection != "" {
+		query = query.Where("collection = ?", collection)
+	}
+	var views []models.SavedView
+	if err := query.Order("name, view_id").Find(&views).Error; err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+
+	responses := make([]models.SavedViewResponse, 0, len(views))
+	for _, view := range views {
+		response, err := savedViewResponseOf(view)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// GetSavedView returns a view the admin saved or that is shared
+func (s *SavedViewService) GetSavedView(ctx context.Context, idpUserID, viewID string) (*models.SavedViewResponse, error) {
+	view, err := s.visibleView(ctx, idpUserID, viewID)
+	if err != nil {
+		return nil, err
+	}
+	return savedViewResponseOf(*view)
+}
+
+// UpdateSavedView changes a view the admin saved
+func (s *SavedViewService) UpdateSavedView(ctx context.Context, idpUserID, viewID string, req *models.UpdateSavedViewRequest) (*models.SavedViewResponse, error) {
+	view, err := s.ownedView(ctx, idpUserID, viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, models.NewValidationError(nil, models.ValidationErrorRequired, "name", "name must not be empty")
+		}
+		if err := s.checkNameAvailable(ctx, idpUserID, view.Collection, name, view.ViewID); err != nil {
+			return nil, err
+		}
+		updates["name"] = name
+	}
+	if req.Query != nil {
+		if err := ValidateCollectionQuery(view.Collection, *req.Query); err != nil {
+			return nil, err
+		}
+		query, err := json.Marshal(req.Query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal saved view query: %w", err)
+		}
+		updates["query"] = string(query)
+	}
+	if req.Shared != nil {
+		updates["shared"] = *req.Shared
+	}
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := s.db.WithContext(ctx).Model(view).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("failed to update saved view: %w", err)
+		}
+	}
+
+	if err := s.db.WithContext(ctx).First(view, "view_id = ?", viewID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+	return savedViewResponseOf(*view)
+}
+
+// DeleteSavedView deletes a view the admin saved
+func (s *SavedViewService) DeleteSavedView(ctx context.Context, idpUserID, viewID string) error {
+	view, err := s.ownedView(ctx, idpUserID, viewID)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Delete(view).Error; err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+	return nil
+}
+
+// visibleView loads a view the admin saved or that is shared. Private views of other admins are
+// reported as not found.
+func (s *SavedViewService) visibleView(ctx context.Context, idpUserID, viewID string) (*models.SavedView, error) {
+	var view models.SavedView
+	err := s.db.WithContext(ctx).Where("owner_idp_user_id = ? OR shared = ?", idpUserID, true).
+		First(&view, "view_id = ?", viewID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+	return &view, nil
+}
+
+// ownedView loads a view the admin saved
+func (s *SavedViewService) ownedView(ctx context.Context, idpUserID, viewID string) (*models.SavedView, error) {
+	view, err := s.visibleView(ctx, idpUserID, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if view.OwnerIdpUserID != idpUserID {
+		return nil, ErrSavedViewNotOwned
+	}
+	return view, nil
+}
+
+// checkNameAvailable checks that the owner has no other view of the collection with the name
+func (s *SavedViewService) checkNameAvailable(ctx context.Context, ownerIdpUserID string, collection models.SavedViewCollection, name, exceptViewID string) error {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.SavedView{}).
+		Where("owner_idp_user_id = ? AND collection = ? AND LOWER(name) = ? AND view_id <> ?", ownerIdpUserID, collection, strings.ToLower(name), exceptViewID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to check saved view names: %w", err)
+	}
+	if count > 0 {
+		return ErrSavedViewNameTaken
+	}
+	return nil
+}
+
+// ValidateCollectionQuery checks that a query only sorts and filters by what the collection supports
+func ValidateCollectionQuery(collection models.SavedViewCollection, query models.SavedViewQuery) error {
+	columns, ok := collectionListColumns[collection]
+	if !ok {
+		return models.NewValidationError(ErrInvalidListQuery, models.ValidationErrorInvalidValue, "collection",
+			"collection must be members, applications, application-submissions or schema-submissions")
+	}
+	q := query.ToListQuery()
+	if _, err := columns.normalize(&q); err != nil {
+		return err
+	}
+
+	unsupported := func(field string) error {
+		return models.NewValidationError(ErrInvalidListQuery, models.ValidationErrorInvalidValue, field,
+			fmt.Sprintf("%s cannot be filtered by %s", collection, field))
+	}
+	if len(query.Status) > 0 && !collection.HasStatus() {
+		return unsupported("status")
+	}
+	if collection == models.SavedViewCollectionMembers {
+		if query.MemberID != nil && *query.MemberID != "" {
+			return unsupported("memberId")
+		}
+	} else {
+		if query.IdpUserID != nil && *query.IdpUserID != "" {
+			return unsupported("idpUserId")
+		}
+		if query.Email != nil && *query.Email != "" {
+			return unsupported("email")
+		}
+	}
+	return nil
+}
+
+func savedViewResponseOf(view models.SavedView) (*models.SavedViewResponse, error) {
+	var query models.SavedViewQuery
+	if err := json.Unmarshal([]byte(view.Query), &query); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saved view query: %w", err)
+	}
+	return &models.SavedViewResponse{
+		ViewID:         view.ViewID,
+		Name:           view.Name,
+		Collection:     view.Collection,
+		Query:          query,
+		OwnerIdpUserID: view.OwnerIdpUserID,
+		Shared:         view.Shared,
+		CreatedAt:      view.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      view.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
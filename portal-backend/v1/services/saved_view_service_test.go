@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedViewService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	service := NewSavedViewService(db)
+	ctx := context.Background()
+
+	memberID := "mem_provider"
+	view, err := service.CreateSavedView(ctx, "idp-admin-1", &models.CreateSavedViewRequest{
+		Name:       "Pending submissions",
+		Collection: models.SavedViewCollectionSchemaSubmissions,
+		Query:      models.SavedViewQuery{Sort: "status", Order: models.SortOrderAsc, Status: []string{"pending"}, MemberID: &memberID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "idp-admin-1", view.OwnerIdpUserID)
+	assert.Equal(t, []string{"pending"}, view.Query.Status)
+	assert.Equal(t, memberID, *view.Query.MemberID)
+
+	t.Run("Rejects queries the collection does not support", func(t *testing.T) {
+		_, err := service.CreateSavedView(ctx, "idp-admin-1", &models.CreateSavedViewRequest{
+			Name: "By status", Collection: models.SavedViewCollectionMembers, Query: models.SavedViewQuery{Status: []string{"pending"}},
+		})
+		assert.ErrorIs(t, err, ErrInvalidListQuery)
+
+		_, err = service.CreateSavedView(ctx, "idp-admin-1", &models.CreateSavedViewRequest{
+			Name: "By secret", Collection: models.SavedViewCollectionApplications, Query: models.SavedViewQuery{Sort: "clientSecret"},
+		})
+		assert.ErrorIs(t, err, ErrInvalidListQuery)
+
+		_, err = service.CreateSavedView(ctx, "idp-admin-1", &models.CreateSavedViewRequest{Name: "Unknown", Collection: "schemas"})
+		assert.ErrorIs(t, err, ErrInvalidListQuery)
+	})
+
+	t.Run("Names are unique per owner and collection", func(t *testing.T) {
+		_, err := service.CreateSavedView(ctx, "idp-admin-1", &models.CreateSavedViewRequest{
+			Name: "pending submissions", Collection: models.SavedViewCollectionSchemaSubmissions,
+		})
+		assert.ErrorIs(t, err, ErrSavedViewNameTaken)
+
+		_, err = service.CreateSavedView(ctx, "idp-admin-2", &models.CreateSavedViewRequest{
+			Name: "Pending submissions", Collection: models.SavedViewCollectionSchemaSubmissions,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Private views are only visible to their owner", func(t *testing.T) {
+		_, err := service.GetSavedView(ctx, "idp-admin-3", view.ViewID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		views, err := service.ListSavedViews(ctx, "idp-admin-3", "")
+		require.NoError(t, err)
+		assert.Empty(t, views)
+	})
+
+	t.Run("Shared views are visible to every admin but only changed by their owner", func(t *testing.T) {
+		shared := true
+		updated, err := service.UpdateSavedView(ctx, "idp-admin-1", view.ViewID, &models.UpdateSavedViewRequest{Shared: &shared})
+		require.NoError(t, err)
+		assert.True(t, updated.Shared)
+		assert.Equal(t, []string{"pending"}, updated.Query.Status, "the query is kept")
+
+		views, err := service.ListSavedViews(ctx, "idp-admin-3", models.SavedViewCollectionSchemaSubmissions)
+		require.NoError(t, err)
+		require.Len(t, views, 1)
+		assert.Equal(t, view.ViewID, views[0].ViewID)
+
+		views, err = service.ListSavedViews(ctx, "idp-admin-3", models.SavedViewCollectionMembers)
+		require.NoError(t, err)
+		assert.Empty(t, views)
+
+		name := "Taken over"
+		_, err = service.UpdateSavedView(ctx, "idp-admin-3", view.ViewID, &models.UpdateSavedViewRequest{Name: &name})
+		assert.ErrorIs(t, err, ErrSavedViewNotOwned)
+		assert.ErrorIs(t, service.DeleteSavedView(ctx, "idp-admin-3", view.ViewID), ErrSavedViewNotOwned)
+	})
+
+	t.Run("Owner updates and deletes the view", func(t *testing.T) {
+		name := "Approved submissions"
+		updated, err := service.UpdateSavedView(ctx, "idp-admin-1", view.ViewID, &models.UpdateSavedViewRequest{
+			Name:  &name,
+			Query: &models.SavedViewQuery{Status: []string{"approved"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, name, updated.Name)
+		assert.Equal(t, []string{"approved"}, updated.Query.Status)
+		assert.Nil(t, updated.Query.MemberID, "a query replaces the saved one")
+
+		require.NoError(t, service.DeleteSavedView(ctx, "idp-admin-1", view.ViewID))
+		_, err = service.GetSavedView(ctx, "idp-admin-1", view.ViewID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+}
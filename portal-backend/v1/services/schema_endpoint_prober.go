@@ -0,0 +1,150 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/portal-backend/v1/utils"
+	"github.com/gov-dx-sandbox/shared/requestid"
+)
+
+var (
+	// ErrSchemaEndpointMismatch is returned when a schema submission's endpoint is unreachable or
+	// does not serve its SDL
+	ErrSchemaEndpointMismatch = errors.New("schema endpoint does not serve the submitted SDL")
+	// ErrSchemaEndpointAddressBlocked is returned when a schema endpoint resolves to a private,
+	// loopback or link-local address, which probes must not reach
+	ErrSchemaEndpointAddressBlocked = errors.New("schema endpoint address is not public")
+)
+
+// SchemaEndpointMismatchError blocks the approval of a schema submission whose endpoint failed the
+// probe. It wraps ErrSchemaEndpointMismatch.
+type SchemaEndpointMismatchError struct {
+	Report *models.SchemaEndpointReport
+}
+
+func (e *SchemaEndpointMismatchError) Error() string {
+	if e.Report.Error != "" {
+		return fmt.Sprintf("%s: %s", ErrSchemaEndpointMismatch, e.Report.Error)
+	}
+	return ErrSchemaEndpointMismatch.Error()
+}
+
+func (e *SchemaEndpointMismatchError) Unwrap() error {
+	return ErrSchemaEndpointMismatch
+}
+
+// healthQuery is sent when an endpoint refuses introspection, to check it serves GraphQL at all
+const healthQuery = `{ __typename }`
+
+// maxProbeResponseSize bounds the introspection result read from a provider endpoint
+const maxProbeResponseSize = 10 << 20
+
+// SchemaEndpointProber checks that the GraphQL endpoint of a schema submission is reachable and
+// serves the submitted SDL, by introspecting it and diffing the result against the SDL
+type SchemaEndpointProber struct {
+	// HTTPClient is used to query provider endpoints; its timeout bounds each probe query
+	HTTPClient *http.Client
+}
+
+// NewSchemaEndpointProber creates a prober whose queries time out after timeout. Schema endpoints
+// are chosen by providers, so probes only reach public https endpoints and do not follow redirects.
+func NewSchemaEndpointProber(timeout time.Duration) *SchemaEndpointProber {
+	return &SchemaEndpointProber{HTTPClient: newPublicHTTPClient(timeout, ErrSchemaEndpointAddressBlocked)}
+}
+
+// graphQLResponse is a GraphQL response with its data left undecoded
+type graphQLResponse struct {
+	Data map[string]json.RawMessage `json:"data"`
+}
+
+// Probe introspects endpoint and compares its schema with sdl. Failures of the endpoint are
+// reported in the returned report rather than as an error.
+func (p *SchemaEndpointProber) Probe(ctx context.Context, endpoint, sdl string) *models.SchemaEndpointReport {
+	start := time.Now()
+	report := &models.SchemaEndpointReport{Endpoint: endpoint, CheckedAt: start.Format(time.RFC3339)}
+	defer func() { report.LatencyMs = time.Since(start).Milliseconds() }()
+
+	response, err := p.query(ctx, endpoint, utils.IntrospectionQuery)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Reachable = true
+
+	if !answered(response, "__schema") {
+		// Introspection is often disabled in production; fall back to checking the endpoint is up
+		health, err := p.query(ctx, endpoint, healthQuery)
+		if err != nil || !answered(health, "__typename") {
+			report.Reachable = false
+			report.Error = "endpoint refused introspection and did not answer a health query"
+			if err != nil {
+				report.Error += ": " + err.Error()
+			}
+			return report
+		}
+		report.Matches = true
+		return report
+	}
+
+	diff, err := utils.DiffIntrospection(sdl, response.Data["__schema"])
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.SDLVerified = true
+	report.Diff = diff
+	report.Matches = !diff.Breaking
+	if diff.Breaking {
+		report.Error = "endpoint is missing or changes types and fields the submitted SDL declares"
+	}
+	return report
+}
+
+// answered reports whether a GraphQL response has a non-null value for field
+func answered(response *graphQLResponse, field string) bool {
+	value, ok := response.Data[field]
+	return ok && string(value) != "null"
+}
+
+// query sends a GraphQL query to endpoint. It fails when the endpoint cannot be reached or does not
+// answer with a GraphQL response; errors in the response are left to the caller.
+func (p *SchemaEndpointProber) query(ctx context.Context, endpoint, query string) (*graphQLResponse, error) {
+	if parsed, err := url.Parse(endpoint); err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return nil, errors.New("invalid endpoint: must be an absolute https URL")
+	}
+	body, err := json.Marshal(models.GraphQLRequest{Query: query})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	requestid.SetHeader(req)
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("endpoint returned HTTP %d", resp.StatusCode)
+	}
+
+	var response graphQLResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxProbeResponseSize)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("endpoint did not return a GraphQL response: %w", err)
+	}
+	return &response, nil
+}
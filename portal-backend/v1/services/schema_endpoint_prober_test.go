@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// providerEndpoint serves a GraphQL endpoint whose introspection reports a Query type with a name
+// field. With introspection disabled it only answers other queries.
+func providerEndpoint(t *testing.T, introspection bool) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.GraphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case !strings.Contains(req.Query, "__schema"):
+			_, _ = w.Write([]byte(`{"data": {"__typename": "Query"}}`))
+		case introspection:
+			_, _ = w.Write([]byte(`{"data": {"__schema": {"types": [
+				{"kind": "OBJECT", "name": "Query", "fields": [{"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}}]}
+			]}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "introspection is disabled"}]}`))
+		}
+	}))
+}
+
+func TestSchemaEndpointProber(t *testing.T) {
+	ctx := context.Background()
+	prober := NewSchemaEndpointProber(5 * time.Second)
+
+	t.Run("An endpoint serving the SDL matches", func(t *testing.T) {
+		endpoint := providerEndpoint(t, true)
+		defer endpoint.Close()
+		prober.HTTPClient = publicTestClient(endpoint)
+
+		report := prober.Probe(ctx, endpoint.URL, "type Query { name: String }")
+		assert.True(t, report.Reachable)
+		assert.True(t, report.SDLVerified)
+		assert.True(t, report.Matches)
+		assert.Empty(t, report.Error)
+		require.NotNil(t, report.Diff)
+		assert.False(t, report.Diff.HasChanges())
+	})
+
+	t.Run("An endpoint missing declared fields does not match", func(t *testing.T) {
+		endpoint := providerEndpoint(t, true)
+		defer endpoint.Close()
+		prober.HTTPClient = publicTestClient(endpoint)
+
+		report := prober.Probe(ctx, endpoint.URL, "type Query { name: String nic: String }")
+		assert.True(t, report.Reachable)
+		assert.False(t, report.Matches)
+		assert.NotEmpty(t, report.Error)
+		require.NotNil(t, report.Diff)
+		assert.Equal(t, []models.FieldChange{{Path: "Query.nic", OldType: "String", Breaking: true}}, report.Diff.RemovedFields)
+	})
+
+	t.Run("Endpoints without introspection are only checked for reachability", func(t *testing.T) {
+		endpoint := providerEndpoint(t, false)
+		defer endpoint.Close()
+		prober.HTTPClient = publicTestClient(endpoint)
+
+		report := prober.Probe(ctx, endpoint.URL, "type Query { name: String nic: String }")
+		assert.True(t, report.Reachable)
+		assert.False(t, report.SDLVerified)
+		assert.True(t, report.Matches)
+		assert.Nil(t, report.Diff)
+	})
+
+	t.Run("Failing endpoints are unreachable", func(t *testing.T) {
+		failing := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		prober.HTTPClient = publicTestClient(failing)
+
+		report := prober.Probe(ctx, failing.URL, "type Query { name: String }")
+		assert.False(t, report.Reachable)
+		assert.False(t, report.Matches)
+		assert.Contains(t, report.Error, "HTTP 503")
+
+		failing.Close()
+		report = prober.Probe(ctx, failing.URL, "type Query { name: String }")
+		assert.False(t, report.Reachable)
+		assert.Contains(t, report.Error, "unreachable")
+	})
+
+	t.Run("Private addresses and plain http endpoints are not probed", func(t *testing.T) {
+		var calls int
+		endpoint := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		defer endpoint.Close()
+
+		prober := NewSchemaEndpointProber(5 * time.Second)
+		for _, url := range []string{endpoint.URL, "https://localhost:1/graphql", "https://169.254.169.254/graphql", "https://10.0.0.5:1/graphql"} {
+			report := prober.Probe(ctx, url, "type Query { name: String }")
+			assert.False(t, report.Reachable, url)
+			assert.Contains(t, report.Error, ErrSchemaEndpointAddressBlocked.Error(), url)
+		}
+		report := prober.Probe(ctx, "http://provider.example.com/graphql", "type Query { name: String }")
+		assert.False(t, report.Reachable)
+		assert.Contains(t, report.Error, "https")
+		assert.Zero(t, calls)
+	})
+}
+
+func TestSchemaSubmissionApprovalProbesEndpoint(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer pdpServer.Close()
+	endpoint := providerEndpoint(t, true)
+	defer endpoint.Close()
+
+	prober := NewSchemaEndpointProber(5 * time.Second)
+	prober.HTTPClient = publicTestClient(endpoint)
+	workflow := []models.ReviewStep{{Name: "technical_review", RequiredApprovals: 1}}
+	service := NewSubmissionReviewService(db, NewSchemaService(db, NewPDPService(pdpServer.URL, "test-key")), nil,
+		prober, nil, workflow)
+	approve := &models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove}
+
+	// The submitted SDL declares a field the endpoint does not serve
+	require.NoError(t, db.Model(&models.SchemaSubmission{}).Where("submission_id = ?", "sub_schema").Updates(map[string]interface{}{
+		"schema_endpoint": endpoint.URL,
+		"sdl":             "type Query { name: String nic: String }",
+	}).Error)
+
+	report, err := service.CheckSchemaEndpoint(ctx, "sub_schema")
+	require.NoError(t, err)
+	assert.False(t, report.Matches)
+
+	_, err = service.Review(ctx, models.SubmissionTypeSchema, "sub_schema", "reviewer-1", approve)
+	var mismatchErr *SchemaEndpointMismatchError
+	require.ErrorAs(t, err, &mismatchErr)
+	assert.ErrorIs(t, err, ErrSchemaEndpointMismatch)
+	assert.Equal(t, endpoint.URL, mismatchErr.Report.Endpoint)
+
+	var submission models.SchemaSubmission
+	require.NoError(t, db.First(&submission, "submission_id = ?", "sub_schema").Error)
+	assert.Equal(t, "technical_review", submission.Status, "a blocked approval can be retried")
+
+	// Once the endpoint serves the SDL the submission is approved
+	require.NoError(t, db.Model(&submission).Update("sdl", "type Query { name: String }").Error)
+	review, err := service.Review(ctx, models.SubmissionTypeSchema, "sub_schema", "reviewer-1", approve)
+	require.NoError(t, err)
+	assert.Equal(t, string(models.StatusApproved), review.Status)
+
+	_, err = service.CheckSchemaEndpoint(ctx, "sub_missing")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+	_, err = NewSubmissionReviewService(db, nil, nil, nil, nil, workflow).CheckSchemaEndpoint(ctx, "sub_schema")
+	assert.ErrorIs(t, err, ErrSchemaEndpointProbeDisabled)
+}
@@ -1,39 +1,103 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/portal-backend/v1/utils"
+	"github.com/gov-dx-sandbox/shared/database"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrNoPreviousSchema is returned when diffing a submission that does not replace an existing schema
+	ErrNoPreviousSchema = errors.New("schema submission does not replace an existing schema")
+	// ErrInvalidSDL is returned when a submitted SDL cannot be parsed
+	ErrInvalidSDL = errors.New("invalid SDL")
+	// ErrInvalidSchemaLifecycle is returned for a schema version change the lifecycle does not allow
+	ErrInvalidSchemaLifecycle = errors.New("invalid schema lifecycle change")
+	// ErrInvalidFieldConfiguration is returned for a field configuration that does not name a field of
+	// the SDL or has an unknown setting
+	ErrInvalidFieldConfiguration = errors.New("invalid field configuration")
+)
+
+// SDLValidationError is returned when a submitted SDL fails validation or linting. It wraps ErrInvalidSDL.
+type SDLValidationError struct {
+	Errors []models.SDLLintError
+}
+
+func (e *SDLValidationError) Error() string {
+	return fmt.Sprintf("%s: %d problem(s), first: %s", ErrInvalidSDL, len(e.Errors), e.Errors[0].Message)
+}
+
+func (e *SDLValidationError) Unwrap() error {
+	return ErrInvalidSDL
+}
+
+// validateSchemaSubmission returns an error if a schema submission is not ready for review
+func validateSchemaSubmission(schemaName, schemaEndpoint, sdl string) error {
+	if err := requireFields(
+		requiredField{name: "schemaName", value: schemaName},
+		requiredField{name: "schemaEndpoint", value: schemaEndpoint},
+	); err != nil {
+		return err
+	}
+	// Reject invalid SDL before it reaches admin review
+	return lintSubmissionSDL(sdl)
+}
+
+// lintSubmissionSDL returns an SDLValidationError if sdl is not an acceptable provider schema
+func lintSubmissionSDL(sdl string) error {
+	if lintErrors := utils.LintSDL(sdl); len(lintErrors) > 0 {
+		return &SDLValidationError{Errors: lintErrors}
+	}
+	return nil
+}
+
 // SchemaService handles schema-related operations
 type SchemaService struct {
 	db            *gorm.DB
 	policyService *PDPService
+	pdpJobs       *PDPJobService
 }
 
 // NewSchemaService creates a new schema service
 func NewSchemaService(db *gorm.DB, policyService *PDPService) *SchemaService {
-	return &SchemaService{db: db, policyService: policyService}
+	return &SchemaService{db: db, policyService: policyService, pdpJobs: NewPDPJobService(db, policyService)}
 }
 
 // CreateSchema creates a new schema
 func (s *SchemaService) CreateSchema(req *models.CreateSchemaRequest) (*models.SchemaResponse, error) {
+	// The schema belongs to its member's organization
+	organizationID, err := memberOrganizationID(s.db, req.MemberID)
+	if err != nil {
+		return nil, err
+	}
+
 	schema := models.Schema{
-		SchemaID:   "sch_" + uuid.New().String(),
-		SchemaName: req.SchemaName,
-		SDL:        req.SDL,
-		Endpoint:   req.Endpoint,
-		MemberID:   req.MemberID,
-		Version:    string(models.ActiveVersion),
+		SchemaID:       "sch_" + uuid.New().String(),
+		SchemaName:     req.SchemaName,
+		SDL:            req.SDL,
+		Endpoint:       req.Endpoint,
+		MemberID:       req.MemberID,
+		OrganizationID: organizationID,
+		Version:        string(models.ActiveVersion),
 	}
 	if req.SchemaDescription != nil {
 		schema.SchemaDescription = req.SchemaDescription
 	}
+	if len(req.FieldConfigurations) > 0 {
+		if err := validateSchemaFieldConfigurations(req.SDL, req.FieldConfigurations); err != nil {
+			return nil, err
+		}
+		schema.FieldConfigurations = req.FieldConfigurations
+	}
 
 	// Step 1: Create schema in database first
 	if err := s.db.Create(&schema).Error; err != nil {
@@ -41,10 +105,10 @@ func (s *SchemaService) CreateSchema(req *models.CreateSchemaRequest) (*models.S
 	}
 
 	// Step 2: Create policy metadata in PDP (Saga Pattern)
-	_, err := s.policyService.CreatePolicyMetadata(schema.SchemaID, schema.SDL)
+	_, err = s.policyService.CreatePolicyMetadata(schema.SchemaID, schema.MemberID, schema.SDL, schema.FieldConfigurations)
 	if err != nil {
-		// Compensation: Delete the schema we just created
-		if deleteErr := s.db.Delete(&schema).Error; deleteErr != nil {
+		// Compensation: Delete the schema we just created; it never went live, so it is removed rather than soft-deleted
+		if deleteErr := s.db.Unscoped().Delete(&schema).Error; deleteErr != nil {
 			// Log the compensation failure - this needs monitoring
 			slog.Error("Failed to compensate schema creation",
 				"schemaID", schema.SchemaID,
@@ -57,21 +121,7 @@ func (s *SchemaService) CreateSchema(req *models.CreateSchemaRequest) (*models.S
 		return nil, fmt.Errorf("failed to create policy metadata in PDP: %w", err)
 	}
 
-	response := &models.SchemaResponse{
-		SchemaID:   schema.SchemaID,
-		SchemaName: schema.SchemaName,
-		SDL:        schema.SDL,
-		Endpoint:   schema.Endpoint,
-		Version:    schema.Version,
-		MemberID:   schema.MemberID,
-		CreatedAt:  schema.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  schema.UpdatedAt.Format(time.RFC3339),
-	}
-	if schema.SchemaDescription != nil && *schema.SchemaDescription != "" {
-		response.SchemaDescription = schema.SchemaDescription
-	}
-
-	return response, nil
+	return schemaResponseOf(&schema), nil
 }
 
 // UpdateSchema updates an existing schema
@@ -81,6 +131,9 @@ func (s *SchemaService) UpdateSchema(schemaID string, req *models.UpdateSchemaRe
 	if err != nil {
 		return nil, fmt.Errorf("schema not found: %w", err)
 	}
+	if err := checkRevision(req.Revision, schema.Revision); err != nil {
+		return nil, revisionConflict(s.GetSchema(schemaID))
+	}
 
 	// Update fields if provided
 	if req.SchemaName != nil {
@@ -89,35 +142,94 @@ func (s *SchemaService) UpdateSchema(schemaID string, req *models.UpdateSchemaRe
 	if req.SchemaDescription != nil {
 		schema.SchemaDescription = req.SchemaDescription
 	}
+	sdlChanged := req.SDL != nil && *req.SDL != schema.SDL
 	if req.SDL != nil {
 		schema.SDL = *req.SDL
 	}
 	if req.Endpoint != nil {
 		schema.Endpoint = *req.Endpoint
 	}
+	lifecycleChanged, err := updateSchemaLifecycle(&schema, req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// SDL and lifecycle changes are synced to the PDP by the PDP worker; the jobs are queued in the
+	// same transaction so the schema and its pending policy sync are committed together
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveRevision(tx, &schema, &schema.Revision); err != nil {
+			return fmt.Errorf("failed to update schema: %w", err)
+		}
+		if sdlChanged {
+			if _, err := s.pdpJobs.EnqueueCreatePolicyMetadata(tx, schema.SchemaID, schema.MemberID, schema.SDL, schema.FieldConfigurations); err != nil {
+				return err
+			}
+		}
+		if lifecycleChanged {
+			if _, err := s.pdpJobs.EnqueueUpdateSchemaLifecycle(tx, models.SchemaLifecycleUpdateRequest{
+				SchemaID: schema.SchemaID,
+				Status:   models.Version(schema.Version),
+				SunsetAt: schema.SunsetAt,
+				Message:  schema.DeprecationMessage,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, ErrRevisionConflict) {
+		return nil, revisionConflict(s.GetSchema(schemaID))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schemaResponseOf(&schema), nil
+}
+
+// updateSchemaLifecycle applies the version, sunset date and deprecation message of req to schema
+// and reports whether they changed. Deprecating a schema requires a sunset date in the future;
+// reactivating it clears the sunset date and message.
+func updateSchemaLifecycle(schema *models.Schema, req *models.UpdateSchemaRequest, now time.Time) (bool, error) {
+	from := models.Version(schema.Version)
+	to := from
 	if req.Version != nil {
-		schema.Version = *req.Version
+		to = models.Version(*req.Version)
+	}
+	if to != from && !models.CanTransitionSchema(from, to) {
+		return false, models.NewValidationError(ErrInvalidSchemaLifecycle, models.ValidationErrorInvalidValue, "version",
+			fmt.Sprintf("a schema cannot move from %s to %s", from, to))
 	}
 
-	if err := s.db.Save(&schema).Error; err != nil {
-		return nil, fmt.Errorf("failed to update schema: %w", err)
+	if !to.IsPhasedOut() {
+		if req.SunsetAt != nil || req.DeprecationMessage != nil {
+			return false, models.NewValidationError(ErrInvalidSchemaLifecycle, models.ValidationErrorInvalidRequest, "sunsetAt",
+				"only deprecated schemas have a sunset date and deprecation message")
+		}
+		changed := to != from || schema.SunsetAt != nil || schema.DeprecationMessage != nil
+		schema.Version, schema.SunsetAt, schema.DeprecationMessage = string(to), nil, nil
+		return changed, nil
 	}
 
-	response := &models.SchemaResponse{
-		SchemaID:   schema.SchemaID,
-		SchemaName: schema.SchemaName,
-		SDL:        schema.SDL,
-		Endpoint:   schema.Endpoint,
-		Version:    schema.Version,
-		MemberID:   schema.MemberID,
-		CreatedAt:  schema.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  schema.UpdatedAt.Format(time.RFC3339),
+	sunsetAt, message := schema.SunsetAt, schema.DeprecationMessage
+	if req.SunsetAt != nil {
+		if !req.SunsetAt.After(now) && to == models.DeprecatedVersion {
+			return false, models.NewValidationError(ErrInvalidSchemaLifecycle, models.ValidationErrorInvalidValue, "sunsetAt",
+				"sunsetAt must be in the future")
+		}
+		sunsetAt = req.SunsetAt
 	}
-	if schema.SchemaDescription != nil && *schema.SchemaDescription != "" {
-		response.SchemaDescription = schema.SchemaDescription
+	if req.DeprecationMessage != nil {
+		message = req.DeprecationMessage
+	}
+	if sunsetAt == nil {
+		return false, models.NewValidationError(ErrInvalidSchemaLifecycle, models.ValidationErrorRequired, "sunsetAt",
+			"sunsetAt is required to deprecate a schema")
 	}
 
-	return response, nil
+	changed := to != from || req.SunsetAt != nil || req.DeprecationMessage != nil
+	schema.Version, schema.SunsetAt, schema.DeprecationMessage = string(to), sunsetAt, message
+	return changed, nil
 }
 
 // GetSchema retrieves a schema by ID
@@ -128,63 +240,187 @@ func (s *SchemaService) GetSchema(schemaID string) (*models.SchemaResponse, erro
 		return nil, fmt.Errorf("schema not found: %w", err)
 	}
 
+	return schemaResponseOf(&schema), nil
+}
+
+// schemaConsumer is an application that selected fields of a schema
+type schemaConsumer struct {
+	ApplicationID   string
+	ApplicationName string
+	MemberID        string
+	// Fields are the application's selected fields that the schema provides
+	Fields []string
+}
+
+// schemaConsumers returns the applications that selected fields of a schema
+func schemaConsumers(ctx context.Context, db *gorm.DB, schemaID string) ([]schemaConsumer, error) {
+	var applications []models.Application
+	if err := db.WithContext(ctx).Select("application_id", "application_name", "member_id", "selected_fields").
+		Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("failed to load applications: %w", err)
+	}
+	var consumers []schemaConsumer
+	for _, application := range applications {
+		var fields []string
+		for _, field := range application.SelectedFields {
+			if field.SchemaID == schemaID {
+				fields = append(fields, field.FieldName)
+			}
+		}
+		if len(fields) > 0 {
+			consumers = append(consumers, schemaConsumer{
+				ApplicationID:   application.ApplicationID,
+				ApplicationName: application.ApplicationName,
+				MemberID:        application.MemberID,
+				Fields:          fields,
+			})
+		}
+	}
+	return consumers, nil
+}
+
+// GetSchemaConsumers lists the applications that selected fields of a schema, with their owning
+// members and the PDP grant status of each field
+func (s *SchemaService) GetSchemaConsumers(ctx context.Context, schemaID string) ([]models.SchemaConsumerResponse, error) {
+	consumers, err := schemaConsumers(ctx, s.db, schemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs := make([]string, 0, len(consumers))
+	for _, consumer := range consumers {
+		memberIDs = append(memberIDs, consumer.MemberID)
+	}
+	var members []models.Member
+	if err := s.db.WithContext(ctx).Where("member_id IN ?", memberIDs).Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to load members: %w", err)
+	}
+	membersByID := make(map[string]models.Member, len(members))
+	for _, member := range members {
+		membersByID[member.MemberID] = member
+	}
+
+	responses := make([]models.SchemaConsumerResponse, 0, len(consumers))
+	for _, consumer := range consumers {
+		grants, err := s.policyService.ListApplicationGrants(consumer.ApplicationID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrPolicyServiceUnavailable, err)
+		}
+		granted := make(map[string]models.ConsumerGrant, len(grants))
+		for _, grant := range grants {
+			if grant.SchemaID == schemaID {
+				granted[grant.FieldName] = grant
+			}
+		}
+
+		member := membersByID[consumer.MemberID]
+		response := models.SchemaConsumerResponse{
+			ApplicationID:   consumer.ApplicationID,
+			ApplicationName: consumer.ApplicationName,
+			MemberID:        consumer.MemberID,
+			MemberName:      member.Name,
+			MemberEmail:     member.Email,
+			Fields:          make([]models.SchemaConsumerField, 0, len(consumer.Fields)),
+		}
+		for _, fieldName := range consumer.Fields {
+			field := models.SchemaConsumerField{FieldName: fieldName, GrantStatus: models.GrantStatusMissing}
+			if grant, ok := granted[fieldName]; ok {
+				field.GrantStatus = models.GrantStatusActive
+				if grant.Expired {
+					field.GrantStatus = models.GrantStatusExpired
+				}
+				field.GrantExpiresAt = &grant.ExpiresAt
+			}
+			response.Fields = append(response.Fields, field)
+		}
+		responses = append(responses, response)
+	}
+
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].ApplicationID < responses[j].ApplicationID
+	})
+	return responses, nil
+}
+
+// schemaResponseOf converts a schema to its API response
+func schemaResponseOf(schema *models.Schema) *models.SchemaResponse {
 	response := &models.SchemaResponse{
-		SchemaID:   schema.SchemaID,
-		SchemaName: schema.SchemaName,
-		SDL:        schema.SDL,
-		Endpoint:   schema.Endpoint,
-		Version:    schema.Version,
-		MemberID:   schema.MemberID,
-		CreatedAt:  schema.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  schema.UpdatedAt.Format(time.RFC3339),
+		SchemaID:            schema.SchemaID,
+		SchemaName:          schema.SchemaName,
+		SDL:                 schema.SDL,
+		Endpoint:            schema.Endpoint,
+		Version:             schema.Version,
+		MemberID:            schema.MemberID,
+		OrganizationID:      schema.OrganizationID,
+		DeprecationMessage:  schema.DeprecationMessage,
+		CreatedAt:           schema.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           schema.UpdatedAt.Format(time.RFC3339),
+		Revision:            schema.Revision,
+		FieldConfigurations: schema.FieldConfigurations,
 	}
 	if schema.SchemaDescription != nil && *schema.SchemaDescription != "" {
 		response.SchemaDescription = schema.SchemaDescription
 	}
+	if schema.SunsetAt != nil {
+		sunsetAt := schema.SunsetAt.Format(time.RFC3339)
+		response.SunsetAt = &sunsetAt
+	}
+	return response
+}
 
-	return response, nil
+// schemaListColumns describes how schema collections are searched and sorted
+var schemaListColumns = listColumns{
+	sortable: map[string]string{
+		"createdAt":  "created_at",
+		"updatedAt":  "updated_at",
+		"schemaName": "schema_name",
+		"version":    "version",
+	},
+	search: "schema_name",
+	key:    "schema_id",
 }
 
-// GetSchemas Get all schemas and filter by member ID if given
-func (s *SchemaService) GetSchemas(memberID *string) ([]*models.SchemaResponse, error) {
-	var schemas []models.Schema
-	query := s.db
-	if memberID != nil && *memberID != "" {
-		query = query.Where("member_id = ?", *memberID)
+// GetSchemas retrieves a page of schemas, filtered by member, name and creation time
+func (s *SchemaService) GetSchemas(q models.ListQuery) ([]*models.SchemaResponse, *models.PaginationMetadata, error) {
+	order, err := schemaListColumns.normalize(&q)
+	if err != nil {
+		return nil, nil, err
 	}
+	query := schemaListColumns.filter(database.Reader(s.db).Model(&models.Schema{}), q).Session(&gorm.Session{})
 
-	// Order by created_at descending
-	query = query.Order("created_at DESC")
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count schemas: %w", err)
+	}
 
-	err := query.Find(&schemas).Error
+	var schemas []models.Schema
+	err = page(query.Order(order), q).Find(&schemas).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve schemas: %w", err)
+		return nil, nil, fmt.Errorf("failed to retrieve schemas: %w", err)
 	}
 
 	// Pre-allocate slice with known capacity for better performance
 	responses := make([]*models.SchemaResponse, 0, len(schemas))
 	for _, schema := range schemas {
-		resp := &models.SchemaResponse{
-			SchemaID:   schema.SchemaID,
-			SchemaName: schema.SchemaName,
-			SDL:        schema.SDL,
-			Endpoint:   schema.Endpoint,
-			Version:    schema.Version,
-			MemberID:   schema.MemberID,
-			CreatedAt:  schema.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:  schema.UpdatedAt.Format(time.RFC3339),
-		}
-		if schema.SchemaDescription != nil && *schema.SchemaDescription != "" {
-			resp.SchemaDescription = schema.SchemaDescription
-		}
-		responses = append(responses, resp)
+		responses = append(responses, schemaResponseOf(&schema))
 	}
 
-	return responses, nil
+	return responses, paginationMetadata(q, total), nil
 }
 
 // CreateSchemaSubmission creates a new schema
 func (s *SchemaService) CreateSchemaSubmission(req *models.CreateSchemaSubmissionRequest) (*models.SchemaSubmissionResponse, error) {
+	status, err := initialSubmissionStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+	// Drafts may be incomplete; they are validated when they are submitted
+	if status == string(models.StatusPending) {
+		if err := validateSchemaSubmission(req.SchemaName, req.SchemaEndpoint, req.SDL); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if member exists
 	var member models.Member
 	if err := s.db.First(&member, "member_id = ?", req.MemberID).Error; err != nil {
@@ -192,8 +428,8 @@ func (s *SchemaService) CreateSchemaSubmission(req *models.CreateSchemaSubmissio
 	}
 
 	// If PreviousSchemaID is provided, check if it exists
+	var previousSchema models.Schema
 	if req.PreviousSchemaID != nil {
-		var previousSchema models.Schema
 		if err := s.db.First(&previousSchema, "schema_id = ?", *req.PreviousSchemaID).Error; err != nil {
 			return nil, fmt.Errorf("previous schema not found: %w", err)
 		}
@@ -207,24 +443,49 @@ func (s *SchemaService) CreateSchemaSubmission(req *models.CreateSchemaSubmissio
 		SchemaDescription: req.SchemaDescription,
 		SDL:               req.SDL,
 		SchemaEndpoint:    req.SchemaEndpoint,
-		Status:            string(models.StatusPending),
+		Status:            status,
 		MemberID:          req.MemberID,
+		OrganizationID:    member.OrganizationID,
+	}
+	if req.PreviousSchemaID != nil {
+		submission.Diff = diffSubmission(&previousSchema, &submission)
+	}
+
+	// The provider type selects the template that pre-fills the field configurations
+	submission.ProviderType = normalizeProviderType(req.ProviderType)
+	if submission.ProviderType == nil && member.OrganizationID != nil {
+		var organization models.Organization
+		if err := s.db.First(&organization, "organization_id = ?", *member.OrganizationID).Error; err != nil {
+			return nil, fmt.Errorf("organization not found: %w", err)
+		}
+		submission.ProviderType = organization.ProviderType
+	}
+	template, err := providerTypeTemplate(s.db, submission.ProviderType)
+	if err != nil {
+		return nil, err
+	}
+	submission.FieldConfigurations, err = submissionFieldConfigurations(submission.SDL, template, nil, req.FieldConfigurations)
+	if err != nil {
+		return nil, err
 	}
 	if err := s.db.Create(&submission).Error; err != nil {
 		return nil, fmt.Errorf("failed to create schema submission: %w", err)
 	}
 
 	response := &models.SchemaSubmissionResponse{
-		SubmissionID:      submission.SubmissionID,
-		PreviousSchemaID:  submission.PreviousSchemaID,
-		SchemaName:        submission.SchemaName,
-		SchemaDescription: submission.SchemaDescription,
-		SDL:               submission.SDL,
-		SchemaEndpoint:    submission.SchemaEndpoint,
-		Status:            submission.Status,
-		MemberID:          submission.MemberID,
-		CreatedAt:         submission.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:         submission.UpdatedAt.Format(time.RFC3339),
+		SubmissionID:        submission.SubmissionID,
+		PreviousSchemaID:    submission.PreviousSchemaID,
+		SchemaName:          submission.SchemaName,
+		SchemaDescription:   submission.SchemaDescription,
+		SDL:                 submission.SDL,
+		SchemaEndpoint:      submission.SchemaEndpoint,
+		Status:              submission.Status,
+		MemberID:            submission.MemberID,
+		OrganizationID:      submission.OrganizationID,
+		CreatedAt:           submission.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           submission.UpdatedAt.Format(time.RFC3339),
+		FieldConfigurations: submission.FieldConfigurations,
+		ProviderType:        submission.ProviderType,
 	}
 
 	return response, nil
@@ -240,9 +501,9 @@ func (s *SchemaService) UpdateSchemaSubmission(submissionID string, req *models.
 	}
 
 	// Validate PreviousSchemaID first before making any updates
+	var previousSchema models.Schema
 	if req.PreviousSchemaID != nil {
 		// Check if the new PreviousSchemaID exists
-		var previousSchema models.Schema
 		if err := s.db.First(&previousSchema, "schema_id = ?", *req.PreviousSchemaID).Error; err != nil {
 			return nil, fmt.Errorf("previous schema not found: %w", err)
 		}
@@ -256,8 +517,14 @@ func (s *SchemaService) UpdateSchemaSubmission(submissionID string, req *models.
 		submission.SchemaDescription = req.SchemaDescription
 	}
 	if req.SDL != nil {
-		if *req.SDL == "" {
-			return nil, fmt.Errorf("SDL field cannot be empty")
+		// A draft's SDL is linted when the draft is submitted
+		if submission.Status != string(models.StatusDraft) {
+			if *req.SDL == "" {
+				return nil, fmt.Errorf("SDL field cannot be empty")
+			}
+			if err := lintSubmissionSDL(*req.SDL); err != nil {
+				return nil, err
+			}
 		}
 		submission.SDL = *req.SDL
 	}
@@ -269,13 +536,38 @@ func (s *SchemaService) UpdateSchemaSubmission(submissionID string, req *models.
 		submission.PreviousSchemaID = req.PreviousSchemaID
 	}
 
-	var shouldCreateSchema bool
+	// Recompute the diff when the SDL or the schema it replaces changes
+	if req.PreviousSchemaID != nil {
+		submission.Diff = diffSubmission(&previousSchema, &submission)
+	} else if req.SDL != nil && submission.PreviousSchemaID != nil {
+		if err := s.db.Unscoped().First(&previousSchema, "schema_id = ?", *submission.PreviousSchemaID).Error; err != nil {
+			return nil, fmt.Errorf("previous schema not found: %w", err)
+		}
+		submission.Diff = diffSubmission(&previousSchema, &submission)
+	}
+
+	// Suggest configurations for the changed SDL, keeping the provider's adjustments
+	if req.SDL != nil || len(req.FieldConfigurations) > 0 {
+		template, err := providerTypeTemplate(s.db, submission.ProviderType)
+		if err != nil {
+			return nil, err
+		}
+		configurations, err := submissionFieldConfigurations(submission.SDL, template, submission.FieldConfigurations, req.FieldConfigurations)
+		if err != nil {
+			return nil, err
+		}
+		submission.FieldConfigurations = configurations
+	}
+
 	if req.Status != nil {
-		submission.Status = *req.Status
-		// Mark that we need to create a schema after saving
-		if *req.Status == string(models.StatusApproved) {
-			shouldCreateSchema = true
+		if submission.Status == string(models.StatusDraft) && *req.Status != submission.Status {
+			return nil, ErrDraftSubmitRequired
 		}
+		// Approval and step changes go through the review workflow; a submission can only be rejected here
+		if *req.Status != submission.Status && *req.Status != string(models.StatusRejected) {
+			return nil, ErrSubmissionReviewRequired
+		}
+		submission.Status = *req.Status
 	}
 
 	if req.Review != nil {
@@ -287,43 +579,81 @@ func (s *SchemaService) UpdateSchemaSubmission(submissionID string, req *models.
 		return nil, fmt.Errorf("failed to update schema submission: %w", err)
 	}
 
-	// Create schema outside of transaction if approval was successful
-	if shouldCreateSchema {
-		var createSchemaRequest models.CreateSchemaRequest
-		createSchemaRequest.SchemaName = submission.SchemaName
-		createSchemaRequest.SchemaDescription = submission.SchemaDescription
-		createSchemaRequest.SDL = submission.SDL
-		createSchemaRequest.Endpoint = submission.SchemaEndpoint
-		createSchemaRequest.MemberID = submission.MemberID
+	response := &models.SchemaSubmissionResponse{
+		SubmissionID:        submission.SubmissionID,
+		PreviousSchemaID:    submission.PreviousSchemaID,
+		SchemaName:          submission.SchemaName,
+		SchemaDescription:   submission.SchemaDescription,
+		SDL:                 submission.SDL,
+		SchemaEndpoint:      submission.SchemaEndpoint,
+		Status:              submission.Status,
+		MemberID:            submission.MemberID,
+		OrganizationID:      submission.OrganizationID,
+		CreatedAt:           submission.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           submission.UpdatedAt.Format(time.RFC3339),
+		Review:              submission.Review,
+		FieldConfigurations: submission.FieldConfigurations,
+		ProviderType:        submission.ProviderType,
+	}
 
-		_, err := s.CreateSchema(&createSchemaRequest)
-		if err != nil {
-			// Compensation: Update submission status back to pending
-			submission.Status = string(models.StatusPending)
-			if updateErr := s.db.Save(&submission).Error; updateErr != nil {
-				slog.Error("Failed to compensate submission status after schema creation failure",
-					"submissionID", submission.SubmissionID,
-					"originalError", err,
-					"compensationError", updateErr)
-				return nil, fmt.Errorf("failed to create schema from approved submission: %w, and failed to compensate submission status: %w", err, updateErr)
-			}
-			slog.Info("Successfully compensated submission status after schema creation failure", "submissionID", submission.SubmissionID)
-			return nil, fmt.Errorf("failed to create schema from approved submission: %w", err)
+	return response, nil
+}
+
+// SubmitSchemaSubmission validates a draft schema submission and submits it for review
+func (s *SchemaService) SubmitSchemaSubmission(submissionID string) (*models.SchemaSubmissionResponse, error) {
+	var submission models.SchemaSubmission
+	if err := s.db.First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get schema submission: %w", err)
+	}
+	if submission.Status != string(models.StatusDraft) {
+		return nil, ErrSubmissionNotDraft
+	}
+	if err := validateSchemaSubmission(submission.SchemaName, submission.SchemaEndpoint, submission.SDL); err != nil {
+		return nil, err
+	}
+
+	// The draft's diff was computed against unvalidated SDL, so compute it again
+	if submission.PreviousSchemaID != nil {
+		var previousSchema models.Schema
+		if err := s.db.First(&previousSchema, "schema_id = ?", *submission.PreviousSchemaID).Error; err != nil {
+			return nil, fmt.Errorf("previous schema not found: %w", err)
 		}
+		submission.Diff = diffSubmission(&previousSchema, &submission)
+	}
+	// The draft's SDL may not have parsed when it was saved
+	template, err := providerTypeTemplate(s.db, submission.ProviderType)
+	if err != nil {
+		return nil, err
+	}
+	configurations, err := submissionFieldConfigurations(submission.SDL, template, submission.FieldConfigurations, nil)
+	if err != nil {
+		return nil, err
+	}
+	submission.FieldConfigurations = configurations
+
+	submission.Status = string(models.StatusPending)
+	if err := s.db.Save(&submission).Error; err != nil {
+		return nil, fmt.Errorf("failed to submit schema submission: %w", err)
 	}
 
 	response := &models.SchemaSubmissionResponse{
-		SubmissionID:      submission.SubmissionID,
-		PreviousSchemaID:  submission.PreviousSchemaID,
-		SchemaName:        submission.SchemaName,
-		SchemaDescription: submission.SchemaDescription,
-		SDL:               submission.SDL,
-		SchemaEndpoint:    submission.SchemaEndpoint,
-		Status:            submission.Status,
-		MemberID:          submission.MemberID,
-		CreatedAt:         submission.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:         submission.UpdatedAt.Format(time.RFC3339),
-		Review:            submission.Review,
+		SubmissionID:        submission.SubmissionID,
+		PreviousSchemaID:    submission.PreviousSchemaID,
+		SchemaName:          submission.SchemaName,
+		SchemaDescription:   submission.SchemaDescription,
+		SDL:                 submission.SDL,
+		SchemaEndpoint:      submission.SchemaEndpoint,
+		Status:              submission.Status,
+		MemberID:            submission.MemberID,
+		OrganizationID:      submission.OrganizationID,
+		CreatedAt:           submission.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           submission.UpdatedAt.Format(time.RFC3339),
+		Review:              submission.Review,
+		FieldConfigurations: submission.FieldConfigurations,
+		ProviderType:        submission.ProviderType,
 	}
 
 	return response, nil
@@ -338,58 +668,289 @@ func (s *SchemaService) GetSchemaSubmission(submissionID string) (*models.Schema
 	}
 
 	response := &models.SchemaSubmissionResponse{
-		SubmissionID:      submission.SubmissionID,
-		PreviousSchemaID:  submission.PreviousSchemaID,
-		SchemaName:        submission.SchemaName,
-		SchemaDescription: submission.SchemaDescription,
-		SDL:               submission.SDL,
-		SchemaEndpoint:    submission.SchemaEndpoint,
-		Status:            submission.Status,
-		MemberID:          submission.MemberID,
-		CreatedAt:         submission.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:         submission.UpdatedAt.Format(time.RFC3339),
-		Review:            submission.Review,
+		SubmissionID:        submission.SubmissionID,
+		PreviousSchemaID:    submission.PreviousSchemaID,
+		SchemaName:          submission.SchemaName,
+		SchemaDescription:   submission.SchemaDescription,
+		SDL:                 submission.SDL,
+		SchemaEndpoint:      submission.SchemaEndpoint,
+		Status:              submission.Status,
+		MemberID:            submission.MemberID,
+		OrganizationID:      submission.OrganizationID,
+		CreatedAt:           submission.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           submission.UpdatedAt.Format(time.RFC3339),
+		Review:              submission.Review,
+		FieldConfigurations: submission.FieldConfigurations,
+		ProviderType:        submission.ProviderType,
 	}
 
 	return response, nil
 }
 
-// GetSchemaSubmissions Get all schema submissions and filter by member ID OR Status Array if given
-func (s *SchemaService) GetSchemaSubmissions(memberID *string, statusFilter *[]string) ([]*models.SchemaSubmissionResponse, error) {
-	var submissions []models.SchemaSubmission
-	query := s.db.Preload("PreviousSchema").Preload("Member")
-	if memberID != nil && *memberID != "" {
-		query = query.Where("member_id = ?", *memberID)
+// GetSchemaSubmissionDiff returns the SDL diff of a schema submission against the schema it replaces.
+// Submissions made before diffs were stored have theirs computed and stored on first read.
+func (s *SchemaService) GetSchemaSubmissionDiff(submissionID string) (*models.SchemaDiff, error) {
+	var submission models.SchemaSubmission
+	if err := s.db.First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve schema submission: %w", err)
+	}
+	if submission.PreviousSchemaID == nil {
+		return nil, ErrNoPreviousSchema
+	}
+	if submission.Diff != nil {
+		return submission.Diff, nil
+	}
+
+	// The schema being replaced may since have been deleted; its SDL is still the baseline
+	var previousSchema models.Schema
+	if err := s.db.Unscoped().First(&previousSchema, "schema_id = ?", *submission.PreviousSchemaID).Error; err != nil {
+		return nil, fmt.Errorf("previous schema not found: %w", err)
+	}
+	diff, err := utils.DiffSDL(previousSchema.SDL, submission.SDL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSDL, err)
+	}
+	diff.PreviousSchemaID = previousSchema.SchemaID
+
+	if err := s.db.Model(&submission).Update("diff", diff).Error; err != nil {
+		slog.Warn("Failed to store schema submission diff", "submissionID", submissionID, "error", err)
+	}
+	return diff, nil
+}
+
+// diffSubmission computes the SDL diff of a submission against the schema it replaces. An SDL that
+// cannot be parsed yields no diff; GetSchemaSubmissionDiff reports the parse error when it is read.
+func diffSubmission(previousSchema *models.Schema, submission *models.SchemaSubmission) *models.SchemaDiff {
+	diff, err := utils.DiffSDL(previousSchema.SDL, submission.SDL)
+	if err != nil {
+		slog.Warn("Failed to diff schema submission SDL",
+			"submissionID", submission.SubmissionID,
+			"previousSchemaID", previousSchema.SchemaID,
+			"error", err)
+		return nil
+	}
+	diff.PreviousSchemaID = previousSchema.SchemaID
+	return diff
+}
+
+// submissionFieldConfigurations suggests the field configurations of a submission's SDL from the
+// template of its provider type, if any, keeping the configurations of previous that the provider
+// adjusted and applying the adjustments. An SDL that cannot be parsed yields no configurations,
+// which is an error only if adjustments are given.
+func submissionFieldConfigurations(sdl string, template *models.FieldConfigurationTemplate, previous, adjustments models.FieldConfigurations) (models.FieldConfigurations, error) {
+	configurations, err := utils.SuggestTemplateFieldConfigurations(sdl, template)
+	if err != nil {
+		if len(adjustments) > 0 {
+			return nil, models.NewValidationError(ErrInvalidFieldConfiguration, models.ValidationErrorInvalidRequest,
+				"fieldConfigurations", "field configurations can only be given for an SDL that can be parsed")
+		}
+		return nil, nil
+	}
+
+	indexes := make(map[string]int, len(configurations))
+	for i, configuration := range configurations {
+		indexes[configuration.FieldName] = i
+	}
+	adjust := func(configuration models.FieldConfiguration) {
+		index := indexes[configuration.FieldName]
+		configuration.Type = configurations[index].Type
+		if configuration.Source == "" {
+			configuration.Source = models.SourceFallback
+		}
+		configuration.Reason = ""
+		configuration.Adjusted = true
+		configuration.ConsentRequired = configuration.RequiresConsent()
+		configurations[index] = configuration
+	}
+	for _, configuration := range previous {
+		if _, ok := indexes[configuration.FieldName]; ok && configuration.Adjusted {
+			adjust(configuration)
+		}
 	}
+	if err := validateFieldConfigurations(adjustments, indexes); err != nil {
+		return nil, err
+	}
+	for _, configuration := range adjustments {
+		adjust(configuration)
+	}
+	return configurations, nil
+}
 
-	// Order by created_at descending
-	query = query.Order("created_at DESC")
+// validateSchemaFieldConfigurations returns a ValidationError if a configuration does not name a
+// field of sdl or has an unknown setting
+func validateSchemaFieldConfigurations(sdl string, configurations models.FieldConfigurations) error {
+	suggested, err := utils.SuggestFieldConfigurations(sdl)
+	if err != nil {
+		return models.NewValidationError(ErrInvalidFieldConfiguration, models.ValidationErrorInvalidRequest,
+			"fieldConfigurations", "field configurations can only be given for an SDL that can be parsed")
+	}
+	fields := make(map[string]int, len(suggested))
+	for i, configuration := range suggested {
+		fields[configuration.FieldName] = i
+	}
+	return validateFieldConfigurations(configurations, fields)
+}
+
+// validateFieldConfigurations returns a ValidationError if a configuration does not name one of
+// fields or has an unknown setting
+func validateFieldConfigurations(configurations models.FieldConfigurations, fields map[string]int) error {
+	var fieldErrors []models.FieldError
+	for i, configuration := range configurations {
+		prefix := fmt.Sprintf("fieldConfigurations[%d].", i)
+		if _, ok := fields[configuration.FieldName]; !ok {
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"fieldName",
+				fmt.Sprintf("%q is not a field of the SDL", configuration.FieldName)))
+		}
+		switch configuration.Source {
+		case "", models.SourcePrimary, models.SourceFallback:
+		default:
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"source",
+				"source must be primary or fallback"))
+		}
+		switch configuration.AccessControlType {
+		case "", models.AccessControlTypePublic, models.AccessControlTypeRestricted:
+		default:
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"accessControlType",
+				"accessControlType must be public or restricted"))
+		}
+		switch configuration.Classification {
+		case "", models.ClassificationPublic, models.ClassificationInternal, models.ClassificationPersonal, models.ClassificationSensitivePersonal:
+		default:
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"classification",
+				"classification must be public, internal, personal or sensitive-personal"))
+		}
+		if configuration.Owner != nil && *configuration.Owner != models.OwnerCitizen {
+			fieldErrors = append(fieldErrors, models.NewFieldError(models.ValidationErrorInvalidValue, prefix+"owner",
+				"owner must be citizen"))
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return &models.ValidationError{Err: ErrInvalidFieldConfiguration, Fields: fieldErrors}
+	}
+	return nil
+}
+
+// schemaSubmissionListColumns describes how schema submission collections are searched and sorted
+var schemaSubmissionListColumns = listColumns{
+	sortable: map[string]string{
+		"createdAt":  "created_at",
+		"updatedAt":  "updated_at",
+		"schemaName": "schema_name",
+		"status":     "status",
+	},
+	search: "schema_name",
+	key:    "submission_id",
+}
+
+// GetSchemaSubmissions retrieves a page of schema submissions, filtered by member, status, name and creation time
+func (s *SchemaService) GetSchemaSubmissions(q models.ListQuery) ([]*models.SchemaSubmissionResponse, *models.PaginationMetadata, error) {
+	order, err := schemaSubmissionListColumns.normalize(&q)
+	if err != nil {
+		return nil, nil, err
+	}
+	query := schemaSubmissionListColumns.filter(database.Reader(s.db).Model(&models.SchemaSubmission{}), q).Session(&gorm.Session{})
 
-	if statusFilter != nil && len(*statusFilter) > 0 {
-		query = query.Where("status IN ?", *statusFilter)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count schema submissions: %w", err)
 	}
 
-	err := query.Find(&submissions).Error
+	var submissions []models.SchemaSubmission
+	err = page(query.Preload("PreviousSchema").Preload("Member").Order(order), q).Find(&submissions).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve schema submissions: %w", err)
+		return nil, nil, fmt.Errorf("failed to retrieve schema submissions: %w", err)
 	}
 
-	var responses []*models.SchemaSubmissionResponse
+	responses := make([]*models.SchemaSubmissionResponse, 0, len(submissions))
 	for _, submission := range submissions {
 		responses = append(responses, &models.SchemaSubmissionResponse{
-			SubmissionID:      submission.SubmissionID,
-			PreviousSchemaID:  submission.PreviousSchemaID,
-			SchemaName:        submission.SchemaName,
-			SchemaDescription: submission.SchemaDescription,
-			SDL:               submission.SDL,
-			SchemaEndpoint:    submission.SchemaEndpoint,
-			Status:            submission.Status,
-			MemberID:          submission.MemberID,
-			CreatedAt:         submission.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:         submission.UpdatedAt.Format(time.RFC3339),
-			Review:            submission.Review,
+			SubmissionID:        submission.SubmissionID,
+			PreviousSchemaID:    submission.PreviousSchemaID,
+			SchemaName:          submission.SchemaName,
+			SchemaDescription:   submission.SchemaDescription,
+			SDL:                 submission.SDL,
+			SchemaEndpoint:      submission.SchemaEndpoint,
+			Status:              submission.Status,
+			MemberID:            submission.MemberID,
+			OrganizationID:      submission.OrganizationID,
+			CreatedAt:           submission.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:           submission.UpdatedAt.Format(time.RFC3339),
+			Review:              submission.Review,
+			FieldConfigurations: submission.FieldConfigurations,
+			ProviderType:        submission.ProviderType,
 		})
 	}
 
-	return responses, nil
+	return responses, paginationMetadata(q, total), nil
+}
+
+// DeleteSchema soft-deletes a schema and flags the application fields it provides as invalid
+func (s *SchemaService) DeleteSchema(schemaID, deletedBy string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		affected, err := softDelete(tx, &models.Schema{}, deletionTime(), deletedBy, "schema_id = ?", schemaID)
+		if err != nil {
+			return fmt.Errorf("failed to delete schema: %w", err)
+		}
+		if affected == 0 {
+			return ErrResourceNotFound
+		}
+		return setSchemaFieldsInvalid(tx, []string{schemaID}, true)
+	})
+}
+
+// RestoreSchema restores a deleted schema and clears the invalid flag on the application fields it provides
+func (s *SchemaService) RestoreSchema(schemaID string) (*models.SchemaResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var schema models.Schema
+		if err := findDeleted(tx, &schema, &schema.DeletedAt, "schema_id = ?", schemaID); err != nil {
+			return err
+		}
+		if err := ensureMemberLive(tx, schema.MemberID); err != nil {
+			return err
+		}
+		if err := restoreDeleted(tx, &models.Schema{}, "schema_id = ?", schemaID); err != nil {
+			return fmt.Errorf("failed to restore schema: %w", err)
+		}
+		return setSchemaFieldsInvalid(tx, []string{schemaID}, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSchema(schemaID)
+}
+
+// DeleteSchemaSubmission soft-deletes a schema submission
+func (s *SchemaService) DeleteSchemaSubmission(submissionID, deletedBy string) error {
+	affected, err := softDelete(s.db, &models.SchemaSubmission{}, deletionTime(), deletedBy, "submission_id = ?", submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete schema submission: %w", err)
+	}
+	if affected == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+// RestoreSchemaSubmission restores a deleted schema submission
+func (s *SchemaService) RestoreSchemaSubmission(submissionID string) (*models.SchemaSubmissionResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var submission models.SchemaSubmission
+		if err := findDeleted(tx, &submission, &submission.DeletedAt, "submission_id = ?", submissionID); err != nil {
+			return err
+		}
+		if err := ensureMemberLive(tx, submission.MemberID); err != nil {
+			return err
+		}
+		if err := restoreDeleted(tx, &models.SchemaSubmission{}, "submission_id = ?", submissionID); err != nil {
+			return fmt.Errorf("failed to restore schema submission: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSchemaSubmission(submissionID)
 }
@@ -2,15 +2,19 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gov-dx-sandbox/portal-backend/v1/models"
 	"github.com/stretchr/testify/assert"
-
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -33,9 +37,13 @@ func TestSchemaService_UpdateSchema(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"schema_id", "schema_name", "schema_description", "sdl", "endpoint", "member_id", "version", "created_at", "updated_at"}).
 				AddRow(schemaID, "Original Name", originalDesc, "type Query { original: String }", "http://original.com", "member-123", string(models.ActiveVersion), time.Now(), time.Now()))
 
-		// Mock: Update schema
+		// Mock: Update schema and queue the PDP sync in one transaction
+		mock.ExpectBegin()
 		mock.ExpectExec(`UPDATE "schemas"`).
 			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO "pdp_jobs"`).
+			WillReturnRows(sqlmock.NewRows([]string{"attempts"}).AddRow(0))
+		mock.ExpectCommit()
 
 		req := &models.UpdateSchemaRequest{
 			SchemaName: &newName,
@@ -140,16 +148,20 @@ func TestSchemaService_GetSchemas(t *testing.T) {
 		pdpService := NewPDPService("http://localhost:9999", "test-key")
 		service := NewSchemaService(db, pdpService)
 
-		// Mock: Find all schemas
-		mock.ExpectQuery(`SELECT .* FROM "schemas" ORDER BY created_at DESC`).
+		// Mock: Count and find all schemas
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "schemas" WHERE "schemas"."deleted_at" IS NULL`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(`SELECT .* FROM "schemas" WHERE "schemas"."deleted_at" IS NULL ORDER BY created_at DESC`).
+			WithArgs(models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"schema_id", "schema_name", "sdl", "endpoint", "member_id", "version", "created_at", "updated_at"}).
 				AddRow("sch_1", "Schema 1", "type Query { test1: String }", "http://example.com", "member-1", string(models.ActiveVersion), time.Now(), time.Now()).
 				AddRow("sch_2", "Schema 2", "type Query { test2: String }", "http://example.com", "member-2", string(models.ActiveVersion), time.Now(), time.Now()))
 
-		result, err := service.GetSchemas(nil)
+		result, pagination, err := service.GetSchemas(models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
+		assert.Equal(t, int64(2), pagination.Total)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -163,13 +175,16 @@ func TestSchemaService_GetSchemas(t *testing.T) {
 
 		memberID := "member-123"
 
-		// Mock: Find schemas filtered by member_id
-		mock.ExpectQuery(`SELECT .* FROM "schemas" WHERE member_id = .* ORDER BY created_at DESC`).
+		// Mock: Count and find schemas filtered by member_id
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "schemas" WHERE member_id = .*`).
 			WithArgs(memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM "schemas" WHERE member_id = .* ORDER BY created_at DESC`).
+			WithArgs(memberID, models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"schema_id", "schema_name", "sdl", "endpoint", "member_id", "version", "created_at", "updated_at"}).
 				AddRow("sch_1", "Schema 1", "type Query { test1: String }", "http://example.com", memberID, string(models.ActiveVersion), time.Now(), time.Now()))
 
-		result, err := service.GetSchemas(&memberID)
+		result, _, err := service.GetSchemas(models.ListQuery{MemberID: &memberID})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
@@ -205,7 +220,7 @@ func TestSchemaService_CreateSchemaSubmission(t *testing.T) {
 		req := &models.CreateSchemaSubmissionRequest{
 			SchemaName:        "Test Submission",
 			SchemaDescription: &desc,
-			SDL:               "type Query { test: String }",
+			SDL:               `type Query { "Test field" test: String }`,
 			SchemaEndpoint:    "http://example.com",
 			MemberID:          memberID,
 		}
@@ -239,7 +254,7 @@ func TestSchemaService_CreateSchemaSubmission(t *testing.T) {
 		req := &models.CreateSchemaSubmissionRequest{
 			SchemaName:        "Test Submission",
 			SchemaDescription: &desc,
-			SDL:               "type Query { test: String }",
+			SDL:               `type Query { "Test field" test: String }`,
 			SchemaEndpoint:    "http://example.com",
 			MemberID:          "non-existent-member",
 		}
@@ -252,6 +267,36 @@ func TestSchemaService_CreateSchemaSubmission(t *testing.T) {
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("CreateSchemaSubmission_InvalidSDL", func(t *testing.T) {
+		db, mock, cleanup := SetupMockDB(t)
+		defer cleanup()
+
+		pdpService := NewPDPService("http://localhost:9999", "test-key")
+		service := NewSchemaService(db, pdpService)
+
+		// The SDL is rejected before any database access
+		req := &models.CreateSchemaSubmissionRequest{
+			SchemaName:     "Test Submission",
+			SDL:            "type person { Name: String }",
+			SchemaEndpoint: "http://example.com",
+			MemberID:       "member-123",
+		}
+
+		result, err := service.CreateSchemaSubmission(req)
+
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, ErrInvalidSDL))
+		var validationErr *SDLValidationError
+		require.True(t, errors.As(err, &validationErr))
+		rules := make([]models.SDLLintRule, 0, len(validationErr.Errors))
+		for _, lintErr := range validationErr.Errors {
+			rules = append(rules, lintErr.Rule)
+		}
+		assert.Equal(t, []models.SDLLintRule{models.SDLLintRuleTypeName, models.SDLLintRuleFieldName, models.SDLLintRuleFieldDescription}, rules)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestSchemaService_UpdateSchemaSubmission(t *testing.T) {
@@ -264,7 +309,7 @@ func TestSchemaService_UpdateSchemaSubmission(t *testing.T) {
 
 		submissionID := "sub_123"
 		newName := "Updated"
-		newSDL := "type Query { updated: String }"
+		newSDL := `type Query { "Updated field" updated: String }`
 
 		// Mock: Find submission
 		mock.ExpectQuery(`SELECT .* FROM "schema_submissions"`).
@@ -401,8 +446,11 @@ func TestSchemaService_GetSchemaSubmissions(t *testing.T) {
 		pdpService := NewPDPService("http://localhost:9999", "test-key")
 		service := NewSchemaService(db, pdpService)
 
-		// Mock: Find all submissions (with Preload)
+		// Mock: Count and find all submissions (with Preload)
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "schema_submissions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 		mock.ExpectQuery(`SELECT .* FROM "schema_submissions"`).
+			WithArgs(models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"submission_id", "schema_name", "sdl", "schema_endpoint", "member_id", "status", "created_at", "updated_at"}).
 				AddRow("sub_1", "Sub 1", "type Query { test1: String }", "http://example.com", "member-123", string(models.StatusPending), time.Now(), time.Now()).
 				AddRow("sub_2", "Sub 2", "type Query { test2: String }", "http://example.com", "member-123", string(models.StatusPending), time.Now(), time.Now()))
@@ -411,7 +459,7 @@ func TestSchemaService_GetSchemaSubmissions(t *testing.T) {
 		// Since PreviousSchemaID is nil in test data, schema preload is skipped
 		mock.ExpectQuery(`SELECT .* FROM "members"`).WillReturnRows(sqlmock.NewRows([]string{"member_id", "name", "email"}))
 
-		result, err := service.GetSchemaSubmissions(nil, nil)
+		result, _, err := service.GetSchemaSubmissions(models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
@@ -428,9 +476,12 @@ func TestSchemaService_GetSchemaSubmissions(t *testing.T) {
 
 		memberID := "member-123"
 
-		// Mock: Find submissions filtered by member_id
-		mock.ExpectQuery(`SELECT .* FROM "schema_submissions"`).
+		// Mock: Count and find submissions filtered by member_id
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "schema_submissions"`).
 			WithArgs(memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM "schema_submissions"`).
+			WithArgs(memberID, models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"submission_id", "schema_name", "sdl", "schema_endpoint", "member_id", "status", "created_at", "updated_at"}).
 				AddRow("sub_1", "Sub 1", "type Query { test1: String }", "http://example.com", memberID, string(models.StatusPending), time.Now(), time.Now()))
 
@@ -438,7 +489,7 @@ func TestSchemaService_GetSchemaSubmissions(t *testing.T) {
 		// Since PreviousSchemaID is nil in test data, schema preload is skipped
 		mock.ExpectQuery(`SELECT .* FROM "members"`).WillReturnRows(sqlmock.NewRows([]string{"member_id", "name", "email"}))
 
-		result, err := service.GetSchemaSubmissions(&memberID, nil)
+		result, _, err := service.GetSchemaSubmissions(models.ListQuery{MemberID: &memberID})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
@@ -456,9 +507,12 @@ func TestSchemaService_GetSchemaSubmissions(t *testing.T) {
 
 		statusFilter := []string{string(models.StatusApproved)}
 
-		// Mock: Find submissions filtered by status
-		mock.ExpectQuery(`SELECT .* FROM "schema_submissions"`).
+		// Mock: Count and find submissions filtered by status
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "schema_submissions"`).
 			WithArgs(string(models.StatusApproved)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT .* FROM "schema_submissions"`).
+			WithArgs(string(models.StatusApproved), models.DefaultPageLimit).
 			WillReturnRows(sqlmock.NewRows([]string{"submission_id", "schema_name", "sdl", "schema_endpoint", "member_id", "status", "created_at", "updated_at"}).
 				AddRow("sub_2", "Sub 2", "type Query { test2: String }", "http://example.com", "member-123", string(models.StatusApproved), time.Now(), time.Now()))
 
@@ -466,7 +520,7 @@ func TestSchemaService_GetSchemaSubmissions(t *testing.T) {
 		// Since PreviousSchemaID is nil in test data, schema preload is skipped
 		mock.ExpectQuery(`SELECT .* FROM "members"`).WillReturnRows(sqlmock.NewRows([]string{"member_id", "name", "email"}))
 
-		result, err := service.GetSchemaSubmissions(nil, &statusFilter)
+		result, _, err := service.GetSchemaSubmissions(models.ListQuery{Status: statusFilter})
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
@@ -498,6 +552,10 @@ func TestSchemaService_CreateSchema_EdgeCases(t *testing.T) {
 
 		service := NewSchemaService(db, pdpService)
 
+		// Mock: Look up the member's organization
+		mock.ExpectQuery(`SELECT .* FROM "members"`).
+			WillReturnRows(sqlmock.NewRows([]string{"member_id", "organization_id"}).AddRow("member-123", nil))
+
 		// Mock: Create schema (will succeed, then PDP fails)
 		mock.ExpectQuery(`INSERT INTO "schemas"`).
 			WillReturnRows(sqlmock.NewRows([]string{"schema_id"}).AddRow("sch_123"))
@@ -538,6 +596,10 @@ func TestSchemaService_CreateSchema_EdgeCases(t *testing.T) {
 
 		service := NewSchemaService(db, pdpService)
 
+		// Mock: Look up the member's organization
+		mock.ExpectQuery(`SELECT .* FROM "members"`).
+			WillReturnRows(sqlmock.NewRows([]string{"member_id", "organization_id"}).AddRow("member-123", nil))
+
 		// Mock: Create schema
 		mock.ExpectQuery(`INSERT INTO "schemas"`).
 			WillReturnRows(sqlmock.NewRows([]string{"schema_id"}).AddRow("sch_123"))
@@ -581,8 +643,10 @@ func TestSchemaService_UpdateSchema_EdgeCases(t *testing.T) {
 				AddRow(schemaID, "Original Name", originalDesc, "type Query { original: String }", "http://original.com", "member-123", string(models.ActiveVersion), time.Now(), time.Now()))
 
 		// Mock: Update schema
+		mock.ExpectBegin()
 		mock.ExpectExec(`UPDATE "schemas"`).
 			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
 
 		// Only update name, leave other fields unchanged
 		req := &models.UpdateSchemaRequest{
@@ -615,7 +679,8 @@ func TestSchemaService_UpdateSchema_EdgeCases(t *testing.T) {
 		newName := "Updated"
 		newSDL := "type Query { updated: String }"
 		newEndpoint := "http://updated.com"
-		newVersion := "v2.0"
+		newVersion := string(models.DeprecatedVersion)
+		sunsetAt := time.Now().Add(30 * 24 * time.Hour)
 
 		// Mock: Find schema
 		mock.ExpectQuery(`SELECT .* FROM "schemas"`).
@@ -623,15 +688,22 @@ func TestSchemaService_UpdateSchema_EdgeCases(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"schema_id", "schema_name", "sdl", "endpoint", "member_id", "version", "created_at", "updated_at"}).
 				AddRow(schemaID, "Original", "type Query { original: String }", "http://original.com", "member-123", string(models.ActiveVersion), time.Now(), time.Now()))
 
-		// Mock: Update schema
+		// Mock: Update schema and queue the policy and lifecycle syncs in one transaction
+		mock.ExpectBegin()
 		mock.ExpectExec(`UPDATE "schemas"`).
 			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO "pdp_jobs"`).
+			WillReturnRows(sqlmock.NewRows([]string{"attempts"}).AddRow(0))
+		mock.ExpectQuery(`INSERT INTO "pdp_jobs"`).
+			WillReturnRows(sqlmock.NewRows([]string{"attempts"}).AddRow(0))
+		mock.ExpectCommit()
 
 		req := &models.UpdateSchemaRequest{
 			SchemaName: &newName,
 			SDL:        &newSDL,
 			Endpoint:   &newEndpoint,
 			Version:    &newVersion,
+			SunsetAt:   &sunsetAt,
 		}
 
 		result, err := service.UpdateSchema(schemaID, req)
@@ -678,7 +750,7 @@ func TestSchemaService_CreateSchemaSubmission_EdgeCases(t *testing.T) {
 
 		req := &models.CreateSchemaSubmissionRequest{
 			SchemaName:       "New Submission",
-			SDL:              "type Query { new: String }",
+			SDL:              `type Query { "New field" new: String }`,
 			SchemaEndpoint:   "http://new.com",
 			MemberID:         memberID,
 			PreviousSchemaID: &previousSchemaID,
@@ -718,7 +790,7 @@ func TestSchemaService_CreateSchemaSubmission_EdgeCases(t *testing.T) {
 
 		req := &models.CreateSchemaSubmissionRequest{
 			SchemaName:       "New Submission",
-			SDL:              "type Query { new: String }",
+			SDL:              `type Query { "New field" new: String }`,
 			SchemaEndpoint:   "http://new.com",
 			MemberID:         memberID,
 			PreviousSchemaID: &invalidSchemaID,
@@ -733,3 +805,232 @@ func TestSchemaService_CreateSchemaSubmission_EdgeCases(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestSchemaService_SchemaSubmissionDiff(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+
+	previousSchemaID := "sch_1"
+	created, err := service.CreateSchemaSubmission(&models.CreateSchemaSubmissionRequest{
+		SchemaName:       "Person v2",
+		SDL:              `type Query { "Name" name: String! "Age" age: Int }`,
+		SchemaEndpoint:   "http://provider",
+		MemberID:         "mem_provider",
+		PreviousSchemaID: &previousSchemaID,
+	})
+	require.NoError(t, err)
+
+	diff, err := service.GetSchemaSubmissionDiff(created.SubmissionID)
+	require.NoError(t, err)
+	assert.Equal(t, "sch_1", diff.PreviousSchemaID)
+	assert.Equal(t, []models.FieldChange{{Path: "Query.age", NewType: "Int"}}, diff.AddedFields)
+	assert.Equal(t, []models.FieldChange{{Path: "Query.name", OldType: "String", NewType: "String!"}}, diff.ChangedFields)
+	assert.False(t, diff.Breaking)
+
+	// Changing the SDL recomputes the stored diff
+	sdl := `type Query { "Age" age: Int }`
+	_, err = service.UpdateSchemaSubmission(created.SubmissionID, &models.UpdateSchemaSubmissionRequest{SDL: &sdl})
+	require.NoError(t, err)
+	diff, err = service.GetSchemaSubmissionDiff(created.SubmissionID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{{Path: "Query.name", OldType: "String", Breaking: true}}, diff.RemovedFields)
+	assert.True(t, diff.Breaking)
+
+	_, err = service.GetSchemaSubmissionDiff("sub_schema")
+	assert.True(t, errors.Is(err, ErrNoPreviousSchema))
+	_, err = service.GetSchemaSubmissionDiff("sub_missing")
+	assert.True(t, errors.Is(err, ErrResourceNotFound))
+}
+
+func TestSchemaService_SchemaSubmissionFieldConfigurations(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+
+	// Configurations are suggested from the SDL when it is submitted
+	created, err := service.CreateSchemaSubmission(&models.CreateSchemaSubmissionRequest{
+		SchemaName:     "Person",
+		SDL:            `type Query { "Person" person: Person } type Person { "NIC" nic: String! "Vehicles" vehicleCount: Int }`,
+		SchemaEndpoint: "http://provider",
+		MemberID:       "mem_provider",
+	})
+	require.NoError(t, err)
+	require.Len(t, created.FieldConfigurations, 2)
+	nic := created.FieldConfigurations[0]
+	assert.Equal(t, "person.nic", nic.FieldName)
+	assert.Equal(t, models.ClassificationPersonal, nic.Classification)
+	assert.True(t, nic.ConsentRequired)
+	assert.False(t, nic.Adjusted)
+
+	// The provider adjusts a suggestion
+	_, err = service.UpdateSchemaSubmission(created.SubmissionID, &models.UpdateSchemaSubmissionRequest{
+		FieldConfigurations: models.FieldConfigurations{
+			{FieldName: "person.vehicleCount", Classification: models.ClassificationInternal},
+		},
+	})
+	require.NoError(t, err)
+
+	// Adjustments are kept when the SDL changes, and new fields get suggestions
+	sdl := `type Query { "Person" person: Person } type Person { "NIC" nic: String! "Vehicles" vehicleCount: Int! "Photo" photo: String }`
+	updated, err := service.UpdateSchemaSubmission(created.SubmissionID, &models.UpdateSchemaSubmissionRequest{SDL: &sdl})
+	require.NoError(t, err)
+	require.Len(t, updated.FieldConfigurations, 3)
+	vehicleCount := updated.FieldConfigurations[1]
+	assert.Equal(t, "person.vehicleCount", vehicleCount.FieldName)
+	assert.Equal(t, "Int!", vehicleCount.Type)
+	assert.Equal(t, models.ClassificationInternal, vehicleCount.Classification)
+	assert.Equal(t, models.SourceFallback, vehicleCount.Source)
+	assert.True(t, vehicleCount.Adjusted)
+	assert.True(t, vehicleCount.ConsentRequired)
+	assert.Equal(t, models.ClassificationSensitivePersonal, updated.FieldConfigurations[2].Classification)
+
+	fetched, err := service.GetSchemaSubmission(created.SubmissionID)
+	require.NoError(t, err)
+	assert.Equal(t, updated.FieldConfigurations, fetched.FieldConfigurations)
+
+	// Adjustments must name a field of the SDL and use known settings
+	_, err = service.UpdateSchemaSubmission(created.SubmissionID, &models.UpdateSchemaSubmissionRequest{
+		FieldConfigurations: models.FieldConfigurations{
+			{FieldName: "person.missing", AccessControlType: "secret"},
+		},
+	})
+	assert.True(t, errors.Is(err, ErrInvalidFieldConfiguration))
+	var validationErr *models.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Fields, 2)
+	assert.Equal(t, "fieldConfigurations[0].fieldName", validationErr.Fields[0].Field)
+}
+
+func TestSchemaService_Lifecycle(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+	version := func(v models.Version) *string { s := string(v); return &s }
+	lifecycleJobs := func(t *testing.T) []models.PDPJob {
+		var jobs []models.PDPJob
+		require.NoError(t, db.Where("job_type = ?", models.PDPJobTypeUpdateSchemaLifecycle).Order("created_at").Find(&jobs).Error)
+		return jobs
+	}
+
+	t.Run("Deprecating requires a future sunset date", func(t *testing.T) {
+		_, err := service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{Version: version(models.DeprecatedVersion)})
+		var validationErr *models.ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.ErrorIs(t, err, ErrInvalidSchemaLifecycle)
+		assert.Equal(t, "sunsetAt", validationErr.Fields[0].Field)
+
+		past := time.Now().Add(-time.Hour)
+		_, err = service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{Version: version(models.DeprecatedVersion), SunsetAt: &past})
+		assert.ErrorIs(t, err, ErrInvalidSchemaLifecycle)
+	})
+
+	t.Run("Schemas move through the lifecycle in order", func(t *testing.T) {
+		_, err := service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{Version: version(models.RetiredVersion)})
+		assert.ErrorIs(t, err, ErrInvalidSchemaLifecycle, "an active schema is deprecated first")
+		_, err = service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{Version: version("v2")})
+		assert.ErrorIs(t, err, ErrInvalidSchemaLifecycle)
+		assert.Empty(t, lifecycleJobs(t))
+	})
+
+	sunsetAt := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	message := "Use the Person v2 schema"
+	t.Run("Deprecating queues a lifecycle sync", func(t *testing.T) {
+		schema, err := service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{
+			Version:            version(models.DeprecatedVersion),
+			SunsetAt:           &sunsetAt,
+			DeprecationMessage: &message,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, string(models.DeprecatedVersion), schema.Version)
+		require.NotNil(t, schema.SunsetAt)
+		assert.Equal(t, sunsetAt.Format(time.RFC3339), *schema.SunsetAt)
+		assert.Equal(t, message, *schema.DeprecationMessage)
+
+		jobs := lifecycleJobs(t)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "sch_1", jobs[0].ResourceID)
+		assert.Contains(t, jobs[0].Payload, `"status":"deprecated"`)
+	})
+
+	t.Run("Retiring keeps the sunset date", func(t *testing.T) {
+		schema, err := service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{Version: version(models.RetiredVersion)})
+		require.NoError(t, err)
+		assert.Equal(t, string(models.RetiredVersion), schema.Version)
+		assert.NotNil(t, schema.SunsetAt)
+		assert.Len(t, lifecycleJobs(t), 2)
+
+		_, err = service.UpdateSchema("sch_1", &models.UpdateSchemaRequest{Version: version(models.ActiveVersion)})
+		assert.ErrorIs(t, err, ErrInvalidSchemaLifecycle, "a retired schema stays retired")
+	})
+}
+
+func TestSchemaService_GetSchemaConsumers(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(&models.Application{ApplicationID: "app_2", ApplicationName: "Other App", MemberID: "mem_consumer",
+		Version: string(models.ActiveVersion), SelectedFields: models.SelectedFieldRecords{
+			{FieldName: "person.name", SchemaID: "sch_1"},
+			{FieldName: "person.nic", SchemaID: "sch_1"},
+		}}).Error)
+
+	expiresAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+	grants := map[string][]models.ConsumerGrant{
+		"app_1": {
+			{SchemaID: "sch_1", FieldName: "person.name", ApplicationID: "app_1", ExpiresAt: expiresAt},
+			{SchemaID: "sch_2", FieldName: "vehicle.plate", ApplicationID: "app_1", ExpiresAt: expiresAt},
+		},
+		"app_2": {
+			{SchemaID: "sch_1", FieldName: "person.name", ApplicationID: "app_2", ExpiresAt: expiresAt, Expired: true},
+		},
+	}
+	pdpAvailable := true
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !pdpAvailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		records := grants[r.URL.Query().Get("applicationId")]
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(models.ConsumerGrantListResponse{Records: records, Count: len(records)}))
+	}))
+	defer pdpServer.Close()
+	service := NewSchemaService(db, NewPDPService(pdpServer.URL, "test-key"))
+
+	t.Run("Lists consuming applications with their grant status", func(t *testing.T) {
+		consumers, err := service.GetSchemaConsumers(ctx, "sch_1")
+		require.NoError(t, err)
+		require.Len(t, consumers, 2)
+
+		assert.Equal(t, "app_1", consumers[0].ApplicationID)
+		assert.Equal(t, "mem_consumer", consumers[0].MemberID)
+		assert.Equal(t, "Consumer", consumers[0].MemberName)
+		assert.Equal(t, "consumer@example.com", consumers[0].MemberEmail)
+		require.Len(t, consumers[0].Fields, 1, "fields of other schemas are not listed")
+		assert.Equal(t, models.GrantStatusActive, consumers[0].Fields[0].GrantStatus)
+		assert.Equal(t, &expiresAt, consumers[0].Fields[0].GrantExpiresAt)
+
+		assert.Equal(t, "app_2", consumers[1].ApplicationID)
+		require.Len(t, consumers[1].Fields, 2)
+		assert.Equal(t, models.GrantStatusExpired, consumers[1].Fields[0].GrantStatus)
+		assert.Equal(t, "person.nic", consumers[1].Fields[1].FieldName)
+		assert.Equal(t, models.GrantStatusMissing, consumers[1].Fields[1].GrantStatus)
+		assert.Nil(t, consumers[1].Fields[1].GrantExpiresAt)
+	})
+
+	t.Run("Schemas without consumers list none", func(t *testing.T) {
+		consumers, err := service.GetSchemaConsumers(ctx, "sch_unused")
+		require.NoError(t, err)
+		assert.Empty(t, consumers)
+	})
+
+	t.Run("PDP failures are reported", func(t *testing.T) {
+		pdpAvailable = false
+		defer func() { pdpAvailable = true }()
+
+		_, err := service.GetSchemaConsumers(ctx, "sch_1")
+		assert.ErrorIs(t, err, ErrPolicyServiceUnavailable)
+	})
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrResourceNotFound is returned when the resource to delete or restore does not exist
+	ErrResourceNotFound = errors.New("resource not found")
+	// ErrResourceNotDeleted is returned when restoring a resource that is not deleted
+	ErrResourceNotDeleted = errors.New("resource is not deleted")
+	// ErrOwnerDeleted is returned when restoring a resource whose member is still deleted
+	ErrOwnerDeleted = errors.New("owning member is deleted; restore the member first")
+)
+
+// deletionTime returns the timestamp recorded for a deletion. It is truncated to the database's
+// microsecond precision so that rows deleted by the same cascade can be matched on restore.
+func deletionTime() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// softDelete marks the live rows of model matching the condition as deleted by deletedBy
+func softDelete(tx *gorm.DB, model interface{}, deletedAt time.Time, deletedBy string, query string, args ...interface{}) (int64, error) {
+	result := tx.Model(model).Where(query, args...).Updates(map[string]interface{}{
+		"deleted_at": deletedAt,
+		"deleted_by": deletedBy,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// restoreDeleted clears the deletion of the rows of model matching the condition
+func restoreDeleted(tx *gorm.DB, model interface{}, query string, args ...interface{}) error {
+	return tx.Unscoped().Model(model).Where(query, args...).Where("deleted_at IS NOT NULL").Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+	}).Error
+}
+
+// findDeleted loads a soft-deleted row into dest, mapping a missing or live row to the service errors.
+// deletedAt must point at dest's DeletedAt field.
+func findDeleted(tx *gorm.DB, dest interface{}, deletedAt *gorm.DeletedAt, query string, args ...interface{}) error {
+	if err := tx.Unscoped().Where(query, args...).First(dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrResourceNotFound
+		}
+		return err
+	}
+	if !deletedAt.Valid {
+		return ErrResourceNotDeleted
+	}
+	return nil
+}
+
+// ensureMemberLive returns ErrOwnerDeleted if the member has been soft-deleted
+func ensureMemberLive(tx *gorm.DB, memberID string) error {
+	var member models.Member
+	if err := tx.Unscoped().First(&member, "member_id = ?", memberID).Error; err != nil {
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+	if member.DeletedAt.Valid {
+		return ErrOwnerDeleted
+	}
+	return nil
+}
+
+// setSchemaFieldsInvalid flags (or clears) the selected fields provided by the given schemas on every
+// live application and pending application submission, so consumers see which fields no longer resolve
+func setSchemaFieldsInvalid(tx *gorm.DB, schemaIDs []string, invalid bool) error {
+	if len(schemaIDs) == 0 {
+		return nil
+	}
+	affected := make(map[string]bool, len(schemaIDs))
+	for _, schemaID := range schemaIDs {
+		affected[schemaID] = true
+	}
+	markFields := func(fields models.SelectedFieldRecords) bool {
+		changed := false
+		for i := range fields {
+			if affected[fields[i].SchemaID] && fields[i].Invalid != invalid {
+				fields[i].Invalid = invalid
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	var applications []models.Application
+	if err := tx.Find(&applications).Error; err != nil {
+		return fmt.Errorf("failed to load applications: %w", err)
+	}
+	for _, application := range applications {
+		if !markFields(application.SelectedFields) {
+			continue
+		}
+		if err := tx.Model(&models.Application{}).Where("application_id = ?", application.ApplicationID).
+			Update("selected_fields", application.SelectedFields).Error; err != nil {
+			return fmt.Errorf("failed to update application fields: %w", err)
+		}
+	}
+
+	var submissions []models.ApplicationSubmission
+	if err := tx.Where("status = ?", string(models.StatusPending)).Find(&submissions).Error; err != nil {
+		return fmt.Errorf("failed to load application submissions: %w", err)
+	}
+	for _, submission := range submissions {
+		if !markFields(submission.SelectedFields) {
+			continue
+		}
+		if err := tx.Model(&models.ApplicationSubmission{}).Where("submission_id = ?", submission.SubmissionID).
+			Update("selected_fields", submission.SelectedFields).Error; err != nil {
+			return fmt.Errorf("failed to update application submission fields: %w", err)
+		}
+	}
+	return nil
+}
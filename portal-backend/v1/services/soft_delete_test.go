@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedSoftDeleteData creates two members: mem_provider owns a schema and a schema submission,
+// and mem_consumer owns an application and a pending submission that select fields from that schema
+func seedSoftDeleteData(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	fields := models.SelectedFieldRecords{
+		{FieldName: "person.name", SchemaID: "sch_1"},
+		{FieldName: "vehicle.plate", SchemaID: "sch_2"},
+	}
+	records := []interface{}{
+		&models.Member{MemberID: "mem_provider", Name: "Provider", Email: "provider@example.com", PhoneNumber: "1", IdpUserID: "idp-provider"},
+		&models.Member{MemberID: "mem_consumer", Name: "Consumer", Email: "consumer@example.com", PhoneNumber: "2", IdpUserID: "idp-consumer"},
+		&models.Schema{SchemaID: "sch_1", MemberID: "mem_provider", SchemaName: "Person", SDL: "type Query { name: String }", Endpoint: "http://provider", Version: string(models.ActiveVersion)},
+		&models.SchemaSubmission{SubmissionID: "sub_schema", SchemaName: "Person v2", SDL: "type Query { name: String }", SchemaEndpoint: "http://provider", Status: string(models.StatusPending), MemberID: "mem_provider"},
+		&models.Application{ApplicationID: "app_1", ApplicationName: "App", SelectedFields: fields, MemberID: "mem_consumer", Version: string(models.ActiveVersion)},
+		&models.ApplicationSubmission{SubmissionID: "sub_app", ApplicationName: "App v2", SelectedFields: fields, MemberID: "mem_consumer", Status: string(models.StatusPending)},
+	}
+	for _, record := range records {
+		require.NoError(t, db.Create(record).Error)
+	}
+}
+
+// fieldInvalid reports the invalid flag of each selected field of the application and submission, keyed by schema ID
+func fieldInvalid(t *testing.T, db *gorm.DB) (map[string]bool, map[string]bool) {
+	t.Helper()
+	var application models.Application
+	require.NoError(t, db.Unscoped().First(&application, "application_id = ?", "app_1").Error)
+	var submission models.ApplicationSubmission
+	require.NoError(t, db.Unscoped().First(&submission, "submission_id = ?", "sub_app").Error)
+
+	flags := func(fields models.SelectedFieldRecords) map[string]bool {
+		result := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			result[field.SchemaID] = field.Invalid
+		}
+		return result
+	}
+	return flags(application.SelectedFields), flags(submission.SelectedFields)
+}
+
+func TestSchemaService_DeleteAndRestoreSchema(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+
+	require.NoError(t, service.DeleteSchema("sch_1", "idp-admin"))
+
+	_, err := service.GetSchema("sch_1")
+	assert.Error(t, err)
+	var deleted models.Schema
+	require.NoError(t, db.Unscoped().First(&deleted, "schema_id = ?", "sch_1").Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+	require.NotNil(t, deleted.DeletedBy)
+	assert.Equal(t, "idp-admin", *deleted.DeletedBy)
+
+	appFields, submissionFields := fieldInvalid(t, db)
+	assert.Equal(t, map[string]bool{"sch_1": true, "sch_2": false}, appFields)
+	assert.Equal(t, map[string]bool{"sch_1": true, "sch_2": false}, submissionFields)
+
+	// A deleted schema cannot be deleted again
+	assert.True(t, errors.Is(service.DeleteSchema("sch_1", "idp-admin"), ErrResourceNotFound))
+
+	restored, err := service.RestoreSchema("sch_1")
+	require.NoError(t, err)
+	assert.Equal(t, "sch_1", restored.SchemaID)
+
+	appFields, submissionFields = fieldInvalid(t, db)
+	assert.Equal(t, map[string]bool{"sch_1": false, "sch_2": false}, appFields)
+	assert.Equal(t, map[string]bool{"sch_1": false, "sch_2": false}, submissionFields)
+
+	_, err = service.RestoreSchema("sch_1")
+	assert.True(t, errors.Is(err, ErrResourceNotDeleted))
+	_, err = service.RestoreSchema("sch_missing")
+	assert.True(t, errors.Is(err, ErrResourceNotFound))
+}
+
+func TestMemberService_DeleteAndRestoreMember(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+	memberService := NewMemberService(db, nil)
+	schemaService := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+
+	// Deleted on its own before the member, so restoring the member leaves it deleted
+	require.NoError(t, schemaService.DeleteSchemaSubmission("sub_schema", "idp-admin"))
+
+	require.NoError(t, memberService.DeleteMember(ctx, "mem_provider", "idp-admin"))
+
+	_, err := memberService.GetMember(ctx, "mem_provider")
+	assert.Error(t, err)
+	_, err = schemaService.GetSchema("sch_1")
+	assert.Error(t, err, "schemas are deleted with their member")
+	appFields, _ := fieldInvalid(t, db)
+	assert.True(t, appFields["sch_1"], "fields of the member's schemas are invalidated")
+
+	// Resources of a deleted member cannot be restored on their own
+	_, err = schemaService.RestoreSchema("sch_1")
+	assert.True(t, errors.Is(err, ErrOwnerDeleted))
+
+	restored, err := memberService.RestoreMember(ctx, "mem_provider")
+	require.NoError(t, err)
+	assert.Equal(t, "mem_provider", restored.MemberID)
+
+	_, err = schemaService.GetSchema("sch_1")
+	assert.NoError(t, err)
+	appFields, _ = fieldInvalid(t, db)
+	assert.False(t, appFields["sch_1"])
+
+	_, err = schemaService.GetSchemaSubmission("sub_schema")
+	assert.Error(t, err, "individually deleted resources stay deleted")
+}
+
+func TestApplicationService_DeleteAndRestoreApplication(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+	service := NewApplicationService(db, NewPDPService("http://localhost:9999", "test-key"), nil)
+
+	idpClientID := "client-1"
+	require.NoError(t, db.Model(&models.Application{}).Where("application_id = ?", "app_1").Update("idp_client_id", idpClientID).Error)
+
+	require.NoError(t, service.DeleteApplication(ctx, "app_1", "idp-admin"))
+	_, err := service.GetApplicationIdByIdpClientId(ctx, idpClientID)
+	assert.Error(t, err, "a deleted application no longer resolves from its client ID")
+
+	require.NoError(t, service.DeleteApplicationSubmission(ctx, "sub_app", "idp-admin"))
+	assert.True(t, errors.Is(service.DeleteApplicationSubmission(ctx, "sub_app", "idp-admin"), ErrResourceNotFound))
+
+	application, err := service.RestoreApplication(ctx, "app_1")
+	require.NoError(t, err)
+	assert.Equal(t, "app_1", application.ApplicationID)
+	submission, err := service.RestoreApplicationSubmission(ctx, "sub_app")
+	require.NoError(t, err)
+	assert.Equal(t, "sub_app", submission.SubmissionID)
+}
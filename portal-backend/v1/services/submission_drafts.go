@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+)
+
+var (
+	// ErrInvalidSubmissionStatus is returned when a submission is created with a status other than draft or pending
+	ErrInvalidSubmissionStatus = errors.New("a submission can only be created as draft or pending")
+	// ErrIncompleteSubmission is returned when a submission is submitted without the fields review needs
+	ErrIncompleteSubmission = errors.New("submission is incomplete")
+	// ErrSubmissionNotDraft is returned when submitting a submission that was already submitted
+	ErrSubmissionNotDraft = errors.New("submission is not a draft")
+	// ErrDraftSubmitRequired is returned when a draft update tries to change its status; drafts are
+	// submitted through the submit endpoint, which validates them
+	ErrDraftSubmitRequired = errors.New("drafts can only be submitted through the submit endpoint")
+)
+
+// initialSubmissionStatus returns the status a submission is created with: pending unless status asks for a draft
+func initialSubmissionStatus(status *string) (string, error) {
+	if status == nil || *status == "" || *status == string(models.StatusPending) {
+		return string(models.StatusPending), nil
+	}
+	if *status == string(models.StatusDraft) {
+		return string(models.StatusDraft), nil
+	}
+	return "", models.NewValidationError(ErrInvalidSubmissionStatus, models.ValidationErrorInvalidValue, "status", "status must be draft or pending")
+}
+
+// requiredField is a field a submission needs before it can be submitted for review
+type requiredField struct {
+	name  string
+	value string
+}
+
+// requireFields returns a *models.ValidationError wrapping ErrIncompleteSubmission that lists the
+// fields whose values are blank
+func requireFields(fields ...requiredField) error {
+	return incompleteSubmission(missingFields(fields...))
+}
+
+// missingFields returns a required error for each field whose value is blank
+func missingFields(fields ...requiredField) []models.FieldError {
+	var missing []models.FieldError
+	for _, field := range fields {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, models.NewFieldError(models.ValidationErrorRequired, field.name, field.name+" is required"))
+		}
+	}
+	return missing
+}
+
+// incompleteSubmission wraps the problems that keep a submission from review in ErrIncompleteSubmission
+func incompleteSubmission(problems []models.FieldError) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &models.ValidationError{Err: ErrIncompleteSubmission, Fields: problems}
+}
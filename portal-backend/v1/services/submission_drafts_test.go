@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaSubmissionDrafts(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewSchemaService(db, NewPDPService("http://localhost:9999", "test-key"))
+
+	draft := string(models.StatusDraft)
+	var submissionID string
+	t.Run("Drafts are saved without validation", func(t *testing.T) {
+		submission, err := service.CreateSchemaSubmission(&models.CreateSchemaSubmissionRequest{
+			SchemaName: "Person v3",
+			SDL:        "type person {",
+			MemberID:   "mem_provider",
+			Status:     &draft,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, draft, submission.Status)
+		submissionID = submission.SubmissionID
+
+		invalid := "approved"
+		_, err = service.CreateSchemaSubmission(&models.CreateSchemaSubmissionRequest{MemberID: "mem_provider", Status: &invalid})
+		assert.ErrorIs(t, err, ErrInvalidSubmissionStatus)
+	})
+
+	t.Run("Submit validates the draft", func(t *testing.T) {
+		_, err := service.SubmitSchemaSubmission(submissionID)
+		assert.ErrorIs(t, err, ErrIncompleteSubmission)
+		assert.Contains(t, err.Error(), "schemaEndpoint")
+
+		endpoint := "http://provider"
+		_, err = service.UpdateSchemaSubmission(submissionID, &models.UpdateSchemaSubmissionRequest{SchemaEndpoint: &endpoint})
+		require.NoError(t, err)
+		_, err = service.SubmitSchemaSubmission(submissionID)
+		assert.ErrorIs(t, err, ErrInvalidSDL)
+
+		sdl := `type Query { "Name" name: String }`
+		_, err = service.UpdateSchemaSubmission(submissionID, &models.UpdateSchemaSubmissionRequest{SDL: &sdl})
+		require.NoError(t, err)
+		pending := string(models.StatusPending)
+		_, err = service.UpdateSchemaSubmission(submissionID, &models.UpdateSchemaSubmissionRequest{Status: &pending})
+		assert.ErrorIs(t, err, ErrDraftSubmitRequired)
+
+		submission, err := service.SubmitSchemaSubmission(submissionID)
+		require.NoError(t, err)
+		assert.Equal(t, pending, submission.Status)
+
+		_, err = service.SubmitSchemaSubmission(submissionID)
+		assert.ErrorIs(t, err, ErrSubmissionNotDraft)
+		_, err = service.SubmitSchemaSubmission("sub_missing")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	t.Run("Submissions are still validated on create", func(t *testing.T) {
+		_, err := service.CreateSchemaSubmission(&models.CreateSchemaSubmissionRequest{
+			SchemaName: "Person v4",
+			SDL:        `type Query { "Name" name: String }`,
+			MemberID:   "mem_provider",
+		})
+		assert.ErrorIs(t, err, ErrIncompleteSubmission)
+	})
+}
+
+func TestApplicationSubmissionDrafts(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	service := NewApplicationService(db, NewPDPService("http://localhost:9999", "test-key"), &MockIDP{})
+	ctx := context.Background()
+
+	draft := string(models.StatusDraft)
+	submission, err := service.CreateApplicationSubmission(ctx, &models.CreateApplicationSubmissionRequest{
+		ApplicationName: "Benefits App",
+		MemberID:        "mem_consumer",
+		Status:          &draft,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, draft, submission.Status)
+
+	_, err = service.SubmitApplicationSubmission(ctx, submission.SubmissionID)
+	assert.ErrorIs(t, err, ErrIncompleteSubmission)
+
+	fields := []models.SelectedFieldRecord{{FieldName: "person.name", SchemaID: "sch_1"}}
+	_, err = service.UpdateApplicationSubmission(ctx, submission.SubmissionID, &models.UpdateApplicationSubmissionRequest{SelectedFields: &fields})
+	require.NoError(t, err)
+	submitted, err := service.SubmitApplicationSubmission(ctx, submission.SubmissionID)
+	require.NoError(t, err)
+	assert.Equal(t, string(models.StatusPending), submitted.Status)
+
+	_, err = service.CreateApplicationSubmission(ctx, &models.CreateApplicationSubmissionRequest{ApplicationName: "Benefits App", MemberID: "mem_consumer"})
+	assert.ErrorIs(t, err, ErrIncompleteSubmission)
+}
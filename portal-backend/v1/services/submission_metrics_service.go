@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidMetricsWindow is returned for a metrics window that is malformed or too long
+var ErrInvalidMetricsWindow = errors.New("invalid metrics window")
+
+// submissionAgeBuckets are the ages the submission backlog is bucketed by, in hours; the last bucket
+// is open-ended
+var submissionAgeBuckets = []struct {
+	label    string
+	minHours int
+	maxHours int
+}{
+	{"<1d", 0, 24},
+	{"1d-3d", 24, 72},
+	{"3d-7d", 72, 168},
+	{"7d-30d", 168, 720},
+	{">30d", 720, 0},
+}
+
+var (
+	// decidedStatuses are the statuses of submissions that left the review workflow
+	decidedStatuses = []string{string(models.StatusApproved), string(models.StatusRejected)}
+	// notInBacklog are the statuses of submissions that are not waiting for a review decision
+	notInBacklog = append([]string{string(models.StatusDraft)}, decidedStatuses...)
+)
+
+// SubmissionMetricsService reports the throughput of the submission review workflow, so operations
+// can track onboarding SLAs without querying the database
+type SubmissionMetricsService struct {
+	db *gorm.DB
+}
+
+// NewSubmissionMetricsService creates a new submission metrics service
+func NewSubmissionMetricsService(db *gorm.DB) *SubmissionMetricsService {
+	return &SubmissionMetricsService{db: db}
+}
+
+// GetSubmissionMetrics counts the current submissions of each type by status, and the backlog of
+// submissions waiting for a decision by age
+func (s *SubmissionMetricsService) GetSubmissionMetrics(ctx context.Context) (*models.SubmissionMetricsResponse, error) {
+	now := time.Now().UTC()
+	response := &models.SubmissionMetricsResponse{GeneratedAt: now}
+	for _, queue := range []struct {
+		submissionType models.SubmissionType
+		metrics        *models.SubmissionQueueMetrics
+	}{
+		{models.SubmissionTypeSchema, &response.SchemaSubmissions},
+		{models.SubmissionTypeApplication, &response.ApplicationSubmissions},
+	} {
+		metrics, err := s.queueMetrics(ctx, queue.submissionType, now)
+		if err != nil {
+			return nil, err
+		}
+		*queue.metrics = *metrics
+		response.Backlog += metrics.Backlog
+	}
+	return response, nil
+}
+
+// queueMetrics counts the submissions of submissionType
+func (s *SubmissionMetricsService) queueMetrics(ctx context.Context, submissionType models.SubmissionType, now time.Time) (*models.SubmissionQueueMetrics, error) {
+	model, err := submissionModel(submissionType)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		Status string
+		Count  int64
+	}
+	if err := s.db.WithContext(ctx).Model(model).Select("status, COUNT(*) AS count").Group("status").Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count %s submissions: %w", submissionType, err)
+	}
+	metrics := &models.SubmissionQueueMetrics{ByStatus: make(map[string]int64, len(counts))}
+	for _, count := range counts {
+		metrics.ByStatus[count.Status] = count.Count
+		if !slices.Contains(notInBacklog, count.Status) {
+			metrics.Backlog += count.Count
+		}
+	}
+
+	backlog := func() *gorm.DB {
+		return s.db.WithContext(ctx).Model(model).Where("status NOT IN ?", notInBacklog)
+	}
+	metrics.BacklogByAge = make([]models.SubmissionAgeBucket, 0, len(submissionAgeBuckets))
+	for _, bucket := range submissionAgeBuckets {
+		ageBucket := models.SubmissionAgeBucket{Label: bucket.label, MinAgeHours: bucket.minHours}
+		query := backlog().Where("created_at <= ?", now.Add(-time.Duration(bucket.minHours)*time.Hour))
+		if bucket.maxHours > 0 {
+			maxHours := bucket.maxHours
+			ageBucket.MaxAgeHours = &maxHours
+			query = query.Where("created_at > ?", now.Add(-time.Duration(bucket.maxHours)*time.Hour))
+		}
+		if err := query.Count(&ageBucket.Count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s submission backlog: %w", submissionType, err)
+		}
+		metrics.BacklogByAge = append(metrics.BacklogByAge, ageBucket)
+	}
+
+	if metrics.Backlog > 0 {
+		var oldest struct{ CreatedAt time.Time }
+		if err := backlog().Select("created_at").Order("created_at ASC").Limit(1).Scan(&oldest).Error; err != nil {
+			return nil, fmt.Errorf("failed to get oldest %s submission: %w", submissionType, err)
+		}
+		oldestAt := oldest.CreatedAt.UTC()
+		metrics.OldestBacklogAt = &oldestAt
+	}
+	return metrics, nil
+}
+
+// reviewTally accumulates review outcomes
+type reviewTally struct {
+	approved, rejected int64
+	reviewTime         time.Duration
+}
+
+func (t *reviewTally) add(status string, reviewTime time.Duration) {
+	if status == string(models.StatusApproved) {
+		t.approved++
+	} else {
+		t.rejected++
+	}
+	t.reviewTime += reviewTime
+}
+
+func (t *reviewTally) metrics() models.ReviewOutcomeMetrics {
+	metrics := models.ReviewOutcomeMetrics{Decided: t.approved + t.rejected, Approved: t.approved, Rejected: t.rejected}
+	if metrics.Decided > 0 {
+		metrics.ApprovalRate = float64(t.approved) / float64(metrics.Decided)
+		metrics.AverageReviewHours = t.reviewTime.Hours() / float64(metrics.Decided)
+	}
+	return metrics
+}
+
+// decidedSubmission is a submission approved or rejected in a metrics window
+type decidedSubmission struct {
+	SubmissionID string
+	MemberID     string
+	Status       string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// GetReviewMetrics reports the submissions approved or rejected between startTime and endTime
+// (RFC3339; by default the last 30 days), overall, per submission type and per submitting member.
+// A submission is decided when its last review decision was recorded; submissions decided without
+// the review workflow fall back to their last update.
+func (s *SubmissionMetricsService) GetReviewMetrics(ctx context.Context, startTime, endTime string) (*models.ReviewMetricsResponse, error) {
+	start, end, err := parseReportWindow(startTime, endTime, ErrInvalidMetricsWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	var total reviewTally
+	members := make(map[string]*reviewTally)
+	response := &models.ReviewMetricsResponse{StartTime: start, EndTime: end}
+	for _, outcome := range []struct {
+		submissionType models.SubmissionType
+		metrics        *models.ReviewOutcomeMetrics
+	}{
+		{models.SubmissionTypeSchema, &response.SchemaSubmissions},
+		{models.SubmissionTypeApplication, &response.ApplicationSubmissions},
+	} {
+		submissions, decidedAt, err := s.decidedSubmissions(ctx, outcome.submissionType, start)
+		if err != nil {
+			return nil, err
+		}
+		var tally reviewTally
+		for _, submission := range submissions {
+			at := decidedAt[submission.SubmissionID]
+			if at.Before(start) || !at.Before(end) {
+				continue
+			}
+			reviewTime := at.Sub(submission.CreatedAt)
+			tally.add(submission.Status, reviewTime)
+			total.add(submission.Status, reviewTime)
+			if members[submission.MemberID] == nil {
+				members[submission.MemberID] = &reviewTally{}
+			}
+			members[submission.MemberID].add(submission.Status, reviewTime)
+		}
+		*outcome.metrics = tally.metrics()
+	}
+	response.ReviewOutcomeMetrics = total.metrics()
+
+	response.Members, err = s.memberMetrics(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// decidedSubmissions returns the submissions of submissionType that may have been decided since
+// start, with the time each was decided
+func (s *SubmissionMetricsService) decidedSubmissions(ctx context.Context, submissionType models.SubmissionType, start time.Time) ([]decidedSubmission, map[string]time.Time, error) {
+	model, err := submissionModel(submissionType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A decision is recorded with the status change, so submissions decided since start were updated since
+	var submissions []decidedSubmission
+	if err := s.db.WithContext(ctx).Model(model).
+		Select("submission_id, member_id, status, created_at, updated_at").
+		Where("status IN ? AND updated_at >= ?", decidedStatuses, start).
+		Scan(&submissions).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get decided %s submissions: %w", submissionType, err)
+	}
+	decidedAt := make(map[string]time.Time, len(submissions))
+	if len(submissions) == 0 {
+		return submissions, decidedAt, nil
+	}
+
+	submissionIDs := make([]string, len(submissions))
+	for i, submission := range submissions {
+		submissionIDs[i] = submission.SubmissionID
+		decidedAt[submission.SubmissionID] = submission.UpdatedAt.UTC()
+	}
+	var reviews []models.SubmissionReview
+	if err := s.db.WithContext(ctx).Select("submission_id, created_at").
+		Where("submission_type = ? AND submission_id IN ?", submissionType, submissionIDs).
+		Find(&reviews).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get %s submission reviews: %w", submissionType, err)
+	}
+	lastReview := make(map[string]time.Time, len(reviews))
+	for _, review := range reviews {
+		if review.CreatedAt.After(lastReview[review.SubmissionID]) {
+			lastReview[review.SubmissionID] = review.CreatedAt.UTC()
+		}
+	}
+	for submissionID, at := range lastReview {
+		decidedAt[submissionID] = at
+	}
+	return submissions, decidedAt, nil
+}
+
+// memberMetrics returns the review outcomes of each member's submissions, most decisions first
+func (s *SubmissionMetricsService) memberMetrics(ctx context.Context, tallies map[string]*reviewTally) ([]models.MemberReviewMetrics, error) {
+	metrics := make([]models.MemberReviewMetrics, 0, len(tallies))
+	if len(tallies) == 0 {
+		return metrics, nil
+	}
+
+	memberIDs := make([]string, 0, len(tallies))
+	for memberID := range tallies {
+		memberIDs = append(memberIDs, memberID)
+	}
+	var members []models.Member
+	if err := s.db.WithContext(ctx).Unscoped().Select("member_id, name").Where("member_id IN ?", memberIDs).Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to get members: %w", err)
+	}
+	names := make(map[string]string, len(members))
+	for _, member := range members {
+		names[member.MemberID] = member.Name
+	}
+
+	for _, memberID := range memberIDs {
+		metrics = append(metrics, models.MemberReviewMetrics{
+			MemberID:             memberID,
+			MemberName:           names[memberID],
+			ReviewOutcomeMetrics: tallies[memberID].metrics(),
+		})
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].Decided != metrics[j].Decided {
+			return metrics[i].Decided > metrics[j].Decided
+		}
+		return metrics[i].MemberID < metrics[j].MemberID
+	})
+	return metrics, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmissionMetricsService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+	service := NewSubmissionMetricsService(db)
+
+	now := time.Now().UTC()
+	daysAgo := func(days int) time.Time { return now.Add(-time.Duration(days) * 24 * time.Hour) }
+	// The create hooks stamp the current time, so records are backdated once created
+	create := func(record interface{}, created, updated time.Time) {
+		require.NoError(t, db.Create(record).Error)
+		require.NoError(t, db.Model(record).UpdateColumns(map[string]interface{}{"created_at": created, "updated_at": updated}).Error)
+	}
+	schemaSubmission := func(id, status string, created, updated time.Time) {
+		create(&models.SchemaSubmission{SubmissionID: id, SchemaName: id, SDL: "type Query { name: String }", SchemaEndpoint: "http://provider",
+			Status: status, MemberID: "mem_provider"}, created, updated)
+	}
+	review := func(id string, submissionType models.SubmissionType, submissionID string, created time.Time) {
+		create(&models.SubmissionReview{ReviewID: id, SubmissionType: submissionType, SubmissionID: submissionID, Step: "technical_review",
+			ReviewerID: "admin", Decision: models.ReviewDecisionApprove}, created, created)
+	}
+
+	schemaSubmission("sub_in_review", "technical_review", daysAgo(10), daysAgo(9))
+	schemaSubmission("sub_draft", string(models.StatusDraft), daysAgo(40), daysAgo(40))
+	// Approved through the review workflow 3 days after it was submitted
+	schemaSubmission("sub_approved", string(models.StatusApproved), daysAgo(5), now)
+	review("rev_1", models.SubmissionTypeSchema, "sub_approved", daysAgo(3))
+	review("rev_2", models.SubmissionTypeSchema, "sub_approved", daysAgo(2))
+	// Rejected without the review workflow, so decided when last updated
+	create(&models.ApplicationSubmission{SubmissionID: "sub_rejected", ApplicationName: "Rejected", MemberID: "mem_consumer",
+		Status: string(models.StatusRejected)}, daysAgo(2), daysAgo(1))
+	// Decided before the default window, though updated since
+	create(&models.ApplicationSubmission{SubmissionID: "sub_decided_long_ago", ApplicationName: "Old", MemberID: "mem_consumer",
+		Status: string(models.StatusApproved)}, daysAgo(70), now)
+	review("rev_3", models.SubmissionTypeApplication, "sub_decided_long_ago", daysAgo(60))
+
+	t.Run("Submissions are counted by status and the backlog by age", func(t *testing.T) {
+		metrics, err := service.GetSubmissionMetrics(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]int64{"pending": 1, "technical_review": 1, "draft": 1, "approved": 1}, metrics.SchemaSubmissions.ByStatus)
+		assert.Equal(t, int64(2), metrics.SchemaSubmissions.Backlog)
+		counts := make(map[string]int64)
+		for _, bucket := range metrics.SchemaSubmissions.BacklogByAge {
+			counts[bucket.Label] = bucket.Count
+		}
+		assert.Equal(t, map[string]int64{"<1d": 1, "1d-3d": 0, "3d-7d": 0, "7d-30d": 1, ">30d": 0}, counts)
+		require.NotNil(t, metrics.SchemaSubmissions.OldestBacklogAt)
+		assert.WithinDuration(t, daysAgo(10), *metrics.SchemaSubmissions.OldestBacklogAt, time.Second)
+
+		assert.Equal(t, map[string]int64{"pending": 1, "rejected": 1, "approved": 1}, metrics.ApplicationSubmissions.ByStatus)
+		assert.Equal(t, int64(1), metrics.ApplicationSubmissions.Backlog)
+		assert.Equal(t, int64(3), metrics.Backlog)
+	})
+
+	t.Run("Review outcomes are reported for the window", func(t *testing.T) {
+		metrics, err := service.GetReviewMetrics(ctx, "", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), metrics.Decided)
+		assert.Equal(t, 0.5, metrics.ApprovalRate)
+		assert.InDelta(t, 48, metrics.AverageReviewHours, 0.01)
+		assert.Equal(t, int64(1), metrics.SchemaSubmissions.Approved)
+		assert.InDelta(t, 72, metrics.SchemaSubmissions.AverageReviewHours, 0.01)
+		assert.Equal(t, int64(1), metrics.ApplicationSubmissions.Rejected)
+		assert.InDelta(t, 24, metrics.ApplicationSubmissions.AverageReviewHours, 0.01)
+
+		require.Len(t, metrics.Members, 2)
+		assert.Equal(t, "mem_consumer", metrics.Members[0].MemberID)
+		assert.Equal(t, "Consumer", metrics.Members[0].MemberName)
+		assert.Equal(t, 0.0, metrics.Members[0].ApprovalRate)
+		assert.Equal(t, "mem_provider", metrics.Members[1].MemberID)
+		assert.Equal(t, 1.0, metrics.Members[1].ApprovalRate)
+
+		metrics, err = service.GetReviewMetrics(ctx, daysAgo(90).Format(time.RFC3339), "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), metrics.Decided)
+	})
+
+	t.Run("Invalid windows are rejected", func(t *testing.T) {
+		_, err := service.GetReviewMetrics(ctx, "yesterday", "")
+		assert.ErrorIs(t, err, ErrInvalidMetricsWindow)
+		_, err = service.GetReviewMetrics(ctx, daysAgo(400).Format(time.RFC3339), "")
+		assert.ErrorIs(t, err, ErrInvalidMetricsWindow)
+	})
+}
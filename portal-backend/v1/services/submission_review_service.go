@@ -0,0 +1,531 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSubmissionReviewRequired is returned when a submission update tries to approve it or move it
+	// between review steps instead of going through the review workflow
+	ErrSubmissionReviewRequired = errors.New("submissions can only be approved through the review workflow")
+	// ErrSubmissionNotInReview is returned when reviewing a submission that was already approved or rejected
+	ErrSubmissionNotInReview = errors.New("submission is not awaiting review")
+	// ErrAlreadyReviewed is returned when a reviewer decides on the same step twice
+	ErrAlreadyReviewed = errors.New("reviewer has already reviewed this step")
+	// ErrNotAssignedReviewer is returned when a step has assigned reviewers and the caller is not one of them
+	ErrNotAssignedReviewer = errors.New("reviewer is not assigned to the current review step")
+	// ErrUnknownReviewStep is returned when a step is not part of the review workflow
+	ErrUnknownReviewStep = errors.New("unknown review step")
+	// ErrInvalidReviewers is returned when fewer reviewers are assigned than the step requires approvals
+	ErrInvalidReviewers = errors.New("a review step needs at least as many reviewers as required approvals")
+	// ErrSchemaEndpointProbeDisabled is returned when checking a schema endpoint while probing is disabled
+	ErrSchemaEndpointProbeDisabled = errors.New("schema endpoint verification is disabled")
+)
+
+// DefaultReviewWorkflow is used when SUBMISSION_REVIEW_WORKFLOW is not set
+var DefaultReviewWorkflow = []models.ReviewStep{
+	{Name: "technical_review", RequiredApprovals: 1},
+	{Name: "security_review", RequiredApprovals: 1},
+}
+
+// ParseReviewWorkflow parses a review workflow of the form "technical_review:1,security_review:2",
+// listing the steps in order with the number of approvals each requires (1 when omitted)
+func ParseReviewWorkflow(value string) ([]models.ReviewStep, error) {
+	var steps []models.ReviewStep
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		name, count, hasCount := strings.Cut(strings.TrimSpace(entry), ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("review step name is empty")
+		}
+		switch models.Status(name) {
+		case models.StatusPending, models.StatusApproved, models.StatusRejected:
+			return nil, fmt.Errorf("review step %q clashes with a submission status", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("review step %q is listed twice", name)
+		}
+		seen[name] = true
+
+		required := 1
+		if hasCount {
+			n, err := strconv.Atoi(strings.TrimSpace(count))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("review step %q must require at least one approval", name)
+			}
+			required = n
+		}
+		steps = append(steps, models.ReviewStep{Name: name, RequiredApprovals: required})
+	}
+	return steps, nil
+}
+
+// SubmissionReviewService moves schema and application submissions through the review workflow.
+// A new submission is pending; the first review activity moves it into the first step, and each
+// step is passed once enough distinct reviewers approve it. Passing the last step approves the
+// submission and creates the schema or application. A single rejection rejects the submission.
+//
+// When endpointProber is set, a schema submission is only approved if its endpoint serves its SDL.
+// When sandboxService is set, approving a new application also provisions its sandbox.
+type SubmissionReviewService struct {
+	db                 *gorm.DB
+	schemaService      *SchemaService
+	applicationService *ApplicationService
+	endpointProber     *SchemaEndpointProber
+	sandboxService     *SandboxService
+	steps              []models.ReviewStep
+}
+
+// NewSubmissionReviewService creates a new submission review service for the given workflow steps;
+// endpointProber may be nil to approve schema submissions without probing their endpoints, and
+// sandboxService nil to approve applications without provisioning sandboxes
+func NewSubmissionReviewService(db *gorm.DB, schemaService *SchemaService, applicationService *ApplicationService, endpointProber *SchemaEndpointProber, sandboxService *SandboxService, steps []models.ReviewStep) *SubmissionReviewService {
+	return &SubmissionReviewService{db: db, schemaService: schemaService, applicationService: applicationService, endpointProber: endpointProber, sandboxService: sandboxService, steps: steps}
+}
+
+// stepIndex returns the index of the step a submission with status is in, or -1 once it is decided
+func (s *SubmissionReviewService) stepIndex(status string) int {
+	if status == string(models.StatusPending) {
+		return 0
+	}
+	for i, step := range s.steps {
+		if step.Name == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsLastStep reports whether a submission in status is on the last step of the review workflow, where
+// approving it approves the submission once the step has its approvals
+func (s *SubmissionReviewService) IsLastStep(status string) bool {
+	index := s.stepIndex(status)
+	return index >= 0 && index == len(s.steps)-1
+}
+
+// GetReview returns the review progress of a submission
+func (s *SubmissionReviewService) GetReview(ctx context.Context, submissionType models.SubmissionType, submissionID string) (*models.SubmissionReviewResponse, error) {
+	db := s.db.WithContext(ctx)
+	status, err := submissionStatus(db, submissionType, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []models.SubmissionReview
+	if err := db.Where("submission_type = ? AND submission_id = ?", submissionType, submissionID).
+		Order("created_at ASC").Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("failed to get submission reviews: %w", err)
+	}
+	var reviewers []models.SubmissionReviewer
+	if err := db.Where("submission_type = ? AND submission_id = ?", submissionType, submissionID).
+		Order("reviewer_id ASC").Find(&reviewers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get submission reviewers: %w", err)
+	}
+
+	response := &models.SubmissionReviewResponse{
+		SubmissionID:   submissionID,
+		SubmissionType: submissionType,
+		Status:         status,
+		Steps:          make([]models.ReviewStepResponse, 0, len(s.steps)),
+		Decisions:      make([]models.ReviewDecisionRecord, 0, len(reviews)),
+	}
+	current := s.stepIndex(status)
+	if current >= 0 {
+		response.CurrentStep = &s.steps[current].Name
+	}
+	for i, step := range s.steps {
+		stepResponse := models.ReviewStepResponse{
+			Name:              step.Name,
+			RequiredApprovals: step.RequiredApprovals,
+			Reviewers:         []string{},
+		}
+		for _, review := range reviews {
+			if review.Step == step.Name && review.Decision == models.ReviewDecisionApprove {
+				stepResponse.Approvals++
+			}
+		}
+		for _, reviewer := range reviewers {
+			if reviewer.Step == step.Name {
+				stepResponse.Reviewers = append(stepResponse.Reviewers, reviewer.ReviewerID)
+			}
+		}
+		stepResponse.Complete = status == string(models.StatusApproved) || (current >= 0 && i < current)
+		response.Steps = append(response.Steps, stepResponse)
+	}
+	for _, review := range reviews {
+		response.Decisions = append(response.Decisions, models.ReviewDecisionRecord{
+			ReviewID:   review.ReviewID,
+			Step:       review.Step,
+			ReviewerID: review.ReviewerID,
+			Decision:   review.Decision,
+			Comment:    review.Comment,
+			CreatedAt:  review.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+// Review records a reviewer's decision on the current step of a submission. Once the step has the
+// approvals it requires the submission moves to the next step, or is approved after the last one.
+func (s *SubmissionReviewService) Review(ctx context.Context, submissionType models.SubmissionType, submissionID, reviewerID string, req *models.SubmissionReviewDecisionRequest) (*models.SubmissionReviewResponse, error) {
+	if !req.Decision.IsValid() {
+		return nil, fmt.Errorf("invalid review decision: %s", req.Decision)
+	}
+
+	var review models.SubmissionReview
+	var step models.ReviewStep
+	var approve bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		status, err := submissionStatus(tx, submissionType, submissionID)
+		if err != nil {
+			return err
+		}
+		index := s.stepIndex(status)
+		if index < 0 {
+			return ErrSubmissionNotInReview
+		}
+		step = s.steps[index]
+
+		var assigned []string
+		if err := tx.Model(&models.SubmissionReviewer{}).
+			Where("submission_type = ? AND submission_id = ? AND step = ?", submissionType, submissionID, step.Name).
+			Pluck("reviewer_id", &assigned).Error; err != nil {
+			return fmt.Errorf("failed to get assigned reviewers: %w", err)
+		}
+		if len(assigned) > 0 && !slices.Contains(assigned, reviewerID) {
+			return ErrNotAssignedReviewer
+		}
+
+		var existing int64
+		if err := tx.Model(&models.SubmissionReview{}).
+			Where("submission_type = ? AND submission_id = ? AND step = ? AND reviewer_id = ?", submissionType, submissionID, step.Name, reviewerID).
+			Count(&existing).Error; err != nil {
+			return fmt.Errorf("failed to check existing reviews: %w", err)
+		}
+		if existing > 0 {
+			return ErrAlreadyReviewed
+		}
+
+		review = models.SubmissionReview{
+			ReviewID:       "rev_" + uuid.New().String(),
+			SubmissionType: submissionType,
+			SubmissionID:   submissionID,
+			Step:           step.Name,
+			ReviewerID:     reviewerID,
+			Decision:       req.Decision,
+			Comment:        req.Comment,
+		}
+		if err := tx.Create(&review).Error; err != nil {
+			return fmt.Errorf("failed to record review: %w", err)
+		}
+
+		next := step.Name
+		if req.Decision == models.ReviewDecisionReject {
+			next = string(models.StatusRejected)
+		} else {
+			var approvals int64
+			if err := tx.Model(&models.SubmissionReview{}).
+				Where("submission_type = ? AND submission_id = ? AND step = ? AND decision = ?", submissionType, submissionID, step.Name, models.ReviewDecisionApprove).
+				Count(&approvals).Error; err != nil {
+				return fmt.Errorf("failed to count approvals: %w", err)
+			}
+			if int(approvals) >= step.RequiredApprovals {
+				if index+1 < len(s.steps) {
+					next = s.steps[index+1].Name
+				} else {
+					next = string(models.StatusApproved)
+					approve = true
+				}
+			}
+		}
+		if next == status {
+			return nil
+		}
+		return setSubmissionStatus(tx, submissionType, submissionID, status, next, req.Comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Decision == models.ReviewDecisionReject && submissionType == models.SubmissionTypeApplication {
+		s.rejectApplicationRenewal(ctx, submissionID)
+	}
+	if approve {
+		if err := s.createApprovedResource(ctx, submissionType, submissionID); err != nil {
+			// Compensation: return the submission to the last step and drop the approval so it can be retried
+			compensationErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				if err := tx.Delete(&models.SubmissionReview{}, "review_id = ?", review.ReviewID).Error; err != nil {
+					return err
+				}
+				return setSubmissionStatus(tx, submissionType, submissionID, string(models.StatusApproved), step.Name, nil)
+			})
+			if compensationErr != nil {
+				slog.Error("Failed to compensate submission approval",
+					"submissionType", submissionType,
+					"submissionID", submissionID,
+					"originalError", err,
+					"compensationError", compensationErr)
+				return nil, fmt.Errorf("failed to create resource from approved submission: %w, and failed to compensate submission status: %w", err, compensationErr)
+			}
+			slog.Info("Successfully compensated submission approval", "submissionType", submissionType, "submissionID", submissionID)
+			return nil, fmt.Errorf("failed to create resource from approved submission: %w", err)
+		}
+	}
+
+	slog.Info("Submission reviewed",
+		"submissionType", submissionType,
+		"submissionID", submissionID,
+		"step", step.Name,
+		"reviewerID", reviewerID,
+		"decision", req.Decision)
+	return s.GetReview(ctx, submissionType, submissionID)
+}
+
+// AssignReviewers replaces the reviewers assigned to a step of a submission. An empty list lets any
+// admin review the step again.
+func (s *SubmissionReviewService) AssignReviewers(ctx context.Context, submissionType models.SubmissionType, submissionID, stepName string, reviewerIDs []string, assignedBy string) (*models.SubmissionReviewResponse, error) {
+	var step *models.ReviewStep
+	for i := range s.steps {
+		if s.steps[i].Name == stepName {
+			step = &s.steps[i]
+		}
+	}
+	if step == nil {
+		return nil, ErrUnknownReviewStep
+	}
+
+	unique := make([]string, 0, len(reviewerIDs))
+	for _, reviewerID := range reviewerIDs {
+		reviewerID = strings.TrimSpace(reviewerID)
+		if reviewerID != "" && !slices.Contains(unique, reviewerID) {
+			unique = append(unique, reviewerID)
+		}
+	}
+	if len(unique) > 0 && len(unique) < step.RequiredApprovals {
+		return nil, models.NewValidationError(ErrInvalidReviewers, models.ValidationErrorTooShort, "reviewerIds",
+			fmt.Sprintf("reviewerIds must list at least %d reviewers", step.RequiredApprovals))
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		status, err := submissionStatus(tx, submissionType, submissionID)
+		if err != nil {
+			return err
+		}
+		if s.stepIndex(status) < 0 {
+			return ErrSubmissionNotInReview
+		}
+		if err := tx.Where("submission_type = ? AND submission_id = ? AND step = ?", submissionType, submissionID, step.Name).
+			Delete(&models.SubmissionReviewer{}).Error; err != nil {
+			return fmt.Errorf("failed to clear reviewers: %w", err)
+		}
+		for _, reviewerID := range unique {
+			reviewer := models.SubmissionReviewer{
+				SubmissionType: submissionType,
+				SubmissionID:   submissionID,
+				Step:           step.Name,
+				ReviewerID:     reviewerID,
+				AssignedBy:     assignedBy,
+			}
+			if err := tx.Create(&reviewer).Error; err != nil {
+				return fmt.Errorf("failed to assign reviewer: %w", err)
+			}
+		}
+		// Assigning reviewers starts the review of a pending submission
+		if status == string(models.StatusPending) {
+			return setSubmissionStatus(tx, submissionType, submissionID, status, s.steps[0].Name, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetReview(ctx, submissionType, submissionID)
+}
+
+// AddComment adds a comment to the discussion thread of a submission
+func (s *SubmissionReviewService) AddComment(ctx context.Context, submissionType models.SubmissionType, submissionID, authorID, body string) (*models.SubmissionCommentResponse, error) {
+	status, err := submissionStatus(s.db.WithContext(ctx), submissionType, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := models.SubmissionComment{
+		CommentID:      "cmt_" + uuid.New().String(),
+		SubmissionType: submissionType,
+		SubmissionID:   submissionID,
+		AuthorID:       authorID,
+		Step:           status,
+		Body:           body,
+	}
+	if err := s.db.WithContext(ctx).Create(&comment).Error; err != nil {
+		return nil, fmt.Errorf("failed to add comment: %w", err)
+	}
+	response := submissionCommentResponseOf(comment)
+	return &response, nil
+}
+
+// ListComments returns the discussion thread of a submission, oldest first
+func (s *SubmissionReviewService) ListComments(ctx context.Context, submissionType models.SubmissionType, submissionID string) ([]models.SubmissionCommentResponse, error) {
+	db := s.db.WithContext(ctx)
+	if _, err := submissionStatus(db, submissionType, submissionID); err != nil {
+		return nil, err
+	}
+
+	var comments []models.SubmissionComment
+	if err := db.Where("submission_type = ? AND submission_id = ?", submissionType, submissionID).
+		Order("created_at ASC").Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	responses := make([]models.SubmissionCommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		responses = append(responses, submissionCommentResponseOf(comment))
+	}
+	return responses, nil
+}
+
+// CheckSchemaEndpoint probes the endpoint of a schema submission the way its approval will, so
+// reviewers can see whether it serves the submitted SDL before approving
+func (s *SubmissionReviewService) CheckSchemaEndpoint(ctx context.Context, submissionID string) (*models.SchemaEndpointReport, error) {
+	if s.endpointProber == nil {
+		return nil, ErrSchemaEndpointProbeDisabled
+	}
+	var submission models.SchemaSubmission
+	if err := s.db.WithContext(ctx).First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get schema submission: %w", err)
+	}
+	return s.endpointProber.Probe(ctx, submission.SchemaEndpoint, submission.SDL), nil
+}
+
+// createApprovedResource creates the schema or application an approved submission describes
+func (s *SubmissionReviewService) createApprovedResource(ctx context.Context, submissionType models.SubmissionType, submissionID string) error {
+	switch submissionType {
+	case models.SubmissionTypeSchema:
+		var submission models.SchemaSubmission
+		if err := s.db.WithContext(ctx).First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+			return fmt.Errorf("schema submission not found: %w", err)
+		}
+		if s.endpointProber != nil {
+			report := s.endpointProber.Probe(ctx, submission.SchemaEndpoint, submission.SDL)
+			if !report.Matches {
+				return &SchemaEndpointMismatchError{Report: report}
+			}
+		}
+		_, err := s.schemaService.CreateSchema(&models.CreateSchemaRequest{
+			SchemaName:          submission.SchemaName,
+			SchemaDescription:   submission.SchemaDescription,
+			SDL:                 submission.SDL,
+			Endpoint:            submission.SchemaEndpoint,
+			MemberID:            submission.MemberID,
+			FieldConfigurations: submission.FieldConfigurations,
+		})
+		return err
+	case models.SubmissionTypeApplication:
+		var submission models.ApplicationSubmission
+		if err := s.db.WithContext(ctx).First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+			return fmt.Errorf("application submission not found: %w", err)
+		}
+		if submission.Renewal {
+			_, err := s.applicationService.RenewApplicationGrant(ctx, submissionID)
+			return err
+		}
+		application, err := s.applicationService.CreateApplication(ctx, &models.CreateApplicationRequest{
+			ApplicationName:        submission.ApplicationName,
+			ApplicationDescription: submission.ApplicationDescription,
+			SelectedFields:         submission.SelectedFields,
+			MemberID:               submission.MemberID,
+		})
+		if err != nil {
+			return err
+		}
+		if s.sandboxService != nil {
+			// The application is approved either way; a failed sandbox can be provisioned again later
+			if _, err := s.sandboxService.ProvisionSandbox(ctx, application.ApplicationID, submission.MemberID); err != nil {
+				slog.Error("Failed to provision application sandbox", "applicationID", application.ApplicationID, "error", err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown submission type: %s", submissionType)
+}
+
+// rejectApplicationRenewal rejects the PDP renewal request of a rejected renewal submission. The
+// submission stays rejected either way; a renewal request left pending in the PDP extends no grants.
+func (s *SubmissionReviewService) rejectApplicationRenewal(ctx context.Context, submissionID string) {
+	err := s.applicationService.RejectApplicationRenewal(ctx, submissionID)
+	if err != nil && !errors.Is(err, ErrNotRenewalSubmission) {
+		slog.Error("Failed to reject PDP renewal request", "submissionID", submissionID, "error", err)
+	}
+}
+
+// submissionModel returns the model of the table holding submissions of submissionType
+func submissionModel(submissionType models.SubmissionType) (interface{}, error) {
+	switch submissionType {
+	case models.SubmissionTypeSchema:
+		return &models.SchemaSubmission{}, nil
+	case models.SubmissionTypeApplication:
+		return &models.ApplicationSubmission{}, nil
+	}
+	return nil, fmt.Errorf("unknown submission type: %s", submissionType)
+}
+
+// submissionStatus reads the status of a live submission
+func submissionStatus(tx *gorm.DB, submissionType models.SubmissionType, submissionID string) (string, error) {
+	model, err := submissionModel(submissionType)
+	if err != nil {
+		return "", err
+	}
+	var statuses []string
+	if err := tx.Model(model).Where("submission_id = ?", submissionID).Limit(1).Pluck("status", &statuses).Error; err != nil {
+		return "", fmt.Errorf("failed to get submission: %w", err)
+	}
+	if len(statuses) == 0 {
+		return "", ErrResourceNotFound
+	}
+	return statuses[0], nil
+}
+
+// setSubmissionStatus moves a submission from one status to another, failing if a concurrent
+// review moved it first. A non-nil review is stored as the submission's review note.
+func setSubmissionStatus(tx *gorm.DB, submissionType models.SubmissionType, submissionID, from, to string, review *string) error {
+	model, err := submissionModel(submissionType)
+	if err != nil {
+		return err
+	}
+	updates := map[string]interface{}{"status": to, "updated_at": time.Now()}
+	if review != nil {
+		updates["review"] = *review
+	}
+	result := tx.Model(model).Where("submission_id = ? AND status = ?", submissionID, from).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update submission status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubmissionNotInReview
+	}
+	return nil
+}
+
+func submissionCommentResponseOf(comment models.SubmissionComment) models.SubmissionCommentResponse {
+	return models.SubmissionCommentResponse{
+		CommentID: comment.CommentID,
+		AuthorID:  comment.AuthorID,
+		Step:      comment.Step,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/idp"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReviewWorkflow(t *testing.T) {
+	steps, err := ParseReviewWorkflow("technical_review, security_review:2")
+	require.NoError(t, err)
+	assert.Equal(t, []models.ReviewStep{
+		{Name: "technical_review", RequiredApprovals: 1},
+		{Name: "security_review", RequiredApprovals: 2},
+	}, steps)
+
+	for _, value := range []string{"", "a,,b", "a:0", "a:x", "a,a", "approved", "pending:1"} {
+		_, err := ParseReviewWorkflow(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSubmissionReviewService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer pdpServer.Close()
+	pdpService := NewPDPService(pdpServer.URL, "test-key")
+	workflow := []models.ReviewStep{
+		{Name: "technical_review", RequiredApprovals: 1},
+		{Name: "security_review", RequiredApprovals: 2},
+	}
+	service := NewSubmissionReviewService(db, NewSchemaService(db, pdpService), NewApplicationService(db, pdpService, &MockIDP{
+		CreateApplicationFunc: func(ctx context.Context, app *idp.Application) (*string, error) {
+			return nil, errors.New("idp unavailable")
+		},
+	}), nil, nil, workflow)
+	approve := &models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove}
+
+	t.Run("SchemaPassesEveryStep", func(t *testing.T) {
+		review, err := service.Review(ctx, models.SubmissionTypeSchema, "sub_schema", "reviewer-1", approve)
+		require.NoError(t, err)
+		assert.Equal(t, "security_review", review.Status)
+		assert.True(t, review.Steps[0].Complete)
+
+		review, err = service.Review(ctx, models.SubmissionTypeSchema, "sub_schema", "reviewer-1", approve)
+		require.NoError(t, err)
+		assert.Equal(t, "security_review", review.Status)
+		assert.Equal(t, 1, review.Steps[1].Approvals)
+
+		_, err = service.Review(ctx, models.SubmissionTypeSchema, "sub_schema", "reviewer-1", approve)
+		assert.ErrorIs(t, err, ErrAlreadyReviewed)
+
+		review, err = service.Review(ctx, models.SubmissionTypeSchema, "sub_schema", "reviewer-2", approve)
+		require.NoError(t, err)
+		assert.Equal(t, string(models.StatusApproved), review.Status)
+		assert.Nil(t, review.CurrentStep)
+		assert.Len(t, review.Decisions, 3)
+
+		var schemas int64
+		require.NoError(t, db.Model(&models.Schema{}).Where("schema_name = ?", "Person v2").Count(&schemas).Error)
+		assert.Equal(t, int64(1), schemas)
+
+		_, err = service.Review(ctx, models.SubmissionTypeSchema, "sub_schema", "reviewer-3", approve)
+		assert.ErrorIs(t, err, ErrSubmissionNotInReview)
+	})
+
+	t.Run("AssignedReviewersAndRejection", func(t *testing.T) {
+		_, err := service.AssignReviewers(ctx, models.SubmissionTypeApplication, "sub_app", "security_review", []string{"reviewer-1"}, "admin")
+		assert.ErrorIs(t, err, ErrInvalidReviewers)
+		_, err = service.AssignReviewers(ctx, models.SubmissionTypeApplication, "sub_app", "legal_review", nil, "admin")
+		assert.ErrorIs(t, err, ErrUnknownReviewStep)
+
+		review, err := service.AssignReviewers(ctx, models.SubmissionTypeApplication, "sub_app", "technical_review", []string{"reviewer-1", "reviewer-1"}, "admin")
+		require.NoError(t, err)
+		assert.Equal(t, "technical_review", review.Status)
+		assert.Equal(t, []string{"reviewer-1"}, review.Steps[0].Reviewers)
+
+		_, err = service.Review(ctx, models.SubmissionTypeApplication, "sub_app", "reviewer-2", approve)
+		assert.ErrorIs(t, err, ErrNotAssignedReviewer)
+
+		comment := "Selected fields are too broad"
+		review, err = service.Review(ctx, models.SubmissionTypeApplication, "sub_app", "reviewer-1", &models.SubmissionReviewDecisionRequest{
+			Decision: models.ReviewDecisionReject,
+			Comment:  &comment,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, string(models.StatusRejected), review.Status)
+
+		var submission models.ApplicationSubmission
+		require.NoError(t, db.First(&submission, "submission_id = ?", "sub_app").Error)
+		require.NotNil(t, submission.Review)
+		assert.Equal(t, comment, *submission.Review)
+	})
+
+	t.Run("FailedApprovalIsCompensated", func(t *testing.T) {
+		fields := models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_1"}}
+		require.NoError(t, db.Create(&models.ApplicationSubmission{
+			SubmissionID: "sub_app_2", ApplicationName: "App v3", SelectedFields: fields,
+			MemberID: "mem_consumer", Status: "security_review",
+		}).Error)
+		_, err := service.Review(ctx, models.SubmissionTypeApplication, "sub_app_2", "reviewer-1", approve)
+		require.NoError(t, err)
+
+		_, err = service.Review(ctx, models.SubmissionTypeApplication, "sub_app_2", "reviewer-2", approve)
+		assert.Error(t, err)
+
+		review, err := service.GetReview(ctx, models.SubmissionTypeApplication, "sub_app_2")
+		require.NoError(t, err)
+		assert.Equal(t, "security_review", review.Status)
+		assert.Equal(t, 1, review.Steps[1].Approvals)
+	})
+
+	t.Run("Comments", func(t *testing.T) {
+		_, err := service.AddComment(ctx, models.SubmissionTypeApplication, "sub_app", "idp-consumer", "Narrowed the fields")
+		require.NoError(t, err)
+		_, err = service.AddComment(ctx, models.SubmissionTypeApplication, "sub_missing", "idp-consumer", "Hello")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		comments, err := service.ListComments(ctx, models.SubmissionTypeApplication, "sub_app")
+		require.NoError(t, err)
+		require.Len(t, comments, 1)
+		assert.Equal(t, string(models.StatusRejected), comments[0].Step)
+		assert.Equal(t, "Narrowed the fields", comments[0].Body)
+	})
+}
+
+func TestSubmissionReviewService_ApprovalWithApplicationCreationFailure(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	// The PDP fails the allow list update made while the approved application is created
+	pdpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error": "pdp error"}`))
+	}))
+	defer pdpServer.Close()
+	pdpService := NewPDPService(pdpServer.URL, "test-key")
+
+	var deletedIdpApplications []string
+	service := NewSubmissionReviewService(db, NewSchemaService(db, pdpService), NewApplicationService(db, pdpService, &MockIDP{
+		DeleteApplicationFunc: func(ctx context.Context, applicationID string) error {
+			deletedIdpApplications = append(deletedIdpApplications, applicationID)
+			return nil
+		},
+	}), nil, nil, []models.ReviewStep{{Name: "technical_review", RequiredApprovals: 1}})
+
+	fields := models.SelectedFieldRecords{{FieldName: "person.name", SchemaID: "sch_1"}}
+	require.NoError(t, db.Create(&models.ApplicationSubmission{
+		SubmissionID: "sub_app_pdp", ApplicationName: "App PDP", SelectedFields: fields,
+		MemberID: "mem_consumer", Status: "technical_review",
+	}).Error)
+
+	_, err := service.Review(ctx, models.SubmissionTypeApplication, "sub_app_pdp", "reviewer-1",
+		&models.SubmissionReviewDecisionRequest{Decision: models.ReviewDecisionApprove})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create resource from approved submission")
+
+	// The application is removed from the database and the IDP
+	var applications int64
+	require.NoError(t, db.Unscoped().Model(&models.Application{}).Where("application_name = ?", "App PDP").Count(&applications).Error)
+	assert.Zero(t, applications)
+	assert.Equal(t, []string{"mock-idp-app-id"}, deletedIdpApplications)
+
+	// The submission is back on its last step without the approval, so it can be approved again
+	review, err := service.GetReview(ctx, models.SubmissionTypeApplication, "sub_app_pdp")
+	require.NoError(t, err)
+	assert.Equal(t, "technical_review", review.Status)
+	assert.Zero(t, review.Steps[0].Approvals)
+	assert.Empty(t, review.Decisions)
+}
@@ -66,11 +66,28 @@ func SetupSQLiteTestDB(t *testing.T) *gorm.DB {
 
 	// Auto-migrate all models
 	err = db.AutoMigrate(
+		&models.Organization{},
 		&models.Member{},
 		&models.Application{},
 		&models.ApplicationSubmission{},
 		&models.Schema{},
 		&models.SchemaSubmission{},
+		&models.PDPJob{},
+		&models.ApplicationCredential{},
+		&models.SubmissionReview{},
+		&models.SubmissionReviewer{},
+		&models.SubmissionComment{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.Invitation{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.ImpersonationSession{},
+		&models.ApplicationSandbox{},
+		&models.AdminApproval{},
+		&models.SavedView{},
+		&models.SubmissionAttachment{},
+		&models.FieldConfigurationTemplate{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
@@ -86,6 +103,54 @@ func SetupSQLiteTestDB(t *testing.T) *gorm.DB {
 // Exported for use in handler tests
 func CleanupTestData(t *testing.T, db *gorm.DB) {
 	// Delete in reverse order of dependencies
+	if err := db.Exec("DELETE FROM pdp_jobs").Error; err != nil {
+		t.Logf("Warning: failed to cleanup pdp_jobs: %v", err)
+	}
+	if err := db.Exec("DELETE FROM application_sandboxes").Error; err != nil {
+		t.Logf("Warning: failed to cleanup application_sandboxes: %v", err)
+	}
+	if err := db.Exec("DELETE FROM admin_approvals").Error; err != nil {
+		t.Logf("Warning: failed to cleanup admin_approvals: %v", err)
+	}
+	if err := db.Exec("DELETE FROM saved_views").Error; err != nil {
+		t.Logf("Warning: failed to cleanup saved_views: %v", err)
+	}
+	if err := db.Exec("DELETE FROM field_configuration_templates").Error; err != nil {
+		t.Logf("Warning: failed to cleanup field_configuration_templates: %v", err)
+	}
+	if err := db.Exec("DELETE FROM submission_attachments").Error; err != nil {
+		t.Logf("Warning: failed to cleanup submission_attachments: %v", err)
+	}
+	if err := db.Exec("DELETE FROM impersonation_sessions").Error; err != nil {
+		t.Logf("Warning: failed to cleanup impersonation_sessions: %v", err)
+	}
+	if err := db.Exec("DELETE FROM webhook_deliveries").Error; err != nil {
+		t.Logf("Warning: failed to cleanup webhook_deliveries: %v", err)
+	}
+	if err := db.Exec("DELETE FROM webhook_subscriptions").Error; err != nil {
+		t.Logf("Warning: failed to cleanup webhook_subscriptions: %v", err)
+	}
+	if err := db.Exec("DELETE FROM invitations").Error; err != nil {
+		t.Logf("Warning: failed to cleanup invitations: %v", err)
+	}
+	if err := db.Exec("DELETE FROM notifications").Error; err != nil {
+		t.Logf("Warning: failed to cleanup notifications: %v", err)
+	}
+	if err := db.Exec("DELETE FROM notification_preferences").Error; err != nil {
+		t.Logf("Warning: failed to cleanup notification_preferences: %v", err)
+	}
+	if err := db.Exec("DELETE FROM submission_comments").Error; err != nil {
+		t.Logf("Warning: failed to cleanup submission_comments: %v", err)
+	}
+	if err := db.Exec("DELETE FROM submission_reviewers").Error; err != nil {
+		t.Logf("Warning: failed to cleanup submission_reviewers: %v", err)
+	}
+	if err := db.Exec("DELETE FROM submission_reviews").Error; err != nil {
+		t.Logf("Warning: failed to cleanup submission_reviews: %v", err)
+	}
+	if err := db.Exec("DELETE FROM application_credentials").Error; err != nil {
+		t.Logf("Warning: failed to cleanup application_credentials: %v", err)
+	}
 	if err := db.Exec("DELETE FROM application_submissions").Error; err != nil {
 		t.Logf("Warning: failed to cleanup application_submissions: %v", err)
 	}
@@ -101,6 +166,9 @@ func CleanupTestData(t *testing.T, db *gorm.DB) {
 	if err := db.Exec("DELETE FROM members").Error; err != nil {
 		t.Logf("Warning: failed to cleanup members: %v", err)
 	}
+	if err := db.Exec("DELETE FROM organizations").Error; err != nil {
+		t.Logf("Warning: failed to cleanup organizations: %v", err)
+	}
 }
 
 // RequireTestDB is a helper function that sets up a test database and fails the test
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/gov-dx-sandbox/shared/requestid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidUsageWindow is returned for a usage window that is malformed or too long
+	ErrInvalidUsageWindow = errors.New("invalid usage window")
+	// ErrAuditServiceUnavailable is returned when the audit service cannot provide usage data
+	ErrAuditServiceUnavailable = errors.New("audit service unavailable")
+)
+
+const (
+	// DefaultUsageWindow is the period reported when the request gives no start time
+	DefaultUsageWindow = 30 * 24 * time.Hour
+	// MaxUsageWindow bounds the period of a usage report
+	MaxUsageWindow = 366 * 24 * time.Hour
+
+	// dataRequestEventType is logged by the orchestration engine for each request a consumer makes
+	dataRequestEventType = "DATA_REQUEST"
+	// usageFieldLimit is the number of fields a usage report lists; the audit service's maximum
+	usageFieldLimit = 100
+)
+
+// auditStats is the part of the audit service's statistics response that usage reports use
+type auditStats struct {
+	Totals   auditStatsCounts `json:"totals"`
+	Timeline []struct {
+		Start time.Time `json:"start"`
+		auditStatsCounts
+		Groups map[string]int64 `json:"groups"`
+	} `json:"timeline"`
+	Groups []struct {
+		Key string `json:"key"`
+		auditStatsCounts
+	} `json:"groups"`
+	TopFields []models.FieldUsage `json:"topFields"`
+}
+
+type auditStatsCounts struct {
+	Events        int64 `json:"events"`
+	Failures      int64 `json:"failures"`
+	ConsentDenied int64 `json:"consentDenied"`
+}
+
+// UsageService reports how applications use the exchange from the audit service's statistics
+type UsageService struct {
+	db *gorm.DB
+	// baseURL is the endpoint of the audit service
+	baseURL string
+	// token is the bearer token for the audit service's read APIs, which require an admin or system user
+	token string
+	// HTTPClient is used to make requests to the audit service
+	HTTPClient *http.Client
+}
+
+// NewUsageService creates a new usage service; token may be empty when the audit service's read APIs are unauthenticated
+func NewUsageService(db *gorm.DB, baseURL, token string) *UsageService {
+	return &UsageService{
+		db:         db,
+		baseURL:    baseURL,
+		token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetApplicationUsage aggregates the exchange events of an application between startTime and endTime
+// (RFC3339; by default the last 30 days). The exchange identifies a consumer by the application ID
+// claim of its token or, without one, by its client ID, so events recorded under either are counted.
+func (s *UsageService) GetApplicationUsage(ctx context.Context, applicationID, startTime, endTime string) (*models.ApplicationUsageResponse, error) {
+	start, end, err := parseUsageWindow(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var application models.Application
+	if err := s.db.WithContext(ctx).Select("application_id", "idp_client_id").
+		First(&application, "application_id = ?", applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	consumerIDs := []string{application.ApplicationID}
+	if application.IdpClientID != nil && *application.IdpClientID != "" && *application.IdpClientID != application.ApplicationID {
+		consumerIDs = append(consumerIDs, *application.IdpClientID)
+	}
+
+	usage := &models.ApplicationUsageResponse{ApplicationID: applicationID, StartTime: start, EndTime: end}
+	dayIndex := make(map[string]int)
+	fields := make(map[string]int64)
+	for _, consumerID := range consumerIDs {
+		stats, err := s.getConsumerStats(ctx, consumerID, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		addUsageCounts(&usage.UsageCounts, stats.Totals, 0)
+		for _, group := range stats.Groups {
+			if group.Key == dataRequestEventType {
+				usage.Requests += group.Events
+			}
+		}
+		for _, bucket := range stats.Timeline {
+			date := bucket.Start.UTC().Format(time.DateOnly)
+			i, ok := dayIndex[date]
+			if !ok {
+				i = len(usage.Daily)
+				dayIndex[date] = i
+				usage.Daily = append(usage.Daily, models.DailyUsage{Date: date})
+			}
+			addUsageCounts(&usage.Daily[i].UsageCounts, bucket.auditStatsCounts, bucket.Groups[dataRequestEventType])
+		}
+		for _, field := range stats.TopFields {
+			fields[field.Field] += field.Count
+		}
+	}
+
+	setErrorRate(&usage.UsageCounts)
+	for i := range usage.Daily {
+		setErrorRate(&usage.Daily[i].UsageCounts)
+	}
+	sort.Slice(usage.Daily, func(i, j int) bool { return usage.Daily[i].Date < usage.Daily[j].Date })
+	usage.Fields = rankFieldUsage(fields)
+	return usage, nil
+}
+
+// getConsumerStats fetches the daily statistics of one consumer ID from the audit service
+func (s *UsageService) getConsumerStats(ctx context.Context, consumerID string, start, end time.Time) (*auditStats, error) {
+	query := url.Values{
+		"consumerAppId": {consumerID},
+		"startTime":     {start.Format(time.RFC3339)},
+		"endTime":       {end.Format(time.RFC3339)},
+		"bucket":        {"day"},
+		"groupBy":       {"eventType"},
+		"limit":         {fmt.Sprint(usageFieldLimit)},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/api/audit-logs/stats?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if s.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	requestid.SetHeader(httpReq)
+
+	resp, err := s.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAuditServiceUnavailable, err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			slog.Error("failed to close response body", "error", err)
+		}
+	}(resp.Body)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response body: %w", ErrAuditServiceUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("Audit service returned error", "status", resp.StatusCode, "body", string(respBody))
+		return nil, fmt.Errorf("%w: audit service returned status %d", ErrAuditServiceUnavailable, resp.StatusCode)
+	}
+
+	var stats auditStats
+	if err := json.Unmarshal(respBody, &stats); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse response: %w", ErrAuditServiceUnavailable, err)
+	}
+	return &stats, nil
+}
+
+// parseUsageWindow parses an RFC3339 window; endTime defaults to now and startTime to DefaultUsageWindow before endTime
+func parseUsageWindow(startTime, endTime string) (time.Time, time.Time, error) {
+	return parseReportWindow(startTime, endTime, ErrInvalidUsageWindow)
+}
+
+// parseReportWindow parses the RFC3339 window of a report over past activity the way usage reports
+// do, wrapping errInvalid for windows that are malformed or longer than MaxUsageWindow
+func parseReportWindow(startTime, endTime string, errInvalid error) (time.Time, time.Time, error) {
+	end := time.Now().UTC()
+	if endTime != "" {
+		parsed, err := time.Parse(time.RFC3339, endTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: endTime must be RFC3339", errInvalid)
+		}
+		end = parsed.UTC()
+	}
+	start := end.Add(-DefaultUsageWindow)
+	if startTime != "" {
+		parsed, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: startTime must be RFC3339", errInvalid)
+		}
+		start = parsed.UTC()
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: endTime must be after startTime", errInvalid)
+	}
+	if end.Sub(start) > MaxUsageWindow {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: window must not exceed %d days", errInvalid, int(MaxUsageWindow.Hours()/24))
+	}
+	return start, end, nil
+}
+
+func addUsageCounts(counts *models.UsageCounts, stats auditStatsCounts, requests int64) {
+	counts.Requests += requests
+	counts.Events += stats.Events
+	counts.Errors += stats.Failures
+	counts.ConsentDenied += stats.ConsentDenied
+}
+
+func setErrorRate(counts *models.UsageCounts) {
+	if counts.Events > 0 {
+		counts.ErrorRate = float64(counts.Errors) / float64(counts.Events)
+	}
+}
+
+// rankFieldUsage sorts fields by request count (then name)
+func rankFieldUsage(counts map[string]int64) []models.FieldUsage {
+	fields := make([]models.FieldUsage, 0, len(counts))
+	for field, count := range counts {
+		fields = append(fields, models.FieldUsage{Field: field, Count: count})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].Count != fields[j].Count {
+			return fields[i].Count > fields[j].Count
+		}
+		return fields[i].Field < fields[j].Field
+	})
+	return fields
+}
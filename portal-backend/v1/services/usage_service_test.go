@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageService_GetApplicationUsage(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	clientID := "client-app-1"
+	require.NoError(t, db.Model(&models.Application{}).Where("application_id = ?", "app_1").Update("idp_client_id", clientID).Error)
+
+	// Events recorded under the application ID and under its client ID are both counted
+	var consumers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/audit-logs/stats", r.URL.Path)
+		assert.Equal(t, "Bearer audit-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "day", r.URL.Query().Get("bucket"))
+		assert.Equal(t, "eventType", r.URL.Query().Get("groupBy"))
+		consumer := r.URL.Query().Get("consumerAppId")
+		consumers = append(consumers, consumer)
+
+		requests, field := 1, "person.name"
+		if consumer == clientID {
+			requests, field = 2, "vehicle.plate"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"totals": {"events": %[1]d, "failures": 1, "consentDenied": 1},
+			"timeline": [
+				{"start": "2025-01-01T00:00:00Z", "events": %[1]d, "failures": 1, "consentDenied": 1, "groups": {"DATA_REQUEST": %[2]d}},
+				{"start": "2025-01-02T00:00:00Z", "events": 0, "failures": 0, "consentDenied": 0, "groups": {}}
+			],
+			"groups": [{"key": "DATA_REQUEST", "events": %[2]d}],
+			"topFields": [{"field": %[3]q, "count": %[2]d}, {"field": "person.address", "count": 1}]
+		}`, requests*4, requests, field)
+	}))
+	defer server.Close()
+
+	service := NewUsageService(db, server.URL, "audit-token")
+	ctx := context.Background()
+
+	t.Run("Aggregates consumer statistics", func(t *testing.T) {
+		usage, err := service.GetApplicationUsage(ctx, "app_1", "2025-01-01T00:00:00Z", "2025-01-03T00:00:00Z")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"app_1", clientID}, consumers)
+
+		assert.Equal(t, int64(3), usage.Requests)
+		assert.Equal(t, int64(12), usage.Events)
+		assert.Equal(t, int64(2), usage.Errors)
+		assert.InDelta(t, 2.0/12.0, usage.ErrorRate, 1e-9)
+		assert.Equal(t, int64(2), usage.ConsentDenied)
+
+		require.Len(t, usage.Daily, 2)
+		assert.Equal(t, "2025-01-01", usage.Daily[0].Date)
+		assert.Equal(t, int64(3), usage.Daily[0].Requests)
+		assert.Zero(t, usage.Daily[1].Requests)
+
+		require.Len(t, usage.Fields, 3)
+		assert.Equal(t, models.FieldUsage{Field: "person.address", Count: 2}, usage.Fields[0], "ties are sorted by name")
+		assert.Equal(t, models.FieldUsage{Field: "vehicle.plate", Count: 2}, usage.Fields[1])
+	})
+
+	t.Run("Invalid window", func(t *testing.T) {
+		_, err := service.GetApplicationUsage(ctx, "app_1", "yesterday", "")
+		assert.ErrorIs(t, err, ErrInvalidUsageWindow)
+		_, err = service.GetApplicationUsage(ctx, "app_1", "2025-01-02T00:00:00Z", "2025-01-01T00:00:00Z")
+		assert.ErrorIs(t, err, ErrInvalidUsageWindow)
+		_, err = service.GetApplicationUsage(ctx, "app_1", "2023-01-01T00:00:00Z", "2025-01-01T00:00:00Z")
+		assert.ErrorIs(t, err, ErrInvalidUsageWindow)
+	})
+
+	t.Run("Unknown application", func(t *testing.T) {
+		_, err := service.GetApplicationUsage(ctx, "app_missing", "", "")
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+	})
+
+	t.Run("Audit service failure", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer failing.Close()
+
+		_, err := NewUsageService(db, failing.URL, "").GetApplicationUsage(ctx, "app_1", "", "")
+		assert.ErrorIs(t, err, ErrAuditServiceUnavailable)
+	})
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// VirusScanner scans files for viruses
+type VirusScanner interface {
+	// Scan reads the file from r and returns the name of the virus found, or "" when the file is clean
+	Scan(ctx context.Context, r io.Reader) (string, error)
+}
+
+// clamdChunkSize is the size of the chunks files are streamed to clamd in
+const clamdChunkSize = 32 * 1024
+
+// ClamAVScanner scans files with a clamd daemon, streaming them over its INSTREAM command
+type ClamAVScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner creates a scanner for the clamd daemon listening on address (host:port). Each
+// scan must complete within timeout.
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{address: address, timeout: timeout}
+}
+
+// NewClamAVScannerFromEnv creates a ClamAV scanner for the clamd daemon at CLAMAV_ADDRESS. It returns
+// nil when CLAMAV_ADDRESS is not set, in which case attachments are stored without being scanned.
+func NewClamAVScannerFromEnv() *ClamAVScanner {
+	address := os.Getenv("CLAMAV_ADDRESS")
+	if address == "" {
+		return nil
+	}
+	return NewClamAVScanner(address, time.Minute)
+}
+
+// Scan streams r to clamd and parses its verdict
+func (c *ClamAVScanner) Scan(ctx context.Context, r io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return "", err
+		}
+	}
+
+	// The z prefix makes clamd read and terminate the command and its reply with a NUL byte. The file
+	// follows as chunks, each preceded by its length, and a zero length ends it.
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return "", fmt.Errorf("failed to send file to clamd: %w", err)
+	}
+	chunk := make([]byte, clamdChunkSize)
+	var length [4]byte
+	for {
+		n, readErr := r.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(length[:], uint32(n))
+			if _, err := writer.Write(length[:]); err != nil {
+				return "", fmt.Errorf("failed to send file to clamd: %w", err)
+			}
+			if _, err := writer.Write(chunk[:n]); err != nil {
+				return "", fmt.Errorf("failed to send file to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return "", fmt.Errorf("failed to read file: %w", readErr)
+		}
+	}
+	binary.BigEndian.PutUint32(length[:], 0)
+	if _, err := writer.Write(length[:]); err != nil {
+		return "", fmt.Errorf("failed to send file to clamd: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return "", fmt.Errorf("failed to send file to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseClamdReply(string(bytes.TrimRight(reply, "\x00")))
+}
+
+// parseClamdReply parses a reply such as "stream: OK" or "stream: Eicar-Signature FOUND"
+func parseClamdReply(reply string) (string, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	}
+	return "", fmt.Errorf("clamd could not scan the file: %s", reply)
+}
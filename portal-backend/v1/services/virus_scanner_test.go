@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClamAVScanner(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// Answer each INSTREAM command like clamd, finding the EICAR test string
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				command := make([]byte, len("zINSTREAM\x00"))
+				if _, err := io.ReadFull(conn, command); err != nil {
+					return
+				}
+				var file []byte
+				for {
+					var length [4]byte
+					if _, err := io.ReadFull(conn, length[:]); err != nil {
+						return
+					}
+					n := int(length[0])<<24 | int(length[1])<<16 | int(length[2])<<8 | int(length[3])
+					if n == 0 {
+						break
+					}
+					chunk := make([]byte, n)
+					if _, err := io.ReadFull(conn, chunk); err != nil {
+						return
+					}
+					file = append(file, chunk...)
+				}
+				reply := "stream: OK\x00"
+				if strings.Contains(string(file), "EICAR") {
+					reply = "stream: Eicar-Test-Signature FOUND\x00"
+				}
+				_, _ = conn.Write([]byte(reply))
+			}(conn)
+		}
+	}()
+
+	scanner := NewClamAVScanner(listener.Addr().String(), 5*time.Second)
+	virus, err := scanner.Scan(context.Background(), strings.NewReader(strings.Repeat("clean ", 10000)))
+	require.NoError(t, err)
+	assert.Empty(t, virus)
+
+	virus, err = scanner.Scan(context.Background(), strings.NewReader("X5O!P%@AP EICAR"))
+	require.NoError(t, err)
+	assert.Equal(t, "Eicar-Test-Signature", virus)
+
+	_, err = parseClamdReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxWebhooksPerApplication caps the webhook subscriptions of an application
+	MaxWebhooksPerApplication = 10
+	// webhookDeliveryMaxAttempts is how often an event is sent before its delivery is dead-lettered
+	webhookDeliveryMaxAttempts = 8
+	// defaultWebhookDeliveryListLimit caps the number of deliveries returned by ListDeliveries
+	defaultWebhookDeliveryListLimit = 100
+	// webhookBaseBackoff is the delay before the first retry; it doubles with every attempt
+	webhookBaseBackoff = 30 * time.Second
+	// webhookMaxBackoff caps the delay between retries
+	webhookMaxBackoff = time.Hour
+	// webhookDeliveryLease is how long a claimed delivery is hidden from other workers while it is sent
+	webhookDeliveryLease = 5 * time.Minute
+	// webhookSecretBytes is the length of the random part of a signing secret
+	webhookSecretBytes = 32
+)
+
+// Headers sent with every webhook request. The signature is the hex HMAC-SHA256, keyed with the
+// subscription's secret, of the timestamp header, a ".", and the request body.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var (
+	// ErrInvalidWebhook is returned for a webhook with an invalid URL or events, or one too many for its application
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	// ErrWebhookAddressBlocked is returned when a webhook URL resolves to a private, loopback or
+	// link-local address, which webhooks must not reach
+	ErrWebhookAddressBlocked = errors.New("webhook address is not public")
+)
+
+// WebhookService manages the webhook subscriptions of applications and delivers their events. Each
+// event is queued in the webhook_deliveries table, which doubles as the delivery log, and sent by
+// the webhook worker with retries, so a slow or failing endpoint never blocks the request that
+// raised the event.
+type WebhookService struct {
+	db *gorm.DB
+	// HTTPClient is used to send events to webhook endpoints
+	HTTPClient *http.Client
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{db: db, HTTPClient: NewWebhookHTTPClient()}
+}
+
+// NewWebhookHTTPClient creates the client webhooks are delivered with. Webhook URLs are chosen by
+// consumers, so the client only connects to public addresses and does not follow redirects.
+func NewWebhookHTTPClient() *http.Client {
+	return newPublicHTTPClient(10*time.Second, ErrWebhookAddressBlocked)
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute https URL whose host is not a blocked
+// address. Names are checked again by the delivery client once resolved.
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return models.NewValidationError(ErrInvalidWebhook, models.ValidationErrorInvalidFormat, "url", "url must be an absolute https URL")
+	}
+	host := parsed.Hostname()
+	if ip := net.ParseIP(host); (ip != nil && blockedIP(ip)) || host == "localhost" {
+		return models.NewValidationError(ErrInvalidWebhook, models.ValidationErrorInvalidValue, "url", "url must not point to a private, loopback or link-local address")
+	}
+	return nil
+}
+
+// CreateWebhook subscribes url to events of an application. The response carries the signing
+// secret, which is not returned again.
+func (s *WebhookService) CreateWebhook(ctx context.Context, applicationID, createdBy string, req *models.CreateWebhookRequest) (*models.WebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if len(req.Events) == 0 {
+		return nil, models.NewValidationError(ErrInvalidWebhook, models.ValidationErrorRequired, "events", "at least one event is required")
+	}
+	var events []models.WebhookEvent
+	seen := make(map[models.WebhookEvent]bool, len(req.Events))
+	for i, event := range req.Events {
+		if !event.IsValid() {
+			return nil, models.NewValidationError(ErrInvalidWebhook, models.ValidationErrorInvalidValue, fmt.Sprintf("events[%d]", i), fmt.Sprintf("unknown event %s", event))
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	eventsJSON, err := json.Marshal(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook events: %w", err)
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := models.WebhookSubscription{
+		WebhookID:     "whk_" + uuid.New().String(),
+		ApplicationID: applicationID,
+		URL:           req.URL,
+		Secret:        secret,
+		Events:        string(eventsJSON),
+		CreatedBy:     createdBy,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.WebhookSubscription{}).Where("application_id = ?", applicationID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count webhooks: %w", err)
+		}
+		if count >= MaxWebhooksPerApplication {
+			return fmt.Errorf("%w: an application can have at most %d webhooks", ErrInvalidWebhook, MaxWebhooksPerApplication)
+		}
+		if err := tx.Create(&webhook).Error; err != nil {
+			return fmt.Errorf("failed to create webhook: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Webhook created", "webhookID", webhook.WebhookID, "applicationID", applicationID, "events", events)
+	response := webhookResponseOf(webhook)
+	response.Secret = secret
+	return &response, nil
+}
+
+// ListWebhooks returns the webhook subscriptions of an application, oldest first
+func (s *WebhookService) ListWebhooks(ctx context.Context, applicationID string) ([]models.WebhookResponse, error) {
+	var webhooks []models.WebhookSubscription
+	if err := s.db.WithContext(ctx).Where("application_id = ?", applicationID).
+		Order("created_at ASC").Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	responses := make([]models.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		responses = append(responses, webhookResponseOf(webhook))
+	}
+	return responses, nil
+}
+
+// DeleteWebhook removes a webhook subscription of an application together with its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, applicationID, webhookID string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("webhook_id = ? AND application_id = ?", webhookID, applicationID).Delete(&models.WebhookSubscription{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete webhook: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrResourceNotFound
+		}
+		if err := tx.Where("webhook_id = ?", webhookID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListDeliveries returns the most recent deliveries of a webhook of an application, optionally
+// filtered by status
+func (s *WebhookService) ListDeliveries(ctx context.Context, applicationID, webhookID, status string, limit int) ([]models.WebhookDeliveryResponse, error) {
+	if status != "" && !models.WebhookDeliveryStatus(status).IsValid() {
+		return nil, models.NewValidationError(ErrInvalidWebhook, models.ValidationErrorInvalidValue, "status", fmt.Sprintf("unknown delivery status %s", status))
+	}
+	if limit <= 0 || limit > defaultWebhookDeliveryListLimit {
+		limit = defaultWebhookDeliveryListLimit
+	}
+
+	db := s.db.WithContext(ctx)
+	var count int64
+	if err := db.Model(&models.WebhookSubscription{}).
+		Where("webhook_id = ? AND application_id = ?", webhookID, applicationID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if count == 0 {
+		return nil, ErrResourceNotFound
+	}
+
+	query := db.Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("created_at DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	responses := make([]models.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		responses = append(responses, webhookDeliveryResponseOf(delivery))
+	}
+	return responses, nil
+}
+
+// EnqueueSubmissionStatusChanged queues a submission_status_changed event for the application an
+// application submission changes. Submissions for new applications have no webhooks yet.
+func (s *WebhookService) EnqueueSubmissionStatusChanged(ctx context.Context, submissionType models.SubmissionType, submissionID, previous, status string) error {
+	if submissionType != models.SubmissionTypeApplication {
+		return nil
+	}
+	var submission models.ApplicationSubmission
+	if err := s.db.WithContext(ctx).Select("submission_id", "previous_application_id").
+		First(&submission, "submission_id = ?", submissionID).Error; err != nil {
+		return fmt.Errorf("failed to get application submission: %w", err)
+	}
+	if submission.PreviousApplicationID == nil {
+		return nil
+	}
+	return s.enqueue(ctx, *submission.PreviousApplicationID, models.WebhookEventSubmissionStatusChanged,
+		models.SubmissionStatusChangedWebhookData{SubmissionID: submissionID, PreviousStatus: previous, Status: status})
+}
+
+// EnqueueSchemaDeprecated queues a schema_deprecated event for every live application that selected
+// fields of the schema, after the schema was deprecated, sunset or retired
+func (s *WebhookService) EnqueueSchemaDeprecated(ctx context.Context, schemaID string) error {
+	var schema models.Schema
+	if err := s.db.WithContext(ctx).First(&schema, "schema_id = ?", schemaID).Error; err != nil {
+		return fmt.Errorf("failed to get schema: %w", err)
+	}
+	consumers, err := schemaConsumers(ctx, s.db, schemaID)
+	if err != nil {
+		return err
+	}
+	response := schemaResponseOf(&schema)
+	for _, consumer := range consumers {
+		if err := s.enqueue(ctx, consumer.ApplicationID, models.WebhookEventSchemaDeprecated, models.SchemaDeprecatedWebhookData{
+			SchemaID:           schemaID,
+			SchemaName:         schema.SchemaName,
+			Version:            schema.Version,
+			SunsetAt:           response.SunsetAt,
+			DeprecationMessage: schema.DeprecationMessage,
+			Fields:             consumer.Fields,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnqueueCredentialExpiring queues a credential_expiring event for an application
+func (s *WebhookService) EnqueueCredentialExpiring(ctx context.Context, applicationID, credentialID string, expiresAt time.Time) error {
+	return s.enqueue(ctx, applicationID, models.WebhookEventCredentialExpiring,
+		models.CredentialExpiringWebhookData{CredentialID: credentialID, ExpiresAt: expiresAt.Format(time.RFC3339)})
+}
+
+// enqueue queues a delivery of event to every webhook of the application subscribed to it
+func (s *WebhookService) enqueue(ctx context.Context, applicationID string, event models.WebhookEvent, data interface{}) error {
+	var webhooks []models.WebhookSubscription
+	if err := s.db.WithContext(ctx).Where("application_id = ?", applicationID).Find(&webhooks).Error; err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	now := time.Now()
+	for _, webhook := range webhooks {
+		if !webhookSubscribes(webhook, event) {
+			continue
+		}
+		delivery := models.WebhookDelivery{
+			DeliveryID:  "whd_" + uuid.New().String(),
+			WebhookID:   webhook.WebhookID,
+			Event:       event,
+			Status:      models.WebhookDeliveryStatusPending,
+			MaxAttempts: webhookDeliveryMaxAttempts,
+			NextRetryAt: now,
+		}
+		payload, err := json.Marshal(models.WebhookPayload{
+			DeliveryID:    delivery.DeliveryID,
+			Event:         event,
+			ApplicationID: applicationID,
+			OccurredAt:    now.UTC().Format(time.RFC3339),
+			Data:          data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook payload: %w", err)
+		}
+		delivery.Payload = string(payload)
+		if err := s.db.WithContext(ctx).Create(&delivery).Error; err != nil {
+			return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// ProcessDueDeliveries sends up to limit pending deliveries whose retry time has passed and returns
+// how many were sent. Each delivery is claimed with a lease first, so several replicas can run
+// workers against the same table.
+func (s *WebhookService) ProcessDueDeliveries(ctx context.Context, limit int) (int, error) {
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	err := s.db.WithContext(ctx).Where("status = ? AND next_retry_at <= ?", models.WebhookDeliveryStatusPending, now).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due webhook deliveries: %w", err)
+	}
+
+	processed := 0
+	for i := range deliveries {
+		claimed, err := s.claim(ctx, &deliveries[i], now)
+		if err != nil {
+			return processed, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.run(ctx, &deliveries[i]); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// claim pushes the delivery's retry time past the lease, unless another worker already did
+func (s *WebhookService) claim(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (bool, error) {
+	leaseUntil := now.Add(webhookDeliveryLease)
+	result := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("delivery_id = ? AND status = ? AND attempts = ? AND next_retry_at = ?",
+			delivery.DeliveryID, models.WebhookDeliveryStatusPending, delivery.Attempts, delivery.NextRetryAt).
+		Updates(map[string]interface{}{"next_retry_at": leaseUntil, "updated_at": now})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim webhook delivery %s: %w", delivery.DeliveryID, result.Error)
+	}
+	delivery.NextRetryAt = leaseUntil
+	return result.RowsAffected == 1, nil
+}
+
+// run sends the delivery and records the result
+func (s *WebhookService) run(ctx context.Context, delivery *models.WebhookDelivery) error {
+	responseStatus, sendErr := s.send(ctx, delivery)
+	now := time.Now()
+	attempts := delivery.Attempts + 1
+
+	updates := map[string]interface{}{"attempts": attempts, "response_status": responseStatus, "updated_at": now}
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.WebhookDeliveryStatusDelivered
+		updates["delivered_at"] = now
+		updates["last_error"] = nil
+	case attempts >= delivery.MaxAttempts:
+		updates["status"] = models.WebhookDeliveryStatusDead
+		updates["last_error"] = sendErr.Error()
+		slog.Error("Webhook delivery dead-lettered", "deliveryID", delivery.DeliveryID, "webhookID", delivery.WebhookID, "attempts", attempts, "error", sendErr)
+	default:
+		updates["next_retry_at"] = now.Add(webhookBackoff(attempts))
+		updates["last_error"] = sendErr.Error()
+		slog.Warn("Webhook delivery failed, will retry", "deliveryID", delivery.DeliveryID, "webhookID", delivery.WebhookID, "attempts", attempts, "error", sendErr)
+	}
+
+	if err := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("delivery_id = ?", delivery.DeliveryID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to record webhook delivery %s result: %w", delivery.DeliveryID, err)
+	}
+	return nil
+}
+
+// send POSTs the signed payload to the webhook's URL and returns the response status, if any
+func (s *WebhookService) send(ctx context.Context, delivery *models.WebhookDelivery) (*int, error) {
+	var webhook models.WebhookSubscription
+	if err := s.db.WithContext(ctx).First(&webhook, "webhook_id = ?", delivery.WebhookID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(WebhookDeliveryHeader, delivery.DeliveryID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(webhook.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		return &status, fmt.Errorf("webhook endpoint responded with status %d", status)
+	}
+	return &status, nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 signature of a webhook request, which receivers
+// recompute from the timestamp header and raw body to verify it
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns the delay before the next attempt after the given number of failed attempts
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// webhookSubscribes reports whether a webhook receives event
+func webhookSubscribes(webhook models.WebhookSubscription, event models.WebhookEvent) bool {
+	for _, subscribed := range webhookEventsOf(webhook) {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookEventsOf(webhook models.WebhookSubscription) []models.WebhookEvent {
+	var events []models.WebhookEvent
+	if err := json.Unmarshal([]byte(webhook.Events), &events); err != nil {
+		slog.Warn("Invalid webhook events", "webhookID", webhook.WebhookID, "error", err)
+	}
+	return events
+}
+
+// newWebhookSecret returns a random webhook signing secret
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func webhookResponseOf(webhook models.WebhookSubscription) models.WebhookResponse {
+	return models.WebhookResponse{
+		WebhookID:     webhook.WebhookID,
+		ApplicationID: webhook.ApplicationID,
+		URL:           webhook.URL,
+		Events:        webhookEventsOf(webhook),
+		CreatedBy:     webhook.CreatedBy,
+		CreatedAt:     webhook.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     webhook.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func webhookDeliveryResponseOf(delivery models.WebhookDelivery) models.WebhookDeliveryResponse {
+	response := models.WebhookDeliveryResponse{
+		DeliveryID:     delivery.DeliveryID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		MaxAttempts:    delivery.MaxAttempts,
+		NextRetryAt:    delivery.NextRetryAt.Format(time.RFC3339),
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      delivery.UpdatedAt.Format(time.RFC3339),
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt := delivery.DeliveredAt.Format(time.RFC3339)
+		response.DeliveredAt = &deliveredAt
+	}
+	return response
+}
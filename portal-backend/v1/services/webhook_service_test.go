@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService(t *testing.T) {
+	db := SetupSQLiteTestDB(t)
+	seedSoftDeleteData(t, db)
+	ctx := context.Background()
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	var requests []received
+	status := http.StatusOK
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, received{header: r.Header.Clone(), body: body})
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	// The endpoint is reached as example.com, which the test server's certificate is valid for; the
+	// delivery client itself refuses the server's loopback address
+	service := NewWebhookService(db)
+	service.HTTPClient = publicTestClient(server)
+	endpoint := "https://example.com/hooks"
+
+	t.Run("CreateWebhook validates the subscription", func(t *testing.T) {
+		for _, url := range []string{"ftp://example.com", "http://example.com/hooks", "https://127.0.0.1/hooks", "https://localhost/hooks",
+			"https://10.0.0.5/hooks", "https://169.254.169.254/latest/meta-data", "https://[::1]/hooks"} {
+			_, err := service.CreateWebhook(ctx, "app_1", "idp-consumer", &models.CreateWebhookRequest{URL: url, Events: models.WebhookEvents})
+			assert.ErrorIs(t, err, ErrInvalidWebhook, url)
+		}
+		_, err := service.CreateWebhook(ctx, "app_1", "idp-consumer", &models.CreateWebhookRequest{URL: endpoint})
+		assert.ErrorIs(t, err, ErrInvalidWebhook)
+		_, err = service.CreateWebhook(ctx, "app_1", "idp-consumer", &models.CreateWebhookRequest{URL: endpoint, Events: []models.WebhookEvent{"unknown"}})
+		assert.ErrorIs(t, err, ErrInvalidWebhook)
+	})
+
+	webhook, err := service.CreateWebhook(ctx, "app_1", "idp-consumer", &models.CreateWebhookRequest{
+		URL:    endpoint,
+		Events: []models.WebhookEvent{models.WebhookEventSchemaDeprecated, models.WebhookEventCredentialExpiring, models.WebhookEventSchemaDeprecated},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, webhook.Secret)
+	assert.Equal(t, []models.WebhookEvent{models.WebhookEventSchemaDeprecated, models.WebhookEventCredentialExpiring}, webhook.Events)
+
+	webhooks, err := service.ListWebhooks(ctx, "app_1")
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Empty(t, webhooks[0].Secret, "the secret is only returned on creation")
+
+	t.Run("Events are signed and delivered to subscribed webhooks", func(t *testing.T) {
+		require.NoError(t, service.EnqueueSchemaDeprecated(ctx, "sch_1"))
+		require.NoError(t, service.EnqueueSubmissionStatusChanged(ctx, models.SubmissionTypeApplication, "sub_app", "pending", "approved"))
+
+		processed, err := service.ProcessDueDeliveries(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed, "the webhook is not subscribed to submission status changes")
+		require.Len(t, requests, 1)
+
+		request := requests[0]
+		assert.Equal(t, string(models.WebhookEventSchemaDeprecated), request.header.Get(WebhookEventHeader))
+		assert.Equal(t, "sha256="+SignWebhookPayload(webhook.Secret, request.header.Get(WebhookTimestampHeader), request.body),
+			request.header.Get(WebhookSignatureHeader))
+
+		var payload struct {
+			models.WebhookPayload
+			Data models.SchemaDeprecatedWebhookData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(request.body, &payload))
+		assert.Equal(t, "app_1", payload.ApplicationID)
+		assert.Equal(t, request.header.Get(WebhookDeliveryHeader), payload.DeliveryID)
+		assert.Equal(t, []string{"person.name"}, payload.Data.Fields)
+		assert.Equal(t, string(models.ActiveVersion), payload.Data.Version)
+
+		deliveries, err := service.ListDeliveries(ctx, "app_1", webhook.WebhookID, "", 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, models.WebhookDeliveryStatusDelivered, deliveries[0].Status)
+		require.NotNil(t, deliveries[0].ResponseStatus)
+		assert.Equal(t, http.StatusOK, *deliveries[0].ResponseStatus)
+	})
+
+	t.Run("Failed deliveries are retried, then dead-lettered", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		require.NoError(t, service.EnqueueCredentialExpiring(ctx, "app_1", "cred_1", time.Now().Add(time.Hour)))
+
+		_, err := service.ProcessDueDeliveries(ctx, 10)
+		require.NoError(t, err)
+		deliveries, err := service.ListDeliveries(ctx, "app_1", webhook.WebhookID, string(models.WebhookDeliveryStatusPending), 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Contains(t, *deliveries[0].LastError, "status 500")
+
+		// Make the retry due and the next attempt the last one
+		require.NoError(t, db.Model(&models.WebhookDelivery{}).Where("delivery_id = ?", deliveries[0].DeliveryID).
+			Updates(map[string]interface{}{"next_retry_at": time.Now().Add(-time.Second), "max_attempts": 2}).Error)
+		_, err = service.ProcessDueDeliveries(ctx, 10)
+		require.NoError(t, err)
+		deliveries, err = service.ListDeliveries(ctx, "app_1", webhook.WebhookID, string(models.WebhookDeliveryStatusDead), 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+	})
+
+	t.Run("DeleteWebhook", func(t *testing.T) {
+		_, err := service.ListDeliveries(ctx, "app_other", webhook.WebhookID, "", 0)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+		assert.ErrorIs(t, service.DeleteWebhook(ctx, "app_other", webhook.WebhookID), ErrResourceNotFound)
+
+		require.NoError(t, service.DeleteWebhook(ctx, "app_1", webhook.WebhookID))
+		var count int64
+		require.NoError(t, db.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhook.WebhookID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
+// publicTestClient is a client for user-chosen URLs connecting every request to server, whose
+// certificate it trusts
+func publicTestClient(server *httptest.Server) *http.Client {
+	client := server.Client()
+	transport := client.Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	client.Transport = transport
+	client.CheckRedirect = NewWebhookHTTPClient().CheckRedirect
+	return client
+}
+
+func TestNewWebhookHTTPClient(t *testing.T) {
+	var calls int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Redirect(w, r, "https://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+
+	t.Run("Private, loopback and link-local addresses are refused at connect time", func(t *testing.T) {
+		client := NewWebhookHTTPClient()
+		for _, url := range []string{server.URL, "https://localhost:1/hooks", "http://169.254.169.254/latest/meta-data", "http://10.0.0.5:1/hooks"} {
+			_, err := client.Post(url, "application/json", nil)
+			assert.ErrorIs(t, err, ErrWebhookAddressBlocked, url)
+		}
+		assert.Zero(t, calls)
+	})
+
+	t.Run("Redirects are not followed", func(t *testing.T) {
+		resp, err := publicTestClient(server).Post("https://example.com/hooks", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+
+	for ip, blocked := range map[string]bool{
+		"127.0.0.1": true, "10.1.2.3": true, "172.16.0.1": true, "192.168.1.1": true, "169.254.169.254": true,
+		"::1": true, "fe80::1": true, "fd00::1": true, "0.0.0.0": true, "::ffff:127.0.0.1": true,
+		"8.8.8.8": false, "2001:4860:4860::8888": false,
+	} {
+		assert.Equal(t, blocked, blockedIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookBackoff(1))
+	assert.Equal(t, 2*time.Minute, webhookBackoff(3))
+	assert.Equal(t, time.Hour, webhookBackoff(20))
+}
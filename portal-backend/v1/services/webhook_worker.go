@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultWebhookWorkerBatchSize is how many due deliveries the worker sends per poll
+const DefaultWebhookWorkerBatchSize = 50
+
+// WebhookWorker periodically sends queued webhook deliveries
+type WebhookWorker struct {
+	webhookService *WebhookService
+	// interval is how often the worker polls for due deliveries
+	interval time.Duration
+	// batchSize is the maximum number of deliveries sent per poll
+	batchSize int
+}
+
+// NewWebhookWorker creates a new webhook worker
+func NewWebhookWorker(webhookService *WebhookService, interval time.Duration) *WebhookWorker {
+	return &WebhookWorker{
+		webhookService: webhookService,
+		interval:       interval,
+		batchSize:      DefaultWebhookWorkerBatchSize,
+	}
+}
+
+// Start runs the delivery loop until the context is cancelled
+func (w *WebhookWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		slog.Info("Webhook worker disabled")
+		return
+	}
+
+	slog.Info("Webhook worker started", "interval", w.interval, "batchSize", w.batchSize)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Webhook worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce sends the deliveries that are due and returns how many were sent
+func (w *WebhookWorker) RunOnce(ctx context.Context) int {
+	processed, err := w.webhookService.ProcessDueDeliveries(ctx, w.batchSize)
+	if err != nil {
+		slog.Error("Failed to process webhook deliveries", "error", err)
+	}
+	if processed > 0 {
+		slog.Info("Processed webhook deliveries", "count", processed)
+	}
+	return processed
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 
@@ -16,6 +17,8 @@ type AuthContextKey string
 const (
 	AuthContextKeyUser AuthContextKey = "authenticated_user"
 	AuthContextKeyAuth AuthContextKey = "auth_context"
+	// AuthContextKeyImpersonation holds the impersonation session of a request made by an admin acting as a member
+	AuthContextKeyImpersonation AuthContextKey = "impersonation"
 )
 
 // ExtractBearerToken extracts the Bearer token from the Authorization header
@@ -75,6 +78,17 @@ func SetAuthContext(ctx context.Context, authCtx *models.AuthContext) context.Co
 	return context.WithValue(ctx, AuthContextKeyAuth, authCtx)
 }
 
+// GetImpersonation retrieves the impersonation session a request is made in, if any
+func GetImpersonation(ctx context.Context) (*models.Impersonation, bool) {
+	impersonation, ok := ctx.Value(AuthContextKeyImpersonation).(*models.Impersonation)
+	return impersonation, ok && impersonation != nil
+}
+
+// SetImpersonation sets the impersonation session in request context
+func SetImpersonation(ctx context.Context, impersonation *models.Impersonation) context.Context {
+	return context.WithValue(ctx, AuthContextKeyImpersonation, impersonation)
+}
+
 // RequireAuthentication is a helper that checks if a user is authenticated
 func RequireAuthentication(r *http.Request) (*models.AuthenticatedUser, error) {
 	return GetAuthenticatedUser(r.Context())
@@ -200,12 +214,32 @@ func GetRequestIP(r *http.Request) string {
 }
 
 // MatchesEndpoint checks if a request path matches an endpoint pattern
-// Supports wildcard matching with *
+// Supports wildcard matching with *: a trailing * matches any suffix and a /*/ segment matches
+// exactly one path segment, e.g. /api/v1/applications/*/credentials*
 func MatchesEndpoint(requestPath, endpointPattern string) bool {
 	if endpointPattern == requestPath {
 		return true
 	}
 
+	// Consume segment wildcards one at a time
+	for {
+		prefix, rest, found := strings.Cut(endpointPattern, "/*/")
+		if !found {
+			break
+		}
+		if !strings.HasPrefix(requestPath, prefix+"/") {
+			return false
+		}
+		segment, remainder, found := strings.Cut(strings.TrimPrefix(requestPath, prefix+"/"), "/")
+		if !found || segment == "" {
+			return false
+		}
+		endpointPattern, requestPath = rest, remainder
+	}
+	if endpointPattern == requestPath {
+		return true
+	}
+
 	// Handle wildcard patterns
 	if strings.HasSuffix(endpointPattern, "*") {
 		prefix := strings.TrimSuffix(endpointPattern, "*")
@@ -284,3 +318,79 @@ func ResetEndpointCacheForTesting() {
 	endpointCache = nil
 	initOnce = sync.Once{}
 }
+
+// FindRoutePolicy finds the first route policy matching the given HTTP method and path
+func FindRoutePolicy(method, path string) (*models.RoutePolicy, bool) {
+	for i := range models.RoutePolicies {
+		policy := &models.RoutePolicies[i]
+		if policy.Method == method && MatchesEndpoint(path, policy.Path) {
+			return policy, true
+		}
+	}
+	return nil, false
+}
+
+// MissingScopes returns the scopes required by the policy that the user's token was not granted
+func MissingScopes(user *models.AuthenticatedUser, policy *models.RoutePolicy) []string {
+	var missing []string
+	for _, scope := range policy.Scopes {
+		if !user.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// reportRoles is the order in which roles are listed in the route permission report
+var reportRoles = []models.Role{models.RoleAdmin, models.RoleMember, models.RoleSystem}
+
+// RoutePermissionReport lists the routes of the authorization tables with the roles and scopes
+// that can call them. Endpoint permissions come first, in table order, followed by the route
+// policies that have no endpoint permission with the same method and path.
+func RoutePermissionReport() []models.RouteAccess {
+	report := make([]models.RouteAccess, 0, len(models.EndpointPermissions)+len(models.RoutePolicies))
+	listed := make(map[string]bool, len(models.EndpointPermissions))
+
+	for _, ep := range models.EndpointPermissions {
+		listed[ep.Method+":"+ep.Path] = true
+		access := models.RouteAccess{
+			Method:            ep.Method,
+			Path:              ep.Path,
+			Permission:        ep.Permission,
+			OwnershipRequired: ep.IsOwnershipRequired,
+			Roles:             []models.Role{},
+		}
+		// A wildcard endpoint is covered by a policy whose pattern matches it
+		policy, hasPolicy := FindRoutePolicy(ep.Method, ep.Path)
+		for _, role := range reportRoles {
+			if !role.HasPermission(ep.Permission) {
+				continue
+			}
+			if hasPolicy && len(policy.Roles) > 0 && !slices.Contains(policy.Roles, role) {
+				continue
+			}
+			access.Roles = append(access.Roles, role)
+		}
+		if hasPolicy {
+			access.Scopes = policy.Scopes
+		}
+		report = append(report, access)
+	}
+
+	for _, policy := range models.RoutePolicies {
+		if listed[policy.Method+":"+policy.Path] {
+			continue
+		}
+		roles := policy.Roles
+		if len(roles) == 0 {
+			roles = reportRoles
+		}
+		report = append(report, models.RouteAccess{
+			Method: policy.Method,
+			Path:   policy.Path,
+			Roles:  roles,
+			Scopes: policy.Scopes,
+		})
+	}
+	return report
+}
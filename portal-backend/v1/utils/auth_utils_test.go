@@ -50,6 +50,70 @@ func TestFindEndpointPermission(t *testing.T) {
 			expectedPerm:  models.PermissionUpdateApplication,
 			expectedOwner: true,
 		},
+		{
+			name:          "Segment wildcard match - POST rotate application credential",
+			method:        "POST",
+			path:          "/api/v1/applications/abcd-efgh/credentials/cred_1/rotate",
+			expectedFound: true,
+			expectedPerm:  models.PermissionManageApplicationCredentials,
+			expectedOwner: true,
+		},
+		{
+			name:          "Wildcard match - POST restore application is not a credential endpoint",
+			method:        "POST",
+			path:          "/api/v1/applications/abcd-efgh/restore",
+			expectedFound: true,
+			expectedPerm:  models.PermissionRestoreApplication,
+			expectedOwner: false,
+		},
+		{
+			name:          "Segment wildcard match - POST schema submission review decision",
+			method:        "POST",
+			path:          "/api/v1/schema-submissions/sub_1/review/decisions",
+			expectedFound: true,
+			expectedPerm:  models.PermissionReviewSubmission,
+			expectedOwner: false,
+		},
+		{
+			name:          "Segment wildcard match - PUT application submission step reviewers",
+			method:        "PUT",
+			path:          "/api/v1/application-submissions/sub_1/review/steps/security_review/reviewers",
+			expectedFound: true,
+			expectedPerm:  models.PermissionReviewSubmission,
+			expectedOwner: false,
+		},
+		{
+			name:          "Segment wildcard match - POST application submission comment",
+			method:        "POST",
+			path:          "/api/v1/application-submissions/sub_1/comments",
+			expectedFound: true,
+			expectedPerm:  models.PermissionCommentSubmission,
+			expectedOwner: true,
+		},
+		{
+			name:          "Wildcard match - PUT notification preferences",
+			method:        "PUT",
+			path:          "/api/v1/notifications/preferences",
+			expectedFound: true,
+			expectedPerm:  models.PermissionUpdateNotificationPreferences,
+			expectedOwner: false,
+		},
+		{
+			name:          "Wildcard match - DELETE invitation",
+			method:        "DELETE",
+			path:          "/api/v1/invitations/inv_1",
+			expectedFound: true,
+			expectedPerm:  models.PermissionRevokeInvitation,
+			expectedOwner: false,
+		},
+		{
+			name:          "Segment wildcard match - PUT organization member",
+			method:        "PUT",
+			path:          "/api/v1/organizations/org_1/members/mem_1",
+			expectedFound: true,
+			expectedPerm:  models.PermissionManageOrganizationMember,
+			expectedOwner: true,
+		},
 		{
 			name:          "No match - unknown endpoint",
 			method:        "GET",
@@ -118,6 +182,32 @@ func TestFindEndpointPermissionCacheInitialization(t *testing.T) {
 	}
 }
 
+func TestMatchesEndpoint(t *testing.T) {
+	tests := []struct {
+		path    string
+		pattern string
+		want    bool
+	}{
+		{"/api/v1/schemas", "/api/v1/schemas", true},
+		{"/api/v1/schemas/123", "/api/v1/schemas/*", true},
+		{"/api/v1/schemas", "/api/v1/schemas/*", false},
+		{"/api/v1/applications/app-1/credentials", "/api/v1/applications/*/credentials*", true},
+		{"/api/v1/applications/app-1/credentials/cred_1/rotate", "/api/v1/applications/*/credentials*", true},
+		{"/api/v1/applications/app-1/credentials", "/api/v1/applications/*/credentials", true},
+		{"/api/v1/applications/app-1", "/api/v1/applications/*/credentials*", false},
+		{"/api/v1/applications/app-1/restore", "/api/v1/applications/*/credentials*", false},
+		{"/api/v1/applications//credentials", "/api/v1/applications/*/credentials*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.pattern, func(t *testing.T) {
+			if got := MatchesEndpoint(tt.path, tt.pattern); got != tt.want {
+				t.Errorf("MatchesEndpoint(%q, %q) = %v, want %v", tt.path, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
 func BenchmarkFindEndpointPermission(b *testing.B) {
 	// Reset cache to test initialization
 	endpointCache = nil
@@ -142,3 +232,75 @@ func BenchmarkFindEndpointPermissionCached(b *testing.B) {
 		FindEndpointPermission("GET", "/api/v1/schemas/12345")
 	}
 }
+
+func TestFindRoutePolicy(t *testing.T) {
+	policy, found := FindRoutePolicy("POST", "/api/v1/admin/bulk/import-members")
+	if !found {
+		t.Fatal("Expected a policy for admin bulk endpoint")
+	}
+	if len(policy.Roles) != 1 || policy.Roles[0] != models.RoleAdmin {
+		t.Errorf("Expected admin bulk endpoint to require the admin role, got %v", policy.Roles)
+	}
+
+	if _, found := FindRoutePolicy("GET", "/api/v1/schemas"); found {
+		t.Error("Expected no policy for schema endpoint")
+	}
+	if _, found := FindRoutePolicy("PATCH", "/api/v1/admin/graphql"); found {
+		t.Error("Expected no policy for a method without one")
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	user := &models.AuthenticatedUser{Scopes: []string{"openid", "portal:read"}}
+	policy := &models.RoutePolicy{Scopes: []string{"portal:read", "portal:write"}}
+
+	missing := MissingScopes(user, policy)
+	if len(missing) != 1 || missing[0] != "portal:write" {
+		t.Errorf("Expected portal:write to be missing, got %v", missing)
+	}
+	if missing := MissingScopes(user, &models.RoutePolicy{}); len(missing) != 0 {
+		t.Errorf("Expected no missing scopes for a policy without scopes, got %v", missing)
+	}
+}
+
+func TestRoutePermissionReport(t *testing.T) {
+	original := models.RoutePolicies
+	defer func() { models.RoutePolicies = original }()
+	models.RoutePolicies = []models.RoutePolicy{
+		{Method: "GET", Path: "/api/v1/schemas", Scopes: []string{"portal:read"}},
+		{Method: "GET", Path: "/api/v1/admin/*", Roles: []models.Role{models.RoleAdmin}},
+		{Method: "POST", Path: "/api/v1/admin/*", Roles: []models.Role{models.RoleAdmin}},
+		{Method: "GET", Path: "/api/v1/reports"},
+	}
+
+	report := RoutePermissionReport()
+	routes := make(map[string]models.RouteAccess, len(report))
+	for _, route := range report {
+		routes[route.Method+" "+route.Path] = route
+	}
+	if len(report) != len(models.EndpointPermissions)+3 {
+		t.Errorf("Expected every endpoint permission and the 3 policy-only routes, got %d routes", len(report))
+	}
+
+	schemas := routes["GET /api/v1/schemas"]
+	if schemas.Permission != models.PermissionReadSchema || len(schemas.Roles) != 3 {
+		t.Errorf("Expected schema reads by every role, got %+v", schemas)
+	}
+	if len(schemas.Scopes) != 1 || schemas.Scopes[0] != "portal:read" {
+		t.Errorf("Expected schema reads to require portal:read, got %v", schemas.Scopes)
+	}
+
+	// A wildcard endpoint takes the roles of the policy covering it
+	graph := routes["POST /api/v1/admin/graphql"]
+	if len(graph.Roles) != 1 || graph.Roles[0] != models.RoleAdmin {
+		t.Errorf("Expected admin GraphQL queries by admins only, got %v", graph.Roles)
+	}
+
+	admin, found := routes["GET /api/v1/admin/*"]
+	if !found || admin.Permission != "" || len(admin.Roles) != 1 {
+		t.Errorf("Expected the admin policy to be listed without a permission, got %+v", admin)
+	}
+	if reports := routes["GET /api/v1/reports"]; len(reports.Roles) != 3 {
+		t.Errorf("Expected a policy without roles to allow every role, got %v", reports.Roles)
+	}
+}
@@ -63,9 +63,10 @@ func (h *GraphQLHandler) createRecordFromField(fieldPath string, field *ast.Fiel
 	description := h.getDirectiveValue(field.Directives, "description", "value")
 	isOwnerValue := h.getDirectiveValue(field.Directives, "isOwner", "value")
 	ownerValue := h.getDirectiveValue(field.Directives, "owner", "value")
+	classification := h.getDirectiveValue(field.Directives, "classification", "level")
 
 	// Skip if no relevant directives found
-	if accessControlType == "" && sourceValue == "" {
+	if accessControlType == "" && sourceValue == "" && classification == "" {
 		return nil
 	}
 
@@ -90,13 +91,19 @@ func (h *GraphQLHandler) createRecordFromField(fieldPath string, field *ast.Fiel
 		record.Source = models.SourceFallback // default
 	}
 
-	// Set access control type (default to "public" if not specified)
+	// Set access control type (default to "public" if not specified).
+	// Classified fields without one are left empty so the PDP applies the tier default.
 	if accessControlType != "" {
 		record.AccessControlType = models.AccessControlType(accessControlType)
-	} else {
+	} else if classification == "" {
 		record.AccessControlType = models.AccessControlTypePublic // default
 	}
 
+	// Set classification tier (the PDP defaults to "personal" if not specified)
+	if classification != "" {
+		record.Classification = models.Classification(classification)
+	}
+
 	// Set isOwner (default to false)
 	record.IsOwner = false
 	if isOwnerValue == "true" {
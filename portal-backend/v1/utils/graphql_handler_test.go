@@ -281,3 +281,36 @@ func TestGraphQLHandler_ParseSDLToPolicyRequest_NoDirectives(t *testing.T) {
 	// Should have no records since no directives are present
 	assert.Equal(t, 0, len(request.Records))
 }
+
+func TestGraphQLHandler_ParseSDLToPolicyRequest_WithClassification(t *testing.T) {
+	handler := NewGraphQLHandler()
+
+	sdl := `
+	directive @accessControl(type: String) on FIELD_DEFINITION
+	directive @classification(level: String) on FIELD_DEFINITION
+
+	type User {
+	  id: ID! @accessControl(type: "public")
+	  medicalHistory: String @classification(level: "sensitive-personal")
+	}
+
+	type Query {
+	  getUser(id: ID!): User
+	}
+	`
+
+	request, err := handler.ParseSDLToPolicyRequest("test-schema", sdl)
+	assert.NoError(t, err)
+
+	records := make(map[string]models.PolicyMetadataCreateRequestRecord)
+	for _, record := range request.Records {
+		records[record.FieldName] = record
+	}
+
+	assert.Equal(t, models.AccessControlTypePublic, records["user.id"].AccessControlType)
+	assert.Empty(t, records["user.id"].Classification)
+
+	// Classified fields leave the access control type to the PDP tier default
+	assert.Equal(t, models.ClassificationSensitivePersonal, records["user.medicalHistory"].Classification)
+	assert.Empty(t, records["user.medicalHistory"].AccessControlType)
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// IntrospectionQuery asks a GraphQL endpoint for every type it serves, with the fields, arguments,
+// input fields, enum values and union members DiffIntrospection compares
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    types {
+      kind
+      name
+      fields(includeDeprecated: true) {
+        name
+        args { name type { ...TypeRef } defaultValue }
+        type { ...TypeRef }
+      }
+      inputFields { name type { ...TypeRef } defaultValue }
+      enumValues(includeDeprecated: true) { name }
+      possibleTypes { name }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } }
+}`
+
+// introspectionSchema is the __schema field of an IntrospectionQuery result
+type introspectionSchema struct {
+	Types []introspectionType `json:"types"`
+}
+
+type introspectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	EnumValues    []struct{ Name string }   `json:"enumValues"`
+	PossibleTypes []struct{ Name string }   `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name string                    `json:"name"`
+	Args []introspectionInputValue `json:"args"`
+	Type introspectionTypeRef      `json:"type"`
+}
+
+type introspectionInputValue struct {
+	Name         string               `json:"name"`
+	Type         introspectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+// builtInScalars are served by every GraphQL endpoint, so submitted SDL does not declare them
+var builtInScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+// DiffIntrospection compares a submitted SDL with the schema a live endpoint reports through the
+// __schema field of an IntrospectionQuery result. Types and fields the endpoint serves beyond the
+// SDL are reported as additions; the diff is breaking when a query valid against the SDL may fail
+// against the endpoint.
+func DiffIntrospection(sdl string, schema json.RawMessage) (*models.SchemaDiff, error) {
+	submittedTypes, err := parseSDLTypes(sdl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse submitted SDL: %w", err)
+	}
+	var introspection introspectionSchema
+	if err := json.Unmarshal(schema, &introspection); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection result: %w", err)
+	}
+
+	liveTypes := make(map[string]*ast.Definition, len(introspection.Types))
+	for _, t := range introspection.Types {
+		if strings.HasPrefix(t.Name, "__") || builtInScalars[t.Name] {
+			continue
+		}
+		liveTypes[t.Name] = t.definition()
+	}
+	return diffSchemaTypes(submittedTypes, liveTypes), nil
+}
+
+// definition converts an introspected type to the AST definition the SDL parser would produce
+func (t introspectionType) definition() *ast.Definition {
+	def := &ast.Definition{Kind: ast.DefinitionKind(t.Kind), Name: t.Name}
+	for _, field := range t.Fields {
+		var arguments ast.ArgumentDefinitionList
+		for _, arg := range field.Args {
+			arguments = append(arguments, &ast.ArgumentDefinition{
+				Name: arg.Name, Type: arg.Type.astType(), DefaultValue: arg.defaultValue(),
+			})
+		}
+		def.Fields = append(def.Fields, &ast.FieldDefinition{Name: field.Name, Type: field.Type.astType(), Arguments: arguments})
+	}
+	for _, value := range t.InputFields {
+		def.Fields = append(def.Fields, &ast.FieldDefinition{Name: value.Name, Type: value.Type.astType(), DefaultValue: value.defaultValue()})
+	}
+	for _, value := range t.EnumValues {
+		def.EnumValues = append(def.EnumValues, &ast.EnumValueDefinition{Name: value.Name})
+	}
+	// Interfaces list their implementations as possible types, but only union members are diffed
+	if def.Kind == ast.Union {
+		for _, member := range t.PossibleTypes {
+			def.Types = append(def.Types, member.Name)
+		}
+	}
+	return def
+}
+
+// defaultValue returns the introspected default value, which is only compared for presence
+func (v introspectionInputValue) defaultValue() *ast.Value {
+	if v.DefaultValue == nil {
+		return nil
+	}
+	return &ast.Value{Raw: *v.DefaultValue}
+}
+
+// astType converts an introspected type reference such as NON_NULL(LIST(String)) to [String]!
+func (r introspectionTypeRef) astType() *ast.Type {
+	switch r.Kind {
+	case "NON_NULL":
+		if r.OfType == nil {
+			return ast.NamedType("", nil)
+		}
+		inner := r.OfType.astType()
+		inner.NonNull = true
+		return inner
+	case "LIST":
+		if r.OfType == nil {
+			return ast.ListType(ast.NamedType("", nil), nil)
+		}
+		return ast.ListType(r.OfType.astType(), nil)
+	}
+	return ast.NamedType(r.Name, nil)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liveSchema is the __schema an endpoint serving the SDL below reports, plus a Person.email field
+const liveSchema = `{"types": [
+	{"kind": "OBJECT", "name": "Query", "fields": [
+		{"name": "person", "args": [{"name": "nic", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}],
+		 "type": {"kind": "OBJECT", "name": "Person"}}
+	]},
+	{"kind": "OBJECT", "name": "Person", "fields": [
+		{"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}},
+		{"name": "aliases", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "SCALAR", "name": "String"}}}},
+		{"name": "email", "args": [], "type": {"kind": "SCALAR", "name": "String"}}
+	]},
+	{"kind": "ENUM", "name": "Status", "enumValues": [{"name": "ACTIVE"}, {"name": "INACTIVE"}]},
+	{"kind": "UNION", "name": "Owner", "possibleTypes": [{"name": "Person"}]},
+	{"kind": "INPUT_OBJECT", "name": "Filter", "inputFields": [
+		{"name": "limit", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "Int"}}, "defaultValue": "10"}
+	]},
+	{"kind": "SCALAR", "name": "String"},
+	{"kind": "OBJECT", "name": "__Schema", "fields": []}
+]}`
+
+func TestDiffIntrospection_Matches(t *testing.T) {
+	sdl := `
+		type Query { person(nic: String!): Person }
+		type Person { name: String @accessControl(type: "public") aliases: [String]! }
+		enum Status { ACTIVE INACTIVE }
+		union Owner = Person
+		input Filter { limit: Int! = 10 }`
+
+	diff, err := DiffIntrospection(sdl, json.RawMessage(liveSchema))
+
+	require.NoError(t, err)
+	assert.False(t, diff.Breaking)
+	assert.Empty(t, diff.AddedTypes, "built-in and introspection types are ignored")
+	assert.Equal(t, []models.FieldChange{{Path: "Person.email", NewType: "String"}}, diff.AddedFields)
+}
+
+func TestDiffIntrospection_Mismatch(t *testing.T) {
+	sdl := `
+		type Query { person(nic: String!): Person vehicle: Vehicle }
+		type Person { name: String! aliases: [String]! }
+		type Vehicle { plate: String }
+		enum Status { ACTIVE INACTIVE SUSPENDED }`
+
+	diff, err := DiffIntrospection(sdl, json.RawMessage(liveSchema))
+
+	require.NoError(t, err)
+	assert.True(t, diff.Breaking)
+	assert.Equal(t, []models.TypeChange{{Name: "Vehicle", Kind: "OBJECT", Breaking: true}}, diff.RemovedTypes)
+	assert.Equal(t, []models.FieldChange{
+		{Path: "Query.vehicle", OldType: "Vehicle", Breaking: true},
+		{Path: "Status.SUSPENDED", Breaking: true},
+	}, diff.RemovedFields)
+	assert.Equal(t, []models.FieldChange{{Path: "Person.name", OldType: "String!", NewType: "String", Breaking: true}}, diff.ChangedFields)
+}
+
+func TestDiffIntrospection_InvalidInput(t *testing.T) {
+	_, err := DiffIntrospection(`type Query {`, json.RawMessage(liveSchema))
+	assert.Error(t, err)
+
+	_, err = DiffIntrospection(`type Query { name: String }`, json.RawMessage(`{"types": "none"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// DiffSDL compares two GraphQL SDL documents and reports the added, removed and changed types and
+// fields of newSDL relative to oldSDL. A change is flagged as breaking when a consumer query or
+// input that was valid against oldSDL may fail against newSDL.
+//
+// The documents are parsed but not validated, so SDL using provider directives such as
+// @accessControl does not need to declare them.
+func DiffSDL(oldSDL, newSDL string) (*models.SchemaDiff, error) {
+	oldTypes, err := parseSDLTypes(oldSDL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse previous SDL: %w", err)
+	}
+	newTypes, err := parseSDLTypes(newSDL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse submitted SDL: %w", err)
+	}
+	return diffSchemaTypes(oldTypes, newTypes), nil
+}
+
+// diffSchemaTypes compares two sets of named type definitions
+func diffSchemaTypes(oldTypes, newTypes map[string]*ast.Definition) *models.SchemaDiff {
+	diff := &models.SchemaDiff{
+		AddedTypes:    []models.TypeChange{},
+		RemovedTypes:  []models.TypeChange{},
+		ChangedTypes:  []models.TypeChange{},
+		AddedFields:   []models.FieldChange{},
+		RemovedFields: []models.FieldChange{},
+		ChangedFields: []models.FieldChange{},
+	}
+
+	for _, name := range sortedTypeNames(oldTypes) {
+		if _, ok := newTypes[name]; !ok {
+			diff.RemovedTypes = append(diff.RemovedTypes, models.TypeChange{Name: name, Kind: string(oldTypes[name].Kind), Breaking: true})
+		}
+	}
+	for _, name := range sortedTypeNames(newTypes) {
+		newDef := newTypes[name]
+		oldDef, ok := oldTypes[name]
+		if !ok {
+			diff.AddedTypes = append(diff.AddedTypes, models.TypeChange{Name: name, Kind: string(newDef.Kind)})
+			continue
+		}
+		if oldDef.Kind != newDef.Kind {
+			diff.ChangedTypes = append(diff.ChangedTypes, models.TypeChange{
+				Name: name, Kind: string(newDef.Kind), OldKind: string(oldDef.Kind), Breaking: true,
+			})
+			continue
+		}
+		diffDefinition(diff, oldDef, newDef)
+	}
+
+	for _, changes := range [][]models.TypeChange{diff.RemovedTypes, diff.ChangedTypes} {
+		if len(changes) > 0 {
+			diff.Breaking = true
+		}
+	}
+	for _, changes := range [][]models.FieldChange{diff.AddedFields, diff.RemovedFields, diff.ChangedFields} {
+		for _, change := range changes {
+			diff.Breaking = diff.Breaking || change.Breaking
+		}
+	}
+	return diff
+}
+
+// parseSDLTypes parses sdl and merges type extensions into their definitions
+func parseSDLTypes(sdl string) (map[string]*ast.Definition, error) {
+	doc, err := parser.ParseSchema(&ast.Source{Input: sdl})
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]*ast.Definition, len(doc.Definitions))
+	for _, def := range append(doc.Definitions, doc.Extensions...) {
+		existing, ok := types[def.Name]
+		if !ok {
+			copied := *def
+			types[def.Name] = &copied
+			continue
+		}
+		existing.Fields = append(existing.Fields, def.Fields...)
+		existing.EnumValues = append(existing.EnumValues, def.EnumValues...)
+		existing.Types = append(existing.Types, def.Types...)
+	}
+	return types, nil
+}
+
+// diffDefinition records the member changes between two definitions of the same kind
+func diffDefinition(diff *models.SchemaDiff, oldDef, newDef *ast.Definition) {
+	switch newDef.Kind {
+	case ast.Object, ast.Interface:
+		diffOutputFields(diff, newDef.Name, oldDef.Fields, newDef.Fields)
+	case ast.InputObject:
+		diffInputValues(diff, newDef.Name+".%s", oldDef.Fields, newDef.Fields)
+	case ast.Enum:
+		oldValues := make(map[string]bool, len(oldDef.EnumValues))
+		for _, value := range oldDef.EnumValues {
+			oldValues[value.Name] = true
+		}
+		newValues := make(map[string]bool, len(newDef.EnumValues))
+		for _, value := range newDef.EnumValues {
+			newValues[value.Name] = true
+			if !oldValues[value.Name] {
+				diff.AddedFields = append(diff.AddedFields, models.FieldChange{Path: newDef.Name + "." + value.Name})
+			}
+		}
+		for _, value := range oldDef.EnumValues {
+			if !newValues[value.Name] {
+				diff.RemovedFields = append(diff.RemovedFields, models.FieldChange{Path: newDef.Name + "." + value.Name, Breaking: true})
+			}
+		}
+	case ast.Union:
+		newMembers := make(map[string]bool, len(newDef.Types))
+		for _, member := range newDef.Types {
+			newMembers[member] = true
+		}
+		oldMembers := make(map[string]bool, len(oldDef.Types))
+		for _, member := range oldDef.Types {
+			oldMembers[member] = true
+			if !newMembers[member] {
+				diff.RemovedFields = append(diff.RemovedFields, models.FieldChange{Path: newDef.Name + "." + member, Breaking: true})
+			}
+		}
+		for _, member := range newDef.Types {
+			if !oldMembers[member] {
+				diff.AddedFields = append(diff.AddedFields, models.FieldChange{Path: newDef.Name + "." + member})
+			}
+		}
+	}
+}
+
+// diffOutputFields records the changes to the fields of an object or interface type and their arguments
+func diffOutputFields(diff *models.SchemaDiff, typeName string, oldFields, newFields ast.FieldList) {
+	for _, oldField := range oldFields {
+		if newFields.ForName(oldField.Name) == nil {
+			diff.RemovedFields = append(diff.RemovedFields, models.FieldChange{
+				Path: typeName + "." + oldField.Name, OldType: oldField.Type.String(), Breaking: true,
+			})
+		}
+	}
+	for _, newField := range newFields {
+		path := typeName + "." + newField.Name
+		oldField := oldFields.ForName(newField.Name)
+		if oldField == nil {
+			diff.AddedFields = append(diff.AddedFields, models.FieldChange{Path: path, NewType: newField.Type.String()})
+			continue
+		}
+		if oldType, newType := oldField.Type.String(), newField.Type.String(); oldType != newType {
+			diff.ChangedFields = append(diff.ChangedFields, models.FieldChange{
+				Path: path, OldType: oldType, NewType: newType,
+				// Output fields may only become stricter: a nullable field made non-null is safe
+				Breaking: !isNonNullOf(newField.Type, oldField.Type),
+			})
+		}
+		diffInputValues(diff, path+"(%s)", toInputValues(oldField.Arguments), toInputValues(newField.Arguments))
+	}
+}
+
+// diffInputValues records the changes to input object fields or field arguments. pathFormat
+// formats the name of a value into its path.
+func diffInputValues(diff *models.SchemaDiff, pathFormat string, oldValues, newValues ast.FieldList) {
+	for _, oldValue := range oldValues {
+		if newValues.ForName(oldValue.Name) == nil {
+			diff.RemovedFields = append(diff.RemovedFields, models.FieldChange{
+				Path: fmt.Sprintf(pathFormat, oldValue.Name), OldType: oldValue.Type.String(), Breaking: true,
+			})
+		}
+	}
+	for _, newValue := range newValues {
+		path := fmt.Sprintf(pathFormat, newValue.Name)
+		oldValue := oldValues.ForName(newValue.Name)
+		if oldValue == nil {
+			diff.AddedFields = append(diff.AddedFields, models.FieldChange{
+				Path: path, NewType: newValue.Type.String(),
+				// Existing callers do not send the new value, so it must be optional
+				Breaking: newValue.Type.NonNull && newValue.DefaultValue == nil,
+			})
+			continue
+		}
+		if oldType, newType := oldValue.Type.String(), newValue.Type.String(); oldType != newType {
+			diff.ChangedFields = append(diff.ChangedFields, models.FieldChange{
+				Path: path, OldType: oldType, NewType: newType,
+				// Inputs may only become looser: a non-null input made nullable is safe
+				Breaking: !isNonNullOf(oldValue.Type, newValue.Type),
+			})
+		}
+	}
+}
+
+// toInputValues converts field arguments to a field list so they can be diffed like input fields
+func toInputValues(arguments ast.ArgumentDefinitionList) ast.FieldList {
+	values := make(ast.FieldList, 0, len(arguments))
+	for _, argument := range arguments {
+		values = append(values, &ast.FieldDefinition{
+			Name:         argument.Name,
+			Type:         argument.Type,
+			DefaultValue: argument.DefaultValue,
+		})
+	}
+	return values
+}
+
+// isNonNullOf reports whether strict is the non-null form of loose, e.g. String! and String
+func isNonNullOf(strict, loose *ast.Type) bool {
+	if !strict.NonNull || loose.NonNull {
+		return false
+	}
+	nullable := *strict
+	nullable.NonNull = false
+	return nullable.String() == loose.String()
+}
+
+// sortedTypeNames returns the names of types in a stable order
+func sortedTypeNames(types map[string]*ast.Definition) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
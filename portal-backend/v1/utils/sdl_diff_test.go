@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSDL_NoChanges(t *testing.T) {
+	sdl := `type Query { person(nic: String!): Person } type Person { name: String @accessControl(type: "public") }`
+
+	diff, err := DiffSDL(sdl, sdl)
+
+	require.NoError(t, err)
+	assert.False(t, diff.HasChanges())
+	assert.False(t, diff.Breaking)
+}
+
+func TestDiffSDL_Types(t *testing.T) {
+	oldSDL := `
+		type Query { person: Person }
+		type Person { name: String }
+		type Vehicle { plate: String }
+		scalar Date`
+	newSDL := `
+		type Query { person: Person }
+		type Person { name: String }
+		type Address { city: String }
+		enum Date { TODAY }`
+
+	diff, err := DiffSDL(oldSDL, newSDL)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.TypeChange{{Name: "Address", Kind: "OBJECT"}}, diff.AddedTypes)
+	assert.Equal(t, []models.TypeChange{{Name: "Vehicle", Kind: "OBJECT", Breaking: true}}, diff.RemovedTypes)
+	assert.Equal(t, []models.TypeChange{{Name: "Date", Kind: "ENUM", OldKind: "SCALAR", Breaking: true}}, diff.ChangedTypes)
+	assert.True(t, diff.Breaking)
+}
+
+func TestDiffSDL_OutputFields(t *testing.T) {
+	oldSDL := `type Person { name: String age: Int nic: String! email: String }`
+	newSDL := `type Person { name: String! age: String nic: String address: String }`
+
+	diff, err := DiffSDL(oldSDL, newSDL)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{{Path: "Person.address", NewType: "String"}}, diff.AddedFields)
+	assert.Equal(t, []models.FieldChange{{Path: "Person.email", OldType: "String", Breaking: true}}, diff.RemovedFields)
+	assert.Equal(t, []models.FieldChange{
+		{Path: "Person.name", OldType: "String", NewType: "String!"},
+		{Path: "Person.age", OldType: "Int", NewType: "String", Breaking: true},
+		{Path: "Person.nic", OldType: "String!", NewType: "String", Breaking: true},
+	}, diff.ChangedFields)
+	assert.True(t, diff.Breaking)
+}
+
+func TestDiffSDL_ArgumentsAndInputs(t *testing.T) {
+	oldSDL := `
+		type Query { person(nic: String!): String }
+		input Filter { name: String! }`
+	newSDL := `
+		type Query { person(nic: String, year: Int!, limit: Int = 10): String }
+		input Filter { name: String city: String }`
+
+	diff, err := DiffSDL(oldSDL, newSDL)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{
+		{Path: "Filter.city", NewType: "String"},
+		{Path: "Query.person(year)", NewType: "Int!", Breaking: true},
+		{Path: "Query.person(limit)", NewType: "Int"},
+	}, diff.AddedFields)
+	assert.Equal(t, []models.FieldChange{
+		{Path: "Filter.name", OldType: "String!", NewType: "String"},
+		{Path: "Query.person(nic)", OldType: "String!", NewType: "String"},
+	}, diff.ChangedFields)
+	assert.True(t, diff.Breaking)
+}
+
+func TestDiffSDL_EnumValuesAndExtensions(t *testing.T) {
+	oldSDL := `enum Gender { MALE FEMALE OTHER } type Person { name: String }`
+	newSDL := `enum Gender { MALE FEMALE } type Person { name: String } extend type Person { age: Int }`
+
+	diff, err := DiffSDL(oldSDL, newSDL)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.FieldChange{{Path: "Person.age", NewType: "Int"}}, diff.AddedFields)
+	assert.Equal(t, []models.FieldChange{{Path: "Gender.OTHER", Breaking: true}}, diff.RemovedFields)
+}
+
+func TestDiffSDL_InvalidSDL(t *testing.T) {
+	_, err := DiffSDL(`type Query { name: String }`, `type Query {`)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "submitted SDL")
+}
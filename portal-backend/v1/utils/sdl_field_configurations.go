@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// sensitiveFieldNames are the words of a field name that suggest sensitive personal data, which
+// always requires consent
+var sensitiveFieldNames = []string{
+	"photo", "photograph", "image", "picture", "portrait", "biometric", "biometrics", "fingerprint",
+	"signature", "health", "medical", "blood", "disability", "religion", "ethnicity", "criminal",
+}
+
+// personalFieldNames are the words of a field name that suggest personal data about a citizen
+var personalFieldNames = []string{
+	"nic", "nid", "passport", "birth", "dob", "address", "phone", "mobile", "email", "name", "surname",
+	"gender", "sex", "marital", "spouse", "salary", "income", "license", "licence", "tin",
+}
+
+// SuggestFieldConfigurations parses a provider SDL and suggests a configuration for every field of
+// its object and interface types, with the same dot-notation paths as policy metadata. Fields with
+// an @accessControl or @classification directive are configured from their directives; the others
+// are classified by their name, e.g. a "nic" field is personal and a "photo" field is sensitive
+// personal data, and fields whose name suggests neither are public.
+//
+// Like DiffSDL, the SDL is parsed but not validated, so drafts get suggestions as soon as they parse.
+func SuggestFieldConfigurations(sdl string) (models.FieldConfigurations, error) {
+	return SuggestTemplateFieldConfigurations(sdl, nil)
+}
+
+// SuggestTemplateFieldConfigurations suggests field configurations like SuggestFieldConfigurations,
+// except that fields without policy directives that a rule of the template matches are configured by
+// the rule instead of by their name. A nil template suggests configurations from names only.
+func SuggestTemplateFieldConfigurations(sdl string, template *models.FieldConfigurationTemplate) (models.FieldConfigurations, error) {
+	types, err := parseSDLTypes(sdl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SDL: %w", err)
+	}
+
+	h := NewGraphQLHandler()
+	configurations := models.FieldConfigurations{}
+	seen := make(map[string]bool)
+	for _, typeName := range sortedTypeNames(types) {
+		def := types[typeName]
+		if !isFieldContainer(def) || h.isRootType(typeName) {
+			continue
+		}
+		visiting := map[string]bool{typeName: true}
+		configurations = suggestFields(h, types, template, strings.ToLower(typeName)+".", def, visiting, seen, configurations)
+	}
+	return configurations, nil
+}
+
+// suggestFields appends the configurations of the fields of def and of their nested object fields.
+// Types already on the path are not descended into again, so recursive types terminate.
+func suggestFields(h *GraphQLHandler, types map[string]*ast.Definition, template *models.FieldConfigurationTemplate,
+	basePath string, def *ast.Definition, visiting, seen map[string]bool, configurations models.FieldConfigurations) models.FieldConfigurations {
+	for _, field := range def.Fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		fieldPath := basePath + field.Name
+		if !seen[fieldPath] {
+			seen[fieldPath] = true
+			configurations = append(configurations, suggestField(h, template, fieldPath, field))
+		}
+
+		typeName := h.getBaseTypeName(field.Type)
+		if nested, ok := types[typeName]; ok && isFieldContainer(nested) && !visiting[typeName] {
+			visiting[typeName] = true
+			configurations = suggestFields(h, types, template, fieldPath+".", nested, visiting, seen, configurations)
+			delete(visiting, typeName)
+		}
+	}
+	return configurations
+}
+
+// suggestField suggests the configuration of one field from its directives, the template or its name
+func suggestField(h *GraphQLHandler, template *models.FieldConfigurationTemplate, fieldPath string, field *ast.FieldDefinition) models.FieldConfiguration {
+	configuration := models.FieldConfiguration{
+		FieldName: fieldPath,
+		Type:      field.Type.String(),
+		Source:    models.SourceFallback,
+	}
+	if displayName := h.getDirectiveValue(field.Directives, "displayName", "value"); displayName != "" {
+		configuration.DisplayName = &displayName
+	}
+	if description := h.getDirectiveValue(field.Directives, "description", "value"); description != "" {
+		configuration.Description = &description
+	} else if field.Description != "" {
+		description := field.Description
+		configuration.Description = &description
+	}
+	if source := h.getDirectiveValue(field.Directives, "source", "value"); source != "" {
+		configuration.Source = models.Source(source)
+	}
+	configuration.IsOwner = h.getDirectiveValue(field.Directives, "isOwner", "value") == "true"
+	if owner := h.getDirectiveValue(field.Directives, "owner", "value"); owner != "" {
+		value := models.Owner(owner)
+		configuration.Owner = &value
+	}
+
+	accessControlType := h.getDirectiveValue(field.Directives, "accessControl", "type")
+	classification := h.getDirectiveValue(field.Directives, "classification", "level")
+	rule := template.Match(fieldPath)
+	switch {
+	case accessControlType != "" || classification != "":
+		configuration.AccessControlType = models.AccessControlType(accessControlType)
+		configuration.Classification = models.Classification(classification)
+		configuration.Reason = "configured by the field's directives"
+	case rule != nil:
+		rule.Apply(&configuration)
+		configuration.Reason = fmt.Sprintf("configured by the %q template (rule %q)", template.Name, rule.Field)
+	case matchFieldName(field.Name, sensitiveFieldNames) != "":
+		suggestPersonal(&configuration, models.ClassificationSensitivePersonal)
+		configuration.Reason = fmt.Sprintf("name suggests sensitive personal data (%q)", matchFieldName(field.Name, sensitiveFieldNames))
+	case matchFieldName(field.Name, personalFieldNames) != "":
+		suggestPersonal(&configuration, models.ClassificationPersonal)
+		configuration.Reason = fmt.Sprintf("name suggests personal data (%q)", matchFieldName(field.Name, personalFieldNames))
+	default:
+		configuration.AccessControlType = models.AccessControlTypePublic
+		configuration.Classification = models.ClassificationPublic
+		configuration.Reason = "name does not suggest personal data"
+	}
+	configuration.ConsentRequired = configuration.RequiresConsent()
+	return configuration
+}
+
+// suggestPersonal classifies a field as citizen data that is restricted to consented access
+func suggestPersonal(configuration *models.FieldConfiguration, classification models.Classification) {
+	configuration.Classification = classification
+	configuration.AccessControlType = models.AccessControlTypeRestricted
+	if configuration.Owner == nil {
+		owner := models.OwnerCitizen
+		configuration.Owner = &owner
+	}
+}
+
+// matchFieldName returns the first of words that is a word of the camelCase or snake_case field
+// name, or "" if there is none
+func matchFieldName(fieldName string, words []string) string {
+	fieldWords := make(map[string]bool)
+	for _, word := range splitFieldName(fieldName) {
+		fieldWords[word] = true
+	}
+	for _, word := range words {
+		if fieldWords[word] {
+			return word
+		}
+	}
+	return ""
+}
+
+// splitFieldName splits a field name into lower-case words, e.g. "dateOfBirth" into "date", "of"
+// and "birth", and "photoURL" or "photo_url" into "photo" and "url"
+func splitFieldName(fieldName string) []string {
+	runes := []rune(fieldName)
+	var words []string
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			previousLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			acronymEnd := unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || acronymEnd {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+	return words
+}
+
+// ApplyFieldConfigurations returns the policy metadata records of an SDL with its field
+// configurations applied: a configuration replaces the record of its field, or adds one for a
+// field without policy directives. Configurations of fields that are no longer in the SDL are
+// ignored.
+func ApplyFieldConfigurations(records []models.PolicyMetadataCreateRequestRecord, sdl string,
+	configurations models.FieldConfigurations) ([]models.PolicyMetadataCreateRequestRecord, error) {
+	if len(configurations) == 0 {
+		return records, nil
+	}
+	current, err := SuggestFieldConfigurations(sdl)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]bool, len(current))
+	for _, configuration := range current {
+		fields[configuration.FieldName] = true
+	}
+
+	indexes := make(map[string]int, len(records))
+	for i, record := range records {
+		indexes[record.FieldName] = i
+	}
+	for i := range configurations {
+		configuration := &configurations[i]
+		if !fields[configuration.FieldName] {
+			continue
+		}
+		if index, ok := indexes[configuration.FieldName]; ok {
+			records[index] = configuration.PolicyRecord()
+			continue
+		}
+		indexes[configuration.FieldName] = len(records)
+		records = append(records, configuration.PolicyRecord())
+	}
+	return records, nil
+}
+
+// isFieldContainer reports whether a definition has output fields that are configured
+func isFieldContainer(def *ast.Definition) bool {
+	return def.Kind == ast.Object || def.Kind == ast.Interface
+}
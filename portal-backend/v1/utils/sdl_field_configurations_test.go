@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestFieldConfigurations(t *testing.T) {
+	sdl := `
+		type Query { person(nic: String!): Person }
+		type Person {
+			nic: String!
+			photoURL: String
+			fullName: String @accessControl(type: "public") @source(value: "primary")
+			vehicleCount: Int
+			address: Address
+		}
+		type Address { city: String resident: Person }
+		enum Colour { RED }`
+
+	configurations, err := SuggestFieldConfigurations(sdl)
+
+	require.NoError(t, err)
+	byName := make(map[string]models.FieldConfiguration)
+	var names []string
+	for _, configuration := range configurations {
+		byName[configuration.FieldName] = configuration
+		names = append(names, configuration.FieldName)
+	}
+	assert.Equal(t, []string{
+		"address.city", "address.resident", "address.resident.nic", "address.resident.photoURL",
+		"address.resident.fullName", "address.resident.vehicleCount", "address.resident.address",
+		"person.nic", "person.photoURL", "person.fullName", "person.vehicleCount", "person.address",
+		"person.address.city", "person.address.resident",
+	}, names)
+
+	nic := byName["person.nic"]
+	assert.Equal(t, "String!", nic.Type)
+	assert.Equal(t, models.ClassificationPersonal, nic.Classification)
+	assert.Equal(t, models.AccessControlTypeRestricted, nic.AccessControlType)
+	require.NotNil(t, nic.Owner)
+	assert.Equal(t, models.OwnerCitizen, *nic.Owner)
+	assert.True(t, nic.ConsentRequired)
+	assert.Equal(t, `name suggests personal data ("nic")`, nic.Reason)
+
+	photo := byName["person.photoURL"]
+	assert.Equal(t, models.ClassificationSensitivePersonal, photo.Classification)
+	assert.True(t, photo.ConsentRequired)
+
+	// Directives take precedence over the name
+	fullName := byName["person.fullName"]
+	assert.Equal(t, models.AccessControlTypePublic, fullName.AccessControlType)
+	assert.Equal(t, models.SourcePrimary, fullName.Source)
+	assert.Empty(t, fullName.Classification)
+	assert.False(t, fullName.ConsentRequired)
+
+	vehicleCount := byName["person.vehicleCount"]
+	assert.Equal(t, models.ClassificationPublic, vehicleCount.Classification)
+	assert.Equal(t, models.AccessControlTypePublic, vehicleCount.AccessControlType)
+	assert.Equal(t, models.SourceFallback, vehicleCount.Source)
+	assert.False(t, vehicleCount.ConsentRequired)
+}
+
+func TestSuggestFieldConfigurations_InvalidSDL(t *testing.T) {
+	_, err := SuggestFieldConfigurations(`type Person {`)
+	assert.Error(t, err)
+}
+
+func TestSplitFieldName(t *testing.T) {
+	assert.Equal(t, []string{"date", "of", "birth"}, splitFieldName("dateOfBirth"))
+	assert.Equal(t, []string{"photo", "url"}, splitFieldName("photoURL"))
+	assert.Equal(t, []string{"nic", "number"}, splitFieldName("NICNumber"))
+	assert.Equal(t, []string{"photo", "url"}, splitFieldName("photo_url"))
+	assert.Equal(t, []string{"address2", "line"}, splitFieldName("address2Line"))
+}
+
+func TestApplyFieldConfigurations(t *testing.T) {
+	sdl := `type Person { nic: String @accessControl(type: "public") name: String age: Int }`
+	records := []models.PolicyMetadataCreateRequestRecord{
+		{FieldName: "person.nic", Source: models.SourceFallback, AccessControlType: models.AccessControlTypePublic},
+	}
+	owner := models.OwnerCitizen
+	configurations := models.FieldConfigurations{
+		{FieldName: "person.nic", Source: models.SourcePrimary, AccessControlType: models.AccessControlTypeRestricted, Owner: &owner},
+		{FieldName: "person.name", Source: models.SourceFallback, Classification: models.ClassificationPersonal},
+		{FieldName: "person.removed", Source: models.SourceFallback},
+	}
+
+	applied, err := ApplyFieldConfigurations(records, sdl, configurations)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.PolicyMetadataCreateRequestRecord{
+		{FieldName: "person.nic", Source: models.SourcePrimary, AccessControlType: models.AccessControlTypeRestricted, Owner: &owner},
+		{FieldName: "person.name", Source: models.SourceFallback, Classification: models.ClassificationPersonal},
+	}, applied)
+
+	// Without configurations the records are unchanged
+	unchanged, err := ApplyFieldConfigurations(records[:1], `not an SDL`, nil)
+	require.NoError(t, err)
+	assert.Len(t, unchanged, 1)
+}
+
+func TestSuggestTemplateFieldConfigurations(t *testing.T) {
+	sdl := `
+		type Query { person(nic: String!): Person }
+		type Person {
+			nic: String!
+			birthDate: String
+			photo: String @classification(level: "public")
+			address: Address
+		}
+		type Address { city: String }`
+	template := &models.FieldConfigurationTemplate{
+		Name: "Person registry defaults",
+		Rules: models.FieldConfigurationRules{
+			{Field: "person.address.*", AccessControlType: models.AccessControlTypePublic, Classification: models.ClassificationInternal},
+			{Field: "*Date", AccessControlType: models.AccessControlTypeRestricted, Classification: models.ClassificationSensitivePersonal},
+			{Field: "photo", AccessControlType: models.AccessControlTypeRestricted, Classification: models.ClassificationSensitivePersonal},
+		},
+	}
+
+	configurations, err := SuggestTemplateFieldConfigurations(sdl, template)
+
+	require.NoError(t, err)
+	byName := make(map[string]models.FieldConfiguration)
+	for _, configuration := range configurations {
+		byName[configuration.FieldName] = configuration
+	}
+
+	// Rules match field names and, with a dot, field paths
+	birthDate := byName["person.birthDate"]
+	assert.Equal(t, models.ClassificationSensitivePersonal, birthDate.Classification)
+	assert.Equal(t, `configured by the "Person registry defaults" template (rule "*Date")`, birthDate.Reason)
+	assert.Equal(t, models.ClassificationInternal, byName["person.address.city"].Classification)
+	assert.Equal(t, models.ClassificationPublic, byName["address.city"].Classification)
+
+	// Fields no rule matches are suggested from their name, and directives take precedence
+	assert.Equal(t, `name suggests personal data ("nic")`, byName["person.nic"].Reason)
+	assert.Equal(t, models.ClassificationPublic, byName["person.photo"].Classification)
+}
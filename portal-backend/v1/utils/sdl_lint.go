@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+var (
+	pascalCase = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	camelCase  = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+)
+
+// LintSDL validates a provider SDL and checks it against the portal's conventions: types are
+// PascalCase, fields are camelCase, and every field has a description, given either as a GraphQL
+// description or with the @description directive. It returns no errors for an acceptable SDL.
+//
+// An SDL that is not a valid schema is reported as a single invalid-schema error, since the
+// convention checks need a parsed schema.
+func LintSDL(sdl string) []models.SDLLintError {
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "sdl", Input: sdl})
+	if err != nil {
+		return schemaErrors(err)
+	}
+
+	var lintErrors []models.SDLLintError
+	for _, name := range sortedTypeNames(schema.Types) {
+		def := schema.Types[name]
+		if def.BuiltIn {
+			continue
+		}
+		if !pascalCase.MatchString(def.Name) {
+			lintErrors = append(lintErrors, lintError(models.SDLLintRuleTypeName, def.Name, def.Position,
+				fmt.Sprintf("type %q must be PascalCase", def.Name)))
+		}
+		if def.Kind != ast.Object && def.Kind != ast.Interface && def.Kind != ast.InputObject {
+			continue
+		}
+		for _, field := range def.Fields {
+			// Introspection fields are added to the query type by the parser
+			if field.Position == nil || field.Position.Src.BuiltIn {
+				continue
+			}
+			path := def.Name + "." + field.Name
+			if !camelCase.MatchString(field.Name) {
+				lintErrors = append(lintErrors, lintError(models.SDLLintRuleFieldName, path, field.Position,
+					fmt.Sprintf("field %q must be camelCase", path)))
+			}
+			if field.Description == "" && field.Directives.ForName("description") == nil {
+				lintErrors = append(lintErrors, lintError(models.SDLLintRuleFieldDescription, path, field.Position,
+					fmt.Sprintf("field %q must have a description", path)))
+			}
+		}
+	}
+	return lintErrors
+}
+
+// schemaErrors converts a gqlparser parse or validation error to lint errors
+func schemaErrors(err error) []models.SDLLintError {
+	var list gqlerror.List
+	if !errors.As(err, &list) {
+		var single *gqlerror.Error
+		if !errors.As(err, &single) {
+			return []models.SDLLintError{{Rule: models.SDLLintRuleInvalidSchema, Message: err.Error()}}
+		}
+		list = gqlerror.List{single}
+	}
+
+	lintErrors := make([]models.SDLLintError, 0, len(list))
+	for _, gqlErr := range list {
+		lintError := models.SDLLintError{Rule: models.SDLLintRuleInvalidSchema, Message: gqlErr.Message}
+		if len(gqlErr.Locations) > 0 {
+			lintError.Line = gqlErr.Locations[0].Line
+			lintError.Column = gqlErr.Locations[0].Column
+		}
+		lintErrors = append(lintErrors, lintError)
+	}
+	return lintErrors
+}
+
+// lintError builds a lint error located at position
+func lintError(rule models.SDLLintRule, path string, position *ast.Position, message string) models.SDLLintError {
+	lintError := models.SDLLintError{Rule: rule, Message: message, Path: path}
+	if position != nil {
+		lintError.Line = position.Line
+		lintError.Column = position.Column
+	}
+	return lintError
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/gov-dx-sandbox/portal-backend/v1/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintSDL_Valid(t *testing.T) {
+	sdl := `
+directive @description(value: String) on FIELD_DEFINITION
+
+type Query {
+	"Look up a person by NIC"
+	person(nic: String!): Person
+}
+
+type Person {
+	fullName: String @description(value: "Full name")
+	"""Date of birth"""
+	birthDate: String
+}
+
+input PersonFilter {
+	"Name prefix"
+	namePrefix: String
+}`
+
+	assert.Empty(t, LintSDL(sdl))
+}
+
+func TestLintSDL_Conventions(t *testing.T) {
+	sdl := `
+type Query {
+	"Look up a person"
+	person: person_record
+}
+
+type person_record {
+	"Full name"
+	FullName: String
+	birthDate: String
+}`
+
+	lintErrors := LintSDL(sdl)
+
+	assert.Equal(t, []models.SDLLintError{
+		{Rule: models.SDLLintRuleTypeName, Message: `type "person_record" must be PascalCase`, Path: "person_record", Line: 7, Column: 6},
+		{Rule: models.SDLLintRuleFieldName, Message: `field "person_record.FullName" must be camelCase`, Path: "person_record.FullName", Line: 8, Column: 3},
+		{Rule: models.SDLLintRuleFieldDescription, Message: `field "person_record.birthDate" must have a description`, Path: "person_record.birthDate", Line: 10, Column: 2},
+	}, lintErrors)
+}
+
+func TestLintSDL_InvalidSchema(t *testing.T) {
+	t.Run("Syntax", func(t *testing.T) {
+		lintErrors := LintSDL(`type Query { name: String`)
+
+		require.Len(t, lintErrors, 1)
+		assert.Equal(t, models.SDLLintRuleInvalidSchema, lintErrors[0].Rule)
+		assert.Equal(t, 1, lintErrors[0].Line)
+	})
+
+	t.Run("UndefinedType", func(t *testing.T) {
+		lintErrors := LintSDL(`type Query { "Person" person: Person }`)
+
+		require.Len(t, lintErrors, 1)
+		assert.Equal(t, models.SDLLintRuleInvalidSchema, lintErrors[0].Rule)
+		assert.Contains(t, lintErrors[0].Message, "Person")
+	})
+}
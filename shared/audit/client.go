@@ -3,14 +3,21 @@ package audit
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/gov-dx-sandbox/shared/requestid"
 )
 
 const (
@@ -18,22 +25,98 @@ const (
 	AuditLogsEndpoint = "/api/audit-logs"
 	// DefaultHTTPTimeout is the default timeout for HTTP requests to the audit service
 	DefaultHTTPTimeout = 10 * time.Second
+
+	// DefaultBufferSize is the default number of events held in memory awaiting delivery
+	DefaultBufferSize = 1000
+	// DefaultBatchSize is the default maximum number of events delivered together
+	DefaultBatchSize = 50
+	// DefaultReplayInterval is how often spooled events are retried while no new events arrive
+	DefaultReplayInterval = 30 * time.Second
+	// DefaultMaxAttempts is the default number of delivery attempts before a batch is spilled to disk
+	DefaultMaxAttempts = 5
+	// DefaultSpoolMaxBytes is the default size limit of the disk spool
+	DefaultSpoolMaxBytes = 100 << 20
+
+	// retryMinBackoff and retryMaxBackoff bound the exponential backoff between delivery attempts
+	retryMinBackoff = 500 * time.Millisecond
+	retryMaxBackoff = 30 * time.Second
 )
 
-// Client is a client for sending audit events to the audit service
+// ClientConfig configures how the audit client buffers and delivers events
+type ClientConfig struct {
+	// BufferSize bounds the in-memory queue; when it is full, events are spilled to disk (or dropped without a spool)
+	BufferSize int
+	// BatchSize caps how many queued events are delivered together. Events are sent as soon as
+	// the worker is free, so batches only grow while events arrive faster than they are delivered.
+	BatchSize int
+	// MaxAttempts is how many times delivery of a batch is attempted before it is spilled to disk
+	MaxAttempts int
+
+	// SpoolDir enables the disk spool: events that cannot be delivered are written there and
+	// replayed once the audit service is reachable again, including after a restart
+	SpoolDir string
+	// SpoolMaxBytes bounds the spool size; events are dropped once it is reached (0 means no limit)
+	SpoolMaxBytes int64
+	// ReplayInterval is how often spooled events are retried while idle; they are also
+	// replayed after every successful delivery
+	ReplayInterval time.Duration
+
+	// RetryMinBackoff and RetryMaxBackoff bound the exponential backoff between attempts
+	RetryMinBackoff time.Duration
+	RetryMaxBackoff time.Duration
+}
+
+// DefaultClientConfig returns the default configuration, overridden by the environment variables
+// AUDIT_BUFFER_SIZE, AUDIT_BATCH_SIZE, AUDIT_MAX_ATTEMPTS, AUDIT_SPOOL_DIR, AUDIT_SPOOL_MAX_BYTES and AUDIT_REPLAY_INTERVAL
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		BufferSize:      envInt("AUDIT_BUFFER_SIZE", DefaultBufferSize),
+		BatchSize:       envInt("AUDIT_BATCH_SIZE", DefaultBatchSize),
+		MaxAttempts:     envInt("AUDIT_MAX_ATTEMPTS", DefaultMaxAttempts),
+		SpoolDir:        os.Getenv("AUDIT_SPOOL_DIR"),
+		SpoolMaxBytes:   int64(envInt("AUDIT_SPOOL_MAX_BYTES", DefaultSpoolMaxBytes)),
+		ReplayInterval:  envDuration("AUDIT_REPLAY_INTERVAL", DefaultReplayInterval),
+		RetryMinBackoff: retryMinBackoff,
+		RetryMaxBackoff: retryMaxBackoff,
+	}
+}
+
+// Client is a client for sending audit events to the audit service.
+// Events are queued in memory and delivered in batches by a background worker, with retries;
+// each event carries an eventId so that retried deliveries are stored only once.
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	enabled    bool
+	config     ClientConfig
+
+	// spool is nil when no SpoolDir is configured
+	spool *spool
+
+	// mu guards closed; queue is closed once closed is set
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *AuditLogRequest
+
+	// ctx is cancelled to abandon retries when Close runs out of time
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
-// NewClient creates a new audit client
+// NewClient creates a new audit client using DefaultClientConfig
 // Audit can be disabled by:
 //   - Setting ENABLE_AUDIT=false environment variable
 //   - Providing an empty baseURL
 //
 // When disabled, all LogEvent calls will be no-ops.
 func NewClient(baseURL string) *Client {
+	return NewClientWithConfig(baseURL, DefaultClientConfig())
+}
+
+// NewClientWithConfig creates a new audit client with the given buffering configuration
+// and starts its delivery worker. Call Close on shutdown to deliver queued events.
+func NewClientWithConfig(baseURL string, config ClientConfig) *Client {
 	enabled := isAuditEnabled(baseURL)
 
 	if !enabled {
@@ -47,8 +130,9 @@ func NewClient(baseURL string) *Client {
 		}
 	}
 
-	slog.Info("Audit client initialized", "baseURL", baseURL)
-	return &Client{
+	config = config.withDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: DefaultHTTPTimeout,
@@ -58,7 +142,51 @@ func NewClient(baseURL string) *Client {
 			},
 		},
 		enabled: true,
+		config:  config,
+		queue:   make(chan *AuditLogRequest, config.BufferSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	if config.SpoolDir != "" {
+		s, err := openSpool(config.SpoolDir, config.SpoolMaxBytes)
+		if err != nil {
+			// Buffering and retries still work without the spool
+			slog.Error("Failed to open audit spool, undeliverable events will be dropped", "error", err, "dir", config.SpoolDir)
+		} else {
+			c.spool = s
+		}
 	}
+
+	go c.run()
+
+	slog.Info("Audit client initialized", "baseURL", baseURL,
+		"bufferSize", config.BufferSize, "batchSize", config.BatchSize, "spoolDir", config.SpoolDir)
+	return c
+}
+
+// withDefaults fills in unset fields
+func (config ClientConfig) withDefaults() ClientConfig {
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultBufferSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.ReplayInterval <= 0 {
+		config.ReplayInterval = DefaultReplayInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.RetryMinBackoff <= 0 {
+		config.RetryMinBackoff = retryMinBackoff
+	}
+	if config.RetryMaxBackoff < config.RetryMinBackoff {
+		config.RetryMaxBackoff = max(retryMaxBackoff, config.RetryMinBackoff)
+	}
+	return config
 }
 
 // IsEnabled returns whether the audit client is enabled
@@ -66,49 +194,214 @@ func (c *Client) IsEnabled() bool {
 	return c.enabled
 }
 
-// LogEvent sends an audit event to the audit service asynchronously (fire-and-forget)
-// This function returns immediately and logs the event in a background goroutine.
+// LogEvent queues an audit event for delivery to the audit service and returns immediately.
+// An eventId is assigned if the event has none, and the request ID of ctx becomes its correlationId. If the queue is full, the event is spilled
+// to disk, or dropped when no spool is configured.
 func (c *Client) LogEvent(ctx context.Context, event *AuditLogRequest) {
 	// Skip if audit client is not enabled
 	if !c.enabled || c.httpClient == nil {
 		return
 	}
+	if event.EventID == nil {
+		eventID := newEventID()
+		event.EventID = &eventID
+	}
+	if event.CorrelationID == nil {
+		if requestID := requestid.FromContext(ctx); requestID != "" {
+			event.CorrelationID = &requestID
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		slog.Warn("Audit client is closed, audit event not logged", "eventType", event.EventType)
+		return
+	}
+	select {
+	case c.queue <- event:
+	default:
+		c.spill([]*AuditLogRequest{event}, errors.New("audit buffer is full"))
+	}
+}
+
+// Close stops accepting events and waits until queued events are delivered or spilled.
+// When ctx expires first, pending retries are abandoned and the remaining events are spilled.
+func (c *Client) Close(ctx context.Context) error {
+	if !c.enabled || c.httpClient == nil {
+		return nil
+	}
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
 
-	// Log asynchronously (fire-and-forget) using background context
-	// Using background context ensures the request completes even if the original context is cancelled
-	go c.logEvent(context.Background(), event)
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		c.cancel()
+		<-c.done
+		return ctx.Err()
+	}
 }
 
-// logEvent sends the audit event to the audit service API
-func (c *Client) logEvent(ctx context.Context, event *AuditLogRequest) {
-	if c.httpClient == nil {
+// run is the delivery worker: it delivers queued events in batches and
+// replays spooled events whenever the audit service is reachable
+func (c *Client) run() {
+	defer close(c.done)
+	defer c.cancel()
+	if c.spool != nil {
+		defer c.spool.Close()
+	}
+
+	// Replay events left over from a previous run before delivering new ones
+	c.replaySpool()
+
+	ticker := time.NewTicker(c.config.ReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-c.queue:
+			if !ok {
+				return
+			}
+			c.deliver(c.collectBatch(event))
+		case <-ticker.C:
+			c.replaySpool()
+		}
+	}
+}
+
+// collectBatch adds the events already waiting in the queue to first, up to BatchSize
+func (c *Client) collectBatch(first *AuditLogRequest) []*AuditLogRequest {
+	batch := []*AuditLogRequest{first}
+	for len(batch) < c.config.BatchSize {
+		select {
+		case event, ok := <-c.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, event)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// deliver sends a batch, retrying with exponential backoff; after MaxAttempts the rest is spilled
+func (c *Client) deliver(batch []*AuditLogRequest) {
+	if len(batch) == 0 {
 		return
 	}
+	backoff := c.config.RetryMinBackoff
+	for attempt := 1; ; attempt++ {
+		remaining, err := c.sendBatch(batch)
+		if err == nil {
+			c.replaySpool()
+			return
+		}
+		batch = remaining
+		if attempt >= c.config.MaxAttempts || c.ctx.Err() != nil {
+			c.spill(batch, err)
+			return
+		}
+		slog.Warn("Failed to deliver audit events, retrying",
+			"error", err, "events", len(batch), "attempt", attempt, "retryIn", backoff)
+		select {
+		case <-c.ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, c.config.RetryMaxBackoff)
+	}
+}
 
-	payloadBytes, err := json.Marshal(event)
+// replaySpool delivers spooled events once; it stops at the first failure, leaving the rest for later
+func (c *Client) replaySpool() {
+	if c.spool == nil || !c.spool.Pending() || c.ctx.Err() != nil {
+		return
+	}
+	err := c.spool.Replay(c.config.BatchSize, func(events []*AuditLogRequest) error {
+		_, err := c.sendBatch(events)
+		return err
+	})
 	if err != nil {
-		slog.Error("Failed to marshal audit request", "error", err)
+		slog.Warn("Failed to replay spooled audit events, will retry later", "error", err)
+		return
+	}
+	slog.Info("Replayed spooled audit events")
+}
+
+// spill writes events that could not be delivered to the spool, or drops them without one
+func (c *Client) spill(events []*AuditLogRequest, cause error) {
+	if len(events) == 0 {
 		return
 	}
+	if c.spool != nil {
+		err := c.spool.Append(events)
+		if err == nil {
+			slog.Warn("Spooled audit events to disk", "reason", cause, "events", len(events))
+			return
+		}
+		slog.Error("Failed to spool audit events", "error", err)
+	}
+	slog.Error("Dropped audit events", "reason", cause, "events", len(events))
+}
+
+// sendBatch sends events in order and returns those not yet delivered when a retryable error occurs.
+// Events the audit service rejects as invalid are logged and skipped, as retrying cannot succeed.
+func (c *Client) sendBatch(events []*AuditLogRequest) ([]*AuditLogRequest, error) {
+	for i, event := range events {
+		err := c.logEvent(c.ctx, event)
+		var rejected *rejectedEventError
+		if errors.As(err, &rejected) {
+			slog.Error("Audit service rejected audit event", "error", err, "eventType", event.EventType, "actorId", event.ActorID)
+			continue
+		}
+		if err != nil {
+			return events[i:], err
+		}
+	}
+	return nil, nil
+}
+
+// rejectedEventError is returned when the audit service refuses an event with a client error other than a timeout or rate limit
+type rejectedEventError struct {
+	status int
+	body   string
+}
+
+func (e *rejectedEventError) Error() string {
+	return fmt.Sprintf("audit service returned status %d: %s", e.status, e.body)
+}
+
+// logEvent sends the audit event to the audit service API.
+// A 200 response means the event had already been stored, e.g. by an earlier attempt.
+func (c *Client) logEvent(ctx context.Context, event *AuditLogRequest) error {
+	payloadBytes, err := json.Marshal(event)
+	if err != nil {
+		return &rejectedEventError{body: fmt.Sprintf("failed to marshal audit request: %v", err)}
+	}
 
 	// Construct URL safely
 	endpointURL, err := url.JoinPath(c.baseURL, AuditLogsEndpoint)
 	if err != nil {
-		slog.Error("Failed to construct audit service URL", "error", err, "baseURL", c.baseURL)
-		return
+		return fmt.Errorf("failed to construct audit service URL: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL, bytes.NewReader(payloadBytes))
 	if err != nil {
-		slog.Error("Failed to create audit request", "error", err)
-		return
+		return fmt.Errorf("failed to create audit request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		slog.Error("Failed to send audit request", "error", err)
-		return
+		return fmt.Errorf("failed to send audit request: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -117,16 +410,13 @@ func (c *Client) logEvent(ctx context.Context, event *AuditLogRequest) {
 		}
 	}(resp.Body)
 
-	if resp.StatusCode != http.StatusCreated {
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			slog.Error("Audit service returned non-201 status and failed to read body",
-				"status", resp.StatusCode, "readError", readErr)
-		} else {
-			slog.Error("Audit service returned non-201 status",
-				"status", resp.StatusCode, "body", string(bodyBytes))
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return &rejectedEventError{status: resp.StatusCode, body: string(bodyBytes)}
 		}
-		return
+		return fmt.Errorf("audit service returned status %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	slog.Info("Audit event logged successfully",
@@ -136,6 +426,34 @@ func (c *Client) logEvent(ctx context.Context, event *AuditLogRequest) {
 		"targetType", event.TargetType,
 		"status", event.Status,
 		"additionalMetadata", string(event.AdditionalMetadata))
+	return nil
+}
+
+// newEventID returns a random (version 4) UUID
+func newEventID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
+
+// envInt reads a positive integer from the environment, falling back to defaultValue
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// envDuration reads a positive duration from the environment, falling back to defaultValue
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
 }
 
 // isAuditEnabled checks if audit logging is enabled via environment variable
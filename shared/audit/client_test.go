@@ -0,0 +1,250 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gov-dx-sandbox/shared/requestid"
+)
+
+// recordingServer is a fake audit service recording the events it stores.
+// While status is set, requests are answered with it and nothing is stored.
+type recordingServer struct {
+	*httptest.Server
+
+	status   atomic.Int32
+	attempts atomic.Int32
+
+	mu     sync.Mutex
+	events map[string]AuditLogRequest
+}
+
+func newRecordingServer(t *testing.T) *recordingServer {
+	s := &recordingServer{events: make(map[string]AuditLogRequest)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.attempts.Add(1)
+		if status := s.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+		var event AuditLogRequest
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil || event.EventID == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.events[*event.EventID]; ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		s.events[*event.EventID] = event
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *recordingServer) stored() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+func testClientConfig(spoolDir string) ClientConfig {
+	return ClientConfig{
+		BufferSize:      100,
+		BatchSize:       10,
+		MaxAttempts:     3,
+		SpoolDir:        spoolDir,
+		ReplayInterval:  20 * time.Millisecond,
+		RetryMinBackoff: time.Millisecond,
+		RetryMaxBackoff: 5 * time.Millisecond,
+	}
+}
+
+func testEvent(actorID string) *AuditLogRequest {
+	return &AuditLogRequest{
+		Timestamp:  CurrentTimestamp(),
+		Status:     StatusSuccess,
+		ActorType:  "SERVICE",
+		ActorID:    actorID,
+		TargetType: "SERVICE",
+	}
+}
+
+func closeClient(t *testing.T, client *Client) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool, message string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func spoolFiles(t *testing.T, dir string) int {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	return len(entries)
+}
+
+func TestClient_DeliversEventsWithEventIDs(t *testing.T) {
+	server := newRecordingServer(t)
+	client := NewClientWithConfig(server.URL, testClientConfig(""))
+
+	for i := 0; i < 25; i++ {
+		client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+	}
+	closeClient(t, client)
+
+	if got := server.stored(); got != 25 {
+		t.Errorf("stored %d events, want 25", got)
+	}
+}
+
+func TestClient_UsesRequestIDAsCorrelationID(t *testing.T) {
+	server := newRecordingServer(t)
+	client := NewClientWithConfig(server.URL, testClientConfig(""))
+
+	client.LogEvent(requestid.WithRequestID(context.Background(), "req-1"), testEvent("portal-backend"))
+	closeClient(t, client)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, event := range server.events {
+		if event.CorrelationID == nil || *event.CorrelationID != "req-1" {
+			t.Errorf("correlationId = %v, want req-1", event.CorrelationID)
+		}
+	}
+}
+
+func TestClient_RetriesWithSameEventID(t *testing.T) {
+	server := newRecordingServer(t)
+	server.status.Store(http.StatusServiceUnavailable)
+	config := testClientConfig("")
+	config.MaxAttempts = 1000
+	client := NewClientWithConfig(server.URL, config)
+
+	client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+	waitFor(t, func() bool { return server.attempts.Load() >= 2 }, "event was not retried")
+	server.status.Store(0)
+	closeClient(t, client)
+
+	if got := server.stored(); got != 1 {
+		t.Errorf("stored %d events, want 1", got)
+	}
+}
+
+func TestClient_DoesNotRetryRejectedEvents(t *testing.T) {
+	server := newRecordingServer(t)
+	server.status.Store(http.StatusBadRequest)
+	spoolDir := t.TempDir()
+	client := NewClientWithConfig(server.URL, testClientConfig(spoolDir))
+
+	client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+	closeClient(t, client)
+
+	if got := server.attempts.Load(); got != 1 {
+		t.Errorf("made %d attempts, want 1", got)
+	}
+	if got := spoolFiles(t, spoolDir); got != 0 {
+		t.Errorf("spooled %d files, want 0", got)
+	}
+}
+
+func TestClient_SpoolsUntilServiceRecovers(t *testing.T) {
+	server := newRecordingServer(t)
+	server.status.Store(http.StatusServiceUnavailable)
+	spoolDir := t.TempDir()
+	client := NewClientWithConfig(server.URL, testClientConfig(spoolDir))
+
+	for i := 0; i < 3; i++ {
+		client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+	}
+	waitFor(t, func() bool { return spoolFiles(t, spoolDir) > 0 }, "events were not spooled")
+
+	server.status.Store(0)
+	waitFor(t, func() bool { return server.stored() == 3 }, "spooled events were not replayed")
+	closeClient(t, client)
+
+	if got := spoolFiles(t, spoolDir); got != 0 {
+		t.Errorf("%d spool files left after replay, want 0", got)
+	}
+}
+
+func TestClient_ReplaysSpoolOfPreviousRun(t *testing.T) {
+	server := newRecordingServer(t)
+	server.status.Store(http.StatusServiceUnavailable)
+	spoolDir := t.TempDir()
+
+	// The service is down until shutdown, so the client spools its events
+	client := NewClientWithConfig(server.URL, testClientConfig(spoolDir))
+	client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+	client.LogEvent(context.Background(), testEvent("portal-backend"))
+	closeClient(t, client)
+	if server.stored() != 0 || spoolFiles(t, spoolDir) == 0 {
+		t.Fatal("expected events to be spooled, not stored")
+	}
+
+	server.status.Store(0)
+	restarted := NewClientWithConfig(server.URL, testClientConfig(spoolDir))
+	waitFor(t, func() bool { return server.stored() == 2 }, "spooled events were not replayed after restart")
+	closeClient(t, restarted)
+}
+
+func TestClient_CloseTimeoutSpoolsPendingEvents(t *testing.T) {
+	server := newRecordingServer(t)
+	server.status.Store(http.StatusServiceUnavailable)
+	spoolDir := t.TempDir()
+	config := testClientConfig(spoolDir)
+	config.MaxAttempts = 1000
+	config.RetryMinBackoff, config.RetryMaxBackoff = time.Hour, time.Hour
+	client := NewClientWithConfig(server.URL, config)
+
+	client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+	waitFor(t, func() bool { return server.attempts.Load() >= 1 }, "event was not sent")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); err == nil {
+		t.Fatal("Close() error = nil, want a timeout")
+	}
+	if got := spoolFiles(t, spoolDir); got != 1 {
+		t.Errorf("spooled %d files, want 1", got)
+	}
+
+	// Events logged after Close are ignored
+	client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+}
+
+func TestClient_Disabled(t *testing.T) {
+	client := NewClient("")
+	if client.IsEnabled() {
+		t.Fatal("client with empty URL should be disabled")
+	}
+	client.LogEvent(context.Background(), testEvent("orchestration-engine"))
+	if err := client.Close(context.Background()); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/gov-dx-sandbox/shared/audit
 
 go 1.24.6
+
+require github.com/gov-dx-sandbox/shared/requestid v0.0.0
+
+replace github.com/gov-dx-sandbox/shared/requestid => ../requestid
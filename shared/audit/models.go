@@ -7,8 +7,16 @@ import (
 // AuditLogRequest represents the request payload for creating an audit log
 // Services like orchestration-engine and portal-backend can use this without importing audit-service
 type AuditLogRequest struct {
+	// Idempotency key; the client assigns one if empty so that retried deliveries are stored only once
+	EventID *string `json:"eventId,omitempty"`
+
 	// Trace & Correlation
-	TraceID *string `json:"traceId,omitempty"` // UUID string, nullable for standalone events
+	TraceID *string `json:"traceId,omitempty"` // UUID or 32-hex OpenTelemetry trace ID, nullable for standalone events
+
+	// W3C traceparent of the operation; supplies the trace ID if TraceID is empty and records the span ID
+	Traceparent *string `json:"traceparent,omitempty"`
+	// Request or correlation ID for producers that do not propagate trace IDs
+	CorrelationID *string `json:"correlationId,omitempty"`
 
 	// Temporal
 	Timestamp string `json:"timestamp"` // ISO 8601 format, required
@@ -18,6 +26,9 @@ type AuditLogRequest struct {
 	EventAction *string `json:"eventAction,omitempty"` // CREATE, READ, UPDATE, DELETE
 	Status      string  `json:"status"`                // SUCCESS, FAILURE
 
+	// Version of the event type's schema the payload follows (GET /api/audit-logs/event-types); the service assumes 1 if unset
+	EventVersion *int `json:"eventVersion,omitempty"`
+
 	// Actor Information (unified approach)
 	ActorType string `json:"actorType"` // SERVICE, ADMIN, MEMBER, SYSTEM
 	ActorID   string `json:"actorId"`   // email, uuid, or service-name (required)
@@ -0,0 +1,208 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// spoolFilePrefix and spoolFileSuffix name spool files; names sort in creation order
+	spoolFilePrefix = "audit-spool-"
+	spoolFileSuffix = ".ndjson"
+
+	// spoolFileMaxBytes is the size at which the active spool file is closed and a new one started
+	spoolFileMaxBytes = 1 << 20
+)
+
+// errSpoolFull is returned when spilling would exceed the spool's size limit
+var errSpoolFull = errors.New("audit spool is full")
+
+// spool stores audit events on disk as NDJSON files while the audit service is unreachable.
+// Events are appended to an active file, which is rotated once it grows past spoolFileMaxBytes;
+// replay reads files oldest first and deletes each once all of its events are delivered.
+type spool struct {
+	dir      string
+	maxBytes int64
+
+	mu         sync.Mutex
+	active     *os.File
+	activeSize int64
+	totalBytes int64
+	sequence   int
+}
+
+// openSpool creates dir if needed and accounts for events left over from a previous run
+func openSpool(dir string, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit spool directory: %w", err)
+	}
+	s := &spool{dir: dir, maxBytes: maxBytes}
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			s.totalBytes += info.Size()
+		}
+	}
+	if len(files) > 0 {
+		slog.Info("Found spooled audit events from a previous run", "files", len(files), "bytes", s.totalBytes)
+	}
+	return s, nil
+}
+
+// Append writes events to the active spool file
+func (s *spool) Append(events []*AuditLogRequest) error {
+	var data []byte
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit event: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxBytes > 0 && s.totalBytes+int64(len(data)) > s.maxBytes {
+		return errSpoolFull
+	}
+	if s.active == nil {
+		s.sequence++
+		name := fmt.Sprintf("%s%020d-%06d%s", spoolFilePrefix, time.Now().UnixNano(), s.sequence, spoolFileSuffix)
+		file, err := os.OpenFile(filepath.Join(s.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create audit spool file: %w", err)
+		}
+		s.active, s.activeSize = file, 0
+	}
+	if _, err := s.active.Write(data); err != nil {
+		return fmt.Errorf("failed to write audit spool file: %w", err)
+	}
+	s.activeSize += int64(len(data))
+	s.totalBytes += int64(len(data))
+	if s.activeSize >= spoolFileMaxBytes {
+		s.rotateLocked()
+	}
+	return nil
+}
+
+// Pending reports whether any spooled events are waiting to be replayed
+func (s *spool) Pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.totalBytes > 0
+}
+
+// Replay passes the spooled events, oldest first and at most batchSize at a time, to deliver.
+// A file is deleted once all of its events were delivered; replay stops at the first error,
+// and the failing file is replayed again in full next time.
+func (s *spool) Replay(batchSize int, deliver func([]*AuditLogRequest) error) error {
+	// Listing under the lock, right after rotating, leaves out the file that concurrent appends start
+	s.mu.Lock()
+	s.rotateLocked()
+	files, err := s.files()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		events, err := readSpoolFile(file)
+		if err != nil {
+			return err
+		}
+		for start := 0; start < len(events); start += batchSize {
+			if err := deliver(events[start:min(start+batchSize, len(events))]); err != nil {
+				return err
+			}
+		}
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove replayed audit spool file: %w", err)
+		}
+		s.release(info.Size())
+	}
+	return nil
+}
+
+// Close closes the active spool file; spooled events are replayed by the next client using the directory
+func (s *spool) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotateLocked()
+}
+
+func (s *spool) rotateLocked() {
+	if s.active == nil {
+		return
+	}
+	if err := s.active.Close(); err != nil {
+		slog.Error("Failed to close audit spool file", "error", err)
+	}
+	s.active = nil
+}
+
+func (s *spool) release(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.totalBytes = max(s.totalBytes-size, 0)
+}
+
+// files lists closed and active spool files in creation order
+func (s *spool) files() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit spool directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, spoolFilePrefix) && strings.HasSuffix(name, spoolFileSuffix) {
+			files = append(files, filepath.Join(s.dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readSpoolFile reads the events of a spool file.
+// Lines that cannot be decoded, such as one cut short by a crash, are logged and skipped.
+func readSpoolFile(path string) ([]*AuditLogRequest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit spool file: %w", err)
+	}
+	defer file.Close()
+
+	var events []*AuditLogRequest
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event AuditLogRequest
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			slog.Error("Skipping unreadable spooled audit event", "file", path, "error", err)
+			continue
+		}
+		events = append(events, &event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit spool file: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpool_AppendAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 0)
+	if err != nil {
+		t.Fatalf("openSpool() error = %v", err)
+	}
+
+	for _, actorID := range []string{"a", "b", "c"} {
+		if err := s.Append([]*AuditLogRequest{testEvent(actorID)}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	if !s.Pending() {
+		t.Fatal("Pending() = false after Append")
+	}
+
+	// A failed delivery keeps the file for the next replay
+	failure := errors.New("audit service unavailable")
+	if err := s.Replay(2, func([]*AuditLogRequest) error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("Replay() error = %v, want %v", err, failure)
+	}
+	if !s.Pending() {
+		t.Fatal("Pending() = false after failed replay")
+	}
+
+	// Events appended during a replay go to a new file and are not lost
+	var replayed []string
+	err = s.Replay(2, func(events []*AuditLogRequest) error {
+		for _, event := range events {
+			replayed = append(replayed, event.ActorID)
+		}
+		return s.Append([]*AuditLogRequest{testEvent("late")})
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if got, want := len(replayed), 3; got != want || replayed[0] != "a" || replayed[2] != "c" {
+		t.Errorf("replayed %v, want [a b c]", replayed)
+	}
+
+	replayed = nil
+	if err := s.Replay(10, func(events []*AuditLogRequest) error {
+		for _, event := range events {
+			replayed = append(replayed, event.ActorID)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if len(replayed) != 2 || replayed[0] != "late" {
+		t.Errorf("replayed %v, want the two late events", replayed)
+	}
+	if s.Pending() {
+		t.Error("Pending() = true after everything was replayed")
+	}
+}
+
+func TestSpool_SizeLimitAndCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 200)
+	if err != nil {
+		t.Fatalf("openSpool() error = %v", err)
+	}
+	if err := s.Append([]*AuditLogRequest{testEvent("a")}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := s.Append([]*AuditLogRequest{testEvent("b")}); !errors.Is(err, errSpoolFull) {
+		t.Fatalf("Append() error = %v, want %v", err, errSpoolFull)
+	}
+	s.Close()
+
+	// A line cut short by a crash is skipped; the rest of the file is replayed
+	files, _ := filepath.Glob(filepath.Join(dir, spoolFilePrefix+"*"))
+	if len(files) != 1 {
+		t.Fatalf("found %d spool files, want 1", len(files))
+	}
+	file, err := os.OpenFile(files[0], os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"timestamp":"2024-`)
+	file.Close()
+
+	reopened, err := openSpool(dir, 0)
+	if err != nil {
+		t.Fatalf("openSpool() error = %v", err)
+	}
+	var count int
+	if err := reopened.Replay(10, func(events []*AuditLogRequest) error {
+		count += len(events)
+		return nil
+	}); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("replayed %d events, want 1", count)
+	}
+}
@@ -0,0 +1,273 @@
+// Package database routes the reads of the services' GORM connections to read replicas. A
+// ReplicaRouter is registered on the primary connection as a GORM plugin; queries made through
+// Reader are then spread over the healthy replicas:
+//
+//   - queries not made through Reader, writes and everything in a transaction use the primary
+//   - a replica that stops answering is taken out of rotation and the query is retried on the primary
+//   - a background health check puts a replica back in rotation once it answers again
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+// PluginName is the name the router is registered under on a GORM connection
+const PluginName = "database:replicas"
+
+// DefaultHealthCheckInterval is how often the replicas are pinged when no interval is given
+const DefaultHealthCheckInterval = 10 * time.Second
+
+// pingTimeout bounds each ping of a replica
+const pingTimeout = 3 * time.Second
+
+const (
+	// readKey marks a statement whose query may be served by a replica
+	readKey = "database:read"
+	// routedKey carries the routing of a statement from the before to the after callback
+	routedKey = "database:routed"
+	// rowsKey is the setting GORM uses to tell Rows from Row; it is consumed when the query runs
+	rowsKey = "rows"
+)
+
+// Reader returns a session of db whose queries may be served by a read replica. Use it for
+// read-only service methods that tolerate the replication lag, such as listings; the session
+// behaves like db when no replica is configured.
+func Reader(db *gorm.DB) *gorm.DB {
+	return db.Set(readKey, true).Session(&gorm.Session{})
+}
+
+// ParseReplicaDSNs splits a comma-separated list of replica DSNs, dropping empty entries
+func ParseReplicaDSNs(value string) []string {
+	var dsns []string
+	for _, dsn := range strings.Split(value, ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			dsns = append(dsns, dsn)
+		}
+	}
+	return dsns
+}
+
+// Replica is a read replica of the primary database
+type Replica struct {
+	// Name identifies the replica in logs; it must not contain credentials
+	Name string
+	DB   *sql.DB
+}
+
+type replica struct {
+	Replica
+	healthy atomic.Bool
+}
+
+// routing records where a statement was sent so that the after callbacks can restore it
+type routing struct {
+	replica *replica
+	pool    gorm.ConnPool
+	rows    bool
+}
+
+// ReplicaRouter is a GORM plugin sending the queries made through Reader to healthy replicas
+type ReplicaRouter struct {
+	replicas []*replica
+	interval time.Duration
+	next     atomic.Uint64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewReplicaRouter creates a router over replicas, pinged every healthCheckInterval once the router
+// is registered with db.Use
+func NewReplicaRouter(replicas []Replica, healthCheckInterval time.Duration) *ReplicaRouter {
+	if healthCheckInterval <= 0 {
+		healthCheckInterval = DefaultHealthCheckInterval
+	}
+	r := &ReplicaRouter{
+		interval: healthCheckInterval,
+		stop:     make(chan struct{}),
+	}
+	for _, rep := range replicas {
+		// Replicas start in rotation so that one down at startup is reported by the first check
+		added := &replica{Replica: rep}
+		added.healthy.Store(true)
+		r.replicas = append(r.replicas, added)
+	}
+	return r
+}
+
+// Name implements gorm.Plugin
+func (r *ReplicaRouter) Name() string {
+	return PluginName
+}
+
+// Initialize implements gorm.Plugin. It registers the routing callbacks, checks the replicas once and
+// starts the background health check.
+func (r *ReplicaRouter) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("database:route_query", r.route); err != nil {
+		return err
+	}
+	// Preloads run on the connection of the query, so a query is released once they are loaded
+	if err := db.Callback().Query().After("gorm:preload").Register("database:release_query", r.release(callbacks.Query, callbacks.Preload)); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("database:route_row", r.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("database:release_row", r.release(callbacks.RowQuery)); err != nil {
+		return err
+	}
+
+	r.CheckReplicas(context.Background())
+	r.wg.Add(1)
+	go r.run()
+	return nil
+}
+
+// Healthy returns the names of the replicas currently in rotation
+func (r *ReplicaRouter) Healthy() []string {
+	var names []string
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			names = append(names, rep.Name)
+		}
+	}
+	return names
+}
+
+// CheckReplicas pings every replica, taking the ones that fail out of rotation and putting the ones
+// that answer back
+func (r *ReplicaRouter) CheckReplicas(ctx context.Context) {
+	for _, rep := range r.replicas {
+		if err := ping(ctx, rep); err != nil {
+			r.markDown(rep, err)
+		} else if !rep.healthy.Swap(true) {
+			slog.Info("Read replica is available", "replica", rep.Name)
+		}
+	}
+}
+
+// Close stops the health check and closes the replica connections
+func (r *ReplicaRouter) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	r.wg.Wait()
+
+	var errs []error
+	for _, rep := range r.replicas {
+		if err := rep.DB.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseReplicas closes the replicas of the router registered on db, if any
+func CloseReplicas(db *gorm.DB) error {
+	if r, ok := db.Config.Plugins[PluginName].(*ReplicaRouter); ok {
+		return r.Close()
+	}
+	return nil
+}
+
+func (r *ReplicaRouter) run() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.CheckReplicas(context.Background())
+		}
+	}
+}
+
+// route sends a read outside a transaction to the next healthy replica
+func (r *ReplicaRouter) route(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if read, ok := db.Get(readKey); !ok || read != true {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	rep := r.pick()
+	if rep == nil {
+		return
+	}
+
+	rows, _ := db.Get(rowsKey)
+	db.Statement.Settings.Store(routedKey, &routing{replica: rep, pool: db.Statement.ConnPool, rows: rows == true})
+	db.Statement.ConnPool = rep.DB
+}
+
+// release restores the connection of a routed statement. When the replica failed because it is
+// unreachable, it is taken out of rotation and the query steps are run again on the primary.
+func (r *ReplicaRouter) release(steps ...func(*gorm.DB)) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.Statement.Settings.LoadAndDelete(routedKey)
+		if !ok {
+			return
+		}
+		routed := value.(*routing)
+		db.Statement.ConnPool = routed.pool
+
+		if db.Error == nil || errors.Is(db.Error, gorm.ErrRecordNotFound) || db.Statement.Context.Err() != nil {
+			return
+		}
+		err := ping(context.Background(), routed.replica)
+		if err == nil {
+			return
+		}
+		r.markDown(routed.replica, err)
+
+		db.Error = nil
+		if routed.rows {
+			db.Statement.Settings.Store(rowsKey, true)
+		}
+		for _, step := range steps {
+			step(db)
+		}
+	}
+}
+
+// pick returns the next healthy replica in rotation, or nil when none is healthy
+func (r *ReplicaRouter) pick() *replica {
+	n := uint64(len(r.replicas))
+	if n == 0 {
+		return nil
+	}
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+func (r *ReplicaRouter) markDown(rep *replica, err error) {
+	if rep.healthy.Swap(false) {
+		slog.Warn("Read replica is unavailable, reading from the primary", "replica", rep.Name, "error", err)
+	}
+}
+
+func ping(ctx context.Context, rep *replica) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return rep.DB.PingContext(ctx)
+}
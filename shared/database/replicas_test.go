@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type person struct {
+	ID    uint
+	Name  string
+	Notes []note
+}
+
+type note struct {
+	ID       uint
+	PersonID uint
+	Text     string
+}
+
+// switchConnector connects to a SQLite file unless the replica is switched off
+type switchConnector struct {
+	dsn  string
+	down atomic.Bool
+}
+
+func (c *switchConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.down.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return c.Driver().Open(c.dsn)
+}
+
+func (c *switchConnector) Driver() driver.Driver {
+	return &sqlite3.SQLiteDriver{}
+}
+
+// setup opens a primary and a replica that each hold one person named after them
+func setup(t *testing.T) (*gorm.DB, *switchConnector, *ReplicaRouter) {
+	t.Helper()
+	dir := t.TempDir()
+
+	open := func(name string) *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(dir, name+".db")), &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", name, err)
+		}
+		if err := db.AutoMigrate(&person{}, &note{}); err != nil {
+			t.Fatalf("failed to migrate %s: %v", name, err)
+		}
+		if err := db.Create(&person{Name: name, Notes: []note{{Text: name}}}).Error; err != nil {
+			t.Fatalf("failed to seed %s: %v", name, err)
+		}
+		return db
+	}
+	primary := open("primary")
+	seeded, _ := open("replica").DB()
+	seeded.Close()
+
+	connector := &switchConnector{dsn: filepath.Join(dir, "replica.db")}
+	replicaDB := sql.OpenDB(connector)
+	// Without idle connections every query connects, so switching the replica off takes effect at once
+	replicaDB.SetMaxIdleConns(0)
+
+	router := NewReplicaRouter([]Replica{{Name: "replica-1", DB: replicaDB}}, 0)
+	if err := primary.Use(router); err != nil {
+		t.Fatalf("failed to register router: %v", err)
+	}
+	t.Cleanup(func() { router.Close() })
+	return primary, connector, router
+}
+
+func name(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var p person
+	if err := db.First(&p).Error; err != nil {
+		t.Fatalf("failed to read person: %v", err)
+	}
+	return p.Name
+}
+
+func TestReplicaRouter_Routing(t *testing.T) {
+	db, _, _ := setup(t)
+
+	if got := name(t, Reader(db)); got != "replica" {
+		t.Errorf("Reader read from %q, want the replica", got)
+	}
+	if got := name(t, db); got != "primary" {
+		t.Errorf("default session read from %q, want the primary", got)
+	}
+	if got := name(t, Reader(db).WithContext(context.Background()).Where("id > ?", 0)); got != "replica" {
+		t.Errorf("chained Reader read from %q, want the replica", got)
+	}
+
+	var p person
+	if err := Reader(db).Preload("Notes").First(&p).Error; err != nil {
+		t.Fatalf("failed to read person with notes: %v", err)
+	}
+	if len(p.Notes) != 1 || p.Notes[0].Text != "replica" {
+		t.Errorf("preloaded notes %+v, want the replica's", p.Notes)
+	}
+
+	var names []string
+	if err := Reader(db).Raw("SELECT name FROM people").Scan(&names).Error; err != nil {
+		t.Fatalf("failed to scan names: %v", err)
+	}
+	if len(names) != 1 || names[0] != "replica" {
+		t.Errorf("raw Reader query returned %v, want the replica", names)
+	}
+
+	err := Reader(db).Transaction(func(tx *gorm.DB) error {
+		if got := name(t, tx); got != "primary" {
+			t.Errorf("transaction read from %q, want the primary", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+}
+
+func TestReplicaRouter_FailoverAndFailback(t *testing.T) {
+	db, connector, router := setup(t)
+
+	connector.down.Store(true)
+	if got := name(t, Reader(db)); got != "primary" {
+		t.Errorf("read from %q while the replica is down, want the primary", got)
+	}
+	if healthy := router.Healthy(); len(healthy) != 0 {
+		t.Errorf("replicas in rotation while down = %v, want none", healthy)
+	}
+
+	var p person
+	if err := Reader(db).Preload("Notes").First(&p).Error; err != nil {
+		t.Fatalf("failed to read person with notes: %v", err)
+	}
+	if p.Name != "primary" || len(p.Notes) != 1 || p.Notes[0].Text != "primary" {
+		t.Errorf("read %+v while the replica is down, want the primary with its notes", p)
+	}
+
+	var names []string
+	if err := Reader(db).Raw("SELECT name FROM people").Scan(&names).Error; err != nil {
+		t.Fatalf("failed to scan names: %v", err)
+	}
+	if len(names) != 1 || names[0] != "primary" {
+		t.Errorf("raw query returned %v while the replica is down, want the primary", names)
+	}
+
+	// The replica is back in rotation after the next health check
+	connector.down.Store(false)
+	if got := name(t, Reader(db)); got != "primary" {
+		t.Errorf("read from %q before the health check, want the primary", got)
+	}
+	router.CheckReplicas(context.Background())
+	if got := name(t, Reader(db)); got != "replica" {
+		t.Errorf("read from %q after the replica recovered, want the replica", got)
+	}
+}
+
+func TestReplicaRouter_ReplicaDownAtStartup(t *testing.T) {
+	primary, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "primary.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open primary: %v", err)
+	}
+	connector := &switchConnector{dsn: filepath.Join(t.TempDir(), "replica.db")}
+	connector.down.Store(true)
+
+	router := NewReplicaRouter([]Replica{{Name: "replica-1", DB: sql.OpenDB(connector)}}, 0)
+	if err := primary.Use(router); err != nil {
+		t.Fatalf("failed to register router: %v", err)
+	}
+	defer CloseReplicas(primary)
+
+	if healthy := router.Healthy(); len(healthy) != 0 {
+		t.Errorf("replicas in rotation = %v, want none", healthy)
+	}
+}
+
+func TestParseReplicaDSNs(t *testing.T) {
+	dsns := ParseReplicaDSNs(" host=replica-1 dbname=portal , ,host=replica-2 dbname=portal")
+	if len(dsns) != 2 || dsns[0] != "host=replica-1 dbname=portal" || dsns[1] != "host=replica-2 dbname=portal" {
+		t.Errorf("ParseReplicaDSNs() = %q", dsns)
+	}
+	if dsns := ParseReplicaDSNs(""); len(dsns) != 0 {
+		t.Errorf("ParseReplicaDSNs(\"\") = %q, want none", dsns)
+	}
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Pinger is implemented by *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingCheck checks that a database answers pings
+func PingCheck(db Pinger) CheckFunc {
+	return func(ctx context.Context) error {
+		if db == nil {
+			return fmt.Errorf("database not connected")
+		}
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("database ping failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// HTTPCheck checks that a downstream service answers GET url with a status below 500. Any answer
+// proves the service reachable; its own readiness is for its probes to report.
+func HTTPCheck(client *http.Client, url string) CheckFunc {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("unreachable: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("answered with status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// ThresholdCheck fails while value, such as the depth of a queue, exceeds max
+func ThresholdCheck(value func(ctx context.Context) (int64, error), max int64) CheckFunc {
+	return func(ctx context.Context) error {
+		current, err := value(ctx)
+		if err != nil {
+			return err
+		}
+		if current > max {
+			return fmt.Errorf("%d exceeds the threshold of %d", current, max)
+		}
+		return nil
+	}
+}
+
+// StalenessCheck fails while the time returned by last, such as the last successful run of a
+// background job, is zero or older than maxAge
+func StalenessCheck(last func() time.Time, maxAge time.Duration) CheckFunc {
+	return func(ctx context.Context) error {
+		at := last()
+		if at.IsZero() {
+			return fmt.Errorf("never completed")
+		}
+		if age := time.Since(at); age > maxAge {
+			return fmt.Errorf("last completed %s ago", age.Round(time.Second))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/gov-dx-sandbox/shared/health
+
+go 1.24.6
@@ -0,0 +1,202 @@
+// Package health implements the liveness and readiness endpoints shared by the HTTP services. Each
+// service registers the checks its readiness depends on, such as a database ping or the reachability
+// of a downstream service, and exposes them with RegisterRoutes:
+//
+//   - /health/live answers as long as the process serves requests and never runs the checks
+//   - /health/ready runs every check and answers 503 if a required check fails
+//   - /health is kept for existing probes and answers like /health/ready
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Statuses of a check and of a service
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+	// StatusDegraded is the status of a ready service whose optional checks fail
+	StatusDegraded = "degraded"
+)
+
+// DefaultTimeout bounds each check run by a readiness probe
+const DefaultTimeout = 3 * time.Second
+
+// CheckFunc checks a dependency of the service and returns why it is unavailable, if it is
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a check
+type CheckResult struct {
+	Status string `json:"status"`
+	// Optional checks do not make the service unready when they fail
+	Optional   bool   `json:"optional,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Report is the body of the health endpoints
+type Report struct {
+	Service string                 `json:"service"`
+	Status  string                 `json:"status"`
+	Checks  map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	optional bool
+}
+
+// Checker runs the health checks of a service
+type Checker struct {
+	service string
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []check
+}
+
+// NewChecker creates a checker without checks for the named service
+func NewChecker(service string) *Checker {
+	return &Checker{service: service, timeout: DefaultTimeout}
+}
+
+// SetTimeout changes how long each check may run; non-positive values keep the current timeout
+func (c *Checker) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		c.timeout = timeout
+	}
+}
+
+// Register adds a check the service cannot serve requests without
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.add(check{name: name, fn: fn})
+}
+
+// RegisterOptional adds a check whose failure degrades the service without making it unready
+func (c *Checker) RegisterOptional(name string, fn CheckFunc) {
+	c.add(check{name: name, fn: fn, optional: true})
+}
+
+func (c *Checker) add(ch check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, ch)
+}
+
+// Live reports that the process is up without running the checks
+func (c *Checker) Live() Report {
+	return Report{Service: c.service, Status: StatusUp}
+}
+
+// Ready runs all checks concurrently and reports the service down if a required check fails
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, ch)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Service: c.service, Status: StatusUp, Checks: make(map[string]CheckResult, len(checks))}
+	for i, ch := range checks {
+		result := results[i]
+		report.Checks[ch.name] = result
+		if result.Status == StatusUp {
+			continue
+		}
+		if !ch.optional {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run runs a check with the checker's timeout, turning panics into failures
+func (c *Checker) run(ctx context.Context, ch check) (result CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result = CheckResult{Status: StatusDown, Error: fmt.Sprintf("check panicked: %v", r)}
+		}
+		result.Optional = ch.optional
+		result.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	if err := ch.fn(ctx); err != nil {
+		return CheckResult{Status: StatusDown, Error: err.Error()}
+	}
+	return CheckResult{Status: StatusUp}
+}
+
+// LiveHandler serves the liveness probe
+func (c *Checker) LiveHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, c.Live())
+	}
+}
+
+// ReadyHandler serves the readiness probe, answering 503 while the service is down
+func (c *Checker) ReadyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Ready(r.Context())
+		status := http.StatusOK
+		if report.Status == StatusDown {
+			status = http.StatusServiceUnavailable
+			slog.Warn("Readiness check failed", "service", c.service, "checks", failedChecks(report))
+		}
+		writeReport(w, status, report)
+	}
+}
+
+// Mux is implemented by http.ServeMux and by routers such as chi
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// RegisterRoutes serves /health, /health/live and /health/ready on mux
+func (c *Checker) RegisterRoutes(mux Mux) {
+	mux.Handle("/health", c.ReadyHandler())
+	mux.Handle("/health/live", c.LiveHandler())
+	mux.Handle("/health/ready", c.ReadyHandler())
+}
+
+func writeReport(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.Error("Failed to encode health report", "error", err)
+	}
+}
+
+func failedChecks(report Report) []string {
+	var failed []string
+	for name, result := range report.Checks {
+		if result.Status != StatusUp {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
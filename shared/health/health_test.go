@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serve(t *testing.T, c *Checker, path string) (int, Report) {
+	t.Helper()
+	mux := http.NewServeMux()
+	c.RegisterRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return w.Code, report
+}
+
+func TestChecker_Ready(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		setup      func(c *Checker)
+		wantCode   int
+		wantStatus string
+	}{
+		{"no checks", func(c *Checker) {}, http.StatusOK, StatusUp},
+		{"all up", func(c *Checker) { c.Register("database", up); c.RegisterOptional("audit", up) }, http.StatusOK, StatusUp},
+		{"optional down", func(c *Checker) { c.Register("database", up); c.RegisterOptional("audit", down) }, http.StatusOK, StatusDegraded},
+		{"required down", func(c *Checker) { c.Register("database", down); c.RegisterOptional("audit", down) }, http.StatusServiceUnavailable, StatusDown},
+		{"panic", func(c *Checker) { c.Register("database", func(context.Context) error { panic("boom") }) }, http.StatusServiceUnavailable, StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker("test-service")
+			tt.setup(c)
+
+			code, report := serve(t, c, "/health/ready")
+			if code != tt.wantCode || report.Status != tt.wantStatus {
+				t.Errorf("got %d %q, want %d %q", code, report.Status, tt.wantCode, tt.wantStatus)
+			}
+			if report.Service != "test-service" {
+				t.Errorf("service = %q", report.Service)
+			}
+
+			// Liveness never depends on the checks
+			code, report = serve(t, c, "/health/live")
+			if code != http.StatusOK || report.Status != StatusUp || report.Checks != nil {
+				t.Errorf("live = %d %+v", code, report)
+			}
+		})
+	}
+}
+
+func TestChecker_ReportsCheckResults(t *testing.T) {
+	c := NewChecker("test-service")
+	c.Register("database", func(context.Context) error { return errors.New("connection refused") })
+	c.RegisterOptional("audit", func(context.Context) error { return nil })
+
+	_, report := serve(t, c, "/health")
+	if got := report.Checks["database"]; got.Status != StatusDown || got.Error != "connection refused" || got.Optional {
+		t.Errorf("database = %+v", got)
+	}
+	if got := report.Checks["audit"]; got.Status != StatusUp || !got.Optional {
+		t.Errorf("audit = %+v", got)
+	}
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	c := NewChecker("test-service")
+	c.SetTimeout(10 * time.Millisecond)
+	c.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := c.Ready(context.Background())
+	if report.Status != StatusDown {
+		t.Errorf("status = %q, want %q", report.Status, StatusDown)
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if err := HTTPCheck(nil, server.URL+"/health")(context.Background()); err != nil {
+		t.Errorf("reachable service failed: %v", err)
+	}
+	if err := HTTPCheck(nil, server.URL+"/broken")(context.Background()); err == nil {
+		t.Error("expected failing service to fail the check")
+	}
+}
+
+func TestThresholdAndStalenessChecks(t *testing.T) {
+	depth := func(context.Context) (int64, error) { return 11, nil }
+	if err := ThresholdCheck(depth, 10)(context.Background()); err == nil {
+		t.Error("expected depth over threshold to fail")
+	}
+	if err := ThresholdCheck(depth, 11)(context.Background()); err != nil {
+		t.Errorf("depth at threshold failed: %v", err)
+	}
+
+	if err := StalenessCheck(func() time.Time { return time.Time{} }, time.Minute)(context.Background()); err == nil {
+		t.Error("expected a job that never ran to fail")
+	}
+	if err := StalenessCheck(func() time.Time { return time.Now().Add(-time.Hour) }, time.Minute)(context.Background()); err == nil {
+		t.Error("expected a stale job to fail")
+	}
+	if err := StalenessCheck(time.Now, time.Minute)(context.Background()); err != nil {
+		t.Errorf("fresh job failed: %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/gov-dx-sandbox/shared/requestid
+
+go 1.24.6
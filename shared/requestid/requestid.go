@@ -0,0 +1,122 @@
+// Package requestid correlates a request across the HTTP services. Middleware assigns each incoming
+// request an ID, reusing the caller's X-Request-ID when it is well-formed, and echoes it in the
+// response header. The ID is carried in the request context, from where it is:
+//
+//   - added to log records written with the *Context slog functions (NewLogHandler)
+//   - forwarded on calls to other services (SetHeader)
+//   - returned in error bodies by the response envelopes (FromResponse)
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// Header is the HTTP header carrying the request ID
+const Header = "X-Request-ID"
+
+// LogKey is the attribute holding the request ID in log records
+const LogKey = "requestId"
+
+// maxLength bounds the IDs accepted from callers
+const maxLength = 128
+
+type contextKey struct{}
+
+// New returns a random UUID (version 4)
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// FromResponse returns the request ID set on a response by Middleware, for writers that only have
+// the http.ResponseWriter
+func FromResponse(w http.ResponseWriter) string {
+	return w.Header().Get(Header)
+}
+
+// Middleware assigns the request an ID, reusing the caller's X-Request-ID if it is well-formed,
+// stores it in the request context and sets it on the response. It should wrap the whole handler
+// chain, so that responses written by other middleware carry the ID too.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !valid(id) {
+			id = New()
+		}
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// SetHeader forwards the request ID of req's context on an outbound request, unless the request
+// already carries one
+func SetHeader(req *http.Request) {
+	if req.Header.Get(Header) != "" {
+		return
+	}
+	if id := FromContext(req.Context()); id != "" {
+		req.Header.Set(Header, id)
+	}
+}
+
+// valid accepts IDs of letters, digits and -_.: so that caller-supplied values cannot inject
+// anything into logs or headers
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewLogHandler wraps h so that records logged with a context carrying a request ID include it
+func NewLogHandler(h slog.Handler) slog.Handler {
+	return logHandler{h}
+}
+
+type logHandler struct {
+	slog.Handler
+}
+
+func (h logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return logHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h logHandler) WithGroup(name string) slog.Handler {
+	return logHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,85 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{"generated when missing", "", false},
+		{"caller ID reused", "req-123:abc", true},
+		{"invalid caller ID replaced", "bad id\r\nX-Injected: 1", false},
+		{"overlong caller ID replaced", strings.Repeat("a", maxLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = FromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(Header, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			got := w.Header().Get(Header)
+			if got == "" || got != seen {
+				t.Fatalf("response ID %q, context ID %q", got, seen)
+			}
+			if (got == tt.incoming) != tt.reused {
+				t.Errorf("ID = %q, incoming %q, want reused %v", got, tt.incoming, tt.reused)
+			}
+		})
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://pdp", nil)
+	SetHeader(req)
+	if got := req.Header.Get(Header); got != "req-1" {
+		t.Errorf("forwarded ID = %q, want req-1", got)
+	}
+
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "http://pdp", nil)
+	req.Header.Set(Header, "explicit")
+	SetHeader(req)
+	if got := req.Header.Get(Header); got != "explicit" {
+		t.Errorf("explicit ID overwritten with %q", got)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "http://pdp", nil)
+	SetHeader(req)
+	if _, ok := req.Header[Header]; ok {
+		t.Error("header set without a request ID")
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("service", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "handled")
+	if !strings.Contains(buf.String(), `"requestId":"req-1"`) {
+		t.Errorf("record without request ID: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Info("background")
+	if strings.Contains(buf.String(), "requestId") {
+		t.Errorf("record has request ID without one in context: %s", buf.String())
+	}
+}
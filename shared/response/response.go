@@ -0,0 +1,89 @@
+// Package response defines the JSON envelopes shared by the HTTP services, so that every service
+// answers with the same success, collection and error shapes.
+package response
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gov-dx-sandbox/shared/requestid"
+)
+
+// ErrorResponse is the body of every error response. Code is a machine-readable reason, e.g.
+// NOT_FOUND, for clients that branch on the kind of error. RequestID identifies the request in
+// the logs of every service it reached.
+type ErrorResponse struct {
+	Error     string `json:"error"`
+	Code      string `json:"code,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// SuccessResponse is the body of a response that reports an outcome rather than a resource
+type SuccessResponse struct {
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// CollectionResponse is the body of a list response. Count is the number of items in this response;
+// Pagination is set for paged collections and holds the total across pages.
+type CollectionResponse struct {
+	Items      interface{} `json:"items"`
+	Count      int         `json:"count"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes the page returned in a CollectionResponse
+type Pagination struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"totalPages"`
+	Sort       string `json:"sort"`
+	Order      string `json:"order"`
+}
+
+// NewPagination describes page of a collection of total items split into pages of limit items
+func NewPagination(page, limit int, total int64, sort, order string) *Pagination {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	return &Pagination{Page: page, Limit: limit, Total: total, TotalPages: totalPages, Sort: sort, Order: order}
+}
+
+// NewCollectionResponse creates a collection response for items, a slice of count elements
+func NewCollectionResponse(items interface{}, count int) CollectionResponse {
+	return CollectionResponse{Items: items, Count: count}
+}
+
+// RespondWithJSON sends a JSON response with the given status code and data
+func RespondWithJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		slog.Error("Failed to encode JSON response", "error", err)
+	}
+}
+
+// RespondWithError sends a JSON error response
+func RespondWithError(w http.ResponseWriter, statusCode int, message string) {
+	RespondWithJSON(w, statusCode, ErrorResponse{Error: message, RequestID: requestid.FromResponse(w)})
+}
+
+// RespondWithErrorCode sends a JSON error response with a machine-readable code
+func RespondWithErrorCode(w http.ResponseWriter, statusCode int, code, message string) {
+	RespondWithJSON(w, statusCode, ErrorResponse{Error: message, Code: code, RequestID: requestid.FromResponse(w)})
+}
+
+// RespondWithSuccess sends a JSON success response
+func RespondWithSuccess(w http.ResponseWriter, statusCode int, data interface{}) {
+	RespondWithJSON(w, statusCode, data)
+}
+
+// RespondWithCollection sends a 200 response listing items, a slice of count elements, with the
+// page they belong to, if any
+func RespondWithCollection(w http.ResponseWriter, items interface{}, count int, pagination *Pagination) {
+	RespondWithJSON(w, http.StatusOK, CollectionResponse{Items: items, Count: count, Pagination: pagination})
+}
@@ -0,0 +1,97 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gov-dx-sandbox/shared/requestid"
+)
+
+func TestRespondWithErrorCode(t *testing.T) {
+	w := httptest.NewRecorder()
+	RespondWithErrorCode(w, http.StatusNotFound, "NOT_FOUND", "Resource not found")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body != (ErrorResponse{Error: "Resource not found", Code: "NOT_FOUND"}) {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestRespondWithError_OmitsCode(t *testing.T) {
+	w := httptest.NewRecorder()
+	RespondWithError(w, http.StatusBadRequest, "Invalid JSON input")
+
+	if got, want := w.Body.String(), "{\"error\":\"Invalid JSON input\"}\n"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestRespondWithErrorCode_IncludesRequestID(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set(requestid.Header, "req-1")
+	RespondWithErrorCode(w, http.StatusBadGateway, "PDP_UNAVAILABLE", "Policy decision point unavailable")
+
+	if got, want := w.Body.String(), "{\"error\":\"Policy decision point unavailable\",\"code\":\"PDP_UNAVAILABLE\",\"requestId\":\"req-1\"}\n"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestRespondWithCollection(t *testing.T) {
+	tests := []struct {
+		name       string
+		pagination *Pagination
+		want       string
+	}{
+		{
+			name: "unpaged collection",
+			want: `{"items":["a","b"],"count":2}`,
+		},
+		{
+			name:       "paged collection",
+			pagination: NewPagination(2, 2, 5, "createdAt", "desc"),
+			want:       `{"items":["a","b"],"count":2,"pagination":{"page":2,"limit":2,"total":5,"totalPages":3,"sort":"createdAt","order":"desc"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			RespondWithCollection(w, []string{"a", "b"}, 2, tt.pagination)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if got := w.Body.String(); got != tt.want+"\n" {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPagination(t *testing.T) {
+	tests := []struct {
+		limit int
+		total int64
+		want  int
+	}{
+		{limit: 20, total: 0, want: 0},
+		{limit: 20, total: 20, want: 1},
+		{limit: 20, total: 21, want: 2},
+		{limit: 0, total: 5, want: 0},
+	}
+	for _, tt := range tests {
+		if got := NewPagination(1, tt.limit, tt.total, "", "").TotalPages; got != tt.want {
+			t.Errorf("NewPagination(limit %d, total %d).TotalPages = %d, want %d", tt.limit, tt.total, got, tt.want)
+		}
+	}
+}
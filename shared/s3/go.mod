@@ -0,0 +1,3 @@
+module github.com/gov-dx-sandbox/shared/s3
+
+go 1.24.6
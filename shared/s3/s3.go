@@ -0,0 +1,300 @@
+// Package s3 implements the client of S3-compatible object stores, such as AWS S3, MinIO or Ceph,
+// shared by the services that keep files in a bucket. Requests are signed with AWS Signature
+// Version 4, either in the Authorization header or, for presigned URLs, in the query string.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRegion is the region signed when none is configured
+	DefaultRegion = "us-east-1"
+	// DefaultTimeout bounds each request when Config.Timeout is not set
+	DefaultTimeout = 30 * time.Second
+	// MaxPresignTTL is the longest validity S3 accepts for a presigned URL
+	MaxPresignTTL = 7 * 24 * time.Hour
+
+	algorithm       = "AWS4-HMAC-SHA256"
+	timeFormat      = "20060102T150405Z"
+	dateFormat      = "20060102"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// Config configures the client of a bucket
+type Config struct {
+	// Endpoint is the base URL of the store, e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// PathStyle addresses the bucket as endpoint/bucket/key instead of bucket.endpoint/key.
+	// Most self-hosted S3-compatible stores only support path-style addressing.
+	PathStyle bool
+
+	// Timeout bounds each request, DefaultTimeout when zero
+	Timeout time.Duration
+}
+
+// Client reads and writes the objects of a bucket
+type Client struct {
+	config     Config
+	endpoint   *url.URL
+	httpClient *http.Client
+
+	// now is replaced in tests to sign requests at a fixed time
+	now func() time.Time
+}
+
+// NewClient creates a client of the configured bucket
+func NewClient(config Config) (*Client, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("S3 access key ID and secret access key are required")
+	}
+	if config.Region == "" {
+		config.Region = DefaultRegion
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+	return &Client{
+		config:     config,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		now:        time.Now,
+	}, nil
+}
+
+// Put uploads content, replacing any object with the same key
+func (c *Client) Put(ctx context.Context, key, contentType string, content []byte) error {
+	sum := sha256.Sum256(content)
+	return c.PutObject(ctx, key, contentType, bytes.NewReader(content), int64(len(content)), hex.EncodeToString(sum[:]))
+}
+
+// PutObject uploads size bytes from body, replacing any object with the same key. sha256Hex is the
+// hex SHA-256 of the content, which lets large objects be streamed without reading them twice.
+func (c *Client) PutObject(ctx context.Context, key, contentType string, body io.ReadSeeker, size int64, sha256Hex string) error {
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind object: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.objectURL(key), io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	resp, err := c.do(req, sha256Hex)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object; the caller closes the returned body
+func (c *Client) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object. Deleting a missing object succeeds, as it does in S3.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PresignGet returns a URL that downloads the object, without credentials, until ttl passes. When
+// fileName is set, the response asks browsers to save the object under that name instead of
+// displaying it.
+func (c *Client) PresignGet(key, fileName string, ttl time.Duration) (string, error) {
+	if ttl <= 0 || ttl > MaxPresignTTL {
+		return "", fmt.Errorf("presigned URLs must expire within %s", MaxPresignTTL)
+	}
+	now := c.now().UTC()
+	objectURL, err := url.Parse(c.objectURL(key))
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", algorithm)
+	query.Set("X-Amz-Credential", c.config.AccessKeyID+"/"+credentialScope(now, c.config.Region))
+	query.Set("X-Amz-Date", now.Format(timeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(ttl/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if fileName != "" {
+		query.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	}
+	objectURL.RawQuery = canonicalQuery(query)
+	_, signature := sign(http.MethodGet, objectURL, map[string]string{"host": objectURL.Host}, unsignedPayload,
+		c.config.Region, c.config.SecretAccessKey, now)
+	return objectURL.String() + "&X-Amz-Signature=" + signature, nil
+}
+
+// do signs and sends a request, returning the response if it succeeded
+func (c *Client) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	SignRequest(req, c.config.Region, c.config.AccessKeyID, c.config.SecretAccessKey, payloadHash, c.now().UTC())
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("object storage returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// objectURL returns the URL of an object, with the bucket in the path or in the host
+func (c *Client) objectURL(key string) string {
+	path := c.endpoint.EscapedPath()
+	host := c.endpoint.Host
+	if c.config.PathStyle {
+		path += "/" + escape(c.config.Bucket, false)
+	} else {
+		host = c.config.Bucket + "." + host
+	}
+	return c.endpoint.Scheme + "://" + host + path + "/" + escape(key, false)
+}
+
+// emptyPayloadHash is the hex SHA-256 of an empty body
+var emptyPayloadHash = func() string {
+	sum := sha256.Sum256(nil)
+	return hex.EncodeToString(sum[:])
+}()
+
+// SignRequest adds the Signature Version 4 Authorization header to req, signed at t. The host and
+// every header already set on the request are signed; payloadHash is the hex SHA-256 of the body.
+func SignRequest(req *http.Request, region, accessKeyID, secretAccessKey, payloadHash string, t time.Time) {
+	req.Header.Set("X-Amz-Date", t.Format(timeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	signedHeaders, signature := sign(req.Method, req.URL, headers, payloadHash, region, secretAccessKey, t)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, accessKeyID, credentialScope(t, region), signedHeaders, signature))
+}
+
+// sign computes the Signature Version 4 signature of a request to u, returning the signed header
+// names and the signature. headers are keyed by lower-case name.
+func sign(method string, u *url.URL, headers map[string]string, payloadHash, region, secretAccessKey string, t time.Time) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method, escapePath(u.Path), canonicalQuery(u.Query()), canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		algorithm, t.Format(timeFormat), credentialScope(t, region), hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), t.Format(dateFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func credentialScope(t time.Time, region string) string {
+	return t.Format(dateFormat) + "/" + region + "/s3/aws4_request"
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath percent-encodes a path as Signature Version 4 signs it
+func escapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return escape(path, false)
+}
+
+// canonicalQuery encodes query parameters sorted by name and value, as Signature Version 4 signs them
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, escape(name, true)+"="+escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escape percent-encodes everything but the RFC 3986 unreserved characters, and slashes unless
+// escapeSlash is set
+func escape(value string, escapeSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			escaped.WriteByte(b)
+		case b == '/' && !escapeSlash:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}